@@ -7,6 +7,7 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"code.gitea.io/gitea/modules/convert"
@@ -72,6 +73,11 @@ var CmdDumpRepository = cli.Command{
 			Usage: `Which items will be migrated, one or more units should be separated as comma.
 wiki, issues, labels, releases, release_assets, milestones, pull_requests, comments are allowed. Empty means all units.`,
 		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: "gitea",
+			Usage: "The format of the dumped data, gitea or github. github writes the GitHub migration archive layout which could be restored by restore-repo --format github.",
+		},
 	},
 }
 
@@ -147,7 +153,16 @@ func runDumpRepository(ctx *cli.Context) error {
 		}
 	}
 
-	if err := migrations.DumpRepository(
+	dump := migrations.DumpRepository
+	switch ctx.String("format") {
+	case "gitea":
+	case "github":
+		dump = migrations.ExportRepository
+	default:
+		return fmt.Errorf("unknown format: %s", ctx.String("format"))
+	}
+
+	if err := dump(
 		context.Background(),
 		ctx.String("repo_dir"),
 		ctx.String("owner_name"),
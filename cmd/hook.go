@@ -195,6 +195,7 @@ Gitea or set your environment appropriately.`, "")
 		GitPushOptions:                  pushOptions(),
 		PullRequestID:                   prID,
 		IsDeployKey:                     isDeployKey,
+		UserName:                        os.Getenv(models.EnvPusherName),
 	}
 
 	scanner := bufio.NewScanner(os.Stdin)
@@ -259,7 +260,7 @@ Gitea or set your environment appropriately.`, "")
 				statusCode, msg := private.HookPreReceive(ctx, username, reponame, hookOptions)
 				switch statusCode {
 				case http.StatusOK:
-					// no-op
+					hookPrintOutput(msg)
 				case http.StatusInternalServerError:
 					return fail("Internal Server Error", msg)
 				default:
@@ -286,6 +287,8 @@ Gitea or set your environment appropriately.`, "")
 
 		statusCode, msg := private.HookPreReceive(ctx, username, reponame, hookOptions)
 		switch statusCode {
+		case http.StatusOK:
+			hookPrintOutput(msg)
 		case http.StatusInternalServerError:
 			return fail("Internal Server Error", msg)
 		case http.StatusForbidden:
@@ -366,6 +369,7 @@ Gitea or set your environment appropriately.`, "")
 	wasEmpty := false
 	masterPushed := false
 	results := make([]private.HookPostReceiveBranchResult, 0)
+	outputs := make([]string, 0)
 
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
@@ -402,6 +406,7 @@ Gitea or set your environment appropriately.`, "")
 			}
 			wasEmpty = wasEmpty || resp.RepoWasEmpty
 			results = append(results, resp.Results...)
+			outputs = append(outputs, resp.Output)
 			count = 0
 		}
 	}
@@ -418,6 +423,7 @@ Gitea or set your environment appropriately.`, "")
 
 		_ = dWriter.Close()
 		hookPrintResults(results)
+		hookPrintOutput(outputs...)
 		return nil
 	}
 
@@ -435,6 +441,7 @@ Gitea or set your environment appropriately.`, "")
 	}
 	wasEmpty = wasEmpty || resp.RepoWasEmpty
 	results = append(results, resp.Results...)
+	outputs = append(outputs, resp.Output)
 
 	fmt.Fprintf(out, "Processed %d references in total\n", total)
 
@@ -447,10 +454,23 @@ Gitea or set your environment appropriately.`, "")
 	}
 	_ = dWriter.Close()
 	hookPrintResults(results)
+	hookPrintOutput(outputs...)
 
 	return nil
 }
 
+// hookPrintOutput prints the output of the hook scripts of the repository
+func hookPrintOutput(outputs ...string) {
+	for _, output := range outputs {
+		if output == "" {
+			continue
+		}
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprint(os.Stderr, output)
+		os.Stderr.Sync()
+	}
+}
+
 func hookPrintResults(results []private.HookPostReceiveBranchResult) {
 	for _, res := range results {
 		if !res.Message {
@@ -43,6 +43,11 @@ var CmdRestoreRepository = cli.Command{
 			Usage: `Which items will be restored, one or more units should be separated as comma.
 wiki, issues, labels, releases, release_assets, milestones, pull_requests, comments are allowed. Empty means all units.`,
 		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: "gitea",
+			Usage: "The format of the data to restore from, gitea or github. github reads the GitHub migration archive layout.",
+		},
 	},
 }
 
@@ -58,6 +63,7 @@ func runRestoreRepository(c *cli.Context) error {
 		c.String("owner_name"),
 		c.String("repo_name"),
 		c.StringSlice("units"),
+		c.String("format"),
 	)
 	if statusCode == http.StatusOK {
 		return nil
@@ -5,12 +5,14 @@
 package cmd
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	pages_service "code.gitea.io/gitea/services/pages"
 
 	"github.com/caddyserver/certmagic"
 	context2 "github.com/gorilla/context"
@@ -47,6 +49,26 @@ func runLetsEncrypt(listenAddr, domain, directory, email string, m http.Handler)
 
 	magic.Issuers = []certmagic.Issuer{myACME}
 
+	if setting.Pages.Enabled {
+		// the hosts of pages sites are not known in advance, so their certificates are obtained on their first request,
+		// custom domains only once they are verified
+		magic.OnDemand = &certmagic.OnDemandConfig{
+			DecisionFunc: func(name string) error {
+				if name == domain {
+					return nil
+				}
+				isPagesHost, err := pages_service.IsPagesHost(name)
+				if err != nil {
+					return err
+				}
+				if !isPagesHost {
+					return fmt.Errorf("%s is not the host of a pages site", name)
+				}
+				return nil
+			},
+		}
+	}
+
 	// this obtains certificates or renews them if necessary
 	err := magic.ManageSync([]string{domain})
 	if err != nil {
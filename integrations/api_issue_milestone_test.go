@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
@@ -76,3 +78,100 @@ func TestAPIIssuesMilestone(t *testing.T) {
 	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/%s/%s/milestones/%d?token=%s", owner.Name, repo.Name, apiMilestone.ID, token))
 	resp = session.MakeRequest(t, req, http.StatusNoContent)
 }
+
+func TestAPICloneMilestone(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	urlStr := fmt.Sprintf("/api/v1/repos/user2/repo1/milestones/1/clone?token=%s", token)
+	req := NewRequestWithJSON(t, "POST", urlStr, structs.CloneMilestoneOption{Repo: "repo2"})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiMilestone structs.Milestone
+	DecodeJSON(t, resp, &apiMilestone)
+	assert.Equal(t, "milestone1", apiMilestone.Title)
+	assert.Equal(t, "content1", apiMilestone.Description)
+	assert.Equal(t, structs.StateOpen, apiMilestone.State)
+	assert.Equal(t, 0, apiMilestone.OpenIssues)
+	db.AssertExistsAndLoadBean(t, &models.Milestone{ID: apiMilestone.ID, RepoID: 2})
+
+	req = NewRequestWithJSON(t, "POST", urlStr, structs.CloneMilestoneOption{Title: "milestone1 again"})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &apiMilestone)
+	assert.Equal(t, "milestone1 again", apiMilestone.Title)
+	db.AssertExistsAndLoadBean(t, &models.Milestone{ID: apiMilestone.ID, RepoID: 1})
+
+	req = NewRequestWithJSON(t, "POST", urlStr, structs.CloneMilestoneOption{Repo: "does-not-exist"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// reading a milestone is not enough to clone it to its repository
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/milestones/1/clone?token=%s", token), structs.CloneMilestoneOption{})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIMilestoneBurndown(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/milestones?token=%s", token), structs.CreateMilestoneOption{
+		Title: "sprint",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiMilestone structs.Milestone
+	DecodeJSON(t, resp, &apiMilestone)
+
+	for _, title := range []string{"open issue", "closed issue"} {
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/issues?token=%s", token), structs.CreateIssueOption{
+			Title:     title,
+			Milestone: apiMilestone.ID,
+			Closed:    title == "closed issue",
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/milestones/%d/burndown", apiMilestone.ID))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var days []*structs.MilestoneBurndownDay
+	DecodeJSON(t, resp, &days)
+	if assert.NotEmpty(t, days) {
+		today := days[len(days)-1]
+		assert.Equal(t, time.Now().In(setting.DefaultUILocation).Format("2006-01-02"), today.Date)
+		assert.Equal(t, 1, today.OpenIssues)
+		assert.Equal(t, 1, today.ClosedIssues)
+	}
+}
+
+func TestAPIMigrateMilestoneIssues(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/milestones/1/migrate-issues?target=2&token=%s", token))
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiMilestone structs.Milestone
+	DecodeJSON(t, resp, &apiMilestone)
+	assert.EqualValues(t, 2, apiMilestone.ID)
+	assert.Equal(t, 1, apiMilestone.OpenIssues)
+	db.AssertExistsAndLoadBean(t, &models.Issue{ID: 2, MilestoneID: 2})
+	db.AssertExistsAndLoadBean(t, &models.Comment{IssueID: 2, Type: models.CommentTypeMilestone, OldMilestoneID: 1, MilestoneID: 2})
+	milestone := db.AssertExistsAndLoadBean(t, &models.Milestone{ID: 1}).(*models.Milestone)
+	assert.EqualValues(t, 0, milestone.NumIssues)
+
+	req = NewRequest(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/milestones/1/migrate-issues?target=1&token=%s", token))
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// milestones of other repositories are no target
+	req = NewRequest(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/milestones/2/migrate-issues?target=4&token=%s", token))
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/milestones/2/migrate-issues?target=1&token=%s", token))
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgMilestones(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/milestones?token="+token, &api.CreateMilestoneOption{
+		Title:       "v1.0",
+		Description: "shared by all repositories",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiMilestone api.Milestone
+	DecodeJSON(t, resp, &apiMilestone)
+	assert.Equal(t, "v1.0", apiMilestone.Title)
+	milestone := db.AssertExistsAndLoadBean(t, &models.Milestone{ID: apiMilestone.ID}).(*models.Milestone)
+	assert.EqualValues(t, 0, milestone.RepoID)
+	assert.EqualValues(t, 3, milestone.OwnerID)
+	assert.Equal(t, models.MilestoneOwnerOrganization, milestone.OwnerType)
+
+	// issues of any repository of the organization can be assigned to the milestone
+	for _, repoName := range []string{"repo3", "repo5"} {
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user3/%s/issues?token=%s", repoName, token), &api.CreateIssueOption{
+			Title:     "issue of " + repoName,
+			Milestone: milestone.ID,
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var apiIssue api.Issue
+		DecodeJSON(t, resp, &apiIssue)
+		if assert.NotNil(t, apiIssue.Milestone) {
+			assert.Equal(t, milestone.ID, apiIssue.Milestone.ID)
+		}
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/user3/milestones/%d", milestone.ID))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiMilestone)
+	assert.Equal(t, 2, apiMilestone.OpenIssues)
+
+	var apiMilestones []*api.Milestone
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/milestones")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiMilestones)
+	if assert.Len(t, apiMilestones, 1) {
+		assert.Equal(t, milestone.ID, apiMilestones[0].ID)
+	}
+
+	// shared milestones are not listed or managed as milestones of a repository
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user3/repo3/milestones/%d?token=%s", milestone.ID, token))
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// only owners of the organization manage its milestones
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/user3/milestones/%d?token=%s", milestone.ID, token4))
+	session4.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/user3/milestones/%d?token=%s", milestone.ID, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	db.AssertNotExistsBean(t, &models.Milestone{ID: milestone.ID})
+	db.AssertNotExistsBean(t, &models.Issue{MilestoneID: milestone.ID})
+}
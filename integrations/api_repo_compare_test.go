@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPICompareRenderedImage(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3, 2))))
+		resp, err := createFileInBranch(user2, repo1, "logo.png", repo1.DefaultBranch, buf.String())
+		assert.NoError(t, err)
+		headSHA := resp.Commit.SHA
+		baseSHA := resp.Commit.Parents[0].SHA
+
+		req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/%s..%s/rendered", baseSHA, headSHA)
+		res := MakeRequest(t, req, http.StatusOK)
+		var diff api.RenderedDiff
+		DecodeJSON(t, res, &diff)
+		if assert.Len(t, diff.Files, 1) && assert.NotNil(t, diff.Files[0].Image) {
+			img := diff.Files[0].Image
+			assert.Nil(t, img.Before)
+			assert.Equal(t, []string{"side-by-side"}, img.Modes)
+			if assert.NotNil(t, img.After) {
+				assert.Equal(t, "image/png", img.After.MimeType)
+				assert.EqualValues(t, buf.Len(), img.After.Size)
+				assert.Equal(t, 3, img.After.Width)
+				assert.Equal(t, 2, img.After.Height)
+				assert.Equal(t, repo1.HTMLURL()+"/raw/commit/"+headSHA+"/logo.png", img.After.RawURL)
+			}
+		}
+	})
+}
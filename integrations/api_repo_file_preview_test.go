@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPreviewFileEdit(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	const baseCommit = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/README.md/preview", &api.PreviewFileEditOptions{
+		BaseCommit: baseCommit,
+		Content:    base64.StdEncoding.EncodeToString([]byte("# repo1\n\nNew description\n")),
+	})
+	resp := MakeRequest(t, req, http.StatusOK)
+	var preview api.FileEditPreview
+	DecodeJSON(t, resp, &preview)
+	assert.Equal(t, baseCommit, preview.BaseCommit)
+	assert.False(t, preview.Conflicted)
+	content, err := base64.StdEncoding.DecodeString(preview.Content)
+	assert.NoError(t, err)
+	assert.Equal(t, "# repo1\n\nNew description\n", string(content))
+
+	// other POSTs to the contents still create files, which needs write access
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/preview.txt", &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("content")),
+	})
+	MakeRequest(t, req, http.StatusForbidden)
+}
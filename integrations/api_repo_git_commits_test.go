@@ -5,7 +5,10 @@
 package integrations
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
 	"code.gitea.io/gitea/models"
@@ -74,6 +77,43 @@ func TestAPIReposGitCommitList(t *testing.T) {
 	compareCommitFiles(t, []string{"readme.md"}, apiData[2].Files)
 }
 
+func TestAPIReposGitCommitListStatAndVerification(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	// Login as User2.
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	// the stats and the verification are only given if requested
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?token="+token+"&files=false", user.Name)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	var apiData []api.Commit
+	DecodeJSON(t, resp, &apiData)
+
+	assert.Len(t, apiData, 3)
+	for _, commit := range apiData {
+		assert.Nil(t, commit.Stats)
+		assert.Nil(t, commit.RepoCommit.Verification)
+		assert.Empty(t, commit.Files)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?token="+token+"&stat=true&verification=true", user.Name)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	apiData = nil
+	DecodeJSON(t, resp, &apiData)
+
+	assert.Len(t, apiData, 3)
+	for _, commit := range apiData {
+		if assert.NotNil(t, commit.Stats) {
+			assert.Greater(t, commit.Stats.Total, 0)
+			assert.Equal(t, commit.Stats.Additions+commit.Stats.Deletions, commit.Stats.Total)
+		}
+		assert.NotNil(t, commit.RepoCommit.Verification)
+		compareCommitFiles(t, []string{"readme.md"}, commit.Files)
+	}
+}
+
 func TestAPIReposGitCommitListPage2Empty(t *testing.T) {
 	defer prepareTestEnv(t)()
 	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
@@ -132,3 +172,69 @@ func TestDownloadCommitDiffOrPatch(t *testing.T) {
 		resp.Body.String())
 
 }
+
+func TestAPIReposGitCommitCherryPickAndRevert(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		session := loginUser(t, user.Name)
+		token := getTokenForLoggedInUser(t, session)
+
+		// cherry-pick "add WoW File" of pr-to-update onto master as a new branch
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/repo1/git/commits/62fb502a7172d4453f0322a2cc85bddffa57f07a/cherry-pick?token=%s", user.Name, token), &api.ApplyCommitOption{
+			BranchName:    "master",
+			NewBranchName: "cherry-picked",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var picked api.Commit
+		DecodeJSON(t, resp, &picked)
+		if assert.Len(t, picked.Parents, 1) {
+			assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", picked.Parents[0].SHA)
+		}
+		assert.Equal(t, "add WoW File\n\n(cherry picked from commit 62fb502a7172d4453f0322a2cc85bddffa57f07a)", strings.TrimSpace(picked.RepoCommit.Message))
+		assert.NotNil(t, picked.RepoCommit.Verification)
+		compareCommitFiles(t, []string{"File-WoW"}, picked.Files)
+
+		// revert it on the new branch
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/repo1/git/commits/%s/revert?token=%s", user.Name, picked.SHA, token), &api.ApplyCommitOption{
+			BranchName: "cherry-picked",
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var reverted api.Commit
+		DecodeJSON(t, resp, &reverted)
+		if assert.Len(t, reverted.Parents, 1) {
+			assert.Equal(t, picked.SHA, reverted.Parents[0].SHA)
+		}
+		assert.Equal(t, "Revert \"add WoW File\"\n\nThis reverts commit "+picked.SHA+".", strings.TrimSpace(reverted.RepoCommit.Message))
+		compareCommitFiles(t, []string{"File-WoW"}, reverted.Files)
+
+		// "make pull5 outdated" changes lines of README.md master does not have
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/repo1/git/commits/985f0301dba5e7b34be866819cd15ad3d8f508ee/cherry-pick?token=%s", user.Name, token), &api.ApplyCommitOption{
+			BranchName: "master",
+		})
+		session.MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/repo1/git/commits/0000000000000000000000000000000000000000/revert?token=%s", user.Name, token), &api.ApplyCommitOption{})
+		session.MakeRequest(t, req, http.StatusNotFound)
+	})
+}
+
+func TestAPIReposGitCommitVerification(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/git/commits/master/verification?token="+token, user.Name)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var detail api.CommitVerificationDetail
+	DecodeJSON(t, resp, &detail)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", detail.SHA)
+	assert.False(t, detail.Verified)
+	assert.Equal(t, "gpg.error.not_signed_commit", detail.Reason)
+	assert.Nil(t, detail.Key)
+	assert.Empty(t, detail.TrustStatus)
+	assert.Equal(t, "collaborator", detail.TrustModel)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/git/commits/0000000000000000000000000000000000000000/verification?token="+token, user.Name)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
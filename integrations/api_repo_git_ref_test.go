@@ -6,10 +6,14 @@ package integrations
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAPIReposGitRefs(t *testing.T) {
@@ -33,3 +37,38 @@ func TestAPIReposGitRefs(t *testing.T) {
 	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/git/refs/heads/unknown?token="+token, user.Name)
 	session.MakeRequest(t, req, http.StatusNotFound)
 }
+
+func TestAPIReposGitRefsUpdate(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+		urlStr := "/api/v1/repos/user2/repo1/git/refs/heads/ref-api?token=" + token
+
+		req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateGitRefOption{SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d"})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var ref api.Reference
+		DecodeJSON(t, resp, &ref)
+		assert.Equal(t, "refs/heads/ref-api", ref.Ref)
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", ref.Object.SHA)
+
+		req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateGitRefOption{SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d"})
+		session.MakeRequest(t, req, http.StatusConflict)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git/refs/pull/9/head?token="+token, &api.CreateGitRefOption{SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d"})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// fast-forward to the head of branch2
+		req = NewRequestWithJSON(t, "PATCH", urlStr, &api.UpdateGitRefOption{SHA: "985f0301dba5e7b34be866819cd15ad3d8f508ee"})
+		session.MakeRequest(t, req, http.StatusOK)
+		req = NewRequestWithJSON(t, "PATCH", urlStr, &api.UpdateGitRefOption{SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d"})
+		session.MakeRequest(t, req, http.StatusConflict)
+		req = NewRequestWithJSON(t, "PATCH", urlStr, &api.UpdateGitRefOption{SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", Force: true})
+		session.MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "DELETE", urlStr)
+		session.MakeRequest(t, req, http.StatusNoContent)
+		req = NewRequest(t, "DELETE", urlStr)
+		session.MakeRequest(t, req, http.StatusNotFound)
+		req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/git/refs/heads/master?token="+token)
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
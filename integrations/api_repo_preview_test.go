@@ -0,0 +1,55 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSTLModel = `solid triangle
+  facet normal 0 0 1
+    outer loop
+      vertex 0 0 0
+      vertex 10 0 0
+      vertex 0 20 5
+    endloop
+  endfacet
+endsolid triangle
+`
+
+func TestAPIReposFilePreview(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		_, err := createFileInBranch(user2, repo1, "models/triangle.stl", repo1.DefaultBranch, testSTLModel)
+		assert.NoError(t, err)
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/preview/models/triangle.stl")
+		resp := MakeRequest(t, req, http.StatusOK)
+		var preview api.FilePreview
+		DecodeJSON(t, resp, &preview)
+		assert.Equal(t, "triangle.stl", preview.Name)
+		assert.Equal(t, "models/triangle.stl", preview.Path)
+		assert.Equal(t, "stl", preview.Provider)
+		assert.Empty(t, preview.Image)
+		assert.EqualValues(t, 1, preview.Metadata["triangles"])
+		assert.EqualValues(t, []interface{}{10.0, 20.0, 5.0}, preview.Metadata["size"])
+
+		// no provider previews text files
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/preview/README.md")
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/preview/models/missing.stl")
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testNotebook = `{
+ "nbformat": 4,
+ "nbformat_minor": 5,
+ "metadata": {"language_info": {"name": "python"}},
+ "cells": [
+  {"cell_type": "code", "execution_count": 1, "source": "print(1)", "outputs": [{"output_type": "stream", "name": "stdout", "text": "1\n"}]}
+ ]
+}`
+
+func TestAPIReposRenderedFile(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		_, err := createFileInBranch(user2, repo1, "data/values.csv", repo1.DefaultBranch, "name,value\n<b>a</b>,1\n")
+		assert.NoError(t, err)
+		_, err = createFileInBranch(user2, repo1, "analysis.ipynb", repo1.DefaultBranch, testNotebook)
+		assert.NoError(t, err)
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/render/data/values.csv")
+		resp := MakeRequest(t, req, http.StatusOK)
+		var file api.RenderedFile
+		DecodeJSON(t, resp, &file)
+		assert.Equal(t, "values.csv", file.Name)
+		assert.Equal(t, "csv", file.Type)
+		assert.False(t, file.TooLarge)
+		assert.Equal(t, [][]string{{"name", "value"}, {"<b>a</b>", "1"}}, file.Rows)
+		assert.Contains(t, file.HTML, `<td>&lt;b&gt;a&lt;/b&gt;</td>`)
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/render/analysis.ipynb")
+		resp = MakeRequest(t, req, http.StatusOK)
+		file = api.RenderedFile{}
+		DecodeJSON(t, resp, &file)
+		assert.Equal(t, "jupyter", file.Type)
+		assert.Equal(t, "python", file.Language)
+		if assert.Len(t, file.Cells, 1) {
+			assert.Equal(t, "code", file.Cells[0].Type)
+			assert.Equal(t, "print(1)", file.Cells[0].Source)
+			assert.EqualValues(t, 1, *file.Cells[0].ExecutionCount)
+			if assert.Len(t, file.Cells[0].Outputs, 1) {
+				assert.Equal(t, "1\n", file.Cells[0].Outputs[0].Text)
+			}
+		}
+		assert.Contains(t, file.HTML, `<div class="notebook-prompt">In [1]:</div>`)
+
+		// only CSV files and notebooks are rendered
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/render/README.md")
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/render/missing.csv")
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
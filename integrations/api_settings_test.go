@@ -35,6 +35,7 @@ func TestAPIExposedSettings(t *testing.T) {
 		DefaultPagingNum:       setting.API.DefaultPagingNum,
 		DefaultGitTreesPerPage: setting.API.DefaultGitTreesPerPage,
 		DefaultMaxBlobSize:     setting.API.DefaultMaxBlobSize,
+		MaxBlobUploadSize:      setting.API.MaxBlobUploadSize,
 	}, apiSettings)
 
 	repo := new(api.GeneralRepoSettings)
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUserProfileReadme(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	// users without profile repository have no README
+	req := NewRequest(t, "GET", "/api/v1/users/user2/profile_readme")
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", "/api/v1/users/user-does-not-exist/profile_readme")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/user/repos?token=%s", token), &api.CreateRepoOption{
+		Name:        ".profile",
+		Description: "Hello from the profile of user2",
+		Readme:      "Default",
+		AutoInit:    true,
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "GET", "/api/v1/users/user2/profile_readme")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var readme api.ProfileReadme
+	DecodeJSON(t, resp, &readme)
+	assert.Equal(t, "user2/.profile", readme.Repository)
+	assert.Contains(t, readme.HTMLURL, "/user2/.profile")
+	assert.Contains(t, readme.HTML, "Hello from the profile of user2")
+
+	// the README of a private profile repository is not shown
+	private := true
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/user2/.profile?token=%s", token), &api.EditRepoOption{Private: &private})
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/users/user2/profile_readme")
+	MakeRequest(t, req, http.StatusNotFound)
+}
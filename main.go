@@ -19,6 +19,7 @@ import (
 
 	// register supported doc types
 	_ "code.gitea.io/gitea/modules/markup/csv"
+	_ "code.gitea.io/gitea/modules/markup/jupyter"
 	_ "code.gitea.io/gitea/modules/markup/markdown"
 	_ "code.gitea.io/gitea/modules/markup/orgmode"
 
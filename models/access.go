@@ -255,6 +255,11 @@ func (repo *Repository) recalculateTeamAccesses(e db.Engine, ignTeamID int64) (e
 		return err
 	}
 
+	// Members of child teams inherit the access of their parent teams,
+	// the members of a team are only loaded once.
+	tree := newTeamTree(repo.Owner.Teams)
+	teamMembers := make(map[int64][]*User, len(repo.Owner.Teams))
+
 	for _, t := range repo.Owner.Teams {
 		if t.ID == ignTeamID {
 			continue
@@ -268,11 +273,17 @@ func (repo *Repository) recalculateTeamAccesses(e db.Engine, ignTeamID int64) (e
 			continue
 		}
 
-		if err = t.getMembers(e); err != nil {
-			return fmt.Errorf("getMembers '%d': %v", t.ID, err)
-		}
-		for _, m := range t.Members {
-			updateUserAccess(accessMap, m, t.Authorize)
+		for _, teamID := range tree.withDescendants(t.ID) {
+			members, ok := teamMembers[teamID]
+			if !ok {
+				if members, err = getTeamMembers(e, teamID); err != nil {
+					return fmt.Errorf("getTeamMembers '%d': %v", teamID, err)
+				}
+				teamMembers[teamID] = members
+			}
+			for _, m := range members {
+				updateUserAccess(accessMap, m, t.Authorize)
+			}
 		}
 	}
 
@@ -298,13 +309,8 @@ func (repo *Repository) recalculateUserAccess(e db.Engine, uid int64) (err error
 	if err = repo.getOwner(e); err != nil {
 		return err
 	} else if repo.Owner.IsOrganization() {
-		var teams []Team
-		if err := e.Join("INNER", "team_repo", "team_repo.team_id = team.id").
-			Join("INNER", "team_user", "team_user.team_id = team.id").
-			Where("team.org_id = ?", repo.OwnerID).
-			And("team_repo.repo_id=?", repo.ID).
-			And("team_user.uid=?", uid).
-			Find(&teams); err != nil {
+		teams, err := getUserRepoTeams(e, repo.OwnerID, uid, repo.ID)
+		if err != nil {
 			return err
 		}
 
@@ -9,6 +9,7 @@ import (
 	"path"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -28,6 +29,7 @@ type Attachment struct {
 	Name          string
 	DownloadCount int64              `xorm:"DEFAULT 0"`
 	Size          int64              `xorm:"DEFAULT 0"`
+	HashSHA256    string             `xorm:"hash_sha256 VARCHAR(64) INDEX"` // empty if the content is not shared with other attachments
 	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
 }
 
@@ -50,11 +52,48 @@ func AttachmentRelativePath(uuid string) string {
 	return path.Join(uuid[0:1], uuid[1:2], uuid)
 }
 
+// IsDeduplicated returns whether the content of the attachment is a blob shared with other attachments.
+// Such content is not removed with the attachment but by the garbage collection of unreferenced blobs.
+func (a *Attachment) IsDeduplicated() bool {
+	return a.HashSHA256 != ""
+}
+
 // RelativePath returns the relative path of the attachment
 func (a *Attachment) RelativePath() string {
+	if a.IsDeduplicated() {
+		return AttachmentBlobRelativePath(a.HashSHA256)
+	}
 	return AttachmentRelativePath(a.UUID)
 }
 
+// AttachmentThumbnailSuffix separates the path of the attachment content from the size in the paths of its thumbnails
+const AttachmentThumbnailSuffix = ".thumbnail-"
+
+// AttachmentThumbnailRelativePath returns the relative path of the thumbnail with the size of the attachment content
+// at the relative path. The thumbnails are stored alongside the content and shared like it.
+func AttachmentThumbnailRelativePath(relativePath string, size int) string {
+	return fmt.Sprintf("%s%s%d", relativePath, AttachmentThumbnailSuffix, size)
+}
+
+// ThumbnailRelativePath returns the relative path of the thumbnail of the attachment with the size
+func (a *Attachment) ThumbnailRelativePath(size int) string {
+	return AttachmentThumbnailRelativePath(a.RelativePath(), size)
+}
+
+// RemoveAttachmentThumbnails removes the generated thumbnails of the attachment content at the relative path,
+// failures are only logged as the thumbnails are regenerated or collected by the doctor
+func RemoveAttachmentThumbnails(relativePath string) {
+	for _, size := range setting.Attachment.ThumbnailSizes {
+		p := AttachmentThumbnailRelativePath(relativePath, size)
+		if _, err := storage.Attachments.Stat(p); err != nil {
+			continue
+		}
+		if err := storage.Attachments.Delete(p); err != nil {
+			log.Error("Delete attachment thumbnail %s: %v", p, err)
+		}
+	}
+}
+
 // DownloadURL returns the download url of the attached file
 func (a *Attachment) DownloadURL() string {
 	return fmt.Sprintf("%sattachments/%s", setting.AppURL, a.UUID)
@@ -196,9 +235,13 @@ func DeleteAttachments(ctx *db.Context, attachments []*Attachment, remove bool)
 
 	if remove {
 		for i, a := range attachments {
+			if a.IsDeduplicated() {
+				continue
+			}
 			if err := storage.Attachments.Delete(a.RelativePath()); err != nil {
 				return i, err
 			}
+			RemoveAttachmentThumbnails(a.RelativePath())
 		}
 	}
 	return int(cnt), nil
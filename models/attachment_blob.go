@@ -0,0 +1,81 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"path"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// AttachmentBlob represents the content of attachments, deduplicated by hash
+type AttachmentBlob struct {
+	ID         int64  `xorm:"pk autoincr"`
+	HashSHA256 string `xorm:"hash_sha256 char(64) UNIQUE NOT NULL"`
+	Size       int64  `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(AttachmentBlob))
+}
+
+// AttachmentBlobRelativePath returns the relative path of the content of attachments with a SHA256 hash
+func AttachmentBlobRelativePath(hashSHA256 string) string {
+	return path.Join("sha256", hashSHA256[0:2], hashSHA256[2:4], hashSHA256)
+}
+
+// RelativePath returns the relative path of the blob in the attachment storage
+func (ab *AttachmentBlob) RelativePath() string {
+	return AttachmentBlobRelativePath(ab.HashSHA256)
+}
+
+// GetOrInsertAttachmentBlob returns the blob with the same SHA256 hash or inserts ab.
+// The returned bool reports whether the blob existed before.
+func GetOrInsertAttachmentBlob(ctx *db.Context, ab *AttachmentBlob) (*AttachmentBlob, bool, error) {
+	e := ctx.Engine()
+
+	existing := &AttachmentBlob{}
+	has, err := e.Where("hash_sha256 = ?", ab.HashSHA256).Get(existing)
+	if err != nil {
+		return nil, false, err
+	}
+	if has {
+		return existing, true, nil
+	}
+	if _, err = e.Insert(ab); err != nil {
+		return nil, false, err
+	}
+	return ab, false, nil
+}
+
+// ExistAttachmentBlobByHash returns true if a blob with the SHA256 hash exists
+func ExistAttachmentBlobByHash(hashSHA256 string) (bool, error) {
+	return db.DefaultContext().Engine().Where("hash_sha256 = ?", hashSHA256).Exist(new(AttachmentBlob))
+}
+
+// CountAttachmentBlobReferences returns the number of attachments whose content is the blob with the SHA256 hash
+func CountAttachmentBlobReferences(hashSHA256 string) (int64, error) {
+	return db.DefaultContext().Engine().Where("hash_sha256 = ?", hashSHA256).Count(new(Attachment))
+}
+
+// FindUnreferencedAttachmentBlobs returns blobs older than olderThan which are not the content of any attachment
+func FindUnreferencedAttachmentBlobs(olderThan timeutil.TimeStamp) ([]*AttachmentBlob, error) {
+	blobs := make([]*AttachmentBlob, 0, 10)
+	return blobs, db.DefaultContext().Engine().
+		Where("created_unix < ?", olderThan).
+		And(builder.NotIn("hash_sha256", builder.Select("hash_sha256").From("attachment").Where(builder.Neq{"hash_sha256": ""}))).
+		Find(&blobs)
+}
+
+// DeleteAttachmentBlobByID deletes an attachment blob row
+func DeleteAttachmentBlobByID(id int64) error {
+	_, err := db.DefaultContext().Engine().ID(id).Delete(&AttachmentBlob{})
+	return err
+}
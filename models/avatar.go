@@ -7,6 +7,7 @@ package models
 import (
 	"crypto/md5"
 	"fmt"
+	"image"
 	"net/url"
 	"path"
 	"strconv"
@@ -51,6 +52,15 @@ const DefaultAvatarPixelSize = 28
 // AvatarRenderedSizeFactor is the factor by which the default size is increased for finer rendering
 const AvatarRenderedSizeFactor = 4
 
+// avatarDataSum returns the MD5 sum identifying an uploaded avatar image cropped to the given area
+func avatarDataSum(data []byte, crop image.Rectangle) [md5.Size]byte {
+	sum := md5.Sum(data)
+	if crop.Empty() {
+		return sum
+	}
+	return md5.Sum([]byte(fmt.Sprintf("%x-%v", sum, crop)))
+}
+
 // HashEmail hashes email address to MD5 string.
 // https://en.gravatar.com/site/implement/hash/
 func HashEmail(email string) string {
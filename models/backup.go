@@ -0,0 +1,146 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ErrBackupNotExist indicates a backup not exist error
+var ErrBackupNotExist = errors.New("Backup does not exist")
+
+// BackupType is what a backup contains
+type BackupType int
+
+// Note: new type must append to the end of list to maintain compatibility.
+const (
+	// BackupTypeInstance contains the database, all repositories, attachments and LFS objects
+	BackupTypeInstance BackupType = iota
+	// BackupTypeRepository contains a single repository with its wiki, issues, pull requests and releases
+	BackupTypeRepository
+)
+
+var backupTypeNames = map[BackupType]string{
+	BackupTypeInstance:   "instance",
+	BackupTypeRepository: "repository",
+}
+
+// String returns the name of the type
+func (t BackupType) String() string {
+	return backupTypeNames[t]
+}
+
+// BackupTypeFromString returns the type with the given name
+func BackupTypeFromString(name string) (BackupType, bool) {
+	for t, n := range backupTypeNames {
+		if n == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// BackupStatus is the progress of a backup
+type BackupStatus int
+
+// Note: new status must append to the end of list to maintain compatibility.
+const (
+	BackupStatusQueued BackupStatus = iota
+	BackupStatusRunning
+	BackupStatusFinished
+	BackupStatusFailed
+)
+
+var backupStatusNames = map[BackupStatus]string{
+	BackupStatusQueued:   "queued",
+	BackupStatusRunning:  "running",
+	BackupStatusFinished: "finished",
+	BackupStatusFailed:   "failed",
+}
+
+// String returns the name of the status
+func (s BackupStatus) String() string {
+	return backupStatusNames[s]
+}
+
+// Backup is an archive of the instance or of a repository produced in the backup storage
+type Backup struct {
+	ID     int64      `xorm:"pk autoincr"`
+	Type   BackupType `xorm:"INDEX NOT NULL"`
+	RepoID int64      `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// OwnerName and RepoName are the names of the repository when it was backed up
+	OwnerName string
+	RepoName  string
+	Status    BackupStatus `xorm:"INDEX NOT NULL"`
+	// Path is the path of the archive in the backup storage
+	Path   string
+	Size   int64  `xorm:"NOT NULL DEFAULT 0"`
+	Error  string `xorm:"TEXT"`
+	DoerID int64  `xorm:"NOT NULL"`
+
+	CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+	FinishedUnix timeutil.TimeStamp
+}
+
+func init() {
+	db.RegisterModel(new(Backup))
+}
+
+// CreateBackup inserts a new queued backup
+func CreateBackup(backup *Backup) error {
+	backup.Status = BackupStatusQueued
+	_, err := db.DefaultContext().Engine().Insert(backup)
+	return err
+}
+
+// GetBackupByID returns the backup with the given id
+func GetBackupByID(id int64) (*Backup, error) {
+	backup := &Backup{ID: id}
+	has, err := db.DefaultContext().Engine().Get(backup)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrBackupNotExist
+	}
+	return backup, nil
+}
+
+// UpdateBackupStatus stores the progress of a backup
+func UpdateBackupStatus(backup *Backup) error {
+	if backup.Status == BackupStatusFinished || backup.Status == BackupStatusFailed {
+		backup.FinishedUnix = timeutil.TimeStampNow()
+	}
+	_, err := db.DefaultContext().Engine().ID(backup.ID).Cols("status", "path", "size", "error", "finished_unix").Update(backup)
+	return err
+}
+
+// FindBackupsOptions are the options for listing backups
+type FindBackupsOptions struct {
+	ListOptions
+	// Type filters the backups by type if not nil
+	Type   *BackupType
+	RepoID int64
+}
+
+// FindBackups returns the backups matching the options, newest first
+func FindBackups(opts *FindBackupsOptions) ([]*Backup, int64, error) {
+	sess := db.DefaultContext().Engine().Desc("id")
+	if opts.Type != nil {
+		sess = sess.Where("type = ?", *opts.Type)
+	}
+	if opts.RepoID > 0 {
+		sess = sess.And("repo_id = ?", opts.RepoID)
+	}
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, &opts.ListOptions)
+	}
+
+	backups := make([]*Backup, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&backups)
+	return backups, count, err
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackup(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	instance := &Backup{Type: BackupTypeInstance, DoerID: 1}
+	assert.NoError(t, CreateBackup(instance))
+	repository := &Backup{Type: BackupTypeRepository, RepoID: 1, OwnerName: "user2", RepoName: "repo1", DoerID: 1}
+	assert.NoError(t, CreateBackup(repository))
+
+	backup, err := GetBackupByID(repository.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupStatusQueued, backup.Status)
+	assert.Zero(t, backup.FinishedUnix)
+
+	backup.Status = BackupStatusFinished
+	backup.Path = "repository-2.zip"
+	backup.Size = 42
+	assert.NoError(t, UpdateBackupStatus(backup))
+	backup, err = GetBackupByID(repository.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupStatusFinished, backup.Status)
+	assert.Equal(t, "repository-2.zip", backup.Path)
+	assert.EqualValues(t, 42, backup.Size)
+	assert.NotZero(t, backup.FinishedUnix)
+
+	backups, count, err := FindBackups(&FindBackupsOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, backups, 2) {
+		assert.Equal(t, repository.ID, backups[0].ID)
+	}
+
+	tp := BackupTypeInstance
+	backups, count, err = FindBackups(&FindBackupsOptions{Type: &tp})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, backups, 1) {
+		assert.Equal(t, instance.ID, backups[0].ID)
+	}
+
+	_, err = GetBackupByID(repository.ID + 1)
+	assert.Equal(t, ErrBackupNotExist, err)
+}
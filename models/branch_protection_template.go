@@ -0,0 +1,338 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+)
+
+// BranchProtectionTemplate is a named branch protection of an organization applied to the branches of its repositories
+// matching a glob pattern
+type BranchProtectionTemplate struct {
+	ID    int64  `xorm:"pk autoincr"`
+	OrgID int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Name  string `xorm:"UNIQUE(s) NOT NULL"`
+	// BranchPattern is a glob matching the names of the protected branches, e.g. "release/*"
+	BranchPattern string `xorm:"NOT NULL"`
+	// ApplyToNewRepos is true if the template is applied to the repositories created in the organization
+	ApplyToNewRepos bool `xorm:"NOT NULL DEFAULT false"`
+
+	CanPush                       bool     `xorm:"NOT NULL DEFAULT false"`
+	EnableWhitelist               bool     `xorm:"NOT NULL DEFAULT false"`
+	WhitelistUserIDs              []int64  `xorm:"JSON TEXT"`
+	WhitelistTeamIDs              []int64  `xorm:"JSON TEXT"`
+	EnableMergeWhitelist          bool     `xorm:"NOT NULL DEFAULT false"`
+	WhitelistDeployKeys           bool     `xorm:"NOT NULL DEFAULT false"`
+	MergeWhitelistUserIDs         []int64  `xorm:"JSON TEXT"`
+	MergeWhitelistTeamIDs         []int64  `xorm:"JSON TEXT"`
+	EnableStatusCheck             bool     `xorm:"NOT NULL DEFAULT false"`
+	StatusCheckContexts           []string `xorm:"JSON TEXT"`
+	StatusCheckMaxAge             int64    `xorm:"NOT NULL DEFAULT 0"`
+	EnableApprovalsWhitelist      bool     `xorm:"NOT NULL DEFAULT false"`
+	ApprovalsWhitelistUserIDs     []int64  `xorm:"JSON TEXT"`
+	ApprovalsWhitelistTeamIDs     []int64  `xorm:"JSON TEXT"`
+	RequiredApprovals             int64    `xorm:"NOT NULL DEFAULT 0"`
+	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
+	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
+	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+	DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
+	ProtectedFilePatterns         string   `xorm:"TEXT"`
+	UnprotectedFilePatterns       string   `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+
+	branchGlob glob.Glob `xorm:"-"`
+}
+
+// BranchProtectionTemplateRepo links a repository to a branch protection template applied to it
+type BranchProtectionTemplateRepo struct {
+	ID         int64 `xorm:"pk autoincr"`
+	TemplateID int64 `xorm:"UNIQUE(s) NOT NULL"`
+	RepoID     int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(BranchProtectionTemplate))
+	db.RegisterModel(new(BranchProtectionTemplateRepo))
+}
+
+// ErrBranchProtectionTemplateNotExist represents a "BranchProtectionTemplateNotExist" kind of error.
+type ErrBranchProtectionTemplateNotExist struct {
+	OrgID int64
+	Name  string
+}
+
+// IsErrBranchProtectionTemplateNotExist checks if an error is a ErrBranchProtectionTemplateNotExist.
+func IsErrBranchProtectionTemplateNotExist(err error) bool {
+	_, ok := err.(ErrBranchProtectionTemplateNotExist)
+	return ok
+}
+
+func (err ErrBranchProtectionTemplateNotExist) Error() string {
+	return fmt.Sprintf("branch protection template does not exist [org_id: %d, name: %s]", err.OrgID, err.Name)
+}
+
+// ErrBranchProtectionTemplateAlreadyExist represents a "BranchProtectionTemplateAlreadyExist" kind of error.
+type ErrBranchProtectionTemplateAlreadyExist struct {
+	OrgID int64
+	Name  string
+}
+
+// IsErrBranchProtectionTemplateAlreadyExist checks if an error is a ErrBranchProtectionTemplateAlreadyExist.
+func IsErrBranchProtectionTemplateAlreadyExist(err error) bool {
+	_, ok := err.(ErrBranchProtectionTemplateAlreadyExist)
+	return ok
+}
+
+func (err ErrBranchProtectionTemplateAlreadyExist) Error() string {
+	return fmt.Sprintf("branch protection template already exists [org_id: %d, name: %s]", err.OrgID, err.Name)
+}
+
+// ErrInvalidBranchPattern represents a "InvalidBranchPattern" kind of error.
+type ErrInvalidBranchPattern struct {
+	Pattern string
+	Err     error
+}
+
+// IsErrInvalidBranchPattern checks if an error is a ErrInvalidBranchPattern.
+func IsErrInvalidBranchPattern(err error) bool {
+	_, ok := err.(ErrInvalidBranchPattern)
+	return ok
+}
+
+func (err ErrInvalidBranchPattern) Error() string {
+	return fmt.Sprintf("invalid branch pattern %q: %v", err.Pattern, err.Err)
+}
+
+// Validate checks the branch pattern of the template
+func (t *BranchProtectionTemplate) Validate() error {
+	if t.BranchPattern == "" {
+		return ErrInvalidBranchPattern{Pattern: t.BranchPattern, Err: errors.New("the pattern is empty")}
+	}
+	g, err := glob.Compile(t.BranchPattern, '/')
+	if err != nil {
+		return ErrInvalidBranchPattern{Pattern: t.BranchPattern, Err: err}
+	}
+	t.branchGlob = g
+	return nil
+}
+
+// MatchBranch returns true if the template protects the branch
+func (t *BranchProtectionTemplate) MatchBranch(branchName string) bool {
+	if t.branchGlob == nil {
+		if err := t.Validate(); err != nil {
+			log.Warn("Branch protection template %d has an invalid pattern: %v", t.ID, err)
+			return false
+		}
+	}
+	return t.branchGlob.Match(branchName)
+}
+
+// WhitelistOptions returns the users and teams whitelisted by the template
+func (t *BranchProtectionTemplate) WhitelistOptions() WhitelistOptions {
+	return WhitelistOptions{
+		UserIDs:          t.WhitelistUserIDs,
+		TeamIDs:          t.WhitelistTeamIDs,
+		MergeUserIDs:     t.MergeWhitelistUserIDs,
+		MergeTeamIDs:     t.MergeWhitelistTeamIDs,
+		ApprovalsUserIDs: t.ApprovalsWhitelistUserIDs,
+		ApprovalsTeamIDs: t.ApprovalsWhitelistTeamIDs,
+	}
+}
+
+// ApplyTo sets the settings of the template on a branch protection, the whitelists are set by UpdateProtectBranch
+func (t *BranchProtectionTemplate) ApplyTo(protectBranch *ProtectedBranch) {
+	protectBranch.CanPush = t.CanPush
+	protectBranch.EnableWhitelist = t.EnableWhitelist
+	protectBranch.EnableMergeWhitelist = t.EnableMergeWhitelist
+	protectBranch.WhitelistDeployKeys = t.WhitelistDeployKeys
+	protectBranch.EnableStatusCheck = t.EnableStatusCheck
+	protectBranch.StatusCheckContexts = t.StatusCheckContexts
+	protectBranch.StatusCheckMaxAge = t.StatusCheckMaxAge
+	protectBranch.EnableApprovalsWhitelist = t.EnableApprovalsWhitelist
+	protectBranch.RequiredApprovals = t.RequiredApprovals
+	protectBranch.BlockOnRejectedReviews = t.BlockOnRejectedReviews
+	protectBranch.BlockOnOfficialReviewRequests = t.BlockOnOfficialReviewRequests
+	protectBranch.BlockOnOutdatedBranch = t.BlockOnOutdatedBranch
+	protectBranch.DismissStaleApprovals = t.DismissStaleApprovals
+	protectBranch.RequireSignedCommits = t.RequireSignedCommits
+	protectBranch.ProtectedFilePatterns = t.ProtectedFilePatterns
+	protectBranch.UnprotectedFilePatterns = t.UnprotectedFilePatterns
+}
+
+// Differences returns the API names of the settings of a branch protection which deviate from the template
+func (t *BranchProtectionTemplate) Differences(protectBranch *ProtectedBranch) []string {
+	var diffs []string
+	check := func(name string, equal bool) {
+		if !equal {
+			diffs = append(diffs, name)
+		}
+	}
+	check("enable_push", protectBranch.CanPush == t.CanPush)
+	check("enable_push_whitelist", protectBranch.EnableWhitelist == t.EnableWhitelist)
+	check("push_whitelist_usernames", sameInt64s(protectBranch.WhitelistUserIDs, t.WhitelistUserIDs))
+	check("push_whitelist_teams", sameInt64s(protectBranch.WhitelistTeamIDs, t.WhitelistTeamIDs))
+	check("push_whitelist_deploy_keys", protectBranch.WhitelistDeployKeys == t.WhitelistDeployKeys)
+	check("enable_merge_whitelist", protectBranch.EnableMergeWhitelist == t.EnableMergeWhitelist)
+	check("merge_whitelist_usernames", sameInt64s(protectBranch.MergeWhitelistUserIDs, t.MergeWhitelistUserIDs))
+	check("merge_whitelist_teams", sameInt64s(protectBranch.MergeWhitelistTeamIDs, t.MergeWhitelistTeamIDs))
+	check("enable_status_check", protectBranch.EnableStatusCheck == t.EnableStatusCheck)
+	check("status_check_contexts", sameStrings(protectBranch.StatusCheckContexts, t.StatusCheckContexts))
+	check("status_check_max_age", protectBranch.StatusCheckMaxAge == t.StatusCheckMaxAge)
+	check("required_approvals", protectBranch.RequiredApprovals == t.RequiredApprovals)
+	check("enable_approvals_whitelist", protectBranch.EnableApprovalsWhitelist == t.EnableApprovalsWhitelist)
+	check("approvals_whitelist_username", sameInt64s(protectBranch.ApprovalsWhitelistUserIDs, t.ApprovalsWhitelistUserIDs))
+	check("approvals_whitelist_teams", sameInt64s(protectBranch.ApprovalsWhitelistTeamIDs, t.ApprovalsWhitelistTeamIDs))
+	check("block_on_rejected_reviews", protectBranch.BlockOnRejectedReviews == t.BlockOnRejectedReviews)
+	check("block_on_official_review_requests", protectBranch.BlockOnOfficialReviewRequests == t.BlockOnOfficialReviewRequests)
+	check("block_on_outdated_branch", protectBranch.BlockOnOutdatedBranch == t.BlockOnOutdatedBranch)
+	check("dismiss_stale_approvals", protectBranch.DismissStaleApprovals == t.DismissStaleApprovals)
+	check("require_signed_commits", protectBranch.RequireSignedCommits == t.RequireSignedCommits)
+	check("protected_file_patterns", strings.TrimSpace(protectBranch.ProtectedFilePatterns) == strings.TrimSpace(t.ProtectedFilePatterns))
+	check("unprotected_file_patterns", strings.TrimSpace(protectBranch.UnprotectedFilePatterns) == strings.TrimSpace(t.UnprotectedFilePatterns))
+	return diffs
+}
+
+// sameInt64s returns true if both lists have the same values in any order
+func sameInt64s(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]int64(nil), a...), append([]int64(nil), b...)
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+	sort.Slice(b, func(i, j int) bool { return b[i] < b[j] })
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameStrings returns true if both lists have the same values in any order
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateBranchProtectionTemplate inserts a new branch protection template
+func CreateBranchProtectionTemplate(t *BranchProtectionTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		has, err := ctx.Engine().Exist(&BranchProtectionTemplate{OrgID: t.OrgID, Name: t.Name})
+		if err != nil {
+			return err
+		} else if has {
+			return ErrBranchProtectionTemplateAlreadyExist{OrgID: t.OrgID, Name: t.Name}
+		}
+		_, err = ctx.Engine().Insert(t)
+		return err
+	})
+}
+
+// UpdateBranchProtectionTemplate stores the settings of a branch protection template
+func UpdateBranchProtectionTemplate(t *BranchProtectionTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	_, err := db.DefaultContext().Engine().ID(t.ID).AllCols().Update(t)
+	return err
+}
+
+// GetBranchProtectionTemplateByName returns a branch protection template of an organization
+func GetBranchProtectionTemplateByName(orgID int64, name string) (*BranchProtectionTemplate, error) {
+	t := &BranchProtectionTemplate{OrgID: orgID, Name: name}
+	has, err := db.DefaultContext().Engine().Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrBranchProtectionTemplateNotExist{OrgID: orgID, Name: name}
+	}
+	return t, nil
+}
+
+// GetBranchProtectionTemplates returns the branch protection templates of an organization
+func GetBranchProtectionTemplates(orgID int64) ([]*BranchProtectionTemplate, error) {
+	templates := make([]*BranchProtectionTemplate, 0, 5)
+	return templates, db.DefaultContext().Engine().Where("org_id = ?", orgID).Asc("name").Find(&templates)
+}
+
+// GetNewRepoBranchProtectionTemplates returns the branch protection templates applied to the new repositories of an organization
+func GetNewRepoBranchProtectionTemplates(orgID int64) ([]*BranchProtectionTemplate, error) {
+	templates := make([]*BranchProtectionTemplate, 0, 5)
+	return templates, db.DefaultContext().Engine().Where("org_id = ? AND apply_to_new_repos = ?", orgID, true).Asc("name").Find(&templates)
+}
+
+// GetRepoBranchProtectionTemplates returns the branch protection templates applied to a repository
+func GetRepoBranchProtectionTemplates(repoID int64) ([]*BranchProtectionTemplate, error) {
+	templates := make([]*BranchProtectionTemplate, 0, 5)
+	return templates, db.DefaultContext().Engine().
+		Join("INNER", "branch_protection_template_repo", "branch_protection_template_repo.template_id = branch_protection_template.id").
+		Where("branch_protection_template_repo.repo_id = ?", repoID).
+		Asc("branch_protection_template.name").
+		Find(&templates)
+}
+
+// DeleteBranchProtectionTemplate deletes a branch protection template, the branch protections created from it are kept
+func DeleteBranchProtectionTemplate(t *BranchProtectionTemplate) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		return deleteBeans(ctx.Engine(),
+			&BranchProtectionTemplateRepo{TemplateID: t.ID},
+			&BranchProtectionTemplate{ID: t.ID},
+		)
+	})
+}
+
+// LinkBranchProtectionTemplate records that a template is applied to a repository
+func LinkBranchProtectionTemplate(templateID, repoID int64) error {
+	link := &BranchProtectionTemplateRepo{TemplateID: templateID, RepoID: repoID}
+	return db.WithTx(func(ctx *db.Context) error {
+		has, err := ctx.Engine().Exist(link)
+		if err != nil || has {
+			return err
+		}
+		_, err = ctx.Engine().Insert(link)
+		return err
+	})
+}
+
+// UnlinkBranchProtectionTemplate records that a template is no longer applied to a repository
+func UnlinkBranchProtectionTemplate(templateID, repoID int64) error {
+	_, err := db.DefaultContext().Engine().Delete(&BranchProtectionTemplateRepo{TemplateID: templateID, RepoID: repoID})
+	return err
+}
+
+// GetBranchProtectionTemplateRepos returns the repositories a template is applied to
+func GetBranchProtectionTemplateRepos(templateID int64) ([]*Repository, error) {
+	repos := make([]*Repository, 0, 10)
+	return repos, db.DefaultContext().Engine().
+		Join("INNER", "branch_protection_template_repo", "branch_protection_template_repo.repo_id = repository.id").
+		Where("branch_protection_template_repo.template_id = ?", templateID).
+		Asc("repository.lower_name").
+		Find(&repos)
+}
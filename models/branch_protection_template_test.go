@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchProtectionTemplate(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	tmpl := &BranchProtectionTemplate{OrgID: 3, Name: "release", BranchPattern: "release/*", RequiredApprovals: 1}
+	assert.NoError(t, CreateBranchProtectionTemplate(tmpl))
+	assert.True(t, IsErrBranchProtectionTemplateAlreadyExist(CreateBranchProtectionTemplate(&BranchProtectionTemplate{OrgID: 3, Name: "release", BranchPattern: "main"})))
+	assert.True(t, IsErrInvalidBranchPattern(CreateBranchProtectionTemplate(&BranchProtectionTemplate{OrgID: 3, Name: "broken", BranchPattern: "release/["})))
+	assert.True(t, IsErrInvalidBranchPattern(CreateBranchProtectionTemplate(&BranchProtectionTemplate{OrgID: 3, Name: "empty"})))
+
+	tmpl, err := GetBranchProtectionTemplateByName(3, "release")
+	assert.NoError(t, err)
+	assert.True(t, tmpl.MatchBranch("release/1.0"))
+	assert.False(t, tmpl.MatchBranch("release/1.0/hotfix"))
+	assert.False(t, tmpl.MatchBranch("master"))
+
+	_, err = GetBranchProtectionTemplateByName(3, "missing")
+	assert.True(t, IsErrBranchProtectionTemplateNotExist(err))
+
+	protectBranch := &ProtectedBranch{RepoID: 3, BranchName: "release/1.0"}
+	assert.Equal(t, []string{"required_approvals"}, tmpl.Differences(protectBranch))
+	tmpl.ApplyTo(protectBranch)
+	assert.Empty(t, tmpl.Differences(protectBranch))
+
+	assert.NoError(t, LinkBranchProtectionTemplate(tmpl.ID, 3))
+	assert.NoError(t, LinkBranchProtectionTemplate(tmpl.ID, 3))
+	templates, err := GetRepoBranchProtectionTemplates(3)
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+	repos, err := GetBranchProtectionTemplateRepos(tmpl.ID)
+	assert.NoError(t, err)
+	assert.Len(t, repos, 1)
+
+	assert.NoError(t, DeleteBranchProtectionTemplate(tmpl))
+	templates, err = GetRepoBranchProtectionTemplates(3)
+	assert.NoError(t, err)
+	assert.Empty(t, templates)
+}
@@ -34,6 +34,7 @@ type ProtectedBranch struct {
 	MergeWhitelistTeamIDs         []int64  `xorm:"JSON TEXT"`
 	EnableStatusCheck             bool     `xorm:"NOT NULL DEFAULT false"`
 	StatusCheckContexts           []string `xorm:"JSON TEXT"`
+	StatusCheckMaxAge             int64    `xorm:"NOT NULL DEFAULT 0"`
 	EnableApprovalsWhitelist      bool     `xorm:"NOT NULL DEFAULT false"`
 	ApprovalsWhitelistUserIDs     []int64  `xorm:"JSON TEXT"`
 	ApprovalsWhitelistTeamIDs     []int64  `xorm:"JSON TEXT"`
@@ -53,6 +54,7 @@ type ProtectedBranch struct {
 func init() {
 	db.RegisterModel(new(ProtectedBranch))
 	db.RegisterModel(new(DeletedBranch))
+	db.RegisterModel(new(RenamedBranch))
 }
 
 // IsProtected returns if the branch is protected
@@ -219,6 +221,13 @@ func (protectBranch *ProtectedBranch) MergeBlockedByOutdatedBranch(pr *PullReque
 	return protectBranch.BlockOnOutdatedBranch && pr.CommitsBehind > 0
 }
 
+// IsStatusCheckStale returns true if a commit status was reported more than StatusCheckMaxAge minutes ago,
+// statuses never expire if StatusCheckMaxAge is 0
+func (protectBranch *ProtectedBranch) IsStatusCheckStale(status *CommitStatus) bool {
+	return protectBranch.StatusCheckMaxAge > 0 &&
+		time.Since(status.UpdatedUnix.AsTime()) > time.Duration(protectBranch.StatusCheckMaxAge)*time.Minute
+}
+
 // GetProtectedFilePatterns parses a semicolon separated list of protected file patterns and returns a glob.Glob slice
 func (protectBranch *ProtectedBranch) GetProtectedFilePatterns() []glob.Glob {
 	return getFilePatterns(protectBranch.ProtectedFilePatterns)
@@ -588,3 +597,113 @@ func RemoveOldDeletedBranches(ctx context.Context, olderThan time.Duration) {
 		log.Error("DeletedBranchesCleanup: %v", err)
 	}
 }
+
+// RenamedBranch records the old name of a renamed branch, requests for the old name are redirected to the new one
+type RenamedBranch struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	From        string             `xorm:"UNIQUE(s) NOT NULL"`
+	To          string             `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// FindRenamedBranch returns the rename of a branch which does not exist anymore, nil if it was not renamed
+func FindRenamedBranch(repoID int64, from string) (*RenamedBranch, error) {
+	branch := &RenamedBranch{RepoID: repoID, From: from}
+	has, err := db.DefaultContext().Engine().Get(branch)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return branch, nil
+}
+
+// RenameBranch updates the references of the database to a branch which is renamed by gitAction:
+// the default branch, the website link, the branch protection, the base and head of the unmerged pull requests,
+// the target of the draft releases, the branch of the pages site and the issue branch.
+// The old name is recorded to redirect the requests for it.
+func (repo *Repository) RenameBranch(from, to string, gitAction func(isDefault bool) error) error {
+	isDefault := repo.DefaultBranch == from
+	updated := &Repository{DefaultBranch: repo.DefaultBranch, Website: repo.Website}
+	if isDefault {
+		updated.DefaultBranch = to
+	}
+	// the website of the repository may link to a directory of the branch
+	branchLink := repo.HTMLURL() + "/src/branch/" + util.PathEscapeSegments(from)
+	if repo.Website == branchLink || strings.HasPrefix(repo.Website, branchLink+"/") {
+		updated.Website = repo.HTMLURL() + "/src/branch/" + util.PathEscapeSegments(to) + strings.TrimPrefix(repo.Website, branchLink)
+	}
+
+	if err := db.WithTx(func(ctx *db.Context) error {
+		sess := ctx.Engine()
+
+		if _, err := sess.ID(repo.ID).Cols("default_branch", "website").Update(updated); err != nil {
+			return err
+		}
+
+		protectBranch, err := getProtectedBranchBy(sess, repo.ID, from)
+		if err != nil {
+			return err
+		}
+		if protectBranch != nil {
+			// a protection of the new name can only be left over from a deleted branch
+			if _, err := sess.Delete(&ProtectedBranch{RepoID: repo.ID, BranchName: to}); err != nil {
+				return err
+			}
+			protectBranch.BranchName = to
+			if _, err := sess.ID(protectBranch.ID).Cols("branch_name").Update(protectBranch); err != nil {
+				return err
+			}
+		}
+
+		if _, err := sess.Table("pull_request").
+			Where("base_repo_id = ? AND base_branch = ? AND has_merged = ?", repo.ID, from, false).
+			Update(map[string]interface{}{"base_branch": to}); err != nil {
+			return err
+		}
+		if _, err := sess.Table("pull_request").
+			Where("head_repo_id = ? AND head_branch = ? AND has_merged = ?", repo.ID, from, false).
+			Update(map[string]interface{}{"head_branch": to}); err != nil {
+			return err
+		}
+
+		if _, err := sess.Table("release").
+			Where("repo_id = ? AND target = ? AND is_draft = ?", repo.ID, from, true).
+			Update(map[string]interface{}{"target": to}); err != nil {
+			return err
+		}
+		if _, err := sess.Table("repo_pages").
+			Where("repo_id = ? AND branch = ?", repo.ID, from).
+			Update(map[string]interface{}{"branch": to}); err != nil {
+			return err
+		}
+		if _, err := sess.Table("issue_branch").
+			Where("repo_id = ? AND branch_name = ?", repo.ID, from).
+			Update(map[string]interface{}{"branch_name": to}); err != nil {
+			return err
+		}
+
+		// the new name is not a redirect anymore and the redirects to the old name follow the rename
+		if _, err := sess.Delete(&RenamedBranch{RepoID: repo.ID, From: to}); err != nil {
+			return err
+		}
+		if _, err := sess.Cols("to").Update(&RenamedBranch{To: to}, &RenamedBranch{RepoID: repo.ID, To: from}); err != nil {
+			return err
+		}
+		if _, err := sess.Delete(&RenamedBranch{RepoID: repo.ID, From: from}); err != nil {
+			return err
+		}
+		if _, err := sess.Insert(&RenamedBranch{RepoID: repo.ID, From: from, To: to}); err != nil {
+			return err
+		}
+
+		return gitAction(isDefault)
+	}); err != nil {
+		return err
+	}
+
+	repo.DefaultBranch = updated.DefaultBranch
+	repo.Website = updated.Website
+	return nil
+}
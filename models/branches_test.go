@@ -5,6 +5,7 @@
 package models
 
 import (
+	"errors"
 	"testing"
 
 	"code.gitea.io/gitea/models/db"
@@ -79,3 +80,39 @@ func getDeletedBranch(t *testing.T, branch *DeletedBranch) *DeletedBranch {
 
 	return deletedBranch
 }
+
+func TestRenameBranch(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, UpdateProtectBranch(repo, &ProtectedBranch{RepoID: repo.ID, BranchName: "master"}, WhitelistOptions{}))
+
+	var gitActionCalled bool
+	assert.NoError(t, repo.RenameBranch("master", "main", func(isDefault bool) error {
+		gitActionCalled = true
+		assert.True(t, isDefault)
+		return nil
+	}))
+	assert.True(t, gitActionCalled)
+
+	db.AssertExistsAndLoadBean(t, &Repository{ID: 1, DefaultBranch: "main"})
+	db.AssertExistsAndLoadBean(t, &ProtectedBranch{RepoID: repo.ID, BranchName: "main"})
+	db.AssertExistsAndLoadBean(t, &PullRequest{ID: 1, BaseBranch: "master"})
+	db.AssertExistsAndLoadBean(t, &PullRequest{ID: 2, BaseBranch: "main"})
+
+	renamed, err := FindRenamedBranch(repo.ID, "master")
+	assert.NoError(t, err)
+	assert.Equal(t, "main", renamed.To)
+
+	// renaming the branch again keeps redirecting the first name
+	assert.NoError(t, repo.RenameBranch("main", "trunk", func(bool) error { return nil }))
+	renamed, err = FindRenamedBranch(repo.ID, "master")
+	assert.NoError(t, err)
+	assert.Equal(t, "trunk", renamed.To)
+
+	// a failing git action leaves the database unchanged
+	assert.Error(t, repo.RenameBranch("trunk", "master", func(bool) error { return errors.New("rename failed") }))
+	db.AssertExistsAndLoadBean(t, &Repository{ID: 1, DefaultBranch: "trunk"})
+	renamed, err = FindRenamedBranch(repo.ID, "main")
+	assert.NoError(t, err)
+	assert.Equal(t, "trunk", renamed.To)
+}
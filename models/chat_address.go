@@ -0,0 +1,178 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var (
+	// ErrChatAddressNotExist indicates a chat address not exist error
+	ErrChatAddressNotExist = errors.New("Chat address does not exist")
+	// ErrChatAddressVerificationFailed indicates that a chat address verification code is wrong
+	ErrChatAddressVerificationFailed = errors.New("Chat address verification code is invalid")
+)
+
+// ChatProtocol is the chat network a chat address belongs to
+type ChatProtocol int
+
+// Note: new protocol must append to the end of list to maintain compatibility.
+const (
+	ChatProtocolXMPP ChatProtocol = iota
+	ChatProtocolIRC
+	ChatProtocolMatrix
+)
+
+var chatProtocolNames = map[ChatProtocol]string{
+	ChatProtocolXMPP:   "xmpp",
+	ChatProtocolIRC:    "irc",
+	ChatProtocolMatrix: "matrix",
+}
+
+// String returns the name of the protocol
+func (p ChatProtocol) String() string {
+	return chatProtocolNames[p]
+}
+
+// ChatProtocolFromString returns the protocol with the given name
+func ChatProtocolFromString(name string) (ChatProtocol, bool) {
+	for p, n := range chatProtocolNames {
+		if n == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// ChatAddress is the address of a user on a chat network, which receives direct messages about mentions
+// and review requests once the user verified it
+type ChatAddress struct {
+	ID               int64        `xorm:"pk autoincr"`
+	UserID           int64        `xorm:"UNIQUE(s) NOT NULL"`
+	Protocol         ChatProtocol `xorm:"UNIQUE(s) NOT NULL"`
+	Address          string       `xorm:"NOT NULL"`
+	IsVerified       bool         `xorm:"NOT NULL DEFAULT false"`
+	VerificationCode string
+	// RoomID is the direct message room created for a Matrix address
+	RoomID string
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ChatAddress))
+}
+
+// BindChatAddress sets the address of a user on a chat network. A new address must be verified
+// with the returned verification code before it receives messages.
+func BindChatAddress(userID int64, protocol ChatProtocol, address string) (*ChatAddress, error) {
+	chatAddress := &ChatAddress{UserID: userID, Protocol: protocol}
+	err := db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		has, err := e.Get(chatAddress)
+		if err != nil {
+			return err
+		}
+		if has && chatAddress.Address == address && chatAddress.IsVerified {
+			return nil
+		}
+
+		code, err := util.RandomString(8)
+		if err != nil {
+			return err
+		}
+		chatAddress.Address = address
+		chatAddress.IsVerified = false
+		chatAddress.VerificationCode = code
+		chatAddress.RoomID = ""
+		if !has {
+			_, err = e.Insert(chatAddress)
+			return err
+		}
+		_, err = e.ID(chatAddress.ID).AllCols().Update(chatAddress)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chatAddress, nil
+}
+
+// VerifyChatAddress marks the chat address of a user as verified if the code matches
+func VerifyChatAddress(userID int64, protocol ChatProtocol, code string) (*ChatAddress, error) {
+	chatAddress, err := GetChatAddress(userID, protocol)
+	if err != nil {
+		return nil, err
+	}
+	if chatAddress.IsVerified {
+		return chatAddress, nil
+	}
+	if code == "" || chatAddress.VerificationCode != code {
+		return nil, ErrChatAddressVerificationFailed
+	}
+
+	chatAddress.IsVerified = true
+	chatAddress.VerificationCode = ""
+	if _, err := db.DefaultContext().Engine().ID(chatAddress.ID).Cols("is_verified", "verification_code").Update(chatAddress); err != nil {
+		return nil, err
+	}
+	return chatAddress, nil
+}
+
+// GetChatAddress returns the address of a user on a chat network
+func GetChatAddress(userID int64, protocol ChatProtocol) (*ChatAddress, error) {
+	chatAddress := &ChatAddress{UserID: userID, Protocol: protocol}
+	has, err := db.DefaultContext().Engine().Get(chatAddress)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrChatAddressNotExist
+	}
+	return chatAddress, nil
+}
+
+// GetChatAddresses returns the chat addresses of a user
+func GetChatAddresses(userID int64) ([]*ChatAddress, error) {
+	addresses := make([]*ChatAddress, 0, len(chatProtocolNames))
+	return addresses, db.DefaultContext().Engine().
+		Where("user_id = ?", userID).
+		OrderBy("protocol").
+		Find(&addresses)
+}
+
+// GetVerifiedChatAddressesByUserIDs returns the verified chat addresses of the given users on the given networks
+func GetVerifiedChatAddressesByUserIDs(userIDs []int64, protocols []ChatProtocol) ([]*ChatAddress, error) {
+	addresses := make([]*ChatAddress, 0, len(userIDs))
+	if len(userIDs) == 0 || len(protocols) == 0 {
+		return addresses, nil
+	}
+	return addresses, db.DefaultContext().Engine().
+		In("user_id", userIDs).
+		In("protocol", protocols).
+		And("is_verified = ?", true).
+		Find(&addresses)
+}
+
+// UpdateChatAddressRoom saves the direct message room of a chat address
+func UpdateChatAddressRoom(chatAddress *ChatAddress) error {
+	_, err := db.DefaultContext().Engine().ID(chatAddress.ID).Cols("room_id").Update(chatAddress)
+	return err
+}
+
+// DeleteChatAddress removes the address of a user on a chat network
+func DeleteChatAddress(userID int64, protocol ChatProtocol) error {
+	deleted, err := db.DefaultContext().Engine().Delete(&ChatAddress{UserID: userID, Protocol: protocol})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrChatAddressNotExist
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindChatAddress(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	chatAddress, err := BindChatAddress(2, ChatProtocolMatrix, "@user2:example.com")
+	assert.NoError(t, err)
+	assert.False(t, chatAddress.IsVerified)
+	assert.NotEmpty(t, chatAddress.VerificationCode)
+
+	addresses, err := GetVerifiedChatAddressesByUserIDs([]int64{2}, []ChatProtocol{ChatProtocolMatrix})
+	assert.NoError(t, err)
+	assert.Empty(t, addresses)
+
+	_, err = VerifyChatAddress(2, ChatProtocolMatrix, "wrong")
+	assert.Equal(t, ErrChatAddressVerificationFailed, err)
+	_, err = VerifyChatAddress(2, ChatProtocolIRC, chatAddress.VerificationCode)
+	assert.Equal(t, ErrChatAddressNotExist, err)
+	verified, err := VerifyChatAddress(2, ChatProtocolMatrix, chatAddress.VerificationCode)
+	assert.NoError(t, err)
+	assert.True(t, verified.IsVerified)
+
+	addresses, err = GetVerifiedChatAddressesByUserIDs([]int64{2}, []ChatProtocol{ChatProtocolMatrix})
+	assert.NoError(t, err)
+	assert.Len(t, addresses, 1)
+
+	// binding the same address keeps it verified
+	chatAddress, err = BindChatAddress(2, ChatProtocolMatrix, "@user2:example.com")
+	assert.NoError(t, err)
+	assert.True(t, chatAddress.IsVerified)
+
+	// binding another address needs a new verification
+	chatAddress.RoomID = "!room:example.com"
+	assert.NoError(t, UpdateChatAddressRoom(chatAddress))
+	chatAddress, err = BindChatAddress(2, ChatProtocolMatrix, "@other:example.com")
+	assert.NoError(t, err)
+	assert.False(t, chatAddress.IsVerified)
+	assert.Empty(t, chatAddress.RoomID)
+	db.AssertCount(t, &ChatAddress{UserID: 2}, 1)
+
+	assert.NoError(t, DeleteChatAddress(2, ChatProtocolMatrix))
+	assert.Equal(t, ErrChatAddressNotExist, DeleteChatAddress(2, ChatProtocolMatrix))
+}
@@ -156,6 +156,7 @@ func SetEngine() (err error) {
 	x.SetMaxOpenConns(setting.Database.MaxOpenConns)
 	x.SetMaxIdleConns(setting.Database.MaxIdleConns)
 	x.SetConnMaxLifetime(setting.Database.ConnMaxLifetime)
+	x.AddHook(readOnlyHook{})
 	return nil
 }
 
@@ -230,6 +231,14 @@ func Ping() error {
 	return errors.New("database not configured")
 }
 
+// GetDBStats returns the statistics of the database connection pool
+func GetDBStats() sql.DBStats {
+	if x == nil {
+		return sql.DBStats{}
+	}
+	return x.DB().Stats()
+}
+
 // DumpDatabase dumps all data from database according the special database SQL syntax to file system.
 func DumpDatabase(filePath, dbType string) error {
 	var tbs []*schemas.Table
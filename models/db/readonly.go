@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/xorm/contexts"
+)
+
+// ErrReadOnlyProbeUnsupported is returned by ProbeReadOnly if the database can only be found to be read-only by writing to it
+var ErrReadOnlyProbeUnsupported = errors.New("the database type does not support probing for read-only mode")
+
+// readOnlyErrorMessages are parts of the messages the drivers return when a write is refused by a read-only database,
+// errors are matched by their message because they are often wrapped with fmt.Errorf("...: %v", err)
+var readOnlyErrorMessages = []string{
+	"read-only option",                     // MySQL 1290: running with the --read-only or --super-read-only option
+	"running in read-only mode",            // MySQL 1836
+	"in a read-only transaction",           // PostgreSQL 25006
+	"in a read only transaction",           // MySQL 1792
+	"attempt to write a readonly database", // SQLite SQLITE_READONLY
+	"because the database is read-only",    // MSSQL 3906
+}
+
+// IsReadOnlyError returns true if the error was returned by the database because it is read-only, e.g. during a failover
+func IsReadOnlyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, part := range readOnlyErrorMessages {
+		if strings.Contains(msg, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// readOnlyHook records that the database is read-only as soon as a statement is refused,
+// so that the following writes are rejected before they reach the database
+type readOnlyHook struct{}
+
+func (readOnlyHook) BeforeProcess(c *contexts.ContextHook) (context.Context, error) {
+	return c.Ctx, nil
+}
+
+func (readOnlyHook) AfterProcess(c *contexts.ContextHook) error {
+	if IsReadOnlyError(c.Err) && maintenance.SetDatabaseReadOnly(true) {
+		log.Warn("Database is read-only, writes are rejected until it is writable again: %v", c.Err)
+	}
+	return nil
+}
+
+// ProbeReadOnly asks the database whether it is read-only
+func ProbeReadOnly() (bool, error) {
+	var query, readOnlyValue string
+	switch {
+	case setting.Database.UseMySQL:
+		query, readOnlyValue = "SELECT @@global.read_only", "1"
+	case setting.Database.UsePostgreSQL:
+		query, readOnlyValue = "SHOW transaction_read_only", "on"
+	case setting.Database.UseMSSQL:
+		query, readOnlyValue = "SELECT CAST(DATABASEPROPERTYEX(DB_NAME(), 'Updateability') AS NVARCHAR(20))", "READ_ONLY"
+	default:
+		return false, ErrReadOnlyProbeUnsupported
+	}
+	if x == nil {
+		return false, errors.New("database not configured")
+	}
+
+	var value string
+	if _, err := x.SQL(query).Get(&value); err != nil {
+		return false, err
+	}
+	return strings.EqualFold(strings.TrimSpace(value), readOnlyValue), nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnlyError(t *testing.T) {
+	for _, msg := range []string{
+		"Error 1290: The MySQL server is running with the --read-only option so it cannot execute this statement",
+		"Error 1290: The MySQL server is running with the --super-read-only option so it cannot execute this statement",
+		"Error 1792: Cannot execute statement in a READ ONLY transaction.",
+		"pq: cannot execute INSERT in a read-only transaction",
+		"attempt to write a readonly database",
+		`mssql: Failed to update database "gitea" because the database is read-only.`,
+	} {
+		assert.True(t, IsReadOnlyError(errors.New(msg)), msg)
+		assert.True(t, IsReadOnlyError(fmt.Errorf("NewIssue: %v", errors.New(msg))), msg)
+	}
+
+	assert.False(t, IsReadOnlyError(nil))
+	assert.False(t, IsReadOnlyError(errors.New("pq: duplicate key value violates unique constraint")))
+	assert.False(t, IsReadOnlyError(errors.New("dial tcp 127.0.0.1:3306: connect: connection refused")))
+}
@@ -80,6 +80,10 @@ func MainTest(m *testing.M, pathToGiteaRoot string) {
 
 	setting.RepoArchive.Storage.Path = filepath.Join(setting.AppDataPath, "repo-archive")
 
+	setting.Packages.Storage.Path = filepath.Join(setting.AppDataPath, "packages")
+
+	setting.Backup.Storage.Path = filepath.Join(setting.AppDataPath, "backups")
+
 	if err = storage.Init(); err != nil {
 		fatalTestError("storage.Init: %v\n", err)
 	}
@@ -1280,6 +1280,21 @@ func (err ErrSHANotFound) Error() string {
 	return fmt.Sprintf("sha not found [%s]", err.SHA)
 }
 
+// ErrBlobTooLarge represents a "BlobTooLarge" kind of error.
+type ErrBlobTooLarge struct {
+	MaxSize int64
+}
+
+// IsErrBlobTooLarge checks if an error is a ErrBlobTooLarge.
+func IsErrBlobTooLarge(err error) bool {
+	_, ok := err.(ErrBlobTooLarge)
+	return ok
+}
+
+func (err ErrBlobTooLarge) Error() string {
+	return fmt.Sprintf("blob is larger than %d bytes", err.MaxSize)
+}
+
 // ErrCommitIDDoesNotMatch represents a "CommitIDDoesNotMatch" kind of error.
 type ErrCommitIDDoesNotMatch struct {
 	GivenCommitID   string
@@ -1928,6 +1943,23 @@ func (err ErrTeamNotExist) Error() string {
 	return fmt.Sprintf("team does not exist [org_id %d, team_id %d, name: %s]", err.OrgID, err.TeamID, err.Name)
 }
 
+// ErrTeamParentInvalid represents a parent team which can not be assigned to a team
+type ErrTeamParentInvalid struct {
+	TeamID   int64
+	ParentID int64
+	Reason   string
+}
+
+// IsErrTeamParentInvalid checks if an error is a ErrTeamParentInvalid.
+func IsErrTeamParentInvalid(err error) bool {
+	_, ok := err.(ErrTeamParentInvalid)
+	return ok
+}
+
+func (err ErrTeamParentInvalid) Error() string {
+	return fmt.Sprintf("invalid parent team [team_id: %d, parent_team_id: %d]: %s", err.TeamID, err.ParentID, err.Reason)
+}
+
 //
 // Two-factor authentication
 //
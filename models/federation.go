@@ -0,0 +1,395 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"net/url"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// FederationOwnerType represents the kind of the local actor a federation record belongs to
+type FederationOwnerType string
+
+// FederationOwnerTypes
+const (
+	FederationOwnerUser FederationOwnerType = "user"
+	FederationOwnerRepo FederationOwnerType = "repo"
+)
+
+// FederationKey represents the key pair the requests of a local actor are signed with
+type FederationKey struct {
+	ID          int64               `xorm:"pk autoincr"`
+	OwnerType   FederationOwnerType `xorm:"VARCHAR(10) UNIQUE(s) NOT NULL"`
+	OwnerID     int64               `xorm:"UNIQUE(s) NOT NULL"`
+	PrivateKey  string              `xorm:"TEXT NOT NULL"`
+	PublicKey   string              `xorm:"TEXT NOT NULL"`
+	CreatedUnix timeutil.TimeStamp  `xorm:"created"`
+}
+
+// RemoteActor represents an actor of another instance which federates with Gitea
+type RemoteActor struct {
+	ID                int64  `xorm:"pk autoincr"`
+	URI               string `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+	Type              string
+	PreferredUsername string
+	Host              string `xorm:"INDEX"`
+	Inbox             string `xorm:"TEXT NOT NULL"`
+	SharedInbox       string `xorm:"TEXT"`
+	PublicKeyID       string `xorm:"VARCHAR(255) INDEX"`
+	PublicKey         string `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// FederationFollower represents a remote actor following a local user or repository
+type FederationFollower struct {
+	ID            int64               `xorm:"pk autoincr"`
+	OwnerType     FederationOwnerType `xorm:"VARCHAR(10) UNIQUE(s) NOT NULL"`
+	OwnerID       int64               `xorm:"UNIQUE(s) NOT NULL"`
+	RemoteActorID int64               `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix   timeutil.TimeStamp  `xorm:"created"`
+}
+
+// FederationFollowing represents a local user following a remote actor,
+// it is accepted once the instance of the remote actor confirmed it
+type FederationFollowing struct {
+	ID            int64        `xorm:"pk autoincr"`
+	UserID        int64        `xorm:"UNIQUE(s) NOT NULL"`
+	RemoteActorID int64        `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RemoteActor   *RemoteActor `xorm:"-"`
+	// ActivityURI is the ID of the Follow activity, the remote instance refers to it when accepting the follow
+	ActivityURI string             `xorm:"VARCHAR(255) INDEX"`
+	IsAccepted  bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// FederationActivity represents an activity published by a local actor,
+// or received by a local user from a remote actor it follows
+type FederationActivity struct {
+	ID        int64               `xorm:"pk autoincr"`
+	OwnerType FederationOwnerType `xorm:"VARCHAR(10) INDEX(o) NOT NULL"`
+	OwnerID   int64               `xorm:"INDEX(o) NOT NULL"`
+	// RemoteActorID is the actor the activity was received from, 0 if it was published by the owner
+	RemoteActorID int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	RemoteActor   *RemoteActor       `xorm:"-"`
+	URI           string             `xorm:"VARCHAR(255) INDEX NOT NULL"`
+	Type          string             `xorm:"VARCHAR(50)"`
+	Content       string             `xorm:"LONGTEXT NOT NULL"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(FederationKey))
+	db.RegisterModel(new(RemoteActor))
+	db.RegisterModel(new(FederationFollower))
+	db.RegisterModel(new(FederationFollowing))
+	db.RegisterModel(new(FederationActivity))
+}
+
+// ErrFederationKeyNotExist represents a "FederationKeyNotExist" kind of error.
+type ErrFederationKeyNotExist struct {
+	OwnerType FederationOwnerType
+	OwnerID   int64
+}
+
+// IsErrFederationKeyNotExist checks if an error is a ErrFederationKeyNotExist.
+func IsErrFederationKeyNotExist(err error) bool {
+	_, ok := err.(ErrFederationKeyNotExist)
+	return ok
+}
+
+func (err ErrFederationKeyNotExist) Error() string {
+	return fmt.Sprintf("federation key does not exist [owner_type: %s, owner_id: %d]", err.OwnerType, err.OwnerID)
+}
+
+// ErrRemoteActorNotExist represents a "RemoteActorNotExist" kind of error.
+type ErrRemoteActorNotExist struct {
+	ID  int64
+	URI string
+}
+
+// IsErrRemoteActorNotExist checks if an error is a ErrRemoteActorNotExist.
+func IsErrRemoteActorNotExist(err error) bool {
+	_, ok := err.(ErrRemoteActorNotExist)
+	return ok
+}
+
+func (err ErrRemoteActorNotExist) Error() string {
+	return fmt.Sprintf("remote actor does not exist [id: %d, uri: %s]", err.ID, err.URI)
+}
+
+// ErrFederationFollowingNotExist represents a "FederationFollowingNotExist" kind of error.
+type ErrFederationFollowingNotExist struct {
+	ID          int64
+	ActivityURI string
+}
+
+// IsErrFederationFollowingNotExist checks if an error is a ErrFederationFollowingNotExist.
+func IsErrFederationFollowingNotExist(err error) bool {
+	_, ok := err.(ErrFederationFollowingNotExist)
+	return ok
+}
+
+func (err ErrFederationFollowingNotExist) Error() string {
+	return fmt.Sprintf("federation following does not exist [id: %d, activity_uri: %s]", err.ID, err.ActivityURI)
+}
+
+// ErrFederationActivityNotExist represents a "FederationActivityNotExist" kind of error.
+type ErrFederationActivityNotExist struct {
+	URI string
+}
+
+// IsErrFederationActivityNotExist checks if an error is a ErrFederationActivityNotExist.
+func IsErrFederationActivityNotExist(err error) bool {
+	_, ok := err.(ErrFederationActivityNotExist)
+	return ok
+}
+
+func (err ErrFederationActivityNotExist) Error() string {
+	return fmt.Sprintf("federation activity does not exist [uri: %s]", err.URI)
+}
+
+// GetFederationKey returns the key pair of a local actor
+func GetFederationKey(ownerType FederationOwnerType, ownerID int64) (*FederationKey, error) {
+	key := &FederationKey{OwnerType: ownerType, OwnerID: ownerID}
+	has, err := db.DefaultContext().Engine().Get(key)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrFederationKeyNotExist{OwnerType: ownerType, OwnerID: ownerID}
+	}
+	return key, nil
+}
+
+// InsertFederationKey inserts the key pair of a local actor
+func InsertFederationKey(key *FederationKey) error {
+	_, err := db.DefaultContext().Engine().Insert(key)
+	return err
+}
+
+// Handle returns the handle of a remote actor, e.g. user@example.com
+func (actor *RemoteActor) Handle() string {
+	if actor.PreferredUsername == "" {
+		return actor.URI
+	}
+	return actor.PreferredUsername + "@" + actor.Host
+}
+
+func getRemoteActor(e db.Engine, cond *RemoteActor) (*RemoteActor, error) {
+	has, err := e.Get(cond)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRemoteActorNotExist{ID: cond.ID, URI: cond.URI}
+	}
+	return cond, nil
+}
+
+// GetRemoteActorByID returns the remote actor with the given ID
+func GetRemoteActorByID(id int64) (*RemoteActor, error) {
+	return getRemoteActor(db.DefaultContext().Engine(), &RemoteActor{ID: id})
+}
+
+// GetRemoteActorByURI returns the remote actor with the given ActivityPub ID
+func GetRemoteActorByURI(uri string) (*RemoteActor, error) {
+	return getRemoteActor(db.DefaultContext().Engine(), &RemoteActor{URI: uri})
+}
+
+// GetRemoteActorByKeyID returns the remote actor owning the given public key
+func GetRemoteActorByKeyID(keyID string) (*RemoteActor, error) {
+	return getRemoteActor(db.DefaultContext().Engine(), &RemoteActor{PublicKeyID: keyID})
+}
+
+// SaveRemoteActor inserts a remote actor or updates the actor with the same URI
+func SaveRemoteActor(actor *RemoteActor) error {
+	if u, err := url.Parse(actor.URI); err == nil {
+		actor.Host = u.Host
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		existing, err := getRemoteActor(e, &RemoteActor{URI: actor.URI})
+		if err != nil {
+			if !IsErrRemoteActorNotExist(err) {
+				return err
+			}
+			_, err = e.Insert(actor)
+			return err
+		}
+		actor.ID = existing.ID
+		_, err = e.ID(actor.ID).AllCols().Omit("created_unix").Update(actor)
+		return err
+	})
+}
+
+// AddFederationFollower adds a remote actor to the followers of a local user or repository
+func AddFederationFollower(ownerType FederationOwnerType, ownerID, remoteActorID int64) error {
+	follower := &FederationFollower{OwnerType: ownerType, OwnerID: ownerID, RemoteActorID: remoteActorID}
+	return db.WithTx(func(ctx *db.Context) error {
+		has, err := ctx.Engine().Exist(follower)
+		if err != nil || has {
+			return err
+		}
+		_, err = ctx.Engine().Insert(follower)
+		return err
+	})
+}
+
+// RemoveFederationFollower removes a remote actor from the followers of a local user or repository
+func RemoveFederationFollower(ownerType FederationOwnerType, ownerID, remoteActorID int64) error {
+	_, err := db.DefaultContext().Engine().Delete(&FederationFollower{OwnerType: ownerType, OwnerID: ownerID, RemoteActorID: remoteActorID})
+	return err
+}
+
+// GetFederationFollowers returns the remote actors following a local user or repository
+func GetFederationFollowers(ownerType FederationOwnerType, ownerID int64) ([]*RemoteActor, error) {
+	actors := make([]*RemoteActor, 0, 10)
+	return actors, db.DefaultContext().Engine().
+		Join("INNER", "federation_follower", "federation_follower.remote_actor_id = remote_actor.id").
+		Where("federation_follower.owner_type = ? AND federation_follower.owner_id = ?", ownerType, ownerID).
+		Asc("remote_actor.id").
+		Find(&actors)
+}
+
+// CountFederationFollowers returns the number of remote actors following a local user or repository
+func CountFederationFollowers(ownerType FederationOwnerType, ownerID int64) (int64, error) {
+	return db.DefaultContext().Engine().Count(&FederationFollower{OwnerType: ownerType, OwnerID: ownerID})
+}
+
+// CreateFederationFollowing records a local user following a remote actor, an existing record is replaced
+func CreateFederationFollowing(following *FederationFollowing) error {
+	following.IsAccepted = false
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Delete(&FederationFollowing{UserID: following.UserID, RemoteActorID: following.RemoteActorID}); err != nil {
+			return err
+		}
+		_, err := e.Insert(following)
+		return err
+	})
+}
+
+func getFederationFollowing(cond *FederationFollowing) (*FederationFollowing, error) {
+	has, err := db.DefaultContext().Engine().Get(cond)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrFederationFollowingNotExist{ID: cond.ID, ActivityURI: cond.ActivityURI}
+	}
+	return cond, nil
+}
+
+// GetFederationFollowingByID returns a remote actor followed by a local user
+func GetFederationFollowingByID(userID, id int64) (*FederationFollowing, error) {
+	return getFederationFollowing(&FederationFollowing{ID: id, UserID: userID})
+}
+
+// GetFederationFollowingByActivityURI returns the following created by a Follow activity
+func GetFederationFollowingByActivityURI(activityURI string) (*FederationFollowing, error) {
+	return getFederationFollowing(&FederationFollowing{ActivityURI: activityURI})
+}
+
+// IsFollowingRemoteActor returns whether a local user follows a remote actor and the remote instance accepted it
+func IsFollowingRemoteActor(userID, remoteActorID int64) (bool, error) {
+	return db.DefaultContext().Engine().
+		Where("user_id = ? AND remote_actor_id = ? AND is_accepted = ?", userID, remoteActorID, true).
+		Exist(new(FederationFollowing))
+}
+
+// GetFederationFollowings returns the remote actors followed by a local user, last followed first
+func GetFederationFollowings(userID int64, listOptions ListOptions) ([]*FederationFollowing, int64, error) {
+	sess := db.DefaultContext().Engine().Where("user_id = ?", userID).Desc("id")
+	if listOptions.Page > 0 {
+		sess = setSessionPagination(sess, &listOptions)
+	}
+	followings := make([]*FederationFollowing, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&followings)
+	return followings, count, err
+}
+
+// AcceptFederationFollowing marks a following as accepted by the remote instance
+func AcceptFederationFollowing(following *FederationFollowing) error {
+	following.IsAccepted = true
+	_, err := db.DefaultContext().Engine().ID(following.ID).Cols("is_accepted").Update(following)
+	return err
+}
+
+// DeleteFederationFollowing deletes a following of a local user
+func DeleteFederationFollowing(following *FederationFollowing) error {
+	_, err := db.DefaultContext().Engine().ID(following.ID).Delete(new(FederationFollowing))
+	return err
+}
+
+// LoadRemoteActor loads the followed remote actor
+func (following *FederationFollowing) LoadRemoteActor() (err error) {
+	if following.RemoteActor == nil {
+		following.RemoteActor, err = GetRemoteActorByID(following.RemoteActorID)
+	}
+	return err
+}
+
+// CreateFederationActivity records a published or received activity, an activity received twice is only recorded once
+func CreateFederationActivity(activity *FederationActivity) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		has, err := e.Exist(&FederationActivity{OwnerType: activity.OwnerType, OwnerID: activity.OwnerID, URI: activity.URI})
+		if err != nil || has {
+			return err
+		}
+		_, err = e.Insert(activity)
+		return err
+	})
+}
+
+// GetPublishedFederationActivity returns an activity published by a local actor
+func GetPublishedFederationActivity(uri string) (*FederationActivity, error) {
+	activity := new(FederationActivity)
+	has, err := db.DefaultContext().Engine().Where("uri = ? AND remote_actor_id = 0", uri).Get(activity)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrFederationActivityNotExist{URI: uri}
+	}
+	return activity, nil
+}
+
+// FindFederationActivitiesOptions represents the options to find the activities of a local actor
+type FindFederationActivitiesOptions struct {
+	ListOptions
+	OwnerType FederationOwnerType
+	OwnerID   int64
+	// Received lists the activities received from remote actors instead of the published ones
+	Received bool
+}
+
+// FindFederationActivities returns the activities of a local actor, newest first
+func FindFederationActivities(opts *FindFederationActivitiesOptions) ([]*FederationActivity, int64, error) {
+	sess := db.DefaultContext().Engine().
+		Where("owner_type = ? AND owner_id = ?", opts.OwnerType, opts.OwnerID)
+	if opts.Received {
+		sess = sess.And("remote_actor_id > 0")
+	} else {
+		sess = sess.And("remote_actor_id = 0")
+	}
+	sess = sess.Desc("id")
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, &opts.ListOptions)
+	}
+
+	activities := make([]*FederationActivity, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&activities)
+	return activities, count, err
+}
+
+// LoadRemoteActor loads the actor a received activity is from
+func (activity *FederationActivity) LoadRemoteActor() (err error) {
+	if activity.RemoteActor == nil && activity.RemoteActorID > 0 {
+		activity.RemoteActor, err = GetRemoteActorByID(activity.RemoteActorID)
+	}
+	return err
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveRemoteActor(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	actor := &RemoteActor{
+		URI:               "https://remote.example/users/alice",
+		PreferredUsername: "alice",
+		Inbox:             "https://remote.example/users/alice/inbox",
+		PublicKeyID:       "https://remote.example/users/alice#main-key",
+	}
+	assert.NoError(t, SaveRemoteActor(actor))
+	assert.EqualValues(t, "remote.example", actor.Host)
+	assert.EqualValues(t, "alice@remote.example", actor.Handle())
+
+	// saving the same URI updates the actor
+	updated := &RemoteActor{
+		URI:               actor.URI,
+		PreferredUsername: "alice",
+		Inbox:             "https://remote.example/inbox",
+		PublicKeyID:       actor.PublicKeyID,
+	}
+	assert.NoError(t, SaveRemoteActor(updated))
+	assert.EqualValues(t, actor.ID, updated.ID)
+
+	loaded, err := GetRemoteActorByKeyID(actor.PublicKeyID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "https://remote.example/inbox", loaded.Inbox)
+
+	_, err = GetRemoteActorByURI("https://remote.example/users/bob")
+	assert.True(t, IsErrRemoteActorNotExist(err))
+}
+
+func TestFederationFollowers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	actor := &RemoteActor{URI: "https://remote.example/users/alice", Inbox: "https://remote.example/inbox"}
+	assert.NoError(t, SaveRemoteActor(actor))
+
+	assert.NoError(t, AddFederationFollower(FederationOwnerRepo, 1, actor.ID))
+	// following twice is a no-op
+	assert.NoError(t, AddFederationFollower(FederationOwnerRepo, 1, actor.ID))
+	count, err := CountFederationFollowers(FederationOwnerRepo, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	count, err = CountFederationFollowers(FederationOwnerUser, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	followers, err := GetFederationFollowers(FederationOwnerRepo, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, followers, 1) {
+		assert.EqualValues(t, actor.URI, followers[0].URI)
+	}
+
+	assert.NoError(t, RemoveFederationFollower(FederationOwnerRepo, 1, actor.ID))
+	count, err = CountFederationFollowers(FederationOwnerRepo, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
+func TestFederationFollowing(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	actor := &RemoteActor{URI: "https://remote.example/users/alice", Inbox: "https://remote.example/inbox"}
+	assert.NoError(t, SaveRemoteActor(actor))
+
+	following := &FederationFollowing{UserID: 2, RemoteActorID: actor.ID, ActivityURI: "https://gitea.example/activity/1"}
+	assert.NoError(t, CreateFederationFollowing(following))
+
+	isFollowing, err := IsFollowingRemoteActor(2, actor.ID)
+	assert.NoError(t, err)
+	assert.False(t, isFollowing)
+
+	loaded, err := GetFederationFollowingByActivityURI(following.ActivityURI)
+	assert.NoError(t, err)
+	assert.NoError(t, AcceptFederationFollowing(loaded))
+	isFollowing, err = IsFollowingRemoteActor(2, actor.ID)
+	assert.NoError(t, err)
+	assert.True(t, isFollowing)
+
+	// following again replaces the previous follow, which has to be accepted again
+	again := &FederationFollowing{UserID: 2, RemoteActorID: actor.ID, ActivityURI: "https://gitea.example/activity/2"}
+	assert.NoError(t, CreateFederationFollowing(again))
+	followings, count, err := GetFederationFollowings(2, ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, followings, 1) {
+		assert.False(t, followings[0].IsAccepted)
+		assert.NoError(t, followings[0].LoadRemoteActor())
+		assert.EqualValues(t, actor.URI, followings[0].RemoteActor.URI)
+	}
+
+	_, err = GetFederationFollowingByID(3, again.ID)
+	assert.True(t, IsErrFederationFollowingNotExist(err))
+	assert.NoError(t, DeleteFederationFollowing(again))
+	_, err = GetFederationFollowingByID(2, again.ID)
+	assert.True(t, IsErrFederationFollowingNotExist(err))
+}
+
+func TestFederationActivities(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	published := &FederationActivity{OwnerType: FederationOwnerUser, OwnerID: 2, URI: "https://gitea.example/activity/1", Type: "Create", Content: "{}"}
+	assert.NoError(t, CreateFederationActivity(published))
+	received := &FederationActivity{OwnerType: FederationOwnerUser, OwnerID: 2, RemoteActorID: 1, URI: "https://remote.example/activity/1", Type: "Create", Content: "{}"}
+	assert.NoError(t, CreateFederationActivity(received))
+	// a delivery received twice is only recorded once
+	assert.NoError(t, CreateFederationActivity(&FederationActivity{OwnerType: FederationOwnerUser, OwnerID: 2, RemoteActorID: 1, URI: received.URI, Type: "Create", Content: "{}"}))
+
+	activities, count, err := FindFederationActivities(&FindFederationActivitiesOptions{OwnerType: FederationOwnerUser, OwnerID: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, activities, 1) {
+		assert.EqualValues(t, published.URI, activities[0].URI)
+	}
+	activities, count, err = FindFederationActivities(&FindFederationActivitiesOptions{OwnerType: FederationOwnerUser, OwnerID: 2, Received: true})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, activities, 1) {
+		assert.EqualValues(t, received.URI, activities[0].URI)
+	}
+
+	activity, err := GetPublishedFederationActivity(published.URI)
+	assert.NoError(t, err)
+	assert.EqualValues(t, published.ID, activity.ID)
+	_, err = GetPublishedFederationActivity(received.URI)
+	assert.True(t, IsErrFederationActivityNotExist(err))
+}
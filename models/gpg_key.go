@@ -61,6 +61,19 @@ func (key *GPGKey) AfterLoad(session *xorm.Session) {
 	}
 }
 
+// Fingerprint returns the fingerprint of the key, or an empty string if the key has no content
+func (key *GPGKey) Fingerprint() string {
+	if key.Content == "" {
+		return ""
+	}
+	pkey, err := base64DecPubKey(key.Content)
+	if err != nil {
+		log.Error("Unable to decode GPG key %s: %v", key.KeyID, err)
+		return ""
+	}
+	return fmt.Sprintf("%X", pkey.Fingerprint)
+}
+
 // ListGPGKeys returns a list of public keys belongs to given user.
 func ListGPGKeys(uid int64, listOptions ListOptions) ([]*GPGKey, error) {
 	return listGPGKeys(db.DefaultContext().Engine(), uid, listOptions)
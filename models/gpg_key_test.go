@@ -49,6 +49,13 @@ MkM/fdpyc2hY7Dl/+qFmN5MG5yGmMpQcX+RNNR222ibNC1D3wg==
 	key, err := checkArmoredGPGKeyString(testGPGArmor)
 	assert.NoError(t, err, "Could not parse a valid GPG public armored rsa key", key)
 	// TODO verify value of key
+
+	content, err := base64EncPubKey(key[0].PrimaryKey)
+	assert.NoError(t, err)
+	gpgKey := &GPGKey{KeyID: key[0].PrimaryKey.KeyIdString(), Content: content}
+	assert.Equal(t, "AE8255B8A0D828E4", gpgKey.KeyID)
+	assert.Equal(t, "1083B0260FF5BE91758AD278AE8255B8A0D828E4", gpgKey.Fingerprint())
+	assert.Empty(t, (&GPGKey{KeyID: gpgKey.KeyID}).Fingerprint())
 }
 
 func TestCheckArmoredbrainpoolP256r1GPGKeyString(t *testing.T) {
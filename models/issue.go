@@ -43,15 +43,22 @@ type Issue struct {
 	MilestoneID      int64      `xorm:"INDEX"`
 	Milestone        *Milestone `xorm:"-"`
 	Project          *Project   `xorm:"-"`
-	Priority         int
-	AssigneeID       int64        `xorm:"-"`
-	Assignee         *User        `xorm:"-"`
-	IsClosed         bool         `xorm:"INDEX"`
-	IsRead           bool         `xorm:"-"`
-	IsPull           bool         `xorm:"INDEX"` // Indicates whether is a pull request or not.
-	PullRequest      *PullRequest `xorm:"-"`
-	NumComments      int
-	Ref              string
+	// WorkflowStateID is the workflow state of the repository the issue is in, 0 if none
+	WorkflowStateID int64               `xorm:"INDEX NOT NULL DEFAULT 0"`
+	WorkflowState   *IssueWorkflowState `xorm:"-"`
+	// Priority is the level of the priority of the repository the issue has, 0 if none
+	Priority      int
+	PriorityLevel *IssuePriority `xorm:"-"`
+	IterationID   int64          `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Iteration     *Iteration     `xorm:"-"`
+	AssigneeID    int64          `xorm:"-"`
+	Assignee      *User          `xorm:"-"`
+	IsClosed      bool           `xorm:"INDEX"`
+	IsRead        bool           `xorm:"-"`
+	IsPull        bool           `xorm:"INDEX"` // Indicates whether is a pull request or not.
+	PullRequest   *PullRequest   `xorm:"-"`
+	NumComments   int
+	Ref           string
 
 	DeadlineUnix timeutil.TimeStamp `xorm:"INDEX"`
 
@@ -68,6 +75,10 @@ type Issue struct {
 	// IsLocked limits commenting abilities to users on an issue
 	// with write access
 	IsLocked bool `xorm:"NOT NULL DEFAULT false"`
+	// LockReason is the reason the issue was locked for
+	LockReason IssueLockReason `xorm:"NOT NULL DEFAULT 0"`
+	// LockedUntilUnix is the time the issue is unlocked automatically, 0 if it stays locked
+	LockedUntilUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
 
 	// For view issue page.
 	ShowTag CommentTag `xorm:"-"`
@@ -264,9 +275,12 @@ func (issue *Issue) loadReactions(e db.Engine) (err error) {
 
 func (issue *Issue) loadMilestone(e db.Engine) (err error) {
 	if (issue.Milestone == nil || issue.Milestone.ID != issue.MilestoneID) && issue.MilestoneID > 0 {
-		issue.Milestone, err = getMilestoneByRepoID(e, issue.RepoID, issue.MilestoneID)
+		if err = issue.loadRepo(e); err != nil {
+			return err
+		}
+		issue.Milestone, err = getMilestoneForRepo(e, issue.Repo, issue.MilestoneID)
 		if err != nil && !IsErrMilestoneNotExist(err) {
-			return fmt.Errorf("getMilestoneByRepoID [repo_id: %d, milestone_id: %d]: %v", issue.RepoID, issue.MilestoneID, err)
+			return fmt.Errorf("getMilestoneForRepo [repo_id: %d, milestone_id: %d]: %v", issue.RepoID, issue.MilestoneID, err)
 		}
 	}
 	return nil
@@ -293,6 +307,14 @@ func (issue *Issue) loadAttributes(e db.Engine) (err error) {
 		return
 	}
 
+	if err = issue.loadWorkflowState(e); err != nil {
+		return
+	}
+
+	if err = issue.loadPriorityLevel(e); err != nil {
+		return
+	}
+
 	if err = issue.loadAssignees(e); err != nil {
 		return
 	}
@@ -426,6 +448,12 @@ func (issue *Issue) ReplyReference() string {
 	return fmt.Sprintf("%s/%s/%d@%s", issue.Repo.FullName(), path, issue.Index, setting.Domain)
 }
 
+// CodeThreadReplyReference returns a stable reference of the code comment thread started by the root comment,
+// made of the pull request, the review of the root comment, the file path and the line
+func (issue *Issue) CodeThreadReplyReference(root *Comment) string {
+	return fmt.Sprintf("%s/pulls/%d/reviews/%d/%s/%d@%s", issue.Repo.FullName(), issue.Index, root.ReviewID, base.EncodeSha1(root.TreePath), root.Line, setting.Domain)
+}
+
 func (issue *Issue) addLabel(e db.Engine, label *Label, doer *User) error {
 	return newIssueLabel(e, issue, label, doer)
 }
@@ -586,6 +614,10 @@ func (issue *Issue) ReadBy(userID int64) error {
 		return err
 	}
 
+	if err := MarkIssueRead(userID, issue); err != nil {
+		return err
+	}
+
 	return setIssueNotificationStatusReadIfUnread(db.DefaultContext().Engine(), userID, issue.ID)
 }
 
@@ -889,7 +921,7 @@ func newIssue(e db.Engine, doer *User, opts NewIssueOptions) (err error) {
 	opts.Issue.Title = strings.TrimSpace(opts.Issue.Title)
 
 	if opts.Issue.MilestoneID > 0 {
-		milestone, err := getMilestoneByRepoID(e, opts.Issue.RepoID, opts.Issue.MilestoneID)
+		milestone, err := getMilestoneForRepo(e, opts.Repo, opts.Issue.MilestoneID)
 		if err != nil && !IsErrMilestoneNotExist(err) {
 			return fmt.Errorf("getMilestoneByID: %v", err)
 		}
@@ -1039,6 +1071,10 @@ func NewIssue(repo *Repository, issue *Issue, labelIDs []int64, uuids []string)
 		return fmt.Errorf("newIssue: %v", err)
 	}
 
+	if err = addOutboxEvent(ctx.Engine(), OutboxEventIssueCreated, issue.ID, repo.ID, issue.PosterID); err != nil {
+		return fmt.Errorf("addOutboxEvent: %v", err)
+	}
+
 	if err = committer.Commit(); err != nil {
 		return fmt.Errorf("Commit: %v", err)
 	}
@@ -1127,6 +1163,7 @@ type IssuesOptions struct {
 	PosterID           int64
 	MentionedID        int64
 	ReviewRequestedID  int64
+	ParticipantID      int64 // opened or commented on by the user
 	MilestoneIDs       []int64
 	ProjectID          int64
 	ProjectBoardID     int64
@@ -1143,6 +1180,47 @@ type IssuesOptions struct {
 	// prioritize issues from this repo
 	PriorityRepoID int64
 	IsArchived     util.OptionalBool
+	// WorkflowStateID filters by workflow state, -1 selects issues in no workflow state
+	WorkflowStateID int64
+	// CustomFields selects the issues having these values of the custom fields, by field name
+	CustomFields map[string]string
+	// After selects the issues after the cursor instead of the page, see SupportsCursor
+	After *Cursor
+}
+
+// issueCursorColumn returns the column the issues are sorted by and whether they are sorted in descending order,
+// ok is false if the sort order can not be paginated by a cursor
+func issueCursorColumn(sortType string, priorityRepoID int64) (column string, desc, ok bool) {
+	switch sortType {
+	case "oldest":
+		return "issue.created_unix", false, true
+	case "recentupdate":
+		return "issue.updated_unix", true, true
+	case "leastupdate":
+		return "issue.updated_unix", false, true
+	case "priorityrepo":
+		// without a repository to prioritize, the issues are sorted by creation time
+		return "issue.created_unix", true, priorityRepoID == 0
+	case "mostcomment", "leastcomment", "priority", "nearduedate", "farduedate":
+		return "", false, false
+	default:
+		return "issue.created_unix", true, true
+	}
+}
+
+// SupportsCursor returns true if the issues can be paginated by a cursor with the sort order of the options
+func (opts *IssuesOptions) SupportsCursor() bool {
+	_, _, ok := issueCursorColumn(opts.SortType, opts.PriorityRepoID)
+	return ok
+}
+
+// CursorOf returns the cursor of an issue of the list, the next page starts after it
+func (opts *IssuesOptions) CursorOf(issue *Issue) *Cursor {
+	column, _, _ := issueCursorColumn(opts.SortType, opts.PriorityRepoID)
+	if column == "issue.updated_unix" {
+		return &Cursor{Value: int64(issue.UpdatedUnix), ID: issue.ID}
+	}
+	return &Cursor{Value: int64(issue.CreatedUnix), ID: issue.ID}
 }
 
 // sortIssuesSession sort an issues-related session based on the provided
@@ -1183,7 +1261,14 @@ func sortIssuesSession(sess *xorm.Session, sortType string, priorityRepoID int64
 }
 
 func (opts *IssuesOptions) setupSession(sess *xorm.Session) {
-	if opts.Page >= 0 && opts.PageSize > 0 {
+	if opts.After != nil {
+		if column, desc, ok := issueCursorColumn(opts.SortType, opts.PriorityRepoID); ok {
+			sess.And(opts.After.afterCond(column, "issue.id", desc))
+		}
+		if opts.PageSize > 0 {
+			sess.Limit(opts.PageSize)
+		}
+	} else if opts.Page >= 0 && opts.PageSize > 0 {
 		var start int
 		if opts.Page == 0 {
 			start = 0
@@ -1224,6 +1309,10 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) {
 		applyReviewRequestedCondition(sess, opts.ReviewRequestedID)
 	}
 
+	if opts.ParticipantID > 0 {
+		applyParticipantCondition(sess, opts.ParticipantID)
+	}
+
 	if len(opts.MilestoneIDs) > 0 {
 		sess.In("issue.milestone_id", opts.MilestoneIDs)
 	}
@@ -1248,6 +1337,16 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) {
 		}
 	}
 
+	if opts.WorkflowStateID > 0 {
+		sess.And("issue.workflow_state_id=?", opts.WorkflowStateID)
+	} else if opts.WorkflowStateID < 0 {
+		sess.And("issue.workflow_state_id=?", 0)
+	}
+
+	if len(opts.CustomFields) > 0 {
+		sess.And(issueCustomFieldsCond(opts.CustomFields))
+	}
+
 	switch opts.IsPull {
 	case util.OptionalBoolTrue:
 		sess.And("issue.is_pull=?", true)
@@ -1314,6 +1413,14 @@ func applyReviewRequestedCondition(sess *xorm.Session, reviewRequestedID int64)
 			reviewRequestedID, ReviewTypeApprove, ReviewTypeReject, ReviewTypeRequest, reviewRequestedID)
 }
 
+func applyParticipantCondition(sess *xorm.Session, participantID int64) *xorm.Session {
+	return sess.And(builder.Or(
+		builder.Eq{"issue.poster_id": participantID},
+		builder.In("issue.id", builder.Select("issue_id").From("comment").Where(builder.Eq{"poster_id": participantID}.
+			And(builder.In("type", CommentTypeComment, CommentTypeCode, CommentTypeReview)))),
+	))
+}
+
 // CountIssuesByRepo map from repoID to number of issues matching the options
 func CountIssuesByRepo(opts *IssuesOptions) (map[int64]int64, error) {
 	sess := db.DefaultContext().NewSession()
@@ -1370,6 +1477,14 @@ func Issues(opts *IssuesOptions) ([]*Issue, error) {
 	sess.Join("INNER", "repository", "`issue`.repo_id = `repository`.id")
 	opts.setupSession(sess)
 	sortIssuesSession(sess, opts.SortType, opts.PriorityRepoID)
+	// break the ties of the sort key, so that the pages do not overlap
+	if _, desc, ok := issueCursorColumn(opts.SortType, opts.PriorityRepoID); ok {
+		if desc {
+			sess.Desc("issue.id")
+		} else {
+			sess.Asc("issue.id")
+		}
+	}
 
 	issues := make([]*Issue, 0, opts.ListOptions.PageSize)
 	if err := sess.Find(&issues); err != nil {
@@ -1485,6 +1600,8 @@ type IssueStatsOptions struct {
 	ReviewRequestedID int64
 	IsPull            util.OptionalBool
 	IssueIDs          []int64
+	// WorkflowStateID filters by workflow state, -1 selects issues in no workflow state
+	WorkflowStateID int64
 }
 
 const (
@@ -1556,6 +1673,12 @@ func getIssueStatsChunk(opts *IssueStatsOptions, issueIDs []int64) (*IssueStats,
 			sess.And("issue.milestone_id = ?", opts.MilestoneID)
 		}
 
+		if opts.WorkflowStateID > 0 {
+			sess.And("issue.workflow_state_id = ?", opts.WorkflowStateID)
+		} else if opts.WorkflowStateID < 0 {
+			sess.And("issue.workflow_state_id = ?", 0)
+		}
+
 		if opts.AssigneeID > 0 {
 			applyAssigneeCondition(sess, opts.AssigneeID)
 		}
@@ -1978,6 +2101,9 @@ func (issue *Issue) FindAndUpdateIssueMentions(ctx *db.Context, doer *User, cont
 	if err = UpdateIssueMentions(ctx, issue.ID, mentions); err != nil {
 		return nil, fmt.Errorf("UpdateIssueMentions [%d]: %v", issue.ID, err)
 	}
+	if err = watchIssueOnMentions(ctx.Engine(), issue.ID, mentions); err != nil {
+		return nil, fmt.Errorf("watchIssueOnMentions [%d]: %v", issue.ID, err)
+	}
 	return
 }
 
@@ -2162,6 +2288,32 @@ func deleteIssuesByRepoID(sess db.Engine, repoID int64) (attachmentPaths []strin
 		return
 	}
 
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&IssueCustomFieldValue{}); err != nil {
+		return
+	}
+
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&IssueSLABreach{}); err != nil {
+		return
+	}
+
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&IssueReadMarker{}); err != nil {
+		return
+	}
+
+	// Delete relations of the issues, including those with issues in other repositories
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&IssueRelation{}); err != nil {
+		return
+	}
+
+	if _, err = sess.In("related_issue_id", deleteCond).
+		Delete(&IssueRelation{}); err != nil {
+		return
+	}
+
 	if _, err = sess.In("issue_id", deleteCond).
 		Delete(&IssueUser{}); err != nil {
 		return
@@ -2192,6 +2344,11 @@ func deleteIssuesByRepoID(sess db.Engine, repoID int64) (attachmentPaths []strin
 		return
 	}
 
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&IssueForm{}); err != nil {
+		return
+	}
+
 	if _, err = sess.In("dependent_issue_id", deleteCond).
 		Delete(&Comment{}); err != nil {
 		return
@@ -2204,7 +2361,9 @@ func deleteIssuesByRepoID(sess db.Engine, repoID int64) (attachmentPaths []strin
 	}
 
 	for j := range attachments {
-		attachmentPaths = append(attachmentPaths, attachments[j].RelativePath())
+		if !attachments[j].IsDeduplicated() {
+			attachmentPaths = append(attachmentPaths, attachments[j].RelativePath())
+		}
 	}
 
 	if _, err = sess.In("issue_id", deleteCond).
@@ -0,0 +1,100 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// DefaultIssueBranchNameTemplate is the name template of the branches created for issues of a repository which does not set one
+const DefaultIssueBranchNameTemplate = "issue-${Index}-${Slug}"
+
+// IssueBranch is a branch created to work on an issue, the pull request opened from it is linked to the issue
+type IssueBranch struct {
+	ID         int64  `xorm:"pk autoincr"`
+	RepoID     int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	BranchName string `xorm:"UNIQUE(s) NOT NULL"`
+	IssueID    int64  `xorm:"INDEX NOT NULL"`
+	CreatorID  int64  `xorm:"NOT NULL"`
+	// PullID is the ID of the pull request opened from the branch, 0 until one is opened
+	PullID      int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueBranch))
+}
+
+// IssueBranchNameTemplate returns the name template of the branches created for the issues of the repository
+func (repo *Repository) IssueBranchNameTemplate() string {
+	u, err := repo.GetUnit(UnitTypeIssues)
+	if err != nil || u.IssuesConfig().BranchNameTemplate == "" {
+		return DefaultIssueBranchNameTemplate
+	}
+	return u.IssuesConfig().BranchNameTemplate
+}
+
+// CreateIssueBranch records that a branch was created to work on an issue
+func CreateIssueBranch(branch *IssueBranch) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		// a branch with the same name may be left over from a deleted branch
+		if _, err := e.Delete(&IssueBranch{RepoID: branch.RepoID, BranchName: branch.BranchName}); err != nil {
+			return err
+		}
+		_, err := e.Insert(branch)
+		return err
+	})
+}
+
+// GetIssueBranches returns the branches created to work on an issue
+func GetIssueBranches(issueID int64) ([]*IssueBranch, error) {
+	branches := make([]*IssueBranch, 0, 2)
+	return branches, db.DefaultContext().Engine().Where("issue_id = ?", issueID).Asc("id").Find(&branches)
+}
+
+// GetIssueBranch returns the issue branch of a repository by its name, nil if the branch was not created for an issue
+func GetIssueBranch(repoID int64, branchName string) (*IssueBranch, error) {
+	branch := &IssueBranch{RepoID: repoID, BranchName: branchName}
+	has, err := db.DefaultContext().Engine().Get(branch)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return branch, nil
+}
+
+// LinkIssueBranchPull links the pull request opened from an issue branch to its issue with a reference comment
+func LinkIssueBranchPull(branch *IssueBranch, pr *PullRequest, doer *User) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		branch.PullID = pr.ID
+		if _, err := e.ID(branch.ID).Cols("pull_id").Update(branch); err != nil {
+			return err
+		}
+
+		issue, err := getIssueByID(e, branch.IssueID)
+		if err != nil {
+			return err
+		}
+		if err = issue.loadRepo(e); err != nil {
+			return err
+		}
+		_, err = createComment(e, &CreateCommentOptions{
+			Type:       CommentTypePullRef,
+			Doer:       doer,
+			Repo:       issue.Repo,
+			Issue:      issue,
+			RefRepoID:  pr.BaseRepoID,
+			RefIssueID: pr.IssueID,
+			RefAction:  references.XRefActionNone,
+			RefIsPull:  true,
+		})
+		return err
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"unicode/utf8"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
@@ -102,6 +103,10 @@ const (
 	CommentTypeProjectBoard
 	// Dismiss Review
 	CommentTypeDismissReview
+	// 33 Workflow state changed
+	CommentTypeWorkflowState
+	// 34 First response SLA breached
+	CommentTypeSLABreach
 )
 
 // CommentTag defines comment tag type
@@ -117,41 +122,45 @@ const (
 
 // Comment represents a comment in commit and issue page.
 type Comment struct {
-	ID               int64       `xorm:"pk autoincr"`
-	Type             CommentType `xorm:"INDEX"`
-	PosterID         int64       `xorm:"INDEX"`
-	Poster           *User       `xorm:"-"`
-	OriginalAuthor   string
-	OriginalAuthorID int64
-	IssueID          int64  `xorm:"INDEX"`
-	Issue            *Issue `xorm:"-"`
-	LabelID          int64
-	Label            *Label   `xorm:"-"`
-	AddedLabels      []*Label `xorm:"-"`
-	RemovedLabels    []*Label `xorm:"-"`
-	OldProjectID     int64
-	ProjectID        int64
-	OldProject       *Project `xorm:"-"`
-	Project          *Project `xorm:"-"`
-	OldMilestoneID   int64
-	MilestoneID      int64
-	OldMilestone     *Milestone `xorm:"-"`
-	Milestone        *Milestone `xorm:"-"`
-	TimeID           int64
-	Time             *TrackedTime `xorm:"-"`
-	AssigneeID       int64
-	RemovedAssignee  bool
-	Assignee         *User `xorm:"-"`
-	AssigneeTeamID   int64 `xorm:"NOT NULL DEFAULT 0"`
-	AssigneeTeam     *Team `xorm:"-"`
-	ResolveDoerID    int64
-	ResolveDoer      *User `xorm:"-"`
-	OldTitle         string
-	NewTitle         string
-	OldRef           string
-	NewRef           string
-	DependentIssueID int64
-	DependentIssue   *Issue `xorm:"-"`
+	ID                 int64       `xorm:"pk autoincr"`
+	Type               CommentType `xorm:"INDEX"`
+	PosterID           int64       `xorm:"INDEX"`
+	Poster             *User       `xorm:"-"`
+	OriginalAuthor     string
+	OriginalAuthorID   int64
+	IssueID            int64  `xorm:"INDEX"`
+	Issue              *Issue `xorm:"-"`
+	LabelID            int64
+	Label              *Label   `xorm:"-"`
+	AddedLabels        []*Label `xorm:"-"`
+	RemovedLabels      []*Label `xorm:"-"`
+	OldProjectID       int64
+	ProjectID          int64
+	OldProject         *Project `xorm:"-"`
+	Project            *Project `xorm:"-"`
+	OldMilestoneID     int64
+	MilestoneID        int64
+	OldMilestone       *Milestone          `xorm:"-"`
+	Milestone          *Milestone          `xorm:"-"`
+	OldWorkflowStateID int64               `xorm:"NOT NULL DEFAULT 0"`
+	WorkflowStateID    int64               `xorm:"NOT NULL DEFAULT 0"`
+	OldWorkflowState   *IssueWorkflowState `xorm:"-"`
+	WorkflowState      *IssueWorkflowState `xorm:"-"`
+	TimeID             int64
+	Time               *TrackedTime `xorm:"-"`
+	AssigneeID         int64
+	RemovedAssignee    bool
+	Assignee           *User `xorm:"-"`
+	AssigneeTeamID     int64 `xorm:"NOT NULL DEFAULT 0"`
+	AssigneeTeam       *Team `xorm:"-"`
+	ResolveDoerID      int64
+	ResolveDoer        *User `xorm:"-"`
+	OldTitle           string
+	NewTitle           string
+	OldRef             string
+	NewRef             string
+	DependentIssueID   int64
+	DependentIssue     *Issue `xorm:"-"`
 
 	CommitID        int64
 	Line            int64 // - previous line / + proposed line
@@ -693,38 +702,41 @@ func createComment(e db.Engine, opts *CreateCommentOptions) (_ *Comment, err err
 	}
 
 	comment := &Comment{
-		Type:             opts.Type,
-		PosterID:         opts.Doer.ID,
-		Poster:           opts.Doer,
-		IssueID:          opts.Issue.ID,
-		LabelID:          LabelID,
-		OldMilestoneID:   opts.OldMilestoneID,
-		MilestoneID:      opts.MilestoneID,
-		OldProjectID:     opts.OldProjectID,
-		ProjectID:        opts.ProjectID,
-		TimeID:           opts.TimeID,
-		RemovedAssignee:  opts.RemovedAssignee,
-		AssigneeID:       opts.AssigneeID,
-		AssigneeTeamID:   opts.AssigneeTeamID,
-		CommitID:         opts.CommitID,
-		CommitSHA:        opts.CommitSHA,
-		Line:             opts.LineNum,
-		Content:          opts.Content,
-		OldTitle:         opts.OldTitle,
-		NewTitle:         opts.NewTitle,
-		OldRef:           opts.OldRef,
-		NewRef:           opts.NewRef,
-		DependentIssueID: opts.DependentIssueID,
-		TreePath:         opts.TreePath,
-		ReviewID:         opts.ReviewID,
-		Patch:            opts.Patch,
-		RefRepoID:        opts.RefRepoID,
-		RefIssueID:       opts.RefIssueID,
-		RefCommentID:     opts.RefCommentID,
-		RefAction:        opts.RefAction,
-		RefIsPull:        opts.RefIsPull,
-		IsForcePush:      opts.IsForcePush,
-		Invalidated:      opts.Invalidated,
+		Type:               opts.Type,
+		PosterID:           opts.Doer.ID,
+		Poster:             opts.Doer,
+		IssueID:            opts.Issue.ID,
+		LabelID:            LabelID,
+		OldMilestoneID:     opts.OldMilestoneID,
+		MilestoneID:        opts.MilestoneID,
+		OldProjectID:       opts.OldProjectID,
+		ProjectID:          opts.ProjectID,
+		OldWorkflowStateID: opts.OldWorkflowStateID,
+		WorkflowStateID:    opts.WorkflowStateID,
+		TimeID:             opts.TimeID,
+		RemovedAssignee:    opts.RemovedAssignee,
+		AssigneeID:         opts.AssigneeID,
+		AssigneeTeamID:     opts.AssigneeTeamID,
+		CommitID:           opts.CommitID,
+		CommitSHA:          opts.CommitSHA,
+		Line:               opts.LineNum,
+		Content:            opts.Content,
+		OldTitle:           opts.OldTitle,
+		NewTitle:           opts.NewTitle,
+		OldRef:             opts.OldRef,
+		NewRef:             opts.NewRef,
+		DependentIssueID:   opts.DependentIssueID,
+		TreePath:           opts.TreePath,
+		ReviewID:           opts.ReviewID,
+		Patch:              opts.Patch,
+		RefRepoID:          opts.RefRepoID,
+		RefIssueID:         opts.RefIssueID,
+		RefCommentID:       opts.RefCommentID,
+		RefAction:          opts.RefAction,
+		RefIsPull:          opts.RefIsPull,
+		IsForcePush:        opts.IsForcePush,
+		Invalidated:        opts.Invalidated,
+		OriginalAuthor:     opts.OriginalAuthor,
 	}
 	if _, err = e.Insert(comment); err != nil {
 		return nil, err
@@ -867,34 +879,40 @@ type CreateCommentOptions struct {
 	Issue *Issue
 	Label *Label
 
-	DependentIssueID int64
-	OldMilestoneID   int64
-	MilestoneID      int64
-	OldProjectID     int64
-	ProjectID        int64
-	TimeID           int64
-	AssigneeID       int64
-	AssigneeTeamID   int64
-	RemovedAssignee  bool
-	OldTitle         string
-	NewTitle         string
-	OldRef           string
-	NewRef           string
-	CommitID         int64
-	CommitSHA        string
-	Patch            string
-	LineNum          int64
-	TreePath         string
-	ReviewID         int64
-	Content          string
-	Attachments      []string // UUIDs of attachments
-	RefRepoID        int64
-	RefIssueID       int64
-	RefCommentID     int64
-	RefAction        references.XRefAction
-	RefIsPull        bool
-	IsForcePush      bool
-	Invalidated      bool
+	DependentIssueID   int64
+	OldMilestoneID     int64
+	MilestoneID        int64
+	OldProjectID       int64
+	ProjectID          int64
+	OldWorkflowStateID int64
+	WorkflowStateID    int64
+	TimeID             int64
+	AssigneeID         int64
+	AssigneeTeamID     int64
+	RemovedAssignee    bool
+	OldTitle           string
+	NewTitle           string
+	OldRef             string
+	NewRef             string
+	CommitID           int64
+	CommitSHA          string
+	Patch              string
+	LineNum            int64
+	TreePath           string
+	ReviewID           int64
+	Content            string
+	Attachments        []string // UUIDs of attachments
+	RefRepoID          int64
+	RefIssueID         int64
+	RefCommentID       int64
+	RefAction          references.XRefAction
+	RefIsPull          bool
+	IsForcePush        bool
+	Invalidated        bool
+	// OutboxEvent records an outbox event of this type for the comment in the same transaction
+	OutboxEvent OutboxEventType
+	// OriginalAuthor is the name of the author of a comment which was not written by a local user
+	OriginalAuthor string
 }
 
 // CreateComment creates comment of issue or commit.
@@ -910,6 +928,12 @@ func CreateComment(opts *CreateCommentOptions) (comment *Comment, err error) {
 		return nil, err
 	}
 
+	if opts.OutboxEvent != "" {
+		if err = addOutboxEvent(sess, opts.OutboxEvent, comment.ID, opts.Repo.ID, opts.Doer.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	if err = sess.Commit(); err != nil {
 		return nil, err
 	}
@@ -962,6 +986,22 @@ func getCommentByID(e db.Engine, id int64) (*Comment, error) {
 	return c, nil
 }
 
+// GetCodeCommentThreadRoot returns the first comment of the thread a code comment belongs to.
+// A thread is made of the code comments of a pull request on the same line of a file.
+func GetCodeCommentThreadRoot(c *Comment) (*Comment, error) {
+	root := new(Comment)
+	has, err := db.DefaultContext().Engine().
+		Where("issue_id = ? AND type = ? AND tree_path = ? AND line = ?", c.IssueID, CommentTypeCode, c.TreePath, c.Line).
+		Asc("id").
+		Get(root)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrCommentNotExist{c.ID, c.IssueID}
+	}
+	return root, nil
+}
+
 // FindCommentsOptions describes the conditions to Find comments
 type FindCommentsOptions struct {
 	ListOptions
@@ -1057,6 +1097,7 @@ func UpdateComment(c *Comment, doer *User) error {
 	if err := sess.Commit(); err != nil {
 		return fmt.Errorf("Commit: %v", err)
 	}
+	cache.Remove(c.RenderCacheKey())
 
 	return nil
 }
@@ -1073,7 +1114,16 @@ func DeleteComment(comment *Comment) error {
 		return err
 	}
 
-	return sess.Commit()
+	if err := sess.Commit(); err != nil {
+		return err
+	}
+	cache.Remove(comment.RenderCacheKey())
+	return nil
+}
+
+// RenderCacheKey returns the key the rendered content of the comment is cached under
+func (c *Comment) RenderCacheKey() string {
+	return fmt.Sprintf("comment_render_%d", c.ID)
 }
 
 func deleteComment(e db.Engine, comment *Comment) error {
@@ -1189,7 +1239,7 @@ func findCodeComments(e db.Engine, opts FindCommentsOptions, issue *Issue, curre
 		}
 
 		var err error
-		if comment.RenderedContent, err = markdown.RenderString(&markup.RenderContext{
+		if comment.RenderedContent, err = markdown.RenderStringCached(comment.RenderCacheKey(), &markup.RenderContext{
 			URLPrefix: issue.Repo.Link(),
 			Metas:     issue.Repo.ComposeMetas(),
 		}, comment.Content); err != nil {
@@ -0,0 +1,428 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// IssueCustomFieldType is the type of the values of a custom field
+type IssueCustomFieldType int
+
+// The types of custom fields
+const (
+	IssueCustomFieldTypeText IssueCustomFieldType = iota + 1
+	IssueCustomFieldTypeNumber
+	IssueCustomFieldTypeEnum
+	IssueCustomFieldTypeDate
+)
+
+var issueCustomFieldTypeNames = map[IssueCustomFieldType]string{
+	IssueCustomFieldTypeText:   "text",
+	IssueCustomFieldTypeNumber: "number",
+	IssueCustomFieldTypeEnum:   "enum",
+	IssueCustomFieldTypeDate:   "date",
+}
+
+// IssueCustomFieldDateFormat is the format of the values of date fields
+const IssueCustomFieldDateFormat = "2006-01-02"
+
+// MaxIssueCustomFieldValueLength is the maximum number of characters of a value of a custom field
+const MaxIssueCustomFieldValueLength = 255
+
+// String returns the name of the field type
+func (t IssueCustomFieldType) String() string {
+	return issueCustomFieldTypeNames[t]
+}
+
+// IssueCustomFieldTypeFromString returns the field type matching name
+func IssueCustomFieldTypeFromString(name string) (IssueCustomFieldType, bool) {
+	for t, n := range issueCustomFieldTypeNames {
+		if n == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// IssueCustomField represents a field defined by the administrators of an organization or a repository,
+// the issues of the repository, or of all the repositories of the organization, can be given a value for it
+type IssueCustomField struct {
+	ID          int64                `xorm:"pk autoincr"`
+	OrgID       int64                `xorm:"INDEX NOT NULL DEFAULT 0"`
+	RepoID      int64                `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Name        string               `xorm:"NOT NULL"`
+	Description string               `xorm:"TEXT"`
+	Type        IssueCustomFieldType `xorm:"NOT NULL"`
+	// Options are the allowed values of an enum field
+	Options []string `xorm:"JSON TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// IssueCustomFieldValue represents the value of a custom field for an issue
+type IssueCustomFieldValue struct {
+	ID      int64  `xorm:"pk autoincr"`
+	IssueID int64  `xorm:"UNIQUE(s) NOT NULL"`
+	FieldID int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Value   string `xorm:"VARCHAR(255) NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueCustomField))
+	db.RegisterModel(new(IssueCustomFieldValue))
+}
+
+// ErrIssueCustomFieldNotExist represents a "IssueCustomFieldNotExist" kind of error.
+type ErrIssueCustomFieldNotExist struct {
+	ID   int64
+	Name string
+}
+
+// IsErrIssueCustomFieldNotExist checks if an error is a ErrIssueCustomFieldNotExist.
+func IsErrIssueCustomFieldNotExist(err error) bool {
+	_, ok := err.(ErrIssueCustomFieldNotExist)
+	return ok
+}
+
+func (err ErrIssueCustomFieldNotExist) Error() string {
+	return fmt.Sprintf("issue custom field does not exist [id: %d, name: %s]", err.ID, err.Name)
+}
+
+// ErrIssueCustomFieldAlreadyExist represents a "IssueCustomFieldAlreadyExist" kind of error.
+type ErrIssueCustomFieldAlreadyExist struct {
+	Name string
+}
+
+// IsErrIssueCustomFieldAlreadyExist checks if an error is a ErrIssueCustomFieldAlreadyExist.
+func IsErrIssueCustomFieldAlreadyExist(err error) bool {
+	_, ok := err.(ErrIssueCustomFieldAlreadyExist)
+	return ok
+}
+
+func (err ErrIssueCustomFieldAlreadyExist) Error() string {
+	return fmt.Sprintf("issue custom field already exists [name: %s]", err.Name)
+}
+
+// ErrInvalidIssueCustomField represents a "InvalidIssueCustomField" kind of error.
+type ErrInvalidIssueCustomField struct {
+	Reason string
+}
+
+// IsErrInvalidIssueCustomField checks if an error is a ErrInvalidIssueCustomField.
+func IsErrInvalidIssueCustomField(err error) bool {
+	_, ok := err.(ErrInvalidIssueCustomField)
+	return ok
+}
+
+func (err ErrInvalidIssueCustomField) Error() string {
+	return fmt.Sprintf("invalid issue custom field: %s", err.Reason)
+}
+
+// ErrInvalidIssueCustomFieldValue represents a "InvalidIssueCustomFieldValue" kind of error.
+type ErrInvalidIssueCustomFieldValue struct {
+	Name   string
+	Value  string
+	Reason string
+}
+
+// IsErrInvalidIssueCustomFieldValue checks if an error is a ErrInvalidIssueCustomFieldValue.
+func IsErrInvalidIssueCustomFieldValue(err error) bool {
+	_, ok := err.(ErrInvalidIssueCustomFieldValue)
+	return ok
+}
+
+func (err ErrInvalidIssueCustomFieldValue) Error() string {
+	return fmt.Sprintf("invalid value of issue custom field %s: %s [value: %s]", err.Name, err.Reason, err.Value)
+}
+
+// NormalizeValue validates a value of the field and returns it in its stored form:
+// numbers are formatted without trailing zeros and dates as YYYY-MM-DD
+func (f *IssueCustomField) NormalizeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) > MaxIssueCustomFieldValueLength {
+		return "", ErrInvalidIssueCustomFieldValue{Name: f.Name, Value: value, Reason: fmt.Sprintf("longer than %d characters", MaxIssueCustomFieldValueLength)}
+	}
+
+	switch f.Type {
+	case IssueCustomFieldTypeNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", ErrInvalidIssueCustomFieldValue{Name: f.Name, Value: value, Reason: "not a number"}
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case IssueCustomFieldTypeEnum:
+		for _, option := range f.Options {
+			if option == value {
+				return value, nil
+			}
+		}
+		return "", ErrInvalidIssueCustomFieldValue{Name: f.Name, Value: value, Reason: "not one of the options"}
+	case IssueCustomFieldTypeDate:
+		d, err := time.Parse(IssueCustomFieldDateFormat, value)
+		if err != nil {
+			return "", ErrInvalidIssueCustomFieldValue{Name: f.Name, Value: value, Reason: "not a date of the form YYYY-MM-DD"}
+		}
+		return d.Format(IssueCustomFieldDateFormat), nil
+	}
+	return value, nil
+}
+
+// isIssueCustomFieldNameTaken checks the field names the issues of the scope of the field can have: the fields of
+// an organization share their names with the fields of its repositories
+func isIssueCustomFieldNameTaken(e db.Engine, f *IssueCustomField) (bool, error) {
+	var scope builder.Cond
+	if f.OrgID != 0 {
+		scope = builder.Or(
+			builder.Eq{"org_id": f.OrgID},
+			builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": f.OrgID})),
+		)
+	} else {
+		scope = builder.Or(
+			builder.Eq{"repo_id": f.RepoID},
+			builder.In("org_id", builder.Select("owner_id").From("repository").Where(builder.Eq{"id": f.RepoID})),
+		)
+	}
+	return e.Where(scope).
+		And("id != ?", f.ID).
+		And("lower(name) = ?", strings.ToLower(f.Name)).
+		Exist(new(IssueCustomField))
+}
+
+func checkIssueCustomField(e db.Engine, f *IssueCustomField) error {
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" || strings.Contains(f.Name, "=") {
+		return ErrInvalidIssueCustomField{Reason: "the name must not be empty nor contain '='"}
+	}
+	if _, ok := issueCustomFieldTypeNames[f.Type]; !ok {
+		return ErrInvalidIssueCustomField{Reason: fmt.Sprintf("unknown field type %d", f.Type)}
+	}
+
+	if f.Type != IssueCustomFieldTypeEnum {
+		f.Options = nil
+	} else {
+		if len(f.Options) == 0 {
+			return ErrInvalidIssueCustomField{Reason: "an enum field must have options"}
+		}
+		seen := make(map[string]bool, len(f.Options))
+		for i, option := range f.Options {
+			option = strings.TrimSpace(option)
+			if option == "" || seen[option] || utf8.RuneCountInString(option) > MaxIssueCustomFieldValueLength {
+				return ErrInvalidIssueCustomField{Reason: fmt.Sprintf("the options must be unique, not empty and up to %d characters", MaxIssueCustomFieldValueLength)}
+			}
+			seen[option] = true
+			f.Options[i] = option
+		}
+	}
+
+	if taken, err := isIssueCustomFieldNameTaken(e, f); err != nil {
+		return err
+	} else if taken {
+		return ErrIssueCustomFieldAlreadyExist{Name: f.Name}
+	}
+	return nil
+}
+
+// NewIssueCustomField creates a custom field of the issues of an organization or a repository
+func NewIssueCustomField(f *IssueCustomField) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if err := checkIssueCustomField(e, f); err != nil {
+			return err
+		}
+		_, err := e.Insert(f)
+		return err
+	})
+}
+
+// UpdateIssueCustomField updates the name, the description and the options of a custom field,
+// the values of the issues which are no longer an option of an enum field are removed
+func UpdateIssueCustomField(f *IssueCustomField) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if err := checkIssueCustomField(e, f); err != nil {
+			return err
+		}
+		if _, err := e.ID(f.ID).Cols("name", "description", "options").Update(f); err != nil {
+			return err
+		}
+		if f.Type != IssueCustomFieldTypeEnum {
+			return nil
+		}
+		_, err := e.Where("field_id = ?", f.ID).
+			And(builder.NotIn("value", f.Options)).
+			Delete(new(IssueCustomFieldValue))
+		return err
+	})
+}
+
+// GetIssueCustomFields returns the custom fields of an organization, or of a repository if orgID is 0
+func GetIssueCustomFields(orgID, repoID int64) ([]*IssueCustomField, error) {
+	fields := make([]*IssueCustomField, 0, 5)
+	return fields, db.DefaultContext().Engine().
+		Where("org_id = ? AND repo_id = ?", orgID, repoID).
+		Asc("id").
+		Find(&fields)
+}
+
+// GetIssueCustomFieldByID returns a custom field of an organization, or of a repository if orgID is 0
+func GetIssueCustomFieldByID(orgID, repoID, id int64) (*IssueCustomField, error) {
+	f := new(IssueCustomField)
+	has, err := db.DefaultContext().Engine().ID(id).Where("org_id = ? AND repo_id = ?", orgID, repoID).Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueCustomFieldNotExist{ID: id}
+	}
+	return f, nil
+}
+
+// DeleteIssueCustomField deletes a custom field and its values
+func DeleteIssueCustomField(f *IssueCustomField) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Delete(&IssueCustomFieldValue{FieldID: f.ID}); err != nil {
+			return err
+		}
+		_, err := e.ID(f.ID).Delete(new(IssueCustomField))
+		return err
+	})
+}
+
+func getIssueCustomFieldsForRepo(e db.Engine, repo *Repository) ([]*IssueCustomField, error) {
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+	cond := builder.Eq{"org_id": 0, "repo_id": repo.ID}
+	var scope builder.Cond = cond
+	if repo.Owner.IsOrganization() {
+		scope = builder.Or(cond, builder.Eq{"org_id": repo.OwnerID, "repo_id": 0})
+	}
+
+	fields := make([]*IssueCustomField, 0, 5)
+	return fields, e.Where(scope).Asc("repo_id", "id").Find(&fields)
+}
+
+// GetIssueCustomFieldsForRepo returns the custom fields the issues of a repository can have,
+// those of its organization first
+func GetIssueCustomFieldsForRepo(repo *Repository) ([]*IssueCustomField, error) {
+	return getIssueCustomFieldsForRepo(db.DefaultContext().Engine(), repo)
+}
+
+// IssueCustomFieldEntry is a custom field an issue can have with the value of the issue, empty if it has none
+type IssueCustomFieldEntry struct {
+	Field *IssueCustomField
+	Value string
+}
+
+// GetIssueCustomFieldEntries returns the custom fields an issue can have with its values
+func GetIssueCustomFieldEntries(issue *Issue) ([]*IssueCustomFieldEntry, error) {
+	e := db.DefaultContext().Engine()
+	if err := issue.loadRepo(e); err != nil {
+		return nil, err
+	}
+	fields, err := getIssueCustomFieldsForRepo(e, issue.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*IssueCustomFieldValue, 0, len(fields))
+	if err := e.Where("issue_id = ?", issue.ID).Find(&values); err != nil {
+		return nil, err
+	}
+	valueByField := make(map[int64]string, len(values))
+	for _, v := range values {
+		valueByField[v.FieldID] = v.Value
+	}
+
+	entries := make([]*IssueCustomFieldEntry, 0, len(fields))
+	for _, f := range fields {
+		entries = append(entries, &IssueCustomFieldEntry{Field: f, Value: valueByField[f.ID]})
+	}
+	return entries, nil
+}
+
+// GetIssueCustomFieldValues returns the values of the custom fields of an issue by field name,
+// the fields without value are omitted
+func GetIssueCustomFieldValues(issue *Issue) (map[string]string, error) {
+	entries, err := GetIssueCustomFieldEntries(issue)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.Value != "" {
+			values[entry.Field.Name] = entry.Value
+		}
+	}
+	return values, nil
+}
+
+// SetIssueCustomFieldValues sets the values of custom fields of an issue by field name,
+// an empty value removes the value of the field. The other fields are left unchanged.
+func SetIssueCustomFieldValues(issue *Issue, values map[string]string) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if err := issue.loadRepo(e); err != nil {
+			return err
+		}
+		fields, err := getIssueCustomFieldsForRepo(e, issue.Repo)
+		if err != nil {
+			return err
+		}
+		fieldByName := make(map[string]*IssueCustomField, len(fields))
+		for _, f := range fields {
+			fieldByName[strings.ToLower(f.Name)] = f
+		}
+
+		for name, value := range values {
+			f, ok := fieldByName[strings.ToLower(name)]
+			if !ok {
+				return ErrIssueCustomFieldNotExist{Name: name}
+			}
+			if _, err := e.Delete(&IssueCustomFieldValue{IssueID: issue.ID, FieldID: f.ID}); err != nil {
+				return err
+			}
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+			if value, err = f.NormalizeValue(value); err != nil {
+				return err
+			}
+			if _, err := e.Insert(&IssueCustomFieldValue{IssueID: issue.ID, FieldID: f.ID, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// issueCustomFieldsCond returns the condition selecting the issues having the given values of the custom fields
+// by field name, the values are compared as stored
+func issueCustomFieldsCond(values map[string]string) builder.Cond {
+	cond := builder.NewCond()
+	for name, value := range values {
+		cond = cond.And(builder.In("issue.id",
+			builder.Select("issue_custom_field_value.issue_id").
+				From("issue_custom_field_value").
+				Join("INNER", "issue_custom_field", "issue_custom_field.id = issue_custom_field_value.field_id").
+				Where(builder.Eq{
+					"lower(issue_custom_field.name)": strings.ToLower(name),
+					"issue_custom_field_value.value": value,
+				}),
+		))
+	}
+	return cond
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueCustomField_NormalizeValue(t *testing.T) {
+	number := &IssueCustomField{Name: "Estimate", Type: IssueCustomFieldTypeNumber}
+	v, err := number.NormalizeValue(" 2.50 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.5", v)
+	_, err = number.NormalizeValue("two")
+	assert.True(t, IsErrInvalidIssueCustomFieldValue(err))
+
+	date := &IssueCustomField{Name: "Due", Type: IssueCustomFieldTypeDate}
+	v, err = date.NormalizeValue("2021-11-05")
+	assert.NoError(t, err)
+	assert.Equal(t, "2021-11-05", v)
+	_, err = date.NormalizeValue("05/11/2021")
+	assert.True(t, IsErrInvalidIssueCustomFieldValue(err))
+
+	enum := &IssueCustomField{Name: "Severity", Type: IssueCustomFieldTypeEnum, Options: []string{"low", "high"}}
+	_, err = enum.NormalizeValue("high")
+	assert.NoError(t, err)
+	_, err = enum.NormalizeValue("medium")
+	assert.True(t, IsErrInvalidIssueCustomFieldValue(err))
+}
+
+func TestNewIssueCustomField(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.True(t, IsErrInvalidIssueCustomField(NewIssueCustomField(&IssueCustomField{RepoID: 1, Name: "a=b", Type: IssueCustomFieldTypeText})))
+	assert.True(t, IsErrInvalidIssueCustomField(NewIssueCustomField(&IssueCustomField{RepoID: 1, Name: "Severity", Type: IssueCustomFieldTypeEnum})))
+
+	assert.NoError(t, NewIssueCustomField(&IssueCustomField{RepoID: 1, Name: "Estimate", Type: IssueCustomFieldTypeNumber}))
+	assert.True(t, IsErrIssueCustomFieldAlreadyExist(NewIssueCustomField(&IssueCustomField{RepoID: 1, Name: "estimate", Type: IssueCustomFieldTypeText})))
+
+	// the fields of an organization share their names with those of its repositories
+	assert.NoError(t, NewIssueCustomField(&IssueCustomField{RepoID: 3, Name: "Team", Type: IssueCustomFieldTypeText}))
+	assert.True(t, IsErrIssueCustomFieldAlreadyExist(NewIssueCustomField(&IssueCustomField{OrgID: 3, Name: "Team", Type: IssueCustomFieldTypeText})))
+	assert.NoError(t, NewIssueCustomField(&IssueCustomField{OrgID: 3, Name: "Estimate", Type: IssueCustomFieldTypeNumber}))
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	fields, err := GetIssueCustomFieldsForRepo(repo)
+	assert.NoError(t, err)
+	if assert.Len(t, fields, 2) {
+		assert.Equal(t, "Estimate", fields[0].Name)
+		assert.Equal(t, "Team", fields[1].Name)
+	}
+}
+
+func TestSetIssueCustomFieldValues(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	estimate := &IssueCustomField{RepoID: 1, Name: "Estimate", Type: IssueCustomFieldTypeNumber}
+	assert.NoError(t, NewIssueCustomField(estimate))
+	severity := &IssueCustomField{RepoID: 1, Name: "Severity", Type: IssueCustomFieldTypeEnum, Options: []string{"low", "high"}}
+	assert.NoError(t, NewIssueCustomField(severity))
+
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.True(t, IsErrIssueCustomFieldNotExist(SetIssueCustomFieldValues(issue, map[string]string{"Unknown": "1"})))
+	assert.True(t, IsErrInvalidIssueCustomFieldValue(SetIssueCustomFieldValues(issue, map[string]string{"Estimate": "a lot"})))
+	assert.NoError(t, SetIssueCustomFieldValues(issue, map[string]string{"estimate": "3.0", "Severity": "high"}))
+
+	values, err := GetIssueCustomFieldValues(issue)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Estimate": "3", "Severity": "high"}, values)
+
+	issues, err := Issues(&IssuesOptions{RepoIDs: []int64{1}, CustomFields: map[string]string{"severity": "high"}})
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 1, issues[0].ID)
+	}
+
+	// an empty value removes the value of the field
+	assert.NoError(t, SetIssueCustomFieldValues(issue, map[string]string{"Estimate": ""}))
+	db.AssertNotExistsBean(t, &IssueCustomFieldValue{IssueID: 1, FieldID: estimate.ID})
+
+	// the values which are no longer options of an enum are removed
+	severity.Options = []string{"low", "medium"}
+	assert.NoError(t, UpdateIssueCustomField(severity))
+	db.AssertNotExistsBean(t, &IssueCustomFieldValue{IssueID: 1, FieldID: severity.ID})
+
+	assert.NoError(t, DeleteIssueCustomField(estimate))
+	db.AssertNotExistsBean(t, &IssueCustomField{ID: estimate.ID})
+}
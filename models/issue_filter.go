@@ -0,0 +1,166 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+var (
+	// ErrIssueFilterNotExist indicates a saved issue filter not exist error
+	ErrIssueFilterNotExist = errors.New("Issue filter does not exist")
+	// ErrIssueFilterAlreadyExist indicates a saved issue filter with the same name exists already
+	ErrIssueFilterAlreadyExist = errors.New("Issue filter already exists")
+	// ErrIssueFilterNameInvalid indicates an empty or too long name of a saved issue filter
+	ErrIssueFilterNameInvalid = errors.New("Issue filter name is invalid")
+)
+
+// ErrIssueFilterQueryInvalid represents a query of a saved issue filter which can not be executed
+type ErrIssueFilterQueryInvalid struct {
+	Query  string
+	Reason string
+}
+
+// IsErrIssueFilterQueryInvalid checks if an error is a ErrIssueFilterQueryInvalid.
+func IsErrIssueFilterQueryInvalid(err error) bool {
+	_, ok := err.(ErrIssueFilterQueryInvalid)
+	return ok
+}
+
+func (err ErrIssueFilterQueryInvalid) Error() string {
+	return fmt.Sprintf("invalid issue filter query %q: %s", err.Query, err.Reason)
+}
+
+// IssueFilterQueryKeys are the parameters of the issue search which may be saved in a filter
+var IssueFilterQueryKeys = []string{
+	"state", "labels", "milestones", "q", "type", "since", "before",
+	"assigned", "created", "mentioned", "review_requested", "owner", "team", "priority_repo_id",
+}
+
+// IssueFilter represents a named issue search query saved by a user or an organization
+type IssueFilter struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name        string             `xorm:"UNIQUE(s) NOT NULL"`
+	Query       string             `xorm:"TEXT NOT NULL"`
+	CreatorID   int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueFilter))
+}
+
+// ParseIssueFilterQuery parses the query of a saved issue filter and checks that
+// it only consists of issue search parameters
+func ParseIssueFilterQuery(query string) (url.Values, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return nil, ErrIssueFilterQueryInvalid{Query: query, Reason: err.Error()}
+	}
+	for key := range values {
+		found := false
+		for _, k := range IssueFilterQueryKeys {
+			if k == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrIssueFilterQueryInvalid{Query: query, Reason: fmt.Sprintf("unknown parameter %s", key)}
+		}
+	}
+	return values, nil
+}
+
+func (f *IssueFilter) normalize() error {
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" || len(f.Name) > 255 {
+		return ErrIssueFilterNameInvalid
+	}
+	values, err := ParseIssueFilterQuery(f.Query)
+	if err != nil {
+		return err
+	}
+	f.Query = values.Encode()
+	return nil
+}
+
+func isIssueFilterNameUsed(e db.Engine, f *IssueFilter) (bool, error) {
+	return e.Where("owner_id = ? AND name = ? AND id != ?", f.OwnerID, f.Name, f.ID).Exist(new(IssueFilter))
+}
+
+// CreateIssueFilter saves a new issue filter
+func CreateIssueFilter(f *IssueFilter) error {
+	if err := f.normalize(); err != nil {
+		return err
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		if used, err := isIssueFilterNameUsed(ctx.Engine(), f); err != nil {
+			return err
+		} else if used {
+			return ErrIssueFilterAlreadyExist
+		}
+		_, err := ctx.Engine().Insert(f)
+		return err
+	})
+}
+
+// UpdateIssueFilter updates the name and the query of a saved issue filter
+func UpdateIssueFilter(f *IssueFilter) error {
+	if err := f.normalize(); err != nil {
+		return err
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		if used, err := isIssueFilterNameUsed(ctx.Engine(), f); err != nil {
+			return err
+		} else if used {
+			return ErrIssueFilterAlreadyExist
+		}
+		_, err := ctx.Engine().ID(f.ID).Cols("name", "query").Update(f)
+		return err
+	})
+}
+
+// GetIssueFilterByID returns the saved issue filter of an owner by its id
+func GetIssueFilterByID(ownerID, id int64) (*IssueFilter, error) {
+	f := &IssueFilter{ID: id, OwnerID: ownerID}
+	has, err := db.DefaultContext().Engine().Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueFilterNotExist
+	}
+	return f, nil
+}
+
+// FindIssueFilters returns the saved issue filters of an owner ordered by name
+func FindIssueFilters(ownerID int64, listOptions ListOptions) ([]*IssueFilter, int64, error) {
+	sess := db.DefaultContext().Engine().Where("owner_id = ?", ownerID).Asc("name")
+	if listOptions.Page > 0 {
+		sess = setSessionPagination(sess, &listOptions)
+	}
+	filters := make([]*IssueFilter, 0, 10)
+	count, err := sess.FindAndCount(&filters)
+	return filters, count, err
+}
+
+// DeleteIssueFilter deletes a saved issue filter of an owner
+func DeleteIssueFilter(ownerID, id int64) error {
+	deleted, err := db.DefaultContext().Engine().Delete(&IssueFilter{ID: id, OwnerID: ownerID})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrIssueFilterNotExist
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIssueFilterQuery(t *testing.T) {
+	values, err := ParseIssueFilterQuery("?state=open&labels=bug,help&type=issues")
+	assert.NoError(t, err)
+	assert.Equal(t, "bug,help", values.Get("labels"))
+
+	_, err = ParseIssueFilterQuery("state=open&page=2")
+	assert.True(t, IsErrIssueFilterQueryInvalid(err))
+	_, err = ParseIssueFilterQuery("state=%zz")
+	assert.True(t, IsErrIssueFilterQueryInvalid(err))
+}
+
+func TestIssueFilters(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	f := &IssueFilter{OwnerID: 2, Name: " Open bugs ", Query: "type=issues&labels=bug", CreatorID: 2}
+	assert.NoError(t, CreateIssueFilter(f))
+	assert.Equal(t, "Open bugs", f.Name)
+	assert.Equal(t, "labels=bug&type=issues", f.Query)
+
+	assert.Equal(t, ErrIssueFilterAlreadyExist, CreateIssueFilter(&IssueFilter{OwnerID: 2, Name: "Open bugs", CreatorID: 2}))
+	assert.Equal(t, ErrIssueFilterNameInvalid, CreateIssueFilter(&IssueFilter{OwnerID: 2, Name: " ", CreatorID: 2}))
+	assert.True(t, IsErrIssueFilterQueryInvalid(CreateIssueFilter(&IssueFilter{OwnerID: 2, Name: "paged", Query: "limit=1", CreatorID: 2})))
+	assert.NoError(t, CreateIssueFilter(&IssueFilter{OwnerID: 3, Name: "Open bugs", CreatorID: 2}))
+
+	other := &IssueFilter{OwnerID: 2, Name: "Assigned", Query: "assigned=true", CreatorID: 2}
+	assert.NoError(t, CreateIssueFilter(other))
+
+	filters, count, err := FindIssueFilters(2, ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, filters, 2) {
+		assert.Equal(t, "Assigned", filters[0].Name)
+		assert.Equal(t, "Open bugs", filters[1].Name)
+	}
+
+	other.Name = "Open bugs"
+	assert.Equal(t, ErrIssueFilterAlreadyExist, UpdateIssueFilter(other))
+	other.Name = "Assigned to me"
+	assert.NoError(t, UpdateIssueFilter(other))
+
+	loaded, err := GetIssueFilterByID(2, other.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Assigned to me", loaded.Name)
+	_, err = GetIssueFilterByID(3, other.ID)
+	assert.Equal(t, ErrIssueFilterNotExist, err)
+
+	assert.Equal(t, ErrIssueFilterNotExist, DeleteIssueFilter(3, other.ID))
+	assert.NoError(t, DeleteIssueFilter(2, other.ID))
+	db.AssertNotExistsBean(t, &IssueFilter{ID: other.ID})
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueForm represents the values of the issue form an issue was created with
+type IssueForm struct {
+	ID      int64 `xorm:"pk autoincr"`
+	IssueID int64 `xorm:"UNIQUE NOT NULL"`
+	// Template is the file name of the issue form
+	Template string
+	// Values are the submitted values by field id
+	Values      map[string][]string `xorm:"JSON TEXT"`
+	CreatedUnix timeutil.TimeStamp  `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueForm))
+}
+
+// ErrIssueFormNotExist represents a "IssueFormNotExist" kind of error.
+type ErrIssueFormNotExist struct {
+	IssueID int64
+}
+
+// IsErrIssueFormNotExist checks if an error is a ErrIssueFormNotExist.
+func IsErrIssueFormNotExist(err error) bool {
+	_, ok := err.(ErrIssueFormNotExist)
+	return ok
+}
+
+func (err ErrIssueFormNotExist) Error() string {
+	return fmt.Sprintf("issue form does not exist [issue_id: %d]", err.IssueID)
+}
+
+// InsertIssueForm stores the values of the issue form an issue was created with
+func InsertIssueForm(form *IssueForm) error {
+	_, err := db.DefaultContext().Engine().Insert(form)
+	return err
+}
+
+// GetIssueForm returns the values of the issue form an issue was created with
+func GetIssueForm(issueID int64) (*IssueForm, error) {
+	form := &IssueForm{IssueID: issueID}
+	has, err := db.DefaultContext().Engine().Get(form)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueFormNotExist{IssueID: issueID}
+	}
+	return form, nil
+}
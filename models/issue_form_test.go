@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueForm(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	_, err := GetIssueForm(1)
+	assert.True(t, IsErrIssueFormNotExist(err))
+
+	values := map[string][]string{"version": {"1.16.0"}, "browsers": {"Firefox", "Chrome"}}
+	assert.NoError(t, InsertIssueForm(&IssueForm{IssueID: 1, Template: "bug.yaml", Values: values}))
+
+	form, err := GetIssueForm(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "bug.yaml", form.Template)
+	assert.Equal(t, values, form.Values)
+}
@@ -0,0 +1,408 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// The limits of the settings of an iteration cadence
+const (
+	MaxIterationDurationDays  = 365
+	MaxIterationsCreatedAhead = 10
+)
+
+// IterationCadence is a schedule of fixed-length iterations (sprints) of an organization or a repository.
+// The iterations roll automatically: the current one and the next ones are created as time passes.
+type IterationCadence struct {
+	ID int64 `xorm:"pk autoincr"`
+	// OrgID is the organization of the cadence, 0 for the cadence of a repository
+	OrgID  int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	RepoID int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Title  string `xorm:"NOT NULL"`
+	// DurationDays is the length of each iteration
+	DurationDays int `xorm:"NOT NULL"`
+	// IterationsAhead is the number of iterations created after the current one
+	IterationsAhead int                `xorm:"NOT NULL DEFAULT 1"`
+	StartUnix       timeutil.TimeStamp `xorm:"NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// Iteration is an iteration of a cadence, the issues assigned to it are planned to be closed before it ends
+type Iteration struct {
+	ID        int64             `xorm:"pk autoincr"`
+	CadenceID int64             `xorm:"UNIQUE(s) NOT NULL"`
+	Cadence   *IterationCadence `xorm:"-"`
+	// Sequence is the number of the iteration in its cadence, starting at 1
+	Sequence int    `xorm:"UNIQUE(s) NOT NULL"`
+	OrgID    int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	RepoID   int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Title    string `xorm:"NOT NULL"`
+	// EndUnix is the end of the iteration, exclusive
+	StartUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	EndUnix   timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(IterationCadence))
+	db.RegisterModel(new(Iteration))
+}
+
+// ErrIterationCadenceNotExist represents a "IterationCadenceNotExist" kind of error.
+type ErrIterationCadenceNotExist struct {
+	ID int64
+}
+
+// IsErrIterationCadenceNotExist checks if an error is a ErrIterationCadenceNotExist.
+func IsErrIterationCadenceNotExist(err error) bool {
+	_, ok := err.(ErrIterationCadenceNotExist)
+	return ok
+}
+
+func (err ErrIterationCadenceNotExist) Error() string {
+	return fmt.Sprintf("iteration cadence does not exist [id: %d]", err.ID)
+}
+
+// ErrIterationNotExist represents a "IterationNotExist" kind of error.
+type ErrIterationNotExist struct {
+	ID int64
+}
+
+// IsErrIterationNotExist checks if an error is a ErrIterationNotExist.
+func IsErrIterationNotExist(err error) bool {
+	_, ok := err.(ErrIterationNotExist)
+	return ok
+}
+
+func (err ErrIterationNotExist) Error() string {
+	return fmt.Sprintf("iteration does not exist [id: %d]", err.ID)
+}
+
+// ErrInvalidIterationCadence represents a "InvalidIterationCadence" kind of error.
+type ErrInvalidIterationCadence struct {
+	Reason string
+}
+
+// IsErrInvalidIterationCadence checks if an error is a ErrInvalidIterationCadence.
+func IsErrInvalidIterationCadence(err error) bool {
+	_, ok := err.(ErrInvalidIterationCadence)
+	return ok
+}
+
+func (err ErrInvalidIterationCadence) Error() string {
+	return fmt.Sprintf("invalid iteration cadence: %s", err.Reason)
+}
+
+// The states of an iteration
+const (
+	IterationStatePast     = "past"
+	IterationStateCurrent  = "current"
+	IterationStateUpcoming = "upcoming"
+)
+
+// State returns whether the iteration is past, current or upcoming
+func (it *Iteration) State() string {
+	now := timeutil.TimeStampNow()
+	switch {
+	case now >= it.EndUnix:
+		return IterationStatePast
+	case now >= it.StartUnix:
+		return IterationStateCurrent
+	}
+	return IterationStateUpcoming
+}
+
+// duration returns the length of the iterations of the cadence in seconds
+func (c *IterationCadence) duration() int64 {
+	return int64(c.DurationDays) * int64(24*time.Hour/time.Second)
+}
+
+func checkIterationCadence(c *IterationCadence) error {
+	if c.DurationDays < 1 || c.DurationDays > MaxIterationDurationDays {
+		return ErrInvalidIterationCadence{Reason: fmt.Sprintf("the duration must be between 1 and %d days", MaxIterationDurationDays)}
+	}
+	if c.IterationsAhead < 0 || c.IterationsAhead > MaxIterationsCreatedAhead {
+		return ErrInvalidIterationCadence{Reason: fmt.Sprintf("the iterations created ahead must be between 0 and %d", MaxIterationsCreatedAhead)}
+	}
+	return nil
+}
+
+// NewIterationCadence creates an iteration cadence and its first iterations
+func NewIterationCadence(c *IterationCadence) error {
+	if err := checkIterationCadence(c); err != nil {
+		return err
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Insert(c); err != nil {
+			return err
+		}
+		return generateIterations(e, c, timeutil.TimeStampNow())
+	})
+}
+
+// UpdateIterationCadence updates the title and the iterations created ahead of a cadence,
+// the titles of the existing iterations are updated too
+func UpdateIterationCadence(c *IterationCadence) error {
+	if err := checkIterationCadence(c); err != nil {
+		return err
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.ID(c.ID).Cols("title", "iterations_ahead").Update(c); err != nil {
+			return err
+		}
+
+		iterations := make([]*Iteration, 0, 10)
+		if err := e.Where("cadence_id = ?", c.ID).Find(&iterations); err != nil {
+			return err
+		}
+		for _, it := range iterations {
+			it.Title = iterationTitle(c, it.Sequence)
+			if _, err := e.ID(it.ID).Cols("title").Update(it); err != nil {
+				return err
+			}
+		}
+		return generateIterations(e, c, timeutil.TimeStampNow())
+	})
+}
+
+// GetIterationCadences returns the iteration cadences of an organization, or of a repository if orgID is 0
+func GetIterationCadences(orgID, repoID int64) ([]*IterationCadence, error) {
+	cadences := make([]*IterationCadence, 0, 2)
+	return cadences, db.DefaultContext().Engine().
+		Where("org_id = ? AND repo_id = ?", orgID, repoID).
+		Asc("id").
+		Find(&cadences)
+}
+
+// GetIterationCadenceByID returns an iteration cadence of an organization, or of a repository if orgID is 0
+func GetIterationCadenceByID(orgID, repoID, id int64) (*IterationCadence, error) {
+	c := new(IterationCadence)
+	has, err := db.DefaultContext().Engine().ID(id).Where("org_id = ? AND repo_id = ?", orgID, repoID).Get(c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIterationCadenceNotExist{ID: id}
+	}
+	return c, nil
+}
+
+// DeleteIterationCadence deletes an iteration cadence and its iterations, the issues assigned to them are unassigned
+func DeleteIterationCadence(c *IterationCadence) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.In("iteration_id", builder.Select("id").From("iteration").Where(builder.Eq{"cadence_id": c.ID})).
+			Cols("iteration_id").
+			NoAutoTime().
+			Update(&Issue{}); err != nil {
+			return err
+		}
+		if _, err := e.Delete(&Iteration{CadenceID: c.ID}); err != nil {
+			return err
+		}
+		_, err := e.ID(c.ID).Delete(new(IterationCadence))
+		return err
+	})
+}
+
+// GetAllIterationCadences returns the iteration cadences of all organizations and repositories
+func GetAllIterationCadences() ([]*IterationCadence, error) {
+	cadences := make([]*IterationCadence, 0, 10)
+	return cadences, db.DefaultContext().Engine().Asc("id").Find(&cadences)
+}
+
+// RollIterationCadence creates the current iteration of a cadence and the ones ahead which do not exist yet
+func RollIterationCadence(c *IterationCadence, now time.Time) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		return generateIterations(ctx.Engine(), c, timeutil.TimeStamp(now.Unix()))
+	})
+}
+
+func iterationTitle(c *IterationCadence, sequence int) string {
+	return fmt.Sprintf("%s %d", c.Title, sequence)
+}
+
+// generateIterations creates the current iteration of a cadence and the ones ahead which do not exist yet.
+// Past iterations which were never created are skipped, the sequence numbers count them anyway.
+func generateIterations(e db.Engine, c *IterationCadence, now timeutil.TimeStamp) error {
+	duration := c.duration()
+	current := 0
+	if now >= c.StartUnix {
+		current = int((int64(now) - int64(c.StartUnix)) / duration)
+	}
+
+	var last int
+	if _, err := e.Table("iteration").Where("cadence_id = ?", c.ID).Select("COALESCE(MAX(sequence), 0)").Get(&last); err != nil {
+		return err
+	}
+
+	first := current + 1
+	if last >= first {
+		first = last + 1
+	}
+	for seq := first; seq <= current+1+c.IterationsAhead; seq++ {
+		start := int64(c.StartUnix) + int64(seq-1)*duration
+		if _, err := e.Insert(&Iteration{
+			CadenceID: c.ID,
+			Sequence:  seq,
+			OrgID:     c.OrgID,
+			RepoID:    c.RepoID,
+			Title:     iterationTitle(c, seq),
+			StartUnix: timeutil.TimeStamp(start),
+			EndUnix:   timeutil.TimeStamp(start + duration),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindIterationOptions represents the options to find the iterations of an organization or a repository
+type FindIterationOptions struct {
+	OrgID  int64
+	RepoID int64
+	// IncludeOrgID includes the iterations of the organization owning the repository
+	IncludeOrgID int64
+	// State is one of IterationStatePast, IterationStateCurrent and IterationStateUpcoming, all if empty
+	State string
+}
+
+func (opts *FindIterationOptions) toConds(now timeutil.TimeStamp) builder.Cond {
+	cond := builder.Eq{"org_id": opts.OrgID, "repo_id": opts.RepoID}
+	var scope builder.Cond = cond
+	if opts.IncludeOrgID != 0 {
+		scope = builder.Or(cond, builder.Eq{"org_id": opts.IncludeOrgID, "repo_id": 0})
+	}
+
+	switch opts.State {
+	case IterationStatePast:
+		return builder.And(scope, builder.Lte{"end_unix": now})
+	case IterationStateCurrent:
+		return builder.And(scope, builder.Lte{"start_unix": now}, builder.Gt{"end_unix": now})
+	case IterationStateUpcoming:
+		return builder.And(scope, builder.Gt{"start_unix": now})
+	}
+	return scope
+}
+
+// FindIterations returns the iterations matching the options, ordered by their start
+func FindIterations(opts *FindIterationOptions) ([]*Iteration, error) {
+	iterations := make([]*Iteration, 0, 10)
+	return iterations, db.DefaultContext().Engine().
+		Where(opts.toConds(timeutil.TimeStampNow())).
+		Asc("start_unix", "cadence_id").
+		Find(&iterations)
+}
+
+func getIterationByID(e db.Engine, id int64) (*Iteration, error) {
+	it := new(Iteration)
+	has, err := e.ID(id).Get(it)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIterationNotExist{ID: id}
+	}
+	return it, nil
+}
+
+// GetIterationByID returns an iteration by its ID
+func GetIterationByID(id int64) (*Iteration, error) {
+	return getIterationByID(db.DefaultContext().Engine(), id)
+}
+
+// getIterationForRepo returns an iteration the issues of a repository can be assigned to,
+// an iteration of the repository or of the organization owning it
+func getIterationForRepo(e db.Engine, repo *Repository, id int64) (*Iteration, error) {
+	it, err := getIterationByID(e, id)
+	if err != nil {
+		return nil, err
+	}
+	if it.RepoID != repo.ID && (it.OrgID == 0 || it.OrgID != repo.OwnerID) {
+		return nil, ErrIterationNotExist{ID: id}
+	}
+	return it, nil
+}
+
+// GetIterationForRepo returns an iteration the issues of a repository can be assigned to
+func GetIterationForRepo(repo *Repository, id int64) (*Iteration, error) {
+	return getIterationForRepo(db.DefaultContext().Engine(), repo, id)
+}
+
+func (issue *Issue) loadIteration(e db.Engine) (err error) {
+	if issue.Iteration == nil && issue.IterationID > 0 {
+		issue.Iteration, err = getIterationByID(e, issue.IterationID)
+		if IsErrIterationNotExist(err) {
+			return nil
+		}
+	}
+	return err
+}
+
+// LoadIteration loads the iteration of an issue
+func (issue *Issue) LoadIteration() error {
+	return issue.loadIteration(db.DefaultContext().Engine())
+}
+
+// ChangeIssueIteration assigns an issue to an iteration of its repository or of the organization owning it,
+// or unassigns it if iterationID is 0
+func ChangeIssueIteration(issue *Issue, iterationID int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if err := issue.loadRepo(e); err != nil {
+			return err
+		}
+		var it *Iteration
+		if iterationID != 0 {
+			var err error
+			if it, err = getIterationForRepo(e, issue.Repo, iterationID); err != nil {
+				return err
+			}
+		}
+
+		issue.IterationID = iterationID
+		issue.Iteration = it
+		return updateIssueCols(e, issue, "iteration_id")
+	})
+}
+
+// IterationStats are the statistics of the issues assigned to an iteration
+type IterationStats struct {
+	Issues       int64
+	ClosedIssues int64
+	// Velocity is the number of issues closed before the end of the iteration
+	Velocity int64
+}
+
+// GetIterationStats returns the statistics of the issues assigned to an iteration
+func GetIterationStats(it *Iteration) (*IterationStats, error) {
+	stats := new(IterationStats)
+	e := db.DefaultContext().Engine()
+	cond := builder.Eq{"iteration_id": it.ID, "is_pull": false}
+
+	var err error
+	if stats.Issues, err = e.Where(cond).Count(new(Issue)); err != nil {
+		return nil, err
+	}
+	if stats.ClosedIssues, err = e.Where(cond).And("is_closed = ?", true).Count(new(Issue)); err != nil {
+		return nil, err
+	}
+	if stats.Velocity, err = e.Where(cond).And("is_closed = ?", true).
+		And("closed_unix < ?", it.EndUnix).
+		Count(new(Issue)); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIterationCadence(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.True(t, IsErrInvalidIterationCadence(NewIterationCadence(&IterationCadence{RepoID: 1, Title: "Sprint", DurationDays: 0})))
+	assert.True(t, IsErrInvalidIterationCadence(NewIterationCadence(&IterationCadence{RepoID: 1, Title: "Sprint", DurationDays: 14, IterationsAhead: MaxIterationsCreatedAhead + 1})))
+
+	// the cadence started 30 days ago, the third iteration is the current one
+	start := time.Now().AddDate(0, 0, -30)
+	cadence := &IterationCadence{
+		RepoID:          1,
+		Title:           "Sprint",
+		DurationDays:    14,
+		IterationsAhead: 1,
+		StartUnix:       timeutil.TimeStamp(start.Unix()),
+	}
+	assert.NoError(t, NewIterationCadence(cadence))
+
+	iterations, err := FindIterations(&FindIterationOptions{RepoID: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, iterations, 2) {
+		assert.Equal(t, 3, iterations[0].Sequence)
+		assert.Equal(t, "Sprint 3", iterations[0].Title)
+		assert.Equal(t, IterationStateCurrent, iterations[0].State())
+		assert.Equal(t, IterationStateUpcoming, iterations[1].State())
+		assert.Equal(t, iterations[0].EndUnix, iterations[1].StartUnix)
+	}
+
+	// rolling forward creates the missing iterations only
+	assert.NoError(t, RollIterationCadence(cadence, start.AddDate(0, 0, 45)))
+	iterations, err = FindIterations(&FindIterationOptions{RepoID: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, iterations, 3) {
+		assert.Equal(t, 5, iterations[2].Sequence)
+	}
+
+	cadence.Title = "Iteration"
+	assert.NoError(t, UpdateIterationCadence(cadence))
+	it := db.AssertExistsAndLoadBean(t, &Iteration{CadenceID: cadence.ID, Sequence: 3}).(*Iteration)
+	assert.Equal(t, "Iteration 3", it.Title)
+
+	iterations, err = FindIterations(&FindIterationOptions{RepoID: 1, State: IterationStatePast})
+	assert.NoError(t, err)
+	assert.Len(t, iterations, 0)
+}
+
+func TestChangeIssueIteration(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	cadence := &IterationCadence{RepoID: 1, Title: "Sprint", DurationDays: 7, StartUnix: timeutil.TimeStampNow()}
+	assert.NoError(t, NewIterationCadence(cadence))
+	it := db.AssertExistsAndLoadBean(t, &Iteration{CadenceID: cadence.ID, Sequence: 1}).(*Iteration)
+
+	// iterations of other repositories cannot be assigned
+	other := &IterationCadence{RepoID: 2, Title: "Other", DurationDays: 7, StartUnix: timeutil.TimeStampNow()}
+	assert.NoError(t, NewIterationCadence(other))
+	otherIt := db.AssertExistsAndLoadBean(t, &Iteration{CadenceID: other.ID, Sequence: 1}).(*Iteration)
+
+	issue1 := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.True(t, IsErrIterationNotExist(ChangeIssueIteration(issue1, otherIt.ID)))
+	assert.NoError(t, ChangeIssueIteration(issue1, it.ID))
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 1, IterationID: it.ID})
+
+	// closed issues count towards the velocity, pull requests are ignored
+	closed := db.AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	closed.ClosedUnix = timeutil.TimeStampNow()
+	assert.NoError(t, updateIssueCols(db.DefaultContext().Engine(), closed, "closed_unix"))
+	assert.NoError(t, ChangeIssueIteration(closed, it.ID))
+	pull := db.AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	assert.NoError(t, ChangeIssueIteration(pull, it.ID))
+
+	stats, err := GetIterationStats(it)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, stats.Issues)
+	assert.EqualValues(t, 1, stats.ClosedIssues)
+	assert.EqualValues(t, 1, stats.Velocity)
+
+	assert.NoError(t, DeleteIterationCadence(cadence))
+	db.AssertNotExistsBean(t, &Iteration{ID: it.ID})
+	issue1 = db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.EqualValues(t, 0, issue1.IterationID)
+}
@@ -4,13 +4,58 @@
 
 package models
 
-import "code.gitea.io/gitea/models/db"
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueLockReason represents the reason an issue was locked for
+type IssueLockReason int
+
+// Note: new type must append to the end of list to maintain compatibility.
+const (
+	IssueLockReasonNone IssueLockReason = iota
+	IssueLockReasonOffTopic
+	IssueLockReasonTooHeated
+	IssueLockReasonResolved
+	IssueLockReasonSpam
+)
+
+var issueLockReasonNames = map[IssueLockReason]string{
+	IssueLockReasonNone:      "",
+	IssueLockReasonOffTopic:  "off_topic",
+	IssueLockReasonTooHeated: "too_heated",
+	IssueLockReasonResolved:  "resolved",
+	IssueLockReasonSpam:      "spam",
+}
+
+// String returns the name of the lock reason
+func (r IssueLockReason) String() string {
+	return issueLockReasonNames[r]
+}
+
+// IssueLockReasonFromString returns the lock reason matching name. Case, spaces and
+// hyphens are ignored, so the configured reasons like "Too heated" are recognized.
+func IssueLockReasonFromString(name string) (IssueLockReason, bool) {
+	name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+	for r, n := range issueLockReasonNames {
+		if n == name {
+			return r, true
+		}
+	}
+	return IssueLockReasonNone, false
+}
 
 // IssueLockOptions defines options for locking and/or unlocking an issue/PR
 type IssueLockOptions struct {
 	Doer   *User
 	Issue  *Issue
 	Reason string
+	// Until is the time the issue is unlocked automatically, 0 keeps it locked
+	Until timeutil.TimeStamp
 }
 
 // LockIssue locks an issue. This would limit commenting abilities to
@@ -33,8 +78,12 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 	var commentType CommentType
 	if opts.Issue.IsLocked {
 		commentType = CommentTypeLock
+		opts.Issue.LockReason, _ = IssueLockReasonFromString(opts.Reason)
+		opts.Issue.LockedUntilUnix = opts.Until
 	} else {
 		commentType = CommentTypeUnlock
+		opts.Issue.LockReason = IssueLockReasonNone
+		opts.Issue.LockedUntilUnix = 0
 	}
 
 	sess := db.DefaultContext().NewSession()
@@ -43,7 +92,7 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 		return err
 	}
 
-	if err := updateIssueCols(sess, opts.Issue, "is_locked"); err != nil {
+	if err := updateIssueCols(sess, opts.Issue, "is_locked", "lock_reason", "locked_until_unix"); err != nil {
 		return err
 	}
 
@@ -60,3 +109,34 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 
 	return sess.Commit()
 }
+
+// FindIssuesToUnlock returns the locked issues whose lock expired before the given time
+func FindIssuesToUnlock(now time.Time) ([]*Issue, error) {
+	issues := make([]*Issue, 0, 10)
+	return issues, db.DefaultContext().Engine().
+		Where("is_locked = ? AND locked_until_unix > 0 AND locked_until_unix <= ?", true, now.Unix()).
+		Find(&issues)
+}
+
+// GetIssueLocker returns the user who locked the issue, or the ghost user if that user does not exist anymore
+func GetIssueLocker(issue *Issue) (*User, error) {
+	comment := new(Comment)
+	has, err := db.DefaultContext().Engine().
+		Where("issue_id = ? AND type = ?", issue.ID, CommentTypeLock).
+		Desc("id").
+		Get(comment)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return NewGhostUser(), nil
+	}
+
+	locker, err := GetUserByID(comment.PosterID)
+	if err != nil {
+		if IsErrUserNotExist(err) {
+			return NewGhostUser(), nil
+		}
+		return nil, err
+	}
+	return locker, nil
+}
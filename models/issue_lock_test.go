@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueLockReasonFromString(t *testing.T) {
+	for name, expected := range map[string]IssueLockReason{
+		"":           IssueLockReasonNone,
+		"off_topic":  IssueLockReasonOffTopic,
+		"Off-topic":  IssueLockReasonOffTopic,
+		"Too heated": IssueLockReasonTooHeated,
+		"resolved":   IssueLockReasonResolved,
+		"Spam":       IssueLockReasonSpam,
+	} {
+		reason, ok := IssueLockReasonFromString(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, reason, name)
+	}
+
+	_, ok := IssueLockReasonFromString("boring")
+	assert.False(t, ok)
+}
+
+func TestLockIssue(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.NoError(t, issue.LoadRepo())
+
+	until := timeutil.TimeStamp(time.Now().Add(-time.Minute).Unix())
+	assert.NoError(t, LockIssue(&IssueLockOptions{Doer: doer, Issue: issue, Reason: "Too heated", Until: until}))
+	issue = db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.True(t, issue.IsLocked)
+	assert.Equal(t, IssueLockReasonTooHeated, issue.LockReason)
+	assert.Equal(t, until, issue.LockedUntilUnix)
+
+	issues, err := FindIssuesToUnlock(time.Now())
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 1, issues[0].ID)
+	}
+
+	locker, err := GetIssueLocker(issue)
+	assert.NoError(t, err)
+	assert.EqualValues(t, doer.ID, locker.ID)
+
+	assert.NoError(t, issue.LoadRepo())
+	assert.NoError(t, UnlockIssue(&IssueLockOptions{Doer: doer, Issue: issue}))
+	issue = db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.False(t, issue.IsLocked)
+	assert.Equal(t, IssueLockReasonNone, issue.LockReason)
+	assert.EqualValues(t, 0, issue.LockedUntilUnix)
+
+	issues, err = FindIssuesToUnlock(time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, issues, 0)
+}
@@ -18,11 +18,26 @@ import (
 	"xorm.io/xorm"
 )
 
-// Milestone represents a milestone of repository.
+// MilestoneOwnerType defines what a milestone belongs to
+type MilestoneOwnerType int
+
+const (
+	// MilestoneOwnerRepository is a milestone of a single repository
+	MilestoneOwnerRepository MilestoneOwnerType = iota
+	// MilestoneOwnerOrganization is a milestone shared by the repositories of an organization
+	MilestoneOwnerOrganization
+	// MilestoneOwnerUser is a milestone shared by the repositories of a user
+	MilestoneOwnerUser
+)
+
+// Milestone represents a milestone of repository, or a milestone shared by
+// the repositories of an organization or user, which has no RepoID.
 type Milestone struct {
-	ID              int64       `xorm:"pk autoincr"`
-	RepoID          int64       `xorm:"INDEX"`
-	Repo            *Repository `xorm:"-"`
+	ID              int64              `xorm:"pk autoincr"`
+	RepoID          int64              `xorm:"INDEX"`
+	Repo            *Repository        `xorm:"-"`
+	OwnerID         int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	OwnerType       MilestoneOwnerType `xorm:"NOT NULL DEFAULT 0"`
 	Name            string
 	Content         string `xorm:"TEXT"`
 	RenderedContent string `xorm:"-"`
@@ -75,6 +90,11 @@ func (m *Milestone) AfterLoad() {
 	}
 }
 
+// IsShared returns whether the milestone is shared by the repositories of its owner.
+func (m *Milestone) IsShared() bool {
+	return m.RepoID == 0 && m.OwnerID > 0
+}
+
 // State returns string representation of milestone status.
 func (m *Milestone) State() api.StateType {
 	if m.IsClosed {
@@ -83,7 +103,7 @@ func (m *Milestone) State() api.StateType {
 	return api.StateOpen
 }
 
-// NewMilestone creates new milestone of repository.
+// NewMilestone creates new milestone of repository, or a shared milestone if it has an OwnerID and no RepoID.
 func NewMilestone(m *Milestone) (err error) {
 	sess := db.DefaultContext().NewSession()
 	defer sess.Close()
@@ -97,8 +117,10 @@ func NewMilestone(m *Milestone) (err error) {
 		return err
 	}
 
-	if _, err = sess.Exec("UPDATE `repository` SET num_milestones = num_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
-		return err
+	if m.RepoID > 0 {
+		if _, err = sess.Exec("UPDATE `repository` SET num_milestones = num_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
+			return err
+		}
 	}
 	return sess.Commit()
 }
@@ -114,6 +136,62 @@ func getMilestoneByRepoID(e db.Engine, repoID, id int64) (*Milestone, error) {
 	return m, nil
 }
 
+// CloneMilestone creates an open copy of a milestone without issues in a repository. A deadline of the
+// milestone is moved by the whole days passed since the milestone was created, so the copy of a milestone
+// due two weeks after its creation is due two weeks after the copy was made.
+func CloneMilestone(m *Milestone, repoID int64) (*Milestone, error) {
+	clone := &Milestone{
+		RepoID:       repoID,
+		Name:         m.Name,
+		Content:      m.Content,
+		DeadlineUnix: m.DeadlineUnix,
+	}
+	if m.DeadlineUnix > 0 && m.DeadlineUnix.Year() < 9999 {
+		const day = 24 * 60 * 60
+		clone.DeadlineUnix += (timeutil.TimeStampNow() - m.CreatedUnix) / day * day
+	}
+	if err := NewMilestone(clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// getMilestoneForRepo returns a milestone of the repository or shared by the owner of the repository
+func getMilestoneForRepo(e db.Engine, repo *Repository, id int64) (*Milestone, error) {
+	m := new(Milestone)
+	has, err := e.ID(id).Where(builder.Eq{"repo_id": repo.ID}.Or(builder.Eq{"repo_id": 0, "owner_id": repo.OwnerID})).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMilestoneNotExist{ID: id, RepoID: repo.ID}
+	}
+	return m, nil
+}
+
+// GetMilestoneByOwnerID returns a milestone shared by the repositories of an organization or user.
+func GetMilestoneByOwnerID(ownerID, id int64) (*Milestone, error) {
+	m := new(Milestone)
+	has, err := db.DefaultContext().Engine().ID(id).Where("repo_id=0 AND owner_id=?", ownerID).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMilestoneNotExist{ID: id}
+	}
+	return m, nil
+}
+
+// GetMilestoneByOwnerIDAndName returns a milestone shared by the repositories of an organization or user by its name.
+func GetMilestoneByOwnerIDAndName(ownerID int64, name string) (*Milestone, error) {
+	m := new(Milestone)
+	has, err := db.DefaultContext().Engine().Where("repo_id=0 AND owner_id=? AND name=?", ownerID, name).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMilestoneNotExist{Name: name}
+	}
+	return m, nil
+}
+
 // GetMilestoneByRepoID returns the milestone in a repository.
 func GetMilestoneByRepoID(repoID, id int64) (*Milestone, error) {
 	return getMilestoneByRepoID(db.DefaultContext().Engine(), repoID, id)
@@ -165,7 +243,7 @@ func UpdateMilestone(m *Milestone, oldIsClosed bool) error {
 	}
 
 	// if IsClosed changed, update milestone numbers of repository
-	if oldIsClosed != m.IsClosed {
+	if oldIsClosed != m.IsClosed && m.RepoID > 0 {
 		if err := updateRepoMilestoneNum(sess, m.RepoID); err != nil {
 			return err
 		}
@@ -257,7 +335,7 @@ func changeMilestoneStatus(e db.Engine, m *Milestone, isClosed bool) error {
 	if err != nil {
 		return err
 	}
-	if count < 1 {
+	if count < 1 || m.RepoID == 0 {
 		return nil
 	}
 	return updateRepoMilestoneNum(e, m.RepoID)
@@ -319,6 +397,55 @@ func ChangeMilestoneAssign(issue *Issue, doer *User, oldMilestoneID int64) (err
 	return nil
 }
 
+// MoveMilestoneIssues moves the open issues of a milestone to another milestone and returns the moved issues.
+func MoveMilestoneIssues(doer *User, from, to *Milestone) (IssueList, error) {
+	sess := db.DefaultContext().NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	issues := make(IssueList, 0, from.NumOpenIssues)
+	if err := sess.Where("milestone_id = ? AND is_closed = ?", from.ID, false).Asc("id").Find(&issues); err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return issues, nil
+	}
+
+	for _, issue := range issues {
+		issue.MilestoneID = to.ID
+		if err := updateIssueCols(sess, issue, "milestone_id"); err != nil {
+			return nil, err
+		}
+		if err := issue.loadRepo(sess); err != nil {
+			return nil, err
+		}
+		if _, err := createComment(sess, &CreateCommentOptions{
+			Type:           CommentTypeMilestone,
+			Doer:           doer,
+			Repo:           issue.Repo,
+			Issue:          issue,
+			OldMilestoneID: from.ID,
+			MilestoneID:    to.ID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := updateMilestoneCounters(sess, from.ID); err != nil {
+		return nil, err
+	}
+	if err := updateMilestoneCounters(sess, to.ID); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Commit(); err != nil {
+		return nil, fmt.Errorf("Commit: %v", err)
+	}
+	return issues, nil
+}
+
 // DeleteMilestoneByRepoID deletes a milestone from a repository.
 func DeleteMilestoneByRepoID(repoID, id int64) error {
 	m, err := GetMilestoneByRepoID(repoID, id)
@@ -365,6 +492,33 @@ func DeleteMilestoneByRepoID(repoID, id int64) error {
 	return sess.Commit()
 }
 
+// DeleteMilestoneByOwnerID deletes a milestone shared by the repositories of an organization or user,
+// and removes it from the issues of all these repositories.
+func DeleteMilestoneByOwnerID(ownerID, id int64) error {
+	m, err := GetMilestoneByOwnerID(ownerID, id)
+	if err != nil {
+		if IsErrMilestoneNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	sess := db.DefaultContext().NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err = sess.ID(m.ID).Delete(new(Milestone)); err != nil {
+		return err
+	}
+
+	if _, err = sess.Exec("UPDATE `issue` SET milestone_id = 0 WHERE milestone_id = ?", m.ID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
 // MilestoneList is a list of milestones offering additional functionality
 type MilestoneList []*Milestone
 
@@ -379,7 +533,10 @@ func (milestones MilestoneList) getMilestoneIDs() []int64 {
 // GetMilestonesOption contain options to get milestones
 type GetMilestonesOption struct {
 	ListOptions
-	RepoID   int64
+	RepoID int64
+	// OwnerID includes the milestones shared by the repositories of the owner,
+	// only these are returned if there is no RepoID
+	OwnerID  int64
 	State    api.StateType
 	Name     string
 	SortType string
@@ -387,7 +544,14 @@ type GetMilestonesOption struct {
 
 func (opts GetMilestonesOption) toCond() builder.Cond {
 	cond := builder.NewCond()
-	if opts.RepoID != 0 {
+	if opts.OwnerID != 0 {
+		ownerCond := builder.Eq{"repo_id": 0, "owner_id": opts.OwnerID}
+		if opts.RepoID != 0 {
+			cond = cond.And(builder.Eq{"repo_id": opts.RepoID}.Or(ownerCond))
+		} else {
+			cond = cond.And(ownerCond)
+		}
+	} else if opts.RepoID != 0 {
 		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
 	}
 
@@ -0,0 +1,162 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MilestoneBurndownDay is the state of the issues of a milestone at the end of a day
+type MilestoneBurndownDay struct {
+	Date         time.Time
+	OpenIssues   int
+	ClosedIssues int
+	// TrackedTime is the time in seconds tracked on the issues of the milestone until the end of the day
+	TrackedTime int64
+}
+
+// burndownEvent is a change of whether an issue is in the milestone or closed
+type burndownEvent struct {
+	Unix  timeutil.TimeStamp
+	State bool
+}
+
+// burndownIssue is the history of an issue in a milestone
+type burndownIssue struct {
+	CreatedUnix timeutil.TimeStamp
+	// InMilestone is whether the issue was in the milestone when it was created, Membership the later changes
+	InMilestone bool
+	Membership  []burndownEvent
+	Closing     []burndownEvent
+	// TrackedTimes are the times tracked on the issue by the time they were tracked at
+	TrackedTimes []*TrackedTime
+}
+
+// stateAt returns the state after the last of the events up to a time
+func stateAt(initial bool, events []burndownEvent, unix timeutil.TimeStamp) bool {
+	state := initial
+	for _, event := range events {
+		if event.Unix > unix {
+			break
+		}
+		state = event.State
+	}
+	return state
+}
+
+// GetMilestoneBurndown returns the state of the issues of a milestone at the end of every day in the location,
+// from the day the milestone was created to its deadline, or to today if it has no deadline or the deadline
+// has not passed yet. The past states are computed from the milestone, close and reopen comments of the issues.
+func GetMilestoneBurndown(m *Milestone, loc *time.Location) ([]*MilestoneBurndownDay, error) {
+	e := db.DefaultContext().Engine()
+
+	issueIDs := make([]int64, 0, m.NumIssues)
+	if err := e.Table("issue").Where("milestone_id = ?", m.ID).Cols("id").Find(&issueIDs); err != nil {
+		return nil, err
+	}
+	// issues which left the milestone are part of its past
+	formerIDs := make([]int64, 0, 10)
+	if err := e.Table("comment").
+		Where("type = ? AND old_milestone_id = ?", CommentTypeMilestone, m.ID).
+		Distinct("issue_id").Find(&formerIDs); err != nil {
+		return nil, err
+	}
+	issueIDs = append(issueIDs, formerIDs...)
+
+	issues := make(map[int64]*Issue, len(issueIDs))
+	if len(issueIDs) > 0 {
+		if err := e.In("id", issueIDs).Find(&issues); err != nil {
+			return nil, err
+		}
+	}
+	history := make(map[int64]*burndownIssue, len(issues))
+	for id, issue := range issues {
+		history[id] = &burndownIssue{
+			CreatedUnix: issue.CreatedUnix,
+			InMilestone: issue.MilestoneID == m.ID,
+		}
+	}
+
+	if len(issues) > 0 {
+		comments := make([]*Comment, 0, len(issues))
+		if err := e.In("issue_id", issueIDs).
+			In("type", CommentTypeMilestone, CommentTypeClose, CommentTypeReopen, CommentTypeMergePull).
+			Asc("created_unix", "id").
+			Find(&comments); err != nil {
+			return nil, err
+		}
+		hasMembershipComment := make(map[int64]bool, len(issues))
+		for _, comment := range comments {
+			h := history[comment.IssueID]
+			switch comment.Type {
+			case CommentTypeMilestone:
+				if comment.MilestoneID != m.ID && comment.OldMilestoneID != m.ID {
+					continue
+				}
+				if !hasMembershipComment[comment.IssueID] {
+					// the first change tells whether the issue was in the milestone before
+					h.InMilestone = comment.OldMilestoneID == m.ID
+					hasMembershipComment[comment.IssueID] = true
+				}
+				h.Membership = append(h.Membership, burndownEvent{Unix: comment.CreatedUnix, State: comment.MilestoneID == m.ID})
+			case CommentTypeClose, CommentTypeMergePull:
+				h.Closing = append(h.Closing, burndownEvent{Unix: comment.CreatedUnix, State: true})
+			case CommentTypeReopen:
+				h.Closing = append(h.Closing, burndownEvent{Unix: comment.CreatedUnix, State: false})
+			}
+		}
+		for id, h := range history {
+			// issues closed without a comment, e.g. migrated ones, were closed when they say
+			if len(h.Closing) == 0 && issues[id].IsClosed {
+				h.Closing = append(h.Closing, burndownEvent{Unix: issues[id].ClosedUnix, State: true})
+			}
+		}
+
+		trackedTimes := make([]*TrackedTime, 0, 10)
+		if err := e.In("issue_id", issueIDs).And("deleted = ?", false).Find(&trackedTimes); err != nil {
+			return nil, err
+		}
+		for _, t := range trackedTimes {
+			history[t.IssueID].TrackedTimes = append(history[t.IssueID].TrackedTimes, t)
+		}
+	}
+
+	end := time.Now().In(loc)
+	if m.DeadlineUnix > 0 && m.DeadlineUnix.Year() < 9999 && m.DeadlineUnix.AsTime().Before(end) {
+		end = m.DeadlineUnix.AsTime().In(loc)
+	}
+	return computeMilestoneBurndown(history, m.CreatedUnix.AsTime().In(loc), end), nil
+}
+
+// computeMilestoneBurndown returns the state of the issues at the end of every day from the day of start
+// to the day of end
+func computeMilestoneBurndown(history map[int64]*burndownIssue, start, end time.Time) []*MilestoneBurndownDay {
+	loc := start.Location()
+	days := make([]*MilestoneBurndownDay, 0, int(end.Sub(start).Hours()/24)+2)
+	for date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc); !date.After(end); date = date.AddDate(0, 0, 1) {
+		endOfDay := timeutil.TimeStamp(date.AddDate(0, 0, 1).Unix() - 1)
+		day := &MilestoneBurndownDay{Date: date}
+		for _, h := range history {
+			if h.CreatedUnix > endOfDay || !stateAt(h.InMilestone, h.Membership, endOfDay) {
+				continue
+			}
+			if stateAt(false, h.Closing, endOfDay) {
+				day.ClosedIssues++
+			} else {
+				day.OpenIssues++
+			}
+			for _, t := range h.TrackedTimes {
+				if timeutil.TimeStamp(t.CreatedUnix) <= endOfDay {
+					day.TrackedTime += t.Time
+				}
+			}
+		}
+		days = append(days, day)
+	}
+	return days
+}
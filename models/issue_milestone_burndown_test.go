@@ -0,0 +1,61 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeMilestoneBurndown(t *testing.T) {
+	start := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	day := func(n int, hour int) timeutil.TimeStamp {
+		return timeutil.TimeStamp(time.Date(2021, 3, 1+n, hour, 0, 0, 0, time.UTC).Unix())
+	}
+
+	history := map[int64]*burndownIssue{
+		// in the milestone from the start, closed on the second day
+		1: {
+			CreatedUnix: day(0, 9),
+			InMilestone: true,
+			Closing:     []burndownEvent{{Unix: day(1, 12), State: true}},
+			TrackedTimes: []*TrackedTime{
+				{CreatedUnix: int64(day(0, 11)), Time: 3600},
+				{CreatedUnix: int64(day(1, 11)), Time: 1800},
+			},
+		},
+		// added on the second day, reopened on the third day
+		2: {
+			CreatedUnix: day(0, 9),
+			Membership:  []burndownEvent{{Unix: day(1, 8), State: true}},
+			Closing:     []burndownEvent{{Unix: day(1, 9), State: true}, {Unix: day(2, 9), State: false}},
+		},
+		// created in the milestone on the second day and moved out on the third day
+		3: {
+			CreatedUnix: day(1, 15),
+			Membership:  []burndownEvent{{Unix: day(1, 15), State: true}, {Unix: day(2, 15), State: false}},
+		},
+	}
+
+	days := computeMilestoneBurndown(history, start, start.AddDate(0, 0, 2))
+	if assert.Len(t, days, 3) {
+		assert.Equal(t, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), days[0].Date)
+		assert.Equal(t, 1, days[0].OpenIssues)
+		assert.Equal(t, 0, days[0].ClosedIssues)
+		assert.EqualValues(t, 3600, days[0].TrackedTime)
+
+		assert.Equal(t, 1, days[1].OpenIssues)
+		assert.Equal(t, 2, days[1].ClosedIssues)
+		assert.EqualValues(t, 5400, days[1].TrackedTime)
+
+		assert.Equal(t, 1, days[2].OpenIssues)
+		assert.Equal(t, 1, days[2].ClosedIssues)
+		assert.EqualValues(t, 5400, days[2].TrackedTime)
+	}
+}
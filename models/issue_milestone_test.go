@@ -35,6 +35,37 @@ func TestNewMilestone(t *testing.T) {
 	CheckConsistencyFor(t, &Repository{ID: milestone.RepoID}, &Milestone{})
 }
 
+func TestCloneMilestone(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	const day = 24 * 60 * 60
+	now := timeutil.TimeStampNow()
+	milestone := &Milestone{
+		RepoID:       1,
+		Name:         "sprint",
+		Content:      "two weeks",
+		IsClosed:     true,
+		CreatedUnix:  now - 30*day,
+		DeadlineUnix: now - 16*day,
+	}
+
+	clone, err := CloneMilestone(milestone, 2)
+	assert.NoError(t, err)
+	clone = db.AssertExistsAndLoadBean(t, &Milestone{ID: clone.ID}).(*Milestone)
+	assert.EqualValues(t, 2, clone.RepoID)
+	assert.Equal(t, "sprint", clone.Name)
+	assert.Equal(t, "two weeks", clone.Content)
+	assert.False(t, clone.IsClosed)
+	assert.EqualValues(t, 0, clone.NumIssues)
+	assert.Equal(t, now+14*day, clone.DeadlineUnix)
+	CheckConsistencyFor(t, &Repository{ID: 2}, &Milestone{})
+
+	// milestones without deadline are cloned without deadline
+	milestone = db.AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+	clone, err = CloneMilestone(milestone, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, milestone.DeadlineUnix, clone.DeadlineUnix)
+}
+
 func TestGetMilestoneByRepoID(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -255,6 +286,40 @@ func TestChangeMilestoneAssign(t *testing.T) {
 	CheckConsistencyFor(t, &Milestone{}, &Issue{})
 }
 
+func TestMoveMilestoneIssues(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	from := db.AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+	to := db.AssertExistsAndLoadBean(t, &Milestone{ID: 2}).(*Milestone)
+
+	issues, err := MoveMilestoneIssues(doer, from, to)
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 2, issues[0].ID)
+	}
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 2, MilestoneID: 2})
+	db.AssertExistsAndLoadBean(t, &Comment{
+		IssueID:        2,
+		Type:           CommentTypeMilestone,
+		MilestoneID:    2,
+		OldMilestoneID: 1,
+	})
+	from = db.AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+	assert.EqualValues(t, 0, from.NumIssues)
+	to = db.AssertExistsAndLoadBean(t, &Milestone{ID: 2}).(*Milestone)
+	assert.EqualValues(t, 1, to.NumIssues)
+	CheckConsistencyFor(t, &Milestone{}, &Issue{})
+
+	// closed issues stay in their milestone
+	_, err = db.DefaultContext().Engine().ID(3).Cols("is_closed").Update(&Issue{IsClosed: true})
+	assert.NoError(t, err)
+	from = db.AssertExistsAndLoadBean(t, &Milestone{ID: 3}).(*Milestone)
+	issues, err = MoveMilestoneIssues(doer, from, to)
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 3, MilestoneID: 3})
+}
+
 func TestDeleteMilestoneByRepoID(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	assert.NoError(t, DeleteMilestoneByRepoID(1, 1))
@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// MaxPinnedIssues is the maximum number of issues pinned in a repository
+const MaxPinnedIssues = 3
+
+var (
+	// ErrTooManyPinnedIssues indicates that more than MaxPinnedIssues issues are pinned
+	ErrTooManyPinnedIssues = fmt.Errorf("At most %d issues can be pinned", MaxPinnedIssues)
+	// ErrPinnedIssueIsPull indicates that a pull request was to be pinned
+	ErrPinnedIssueIsPull = errors.New("Pull requests cannot be pinned")
+)
+
+// PinnedIssue represents an issue pinned at the top of the issue list of a repository
+type PinnedIssue struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	IssueID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	// Position is the place of the issue in the pinned issues, starting at 0
+	Position int `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(PinnedIssue))
+}
+
+// GetPinnedIssues returns the issues pinned in a repository in their order
+func GetPinnedIssues(repoID int64) (IssueList, error) {
+	e := db.DefaultContext().Engine()
+	issues := make(IssueList, 0, MaxPinnedIssues)
+	if err := e.Join("INNER", "pinned_issue", "pinned_issue.issue_id = issue.id").
+		Where("pinned_issue.repo_id = ?", repoID).
+		Asc("pinned_issue.position").
+		Find(&issues); err != nil {
+		return nil, err
+	}
+	return issues, issues.loadAttributes(e)
+}
+
+// SetPinnedIssues replaces the issues pinned in a repository, keeping the given order.
+// Repeated issues are pinned once.
+func SetPinnedIssues(repoID int64, issues []*Issue) error {
+	seen := make(map[int64]bool, len(issues))
+	pinned := make([]*PinnedIssue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.RepoID != repoID {
+			return ErrIssueNotExist{ID: issue.ID}
+		}
+		if issue.IsPull {
+			return ErrPinnedIssueIsPull
+		}
+		if seen[issue.ID] {
+			continue
+		}
+		seen[issue.ID] = true
+		pinned = append(pinned, &PinnedIssue{RepoID: repoID, IssueID: issue.ID, Position: len(pinned)})
+	}
+	if len(pinned) > MaxPinnedIssues {
+		return ErrTooManyPinnedIssues
+	}
+
+	return db.WithTx(func(ctx *db.Context) error {
+		if _, err := ctx.Engine().Delete(&PinnedIssue{RepoID: repoID}); err != nil {
+			return err
+		}
+		if len(pinned) == 0 {
+			return nil
+		}
+		_, err := ctx.Engine().Insert(pinned)
+		return err
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPinnedIssues(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	issue1 := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue5 := db.AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	pull := db.AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+
+	assert.NoError(t, SetPinnedIssues(1, []*Issue{issue5, issue1}))
+	issues, err := GetPinnedIssues(1)
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 5, issues[0].ID)
+		assert.EqualValues(t, 1, issues[1].ID)
+	}
+
+	assert.Equal(t, ErrPinnedIssueIsPull, SetPinnedIssues(1, []*Issue{pull}))
+	assert.NoError(t, SetPinnedIssues(1, []*Issue{issue1, issue5, issue1, issue5}), "repeated issues are pinned once")
+	assert.Equal(t, ErrTooManyPinnedIssues, SetPinnedIssues(1, []*Issue{issue1, issue5, {ID: 100, RepoID: 1}, {ID: 101, RepoID: 1}}))
+}
@@ -0,0 +1,312 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssuePriority represents a priority level the issues of a repository can be given, e.g. "low" or "critical".
+// The level is stored in the priority column of an issue, the higher it is the more urgent is the issue.
+type IssuePriority struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RepoID      int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	Level       int    `xorm:"UNIQUE(s) NOT NULL"`
+	Name        string `xorm:"NOT NULL"`
+	Description string
+	Color       string `xorm:"VARCHAR(7)"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func init() {
+	db.RegisterModel(new(IssuePriority))
+}
+
+// ErrIssuePriorityNotExist represents a "IssuePriorityNotExist" kind of error.
+type ErrIssuePriorityNotExist struct {
+	ID     int64
+	RepoID int64
+	Level  int
+}
+
+// IsErrIssuePriorityNotExist checks if an error is a ErrIssuePriorityNotExist.
+func IsErrIssuePriorityNotExist(err error) bool {
+	_, ok := err.(ErrIssuePriorityNotExist)
+	return ok
+}
+
+func (err ErrIssuePriorityNotExist) Error() string {
+	return fmt.Sprintf("issue priority does not exist [id: %d, repo_id: %d, level: %d]", err.ID, err.RepoID, err.Level)
+}
+
+// ErrIssuePriorityAlreadyExist represents a "IssuePriorityAlreadyExist" kind of error.
+type ErrIssuePriorityAlreadyExist struct {
+	Level int
+	Name  string
+}
+
+// IsErrIssuePriorityAlreadyExist checks if an error is a ErrIssuePriorityAlreadyExist.
+func IsErrIssuePriorityAlreadyExist(err error) bool {
+	_, ok := err.(ErrIssuePriorityAlreadyExist)
+	return ok
+}
+
+func (err ErrIssuePriorityAlreadyExist) Error() string {
+	return fmt.Sprintf("issue priority already exists [level: %d, name: %s]", err.Level, err.Name)
+}
+
+// ErrIssuePriorityInvalidLevel represents a "IssuePriorityInvalidLevel" kind of error.
+type ErrIssuePriorityInvalidLevel struct {
+	Level int
+}
+
+// IsErrIssuePriorityInvalidLevel checks if an error is a ErrIssuePriorityInvalidLevel.
+func IsErrIssuePriorityInvalidLevel(err error) bool {
+	_, ok := err.(ErrIssuePriorityInvalidLevel)
+	return ok
+}
+
+func (err ErrIssuePriorityInvalidLevel) Error() string {
+	return fmt.Sprintf("issue priority level must be positive [level: %d]", err.Level)
+}
+
+func isIssuePriorityTaken(e db.Engine, priority *IssuePriority) (bool, error) {
+	return e.Where("repo_id = ? AND id != ?", priority.RepoID, priority.ID).
+		And("(level = ? OR lower(name) = ?)", priority.Level, strings.ToLower(priority.Name)).
+		Exist(new(IssuePriority))
+}
+
+func checkIssuePriority(e db.Engine, priority *IssuePriority) error {
+	if priority.Level <= 0 {
+		return ErrIssuePriorityInvalidLevel{Level: priority.Level}
+	}
+	if taken, err := isIssuePriorityTaken(e, priority); err != nil {
+		return err
+	} else if taken {
+		return ErrIssuePriorityAlreadyExist{Level: priority.Level, Name: priority.Name}
+	}
+	return nil
+}
+
+// NewIssuePriority creates a new priority level of the issues of a repository
+func NewIssuePriority(priority *IssuePriority) error {
+	priority.Name = strings.TrimSpace(priority.Name)
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if err := checkIssuePriority(e, priority); err != nil {
+			return err
+		}
+		_, err := e.Insert(priority)
+		return err
+	})
+}
+
+// UpdateIssuePriority updates a priority level of the issues of a repository,
+// the issues of the old level are moved to the new one
+func UpdateIssuePriority(priority *IssuePriority) error {
+	priority.Name = strings.TrimSpace(priority.Name)
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		old, err := getIssuePriorityByRepoID(e, priority.RepoID, priority.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkIssuePriority(e, priority); err != nil {
+			return err
+		}
+		if old.Level != priority.Level {
+			if _, err := e.Where("repo_id = ? AND priority = ?", priority.RepoID, old.Level).
+				Cols("priority").
+				Update(&Issue{Priority: priority.Level}); err != nil {
+				return err
+			}
+		}
+		_, err = e.ID(priority.ID).Cols("level", "name", "description", "color").Update(priority)
+		return err
+	})
+}
+
+func getIssuePriorityByRepoID(e db.Engine, repoID, id int64) (*IssuePriority, error) {
+	priority := &IssuePriority{ID: id, RepoID: repoID}
+	has, err := e.Get(priority)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssuePriorityNotExist{ID: id, RepoID: repoID}
+	}
+	return priority, nil
+}
+
+// GetIssuePriorityByRepoID returns the priority level of the issues of a repository with the given ID
+func GetIssuePriorityByRepoID(repoID, id int64) (*IssuePriority, error) {
+	return getIssuePriorityByRepoID(db.DefaultContext().Engine(), repoID, id)
+}
+
+func getIssuePriorityByLevel(e db.Engine, repoID int64, level int) (*IssuePriority, error) {
+	priority := new(IssuePriority)
+	has, err := e.Where("repo_id = ? AND level = ?", repoID, level).Get(priority)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssuePriorityNotExist{RepoID: repoID, Level: level}
+	}
+	return priority, nil
+}
+
+// GetIssuePriorityByLevel returns the priority of the issues of a repository with the given level
+func GetIssuePriorityByLevel(repoID int64, level int) (*IssuePriority, error) {
+	return getIssuePriorityByLevel(db.DefaultContext().Engine(), repoID, level)
+}
+
+// GetIssuePrioritiesByRepoID returns the priority levels of the issues of a repository, most urgent first
+func GetIssuePrioritiesByRepoID(repoID int64) ([]*IssuePriority, error) {
+	priorities := make([]*IssuePriority, 0, 5)
+	return priorities, db.DefaultContext().Engine().
+		Where("repo_id = ?", repoID).
+		Desc("level").
+		Find(&priorities)
+}
+
+// DeleteIssuePriority deletes a priority level of the issues of a repository,
+// the issues of that level are left without priority
+func DeleteIssuePriority(repoID, id int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		priority, err := getIssuePriorityByRepoID(e, repoID, id)
+		if err != nil {
+			return err
+		}
+		if _, err := e.Where("repo_id = ? AND priority = ?", repoID, priority.Level).
+			Cols("priority").
+			Update(&Issue{Priority: 0}); err != nil {
+			return err
+		}
+		_, err = e.ID(id).Delete(new(IssuePriority))
+		return err
+	})
+}
+
+func (issue *Issue) loadPriorityLevel(e db.Engine) (err error) {
+	if issue.PriorityLevel == nil && issue.Priority > 0 {
+		issue.PriorityLevel, err = getIssuePriorityByLevel(e, issue.RepoID, issue.Priority)
+		if IsErrIssuePriorityNotExist(err) {
+			return nil
+		}
+	}
+	return err
+}
+
+// LoadPriorityLevel loads the priority level of an issue
+func (issue *Issue) LoadPriorityLevel() error {
+	return issue.loadPriorityLevel(db.DefaultContext().Engine())
+}
+
+// ChangeIssuePriority gives an issue another priority level of its repository, or none if level is 0
+func ChangeIssuePriority(issue *Issue, level int) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		var priority *IssuePriority
+		if level != 0 {
+			var err error
+			if priority, err = getIssuePriorityByLevel(e, issue.RepoID, level); err != nil {
+				return err
+			}
+		}
+
+		issue.Priority = level
+		issue.PriorityLevel = priority
+		return updateIssueCols(e, issue, "priority")
+	})
+}
+
+// IssueSLAReport summarizes how fast the issues of a priority level got their first response
+type IssueSLAReport struct {
+	// Priority is nil for the issues without priority
+	Priority       *IssuePriority
+	Level          int
+	IssueCount     int64
+	RespondedCount int64
+	// AverageFirstResponse and MedianFirstResponse are the seconds until the first response of the responded issues
+	AverageFirstResponse int64
+	MedianFirstResponse  int64
+}
+
+// GetIssueSLAReports returns the time-to-first-response of the issues of a repository created
+// between sinceUnix and beforeUnix (both ignored if 0), per priority level and most urgent first.
+// The first response to an issue is the first comment or review by someone other than its poster.
+func GetIssueSLAReports(repoID, sinceUnix, beforeUnix int64) ([]*IssueSLAReport, error) {
+	e := db.DefaultContext().Engine()
+	priorities, err := GetIssuePrioritiesByRepoID(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	type issueResponse struct {
+		Priority      int
+		CreatedUnix   timeutil.TimeStamp
+		FirstResponse timeutil.TimeStamp
+	}
+	sess := e.Table("issue").
+		Select("issue.priority AS priority, issue.created_unix AS created_unix, MIN(comment.created_unix) AS first_response").
+		Join("LEFT", "comment", "comment.issue_id = issue.id AND comment.poster_id != issue.poster_id AND comment.type IN (?, ?)",
+			CommentTypeComment, CommentTypeReview).
+		Where("issue.repo_id = ? AND issue.is_pull = ?", repoID, false)
+	if sinceUnix > 0 {
+		sess.And("issue.created_unix >= ?", sinceUnix)
+	}
+	if beforeUnix > 0 {
+		sess.And("issue.created_unix <= ?", beforeUnix)
+	}
+	responses := make([]*issueResponse, 0, 50)
+	if err := sess.GroupBy("issue.id, issue.priority, issue.created_unix").Find(&responses); err != nil {
+		return nil, err
+	}
+
+	reports := make([]*IssueSLAReport, 0, len(priorities)+1)
+	byLevel := make(map[int]*IssueSLAReport, len(priorities)+1)
+	for _, priority := range priorities {
+		report := &IssueSLAReport{Priority: priority, Level: priority.Level}
+		reports = append(reports, report)
+		byLevel[priority.Level] = report
+	}
+	// issues of a level which is no longer configured count as without priority
+	none := &IssueSLAReport{}
+	reports = append(reports, none)
+
+	durations := make(map[*IssueSLAReport][]int64, len(reports))
+	for _, response := range responses {
+		report, ok := byLevel[response.Priority]
+		if !ok {
+			report = none
+		}
+		report.IssueCount++
+		if response.FirstResponse > 0 {
+			report.RespondedCount++
+			durations[report] = append(durations[report], int64(response.FirstResponse-response.CreatedUnix))
+		}
+	}
+
+	for report, seconds := range durations {
+		sort.Slice(seconds, func(i, j int) bool { return seconds[i] < seconds[j] })
+		var sum int64
+		for _, s := range seconds {
+			sum += s
+		}
+		report.AverageFirstResponse = sum / int64(len(seconds))
+		if n := len(seconds); n%2 == 1 {
+			report.MedianFirstResponse = seconds[n/2]
+		} else {
+			report.MedianFirstResponse = (seconds[n/2-1] + seconds[n/2]) / 2
+		}
+	}
+	return reports, nil
+}
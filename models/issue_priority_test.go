@@ -0,0 +1,92 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIssuePrioritiesByRepoID(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	priorities, err := GetIssuePrioritiesByRepoID(1)
+	assert.NoError(t, err)
+	if assert.Len(t, priorities, 2) {
+		assert.Equal(t, "high", priorities[0].Name)
+		assert.Equal(t, "low", priorities[1].Name)
+	}
+
+	priorities, err = GetIssuePrioritiesByRepoID(2)
+	assert.NoError(t, err)
+	assert.Empty(t, priorities)
+}
+
+func TestNewIssuePriority(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	priority := &IssuePriority{RepoID: 1, Level: 2, Name: " medium "}
+	assert.NoError(t, NewIssuePriority(priority))
+	assert.Equal(t, "medium", priority.Name)
+	db.AssertExistsAndLoadBean(t, &IssuePriority{ID: priority.ID, RepoID: 1, Level: 2})
+
+	err := NewIssuePriority(&IssuePriority{RepoID: 1, Level: 3, Name: "urgent"})
+	assert.True(t, IsErrIssuePriorityAlreadyExist(err))
+	err = NewIssuePriority(&IssuePriority{RepoID: 1, Level: 4, Name: "High"})
+	assert.True(t, IsErrIssuePriorityAlreadyExist(err))
+	err = NewIssuePriority(&IssuePriority{RepoID: 1, Level: 0, Name: "none"})
+	assert.True(t, IsErrIssuePriorityInvalidLevel(err))
+}
+
+func TestUpdateAndDeleteIssuePriority(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.NoError(t, ChangeIssuePriority(issue, 3))
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 1, Priority: 3})
+
+	err := ChangeIssuePriority(issue, 2)
+	assert.True(t, IsErrIssuePriorityNotExist(err))
+
+	priority := db.AssertExistsAndLoadBean(t, &IssuePriority{ID: 2}).(*IssuePriority)
+	priority.Level = 5
+	assert.NoError(t, UpdateIssuePriority(priority))
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 1, Priority: 5})
+
+	assert.NoError(t, DeleteIssuePriority(1, 2))
+	db.AssertNotExistsBean(t, &IssuePriority{ID: 2})
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 1, Priority: 0})
+}
+
+func TestGetIssueSLAReports(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.NoError(t, ChangeIssuePriority(issue, 3))
+
+	reports, err := GetIssueSLAReports(1, 0, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, reports, 3) {
+		assert.Equal(t, int64(2), reports[0].Priority.ID)
+		assert.EqualValues(t, 1, reports[0].IssueCount)
+		assert.EqualValues(t, 1, reports[0].RespondedCount)
+		// the first comment by someone else than the poster was 11 seconds after the issue was created
+		assert.EqualValues(t, 11, reports[0].AverageFirstResponse)
+		assert.EqualValues(t, 11, reports[0].MedianFirstResponse)
+
+		assert.Equal(t, int64(1), reports[1].Priority.ID)
+		assert.EqualValues(t, 0, reports[1].IssueCount)
+		assert.Nil(t, reports[2].Priority)
+	}
+
+	reports, err = GetIssueSLAReports(1, 946684801, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, reports, 3) {
+		assert.EqualValues(t, 0, reports[0].IssueCount)
+	}
+}
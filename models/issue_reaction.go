@@ -158,6 +158,9 @@ func CreateReaction(opts *ReactionOptions) (*Reaction, error) {
 	if err != nil {
 		return reaction, err
 	}
+	if err := watchIssueOnReaction(sess, opts.Doer, opts.Issue.ID); err != nil {
+		return nil, err
+	}
 
 	if err := sess.Commit(); err != nil {
 		return nil, err
@@ -0,0 +1,92 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// IssueReadMarker records up to which comment a user has read an issue
+type IssueReadMarker struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UID         int64              `xorm:"UNIQUE(s) NOT NULL"`
+	IssueID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CommentID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueReadMarker))
+}
+
+// MarkIssueRead marks all comments of an issue as read by the user
+func MarkIssueRead(userID int64, issue *Issue) error {
+	var commentID int64
+	if _, err := db.DefaultContext().Engine().Table("comment").Select("COALESCE(MAX(id), 0)").
+		Where("issue_id = ?", issue.ID).Get(&commentID); err != nil {
+		return err
+	}
+	return SetIssueReadMarker(userID, issue, commentID)
+}
+
+// SetIssueReadMarker marks the comments of an issue up to the given one as read by the user,
+// the marker never moves back
+func SetIssueReadMarker(userID int64, issue *Issue, commentID int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		marker := &IssueReadMarker{UID: userID, IssueID: issue.ID}
+		has, err := e.Get(marker)
+		if err != nil {
+			return err
+		}
+		if !has {
+			marker.CommentID = commentID
+			_, err = e.Insert(marker)
+			return err
+		}
+		if marker.CommentID >= commentID {
+			return nil
+		}
+		marker.CommentID = commentID
+		_, err = e.ID(marker.ID).Cols("comment_id").Update(marker)
+		return err
+	})
+}
+
+// GetIssueUnreadCounts returns the numbers of comments the user has not read yet by issue ID,
+// all comments of the issues the user has never read are unread. Only the issues with unread
+// comments are returned.
+func GetIssueUnreadCounts(userID int64, issueIDs []int64) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(issueIDs))
+	for left := issueIDs; len(left) > 0; {
+		limit := defaultMaxInSize
+		if len(left) < limit {
+			limit = len(left)
+		}
+
+		rows := make([]*struct {
+			IssueID int64
+			Count   int64
+		}, 0, limit)
+		if err := db.DefaultContext().Engine().Table("comment").
+			Select("comment.issue_id, COUNT(comment.id) AS count").
+			Join("LEFT", "issue_read_marker", "issue_read_marker.issue_id = comment.issue_id AND issue_read_marker.uid = ?", userID).
+			Where(builder.In("comment.issue_id", left[:limit])).
+			And("comment.type = ?", CommentTypeComment).
+			And("(issue_read_marker.id IS NULL OR comment.id > issue_read_marker.comment_id)").
+			GroupBy("comment.issue_id").
+			Find(&rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			counts[row.IssueID] = row.Count
+		}
+		left = left[limit:]
+	}
+	return counts, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueReadMarkers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	// all comments of unread issues are unread, the code comments of issue 2 are not counted
+	counts, err := GetIssueUnreadCounts(1, []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 2}, counts)
+
+	assert.NoError(t, SetIssueReadMarker(1, issue, 2))
+	counts, err = GetIssueUnreadCounts(1, []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 1}, counts)
+
+	// the marker does not move back
+	assert.NoError(t, SetIssueReadMarker(1, issue, 1))
+	db.AssertExistsAndLoadBean(t, &IssueReadMarker{UID: 1, IssueID: 1, CommentID: 2})
+
+	assert.NoError(t, MarkIssueRead(1, issue))
+	counts, err = GetIssueUnreadCounts(1, []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+
+	// the markers are per user
+	counts, err = GetIssueUnreadCounts(2, []int64{1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 2}, counts)
+	assert.NoError(t, issue.ReadBy(2))
+	db.AssertExistsAndLoadBean(t, &IssueReadMarker{UID: 2, IssueID: 1, CommentID: 3})
+}
@@ -0,0 +1,296 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// IssueRelationType is the type of a relation between two issues
+type IssueRelationType int
+
+// The types of issue relations, the issue of a relation is the subject: it relates to, duplicates
+// or is caused by the related issue
+const (
+	IssueRelationTypeRelates IssueRelationType = iota + 1
+	IssueRelationTypeDuplicates
+	IssueRelationTypeCausedBy
+)
+
+var issueRelationTypeNames = map[IssueRelationType]string{
+	IssueRelationTypeRelates:    "relates",
+	IssueRelationTypeDuplicates: "duplicates",
+	IssueRelationTypeCausedBy:   "caused_by",
+}
+
+// issueRelationTypeInverseNames are the names of the relation types seen from the related issue
+var issueRelationTypeInverseNames = map[IssueRelationType]string{
+	IssueRelationTypeRelates:    "relates",
+	IssueRelationTypeDuplicates: "duplicated_by",
+	IssueRelationTypeCausedBy:   "causes",
+}
+
+// String returns the name of the relation type
+func (t IssueRelationType) String() string {
+	return issueRelationTypeNames[t]
+}
+
+// IssueRelationTypeFromString returns the relation type matching name
+func IssueRelationTypeFromString(name string) (IssueRelationType, bool) {
+	for t, n := range issueRelationTypeNames {
+		if n == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// IssueRelation represents a typed relation between two issues, unlike dependencies it does not block closing
+type IssueRelation struct {
+	ID             int64              `xorm:"pk autoincr"`
+	Type           IssueRelationType  `xorm:"UNIQUE(s) NOT NULL"`
+	IssueID        int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RelatedIssueID int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	PosterID       int64              `xorm:"NOT NULL"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueRelation))
+}
+
+// ErrIssueRelationExists represents a "IssueRelationExists" kind of error.
+type ErrIssueRelationExists struct {
+	Type           IssueRelationType
+	IssueID        int64
+	RelatedIssueID int64
+}
+
+// IsErrIssueRelationExists checks if an error is a ErrIssueRelationExists.
+func IsErrIssueRelationExists(err error) bool {
+	_, ok := err.(ErrIssueRelationExists)
+	return ok
+}
+
+func (err ErrIssueRelationExists) Error() string {
+	return fmt.Sprintf("issue relation does already exist [type: %s, issue id: %d, related issue id: %d]", err.Type, err.IssueID, err.RelatedIssueID)
+}
+
+// ErrIssueRelationNotExist represents a "IssueRelationNotExist" kind of error.
+type ErrIssueRelationNotExist struct {
+	ID int64
+}
+
+// IsErrIssueRelationNotExist checks if an error is a ErrIssueRelationNotExist.
+func IsErrIssueRelationNotExist(err error) bool {
+	_, ok := err.(ErrIssueRelationNotExist)
+	return ok
+}
+
+func (err ErrIssueRelationNotExist) Error() string {
+	return fmt.Sprintf("issue relation does not exist [id: %d]", err.ID)
+}
+
+// ErrInvalidIssueRelation represents a "InvalidIssueRelation" kind of error.
+type ErrInvalidIssueRelation struct {
+	Reason string
+}
+
+// IsErrInvalidIssueRelation checks if an error is a ErrInvalidIssueRelation.
+func IsErrInvalidIssueRelation(err error) bool {
+	_, ok := err.(ErrInvalidIssueRelation)
+	return ok
+}
+
+func (err ErrInvalidIssueRelation) Error() string {
+	return fmt.Sprintf("invalid issue relation: %s", err.Reason)
+}
+
+// IssueRelationInfo is a relation of an issue seen from the issue, with the other issue of the relation
+type IssueRelationInfo struct {
+	*IssueRelation
+	// Inverse is true if the issue is the related issue of the relation
+	Inverse bool
+	Issue   *Issue
+}
+
+// Name returns the name of the relation seen from the issue, e.g. "duplicated_by" for the original of a duplicate
+func (info *IssueRelationInfo) Name() string {
+	if info.Inverse {
+		return issueRelationTypeInverseNames[info.Type]
+	}
+	return info.Type.String()
+}
+
+// CreateIssueRelation creates a relation between two issues. For duplicates, the original issue gets a reference
+// to the duplicate.
+func CreateIssueRelation(doer *User, issue, related *Issue, typ IssueRelationType) (*IssueRelation, error) {
+	if issue.ID == related.ID {
+		return nil, ErrInvalidIssueRelation{Reason: "an issue cannot be related to itself"}
+	}
+	if _, ok := issueRelationTypeNames[typ]; !ok {
+		return nil, ErrInvalidIssueRelation{Reason: fmt.Sprintf("unknown relation type %d", typ)}
+	}
+
+	relation := &IssueRelation{
+		Type:           typ,
+		IssueID:        issue.ID,
+		RelatedIssueID: related.ID,
+		PosterID:       doer.ID,
+	}
+	return relation, db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+
+		// relations are checked in both directions: a relation is symmetric and issues cannot duplicate
+		// or cause each other
+		exists, err := e.Where("type = ?", typ).
+			And(builder.Or(
+				builder.Eq{"issue_id": issue.ID, "related_issue_id": related.ID},
+				builder.Eq{"issue_id": related.ID, "related_issue_id": issue.ID},
+			)).Exist(&IssueRelation{})
+		if err != nil {
+			return err
+		} else if exists {
+			return ErrIssueRelationExists{Type: typ, IssueID: issue.ID, RelatedIssueID: related.ID}
+		}
+
+		if _, err = e.Insert(relation); err != nil {
+			return err
+		}
+
+		if typ != IssueRelationTypeDuplicates {
+			return nil
+		}
+		if err = related.loadRepo(e); err != nil {
+			return err
+		}
+		_, err = createComment(e, &CreateCommentOptions{
+			Type:       CommentTypeIssueRef,
+			Doer:       doer,
+			Repo:       related.Repo,
+			Issue:      related,
+			RefRepoID:  issue.RepoID,
+			RefIssueID: issue.ID,
+			RefAction:  references.XRefActionNone,
+			RefIsPull:  issue.IsPull,
+		})
+		return err
+	})
+}
+
+// GetIssueRelationByID returns the relation of an issue by its ID, the issue may be either side of the relation
+func GetIssueRelationByID(issueID, id int64) (*IssueRelation, error) {
+	relation := new(IssueRelation)
+	has, err := db.DefaultContext().Engine().ID(id).
+		And(builder.Or(builder.Eq{"issue_id": issueID}, builder.Eq{"related_issue_id": issueID})).
+		Get(relation)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueRelationNotExist{ID: id}
+	}
+	return relation, nil
+}
+
+// DeleteIssueRelation deletes a relation between two issues
+func DeleteIssueRelation(relation *IssueRelation) error {
+	_, err := db.DefaultContext().Engine().ID(relation.ID).Delete(new(IssueRelation))
+	return err
+}
+
+// GetIssueRelations returns the relations of an issue
+func GetIssueRelations(issue *Issue) ([]*IssueRelationInfo, error) {
+	relations, err := IssueList{issue}.GetRelations()
+	if err != nil {
+		return nil, err
+	}
+	return relations[issue.ID], nil
+}
+
+// GetRelations returns a map of issue ID to the relations of the issue, the other issues of the relations are
+// loaded with their repositories
+func (issues IssueList) GetRelations() (map[int64][]*IssueRelationInfo, error) {
+	return issues.getRelations(db.DefaultContext().Engine())
+}
+
+func (issues IssueList) getRelations(e db.Engine) (map[int64][]*IssueRelationInfo, error) {
+	relationMap := make(map[int64][]*IssueRelationInfo, len(issues))
+	if len(issues) == 0 {
+		return relationMap, nil
+	}
+
+	inList := make(map[int64]bool, len(issues))
+	for _, issue := range issues {
+		inList[issue.ID] = true
+	}
+
+	relations := make([]*IssueRelation, 0, len(issues))
+	issueIDs := issues.getIssueIDs()
+	for left := len(issueIDs); left > 0; {
+		limit := defaultMaxInSize
+		if left < limit {
+			limit = left
+		}
+		if err := e.Where(builder.Or(
+			builder.In("issue_id", issueIDs[:limit]),
+			builder.In("related_issue_id", issueIDs[:limit]),
+		)).Asc("id").Find(&relations); err != nil {
+			return nil, err
+		}
+		left -= limit
+		issueIDs = issueIDs[limit:]
+	}
+
+	// a relation between two issues of different chunks is found twice
+	seen := make(map[int64]bool, len(relations))
+	otherIDs := make([]int64, 0, len(relations))
+	for _, relation := range relations {
+		if seen[relation.ID] {
+			continue
+		}
+		seen[relation.ID] = true
+		otherIDs = append(otherIDs, relation.IssueID, relation.RelatedIssueID)
+	}
+
+	others, err := getIssuesByIDs(e, otherIDs)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = IssueList(others).loadRepositories(e); err != nil {
+		return nil, err
+	}
+	issueByID := make(map[int64]*Issue, len(others))
+	for _, issue := range others {
+		issueByID[issue.ID] = issue
+	}
+
+	seen = make(map[int64]bool, len(relations))
+	for _, relation := range relations {
+		if seen[relation.ID] {
+			continue
+		}
+		seen[relation.ID] = true
+		if inList[relation.IssueID] && issueByID[relation.RelatedIssueID] != nil {
+			relationMap[relation.IssueID] = append(relationMap[relation.IssueID], &IssueRelationInfo{
+				IssueRelation: relation,
+				Issue:         issueByID[relation.RelatedIssueID],
+			})
+		}
+		if inList[relation.RelatedIssueID] && issueByID[relation.IssueID] != nil {
+			relationMap[relation.RelatedIssueID] = append(relationMap[relation.RelatedIssueID], &IssueRelationInfo{
+				IssueRelation: relation,
+				Inverse:       true,
+				Issue:         issueByID[relation.IssueID],
+			})
+		}
+	}
+	return relationMap, nil
+}
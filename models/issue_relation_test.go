@@ -0,0 +1,72 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueRelationTypeFromString(t *testing.T) {
+	for name, expected := range map[string]IssueRelationType{
+		"relates":    IssueRelationTypeRelates,
+		"duplicates": IssueRelationTypeDuplicates,
+		"caused_by":  IssueRelationTypeCausedBy,
+	} {
+		typ, ok := IssueRelationTypeFromString(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, typ, name)
+	}
+
+	_, ok := IssueRelationTypeFromString("duplicated_by")
+	assert.False(t, ok)
+}
+
+func TestCreateIssueRelation(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := db.AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+
+	_, err := CreateIssueRelation(doer, issue1, issue1, IssueRelationTypeRelates)
+	assert.True(t, IsErrInvalidIssueRelation(err))
+
+	_, err = CreateIssueRelation(doer, issue1, issue2, IssueRelationTypeRelates)
+	assert.NoError(t, err)
+	// relations are symmetric
+	_, err = CreateIssueRelation(doer, issue2, issue1, IssueRelationTypeRelates)
+	assert.True(t, IsErrIssueRelationExists(err))
+
+	relation, err := CreateIssueRelation(doer, issue2, issue1, IssueRelationTypeDuplicates)
+	assert.NoError(t, err)
+	// the original issue references its duplicate
+	db.AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeIssueRef, IssueID: issue1.ID, RefIssueID: issue2.ID})
+
+	relations, err := IssueList{issue1, issue2}.GetRelations()
+	assert.NoError(t, err)
+	if assert.Len(t, relations[issue1.ID], 2) {
+		assert.Equal(t, "relates", relations[issue1.ID][0].Name())
+		assert.Equal(t, issue2.ID, relations[issue1.ID][0].Issue.ID)
+		assert.Equal(t, "duplicated_by", relations[issue1.ID][1].Name())
+	}
+	if assert.Len(t, relations[issue2.ID], 2) {
+		assert.Equal(t, "relates", relations[issue2.ID][0].Name())
+		assert.Equal(t, "duplicates", relations[issue2.ID][1].Name())
+		assert.Equal(t, issue1.ID, relations[issue2.ID][1].Issue.ID)
+	}
+
+	// the relation can be found from both issues
+	_, err = GetIssueRelationByID(issue1.ID, relation.ID)
+	assert.NoError(t, err)
+	_, err = GetIssueRelationByID(3, relation.ID)
+	assert.True(t, IsErrIssueRelationNotExist(err))
+
+	assert.NoError(t, DeleteIssueRelation(relation))
+	db.AssertNotExistsBean(t, &IssueRelation{ID: relation.ID})
+}
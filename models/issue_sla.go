@@ -0,0 +1,305 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// IssueSLAPolicy is a service level agreement on the issues of a repository: the issues it applies to must get
+// their first response within FirstResponseHours of their creation. The first response to an issue is the
+// first comment or review by someone other than its poster.
+type IssueSLAPolicy struct {
+	ID     int64  `xorm:"pk autoincr"`
+	RepoID int64  `xorm:"INDEX NOT NULL"`
+	Name   string `xorm:"NOT NULL"`
+	// LabelID restricts the policy to the issues having this label, 0 applies it to all issues
+	LabelID            int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Label              *Label `xorm:"-"`
+	FirstResponseHours int    `xorm:"NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// IssueSLABreach records an issue which did not get its first response within the time of a policy,
+// an issue is flagged once per policy
+type IssueSLABreach struct {
+	ID          int64              `xorm:"pk autoincr"`
+	PolicyID    int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	IssueID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueSLAPolicy))
+	db.RegisterModel(new(IssueSLABreach))
+}
+
+// ErrIssueSLAPolicyNotExist represents a "IssueSLAPolicyNotExist" kind of error.
+type ErrIssueSLAPolicyNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrIssueSLAPolicyNotExist checks if an error is a ErrIssueSLAPolicyNotExist.
+func IsErrIssueSLAPolicyNotExist(err error) bool {
+	_, ok := err.(ErrIssueSLAPolicyNotExist)
+	return ok
+}
+
+func (err ErrIssueSLAPolicyNotExist) Error() string {
+	return fmt.Sprintf("issue SLA policy does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// ErrInvalidIssueSLAPolicy represents a "InvalidIssueSLAPolicy" kind of error.
+type ErrInvalidIssueSLAPolicy struct {
+	Reason string
+}
+
+// IsErrInvalidIssueSLAPolicy checks if an error is a ErrInvalidIssueSLAPolicy.
+func IsErrInvalidIssueSLAPolicy(err error) bool {
+	_, ok := err.(ErrInvalidIssueSLAPolicy)
+	return ok
+}
+
+func (err ErrInvalidIssueSLAPolicy) Error() string {
+	return fmt.Sprintf("invalid issue SLA policy: %s", err.Reason)
+}
+
+// FirstResponseSeconds returns the time the issues of the policy have for their first response
+func (policy *IssueSLAPolicy) FirstResponseSeconds() int64 {
+	return int64(policy.FirstResponseHours) * 3600
+}
+
+func (policy *IssueSLAPolicy) loadLabel(e db.Engine) (err error) {
+	if policy.Label == nil && policy.LabelID > 0 {
+		policy.Label, err = getLabelByID(e, policy.LabelID)
+		if IsErrLabelNotExist(err) {
+			return nil
+		}
+	}
+	return err
+}
+
+// LoadLabel loads the label the policy is restricted to, if any
+func (policy *IssueSLAPolicy) LoadLabel() error {
+	return policy.loadLabel(db.DefaultContext().Engine())
+}
+
+// checkIssueSLAPolicy checks a policy, its label must be one the issues of the repository can have
+func checkIssueSLAPolicy(e db.Engine, policy *IssueSLAPolicy) error {
+	policy.Name = strings.TrimSpace(policy.Name)
+	if policy.Name == "" {
+		return ErrInvalidIssueSLAPolicy{Reason: "the name cannot be empty"}
+	}
+	if policy.FirstResponseHours <= 0 {
+		return ErrInvalidIssueSLAPolicy{Reason: "the first response time must be positive"}
+	}
+	if policy.LabelID == 0 {
+		return nil
+	}
+
+	label, err := getLabelByID(e, policy.LabelID)
+	if err != nil {
+		if IsErrLabelNotExist(err) {
+			return ErrInvalidIssueSLAPolicy{Reason: fmt.Sprintf("label %d does not exist", policy.LabelID)}
+		}
+		return err
+	}
+	if label.RepoID != policy.RepoID {
+		repo, err := getRepositoryByID(e, policy.RepoID)
+		if err != nil {
+			return err
+		}
+		if label.OrgID == 0 || label.OrgID != repo.OwnerID {
+			return ErrInvalidIssueSLAPolicy{Reason: fmt.Sprintf("label %d does not exist", policy.LabelID)}
+		}
+	}
+	policy.Label = label
+	return nil
+}
+
+// NewIssueSLAPolicy creates a SLA policy on the issues of a repository
+func NewIssueSLAPolicy(policy *IssueSLAPolicy) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if err := checkIssueSLAPolicy(e, policy); err != nil {
+			return err
+		}
+		_, err := e.Insert(policy)
+		return err
+	})
+}
+
+// UpdateIssueSLAPolicy updates a SLA policy, the issues already flagged stay flagged
+func UpdateIssueSLAPolicy(policy *IssueSLAPolicy) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if err := checkIssueSLAPolicy(e, policy); err != nil {
+			return err
+		}
+		_, err := e.ID(policy.ID).Cols("name", "label_id", "first_response_hours").Update(policy)
+		return err
+	})
+}
+
+// GetIssueSLAPolicyByRepoID returns the SLA policy of the issues of a repository with the given ID
+func GetIssueSLAPolicyByRepoID(repoID, id int64) (*IssueSLAPolicy, error) {
+	policy := &IssueSLAPolicy{ID: id, RepoID: repoID}
+	has, err := db.DefaultContext().Engine().Get(policy)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueSLAPolicyNotExist{ID: id, RepoID: repoID}
+	}
+	return policy, nil
+}
+
+// GetIssueSLAPoliciesByRepoID returns the SLA policies of the issues of a repository
+func GetIssueSLAPoliciesByRepoID(repoID int64) ([]*IssueSLAPolicy, error) {
+	policies := make([]*IssueSLAPolicy, 0, 5)
+	return policies, db.DefaultContext().Engine().
+		Where("repo_id = ?", repoID).
+		Asc("id").
+		Find(&policies)
+}
+
+// GetAllIssueSLAPolicies returns the SLA policies of all repositories
+func GetAllIssueSLAPolicies() ([]*IssueSLAPolicy, error) {
+	policies := make([]*IssueSLAPolicy, 0, 10)
+	return policies, db.DefaultContext().Engine().Asc("id").Find(&policies)
+}
+
+// DeleteIssueSLAPolicy deletes a SLA policy with the breaches it recorded
+func DeleteIssueSLAPolicy(policy *IssueSLAPolicy) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Where("policy_id = ?", policy.ID).Delete(new(IssueSLABreach)); err != nil {
+			return err
+		}
+		_, err := e.ID(policy.ID).Delete(new(IssueSLAPolicy))
+		return err
+	})
+}
+
+// issueSLAPolicyCond returns the condition selecting the issues a policy applies to
+func issueSLAPolicyCond(policy *IssueSLAPolicy) builder.Cond {
+	cond := builder.Eq{"issue.repo_id": policy.RepoID, "issue.is_pull": false}
+	if policy.LabelID == 0 {
+		return cond
+	}
+	return cond.And(builder.In("issue.id",
+		builder.Select("issue_id").From("issue_label").Where(builder.Eq{"label_id": policy.LabelID})))
+}
+
+// FindIssuesBreachingSLAPolicy returns the open issues which did not get their first response within the time
+// of a policy at nowUnix and were not flagged yet
+func FindIssuesBreachingSLAPolicy(policy *IssueSLAPolicy, nowUnix int64) ([]*Issue, error) {
+	issues := make([]*Issue, 0, 10)
+	return issues, db.DefaultContext().Engine().
+		Where(issueSLAPolicyCond(policy)).
+		And("issue.is_closed = ?", false).
+		And("issue.created_unix <= ?", nowUnix-policy.FirstResponseSeconds()).
+		And("NOT EXISTS (SELECT 1 FROM comment WHERE comment.issue_id = issue.id AND comment.poster_id != issue.poster_id"+
+			" AND comment.type IN (?, ?) AND comment.created_unix <= issue.created_unix + ?)",
+			CommentTypeComment, CommentTypeReview, policy.FirstResponseSeconds()).
+		And(builder.NotIn("issue.id",
+			builder.Select("issue_id").From("issue_sla_breach").Where(builder.Eq{"policy_id": policy.ID}))).
+		Asc("issue.id").
+		Find(&issues)
+}
+
+// NewIssueSLABreach flags an issue as breaching a policy and posts a warning on the issue.
+// The warning is posted on behalf of the ghost user since no user caused it.
+func NewIssueSLABreach(policy *IssueSLAPolicy, issue *Issue) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Insert(&IssueSLABreach{PolicyID: policy.ID, IssueID: issue.ID}); err != nil {
+			return err
+		}
+		if err := issue.loadRepo(e); err != nil {
+			return err
+		}
+		_, err := createComment(e, &CreateCommentOptions{
+			Type:    CommentTypeSLABreach,
+			Doer:    NewGhostUser(),
+			Repo:    issue.Repo,
+			Issue:   issue,
+			Content: policy.Name,
+		})
+		return err
+	})
+}
+
+// IssueSLACompliance summarizes how well the issues a policy applies to met it
+type IssueSLACompliance struct {
+	Policy     *IssueSLAPolicy
+	IssueCount int64
+	// MetCount is the number of issues which got their first response in time
+	MetCount int64
+	// BreachedCount is the number of issues which got their first response late, or did not get one in time
+	BreachedCount int64
+	// PendingCount is the number of issues still waiting for their first response within the time of the policy
+	PendingCount int64
+}
+
+// GetIssueSLACompliances returns the compliance of the issues of a repository created between sinceUnix and
+// beforeUnix (both ignored if 0) with each of its policies, at nowUnix
+func GetIssueSLACompliances(repoID, sinceUnix, beforeUnix, nowUnix int64) ([]*IssueSLACompliance, error) {
+	e := db.DefaultContext().Engine()
+	policies, err := GetIssueSLAPoliciesByRepoID(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	type issueResponse struct {
+		CreatedUnix   timeutil.TimeStamp
+		FirstResponse timeutil.TimeStamp
+	}
+	compliances := make([]*IssueSLACompliance, 0, len(policies))
+	for _, policy := range policies {
+		if err := policy.loadLabel(e); err != nil {
+			return nil, err
+		}
+
+		sess := e.Table("issue").
+			Select("issue.created_unix AS created_unix, MIN(comment.created_unix) AS first_response").
+			Join("LEFT", "comment", "comment.issue_id = issue.id AND comment.poster_id != issue.poster_id AND comment.type IN (?, ?)",
+				CommentTypeComment, CommentTypeReview).
+			Where(issueSLAPolicyCond(policy))
+		if sinceUnix > 0 {
+			sess.And("issue.created_unix >= ?", sinceUnix)
+		}
+		if beforeUnix > 0 {
+			sess.And("issue.created_unix <= ?", beforeUnix)
+		}
+		responses := make([]*issueResponse, 0, 50)
+		if err := sess.GroupBy("issue.id, issue.created_unix").Find(&responses); err != nil {
+			return nil, err
+		}
+
+		compliance := &IssueSLACompliance{Policy: policy, IssueCount: int64(len(responses))}
+		for _, response := range responses {
+			deadline := int64(response.CreatedUnix) + policy.FirstResponseSeconds()
+			switch {
+			case response.FirstResponse > 0 && int64(response.FirstResponse) <= deadline:
+				compliance.MetCount++
+			case response.FirstResponse == 0 && nowUnix < deadline:
+				compliance.PendingCount++
+			default:
+				compliance.BreachedCount++
+			}
+		}
+		compliances = append(compliances, compliance)
+	}
+	return compliances, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIssueSLAPolicy(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.True(t, IsErrInvalidIssueSLAPolicy(NewIssueSLAPolicy(&IssueSLAPolicy{RepoID: 1, Name: "Support", FirstResponseHours: 0})))
+	assert.True(t, IsErrInvalidIssueSLAPolicy(NewIssueSLAPolicy(&IssueSLAPolicy{RepoID: 1, Name: "Support", LabelID: 999, FirstResponseHours: 24})))
+	// labels of other repositories and organizations cannot be used
+	assert.True(t, IsErrInvalidIssueSLAPolicy(NewIssueSLAPolicy(&IssueSLAPolicy{RepoID: 1, Name: "Support", LabelID: 5, FirstResponseHours: 24})))
+	assert.True(t, IsErrInvalidIssueSLAPolicy(NewIssueSLAPolicy(&IssueSLAPolicy{RepoID: 1, Name: "Support", LabelID: 3, FirstResponseHours: 24})))
+
+	policy := &IssueSLAPolicy{RepoID: 1, Name: "Support", LabelID: 1, FirstResponseHours: 24}
+	assert.NoError(t, NewIssueSLAPolicy(policy))
+	db.AssertExistsAndLoadBean(t, &IssueSLAPolicy{ID: policy.ID, RepoID: 1, LabelID: 1})
+
+	policy.FirstResponseHours = 48
+	assert.NoError(t, UpdateIssueSLAPolicy(policy))
+	db.AssertExistsAndLoadBean(t, &IssueSLAPolicy{ID: policy.ID, FirstResponseHours: 48})
+}
+
+func TestIssueSLABreaches(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	now := time.Now().Unix()
+
+	all := &IssueSLAPolicy{RepoID: 1, Name: "All", FirstResponseHours: 1}
+	assert.NoError(t, NewIssueSLAPolicy(all))
+
+	// issue 1 got a response in time, the closed issue 5 never got one and pull requests are ignored
+	compliances, err := GetIssueSLACompliances(1, 0, 0, now)
+	assert.NoError(t, err)
+	if assert.Len(t, compliances, 1) {
+		assert.EqualValues(t, 2, compliances[0].IssueCount)
+		assert.EqualValues(t, 1, compliances[0].MetCount)
+		assert.EqualValues(t, 1, compliances[0].BreachedCount)
+		assert.EqualValues(t, 0, compliances[0].PendingCount)
+	}
+	issues, err := FindIssuesBreachingSLAPolicy(all, now)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 0)
+
+	// without its responses, the open issue 1 breaches the policies applying to it
+	_, err = db.DefaultContext().Engine().Where("issue_id = ?", 1).Delete(new(Comment))
+	assert.NoError(t, err)
+	support := &IssueSLAPolicy{RepoID: 1, Name: "Support", LabelID: 1, FirstResponseHours: 1}
+	assert.NoError(t, NewIssueSLAPolicy(support))
+
+	issues, err = FindIssuesBreachingSLAPolicy(support, now)
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 1, issues[0].ID)
+		assert.NoError(t, NewIssueSLABreach(support, issues[0]))
+	}
+	db.AssertExistsAndLoadBean(t, &IssueSLABreach{PolicyID: support.ID, IssueID: 1})
+	db.AssertExistsAndLoadBean(t, &Comment{IssueID: 1, Type: CommentTypeSLABreach, Content: "Support"})
+
+	// the issue is flagged once per policy
+	issues, err = FindIssuesBreachingSLAPolicy(support, now)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 0)
+	issues, err = FindIssuesBreachingSLAPolicy(all, now)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 1)
+
+	// the issue was not due yet an hour after its creation
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	compliances, err = GetIssueSLACompliances(1, 0, 0, int64(issue.CreatedUnix)+60)
+	assert.NoError(t, err)
+	if assert.Len(t, compliances, 2) {
+		assert.EqualValues(t, 1, compliances[1].IssueCount)
+		assert.EqualValues(t, 1, compliances[1].PendingCount)
+	}
+
+	assert.NoError(t, DeleteIssueSLAPolicy(support))
+	db.AssertNotExistsBean(t, &IssueSLABreach{PolicyID: support.ID})
+}
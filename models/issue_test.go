@@ -178,6 +178,22 @@ func TestIssues(t *testing.T) {
 			},
 			[]int64{}, // issues with **both** label 1 and 2, none of these issues matches, TODO: add more tests
 		},
+		{
+			IssuesOptions{
+				RepoIDs:       []int64{1},
+				ParticipantID: 3,
+				SortType:      "oldest",
+			},
+			[]int64{1}, // commented on by the user
+		},
+		{
+			IssuesOptions{
+				RepoIDs:       []int64{1},
+				ParticipantID: 1,
+				SortType:      "oldest",
+			},
+			[]int64{1, 2, 3, 11},
+		},
 	} {
 		issues, err := Issues(&test.Opts)
 		assert.NoError(t, err)
@@ -189,6 +205,38 @@ func TestIssues(t *testing.T) {
 	}
 }
 
+func TestIssuesCursor(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	for _, sortType := range []string{"", "oldest", "recentupdate", "leastupdate"} {
+		opts := &IssuesOptions{RepoIDs: []int64{1, 3}, SortType: sortType}
+		assert.True(t, opts.SupportsCursor())
+		all, err := Issues(opts)
+		assert.NoError(t, err)
+
+		opts.PageSize = 2
+		paged := make([]*Issue, 0, len(all))
+		for {
+			issues, err := Issues(opts)
+			assert.NoError(t, err)
+			if len(issues) == 0 {
+				break
+			}
+			paged = append(paged, issues...)
+			opts.After = opts.CursorOf(issues[len(issues)-1])
+		}
+		if assert.Len(t, paged, len(all), sortType) {
+			for i := range all {
+				assert.EqualValues(t, all[i].ID, paged[i].ID, sortType)
+			}
+		}
+	}
+
+	assert.False(t, (&IssuesOptions{SortType: "mostcomment"}).SupportsCursor())
+	assert.False(t, (&IssuesOptions{SortType: "priorityrepo", PriorityRepoID: 1}).SupportsCursor())
+	assert.True(t, (&IssuesOptions{SortType: "priorityrepo"}).SupportsCursor())
+}
+
 func TestGetUserIssueStats(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	for _, test := range []struct {
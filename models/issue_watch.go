@@ -15,6 +15,8 @@ type IssueWatch struct {
 	UserID      int64              `xorm:"UNIQUE(watch) NOT NULL"`
 	IssueID     int64              `xorm:"UNIQUE(watch) NOT NULL"`
 	IsWatching  bool               `xorm:"NOT NULL"`
+	Level       SubscriptionLevel  `xorm:"NOT NULL DEFAULT 0"`
+	Events      SubscriptionEvents `xorm:"NOT NULL DEFAULT 0"`
 	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL"`
 }
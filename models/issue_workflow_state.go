@@ -0,0 +1,221 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueWorkflowState represents an intermediate state of the issues of a repository beyond open and closed,
+// e.g. "triaged", "in progress" or "blocked". An issue is in at most one workflow state,
+// independent of whether it is open or closed.
+type IssueWorkflowState struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RepoID      int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	Name        string `xorm:"UNIQUE(s) NOT NULL"`
+	Description string
+	Color       string `xorm:"VARCHAR(7)"`
+	// Sort is the position of the state in the workflow of the repository
+	Sort int `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueWorkflowState))
+}
+
+// ErrIssueWorkflowStateNotExist represents a "IssueWorkflowStateNotExist" kind of error.
+type ErrIssueWorkflowStateNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrIssueWorkflowStateNotExist checks if an error is a ErrIssueWorkflowStateNotExist.
+func IsErrIssueWorkflowStateNotExist(err error) bool {
+	_, ok := err.(ErrIssueWorkflowStateNotExist)
+	return ok
+}
+
+func (err ErrIssueWorkflowStateNotExist) Error() string {
+	return fmt.Sprintf("issue workflow state does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// ErrIssueWorkflowStateAlreadyExist represents a "IssueWorkflowStateAlreadyExist" kind of error.
+type ErrIssueWorkflowStateAlreadyExist struct {
+	Name string
+}
+
+// IsErrIssueWorkflowStateAlreadyExist checks if an error is a ErrIssueWorkflowStateAlreadyExist.
+func IsErrIssueWorkflowStateAlreadyExist(err error) bool {
+	_, ok := err.(ErrIssueWorkflowStateAlreadyExist)
+	return ok
+}
+
+func (err ErrIssueWorkflowStateAlreadyExist) Error() string {
+	return fmt.Sprintf("issue workflow state already exists [name: %s]", err.Name)
+}
+
+func isIssueWorkflowStateNameTaken(e db.Engine, repoID, id int64, name string) (bool, error) {
+	return e.Where("repo_id = ? AND id != ?", repoID, id).
+		And("lower(name) = ?", strings.ToLower(name)).
+		Exist(new(IssueWorkflowState))
+}
+
+// NewIssueWorkflowState creates a new workflow state of the issues of a repository
+func NewIssueWorkflowState(state *IssueWorkflowState) error {
+	state.Name = strings.TrimSpace(state.Name)
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if taken, err := isIssueWorkflowStateNameTaken(e, state.RepoID, 0, state.Name); err != nil {
+			return err
+		} else if taken {
+			return ErrIssueWorkflowStateAlreadyExist{Name: state.Name}
+		}
+		_, err := e.Insert(state)
+		return err
+	})
+}
+
+// UpdateIssueWorkflowState updates the name, description, color and position of a workflow state
+func UpdateIssueWorkflowState(state *IssueWorkflowState) error {
+	state.Name = strings.TrimSpace(state.Name)
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if taken, err := isIssueWorkflowStateNameTaken(e, state.RepoID, state.ID, state.Name); err != nil {
+			return err
+		} else if taken {
+			return ErrIssueWorkflowStateAlreadyExist{Name: state.Name}
+		}
+		_, err := e.ID(state.ID).Cols("name", "description", "color", "sort").Update(state)
+		return err
+	})
+}
+
+func getIssueWorkflowStateByRepoID(e db.Engine, repoID, id int64) (*IssueWorkflowState, error) {
+	state := &IssueWorkflowState{ID: id, RepoID: repoID}
+	has, err := e.Get(state)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueWorkflowStateNotExist{ID: id, RepoID: repoID}
+	}
+	return state, nil
+}
+
+// GetIssueWorkflowStateByRepoID returns the workflow state of the issues of a repository with the given ID
+func GetIssueWorkflowStateByRepoID(repoID, id int64) (*IssueWorkflowState, error) {
+	return getIssueWorkflowStateByRepoID(db.DefaultContext().Engine(), repoID, id)
+}
+
+// GetIssueWorkflowStatesByRepoID returns the workflow states of the issues of a repository in their order
+func GetIssueWorkflowStatesByRepoID(repoID int64) ([]*IssueWorkflowState, error) {
+	states := make([]*IssueWorkflowState, 0, 5)
+	return states, db.DefaultContext().Engine().
+		Where("repo_id = ?", repoID).
+		Asc("sort").
+		Asc("id").
+		Find(&states)
+}
+
+// DeleteIssueWorkflowState deletes a workflow state of the issues of a repository,
+// the issues in that state are left in no workflow state
+func DeleteIssueWorkflowState(repoID, id int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := getIssueWorkflowStateByRepoID(e, repoID, id); err != nil {
+			return err
+		}
+		if _, err := e.Where("repo_id = ? AND workflow_state_id = ?", repoID, id).
+			Cols("workflow_state_id").
+			Update(&Issue{WorkflowStateID: 0}); err != nil {
+			return err
+		}
+		_, err := e.ID(id).Delete(new(IssueWorkflowState))
+		return err
+	})
+}
+
+func (issue *Issue) loadWorkflowState(e db.Engine) (err error) {
+	if issue.WorkflowState == nil && issue.WorkflowStateID > 0 {
+		issue.WorkflowState, err = getIssueWorkflowStateByRepoID(e, issue.RepoID, issue.WorkflowStateID)
+		if IsErrIssueWorkflowStateNotExist(err) {
+			issue.WorkflowStateID = 0
+			return nil
+		}
+	}
+	return err
+}
+
+// LoadWorkflowState loads the workflow state of an issue
+func (issue *Issue) LoadWorkflowState() error {
+	return issue.loadWorkflowState(db.DefaultContext().Engine())
+}
+
+// ChangeIssueWorkflowState moves an issue into another workflow state of its repository, or none if stateID is 0,
+// and records the transition as comment
+func ChangeIssueWorkflowState(issue *Issue, doer *User, stateID int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if stateID > 0 {
+			if _, err := getIssueWorkflowStateByRepoID(e, issue.RepoID, stateID); err != nil {
+				return err
+			}
+		}
+
+		oldStateID := issue.WorkflowStateID
+		if oldStateID == stateID {
+			return nil
+		}
+		issue.WorkflowStateID = stateID
+		issue.WorkflowState = nil
+		if err := updateIssueCols(e, issue, "workflow_state_id"); err != nil {
+			return err
+		}
+
+		if err := issue.loadRepo(e); err != nil {
+			return err
+		}
+		_, err := createComment(e, &CreateCommentOptions{
+			Type:               CommentTypeWorkflowState,
+			Doer:               doer,
+			Repo:               issue.Repo,
+			Issue:              issue,
+			OldWorkflowStateID: oldStateID,
+			WorkflowStateID:    stateID,
+		})
+		return err
+	})
+}
+
+// LoadWorkflowState loads the workflow states a comment recorded the transition between
+func (c *Comment) LoadWorkflowState() error {
+	e := db.DefaultContext().Engine()
+	if c.OldWorkflowStateID > 0 {
+		var state IssueWorkflowState
+		has, err := e.ID(c.OldWorkflowStateID).Get(&state)
+		if err != nil {
+			return err
+		} else if has {
+			c.OldWorkflowState = &state
+		}
+	}
+
+	if c.WorkflowStateID > 0 {
+		var state IssueWorkflowState
+		has, err := e.ID(c.WorkflowStateID).Get(&state)
+		if err != nil {
+			return err
+		} else if has {
+			c.WorkflowState = &state
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIssueWorkflowStatesByRepoID(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	states, err := GetIssueWorkflowStatesByRepoID(1)
+	assert.NoError(t, err)
+	if assert.Len(t, states, 2) {
+		assert.Equal(t, "triaged", states[0].Name)
+		assert.Equal(t, "in progress", states[1].Name)
+	}
+
+	states, err = GetIssueWorkflowStatesByRepoID(2)
+	assert.NoError(t, err)
+	assert.Empty(t, states)
+}
+
+func TestNewIssueWorkflowState(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	state := &IssueWorkflowState{RepoID: 1, Name: " blocked ", Color: "#b60205", Sort: 2}
+	assert.NoError(t, NewIssueWorkflowState(state))
+	assert.Equal(t, "blocked", state.Name)
+	db.AssertExistsAndLoadBean(t, &IssueWorkflowState{ID: state.ID, RepoID: 1, Name: "blocked"})
+
+	err := NewIssueWorkflowState(&IssueWorkflowState{RepoID: 1, Name: "Triaged"})
+	assert.True(t, IsErrIssueWorkflowStateAlreadyExist(err))
+
+	assert.NoError(t, NewIssueWorkflowState(&IssueWorkflowState{RepoID: 2, Name: "triaged"}))
+}
+
+func TestChangeIssueWorkflowState(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	assert.NoError(t, ChangeIssueWorkflowState(issue, doer, 1))
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 1, WorkflowStateID: 1})
+	db.AssertExistsAndLoadBean(t, &Comment{IssueID: 1, Type: CommentTypeWorkflowState, OldWorkflowStateID: 0, WorkflowStateID: 1})
+
+	assert.NoError(t, ChangeIssueWorkflowState(issue, doer, 2))
+	db.AssertExistsAndLoadBean(t, &Comment{IssueID: 1, Type: CommentTypeWorkflowState, OldWorkflowStateID: 1, WorkflowStateID: 2})
+
+	// states of other repositories cannot be used
+	other := &IssueWorkflowState{RepoID: 2, Name: "triaged"}
+	assert.NoError(t, NewIssueWorkflowState(other))
+	err := ChangeIssueWorkflowState(issue, doer, other.ID)
+	assert.True(t, IsErrIssueWorkflowStateNotExist(err))
+
+	issues, err := Issues(&IssuesOptions{RepoIDs: []int64{1}, WorkflowStateID: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 1, issues[0].ID)
+	}
+
+	assert.NoError(t, DeleteIssueWorkflowState(1, 2))
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 1}, "workflow_state_id = 0")
+	db.AssertNotExistsBean(t, &IssueWorkflowState{ID: 2})
+}
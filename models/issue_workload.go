@@ -0,0 +1,175 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// AssigneeWorkload represents the open issues and pull requests assigned to a user
+type AssigneeWorkload struct {
+	Assignee   *User
+	OpenIssues int64
+	OpenPulls  int64
+	// Milestones breaks the workload down by the milestones of the issues and pull requests,
+	// the issues and pull requests without milestone are counted with a nil Milestone
+	Milestones []*MilestoneWorkload
+}
+
+// MilestoneWorkload represents the open issues and pull requests of a milestone assigned to a user
+type MilestoneWorkload struct {
+	Milestone  *Milestone
+	OpenIssues int64
+	OpenPulls  int64
+}
+
+func (w *MilestoneWorkload) total() int64 {
+	return w.OpenIssues + w.OpenPulls
+}
+
+func (w *AssigneeWorkload) total() int64 {
+	return w.OpenIssues + w.OpenPulls
+}
+
+type assigneeWorkloadCount struct {
+	AssigneeID  int64
+	MilestoneID int64
+	Count       int64
+}
+
+func countAssigneeWorkloads(e db.Engine, repoIDs []int64, isPull bool) ([]*assigneeWorkloadCount, error) {
+	counts := make([]*assigneeWorkloadCount, 0, 10)
+	for i := 0; i < len(repoIDs); i += maxQueryParameters {
+		chunk := i + maxQueryParameters
+		if chunk > len(repoIDs) {
+			chunk = len(repoIDs)
+		}
+		chunkCounts := make([]*assigneeWorkloadCount, 0, 10)
+		if err := e.Table("issue_assignees").
+			Select("issue_assignees.assignee_id AS assignee_id, issue.milestone_id AS milestone_id, COUNT(*) AS count").
+			Join("INNER", "issue", "issue.id = issue_assignees.issue_id").
+			Where("issue.is_closed = ? AND issue.is_pull = ?", false, isPull).
+			In("issue.repo_id", repoIDs[i:chunk]).
+			GroupBy("issue_assignees.assignee_id, issue.milestone_id").
+			Find(&chunkCounts); err != nil {
+			return nil, err
+		}
+		counts = append(counts, chunkCounts...)
+	}
+	return counts, nil
+}
+
+// GetAssigneeWorkloads returns the workload of every user who is assigned to open issues of the repositories
+// of issueRepoIDs or open pull requests of the repositories of pullRepoIDs, the most loaded user first
+func GetAssigneeWorkloads(issueRepoIDs, pullRepoIDs []int64) ([]*AssigneeWorkload, error) {
+	e := db.DefaultContext().Engine()
+	issueCounts, err := countAssigneeWorkloads(e, issueRepoIDs, false)
+	if err != nil {
+		return nil, err
+	}
+	pullCounts, err := countAssigneeWorkloads(e, pullRepoIDs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		assigneeID, milestoneID int64
+	}
+	milestoneWorkloads := make(map[key]*MilestoneWorkload)
+	workloads := make(map[int64]*AssigneeWorkload)
+	milestoneIDs := make([]int64, 0, 10)
+	add := func(count *assigneeWorkloadCount, isPull bool) {
+		workload, ok := workloads[count.AssigneeID]
+		if !ok {
+			workload = &AssigneeWorkload{}
+			workloads[count.AssigneeID] = workload
+		}
+		k := key{count.AssigneeID, count.MilestoneID}
+		milestoneWorkload, ok := milestoneWorkloads[k]
+		if !ok {
+			milestoneWorkload = &MilestoneWorkload{}
+			milestoneWorkloads[k] = milestoneWorkload
+			workload.Milestones = append(workload.Milestones, milestoneWorkload)
+			if count.MilestoneID > 0 {
+				milestoneIDs = append(milestoneIDs, count.MilestoneID)
+			}
+		}
+		if isPull {
+			workload.OpenPulls += count.Count
+			milestoneWorkload.OpenPulls += count.Count
+		} else {
+			workload.OpenIssues += count.Count
+			milestoneWorkload.OpenIssues += count.Count
+		}
+	}
+	for _, count := range issueCounts {
+		add(count, false)
+	}
+	for _, count := range pullCounts {
+		add(count, true)
+	}
+
+	assigneeIDs := make([]int64, 0, len(workloads))
+	for id := range workloads {
+		assigneeIDs = append(assigneeIDs, id)
+	}
+	users := make(map[int64]*User, len(assigneeIDs))
+	if len(assigneeIDs) > 0 {
+		if err := e.In("id", assigneeIDs).Find(&users); err != nil {
+			return nil, err
+		}
+	}
+	milestones := make(map[int64]*Milestone, len(milestoneIDs))
+	if len(milestoneIDs) > 0 {
+		if err := e.In("id", milestoneIDs).Find(&milestones); err != nil {
+			return nil, err
+		}
+	}
+	repoIDs := make([]int64, 0, len(milestones))
+	for _, milestone := range milestones {
+		repoIDs = append(repoIDs, milestone.RepoID)
+	}
+	repos := make(map[int64]*Repository, len(repoIDs))
+	if len(repoIDs) > 0 {
+		if err := e.In("id", repoIDs).Find(&repos); err != nil {
+			return nil, err
+		}
+	}
+	for _, milestone := range milestones {
+		milestone.Repo = repos[milestone.RepoID]
+	}
+	for k, milestoneWorkload := range milestoneWorkloads {
+		if k.milestoneID > 0 {
+			milestoneWorkload.Milestone = milestones[k.milestoneID]
+		}
+	}
+
+	result := make([]*AssigneeWorkload, 0, len(workloads))
+	for assigneeID, workload := range workloads {
+		// assignments of deleted users are left behind until the issue is touched
+		if workload.Assignee = users[assigneeID]; workload.Assignee == nil {
+			continue
+		}
+		sort.SliceStable(workload.Milestones, func(i, j int) bool {
+			mi, mj := workload.Milestones[i], workload.Milestones[j]
+			// the issues and pull requests without milestone go last
+			if (mi.Milestone == nil) != (mj.Milestone == nil) {
+				return mj.Milestone == nil
+			}
+			return mi.total() > mj.total()
+		})
+		result = append(result, workload)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].total() != result[j].total() {
+			return result[i].total() > result[j].total()
+		}
+		return strings.ToLower(result[i].Assignee.Name) < strings.ToLower(result[j].Assignee.Name)
+	})
+	return result, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAssigneeWorkloads(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	workloads, err := GetAssigneeWorkloads([]int64{1, 3}, []int64{1, 3})
+	assert.NoError(t, err)
+	if assert.Len(t, workloads, 2) {
+		assert.EqualValues(t, 1, workloads[0].Assignee.ID)
+		assert.EqualValues(t, 2, workloads[0].OpenIssues)
+		assert.EqualValues(t, 0, workloads[0].OpenPulls)
+		if assert.Len(t, workloads[0].Milestones, 1) {
+			assert.Nil(t, workloads[0].Milestones[0].Milestone)
+			assert.EqualValues(t, 2, workloads[0].Milestones[0].OpenIssues)
+		}
+		assert.EqualValues(t, 2, workloads[1].Assignee.ID)
+		assert.EqualValues(t, 1, workloads[1].OpenIssues)
+	}
+
+	// only the issues of the given repositories are counted
+	workloads, err = GetAssigneeWorkloads([]int64{3}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, workloads, 2) {
+		assert.EqualValues(t, 1, workloads[0].OpenIssues)
+		assert.EqualValues(t, 1, workloads[1].OpenIssues)
+	}
+
+	workloads, err = GetAssigneeWorkloads(nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, workloads)
+}
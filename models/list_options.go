@@ -8,6 +8,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/setting"
 
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -98,3 +99,26 @@ func (opts *AbsoluteListOptions) GetSkipTake() (skip, take int) {
 func (opts *AbsoluteListOptions) GetStartEnd() (start, end int) {
 	return opts.skip, opts.skip + opts.take
 }
+
+// Cursor is the position of the last item of a page of a list paginated by keyset, the next page starts after it.
+// Unlike an offset, the items of the previous pages do not have to be skipped and new items do not shift the pages.
+type Cursor struct {
+	// Value is the sort key of the last item, e.g. its creation time
+	Value int64
+	// ID breaks the ties between the items with the same sort key
+	ID int64
+}
+
+// afterCond returns the condition selecting the items after the cursor in a list sorted by column, then by idColumn
+func (c *Cursor) afterCond(column, idColumn string, desc bool) builder.Cond {
+	if desc {
+		return builder.Or(
+			builder.Lt{column: c.Value},
+			builder.Eq{column: c.Value}.And(builder.Lt{idColumn: c.ID}),
+		)
+	}
+	return builder.Or(
+		builder.Gt{column: c.Value},
+		builder.Eq{column: c.Value}.And(builder.Gt{idColumn: c.ID}),
+	)
+}
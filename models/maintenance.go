@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// maintenanceModeID is the id of the single row holding the maintenance mode of the instance
+const maintenanceModeID = 1
+
+// MaintenanceMode is the persisted maintenance mode state of the instance
+type MaintenanceMode struct {
+	ID        int64  `xorm:"pk"`
+	IsEnabled bool   `xorm:"NOT NULL DEFAULT false"`
+	Message   string `xorm:"TEXT"`
+	// RetryAfter is the delay in seconds clients are asked to wait before retrying a rejected write
+	RetryAfter  int64              `xorm:"NOT NULL DEFAULT 0"`
+	DoerID      int64              `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(MaintenanceMode))
+}
+
+// GetMaintenanceMode returns the persisted maintenance mode, which is disabled if it was never set
+func GetMaintenanceMode() (*MaintenanceMode, error) {
+	m := &MaintenanceMode{ID: maintenanceModeID}
+	has, err := db.DefaultContext().Engine().Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return &MaintenanceMode{ID: maintenanceModeID}, nil
+	}
+	return m, nil
+}
+
+// SetMaintenanceMode persists the maintenance mode
+func SetMaintenanceMode(m *MaintenanceMode) error {
+	m.ID = maintenanceModeID
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		has, err := e.Exist(&MaintenanceMode{ID: maintenanceModeID})
+		if err != nil {
+			return err
+		}
+		if !has {
+			_, err = e.Insert(m)
+			return err
+		}
+		_, err = e.ID(maintenanceModeID).AllCols().Update(m)
+		return err
+	})
+}
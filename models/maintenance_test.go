@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	m, err := GetMaintenanceMode()
+	assert.NoError(t, err)
+	assert.False(t, m.IsEnabled)
+
+	assert.NoError(t, SetMaintenanceMode(&MaintenanceMode{IsEnabled: true, Message: "upgrading", RetryAfter: 60, DoerID: 1}))
+	m, err = GetMaintenanceMode()
+	assert.NoError(t, err)
+	assert.True(t, m.IsEnabled)
+	assert.Equal(t, "upgrading", m.Message)
+	assert.EqualValues(t, 60, m.RetryAfter)
+
+	// disabling must clear the message of the previous maintenance
+	assert.NoError(t, SetMaintenanceMode(&MaintenanceMode{DoerID: 1}))
+	m, err = GetMaintenanceMode()
+	assert.NoError(t, err)
+	assert.False(t, m.IsEnabled)
+	assert.Empty(t, m.Message)
+}
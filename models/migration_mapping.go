@@ -0,0 +1,91 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MigrationMappingType is the kind of object of the migration source a mapping refers to
+type MigrationMappingType string
+
+const (
+	// MigrationMappingRepo marks the repository itself, it is updated every time the repository is synced
+	MigrationMappingRepo MigrationMappingType = "repo"
+	// MigrationMappingIssue maps the number of an issue or a pull request to the id of the local issue
+	MigrationMappingIssue MigrationMappingType = "issue"
+	// MigrationMappingComment maps the id of a comment to the id of the local comment
+	MigrationMappingComment MigrationMappingType = "comment"
+	// MigrationMappingRelease maps the tag name of a release to the id of the local release
+	MigrationMappingRelease MigrationMappingType = "release"
+)
+
+// MigrationMapping maps an object of the source of a migrated repository to the local object it was imported as,
+// so that a later sync of the repository only imports the objects which are new
+type MigrationMapping struct {
+	ID          int64                `xorm:"pk autoincr"`
+	RepoID      int64                `xorm:"UNIQUE(s) NOT NULL"`
+	Type        MigrationMappingType `xorm:"VARCHAR(16) UNIQUE(s) NOT NULL"`
+	ForeignID   string               `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	LocalID     int64                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp   `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp   `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(MigrationMapping))
+}
+
+// GetMigrationMappings returns the local ids of the objects of the given type imported into the repository
+// keyed by their foreign id
+func GetMigrationMappings(repoID int64, tp MigrationMappingType) (map[string]int64, error) {
+	mappings := make([]*MigrationMapping, 0, 10)
+	if err := db.DefaultContext().Engine().Where("repo_id = ? AND type = ?", repoID, tp).Find(&mappings); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]int64, len(mappings))
+	for _, mapping := range mappings {
+		ids[mapping.ForeignID] = mapping.LocalID
+	}
+	return ids, nil
+}
+
+// InsertMigrationMappings inserts mappings of imported objects
+func InsertMigrationMappings(mappings ...*MigrationMapping) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+	_, err := db.DefaultContext().Engine().Insert(mappings)
+	return err
+}
+
+// GetMigrationLastSync returns when the repository was last migrated or synced from its source,
+// it is zero if the repository was not migrated with sync tracking
+func GetMigrationLastSync(repoID int64) (timeutil.TimeStamp, error) {
+	mapping := &MigrationMapping{RepoID: repoID, Type: MigrationMappingRepo}
+	has, err := db.DefaultContext().Engine().Get(mapping)
+	if err != nil || !has {
+		return 0, err
+	}
+	return mapping.UpdatedUnix, nil
+}
+
+// UpdateMigrationLastSync records that the repository was just migrated or synced from its source
+func UpdateMigrationLastSync(repoID int64) error {
+	e := db.DefaultContext().Engine()
+	mapping := &MigrationMapping{RepoID: repoID, Type: MigrationMappingRepo}
+	has, err := e.Get(mapping)
+	if err != nil {
+		return err
+	}
+	if !has {
+		_, err = e.Insert(mapping)
+		return err
+	}
+	_, err = e.ID(mapping.ID).Cols("updated_unix").Update(mapping)
+	return err
+}
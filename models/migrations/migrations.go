@@ -342,6 +342,106 @@ var migrations = []Migration{
 	NewMigration("Add repo id column for attachment table", addRepoIDForAttachment),
 	// v194 -> v195
 	NewMigration("Add Branch Protection Unprotected Files Column", addBranchProtectionUnprotectedFilesColumn),
+	// v195 -> v196
+	NewMigration("Add package tables", addPackageTables),
+	// v196 -> v197
+	NewMigration("Add package blob upload table", addPackageBlobUploadTable),
+	// v197 -> v198
+	NewMigration("Add workflow run, job and runner tables", addWorkflowTables),
+	// v198 -> v199
+	NewMigration("Add secret table", addSecretTable),
+	// v199 -> v200
+	NewMigration("Add repository schedule table", addRepoScheduleTable),
+	// v200 -> v201
+	NewMigration("Add repository symbol tables", addRepoSymbolTables),
+	// v201 -> v202
+	NewMigration("Add issue filter table", addIssueFilterTable),
+	// v202 -> v203
+	NewMigration("Add subscription levels to issue and repository watches", addSubscriptionLevels),
+	// v203 -> v204
+	NewMigration("Add parent team to teams", addParentTeamID),
+	// v204 -> v205
+	NewMigration("Add guest collaborators", addGuestCollaborations),
+	// v205 -> v206
+	NewMigration("Add repository interaction limit table", addRepoInteractionLimitTable),
+	// v206 -> v207
+	NewMigration("Add lock reason and expiry to issues", addIssueLockReasonAndExpiry),
+	// v207 -> v208
+	NewMigration("Add snoozed until to notifications", addSnoozedUntilToNotification),
+	// v208 -> v209
+	NewMigration("Add push device and notification channel preference tables", addPushDeviceTables),
+	// v209 -> v210
+	NewMigration("Add chat address table", addChatAddressTable),
+	// v210 -> v211
+	NewMigration("Add star list tables and move stars into default lists", addStarListTables),
+	// v211 -> v212
+	NewMigration("Add repo trending table", addRepoTrendingTable),
+	// v212 -> v213
+	NewMigration("Add maintenance mode table", addMaintenanceModeTable),
+	// v213 -> v214
+	NewMigration("Add backup table", addBackupTable),
+	// v214 -> v215
+	NewMigration("Add migration mapping table", addMigrationMappingTable),
+	// v215 -> v216
+	NewMigration("Add created unix to repo and user redirects", addCreatedUnixToRedirects),
+	// v216 -> v217
+	NewMigration("Add repo dependency table", addRepoDependencyTable),
+	// v217 -> v218
+	NewMigration("Add security advisory and vulnerability alert tables", addVulnerabilityAlertTables),
+	// v218 -> v219
+	NewMigration("Add repo push rule table", addRepoPushRuleTable),
+	// v219 -> v220
+	NewMigration("Add staging session and staged change tables", addStagingSessionTables),
+	// v220 -> v221
+	NewMigration("Add backport mapping table", addBackportMappingTable),
+	// v221 -> v222
+	NewMigration("Add repo pages table", addRepoPagesTable),
+	// v222 -> v223
+	NewMigration("Add attachment blob table and deduplicate new attachments", addAttachmentBlobTable),
+	// v223 -> v224
+	NewMigration("Add pinned repository and pinned issue tables", addPinnedRepoAndIssueTables),
+	// v224 -> v225
+	NewMigration("Add issue form table", addIssueFormTable),
+	// v225 -> v226
+	NewMigration("Add issue workflow states", addIssueWorkflowStates),
+	// v226 -> v227
+	NewMigration("Add issue priorities", addIssuePriorities),
+	// v227 -> v228
+	NewMigration("Add repository hook scripts", addRepoHookScripts),
+	// v228 -> v229
+	NewMigration("Add outbox events", addOutboxEvents),
+	// v229 -> v230
+	NewMigration("Add federation tables", addFederationTables),
+	// v230 -> v231
+	NewMigration("Add repository federation tables", addRepoFederationTables),
+	// v231 -> v232
+	NewMigration("Add bulk repository settings job tables", addRepoSettingsJobTables),
+	// v232 -> v233
+	NewMigration("Add branch protection template tables", addBranchProtectionTemplateTables),
+	// v233 -> v234
+	NewMigration("Add renamed branch table", addRenamedBranchTable),
+	// v234 -> v235
+	NewMigration("Add repository auto release table", addRepoAutoReleaseTable),
+	// v235 -> v236
+	NewMigration("Add status check max age to protected branches", addStatusCheckMaxAge),
+	// v236 -> v237
+	NewMigration("Add issue branch table", addIssueBranchTable),
+	// v237 -> v238
+	NewMigration("Add issue relation table", addIssueRelationTable),
+	// v238 -> v239
+	NewMigration("Add iteration tables", addIterationTables),
+	// v239 -> v240
+	NewMigration("Add issue custom field tables", addIssueCustomFieldTables),
+	// v240 -> v241
+	NewMigration("Add issue SLA tables", addIssueSLATables),
+	// v241 -> v242
+	NewMigration("Add issue read marker table", addIssueReadMarkerTable),
+	// v242 -> v243
+	NewMigration("Add auto-watch rule columns for user", addAutoWatchRuleColumnsForUser),
+	// v243 -> v244
+	NewMigration("Add owner columns for milestone", addOwnerColumnsForMilestone),
+	// v244 -> v245
+	NewMigration("Add custom domain verification columns for repo pages", addCustomDomainVerificationColumnsForRepoPages),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addPackageBlobUploadTable(x *xorm.Engine) error {
+	type PackageBlobUpload struct {
+		ID            string `xorm:"pk"`
+		PackageID     int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+		BytesReceived int64  `xorm:"NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated NOT NULL"`
+	}
+
+	if err := x.Sync2(new(PackageBlobUpload)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addWorkflowTables(x *xorm.Engine) error {
+	type WorkflowRun struct {
+		ID                int64  `xorm:"pk autoincr"`
+		RepoID            int64  `xorm:"INDEX NOT NULL"`
+		WorkflowID        string `xorm:"NOT NULL"`
+		Name              string `xorm:"NOT NULL"`
+		Event             string `xorm:"NOT NULL"`
+		Ref               string `xorm:"NOT NULL"`
+		CommitSHA         string `xorm:"VARCHAR(40) NOT NULL"`
+		IsForkPullRequest bool   `xorm:"NOT NULL DEFAULT false"`
+		TriggerUserID     int64  `xorm:"NOT NULL"`
+		Status            int    `xorm:"INDEX NOT NULL"`
+
+		StartedUnix timeutil.TimeStamp
+		StoppedUnix timeutil.TimeStamp
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type WorkflowJob struct {
+		ID       int64    `xorm:"pk autoincr"`
+		RunID    int64    `xorm:"INDEX NOT NULL"`
+		RepoID   int64    `xorm:"INDEX NOT NULL"`
+		JobID    string   `xorm:"NOT NULL"`
+		Name     string   `xorm:"NOT NULL"`
+		RunsOn   []string `xorm:"JSON TEXT"`
+		Needs    []string `xorm:"JSON TEXT"`
+		Payload  string   `xorm:"LONGTEXT"`
+		Status   int      `xorm:"INDEX NOT NULL"`
+		RunnerID int64    `xorm:"INDEX NOT NULL DEFAULT 0"`
+
+		StartedUnix timeutil.TimeStamp
+		StoppedUnix timeutil.TimeStamp
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type WorkflowRunner struct {
+		ID             int64    `xorm:"pk autoincr"`
+		Name           string   `xorm:"NOT NULL"`
+		OwnerID        int64    `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RepoID         int64    `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Labels         []string `xorm:"JSON TEXT"`
+		TokenHash      string   `xorm:"UNIQUE"`
+		TokenSalt      string
+		TokenLastEight string `xorm:"INDEX token_last_eight"`
+
+		LastOnlineUnix timeutil.TimeStamp
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(WorkflowRun), new(WorkflowJob), new(WorkflowRunner)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoScheduleTable(x *xorm.Engine) error {
+	type RepoSchedule struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Name        string             `xorm:"UNIQUE(s) NOT NULL"`
+		Spec        string             `xorm:"NOT NULL"`
+		CatchUp     int                `xorm:"NOT NULL DEFAULT 0"`
+		IsActive    bool               `xorm:"INDEX NOT NULL DEFAULT true"`
+		CreatorID   int64              `xorm:"NOT NULL"`
+		NextRunUnix timeutil.TimeStamp `xorm:"INDEX"`
+		LastRunUnix timeutil.TimeStamp
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(RepoSchedule))
+}
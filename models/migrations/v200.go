@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoSymbolTables(x *xorm.Engine) error {
+	type RepoSymbol struct {
+		ID        int64  `xorm:"pk autoincr"`
+		RepoID    int64  `xorm:"INDEX(s) NOT NULL"`
+		Ref       string `xorm:"INDEX(s) VARCHAR(255) NOT NULL"`
+		Name      string `xorm:"INDEX NOT NULL"`
+		Kind      string `xorm:"VARCHAR(50)"`
+		Language  string `xorm:"VARCHAR(50)"`
+		Path      string `xorm:"TEXT NOT NULL"`
+		Line      int
+		Scope     string
+		Signature string `xorm:"TEXT"`
+	}
+
+	type RepoSymbolStatus struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Ref         string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		CommitSha   string             `xorm:"VARCHAR(40)"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(RepoSymbol), new(RepoSymbolStatus))
+}
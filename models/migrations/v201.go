@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueFilterTable(x *xorm.Engine) error {
+	type IssueFilter struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Name        string             `xorm:"UNIQUE(s) NOT NULL"`
+		Query       string             `xorm:"TEXT NOT NULL"`
+		CreatorID   int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(IssueFilter))
+}
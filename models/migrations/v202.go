@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addSubscriptionLevels(x *xorm.Engine) error {
+	type IssueWatch struct {
+		Level  int `xorm:"NOT NULL DEFAULT 0"`
+		Events int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type Watch struct {
+		Level  int `xorm:"NOT NULL DEFAULT 0"`
+		Events int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(IssueWatch), new(Watch))
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoInteractionLimitTable(x *xorm.Engine) error {
+	type RepoInteractionLimit struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE NOT NULL"`
+		Type        int                `xorm:"NOT NULL DEFAULT 0"`
+		DoerID      int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"NOT NULL"`
+		ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+
+	return x.Sync2(new(RepoInteractionLimit))
+}
@@ -0,0 +1,20 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueLockReasonAndExpiry(x *xorm.Engine) error {
+	type Issue struct {
+		LockReason      int                `xorm:"NOT NULL DEFAULT 0"`
+		LockedUntilUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(Issue))
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addPushDeviceTables(x *xorm.Engine) error {
+	type PushDevice struct {
+		ID       int64 `xorm:"pk autoincr"`
+		UserID   int64 `xorm:"INDEX NOT NULL"`
+		Type     int   `xorm:"NOT NULL DEFAULT 0"`
+		Name     string
+		Endpoint string `xorm:"TEXT"`
+		P256DH   string `xorm:"'p256dh'"`
+		Auth     string
+		Token    string `xorm:"TEXT"`
+		Platform string
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type NotificationChannelPreference struct {
+		ID      int64 `xorm:"pk autoincr"`
+		UserID  int64 `xorm:"UNIQUE(s) NOT NULL"`
+		Channel int   `xorm:"UNIQUE(s) NOT NULL"`
+		Events  int   `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(PushDevice), new(NotificationChannelPreference))
+}
@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addChatAddressTable(x *xorm.Engine) error {
+	type ChatAddress struct {
+		ID               int64  `xorm:"pk autoincr"`
+		UserID           int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Protocol         int    `xorm:"UNIQUE(s) NOT NULL"`
+		Address          string `xorm:"NOT NULL"`
+		IsVerified       bool   `xorm:"NOT NULL DEFAULT false"`
+		VerificationCode string
+		RoomID           string
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync2(new(ChatAddress))
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addStarListTables(x *xorm.Engine) error {
+	type StarList struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Name        string             `xorm:"UNIQUE(s) NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		IsPrivate   bool               `xorm:"NOT NULL DEFAULT false"`
+		IsDefault   bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type StarListRepo struct {
+		ID          int64              `xorm:"pk autoincr"`
+		ListID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(StarList), new(StarListRepo)); err != nil {
+		return err
+	}
+
+	// Move the existing stars of every user into a default list
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	userIDs := make([]int64, 0, 100)
+	if err := sess.Table("star").Distinct("uid").Find(&userIDs); err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		list := &StarList{UserID: userID, Name: "Starred", IsDefault: true}
+		if _, err := sess.Insert(list); err != nil {
+			return err
+		}
+		if _, err := sess.Exec("INSERT INTO star_list_repo (list_id, repo_id, created_unix) SELECT ?, repo_id, created_unix FROM star WHERE uid = ?", list.ID, userID); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoTrendingTable(x *xorm.Engine) error {
+	type RepoTrending struct {
+		ID           int64              `xorm:"pk autoincr"`
+		RepoID       int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Period       int                `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Stars        int64              `xorm:"NOT NULL DEFAULT 0"`
+		PullRequests int64              `xorm:"NOT NULL DEFAULT 0"`
+		Score        int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		ComputedUnix timeutil.TimeStamp `xorm:"NOT NULL"`
+	}
+
+	return x.Sync2(new(RepoTrending))
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addMaintenanceModeTable(x *xorm.Engine) error {
+	type MaintenanceMode struct {
+		ID          int64              `xorm:"pk"`
+		IsEnabled   bool               `xorm:"NOT NULL DEFAULT false"`
+		Message     string             `xorm:"TEXT"`
+		RetryAfter  int64              `xorm:"NOT NULL DEFAULT 0"`
+		DoerID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(MaintenanceMode))
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addBackupTable(x *xorm.Engine) error {
+	type Backup struct {
+		ID           int64 `xorm:"pk autoincr"`
+		Type         int   `xorm:"INDEX NOT NULL"`
+		RepoID       int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+		OwnerName    string
+		RepoName     string
+		Status       int `xorm:"INDEX NOT NULL"`
+		Path         string
+		Size         int64              `xorm:"NOT NULL DEFAULT 0"`
+		Error        string             `xorm:"TEXT"`
+		DoerID       int64              `xorm:"NOT NULL"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+		FinishedUnix timeutil.TimeStamp
+	}
+
+	return x.Sync2(new(Backup))
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addMigrationMappingTable(x *xorm.Engine) error {
+	type MigrationMapping struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Type        string             `xorm:"VARCHAR(16) UNIQUE(s) NOT NULL"`
+		ForeignID   string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		LocalID     int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(MigrationMapping))
+}
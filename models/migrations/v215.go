@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addCreatedUnixToRedirects(x *xorm.Engine) error {
+	type RepoRedirect struct {
+		ID             int64  `xorm:"pk autoincr"`
+		OwnerID        int64  `xorm:"UNIQUE(s)"`
+		LowerName      string `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		RedirectRepoID int64
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type UserRedirect struct {
+		ID             int64  `xorm:"pk autoincr"`
+		LowerName      string `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		RedirectUserID int64
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(RepoRedirect), new(UserRedirect))
+}
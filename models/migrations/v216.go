@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addRepoDependencyTable(x *xorm.Engine) error {
+	type RepoDependency struct {
+		ID        int64  `xorm:"pk autoincr"`
+		RepoID    int64  `xorm:"INDEX NOT NULL"`
+		Manifest  string `xorm:"TEXT NOT NULL"`
+		Ecosystem string `xorm:"VARCHAR(20) INDEX NOT NULL"`
+		Name      string `xorm:"INDEX NOT NULL"`
+		Version   string
+		Scope     string `xorm:"VARCHAR(20)"`
+	}
+
+	return x.Sync2(new(RepoDependency))
+}
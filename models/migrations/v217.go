@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addVulnerabilityAlertTables(x *xorm.Engine) error {
+	type AdvisoryVersionRange struct {
+		Introduced   string `json:"introduced,omitempty"`
+		Fixed        string `json:"fixed,omitempty"`
+		LastAffected string `json:"last_affected,omitempty"`
+	}
+
+	type SecurityAdvisory struct {
+		ID            int64                   `xorm:"pk autoincr"`
+		OSVID         string                  `xorm:"'osv_id' UNIQUE(s) VARCHAR(255) NOT NULL"`
+		Ecosystem     string                  `xorm:"UNIQUE(s) VARCHAR(20) NOT NULL"`
+		Package       string                  `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		Aliases       []string                `xorm:"JSON TEXT"`
+		Summary       string                  `xorm:"TEXT"`
+		Severity      string                  `xorm:"VARCHAR(20)"`
+		URL           string                  `xorm:"TEXT"`
+		Ranges        []*AdvisoryVersionRange `xorm:"JSON TEXT"`
+		Versions      []string                `xorm:"JSON TEXT"`
+		PublishedUnix timeutil.TimeStamp
+		ModifiedUnix  timeutil.TimeStamp
+		WithdrawnUnix timeutil.TimeStamp
+		UpdatedUnix   timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type RepoVulnerabilitySetting struct {
+		ID               int64 `xorm:"pk autoincr"`
+		RepoID           int64 `xorm:"UNIQUE NOT NULL"`
+		Enabled          bool  `xorm:"INDEX NOT NULL DEFAULT false"`
+		OpenIssues       bool  `xorm:"NOT NULL DEFAULT false"`
+		DoerID           int64
+		CheckedCommitSha string             `xorm:"VARCHAR(40)"`
+		CheckedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		UpdatedUnix      timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type VulnerabilityAlert struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"INDEX NOT NULL"`
+		AdvisoryID     int64  `xorm:"INDEX NOT NULL"`
+		Manifest       string `xorm:"TEXT NOT NULL"`
+		Ecosystem      string `xorm:"VARCHAR(20) NOT NULL"`
+		Package        string `xorm:"NOT NULL"`
+		Version        string
+		FixedVersion   string
+		State          int    `xorm:"INDEX NOT NULL DEFAULT 0"`
+		DismissReason  string `xorm:"VARCHAR(20)"`
+		DismissComment string `xorm:"TEXT"`
+		DismisserID    int64
+		IssueID        int64
+		DismissedUnix  timeutil.TimeStamp
+		FixedUnix      timeutil.TimeStamp
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(SecurityAdvisory), new(RepoVulnerabilitySetting), new(VulnerabilityAlert))
+}
@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoPushRuleTable(x *xorm.Engine) error {
+	type RepoPushRule struct {
+		ID                    int64              `xorm:"pk autoincr"`
+		RepoID                int64              `xorm:"UNIQUE NOT NULL"`
+		MaxFileSize           int64              `xorm:"NOT NULL DEFAULT 0"`
+		ForbiddenFilePatterns string             `xorm:"TEXT"`
+		AuthorEmailDomains    string             `xorm:"TEXT"`
+		RejectForcePush       bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix           timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix           timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(RepoPushRule))
+}
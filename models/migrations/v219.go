@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addStagingSessionTables(x *xorm.Engine) error {
+	type StagingSession struct {
+		ID           int64              `xorm:"pk autoincr"`
+		RepoID       int64              `xorm:"INDEX NOT NULL"`
+		UserID       int64              `xorm:"INDEX NOT NULL"`
+		Branch       string             `xorm:"NOT NULL"`
+		BaseCommitID string             `xorm:"VARCHAR(40) NOT NULL"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type StagedChange struct {
+		ID           int64              `xorm:"pk autoincr"`
+		SessionID    int64              `xorm:"UNIQUE(s) NOT NULL"`
+		TreePath     string             `xorm:"UNIQUE(s) VARCHAR(500) NOT NULL"`
+		Operation    string             `xorm:"VARCHAR(10) NOT NULL"`
+		FromTreePath string             `xorm:"VARCHAR(500)"`
+		Content      string             `xorm:"LONGTEXT"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(StagingSession), new(StagedChange))
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoPagesTable(x *xorm.Engine) error {
+	type RepoPages struct {
+		ID            int64  `xorm:"pk autoincr"`
+		RepoID        int64  `xorm:"UNIQUE NOT NULL"`
+		Branch        string `xorm:"NOT NULL"`
+		Directory     string
+		CustomDomain  string             `xorm:"INDEX"`
+		Status        int                `xorm:"NOT NULL DEFAULT 0"`
+		CommitID      string             `xorm:"VARCHAR(40)"`
+		Error         string             `xorm:"TEXT"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+		PublishedUnix timeutil.TimeStamp
+	}
+
+	return x.Sync2(new(RepoPages))
+}
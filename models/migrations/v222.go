@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addAttachmentBlobTable(x *xorm.Engine) error {
+	type AttachmentBlob struct {
+		ID          int64              `xorm:"pk autoincr"`
+		HashSHA256  string             `xorm:"hash_sha256 char(64) UNIQUE NOT NULL"`
+		Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	type Attachment struct {
+		HashSHA256 string `xorm:"hash_sha256 VARCHAR(64) INDEX"`
+	}
+
+	return x.Sync2(new(AttachmentBlob), new(Attachment))
+}
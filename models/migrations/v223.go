@@ -0,0 +1,27 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addPinnedRepoAndIssueTables(x *xorm.Engine) error {
+	type PinnedRepo struct {
+		ID       int64 `xorm:"pk autoincr"`
+		OwnerID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		RepoID   int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Position int   `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type PinnedIssue struct {
+		ID       int64 `xorm:"pk autoincr"`
+		RepoID   int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		IssueID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Position int   `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(PinnedRepo), new(PinnedIssue))
+}
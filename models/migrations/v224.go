@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueFormTable(x *xorm.Engine) error {
+	type IssueForm struct {
+		ID          int64 `xorm:"pk autoincr"`
+		IssueID     int64 `xorm:"UNIQUE NOT NULL"`
+		Template    string
+		Values      map[string][]string `xorm:"JSON TEXT"`
+		CreatedUnix timeutil.TimeStamp  `xorm:"created"`
+	}
+
+	return x.Sync2(new(IssueForm))
+}
@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueWorkflowStates(x *xorm.Engine) error {
+	type IssueWorkflowState struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		Name        string `xorm:"UNIQUE(s) NOT NULL"`
+		Description string
+		Color       string             `xorm:"VARCHAR(7)"`
+		Sort        int                `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type Issue struct {
+		WorkflowStateID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	type Comment struct {
+		OldWorkflowStateID int64 `xorm:"NOT NULL DEFAULT 0"`
+		WorkflowStateID    int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(IssueWorkflowState), new(Issue), new(Comment))
+}
@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssuePriorities(x *xorm.Engine) error {
+	type IssuePriority struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		Level       int    `xorm:"UNIQUE(s) NOT NULL"`
+		Name        string `xorm:"NOT NULL"`
+		Description string
+		Color       string             `xorm:"VARCHAR(7)"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync2(new(IssuePriority))
+}
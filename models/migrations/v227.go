@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoHookScripts(x *xorm.Engine) error {
+	type RepoHookScript struct {
+		ID           int64  `xorm:"pk autoincr"`
+		RepoID       int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		Name         string `xorm:"UNIQUE(s) NOT NULL"`
+		HookType     string `xorm:"VARCHAR(20) NOT NULL"`
+		Content      string `xorm:"LONGTEXT NOT NULL"`
+		IsActive     bool   `xorm:"NOT NULL DEFAULT true"`
+		Sort         int    `xorm:"NOT NULL DEFAULT 0"`
+		CreatorID    int64
+		IsApproved   bool `xorm:"INDEX NOT NULL DEFAULT false"`
+		ApproverID   int64
+		ApprovedUnix timeutil.TimeStamp
+		CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync2(new(RepoHookScript))
+}
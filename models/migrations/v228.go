@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addOutboxEvents(x *xorm.Engine) error {
+	type OutboxEvent struct {
+		ID     int64  `xorm:"pk autoincr"`
+		Type   string `xorm:"VARCHAR(50) INDEX(ref) NOT NULL"`
+		RefID  int64  `xorm:"INDEX(ref) NOT NULL"`
+		RepoID int64  `xorm:"INDEX"`
+		DoerID int64
+
+		Attempts       int
+		LastError      string             `xorm:"TEXT"`
+		ClaimedUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		DispatchedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(OutboxEvent))
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addFederationTables(x *xorm.Engine) error {
+	type FederationKey struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerType   string             `xorm:"VARCHAR(10) UNIQUE(s) NOT NULL"`
+		OwnerID     int64              `xorm:"UNIQUE(s) NOT NULL"`
+		PrivateKey  string             `xorm:"TEXT NOT NULL"`
+		PublicKey   string             `xorm:"TEXT NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type RemoteActor struct {
+		ID                int64  `xorm:"pk autoincr"`
+		URI               string `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+		Type              string
+		PreferredUsername string
+		Host              string `xorm:"INDEX"`
+		Inbox             string `xorm:"TEXT NOT NULL"`
+		SharedInbox       string `xorm:"TEXT"`
+		PublicKeyID       string `xorm:"VARCHAR(255) INDEX"`
+		PublicKey         string `xorm:"TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type FederationFollower struct {
+		ID            int64              `xorm:"pk autoincr"`
+		OwnerType     string             `xorm:"VARCHAR(10) UNIQUE(s) NOT NULL"`
+		OwnerID       int64              `xorm:"UNIQUE(s) NOT NULL"`
+		RemoteActorID int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type FederationFollowing struct {
+		ID            int64              `xorm:"pk autoincr"`
+		UserID        int64              `xorm:"UNIQUE(s) NOT NULL"`
+		RemoteActorID int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		ActivityURI   string             `xorm:"VARCHAR(255) INDEX"`
+		IsAccepted    bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type FederationActivity struct {
+		ID            int64              `xorm:"pk autoincr"`
+		OwnerType     string             `xorm:"VARCHAR(10) INDEX(o) NOT NULL"`
+		OwnerID       int64              `xorm:"INDEX(o) NOT NULL"`
+		RemoteActorID int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		URI           string             `xorm:"VARCHAR(255) INDEX NOT NULL"`
+		Type          string             `xorm:"VARCHAR(50)"`
+		Content       string             `xorm:"LONGTEXT NOT NULL"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(FederationKey), new(RemoteActor), new(FederationFollower), new(FederationFollowing), new(FederationActivity))
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoFederationTables(x *xorm.Engine) error {
+	type RepoFederation struct {
+		ID            int64              `xorm:"pk autoincr"`
+		RepoID        int64              `xorm:"UNIQUE NOT NULL"`
+		AllowIssues   bool               `xorm:"NOT NULL DEFAULT false"`
+		AllowComments bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type RemoteStar struct {
+		ID            int64              `xorm:"pk autoincr"`
+		RepoID        int64              `xorm:"UNIQUE(s) NOT NULL"`
+		RemoteActorID int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		ActivityURI   string             `xorm:"VARCHAR(255)"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type FederatedObject struct {
+		ID            int64              `xorm:"pk autoincr"`
+		RepoID        int64              `xorm:"INDEX NOT NULL"`
+		RemoteActorID int64              `xorm:"INDEX NOT NULL"`
+		URI           string             `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+		IssueID       int64              `xorm:"INDEX NOT NULL"`
+		CommentID     int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type FederationBlock struct {
+		ID            int64              `xorm:"pk autoincr"`
+		RepoID        int64              `xorm:"UNIQUE(s) NOT NULL"`
+		RemoteActorID int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Domain        string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL DEFAULT ''"`
+		DoerID        int64              `xorm:"NOT NULL"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(RepoFederation), new(RemoteStar), new(FederatedObject), new(FederationBlock))
+}
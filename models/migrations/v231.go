@@ -0,0 +1,34 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoSettingsJobTables(x *xorm.Engine) error {
+	type RepoSettingsJob struct {
+		ID           int64              `xorm:"pk autoincr"`
+		OrgID        int64              `xorm:"INDEX NOT NULL"`
+		DoerID       int64              `xorm:"NOT NULL"`
+		Patch        string             `xorm:"TEXT"`
+		Status       int                `xorm:"INDEX NOT NULL"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+		FinishedUnix timeutil.TimeStamp
+	}
+
+	type RepoSettingsResult struct {
+		ID       int64 `xorm:"pk autoincr"`
+		JobID    int64 `xorm:"INDEX NOT NULL"`
+		RepoID   int64 `xorm:"NOT NULL"`
+		RepoName string
+		Status   int    `xorm:"NOT NULL"`
+		Message  string `xorm:"TEXT"`
+	}
+
+	return x.Sync2(new(RepoSettingsJob), new(RepoSettingsResult))
+}
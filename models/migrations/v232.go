@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addBranchProtectionTemplateTables(x *xorm.Engine) error {
+	type BranchProtectionTemplate struct {
+		ID                            int64    `xorm:"pk autoincr"`
+		OrgID                         int64    `xorm:"UNIQUE(s) NOT NULL"`
+		Name                          string   `xorm:"UNIQUE(s) NOT NULL"`
+		BranchPattern                 string   `xorm:"NOT NULL"`
+		ApplyToNewRepos               bool     `xorm:"NOT NULL DEFAULT false"`
+		CanPush                       bool     `xorm:"NOT NULL DEFAULT false"`
+		EnableWhitelist               bool     `xorm:"NOT NULL DEFAULT false"`
+		WhitelistUserIDs              []int64  `xorm:"JSON TEXT"`
+		WhitelistTeamIDs              []int64  `xorm:"JSON TEXT"`
+		EnableMergeWhitelist          bool     `xorm:"NOT NULL DEFAULT false"`
+		WhitelistDeployKeys           bool     `xorm:"NOT NULL DEFAULT false"`
+		MergeWhitelistUserIDs         []int64  `xorm:"JSON TEXT"`
+		MergeWhitelistTeamIDs         []int64  `xorm:"JSON TEXT"`
+		EnableStatusCheck             bool     `xorm:"NOT NULL DEFAULT false"`
+		StatusCheckContexts           []string `xorm:"JSON TEXT"`
+		EnableApprovalsWhitelist      bool     `xorm:"NOT NULL DEFAULT false"`
+		ApprovalsWhitelistUserIDs     []int64  `xorm:"JSON TEXT"`
+		ApprovalsWhitelistTeamIDs     []int64  `xorm:"JSON TEXT"`
+		RequiredApprovals             int64    `xorm:"NOT NULL DEFAULT 0"`
+		BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
+		BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
+		BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+		DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
+		RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
+		ProtectedFilePatterns         string   `xorm:"TEXT"`
+		UnprotectedFilePatterns       string   `xorm:"TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type BranchProtectionTemplateRepo struct {
+		ID         int64 `xorm:"pk autoincr"`
+		TemplateID int64 `xorm:"UNIQUE(s) NOT NULL"`
+		RepoID     int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	}
+
+	return x.Sync2(new(BranchProtectionTemplate), new(BranchProtectionTemplateRepo))
+}
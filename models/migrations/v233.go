@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRenamedBranchTable(x *xorm.Engine) error {
+	type RenamedBranch struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		From        string             `xorm:"UNIQUE(s) NOT NULL"`
+		To          string             `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(RenamedBranch))
+}
@@ -0,0 +1,34 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoAutoReleaseTable(x *xorm.Engine) error {
+	type RepoAutoRelease struct {
+		ID               int64  `xorm:"pk autoincr"`
+		RepoID           int64  `xorm:"UNIQUE NOT NULL"`
+		FilePath         string `xorm:"NOT NULL"`
+		VersionPattern   string `xorm:"TEXT"`
+		TagTemplate      string `xorm:"NOT NULL"`
+		TitleTemplate    string `xorm:"TEXT"`
+		NotesTemplate    string `xorm:"TEXT"`
+		IsDraft          bool   `xorm:"NOT NULL DEFAULT false"`
+		IsPrerelease     bool   `xorm:"NOT NULL DEFAULT false"`
+		LastVersion      string
+		LastTagName      string
+		LastCommitID     string             `xorm:"VARCHAR(40)"`
+		LastError        string             `xorm:"TEXT"`
+		CreatedUnix      timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix      timeutil.TimeStamp `xorm:"updated"`
+		LastReleasedUnix timeutil.TimeStamp
+	}
+
+	return x.Sync2(new(RepoAutoRelease))
+}
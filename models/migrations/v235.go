@@ -0,0 +1,21 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addStatusCheckMaxAge(x *xorm.Engine) error {
+	type ProtectedBranch struct {
+		StatusCheckMaxAge int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type BranchProtectionTemplate struct {
+		StatusCheckMaxAge int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(ProtectedBranch), new(BranchProtectionTemplate))
+}
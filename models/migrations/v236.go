@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueBranchTable(x *xorm.Engine) error {
+	type IssueBranch struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		BranchName  string             `xorm:"UNIQUE(s) NOT NULL"`
+		IssueID     int64              `xorm:"INDEX NOT NULL"`
+		CreatorID   int64              `xorm:"NOT NULL"`
+		PullID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(IssueBranch))
+}
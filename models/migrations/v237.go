@@ -0,0 +1,24 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueRelationTable(x *xorm.Engine) error {
+	type IssueRelation struct {
+		ID             int64              `xorm:"pk autoincr"`
+		Type           int                `xorm:"UNIQUE(s) NOT NULL"`
+		IssueID        int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		RelatedIssueID int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		PosterID       int64              `xorm:"NOT NULL"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(IssueRelation))
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIterationTables(x *xorm.Engine) error {
+	type IterationCadence struct {
+		ID              int64              `xorm:"pk autoincr"`
+		OrgID           int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RepoID          int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Title           string             `xorm:"NOT NULL"`
+		DurationDays    int                `xorm:"NOT NULL"`
+		IterationsAhead int                `xorm:"NOT NULL DEFAULT 1"`
+		StartUnix       timeutil.TimeStamp `xorm:"NOT NULL"`
+		CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type Iteration struct {
+		ID          int64              `xorm:"pk autoincr"`
+		CadenceID   int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Sequence    int                `xorm:"UNIQUE(s) NOT NULL"`
+		OrgID       int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RepoID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Title       string             `xorm:"NOT NULL"`
+		StartUnix   timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		EndUnix     timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type Issue struct {
+		IterationID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(IterationCadence), new(Iteration), new(Issue))
+}
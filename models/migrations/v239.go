@@ -0,0 +1,34 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueCustomFieldTables(x *xorm.Engine) error {
+	type IssueCustomField struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RepoID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Name        string             `xorm:"NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		Type        int                `xorm:"NOT NULL"`
+		Options     []string           `xorm:"JSON TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type IssueCustomFieldValue struct {
+		ID      int64  `xorm:"pk autoincr"`
+		IssueID int64  `xorm:"UNIQUE(s) NOT NULL"`
+		FieldID int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Value   string `xorm:"VARCHAR(255) NOT NULL"`
+	}
+
+	return x.Sync2(new(IssueCustomField), new(IssueCustomFieldValue))
+}
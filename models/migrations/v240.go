@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueSLATables(x *xorm.Engine) error {
+	type IssueSLAPolicy struct {
+		ID                 int64              `xorm:"pk autoincr"`
+		RepoID             int64              `xorm:"INDEX NOT NULL"`
+		Name               string             `xorm:"NOT NULL"`
+		LabelID            int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		FirstResponseHours int                `xorm:"NOT NULL"`
+		CreatedUnix        timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix        timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type IssueSLABreach struct {
+		ID          int64              `xorm:"pk autoincr"`
+		PolicyID    int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		IssueID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(IssueSLAPolicy), new(IssueSLABreach))
+}
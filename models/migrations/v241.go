@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueReadMarkerTable(x *xorm.Engine) error {
+	type IssueReadMarker struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UID         int64              `xorm:"UNIQUE(s) NOT NULL"`
+		IssueID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CommentID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(IssueReadMarker))
+}
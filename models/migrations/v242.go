@@ -0,0 +1,16 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "xorm.io/xorm"
+
+func addAutoWatchRuleColumnsForUser(x *xorm.Engine) error {
+	type User struct {
+		AutoWatchOnPush    string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+		AutoWatchOnMention string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	}
+
+	return x.Sync2(new(User))
+}
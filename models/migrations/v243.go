@@ -0,0 +1,16 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "xorm.io/xorm"
+
+func addOwnerColumnsForMilestone(x *xorm.Engine) error {
+	type Milestone struct {
+		OwnerID   int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+		OwnerType int   `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(Milestone))
+}
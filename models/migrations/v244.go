@@ -0,0 +1,40 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/xorm"
+)
+
+func addCustomDomainVerificationColumnsForRepoPages(x *xorm.Engine) error {
+	type RepoPages struct {
+		ID                           int64  `xorm:"pk autoincr"`
+		CustomDomainVerificationCode string `xorm:"VARCHAR(40)"`
+		CustomDomainVerified         bool   `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync2(new(RepoPages)); err != nil {
+		return err
+	}
+
+	// existing custom domains have to be verified as well
+	var pages []*RepoPages
+	if err := x.Where("custom_domain_verification_code IS NULL OR custom_domain_verification_code = ''").Find(&pages); err != nil {
+		return err
+	}
+	for _, p := range pages {
+		code, err := util.RandomString(40)
+		if err != nil {
+			return err
+		}
+		p.CustomDomainVerificationCode = code
+		if _, err := x.ID(p.ID).Cols("custom_domain_verification_code").Update(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
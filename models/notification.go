@@ -31,6 +31,8 @@ const (
 	NotificationStatusRead
 	// NotificationStatusPinned represents a pinned notification
 	NotificationStatusPinned
+	// NotificationStatusSnoozed represents a notification which is hidden until it resurfaces as unread
+	NotificationStatusSnoozed
 )
 
 const (
@@ -59,6 +61,9 @@ type Notification struct {
 
 	UpdatedBy int64 `xorm:"INDEX NOT NULL"`
 
+	// SnoozedUntilUnix is the time a snoozed notification becomes unread again
+	SnoozedUntilUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+
 	Issue      *Issue      `xorm:"-"`
 	Repository *Repository `xorm:"-"`
 	Comment    *Comment    `xorm:"-"`
@@ -82,6 +87,8 @@ type FindNotificationOptions struct {
 	Source            []NotificationSource
 	UpdatedAfterUnix  int64
 	UpdatedBeforeUnix int64
+	// After selects the notifications after the cursor instead of the page
+	After *Cursor
 }
 
 // ToCond will convert each condition into a xorm-Cond
@@ -108,23 +115,34 @@ func (opts *FindNotificationOptions) ToCond() builder.Cond {
 	if opts.UpdatedBeforeUnix != 0 {
 		cond = cond.And(builder.Lte{"notification.updated_unix": opts.UpdatedBeforeUnix})
 	}
+	if opts.After != nil {
+		cond = cond.And(opts.After.afterCond("notification.updated_unix", "notification.id", true))
+	}
 	return cond
 }
 
 // ToSession will convert the given options to a xorm Session by using the conditions from ToCond and joining with issue table if required
 func (opts *FindNotificationOptions) ToSession(e db.Engine) *xorm.Session {
 	sess := e.Where(opts.ToCond())
-	if opts.Page != 0 {
+	if opts.After != nil {
+		_, take := opts.GetSkipTake()
+		sess = sess.Limit(take)
+	} else if opts.Page != 0 {
 		sess = setSessionPagination(sess, opts)
 	}
 	return sess
 }
 
 func getNotifications(e db.Engine, options *FindNotificationOptions) (nl NotificationList, err error) {
-	err = options.ToSession(e).OrderBy("notification.updated_unix DESC").Find(&nl)
+	err = options.ToSession(e).OrderBy("notification.updated_unix DESC, notification.id DESC").Find(&nl)
 	return
 }
 
+// CursorOf returns the cursor of a notification of the list, the next page starts after it
+func (opts *FindNotificationOptions) CursorOf(n *Notification) *Cursor {
+	return &Cursor{Value: int64(n.UpdatedUnix), ID: n.ID}
+}
+
 // GetNotifications returns all notifications that fit to the given options.
 func GetNotifications(opts *FindNotificationOptions) (NotificationList, error) {
 	return getNotifications(db.DefaultContext().Engine(), opts)
@@ -176,23 +194,23 @@ func CreateRepoTransferNotification(doer, newOwner *User, repo *Repository) erro
 }
 
 // CreateOrUpdateIssueNotifications creates an issue notification
-// for each watcher, or updates it if already exists
+// for each watcher whose subscription includes the event, or updates it if already exists
 // receiverID > 0 just send to reciver, else send to all watcher
-func CreateOrUpdateIssueNotifications(issueID, commentID, notificationAuthorID, receiverID int64) error {
+func CreateOrUpdateIssueNotifications(issueID, commentID, notificationAuthorID, receiverID int64, event SubscriptionEvents) error {
 	sess := db.DefaultContext().NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
 		return err
 	}
 
-	if err := createOrUpdateIssueNotifications(sess, issueID, commentID, notificationAuthorID, receiverID); err != nil {
+	if err := createOrUpdateIssueNotifications(sess, issueID, commentID, notificationAuthorID, receiverID, event); err != nil {
 		return err
 	}
 
 	return sess.Commit()
 }
 
-func createOrUpdateIssueNotifications(e db.Engine, issueID, commentID, notificationAuthorID, receiverID int64) error {
+func createOrUpdateIssueNotifications(e db.Engine, issueID, commentID, notificationAuthorID, receiverID int64, event SubscriptionEvents) error {
 	// init
 	var toNotify map[int64]struct{}
 	notifications, err := getNotificationsByIssueID(e, issueID)
@@ -251,8 +269,16 @@ func createOrUpdateIssueNotifications(e db.Engine, issueID, commentID, notificat
 		return err
 	}
 
-	// notify
+	userIDs := make([]int64, 0, len(toNotify))
 	for userID := range toNotify {
+		userIDs = append(userIDs, userID)
+	}
+	if userIDs, err = filterIssueSubscribers(e, issue, userIDs, event); err != nil {
+		return err
+	}
+
+	// notify
+	for _, userID := range userIDs {
 		issue.Repo.Units = nil
 		user, err := getUserByID(e, userID)
 		if err != nil {
@@ -788,11 +814,39 @@ func SetNotificationStatus(notificationID int64, user *User, status Notification
 	}
 
 	notification.Status = status
+	notification.SnoozedUntilUnix = 0
 
-	_, err = db.DefaultContext().Engine().ID(notificationID).Update(notification)
+	_, err = db.DefaultContext().Engine().ID(notificationID).MustCols("snoozed_until_unix").Update(notification)
 	return notification, err
 }
 
+// SnoozeNotification hides a notification until the given time, when it becomes unread again
+func SnoozeNotification(notificationID int64, user *User, until timeutil.TimeStamp) (*Notification, error) {
+	notification, err := getNotificationByID(db.DefaultContext().Engine(), notificationID)
+	if err != nil {
+		return notification, err
+	}
+
+	if notification.UserID != user.ID {
+		return nil, fmt.Errorf("Can't change notification of another user: %d, %d", notification.UserID, user.ID)
+	}
+
+	notification.Status = NotificationStatusSnoozed
+	notification.SnoozedUntilUnix = until
+
+	_, err = db.DefaultContext().Engine().ID(notificationID).Cols("status", "snoozed_until_unix").Update(notification)
+	return notification, err
+}
+
+// ResurfaceSnoozedNotifications marks the snoozed notifications whose snooze time has passed as unread
+func ResurfaceSnoozedNotifications() error {
+	_, err := db.DefaultContext().Engine().
+		Where("status = ? AND snoozed_until_unix <= ?", NotificationStatusSnoozed, timeutil.TimeStampNow()).
+		Cols("status", "snoozed_until_unix", "updated_unix").
+		Update(&Notification{Status: NotificationStatusUnread})
+	return err
+}
+
 // GetNotificationByID return notification by ID
 func GetNotificationByID(notificationID int64) (*Notification, error) {
 	return getNotificationByID(db.DefaultContext().Engine(), notificationID)
@@ -0,0 +1,121 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+)
+
+// NotificationChannel is a way notifications are delivered to a user outside of the web interface
+type NotificationChannel int
+
+// Note: new channel must append to the end of list to maintain compatibility.
+const (
+	NotificationChannelMail NotificationChannel = iota
+	NotificationChannelPush
+)
+
+var notificationChannelNames = map[NotificationChannel]string{
+	NotificationChannelMail: "mail",
+	NotificationChannelPush: "push",
+}
+
+// NotificationChannels lists all notification channels
+var NotificationChannels = []NotificationChannel{NotificationChannelMail, NotificationChannelPush}
+
+// String returns the name of the channel
+func (c NotificationChannel) String() string {
+	return notificationChannelNames[c]
+}
+
+// NotificationChannelFromString returns the channel with the given name
+func NotificationChannelFromString(name string) (NotificationChannel, bool) {
+	for c, n := range notificationChannelNames {
+		if n == name {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// AllSubscriptionEvents is the set of all issue events
+const AllSubscriptionEvents = SubscriptionEventIssue | SubscriptionEventComment | SubscriptionEventStateChange |
+	SubscriptionEventPush | SubscriptionEventMention
+
+// NotificationChannelPreference defines which issue events a user receives through a channel.
+// Users without a preference for a channel receive all events through it.
+type NotificationChannelPreference struct {
+	ID      int64               `xorm:"pk autoincr"`
+	UserID  int64               `xorm:"UNIQUE(s) NOT NULL"`
+	Channel NotificationChannel `xorm:"UNIQUE(s) NOT NULL"`
+	Events  SubscriptionEvents  `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(NotificationChannelPreference))
+}
+
+// GetNotificationChannelEvents returns the events a user receives through each channel
+func GetNotificationChannelEvents(userID int64) (map[NotificationChannel]SubscriptionEvents, error) {
+	prefs := make([]*NotificationChannelPreference, 0, len(NotificationChannels))
+	if err := db.DefaultContext().Engine().Where("user_id = ?", userID).Find(&prefs); err != nil {
+		return nil, err
+	}
+
+	events := make(map[NotificationChannel]SubscriptionEvents, len(NotificationChannels))
+	for _, c := range NotificationChannels {
+		events[c] = AllSubscriptionEvents
+	}
+	for _, p := range prefs {
+		events[p.Channel] = p.Events
+	}
+	return events, nil
+}
+
+// SetNotificationChannelEvents sets the events a user receives through a channel
+func SetNotificationChannelEvents(userID int64, channel NotificationChannel, events SubscriptionEvents) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Delete(&NotificationChannelPreference{UserID: userID, Channel: channel}); err != nil {
+			return err
+		}
+		if events == AllSubscriptionEvents {
+			return nil
+		}
+		_, err := e.Insert(&NotificationChannelPreference{UserID: userID, Channel: channel, Events: events})
+		return err
+	})
+}
+
+// FilterNotificationChannelReceivers returns the users who receive the event through the channel
+func FilterNotificationChannelReceivers(userIDs []int64, channel NotificationChannel, event SubscriptionEvents) ([]int64, error) {
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+
+	excluded := make([]int64, 0, len(userIDs))
+	if err := db.DefaultContext().Engine().Table("notification_channel_preference").Cols("user_id").
+		Where("channel = ?", channel).
+		And("(events & ?) = 0", event).
+		In("user_id", userIDs).
+		Find(&excluded); err != nil {
+		return nil, err
+	}
+	if len(excluded) == 0 {
+		return userIDs, nil
+	}
+
+	isExcluded := make(map[int64]bool, len(excluded))
+	for _, id := range excluded {
+		isExcluded[id] = true
+	}
+	filtered := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !isExcluded[id] {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered, nil
+}
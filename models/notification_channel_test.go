@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationChannelEvents(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	events, err := GetNotificationChannelEvents(2)
+	assert.NoError(t, err)
+	assert.Equal(t, AllSubscriptionEvents, events[NotificationChannelMail])
+	assert.Equal(t, AllSubscriptionEvents, events[NotificationChannelPush])
+
+	assert.NoError(t, SetNotificationChannelEvents(2, NotificationChannelPush, SubscriptionEventMention))
+	events, err = GetNotificationChannelEvents(2)
+	assert.NoError(t, err)
+	assert.Equal(t, AllSubscriptionEvents, events[NotificationChannelMail])
+	assert.Equal(t, SubscriptionEventMention, events[NotificationChannelPush])
+
+	ids, err := FilterNotificationChannelReceivers([]int64{2, 4}, NotificationChannelPush, SubscriptionEventComment)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4}, ids)
+	ids, err = FilterNotificationChannelReceivers([]int64{2, 4}, NotificationChannelPush, SubscriptionEventMention)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 4}, ids)
+	ids, err = FilterNotificationChannelReceivers([]int64{2, 4}, NotificationChannelMail, SubscriptionEventComment)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 4}, ids)
+
+	// receiving all events again removes the preference
+	assert.NoError(t, SetNotificationChannelEvents(2, NotificationChannelPush, AllSubscriptionEvents))
+	db.AssertNotExistsBean(t, &NotificationChannelPreference{UserID: 2})
+}
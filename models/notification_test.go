@@ -6,8 +6,11 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,7 +18,7 @@ func TestCreateOrUpdateIssueNotifications(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
 
-	assert.NoError(t, CreateOrUpdateIssueNotifications(issue.ID, 0, 2, 0))
+	assert.NoError(t, CreateOrUpdateIssueNotifications(issue.ID, 0, 2, 0, SubscriptionEventComment))
 
 	// User 9 is inactive, thus notifications for user 1 and 4 are created
 	notf := db.AssertExistsAndLoadBean(t, &Notification{UserID: 1, IssueID: issue.ID}).(*Notification)
@@ -42,6 +45,33 @@ func TestNotificationsForUser(t *testing.T) {
 	}
 }
 
+func TestGetNotificationsCursor(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	opts := &FindNotificationOptions{
+		ListOptions: ListOptions{Page: 1, PageSize: 2},
+		UserID:      2,
+		Status:      []NotificationStatus{NotificationStatusRead, NotificationStatusUnread},
+	}
+	notfs, err := GetNotifications(opts)
+	assert.NoError(t, err)
+	if assert.Len(t, notfs, 2) {
+		assert.EqualValues(t, 5, notfs[0].ID)
+		assert.EqualValues(t, 4, notfs[1].ID)
+	}
+
+	opts.After = opts.CursorOf(notfs[1])
+	notfs, err = GetNotifications(opts)
+	assert.NoError(t, err)
+	if assert.Len(t, notfs, 1) {
+		assert.EqualValues(t, 2, notfs[0].ID)
+	}
+
+	opts.After = opts.CursorOf(notfs[0])
+	notfs, err = GetNotifications(opts)
+	assert.NoError(t, err)
+	assert.Len(t, notfs, 0)
+}
+
 func TestNotification_GetRepo(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	notf := db.AssertExistsAndLoadBean(t, &Notification{RepoID: 1}).(*Notification)
@@ -88,6 +118,49 @@ func TestSetNotificationStatus(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSnoozeNotification(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	notf := db.AssertExistsAndLoadBean(t,
+		&Notification{UserID: user.ID, Status: NotificationStatusRead}).(*Notification)
+
+	until := timeutil.TimeStamp(time.Now().Add(time.Hour).Unix())
+	_, err := SnoozeNotification(notf.ID, user, until)
+	assert.NoError(t, err)
+	db.AssertExistsAndLoadBean(t,
+		&Notification{ID: notf.ID, Status: NotificationStatusSnoozed, SnoozedUntilUnix: until})
+
+	// snoozed notifications are not due yet
+	assert.NoError(t, ResurfaceSnoozedNotifications())
+	db.AssertExistsAndLoadBean(t, &Notification{ID: notf.ID, Status: NotificationStatusSnoozed})
+
+	// changing the status ends the snooze
+	_, err = SetNotificationStatus(notf.ID, user, NotificationStatusPinned)
+	assert.NoError(t, err)
+	notf = db.AssertExistsAndLoadBean(t, &Notification{ID: notf.ID, Status: NotificationStatusPinned}).(*Notification)
+	assert.EqualValues(t, 0, notf.SnoozedUntilUnix)
+
+	_, err = SnoozeNotification(1, user, until)
+	assert.Error(t, err)
+}
+
+func TestResurfaceSnoozedNotifications(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	notf := db.AssertExistsAndLoadBean(t,
+		&Notification{UserID: user.ID, Status: NotificationStatusRead}).(*Notification)
+
+	unread := CountUnread(user)
+	_, err := SnoozeNotification(notf.ID, user, timeutil.TimeStamp(time.Now().Add(-time.Minute).Unix()))
+	assert.NoError(t, err)
+	assert.EqualValues(t, unread, CountUnread(user))
+
+	assert.NoError(t, ResurfaceSnoozedNotifications())
+	notf = db.AssertExistsAndLoadBean(t, &Notification{ID: notf.ID, Status: NotificationStatusUnread}).(*Notification)
+	assert.EqualValues(t, 0, notf.SnoozedUntilUnix)
+	assert.EqualValues(t, unread+1, CountUnread(user))
+}
+
 func TestUpdateNotificationStatuses(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
@@ -293,10 +293,29 @@ func deleteOrg(e *xorm.Session, u *User) error {
 		&OrgUser{OrgID: u.ID},
 		&TeamUser{OrgID: u.ID},
 		&TeamUnit{OrgID: u.ID},
+		&Secret{OwnerID: u.ID},
+		&IssueFilter{OwnerID: u.ID},
+		&PinnedRepo{OwnerID: u.ID},
+		&Iteration{OrgID: u.ID},
+		&IterationCadence{OrgID: u.ID},
+		&IssueCustomField{OrgID: u.ID},
+		&Milestone{OwnerID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if _, err := e.In("job_id", builder.Select("id").From("repo_settings_job").Where(builder.Eq{"org_id": u.ID})).
+		Delete(new(RepoSettingsResult)); err != nil {
+		return fmt.Errorf("delete repo settings results: %v", err)
+	}
+	if _, err := e.Delete(&RepoSettingsJob{OrgID: u.ID}); err != nil {
+		return fmt.Errorf("delete repo settings jobs: %v", err)
+	}
+	// the repositories are already deleted with their links to the templates
+	if _, err := e.Delete(&BranchProtectionTemplate{OrgID: u.ID}); err != nil {
+		return fmt.Errorf("delete branch protection templates: %v", err)
+	}
+
 	if _, err = e.ID(u.ID).Delete(new(User)); err != nil {
 		return fmt.Errorf("Delete: %v", err)
 	}
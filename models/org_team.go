@@ -25,6 +25,7 @@ const ownerTeamName = "Owners"
 type Team struct {
 	ID                      int64 `xorm:"pk autoincr"`
 	OrgID                   int64 `xorm:"INDEX"`
+	ParentTeamID            int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
 	LowerName               string
 	Name                    string
 	Description             string
@@ -493,6 +494,10 @@ func NewTeam(t *Team) (err error) {
 		return ErrTeamAlreadyExist{t.OrgID, t.LowerName}
 	}
 
+	if err = checkTeamParent(db.DefaultContext().Engine(), t, t.ParentTeamID); err != nil {
+		return err
+	}
+
 	sess := db.DefaultContext().NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
@@ -641,7 +646,18 @@ func UpdateTeam(t *Team, authChanged, includeAllChanged bool) (err error) {
 		return ErrTeamAlreadyExist{t.OrgID, t.LowerName}
 	}
 
-	if _, err = sess.ID(t.ID).Cols("name", "lower_name", "description",
+	oldTeam, err := getTeamByID(sess, t.ID)
+	if err != nil {
+		return err
+	}
+	parentChanged := oldTeam.ParentTeamID != t.ParentTeamID
+	if parentChanged {
+		if err = checkTeamParent(sess, t, t.ParentTeamID); err != nil {
+			return err
+		}
+	}
+
+	if _, err = sess.ID(t.ID).Cols("name", "lower_name", "description", "parent_team_id",
 		"can_create_org_repo", "authorize", "includes_all_repositories").Update(t); err != nil {
 		return fmt.Errorf("update: %v", err)
 	}
@@ -679,6 +695,23 @@ func UpdateTeam(t *Team, authChanged, includeAllChanged bool) (err error) {
 		}
 	}
 
+	// Update access of the members of the team and its child teams to the
+	// repositories of the old and the new parent teams.
+	if parentChanged {
+		tree, err := getTeamTree(sess, t.OrgID)
+		if err != nil {
+			return err
+		}
+		ancestorIDs := tree.ancestors(t.ID)
+		if oldTeam.ParentTeamID != 0 {
+			ancestorIDs = append(ancestorIDs, oldTeam.ParentTeamID)
+			ancestorIDs = append(ancestorIDs, tree.ancestors(oldTeam.ParentTeamID)...)
+		}
+		if err = recalculateTeamsAccesses(sess, ancestorIDs); err != nil {
+			return err
+		}
+	}
+
 	// Add all repositories to the team if it has access to all of them.
 	if includeAllChanged && t.IncludesAllRepositories {
 		err = t.addAllRepositories(sess)
@@ -707,6 +740,15 @@ func DeleteTeam(t *Team) error {
 		return err
 	}
 
+	// Move the child teams up to the parent of the team.
+	if _, err := sess.
+		Where("org_id=?", t.OrgID).
+		And("parent_team_id=?", t.ID).
+		Cols("parent_team_id").
+		Update(&Team{ParentTeamID: t.ParentTeamID}); err != nil {
+		return err
+	}
+
 	if err := t.removeAllRepositories(sess); err != nil {
 		return err
 	}
@@ -726,6 +768,17 @@ func DeleteTeam(t *Team) error {
 		return err
 	}
 
+	// Members of the team lose the access inherited from the parent teams.
+	if t.ParentTeamID != 0 {
+		tree, err := getTeamTree(sess, t.OrgID)
+		if err != nil {
+			return err
+		}
+		if err := recalculateTeamsAccesses(sess, append(tree.ancestors(t.ID), t.ParentTeamID)); err != nil {
+			return err
+		}
+	}
+
 	// Delete team.
 	if _, err := sess.ID(t.ID).Delete(new(Team)); err != nil {
 		return err
@@ -806,12 +859,17 @@ func getUserOrgTeams(e db.Engine, orgID, userID int64) (teams []*Team, err error
 		Find(&teams)
 }
 
+// getUserRepoTeams returns the teams with access to the repository the user is a member of,
+// directly or through a child team.
 func getUserRepoTeams(e db.Engine, orgID, userID, repoID int64) (teams []*Team, err error) {
+	teamIDs, err := getUserTeamIDsWithAncestors(e, orgID, userID)
+	if err != nil || len(teamIDs) == 0 {
+		return nil, err
+	}
 	return teams, e.
-		Join("INNER", "team_user", "team_user.team_id = team.id").
 		Join("INNER", "team_repo", "team_repo.team_id = team.id").
 		Where("team.org_id = ?", orgID).
-		And("team_user.uid=?", userID).
+		In("team.id", teamIDs).
 		And("team_repo.repo_id=?", repoID).
 		Find(&teams)
 }
@@ -833,8 +891,9 @@ func AddTeamMember(team *Team, userID int64) error {
 		return err
 	}
 
-	// Get team and its repositories.
-	if err := team.GetRepositories(&SearchTeamOptions{}); err != nil {
+	// Get the repositories of the team and the ones inherited from its parent teams.
+	repos, err := team.getRepositoriesWithAncestors(db.DefaultContext().Engine())
+	if err != nil {
 		return err
 	}
 
@@ -857,7 +916,7 @@ func AddTeamMember(team *Team, userID int64) error {
 	team.NumMembers++
 
 	// Give access to team repositories.
-	for _, repo := range team.Repos {
+	for _, repo := range repos {
 		if err := repo.recalculateUserAccess(sess, userID); err != nil {
 			return err
 		}
@@ -884,7 +943,8 @@ func removeTeamMember(e *xorm.Session, team *Team, userID int64) error {
 
 	team.NumMembers--
 
-	if err := team.getRepositories(e); err != nil {
+	repos, err := team.getRepositoriesWithAncestors(e)
+	if err != nil {
 		return err
 	}
 
@@ -902,7 +962,7 @@ func removeTeamMember(e *xorm.Session, team *Team, userID int64) error {
 	}
 
 	// Delete access to team repositories.
-	for _, repo := range team.Repos {
+	for _, repo := range repos {
 		if err := repo.recalculateUserAccess(e, userID); err != nil {
 			return err
 		}
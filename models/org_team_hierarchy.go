@@ -0,0 +1,182 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// Members of a child team are considered members of all its ancestors when
+// repository permissions are resolved: a child team inherits the repositories
+// of its parent teams. The resolved permissions are stored in the access table,
+// which is recalculated whenever the hierarchy or the membership changes.
+
+// teamTree maps the ids of the teams of an organization to the ids of their parent teams
+type teamTree map[int64]int64
+
+func newTeamTree(teams []*Team) teamTree {
+	tree := make(teamTree, len(teams))
+	for _, t := range teams {
+		tree[t.ID] = t.ParentTeamID
+	}
+	return tree
+}
+
+func getTeamTree(e db.Engine, orgID int64) (teamTree, error) {
+	teams := make([]*Team, 0, 10)
+	if err := e.Cols("id", "parent_team_id").Where("org_id = ?", orgID).Find(&teams); err != nil {
+		return nil, err
+	}
+	return newTeamTree(teams), nil
+}
+
+// ancestors returns the ids of the parent team, its parent and so on
+func (tree teamTree) ancestors(teamID int64) []int64 {
+	ids := make([]int64, 0, 2)
+	seen := map[int64]bool{teamID: true}
+	for id := tree[teamID]; id != 0 && !seen[id]; id = tree[id] {
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// withDescendants returns the id of the team and the ids of all teams below it
+func (tree teamTree) withDescendants(teamID int64) []int64 {
+	ids := []int64{teamID}
+	seen := map[int64]bool{teamID: true}
+	for i := 0; i < len(ids); i++ {
+		for id, parentID := range tree {
+			if parentID == ids[i] && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// checkTeamParent checks that parentID may become the parent team of t
+func checkTeamParent(e db.Engine, t *Team, parentID int64) error {
+	if parentID == 0 {
+		return nil
+	}
+	invalid := func(reason string) error {
+		return ErrTeamParentInvalid{TeamID: t.ID, ParentID: parentID, Reason: reason}
+	}
+	if t.IsOwnerTeam() {
+		return invalid("the owner team can not have a parent team")
+	}
+	if parentID == t.ID {
+		return invalid("a team can not be its own parent")
+	}
+
+	parent, err := getTeamByID(e, parentID)
+	if err != nil {
+		if IsErrTeamNotExist(err) {
+			return invalid("parent team does not exist")
+		}
+		return err
+	}
+	if parent.OrgID != t.OrgID {
+		return invalid("parent team belongs to another organization")
+	}
+	if parent.IsOwnerTeam() {
+		return invalid("the owner team can not have child teams")
+	}
+	if t.ID == 0 {
+		return nil
+	}
+
+	tree, err := getTeamTree(e, t.OrgID)
+	if err != nil {
+		return err
+	}
+	for _, id := range tree.ancestors(parentID) {
+		if id == t.ID {
+			return invalid("parent team is a child of the team")
+		}
+	}
+	return nil
+}
+
+// getUserTeamIDsWithAncestors returns the ids of the teams of an organization a user
+// is a member of, directly or through a child team
+func getUserTeamIDsWithAncestors(e db.Engine, orgID, userID int64) ([]int64, error) {
+	teamIDs := make([]int64, 0, 5)
+	if err := e.Table("team_user").Cols("team_id").
+		Where("org_id = ? AND uid = ?", orgID, userID).
+		Find(&teamIDs); err != nil {
+		return nil, err
+	}
+	if len(teamIDs) == 0 {
+		return teamIDs, nil
+	}
+
+	tree, err := getTeamTree(e, orgID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int64]bool, len(teamIDs))
+	for _, id := range teamIDs {
+		seen[id] = true
+	}
+	for _, id := range teamIDs {
+		for _, ancestorID := range tree.ancestors(id) {
+			if !seen[ancestorID] {
+				seen[ancestorID] = true
+				teamIDs = append(teamIDs, ancestorID)
+			}
+		}
+	}
+	return teamIDs, nil
+}
+
+// getRepositoriesWithAncestors returns the repositories of the team and of its ancestors
+func (t *Team) getRepositoriesWithAncestors(e db.Engine) ([]*Repository, error) {
+	tree, err := getTeamTree(e, t.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	return getTeamsRepositories(e, append(tree.ancestors(t.ID), t.ID))
+}
+
+func getTeamsRepositories(e db.Engine, teamIDs []int64) ([]*Repository, error) {
+	repos := make([]*Repository, 0, 10)
+	return repos, e.
+		In("id", builder.Select("repo_id").From("team_repo").Where(builder.In("team_id", teamIDs))).
+		OrderBy("name").
+		Find(&repos)
+}
+
+// recalculateTeamsAccesses recalculates the accesses to the repositories of the given teams
+func recalculateTeamsAccesses(e db.Engine, teamIDs []int64) error {
+	if len(teamIDs) == 0 {
+		return nil
+	}
+	repos, err := getTeamsRepositories(e, teamIDs)
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if err := repo.recalculateTeamAccesses(e, 0); err != nil {
+			return fmt.Errorf("recalculateTeamAccesses: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetChildTeams returns the teams directly below a team
+func GetChildTeams(t *Team) ([]*Team, error) {
+	teams := make([]*Team, 0, 5)
+	return teams, db.DefaultContext().Engine().
+		Where("org_id = ? AND parent_team_id = ?", t.OrgID, t.ID).
+		OrderBy("lower_name").
+		Find(&teams)
+}
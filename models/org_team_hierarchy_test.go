@@ -0,0 +1,108 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamTree(t *testing.T) {
+	tree := teamTree{1: 0, 2: 1, 3: 2, 4: 1, 5: 0}
+	assert.Equal(t, []int64{2, 1}, tree.ancestors(3))
+	assert.Empty(t, tree.ancestors(5))
+	assert.ElementsMatch(t, []int64{1, 2, 3, 4}, tree.withDescendants(1))
+	assert.Equal(t, []int64{5}, tree.withDescendants(5))
+
+	// a broken hierarchy must not loop forever
+	tree = teamTree{1: 2, 2: 1}
+	assert.Equal(t, []int64{2}, tree.ancestors(1))
+	assert.ElementsMatch(t, []int64{1, 2}, tree.withDescendants(1))
+}
+
+func TestUpdateTeam_Parent(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// team12Creators has no repositories, test_team has write access to repo 32
+	child := db.AssertExistsAndLoadBean(t, &Team{ID: 12}).(*Team)
+	db.AssertNotExistsBean(t, &Access{UserID: 28, RepoID: 32})
+
+	child.ParentTeamID = 7
+	assert.NoError(t, UpdateTeam(child, false, false))
+	db.AssertExistsAndLoadBean(t, &Team{ID: 12, ParentTeamID: 7})
+	access := db.AssertExistsAndLoadBean(t, &Access{UserID: 28, RepoID: 32}).(*Access)
+	assert.EqualValues(t, AccessModeWrite, access.Mode)
+
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 28}).(*User)
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 32}).(*Repository)
+	perm, err := GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanWrite(UnitTypeIssues))
+
+	// cycles are prevented
+	parent := db.AssertExistsAndLoadBean(t, &Team{ID: 7}).(*Team)
+	parent.ParentTeamID = 12
+	assert.True(t, IsErrTeamParentInvalid(UpdateTeam(parent, false, false)))
+
+	child.ParentTeamID = 0
+	assert.NoError(t, UpdateTeam(child, false, false))
+	db.AssertNotExistsBean(t, &Access{UserID: 28, RepoID: 32})
+
+	CheckConsistencyFor(t, &Team{ID: child.ID})
+}
+
+func TestUpdateTeam_ParentInvalid(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	team := db.AssertExistsAndLoadBean(t, &Team{ID: 2}).(*Team)
+	for _, parentID := range []int64{
+		2,   // itself
+		1,   // owner team
+		8,   // team of another organization
+		999, // not existing
+	} {
+		team.ParentTeamID = parentID
+		assert.True(t, IsErrTeamParentInvalid(UpdateTeam(team, false, false)), "parent %d", parentID)
+	}
+
+	owners := db.AssertExistsAndLoadBean(t, &Team{ID: 1}).(*Team)
+	owners.ParentTeamID = 2
+	assert.True(t, IsErrTeamParentInvalid(UpdateTeam(owners, false, false)))
+}
+
+func TestAddTeamMember_Inherited(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	child := &Team{OrgID: 3, Name: "child", Authorize: AccessModeRead, ParentTeamID: 7}
+	assert.NoError(t, NewTeam(child))
+
+	assert.NoError(t, AddTeamMember(child, 5))
+	access := db.AssertExistsAndLoadBean(t, &Access{UserID: 5, RepoID: 32}).(*Access)
+	assert.EqualValues(t, AccessModeWrite, access.Mode)
+
+	assert.NoError(t, RemoveTeamMember(child, 5))
+	db.AssertNotExistsBean(t, &Access{UserID: 5, RepoID: 32})
+}
+
+func TestDeleteTeam_Parent(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	middle := &Team{OrgID: 3, Name: "middle", Authorize: AccessModeRead, ParentTeamID: 7}
+	assert.NoError(t, NewTeam(middle))
+	assert.NoError(t, AddTeamMember(middle, 5))
+	child := db.AssertExistsAndLoadBean(t, &Team{ID: 12}).(*Team)
+	child.ParentTeamID = middle.ID
+	assert.NoError(t, UpdateTeam(child, false, false))
+	db.AssertExistsAndLoadBean(t, &Access{UserID: 28, RepoID: 32})
+
+	assert.NoError(t, DeleteTeam(middle))
+	// the child team moves up and keeps the inherited access
+	db.AssertExistsAndLoadBean(t, &Team{ID: 12, ParentTeamID: 7})
+	db.AssertExistsAndLoadBean(t, &Access{UserID: 28, RepoID: 32})
+	db.AssertNotExistsBean(t, &Access{UserID: 5, RepoID: 32})
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// OutboxEventType represents the mutation an outbox event was recorded for
+type OutboxEventType string
+
+// OutboxEventTypes
+const (
+	OutboxEventIssueCreated   OutboxEventType = "issue_created"
+	OutboxEventCommentCreated OutboxEventType = "comment_created"
+)
+
+// OutboxEvent is recorded in the same transaction as a mutation whose webhooks, notifications
+// and mails have yet to be dispatched, so they are not lost if the process dies before.
+// Events are dispatched at least once, an event claimed by a dispatcher which died before it
+// finished is dispatched again once its claim is stale.
+type OutboxEvent struct {
+	ID     int64           `xorm:"pk autoincr"`
+	Type   OutboxEventType `xorm:"VARCHAR(50) INDEX(ref) NOT NULL"`
+	RefID  int64           `xorm:"INDEX(ref) NOT NULL"`
+	RepoID int64           `xorm:"INDEX"`
+	DoerID int64
+
+	Attempts       int
+	LastError      string             `xorm:"TEXT"`
+	ClaimedUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	DispatchedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(OutboxEvent))
+}
+
+// ErrOutboxEventNotExist represents a "OutboxEventNotExist" kind of error.
+type ErrOutboxEventNotExist struct {
+	Type  OutboxEventType
+	RefID int64
+}
+
+// IsErrOutboxEventNotExist checks if an error is a ErrOutboxEventNotExist.
+func IsErrOutboxEventNotExist(err error) bool {
+	_, ok := err.(ErrOutboxEventNotExist)
+	return ok
+}
+
+func (err ErrOutboxEventNotExist) Error() string {
+	return fmt.Sprintf("outbox event does not exist [type: %s, ref_id: %d]", err.Type, err.RefID)
+}
+
+// addOutboxEvent records an outbox event, it must be called in the transaction of the mutation
+func addOutboxEvent(e db.Engine, typ OutboxEventType, refID, repoID, doerID int64) error {
+	_, err := e.Insert(&OutboxEvent{
+		Type:   typ,
+		RefID:  refID,
+		RepoID: repoID,
+		DoerID: doerID,
+	})
+	return err
+}
+
+// GetUndispatchedOutboxEvent returns the undispatched outbox event of a mutation
+func GetUndispatchedOutboxEvent(typ OutboxEventType, refID int64) (*OutboxEvent, error) {
+	event := new(OutboxEvent)
+	has, err := db.DefaultContext().Engine().
+		Where("type = ? AND ref_id = ? AND dispatched_unix = 0", typ, refID).
+		Get(event)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOutboxEventNotExist{Type: typ, RefID: refID}
+	}
+	return event, nil
+}
+
+// FindPendingOutboxEvents returns the undispatched outbox events after afterID which were recorded before
+// staleAfter and are not claimed, or whose claim is older than staleAfter
+func FindPendingOutboxEvents(afterID int64, staleAfter time.Duration, limit int) ([]*OutboxEvent, error) {
+	staleBefore := time.Now().Add(-staleAfter).Unix()
+	events := make([]*OutboxEvent, 0, limit)
+	return events, db.DefaultContext().Engine().
+		Where("id > ? AND dispatched_unix = 0 AND created_unix < ?", afterID, staleBefore).
+		And(builder.Or(builder.Eq{"claimed_unix": 0}, builder.Lt{"claimed_unix": staleBefore})).
+		Asc("id").
+		Limit(limit).
+		Find(&events)
+}
+
+// ClaimOutboxEvent claims an undispatched outbox event for its dispatch, it returns false if another
+// dispatcher claimed it in the meantime
+func ClaimOutboxEvent(event *OutboxEvent) (bool, error) {
+	claimed := timeutil.TimeStampNow()
+	n, err := db.DefaultContext().Engine().
+		Where("id = ? AND dispatched_unix = 0 AND claimed_unix = ?", event.ID, event.ClaimedUnix).
+		Cols("claimed_unix").
+		Update(&OutboxEvent{ClaimedUnix: claimed})
+	if err != nil || n == 0 {
+		return false, err
+	}
+	event.ClaimedUnix = claimed
+	return true, nil
+}
+
+// MarkOutboxEventDispatched marks a claimed outbox event as dispatched
+func MarkOutboxEventDispatched(event *OutboxEvent) error {
+	event.Attempts++
+	event.DispatchedUnix = timeutil.TimeStampNow()
+	_, err := db.DefaultContext().Engine().ID(event.ID).
+		Cols("attempts", "dispatched_unix").
+		Update(event)
+	return err
+}
+
+// MarkOutboxEventFailed releases the claim of an outbox event whose dispatch failed, so it is retried
+func MarkOutboxEventFailed(event *OutboxEvent, dispatchErr error) error {
+	event.Attempts++
+	event.LastError = dispatchErr.Error()
+	event.ClaimedUnix = 0
+	_, err := db.DefaultContext().Engine().ID(event.ID).
+		Cols("attempts", "last_error", "claimed_unix").
+		Update(event)
+	return err
+}
+
+// DeleteDispatchedOutboxEvents deletes the outbox events dispatched before olderThan
+func DeleteDispatchedOutboxEvents(olderThan time.Duration) error {
+	_, err := db.DefaultContext().Engine().
+		Where("dispatched_unix > 0 AND dispatched_unix < ?", time.Now().Add(-olderThan).Unix()).
+		Delete(new(OutboxEvent))
+	return err
+}
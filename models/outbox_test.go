@@ -0,0 +1,60 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxEvent(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	issue := testInsertIssue(t, "outbox issue", "", 0)
+	event, err := GetUndispatchedOutboxEvent(OutboxEventIssueCreated, issue.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, event.RepoID)
+	assert.EqualValues(t, 2, event.DoerID)
+
+	// recorded events are only pending once they are stale
+	events, err := FindPendingOutboxEvents(0, time.Hour, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	events, err = FindPendingOutboxEvents(0, -time.Hour, 10)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	claimed, err := ClaimOutboxEvent(event)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	// another dispatcher loaded the event before it was claimed
+	other := &OutboxEvent{ID: event.ID}
+	claimed, err = ClaimOutboxEvent(other)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	assert.NoError(t, MarkOutboxEventFailed(event, errors.New("unreachable")))
+	event = db.AssertExistsAndLoadBean(t, &OutboxEvent{ID: event.ID}).(*OutboxEvent)
+	assert.EqualValues(t, 1, event.Attempts)
+	assert.EqualValues(t, 0, event.ClaimedUnix)
+	assert.Equal(t, "unreachable", event.LastError)
+
+	claimed, err = ClaimOutboxEvent(event)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.NoError(t, MarkOutboxEventDispatched(event))
+
+	_, err = GetUndispatchedOutboxEvent(OutboxEventIssueCreated, issue.ID)
+	assert.True(t, IsErrOutboxEventNotExist(err))
+
+	assert.NoError(t, DeleteDispatchedOutboxEvents(-time.Hour))
+	db.AssertNotExistsBean(t, &OutboxEvent{ID: event.ID})
+}
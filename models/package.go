@@ -0,0 +1,389 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+var (
+	// ErrPackageNotExist indicates a package not exist error
+	ErrPackageNotExist = errors.New("Package does not exist")
+	// ErrPackageVersionNotExist indicates a package version not exist error
+	ErrPackageVersionNotExist = errors.New("Package version does not exist")
+	// ErrDuplicatePackageVersion indicates a duplicated package version error
+	ErrDuplicatePackageVersion = errors.New("Package version already exists")
+	// ErrDuplicatePackageFile indicates a duplicated package file error
+	ErrDuplicatePackageFile = errors.New("Package file already exists")
+	// ErrPackageFileNotExist indicates a package file not exist error
+	ErrPackageFileNotExist = errors.New("Package file does not exist")
+	// ErrPackageBlobNotExist indicates a package blob not exist error
+	ErrPackageBlobNotExist = errors.New("Package blob does not exist")
+)
+
+// PackageType specifies the protocol of a package
+type PackageType int
+
+// Note: new type must append to the end of list to maintain compatibility.
+const (
+	PackageTypeNpm PackageType = iota + 1
+	PackageTypePyPI
+	PackageTypeContainer
+	PackageTypeGeneric
+)
+
+// Name gets the name of the package type
+func (pt PackageType) Name() string {
+	switch pt {
+	case PackageTypeNpm:
+		return "npm"
+	case PackageTypePyPI:
+		return "pypi"
+	case PackageTypeContainer:
+		return "container"
+	case PackageTypeGeneric:
+		return "generic"
+	}
+	return ""
+}
+
+// Package represents a package of an owner (user or organization)
+type Package struct {
+	ID        int64       `xorm:"pk autoincr"`
+	OwnerID   int64       `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Type      PackageType `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name      string      `xorm:"NOT NULL"`
+	LowerName string      `xorm:"UNIQUE(s) INDEX NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// PackageVersion represents a single published version of a package
+type PackageVersion struct {
+	ID            int64  `xorm:"pk autoincr"`
+	PackageID     int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatorID     int64  `xorm:"NOT NULL DEFAULT 0"`
+	Version       string `xorm:"NOT NULL"`
+	LowerVersion  string `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	MetadataJSON  string `xorm:"metadata_json TEXT"`
+	DownloadCount int64  `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// PackageFile represents a named file of a package version
+type PackageFile struct {
+	ID        int64  `xorm:"pk autoincr"`
+	VersionID int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	BlobID    int64  `xorm:"INDEX NOT NULL"`
+	Name      string `xorm:"NOT NULL"`
+	LowerName string `xorm:"UNIQUE(s) INDEX NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// PackageBlob represents the content of package files, deduplicated by hash
+type PackageBlob struct {
+	ID         int64  `xorm:"pk autoincr"`
+	Size       int64  `xorm:"NOT NULL DEFAULT 0"`
+	HashMD5    string `xorm:"hash_md5 char(32) NOT NULL"`
+	HashSHA1   string `xorm:"hash_sha1 char(40) NOT NULL"`
+	HashSHA256 string `xorm:"hash_sha256 char(64) UNIQUE NOT NULL"`
+	HashSHA512 string `xorm:"hash_sha512 char(128) NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(Package))
+	db.RegisterModel(new(PackageVersion))
+	db.RegisterModel(new(PackageFile))
+	db.RegisterModel(new(PackageBlob))
+}
+
+// GetOrInsertPackage returns the package with the same owner, type and name or inserts p
+func GetOrInsertPackage(ctx *db.Context, p *Package) (*Package, error) {
+	e := ctx.Engine()
+
+	p.LowerName = strings.ToLower(p.Name)
+
+	existing := &Package{}
+	has, err := e.Where(builder.Eq{
+		"owner_id":   p.OwnerID,
+		"type":       p.Type,
+		"lower_name": p.LowerName,
+	}).Get(existing)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		return existing, nil
+	}
+	if _, err = e.Insert(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetPackageByName gets a package by owner, type and name
+func GetPackageByName(ownerID int64, packageType PackageType, name string) (*Package, error) {
+	p := &Package{}
+	has, err := db.DefaultContext().Engine().Where(builder.Eq{
+		"owner_id":   ownerID,
+		"type":       packageType,
+		"lower_name": strings.ToLower(name),
+	}).Get(p)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageNotExist
+	}
+	return p, nil
+}
+
+// GetAllPackageIDs returns the ids of all packages
+func GetAllPackageIDs() ([]int64, error) {
+	ids := make([]int64, 0, 50)
+	return ids, db.DefaultContext().Engine().Table("package").Cols("id").Find(&ids)
+}
+
+// GetPackagesByOwner returns all packages of an owner, optionally filtered by type
+func GetPackagesByOwner(ownerID int64, packageType PackageType) ([]*Package, error) {
+	cond := builder.NewCond().And(builder.Eq{"owner_id": ownerID})
+	if packageType != 0 {
+		cond = cond.And(builder.Eq{"type": packageType})
+	}
+	packages := make([]*Package, 0, 10)
+	return packages, db.DefaultContext().Engine().Where(cond).Asc("lower_name").Find(&packages)
+}
+
+// InsertPackageVersion inserts a new version or returns ErrDuplicatePackageVersion
+func InsertPackageVersion(ctx *db.Context, pv *PackageVersion) error {
+	e := ctx.Engine()
+
+	pv.LowerVersion = strings.ToLower(pv.Version)
+
+	has, err := e.Exist(&PackageVersion{
+		PackageID:    pv.PackageID,
+		LowerVersion: pv.LowerVersion,
+	})
+	if err != nil {
+		return err
+	}
+	if has {
+		return ErrDuplicatePackageVersion
+	}
+	_, err = e.Insert(pv)
+	return err
+}
+
+// GetPackageVersionByID gets a package version by its id
+func GetPackageVersionByID(id int64) (*PackageVersion, error) {
+	pv := &PackageVersion{}
+	has, err := db.DefaultContext().Engine().ID(id).Get(pv)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageVersionNotExist
+	}
+	return pv, nil
+}
+
+// GetPackageVersionByVersion gets a version of a package
+func GetPackageVersionByVersion(packageID int64, version string) (*PackageVersion, error) {
+	pv := &PackageVersion{}
+	has, err := db.DefaultContext().Engine().Where(builder.Eq{
+		"package_id":    packageID,
+		"lower_version": strings.ToLower(version),
+	}).Get(pv)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageVersionNotExist
+	}
+	return pv, nil
+}
+
+// GetPackageVersionsByPackageID returns all versions of a package, oldest first
+func GetPackageVersionsByPackageID(packageID int64) ([]*PackageVersion, error) {
+	versions := make([]*PackageVersion, 0, 10)
+	return versions, db.DefaultContext().Engine().
+		Where("package_id = ?", packageID).
+		Asc("created_unix", "id").
+		Find(&versions)
+}
+
+// UpdatePackageVersionMetadata updates the metadata of a package version
+func UpdatePackageVersionMetadata(ctx *db.Context, pv *PackageVersion) error {
+	_, err := ctx.Engine().ID(pv.ID).Cols("metadata_json").Update(pv)
+	return err
+}
+
+// IncrementPackageVersionDownloadCount increments the download counter of a version
+func IncrementPackageVersionDownloadCount(versionID int64) error {
+	_, err := db.DefaultContext().Engine().Exec("UPDATE `package_version` SET download_count = download_count + 1 WHERE id = ?", versionID)
+	return err
+}
+
+// InsertPackageFile inserts a file of a package version or returns ErrDuplicatePackageFile
+func InsertPackageFile(ctx *db.Context, pf *PackageFile) error {
+	e := ctx.Engine()
+
+	pf.LowerName = strings.ToLower(pf.Name)
+
+	has, err := e.Exist(&PackageFile{
+		VersionID: pf.VersionID,
+		LowerName: pf.LowerName,
+	})
+	if err != nil {
+		return err
+	}
+	if has {
+		return ErrDuplicatePackageFile
+	}
+	_, err = e.Insert(pf)
+	return err
+}
+
+// GetPackageFilesByVersionID returns all files of a package version
+func GetPackageFilesByVersionID(versionID int64) ([]*PackageFile, error) {
+	files := make([]*PackageFile, 0, 5)
+	return files, db.DefaultContext().Engine().Where("version_id = ?", versionID).Asc("lower_name").Find(&files)
+}
+
+// GetPackageFileByName gets a file of a package version by its name
+func GetPackageFileByName(versionID int64, name string) (*PackageFile, error) {
+	pf := &PackageFile{}
+	has, err := db.DefaultContext().Engine().Where(builder.Eq{
+		"version_id": versionID,
+		"lower_name": strings.ToLower(name),
+	}).Get(pf)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageFileNotExist
+	}
+	return pf, nil
+}
+
+// DeletePackageFilesByVersionID deletes all files of a package version
+func DeletePackageFilesByVersionID(ctx *db.Context, versionID int64) error {
+	_, err := ctx.Engine().Where("version_id = ?", versionID).Delete(&PackageFile{})
+	return err
+}
+
+// GetPackageBlobByHash gets a package blob by its SHA256 hash
+func GetPackageBlobByHash(hashSHA256 string) (*PackageBlob, error) {
+	pb := &PackageBlob{}
+	has, err := db.DefaultContext().Engine().Where("hash_sha256 = ?", strings.ToLower(hashSHA256)).Get(pb)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageBlobNotExist
+	}
+	return pb, nil
+}
+
+// IsPackageBlobReferencedByPackage checks if a blob is referenced by a file of the package
+func IsPackageBlobReferencedByPackage(packageID, blobID int64) (bool, error) {
+	return db.DefaultContext().Engine().
+		Table("package_file").
+		Join("INNER", "package_version", "package_version.id = package_file.version_id").
+		Where("package_version.package_id = ? AND package_file.blob_id = ?", packageID, blobID).
+		Exist()
+}
+
+// GetOrInsertPackageBlob returns the blob with the same SHA256 hash or inserts pb.
+// The returned bool reports whether the blob existed before.
+func GetOrInsertPackageBlob(ctx *db.Context, pb *PackageBlob) (*PackageBlob, bool, error) {
+	e := ctx.Engine()
+
+	existing := &PackageBlob{}
+	has, err := e.Where("hash_sha256 = ?", pb.HashSHA256).Get(existing)
+	if err != nil {
+		return nil, false, err
+	}
+	if has {
+		return existing, true, nil
+	}
+	if _, err = e.Insert(pb); err != nil {
+		return nil, false, err
+	}
+	return pb, false, nil
+}
+
+// GetPackageBlobByID gets a package blob by its id
+func GetPackageBlobByID(id int64) (*PackageBlob, error) {
+	pb := &PackageBlob{}
+	has, err := db.DefaultContext().Engine().ID(id).Get(pb)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageBlobNotExist
+	}
+	return pb, nil
+}
+
+// FindUnreferencedPackageBlobs returns blobs older than olderThan which are not referenced by any file
+func FindUnreferencedPackageBlobs(olderThan timeutil.TimeStamp) ([]*PackageBlob, error) {
+	blobs := make([]*PackageBlob, 0, 10)
+	return blobs, db.DefaultContext().Engine().
+		Where("created_unix < ?", olderThan).
+		And(builder.NotIn("id", builder.Select("blob_id").From("package_file"))).
+		Find(&blobs)
+}
+
+// DeletePackageBlobByID deletes a package blob row
+func DeletePackageBlobByID(id int64) error {
+	_, err := db.DefaultContext().Engine().ID(id).Delete(&PackageBlob{})
+	return err
+}
+
+// DeletePackageVersionByID deletes a package version with its files.
+// The package itself is removed if it has no versions left.
+// Blobs are left for the garbage collection.
+func DeletePackageVersionByID(versionID int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+
+		pv := &PackageVersion{}
+		has, err := e.ID(versionID).Get(pv)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrPackageVersionNotExist
+		}
+
+		if _, err := e.Where("version_id = ?", pv.ID).Delete(&PackageFile{}); err != nil {
+			return err
+		}
+		if _, err := e.ID(pv.ID).Delete(&PackageVersion{}); err != nil {
+			return err
+		}
+
+		count, err := e.Where("package_id = ?", pv.PackageID).Count(&PackageVersion{})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			_, err = e.ID(pv.PackageID).Delete(&Package{})
+		}
+		return err
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	gouuid "github.com/google/uuid"
+)
+
+// ErrPackageBlobUploadNotExist indicates a package blob upload not exist error
+var ErrPackageBlobUploadNotExist = errors.New("Package blob upload does not exist")
+
+// PackageBlobUpload represents an in-progress chunked upload of a package blob
+type PackageBlobUpload struct {
+	ID            string `xorm:"pk"`
+	PackageID     int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	BytesReceived int64  `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(PackageBlobUpload))
+}
+
+// CreatePackageBlobUpload inserts a new blob upload
+func CreatePackageBlobUpload(packageID int64) (*PackageBlobUpload, error) {
+	pbu := &PackageBlobUpload{
+		ID:        gouuid.New().String(),
+		PackageID: packageID,
+	}
+	_, err := db.DefaultContext().Engine().Insert(pbu)
+	return pbu, err
+}
+
+// GetPackageBlobUploadByID gets a blob upload by id
+func GetPackageBlobUploadByID(id string) (*PackageBlobUpload, error) {
+	pbu := &PackageBlobUpload{}
+	has, err := db.DefaultContext().Engine().ID(id).Get(pbu)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageBlobUploadNotExist
+	}
+	return pbu, nil
+}
+
+// UpdatePackageBlobUpload updates the received size of the blob upload
+func UpdatePackageBlobUpload(pbu *PackageBlobUpload) error {
+	_, err := db.DefaultContext().Engine().ID(pbu.ID).Cols("bytes_received").Update(pbu)
+	return err
+}
+
+// DeletePackageBlobUploadByID deletes the blob upload
+func DeletePackageBlobUploadByID(id string) error {
+	_, err := db.DefaultContext().Engine().ID(id).Delete(&PackageBlobUpload{})
+	return err
+}
+
+// FindExpiredPackageBlobUploads gets all blob uploads which were not updated since olderThan
+func FindExpiredPackageBlobUploads(olderThan timeutil.TimeStamp) ([]*PackageBlobUpload, error) {
+	pbus := make([]*PackageBlobUpload, 0, 10)
+	return pbus, db.DefaultContext().Engine().Where("updated_unix < ?", olderThan).Find(&pbus)
+}
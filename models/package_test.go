@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageVersionLifecycle(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	ctx := db.DefaultContext()
+
+	p, err := GetOrInsertPackage(ctx, &Package{OwnerID: 2, Type: PackageTypeNpm, Name: "Test-Package"})
+	assert.NoError(t, err)
+	same, err := GetOrInsertPackage(ctx, &Package{OwnerID: 2, Type: PackageTypeNpm, Name: "test-package"})
+	assert.NoError(t, err)
+	assert.Equal(t, p.ID, same.ID)
+
+	pv := &PackageVersion{PackageID: p.ID, CreatorID: 2, Version: "1.0.0"}
+	assert.NoError(t, InsertPackageVersion(ctx, pv))
+	assert.Equal(t, ErrDuplicatePackageVersion, InsertPackageVersion(ctx, &PackageVersion{PackageID: p.ID, Version: "1.0.0"}))
+
+	pb, exists, err := GetOrInsertPackageBlob(ctx, &PackageBlob{HashSHA256: "abcd", Size: 4})
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	_, exists, err = GetOrInsertPackageBlob(ctx, &PackageBlob{HashSHA256: "abcd", Size: 4})
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.NoError(t, InsertPackageFile(ctx, &PackageFile{VersionID: pv.ID, BlobID: pb.ID, Name: "test.tgz"}))
+	assert.Equal(t, ErrDuplicatePackageFile, InsertPackageFile(ctx, &PackageFile{VersionID: pv.ID, BlobID: pb.ID, Name: "TEST.tgz"}))
+
+	pf, err := GetPackageFileByName(pv.ID, "test.tgz")
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ID, pf.BlobID)
+
+	referenced, err := IsPackageBlobReferencedByPackage(p.ID, pb.ID)
+	assert.NoError(t, err)
+	assert.True(t, referenced)
+
+	future := timeutil.TimeStampNow().Add(10)
+	blobs, err := FindUnreferencedPackageBlobs(future)
+	assert.NoError(t, err)
+	assert.Len(t, blobs, 0)
+
+	assert.NoError(t, DeletePackageVersionByID(pv.ID))
+	_, err = GetPackageByName(2, PackageTypeNpm, "test-package")
+	assert.Equal(t, ErrPackageNotExist, err)
+
+	blobs, err = FindUnreferencedPackageBlobs(future)
+	assert.NoError(t, err)
+	assert.Len(t, blobs, 1)
+}
+
+func TestPackageBlobUpload(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	pbu, err := CreatePackageBlobUpload(1)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pbu.ID)
+
+	pbu.BytesReceived = 10
+	assert.NoError(t, UpdatePackageBlobUpload(pbu))
+
+	pbu, err = GetPackageBlobUploadByID(pbu.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, pbu.BytesReceived)
+
+	expired, err := FindExpiredPackageBlobUploads(timeutil.TimeStampNow().Add(10))
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+
+	assert.NoError(t, DeletePackageBlobUploadByID(pbu.ID))
+	_, err = GetPackageBlobUploadByID(pbu.ID)
+	assert.Equal(t, ErrPackageBlobUploadNotExist, err)
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ErrPushDeviceNotExist indicates a push device not exist error
+var ErrPushDeviceNotExist = errors.New("Push device does not exist")
+
+// PushDeviceType defines how push messages are delivered to a device
+type PushDeviceType int
+
+// Note: new type must append to the end of list to maintain compatibility.
+const (
+	PushDeviceTypeWeb     PushDeviceType = iota // browser subscribed through the Web Push protocol
+	PushDeviceTypeGateway                       // mobile device reached through the configured push gateway
+)
+
+var pushDeviceTypeNames = map[PushDeviceType]string{
+	PushDeviceTypeWeb:     "web",
+	PushDeviceTypeGateway: "gateway",
+}
+
+// String returns the name of the device type
+func (t PushDeviceType) String() string {
+	return pushDeviceTypeNames[t]
+}
+
+// PushDeviceTypeFromString returns the device type with the given name
+func PushDeviceTypeFromString(name string) (PushDeviceType, bool) {
+	for t, n := range pushDeviceTypeNames {
+		if n == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// PushDevice represents a device of a user which receives push notifications
+type PushDevice struct {
+	ID     int64          `xorm:"pk autoincr"`
+	UserID int64          `xorm:"INDEX NOT NULL"`
+	Type   PushDeviceType `xorm:"NOT NULL DEFAULT 0"`
+	Name   string
+
+	// Endpoint, P256DH and Auth identify the subscription of a web device
+	Endpoint string `xorm:"TEXT"`
+	P256DH   string `xorm:"'p256dh'"`
+	Auth     string
+
+	// Token and Platform identify a gateway device
+	Token    string `xorm:"TEXT"`
+	Platform string
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func init() {
+	db.RegisterModel(new(PushDevice))
+}
+
+// RegisterPushDevice adds a push device to a user. If the user registered the device before,
+// the existing registration is updated instead.
+func RegisterPushDevice(device *PushDevice) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		existing := &PushDevice{UserID: device.UserID, Type: device.Type}
+		var has bool
+		var err error
+		if device.Type == PushDeviceTypeWeb {
+			has, err = e.Where("endpoint = ?", device.Endpoint).Get(existing)
+		} else {
+			has, err = e.Where("token = ?", device.Token).Get(existing)
+		}
+		if err != nil {
+			return err
+		}
+		if !has {
+			_, err = e.Insert(device)
+			return err
+		}
+
+		device.ID = existing.ID
+		device.CreatedUnix = existing.CreatedUnix
+		_, err = e.ID(device.ID).AllCols().Update(device)
+		return err
+	})
+}
+
+// GetPushDeviceByID returns the push device of a user with the given id
+func GetPushDeviceByID(userID, id int64) (*PushDevice, error) {
+	device := &PushDevice{ID: id, UserID: userID}
+	has, err := db.DefaultContext().Engine().Get(device)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPushDeviceNotExist
+	}
+	return device, nil
+}
+
+// GetPushDevices returns the push devices of a user
+func GetPushDevices(userID int64) ([]*PushDevice, error) {
+	devices := make([]*PushDevice, 0, 5)
+	return devices, db.DefaultContext().Engine().
+		Where("user_id = ?", userID).
+		OrderBy("id").
+		Find(&devices)
+}
+
+// GetPushDevicesByUserIDs returns the push devices of the given types of the given users
+func GetPushDevicesByUserIDs(userIDs []int64, types []PushDeviceType) ([]*PushDevice, error) {
+	devices := make([]*PushDevice, 0, len(userIDs))
+	if len(userIDs) == 0 || len(types) == 0 {
+		return devices, nil
+	}
+	return devices, db.DefaultContext().Engine().
+		In("user_id", userIDs).
+		In("type", types).
+		Find(&devices)
+}
+
+// DeletePushDevice removes a push device of a user
+func DeletePushDevice(userID, id int64) error {
+	deleted, err := db.DefaultContext().Engine().Delete(&PushDevice{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrPushDeviceNotExist
+	}
+	return nil
+}
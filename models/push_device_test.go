@@ -0,0 +1,59 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterPushDevice(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	device := &PushDevice{
+		UserID:   2,
+		Type:     PushDeviceTypeWeb,
+		Name:     "laptop",
+		Endpoint: "https://push.example.com/abc",
+		P256DH:   "key",
+		Auth:     "secret",
+	}
+	assert.NoError(t, RegisterPushDevice(device))
+	assert.NotZero(t, device.ID)
+
+	// registering the same subscription again updates the device
+	again := &PushDevice{
+		UserID:   2,
+		Type:     PushDeviceTypeWeb,
+		Name:     "renamed",
+		Endpoint: "https://push.example.com/abc",
+		P256DH:   "new key",
+		Auth:     "new secret",
+	}
+	assert.NoError(t, RegisterPushDevice(again))
+	assert.Equal(t, device.ID, again.ID)
+
+	assert.NoError(t, RegisterPushDevice(&PushDevice{UserID: 2, Type: PushDeviceTypeGateway, Token: "token", Platform: "android"}))
+	assert.NoError(t, RegisterPushDevice(&PushDevice{UserID: 4, Type: PushDeviceTypeGateway, Token: "token", Platform: "android"}))
+
+	devices, err := GetPushDevices(2)
+	assert.NoError(t, err)
+	if assert.Len(t, devices, 2) {
+		assert.Equal(t, "renamed", devices[0].Name)
+		assert.Equal(t, "new key", devices[0].P256DH)
+	}
+
+	devices, err = GetPushDevicesByUserIDs([]int64{2, 4}, []PushDeviceType{PushDeviceTypeGateway})
+	assert.NoError(t, err)
+	assert.Len(t, devices, 2)
+
+	assert.Equal(t, ErrPushDeviceNotExist, DeletePushDevice(4, device.ID))
+	assert.NoError(t, DeletePushDevice(2, device.ID))
+	_, err = GetPushDeviceByID(2, device.ID)
+	assert.Equal(t, ErrPushDeviceNotExist, err)
+}
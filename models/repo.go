@@ -633,9 +633,9 @@ func (repo *Repository) GetReviewerTeams() ([]*Team, error) {
 	return teams, err
 }
 
-// GetMilestoneByID returns the milestone belongs to repository by given ID.
+// GetMilestoneByID returns the milestone belongs to repository, or shared by its owner, by given ID.
 func (repo *Repository) GetMilestoneByID(milestoneID int64) (*Milestone, error) {
-	return GetMilestoneByRepoID(repo.ID, milestoneID)
+	return getMilestoneForRepo(db.DefaultContext().Engine(), repo, milestoneID)
 }
 
 // IssueStats returns number of open and closed repository issues by given filter mode.
@@ -1487,7 +1487,9 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 	}
 	releaseAttachments := make([]string, 0, len(attachments))
 	for i := 0; i < len(attachments); i++ {
-		releaseAttachments = append(releaseAttachments, attachments[i].RelativePath())
+		if !attachments[i].IsDeduplicated() {
+			releaseAttachments = append(releaseAttachments, attachments[i].RelativePath())
+		}
 	}
 
 	if _, err := sess.Exec("UPDATE `user` SET num_stars=num_stars-1 WHERE id IN (SELECT `uid` FROM `star` WHERE repo_id = ?)", repo.ID); err != nil {
@@ -1497,26 +1499,61 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 	if err := deleteBeans(sess,
 		&Access{RepoID: repo.ID},
 		&Action{RepoID: repo.ID},
+		&BackportMapping{RepoID: repoID},
+		&BranchProtectionTemplateRepo{RepoID: repoID},
 		&Collaboration{RepoID: repoID},
 		&Comment{RefRepoID: repoID},
 		&CommitStatus{RepoID: repoID},
 		&DeletedBranch{RepoID: repoID},
+		&FederatedObject{RepoID: repoID},
+		&FederationActivity{OwnerType: FederationOwnerRepo, OwnerID: repoID},
+		&FederationBlock{RepoID: repoID},
+		&FederationFollower{OwnerType: FederationOwnerRepo, OwnerID: repoID},
+		&FederationKey{OwnerType: FederationOwnerRepo, OwnerID: repoID},
 		&HookTask{RepoID: repoID},
+		&IssueBranch{RepoID: repoID},
+		&IssueCustomField{RepoID: repoID},
+		&IssuePriority{RepoID: repoID},
+		&IssueSLAPolicy{RepoID: repoID},
+		&IssueWorkflowState{RepoID: repoID},
+		&Iteration{RepoID: repoID},
+		&IterationCadence{RepoID: repoID},
 		&LFSLock{RepoID: repoID},
 		&LanguageStat{RepoID: repoID},
+		&MigrationMapping{RepoID: repoID},
 		&Milestone{RepoID: repoID},
 		&Mirror{RepoID: repoID},
 		&Notification{RepoID: repoID},
+		&OutboxEvent{RepoID: repoID},
+		&PinnedIssue{RepoID: repoID},
+		&PinnedRepo{RepoID: repoID},
 		&ProtectedBranch{RepoID: repoID},
 		&ProtectedTag{RepoID: repoID},
 		&PullRequest{BaseRepoID: repoID},
 		&PushMirror{RepoID: repoID},
 		&Release{RepoID: repoID},
+		&RemoteStar{RepoID: repoID},
+		&RenamedBranch{RepoID: repoID},
+		&RepoAutoRelease{RepoID: repoID},
+		&RepoDependency{RepoID: repoID},
+		&RepoFederation{RepoID: repoID},
+		&RepoHookScript{RepoID: repoID},
 		&RepoIndexerStatus{RepoID: repoID},
+		&RepoInteractionLimit{RepoID: repoID},
+		&RepoPages{RepoID: repoID},
+		&RepoPushRule{RepoID: repoID},
 		&RepoRedirect{RedirectRepoID: repoID},
+		&RepoSchedule{RepoID: repoID},
+		&RepoSymbol{RepoID: repoID},
+		&RepoSymbolStatus{RepoID: repoID},
+		&RepoTrending{RepoID: repoID},
 		&RepoUnit{RepoID: repoID},
+		&RepoVulnerabilitySetting{RepoID: repoID},
+		&Secret{RepoID: repoID},
 		&Star{RepoID: repoID},
+		&StarListRepo{RepoID: repoID},
 		&Task{RepoID: repoID},
+		&VulnerabilityAlert{RepoID: repoID},
 		&Watch{RepoID: repoID},
 		&Webhook{RepoID: repoID},
 	); err != nil {
@@ -1528,6 +1565,11 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
+	// Delete staging sessions and their changes
+	if err := deleteStagingSessionsByRepoID(sess, repoID); err != nil {
+		return err
+	}
+
 	// Delete Issues and related objects
 	var attachmentPaths []string
 	if attachmentPaths, err = deleteIssuesByRepoID(sess, repoID); err != nil {
@@ -1625,7 +1667,9 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 
 	var newAttachmentPaths = make([]string, 0, len(newAttachments))
 	for _, attach := range newAttachments {
-		newAttachmentPaths = append(newAttachmentPaths, attach.RelativePath())
+		if !attach.IsDeduplicated() {
+			newAttachmentPaths = append(newAttachmentPaths, attach.RelativePath())
+		}
 	}
 
 	if _, err := sess.Where("repo_id=?", repo.ID).Delete(new(Attachment)); err != nil {
@@ -1663,16 +1707,19 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 	// Remove issue attachment files.
 	for i := range attachmentPaths {
 		RemoveStorageWithNotice(storage.Attachments, "Delete issue attachment", attachmentPaths[i])
+		RemoveAttachmentThumbnails(attachmentPaths[i])
 	}
 
 	// Remove release attachment files.
 	for i := range releaseAttachments {
 		RemoveStorageWithNotice(storage.Attachments, "Delete release attachment", releaseAttachments[i])
+		RemoveAttachmentThumbnails(releaseAttachments[i])
 	}
 
 	// Remove attachment with no issue_id and release_id.
 	for i := range newAttachmentPaths {
-		RemoveStorageWithNotice(storage.Attachments, "Delete issue attachment", attachmentPaths[i])
+		RemoveStorageWithNotice(storage.Attachments, "Delete issue attachment", newAttachmentPaths[i])
+		RemoveAttachmentThumbnails(newAttachmentPaths[i])
 	}
 
 	if len(repo.Avatar) > 0 {
@@ -1784,6 +1831,17 @@ func GetUserMirrorRepositories(userID int64) ([]*Repository, error) {
 		Find(&repos)
 }
 
+// GetOwnerRepositoriesByNames returns the repositories of an owner with the given lower names,
+// or all of its repositories if no name is given
+func GetOwnerRepositoriesByNames(ownerID int64, lowerNames []string) ([]*Repository, error) {
+	sess := db.DefaultContext().Engine().Where("owner_id = ?", ownerID)
+	if len(lowerNames) > 0 {
+		sess = sess.In("lower_name", lowerNames)
+	}
+	repos := make([]*Repository, 0, 10)
+	return repos, sess.Asc("lower_name").Find(&repos)
+}
+
 func getRepositoryCount(e db.Engine, u *User) (int64, error) {
 	return e.Count(&Repository{OwnerID: u.ID})
 }
@@ -0,0 +1,107 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ErrRepoAutoReleaseNotExist indicates an auto release rule not exist error
+var ErrRepoAutoReleaseNotExist = errors.New("Auto release rule does not exist")
+
+// RepoAutoRelease is the rule of a repository creating a tag and a release when the version
+// read from a file of its default branch changes
+type RepoAutoRelease struct {
+	ID       int64  `xorm:"pk autoincr"`
+	RepoID   int64  `xorm:"UNIQUE NOT NULL"`
+	FilePath string `xorm:"NOT NULL"`
+	// VersionPattern is a regular expression whose first group matches the version in the file,
+	// empty to read the "version" key of a JSON file or the first line of any other file
+	VersionPattern string `xorm:"TEXT"`
+	TagTemplate    string `xorm:"NOT NULL"`
+	TitleTemplate  string `xorm:"TEXT"`
+	NotesTemplate  string `xorm:"TEXT"`
+	IsDraft        bool   `xorm:"NOT NULL DEFAULT false"`
+	IsPrerelease   bool   `xorm:"NOT NULL DEFAULT false"`
+
+	// LastVersion is the version of the latest release, a release is only created once per version
+	LastVersion  string
+	LastTagName  string
+	LastCommitID string `xorm:"VARCHAR(40)"`
+	LastError    string `xorm:"TEXT"`
+
+	CreatedUnix      timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix      timeutil.TimeStamp `xorm:"updated"`
+	LastReleasedUnix timeutil.TimeStamp
+}
+
+func init() {
+	db.RegisterModel(new(RepoAutoRelease))
+}
+
+// GetRepoAutoRelease returns the auto release rule of a repository
+func GetRepoAutoRelease(repoID int64) (*RepoAutoRelease, error) {
+	rule := &RepoAutoRelease{}
+	has, err := db.DefaultContext().Engine().Where("repo_id = ?", repoID).Get(rule)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoAutoReleaseNotExist
+	}
+	return rule, nil
+}
+
+// CreateOrUpdateRepoAutoRelease stores the settings of the auto release rule of a repository,
+// the latest release of an existing rule is kept
+func CreateOrUpdateRepoAutoRelease(rule *RepoAutoRelease) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		existing := &RepoAutoRelease{}
+		has, err := e.Where("repo_id = ?", rule.RepoID).Get(existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			_, err = e.Insert(rule)
+			return err
+		}
+		rule.ID = existing.ID
+		rule.LastVersion = existing.LastVersion
+		rule.LastTagName = existing.LastTagName
+		rule.LastCommitID = existing.LastCommitID
+		rule.LastError = existing.LastError
+		rule.LastReleasedUnix = existing.LastReleasedUnix
+		_, err = e.ID(rule.ID).
+			Cols("file_path", "version_pattern", "tag_template", "title_template", "notes_template", "is_draft", "is_prerelease").
+			Update(rule)
+		return err
+	})
+}
+
+// UpdateRepoAutoReleaseResult stores the outcome of the latest change of the version of an auto release rule
+func UpdateRepoAutoReleaseResult(rule *RepoAutoRelease) error {
+	cols := []string{"last_error"}
+	if rule.LastError == "" {
+		rule.LastReleasedUnix = timeutil.TimeStampNow()
+		cols = append(cols, "last_version", "last_tag_name", "last_commit_id", "last_released_unix")
+	}
+	_, err := db.DefaultContext().Engine().ID(rule.ID).Cols(cols...).Update(rule)
+	return err
+}
+
+// DeleteRepoAutoRelease deletes the auto release rule of a repository
+func DeleteRepoAutoRelease(repoID int64) error {
+	affected, err := db.DefaultContext().Engine().Where("repo_id = ?", repoID).Delete(&RepoAutoRelease{})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRepoAutoReleaseNotExist
+	}
+	return nil
+}
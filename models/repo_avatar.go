@@ -6,8 +6,8 @@ package models
 
 import (
 	"context"
-	"crypto/md5"
 	"fmt"
+	"image"
 	"image/png"
 	"io"
 	"strconv"
@@ -30,9 +30,9 @@ func (repo *Repository) generateRandomAvatar(e db.Engine) error {
 	idToString := fmt.Sprintf("%d", repo.ID)
 
 	seed := idToString
-	img, err := avatar.RandomImage([]byte(seed))
+	img, err := avatar.GeneratedImage([]byte(seed), repo.Name)
 	if err != nil {
-		return fmt.Errorf("RandomImage: %v", err)
+		return fmt.Errorf("GeneratedImage: %v", err)
 	}
 
 	repo.Avatar = idToString
@@ -119,12 +119,18 @@ func (repo *Repository) avatarLink(e db.Engine) string {
 // UploadAvatar saves custom avatar for repository.
 // FIXME: split uploads to different subdirs in case we have massive number of repos.
 func (repo *Repository) UploadAvatar(data []byte) error {
-	m, err := avatar.Prepare(data)
+	return repo.UploadCroppedAvatar(data, image.Rectangle{})
+}
+
+// UploadCroppedAvatar saves the given area of an image as custom avatar for repository.
+// The whole image is used if the area is empty.
+func (repo *Repository) UploadCroppedAvatar(data []byte, crop image.Rectangle) error {
+	m, err := avatar.PrepareWithCrop(data, crop)
 	if err != nil {
 		return err
 	}
 
-	newAvatar := fmt.Sprintf("%d-%x", repo.ID, md5.Sum(data))
+	newAvatar := fmt.Sprintf("%d-%x", repo.ID, avatarDataSum(data, crop))
 	if repo.Avatar == newAvatar { // upload the same picture
 		return nil
 	}
@@ -0,0 +1,89 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// BackportLabelPrefix is the prefix of the labels which request a backport of a merged pull request
+const BackportLabelPrefix = "backport/"
+
+var (
+	// ErrBackportMappingNotExist indicates a backport branch mapping not exist error
+	ErrBackportMappingNotExist = errors.New("Backport branch mapping does not exist")
+	// ErrBackportMappingAlreadyExist indicates a backport branch mapping for the same label exists already
+	ErrBackportMappingAlreadyExist = errors.New("Backport branch mapping already exists")
+)
+
+// BackportMapping maps a backport label to the branch merged pull requests with the label are backported to
+type BackportMapping struct {
+	ID           int64  `xorm:"pk autoincr"`
+	RepoID       int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Label        string `xorm:"UNIQUE(s) NOT NULL"`
+	TargetBranch string `xorm:"NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(BackportMapping))
+}
+
+// IsBackportLabel returns whether a label requests a backport
+func IsBackportLabel(name string) bool {
+	return strings.HasPrefix(name, BackportLabelPrefix) && len(name) > len(BackportLabelPrefix)
+}
+
+// CreateBackportMapping creates a backport branch mapping
+func CreateBackportMapping(m *BackportMapping) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		has, err := ctx.Engine().Exist(&BackportMapping{RepoID: m.RepoID, Label: m.Label})
+		if err != nil {
+			return err
+		}
+		if has {
+			return ErrBackportMappingAlreadyExist
+		}
+		_, err = ctx.Engine().Insert(m)
+		return err
+	})
+}
+
+// GetBackportMappings returns the backport branch mappings of a repository ordered by label
+func GetBackportMappings(repoID int64) ([]*BackportMapping, error) {
+	mappings := make([]*BackportMapping, 0, 5)
+	return mappings, db.DefaultContext().Engine().Where("repo_id = ?", repoID).Asc("label").Find(&mappings)
+}
+
+// GetBackportTargetBranch returns the branch a backport label maps to. Without a mapping a label
+// `backport/<branch>` maps to the branch named by its suffix.
+func GetBackportTargetBranch(repoID int64, label string) (string, error) {
+	m := &BackportMapping{}
+	has, err := db.DefaultContext().Engine().Where("repo_id = ? AND label = ?", repoID, label).Get(m)
+	if err != nil {
+		return "", err
+	}
+	if has {
+		return m.TargetBranch, nil
+	}
+	return strings.TrimPrefix(label, BackportLabelPrefix), nil
+}
+
+// DeleteBackportMapping deletes a backport branch mapping of a repository
+func DeleteBackportMapping(repoID, id int64) error {
+	affected, err := db.DefaultContext().Engine().Where("repo_id = ?", repoID).ID(id).Delete(&BackportMapping{})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrBackportMappingNotExist
+	}
+	return nil
+}
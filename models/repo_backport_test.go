@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBackportLabel(t *testing.T) {
+	assert.True(t, IsBackportLabel("backport/v1.15"))
+	assert.False(t, IsBackportLabel("backport/"))
+	assert.False(t, IsBackportLabel("bug"))
+}
+
+func TestBackportMapping(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	m := &BackportMapping{RepoID: 1, Label: "backport/v1.15", TargetBranch: "release/v1.15"}
+	assert.NoError(t, CreateBackportMapping(m))
+	assert.Equal(t, ErrBackportMappingAlreadyExist, CreateBackportMapping(&BackportMapping{RepoID: 1, Label: "backport/v1.15", TargetBranch: "other"}))
+
+	mappings, err := GetBackportMappings(1)
+	assert.NoError(t, err)
+	assert.Len(t, mappings, 1)
+
+	branch, err := GetBackportTargetBranch(1, "backport/v1.15")
+	assert.NoError(t, err)
+	assert.Equal(t, "release/v1.15", branch)
+	// without a mapping the label names the branch
+	branch, err = GetBackportTargetBranch(1, "backport/v1.14")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.14", branch)
+	branch, err = GetBackportTargetBranch(2, "backport/v1.15")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.15", branch)
+
+	assert.Equal(t, ErrBackportMappingNotExist, DeleteBackportMapping(2, m.ID))
+	assert.NoError(t, DeleteBackportMapping(1, m.ID))
+	db.AssertNotExistsBean(t, &BackportMapping{ID: m.ID})
+}
@@ -20,6 +20,7 @@ type Collaboration struct {
 	RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
 	UserID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
 	Mode        AccessMode         `xorm:"DEFAULT 2 NOT NULL"`
+	IsGuest     bool               `xorm:"NOT NULL DEFAULT false"`
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 }
@@ -28,6 +29,32 @@ func init() {
 	db.RegisterModel(new(Collaboration))
 }
 
+// GuestUnitTypes are the units of a private repository a guest collaborator may read
+var GuestUnitTypes = []UnitType{
+	UnitTypeIssues,
+	UnitTypePullRequests,
+	UnitTypeExternalTracker,
+	UnitTypeProjects,
+}
+
+// IsGuestUnitType returns true if guest collaborators may read the unit
+func IsGuestUnitType(unitType UnitType) bool {
+	for _, tp := range GuestUnitTypes {
+		if tp == unitType {
+			return true
+		}
+	}
+	return false
+}
+
+// Permission returns the name of the permission of the collaboration
+func (c *Collaboration) Permission() string {
+	if c.IsGuest {
+		return "guest"
+	}
+	return c.Mode.String()
+}
+
 func (repo *Repository) addCollaborator(e db.Engine, u *User) error {
 	collaboration := &Collaboration{
 		RepoID: repo.ID,
@@ -124,6 +151,11 @@ func (repo *Repository) getCollaboration(e db.Engine, uid int64) (*Collaboration
 	return collaboration, err
 }
 
+// GetCollaboration returns the collaboration of a user with the repository, or nil if the user is no collaborator
+func (repo *Repository) GetCollaboration(uid int64) (*Collaboration, error) {
+	return repo.getCollaboration(db.DefaultContext().Engine(), uid)
+}
+
 func (repo *Repository) isCollaborator(e db.Engine, userID int64) (bool, error) {
 	return e.Get(&Collaboration{RepoID: repo.ID, UserID: userID})
 }
@@ -150,14 +182,15 @@ func (repo *Repository) changeCollaborationAccessMode(e db.Engine, uid int64, mo
 		return nil
 	}
 
-	if collaboration.Mode == mode {
+	if collaboration.Mode == mode && !collaboration.IsGuest {
 		return nil
 	}
 	collaboration.Mode = mode
+	collaboration.IsGuest = false
 
 	if _, err = e.
 		ID(collaboration.ID).
-		Cols("mode").
+		Cols("mode", "is_guest").
 		Update(collaboration); err != nil {
 		return fmt.Errorf("update collaboration: %v", err)
 	} else if _, err = e.Exec("UPDATE access SET mode = ? WHERE user_id = ? AND repo_id = ?", mode, uid, repo.ID); err != nil {
@@ -182,6 +215,41 @@ func (repo *Repository) ChangeCollaborationAccessMode(uid int64, mode AccessMode
 	return sess.Commit()
 }
 
+func (repo *Repository) changeCollaborationToGuest(e db.Engine, uid int64) error {
+	collaboration, err := repo.getCollaboration(e, uid)
+	if err != nil {
+		return fmt.Errorf("get collaboration: %v", err)
+	} else if collaboration == nil || collaboration.IsGuest {
+		return nil
+	}
+
+	collaboration.Mode = AccessModeRead
+	collaboration.IsGuest = true
+	if _, err = e.
+		ID(collaboration.ID).
+		Cols("mode", "is_guest").
+		Update(collaboration); err != nil {
+		return fmt.Errorf("update collaboration: %v", err)
+	}
+	return repo.recalculateUserAccess(e, uid)
+}
+
+// ChangeCollaborationToGuest makes the collaborator a guest, who may only read
+// the issues and pull requests of a private repository but not its code.
+func (repo *Repository) ChangeCollaborationToGuest(uid int64) error {
+	sess := db.DefaultContext().NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if err := repo.changeCollaborationToGuest(sess, uid); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
 // DeleteCollaboration removes collaboration relation between the user and repository.
 func (repo *Repository) DeleteCollaboration(uid int64) (err error) {
 	collaboration := &Collaboration{
@@ -80,6 +80,31 @@ func TestRepository_ChangeCollaborationAccessMode(t *testing.T) {
 	CheckConsistencyFor(t, &Repository{ID: repo.ID})
 }
 
+func TestRepository_ChangeCollaborationToGuest(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 4}).(*Repository)
+	assert.NoError(t, repo.ChangeCollaborationToGuest(4))
+
+	collaboration := db.AssertExistsAndLoadBean(t, &Collaboration{RepoID: repo.ID, UserID: 4}).(*Collaboration)
+	assert.True(t, collaboration.IsGuest)
+	assert.EqualValues(t, AccessModeRead, collaboration.Mode)
+	assert.Equal(t, "guest", collaboration.Permission())
+
+	// read access to public repositories is not stored
+	db.AssertNotExistsBean(t, &Access{UserID: 4, RepoID: repo.ID})
+
+	// changing the access mode turns the guest into a regular collaborator
+	assert.NoError(t, repo.ChangeCollaborationAccessMode(4, AccessModeRead))
+	collaboration = db.AssertExistsAndLoadBean(t, &Collaboration{RepoID: repo.ID, UserID: 4}).(*Collaboration)
+	assert.False(t, collaboration.IsGuest)
+	assert.Equal(t, "read", collaboration.Permission())
+
+	assert.NoError(t, repo.ChangeCollaborationToGuest(db.NonexistentID))
+
+	CheckConsistencyFor(t, &Repository{ID: repo.ID})
+}
+
 func TestRepository_DeleteCollaboration(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -0,0 +1,101 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// dependencyInsertBatchSize is the number of dependencies inserted by a single statement
+const dependencyInsertBatchSize = 100
+
+// Package ecosystems of dependencies
+const (
+	DependencyEcosystemGo       = "go"
+	DependencyEcosystemNpm      = "npm"
+	DependencyEcosystemPyPI     = "pypi"
+	DependencyEcosystemComposer = "composer"
+)
+
+// Scopes of dependencies
+const (
+	DependencyScopeRuntime     = "runtime"
+	DependencyScopeDevelopment = "development"
+	DependencyScopeIndirect    = "indirect"
+)
+
+// RepoDependency represents a dependency declared by a manifest on the default branch of a repository
+type RepoDependency struct {
+	ID       int64  `xorm:"pk autoincr"`
+	RepoID   int64  `xorm:"INDEX NOT NULL"`
+	Manifest string `xorm:"TEXT NOT NULL"`
+	// Ecosystem is the package ecosystem of the dependency, e.g. go, npm, pypi or composer
+	Ecosystem string `xorm:"VARCHAR(20) INDEX NOT NULL"`
+	Name      string `xorm:"INDEX NOT NULL"`
+	// Version is the version or the version requirement as written in the manifest
+	Version string
+	// Scope is runtime, development or indirect
+	Scope string `xorm:"VARCHAR(20)"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoDependency))
+}
+
+// ReplaceRepoDependencies replaces the dependencies of the repository with the ones extracted from the given commit
+func (repo *Repository) ReplaceRepoDependencies(commitID string, deps []*RepoDependency) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Delete(&RepoDependency{RepoID: repo.ID}); err != nil {
+			return err
+		}
+		for _, dep := range deps {
+			dep.ID = 0
+			dep.RepoID = repo.ID
+		}
+		for i := 0; i < len(deps); i += dependencyInsertBatchSize {
+			end := i + dependencyInsertBatchSize
+			if end > len(deps) {
+				end = len(deps)
+			}
+			if _, err := e.Insert(deps[i:end]); err != nil {
+				return err
+			}
+		}
+		return repo.updateIndexerStatus(e, RepoIndexerTypeDependencies, commitID)
+	})
+}
+
+// FindRepoDependencyOptions represents the options to find dependencies of a repository
+type FindRepoDependencyOptions struct {
+	ListOptions
+	RepoID    int64
+	Ecosystem string
+	Manifest  string
+}
+
+func (opts *FindRepoDependencyOptions) toConds() builder.Cond {
+	cond := builder.NewCond().And(builder.Eq{"repo_id": opts.RepoID})
+	if opts.Ecosystem != "" {
+		cond = cond.And(builder.Eq{"ecosystem": opts.Ecosystem})
+	}
+	if opts.Manifest != "" {
+		cond = cond.And(builder.Eq{"manifest": opts.Manifest})
+	}
+	return cond
+}
+
+// FindRepoDependencies returns the dependencies of a repository matching the options and their total count
+func FindRepoDependencies(opts *FindRepoDependencyOptions) ([]*RepoDependency, int64, error) {
+	sess := db.DefaultContext().Engine().Where(opts.toConds()).Asc("ecosystem", "name", "id")
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, opts)
+	}
+	deps := make([]*RepoDependency, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&deps)
+	return deps, count, err
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoDependencies(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, repo.ReplaceRepoDependencies("65f1bf27bc3bf70f64657658635e66094edbcb4d", []*RepoDependency{
+		{Manifest: "go.mod", Ecosystem: DependencyEcosystemGo, Name: "xorm.io/xorm", Version: "v1.2.2", Scope: DependencyScopeRuntime},
+		{Manifest: "package.json", Ecosystem: DependencyEcosystemNpm, Name: "vue", Version: "^2.6.14", Scope: DependencyScopeRuntime},
+		{Manifest: "package.json", Ecosystem: DependencyEcosystemNpm, Name: "eslint", Version: "7.32.0", Scope: DependencyScopeDevelopment},
+	}))
+
+	status, err := repo.GetIndexerStatus(RepoIndexerTypeDependencies)
+	assert.NoError(t, err)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", status.CommitSha)
+
+	deps, count, err := FindRepoDependencies(&FindRepoDependencyOptions{RepoID: 1, Ecosystem: DependencyEcosystemNpm})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, deps, 2) {
+		assert.Equal(t, "eslint", deps[0].Name)
+		assert.Equal(t, "vue", deps[1].Name)
+	}
+
+	// the dependencies of a newer commit replace the old ones
+	assert.NoError(t, repo.ReplaceRepoDependencies("2a47ca4b614a9f5a43abbd5ad851a54a616ffee6", []*RepoDependency{
+		{Manifest: "go.mod", Ecosystem: DependencyEcosystemGo, Name: "xorm.io/xorm", Version: "v1.2.5", Scope: DependencyScopeRuntime},
+	}))
+	deps, count, err = FindRepoDependencies(&FindRepoDependencyOptions{RepoID: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, deps, 1) {
+		assert.Equal(t, "v1.2.5", deps[0].Version)
+	}
+	status, err = repo.GetIndexerStatus(RepoIndexerTypeDependencies)
+	assert.NoError(t, err)
+	assert.Equal(t, "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6", status.CommitSha)
+}
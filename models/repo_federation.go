@@ -0,0 +1,266 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RepoFederation represents the opt-in of a repository to interactions of users of other instances
+type RepoFederation struct {
+	ID            int64              `xorm:"pk autoincr"`
+	RepoID        int64              `xorm:"UNIQUE NOT NULL"`
+	AllowIssues   bool               `xorm:"NOT NULL DEFAULT false"`
+	AllowComments bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+}
+
+// RemoteStar represents a repository starred by a remote actor
+type RemoteStar struct {
+	ID            int64        `xorm:"pk autoincr"`
+	RepoID        int64        `xorm:"UNIQUE(s) NOT NULL"`
+	RemoteActorID int64        `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RemoteActor   *RemoteActor `xorm:"-"`
+	// ActivityURI is the ID of the activity which starred the repository, the remote instance refers to it when undoing it
+	ActivityURI string             `xorm:"VARCHAR(255)"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// FederatedObject links an issue or a comment to the remote object it was created from
+type FederatedObject struct {
+	ID            int64              `xorm:"pk autoincr"`
+	RepoID        int64              `xorm:"INDEX NOT NULL"`
+	RemoteActorID int64              `xorm:"INDEX NOT NULL"`
+	URI           string             `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+	IssueID       int64              `xorm:"INDEX NOT NULL"`
+	CommentID     int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+}
+
+// FederationBlock represents a remote actor or a domain whose interactions with a repository are rejected
+type FederationBlock struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"UNIQUE(s) NOT NULL"`
+	// RemoteActorID is 0 if the whole domain is blocked
+	RemoteActorID int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	RemoteActor   *RemoteActor       `xorm:"-"`
+	Domain        string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL DEFAULT ''"`
+	DoerID        int64              `xorm:"NOT NULL"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoFederation))
+	db.RegisterModel(new(RemoteStar))
+	db.RegisterModel(new(FederatedObject))
+	db.RegisterModel(new(FederationBlock))
+}
+
+// ErrFederationBlockNotExist represents a "FederationBlockNotExist" kind of error.
+type ErrFederationBlockNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrFederationBlockNotExist checks if an error is a ErrFederationBlockNotExist.
+func IsErrFederationBlockNotExist(err error) bool {
+	_, ok := err.(ErrFederationBlockNotExist)
+	return ok
+}
+
+func (err ErrFederationBlockNotExist) Error() string {
+	return fmt.Sprintf("federation block does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// ErrFederationBlockAlreadyExist represents a "FederationBlockAlreadyExist" kind of error.
+type ErrFederationBlockAlreadyExist struct {
+	RepoID        int64
+	RemoteActorID int64
+	Domain        string
+}
+
+// IsErrFederationBlockAlreadyExist checks if an error is a ErrFederationBlockAlreadyExist.
+func IsErrFederationBlockAlreadyExist(err error) bool {
+	_, ok := err.(ErrFederationBlockAlreadyExist)
+	return ok
+}
+
+func (err ErrFederationBlockAlreadyExist) Error() string {
+	return fmt.Sprintf("federation block already exists [repo_id: %d, remote_actor_id: %d, domain: %s]", err.RepoID, err.RemoteActorID, err.Domain)
+}
+
+// GetRepoFederation returns the federation settings of a repository, or nil if it did not opt in
+func GetRepoFederation(repoID int64) (*RepoFederation, error) {
+	settings := &RepoFederation{RepoID: repoID}
+	has, err := db.DefaultContext().Engine().Get(settings)
+	if err != nil || !has {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SetRepoFederation opts a repository in to federation or updates its settings
+func SetRepoFederation(settings *RepoFederation) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		existing := &RepoFederation{RepoID: settings.RepoID}
+		has, err := e.Get(existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			_, err = e.Insert(settings)
+			return err
+		}
+		settings.ID = existing.ID
+		settings.CreatedUnix = existing.CreatedUnix
+		_, err = e.ID(settings.ID).Cols("allow_issues", "allow_comments").Update(settings)
+		return err
+	})
+}
+
+// DeleteRepoFederation opts a repository out of federation, the stars of remote actors are removed
+func DeleteRepoFederation(repoID int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		return deleteBeans(ctx.Engine(),
+			&RepoFederation{RepoID: repoID},
+			&RemoteStar{RepoID: repoID},
+		)
+	})
+}
+
+// AddRemoteStar records a repository starred by a remote actor
+func AddRemoteStar(repoID, remoteActorID int64, activityURI string) error {
+	star := &RemoteStar{RepoID: repoID, RemoteActorID: remoteActorID}
+	return db.WithTx(func(ctx *db.Context) error {
+		has, err := ctx.Engine().Exist(star)
+		if err != nil || has {
+			return err
+		}
+		star.ActivityURI = activityURI
+		_, err = ctx.Engine().Insert(star)
+		return err
+	})
+}
+
+// RemoveRemoteStar removes the star of a remote actor from a repository
+func RemoveRemoteStar(repoID, remoteActorID int64) error {
+	_, err := db.DefaultContext().Engine().Delete(&RemoteStar{RepoID: repoID, RemoteActorID: remoteActorID})
+	return err
+}
+
+// GetRemoteStars returns the stars of remote actors of a repository, last starred first
+func GetRemoteStars(repoID int64, listOptions ListOptions) ([]*RemoteStar, int64, error) {
+	sess := db.DefaultContext().Engine().Where("repo_id = ?", repoID).Desc("id")
+	if listOptions.Page > 0 {
+		sess = setSessionPagination(sess, &listOptions)
+	}
+	stars := make([]*RemoteStar, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&stars)
+	return stars, count, err
+}
+
+// CountRemoteStars returns the number of remote actors who starred a repository
+func CountRemoteStars(repoID int64) (int64, error) {
+	return db.DefaultContext().Engine().Count(&RemoteStar{RepoID: repoID})
+}
+
+// LoadRemoteActor loads the remote actor who starred the repository
+func (star *RemoteStar) LoadRemoteActor() (err error) {
+	if star.RemoteActor == nil {
+		star.RemoteActor, err = GetRemoteActorByID(star.RemoteActorID)
+	}
+	return err
+}
+
+// GetFederatedObjectByURI returns the issue or comment created from a remote object
+func GetFederatedObjectByURI(uri string) (*FederatedObject, error) {
+	object := &FederatedObject{URI: uri}
+	has, err := db.DefaultContext().Engine().Get(object)
+	if err != nil || !has {
+		return nil, err
+	}
+	return object, nil
+}
+
+// InsertFederatedObject links an issue or a comment to the remote object it was created from
+func InsertFederatedObject(object *FederatedObject) error {
+	_, err := db.DefaultContext().Engine().Insert(object)
+	return err
+}
+
+// IsFederationBlocked returns whether a remote actor or its domain is blocked from interacting with a repository
+func IsFederationBlocked(repoID int64, actor *RemoteActor) (bool, error) {
+	return db.DefaultContext().Engine().
+		Where("repo_id = ? AND (remote_actor_id = ? OR (remote_actor_id = 0 AND domain = ?))", repoID, actor.ID, strings.ToLower(actor.Host)).
+		Exist(new(FederationBlock))
+}
+
+// CreateFederationBlock blocks a remote actor or a domain from interacting with a repository.
+// The stars of the blocked actors are removed.
+func CreateFederationBlock(block *FederationBlock) error {
+	block.Domain = strings.ToLower(block.Domain)
+	if block.RemoteActorID > 0 {
+		block.Domain = ""
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		has, err := e.Where("repo_id = ? AND remote_actor_id = ? AND domain = ?", block.RepoID, block.RemoteActorID, block.Domain).
+			Exist(new(FederationBlock))
+		if err != nil {
+			return err
+		} else if has {
+			return ErrFederationBlockAlreadyExist{RepoID: block.RepoID, RemoteActorID: block.RemoteActorID, Domain: block.Domain}
+		}
+		if _, err := e.Insert(block); err != nil {
+			return err
+		}
+
+		if block.RemoteActorID > 0 {
+			_, err = e.Delete(&RemoteStar{RepoID: block.RepoID, RemoteActorID: block.RemoteActorID})
+		} else {
+			_, err = e.Where("repo_id = ? AND remote_actor_id IN (SELECT id FROM remote_actor WHERE host = ?)", block.RepoID, block.Domain).
+				Delete(new(RemoteStar))
+		}
+		return err
+	})
+}
+
+// GetFederationBlocks returns the remote actors and domains blocked from interacting with a repository
+func GetFederationBlocks(repoID int64) ([]*FederationBlock, error) {
+	blocks := make([]*FederationBlock, 0, 10)
+	return blocks, db.DefaultContext().Engine().Where("repo_id = ?", repoID).Asc("id").Find(&blocks)
+}
+
+// GetFederationBlockByID returns a block of a repository
+func GetFederationBlockByID(repoID, id int64) (*FederationBlock, error) {
+	block := &FederationBlock{ID: id, RepoID: repoID}
+	has, err := db.DefaultContext().Engine().Get(block)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrFederationBlockNotExist{ID: id, RepoID: repoID}
+	}
+	return block, nil
+}
+
+// DeleteFederationBlock lifts a block of a repository
+func DeleteFederationBlock(block *FederationBlock) error {
+	_, err := db.DefaultContext().Engine().ID(block.ID).Delete(new(FederationBlock))
+	return err
+}
+
+// LoadRemoteActor loads the blocked remote actor
+func (block *FederationBlock) LoadRemoteActor() (err error) {
+	if block.RemoteActor == nil && block.RemoteActorID > 0 {
+		block.RemoteActor, err = GetRemoteActorByID(block.RemoteActorID)
+	}
+	return err
+}
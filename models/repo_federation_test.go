@@ -0,0 +1,100 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoFederation(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	settings, err := GetRepoFederation(1)
+	assert.NoError(t, err)
+	assert.Nil(t, settings)
+
+	assert.NoError(t, SetRepoFederation(&RepoFederation{RepoID: 1, AllowIssues: true}))
+	assert.NoError(t, SetRepoFederation(&RepoFederation{RepoID: 1, AllowComments: true}))
+	settings, err = GetRepoFederation(1)
+	assert.NoError(t, err)
+	if assert.NotNil(t, settings) {
+		assert.False(t, settings.AllowIssues)
+		assert.True(t, settings.AllowComments)
+	}
+
+	actor := &RemoteActor{URI: "https://remote.example/users/alice", Inbox: "https://remote.example/inbox"}
+	assert.NoError(t, SaveRemoteActor(actor))
+	assert.NoError(t, AddRemoteStar(1, actor.ID, "https://remote.example/like/1"))
+	assert.NoError(t, AddRemoteStar(1, actor.ID, "https://remote.example/like/2"))
+	count, err := CountRemoteStars(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	// opting out removes the stars
+	assert.NoError(t, DeleteRepoFederation(1))
+	settings, err = GetRepoFederation(1)
+	assert.NoError(t, err)
+	assert.Nil(t, settings)
+	count, err = CountRemoteStars(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
+func TestFederationBlock(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	alice := &RemoteActor{URI: "https://remote.example/users/alice", Inbox: "https://remote.example/inbox"}
+	assert.NoError(t, SaveRemoteActor(alice))
+	bob := &RemoteActor{URI: "https://other.example/users/bob", Inbox: "https://other.example/inbox"}
+	assert.NoError(t, SaveRemoteActor(bob))
+	assert.NoError(t, AddRemoteStar(1, alice.ID, ""))
+	assert.NoError(t, AddRemoteStar(1, bob.ID, ""))
+
+	// blocking a domain removes the stars of its actors
+	block := &FederationBlock{RepoID: 1, Domain: "Remote.Example", DoerID: 2}
+	assert.NoError(t, CreateFederationBlock(block))
+	err := CreateFederationBlock(&FederationBlock{RepoID: 1, Domain: "remote.example", DoerID: 2})
+	assert.True(t, IsErrFederationBlockAlreadyExist(err))
+
+	blocked, err := IsFederationBlocked(1, alice)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+	blocked, err = IsFederationBlocked(1, bob)
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+	blocked, err = IsFederationBlocked(2, alice)
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+
+	stars, count, err := GetRemoteStars(1, ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, stars, 1) {
+		assert.EqualValues(t, bob.ID, stars[0].RemoteActorID)
+	}
+
+	// blocking an actor
+	assert.NoError(t, CreateFederationBlock(&FederationBlock{RepoID: 1, RemoteActorID: bob.ID, DoerID: 2}))
+	blocked, err = IsFederationBlocked(1, bob)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+	count, err = CountRemoteStars(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	blocks, err := GetFederationBlocks(1)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 2)
+
+	loaded, err := GetFederationBlockByID(1, block.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, DeleteFederationBlock(loaded))
+	_, err = GetFederationBlockByID(1, block.ID)
+	assert.True(t, IsErrFederationBlockNotExist(err))
+}
@@ -0,0 +1,289 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// HookScriptType represents the git hook a hook script is run in
+type HookScriptType string
+
+// HookScriptTypes
+const (
+	HookScriptPreReceive  HookScriptType = "pre-receive"
+	HookScriptPostReceive HookScriptType = "post-receive"
+)
+
+// IsValid returns whether a hook script can be run in the git hook
+func (t HookScriptType) IsValid() bool {
+	return t == HookScriptPreReceive || t == HookScriptPostReceive
+}
+
+// RepoHookScript represents a script run in the pre-receive or post-receive hook of a repository.
+// A script is only run after a site administrator approved it, changing its content revokes the approval.
+type RepoHookScript struct {
+	ID         int64          `xorm:"pk autoincr"`
+	RepoID     int64          `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	Repo       *Repository    `xorm:"-"`
+	Name       string         `xorm:"UNIQUE(s) NOT NULL"`
+	HookType   HookScriptType `xorm:"VARCHAR(20) NOT NULL"`
+	Content    string         `xorm:"LONGTEXT NOT NULL"`
+	IsActive   bool           `xorm:"NOT NULL DEFAULT true"`
+	Sort       int            `xorm:"NOT NULL DEFAULT 0"`
+	CreatorID  int64
+	Creator    *User `xorm:"-"`
+	IsApproved bool  `xorm:"INDEX NOT NULL DEFAULT false"`
+	ApproverID int64
+	Approver   *User `xorm:"-"`
+
+	ApprovedUnix timeutil.TimeStamp
+	CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoHookScript))
+}
+
+// ErrRepoHookScriptNotExist represents a "RepoHookScriptNotExist" kind of error.
+type ErrRepoHookScriptNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrRepoHookScriptNotExist checks if an error is a ErrRepoHookScriptNotExist.
+func IsErrRepoHookScriptNotExist(err error) bool {
+	_, ok := err.(ErrRepoHookScriptNotExist)
+	return ok
+}
+
+func (err ErrRepoHookScriptNotExist) Error() string {
+	return fmt.Sprintf("hook script does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// ErrRepoHookScriptAlreadyExist represents a "RepoHookScriptAlreadyExist" kind of error.
+type ErrRepoHookScriptAlreadyExist struct {
+	Name string
+}
+
+// IsErrRepoHookScriptAlreadyExist checks if an error is a ErrRepoHookScriptAlreadyExist.
+func IsErrRepoHookScriptAlreadyExist(err error) bool {
+	_, ok := err.(ErrRepoHookScriptAlreadyExist)
+	return ok
+}
+
+func (err ErrRepoHookScriptAlreadyExist) Error() string {
+	return fmt.Sprintf("hook script already exists [name: %s]", err.Name)
+}
+
+// ErrRepoHookScriptInvalid represents a "RepoHookScriptInvalid" kind of error.
+type ErrRepoHookScriptInvalid struct {
+	Reason string
+}
+
+// IsErrRepoHookScriptInvalid checks if an error is a ErrRepoHookScriptInvalid.
+func IsErrRepoHookScriptInvalid(err error) bool {
+	_, ok := err.(ErrRepoHookScriptInvalid)
+	return ok
+}
+
+func (err ErrRepoHookScriptInvalid) Error() string {
+	return fmt.Sprintf("hook script is invalid: %s", err.Reason)
+}
+
+func checkRepoHookScript(e db.Engine, script *RepoHookScript) error {
+	if script.Name == "" {
+		return ErrRepoHookScriptInvalid{Reason: "name must not be empty"}
+	}
+	if !script.HookType.IsValid() {
+		return ErrRepoHookScriptInvalid{Reason: fmt.Sprintf("unknown hook type %q", script.HookType)}
+	}
+	if strings.TrimSpace(script.Content) == "" {
+		return ErrRepoHookScriptInvalid{Reason: "content must not be empty"}
+	}
+	taken, err := e.Where("repo_id = ? AND id != ?", script.RepoID, script.ID).
+		And("lower(name) = ?", strings.ToLower(script.Name)).
+		Exist(new(RepoHookScript))
+	if err != nil {
+		return err
+	} else if taken {
+		return ErrRepoHookScriptAlreadyExist{Name: script.Name}
+	}
+	return nil
+}
+
+// CreateRepoHookScript creates a new hook script of a repository which has yet to be approved
+func CreateRepoHookScript(script *RepoHookScript) error {
+	script.Name = strings.TrimSpace(script.Name)
+	script.IsApproved = false
+	script.ApproverID = 0
+	script.ApprovedUnix = 0
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if err := checkRepoHookScript(e, script); err != nil {
+			return err
+		}
+		_, err := e.Insert(script)
+		return err
+	})
+}
+
+// UpdateRepoHookScript updates a hook script of a repository,
+// a change of its content or hook type revokes its approval
+func UpdateRepoHookScript(script *RepoHookScript) error {
+	script.Name = strings.TrimSpace(script.Name)
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		old, err := getRepoHookScriptByRepoID(e, script.RepoID, script.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkRepoHookScript(e, script); err != nil {
+			return err
+		}
+		if old.Content != script.Content || old.HookType != script.HookType {
+			script.IsApproved = false
+			script.ApproverID = 0
+			script.ApprovedUnix = 0
+			script.Approver = nil
+		}
+		_, err = e.ID(script.ID).
+			Cols("name", "hook_type", "content", "is_active", "sort", "is_approved", "approver_id", "approved_unix").
+			Update(script)
+		return err
+	})
+}
+
+// ApproveRepoHookScript approves or revokes the approval of a hook script by a site administrator
+func ApproveRepoHookScript(script *RepoHookScript, approver *User, approve bool) error {
+	script.IsApproved = approve
+	if approve {
+		script.ApproverID = approver.ID
+		script.Approver = approver
+		script.ApprovedUnix = timeutil.TimeStampNow()
+	} else {
+		script.ApproverID = 0
+		script.Approver = nil
+		script.ApprovedUnix = 0
+	}
+	_, err := db.DefaultContext().Engine().ID(script.ID).
+		Cols("is_approved", "approver_id", "approved_unix").
+		Update(script)
+	return err
+}
+
+func getRepoHookScriptByRepoID(e db.Engine, repoID, id int64) (*RepoHookScript, error) {
+	script := &RepoHookScript{ID: id, RepoID: repoID}
+	has, err := e.Get(script)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoHookScriptNotExist{ID: id, RepoID: repoID}
+	}
+	return script, nil
+}
+
+// GetRepoHookScriptByRepoID returns the hook script of a repository with the given ID
+func GetRepoHookScriptByRepoID(repoID, id int64) (*RepoHookScript, error) {
+	return getRepoHookScriptByRepoID(db.DefaultContext().Engine(), repoID, id)
+}
+
+// GetRepoHookScriptByID returns the hook script with the given ID
+func GetRepoHookScriptByID(id int64) (*RepoHookScript, error) {
+	script := new(RepoHookScript)
+	has, err := db.DefaultContext().Engine().ID(id).Get(script)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoHookScriptNotExist{ID: id}
+	}
+	return script, nil
+}
+
+// GetRepoHookScripts returns the hook scripts of a repository in the order they are run
+func GetRepoHookScripts(repoID int64) ([]*RepoHookScript, error) {
+	scripts := make([]*RepoHookScript, 0, 5)
+	return scripts, db.DefaultContext().Engine().
+		Where("repo_id = ?", repoID).
+		Asc("sort").
+		Asc("id").
+		Find(&scripts)
+}
+
+// GetRunnableRepoHookScripts returns the active and approved hook scripts of a repository
+// for a git hook in the order they are run
+func GetRunnableRepoHookScripts(repoID int64, hookType HookScriptType) ([]*RepoHookScript, error) {
+	scripts := make([]*RepoHookScript, 0, 5)
+	return scripts, db.DefaultContext().Engine().
+		Where("repo_id = ? AND hook_type = ? AND is_active = ? AND is_approved = ?", repoID, hookType, true, true).
+		Asc("sort").
+		Asc("id").
+		Find(&scripts)
+}
+
+// FindRepoHookScriptsOptions represents the options to find the hook scripts of all repositories
+type FindRepoHookScriptsOptions struct {
+	ListOptions
+	// IsApproved filters the scripts by their approval if not nil
+	IsApproved *bool
+}
+
+// FindRepoHookScripts returns the hook scripts of all repositories matching the options, last updated first
+func FindRepoHookScripts(opts *FindRepoHookScriptsOptions) ([]*RepoHookScript, int64, error) {
+	sess := db.DefaultContext().Engine().Desc("updated_unix")
+	if opts.IsApproved != nil {
+		sess = sess.Where("is_approved = ?", *opts.IsApproved)
+	}
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, &opts.ListOptions)
+	}
+
+	scripts := make([]*RepoHookScript, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&scripts)
+	return scripts, count, err
+}
+
+// DeleteRepoHookScript deletes a hook script of a repository
+func DeleteRepoHookScript(repoID, id int64) error {
+	deleted, err := db.DefaultContext().Engine().Delete(&RepoHookScript{ID: id, RepoID: repoID})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrRepoHookScriptNotExist{ID: id, RepoID: repoID}
+	}
+	return nil
+}
+
+// LoadAttributes loads the repository, the creator and the approver of a hook script
+func (script *RepoHookScript) LoadAttributes() (err error) {
+	e := db.DefaultContext().Engine()
+	if script.Repo == nil {
+		if script.Repo, err = getRepositoryByID(e, script.RepoID); err != nil {
+			return err
+		}
+	}
+	if script.Creator == nil && script.CreatorID > 0 {
+		if script.Creator, err = getUserByID(e, script.CreatorID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			script.Creator = NewGhostUser()
+		}
+	}
+	if script.Approver == nil && script.ApproverID > 0 {
+		if script.Approver, err = getUserByID(e, script.ApproverID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			script.Approver = NewGhostUser()
+		}
+	}
+	return nil
+}
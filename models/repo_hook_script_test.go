@@ -0,0 +1,76 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRunnableRepoHookScripts(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	scripts, err := GetRunnableRepoHookScripts(1, HookScriptPreReceive)
+	assert.NoError(t, err)
+	if assert.Len(t, scripts, 1) {
+		assert.EqualValues(t, 1, scripts[0].ID)
+	}
+
+	// not yet approved
+	scripts, err = GetRunnableRepoHookScripts(1, HookScriptPostReceive)
+	assert.NoError(t, err)
+	assert.Empty(t, scripts)
+}
+
+func TestCreateRepoHookScript(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	script := &RepoHookScript{RepoID: 1, Name: " lint ", HookType: HookScriptPreReceive, Content: "#!/bin/sh\n", IsApproved: true}
+	assert.NoError(t, CreateRepoHookScript(script))
+	assert.Equal(t, "lint", script.Name)
+	assert.False(t, script.IsApproved)
+	db.AssertExistsAndLoadBean(t, &RepoHookScript{ID: script.ID, RepoID: 1})
+
+	err := CreateRepoHookScript(&RepoHookScript{RepoID: 1, Name: "Notify", HookType: HookScriptPostReceive, Content: "#!/bin/sh\n"})
+	assert.True(t, IsErrRepoHookScriptAlreadyExist(err))
+	err = CreateRepoHookScript(&RepoHookScript{RepoID: 1, Name: "update", HookType: "update", Content: "#!/bin/sh\n"})
+	assert.True(t, IsErrRepoHookScriptInvalid(err))
+}
+
+func TestUpdateRepoHookScript(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	script, err := GetRepoHookScriptByRepoID(1, 1)
+	assert.NoError(t, err)
+	script.IsActive = false
+	assert.NoError(t, UpdateRepoHookScript(script))
+	script = db.AssertExistsAndLoadBean(t, &RepoHookScript{ID: 1}).(*RepoHookScript)
+	assert.False(t, script.IsActive)
+	assert.True(t, script.IsApproved)
+
+	// changing the content revokes the approval
+	script.Content = "#!/bin/sh\nexit 1\n"
+	assert.NoError(t, UpdateRepoHookScript(script))
+	script = db.AssertExistsAndLoadBean(t, &RepoHookScript{ID: 1}).(*RepoHookScript)
+	assert.False(t, script.IsApproved)
+	assert.EqualValues(t, 0, script.ApproverID)
+
+	approver := db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	assert.NoError(t, ApproveRepoHookScript(script, approver, true))
+	script = db.AssertExistsAndLoadBean(t, &RepoHookScript{ID: 1}).(*RepoHookScript)
+	assert.True(t, script.IsApproved)
+	assert.EqualValues(t, 1, script.ApproverID)
+}
+
+func TestDeleteRepoHookScript(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.True(t, IsErrRepoHookScriptNotExist(DeleteRepoHookScript(2, 1)))
+	assert.NoError(t, DeleteRepoHookScript(1, 1))
+	db.AssertNotExistsBean(t, &RepoHookScript{ID: 1})
+}
@@ -19,6 +19,8 @@ const (
 	RepoIndexerTypeCode RepoIndexerType = iota // 0
 	// RepoIndexerTypeStats repository stats indexer
 	RepoIndexerTypeStats // 1
+	// RepoIndexerTypeDependencies repository dependency analyzer
+	RepoIndexerTypeDependencies // 2
 )
 
 // RepoIndexerStatus status of a repo's entry in the repo indexer
@@ -0,0 +1,180 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// InteractionLimitType defines who may still open issues and comment while a repository is limited
+type InteractionLimitType int
+
+// Note: new type must append to the end of list to maintain compatibility.
+const (
+	InteractionLimitNone              InteractionLimitType = iota // everyone may interact
+	InteractionLimitCollaboratorsOnly                             // only collaborators and organization members
+	InteractionLimitContributorsOnly                              // collaborators and users who took part in an issue before the limit
+)
+
+var interactionLimitNames = map[InteractionLimitType]string{
+	InteractionLimitNone:              "none",
+	InteractionLimitCollaboratorsOnly: "collaborators_only",
+	InteractionLimitContributorsOnly:  "contributors_only",
+}
+
+// String returns the name of the limit
+func (l InteractionLimitType) String() string {
+	return interactionLimitNames[l]
+}
+
+// InteractionLimitTypeFromString returns the limit with the given name
+func InteractionLimitTypeFromString(name string) (InteractionLimitType, bool) {
+	for l, n := range interactionLimitNames {
+		if n == name {
+			return l, true
+		}
+	}
+	return InteractionLimitNone, false
+}
+
+// RepoInteractionLimit represents a temporary limit of the users who may open issues and comment in a repository
+type RepoInteractionLimit struct {
+	ID          int64                `xorm:"pk autoincr"`
+	RepoID      int64                `xorm:"UNIQUE NOT NULL"`
+	Type        InteractionLimitType `xorm:"NOT NULL DEFAULT 0"`
+	DoerID      int64                `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp   `xorm:"NOT NULL"`
+	ExpiresUnix timeutil.TimeStamp   `xorm:"INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoInteractionLimit))
+}
+
+// IsExpired returns true if the limit does not apply anymore
+func (l *RepoInteractionLimit) IsExpired() bool {
+	return l.ExpiresUnix <= timeutil.TimeStampNow()
+}
+
+// ErrRepoInteractionLimited represents an interaction which is not allowed by the interaction limit of a repository
+type ErrRepoInteractionLimited struct {
+	RepoID int64
+	UserID int64
+	Limit  InteractionLimitType
+}
+
+// IsErrRepoInteractionLimited checks if an error is a ErrRepoInteractionLimited.
+func IsErrRepoInteractionLimited(err error) bool {
+	_, ok := err.(ErrRepoInteractionLimited)
+	return ok
+}
+
+func (err ErrRepoInteractionLimited) Error() string {
+	return fmt.Sprintf("interactions are limited to %s [repo_id: %d, user_id: %d]", err.Limit, err.RepoID, err.UserID)
+}
+
+func getRepoInteractionLimit(e db.Engine, repoID int64) (*RepoInteractionLimit, error) {
+	limit := &RepoInteractionLimit{RepoID: repoID}
+	has, err := e.Get(limit)
+	if err != nil {
+		return nil, err
+	} else if !has || limit.IsExpired() {
+		return nil, nil
+	}
+	return limit, nil
+}
+
+// GetRepoInteractionLimit returns the active interaction limit of a repository, or nil if there is none
+func GetRepoInteractionLimit(repoID int64) (*RepoInteractionLimit, error) {
+	return getRepoInteractionLimit(db.DefaultContext().Engine(), repoID)
+}
+
+// SetRepoInteractionLimit limits the interactions with a repository for the given duration,
+// replacing the previous limit
+func SetRepoInteractionLimit(repo *Repository, doer *User, limitType InteractionLimitType, duration time.Duration) (*RepoInteractionLimit, error) {
+	now := time.Now()
+	limit := &RepoInteractionLimit{
+		RepoID:      repo.ID,
+		Type:        limitType,
+		DoerID:      doer.ID,
+		CreatedUnix: timeutil.TimeStamp(now.Unix()),
+		ExpiresUnix: timeutil.TimeStamp(now.Add(duration).Unix()),
+	}
+	return limit, db.WithTx(func(ctx *db.Context) error {
+		if _, err := ctx.Engine().Delete(&RepoInteractionLimit{RepoID: repo.ID}); err != nil {
+			return err
+		}
+		_, err := ctx.Engine().Insert(limit)
+		return err
+	})
+}
+
+// DeleteRepoInteractionLimit lifts the interaction limit of a repository
+func DeleteRepoInteractionLimit(repoID int64) error {
+	_, err := db.DefaultContext().Engine().Delete(&RepoInteractionLimit{RepoID: repoID})
+	return err
+}
+
+// isRepoCollaboratorOrMember returns true if the user has been granted access to the repository,
+// is a member of the organization owning it or is its owner
+func isRepoCollaboratorOrMember(e db.Engine, repo *Repository, user *User) (bool, error) {
+	if user.IsAdmin || user.ID == repo.OwnerID {
+		return true, nil
+	}
+	if isCollaborator, err := repo.isCollaborator(e, user.ID); err != nil || isCollaborator {
+		return isCollaborator, err
+	}
+	if hasAccess, err := e.Exist(&Access{UserID: user.ID, RepoID: repo.ID}); err != nil || hasAccess {
+		return hasAccess, err
+	}
+	if err := repo.getOwner(e); err != nil {
+		return false, err
+	}
+	if repo.Owner.IsOrganization() {
+		return isOrganizationMember(e, repo.OwnerID, user.ID)
+	}
+	return false, nil
+}
+
+// hasInteractedWithRepoBefore returns true if the user opened an issue or commented in the repository before the given time
+func hasInteractedWithRepoBefore(e db.Engine, repoID, userID int64, before timeutil.TimeStamp) (bool, error) {
+	has, err := e.Where("repo_id = ? AND poster_id = ? AND created_unix < ?", repoID, userID, before).Exist(new(Issue))
+	if err != nil || has {
+		return has, err
+	}
+	return e.Table("comment").
+		Join("INNER", "issue", "issue.id = comment.issue_id").
+		Where("issue.repo_id = ? AND comment.poster_id = ? AND comment.type = ? AND comment.created_unix < ?",
+			repoID, userID, CommentTypeComment, before).
+		Exist()
+}
+
+// CheckRepoInteractionLimit returns ErrRepoInteractionLimited if the active interaction limit
+// of the repository does not allow the user to open issues or comment
+func CheckRepoInteractionLimit(repo *Repository, user *User) error {
+	e := db.DefaultContext().Engine()
+	limit, err := getRepoInteractionLimit(e, repo.ID)
+	if err != nil || limit == nil || limit.Type == InteractionLimitNone {
+		return err
+	}
+
+	allowed, err := isRepoCollaboratorOrMember(e, repo, user)
+	if err != nil {
+		return err
+	}
+	if !allowed && limit.Type == InteractionLimitContributorsOnly {
+		if allowed, err = hasInteractedWithRepoBefore(e, repo.ID, user.ID, limit.CreatedUnix); err != nil {
+			return err
+		}
+	}
+	if !allowed {
+		return ErrRepoInteractionLimited{RepoID: repo.ID, UserID: user.ID, Limit: limit.Type}
+	}
+	return nil
+}
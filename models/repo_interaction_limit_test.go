@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRepoInteractionLimit(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 4}).(*Repository)
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+
+	limit, err := GetRepoInteractionLimit(repo.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, limit)
+
+	_, err = SetRepoInteractionLimit(repo, doer, InteractionLimitCollaboratorsOnly, time.Hour)
+	assert.NoError(t, err)
+	limit, err = GetRepoInteractionLimit(repo.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, limit) {
+		assert.EqualValues(t, InteractionLimitCollaboratorsOnly, limit.Type)
+		assert.EqualValues(t, doer.ID, limit.DoerID)
+	}
+
+	// setting a new limit replaces the previous one
+	_, err = SetRepoInteractionLimit(repo, doer, InteractionLimitContributorsOnly, -time.Hour)
+	assert.NoError(t, err)
+	db.AssertCount(t, &RepoInteractionLimit{RepoID: repo.ID}, 1)
+	limit, err = GetRepoInteractionLimit(repo.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, limit, "expired limits do not apply")
+
+	assert.NoError(t, DeleteRepoInteractionLimit(repo.ID))
+	db.AssertNotExistsBean(t, &RepoInteractionLimit{RepoID: repo.ID})
+}
+
+func TestCheckRepoInteractionLimit(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	owner := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	commenter := db.AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	outsider := db.AssertExistsAndLoadBean(t, &User{ID: 8}).(*User)
+
+	// without a limit everyone may interact
+	assert.NoError(t, CheckRepoInteractionLimit(repo, outsider))
+
+	_, err := SetRepoInteractionLimit(repo, owner, InteractionLimitCollaboratorsOnly, time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, CheckRepoInteractionLimit(repo, owner))
+	assert.True(t, IsErrRepoInteractionLimited(CheckRepoInteractionLimit(repo, commenter)))
+	assert.True(t, IsErrRepoInteractionLimited(CheckRepoInteractionLimit(repo, outsider)))
+
+	_, err = SetRepoInteractionLimit(repo, owner, InteractionLimitContributorsOnly, time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, CheckRepoInteractionLimit(repo, owner))
+	assert.NoError(t, CheckRepoInteractionLimit(repo, commenter))
+	assert.True(t, IsErrRepoInteractionLimited(CheckRepoInteractionLimit(repo, outsider)))
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// interactionTime is the time of the last issue or comment a user created in a repository
+type interactionTime struct {
+	PosterID int64
+	LastUnix timeutil.TimeStamp
+}
+
+// getRepoInteractions returns the time of the last issue or comment of every participant of a repository
+func getRepoInteractions(e db.Engine, repoID int64) (map[int64]timeutil.TimeStamp, error) {
+	interactions := make(map[int64]timeutil.TimeStamp)
+	merge := func(times []*interactionTime) {
+		for _, t := range times {
+			if t.LastUnix > interactions[t.PosterID] {
+				interactions[t.PosterID] = t.LastUnix
+			}
+		}
+	}
+
+	issueTimes := make([]*interactionTime, 0, 10)
+	if err := e.Table("issue").
+		Select("poster_id, MAX(created_unix) AS last_unix").
+		Where("repo_id = ?", repoID).
+		GroupBy("poster_id").
+		Find(&issueTimes); err != nil {
+		return nil, err
+	}
+	merge(issueTimes)
+
+	commentTimes := make([]*interactionTime, 0, 10)
+	if err := e.Table("comment").
+		Select("comment.poster_id AS poster_id, MAX(comment.created_unix) AS last_unix").
+		Join("INNER", "issue", "issue.id = comment.issue_id").
+		Where("issue.repo_id = ?", repoID).
+		GroupBy("comment.poster_id").
+		Find(&commentTimes); err != nil {
+		return nil, err
+	}
+	merge(commentTimes)
+
+	return interactions, nil
+}
+
+// GetMentionableUsers returns up to limit users who may be mentioned in the issues of a repository and whose
+// name starts with or full name contains keyword. Candidates are the users with read access and, for public
+// repositories, everyone who took part in an issue. Users who interacted recently are listed first.
+func GetMentionableUsers(repo *Repository, keyword string, limit int) ([]*User, error) {
+	e := db.DefaultContext().Engine()
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+
+	interactions, err := getRepoInteractions(e, repo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaboratorIDs := make([]int64, 0, 10)
+	if err := e.Table("access").Cols("user_id").
+		Where("repo_id = ? AND mode >= ?", repo.ID, AccessModeRead).
+		Find(&collaboratorIDs); err != nil {
+		return nil, err
+	}
+	if !repo.Owner.IsOrganization() {
+		collaboratorIDs = append(collaboratorIDs, repo.OwnerID)
+	}
+
+	cond := builder.In("id", collaboratorIDs)
+	if !repo.IsPrivate && repo.Owner.Visibility != structs.VisibleTypePrivate {
+		// participants who are no collaborators should only be suggested if their profile is visible
+		participantIDs := make([]int64, 0, len(interactions))
+		for id := range interactions {
+			participantIDs = append(participantIDs, id)
+		}
+		cond = cond.Or(builder.In("id", participantIDs).
+			And(builder.In("visibility", structs.VisibleTypePublic, structs.VisibleTypeLimited)))
+	}
+	cond = builder.And(cond,
+		builder.Eq{"type": UserTypeIndividual, "is_active": true, "prohibit_login": false})
+	if len(keyword) > 0 {
+		lowerKeyword := strings.ToLower(keyword)
+		cond = cond.And(builder.Or(
+			builder.Like{"lower_name", lowerKeyword + "%"},
+			builder.Like{"LOWER(full_name)", lowerKeyword},
+		))
+	}
+
+	users := make([]*User, 0, limit)
+	if err := e.Where(cond).Find(&users); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		ti, tj := interactions[users[i].ID], interactions[users[j].ID]
+		if ti != tj {
+			return ti > tj
+		}
+		return users[i].LowerName < users[j].LowerName
+	})
+	if limit > 0 && len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+// GetMentionableTeams returns the teams with access to an organization repository which doer may mention and
+// whose name starts with keyword. Site admins and organization owners may mention every team,
+// other members only the teams they belong to.
+func GetMentionableTeams(repo *Repository, doer *User, keyword string) ([]*Team, error) {
+	e := db.DefaultContext().Engine()
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+	if doer == nil || !repo.Owner.IsOrganization() {
+		return []*Team{}, nil
+	}
+
+	sess := e.
+		Join("INNER", "team_repo", "team_repo.team_id = team.id").
+		Where("team.org_id = ?", repo.OwnerID).
+		And("team_repo.repo_id = ?", repo.ID)
+	if !doer.IsAdmin {
+		isOwner, err := isOrganizationOwner(e, repo.OwnerID, doer.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !isOwner {
+			sess = sess.And(builder.In("team.id", builder.Select("team_id").From("team_user").Where(builder.Eq{"uid": doer.ID})))
+		}
+	}
+	if len(keyword) > 0 {
+		sess = sess.And(builder.Like{"team.lower_name", strings.ToLower(keyword) + "%"})
+	}
+
+	teams := make([]*Team, 0, 5)
+	return teams, sess.Asc("team.lower_name").Find(&teams)
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMentionableUsers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	users, err := GetMentionableUsers(repo, "", 10)
+	assert.NoError(t, err)
+	// most recent participants first, organizations are never listed
+	if assert.Len(t, users, 3) {
+		assert.EqualValues(t, 1, users[0].ID)
+		assert.EqualValues(t, 2, users[1].ID)
+		assert.EqualValues(t, 5, users[2].ID)
+	}
+
+	users, err = GetMentionableUsers(repo, "", 1)
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	users, err = GetMentionableUsers(repo, "USER5", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, users, 1) {
+		assert.EqualValues(t, 5, users[0].ID)
+	}
+
+	// private repository: only users with access
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	users, err = GetMentionableUsers(repo, "", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.EqualValues(t, 2, users[0].ID)
+		assert.EqualValues(t, 4, users[1].ID)
+	}
+}
+
+func TestGetMentionableTeams(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+
+	// organization owner
+	owner := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	teams, err := GetMentionableTeams(repo, owner, "")
+	assert.NoError(t, err)
+	assert.Len(t, teams, 2)
+
+	// member of team1 only
+	member := db.AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	teams, err = GetMentionableTeams(repo, member, "")
+	assert.NoError(t, err)
+	if assert.Len(t, teams, 1) {
+		assert.EqualValues(t, 2, teams[0].ID)
+	}
+
+	teams, err = GetMentionableTeams(repo, owner, "own")
+	assert.NoError(t, err)
+	if assert.Len(t, teams, 1) {
+		assert.EqualValues(t, 1, teams[0].ID)
+	}
+
+	// no teams for repositories of users
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	teams, err = GetMentionableTeams(repo, owner, "")
+	assert.NoError(t, err)
+	assert.Len(t, teams, 0)
+}
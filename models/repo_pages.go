@@ -0,0 +1,180 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrRepoPagesNotExist indicates a pages site not exist error
+var ErrRepoPagesNotExist = errors.New("Pages site does not exist")
+
+// RepoPagesStatus is the progress of publishing a pages site
+type RepoPagesStatus int
+
+// Note: new status must append to the end of list to maintain compatibility.
+const (
+	RepoPagesStatusQueued RepoPagesStatus = iota
+	RepoPagesStatusPublished
+	RepoPagesStatusFailed
+)
+
+var repoPagesStatusNames = map[RepoPagesStatus]string{
+	RepoPagesStatusQueued:    "queued",
+	RepoPagesStatusPublished: "published",
+	RepoPagesStatusFailed:    "failed",
+}
+
+// String returns the name of the status
+func (s RepoPagesStatus) String() string {
+	return repoPagesStatusNames[s]
+}
+
+// RepoPages is the static website published from a directory of a branch of a repository
+type RepoPages struct {
+	ID     int64  `xorm:"pk autoincr"`
+	RepoID int64  `xorm:"UNIQUE NOT NULL"`
+	Branch string `xorm:"NOT NULL"`
+	// Directory is the directory of the branch which is the root of the site, empty for the root of the branch
+	Directory    string
+	CustomDomain string `xorm:"INDEX"`
+	// CustomDomainVerificationCode is the value of the TXT record proving the repository controls its custom domain
+	CustomDomainVerificationCode string `xorm:"VARCHAR(40)"`
+	// CustomDomainVerified is whether the custom domain is verified, the site is only served at it once it is
+	CustomDomainVerified bool            `xorm:"NOT NULL DEFAULT false"`
+	Status               RepoPagesStatus `xorm:"NOT NULL DEFAULT 0"`
+	// CommitID is the commit the site is served from, empty until it is published the first time
+	CommitID string `xorm:"VARCHAR(40)"`
+	Error    string `xorm:"TEXT"`
+
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	PublishedUnix timeutil.TimeStamp
+}
+
+func init() {
+	db.RegisterModel(new(RepoPages))
+}
+
+// HTMLURL returns the URL the site is served at, its custom domain if it has a verified one
+func (pages *RepoPages) HTMLURL(repo *Repository) string {
+	scheme := "http"
+	if u, err := url.Parse(setting.AppURL); err == nil && u.Scheme != "" {
+		scheme = u.Scheme
+	}
+	if pages.CustomDomain != "" && pages.CustomDomainVerified && setting.Pages.AllowCustomDomains {
+		return scheme + "://" + pages.CustomDomain + "/"
+	}
+	return scheme + "://" + strings.ToLower(repo.OwnerName) + "." + setting.Pages.Domain + "/" + url.PathEscape(repo.Name) + "/"
+}
+
+// CustomDomainChallengeRecord returns the name of the TXT record of the custom domain which has to contain the verification code
+func (pages *RepoPages) CustomDomainChallengeRecord() string {
+	return "_gitea-pages-challenge." + pages.CustomDomain
+}
+
+// GetRepoPages returns the pages site of a repository
+func GetRepoPages(repoID int64) (*RepoPages, error) {
+	pages := &RepoPages{}
+	has, err := db.DefaultContext().Engine().Where("repo_id = ?", repoID).Get(pages)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoPagesNotExist
+	}
+	return pages, nil
+}
+
+// GetRepoPagesByCustomDomain returns the pages site served at a custom domain, which has to be verified
+func GetRepoPagesByCustomDomain(domain string) (*RepoPages, error) {
+	domain = strings.ToLower(domain)
+	if domain == "" {
+		return nil, ErrRepoPagesNotExist
+	}
+	pages := &RepoPages{}
+	has, err := db.DefaultContext().Engine().Where("custom_domain = ? AND custom_domain_verified = ?", domain, true).Get(pages)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoPagesNotExist
+	}
+	return pages, nil
+}
+
+// CreateOrUpdateRepoPages stores the settings of the pages site of a repository and queues publishing it.
+// A changed custom domain has to be verified again.
+func CreateOrUpdateRepoPages(pages *RepoPages) error {
+	pages.CustomDomain = strings.ToLower(pages.CustomDomain)
+	pages.Status = RepoPagesStatusQueued
+	pages.Error = ""
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		existing := &RepoPages{}
+		has, err := e.Where("repo_id = ?", pages.RepoID).Get(existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			if pages.CustomDomainVerificationCode, err = util.RandomString(40); err != nil {
+				return err
+			}
+			pages.CustomDomainVerified = false
+			_, err = e.Insert(pages)
+			return err
+		}
+		pages.ID = existing.ID
+		pages.CustomDomainVerificationCode = existing.CustomDomainVerificationCode
+		pages.CustomDomainVerified = existing.CustomDomainVerified && pages.CustomDomain == existing.CustomDomain
+		pages.CommitID = existing.CommitID
+		pages.PublishedUnix = existing.PublishedUnix
+		_, err = e.ID(pages.ID).Cols("branch", "directory", "custom_domain", "custom_domain_verified", "status", "error").Update(pages)
+		return err
+	})
+}
+
+// VerifyRepoPagesCustomDomain marks the custom domain of a pages site as verified,
+// other repositories which claim the domain have to verify it again
+func VerifyRepoPagesCustomDomain(pages *RepoPages) error {
+	pages.CustomDomainVerified = true
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Where("custom_domain = ? AND repo_id != ?", pages.CustomDomain, pages.RepoID).
+			Cols("custom_domain_verified").Update(&RepoPages{CustomDomainVerified: false}); err != nil {
+			return err
+		}
+		_, err := e.ID(pages.ID).Cols("custom_domain_verified").Update(pages)
+		return err
+	})
+}
+
+// UpdateRepoPagesStatus stores the progress of publishing a pages site
+func UpdateRepoPagesStatus(pages *RepoPages) error {
+	cols := []string{"status", "error"}
+	if pages.Status == RepoPagesStatusPublished {
+		pages.PublishedUnix = timeutil.TimeStampNow()
+		cols = append(cols, "commit_id", "published_unix")
+	}
+	_, err := db.DefaultContext().Engine().ID(pages.ID).Cols(cols...).Update(pages)
+	return err
+}
+
+// DeleteRepoPages deletes the pages site of a repository
+func DeleteRepoPages(repoID int64) error {
+	affected, err := db.DefaultContext().Engine().Where("repo_id = ?", repoID).Delete(&RepoPages{})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRepoPagesNotExist
+	}
+	return nil
+}
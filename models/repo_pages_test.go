@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoPages(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	_, err := GetRepoPages(1)
+	assert.Equal(t, ErrRepoPagesNotExist, err)
+
+	assert.NoError(t, CreateOrUpdateRepoPages(&RepoPages{RepoID: 1, Branch: "master", CustomDomain: "Docs.Example.com"}))
+	pages, err := GetRepoPages(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "docs.example.com", pages.CustomDomain)
+	assert.Len(t, pages.CustomDomainVerificationCode, 40)
+	assert.False(t, pages.CustomDomainVerified)
+	assert.Equal(t, RepoPagesStatusQueued, pages.Status)
+
+	// unverified custom domains are not served
+	_, err = GetRepoPagesByCustomDomain("docs.example.com")
+	assert.Equal(t, ErrRepoPagesNotExist, err)
+	assert.NoError(t, VerifyRepoPagesCustomDomain(pages))
+	pages, err = GetRepoPagesByCustomDomain("docs.example.com")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, pages.RepoID)
+
+	// claiming the domain of another repository does not take it over until it is verified
+	assert.NoError(t, CreateOrUpdateRepoPages(&RepoPages{RepoID: 2, Branch: "master", CustomDomain: "docs.example.com"}))
+	pages2, err := GetRepoPages(2)
+	assert.NoError(t, err)
+	assert.NotEqual(t, pages.CustomDomainVerificationCode, pages2.CustomDomainVerificationCode)
+	pages, err = GetRepoPagesByCustomDomain("docs.example.com")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, pages.RepoID)
+	assert.NoError(t, VerifyRepoPagesCustomDomain(pages2))
+	pages, err = GetRepoPagesByCustomDomain("docs.example.com")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, pages.RepoID)
+	pages, err = GetRepoPages(1)
+	assert.NoError(t, err)
+	assert.False(t, pages.CustomDomainVerified)
+	assert.NoError(t, DeleteRepoPages(2))
+	assert.NoError(t, VerifyRepoPagesCustomDomain(pages))
+
+	// the verification is kept as long as the custom domain is unchanged
+	assert.NoError(t, CreateOrUpdateRepoPages(&RepoPages{RepoID: 1, Branch: "master", CustomDomain: "docs.example.com"}))
+	pages, err = GetRepoPages(1)
+	assert.NoError(t, err)
+	assert.True(t, pages.CustomDomainVerified)
+
+	pages.Status = RepoPagesStatusPublished
+	pages.CommitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	assert.NoError(t, UpdateRepoPagesStatus(pages))
+
+	assert.NoError(t, CreateOrUpdateRepoPages(&RepoPages{RepoID: 1, Branch: "develop", Directory: "docs"}))
+	pages, err = GetRepoPages(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "develop", pages.Branch)
+	assert.Equal(t, "docs", pages.Directory)
+	assert.Empty(t, pages.CustomDomain)
+	assert.Equal(t, RepoPagesStatusQueued, pages.Status)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", pages.CommitID)
+	assert.NotZero(t, pages.PublishedUnix)
+
+	_, err = GetRepoPagesByCustomDomain("docs.example.com")
+	assert.Equal(t, ErrRepoPagesNotExist, err)
+
+	assert.NoError(t, DeleteRepoPages(1))
+	assert.Equal(t, ErrRepoPagesNotExist, DeleteRepoPages(1))
+}
@@ -165,13 +165,16 @@ func getUserRepoPermission(e db.Engine, repo *Repository, user *User) (perm Perm
 		return
 	}
 
-	var isCollaborator bool
+	var collaboration *Collaboration
 	if user != nil {
-		isCollaborator, err = repo.isCollaborator(e, user.ID)
+		collaboration, err = repo.getCollaboration(e, user.ID)
 		if err != nil {
 			return perm, err
 		}
 	}
+	isCollaborator := collaboration != nil
+	// guests may only read some units of private repositories
+	isGuest := isCollaborator && collaboration.IsGuest && repo.IsPrivate
 
 	if err = repo.getOwner(e); err != nil {
 		return
@@ -212,6 +215,16 @@ func getUserRepoPermission(e db.Engine, repo *Repository, user *User) (perm Perm
 		return
 	}
 	if !repo.Owner.IsOrganization() {
+		if isGuest {
+			perm.UnitsMode = make(map[UnitType]AccessMode)
+			perm.Units = make([]*RepoUnit, 0, len(GuestUnitTypes))
+			for _, u := range repo.Units {
+				if IsGuestUnitType(u.Type) {
+					perm.UnitsMode[u.Type] = AccessModeRead
+					perm.Units = append(perm.Units, u)
+				}
+			}
+		}
 		return
 	}
 
@@ -220,7 +233,11 @@ func getUserRepoPermission(e db.Engine, repo *Repository, user *User) (perm Perm
 	// Collaborators on organization
 	if isCollaborator {
 		for _, u := range repo.Units {
-			perm.UnitsMode[u.Type] = perm.AccessMode
+			if !isGuest {
+				perm.UnitsMode[u.Type] = perm.AccessMode
+			} else if IsGuestUnitType(u.Type) {
+				perm.UnitsMode[u.Type] = AccessModeRead
+			}
 		}
 	}
 
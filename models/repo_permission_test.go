@@ -97,6 +97,17 @@ func TestRepoPermissionPrivateNonOrgRepo(t *testing.T) {
 		assert.False(t, perm.CanWrite(unit.Type))
 	}
 
+	// guest collaborator
+	assert.NoError(t, repo.ChangeCollaborationToGuest(user.ID))
+	perm, err = GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.HasAccess())
+	for _, unit := range repo.Units {
+		assert.Equal(t, IsGuestUnitType(unit.Type), perm.CanRead(unit.Type))
+		assert.False(t, perm.CanWrite(unit.Type))
+	}
+	assert.False(t, perm.CanRead(UnitTypeCode))
+
 	// owner
 	owner := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
 	perm, err = GetUserRepoPermission(repo, owner)
@@ -0,0 +1,171 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+)
+
+// RepoPushRule represents the rules every push to a repository has to follow
+type RepoPushRule struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"UNIQUE NOT NULL"`
+	// MaxFileSize is the maximum size in bytes of a pushed file, 0 means unlimited
+	MaxFileSize int64 `xorm:"NOT NULL DEFAULT 0"`
+	// ForbiddenFilePatterns is a semicolon separated list of glob patterns of files which can not be pushed
+	ForbiddenFilePatterns string `xorm:"TEXT"`
+	// AuthorEmailDomains is a comma separated list of the domains the author emails of pushed commits must belong to
+	AuthorEmailDomains string `xorm:"TEXT"`
+	// RejectForcePush rejects force pushes to every branch, not only to protected ones
+	RejectForcePush bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoPushRule))
+}
+
+// OversizedFile is a pushed file larger than the maximum file size of the push rules
+type OversizedFile struct {
+	Path string
+	Size int64
+}
+
+// ErrPushRuleViolated represents a push which violates the push rules of a repository
+type ErrPushRuleViolated struct {
+	Reason string
+	// OversizedFiles are the files larger than MaxFileSize if the push was rejected because of them
+	OversizedFiles []*OversizedFile
+	MaxFileSize    int64
+}
+
+// IsErrPushRuleViolated checks if an error is an ErrPushRuleViolated.
+func IsErrPushRuleViolated(err error) bool {
+	_, ok := err.(ErrPushRuleViolated)
+	return ok
+}
+
+func (err ErrPushRuleViolated) Error() string {
+	return fmt.Sprintf("push rule violated: %s", err.Reason)
+}
+
+// GetRepoPushRule returns the push rules of a repository, a repository without rules gets empty ones
+func GetRepoPushRule(repoID int64) (*RepoPushRule, error) {
+	rule := &RepoPushRule{RepoID: repoID}
+	if _, err := db.DefaultContext().Engine().Get(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// SaveRepoPushRule inserts or updates the push rules of a repository
+func SaveRepoPushRule(rule *RepoPushRule) error {
+	if rule.ID == 0 {
+		_, err := db.DefaultContext().Engine().Insert(rule)
+		return err
+	}
+	_, err := db.DefaultContext().Engine().ID(rule.ID).
+		Cols("max_file_size", "forbidden_file_patterns", "author_email_domains", "reject_force_push").
+		Update(rule)
+	return err
+}
+
+// IsEmpty returns whether the rules do not restrict any push
+func (rule *RepoPushRule) IsEmpty() bool {
+	return !rule.HasFileRules() && len(rule.GetAuthorEmailDomains()) == 0 && !rule.RejectForcePush
+}
+
+// HasFileRules returns whether the pushed files have to be checked
+func (rule *RepoPushRule) HasFileRules() bool {
+	return rule.MaxFileSize > 0 || len(rule.GetForbiddenFilePatterns()) > 0
+}
+
+// GetForbiddenFilePatterns parses the semicolon separated list of forbidden file patterns
+func (rule *RepoPushRule) GetForbiddenFilePatterns() []glob.Glob {
+	return getFilePatterns(rule.ForbiddenFilePatterns)
+}
+
+// GetAuthorEmailDomains parses the comma separated list of allowed author email domains
+func (rule *RepoPushRule) GetAuthorEmailDomains() []string {
+	domains := make([]string, 0, 2)
+	for _, domain := range strings.Split(rule.AuthorEmailDomains, ",") {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// CheckFiles returns an ErrPushRuleViolated if the files, mapped to their size, can not be pushed.
+// All files larger than the maximum file size are reported at once.
+func (rule *RepoPushRule) CheckFiles(files map[string]int64) error {
+	if rule.MaxFileSize > 0 {
+		var oversized []*OversizedFile
+		for path, size := range files {
+			if size > rule.MaxFileSize {
+				oversized = append(oversized, &OversizedFile{Path: path, Size: size})
+			}
+		}
+		if len(oversized) > 0 {
+			sort.Slice(oversized, func(i, j int) bool {
+				return oversized[i].Path < oversized[j].Path
+			})
+			return ErrPushRuleViolated{
+				Reason:         fmt.Sprintf("%d file(s) larger than %d bytes", len(oversized), rule.MaxFileSize),
+				OversizedFiles: oversized,
+				MaxFileSize:    rule.MaxFileSize,
+			}
+		}
+	}
+
+	patterns := rule.GetForbiddenFilePatterns()
+	if len(patterns) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		lpath := strings.ToLower(strings.TrimSpace(path))
+		for _, pat := range patterns {
+			if pat.Match(lpath) {
+				return ErrPushRuleViolated{
+					Reason: fmt.Sprintf("file %s matches a forbidden file pattern", path),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckAuthorEmail returns an ErrPushRuleViolated if a commit with the given author email can not be pushed
+func (rule *RepoPushRule) CheckAuthorEmail(email string) error {
+	domains := rule.GetAuthorEmailDomains()
+	if len(domains) == 0 {
+		return nil
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		for _, domain := range domains {
+			if email[i+1:] == domain {
+				return nil
+			}
+		}
+	}
+	return ErrPushRuleViolated{
+		Reason: fmt.Sprintf("author email %s does not belong to an allowed domain", email),
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoPushRule(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	rule, err := GetRepoPushRule(1)
+	assert.NoError(t, err)
+	assert.True(t, rule.IsEmpty())
+
+	rule.MaxFileSize = 1024
+	rule.ForbiddenFilePatterns = "**.exe; secrets/**"
+	rule.AuthorEmailDomains = "example.com, @Example.org"
+	assert.NoError(t, SaveRepoPushRule(rule))
+
+	rule, err = GetRepoPushRule(1)
+	assert.NoError(t, err)
+	assert.False(t, rule.IsEmpty())
+	assert.Equal(t, []string{"example.com", "example.org"}, rule.GetAuthorEmailDomains())
+
+	assert.NoError(t, rule.CheckFiles(map[string]int64{"README.md": 1024}))
+	assert.True(t, IsErrPushRuleViolated(rule.CheckFiles(map[string]int64{"bin/Setup.EXE": 10})))
+	assert.True(t, IsErrPushRuleViolated(rule.CheckFiles(map[string]int64{"secrets/prod/key.pem": 10})))
+
+	err = rule.CheckFiles(map[string]int64{"z.bin": 2048, "README.md": 10, "a.iso": 4096})
+	if assert.True(t, IsErrPushRuleViolated(err)) {
+		assert.Equal(t, []*OversizedFile{{Path: "a.iso", Size: 4096}, {Path: "z.bin", Size: 2048}}, err.(ErrPushRuleViolated).OversizedFiles)
+		assert.EqualValues(t, 1024, err.(ErrPushRuleViolated).MaxFileSize)
+	}
+
+	assert.NoError(t, rule.CheckAuthorEmail("user@example.com"))
+	assert.NoError(t, rule.CheckAuthorEmail("User@EXAMPLE.ORG"))
+	assert.True(t, IsErrPushRuleViolated(rule.CheckAuthorEmail("user@sub.example.com")))
+	assert.True(t, IsErrPushRuleViolated(rule.CheckAuthorEmail("example.com")))
+}
@@ -8,14 +8,16 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
 )
 
 // RepoRedirect represents that a repo name should be redirected to another
 type RepoRedirect struct {
-	ID             int64  `xorm:"pk autoincr"`
-	OwnerID        int64  `xorm:"UNIQUE(s)"`
-	LowerName      string `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	RedirectRepoID int64  // repoID to redirect to
+	ID             int64              `xorm:"pk autoincr"`
+	OwnerID        int64              `xorm:"UNIQUE(s)"`
+	LowerName      string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RedirectRepoID int64              // repoID to redirect to
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
 }
 
 func init() {
@@ -34,6 +36,16 @@ func LookupRepoRedirect(ownerID int64, repoName string) (int64, error) {
 	return redirect.RedirectRepoID, nil
 }
 
+// FindRepoRedirects returns the redirects to a repository, most recent first
+func FindRepoRedirects(repoID int64) ([]*RepoRedirect, error) {
+	redirects := make([]*RepoRedirect, 0, 5)
+	err := db.DefaultContext().Engine().
+		Where("redirect_repo_id = ?", repoID).
+		Desc("created_unix", "id").
+		Find(&redirects)
+	return redirects, err
+}
+
 // newRepoRedirect create a new repo redirect
 func newRepoRedirect(e db.Engine, ownerID, repoID int64, oldRepoName, newRepoName string) error {
 	oldRepoName = strings.ToLower(oldRepoName)
@@ -22,6 +22,25 @@ func TestLookupRepoRedirect(t *testing.T) {
 	assert.True(t, IsErrRepoRedirectNotExist(err))
 }
 
+func TestFindRepoRedirects(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, newRepoRedirect(db.DefaultContext().Engine(), repo.OwnerID, repo.ID, repo.Name, "newreponame"))
+
+	redirects, err := FindRepoRedirects(repo.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, redirects, 2) {
+		assert.Equal(t, repo.LowerName, redirects[0].LowerName)
+		assert.NotZero(t, redirects[0].CreatedUnix)
+		assert.Equal(t, "oldrepo1", redirects[1].LowerName)
+	}
+
+	redirects, err = FindRepoRedirects(db.NonexistentID)
+	assert.NoError(t, err)
+	assert.Empty(t, redirects)
+}
+
 func TestNewRepoRedirect(t *testing.T) {
 	// redirect to a completely new name
 	assert.NoError(t, db.PrepareTestDatabase())
@@ -0,0 +1,203 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gogs/cron"
+)
+
+var (
+	// ErrRepoScheduleNotExist indicates a repository schedule not exist error
+	ErrRepoScheduleNotExist = errors.New("Repository schedule does not exist")
+	// ErrRepoScheduleAlreadyExist indicates a repository schedule with the same name exists already
+	ErrRepoScheduleAlreadyExist = errors.New("Repository schedule already exists")
+)
+
+// ErrInvalidScheduleSpec represents an invalid cron expression
+type ErrInvalidScheduleSpec struct {
+	Spec   string
+	Reason string
+}
+
+// IsErrInvalidScheduleSpec checks if an error is a ErrInvalidScheduleSpec.
+func IsErrInvalidScheduleSpec(err error) bool {
+	_, ok := err.(ErrInvalidScheduleSpec)
+	return ok
+}
+
+func (err ErrInvalidScheduleSpec) Error() string {
+	return fmt.Sprintf("invalid schedule %q: %s", err.Spec, err.Reason)
+}
+
+// minScheduleInterval is the shortest interval between two runs of a schedule
+const minScheduleInterval = time.Minute
+
+// ScheduleCatchUpPolicy defines how runs are handled which were missed while the server was down
+type ScheduleCatchUpPolicy int
+
+// Note: new policy must append to the end of list to maintain compatibility.
+const (
+	ScheduleCatchUpNone ScheduleCatchUpPolicy = iota // missed runs are skipped
+	ScheduleCatchUpOnce                              // missed runs are fired once
+	ScheduleCatchUpAll                               // every missed run is fired
+)
+
+var scheduleCatchUpPolicyNames = map[ScheduleCatchUpPolicy]string{
+	ScheduleCatchUpNone: "none",
+	ScheduleCatchUpOnce: "once",
+	ScheduleCatchUpAll:  "all",
+}
+
+// String returns the name of the policy
+func (p ScheduleCatchUpPolicy) String() string {
+	return scheduleCatchUpPolicyNames[p]
+}
+
+// ScheduleCatchUpPolicyFromString returns the policy with the given name
+func ScheduleCatchUpPolicyFromString(name string) (ScheduleCatchUpPolicy, bool) {
+	for p, n := range scheduleCatchUpPolicyNames {
+		if n == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// RepoSchedule represents a cron expression which fires repository events
+type RepoSchedule struct {
+	ID          int64                 `xorm:"pk autoincr"`
+	RepoID      int64                 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name        string                `xorm:"UNIQUE(s) NOT NULL"`
+	Spec        string                `xorm:"NOT NULL"`
+	CatchUp     ScheduleCatchUpPolicy `xorm:"NOT NULL DEFAULT 0"`
+	IsActive    bool                  `xorm:"INDEX NOT NULL DEFAULT true"`
+	CreatorID   int64                 `xorm:"NOT NULL"`
+	NextRunUnix timeutil.TimeStamp    `xorm:"INDEX"`
+	LastRunUnix timeutil.TimeStamp
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoSchedule))
+}
+
+// ParseScheduleSpec parses a standard cron expression with five fields or a descriptor like @daily or @every 1h
+func ParseScheduleSpec(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, ErrInvalidScheduleSpec{Spec: spec, Reason: err.Error()}
+	}
+	next := schedule.Next(time.Now())
+	if schedule.Next(next).Sub(next) < minScheduleInterval {
+		return nil, ErrInvalidScheduleSpec{Spec: spec, Reason: fmt.Sprintf("interval must be at least %s", minScheduleInterval)}
+	}
+	return schedule, nil
+}
+
+// UpdateNextRun computes the next time the schedule fires after t
+func (s *RepoSchedule) UpdateNextRun(t time.Time) error {
+	schedule, err := ParseScheduleSpec(s.Spec)
+	if err != nil {
+		return err
+	}
+	s.NextRunUnix = timeutil.TimeStamp(schedule.Next(t).Unix())
+	return nil
+}
+
+// MissedRuns returns the times the schedule should have fired between its next run and now, oldest first.
+// At most limit times are returned.
+func (s *RepoSchedule) MissedRuns(now time.Time, limit int) ([]time.Time, error) {
+	schedule, err := ParseScheduleSpec(s.Spec)
+	if err != nil {
+		return nil, err
+	}
+	var runs []time.Time
+	for t := s.NextRunUnix.AsTime(); !t.After(now) && len(runs) < limit; t = schedule.Next(t) {
+		runs = append(runs, t)
+	}
+	return runs, nil
+}
+
+// CreateRepoSchedule creates a schedule and computes its next run
+func CreateRepoSchedule(s *RepoSchedule) error {
+	if err := s.UpdateNextRun(time.Now()); err != nil {
+		return err
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		has, err := ctx.Engine().Exist(&RepoSchedule{RepoID: s.RepoID, Name: s.Name})
+		if err != nil {
+			return err
+		}
+		if has {
+			return ErrRepoScheduleAlreadyExist
+		}
+		_, err = ctx.Engine().Insert(s)
+		return err
+	})
+}
+
+// GetRepoScheduleByID gets a schedule of a repository by its id
+func GetRepoScheduleByID(repoID, id int64) (*RepoSchedule, error) {
+	s := &RepoSchedule{}
+	has, err := db.DefaultContext().Engine().Where("repo_id = ?", repoID).ID(id).Get(s)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrRepoScheduleNotExist
+	}
+	return s, nil
+}
+
+// GetRepoSchedules returns the schedules of a repository ordered by name
+func GetRepoSchedules(repoID int64) ([]*RepoSchedule, error) {
+	schedules := make([]*RepoSchedule, 0, 5)
+	return schedules, db.DefaultContext().Engine().Where("repo_id = ?", repoID).Asc("name").Find(&schedules)
+}
+
+// UpdateRepoSchedule updates the definition of a schedule and recomputes its next run
+func UpdateRepoSchedule(s *RepoSchedule) error {
+	if err := s.UpdateNextRun(time.Now()); err != nil {
+		return err
+	}
+	_, err := db.DefaultContext().Engine().ID(s.ID).Cols("spec", "catch_up", "is_active", "next_run_unix").Update(s)
+	return err
+}
+
+// UpdateRepoScheduleRuns stores the last and next run of a schedule
+func UpdateRepoScheduleRuns(s *RepoSchedule) error {
+	_, err := db.DefaultContext().Engine().ID(s.ID).Cols("last_run_unix", "next_run_unix").NoAutoTime().Update(s)
+	return err
+}
+
+// DeleteRepoSchedule deletes a schedule of a repository
+func DeleteRepoSchedule(repoID, id int64) error {
+	affected, err := db.DefaultContext().Engine().Where("repo_id = ?", repoID).ID(id).Delete(&RepoSchedule{})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRepoScheduleNotExist
+	}
+	return nil
+}
+
+// FindDueRepoSchedules returns the active schedules whose next run is not after t
+func FindDueRepoSchedules(t time.Time) ([]*RepoSchedule, error) {
+	schedules := make([]*RepoSchedule, 0, 10)
+	return schedules, db.DefaultContext().Engine().
+		Where("is_active = ? AND next_run_unix <= ?", true, t.Unix()).
+		Asc("next_run_unix").
+		Find(&schedules)
+}
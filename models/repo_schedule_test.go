@@ -0,0 +1,76 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScheduleSpec(t *testing.T) {
+	for _, spec := range []string{"0 3 * * *", "*/5 * * * *", "@daily", "@every 1h"} {
+		_, err := ParseScheduleSpec(spec)
+		assert.NoError(t, err, spec)
+	}
+	for _, spec := range []string{"", "not a cron", "* * * *", "@every 10s"} {
+		_, err := ParseScheduleSpec(spec)
+		assert.True(t, IsErrInvalidScheduleSpec(err), spec)
+	}
+}
+
+func TestRepoScheduleMissedRuns(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 30, 0, 0, time.UTC)
+	s := &RepoSchedule{
+		Spec:        "0 * * * *",
+		NextRunUnix: timeutil.TimeStamp(now.Add(-150 * time.Minute).Truncate(time.Hour).Unix()),
+	}
+
+	runs, err := s.MissedRuns(now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 3)
+	assert.Equal(t, now.Add(-30*time.Minute).Unix(), runs[2].Unix())
+
+	runs, err = s.MissedRuns(now, 2)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+}
+
+func TestCreateRepoSchedule(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	s := &RepoSchedule{RepoID: 1, Name: "nightly", Spec: "0 3 * * *", IsActive: true, CreatorID: 2}
+	assert.NoError(t, CreateRepoSchedule(s))
+	assert.NotZero(t, s.ID)
+	assert.True(t, s.NextRunUnix.AsTime().After(time.Now()))
+
+	err := CreateRepoSchedule(&RepoSchedule{RepoID: 1, Name: "nightly", Spec: "@daily"})
+	assert.Equal(t, ErrRepoScheduleAlreadyExist, err)
+	err = CreateRepoSchedule(&RepoSchedule{RepoID: 1, Name: "invalid", Spec: "invalid"})
+	assert.True(t, IsErrInvalidScheduleSpec(err))
+
+	schedules, err := GetRepoSchedules(1)
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 1)
+
+	due, err := FindDueRepoSchedules(s.NextRunUnix.AsTime())
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	s.IsActive = false
+	assert.NoError(t, UpdateRepoSchedule(s))
+	due, err = FindDueRepoSchedules(s.NextRunUnix.AsTime())
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+
+	assert.Equal(t, ErrRepoScheduleNotExist, DeleteRepoSchedule(2, s.ID))
+	assert.NoError(t, DeleteRepoSchedule(1, s.ID))
+	_, err = GetRepoScheduleByID(1, s.ID)
+	assert.Equal(t, ErrRepoScheduleNotExist, err)
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RepoSettingsJobStatus is the progress of a bulk repository settings update
+type RepoSettingsJobStatus int
+
+// Note: new status must append to the end of list to maintain compatibility.
+const (
+	RepoSettingsJobStatusQueued RepoSettingsJobStatus = iota
+	RepoSettingsJobStatusRunning
+	RepoSettingsJobStatusFinished
+)
+
+var repoSettingsJobStatusNames = map[RepoSettingsJobStatus]string{
+	RepoSettingsJobStatusQueued:   "queued",
+	RepoSettingsJobStatusRunning:  "running",
+	RepoSettingsJobStatusFinished: "finished",
+}
+
+// String returns the name of the status
+func (s RepoSettingsJobStatus) String() string {
+	return repoSettingsJobStatusNames[s]
+}
+
+// RepoSettingsResultStatus is the outcome of a bulk repository settings update for one repository
+type RepoSettingsResultStatus int
+
+// Note: new status must append to the end of list to maintain compatibility.
+const (
+	RepoSettingsResultStatusPending RepoSettingsResultStatus = iota
+	RepoSettingsResultStatusSuccess
+	RepoSettingsResultStatusFailed
+	RepoSettingsResultStatusSkipped
+)
+
+var repoSettingsResultStatusNames = map[RepoSettingsResultStatus]string{
+	RepoSettingsResultStatusPending: "pending",
+	RepoSettingsResultStatusSuccess: "success",
+	RepoSettingsResultStatusFailed:  "failed",
+	RepoSettingsResultStatusSkipped: "skipped",
+}
+
+// String returns the name of the status
+func (s RepoSettingsResultStatus) String() string {
+	return repoSettingsResultStatusNames[s]
+}
+
+// RepoSettingsJob is a settings patch applied in the background to many repositories of an organization
+type RepoSettingsJob struct {
+	ID     int64 `xorm:"pk autoincr"`
+	OrgID  int64 `xorm:"INDEX NOT NULL"`
+	DoerID int64 `xorm:"NOT NULL"`
+	// Patch is the JSON encoded settings patch
+	Patch  string                `xorm:"TEXT"`
+	Status RepoSettingsJobStatus `xorm:"INDEX NOT NULL"`
+
+	CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+	FinishedUnix timeutil.TimeStamp
+
+	Results []*RepoSettingsResult `xorm:"-"`
+}
+
+// RepoSettingsResult is the outcome of a bulk repository settings update for one repository
+type RepoSettingsResult struct {
+	ID     int64 `xorm:"pk autoincr"`
+	JobID  int64 `xorm:"INDEX NOT NULL"`
+	RepoID int64 `xorm:"NOT NULL"`
+	// RepoName is the name of the repository when the job was created
+	RepoName string
+	Status   RepoSettingsResultStatus `xorm:"NOT NULL"`
+	Message  string                   `xorm:"TEXT"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoSettingsJob))
+	db.RegisterModel(new(RepoSettingsResult))
+}
+
+// ErrRepoSettingsJobNotExist represents a "RepoSettingsJobNotExist" kind of error.
+type ErrRepoSettingsJobNotExist struct {
+	ID    int64
+	OrgID int64
+}
+
+// IsErrRepoSettingsJobNotExist checks if an error is a ErrRepoSettingsJobNotExist.
+func IsErrRepoSettingsJobNotExist(err error) bool {
+	_, ok := err.(ErrRepoSettingsJobNotExist)
+	return ok
+}
+
+func (err ErrRepoSettingsJobNotExist) Error() string {
+	return fmt.Sprintf("repository settings job does not exist [id: %d, org_id: %d]", err.ID, err.OrgID)
+}
+
+// CreateRepoSettingsJob inserts a new queued job with a pending result for each of the repositories
+func CreateRepoSettingsJob(job *RepoSettingsJob, repos []*Repository) error {
+	job.Status = RepoSettingsJobStatusQueued
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Insert(job); err != nil {
+			return err
+		}
+		job.Results = make([]*RepoSettingsResult, 0, len(repos))
+		for _, repo := range repos {
+			result := &RepoSettingsResult{
+				JobID:    job.ID,
+				RepoID:   repo.ID,
+				RepoName: repo.Name,
+				Status:   RepoSettingsResultStatusPending,
+			}
+			if _, err := e.Insert(result); err != nil {
+				return err
+			}
+			job.Results = append(job.Results, result)
+		}
+		return nil
+	})
+}
+
+// GetRepoSettingsJobByID returns a job of an organization, orgID 0 matches any organization
+func GetRepoSettingsJobByID(orgID, id int64) (*RepoSettingsJob, error) {
+	job := &RepoSettingsJob{ID: id, OrgID: orgID}
+	has, err := db.DefaultContext().Engine().Get(job)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoSettingsJobNotExist{ID: id, OrgID: orgID}
+	}
+	return job, nil
+}
+
+// LoadResults loads the results of the job for each repository
+func (job *RepoSettingsJob) LoadResults() error {
+	if job.Results != nil {
+		return nil
+	}
+	job.Results = make([]*RepoSettingsResult, 0, 10)
+	return db.DefaultContext().Engine().Where("job_id = ?", job.ID).Asc("id").Find(&job.Results)
+}
+
+// UpdateRepoSettingsJobStatus stores the progress of a job
+func UpdateRepoSettingsJobStatus(job *RepoSettingsJob) error {
+	if job.Status == RepoSettingsJobStatusFinished {
+		job.FinishedUnix = timeutil.TimeStampNow()
+	}
+	_, err := db.DefaultContext().Engine().ID(job.ID).Cols("status", "finished_unix").Update(job)
+	return err
+}
+
+// UpdateRepoSettingsResult stores the outcome of a job for one repository
+func UpdateRepoSettingsResult(result *RepoSettingsResult) error {
+	_, err := db.DefaultContext().Engine().ID(result.ID).Cols("status", "message").Update(result)
+	return err
+}
+
+// FindRepoSettingsJobs returns the jobs of an organization, newest first
+func FindRepoSettingsJobs(orgID int64, listOptions ListOptions) ([]*RepoSettingsJob, int64, error) {
+	sess := db.DefaultContext().Engine().Where("org_id = ?", orgID).Desc("id")
+	if listOptions.Page > 0 {
+		sess = setSessionPagination(sess, &listOptions)
+	}
+
+	jobs := make([]*RepoSettingsJob, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&jobs)
+	return jobs, count, err
+}
@@ -0,0 +1,174 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ErrStagingSessionNotExist indicates a staging session not exist error
+var ErrStagingSessionNotExist = errors.New("Staging session does not exist")
+
+// StagedChangeOperation is the operation a staged change applies to a file
+type StagedChangeOperation string
+
+// The operations of staged changes
+const (
+	StagedChangeOperationCreate StagedChangeOperation = "create"
+	StagedChangeOperationUpdate StagedChangeOperation = "update"
+	StagedChangeOperationDelete StagedChangeOperation = "delete"
+)
+
+// IsValid returns whether the operation is known
+func (op StagedChangeOperation) IsValid() bool {
+	switch op {
+	case StagedChangeOperationCreate, StagedChangeOperationUpdate, StagedChangeOperationDelete:
+		return true
+	}
+	return false
+}
+
+// StagingSession accumulates changes of files of a branch which are committed at once
+type StagingSession struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"INDEX NOT NULL"`
+	UserID int64 `xorm:"INDEX NOT NULL"`
+	// Branch is the branch the changes are based on
+	Branch string `xorm:"NOT NULL"`
+	// BaseCommitID is the commit of the branch when the session was created
+	BaseCommitID string             `xorm:"VARCHAR(40) NOT NULL"`
+	Changes      []*StagedChange    `xorm:"-"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+// StagedChange represents a change of a file staged in a session
+type StagedChange struct {
+	ID        int64                 `xorm:"pk autoincr"`
+	SessionID int64                 `xorm:"UNIQUE(s) NOT NULL"`
+	TreePath  string                `xorm:"UNIQUE(s) VARCHAR(500) NOT NULL"`
+	Operation StagedChangeOperation `xorm:"VARCHAR(10) NOT NULL"`
+	// FromTreePath is the path of the file which is moved to TreePath by an update
+	FromTreePath string `xorm:"VARCHAR(500)"`
+	// Content is the base64 encoded content of the file, empty for a deletion
+	Content     string             `xorm:"LONGTEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(StagingSession))
+	db.RegisterModel(new(StagedChange))
+}
+
+// CreateStagingSession creates a new staging session
+func CreateStagingSession(s *StagingSession) error {
+	_, err := db.DefaultContext().Engine().Insert(s)
+	return err
+}
+
+// GetStagingSession returns the staging session of a repository by its ID
+func GetStagingSession(repoID, id int64) (*StagingSession, error) {
+	s := new(StagingSession)
+	has, err := db.DefaultContext().Engine().Where("repo_id = ? AND id = ?", repoID, id).Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrStagingSessionNotExist
+	}
+	return s, nil
+}
+
+// FindStagingSessions returns the staging sessions of a user in a repository
+func FindStagingSessions(repoID, userID int64) ([]*StagingSession, error) {
+	sessions := make([]*StagingSession, 0, 5)
+	return sessions, db.DefaultContext().Engine().
+		Where("repo_id = ? AND user_id = ?", repoID, userID).
+		Desc("updated_unix").
+		Find(&sessions)
+}
+
+// LoadChanges loads the staged changes of the session
+func (s *StagingSession) LoadChanges() error {
+	if s.Changes != nil {
+		return nil
+	}
+	s.Changes = make([]*StagedChange, 0, 5)
+	return db.DefaultContext().Engine().Where("session_id = ?", s.ID).Asc("tree_path").Find(&s.Changes)
+}
+
+// StageChange adds a change to the session, replacing the change previously staged for the same path
+func StageChange(s *StagingSession, change *StagedChange) error {
+	change.SessionID = s.ID
+	return db.WithTx(func(ctx *db.Context) error {
+		sess := ctx.Engine()
+		if _, err := sess.Delete(&StagedChange{SessionID: s.ID, TreePath: change.TreePath}); err != nil {
+			return err
+		}
+		change.ID = 0
+		if _, err := sess.Insert(change); err != nil {
+			return err
+		}
+		_, err := sess.ID(s.ID).NoAutoTime().Cols("updated_unix").Update(&StagingSession{UpdatedUnix: timeutil.TimeStampNow()})
+		return err
+	})
+}
+
+// UnstageChange removes the change of a path from the session
+func UnstageChange(s *StagingSession, treePath string) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		sess := ctx.Engine()
+		if _, err := sess.Delete(&StagedChange{SessionID: s.ID, TreePath: treePath}); err != nil {
+			return err
+		}
+		_, err := sess.ID(s.ID).NoAutoTime().Cols("updated_unix").Update(&StagingSession{UpdatedUnix: timeutil.TimeStampNow()})
+		return err
+	})
+}
+
+// DeleteStagingSession deletes a staging session and its changes
+func DeleteStagingSession(s *StagingSession) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		sess := ctx.Engine()
+		if _, err := sess.Delete(&StagedChange{SessionID: s.ID}); err != nil {
+			return err
+		}
+		_, err := sess.ID(s.ID).Delete(new(StagingSession))
+		return err
+	})
+}
+
+// DeleteOldStagingSessions deletes the staging sessions which have not been updated for the given duration
+func DeleteOldStagingSessions(olderThan time.Duration) error {
+	log.Trace("Doing: DeleteOldStagingSessions")
+
+	updatedBefore := time.Now().Add(-olderThan).Unix()
+	if err := db.WithTx(func(ctx *db.Context) error {
+		sess := ctx.Engine()
+		if _, err := sess.Where("session_id IN (SELECT id FROM staging_session WHERE updated_unix < ?)", updatedBefore).Delete(new(StagedChange)); err != nil {
+			return err
+		}
+		_, err := sess.Where("updated_unix < ?", updatedBefore).Delete(new(StagingSession))
+		return err
+	}); err != nil {
+		return err
+	}
+
+	log.Trace("Finished: DeleteOldStagingSessions")
+	return nil
+}
+
+func deleteStagingSessionsByRepoID(e db.Engine, repoID int64) error {
+	if _, err := e.Where("session_id IN (SELECT id FROM staging_session WHERE repo_id = ?)", repoID).Delete(new(StagedChange)); err != nil {
+		return err
+	}
+	_, err := e.Delete(&StagingSession{RepoID: repoID})
+	return err
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStagingSession(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	s := &StagingSession{RepoID: 1, UserID: 2, Branch: "master", BaseCommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d"}
+	assert.NoError(t, CreateStagingSession(s))
+
+	assert.NoError(t, StageChange(s, &StagedChange{TreePath: "README.md", Operation: StagedChangeOperationUpdate, Content: "YQ=="}))
+	assert.NoError(t, StageChange(s, &StagedChange{TreePath: "new.txt", Operation: StagedChangeOperationCreate, Content: "Yg=="}))
+	// a change replaces the change staged for the same path
+	assert.NoError(t, StageChange(s, &StagedChange{TreePath: "README.md", Operation: StagedChangeOperationDelete}))
+
+	s, err := GetStagingSession(1, s.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, s.LoadChanges())
+	if assert.Len(t, s.Changes, 2) {
+		assert.Equal(t, "README.md", s.Changes[0].TreePath)
+		assert.Equal(t, StagedChangeOperationDelete, s.Changes[0].Operation)
+		assert.Equal(t, "new.txt", s.Changes[1].TreePath)
+	}
+
+	assert.NoError(t, UnstageChange(s, "new.txt"))
+	s.Changes = nil
+	assert.NoError(t, s.LoadChanges())
+	assert.Len(t, s.Changes, 1)
+
+	sessions, err := FindStagingSessions(1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	sessions, err = FindStagingSessions(1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 0)
+
+	_, err = GetStagingSession(2, s.ID)
+	assert.Equal(t, ErrStagingSessionNotExist, err)
+
+	assert.NoError(t, DeleteStagingSession(s))
+	_, err = GetStagingSession(1, s.ID)
+	assert.Equal(t, ErrStagingSessionNotExist, err)
+	db.AssertNotExistsBean(t, &StagedChange{SessionID: s.ID})
+}
+
+func TestDeleteOldStagingSessions(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	old := &StagingSession{RepoID: 1, UserID: 2, Branch: "master", BaseCommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d"}
+	assert.NoError(t, CreateStagingSession(old))
+	assert.NoError(t, StageChange(old, &StagedChange{TreePath: "README.md", Operation: StagedChangeOperationUpdate, Content: "YQ=="}))
+	_, err := db.DefaultContext().Engine().ID(old.ID).NoAutoTime().Cols("updated_unix").
+		Update(&StagingSession{UpdatedUnix: timeutil.TimeStamp(time.Now().Add(-48 * time.Hour).Unix())})
+	assert.NoError(t, err)
+
+	recent := &StagingSession{RepoID: 1, UserID: 2, Branch: "master", BaseCommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d"}
+	assert.NoError(t, CreateStagingSession(recent))
+	assert.NoError(t, StageChange(recent, &StagedChange{TreePath: "README.md", Operation: StagedChangeOperationUpdate, Content: "YQ=="}))
+
+	assert.NoError(t, DeleteOldStagingSessions(24*time.Hour))
+
+	db.AssertNotExistsBean(t, &StagingSession{ID: old.ID})
+	db.AssertNotExistsBean(t, &StagedChange{SessionID: old.ID})
+	db.AssertExistsAndLoadBean(t, &StagingSession{ID: recent.ID})
+	db.AssertExistsAndLoadBean(t, &StagedChange{SessionID: recent.ID})
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// symbolInsertBatchSize is the number of symbols inserted by a single statement
+const symbolInsertBatchSize = 100
+
+// RepoSymbol represents a definition found by the symbol indexer in a branch of a repository
+type RepoSymbol struct {
+	ID        int64  `xorm:"pk autoincr"`
+	RepoID    int64  `xorm:"INDEX(s) NOT NULL"`
+	Ref       string `xorm:"INDEX(s) VARCHAR(255) NOT NULL"`
+	Name      string `xorm:"INDEX NOT NULL"`
+	Kind      string `xorm:"VARCHAR(50)"`
+	Language  string `xorm:"VARCHAR(50)"`
+	Path      string `xorm:"TEXT NOT NULL"`
+	Line      int
+	Scope     string
+	Signature string `xorm:"TEXT"`
+}
+
+// RepoSymbolStatus records the commit the symbols of a branch were extracted from
+type RepoSymbolStatus struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	Ref         string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	CommitSha   string             `xorm:"VARCHAR(40)"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoSymbol))
+	db.RegisterModel(new(RepoSymbolStatus))
+}
+
+// GetRepoSymbolStatus returns the symbol index status of a branch, nil if the branch was never indexed
+func GetRepoSymbolStatus(repoID int64, ref string) (*RepoSymbolStatus, error) {
+	status := &RepoSymbolStatus{RepoID: repoID, Ref: ref}
+	has, err := db.DefaultContext().Engine().Get(status)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return status, nil
+}
+
+// ReplaceRepoSymbols replaces the symbols of a branch with the ones extracted from the given commit
+func ReplaceRepoSymbols(repoID int64, ref, commitSha string, symbols []*RepoSymbol) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		if _, err := e.Delete(&RepoSymbol{RepoID: repoID, Ref: ref}); err != nil {
+			return err
+		}
+		for _, s := range symbols {
+			s.ID = 0
+			s.RepoID = repoID
+			s.Ref = ref
+		}
+		for i := 0; i < len(symbols); i += symbolInsertBatchSize {
+			end := i + symbolInsertBatchSize
+			if end > len(symbols) {
+				end = len(symbols)
+			}
+			if _, err := e.Insert(symbols[i:end]); err != nil {
+				return err
+			}
+		}
+
+		status := &RepoSymbolStatus{RepoID: repoID, Ref: ref}
+		has, err := e.Get(status)
+		if err != nil {
+			return err
+		}
+		status.CommitSha = commitSha
+		if has {
+			_, err = e.ID(status.ID).Cols("commit_sha").Update(status)
+		} else {
+			_, err = e.Insert(status)
+		}
+		return err
+	})
+}
+
+// DeleteRepoSymbols removes the symbols and the index status of a branch
+func DeleteRepoSymbols(repoID int64, ref string) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		if _, err := ctx.Engine().Delete(&RepoSymbol{RepoID: repoID, Ref: ref}); err != nil {
+			return err
+		}
+		_, err := ctx.Engine().Delete(&RepoSymbolStatus{RepoID: repoID, Ref: ref})
+		return err
+	})
+}
+
+// FindRepoSymbolOptions represents the options to find symbols of a branch
+type FindRepoSymbolOptions struct {
+	ListOptions
+	RepoID int64
+	Ref    string
+	Name   string
+	Kind   string
+	Path   string
+}
+
+func (opts *FindRepoSymbolOptions) toConds() builder.Cond {
+	cond := builder.NewCond().And(builder.Eq{"repo_id": opts.RepoID, "ref": opts.Ref})
+	if opts.Name != "" {
+		cond = cond.And(builder.Eq{"name": opts.Name})
+	}
+	if opts.Kind != "" {
+		cond = cond.And(builder.Eq{"kind": opts.Kind})
+	}
+	if opts.Path != "" {
+		cond = cond.And(builder.Eq{"path": opts.Path})
+	}
+	return cond
+}
+
+// FindRepoSymbols returns the symbols of a branch matching the options and their total count
+func FindRepoSymbols(opts *FindRepoSymbolOptions) ([]*RepoSymbol, int64, error) {
+	sess := db.DefaultContext().Engine().Where(opts.toConds()).Asc("path", "line", "id")
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, opts)
+	}
+	symbols := make([]*RepoSymbol, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&symbols)
+	return symbols, count, err
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoSymbols(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	status, err := GetRepoSymbolStatus(1, "master")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	assert.NoError(t, ReplaceRepoSymbols(1, "master", "65f1bf27bc3bf70f64657658635e66094edbcb4d", []*RepoSymbol{
+		{Name: "Server", Kind: "struct", Language: "Go", Path: "server.go", Line: 10},
+		{Name: "Start", Kind: "method", Language: "Go", Path: "server.go", Line: 15, Scope: "Server"},
+		{Name: "Start", Kind: "function", Language: "Go", Path: "main.go", Line: 3},
+	}))
+	assert.NoError(t, ReplaceRepoSymbols(1, "develop", "65f1bf27bc3bf70f64657658635e66094edbcb4d", []*RepoSymbol{
+		{Name: "Start", Kind: "function", Language: "Go", Path: "main.go", Line: 5},
+	}))
+
+	status, err = GetRepoSymbolStatus(1, "master")
+	assert.NoError(t, err)
+	if assert.NotNil(t, status) {
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", status.CommitSha)
+	}
+
+	symbols, count, err := FindRepoSymbols(&FindRepoSymbolOptions{RepoID: 1, Ref: "master", Name: "Start"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, symbols, 2) {
+		assert.Equal(t, "main.go", symbols[0].Path)
+		assert.Equal(t, "server.go", symbols[1].Path)
+	}
+
+	symbols, count, err = FindRepoSymbols(&FindRepoSymbolOptions{RepoID: 1, Ref: "master", Path: "server.go", ListOptions: ListOptions{Page: 1, PageSize: 1}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, symbols, 1) {
+		assert.Equal(t, "Server", symbols[0].Name)
+	}
+
+	// replacing the symbols drops the old ones
+	assert.NoError(t, ReplaceRepoSymbols(1, "master", "37991dec2c8e592043f47155ce4808d4580f9123", []*RepoSymbol{
+		{Name: "Run", Kind: "function", Language: "Go", Path: "main.go", Line: 1},
+	}))
+	_, count, err = FindRepoSymbols(&FindRepoSymbolOptions{RepoID: 1, Ref: "master", Name: "Start"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	status, err = GetRepoSymbolStatus(1, "master")
+	assert.NoError(t, err)
+	assert.Equal(t, "37991dec2c8e592043f47155ce4808d4580f9123", status.CommitSha)
+
+	assert.NoError(t, DeleteRepoSymbols(1, "master"))
+	status, err = GetRepoSymbolStatus(1, "master")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+	db.AssertNotExistsBean(t, &RepoSymbol{RepoID: 1, Ref: "master"})
+	db.AssertExistsAndLoadBean(t, &RepoSymbol{RepoID: 1, Ref: "develop"})
+}
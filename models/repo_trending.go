@@ -0,0 +1,224 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// TrendingPeriod is the time span the activity of trending repositories is counted over
+type TrendingPeriod int
+
+// Note: new period must append to the end of list to maintain compatibility.
+const (
+	TrendingPeriodDaily TrendingPeriod = iota
+	TrendingPeriodWeekly
+	TrendingPeriodMonthly
+)
+
+var trendingPeriodNames = map[TrendingPeriod]string{
+	TrendingPeriodDaily:   "daily",
+	TrendingPeriodWeekly:  "weekly",
+	TrendingPeriodMonthly: "monthly",
+}
+
+var trendingPeriodDurations = map[TrendingPeriod]time.Duration{
+	TrendingPeriodDaily:   24 * time.Hour,
+	TrendingPeriodWeekly:  7 * 24 * time.Hour,
+	TrendingPeriodMonthly: 30 * 24 * time.Hour,
+}
+
+// String returns the name of the period
+func (p TrendingPeriod) String() string {
+	return trendingPeriodNames[p]
+}
+
+// Duration returns the time span of the period
+func (p TrendingPeriod) Duration() time.Duration {
+	return trendingPeriodDurations[p]
+}
+
+// TrendingPeriodFromString returns the period with the given name
+func TrendingPeriodFromString(name string) (TrendingPeriod, bool) {
+	for p, n := range trendingPeriodNames {
+		if n == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+const (
+	// trendingStarWeight is how much more a gained star counts than pull request activity
+	trendingStarWeight = 2
+	// trendingMaxRepos is the number of repositories kept per period
+	trendingMaxRepos = 1000
+)
+
+// RepoTrending holds the activity of a repository over a trending period, as computed by the last aggregation
+type RepoTrending struct {
+	ID     int64          `xorm:"pk autoincr"`
+	RepoID int64          `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Period TrendingPeriod `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	// Stars is the number of stars gained in the period
+	Stars int64 `xorm:"NOT NULL DEFAULT 0"`
+	// PullRequests is the number of pull requests opened and merged in the period
+	PullRequests int64              `xorm:"NOT NULL DEFAULT 0"`
+	Score        int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	ComputedUnix timeutil.TimeStamp `xorm:"NOT NULL"`
+
+	Repo *Repository `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoTrending))
+}
+
+type repoActivityCount struct {
+	RepoID int64
+	Count  int64
+}
+
+// UpdateRepoTrending recomputes the trending repositories of every period from the activity until now
+func UpdateRepoTrending(now time.Time) error {
+	for period := range trendingPeriodNames {
+		if err := updateRepoTrending(period, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateRepoTrending(period TrendingPeriod, now time.Time) error {
+	e := db.DefaultContext().Engine()
+	since := now.Add(-period.Duration()).Unix()
+
+	trending := make(map[int64]*RepoTrending)
+	get := func(repoID int64) *RepoTrending {
+		t, ok := trending[repoID]
+		if !ok {
+			t = &RepoTrending{RepoID: repoID, Period: period, ComputedUnix: timeutil.TimeStamp(now.Unix())}
+			trending[repoID] = t
+		}
+		return t
+	}
+
+	counts := make([]*repoActivityCount, 0, 100)
+	if err := e.Table("star").Select("repo_id, COUNT(*) AS count").
+		Where("created_unix >= ?", since).
+		GroupBy("repo_id").Find(&counts); err != nil {
+		return err
+	}
+	for _, c := range counts {
+		get(c.RepoID).Stars = c.Count
+	}
+
+	counts = counts[:0]
+	if err := e.Table("issue").Select("repo_id, COUNT(*) AS count").
+		Where("is_pull = ? AND created_unix >= ?", true, since).
+		GroupBy("repo_id").Find(&counts); err != nil {
+		return err
+	}
+	for _, c := range counts {
+		get(c.RepoID).PullRequests += c.Count
+	}
+
+	counts = counts[:0]
+	if err := e.Table("pull_request").Select("base_repo_id AS repo_id, COUNT(*) AS count").
+		Where("has_merged = ? AND merged_unix >= ?", true, since).
+		GroupBy("base_repo_id").Find(&counts); err != nil {
+		return err
+	}
+	for _, c := range counts {
+		get(c.RepoID).PullRequests += c.Count
+	}
+
+	ranked := make([]*RepoTrending, 0, len(trending))
+	for _, t := range trending {
+		t.Score = trendingStarWeight*t.Stars + t.PullRequests
+		ranked = append(ranked, t)
+	}
+	sortRepoTrending(ranked)
+	if len(ranked) > trendingMaxRepos {
+		ranked = ranked[:trendingMaxRepos]
+	}
+
+	return db.WithTx(func(ctx *db.Context) error {
+		if _, err := ctx.Engine().Where("period = ?", period).Delete(new(RepoTrending)); err != nil {
+			return err
+		}
+		for i := 0; i < len(ranked); i += 100 {
+			end := i + 100
+			if end > len(ranked) {
+				end = len(ranked)
+			}
+			if _, err := ctx.Engine().Insert(ranked[i:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sortRepoTrending orders trending repositories by score, then by gained stars and finally by repository id
+func sortRepoTrending(ranked []*RepoTrending) {
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Stars != b.Stars {
+			return a.Stars > b.Stars
+		}
+		return a.RepoID < b.RepoID
+	})
+}
+
+// FindTrendingReposOptions are the options for listing trending repositories
+type FindTrendingReposOptions struct {
+	ListOptions
+	Period TrendingPeriod
+	// Actor is the user the repositories must be visible to, nil for anonymous users
+	Actor *User
+}
+
+// FindTrendingRepos returns the trending repositories of a period visible to the actor, ordered by score
+func FindTrendingRepos(opts *FindTrendingReposOptions) ([]*RepoTrending, int64, error) {
+	e := db.DefaultContext().Engine()
+	sess := e.Table("repo_trending").
+		Join("INNER", "repository", "`repository`.id = repo_trending.repo_id").
+		Where("repo_trending.period = ?", opts.Period).
+		And(accessibleRepositoryCondition(opts.Actor)).
+		Desc("repo_trending.score").Desc("repo_trending.stars").Asc("repo_trending.repo_id")
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, &opts.ListOptions)
+	}
+
+	trending := make([]*RepoTrending, 0, opts.PageSize)
+	count, err := sess.Select("repo_trending.*").FindAndCount(&trending)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	repoIDs := make([]int64, 0, len(trending))
+	for _, t := range trending {
+		repoIDs = append(repoIDs, t.RepoID)
+	}
+	repos, err := GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := RepositoryListOfMap(repos).loadAttributes(e); err != nil {
+		return nil, 0, err
+	}
+	for _, t := range trending {
+		t.Repo = repos[t.RepoID]
+	}
+	return trending, count, nil
+}
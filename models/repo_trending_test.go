@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateRepoTrending(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// repository 1 is public and repository 2 is private, both are owned by user 2
+	assert.NoError(t, StarRepo(4, 1, true))
+	assert.NoError(t, StarRepo(5, 1, true))
+	assert.NoError(t, StarRepo(4, 2, true))
+
+	// running the aggregation twice must replace the previous results
+	assert.NoError(t, UpdateRepoTrending(time.Now()))
+	assert.NoError(t, UpdateRepoTrending(time.Now()))
+
+	trending, count, err := FindTrendingRepos(&FindTrendingReposOptions{Period: TrendingPeriodWeekly})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, trending, 1) {
+		assert.EqualValues(t, 1, trending[0].RepoID)
+		assert.EqualValues(t, 2, trending[0].Stars)
+		assert.EqualValues(t, 4, trending[0].Score)
+		assert.NotNil(t, trending[0].Repo)
+	}
+
+	owner := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	trending, count, err = FindTrendingRepos(&FindTrendingReposOptions{Period: TrendingPeriodDaily, Actor: owner})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, trending, 2) {
+		assert.EqualValues(t, 1, trending[0].RepoID)
+		assert.EqualValues(t, 2, trending[1].RepoID)
+		assert.EqualValues(t, 1, trending[1].Stars)
+	}
+
+	trending, count, err = FindTrendingRepos(&FindTrendingReposOptions{
+		ListOptions: ListOptions{Page: 2, PageSize: 1},
+		Period:      TrendingPeriodMonthly,
+		Actor:       owner,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, trending, 1) {
+		assert.EqualValues(t, 2, trending[0].RepoID)
+	}
+}
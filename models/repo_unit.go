@@ -78,6 +78,7 @@ type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	BranchNameTemplate               string
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -101,6 +102,8 @@ type PullRequestsConfig struct {
 	AutodetectManualMerge         bool
 	DefaultDeleteBranchAfterMerge bool
 	DefaultMergeStyle             MergeStyle
+	DefaultMergeMessageTemplate   string
+	DefaultSquashMessageTemplate  string
 }
 
 // FromDB fills up a PullRequestsConfig from serialized format.
@@ -0,0 +1,203 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ErrVulnerabilityAlertNotExist indicates a vulnerability alert not exist error
+var ErrVulnerabilityAlertNotExist = errors.New("Vulnerability alert does not exist")
+
+// VulnerabilityAlertState is the state of a vulnerability alert
+type VulnerabilityAlertState int
+
+// Note: new state must append to the end of list to maintain compatibility.
+const (
+	// VulnerabilityAlertStateOpen the repository depends on a vulnerable version
+	VulnerabilityAlertStateOpen VulnerabilityAlertState = iota
+	// VulnerabilityAlertStateDismissed the alert was dismissed by a repository admin
+	VulnerabilityAlertStateDismissed
+	// VulnerabilityAlertStateFixed the repository does not depend on the vulnerable version anymore
+	VulnerabilityAlertStateFixed
+)
+
+var vulnerabilityAlertStateNames = map[VulnerabilityAlertState]string{
+	VulnerabilityAlertStateOpen:      "open",
+	VulnerabilityAlertStateDismissed: "dismissed",
+	VulnerabilityAlertStateFixed:     "fixed",
+}
+
+// String returns the name of the state
+func (s VulnerabilityAlertState) String() string {
+	return vulnerabilityAlertStateNames[s]
+}
+
+// VulnerabilityAlertStateFromString returns the state with the given name
+func VulnerabilityAlertStateFromString(name string) (VulnerabilityAlertState, bool) {
+	for s, n := range vulnerabilityAlertStateNames {
+		if n == name {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// VulnerabilityAlertDismissReasons are the reasons an alert can be dismissed for
+var VulnerabilityAlertDismissReasons = []string{"fix_started", "inaccurate", "no_bandwidth", "not_used", "tolerable_risk"}
+
+// RepoVulnerabilitySetting represents whether a repository is alerted about vulnerable dependencies
+type RepoVulnerabilitySetting struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE NOT NULL"`
+	Enabled bool  `xorm:"INDEX NOT NULL DEFAULT false"`
+	// OpenIssues opens an issue for each new alert instead of mailing the repository owners
+	OpenIssues bool `xorm:"NOT NULL DEFAULT false"`
+	// DoerID is the user who configured the alerts, the issues are opened in their name
+	DoerID int64
+	// CheckedCommitSha is the commit the dependencies were extracted from when the alerts were last updated
+	CheckedCommitSha string             `xorm:"VARCHAR(40)"`
+	CheckedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedUnix      timeutil.TimeStamp `xorm:"updated"`
+}
+
+// VulnerabilityAlert represents a dependency of a repository on a version affected by a security advisory
+type VulnerabilityAlert struct {
+	ID         int64             `xorm:"pk autoincr"`
+	RepoID     int64             `xorm:"INDEX NOT NULL"`
+	AdvisoryID int64             `xorm:"INDEX NOT NULL"`
+	Advisory   *SecurityAdvisory `xorm:"-"`
+	Manifest   string            `xorm:"TEXT NOT NULL"`
+	Ecosystem  string            `xorm:"VARCHAR(20) NOT NULL"`
+	Package    string            `xorm:"NOT NULL"`
+	// Version is the version or the version requirement as written in the manifest
+	Version string
+	// FixedVersion is the lowest version which is not affected, empty if no fix is known
+	FixedVersion   string
+	State          VulnerabilityAlertState `xorm:"INDEX NOT NULL DEFAULT 0"`
+	DismissReason  string                  `xorm:"VARCHAR(20)"`
+	DismissComment string                  `xorm:"TEXT"`
+	DismisserID    int64
+	Dismisser      *User `xorm:"-"`
+	// IssueID is the issue opened for the alert
+	IssueID       int64
+	DismissedUnix timeutil.TimeStamp
+	FixedUnix     timeutil.TimeStamp
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoVulnerabilitySetting))
+	db.RegisterModel(new(VulnerabilityAlert))
+}
+
+// GetRepoVulnerabilitySetting returns the vulnerability alert setting of a repository, alerts are disabled by default
+func GetRepoVulnerabilitySetting(repoID int64) (*RepoVulnerabilitySetting, error) {
+	s := &RepoVulnerabilitySetting{RepoID: repoID}
+	if _, err := db.DefaultContext().Engine().Get(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveRepoVulnerabilitySetting inserts or updates the vulnerability alert setting of a repository,
+// the alerts are checked again on the next run
+func SaveRepoVulnerabilitySetting(s *RepoVulnerabilitySetting) error {
+	s.CheckedCommitSha = ""
+	s.CheckedUnix = 0
+	if s.ID == 0 {
+		_, err := db.DefaultContext().Engine().Insert(s)
+		return err
+	}
+	_, err := db.DefaultContext().Engine().ID(s.ID).Cols("enabled", "open_issues", "doer_id", "checked_commit_sha", "checked_unix").Update(s)
+	return err
+}
+
+// UpdateRepoVulnerabilityChecked records the commit and the time the alerts of a repository were updated
+func UpdateRepoVulnerabilityChecked(s *RepoVulnerabilitySetting) error {
+	_, err := db.DefaultContext().Engine().ID(s.ID).Cols("checked_commit_sha", "checked_unix").Update(s)
+	return err
+}
+
+// FindEnabledRepoVulnerabilitySettings returns the settings of the repositories with enabled alerts
+func FindEnabledRepoVulnerabilitySettings() ([]*RepoVulnerabilitySetting, error) {
+	settings := make([]*RepoVulnerabilitySetting, 0, 10)
+	return settings, db.DefaultContext().Engine().Where("enabled = ?", true).Asc("repo_id").Find(&settings)
+}
+
+// LoadAttributes loads the advisory and the dismisser of the alert
+func (a *VulnerabilityAlert) LoadAttributes() (err error) {
+	if a.Advisory == nil {
+		if a.Advisory, err = GetSecurityAdvisoryByID(a.AdvisoryID); err != nil {
+			return err
+		}
+	}
+	if a.Dismisser == nil && a.DismisserID != 0 {
+		if a.Dismisser, err = GetUserByID(a.DismisserID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			a.Dismisser = NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// FindVulnerabilityAlertOptions represents the options to find vulnerability alerts of a repository
+type FindVulnerabilityAlertOptions struct {
+	ListOptions
+	RepoID int64
+	// States limits the alerts to these states, all alerts are returned if it is empty
+	States []VulnerabilityAlertState
+}
+
+func (opts *FindVulnerabilityAlertOptions) toConds() builder.Cond {
+	cond := builder.NewCond().And(builder.Eq{"repo_id": opts.RepoID})
+	if len(opts.States) > 0 {
+		cond = cond.And(builder.In("state", opts.States))
+	}
+	return cond
+}
+
+// FindVulnerabilityAlerts returns the alerts of a repository matching the options and their total count, newest first
+func FindVulnerabilityAlerts(opts *FindVulnerabilityAlertOptions) ([]*VulnerabilityAlert, int64, error) {
+	sess := db.DefaultContext().Engine().Where(opts.toConds()).Desc("id")
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, opts)
+	}
+	alerts := make([]*VulnerabilityAlert, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&alerts)
+	return alerts, count, err
+}
+
+// GetVulnerabilityAlertByID returns the alert of a repository with the given id
+func GetVulnerabilityAlertByID(repoID, id int64) (*VulnerabilityAlert, error) {
+	alert := new(VulnerabilityAlert)
+	has, err := db.DefaultContext().Engine().Where("id = ? AND repo_id = ?", id, repoID).Get(alert)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrVulnerabilityAlertNotExist
+	}
+	return alert, nil
+}
+
+// InsertVulnerabilityAlert inserts a new alert
+func InsertVulnerabilityAlert(alert *VulnerabilityAlert) error {
+	_, err := db.DefaultContext().Engine().Insert(alert)
+	return err
+}
+
+// UpdateVulnerabilityAlert updates the given columns of an alert
+func UpdateVulnerabilityAlert(alert *VulnerabilityAlert, cols ...string) error {
+	_, err := db.DefaultContext().Engine().ID(alert.ID).Cols(cols...).Update(alert)
+	return err
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertSecurityAdvisories(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	changed, err := UpsertSecurityAdvisories([]*SecurityAdvisory{
+		{OSVID: "GHSA-1", Ecosystem: DependencyEcosystemNpm, Package: "lodash", ModifiedUnix: 100, Ranges: []*AdvisoryVersionRange{{Fixed: "4.17.21"}}},
+		{OSVID: "GHSA-2", Ecosystem: DependencyEcosystemNpm, Package: "lodash", ModifiedUnix: 100, WithdrawnUnix: 100},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, changed)
+
+	// unmodified advisories are not updated
+	changed, err = UpsertSecurityAdvisories([]*SecurityAdvisory{
+		{OSVID: "GHSA-1", Ecosystem: DependencyEcosystemNpm, Package: "lodash", ModifiedUnix: 100},
+		{OSVID: "GHSA-2", Ecosystem: DependencyEcosystemNpm, Package: "lodash", ModifiedUnix: 200},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	advisories, err := FindSecurityAdvisoriesByPackage(DependencyEcosystemNpm, "lodash")
+	assert.NoError(t, err)
+	if assert.Len(t, advisories, 2) {
+		assert.Equal(t, "GHSA-1", advisories[0].OSVID)
+		assert.Equal(t, []*AdvisoryVersionRange{{Fixed: "4.17.21"}}, advisories[0].Ranges)
+		assert.EqualValues(t, 200, advisories[1].ModifiedUnix)
+	}
+}
+
+func TestVulnerabilityAlerts(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	s, err := GetRepoVulnerabilitySetting(1)
+	assert.NoError(t, err)
+	assert.False(t, s.Enabled)
+	s.Enabled, s.DoerID = true, 2
+	assert.NoError(t, SaveRepoVulnerabilitySetting(s))
+
+	settings, err := FindEnabledRepoVulnerabilitySettings()
+	assert.NoError(t, err)
+	if assert.Len(t, settings, 1) {
+		assert.EqualValues(t, 1, settings[0].RepoID)
+	}
+
+	_, err = UpsertSecurityAdvisories([]*SecurityAdvisory{{OSVID: "GHSA-1", Ecosystem: DependencyEcosystemGo, Package: "xorm.io/xorm"}})
+	assert.NoError(t, err)
+	advisories, err := FindSecurityAdvisoriesByPackage(DependencyEcosystemGo, "xorm.io/xorm")
+	assert.NoError(t, err)
+	assert.Len(t, advisories, 1)
+
+	open := &VulnerabilityAlert{RepoID: 1, AdvisoryID: advisories[0].ID, Manifest: "go.mod", Ecosystem: DependencyEcosystemGo, Package: "xorm.io/xorm", Version: "v1.2.2"}
+	assert.NoError(t, InsertVulnerabilityAlert(open))
+	dismissed := &VulnerabilityAlert{RepoID: 1, AdvisoryID: advisories[0].ID, Manifest: "tools/go.mod", Ecosystem: DependencyEcosystemGo, Package: "xorm.io/xorm", Version: "v1.2.2"}
+	assert.NoError(t, InsertVulnerabilityAlert(dismissed))
+	dismissed.State, dismissed.DismissReason, dismissed.DismisserID = VulnerabilityAlertStateDismissed, "not_used", 2
+	assert.NoError(t, UpdateVulnerabilityAlert(dismissed, "state", "dismiss_reason", "dismisser_id"))
+
+	alerts, count, err := FindVulnerabilityAlerts(&FindVulnerabilityAlertOptions{RepoID: 1, States: []VulnerabilityAlertState{VulnerabilityAlertStateOpen}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "go.mod", alerts[0].Manifest)
+	}
+
+	alert, err := GetVulnerabilityAlertByID(1, dismissed.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, alert.LoadAttributes())
+	assert.Equal(t, "GHSA-1", alert.Advisory.OSVID)
+	assert.EqualValues(t, 2, alert.Dismisser.ID)
+
+	_, err = GetVulnerabilityAlertByID(2, dismissed.ID)
+	assert.Equal(t, ErrVulnerabilityAlertNotExist, err)
+}
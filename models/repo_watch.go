@@ -8,7 +8,6 @@ import (
 	"fmt"
 
 	"code.gitea.io/gitea/models/db"
-	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 )
 
@@ -22,7 +21,7 @@ const (
 	RepoWatchModeNormal // 1
 	// RepoWatchModeDont explicit don't auto-watch
 	RepoWatchModeDont // 2
-	// RepoWatchModeAuto watch repository (from AutoWatchOnChanges)
+	// RepoWatchModeAuto watch repository (from the auto-watch rule of the user for pushes)
 	RepoWatchModeAuto // 3
 )
 
@@ -32,6 +31,8 @@ type Watch struct {
 	UserID      int64              `xorm:"UNIQUE(watch)"`
 	RepoID      int64              `xorm:"UNIQUE(watch)"`
 	Mode        RepoWatchMode      `xorm:"SMALLINT NOT NULL DEFAULT 1"`
+	Level       SubscriptionLevel  `xorm:"NOT NULL DEFAULT 0"`
+	Events      SubscriptionEvents `xorm:"NOT NULL DEFAULT 0"`
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 }
@@ -303,7 +304,14 @@ func NotifyWatchersActions(acts []*Action) error {
 }
 
 func watchIfAuto(e db.Engine, userID, repoID int64, isWrite bool) error {
-	if !isWrite || !setting.Service.AutoWatchOnChanges {
+	if !isWrite {
+		return nil
+	}
+	u, err := getUserByID(e, userID)
+	if err != nil {
+		return err
+	}
+	if !u.AutoWatchesOnPush() {
 		return nil
 	}
 	watch, err := getWatch(e, userID, repoID)
@@ -316,7 +324,7 @@ func watchIfAuto(e db.Engine, userID, repoID int64, isWrite bool) error {
 	return watchRepoMode(e, watch, RepoWatchModeAuto)
 }
 
-// WatchIfAuto subscribes to repo if AutoWatchOnChanges is set
+// WatchIfAuto subscribes to repo if the auto-watch rule of the user for pushes applies
 func WatchIfAuto(userID, repoID int64, isWrite bool) error {
 	return watchIfAuto(db.DefaultContext().Engine(), userID, repoID, isWrite)
 }
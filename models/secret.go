@@ -0,0 +1,143 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+var (
+	// ErrSecretNotExist indicates a secret not exist error
+	ErrSecretNotExist = errors.New("Secret does not exist")
+	// ErrSecretNameInvalid indicates an invalid secret name
+	ErrSecretNameInvalid = errors.New("Secret name is invalid")
+	// ErrSecretDataTooLarge indicates a secret value exceeding MaxSecretDataSize
+	ErrSecretDataTooLarge = errors.New("Secret value is too large")
+)
+
+// MaxSecretDataSize is the maximum size of a secret value in bytes
+const MaxSecretDataSize = 64 * 1024
+
+var secretNamePattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// Secret represents an encrypted value which belongs either to an owner or to a repository
+type Secret struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"UNIQUE(owner_repo_name) INDEX NOT NULL DEFAULT 0"`
+	RepoID      int64              `xorm:"UNIQUE(owner_repo_name) INDEX NOT NULL DEFAULT 0"`
+	Name        string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+	Data        string             `xorm:"LONGTEXT"` // encrypted with the secret key of the server
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(Secret))
+}
+
+// NormalizeSecretName returns the upper case name of a secret and checks if it is valid.
+// Names must consist of letters, digits and underscores, must not start with a digit
+// and must not use the reserved GITEA_ and GITHUB_ prefixes.
+func NormalizeSecretName(name string) (string, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if len(name) > 255 || !secretNamePattern.MatchString(name) || strings.HasPrefix(name, "GITEA_") || strings.HasPrefix(name, "GITHUB_") {
+		return "", ErrSecretNameInvalid
+	}
+	return name, nil
+}
+
+// Decrypt returns the plain value of the secret
+func (s *Secret) Decrypt() (string, error) {
+	return secret.DecryptSecret(setting.SecretKey, s.Data)
+}
+
+// CreateOrUpdateSecret stores the encrypted value of a secret of an owner or a repository.
+// It returns true if a new secret was created.
+func CreateOrUpdateSecret(ownerID, repoID int64, name, data string) (bool, error) {
+	name, err := NormalizeSecretName(name)
+	if err != nil {
+		return false, err
+	}
+	if len(data) > MaxSecretDataSize {
+		return false, ErrSecretDataTooLarge
+	}
+	encrypted, err := secret.EncryptSecret(setting.SecretKey, data)
+	if err != nil {
+		return false, err
+	}
+
+	var created bool
+	return created, db.WithTx(func(ctx *db.Context) error {
+		s := &Secret{OwnerID: ownerID, RepoID: repoID, Name: name}
+		has, err := ctx.Engine().Get(s)
+		if err != nil {
+			return err
+		}
+		s.Data = encrypted
+		if has {
+			_, err = ctx.Engine().ID(s.ID).Cols("data").Update(s)
+			return err
+		}
+		created = true
+		_, err = ctx.Engine().Insert(s)
+		return err
+	})
+}
+
+// GetSecret returns the secret of an owner or a repository by its name
+func GetSecret(ownerID, repoID int64, name string) (*Secret, error) {
+	name, err := NormalizeSecretName(name)
+	if err != nil {
+		return nil, ErrSecretNotExist
+	}
+	s := &Secret{OwnerID: ownerID, RepoID: repoID, Name: name}
+	has, err := db.DefaultContext().Engine().Get(s)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrSecretNotExist
+	}
+	return s, nil
+}
+
+// FindSecretsOptions represents the options to find secrets
+type FindSecretsOptions struct {
+	ListOptions
+	OwnerID int64
+	RepoID  int64
+}
+
+// FindSecrets returns the secrets of an owner or a repository ordered by name
+func FindSecrets(opts *FindSecretsOptions) ([]*Secret, int64, error) {
+	sess := db.DefaultContext().Engine().
+		Where(builder.Eq{"owner_id": opts.OwnerID, "repo_id": opts.RepoID}).
+		Asc("name")
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, opts)
+	}
+	secrets := make([]*Secret, 0, 10)
+	count, err := sess.FindAndCount(&secrets)
+	return secrets, count, err
+}
+
+// DeleteSecret deletes a secret of an owner or a repository
+func DeleteSecret(ownerID, repoID int64, name string) error {
+	s, err := GetSecret(ownerID, repoID, name)
+	if err != nil {
+		return err
+	}
+	_, err = db.DefaultContext().Engine().ID(s.ID).Delete(&Secret{})
+	return err
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSecretName(t *testing.T) {
+	for _, name := range []string{"token", "MY_TOKEN", "_x", "a1"} {
+		_, err := NormalizeSecretName(name)
+		assert.NoError(t, err, name)
+	}
+	for _, name := range []string{"", "1a", "my-token", "GITEA_TOKEN", "github_token"} {
+		_, err := NormalizeSecretName(name)
+		assert.Equal(t, ErrSecretNameInvalid, err, name)
+	}
+}
+
+func TestCreateOrUpdateSecret(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	created, err := CreateOrUpdateSecret(0, 1, "token", "value1")
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = CreateOrUpdateSecret(0, 1, "TOKEN", "value2")
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	s, err := GetSecret(0, 1, "Token")
+	assert.NoError(t, err)
+	assert.Equal(t, "TOKEN", s.Name)
+	assert.NotEqual(t, "value2", s.Data)
+	value, err := s.Decrypt()
+	assert.NoError(t, err)
+	assert.Equal(t, "value2", value)
+
+	_, err = GetSecret(3, 0, "TOKEN")
+	assert.Equal(t, ErrSecretNotExist, err)
+
+	secrets, count, err := FindSecrets(&FindSecretsOptions{RepoID: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Len(t, secrets, 1)
+
+	assert.NoError(t, DeleteSecret(0, 1, "token"))
+	assert.Equal(t, ErrSecretNotExist, DeleteSecret(0, 1, "token"))
+}
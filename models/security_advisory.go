@@ -0,0 +1,123 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ErrSecurityAdvisoryNotExist indicates a security advisory not exist error
+var ErrSecurityAdvisoryNotExist = errors.New("Security advisory does not exist")
+
+// Severities of security advisories
+const (
+	AdvisorySeverityLow      = "low"
+	AdvisorySeverityModerate = "moderate"
+	AdvisorySeverityHigh     = "high"
+	AdvisorySeverityCritical = "critical"
+)
+
+// AdvisoryVersionRange is a range of affected versions. A version is affected if it is not lower than Introduced
+// and lower than Fixed or not greater than LastAffected, an empty bound does not limit the range.
+type AdvisoryVersionRange struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// SecurityAdvisory represents a package affected by an advisory of the OSV database,
+// an advisory affecting several packages is stored once for each package
+type SecurityAdvisory struct {
+	ID    int64  `xorm:"pk autoincr"`
+	OSVID string `xorm:"'osv_id' UNIQUE(s) VARCHAR(255) NOT NULL"`
+	// Ecosystem is the package ecosystem of the affected package, e.g. go, npm, pypi or composer
+	Ecosystem string `xorm:"UNIQUE(s) VARCHAR(20) NOT NULL"`
+	// Package is the normalized name of the affected package
+	Package  string   `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	Aliases  []string `xorm:"JSON TEXT"`
+	Summary  string   `xorm:"TEXT"`
+	Severity string   `xorm:"VARCHAR(20)"`
+	URL      string   `xorm:"TEXT"`
+	// Ranges and Versions list the affected versions
+	Ranges        []*AdvisoryVersionRange `xorm:"JSON TEXT"`
+	Versions      []string                `xorm:"JSON TEXT"`
+	PublishedUnix timeutil.TimeStamp
+	ModifiedUnix  timeutil.TimeStamp
+	// WithdrawnUnix is set if the advisory was withdrawn, withdrawn advisories do not raise alerts
+	WithdrawnUnix timeutil.TimeStamp
+	UpdatedUnix   timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func init() {
+	db.RegisterModel(new(SecurityAdvisory))
+}
+
+// UpsertSecurityAdvisories inserts new advisories and updates the ones modified since they were stored,
+// it returns the number of inserted or updated advisories
+func UpsertSecurityAdvisories(advisories []*SecurityAdvisory) (int, error) {
+	changed := 0
+	err := db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		for _, adv := range advisories {
+			existing := &SecurityAdvisory{OSVID: adv.OSVID, Ecosystem: adv.Ecosystem, Package: adv.Package}
+			has, err := e.Get(existing)
+			if err != nil {
+				return err
+			}
+			if !has {
+				adv.ID = 0
+				if _, err := e.Insert(adv); err != nil {
+					return err
+				}
+				changed++
+				continue
+			}
+			if existing.ModifiedUnix == adv.ModifiedUnix && existing.WithdrawnUnix == adv.WithdrawnUnix {
+				continue
+			}
+			adv.ID = existing.ID
+			if _, err := e.ID(adv.ID).AllCols().Update(adv); err != nil {
+				return err
+			}
+			changed++
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// GetSecurityAdvisoryByID returns the security advisory with the given id
+func GetSecurityAdvisoryByID(id int64) (*SecurityAdvisory, error) {
+	adv := new(SecurityAdvisory)
+	has, err := db.DefaultContext().Engine().ID(id).Get(adv)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrSecurityAdvisoryNotExist
+	}
+	return adv, nil
+}
+
+// FindSecurityAdvisoriesByPackage returns the advisories affecting a package which were not withdrawn
+func FindSecurityAdvisoriesByPackage(ecosystem, pkg string) ([]*SecurityAdvisory, error) {
+	advisories := make([]*SecurityAdvisory, 0, 5)
+	return advisories, db.DefaultContext().Engine().
+		Where("ecosystem = ? AND package = ? AND withdrawn_unix = 0", ecosystem, pkg).
+		Asc("id").
+		Find(&advisories)
+}
+
+// GetLatestSecurityAdvisoryUpdate returns the time advisories were last inserted or updated
+func GetLatestSecurityAdvisoryUpdate() (timeutil.TimeStamp, error) {
+	adv := new(SecurityAdvisory)
+	has, err := db.DefaultContext().Engine().Desc("updated_unix").Cols("updated_unix").Get(adv)
+	if err != nil || !has {
+		return 0, err
+	}
+	return adv.UpdatedUnix, nil
+}
@@ -30,37 +30,50 @@ func StarRepo(userID, repoID int64, star bool) error {
 		return err
 	}
 
+	if err := starRepo(sess, userID, repoID, star); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+func starRepo(e db.Engine, userID, repoID int64, star bool) error {
 	if star {
-		if isStaring(sess, userID, repoID) {
+		if isStaring(e, userID, repoID) {
 			return nil
 		}
 
-		if _, err := sess.Insert(&Star{UID: userID, RepoID: repoID}); err != nil {
+		if _, err := e.Insert(&Star{UID: userID, RepoID: repoID}); err != nil {
 			return err
 		}
-		if _, err := sess.Exec("UPDATE `repository` SET num_stars = num_stars + 1 WHERE id = ?", repoID); err != nil {
+		if _, err := e.Exec("UPDATE `repository` SET num_stars = num_stars + 1 WHERE id = ?", repoID); err != nil {
 			return err
 		}
-		if _, err := sess.Exec("UPDATE `user` SET num_stars = num_stars + 1 WHERE id = ?", userID); err != nil {
+		if _, err := e.Exec("UPDATE `user` SET num_stars = num_stars + 1 WHERE id = ?", userID); err != nil {
 			return err
 		}
-	} else {
-		if !isStaring(sess, userID, repoID) {
-			return nil
-		}
 
-		if _, err := sess.Delete(&Star{UID: userID, RepoID: repoID}); err != nil {
-			return err
-		}
-		if _, err := sess.Exec("UPDATE `repository` SET num_stars = num_stars - 1 WHERE id = ?", repoID); err != nil {
-			return err
-		}
-		if _, err := sess.Exec("UPDATE `user` SET num_stars = num_stars - 1 WHERE id = ?", userID); err != nil {
+		list, err := getOrCreateDefaultStarList(e, userID)
+		if err != nil {
 			return err
 		}
+		return addRepoToStarList(e, list.ID, repoID)
 	}
 
-	return sess.Commit()
+	if !isStaring(e, userID, repoID) {
+		return nil
+	}
+
+	if _, err := e.Delete(&Star{UID: userID, RepoID: repoID}); err != nil {
+		return err
+	}
+	if _, err := e.Exec("UPDATE `repository` SET num_stars = num_stars - 1 WHERE id = ?", repoID); err != nil {
+		return err
+	}
+	if _, err := e.Exec("UPDATE `user` SET num_stars = num_stars - 1 WHERE id = ?", userID); err != nil {
+		return err
+	}
+	return removeRepoFromStarLists(e, userID, repoID)
 }
 
 // IsStaring checks if user has starred given repository.
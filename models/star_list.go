@@ -0,0 +1,251 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+var (
+	// ErrStarListNotExist indicates a star list not exist error
+	ErrStarListNotExist = errors.New("Star list does not exist")
+	// ErrStarListAlreadyExist indicates a star list with the same name exists already
+	ErrStarListAlreadyExist = errors.New("Star list already exists")
+	// ErrStarListNameInvalid indicates an empty or too long name of a star list
+	ErrStarListNameInvalid = errors.New("Star list name is invalid")
+	// ErrStarListIsDefault indicates that an operation is not possible on the default star list
+	ErrStarListIsDefault = errors.New("Star list is the default list")
+)
+
+// DefaultStarListName is the name of the list every starred repository is added to
+const DefaultStarListName = "Starred"
+
+// StarList is a named collection of repositories starred by a user
+type StarList struct {
+	ID          int64  `xorm:"pk autoincr"`
+	UserID      int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name        string `xorm:"UNIQUE(s) NOT NULL"`
+	Description string `xorm:"TEXT"`
+	IsPrivate   bool   `xorm:"NOT NULL DEFAULT false"`
+	// IsDefault marks the list holding all repositories starred by the user
+	IsDefault   bool               `xorm:"NOT NULL DEFAULT false"`
+	NumRepos    int64              `xorm:"-"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// StarListRepo represents a repository in a star list
+type StarListRepo struct {
+	ID          int64              `xorm:"pk autoincr"`
+	ListID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(StarList))
+	db.RegisterModel(new(StarListRepo))
+}
+
+func (list *StarList) normalize() error {
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Name == "" || len(list.Name) > 255 {
+		return ErrStarListNameInvalid
+	}
+	return nil
+}
+
+func isStarListNameUsed(e db.Engine, list *StarList) (bool, error) {
+	return e.Where("user_id = ? AND name = ? AND id != ?", list.UserID, list.Name, list.ID).Exist(new(StarList))
+}
+
+// CreateStarList creates a new star list
+func CreateStarList(list *StarList) error {
+	if err := list.normalize(); err != nil {
+		return err
+	}
+	list.IsDefault = false
+	return db.WithTx(func(ctx *db.Context) error {
+		if used, err := isStarListNameUsed(ctx.Engine(), list); err != nil {
+			return err
+		} else if used {
+			return ErrStarListAlreadyExist
+		}
+		_, err := ctx.Engine().Insert(list)
+		return err
+	})
+}
+
+// UpdateStarList updates the name, the description and the privacy of a star list
+func UpdateStarList(list *StarList) error {
+	if err := list.normalize(); err != nil {
+		return err
+	}
+	return db.WithTx(func(ctx *db.Context) error {
+		if used, err := isStarListNameUsed(ctx.Engine(), list); err != nil {
+			return err
+		} else if used {
+			return ErrStarListAlreadyExist
+		}
+		_, err := ctx.Engine().ID(list.ID).Cols("name", "description", "is_private").Update(list)
+		return err
+	})
+}
+
+// GetStarListByID returns the star list of a user by its id
+func GetStarListByID(userID, id int64) (*StarList, error) {
+	list := &StarList{ID: id, UserID: userID}
+	has, err := db.DefaultContext().Engine().Get(list)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrStarListNotExist
+	}
+	if list.NumRepos, err = db.DefaultContext().Engine().Where("list_id = ?", list.ID).Count(new(StarListRepo)); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// FindStarLists returns the star lists of a user, the default list first and the others ordered by name
+func FindStarLists(userID int64, includePrivate bool, listOptions ListOptions) ([]*StarList, int64, error) {
+	e := db.DefaultContext().Engine()
+	sess := e.Where("user_id = ?", userID)
+	if !includePrivate {
+		sess = sess.And("is_private = ?", false)
+	}
+	sess = sess.Desc("is_default").Asc("name")
+	if listOptions.Page > 0 {
+		sess = setSessionPagination(sess, &listOptions)
+	}
+	lists := make([]*StarList, 0, 10)
+	count, err := sess.FindAndCount(&lists)
+	if err != nil || len(lists) == 0 {
+		return lists, count, err
+	}
+
+	listIDs := make([]int64, 0, len(lists))
+	for _, list := range lists {
+		listIDs = append(listIDs, list.ID)
+	}
+	counts := make([]struct {
+		ListID int64
+		Count  int64
+	}, 0, len(lists))
+	if err := e.Table("star_list_repo").Select("list_id, count(*) AS count").
+		In("list_id", listIDs).GroupBy("list_id").Find(&counts); err != nil {
+		return nil, 0, err
+	}
+	numRepos := make(map[int64]int64, len(counts))
+	for _, c := range counts {
+		numRepos[c.ListID] = c.Count
+	}
+	for _, list := range lists {
+		list.NumRepos = numRepos[list.ID]
+	}
+	return lists, count, nil
+}
+
+// DeleteStarList deletes a star list of a user, the repositories in it stay starred
+func DeleteStarList(userID, id int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		list := &StarList{ID: id, UserID: userID}
+		if has, err := e.Get(list); err != nil {
+			return err
+		} else if !has {
+			return ErrStarListNotExist
+		}
+		if list.IsDefault {
+			return ErrStarListIsDefault
+		}
+		if _, err := e.Delete(&StarListRepo{ListID: list.ID}); err != nil {
+			return err
+		}
+		_, err := e.ID(list.ID).Delete(new(StarList))
+		return err
+	})
+}
+
+// getOrCreateDefaultStarList returns the default star list of a user and creates it if needed
+func getOrCreateDefaultStarList(e db.Engine, userID int64) (*StarList, error) {
+	list := &StarList{UserID: userID, IsDefault: true}
+	has, err := e.Get(list)
+	if err != nil {
+		return nil, err
+	} else if has {
+		return list, nil
+	}
+
+	list = &StarList{UserID: userID, Name: DefaultStarListName, IsDefault: true}
+	// a list created by the user may have taken the name already
+	if used, err := isStarListNameUsed(e, list); err != nil {
+		return nil, err
+	} else if used {
+		list.Name = DefaultStarListName + " (default)"
+	}
+	_, err = e.Insert(list)
+	return list, err
+}
+
+func addRepoToStarList(e db.Engine, listID, repoID int64) error {
+	if has, err := e.Exist(&StarListRepo{ListID: listID, RepoID: repoID}); err != nil || has {
+		return err
+	}
+	_, err := e.Insert(&StarListRepo{ListID: listID, RepoID: repoID})
+	return err
+}
+
+// removeRepoFromStarLists removes a repository from all star lists of a user
+func removeRepoFromStarLists(e db.Engine, userID, repoID int64) error {
+	_, err := e.Where("repo_id = ?", repoID).
+		And("list_id IN (SELECT id FROM star_list WHERE user_id = ?)", userID).
+		Delete(new(StarListRepo))
+	return err
+}
+
+// AddRepoToStarList adds a repository to a star list, starring it if the user did not yet
+func AddRepoToStarList(list *StarList, repoID int64) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		if err := starRepo(ctx.Engine(), list.UserID, repoID, true); err != nil {
+			return err
+		}
+		return addRepoToStarList(ctx.Engine(), list.ID, repoID)
+	})
+}
+
+// RemoveRepoFromStarList removes a repository from a star list. Removing it from the default list unstars it.
+func RemoveRepoFromStarList(list *StarList, repoID int64) error {
+	if list.IsDefault {
+		return StarRepo(list.UserID, repoID, false)
+	}
+	_, err := db.DefaultContext().Engine().Delete(&StarListRepo{ListID: list.ID, RepoID: repoID})
+	return err
+}
+
+// GetStarListRepos returns the repositories in a star list, ordered by the time they were added
+func GetStarListRepos(list *StarList, private bool, listOptions ListOptions) (RepositoryList, int64, error) {
+	e := db.DefaultContext().Engine()
+	sess := e.Join("INNER", "star_list_repo", "star_list_repo.repo_id = repository.id").
+		Where("star_list_repo.list_id = ?", list.ID)
+	if !private {
+		sess = sess.And("repository.is_private = ?", false)
+	}
+	sess = sess.Desc("star_list_repo.created_unix").Desc("star_list_repo.id")
+	if listOptions.Page > 0 {
+		sess = setSessionPagination(sess, &listOptions)
+	}
+
+	repos := make(RepositoryList, 0, 10)
+	count, err := sess.FindAndCount(&repos)
+	if err != nil {
+		return nil, 0, err
+	}
+	return repos, count, repos.loadAttributes(e)
+}
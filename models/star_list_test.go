@@ -0,0 +1,111 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStarList(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	list := &StarList{UserID: 2, Name: " Reading "}
+	assert.NoError(t, CreateStarList(list))
+	assert.Equal(t, "Reading", list.Name)
+	assert.False(t, list.IsDefault)
+
+	assert.Equal(t, ErrStarListAlreadyExist, CreateStarList(&StarList{UserID: 2, Name: "Tools"}))
+	assert.Equal(t, ErrStarListNameInvalid, CreateStarList(&StarList{UserID: 2, Name: " "}))
+	assert.NoError(t, CreateStarList(&StarList{UserID: 4, Name: "Tools"}))
+
+	list.Name = "Tools"
+	assert.Equal(t, ErrStarListAlreadyExist, UpdateStarList(list))
+	list.Name = "Later"
+	list.IsPrivate = true
+	assert.NoError(t, UpdateStarList(list))
+	db.AssertExistsAndLoadBean(t, &StarList{ID: list.ID, Name: "Later", IsPrivate: true})
+}
+
+func TestFindStarLists(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	lists, count, err := FindStarLists(2, true, ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, lists, 2) {
+		assert.True(t, lists[0].IsDefault)
+		assert.EqualValues(t, 2, lists[0].NumRepos)
+		assert.Equal(t, "Tools", lists[1].Name)
+		assert.EqualValues(t, 1, lists[1].NumRepos)
+	}
+
+	lists, count, err = FindStarLists(2, false, ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Len(t, lists, 1)
+}
+
+func TestStarListRepos(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	defaultList, err := GetStarListByID(2, 1)
+	assert.NoError(t, err)
+	tools, err := GetStarListByID(2, 2)
+	assert.NoError(t, err)
+
+	// adding a repository to a list stars it
+	assert.False(t, IsStaring(2, 1))
+	assert.NoError(t, AddRepoToStarList(tools, 1))
+	assert.True(t, IsStaring(2, 1))
+	db.AssertExistsAndLoadBean(t, &StarListRepo{ListID: defaultList.ID, RepoID: 1})
+	db.AssertExistsAndLoadBean(t, &StarListRepo{ListID: tools.ID, RepoID: 1})
+
+	repos, count, err := GetStarListRepos(tools, true, ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Len(t, repos, 2)
+
+	// removing it from a list keeps the star
+	assert.NoError(t, RemoveRepoFromStarList(tools, 1))
+	assert.True(t, IsStaring(2, 1))
+	db.AssertNotExistsBean(t, &StarListRepo{ListID: tools.ID, RepoID: 1})
+
+	// unstarring removes it from all lists
+	assert.NoError(t, AddRepoToStarList(tools, 1))
+	assert.NoError(t, StarRepo(2, 1, false))
+	db.AssertNotExistsBean(t, &StarListRepo{RepoID: 1})
+
+	// removing it from the default list unstars it
+	assert.NoError(t, RemoveRepoFromStarList(defaultList, 4))
+	assert.False(t, IsStaring(2, 4))
+	db.AssertNotExistsBean(t, &StarListRepo{RepoID: 4})
+
+	CheckConsistencyFor(t, &User{}, &Repository{})
+}
+
+func TestStarRepoCreatesDefaultStarList(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	db.AssertNotExistsBean(t, &StarList{UserID: 4})
+	assert.NoError(t, StarRepo(4, 1, true))
+	list := db.AssertExistsAndLoadBean(t, &StarList{UserID: 4, IsDefault: true}).(*StarList)
+	assert.Equal(t, DefaultStarListName, list.Name)
+	db.AssertExistsAndLoadBean(t, &StarListRepo{ListID: list.ID, RepoID: 1})
+}
+
+func TestDeleteStarList(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.Equal(t, ErrStarListIsDefault, DeleteStarList(2, 1))
+	assert.Equal(t, ErrStarListNotExist, DeleteStarList(4, 2))
+	assert.NoError(t, DeleteStarList(2, 2))
+	db.AssertNotExistsBean(t, &StarList{ID: 2})
+	db.AssertNotExistsBean(t, &StarListRepo{ListID: 2})
+	assert.True(t, IsStaring(2, 4))
+}
@@ -0,0 +1,256 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// ErrNotWatching indicates that the subscription of a user who is not watching can not be changed
+var ErrNotWatching = errors.New("User is not watching")
+
+// SubscriptionLevel defines which events of an issue are delivered to a watcher
+type SubscriptionLevel int
+
+// Note: new level must append to the end of list to maintain compatibility.
+const (
+	SubscriptionLevelAll           SubscriptionLevel = iota // all activity
+	SubscriptionLevelParticipating                          // only mentions and activity on issues the user authored, is assigned to or commented on
+	SubscriptionLevelCustom                                 // only the selected events
+)
+
+var subscriptionLevelNames = map[SubscriptionLevel]string{
+	SubscriptionLevelAll:           "all",
+	SubscriptionLevelParticipating: "participating",
+	SubscriptionLevelCustom:        "custom",
+}
+
+// String returns the name of the level
+func (l SubscriptionLevel) String() string {
+	return subscriptionLevelNames[l]
+}
+
+// SubscriptionLevelFromString returns the level with the given name
+func SubscriptionLevelFromString(name string) (SubscriptionLevel, bool) {
+	for l, n := range subscriptionLevelNames {
+		if n == name {
+			return l, true
+		}
+	}
+	return 0, false
+}
+
+// SubscriptionEvents is a set of issue events a custom subscription delivers
+type SubscriptionEvents int
+
+// Note: new event must append to the end of list to maintain compatibility.
+const (
+	SubscriptionEventIssue       SubscriptionEvents = 1 << iota // opened issues and pull requests
+	SubscriptionEventComment                                    // comments and reviews
+	SubscriptionEventStateChange                                // closing, reopening and merging
+	SubscriptionEventPush                                       // commits pushed to pull requests
+	SubscriptionEventMention                                    // mentions, assignments and review requests
+)
+
+var subscriptionEventNames = []struct {
+	Event SubscriptionEvents
+	Name  string
+}{
+	{SubscriptionEventIssue, "issue"},
+	{SubscriptionEventComment, "comment"},
+	{SubscriptionEventStateChange, "state_change"},
+	{SubscriptionEventPush, "push"},
+	{SubscriptionEventMention, "mention"},
+}
+
+// Names returns the names of the events in the set
+func (events SubscriptionEvents) Names() []string {
+	names := make([]string, 0, len(subscriptionEventNames))
+	for _, e := range subscriptionEventNames {
+		if events&e.Event != 0 {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// ParseSubscriptionEvents returns the set of the events with the given names
+func ParseSubscriptionEvents(names []string) (SubscriptionEvents, error) {
+	var events SubscriptionEvents
+outer:
+	for _, name := range names {
+		for _, e := range subscriptionEventNames {
+			if e.Name == name {
+				events |= e.Event
+				continue outer
+			}
+		}
+		return 0, fmt.Errorf("unknown subscription event: %s", name)
+	}
+	return events, nil
+}
+
+// SubscriptionEventFromActionType returns the subscription event of an issue action
+func SubscriptionEventFromActionType(opType ActionType) SubscriptionEvents {
+	switch opType {
+	case ActionCreateIssue, ActionCreatePullRequest, ActionPullRequestReadyForReview:
+		return SubscriptionEventIssue
+	case ActionCloseIssue, ActionReopenIssue, ActionClosePullRequest, ActionReopenPullRequest, ActionMergePullRequest:
+		return SubscriptionEventStateChange
+	default:
+		return SubscriptionEventComment
+	}
+}
+
+// Subscription is the level and the selected events of a watch
+type Subscription struct {
+	Level  SubscriptionLevel
+	Events SubscriptionEvents
+}
+
+// Delivers returns whether the event is delivered to a watcher with this subscription
+func (s Subscription) Delivers(event SubscriptionEvents, isParticipant bool) bool {
+	switch s.Level {
+	case SubscriptionLevelParticipating:
+		return isParticipant || event == SubscriptionEventMention
+	case SubscriptionLevelCustom:
+		return s.Events&event != 0
+	default:
+		return true
+	}
+}
+
+// FilterIssueSubscribers returns the users who should receive the event of an issue according to their subscriptions.
+// The subscription of an issue watch takes precedence over the one of a repository watch,
+// users without either receive all events.
+func FilterIssueSubscribers(issue *Issue, userIDs []int64, event SubscriptionEvents) ([]int64, error) {
+	return filterIssueSubscribers(db.DefaultContext().Engine(), issue, userIDs, event)
+}
+
+func filterIssueSubscribers(e db.Engine, issue *Issue, userIDs []int64, event SubscriptionEvents) ([]int64, error) {
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+
+	subscriptions := make(map[int64]Subscription, len(userIDs))
+	repoWatches := make([]*Watch, 0, len(userIDs))
+	if err := e.Where("repo_id = ?", issue.RepoID).In("user_id", userIDs).Find(&repoWatches); err != nil {
+		return nil, err
+	}
+	for _, w := range repoWatches {
+		if isWatchMode(w.Mode) {
+			subscriptions[w.UserID] = Subscription{Level: w.Level, Events: w.Events}
+		}
+	}
+	issueWatches := make([]*IssueWatch, 0, len(userIDs))
+	if err := e.Where("issue_id = ? AND is_watching = ?", issue.ID, true).In("user_id", userIDs).Find(&issueWatches); err != nil {
+		return nil, err
+	}
+	for _, w := range issueWatches {
+		subscriptions[w.UserID] = Subscription{Level: w.Level, Events: w.Events}
+	}
+
+	var participants map[int64]bool
+	filtered := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		s := subscriptions[id]
+		if s.Level == SubscriptionLevelParticipating && participants == nil {
+			var err error
+			if participants, err = getIssueParticipantSet(e, issue); err != nil {
+				return nil, err
+			}
+		}
+		if s.Delivers(event, participants[id]) {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered, nil
+}
+
+// getIssueParticipantSet returns the poster, the assignees and the commenters of an issue
+func getIssueParticipantSet(e db.Engine, issue *Issue) (map[int64]bool, error) {
+	participants := make(map[int64]bool)
+	ids, err := issue.getParticipantIDsByIssue(e)
+	if err != nil {
+		return nil, err
+	}
+	assigneeIDs := make([]int64, 0, 5)
+	if err := e.Table("issue_assignees").Cols("assignee_id").Where("issue_id = ?", issue.ID).Find(&assigneeIDs); err != nil {
+		return nil, err
+	}
+	for _, id := range append(ids, assigneeIDs...) {
+		participants[id] = true
+	}
+	return participants, nil
+}
+
+// GetIssueSubscription returns the subscription which applies to a user watching an issue
+func GetIssueSubscription(userID int64, issue *Issue) (Subscription, error) {
+	e := db.DefaultContext().Engine()
+	iw, exists, err := getIssueWatch(e, userID, issue.ID)
+	if err != nil {
+		return Subscription{}, err
+	} else if exists && iw.IsWatching {
+		return Subscription{Level: iw.Level, Events: iw.Events}, nil
+	}
+	w, err := getWatch(e, userID, issue.RepoID)
+	if err != nil {
+		return Subscription{}, err
+	} else if isWatchMode(w.Mode) {
+		return Subscription{Level: w.Level, Events: w.Events}, nil
+	}
+	return Subscription{}, nil
+}
+
+// UpdateIssueWatchSubscription subscribes a user to an issue with the given subscription
+func UpdateIssueWatchSubscription(userID, issueID int64, s Subscription) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		e := ctx.Engine()
+		iw, exists, err := getIssueWatch(e, userID, issueID)
+		if err != nil {
+			return err
+		}
+		iw.IsWatching = true
+		iw.Level = s.Level
+		iw.Events = s.Events
+		if !exists {
+			iw.UserID = userID
+			iw.IssueID = issueID
+			_, err = e.Insert(iw)
+		} else {
+			_, err = e.ID(iw.ID).Cols("is_watching", "level", "events", "updated_unix").Update(iw)
+		}
+		return err
+	})
+}
+
+// GetRepoWatchSubscription returns the subscription of a user watching a repository
+func GetRepoWatchSubscription(userID, repoID int64) (Subscription, error) {
+	w, err := getWatch(db.DefaultContext().Engine(), userID, repoID)
+	if err != nil {
+		return Subscription{}, err
+	} else if !isWatchMode(w.Mode) {
+		return Subscription{}, ErrNotWatching
+	}
+	return Subscription{Level: w.Level, Events: w.Events}, nil
+}
+
+// UpdateRepoWatchSubscription changes the subscription of a user watching a repository
+func UpdateRepoWatchSubscription(userID, repoID int64, s Subscription) error {
+	e := db.DefaultContext().Engine()
+	w, err := getWatch(e, userID, repoID)
+	if err != nil {
+		return err
+	} else if !isWatchMode(w.Mode) {
+		return ErrNotWatching
+	}
+	w.Level = s.Level
+	w.Events = s.Events
+	_, err = e.ID(w.ID).Cols("level", "events").Update(&w)
+	return err
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSubscriptionEvents(t *testing.T) {
+	events, err := ParseSubscriptionEvents([]string{"mention", "state_change"})
+	assert.NoError(t, err)
+	assert.Equal(t, SubscriptionEventMention|SubscriptionEventStateChange, events)
+	assert.Equal(t, []string{"state_change", "mention"}, events.Names())
+
+	_, err = ParseSubscriptionEvents([]string{"comment", "unknown"})
+	assert.Error(t, err)
+}
+
+func TestSubscriptionDelivers(t *testing.T) {
+	all := Subscription{}
+	assert.True(t, all.Delivers(SubscriptionEventComment, false))
+
+	participating := Subscription{Level: SubscriptionLevelParticipating}
+	assert.False(t, participating.Delivers(SubscriptionEventComment, false))
+	assert.True(t, participating.Delivers(SubscriptionEventComment, true))
+	assert.True(t, participating.Delivers(SubscriptionEventMention, false))
+
+	custom := Subscription{Level: SubscriptionLevelCustom, Events: SubscriptionEventStateChange}
+	assert.True(t, custom.Delivers(SubscriptionEventStateChange, false))
+	assert.False(t, custom.Delivers(SubscriptionEventComment, true))
+	assert.False(t, custom.Delivers(SubscriptionEventMention, false))
+}
+
+func TestFilterIssueSubscribers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	assert.NoError(t, UpdateRepoWatchSubscription(4, issue.RepoID, Subscription{Level: SubscriptionLevelParticipating}))
+	assert.NoError(t, UpdateRepoWatchSubscription(11, issue.RepoID, Subscription{Level: SubscriptionLevelCustom}))
+	// the subscription of the issue takes precedence over the one of the repository
+	assert.NoError(t, UpdateRepoWatchSubscription(9, issue.RepoID, Subscription{Level: SubscriptionLevelParticipating}))
+	assert.NoError(t, UpdateIssueWatchSubscription(9, issue.ID, Subscription{Level: SubscriptionLevelCustom, Events: SubscriptionEventStateChange}))
+	// user 5 commented on the issue
+	assert.NoError(t, UpdateIssueWatchSubscription(5, issue.ID, Subscription{Level: SubscriptionLevelParticipating}))
+	assert.Equal(t, ErrNotWatching, UpdateRepoWatchSubscription(12, issue.RepoID, Subscription{Level: SubscriptionLevelCustom}))
+
+	userIDs := []int64{1, 4, 5, 9, 11, 12}
+	ids, err := FilterIssueSubscribers(issue, userIDs, SubscriptionEventComment)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 5, 12}, ids)
+
+	ids, err = FilterIssueSubscribers(issue, userIDs, SubscriptionEventStateChange)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 5, 9, 12}, ids)
+
+	ids, err = FilterIssueSubscribers(issue, userIDs, SubscriptionEventMention)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 4, 5, 12}, ids)
+
+	s, err := GetIssueSubscription(9, issue)
+	assert.NoError(t, err)
+	assert.Equal(t, Subscription{Level: SubscriptionLevelCustom, Events: SubscriptionEventStateChange}, s)
+	s, err = GetIssueSubscription(4, issue)
+	assert.NoError(t, err)
+	assert.Equal(t, SubscriptionLevelParticipating, s.Level)
+	s, err = GetRepoWatchSubscription(11, issue.RepoID)
+	assert.NoError(t, err)
+	assert.Equal(t, SubscriptionLevelCustom, s.Level)
+	_, err = GetRepoWatchSubscription(12, issue.RepoID)
+	assert.Equal(t, ErrNotWatching, err)
+}
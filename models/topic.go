@@ -354,3 +354,60 @@ func SaveTopics(repoID int64, topicNames ...string) error {
 
 	return sess.Commit()
 }
+
+var topicWordSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+
+// topicCandidates derives possible topic names from the name, the description and the languages of a repository
+func topicCandidates(repo *Repository) ([]string, error) {
+	words := topicWordSeparator.Split(strings.ToLower(repo.Name+" "+repo.Description), -1)
+
+	stats, err := repo.GetTopLanguageStats(5)
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		words = append(words, topicWordSeparator.ReplaceAllString(strings.ToLower(stat.Language), "-"))
+	}
+
+	candidates := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		word = strings.Trim(word, "-")
+		if seen[word] || !ValidateTopic(word) {
+			continue
+		}
+		seen[word] = true
+		candidates = append(candidates, word)
+	}
+	return candidates, nil
+}
+
+// SuggestTopics returns existing topics which are not yet assigned to the repository and
+// match its name, description or languages or are used by other repositories of its owner
+// the doer can access. The most used topics are returned first.
+func SuggestTopics(repo *Repository, doer *User, limit int) ([]*Topic, error) {
+	candidates, err := topicCandidates(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	siblingCond := builder.Eq{"repository.owner_id": repo.OwnerID}.And(builder.Neq{"repository.id": repo.ID})
+	if doer == nil || !doer.IsAdmin {
+		siblingCond = siblingCond.And(accessibleRepositoryCondition(doer))
+	}
+	cond := builder.In("topic.id", builder.Select("repo_topic.topic_id").From("repo_topic").
+		Join("INNER", "repository", "repository.id = repo_topic.repo_id").
+		Where(siblingCond))
+	if len(candidates) > 0 {
+		cond = cond.Or(builder.In("topic.name", candidates))
+	}
+	cond = cond.And(builder.NotIn("topic.id", builder.Select("topic_id").From("repo_topic").Where(builder.Eq{"repo_id": repo.ID})))
+
+	topics := make([]*Topic, 0, limit)
+	return topics, db.DefaultContext().Engine().
+		Where(cond).
+		Desc("topic.repo_count").
+		Asc("topic.name").
+		Limit(limit).
+		Find(&topics)
+}
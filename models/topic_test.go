@@ -77,3 +77,55 @@ func TestTopicValidator(t *testing.T) {
 	assert.False(t, ValidateTopic("-fifth-test-topic"))
 	assert.False(t, ValidateTopic("sixth-go-project-topic-with-excess-length"))
 }
+
+func TestSuggestTopics(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	owner := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	topics, err := SuggestTopics(repo, owner, 10)
+	assert.NoError(t, err)
+	// topics of repo1 and repo33 of the same owner, but not the topics repo2 has already
+	assert.Len(t, topics, 4)
+	assert.Equal(t, "golang", topics[0].Name)
+	for _, topic := range topics {
+		assert.NotContains(t, []string{"topicname1", "topicname2"}, topic.Name)
+	}
+
+	topics, err = SuggestTopics(repo, owner, 1)
+	assert.NoError(t, err)
+	assert.Len(t, topics, 1)
+
+	// topics of private repositories are only suggested to users who can access them
+	assert.NoError(t, SaveTopics(16, "secret"))
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	topics, err = SuggestTopics(repo, nil, 10)
+	assert.NoError(t, err)
+	for _, topic := range topics {
+		assert.NotContains(t, []string{"topicname1", "topicname2", "secret"}, topic.Name)
+	}
+	other := db.AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	topics, err = SuggestTopics(repo, other, 10)
+	assert.NoError(t, err)
+	for _, topic := range topics {
+		assert.NotEqual(t, "secret", topic.Name)
+	}
+	topics, err = SuggestTopics(repo, owner, 10)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		names = append(names, topic.Name)
+	}
+	assert.Contains(t, names, "secret")
+
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	topics, err = SuggestTopics(repo, nil, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, topics)
+
+	repo.Description = "A GraphQL server"
+	topics, err = SuggestTopics(repo, nil, 10)
+	assert.NoError(t, err)
+	assert.Len(t, topics, 1)
+	assert.Equal(t, "graphql", topics[0].Name)
+}
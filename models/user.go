@@ -161,6 +161,8 @@ type User struct {
 	DiffViewStyle       string `xorm:"NOT NULL DEFAULT ''"`
 	Theme               string `xorm:"NOT NULL DEFAULT ''"`
 	KeepActivityPrivate bool   `xorm:"NOT NULL DEFAULT false"`
+	AutoWatchOnPush     string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	AutoWatchOnMention  string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
 }
 
 func init() {
@@ -814,7 +816,9 @@ var (
 		"serviceworker.js",
 		"stars",
 		"template",
+		"unsubscribe",
 		"user",
+		"v2",
 	}
 
 	reservedUserPatterns = []string{"*.keys", "*.gpg", "*.rss", "*.atom"}
@@ -1170,6 +1174,9 @@ func deleteUser(e db.Engine, u *User) error {
 	} else if _, err = e.Decr("num_stars").In("id", starredRepoIDs).NoAutoTime().Update(new(Repository)); err != nil {
 		return fmt.Errorf("decrease repository num_stars: %v", err)
 	}
+	if _, err = e.Where("list_id IN (SELECT id FROM star_list WHERE user_id = ?)", u.ID).Delete(new(StarListRepo)); err != nil {
+		return fmt.Errorf("delete star list repos: %v", err)
+	}
 	// ***** END: Star *****
 
 	// ***** START: Follow *****
@@ -1200,12 +1207,23 @@ func deleteUser(e db.Engine, u *User) error {
 		&Follow{FollowID: u.ID},
 		&Action{UserID: u.ID},
 		&IssueUser{UID: u.ID},
+		&IssueReadMarker{UID: u.ID},
 		&EmailAddress{UID: u.ID},
 		&UserOpenID{UID: u.ID},
 		&Reaction{UserID: u.ID},
 		&TeamUser{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Stopwatch{UserID: u.ID},
+		&IssueFilter{OwnerID: u.ID},
+		&PushDevice{UserID: u.ID},
+		&NotificationChannelPreference{UserID: u.ID},
+		&ChatAddress{UserID: u.ID},
+		&StarList{UserID: u.ID},
+		&PinnedRepo{OwnerID: u.ID},
+		&FederationKey{OwnerType: FederationOwnerUser, OwnerID: u.ID},
+		&FederationFollower{OwnerType: FederationOwnerUser, OwnerID: u.ID},
+		&FederationFollowing{UserID: u.ID},
+		&FederationActivity{OwnerType: FederationOwnerUser, OwnerID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
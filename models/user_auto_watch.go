@@ -0,0 +1,87 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// The auto-watch rules decide when a user starts watching a repository or an issue without being asked to.
+// Explicitly watching or unwatching always takes precedence over them.
+const (
+	// AutoWatchDefault follows the default of the instance
+	AutoWatchDefault = ""
+	// AutoWatchAlways starts watching on every occasion
+	AutoWatchAlways = "always"
+	// AutoWatchNever never starts watching
+	AutoWatchNever = "never"
+	// AutoWatchOnReaction starts watching an issue the user is mentioned in once the user reacts to it
+	AutoWatchOnReaction = "on_reaction"
+)
+
+// IsValidAutoWatchOnPush returns whether the rule may be used for the repositories the user pushes to
+func IsValidAutoWatchOnPush(rule string) bool {
+	return rule == AutoWatchDefault || rule == AutoWatchAlways || rule == AutoWatchNever
+}
+
+// IsValidAutoWatchOnMention returns whether the rule may be used for the issues the user is mentioned in
+func IsValidAutoWatchOnMention(rule string) bool {
+	return IsValidAutoWatchOnPush(rule) || rule == AutoWatchOnReaction
+}
+
+// AutoWatchesOnPush returns whether the user starts watching the repositories they push to,
+// by default if AUTO_WATCH_ON_CHANGES is enabled
+func (u *User) AutoWatchesOnPush() bool {
+	switch u.AutoWatchOnPush {
+	case AutoWatchAlways:
+		return true
+	case AutoWatchNever:
+		return false
+	default:
+		return setting.Service.AutoWatchOnChanges
+	}
+}
+
+// autoWatchIssue makes the user watch the issue unless the user explicitly watched or unwatched it before
+func autoWatchIssue(e db.Engine, userID, issueID int64) error {
+	_, exists, err := getIssueWatch(e, userID, issueID)
+	if err != nil || exists {
+		return err
+	}
+	_, err = e.Insert(&IssueWatch{
+		UserID:     userID,
+		IssueID:    issueID,
+		IsWatching: true,
+	})
+	return err
+}
+
+// watchIssueOnMentions makes the mentioned users who always watch the issues they are mentioned in watch the issue.
+// By default, being mentioned only notifies the user once.
+func watchIssueOnMentions(e db.Engine, issueID int64, mentions []*User) error {
+	for _, u := range mentions {
+		if u.AutoWatchOnMention != AutoWatchAlways {
+			continue
+		}
+		if err := autoWatchIssue(e, u.ID, issueID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchIssueOnReaction makes a user who watches the issues they are mentioned in after reacting to them
+// watch the issue, if the user was mentioned in it
+func watchIssueOnReaction(e db.Engine, doer *User, issueID int64) error {
+	if doer.AutoWatchOnMention != AutoWatchOnReaction {
+		return nil
+	}
+	mentioned, err := e.Where("uid = ? AND issue_id = ? AND is_mentioned = ?", doer.ID, issueID, true).Exist(new(IssueUser))
+	if err != nil || !mentioned {
+		return err
+	}
+	return autoWatchIssue(e, doer.ID, issueID)
+}
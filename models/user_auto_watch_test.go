@@ -0,0 +1,71 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoWatchOnPush(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	setting.Service.AutoWatchOnChanges = false
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 12}).(*User)
+	user.AutoWatchOnPush = AutoWatchAlways
+	assert.NoError(t, UpdateUserCols(user, "auto_watch_on_push"))
+	assert.NoError(t, WatchIfAuto(12, 1, true))
+	db.AssertExistsAndLoadBean(t, &Watch{UserID: 12, RepoID: 1, Mode: RepoWatchModeAuto})
+
+	setting.Service.AutoWatchOnChanges = true
+	user = db.AssertExistsAndLoadBean(t, &User{ID: 10}).(*User)
+	user.AutoWatchOnPush = AutoWatchNever
+	assert.NoError(t, UpdateUserCols(user, "auto_watch_on_push"))
+	assert.NoError(t, WatchIfAuto(10, 1, true))
+	db.AssertNotExistsBean(t, &Watch{UserID: 10, RepoID: 1})
+}
+
+func TestAutoWatchOnMention(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	for _, id := range []int64{2, 5} {
+		user := db.AssertExistsAndLoadBean(t, &User{ID: id}).(*User)
+		user.AutoWatchOnMention = AutoWatchAlways
+		assert.NoError(t, UpdateUserCols(user, "auto_watch_on_mention"))
+	}
+
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	_, err := issue.FindAndUpdateIssueMentions(db.DefaultContext(), doer, "@user5 please take a look")
+	assert.NoError(t, err)
+	db.AssertExistsAndLoadBean(t, &IssueWatch{UserID: 5, IssueID: 1, IsWatching: true})
+
+	// explicitly unwatching takes precedence
+	issue = db.AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	_, err = issue.FindAndUpdateIssueMentions(db.DefaultContext(), doer, "@user2 please take a look")
+	assert.NoError(t, err)
+	iw := db.AssertExistsAndLoadBean(t, &IssueWatch{UserID: 2, IssueID: 2}).(*IssueWatch)
+	assert.False(t, iw.IsWatching)
+}
+
+func TestAutoWatchOnReaction(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	for _, id := range []int64{2, 4} {
+		user := db.AssertExistsAndLoadBean(t, &User{ID: id}).(*User)
+		user.AutoWatchOnMention = AutoWatchOnReaction
+		assert.NoError(t, UpdateUserCols(user, "auto_watch_on_mention"))
+		addReaction(t, user, issue, nil, "heart")
+	}
+
+	// only user 4 was mentioned in the issue
+	db.AssertExistsAndLoadBean(t, &IssueWatch{UserID: 4, IssueID: 1, IsWatching: true})
+	db.AssertNotExistsBean(t, &IssueWatch{UserID: 2, IssueID: 1})
+}
@@ -7,6 +7,7 @@ package models
 import (
 	"crypto/md5"
 	"fmt"
+	"image"
 	"image/png"
 	"io"
 	"strconv"
@@ -35,9 +36,9 @@ func (u *User) generateRandomAvatar(e db.Engine) error {
 		seed = u.Name
 	}
 
-	img, err := avatar.RandomImage([]byte(seed))
+	img, err := avatar.GeneratedImage([]byte(seed), u.DisplayName())
 	if err != nil {
-		return fmt.Errorf("RandomImage: %v", err)
+		return fmt.Errorf("GeneratedImage: %v", err)
 	}
 
 	u.Avatar = HashEmail(seed)
@@ -120,7 +121,13 @@ func (u *User) AvatarLink() string {
 // UploadAvatar saves custom avatar for user.
 // FIXME: split uploads to different subdirs in case we have massive users.
 func (u *User) UploadAvatar(data []byte) error {
-	m, err := avatar.Prepare(data)
+	return u.UploadCroppedAvatar(data, image.Rectangle{})
+}
+
+// UploadCroppedAvatar saves the given area of an image as custom avatar for user.
+// The whole image is used if the area is empty.
+func (u *User) UploadCroppedAvatar(data []byte, crop image.Rectangle) error {
+	m, err := avatar.PrepareWithCrop(data, crop)
 	if err != nil {
 		return err
 	}
@@ -136,7 +143,7 @@ func (u *User) UploadAvatar(data []byte) error {
 	// If we prefix it with u.ID, it will be separated
 	// Otherwise, if any of the users delete his avatar
 	// Other users will lose their avatars too.
-	u.Avatar = fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%d-%x", u.ID, md5.Sum(data)))))
+	u.Avatar = fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%d-%x", u.ID, avatarDataSum(data, crop)))))
 	if err = updateUserCols(sess, u, "use_custom_avatar", "avatar"); err != nil {
 		return fmt.Errorf("updateUser: %v", err)
 	}
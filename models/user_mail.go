@@ -79,6 +79,21 @@ func GetEmailAddressByID(uid, id int64) (*EmailAddress, error) {
 	return email, nil
 }
 
+// GetUserIDByVerifiedEmail returns the id of the user owning the activated email address or 0 if there is none
+func GetUserIDByVerifiedEmail(email string) (int64, error) {
+	if len(email) == 0 {
+		return 0, nil
+	}
+
+	var uid int64
+	_, err := db.DefaultContext().Engine().Table("email_address").
+		Select("uid").
+		Where("lower_email=?", strings.ToLower(email)).
+		And("is_activated=?", true).
+		Get(&uid)
+	return uid, err
+}
+
 // isEmailActive check if email is activated with a different emailID
 func isEmailActive(e db.Engine, email string, excludeEmailID int64) (bool, error) {
 	if len(email) == 0 {
@@ -41,6 +41,23 @@ func TestIsEmailUsed(t *testing.T) {
 	assert.False(t, isExist)
 }
 
+func TestGetUserIDByVerifiedEmail(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	uid, err := GetUserIDByVerifiedEmail("User2@Example.com")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, uid)
+
+	// not activated
+	uid, err = GetUserIDByVerifiedEmail("user11@example.com")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, uid)
+
+	uid, err = GetUserIDByVerifiedEmail("user1234567890@example.com")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, uid)
+}
+
 func TestAddEmailAddress(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
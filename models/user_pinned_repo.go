@@ -0,0 +1,83 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// MaxPinnedRepos is the maximum number of repositories pinned on the profile of a user or an organization
+const MaxPinnedRepos = 6
+
+// ErrTooManyPinnedRepos indicates that more than MaxPinnedRepos repositories are pinned
+var ErrTooManyPinnedRepos = fmt.Errorf("At most %d repositories can be pinned", MaxPinnedRepos)
+
+// PinnedRepo represents a repository pinned on the profile of a user or an organization
+type PinnedRepo struct {
+	ID      int64 `xorm:"pk autoincr"`
+	OwnerID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RepoID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	// Position is the place of the repository on the profile, starting at 0
+	Position int `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(PinnedRepo))
+}
+
+// GetPinnedRepos returns the repositories pinned on the profile of a user or an organization in their order,
+// leaving out those the doer has no access to. The doer may be nil for anonymous users.
+func GetPinnedRepos(ownerID int64, doer *User) (RepositoryList, error) {
+	e := db.DefaultContext().Engine()
+	repos := make(RepositoryList, 0, MaxPinnedRepos)
+	if err := e.Join("INNER", "pinned_repo", "pinned_repo.repo_id = repository.id").
+		Where("pinned_repo.owner_id = ?", ownerID).
+		Asc("pinned_repo.position").
+		Find(&repos); err != nil {
+		return nil, err
+	}
+
+	visible := make(RepositoryList, 0, len(repos))
+	for _, repo := range repos {
+		perm, err := getUserRepoPermission(e, repo, doer)
+		if err != nil {
+			return nil, err
+		}
+		if perm.HasAccess() {
+			visible = append(visible, repo)
+		}
+	}
+	return visible, visible.loadAttributes(e)
+}
+
+// SetPinnedRepos replaces the repositories pinned on the profile of a user or an organization,
+// keeping the given order. Repeated repositories are pinned once.
+func SetPinnedRepos(ownerID int64, repoIDs []int64) error {
+	seen := make(map[int64]bool, len(repoIDs))
+	pinned := make([]*PinnedRepo, 0, len(repoIDs))
+	for _, repoID := range repoIDs {
+		if seen[repoID] {
+			continue
+		}
+		seen[repoID] = true
+		pinned = append(pinned, &PinnedRepo{OwnerID: ownerID, RepoID: repoID, Position: len(pinned)})
+	}
+	if len(pinned) > MaxPinnedRepos {
+		return ErrTooManyPinnedRepos
+	}
+
+	return db.WithTx(func(ctx *db.Context) error {
+		if _, err := ctx.Engine().Delete(&PinnedRepo{OwnerID: ownerID}); err != nil {
+			return err
+		}
+		if len(pinned) == 0 {
+			return nil
+		}
+		_, err := ctx.Engine().Insert(pinned)
+		return err
+	})
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPinnedRepos(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user2 := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	assert.NoError(t, SetPinnedRepos(2, []int64{2, 1, 2}))
+	repos, err := GetPinnedRepos(2, user2)
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 2) {
+		assert.EqualValues(t, 2, repos[0].ID)
+		assert.EqualValues(t, 1, repos[1].ID)
+	}
+
+	// the private repository is left out for anonymous users
+	repos, err = GetPinnedRepos(2, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 1, repos[0].ID)
+	}
+
+	assert.Equal(t, ErrTooManyPinnedRepos, SetPinnedRepos(2, []int64{1, 2, 3, 4, 5, 6, 7}))
+
+	assert.NoError(t, SetPinnedRepos(2, nil))
+	db.AssertNotExistsBean(t, &PinnedRepo{OwnerID: 2})
+}
@@ -8,13 +8,15 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
 )
 
 // UserRedirect represents that a user name should be redirected to another
 type UserRedirect struct {
-	ID             int64  `xorm:"pk autoincr"`
-	LowerName      string `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	RedirectUserID int64  // userID to redirect to
+	ID             int64              `xorm:"pk autoincr"`
+	LowerName      string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RedirectUserID int64              // userID to redirect to
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
 }
 
 func init() {
@@ -33,6 +35,16 @@ func LookupUserRedirect(userName string) (int64, error) {
 	return redirect.RedirectUserID, nil
 }
 
+// FindUserRedirects returns the redirects to a user, most recent first
+func FindUserRedirects(userID int64) ([]*UserRedirect, error) {
+	redirects := make([]*UserRedirect, 0, 5)
+	err := db.DefaultContext().Engine().
+		Where("redirect_user_id = ?", userID).
+		Desc("created_unix", "id").
+		Find(&redirects)
+	return redirects, err
+}
+
 // newUserRedirect create a new user redirect
 func newUserRedirect(e db.Engine, ID int64, oldUserName, newUserName string) error {
 	oldUserName = strings.ToLower(oldUserName)
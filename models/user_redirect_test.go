@@ -22,6 +22,21 @@ func TestLookupUserRedirect(t *testing.T) {
 	assert.True(t, IsErrUserRedirectNotExist(err))
 }
 
+func TestFindUserRedirects(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	assert.NoError(t, newUserRedirect(db.DefaultContext().Engine(), user.ID, user.Name, "newusername"))
+
+	redirects, err := FindUserRedirects(user.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, redirects, 2) {
+		assert.Equal(t, user.LowerName, redirects[0].LowerName)
+		assert.NotZero(t, redirects[0].CreatedUnix)
+		assert.Equal(t, "olduser1", redirects[1].LowerName)
+	}
+}
+
 func TestNewUserRedirect(t *testing.T) {
 	// redirect to a completely new name
 	assert.NoError(t, db.PrepareTestDatabase())
@@ -100,6 +100,7 @@ type HookEvents struct {
 	PullRequestSync      bool `json:"pull_request_sync"`
 	Repository           bool `json:"repository"`
 	Release              bool `json:"release"`
+	Schedule             bool `json:"schedule"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -305,6 +306,12 @@ func (w *Webhook) HasRepositoryEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Repository)
 }
 
+// HasScheduleEvent returns if hook enabled schedule event.
+func (w *Webhook) HasScheduleEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Schedule)
+}
+
 // EventCheckers returns event checkers
 func (w *Webhook) EventCheckers() []struct {
 	Has  func() bool
@@ -334,6 +341,7 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasPullRequestSyncEvent, HookEventPullRequestSync},
 		{w.HasRepositoryEvent, HookEventRepository},
 		{w.HasReleaseEvent, HookEventRelease},
+		{w.HasScheduleEvent, HookEventSchedule},
 	}
 }
 
@@ -601,6 +609,7 @@ const (
 	HookEventPullRequestSync           HookEventType = "pull_request_sync"
 	HookEventRepository                HookEventType = "repository"
 	HookEventRelease                   HookEventType = "release"
+	HookEventSchedule                  HookEventType = "schedule"
 )
 
 // Event returns the HookEventType as an event string
@@ -631,6 +640,8 @@ func (h HookEventType) Event() string {
 		return "repository"
 	case HookEventRelease:
 		return "release"
+	case HookEventSchedule:
+		return "schedule"
 	}
 	return ""
 }
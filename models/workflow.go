@@ -0,0 +1,276 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+var (
+	// ErrWorkflowRunNotExist indicates a workflow run not exist error
+	ErrWorkflowRunNotExist = errors.New("Workflow run does not exist")
+	// ErrWorkflowJobNotExist indicates a workflow job not exist error
+	ErrWorkflowJobNotExist = errors.New("Workflow job does not exist")
+)
+
+// WorkflowStatus represents the status of a workflow run or job
+type WorkflowStatus int
+
+// Note: new status must append to the end of list to maintain compatibility.
+const (
+	WorkflowStatusWaiting   WorkflowStatus = iota + 1 // job waits for the jobs it needs
+	WorkflowStatusPending                             // job waits for a runner
+	WorkflowStatusRunning                             // job is executed by a runner
+	WorkflowStatusSuccess                             // job finished successfully
+	WorkflowStatusFailure                             // job failed
+	WorkflowStatusCancelled                           // job was cancelled
+	WorkflowStatusSkipped                             // job was skipped because a needed job did not succeed
+)
+
+var workflowStatusNames = map[WorkflowStatus]string{
+	WorkflowStatusWaiting:   "waiting",
+	WorkflowStatusPending:   "pending",
+	WorkflowStatusRunning:   "running",
+	WorkflowStatusSuccess:   "success",
+	WorkflowStatusFailure:   "failure",
+	WorkflowStatusCancelled: "cancelled",
+	WorkflowStatusSkipped:   "skipped",
+}
+
+// String returns the name of the status
+func (s WorkflowStatus) String() string {
+	return workflowStatusNames[s]
+}
+
+// IsDone returns true if the status is final
+func (s WorkflowStatus) IsDone() bool {
+	return s >= WorkflowStatusSuccess
+}
+
+// WorkflowStatusFromString returns the status with the given name
+func WorkflowStatusFromString(name string) (WorkflowStatus, bool) {
+	for s, n := range workflowStatusNames {
+		if n == name {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// WorkflowRun represents a run of a workflow file triggered by an event
+type WorkflowRun struct {
+	ID                int64          `xorm:"pk autoincr"`
+	RepoID            int64          `xorm:"INDEX NOT NULL"`
+	Repo              *Repository    `xorm:"-"`
+	WorkflowID        string         `xorm:"NOT NULL"` // name of the workflow file
+	Name              string         `xorm:"NOT NULL"`
+	Event             string         `xorm:"NOT NULL"`
+	Ref               string         `xorm:"NOT NULL"`
+	CommitSHA         string         `xorm:"VARCHAR(40) NOT NULL"`
+	IsForkPullRequest bool           `xorm:"NOT NULL DEFAULT false"`
+	TriggerUserID     int64          `xorm:"NOT NULL"`
+	TriggerUser       *User          `xorm:"-"`
+	Status            WorkflowStatus `xorm:"INDEX NOT NULL"`
+
+	StartedUnix timeutil.TimeStamp
+	StoppedUnix timeutil.TimeStamp
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// WorkflowJob represents a job of a workflow run
+type WorkflowJob struct {
+	ID       int64          `xorm:"pk autoincr"`
+	RunID    int64          `xorm:"INDEX NOT NULL"`
+	RepoID   int64          `xorm:"INDEX NOT NULL"`
+	JobID    string         `xorm:"NOT NULL"` // id of the job in the workflow file
+	Name     string         `xorm:"NOT NULL"`
+	RunsOn   []string       `xorm:"JSON TEXT"`
+	Needs    []string       `xorm:"JSON TEXT"`
+	Payload  string         `xorm:"LONGTEXT"` // JSON encoded job definition which is passed to the runner
+	Status   WorkflowStatus `xorm:"INDEX NOT NULL"`
+	RunnerID int64          `xorm:"INDEX NOT NULL DEFAULT 0"`
+
+	StartedUnix timeutil.TimeStamp
+	StoppedUnix timeutil.TimeStamp
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(WorkflowRun))
+	db.RegisterModel(new(WorkflowJob))
+}
+
+// LoadAttributes loads the repository and the trigger user of the run
+func (run *WorkflowRun) LoadAttributes() (err error) {
+	if run.Repo == nil {
+		if run.Repo, err = GetRepositoryByID(run.RepoID); err != nil {
+			return err
+		}
+	}
+	if run.TriggerUser == nil {
+		if run.TriggerUser, err = GetUserByID(run.TriggerUserID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			run.TriggerUser = NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// InsertWorkflowRun inserts a run with its jobs
+func InsertWorkflowRun(run *WorkflowRun, jobs []*WorkflowJob) error {
+	return db.WithTx(func(ctx *db.Context) error {
+		if _, err := ctx.Engine().Insert(run); err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			job.RunID = run.ID
+			job.RepoID = run.RepoID
+			if _, err := ctx.Engine().Insert(job); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetWorkflowRunByID gets a workflow run by its id
+func GetWorkflowRunByID(id int64) (*WorkflowRun, error) {
+	run := &WorkflowRun{}
+	has, err := db.DefaultContext().Engine().ID(id).Get(run)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrWorkflowRunNotExist
+	}
+	return run, nil
+}
+
+// GetWorkflowRunByRepoAndID gets a workflow run of a repository by its id
+func GetWorkflowRunByRepoAndID(repoID, id int64) (*WorkflowRun, error) {
+	run, err := GetWorkflowRunByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if run.RepoID != repoID {
+		return nil, ErrWorkflowRunNotExist
+	}
+	return run, nil
+}
+
+// FindWorkflowRunOptions represents the options to find workflow runs
+type FindWorkflowRunOptions struct {
+	ListOptions
+	RepoID     int64
+	WorkflowID string
+	Ref        string
+	Event      string
+	Status     WorkflowStatus
+}
+
+func (opts *FindWorkflowRunOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.WorkflowID != "" {
+		cond = cond.And(builder.Eq{"workflow_id": opts.WorkflowID})
+	}
+	if opts.Ref != "" {
+		cond = cond.And(builder.Eq{"ref": opts.Ref})
+	}
+	if opts.Event != "" {
+		cond = cond.And(builder.Eq{"event": opts.Event})
+	}
+	if opts.Status > 0 {
+		cond = cond.And(builder.Eq{"status": opts.Status})
+	}
+	return cond
+}
+
+// FindWorkflowRuns returns the workflow runs matching the options, newest first
+func FindWorkflowRuns(opts *FindWorkflowRunOptions) ([]*WorkflowRun, int64, error) {
+	sess := db.DefaultContext().Engine().Where(opts.toConds()).Desc("id")
+	if opts.Page > 0 {
+		sess = setSessionPagination(sess, opts)
+	}
+	runs := make([]*WorkflowRun, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&runs)
+	return runs, count, err
+}
+
+// UpdateWorkflowRun updates the status and times of the run
+func UpdateWorkflowRun(ctx *db.Context, run *WorkflowRun) error {
+	_, err := ctx.Engine().ID(run.ID).Cols("status", "started_unix", "stopped_unix").Update(run)
+	return err
+}
+
+// GetWorkflowJobByID gets a workflow job by its id
+func GetWorkflowJobByID(id int64) (*WorkflowJob, error) {
+	job := &WorkflowJob{}
+	has, err := db.DefaultContext().Engine().ID(id).Get(job)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrWorkflowJobNotExist
+	}
+	return job, nil
+}
+
+// GetWorkflowJobsByRunID returns the jobs of a workflow run
+func GetWorkflowJobsByRunID(ctx *db.Context, runID int64) ([]*WorkflowJob, error) {
+	jobs := make([]*WorkflowJob, 0, 5)
+	return jobs, ctx.Engine().Where("run_id = ?", runID).Asc("id").Find(&jobs)
+}
+
+// FindPendingWorkflowJobs returns up to limit jobs with an ID greater than afterID which wait for a runner
+// and are in the scope of the runner
+func FindPendingWorkflowJobs(runner *WorkflowRunner, afterID int64, limit int) ([]*WorkflowJob, error) {
+	cond := builder.NewCond().And(builder.Eq{"status": WorkflowStatusPending}, builder.Gt{"id": afterID})
+	if runner.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": runner.RepoID})
+	} else if runner.OwnerID > 0 {
+		cond = cond.And(builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": runner.OwnerID})))
+	}
+	jobs := make([]*WorkflowJob, 0, 10)
+	return jobs, db.DefaultContext().Engine().Where(cond).Asc("id").Limit(limit).Find(&jobs)
+}
+
+// FindTimedOutWorkflowJobs returns the running jobs which were started before the given time
+func FindTimedOutWorkflowJobs(startedBefore timeutil.TimeStamp) ([]*WorkflowJob, error) {
+	jobs := make([]*WorkflowJob, 0, 10)
+	return jobs, db.DefaultContext().Engine().
+		Where("status = ? AND started_unix < ?", WorkflowStatusRunning, startedBefore).
+		Asc("id").
+		Find(&jobs)
+}
+
+// ClaimWorkflowJob assigns a pending job to the runner. It returns false if the job was claimed by another runner already.
+func ClaimWorkflowJob(job *WorkflowJob, runnerID int64) (bool, error) {
+	job.RunnerID = runnerID
+	job.Status = WorkflowStatusRunning
+	job.StartedUnix = timeutil.TimeStampNow()
+	affected, err := db.DefaultContext().Engine().
+		Where("id = ? AND status = ?", job.ID, WorkflowStatusPending).
+		Cols("runner_id", "status", "started_unix").
+		Update(job)
+	return affected == 1, err
+}
+
+// UpdateWorkflowJob updates the status and times of the job
+func UpdateWorkflowJob(ctx *db.Context, job *WorkflowJob) error {
+	_, err := ctx.Engine().ID(job.ID).Cols("status", "started_unix", "stopped_unix").Update(job)
+	return err
+}
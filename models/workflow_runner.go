@@ -0,0 +1,121 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	gouuid "github.com/google/uuid"
+	"xorm.io/builder"
+)
+
+// ErrWorkflowRunnerNotExist indicates a workflow runner not exist error
+var ErrWorkflowRunnerNotExist = errors.New("Workflow runner does not exist")
+
+// WorkflowRunner represents a registered runner which executes workflow jobs.
+// A runner with neither owner nor repository is available for all repositories.
+type WorkflowRunner struct {
+	ID             int64    `xorm:"pk autoincr"`
+	Name           string   `xorm:"NOT NULL"`
+	OwnerID        int64    `xorm:"INDEX NOT NULL DEFAULT 0"`
+	RepoID         int64    `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Labels         []string `xorm:"JSON TEXT"`
+	Token          string   `xorm:"-"`
+	TokenHash      string   `xorm:"UNIQUE"`
+	TokenSalt      string
+	TokenLastEight string `xorm:"INDEX token_last_eight"`
+
+	LastOnlineUnix timeutil.TimeStamp
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(WorkflowRunner))
+}
+
+// CanRunJob checks if the runner provides all labels the job requires
+func (r *WorkflowRunner) CanRunJob(job *WorkflowJob) bool {
+	for _, required := range job.RunsOn {
+		if !util.IsStringInSlice(required, r.Labels, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateWorkflowRunner creates a runner and generates its token
+func CreateWorkflowRunner(r *WorkflowRunner) error {
+	salt, err := util.RandomString(10)
+	if err != nil {
+		return err
+	}
+	r.TokenSalt = salt
+	r.Token = base.EncodeSha1(gouuid.New().String())
+	r.TokenHash = hashToken(r.Token, r.TokenSalt)
+	r.TokenLastEight = r.Token[len(r.Token)-8:]
+	_, err = db.DefaultContext().Engine().Insert(r)
+	return err
+}
+
+// GetWorkflowRunnerByToken returns the runner the token belongs to
+func GetWorkflowRunnerByToken(token string) (*WorkflowRunner, error) {
+	if len(token) != 40 {
+		return nil, ErrWorkflowRunnerNotExist
+	}
+
+	var runners []*WorkflowRunner
+	if err := db.DefaultContext().Engine().Where("token_last_eight = ?", token[len(token)-8:]).Find(&runners); err != nil {
+		return nil, err
+	}
+	for _, r := range runners {
+		if subtle.ConstantTimeCompare([]byte(r.TokenHash), []byte(hashToken(token, r.TokenSalt))) == 1 {
+			return r, nil
+		}
+	}
+	return nil, ErrWorkflowRunnerNotExist
+}
+
+// GetWorkflowRunnerByID gets a runner by its id
+func GetWorkflowRunnerByID(id int64) (*WorkflowRunner, error) {
+	r := &WorkflowRunner{}
+	has, err := db.DefaultContext().Engine().ID(id).Get(r)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrWorkflowRunnerNotExist
+	}
+	return r, nil
+}
+
+// FindWorkflowRunners returns the runners registered for the owner and repository.
+// Zero values return the global runners.
+func FindWorkflowRunners(ownerID, repoID int64) ([]*WorkflowRunner, error) {
+	runners := make([]*WorkflowRunner, 0, 5)
+	return runners, db.DefaultContext().Engine().
+		Where(builder.Eq{"owner_id": ownerID, "repo_id": repoID}).
+		Asc("id").
+		Find(&runners)
+}
+
+// UpdateWorkflowRunnerLastOnline records that the runner contacted the server
+func UpdateWorkflowRunnerLastOnline(r *WorkflowRunner) error {
+	r.LastOnlineUnix = timeutil.TimeStampNow()
+	_, err := db.DefaultContext().Engine().ID(r.ID).Cols("last_online_unix").NoAutoTime().Update(r)
+	return err
+}
+
+// DeleteWorkflowRunnerByID deletes a runner. Jobs assigned to the runner keep their status.
+func DeleteWorkflowRunnerByID(id int64) error {
+	_, err := db.DefaultContext().Engine().ID(id).Delete(&WorkflowRunner{})
+	return err
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowRunner(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	runner := &WorkflowRunner{Name: "runner", RepoID: 1, Labels: []string{"linux", "x64"}}
+	assert.NoError(t, CreateWorkflowRunner(runner))
+	assert.Len(t, runner.Token, 40)
+
+	found, err := GetWorkflowRunnerByToken(runner.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, runner.ID, found.ID)
+
+	_, err = GetWorkflowRunnerByToken(runner.Token[:39] + "x")
+	assert.Equal(t, ErrWorkflowRunnerNotExist, err)
+
+	assert.True(t, runner.CanRunJob(&WorkflowJob{RunsOn: []string{"linux"}}))
+	assert.False(t, runner.CanRunJob(&WorkflowJob{RunsOn: []string{"windows"}}))
+
+	runners, err := FindWorkflowRunners(0, 1)
+	assert.NoError(t, err)
+	assert.Len(t, runners, 1)
+
+	assert.NoError(t, DeleteWorkflowRunnerByID(runner.ID))
+	_, err = GetWorkflowRunnerByID(runner.ID)
+	assert.Equal(t, ErrWorkflowRunnerNotExist, err)
+}
+
+func TestWorkflowJobDispatch(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	run := &WorkflowRun{RepoID: 1, WorkflowID: "ci.yml", Name: "CI", Event: "push", Ref: "refs/heads/master", CommitSHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", TriggerUserID: 2, Status: WorkflowStatusPending}
+	jobs := []*WorkflowJob{
+		{JobID: "build", Name: "build", RunsOn: []string{"linux"}, Status: WorkflowStatusPending},
+		{JobID: "test", Name: "test", Needs: []string{"build"}, Status: WorkflowStatusWaiting},
+	}
+	assert.NoError(t, InsertWorkflowRun(run, jobs))
+
+	repoRunner := &WorkflowRunner{ID: 1, RepoID: 1}
+	pending, err := FindPendingWorkflowJobs(repoRunner, 0, 50)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, jobs[0].ID, pending[0].ID)
+
+	pending, err = FindPendingWorkflowJobs(&WorkflowRunner{ID: 2, RepoID: 2}, 0, 50)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	pending, err = FindPendingWorkflowJobs(&WorkflowRunner{ID: 3, OwnerID: 2}, 0, 50)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	pending, err = FindPendingWorkflowJobs(repoRunner, jobs[0].ID, 50)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	pending, err = FindPendingWorkflowJobs(repoRunner, 0, 50)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	claimed, err := ClaimWorkflowJob(jobs[0], repoRunner.ID)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = ClaimWorkflowJob(&WorkflowJob{ID: jobs[0].ID}, 2)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	job, err := GetWorkflowJobByID(jobs[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, WorkflowStatusRunning, job.Status)
+	assert.EqualValues(t, 1, job.RunnerID)
+
+	timedOut, err := FindTimedOutWorkflowJobs(job.StartedUnix)
+	assert.NoError(t, err)
+	assert.Len(t, timedOut, 0)
+	timedOut, err = FindTimedOutWorkflowJobs(job.StartedUnix.Add(1))
+	assert.NoError(t, err)
+	if assert.Len(t, timedOut, 1) {
+		assert.Equal(t, job.ID, timedOut[0].ID)
+	}
+
+	runs, count, err := FindWorkflowRuns(&FindWorkflowRunOptions{RepoID: 1, Status: WorkflowStatusPending})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Equal(t, run.ID, runs[0].ID)
+}
@@ -0,0 +1,245 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrBlockedURL represents an URL of a remote object which can not be federated with
+type ErrBlockedURL struct {
+	URL    string
+	Reason string
+}
+
+// IsErrBlockedURL checks if an error is a ErrBlockedURL.
+func IsErrBlockedURL(err error) bool {
+	_, ok := err.(ErrBlockedURL)
+	return ok
+}
+
+func (err ErrBlockedURL) Error() string {
+	return fmt.Sprintf("can not federate with %s: %s", err.URL, err.Reason)
+}
+
+// CheckURL returns the parsed URL of a remote object if Gitea may federate with its host
+func CheckURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, ErrBlockedURL{URL: rawURL, Reason: "invalid URL"}
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, ErrBlockedURL{URL: rawURL, Reason: "unsupported scheme"}
+	}
+	if u.Hostname() == "" {
+		return nil, ErrBlockedURL{URL: rawURL, Reason: "no host"}
+	}
+	if setting.IsFederationDomainBlocked(u.Hostname()) {
+		return nil, ErrBlockedURL{URL: rawURL, Reason: "blocked domain"}
+	}
+	if !setting.Federation.AllowLocalNetworks {
+		addrs, err := net.LookupIP(u.Hostname())
+		if err != nil {
+			return nil, ErrBlockedURL{URL: rawURL, Reason: "unable to resolve host"}
+		}
+		for _, addr := range addrs {
+			if util.IsIPLocalNetwork(addr) {
+				return nil, ErrBlockedURL{URL: rawURL, Reason: "local network"}
+			}
+		}
+	}
+	return u, nil
+}
+
+// checkDialAddress refuses connections to local networks, the host of an URL might resolve to another address once it is dialed
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	if setting.Federation.AllowLocalNetworks {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || util.IsIPLocalNetwork(ip) {
+		return ErrBlockedURL{URL: address, Reason: "local network"}
+	}
+	return nil
+}
+
+// isProxyAddress returns whether an address is the one of the configured proxy
+func isProxyAddress(address string) bool {
+	proxyURL, err := url.Parse(proxy.GetProxyURL())
+	if err != nil || proxyURL.Hostname() == "" {
+		return false
+	}
+	port := proxyURL.Port()
+	if port == "" {
+		port = "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return strings.EqualFold(address, net.JoinHostPort(proxyURL.Hostname(), port))
+}
+
+// dialContext dials the hosts of remote objects, which may not be in local networks unlike the proxy
+func dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if !isProxyAddress(address) {
+		dialer.Control = checkDialAddress
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// checkRedirect checks the URLs requests are redirected to like those they are sent to
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("%s: stopped after 10 redirects", req.URL)
+	}
+	_, err := CheckURL(req.URL.String())
+	return err
+}
+
+// Client fetches remote objects and delivers activities, signing its requests with the key of an actor
+type Client struct {
+	client *http.Client
+	keyID  string
+	key    *rsa.PrivateKey
+}
+
+// NewClient creates a client signing its requests with the key of an actor
+func NewClient(keyID string, key *rsa.PrivateKey) *Client {
+	return &Client{
+		client: &http.Client{
+			Timeout: setting.Federation.DeliverTimeout,
+			Transport: &http.Transport{
+				Proxy:       proxy.Proxy(),
+				DialContext: dialContext,
+			},
+			CheckRedirect: checkRedirect,
+		},
+		keyID: keyID,
+		key:   key,
+	}
+}
+
+func (c *Client) do(req *http.Request, body []byte) (*http.Response, error) {
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+	if c.key != nil {
+		if err := SignRequest(req, body, c.keyID, c.key); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: unexpected status %s", req.Method, req.URL, resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *Client) get(ctx context.Context, rawURL, accept string, v interface{}) error {
+	u, err := CheckURL(rawURL)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.do(req, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(io.LimitReader(resp.Body, setting.Federation.MaxSize)).Decode(v)
+}
+
+// Fetch fetches a remote object, e.g. an actor
+func (c *Client) Fetch(ctx context.Context, iri string, v interface{}) error {
+	return c.get(ctx, iri, ContentType+", "+LDContentType, v)
+}
+
+// Post delivers an activity to an inbox
+func (c *Client) Post(ctx context.Context, inbox string, activity interface{}) error {
+	u, err := CheckURL(inbox)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+
+	resp, err := c.do(req, body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// WebFingerLink represents a link of a WebFinger resource
+type WebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href,omitempty"`
+}
+
+// WebFingerResource represents a resource returned by WebFinger
+type WebFingerResource struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases,omitempty"`
+	Links   []WebFingerLink `json:"links"`
+}
+
+// ResolveHandle returns the ID of the actor of a handle like user@example.com using WebFinger
+func (c *Client) ResolveHandle(ctx context.Context, handle string) (string, error) {
+	handle = strings.TrimPrefix(handle, "@")
+	idx := strings.LastIndexByte(handle, '@')
+	if idx <= 0 || idx == len(handle)-1 {
+		return "", ErrBlockedURL{URL: handle, Reason: "invalid handle"}
+	}
+	query := url.Values{"resource": {"acct:" + handle}}
+	webFingerURL := "https://" + handle[idx+1:] + "/.well-known/webfinger?" + query.Encode()
+
+	var resource WebFingerResource
+	if err := c.get(ctx, webFingerURL, "application/jrd+json, application/json", &resource); err != nil {
+		return "", err
+	}
+	for _, link := range resource.Links {
+		if link.Rel == "self" && (link.Type == ContentType || link.Type == LDContentType) && link.Href != "" {
+			return link.Href, nil
+		}
+	}
+	return "", fmt.Errorf("%s has no ActivityPub actor", handle)
+}
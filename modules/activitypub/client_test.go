@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"http://127.0.0.1:3000/users/alice#main-key",
+		"http://[::1]/users/alice",
+		"http://localhost/users/alice",
+		"http://10.0.0.1/users/alice",
+		"http://169.254.169.254/latest/meta-data",
+		"http://0.0.0.0/users/alice",
+	} {
+		_, err := CheckURL(rawURL)
+		assert.True(t, IsErrBlockedURL(err), rawURL)
+	}
+
+	_, err := CheckURL("https://93.184.216.34/users/alice")
+	assert.NoError(t, err)
+
+	setting.Federation.AllowLocalNetworks = true
+	defer func() {
+		setting.Federation.AllowLocalNetworks = false
+	}()
+	_, err = CheckURL("http://127.0.0.1:3000/users/alice#main-key")
+	assert.NoError(t, err)
+}
+
+func TestClientLocalNetworks(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	// the host of an URL might resolve to a local address once it is dialed
+	c := NewClient("", nil)
+	_, err := c.client.Get(server.URL)
+	assert.Error(t, err)
+	assert.False(t, requested)
+}
+
+func TestClientRedirectToLocalNetwork(t *testing.T) {
+	var requested []string
+	c := NewClient("", nil)
+	c.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusFound,
+			Header:     http.Header{"Location": {"http://127.0.0.1:3000/api/v1/admin/users"}},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	})
+
+	var v map[string]interface{}
+	err := c.Fetch(context.Background(), "https://93.184.216.34/users/alice", &v)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "local network")
+	assert.Equal(t, []string{"https://93.184.216.34/users/alice"}, requested)
+}
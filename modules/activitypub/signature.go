@@ -0,0 +1,215 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxClockSkew is the maximum difference between the date of a signed request and the time it is verified
+const maxClockSkew = 12 * time.Hour
+
+// ErrInvalidSignature represents a missing or invalid HTTP signature
+type ErrInvalidSignature struct {
+	Reason string
+}
+
+// IsErrInvalidSignature checks if an error is a ErrInvalidSignature.
+func IsErrInvalidSignature(err error) bool {
+	_, ok := err.(ErrInvalidSignature)
+	return ok
+}
+
+func (err ErrInvalidSignature) Error() string {
+	return fmt.Sprintf("invalid HTTP signature: %s", err.Reason)
+}
+
+// GenerateKeyPair generates a RSA key pair for an actor, encoded as PEM
+func GenerateKeyPair() (privateKey, publicKey string, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", "", err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	privateKey = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	publicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	return privateKey, publicKey, nil
+}
+
+// ParsePrivateKey parses a PEM encoded RSA private key
+func ParsePrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// ParsePublicKey parses a PEM encoded RSA public key in the PKIX or PKCS #1 format
+func ParsePublicKey(publicKey string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not a RSA public key")
+	}
+	return rsaKey, nil
+}
+
+// Digest returns the value of the Digest header of a body
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func signingString(req *http.Request, headers []string) (string, error) {
+	lines := make([]string, 0, len(headers))
+	for _, header := range headers {
+		var value string
+		switch header {
+		case "(request-target)":
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		default:
+			values := req.Header.Values(header)
+			if len(values) == 0 {
+				return "", ErrInvalidSignature{Reason: fmt.Sprintf("signed header %q is missing", header)}
+			}
+			value = strings.Join(values, ", ")
+		}
+		lines = append(lines, header+": "+strings.TrimSpace(value))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// SignRequest signs a request with the key of an actor. The Date, Host and, if there is a body, Digest headers are set and signed.
+func SignRequest(req *http.Request, body []byte, keyID string, key *rsa.PrivateKey) error {
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	headers := []string{"(request-target)", "host", "date"}
+	if body != nil {
+		req.Header.Set("Digest", Digest(body))
+		headers = append(headers, "digest")
+	}
+
+	toSign, err := signingString(req, headers)
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(toSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+func parseSignatureHeader(header string) map[string]string {
+	params := make(map[string]string)
+	for _, part := range strings.Split(header, ",") {
+		idx := strings.IndexByte(part, '=')
+		if idx < 0 {
+			continue
+		}
+		params[strings.TrimSpace(part[:idx])] = strings.Trim(strings.TrimSpace(part[idx+1:]), `"`)
+	}
+	return params
+}
+
+// SignatureKeyID returns the ID of the key a request claims to be signed with
+func SignatureKeyID(req *http.Request) (string, error) {
+	keyID := parseSignatureHeader(req.Header.Get("Signature"))["keyId"]
+	if keyID == "" {
+		return "", ErrInvalidSignature{Reason: "no keyId"}
+	}
+	return keyID, nil
+}
+
+// VerifyRequest verifies the signature of a request with the public key of its keyId.
+// The signature has to cover the request target, the date and, if there is a body, its digest.
+func VerifyRequest(req *http.Request, body []byte, key *rsa.PublicKey) error {
+	params := parseSignatureHeader(req.Header.Get("Signature"))
+	if params["signature"] == "" {
+		return ErrInvalidSignature{Reason: "no signature"}
+	}
+	if algorithm := params["algorithm"]; algorithm != "" && algorithm != "rsa-sha256" && algorithm != "hs2019" {
+		return ErrInvalidSignature{Reason: fmt.Sprintf("unsupported algorithm %q", algorithm)}
+	}
+
+	headers := strings.Fields(strings.ToLower(params["headers"]))
+	if len(headers) == 0 {
+		headers = []string{"date"}
+	}
+	required := []string{"(request-target)", "date"}
+	if body != nil {
+		required = append(required, "digest")
+	}
+	for _, header := range required {
+		found := false
+		for _, signed := range headers {
+			if signed == header {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrInvalidSignature{Reason: fmt.Sprintf("header %q is not signed", header)}
+		}
+	}
+
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil {
+		return ErrInvalidSignature{Reason: "invalid date"}
+	}
+	if skew := time.Since(date); skew > maxClockSkew || skew < -maxClockSkew {
+		return ErrInvalidSignature{Reason: "date is too far from now"}
+	}
+	if body != nil && req.Header.Get("Digest") != Digest(body) {
+		return ErrInvalidSignature{Reason: "digest does not match the body"}
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return ErrInvalidSignature{Reason: "signature is not base64 encoded"}
+	}
+	toVerify, err := signingString(req, headers)
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(toVerify))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature); err != nil {
+		return ErrInvalidSignature{Reason: "signature does not match"}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignRequest(t *testing.T) {
+	privatePem, publicPem, err := GenerateKeyPair()
+	assert.NoError(t, err)
+	privateKey, err := ParsePrivateKey(privatePem)
+	assert.NoError(t, err)
+	publicKey, err := ParsePublicKey(publicPem)
+	assert.NoError(t, err)
+
+	body := []byte(`{"type":"Follow"}`)
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "https://example.com/api/v1/activitypub/user-id/1/inbox", strings.NewReader(string(body)))
+		assert.NoError(t, err)
+		assert.NoError(t, SignRequest(req, body, "https://remote.example/users/alice#main-key", privateKey))
+		return req
+	}
+
+	req := newRequest()
+	keyID, err := SignatureKeyID(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://remote.example/users/alice#main-key", keyID)
+	assert.NoError(t, VerifyRequest(req, body, publicKey))
+
+	// tampered body
+	err = VerifyRequest(req, []byte(`{"type":"Undo"}`), publicKey)
+	assert.True(t, IsErrInvalidSignature(err))
+
+	// tampered target
+	req = newRequest()
+	req.URL.Path = "/api/v1/activitypub/user-id/2/inbox"
+	err = VerifyRequest(req, body, publicKey)
+	assert.True(t, IsErrInvalidSignature(err))
+
+	// expired date, the signature does not match either
+	req = newRequest()
+	req.Header.Set("Date", time.Now().Add(-24*time.Hour).UTC().Format(http.TimeFormat))
+	err = VerifyRequest(req, body, publicKey)
+	assert.True(t, IsErrInvalidSignature(err))
+
+	// other key
+	_, otherPem, err := GenerateKeyPair()
+	assert.NoError(t, err)
+	otherKey, err := ParsePublicKey(otherPem)
+	assert.NoError(t, err)
+	err = VerifyRequest(newRequest(), body, otherKey)
+	assert.True(t, IsErrInvalidSignature(err))
+
+	// unsigned
+	req, err = http.NewRequest(http.MethodPost, "https://example.com/inbox", nil)
+	assert.NoError(t, err)
+	_, err = SignatureKeyID(req)
+	assert.True(t, IsErrInvalidSignature(err))
+	assert.True(t, IsErrInvalidSignature(VerifyRequest(req, body, publicKey)))
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"code.gitea.io/gitea/modules/json"
+)
+
+// The contexts and content types of ActivityPub
+const (
+	ActivityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	SecurityContext        = "https://w3id.org/security/v1"
+	ForgeFedContext        = "https://forgefed.org/ns"
+	PublicCollection       = ActivityStreamsContext + "#Public"
+
+	ContentType   = "application/activity+json"
+	LDContentType = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+)
+
+// The types of the actors and activities used by Gitea
+const (
+	TypePerson            = "Person"
+	TypeGroup             = "Group"
+	TypeNote              = "Note"
+	TypeOrderedCollection = "OrderedCollection"
+	TypeRepository        = "Repository"
+	TypeTicket            = "Ticket"
+
+	TypeFollow   = "Follow"
+	TypeAccept   = "Accept"
+	TypeReject   = "Reject"
+	TypeUndo     = "Undo"
+	TypeCreate   = "Create"
+	TypeAnnounce = "Announce"
+	TypeLike     = "Like"
+	TypeStar     = "Star"
+	TypeOffer    = "Offer"
+)
+
+// IRI represents a reference to an object, which is either given as its ID or embedded
+type IRI string
+
+// UnmarshalJSON accepts an ID or an embedded object with an ID
+func (iri *IRI) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*iri = IRI(id)
+		return nil
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*iri = IRI(object.ID)
+	return nil
+}
+
+// PublicKey represents the key HTTP signatures of an actor are verified with
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Endpoints represents the endpoints shared by the actors of an instance
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+// Image represents the icon of an actor
+type Image struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Actor represents a user, an organization or a repository
+type Actor struct {
+	Context           interface{} `json:"@context,omitempty"`
+	ID                string      `json:"id"`
+	Type              string      `json:"type"`
+	PreferredUsername string      `json:"preferredUsername"`
+	Name              string      `json:"name,omitempty"`
+	Summary           string      `json:"summary,omitempty"`
+	URL               string      `json:"url,omitempty"`
+	Icon              *Image      `json:"icon,omitempty"`
+	Inbox             string      `json:"inbox"`
+	Outbox            string      `json:"outbox,omitempty"`
+	Followers         string      `json:"followers,omitempty"`
+	Following         string      `json:"following,omitempty"`
+	Endpoints         *Endpoints  `json:"endpoints,omitempty"`
+	PublicKey         PublicKey   `json:"publicKey"`
+}
+
+// Activity represents an activity, the object is kept as is to be interpreted according to the type
+type Activity struct {
+	Context   interface{}     `json:"@context,omitempty"`
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Actor     IRI             `json:"actor"`
+	Object    json.RawMessage `json:"object,omitempty"`
+	Target    IRI             `json:"target,omitempty"`
+	Result    string          `json:"result,omitempty"`
+	To        []string        `json:"to,omitempty"`
+	Cc        []string        `json:"cc,omitempty"`
+	Published string          `json:"published,omitempty"`
+}
+
+// ObjectIRI returns the ID of the object of an activity
+func (a *Activity) ObjectIRI() IRI {
+	var iri IRI
+	if len(a.Object) > 0 {
+		_ = iri.UnmarshalJSON(a.Object)
+	}
+	return iri
+}
+
+// ObjectActivity returns the object of an activity which is an embedded activity, e.g. the Follow of an Undo
+func (a *Activity) ObjectActivity() (*Activity, error) {
+	object := new(Activity)
+	if err := json.Unmarshal(a.Object, object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// Source represents the source an object was rendered from, e.g. Markdown
+type Source struct {
+	Content   string `json:"content"`
+	MediaType string `json:"mediaType"`
+}
+
+// SourceContent returns the Markdown source of an object if it has one, its HTML content otherwise
+func SourceContent(source *Source, content string) string {
+	if source != nil && source.MediaType == "text/markdown" {
+		return source.Content
+	}
+	return content
+}
+
+// Note represents a short text, Gitea publishes its activities as notes. Remote users comment on issues with notes.
+type Note struct {
+	ID           string   `json:"id,omitempty"`
+	Type         string   `json:"type"`
+	AttributedTo IRI      `json:"attributedTo"`
+	Content      string   `json:"content"`
+	Source       *Source  `json:"source,omitempty"`
+	InReplyTo    IRI      `json:"inReplyTo,omitempty"`
+	URL          string   `json:"url,omitempty"`
+	To           []string `json:"to,omitempty"`
+	Published    string   `json:"published,omitempty"`
+}
+
+// Ticket represents an issue of the ForgeFed vocabulary, remote users offer tickets to open issues
+type Ticket struct {
+	ID           string  `json:"id,omitempty"`
+	Type         string  `json:"type"`
+	AttributedTo IRI     `json:"attributedTo"`
+	Summary      string  `json:"summary"`
+	Name         string  `json:"name,omitempty"`
+	Content      string  `json:"content"`
+	Source       *Source `json:"source,omitempty"`
+	Context      IRI     `json:"context,omitempty"`
+}
+
+// OrderedCollection represents an ordered list of objects, e.g. the outbox or the followers of an actor
+type OrderedCollection struct {
+	Context      interface{}   `json:"@context,omitempty"`
+	ID           string        `json:"id"`
+	Type         string        `json:"type"`
+	TotalItems   int64         `json:"totalItems"`
+	OrderedItems []interface{} `json:"orderedItems"`
+}
@@ -57,6 +57,12 @@ func RandomImage(data []byte) (image.Image, error) {
 // Prepare accepts a byte slice as input, validates it contains an image of an
 // acceptable format, and crops and resizes it appropriately.
 func Prepare(data []byte) (*image.Image, error) {
+	return PrepareWithCrop(data, image.Rectangle{})
+}
+
+// PrepareWithCrop works like Prepare, but first crops the image to the given area
+// unless it is empty.
+func PrepareWithCrop(data []byte, crop image.Rectangle) (*image.Image, error) {
 	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("DecodeConfig: %v", err)
@@ -73,6 +79,21 @@ func Prepare(data []byte) (*image.Image, error) {
 		return nil, fmt.Errorf("Decode: %v", err)
 	}
 
+	if !crop.Empty() {
+		if !crop.In(image.Rect(0, 0, imgCfg.Width, imgCfg.Height)) {
+			return nil, fmt.Errorf("Crop area %v is out of the image bounds: %dx%d", crop, imgCfg.Width, imgCfg.Height)
+		}
+		img, err = cutter.Crop(img, cutter.Config{
+			Width:  crop.Dx(),
+			Height: crop.Dy(),
+			Anchor: crop.Min,
+		})
+		if err != nil {
+			return nil, err
+		}
+		imgCfg.Width, imgCfg.Height = crop.Dx(), crop.Dy()
+	}
+
 	if imgCfg.Width != imgCfg.Height {
 		var newSize, ax, ay int
 		if imgCfg.Width > imgCfg.Height {
@@ -5,6 +5,7 @@
 package avatar
 
 import (
+	"image"
 	"os"
 	"testing"
 
@@ -71,3 +72,42 @@ func Test_PrepareWithInvalidImageSize(t *testing.T) {
 	_, err = Prepare(data)
 	assert.EqualError(t, err, "Image width is too large: 10 > 5")
 }
+
+func Test_PrepareWithCrop(t *testing.T) {
+	setting.Avatar.MaxWidth = 4096
+	setting.Avatar.MaxHeight = 4096
+
+	data, err := os.ReadFile("testdata/avatar.png")
+	assert.NoError(t, err)
+
+	imgPtr, err := PrepareWithCrop(data, image.Rect(2, 2, 8, 6))
+	assert.NoError(t, err)
+	assert.Equal(t, 290, (*imgPtr).Bounds().Dx())
+	assert.Equal(t, 290, (*imgPtr).Bounds().Dy())
+
+	_, err = PrepareWithCrop(data, image.Rect(5, 5, 15, 15))
+	assert.EqualError(t, err, "Crop area (5,5)-(15,15) is out of the image bounds: 10x10")
+}
+
+func Test_Initials(t *testing.T) {
+	assert.Equal(t, "JD", Initials("John Doe"))
+	assert.Equal(t, "JD", Initials("john-ronald.doe"))
+	assert.Equal(t, "G", Initials("gitea"))
+	assert.Equal(t, "?", Initials("--"))
+	assert.Equal(t, "?", Initials("ñandú"))
+	assert.Equal(t, "?", Initials(""))
+}
+
+func Test_InitialsImageSize(t *testing.T) {
+	_, err := InitialsImageSize(0, "gitea")
+	assert.Error(t, err)
+
+	img, err := InitialsImageSize(64, "gitea")
+	assert.NoError(t, err)
+	assert.Equal(t, 64, img.Bounds().Dx())
+
+	// the same name always gives the same image
+	other, err := InitialsImageSize(64, "gitea")
+	assert.NoError(t, err)
+	assert.Equal(t, img, other)
+}
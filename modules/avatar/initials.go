@@ -0,0 +1,154 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package avatar
+
+import (
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"unicode"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyphs is a 5x7 bitmap font for the characters initials avatars can show,
+// the highest of the 5 lowest bits of each row is the leftmost pixel
+var glyphs = map[rune][glyphHeight]uint8{
+	'A': {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B': {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C': {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D': {0b11110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b11110},
+	'E': {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F': {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G': {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H': {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I': {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J': {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K': {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L': {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M': {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N': {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O': {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P': {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q': {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R': {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S': {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T': {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U': {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V': {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W': {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X': {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y': {0b10001, 0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100},
+	'Z': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	'0': {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1': {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3': {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4': {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5': {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6': {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8': {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9': {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'?': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b00000, 0b00100},
+}
+
+// initialsBackgrounds are the background colors of initials avatars
+var initialsBackgrounds = []color.RGBA{
+	{0xdb, 0x28, 0x28, 0xff},
+	{0xf2, 0x71, 0x1c, 0xff},
+	{0xb5, 0x8d, 0x00, 0xff},
+	{0x21, 0xba, 0x45, 0xff},
+	{0x00, 0xb5, 0xad, 0xff},
+	{0x21, 0x85, 0xd0, 0xff},
+	{0x64, 0x35, 0xc9, 0xff},
+	{0xa3, 0x33, 0xc8, 0xff},
+	{0xe0, 0x39, 0x97, 0xff},
+	{0xa5, 0x67, 0x3f, 0xff},
+	{0x76, 0x76, 0x76, 0xff},
+}
+
+// Initials returns the up to two uppercase characters shown by the initials avatar of name:
+// the first characters of its first and last words
+func Initials(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "?"
+	}
+
+	initials := make([]rune, 0, 2)
+	initials = append(initials, []rune(words[0])[0])
+	if len(words) > 1 {
+		initials = append(initials, []rune(words[len(words)-1])[0])
+	}
+	for i, r := range initials {
+		r = unicode.ToUpper(r)
+		if _, ok := glyphs[r]; !ok {
+			r = '?'
+		}
+		initials[i] = r
+	}
+	return string(initials)
+}
+
+// InitialsImageSize generates and returns an avatar image showing the initials of name
+// in custom size (height and width). The background color is derived from name.
+func InitialsImageSize(size int, name string) (image.Image, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid avatar size: %d", size)
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	background := initialsBackgrounds[h.Sum32()%uint32(len(initialsBackgrounds))]
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	initials := []rune(Initials(name))
+	// the glyphs take up about two fifths of the avatar height, separated by a one pixel wide column
+	scale := size * 2 / (5 * glyphHeight)
+	if scale < 1 {
+		scale = 1
+	}
+	textWidth := (len(initials)*(glyphWidth+1) - 1) * scale
+	x0 := (size - textWidth) / 2
+	y0 := (size - glyphHeight*scale) / 2
+
+	foreground := &image.Uniform{color.White}
+	for i, r := range initials {
+		glyph := glyphs[r]
+		gx := x0 + i*(glyphWidth+1)*scale
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if glyph[row]&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				px := image.Rect(gx+col*scale, y0+row*scale, gx+(col+1)*scale, y0+(row+1)*scale)
+				draw.Draw(img, px, foreground, image.Point{}, draw.Src)
+			}
+		}
+	}
+	return img, nil
+}
+
+// GeneratedImage generates and returns an avatar image in default size, in the style
+// configured by setting.Avatar.GeneratedStyle: an identicon unique to data, or the initials of name.
+func GeneratedImage(data []byte, name string) (image.Image, error) {
+	if setting.Avatar.GeneratedStyle == "initials" {
+		return InitialsImageSize(AvatarSize, name)
+	}
+	return RandomImage(data)
+}
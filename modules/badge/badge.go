@@ -0,0 +1,87 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"text/template"
+
+	"code.gitea.io/gitea/modules/cache"
+)
+
+// Colors used by status badges
+const (
+	ColorGreen  = "#4c1"
+	ColorYellow = "#dfb317"
+	ColorOrange = "#fe7d37"
+	ColorRed    = "#e05d44"
+	ColorGrey   = "#9f9f9f"
+	ColorBlue   = "#007ec6"
+)
+
+const (
+	horizontalPadding = 5
+	// defaultCharWidth is the average width of a character in 11px Verdana
+	defaultCharWidth = 7
+)
+
+// charWidths holds the widths of characters which differ noticeably from the default width
+var charWidths = map[rune]int{
+	' ': 4, '.': 4, ',': 4, ':': 4, ';': 4, '!': 4, '|': 4, '\'': 3,
+	'i': 3, 'j': 4, 'l': 3, 'f': 4, 't': 5, 'r': 5, 'I': 5,
+	'm': 11, 'w': 9, 'M': 10, 'W': 11,
+}
+
+var badgeTemplate = template.Must(template.New("badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">` +
+	`<title>{{.Label}}: {{.Message}}</title>` +
+	`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` +
+	`<clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>` +
+	`<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="20" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/><rect width="{{.Width}}" height="20" fill="url(#s)"/></g>` +
+	`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+	`<text x="{{.LabelX}}" y="15" fill="#010101" fill-opacity=".3">{{.Label}}</text><text x="{{.LabelX}}" y="14">{{.Label}}</text>` +
+	`<text x="{{.MessageX}}" y="15" fill="#010101" fill-opacity=".3">{{.Message}}</text><text x="{{.MessageX}}" y="14">{{.Message}}</text>` +
+	`</g></svg>`))
+
+// textWidth estimates the width of the text in pixels
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		if w, ok := charWidths[r]; ok {
+			width += w
+		} else {
+			width += defaultCharWidth
+		}
+	}
+	return width
+}
+
+// Generate renders a flat badge with the label on the left and the message on the right
+func Generate(label, message, color string) string {
+	labelWidth := textWidth(label) + 2*horizontalPadding
+	messageWidth := textWidth(message) + 2*horizontalPadding
+
+	var buf bytes.Buffer
+	// the template is static and the data is escaped, so executing it can't fail
+	_ = badgeTemplate.Execute(&buf, map[string]interface{}{
+		"Label":        html.EscapeString(label),
+		"Message":      html.EscapeString(message),
+		"Color":        html.EscapeString(color),
+		"Width":        labelWidth + messageWidth,
+		"LabelWidth":   labelWidth,
+		"MessageWidth": messageWidth,
+		"LabelX":       fmt.Sprintf("%.1f", float64(labelWidth)/2),
+		"MessageX":     fmt.Sprintf("%.1f", float64(labelWidth)+float64(messageWidth)/2),
+	})
+	return buf.String()
+}
+
+// GetOrGenerate returns the cached badge or renders and caches it
+func GetOrGenerate(label, message, color string) (string, error) {
+	return cache.GetString(fmt.Sprintf("badge_%s_%s_%s", label, message, color), func() (string, error) {
+		return Generate(label, message, color), nil
+	})
+}
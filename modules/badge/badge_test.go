@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package badge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 0, textWidth(""))
+	assert.Equal(t, 3*defaultCharWidth, textWidth("abc"))
+	assert.Less(t, textWidth("ill"), textWidth("www"))
+}
+
+func TestGenerate(t *testing.T) {
+	svg := Generate("build", "passing", ColorGreen)
+	assert.Contains(t, svg, `aria-label="build: passing"`)
+	assert.Contains(t, svg, `fill="#4c1"`)
+	assert.Contains(t, svg, `width="92"`)
+
+	svg = Generate("<script>", "a&b", ColorRed)
+	assert.NotContains(t, svg, "<script>")
+	assert.Contains(t, svg, "&lt;script&gt;")
+	assert.Contains(t, svg, "a&amp;b")
+}
@@ -5,6 +5,7 @@
 package cache
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -45,6 +46,25 @@ func GetCache() mc.Cache {
 	return conn
 }
 
+const (
+	pingKey   = "__gitea_cache_ping"
+	pingValue = "pong"
+)
+
+// Ping checks that the cache works by storing and reading back a value
+func Ping() error {
+	if conn == nil {
+		return errors.New("cache is not initialized")
+	}
+	if err := conn.Put(pingKey, pingValue, 10); err != nil {
+		return err
+	}
+	if value := fmt.Sprintf("%v", conn.Get(pingKey)); value != pingValue {
+		return fmt.Errorf("cache returned %q instead of %q", value, pingValue)
+	}
+	return nil
+}
+
 // GetString returns the key value from cache with callback when no key exists in cache
 func GetString(key string, getFunc func() (string, error)) (string, error) {
 	if conn == nil || setting.CacheService.TTL == 0 {
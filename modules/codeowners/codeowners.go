@@ -0,0 +1,242 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FilePaths are the locations of the code owners file of a repository, in the order they are looked up
+var FilePaths = []string{".gitea/CODEOWNERS", ".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+var (
+	userOwnerPattern  = regexp.MustCompile(`^@[\w.-]+$`)
+	teamOwnerPattern  = regexp.MustCompile(`^@[\w.-]+/[\w.-]+$`)
+	emailOwnerPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// OwnerType is the type of an owner of a code owners rule
+type OwnerType string
+
+// The types of owners
+const (
+	OwnerTypeUser  OwnerType = "user"
+	OwnerTypeTeam  OwnerType = "team"
+	OwnerTypeEmail OwnerType = "email"
+)
+
+// Owner is an owner of a code owners rule, a user, a team of an organization or the email address of a user
+type Owner struct {
+	Type OwnerType
+	// Name is the user name, the team name or the email address
+	Name string
+	// Org is the organization of a team
+	Org string
+}
+
+func (o *Owner) String() string {
+	switch o.Type {
+	case OwnerTypeTeam:
+		return "@" + o.Org + "/" + o.Name
+	case OwnerTypeUser:
+		return "@" + o.Name
+	}
+	return o.Name
+}
+
+// Rule is a line of a code owners file assigning owners to the paths matching a pattern
+type Rule struct {
+	Line    int
+	Pattern string
+	Owners  []*Owner
+
+	re *regexp.Regexp
+}
+
+// Match returns true if the rule applies to a file path
+func (r *Rule) Match(path string) bool {
+	return r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// SyntaxError is a line of a code owners file which cannot be parsed
+type SyntaxError struct {
+	Line   int
+	Reason string
+}
+
+func (err SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Reason)
+}
+
+// File is a parsed code owners file
+type File struct {
+	Rules []*Rule
+}
+
+// Parse parses a code owners file, the lines with syntax errors are skipped and reported
+func Parse(content []byte) (*File, []SyntaxError) {
+	file := &File{}
+	var errs []SyntaxError
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			errs = append(errs, SyntaxError{Line: line, Reason: err.Error()})
+			continue
+		}
+		rule := &Rule{Line: line, Pattern: fields[0], re: re}
+
+		valid := true
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "#") {
+				break
+			}
+			owner, err := parseOwner(field)
+			if err != nil {
+				errs = append(errs, SyntaxError{Line: line, Reason: err.Error()})
+				valid = false
+				break
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		if valid {
+			file.Rules = append(file.Rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, SyntaxError{Line: line + 1, Reason: err.Error()})
+	}
+	return file, errs
+}
+
+// Match returns the rule which applies to a file path, the last matching rule takes precedence
+func (f *File) Match(path string) *Rule {
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].Match(path) {
+			return f.Rules[i]
+		}
+	}
+	return nil
+}
+
+// UnmatchedRules returns the rules which do not apply to any of the file paths,
+// either because they match none of them or because later rules take precedence for all of them
+func (f *File) UnmatchedRules(paths []string) []*Rule {
+	used := make(map[*Rule]bool, len(f.Rules))
+	for _, path := range paths {
+		if rule := f.Match(path); rule != nil {
+			used[rule] = true
+		}
+	}
+
+	var rules []*Rule
+	for _, rule := range f.Rules {
+		if !used[rule] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func parseOwner(field string) (*Owner, error) {
+	switch {
+	case teamOwnerPattern.MatchString(field):
+		parts := strings.SplitN(field[1:], "/", 2)
+		return &Owner{Type: OwnerTypeTeam, Org: parts[0], Name: parts[1]}, nil
+	case userOwnerPattern.MatchString(field):
+		return &Owner{Type: OwnerTypeUser, Name: field[1:]}, nil
+	case emailOwnerPattern.MatchString(field):
+		return &Owner{Type: OwnerTypeEmail, Name: field}, nil
+	}
+	return nil, fmt.Errorf("invalid owner %q, owners must be @user, @org/team or an email address", field)
+}
+
+// compilePattern converts a gitignore style pattern of a code owners file to a regular expression
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") {
+		return nil, fmt.Errorf("negated pattern %q is not supported", pattern)
+	}
+	if strings.ContainsAny(pattern, "[]") {
+		return nil, fmt.Errorf("character ranges in pattern %q are not supported", pattern)
+	}
+
+	p := strings.TrimPrefix(pattern, "/")
+	// patterns with a slash other than a trailing one are relative to the root of the repository
+	anchored := p != pattern || strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	isDir := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("pattern %q matches no path", pattern)
+	}
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(?:.*/)?")
+	}
+
+	segments := strings.Split(p, "/")
+	last := len(segments) - 1
+	for i, segment := range segments {
+		if segment == "**" {
+			if i == last {
+				sb.WriteString(".*$")
+				return regexp.Compile(sb.String())
+			}
+			sb.WriteString("(?:.*/)?")
+			continue
+		}
+		if err := writeSegment(&sb, segment); err != nil {
+			return nil, fmt.Errorf("%v in pattern %q", err, pattern)
+		}
+		if i < last {
+			sb.WriteString("/")
+		}
+	}
+
+	if isDir {
+		sb.WriteString("/.*$")
+	} else {
+		// a pattern matching a directory applies to all its files
+		sb.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(sb.String())
+}
+
+func writeSegment(sb *strings.Builder, segment string) error {
+	for i := 0; i < len(segment); i++ {
+		switch segment[i] {
+		case '*':
+			sb.WriteString("[^/]*")
+			for i+1 < len(segment) && segment[i+1] == '*' {
+				i++
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '\\':
+			if i+1 == len(segment) {
+				return fmt.Errorf("trailing backslash")
+			}
+			i++
+			sb.WriteString(regexp.QuoteMeta(segment[i : i+1]))
+		default:
+			sb.WriteString(regexp.QuoteMeta(segment[i : i+1]))
+		}
+	}
+	return nil
+}
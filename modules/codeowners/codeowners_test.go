@@ -0,0 +1,87 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	file, errs := Parse([]byte(`# default owners
+*               @admin
+*.go            @gopher @org/backend # go code
+/docs/          docs@example.com
+web_src/**/*.js @org/frontend
+!vendor         @admin
+LICENSE         admin
+`))
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, 6, errs[0].Line)
+		assert.Equal(t, 7, errs[1].Line)
+	}
+	if !assert.Len(t, file.Rules, 4) {
+		return
+	}
+	assert.Equal(t, []*Owner{{Type: OwnerTypeUser, Name: "gopher"}, {Type: OwnerTypeTeam, Org: "org", Name: "backend"}}, file.Rules[1].Owners)
+	assert.Equal(t, "docs@example.com", file.Rules[2].Owners[0].String())
+
+	for path, line := range map[string]int{
+		"README.md":                   2,
+		"main.go":                     3,
+		"modules/git/repo.go":         3,
+		"docs/install.md":             4,
+		"modules/docs/install.md":     2,
+		"web_src/js/index.js":         5,
+		"web_src/js/features/repo.js": 5,
+		"web_src/index.js":            5,
+		"web_src/less/index.less":     2,
+	} {
+		rule := file.Match(path)
+		if assert.NotNil(t, rule, path) {
+			assert.Equal(t, line, rule.Line, path)
+		}
+	}
+
+	unmatched := file.UnmatchedRules([]string{"main.go", "docs/install.md"})
+	if assert.Len(t, unmatched, 2) {
+		assert.Equal(t, 2, unmatched[0].Line)
+		assert.Equal(t, 5, unmatched[1].Line)
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	for _, kase := range []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"docs", []string{"docs", "docs/a.md", "src/docs/a.md"}, []string{"docs.md"}},
+		{"/docs", []string{"docs", "docs/a.md"}, []string{"src/docs/a.md"}},
+		{"docs/", []string{"docs/a.md", "src/docs/a.md"}, []string{"docs"}},
+		{"src/*.go", []string{"src/main.go"}, []string{"src/cmd/main.go", "lib/src/main.go"}},
+		{"**/test/*", []string{"test/a.go", "pkg/test/a.go"}, []string{"test.go"}},
+		{"build/**", []string{"build/a", "build/x/y"}, []string{"src/build/a"}},
+		{"file?.txt", []string{"file1.txt"}, []string{"file10.txt"}},
+		{`\#notes`, []string{"#notes"}, []string{"notes"}},
+	} {
+		re, err := compilePattern(kase.pattern)
+		if !assert.NoError(t, err, kase.pattern) {
+			continue
+		}
+		for _, path := range kase.match {
+			assert.True(t, re.MatchString(path), "%s should match %s", kase.pattern, path)
+		}
+		for _, path := range kase.noMatch {
+			assert.False(t, re.MatchString(path), "%s should not match %s", kase.pattern, path)
+		}
+	}
+
+	for _, pattern := range []string{"/", "!docs", "src/[ab].go", `docs\`} {
+		_, err := compilePattern(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
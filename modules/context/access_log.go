@@ -47,6 +47,7 @@ func AccessLogger() func(http.Handler) http.Handler {
 				Ctx: map[string]interface{}{
 					"RemoteAddr": req.RemoteAddr,
 					"Req":        req,
+					"RequestID":  log.RequestIDFromContext(req.Context()),
 				},
 			})
 			if err != nil {
@@ -12,10 +12,14 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web/middleware"
 	"code.gitea.io/gitea/services/auth"
@@ -50,6 +54,27 @@ type APIInvalidTopicsError struct {
 	Message string   `json:"message"`
 }
 
+// APIReadOnlyError is the error response to writes while the instance is in maintenance mode or its database is read-only
+// swagger:response readOnlyError
+type APIReadOnlyError struct {
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	// Reason is "maintenance" or "database_read_only"
+	Reason string `json:"reason"`
+	// RetryAfter is the number of seconds to wait before retrying, it is also sent in the Retry-After header
+	RetryAfter int64 `json:"retry_after"`
+}
+
+// NewAPIReadOnlyError returns the error response to a write rejected because the instance is read-only
+func NewAPIReadOnlyError(err maintenance.ErrReadOnly) APIReadOnlyError {
+	return APIReadOnlyError{
+		Message:    err.Error(),
+		URL:        setting.API.SwaggerURL,
+		Reason:     err.Reason(),
+		RetryAfter: int64(err.RetryAfter / time.Second),
+	}
+}
+
 //APIEmpty is an empty response
 // swagger:response empty
 type APIEmpty struct{}
@@ -92,7 +117,12 @@ func (ctx *APIContext) Error(status int, title string, obj interface{}) {
 	}
 
 	if status == http.StatusInternalServerError {
-		log.ErrorWithSkip(1, "%s: %s", title, message)
+		if err, ok := obj.(error); ok && db.IsReadOnlyError(err) {
+			log.Warn("%s: %s%s", title, message, ctx.requestIDLogSuffix())
+			ctx.ReadOnlyError(maintenance.NewErrDatabaseReadOnly())
+			return
+		}
+		log.ErrorWithSkip(1, "%s: %s%s", title, message, ctx.requestIDLogSuffix())
 
 		if setting.IsProd() && !(ctx.User != nil && ctx.User.IsAdmin) {
 			message = ""
@@ -108,7 +138,12 @@ func (ctx *APIContext) Error(status int, title string, obj interface{}) {
 // InternalServerError responds with an error message to the client with the error as a message
 // and the file and line of the caller.
 func (ctx *APIContext) InternalServerError(err error) {
-	log.ErrorWithSkip(1, "InternalServerError: %v", err)
+	if db.IsReadOnlyError(err) {
+		log.Warn("InternalServerError: %v%s", err, ctx.requestIDLogSuffix())
+		ctx.ReadOnlyError(maintenance.NewErrDatabaseReadOnly())
+		return
+	}
+	log.ErrorWithSkip(1, "InternalServerError: %v%s", err, ctx.requestIDLogSuffix())
 
 	var message string
 	if !setting.IsProd() || (ctx.User != nil && ctx.User.IsAdmin) {
@@ -121,6 +156,12 @@ func (ctx *APIContext) InternalServerError(err error) {
 	})
 }
 
+// ReadOnlyError responds with 503 Service Unavailable to a write rejected because the instance is read-only
+func (ctx *APIContext) ReadOnlyError(err maintenance.ErrReadOnly) {
+	ctx.Resp.Header().Set("Retry-After", err.RetryAfterSeconds())
+	ctx.JSON(http.StatusServiceUnavailable, NewAPIReadOnlyError(err))
+}
+
 var (
 	apiContextKey interface{} = "default_api_context"
 )
@@ -175,6 +216,17 @@ func genAPILinks(curURL *url.URL, total, pageSize, curPage int) []string {
 	return links
 }
 
+// FieldSet returns the fields of the response requested by the fields query parameter, nil if all fields are requested
+func (ctx *APIContext) FieldSet() json.FieldSet {
+	return json.ParseFieldSet(ctx.FormString("fields"))
+}
+
+// JSONWithFields responds like JSON, but the objects of the response only have the fields requested by the fields
+// query parameter
+func (ctx *APIContext) JSONWithFields(status int, obj interface{}) {
+	ctx.JSON(status, ctx.FieldSet().Select(obj))
+}
+
 // SetLinkHeader sets pagination link header by given total number and page size.
 func (ctx *APIContext) SetLinkHeader(total, pageSize int) {
 	links := genAPILinks(ctx.Req.URL, total, pageSize, ctx.FormInt("page"))
@@ -185,6 +237,22 @@ func (ctx *APIContext) SetLinkHeader(total, pageSize int) {
 	}
 }
 
+// SetCursorLinkHeader sets the link header to the next page of a list paginated by cursor,
+// there is no next page if the cursor is empty
+func (ctx *APIContext) SetCursorLinkHeader(next string) {
+	if next == "" {
+		return
+	}
+	u := *ctx.Req.URL
+	queries := u.Query()
+	queries.Del("page")
+	queries.Set("cursor", next)
+	u.RawQuery = queries.Encode()
+
+	ctx.Header().Set("Link", fmt.Sprintf("<%s%s>; rel=\"next\"", setting.AppURL, u.RequestURI()[1:]))
+	ctx.AppendAccessControlExposeHeaders("Link")
+}
+
 // SetTotalCountHeader set "X-Total-Count" header
 func (ctx *APIContext) SetTotalCountHeader(total int64) {
 	ctx.Header().Set("X-Total-Count", fmt.Sprint(total))
@@ -324,7 +392,7 @@ func ReferencesGitRepo(allowEmpty bool) func(http.Handler) http.Handler {
 			// For API calls.
 			if ctx.Repo.GitRepo == nil {
 				repoPath := models.RepoPath(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
-				gitRepo, err := git.OpenRepository(repoPath)
+				gitRepo, err := git.OpenRepositoryCtx(ctx.GitContext(), repoPath)
 				if err != nil {
 					ctx.Error(http.StatusInternalServerError, "RepoRef Invalid repo "+repoPath, err)
 					return
@@ -382,7 +450,7 @@ func RepoRefForAPI(next http.Handler) http.Handler {
 
 		if ctx.Repo.GitRepo == nil {
 			repoPath := models.RepoPath(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
-			ctx.Repo.GitRepo, err = git.OpenRepository(repoPath)
+			ctx.Repo.GitRepo, err = git.OpenRepositoryCtx(ctx.GitContext(), repoPath)
 			if err != nil {
 				ctx.InternalServerError(err)
 				return
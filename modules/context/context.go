@@ -21,10 +21,13 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/base"
 	mc "code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/templates"
 	"code.gitea.io/gitea/modules/translation"
@@ -64,8 +67,9 @@ type Context struct {
 	IsSigned    bool
 	IsBasicAuth bool
 
-	Repo *Repository
-	Org  *Organization
+	Repo    *Repository
+	Org     *Organization
+	Package *Package
 }
 
 // GetData returns the data
@@ -126,7 +130,16 @@ func RedirectToUser(ctx *Context, userName string, redirectUserID int64) {
 	if ctx.Req.URL.RawQuery != "" {
 		redirectPath += "?" + ctx.Req.URL.RawQuery
 	}
-	ctx.Redirect(path.Join(setting.AppSubURL, redirectPath))
+	ctx.Redirect(path.Join(setting.AppSubURL, redirectPath), renameRedirectStatus(ctx.Req))
+}
+
+// renameRedirectStatus returns the status to redirect a request for a renamed user or repository with,
+// requests other than GET and HEAD, like git pushes and API writes, must keep their method and body
+func renameRedirectStatus(req *http.Request) int {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return http.StatusFound
+	}
+	return http.StatusTemporaryRedirect
 }
 
 // HasAPIError returns true if error occurs in form validation.
@@ -251,6 +264,25 @@ func (ctx *Context) notFoundInternal(title string, err error) {
 	ctx.HTML(http.StatusNotFound, base.TplName("status/404"))
 }
 
+// RequestID returns the id assigned to the request by the request id middleware
+func (ctx *Context) RequestID() string {
+	return log.RequestIDFromContext(ctx.Req.Context())
+}
+
+// requestIDLogSuffix returns the request id to append to log lines, if the request has one
+func (ctx *Context) requestIDLogSuffix() string {
+	if requestID := ctx.RequestID(); requestID != "" {
+		return " [request_id: " + requestID + "]"
+	}
+	return ""
+}
+
+// GitContext returns the context to run the git commands of the request in.
+// It carries the request id but unlike the request context it is not canceled when the client disconnects.
+func (ctx *Context) GitContext() context.Context {
+	return log.WithRequestID(git.DefaultContext, ctx.RequestID())
+}
+
 // ServerError displays a 500 (Internal Server Error) page and prints the given
 // error, if any.
 func (ctx *Context) ServerError(title string, err error) {
@@ -258,8 +290,18 @@ func (ctx *Context) ServerError(title string, err error) {
 }
 
 func (ctx *Context) serverErrorInternal(title string, err error) {
+	if db.IsReadOnlyError(err) {
+		log.Warn("%s: %v%s", title, err, ctx.requestIDLogSuffix())
+		readOnlyErr := maintenance.NewErrDatabaseReadOnly()
+		ctx.Resp.Header().Set("Retry-After", readOnlyErr.RetryAfterSeconds())
+		ctx.Data["Title"] = "Service Unavailable"
+		ctx.Data["ErrorMsg"] = ctx.Tr("error.database_read_only")
+		ctx.HTML(http.StatusServiceUnavailable, base.TplName("status/500"))
+		return
+	}
+
 	if err != nil {
-		log.ErrorWithSkip(2, "%s: %v", title, err)
+		log.ErrorWithSkip(2, "%s: %v%s", title, err, ctx.requestIDLogSuffix())
 		if !setting.IsProd() {
 			ctx.Data["ErrorMsg"] = err
 		}
@@ -722,6 +764,13 @@ func Contexter() func(next http.Handler) http.Handler {
 			ctx.Data["UnitPullsGlobalDisabled"] = models.UnitTypePullRequests.UnitGlobalDisabled()
 			ctx.Data["UnitProjectsGlobalDisabled"] = models.UnitTypeProjects.UnitGlobalDisabled()
 
+			if state := maintenance.Get(); state.Enabled {
+				ctx.Data["MaintenanceMode"] = true
+				ctx.Data["MaintenanceMessage"] = state.Message
+			} else if maintenance.IsDatabaseReadOnly() {
+				ctx.Data["DatabaseReadOnly"] = true
+			}
+
 			ctx.Data["i18n"] = locale
 			ctx.Data["Tr"] = i18n.Tr
 			ctx.Data["Lang"] = locale.Language()
@@ -0,0 +1,77 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Package contains owner and access mode of a package registry request
+type Package struct {
+	Owner      *models.User
+	AccessMode models.AccessMode
+}
+
+// PackageAssignmentAPI returns a middleware to handle context package assignment
+func PackageAssignmentAPI() func(ctx *APIContext) {
+	return func(ctx *APIContext) {
+		if !setting.Packages.Enabled {
+			ctx.NotFound()
+			return
+		}
+
+		owner, err := models.GetUserByName(ctx.Params("username"))
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound("GetUserByName", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+
+		accessMode, err := determinePackageAccessMode(owner, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "determinePackageAccessMode", err)
+			return
+		}
+
+		ctx.Package = &Package{
+			Owner:      owner,
+			AccessMode: accessMode,
+		}
+	}
+}
+
+func determinePackageAccessMode(owner, doer *models.User) (models.AccessMode, error) {
+	if doer != nil && (doer.IsAdmin || doer.ID == owner.ID) {
+		return models.AccessModeOwner, nil
+	}
+
+	if doer != nil && owner.IsOrganization() {
+		isOwner, err := owner.IsOwnedBy(doer.ID)
+		if err != nil {
+			return models.AccessModeNone, err
+		}
+		if isOwner {
+			return models.AccessModeOwner, nil
+		}
+		canWrite, err := owner.CanCreateOrgRepo(doer.ID)
+		if err != nil {
+			return models.AccessModeNone, err
+		}
+		if canWrite {
+			return models.AccessModeWrite, nil
+		}
+	}
+
+	if models.HasOrgOrUserVisible(owner, doer) {
+		return models.AccessModeRead, nil
+	}
+	return models.AccessModeNone, nil
+}
@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/issueform"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
@@ -311,7 +312,8 @@ func EarlyResponseForGoGetMeta(ctx *Context) {
 // RedirectToRepo redirect to a differently-named repository
 func RedirectToRepo(ctx *Context, redirectRepoID int64) {
 	ownerName := ctx.Params(":username")
-	previousRepoName := ctx.Params(":reponame")
+	// keep the suffixes of git http and wiki paths, they can't be part of repository names
+	previousRepoName := strings.TrimSuffix(strings.TrimSuffix(ctx.Params(":reponame"), ".git"), ".wiki")
 
 	repo, err := models.GetRepositoryByID(redirectRepoID)
 	if err != nil {
@@ -328,7 +330,7 @@ func RedirectToRepo(ctx *Context, redirectRepoID int64) {
 	if ctx.Req.URL.RawQuery != "" {
 		redirectPath += "?" + ctx.Req.URL.RawQuery
 	}
-	ctx.Redirect(path.Join(setting.AppSubURL, redirectPath))
+	ctx.Redirect(path.Join(setting.AppSubURL, redirectPath), renameRedirectStatus(ctx.Req))
 }
 
 func repoAssignment(ctx *Context, repo *models.Repository) {
@@ -526,7 +528,7 @@ func RepoAssignment(ctx *Context) (cancel context.CancelFunc) {
 		return
 	}
 
-	gitRepo, err := git.OpenRepository(models.RepoPath(userName, repoName))
+	gitRepo, err := git.OpenRepositoryCtx(ctx.GitContext(), models.RepoPath(userName, repoName))
 	if err != nil {
 		ctx.ServerError("RepoAssignment Invalid repo "+models.RepoPath(userName, repoName), err)
 		return
@@ -726,6 +728,36 @@ func getRefName(ctx *Context, pathType RepoRefType) string {
 	return ""
 }
 
+// redirectRenamedBranch redirects to the new name of the renamed branch the path starts with,
+// it returns false if the path does not start with a renamed branch
+func redirectRenamedBranch(ctx *Context) bool {
+	var renamed *models.RenamedBranch
+	oldName := getRefNameFromPath(ctx, ctx.Params("*"), func(name string) bool {
+		b, err := models.FindRenamedBranch(ctx.Repo.Repository.ID, name)
+		if err != nil {
+			log.Error("FindRenamedBranch: %v", err)
+			return false
+		}
+		renamed = b
+		return b != nil
+	})
+	if renamed == nil {
+		return false
+	}
+
+	link := path.Join(
+		setting.AppSubURL,
+		strings.TrimSuffix(ctx.Req.URL.Path, ctx.Params("*")),
+		util.PathEscapeSegments(renamed.To),
+		util.PathEscapeSegments(ctx.Repo.TreePath))
+	if ctx.Req.URL.RawQuery != "" {
+		link += "?" + ctx.Req.URL.RawQuery
+	}
+	ctx.Flash.Info(ctx.Tr("repo.branch.renamed", oldName, renamed.To))
+	ctx.Redirect(link)
+	return true
+}
+
 // RepoRefByType handles repository reference name for a specific type
 // of repository reference
 func RepoRefByType(refType RepoRefType, ignoreNotExistErr ...bool) func(*Context) context.CancelFunc {
@@ -742,7 +774,7 @@ func RepoRefByType(refType RepoRefType, ignoreNotExistErr ...bool) func(*Context
 
 		if ctx.Repo.GitRepo == nil {
 			repoPath := models.RepoPath(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
-			ctx.Repo.GitRepo, err = git.OpenRepository(repoPath)
+			ctx.Repo.GitRepo, err = git.OpenRepositoryCtx(ctx.GitContext(), repoPath)
 			if err != nil {
 				ctx.ServerError("RepoRef Invalid repo "+repoPath, err)
 				return
@@ -820,6 +852,9 @@ func RepoRefByType(refType RepoRefType, ignoreNotExistErr ...bool) func(*Context
 				if len(ignoreNotExistErr) > 0 && ignoreNotExistErr[0] {
 					return
 				}
+				if refType.RefTypeIncludesBranches() && redirectRenamedBranch(ctx) {
+					return
+				}
 				ctx.NotFound("RepoRef invalid repo", fmt.Errorf("branch or tag not exist: %s", refName))
 				return
 			}
@@ -899,39 +934,52 @@ func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
 			return issueTemplates
 		}
 		for _, entry := range entries {
-			if strings.HasSuffix(entry.Name(), ".md") {
-				if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
-					log.Debug("Issue template is too large: %s", entry.Name())
-					continue
-				}
-				r, err := entry.Blob().DataAsync()
-				if err != nil {
-					log.Debug("DataAsync: %v", err)
-					continue
-				}
-				closed := false
-				defer func() {
-					if !closed {
-						_ = r.Close()
-					}
-				}()
-				data, err := io.ReadAll(r)
-				if err != nil {
-					log.Debug("ReadAll: %v", err)
-					continue
+			if !strings.HasSuffix(entry.Name(), ".md") && !issueform.IsFormFile(entry.Name()) {
+				continue
+			}
+			if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
+				log.Debug("Issue template is too large: %s", entry.Name())
+				continue
+			}
+			r, err := entry.Blob().DataAsync()
+			if err != nil {
+				log.Debug("DataAsync: %v", err)
+				continue
+			}
+			closed := false
+			defer func() {
+				if !closed {
+					_ = r.Close()
 				}
-				_ = r.Close()
-				var it api.IssueTemplate
-				content, err := markdown.ExtractMetadata(string(data), &it)
+			}()
+			data, err := io.ReadAll(r)
+			if err != nil {
+				log.Debug("ReadAll: %v", err)
+				continue
+			}
+			_ = r.Close()
+
+			if issueform.IsFormFile(entry.Name()) {
+				it, err := issueform.Parse(data)
 				if err != nil {
-					log.Debug("ExtractMetadata: %v", err)
+					log.Debug("Issue form %s: %v", entry.Name(), err)
 					continue
 				}
-				it.Content = content
 				it.FileName = entry.Name()
-				if it.Valid() {
-					issueTemplates = append(issueTemplates, it)
-				}
+				issueTemplates = append(issueTemplates, *it)
+				continue
+			}
+
+			var it api.IssueTemplate
+			content, err := markdown.ExtractMetadata(string(data), &it)
+			if err != nil {
+				log.Debug("ExtractMetadata: %v", err)
+				continue
+			}
+			it.Content = content
+			it.FileName = entry.Name()
+			if it.Valid() {
+				issueTemplates = append(issueTemplates, it)
 			}
 		}
 		if len(issueTemplates) > 0 {
@@ -940,3 +988,14 @@ func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
 	}
 	return issueTemplates
 }
+
+// IssueFormFromDefaultBranch returns the issue form with the given file name in the repo's default branch,
+// or nil if there is none
+func (ctx *Context) IssueFormFromDefaultBranch(fileName string) *api.IssueTemplate {
+	for _, it := range ctx.IssueTemplatesFromDefaultBranch() {
+		if it.FileName == fileName && it.IsForm() {
+			return &it
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToBackup converts a backup to API format
+func ToBackup(backup *models.Backup) *api.Backup {
+	result := &api.Backup{
+		ID:      backup.ID,
+		Type:    backup.Type.String(),
+		Status:  backup.Status.String(),
+		Size:    backup.Size,
+		Error:   backup.Error,
+		Created: backup.CreatedUnix.AsTime(),
+	}
+	if backup.Type == models.BackupTypeRepository {
+		result.Repository = backup.OwnerName + "/" + backup.RepoName
+	}
+	if backup.FinishedUnix > 0 {
+		finished := backup.FinishedUnix.AsTime()
+		result.Finished = &finished
+	}
+	return result
+}
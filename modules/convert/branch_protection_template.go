@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToBranchProtectionTemplate converts a branch protection template to API format
+func ToBranchProtectionTemplate(t *models.BranchProtectionTemplate) *api.BranchProtectionTemplate {
+	pushWhitelistUsernames, err := models.GetUserNamesByIDs(t.WhitelistUserIDs)
+	if err != nil {
+		log.Error("GetUserNamesByIDs (WhitelistUserIDs): %v", err)
+	}
+	mergeWhitelistUsernames, err := models.GetUserNamesByIDs(t.MergeWhitelistUserIDs)
+	if err != nil {
+		log.Error("GetUserNamesByIDs (MergeWhitelistUserIDs): %v", err)
+	}
+	approvalsWhitelistUsernames, err := models.GetUserNamesByIDs(t.ApprovalsWhitelistUserIDs)
+	if err != nil {
+		log.Error("GetUserNamesByIDs (ApprovalsWhitelistUserIDs): %v", err)
+	}
+	pushWhitelistTeams, err := models.GetTeamNamesByID(t.WhitelistTeamIDs)
+	if err != nil {
+		log.Error("GetTeamNamesByID (WhitelistTeamIDs): %v", err)
+	}
+	mergeWhitelistTeams, err := models.GetTeamNamesByID(t.MergeWhitelistTeamIDs)
+	if err != nil {
+		log.Error("GetTeamNamesByID (MergeWhitelistTeamIDs): %v", err)
+	}
+	approvalsWhitelistTeams, err := models.GetTeamNamesByID(t.ApprovalsWhitelistTeamIDs)
+	if err != nil {
+		log.Error("GetTeamNamesByID (ApprovalsWhitelistTeamIDs): %v", err)
+	}
+
+	return &api.BranchProtectionTemplate{
+		Name:                          t.Name,
+		BranchPattern:                 t.BranchPattern,
+		ApplyToNewRepos:               t.ApplyToNewRepos,
+		EnablePush:                    t.CanPush,
+		EnablePushWhitelist:           t.EnableWhitelist,
+		PushWhitelistUsernames:        pushWhitelistUsernames,
+		PushWhitelistTeams:            pushWhitelistTeams,
+		PushWhitelistDeployKeys:       t.WhitelistDeployKeys,
+		EnableMergeWhitelist:          t.EnableMergeWhitelist,
+		MergeWhitelistUsernames:       mergeWhitelistUsernames,
+		MergeWhitelistTeams:           mergeWhitelistTeams,
+		EnableStatusCheck:             t.EnableStatusCheck,
+		StatusCheckContexts:           t.StatusCheckContexts,
+		StatusCheckMaxAge:             t.StatusCheckMaxAge,
+		RequiredApprovals:             t.RequiredApprovals,
+		EnableApprovalsWhitelist:      t.EnableApprovalsWhitelist,
+		ApprovalsWhitelistUsernames:   approvalsWhitelistUsernames,
+		ApprovalsWhitelistTeams:       approvalsWhitelistTeams,
+		BlockOnRejectedReviews:        t.BlockOnRejectedReviews,
+		BlockOnOfficialReviewRequests: t.BlockOnOfficialReviewRequests,
+		BlockOnOutdatedBranch:         t.BlockOnOutdatedBranch,
+		DismissStaleApprovals:         t.DismissStaleApprovals,
+		RequireSignedCommits:          t.RequireSignedCommits,
+		ProtectedFilePatterns:         t.ProtectedFilePatterns,
+		UnprotectedFilePatterns:       t.UnprotectedFilePatterns,
+		Created:                       t.CreatedUnix.AsTime(),
+		Updated:                       t.UpdatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToChatAddress converts a chat address to API format
+func ToChatAddress(chatAddress *models.ChatAddress) *api.ChatAddress {
+	return &api.ChatAddress{
+		Protocol: chatAddress.Protocol.String(),
+		Address:  chatAddress.Address,
+		Verified: chatAddress.IsVerified,
+		Created:  chatAddress.CreatedUnix.AsTime(),
+	}
+}
@@ -117,6 +117,7 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 		MergeWhitelistTeams:           mergeWhitelistTeams,
 		EnableStatusCheck:             bp.EnableStatusCheck,
 		StatusCheckContexts:           bp.StatusCheckContexts,
+		StatusCheckMaxAge:             bp.StatusCheckMaxAge,
 		RequiredApprovals:             bp.RequiredApprovals,
 		EnableApprovalsWhitelist:      bp.EnableApprovalsWhitelist,
 		ApprovalsWhitelistUsernames:   approvalsWhitelistUsernames,
@@ -165,6 +166,53 @@ func ToVerification(c *git.Commit) *api.PayloadCommitVerification {
 	return commitVerification
 }
 
+// ToCommitVerificationDetail verifies the signature of a commit and returns the key which verified it
+// and whether the repository trusts it
+func ToCommitVerificationDetail(repo *models.Repository, c *git.Commit) (*api.CommitVerificationDetail, error) {
+	verif := models.ParseCommitWithSignature(c)
+	if err := models.CalculateTrustStatus(verif, repo, nil); err != nil {
+		return nil, err
+	}
+
+	detail := &api.CommitVerificationDetail{
+		SHA:          c.ID.String(),
+		Verified:     verif.Verified,
+		Warning:      verif.Warning,
+		Reason:       verif.Reason,
+		SigningEmail: verif.SigningEmail,
+		Signer:       toVerificationUser(verif.SigningUser),
+		Committer:    toVerificationUser(verif.CommittingUser),
+		TrustModel:   repo.GetTrustModel().String(),
+		TrustStatus:  verif.TrustStatus,
+	}
+	if c.Signature != nil {
+		detail.Signature = c.Signature.Signature
+		detail.Payload = c.Signature.Payload
+	}
+	if verif.SigningKey != nil {
+		detail.KeyID = verif.SigningKey.KeyID
+		if verif.Verified {
+			detail.Fingerprint = verif.SigningKey.Fingerprint()
+			detail.Key = ToGPGKey(verif.SigningKey)
+		}
+	}
+	return detail, nil
+}
+
+func toVerificationUser(u *models.User) *api.PayloadUser {
+	if u == nil {
+		return nil
+	}
+	user := &api.PayloadUser{
+		Name:  u.DisplayName(),
+		Email: u.Email,
+	}
+	if u.ID != 0 {
+		user.UserName = u.Name
+	}
+	return user
+}
+
 // ToPublicKey convert models.PublicKey to api.PublicKey
 func ToPublicKey(apiLink string, key *models.PublicKey) *api.PublicKey {
 	return &api.PublicKey{
@@ -298,6 +346,7 @@ func ToTeam(team *models.Team) *api.Team {
 		ID:                      team.ID,
 		Name:                    team.Name,
 		Description:             team.Description,
+		ParentTeamID:            team.ParentTeamID,
 		IncludesAllRepositories: team.IncludesAllRepositories,
 		CanCreateOrgRepo:        team.CanCreateOrgRepo,
 		Permission:              team.Authorize.String(),
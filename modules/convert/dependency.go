@@ -0,0 +1,128 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/google/uuid"
+)
+
+// ToDependency converts a dependency of a repository to API format
+func ToDependency(d *models.RepoDependency) *api.Dependency {
+	return &api.Dependency{
+		Manifest:   d.Manifest,
+		Ecosystem:  d.Ecosystem,
+		Name:       d.Name,
+		Version:    d.Version,
+		Scope:      d.Scope,
+		PackageURL: packageURL(d),
+	}
+}
+
+// ToCycloneDXBOM converts the dependencies of a repository found at the commit to a CycloneDX BOM,
+// dependencies declared by several manifests are listed once
+func ToCycloneDXBOM(repo *models.Repository, commitID string, deps []*models.RepoDependency) *api.CycloneDXBOM {
+	bom := &api.CycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: "urn:uuid:" + uuid.New().String(),
+		Version:      1,
+		Metadata: &api.CycloneDXMetadata{
+			Timestamp: time.Now().UTC(),
+			Tools: []*api.CycloneDXTool{{
+				Vendor:  "Gitea",
+				Name:    "Gitea",
+				Version: setting.AppVer,
+			}},
+			Component: &api.CycloneDXComponent{
+				Type:    "application",
+				Name:    repo.FullName(),
+				Version: commitID,
+			},
+		},
+		Components: make([]*api.CycloneDXComponent, 0, len(deps)),
+	}
+
+	components := make(map[string]*api.CycloneDXComponent, len(deps))
+	for _, d := range deps {
+		purl := packageURL(d)
+		scope := "required"
+		if d.Scope == models.DependencyScopeDevelopment {
+			scope = "excluded"
+		}
+
+		key := purl + " " + d.Version
+		if c, ok := components[key]; ok {
+			if scope == "required" {
+				c.Scope = scope
+			}
+			continue
+		}
+
+		c := &api.CycloneDXComponent{
+			Type:       "library",
+			Name:       d.Name,
+			Version:    d.Version,
+			PackageURL: purl,
+			Scope:      scope,
+		}
+		if isExactVersion(d.Version) {
+			c.BOMRef = purl
+		}
+		components[key] = c
+		bom.Components = append(bom.Components, c)
+	}
+	return bom
+}
+
+// packageURL returns the package url of the dependency, see https://github.com/package-url/purl-spec
+func packageURL(d *models.RepoDependency) string {
+	var tp, name string
+	switch d.Ecosystem {
+	case models.DependencyEcosystemGo:
+		tp, name = "golang", d.Name
+	case models.DependencyEcosystemNpm:
+		tp, name = "npm", d.Name
+	case models.DependencyEcosystemPyPI:
+		tp, name = "pypi", strings.ReplaceAll(strings.ToLower(d.Name), "_", "-")
+	case models.DependencyEcosystemComposer:
+		tp, name = "composer", strings.ToLower(d.Name)
+	default:
+		tp, name = "generic", d.Name
+	}
+
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+	}
+	purl := "pkg:" + tp + "/" + strings.Join(segments, "/")
+	if isExactVersion(d.Version) {
+		purl += "@" + url.PathEscape(d.Version)
+	}
+	return purl
+}
+
+// isExactVersion returns whether the version of a manifest is a single version and not a requirement
+func isExactVersion(version string) bool {
+	if version == "" || strings.ContainsAny(version, "^~<>=*|, :/") {
+		return false
+	}
+	if c := version[0]; c != 'v' && (c < '0' || c > '9') {
+		return false
+	}
+	for _, part := range strings.Split(version, ".") {
+		if part == "x" || part == "X" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageURL(t *testing.T) {
+	kases := map[string]*models.RepoDependency{
+		"pkg:golang/xorm.io/xorm@v1.2.5":        {Ecosystem: models.DependencyEcosystemGo, Name: "xorm.io/xorm", Version: "v1.2.5"},
+		"pkg:npm/%40claviska/jquery-minicolors": {Ecosystem: models.DependencyEcosystemNpm, Name: "@claviska/jquery-minicolors", Version: "^2.3.5"},
+		"pkg:npm/vue@2.6.14":                    {Ecosystem: models.DependencyEcosystemNpm, Name: "vue", Version: "2.6.14"},
+		"pkg:npm/vue":                           {Ecosystem: models.DependencyEcosystemNpm, Name: "vue", Version: "2.x"},
+		"pkg:pypi/django-rest-framework@3.12.4": {Ecosystem: models.DependencyEcosystemPyPI, Name: "Django_Rest_Framework", Version: "3.12.4"},
+		"pkg:composer/monolog/monolog":          {Ecosystem: models.DependencyEcosystemComposer, Name: "monolog/monolog", Version: ">=2"},
+	}
+	for expected, dep := range kases {
+		assert.Equal(t, expected, packageURL(dep))
+	}
+}
+
+func TestToCycloneDXBOM(t *testing.T) {
+	repo := &models.Repository{Name: "repo1", OwnerName: "user2"}
+	bom := ToCycloneDXBOM(repo, "65f1bf27bc3bf70f64657658635e66094edbcb4d", []*models.RepoDependency{
+		{Manifest: "a/package.json", Ecosystem: models.DependencyEcosystemNpm, Name: "vue", Version: "2.6.14", Scope: models.DependencyScopeDevelopment},
+		{Manifest: "b/package.json", Ecosystem: models.DependencyEcosystemNpm, Name: "vue", Version: "2.6.14", Scope: models.DependencyScopeRuntime},
+		{Manifest: "go.mod", Ecosystem: models.DependencyEcosystemGo, Name: "xorm.io/xorm", Version: "v1.2.5", Scope: models.DependencyScopeIndirect},
+	})
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "user2/repo1", bom.Metadata.Component.Name)
+	if assert.Len(t, bom.Components, 2) {
+		assert.Equal(t, "pkg:npm/vue@2.6.14", bom.Components[0].BOMRef)
+		assert.Equal(t, "required", bom.Components[0].Scope)
+		assert.Equal(t, "pkg:golang/xorm.io/xorm@v1.2.5", bom.Components[1].PackageURL)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/gitdiff"
+)
+
+var diffFileStatus = map[gitdiff.DiffFileType]string{
+	gitdiff.DiffFileAdd:    "added",
+	gitdiff.DiffFileChange: "modified",
+	gitdiff.DiffFileDel:    "deleted",
+	gitdiff.DiffFileRename: "renamed",
+	gitdiff.DiffFileCopy:   "copied",
+}
+
+var diffLineType = map[gitdiff.DiffLineType]string{
+	gitdiff.DiffLinePlain: "context",
+	gitdiff.DiffLineAdd:   "add",
+	gitdiff.DiffLineDel:   "delete",
+}
+
+// ToRenderedDiff converts a gitdiff.Diff to api.RenderedDiff.
+// If wordDiff is true, the changed words of modified lines are marked.
+func ToRenderedDiff(diff *gitdiff.Diff, baseSHA, headSHA string, wordDiff bool) *api.RenderedDiff {
+	result := &api.RenderedDiff{
+		BaseCommitSHA:  baseSHA,
+		HeadCommitSHA:  headSHA,
+		TotalAdditions: diff.TotalAddition,
+		TotalDeletions: diff.TotalDeletion,
+		IsIncomplete:   diff.IsIncomplete,
+		Files:          make([]*api.RenderedDiffFile, 0, len(diff.Files)),
+	}
+	for _, file := range diff.Files {
+		result.Files = append(result.Files, toRenderedDiffFile(file, wordDiff))
+	}
+	return result
+}
+
+func toRenderedDiffFile(file *gitdiff.DiffFile, wordDiff bool) *api.RenderedDiffFile {
+	apiFile := &api.RenderedDiffFile{
+		Name:         file.Name,
+		OldName:      file.OldName,
+		Status:       diffFileStatus[file.Type],
+		Additions:    file.Addition,
+		Deletions:    file.Deletion,
+		IsBinary:     file.IsBin,
+		IsLFS:        file.IsLFSFile,
+		IsSubmodule:  file.IsSubmodule,
+		IsGenerated:  file.IsGenerated,
+		IsVendored:   file.IsVendored,
+		IsIncomplete: file.IsIncomplete,
+		DiffDriver:   file.DiffDriver,
+		Hunks:        make([]*api.RenderedDiffHunk, 0, len(file.Sections)),
+	}
+	if apiFile.OldName == "" {
+		apiFile.OldName = file.Name
+	}
+
+	for _, section := range file.Sections {
+		hunk := &api.RenderedDiffHunk{
+			Lines: make([]*api.RenderedDiffLine, 0, len(section.Lines)),
+		}
+		for _, line := range section.Lines {
+			if line.Type == gitdiff.DiffLineSection {
+				hunk.Header = line.Content
+				continue
+			}
+
+			apiLine := &api.RenderedDiffLine{
+				Type:    diffLineType[line.Type],
+				OldLine: line.LeftIdx,
+				NewLine: line.RightIdx,
+				Content: line.Content,
+			}
+			if len(line.Content) > 0 && strings.IndexByte(" +-", line.Content[0]) > -1 {
+				apiLine.Content = line.Content[1:]
+			}
+			if wordDiff {
+				apiLine.HTML = string(section.GetComputedInlineDiffFor(line))
+			} else {
+				apiLine.HTML = string(section.GetComputedHighlightFor(line))
+			}
+			hunk.Lines = append(hunk.Lines, apiLine)
+		}
+		apiFile.Hunks = append(apiFile.Hunks, hunk)
+	}
+	return apiFile
+}
+
+// ToRenderedDiffImage converts the versions of an image changed in a file of a diff to api.RenderedDiffImage
+func ToRenderedDiffImage(repo *models.Repository, file *gitdiff.DiffFile, image *gitdiff.ImageDiff, baseSHA, headSHA string) *api.RenderedDiffImage {
+	oldName := file.OldName
+	if oldName == "" {
+		oldName = file.Name
+	}
+	result := &api.RenderedDiffImage{
+		Before: toRenderedDiffImageBlob(repo, image.Before, baseSHA, oldName),
+		After:  toRenderedDiffImageBlob(repo, image.After, headSHA, file.Name),
+		Modes:  []string{"side-by-side"},
+	}
+	if result.Before != nil && result.After != nil {
+		result.Modes = append(result.Modes, "swipe", "onion-skin")
+	}
+	return result
+}
+
+func toRenderedDiffImageBlob(repo *models.Repository, blob *gitdiff.ImageDiffBlob, commitID, treePath string) *api.RenderedDiffImageBlob {
+	if blob == nil {
+		return nil
+	}
+	return &api.RenderedDiffImageBlob{
+		SHA:      blob.SHA,
+		Size:     blob.Size,
+		MimeType: blob.MimeType,
+		Width:    blob.Width,
+		Height:   blob.Height,
+		RawURL:   repo.HTMLURL() + "/raw/commit/" + commitID + "/" + util.PathEscapeSegments(treePath),
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/gitdiff"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+func TestToRenderedDiff(t *testing.T) {
+	setting.Cfg = ini.Empty()
+	patch := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var name = "old"
++var name = "new"
+ // end
+`
+	diff, err := gitdiff.ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(patch))
+	assert.NoError(t, err)
+
+	rendered := ToRenderedDiff(diff, "base", "head", false)
+	assert.Equal(t, "base", rendered.BaseCommitSHA)
+	assert.Equal(t, 1, rendered.TotalAdditions)
+	assert.Equal(t, 1, rendered.TotalDeletions)
+	assert.Len(t, rendered.Files, 1)
+
+	file := rendered.Files[0]
+	assert.Equal(t, "main.go", file.Name)
+	assert.Equal(t, "main.go", file.OldName)
+	assert.Equal(t, "modified", file.Status)
+	assert.Len(t, file.Hunks, 1)
+
+	hunk := file.Hunks[0]
+	assert.Equal(t, "@@ -1,3 +1,3 @@", hunk.Header)
+	assert.Len(t, hunk.Lines, 4)
+	assert.Equal(t, "delete", hunk.Lines[1].Type)
+	assert.Equal(t, 2, hunk.Lines[1].OldLine)
+	assert.Equal(t, 0, hunk.Lines[1].NewLine)
+	assert.Equal(t, `var name = "old"`, hunk.Lines[1].Content)
+	assert.Equal(t, "add", hunk.Lines[2].Type)
+	assert.Contains(t, hunk.Lines[2].HTML, `class="`)
+	assert.NotContains(t, hunk.Lines[2].HTML, "added-code")
+
+	rendered = ToRenderedDiff(diff, "base", "head", true)
+	assert.Contains(t, rendered.Files[0].Hunks[0].Lines[2].HTML, "added-code")
+	assert.Contains(t, rendered.Files[0].Hunks[0].Lines[1].HTML, "removed-code")
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/markup"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRemoteActor converts a remote actor to API format
+func ToRemoteActor(actor *models.RemoteActor) *api.RemoteActor {
+	if actor == nil {
+		return nil
+	}
+	return &api.RemoteActor{
+		URI:    actor.URI,
+		Handle: actor.Handle(),
+		Type:   actor.Type,
+	}
+}
+
+// ToFederationFollowing converts a following of a remote actor to API format
+func ToFederationFollowing(following *models.FederationFollowing) *api.FederationFollowing {
+	return &api.FederationFollowing{
+		ID:         following.ID,
+		Actor:      ToRemoteActor(following.RemoteActor),
+		IsAccepted: following.IsAccepted,
+		Created:    following.CreatedUnix.AsTime(),
+	}
+}
+
+// ToFederationActivity converts a received activity to API format
+func ToFederationActivity(activity *models.FederationActivity) *api.FederationActivity {
+	apiActivity := &api.FederationActivity{
+		ID:      activity.ID,
+		URI:     activity.URI,
+		Type:    activity.Type,
+		Actor:   ToRemoteActor(activity.RemoteActor),
+		Created: activity.CreatedUnix.AsTime(),
+	}
+
+	var content activitypub.Activity
+	if err := json.Unmarshal([]byte(activity.Content), &content); err != nil {
+		return apiActivity
+	}
+	var note activitypub.Note
+	if err := json.Unmarshal(content.Object, &note); err == nil && note.Type == activitypub.TypeNote {
+		// the content is written by the remote instance
+		apiActivity.Content = markup.Sanitize(note.Content)
+		apiActivity.URL = note.URL
+		if apiActivity.URL == "" {
+			apiActivity.URL = note.ID
+		}
+	} else {
+		apiActivity.URL = string(content.ObjectIRI())
+	}
+	return apiActivity
+}
+
+// ToRepoFederation converts the federation settings of a repository to API format,
+// settings is nil if the repository did not opt in
+func ToRepoFederation(settings *models.RepoFederation, numStars int64) *api.RepoFederation {
+	if settings == nil {
+		return &api.RepoFederation{}
+	}
+	return &api.RepoFederation{
+		Enabled:       true,
+		AllowIssues:   settings.AllowIssues,
+		AllowComments: settings.AllowComments,
+		NumStars:      numStars,
+	}
+}
+
+// ToRemoteStar converts the star of a remote actor to API format
+func ToRemoteStar(star *models.RemoteStar) *api.RemoteStar {
+	return &api.RemoteStar{
+		Actor:   ToRemoteActor(star.RemoteActor),
+		Created: star.CreatedUnix.AsTime(),
+	}
+}
+
+// ToFederationBlock converts a block of a repository to API format
+func ToFederationBlock(block *models.FederationBlock) *api.FederationBlock {
+	return &api.FederationBlock{
+		ID:      block.ID,
+		Actor:   ToRemoteActor(block.RemoteActor),
+		Domain:  block.Domain,
+		Created: block.CreatedUnix.AsTime(),
+	}
+}
@@ -69,8 +69,19 @@ func ToPayloadCommit(repo *models.Repository, c *git.Commit) *api.PayloadCommit
 	}
 }
 
+// ToCommitOptions selects the parts of a commit ToCommit includes which need more than the commit object,
+// they are costly when many commits are converted
+type ToCommitOptions struct {
+	// Stat counts the lines changed compared to the first parent
+	Stat bool
+	// Verification verifies the signature of the commit
+	Verification bool
+	// Files lists the files affected by the commit
+	Files bool
+}
+
 // ToCommit convert a git.Commit to api.Commit
-func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]*models.User) (*api.Commit, error) {
+func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]*models.User, opts ToCommitOptions) (*api.Commit, error) {
 
 	var apiAuthor, apiCommitter *api.User
 
@@ -132,17 +143,46 @@ func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]
 	}
 
 	// Retrieve files affected by the commit
-	fileStatus, err := git.GetCommitFileStatus(repo.RepoPath(), commit.ID.String())
-	if err != nil {
-		return nil, err
+	var affectedFileList []*api.CommitAffectedFiles
+	if opts.Files {
+		fileStatus, err := git.GetCommitFileStatus(repo.RepoPath(), commit.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		affectedFileList = make([]*api.CommitAffectedFiles, 0, len(fileStatus.Added)+len(fileStatus.Removed)+len(fileStatus.Modified))
+		for _, files := range [][]string{fileStatus.Added, fileStatus.Removed, fileStatus.Modified} {
+			for _, filename := range files {
+				affectedFileList = append(affectedFileList, &api.CommitAffectedFiles{
+					Filename: filename,
+				})
+			}
+		}
 	}
-	affectedFileList := make([]*api.CommitAffectedFiles, 0, len(fileStatus.Added)+len(fileStatus.Removed)+len(fileStatus.Modified))
-	for _, files := range [][]string{fileStatus.Added, fileStatus.Removed, fileStatus.Modified} {
-		for _, filename := range files {
-			affectedFileList = append(affectedFileList, &api.CommitAffectedFiles{
-				Filename: filename,
-			})
+
+	var stats *api.CommitStats
+	if opts.Stat {
+		base := git.EmptyTreeSHA
+		if commit.ParentCount() > 0 {
+			parentID, err := commit.ParentID(0)
+			if err != nil {
+				return nil, err
+			}
+			base = parentID.String()
+		}
+		_, additions, deletions, err := git.GetDiffShortStat(repo.RepoPath(), base, commit.ID.String())
+		if err != nil {
+			return nil, err
 		}
+		stats = &api.CommitStats{
+			Total:     additions + deletions,
+			Additions: additions,
+			Deletions: deletions,
+		}
+	}
+
+	var verification *api.PayloadCommitVerification
+	if opts.Verification {
+		verification = ToVerification(commit)
 	}
 
 	return &api.Commit{
@@ -172,10 +212,12 @@ func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]
 				URL: repo.APIURL() + "/git/trees/" + commit.ID.String(),
 				SHA: commit.ID.String(),
 			},
+			Verification: verification,
 		},
 		Author:    apiAuthor,
 		Committer: apiCommitter,
 		Parents:   apiParents,
 		Files:     affectedFileList,
+		Stats:     stats,
 	}, nil
 }
@@ -60,6 +60,13 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 		apiIssue.Closed = issue.ClosedUnix.AsTimePtr()
 	}
 
+	if issue.IsLocked {
+		apiIssue.LockReason = issue.LockReason.String()
+		if issue.LockedUntilUnix != 0 {
+			apiIssue.LockedUntil = issue.LockedUntilUnix.AsTimePtr()
+		}
+	}
+
 	if err := issue.LoadMilestone(); err != nil {
 		return &api.Issue{}
 	}
@@ -67,6 +74,33 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 		apiIssue.Milestone = ToAPIMilestone(issue.Milestone)
 	}
 
+	if err := issue.LoadWorkflowState(); err != nil {
+		return &api.Issue{}
+	}
+	if issue.WorkflowState != nil {
+		apiIssue.WorkflowState = ToAPIIssueWorkflowState(issue.WorkflowState)
+	}
+
+	if err := issue.LoadIteration(); err != nil {
+		return &api.Issue{}
+	}
+	if issue.Iteration != nil {
+		apiIssue.Iteration = ToAPIIteration(issue.Iteration)
+	}
+
+	customFields, err := models.GetIssueCustomFieldEntries(issue)
+	if err != nil {
+		return &api.Issue{}
+	}
+	apiIssue.CustomFields = ToAPIIssueCustomFieldValues(customFields)
+
+	if err := issue.LoadPriorityLevel(); err != nil {
+		return &api.Issue{}
+	}
+	if issue.PriorityLevel != nil {
+		apiIssue.Priority = ToAPIIssuePriority(issue.PriorityLevel)
+	}
+
 	if err := issue.LoadAssignees(); err != nil {
 		return &api.Issue{}
 	}
@@ -229,3 +263,181 @@ func ToAPIMilestone(m *models.Milestone) *api.Milestone {
 	}
 	return apiMilestone
 }
+
+// ToMilestoneBurndown converts the burndown days of a milestone to API format
+func ToMilestoneBurndown(days []*models.MilestoneBurndownDay) []*api.MilestoneBurndownDay {
+	result := make([]*api.MilestoneBurndownDay, 0, len(days))
+	for _, day := range days {
+		result = append(result, &api.MilestoneBurndownDay{
+			Date:         day.Date.Format("2006-01-02"),
+			OpenIssues:   day.OpenIssues,
+			ClosedIssues: day.ClosedIssues,
+			TrackedTime:  day.TrackedTime,
+		})
+	}
+	return result
+}
+
+// ToAPIIssueWorkflowState converts IssueWorkflowState into API Format
+func ToAPIIssueWorkflowState(state *models.IssueWorkflowState) *api.IssueWorkflowState {
+	return &api.IssueWorkflowState{
+		ID:          state.ID,
+		Name:        state.Name,
+		Description: state.Description,
+		Color:       strings.TrimLeft(state.Color, "#"),
+		Sort:        state.Sort,
+	}
+}
+
+// ToAPIIterationCadence converts IterationCadence into API Format
+func ToAPIIterationCadence(cadence *models.IterationCadence) *api.IterationCadence {
+	return &api.IterationCadence{
+		ID:              cadence.ID,
+		Title:           cadence.Title,
+		DurationDays:    cadence.DurationDays,
+		IterationsAhead: cadence.IterationsAhead,
+		Start:           cadence.StartUnix.AsTime(),
+	}
+}
+
+// ToAPIIteration converts Iteration into API Format
+func ToAPIIteration(it *models.Iteration) *api.Iteration {
+	return &api.Iteration{
+		ID:        it.ID,
+		CadenceID: it.CadenceID,
+		Sequence:  it.Sequence,
+		Title:     it.Title,
+		State:     it.State(),
+		Start:     it.StartUnix.AsTime(),
+		End:       it.EndUnix.AsTime(),
+	}
+}
+
+// ToAPIIssueCustomField converts IssueCustomField into API Format
+func ToAPIIssueCustomField(f *models.IssueCustomField) *api.IssueCustomField {
+	options := f.Options
+	if options == nil {
+		options = []string{}
+	}
+	return &api.IssueCustomField{
+		ID:          f.ID,
+		Name:        f.Name,
+		Description: f.Description,
+		Type:        f.Type.String(),
+		Options:     options,
+	}
+}
+
+// ToAPIIssueCustomFieldValues converts the custom fields of an issue into API Format, the fields without value are omitted
+func ToAPIIssueCustomFieldValues(entries []*models.IssueCustomFieldEntry) []*api.IssueCustomFieldValue {
+	values := make([]*api.IssueCustomFieldValue, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Value == "" {
+			continue
+		}
+		values = append(values, &api.IssueCustomFieldValue{
+			FieldID: entry.Field.ID,
+			Name:    entry.Field.Name,
+			Type:    entry.Field.Type.String(),
+			Value:   entry.Value,
+		})
+	}
+	return values
+}
+
+// ToAPIIssuePriority converts IssuePriority into API Format
+func ToAPIIssuePriority(priority *models.IssuePriority) *api.IssuePriority {
+	return &api.IssuePriority{
+		ID:          priority.ID,
+		Level:       priority.Level,
+		Name:        priority.Name,
+		Description: priority.Description,
+		Color:       strings.TrimLeft(priority.Color, "#"),
+	}
+}
+
+// ToAPIIssueRelation converts IssueRelationInfo into API Format
+func ToAPIIssueRelation(relation *models.IssueRelationInfo) *api.IssueRelation {
+	return &api.IssueRelation{
+		ID:         relation.ID,
+		Type:       relation.Name(),
+		Repository: relation.Issue.Repo.FullName(),
+		Index:      relation.Issue.Index,
+		Title:      relation.Issue.Title,
+		State:      relation.Issue.State(),
+		IsPull:     relation.Issue.IsPull,
+		HTMLURL:    relation.Issue.HTMLURL(),
+		Created:    relation.CreatedUnix.AsTime(),
+	}
+}
+
+// ToAPIIssueSLAReport converts IssueSLAReport into API Format
+func ToAPIIssueSLAReport(report *models.IssueSLAReport) *api.IssueSLAReport {
+	apiReport := &api.IssueSLAReport{
+		IssueCount:           report.IssueCount,
+		RespondedCount:       report.RespondedCount,
+		AverageFirstResponse: report.AverageFirstResponse,
+		MedianFirstResponse:  report.MedianFirstResponse,
+	}
+	if report.Priority != nil {
+		apiReport.Priority = ToAPIIssuePriority(report.Priority)
+	}
+	return apiReport
+}
+
+// ToAPIIssueSLAPolicy converts IssueSLAPolicy into API Format
+func ToAPIIssueSLAPolicy(policy *models.IssueSLAPolicy) *api.IssueSLAPolicy {
+	return &api.IssueSLAPolicy{
+		ID:                 policy.ID,
+		Name:               policy.Name,
+		LabelID:            policy.LabelID,
+		FirstResponseHours: policy.FirstResponseHours,
+		Created:            policy.CreatedUnix.AsTime(),
+		Updated:            policy.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToAPIIssueSLACompliance converts IssueSLACompliance into API Format
+func ToAPIIssueSLACompliance(compliance *models.IssueSLACompliance) *api.IssueSLACompliance {
+	apiCompliance := &api.IssueSLACompliance{
+		Policy:            ToAPIIssueSLAPolicy(compliance.Policy),
+		IssueCount:        compliance.IssueCount,
+		MetCount:          compliance.MetCount,
+		BreachedCount:     compliance.BreachedCount,
+		PendingCount:      compliance.PendingCount,
+		CompliancePercent: 100,
+	}
+	if decided := compliance.MetCount + compliance.BreachedCount; decided > 0 {
+		apiCompliance.CompliancePercent = float64(compliance.MetCount) * 100 / float64(decided)
+	}
+	return apiCompliance
+}
+
+// ToAPIAssigneeWorkload converts AssigneeWorkload into API Format
+func ToAPIAssigneeWorkload(workload *models.AssigneeWorkload, doer *models.User) *api.AssigneeWorkload {
+	apiWorkload := &api.AssigneeWorkload{
+		Assignee:         ToUser(workload.Assignee, doer),
+		OpenIssues:       workload.OpenIssues,
+		OpenPullRequests: workload.OpenPulls,
+		Milestones:       make([]*api.MilestoneWorkload, len(workload.Milestones)),
+	}
+	for i, milestoneWorkload := range workload.Milestones {
+		apiMilestoneWorkload := &api.MilestoneWorkload{
+			OpenIssues:       milestoneWorkload.OpenIssues,
+			OpenPullRequests: milestoneWorkload.OpenPulls,
+		}
+		if m := milestoneWorkload.Milestone; m != nil {
+			apiMilestoneWorkload.Milestone = ToAPIMilestone(m)
+			if m.Repo != nil {
+				apiMilestoneWorkload.Repo = &api.RepositoryMeta{
+					ID:       m.Repo.ID,
+					Name:     m.Repo.Name,
+					Owner:    m.Repo.OwnerName,
+					FullName: m.Repo.FullName(),
+				}
+			}
+		}
+		apiWorkload.Milestones[i] = apiMilestoneWorkload
+	}
+	return apiWorkload
+}
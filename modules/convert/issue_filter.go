@@ -0,0 +1,21 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToIssueFilter converts a models.IssueFilter to api.IssueFilter
+func ToIssueFilter(f *models.IssueFilter) *api.IssueFilter {
+	return &api.IssueFilter{
+		ID:      f.ID,
+		Name:    f.Name,
+		Query:   f.Query,
+		Created: f.CreatedUnix.AsTime(),
+		Updated: f.UpdatedUnix.AsTime(),
+	}
+}
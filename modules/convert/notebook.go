@@ -0,0 +1,48 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/modules/markup/jupyter"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToNotebookCells converts the cells of a Jupyter notebook to API format
+func ToNotebookCells(nb *jupyter.Notebook) []*api.NotebookCell {
+	cells := make([]*api.NotebookCell, 0, len(nb.Cells))
+	for _, cell := range nb.Cells {
+		outputs := make([]*api.NotebookOutput, 0, len(cell.Outputs))
+		for _, o := range cell.Outputs {
+			outputs = append(outputs, toNotebookOutput(o))
+		}
+		cells = append(cells, &api.NotebookCell{
+			Type:           cell.CellType,
+			Source:         string(cell.Source),
+			ExecutionCount: cell.ExecutionCount,
+			Outputs:        outputs,
+		})
+	}
+	return cells
+}
+
+func toNotebookOutput(o *jupyter.Output) *api.NotebookOutput {
+	output := &api.NotebookOutput{
+		Type: o.OutputType,
+		Data: make(map[string]string, len(o.Data)),
+	}
+	switch o.OutputType {
+	case "stream":
+		output.Text = string(o.Text)
+	case "error":
+		output.Text = o.ErrorText()
+	}
+	for mimeType := range o.Data {
+		// representations which are no strings, like JSON data, are left out
+		if data, ok := o.DataString(mimeType); ok {
+			output.Data[mimeType] = data
+		}
+	}
+	return output
+}
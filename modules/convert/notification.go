@@ -13,12 +13,16 @@ import (
 func ToNotificationThread(n *models.Notification) *api.NotificationThread {
 	result := &api.NotificationThread{
 		ID:        n.ID,
-		Unread:    !(n.Status == models.NotificationStatusRead || n.Status == models.NotificationStatusPinned),
+		Unread:    n.Status == models.NotificationStatusUnread,
 		Pinned:    n.Status == models.NotificationStatusPinned,
 		UpdatedAt: n.UpdatedUnix.AsTime(),
 		URL:       n.APIURL(),
 	}
 
+	if n.Status == models.NotificationStatusSnoozed {
+		result.SnoozedUntil = n.SnoozedUntilUnix.AsTimePtr()
+	}
+
 	//since user only get notifications when he has access to use minimal access mode
 	if n.Repository != nil {
 		result.Repository = ToRepo(n.Repository, models.AccessModeRead)
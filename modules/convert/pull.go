@@ -64,6 +64,9 @@ func ToAPIPullRequest(pr *models.PullRequest) *api.PullRequest {
 		Created:   pr.Issue.CreatedUnix.AsTimePtr(),
 		Updated:   pr.Issue.UpdatedUnix.AsTimePtr(),
 
+		LockReason:  apiIssue.LockReason,
+		LockedUntil: apiIssue.LockedUntil,
+
 		Base: &api.PRBranchInfo{
 			Name:       pr.BaseBranch,
 			Ref:        pr.BaseBranch,
@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToPushDevice converts a push device to API format
+func ToPushDevice(device *models.PushDevice) *api.PushDevice {
+	return &api.PushDevice{
+		ID:       device.ID,
+		Type:     device.Type.String(),
+		Name:     device.Name,
+		Platform: device.Platform,
+		Created:  device.CreatedUnix.AsTime(),
+	}
+}
+
+// ToNotificationChannel converts the events a user receives through a channel to API format
+func ToNotificationChannel(channel models.NotificationChannel, events models.SubscriptionEvents) *api.NotificationChannel {
+	return &api.NotificationChannel{
+		Channel: channel.String(),
+		Events:  events.Names(),
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToPushRule converts a models.RepoPushRule to api.PushRule
+func ToPushRule(rule *models.RepoPushRule) *api.PushRule {
+	return &api.PushRule{
+		MaxFileSize:           rule.MaxFileSize,
+		ForbiddenFilePatterns: rule.ForbiddenFilePatterns,
+		AuthorEmailDomains:    rule.GetAuthorEmailDomains(),
+		RejectForcePush:       rule.RejectForcePush,
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoRedirect converts a redirect from a path the repository had under the owner with the given name to API format
+func ToRepoRedirect(redirect *models.RepoRedirect, ownerName string, repo *models.Repository) *api.Redirect {
+	return &api.Redirect{
+		OldPath: ownerName + "/" + redirect.LowerName,
+		NewPath: repo.FullName(),
+		Created: redirect.CreatedUnix.AsTime(),
+	}
+}
+
+// ToUserRedirect converts a redirect from a name the user had to API format
+func ToUserRedirect(redirect *models.UserRedirect, u *models.User) *api.Redirect {
+	return &api.Redirect{
+		OldPath: redirect.LowerName,
+		NewPath: u.Name,
+		Created: redirect.CreatedUnix.AsTime(),
+	}
+}
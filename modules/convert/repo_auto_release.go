@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoAutoRelease converts the auto release rule of a repository to API format
+func ToRepoAutoRelease(rule *models.RepoAutoRelease) *api.RepoAutoRelease {
+	result := &api.RepoAutoRelease{
+		FilePath:       rule.FilePath,
+		VersionPattern: rule.VersionPattern,
+		TagTemplate:    rule.TagTemplate,
+		TitleTemplate:  rule.TitleTemplate,
+		NotesTemplate:  rule.NotesTemplate,
+		IsDraft:        rule.IsDraft,
+		IsPrerelease:   rule.IsPrerelease,
+		LastVersion:    rule.LastVersion,
+		LastTagName:    rule.LastTagName,
+		LastCommitID:   rule.LastCommitID,
+		LastError:      rule.LastError,
+	}
+	if rule.LastReleasedUnix > 0 {
+		released := rule.LastReleasedUnix.AsTime()
+		result.LastReleased = &released
+	}
+	return result
+}
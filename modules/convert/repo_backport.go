@@ -0,0 +1,20 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToBackportMapping converts a models.BackportMapping to api.BackportMapping
+func ToBackportMapping(m *models.BackportMapping) *api.BackportMapping {
+	return &api.BackportMapping{
+		ID:           m.ID,
+		Label:        m.Label,
+		TargetBranch: m.TargetBranch,
+		Created:      m.CreatedUnix.AsTime(),
+	}
+}
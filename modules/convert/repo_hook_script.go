@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToHookScript converts a hook script of a repository to API format, its attributes have to be loaded
+func ToHookScript(script *models.RepoHookScript, doer *models.User) *api.HookScript {
+	result := &api.HookScript{
+		ID:       script.ID,
+		Name:     script.Name,
+		HookType: string(script.HookType),
+		Content:  script.Content,
+		Active:   script.IsActive,
+		Sort:     script.Sort,
+		Creator:  ToUser(script.Creator, doer),
+		Approved: script.IsApproved,
+		Approver: ToUser(script.Approver, doer),
+		Created:  script.CreatedUnix.AsTime(),
+		Updated:  script.UpdatedUnix.AsTime(),
+	}
+	if script.Repo != nil {
+		result.Repository = script.Repo.FullName()
+	}
+	if script.ApprovedUnix > 0 {
+		approved := script.ApprovedUnix.AsTime()
+		result.ApprovedAt = &approved
+	}
+	return result
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoInteractionLimit converts the active interaction limit of a repository to API format,
+// a nil limit means that the interactions are not limited
+func ToRepoInteractionLimit(limit *models.RepoInteractionLimit) *api.RepoInteractionLimit {
+	if limit == nil {
+		return &api.RepoInteractionLimit{Limit: models.InteractionLimitNone.String()}
+	}
+	created := limit.CreatedUnix.AsTime()
+	expires := limit.ExpiresUnix.AsTime()
+	return &api.RepoInteractionLimit{
+		Limit:   limit.Type.String(),
+		Created: &created,
+		Expires: &expires,
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoPages converts the pages site of a repository to API format
+func ToRepoPages(repo *models.Repository, pages *models.RepoPages) *api.RepoPages {
+	result := &api.RepoPages{
+		Branch:       pages.Branch,
+		Directory:    pages.Directory,
+		CustomDomain: pages.CustomDomain,
+		URL:          pages.HTMLURL(repo),
+		Status:       pages.Status.String(),
+		CommitID:     pages.CommitID,
+		Error:        pages.Error,
+	}
+	if pages.CustomDomain != "" {
+		result.CustomDomainVerified = pages.CustomDomainVerified
+		result.CustomDomainChallengeRecord = pages.CustomDomainChallengeRecord()
+		result.CustomDomainVerificationCode = pages.CustomDomainVerificationCode
+	}
+	if pages.PublishedUnix > 0 {
+		published := pages.PublishedUnix.AsTime()
+		result.Published = &published
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoSchedule converts a models.RepoSchedule to api.RepoSchedule
+func ToRepoSchedule(s *models.RepoSchedule) *api.RepoSchedule {
+	apiSchedule := &api.RepoSchedule{
+		ID:      s.ID,
+		Name:    s.Name,
+		Cron:    s.Spec,
+		CatchUp: s.CatchUp.String(),
+		Active:  s.IsActive,
+		Created: s.CreatedUnix.AsTime(),
+		Updated: s.UpdatedUnix.AsTime(),
+	}
+	if s.IsActive && s.NextRunUnix != 0 {
+		apiSchedule.NextRun = s.NextRunUnix.AsTimePtr()
+	}
+	if s.LastRunUnix != 0 {
+		apiSchedule.LastRun = s.LastRunUnix.AsTimePtr()
+	}
+	return apiSchedule
+}
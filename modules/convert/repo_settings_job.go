@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoSettingsJob converts a bulk repository settings update to API format, the results must be loaded
+func ToRepoSettingsJob(job *models.RepoSettingsJob, doer *models.User) *api.RepoSettingsJob {
+	result := &api.RepoSettingsJob{
+		ID:       job.ID,
+		Status:   job.Status.String(),
+		Settings: &api.RepoSettingsPatch{},
+		Doer:     ToUser(doer, nil),
+		Results:  make([]*api.RepoSettingsResult, 0, len(job.Results)),
+		Created:  job.CreatedUnix.AsTime(),
+	}
+	if err := json.Unmarshal([]byte(job.Patch), result.Settings); err != nil {
+		log.Error("Unable to decode the settings patch of job %d: %v", job.ID, err)
+	}
+	for _, r := range job.Results {
+		result.Results = append(result.Results, &api.RepoSettingsResult{
+			Repository: r.RepoName,
+			Status:     r.Status.String(),
+			Message:    r.Message,
+		})
+	}
+	if job.FinishedUnix > 0 {
+		finished := job.FinishedUnix.AsTime()
+		result.Finished = &finished
+	}
+	return result
+}
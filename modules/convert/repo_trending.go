@@ -0,0 +1,22 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToTrendingRepository converts a trending repository to API format
+func ToTrendingRepository(trending *models.RepoTrending, mode models.AccessMode) *api.TrendingRepository {
+	return &api.TrendingRepository{
+		Repository:   ToRepo(trending.Repo, mode),
+		Period:       trending.Period.String(),
+		StarsGained:  trending.Stars,
+		PullRequests: trending.PullRequests,
+		Score:        trending.Score,
+		ComputedAt:   trending.ComputedUnix.AsTime(),
+	}
+}
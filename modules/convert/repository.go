@@ -44,6 +44,7 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 			EnableTimeTracker:                config.EnableTimetracker,
 			AllowOnlyContributorsToTrackTime: config.AllowOnlyContributorsToTrackTime,
 			EnableIssueDependencies:          config.EnableDependencies,
+			IssueBranchNameTemplate:          config.BranchNameTemplate,
 		}
 	} else if unit, err := repo.GetUnit(models.UnitTypeExternalTracker); err == nil {
 		config := unit.ExternalTrackerConfig()
@@ -72,6 +73,8 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 	allowRebaseMerge := false
 	allowSquash := false
 	defaultMergeStyle := models.MergeStyleMerge
+	defaultMergeMessageTemplate := ""
+	defaultSquashMessageTemplate := ""
 	if unit, err := repo.GetUnit(models.UnitTypePullRequests); err == nil {
 		config := unit.PullRequestsConfig()
 		hasPullRequests = true
@@ -81,6 +84,8 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		allowRebaseMerge = config.AllowRebaseMerge
 		allowSquash = config.AllowSquash
 		defaultMergeStyle = config.GetDefaultMergeStyle()
+		defaultMergeMessageTemplate = config.DefaultMergeMessageTemplate
+		defaultSquashMessageTemplate = config.DefaultSquashMessageTemplate
 	}
 	hasProjects := false
 	if _, err := repo.GetUnit(models.UnitTypeProjects); err == nil {
@@ -101,49 +106,51 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 	}
 
 	return &api.Repository{
-		ID:                        repo.ID,
-		Owner:                     ToUserWithAccessMode(repo.Owner, mode),
-		Name:                      repo.Name,
-		FullName:                  repo.FullName(),
-		Description:               repo.Description,
-		Private:                   repo.IsPrivate,
-		Template:                  repo.IsTemplate,
-		Empty:                     repo.IsEmpty,
-		Archived:                  repo.IsArchived,
-		Size:                      int(repo.Size / 1024),
-		Fork:                      repo.IsFork,
-		Parent:                    parent,
-		Mirror:                    repo.IsMirror,
-		HTMLURL:                   repo.HTMLURL(),
-		SSHURL:                    cloneLink.SSH,
-		CloneURL:                  cloneLink.HTTPS,
-		OriginalURL:               repo.SanitizedOriginalURL(),
-		Website:                   repo.Website,
-		Stars:                     repo.NumStars,
-		Forks:                     repo.NumForks,
-		Watchers:                  repo.NumWatches,
-		OpenIssues:                repo.NumOpenIssues,
-		OpenPulls:                 repo.NumOpenPulls,
-		Releases:                  int(numReleases),
-		DefaultBranch:             repo.DefaultBranch,
-		Created:                   repo.CreatedUnix.AsTime(),
-		Updated:                   repo.UpdatedUnix.AsTime(),
-		Permissions:               permission,
-		HasIssues:                 hasIssues,
-		ExternalTracker:           externalTracker,
-		InternalTracker:           internalTracker,
-		HasWiki:                   hasWiki,
-		HasProjects:               hasProjects,
-		ExternalWiki:              externalWiki,
-		HasPullRequests:           hasPullRequests,
-		IgnoreWhitespaceConflicts: ignoreWhitespaceConflicts,
-		AllowMerge:                allowMerge,
-		AllowRebase:               allowRebase,
-		AllowRebaseMerge:          allowRebaseMerge,
-		AllowSquash:               allowSquash,
-		DefaultMergeStyle:         string(defaultMergeStyle),
-		AvatarURL:                 repo.AvatarLink(),
-		Internal:                  !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
-		MirrorInterval:            mirrorInterval,
+		ID:                           repo.ID,
+		Owner:                        ToUserWithAccessMode(repo.Owner, mode),
+		Name:                         repo.Name,
+		FullName:                     repo.FullName(),
+		Description:                  repo.Description,
+		Private:                      repo.IsPrivate,
+		Template:                     repo.IsTemplate,
+		Empty:                        repo.IsEmpty,
+		Archived:                     repo.IsArchived,
+		Size:                         int(repo.Size / 1024),
+		Fork:                         repo.IsFork,
+		Parent:                       parent,
+		Mirror:                       repo.IsMirror,
+		HTMLURL:                      repo.HTMLURL(),
+		SSHURL:                       cloneLink.SSH,
+		CloneURL:                     cloneLink.HTTPS,
+		OriginalURL:                  repo.SanitizedOriginalURL(),
+		Website:                      repo.Website,
+		Stars:                        repo.NumStars,
+		Forks:                        repo.NumForks,
+		Watchers:                     repo.NumWatches,
+		OpenIssues:                   repo.NumOpenIssues,
+		OpenPulls:                    repo.NumOpenPulls,
+		Releases:                     int(numReleases),
+		DefaultBranch:                repo.DefaultBranch,
+		Created:                      repo.CreatedUnix.AsTime(),
+		Updated:                      repo.UpdatedUnix.AsTime(),
+		Permissions:                  permission,
+		HasIssues:                    hasIssues,
+		ExternalTracker:              externalTracker,
+		InternalTracker:              internalTracker,
+		HasWiki:                      hasWiki,
+		HasProjects:                  hasProjects,
+		ExternalWiki:                 externalWiki,
+		HasPullRequests:              hasPullRequests,
+		IgnoreWhitespaceConflicts:    ignoreWhitespaceConflicts,
+		AllowMerge:                   allowMerge,
+		AllowRebase:                  allowRebase,
+		AllowRebaseMerge:             allowRebaseMerge,
+		AllowSquash:                  allowSquash,
+		DefaultMergeStyle:            string(defaultMergeStyle),
+		DefaultMergeMessageTemplate:  defaultMergeMessageTemplate,
+		DefaultSquashMessageTemplate: defaultSquashMessageTemplate,
+		AvatarURL:                    repo.AvatarLink(),
+		Internal:                     !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
+		MirrorInterval:               mirrorInterval,
 	}
 }
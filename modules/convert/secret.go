@@ -0,0 +1,19 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToSecret converts a models.Secret to api.Secret without its value
+func ToSecret(s *models.Secret) *api.Secret {
+	return &api.Secret{
+		Name:    s.Name,
+		Created: s.CreatedUnix.AsTime(),
+		Updated: s.UpdatedUnix.AsTime(),
+	}
+}
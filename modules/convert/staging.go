@@ -0,0 +1,34 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToStagingSession converts a models.StagingSession to api.StagingSession, the changes are included if they were loaded
+func ToStagingSession(s *models.StagingSession) *api.StagingSession {
+	result := &api.StagingSession{
+		ID:            s.ID,
+		Branch:        s.Branch,
+		BaseCommitSHA: s.BaseCommitID,
+		Created:       s.CreatedUnix.AsTime(),
+		Updated:       s.UpdatedUnix.AsTime(),
+	}
+	if s.Changes != nil {
+		result.Changes = make([]*api.StagedChange, 0, len(s.Changes))
+		for _, c := range s.Changes {
+			result.Changes = append(result.Changes, &api.StagedChange{
+				Path:      c.TreePath,
+				FromPath:  c.FromTreePath,
+				Operation: string(c.Operation),
+				Content:   c.Content,
+				Updated:   c.UpdatedUnix.AsTime(),
+			})
+		}
+	}
+	return result
+}
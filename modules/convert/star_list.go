@@ -0,0 +1,24 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToStarList converts a star list to API format
+func ToStarList(list *models.StarList) *api.StarList {
+	return &api.StarList{
+		ID:          list.ID,
+		Name:        list.Name,
+		Description: list.Description,
+		Private:     list.IsPrivate,
+		IsDefault:   list.IsDefault,
+		NumRepos:    list.NumRepos,
+		Created:     list.CreatedUnix.AsTime(),
+		Updated:     list.UpdatedUnix.AsTime(),
+	}
+}
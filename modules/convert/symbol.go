@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ToSymbol converts a symbol of a branch to API format
+func ToSymbol(repo *models.Repository, s *models.RepoSymbol) *api.Symbol {
+	return &api.Symbol{
+		Name:      s.Name,
+		Kind:      s.Kind,
+		Language:  s.Language,
+		Path:      s.Path,
+		Line:      s.Line,
+		Scope:     s.Scope,
+		Signature: s.Signature,
+		HTMLURL:   repo.HTMLURL() + "/src/branch/" + util.PathEscapeSegments(s.Ref) + "/" + util.PathEscapeSegments(s.Path) + "#L" + strconv.Itoa(s.Line),
+	}
+}
+
+// ToSymbolReference converts a line matched at the given commit to API format
+func ToSymbolReference(repo *models.Repository, commitID string, r *git.GrepResult, isDefinition bool) *api.SymbolReference {
+	return &api.SymbolReference{
+		Path:         r.Filename,
+		Line:         r.LineNumber,
+		Content:      r.Content,
+		IsDefinition: isDefinition,
+		HTMLURL:      repo.HTMLURL() + "/src/commit/" + commitID + "/" + util.PathEscapeSegments(r.Filename) + "#L" + strconv.Itoa(r.LineNumber),
+	}
+}
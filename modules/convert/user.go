@@ -93,5 +93,8 @@ func User2UserSettings(user *models.User) api.UserSettings {
 		HideEmail:     user.KeepEmailPrivate,
 		HideActivity:  user.KeepActivityPrivate,
 		DiffViewStyle: user.DiffViewStyle,
+
+		AutoWatchOnPush:    user.AutoWatchOnPush,
+		AutoWatchOnMention: user.AutoWatchOnMention,
 	}
 }
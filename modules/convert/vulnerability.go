@@ -0,0 +1,68 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToSecurityAdvisory converts a models.SecurityAdvisory to api.SecurityAdvisory
+func ToSecurityAdvisory(adv *models.SecurityAdvisory) *api.SecurityAdvisory {
+	aliases := adv.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return &api.SecurityAdvisory{
+		ID:        adv.OSVID,
+		Aliases:   aliases,
+		Summary:   adv.Summary,
+		Severity:  adv.Severity,
+		URL:       adv.URL,
+		Published: adv.PublishedUnix.AsTime(),
+		Modified:  adv.ModifiedUnix.AsTime(),
+	}
+}
+
+// ToVulnerabilityAlert converts a models.VulnerabilityAlert to api.VulnerabilityAlert,
+// the attributes of the alert must be loaded
+func ToVulnerabilityAlert(a *models.VulnerabilityAlert, doer *models.User) *api.VulnerabilityAlert {
+	apiAlert := &api.VulnerabilityAlert{
+		ID:           a.ID,
+		State:        a.State.String(),
+		Advisory:     ToSecurityAdvisory(a.Advisory),
+		Manifest:     a.Manifest,
+		Ecosystem:    a.Ecosystem,
+		Package:      a.Package,
+		Version:      a.Version,
+		FixedVersion: a.FixedVersion,
+		Created:      a.CreatedUnix.AsTime(),
+		Updated:      a.UpdatedUnix.AsTime(),
+	}
+	if a.State == models.VulnerabilityAlertStateDismissed {
+		apiAlert.DismissedReason = a.DismissReason
+		apiAlert.DismissedComment = a.DismissComment
+		apiAlert.Dismissed = a.DismissedUnix.AsTimePtr()
+		if a.Dismisser != nil {
+			apiAlert.DismissedBy = ToUser(a.Dismisser, doer)
+		}
+	}
+	if a.State == models.VulnerabilityAlertStateFixed {
+		apiAlert.Fixed = a.FixedUnix.AsTimePtr()
+	}
+	return apiAlert
+}
+
+// ToVulnerabilityAlertSettings converts a models.RepoVulnerabilitySetting to api.VulnerabilityAlertSettings
+func ToVulnerabilityAlertSettings(s *models.RepoVulnerabilitySetting) *api.VulnerabilityAlertSettings {
+	settings := &api.VulnerabilityAlertSettings{
+		Enabled:    s.Enabled,
+		OpenIssues: s.OpenIssues,
+	}
+	if s.Enabled && s.CheckedUnix != 0 {
+		settings.LastChecked = s.CheckedUnix.AsTimePtr()
+	}
+	return settings
+}
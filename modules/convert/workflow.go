@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToWorkflowRun converts a models.WorkflowRun to api.WorkflowRun. Jobs are included if not nil.
+func ToWorkflowRun(run *models.WorkflowRun, jobs []*models.WorkflowJob) *api.WorkflowRun {
+	apiRun := &api.WorkflowRun{
+		ID:                run.ID,
+		WorkflowID:        run.WorkflowID,
+		Name:              run.Name,
+		Event:             run.Event,
+		Ref:               run.Ref,
+		CommitSHA:         run.CommitSHA,
+		IsForkPullRequest: run.IsForkPullRequest,
+		TriggerUser:       ToUser(run.TriggerUser, nil),
+		Status:            run.Status.String(),
+		URL:               fmt.Sprintf("%s/workflows/runs/%d", run.Repo.APIURL(), run.ID),
+		Created:           run.CreatedUnix.AsTime(),
+	}
+	if run.StartedUnix != 0 {
+		apiRun.Started = run.StartedUnix.AsTimePtr()
+	}
+	if run.StoppedUnix != 0 {
+		apiRun.Stopped = run.StoppedUnix.AsTimePtr()
+	}
+	if jobs != nil {
+		apiRun.Jobs = make([]*api.WorkflowJob, 0, len(jobs))
+		for _, job := range jobs {
+			apiRun.Jobs = append(apiRun.Jobs, ToWorkflowJob(job))
+		}
+	}
+	return apiRun
+}
+
+// ToWorkflowJob converts a models.WorkflowJob to api.WorkflowJob
+func ToWorkflowJob(job *models.WorkflowJob) *api.WorkflowJob {
+	apiJob := &api.WorkflowJob{
+		ID:       job.ID,
+		JobID:    job.JobID,
+		Name:     job.Name,
+		RunsOn:   job.RunsOn,
+		Needs:    job.Needs,
+		Status:   job.Status.String(),
+		RunnerID: job.RunnerID,
+	}
+	if job.StartedUnix != 0 {
+		apiJob.Started = job.StartedUnix.AsTimePtr()
+	}
+	if job.StoppedUnix != 0 {
+		apiJob.Stopped = job.StoppedUnix.AsTimePtr()
+	}
+	return apiJob
+}
+
+// ToWorkflowRunner converts a models.WorkflowRunner to api.WorkflowRunner
+func ToWorkflowRunner(r *models.WorkflowRunner) *api.WorkflowRunner {
+	apiRunner := &api.WorkflowRunner{
+		ID:             r.ID,
+		Name:           r.Name,
+		Labels:         r.Labels,
+		Token:          r.Token,
+		TokenLastEight: r.TokenLastEight,
+		Created:        r.CreatedUnix.AsTime(),
+	}
+	if r.LastOnlineUnix != 0 {
+		apiRunner.LastOnline = r.LastOnlineUnix.AsTimePtr()
+	}
+	return apiRunner
+}
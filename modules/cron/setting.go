@@ -48,6 +48,13 @@ type CleanupHookTaskConfig struct {
 	NumberToKeep int
 }
 
+// CleanupPackagesConfig represents a cron task with settings to cleanup packages
+type CleanupPackagesConfig struct {
+	BaseConfig
+	OlderThan    time.Duration
+	NumberToKeep int
+}
+
 // GetSchedule returns the schedule for the base config
 func (b *BaseConfig) GetSchedule() string {
 	return b.Schedule
@@ -12,8 +12,15 @@ import (
 	"code.gitea.io/gitea/modules/migrations"
 	repository_service "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	advisory_service "code.gitea.io/gitea/services/advisory"
+	attachment_service "code.gitea.io/gitea/services/attachment"
 	"code.gitea.io/gitea/services/auth"
+	issue_service "code.gitea.io/gitea/services/issue"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	outbox_service "code.gitea.io/gitea/services/outbox"
+	packages_service "code.gitea.io/gitea/services/packages"
+	schedule_service "code.gitea.io/gitea/services/schedule"
+	workflow_service "code.gitea.io/gitea/services/workflow"
 )
 
 func registerUpdateMirrorTask() {
@@ -126,6 +133,172 @@ func registerCleanupHookTaskTable() {
 	})
 }
 
+func registerCleanupPackages() {
+	RegisterTaskFatal("cleanup_packages", &CleanupPackagesConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: true,
+			Schedule:   "@midnight",
+		},
+		OlderThan:    0,
+		NumberToKeep: 0,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*CleanupPackagesConfig)
+		return packages_service.Cleanup(ctx, realConfig.OlderThan, realConfig.NumberToKeep)
+	})
+}
+
+func registerDeleteOldStagingSessions() {
+	RegisterTaskFatal("delete_old_staging_sessions", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		OlderThan: 168 * time.Hour,
+	}, func(_ context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return models.DeleteOldStagingSessions(realConfig.OlderThan)
+	})
+}
+
+func registerDeleteUnreferencedAttachmentBlobs() {
+	RegisterTaskFatal("delete_unreferenced_attachment_blobs", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		OlderThan: time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return attachment_service.DeleteUnreferencedBlobs(ctx, realConfig.OlderThan)
+	})
+}
+
+func registerFireRepoSchedules() {
+	RegisterTaskFatal("fire_repo_schedules", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 1m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return schedule_service.Run(ctx)
+	})
+}
+
+func registerUnlockExpiredIssues() {
+	RegisterTaskFatal("unlock_expired_issues", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return issue_service.UnlockExpiredIssues(ctx)
+	})
+}
+
+func registerRollIterations() {
+	RegisterTaskFatal("roll_iterations", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 1h",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return issue_service.RollIterations(ctx)
+	})
+}
+
+func registerCheckIssueSLAs() {
+	RegisterTaskFatal("check_issue_slas", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return issue_service.CheckIssueSLAs(ctx)
+	})
+}
+
+func registerResurfaceSnoozedNotifications() {
+	RegisterTaskFatal("resurface_snoozed_notifications", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 1m",
+		NoSuccessNotice: true,
+	}, func(_ context.Context, _ *models.User, _ Config) error {
+		return models.ResurfaceSnoozedNotifications()
+	})
+}
+
+func registerUpdateRepoTrending() {
+	RegisterTaskFatal("update_repo_trending", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 1h",
+		NoSuccessNotice: true,
+	}, func(_ context.Context, _ *models.User, _ Config) error {
+		return models.UpdateRepoTrending(time.Now())
+	})
+}
+
+func registerSyncSecurityAdvisories() {
+	RegisterTaskFatal("sync_security_advisories", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return advisory_service.Sync(ctx)
+	})
+}
+
+func registerCheckVulnerabilityAlerts() {
+	RegisterTaskFatal("check_vulnerability_alerts", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      false,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return advisory_service.CheckRepositories(ctx)
+	})
+}
+
+func registerFailTimedOutWorkflowJobs() {
+	RegisterTaskFatal("fail_timed_out_workflow_jobs", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return workflow_service.FailTimedOutJobs(ctx)
+	})
+}
+
+func registerDispatchOutboxEvents() {
+	RegisterTaskFatal("dispatch_outbox_events", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 1m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return outbox_service.DispatchPending(ctx)
+	})
+}
+
+func registerDeleteOldOutboxEvents() {
+	RegisterTaskFatal("delete_old_outbox_events", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		OlderThan: 72 * time.Hour,
+	}, func(_ context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return models.DeleteDispatchedOutboxEvents(realConfig.OlderThan)
+	})
+}
+
 func initBasicTasks() {
 	registerUpdateMirrorTask()
 	registerRepoHealthCheck()
@@ -137,4 +310,24 @@ func initBasicTasks() {
 		registerUpdateMigrationPosterID()
 	}
 	registerCleanupHookTaskTable()
+	registerDeleteOldStagingSessions()
+	registerDeleteUnreferencedAttachmentBlobs()
+	registerFireRepoSchedules()
+	registerUnlockExpiredIssues()
+	registerRollIterations()
+	registerCheckIssueSLAs()
+	registerResurfaceSnoozedNotifications()
+	registerUpdateRepoTrending()
+	registerDispatchOutboxEvents()
+	registerDeleteOldOutboxEvents()
+	if setting.Packages.Enabled {
+		registerCleanupPackages()
+	}
+	if setting.Advisories.Enabled {
+		registerSyncSecurityAdvisories()
+		registerCheckVulnerabilityAlerts()
+	}
+	if setting.Workflow.Enabled {
+		registerFailTimedOutWorkflowJobs()
+	}
 }
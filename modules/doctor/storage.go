@@ -5,6 +5,8 @@
 package doctor
 
 import (
+	"strings"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/storage"
@@ -21,7 +23,17 @@ func checkAttachmentStorageFiles(logger log.Logger, autofix bool) error {
 		if err != nil {
 			return err
 		}
-		exist, err := models.ExistAttachmentsByUUID(stat.Name())
+		// the thumbnails are kept as long as their attachment content
+		name := stat.Name()
+		if i := strings.LastIndex(name, models.AttachmentThumbnailSuffix); i > 0 {
+			name = name[:i]
+		}
+		var exist bool
+		if strings.HasPrefix(p, "sha256/") {
+			exist, err = models.ExistAttachmentBlobByHash(name)
+		} else {
+			exist, err = models.ExistAttachmentsByUUID(name)
+		}
 		if err != nil {
 			return err
 		}
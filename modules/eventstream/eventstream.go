@@ -0,0 +1,200 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package eventstream
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Event represents a repository event delivered to the streams
+type Event struct {
+	// Seq is assigned when the event is published and increases monotonically
+	Seq    uint64
+	Type   string
+	Action string
+	RepoID int64
+	// Unit is the unit a user has to be able to read to receive the event
+	Unit  models.UnitType
+	Actor *models.User
+	// UserIDs are the users the event is directly about, e.g. assignees,
+	// requested reviewers or mentioned users, who receive it without watching the repository
+	UserIDs []int64
+	Payload interface{}
+	Created time.Time
+}
+
+// Involves returns whether a user is directly involved in the event
+func (e *Event) Involves(userID int64) bool {
+	for _, id := range e.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Broker keeps the recent events and wakes up the streams when new events are published
+type Broker struct {
+	mu          sync.RWMutex
+	epoch       string
+	seq         uint64
+	historySize int
+	history     []*Event
+	subscribers map[chan struct{}]struct{}
+	connections map[int64]int
+}
+
+// NewBroker creates a broker keeping the given number of recent events
+func NewBroker(historySize int) *Broker {
+	return &Broker{
+		// the epoch invalidates the cursors of a previous process, whose history is lost
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: historySize,
+		history:     make([]*Event, 0, historySize),
+		subscribers: make(map[chan struct{}]struct{}),
+		connections: make(map[int64]int),
+	}
+}
+
+var (
+	broker     *Broker
+	brokerOnce sync.Once
+)
+
+// GetBroker returns the broker of the event stream
+func GetBroker() *Broker {
+	brokerOnce.Do(func() {
+		broker = NewBroker(setting.EventStream.HistorySize)
+	})
+	return broker
+}
+
+// Publish publishes an event to the streams if the event stream is enabled
+func Publish(event *Event) {
+	if !setting.EventStream.Enabled {
+		return
+	}
+	GetBroker().Publish(event)
+}
+
+// Cursor returns the cursor of the event with the given sequence number
+func (b *Broker) Cursor(seq uint64) string {
+	return fmt.Sprintf("%s-%d", b.epoch, seq)
+}
+
+// Latest returns the cursor of the last published event
+func (b *Broker) Latest() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Cursor(b.seq)
+}
+
+// Publish assigns the next sequence number to an event, adds it to the history and wakes up the streams
+func (b *Broker) Publish(event *Event) {
+	if event.Created.IsZero() {
+		event.Created = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.Seq = b.seq
+	if len(b.history) >= b.historySize {
+		b.history = append(b.history[:0], b.history[len(b.history)-b.historySize+1:]...)
+	}
+	b.history = append(b.history, event)
+
+	for wake := range b.subscribers {
+		select {
+		case wake <- struct{}{}:
+		default:
+			// the stream has yet to catch up with a previous wake up
+		}
+	}
+}
+
+// Since returns the events published after the event of a cursor. An empty cursor
+// returns no events. If the events after the cursor are no longer in the history,
+// or the cursor is not from this broker, false is returned.
+func (b *Broker) Since(cursor string) ([]*Event, bool) {
+	if cursor == "" {
+		return nil, true
+	}
+	idx := strings.LastIndexByte(cursor, '-')
+	if idx < 0 || cursor[:idx] != b.epoch {
+		return nil, false
+	}
+	seq, err := strconv.ParseUint(cursor[idx+1:], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if seq > b.seq {
+		return nil, false
+	}
+	if seq == b.seq {
+		return nil, true
+	}
+	if len(b.history) == 0 || seq+1 < b.history[0].Seq {
+		return nil, false
+	}
+	i := sort.Search(len(b.history), func(i int) bool {
+		return b.history[i].Seq > seq
+	})
+	events := make([]*Event, len(b.history)-i)
+	copy(events, b.history[i:])
+	return events, true
+}
+
+// Subscribe returns a channel receiving a value whenever new events were published
+func (b *Broker) Subscribe() chan struct{} {
+	wake := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.subscribers[wake] = struct{}{}
+	b.mu.Unlock()
+	return wake
+}
+
+// Unsubscribe stops waking up a channel returned by Subscribe
+func (b *Broker) Unsubscribe(wake chan struct{}) {
+	b.mu.Lock()
+	delete(b.subscribers, wake)
+	b.mu.Unlock()
+}
+
+// AcquireConnection counts a new stream of a user, it returns false if the user
+// already has the maximum number of streams
+func (b *Broker) AcquireConnection(userID int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.connections[userID] >= setting.EventStream.MaxConnectionsPerUser {
+		return false
+	}
+	b.connections[userID]++
+	return true
+}
+
+// ReleaseConnection stops counting a stream of a user
+func (b *Broker) ReleaseConnection(userID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.connections[userID] <= 1 {
+		delete(b.connections, userID)
+		return
+	}
+	b.connections[userID]--
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package eventstream
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker_Since(t *testing.T) {
+	b := NewBroker(3)
+	start := b.Latest()
+
+	events, ok := b.Since(start)
+	assert.True(t, ok)
+	assert.Empty(t, events)
+
+	for i := 0; i < 4; i++ {
+		b.Publish(&Event{Type: "push", RepoID: int64(i)})
+	}
+
+	// the first event is no longer in the history
+	_, ok = b.Since(start)
+	assert.False(t, ok)
+
+	events, ok = b.Since(b.Cursor(1))
+	assert.True(t, ok)
+	if assert.Len(t, events, 3) {
+		assert.EqualValues(t, 2, events[0].Seq)
+		assert.EqualValues(t, 4, events[2].Seq)
+	}
+
+	events, ok = b.Since(b.Cursor(3))
+	assert.True(t, ok)
+	if assert.Len(t, events, 1) {
+		assert.EqualValues(t, 3, events[0].RepoID)
+	}
+
+	events, ok = b.Since(b.Latest())
+	assert.True(t, ok)
+	assert.Empty(t, events)
+
+	for _, cursor := range []string{"unknown-1", "1", b.Cursor(5), b.Cursor(1) + "x"} {
+		_, ok = b.Since(cursor)
+		assert.False(t, ok, cursor)
+	}
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	b := NewBroker(10)
+	wake := b.Subscribe()
+
+	b.Publish(&Event{Type: "push"})
+	b.Publish(&Event{Type: "push"})
+	assert.Len(t, wake, 1)
+	<-wake
+
+	b.Unsubscribe(wake)
+	b.Publish(&Event{Type: "push"})
+	assert.Len(t, wake, 0)
+}
+
+func TestBroker_AcquireConnection(t *testing.T) {
+	defer func(max int) {
+		setting.EventStream.MaxConnectionsPerUser = max
+	}(setting.EventStream.MaxConnectionsPerUser)
+	setting.EventStream.MaxConnectionsPerUser = 2
+
+	b := NewBroker(10)
+	assert.True(t, b.AcquireConnection(1))
+	assert.True(t, b.AcquireConnection(1))
+	assert.False(t, b.AcquireConnection(1))
+	assert.True(t, b.AcquireConnection(2))
+
+	b.ReleaseConnection(1)
+	assert.True(t, b.AcquireConnection(1))
+}
@@ -6,6 +6,8 @@
 package generate
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/base64"
 	"io"
@@ -58,6 +60,19 @@ func NewJwtSecretBase64() (string, error) {
 	return base64.RawURLEncoding.EncodeToString(bytes), nil
 }
 
+// NewVapidKeys generates a new P-256 key pair intended to be used for identifying the server
+// to Web Push services. Both keys are base64 encoded, the public key as an uncompressed point.
+func NewVapidKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	privateBytes := make([]byte, 32)
+	key.D.FillBytes(privateBytes)
+	publicBytes := elliptic.Marshal(elliptic.P256(), key.X, key.Y)
+	return base64.RawURLEncoding.EncodeToString(publicBytes), base64.RawURLEncoding.EncodeToString(privateBytes), nil
+}
+
 // NewSecretKey generate a new value intended to be used by SECRET_KEY.
 func NewSecretKey() (string, error) {
 	secretKey, err := util.RandomString(64)
@@ -17,6 +17,7 @@ import (
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 var (
@@ -146,6 +147,8 @@ func (c *Command) RunWithContext(rc *RunContext) error {
 	ctx, cancel := context.WithTimeout(c.parentContext, rc.Timeout)
 	defer cancel()
 
+	requestID := log.RequestIDFromContext(c.parentContext)
+
 	cmd := exec.CommandContext(ctx, c.name, c.args...)
 	if rc.Env == nil {
 		cmd.Env = os.Environ()
@@ -159,6 +162,10 @@ func (c *Command) RunWithContext(rc *RunContext) error {
 		// avoid prompting for credentials interactively, supported since git v2.3
 		"GIT_TERMINAL_PROMPT=0",
 	)
+	if requestID != "" {
+		// allows hooks and other subprocesses to correlate their work with the request
+		cmd.Env = append(cmd.Env, "GITEA_REQUEST_ID="+requestID)
+	}
 
 	// TODO: verify if this is still needed in golang 1.15
 	if goVersionLessThan115 {
@@ -168,6 +175,7 @@ func (c *Command) RunWithContext(rc *RunContext) error {
 	cmd.Stdout = rc.Stdout
 	cmd.Stderr = rc.Stderr
 	cmd.Stdin = rc.Stdin
+	start := time.Now()
 	if err := cmd.Start(); err != nil {
 		return err
 	}
@@ -176,6 +184,14 @@ func (c *Command) RunWithContext(rc *RunContext) error {
 	if desc == "" {
 		desc = fmt.Sprintf("%s %s %s [repo_path: %s]", GitExecutable, c.name, strings.Join(c.args, " "), rc.Dir)
 	}
+	if requestID != "" {
+		desc += fmt.Sprintf(" [request_id: %s]", requestID)
+	}
+	defer func() {
+		if elapsed := time.Since(start); setting.Git.SlowCommandThreshold > 0 && elapsed > setting.Git.SlowCommandThreshold {
+			log.Warn("Slow git command took %v: %s", elapsed, desc)
+		}
+	}()
 	pid := process.GetManager().Add(desc, cancel)
 	defer process.GetManager().Remove(pid)
 
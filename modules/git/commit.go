@@ -190,6 +190,22 @@ func (c *Commit) CommitsByRange(page, pageSize int) ([]*Commit, error) {
 	return c.repo.commitsByRange(c.ID, page, pageSize)
 }
 
+// CommitsBySkip returns at most limit commits of the history of the commit, after skipping the first skip commits
+func (c *Commit) CommitsBySkip(skip, limit int) ([]*Commit, error) {
+	return c.repo.commitsBySkip(c.ID, skip, limit)
+}
+
+// FilteredCommitsCount returns the number of commits of the history of the commit kept by the filter
+func (c *Commit) FilteredCommitsCount(filter *CommitsFilter) (int64, error) {
+	return c.repo.filteredCommitsCount(c.ID, filter)
+}
+
+// FilteredCommitsBySkip returns at most limit commits of the history of the commit kept by the filter,
+// after skipping the first skip of them
+func (c *Commit) FilteredCommitsBySkip(filter *CommitsFilter, skip, limit int) ([]*Commit, error) {
+	return c.repo.filteredCommitsBySkip(c.ID, filter, skip, limit)
+}
+
 // CommitsBefore returns all the commits before current revision
 func (c *Commit) CommitsBefore() ([]*Commit, error) {
 	return c.repo.getCommitsBefore(c.ID)
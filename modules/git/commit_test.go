@@ -20,6 +20,37 @@ func TestCommitsCount(t *testing.T) {
 	assert.Equal(t, int64(3), commitsCount)
 }
 
+func TestFilteredCommits(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	commit, err := bareRepo1.GetBranchCommit("master")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		Filter        *CommitsFilter
+		ExpectedCount int64
+	}{
+		{nil, 6},
+		{&CommitsFilter{Path: "foo"}, 3},
+		{&CommitsFilter{Author: "Example"}, 2},
+		{&CommitsFilter{Since: "2018-01-01T00:00:00Z", Until: "2018-04-18T14:10:00+10:00"}, 1},
+	}
+	for _, testCase := range testCases {
+		count, err := commit.FilteredCommitsCount(testCase.Filter)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.ExpectedCount, count)
+	}
+
+	commits, err := commit.FilteredCommitsBySkip(&CommitsFilter{Path: "foo"}, 1, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 1) {
+		assert.Equal(t, "6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1", commits[0].ID.String())
+	}
+}
+
 func TestGetFullCommitID(t *testing.T) {
 	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
 
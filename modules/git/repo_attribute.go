@@ -59,7 +59,7 @@ func (repo *Repository) CheckAttribute(opts CheckAttributeOpts) (map[string]map[
 		}
 	}
 
-	cmd := NewCommand(cmdArgs...)
+	cmd := NewCommandContext(repo.Ctx, cmdArgs...)
 
 	if err := cmd.RunInDirPipeline(repo.Path, stdOut, stdErr); err != nil {
 		return nil, fmt.Errorf("failed to run check-attr: %v\n%s\n%s", err, stdOut.String(), stdErr.String())
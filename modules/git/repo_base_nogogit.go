@@ -20,6 +20,8 @@ import (
 // Repository represents a Git repository.
 type Repository struct {
 	Path string
+	// Ctx is the context the commands of the repository are run in
+	Ctx context.Context
 
 	tagCache *ObjectCache
 
@@ -36,6 +38,11 @@ type Repository struct {
 
 // OpenRepository opens the repository at the given path.
 func OpenRepository(repoPath string) (*Repository, error) {
+	return OpenRepositoryCtx(DefaultContext, repoPath)
+}
+
+// OpenRepositoryCtx opens the repository at the given path and runs its commands within the given context.
+func OpenRepositoryCtx(ctx context.Context, repoPath string) (*Repository, error) {
 	repoPath, err := filepath.Abs(repoPath)
 	if err != nil {
 		return nil, err
@@ -45,11 +52,12 @@ func OpenRepository(repoPath string) (*Repository, error) {
 
 	repo := &Repository{
 		Path:     repoPath,
+		Ctx:      ctx,
 		tagCache: newObjectCache(),
 	}
 
-	repo.batchWriter, repo.batchReader, repo.batchCancel = CatFileBatch(repoPath)
-	repo.checkWriter, repo.checkReader, repo.checkCancel = CatFileBatchCheck(repo.Path)
+	repo.batchWriter, repo.batchReader, repo.batchCancel = CatFileBatch(ctx, repoPath)
+	repo.checkWriter, repo.checkReader, repo.checkCancel = CatFileBatchCheck(ctx, repo.Path)
 
 	return repo, nil
 }
@@ -58,7 +66,7 @@ func OpenRepository(repoPath string) (*Repository, error) {
 func (repo *Repository) CatFileBatch() (WriteCloserError, *bufio.Reader, func()) {
 	if repo.batchCancel == nil || repo.batchReader.Buffered() > 0 {
 		log.Debug("Opening temporary cat file batch for: %s", repo.Path)
-		return CatFileBatch(repo.Path)
+		return CatFileBatch(repo.Ctx, repo.Path)
 	}
 	return repo.batchWriter, repo.batchReader, func() {}
 }
@@ -67,7 +75,7 @@ func (repo *Repository) CatFileBatch() (WriteCloserError, *bufio.Reader, func())
 func (repo *Repository) CatFileBatchCheck() (WriteCloserError, *bufio.Reader, func()) {
 	if repo.checkCancel == nil || repo.checkReader.Buffered() > 0 {
 		log.Debug("Opening temporary cat file batch-check: %s", repo.Path)
-		return CatFileBatchCheck(repo.Path)
+		return CatFileBatchCheck(repo.Ctx, repo.Path)
 	}
 	return repo.checkWriter, repo.checkReader, func() {}
 }
@@ -45,7 +45,7 @@ func (repo *Repository) GetHEADBranch() (*Branch, error) {
 	if repo == nil {
 		return nil, fmt.Errorf("nil repo")
 	}
-	stdout, err := NewCommand("symbolic-ref", "HEAD").RunInDir(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "symbolic-ref", "HEAD").RunInDir(repo.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -64,13 +64,13 @@ func (repo *Repository) GetHEADBranch() (*Branch, error) {
 
 // SetDefaultBranch sets default branch of repository.
 func (repo *Repository) SetDefaultBranch(name string) error {
-	_, err := NewCommand("symbolic-ref", "HEAD", BranchPrefix+name).RunInDir(repo.Path)
+	_, err := NewCommandContext(repo.Ctx, "symbolic-ref", "HEAD", BranchPrefix+name).RunInDir(repo.Path)
 	return err
 }
 
 // GetDefaultBranch gets default branch of repository.
 func (repo *Repository) GetDefaultBranch() (string, error) {
-	return NewCommand("symbolic-ref", "HEAD").RunInDir(repo.Path)
+	return NewCommandContext(repo.Ctx, "symbolic-ref", "HEAD").RunInDir(repo.Path)
 }
 
 // GetBranch returns a branch by it's name
@@ -118,7 +118,7 @@ type DeleteBranchOptions struct {
 
 // DeleteBranch delete a branch by name on repository.
 func (repo *Repository) DeleteBranch(name string, opts DeleteBranchOptions) error {
-	cmd := NewCommand("branch")
+	cmd := NewCommandContext(repo.Ctx, "branch")
 
 	if opts.Force {
 		cmd.AddArguments("-D")
@@ -134,7 +134,7 @@ func (repo *Repository) DeleteBranch(name string, opts DeleteBranchOptions) erro
 
 // CreateBranch create a new branch
 func (repo *Repository) CreateBranch(branch, oldbranchOrCommit string) error {
-	cmd := NewCommand("branch")
+	cmd := NewCommandContext(repo.Ctx, "branch")
 	cmd.AddArguments("--", branch, oldbranchOrCommit)
 
 	_, err := cmd.RunInDir(repo.Path)
@@ -142,9 +142,15 @@ func (repo *Repository) CreateBranch(branch, oldbranchOrCommit string) error {
 	return err
 }
 
+// RenameBranch rename a branch
+func (repo *Repository) RenameBranch(from, to string) error {
+	_, err := NewCommandContext(repo.Ctx, "branch", "-m", "--", from, to).RunInDir(repo.Path)
+	return err
+}
+
 // AddRemote adds a new remote to repository.
 func (repo *Repository) AddRemote(name, url string, fetch bool) error {
-	cmd := NewCommand("remote", "add")
+	cmd := NewCommandContext(repo.Ctx, "remote", "add")
 	if fetch {
 		cmd.AddArguments("-f")
 	}
@@ -156,7 +162,7 @@ func (repo *Repository) AddRemote(name, url string, fetch bool) error {
 
 // RemoveRemote removes a remote from repository.
 func (repo *Repository) RemoveRemote(name string) error {
-	_, err := NewCommand("remote", "rm", name).RunInDir(repo.Path)
+	_, err := NewCommandContext(repo.Ctx, "remote", "rm", name).RunInDir(repo.Path)
 	return err
 }
 
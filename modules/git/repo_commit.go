@@ -58,7 +58,7 @@ func (repo *Repository) getCommitByPathWithID(id SHA1, relpath string) (*Commit,
 		relpath = `\` + relpath
 	}
 
-	stdout, err := NewCommand("log", "-1", prettyLogFormat, id.String(), "--", relpath).RunInDir(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "log", "-1", prettyLogFormat, id.String(), "--", relpath).RunInDir(repo.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +73,7 @@ func (repo *Repository) getCommitByPathWithID(id SHA1, relpath string) (*Commit,
 
 // GetCommitByPath returns the last commit of relative path.
 func (repo *Repository) GetCommitByPath(relpath string) (*Commit, error) {
-	stdout, err := NewCommand("log", "-1", prettyLogFormat, "--", relpath).RunInDirBytes(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "log", "-1", prettyLogFormat, "--", relpath).RunInDirBytes(repo.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -86,18 +86,67 @@ func (repo *Repository) GetCommitByPath(relpath string) (*Commit, error) {
 }
 
 func (repo *Repository) commitsByRange(id SHA1, page, pageSize int) ([]*Commit, error) {
-	stdout, err := NewCommand("log", id.String(), "--skip="+strconv.Itoa((page-1)*pageSize),
-		"--max-count="+strconv.Itoa(pageSize), prettyLogFormat).RunInDirBytes(repo.Path)
+	return repo.commitsBySkip(id, (page-1)*pageSize, pageSize)
+}
+
+func (repo *Repository) commitsBySkip(id SHA1, skip, limit int) ([]*Commit, error) {
+	return repo.filteredCommitsBySkip(id, nil, skip, limit)
+}
+
+// CommitsFilter keeps the commits of a history which changed a path, are by an author or were committed in a time range
+type CommitsFilter struct {
+	Path   string
+	Author string
+	// Since and Until are dates in any format git understands
+	Since string
+	Until string
+}
 
+// arguments returns the arguments of git log and rev-list, the path comes last
+func (f *CommitsFilter) arguments() []string {
+	if f == nil {
+		return nil
+	}
+	var args []string
+	if f.Author != "" {
+		args = append(args, "--author="+f.Author)
+	}
+	if f.Since != "" {
+		args = append(args, "--since="+f.Since)
+	}
+	if f.Until != "" {
+		args = append(args, "--until="+f.Until)
+	}
+	if f.Path != "" {
+		args = append(args, "--", f.Path)
+	}
+	return args
+}
+
+func (repo *Repository) filteredCommitsBySkip(id SHA1, filter *CommitsFilter, skip, limit int) ([]*Commit, error) {
+	cmd := NewCommandContext(repo.Ctx, "log", id.String(), "--skip="+strconv.Itoa(skip),
+		"--max-count="+strconv.Itoa(limit), prettyLogFormat)
+	cmd.AddArguments(filter.arguments()...)
+	stdout, err := cmd.RunInDirBytes(repo.Path)
 	if err != nil {
 		return nil, err
 	}
 	return repo.parsePrettyFormatLogToList(stdout)
 }
 
+func (repo *Repository) filteredCommitsCount(id SHA1, filter *CommitsFilter) (int64, error) {
+	cmd := NewCommandContext(repo.Ctx, "rev-list", "--count", id.String())
+	cmd.AddArguments(filter.arguments()...)
+	stdout, err := cmd.RunInDir(repo.Path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+}
+
 func (repo *Repository) searchCommits(id SHA1, opts SearchCommitsOptions) ([]*Commit, error) {
 	// create new git log command with limit of 100 commis
-	cmd := NewCommand("log", id.String(), "-100", prettyLogFormat)
+	cmd := NewCommandContext(repo.Ctx, "log", id.String(), "-100", prettyLogFormat)
 	// ignore case
 	args := []string{"-i"}
 
@@ -155,7 +204,7 @@ func (repo *Repository) searchCommits(id SHA1, opts SearchCommitsOptions) ([]*Co
 			// ignore anything below 4 characters as too unspecific
 			if len(v) >= 4 {
 				// create new git log command with 1 commit limit
-				hashCmd := NewCommand("log", "-1", prettyLogFormat)
+				hashCmd := NewCommandContext(repo.Ctx, "log", "-1", prettyLogFormat)
 				// add previous arguments except for --grep and --all
 				hashCmd.AddArguments(args...)
 				// add keyword as <commit>
@@ -176,7 +225,7 @@ func (repo *Repository) searchCommits(id SHA1, opts SearchCommitsOptions) ([]*Co
 }
 
 func (repo *Repository) getFilesChanged(id1, id2 string) ([]string, error) {
-	stdout, err := NewCommand("diff", "--name-only", id1, id2).RunInDirBytes(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "diff", "--name-only", id1, id2).RunInDirBytes(repo.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +235,7 @@ func (repo *Repository) getFilesChanged(id1, id2 string) ([]string, error) {
 // FileChangedBetweenCommits Returns true if the file changed between commit IDs id1 and id2
 // You must ensure that id1 and id2 are valid commit ids.
 func (repo *Repository) FileChangedBetweenCommits(filename, id1, id2 string) (bool, error) {
-	stdout, err := NewCommand("diff", "--name-only", "-z", id1, id2, "--", filename).RunInDirBytes(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "diff", "--name-only", "-z", id1, id2, "--", filename).RunInDirBytes(repo.Path)
 	if err != nil {
 		return false, err
 	}
@@ -209,7 +258,7 @@ func (repo *Repository) CommitsByFileAndRange(revision, file string, page int) (
 	}()
 	go func() {
 		stderr := strings.Builder{}
-		err := NewCommand("log", revision, "--follow",
+		err := NewCommandContext(repo.Ctx, "log", revision, "--follow",
 			"--max-count="+strconv.Itoa(setting.Git.CommitsRangeSize*page),
 			prettyLogFormat, "--", file).
 			RunInDirPipeline(repo.Path, stdoutWriter, &stderr)
@@ -240,7 +289,7 @@ func (repo *Repository) CommitsByFileAndRange(revision, file string, page int) (
 
 // CommitsByFileAndRangeNoFollow return the commits according revision file and the page
 func (repo *Repository) CommitsByFileAndRangeNoFollow(revision, file string, page int) ([]*Commit, error) {
-	stdout, err := NewCommand("log", revision, "--skip="+strconv.Itoa((page-1)*50),
+	stdout, err := NewCommandContext(repo.Ctx, "log", revision, "--skip="+strconv.Itoa((page-1)*50),
 		"--max-count="+strconv.Itoa(setting.Git.CommitsRangeSize), prettyLogFormat, "--", file).RunInDirBytes(repo.Path)
 	if err != nil {
 		return nil, err
@@ -248,13 +297,29 @@ func (repo *Repository) CommitsByFileAndRangeNoFollow(revision, file string, pag
 	return repo.parsePrettyFormatLogToList(stdout)
 }
 
+// GetFileCommitAuthorEmails returns the author emails of the latest non-merge commits changing a file at a revision
+func (repo *Repository) GetFileCommitAuthorEmails(revision, file string, limit int) ([]string, error) {
+	stdout, err := NewCommandContext(repo.Ctx, "log", revision, "--no-merges",
+		"--max-count="+strconv.Itoa(limit), "--format=%aE", "--", file).RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	emails := make([]string, 0, limit)
+	for _, email := range strings.Split(stdout, "\n") {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails, nil
+}
+
 // FilesCountBetween return the number of files changed between two commits
 func (repo *Repository) FilesCountBetween(startCommitID, endCommitID string) (int, error) {
-	stdout, err := NewCommand("diff", "--name-only", startCommitID+"..."+endCommitID).RunInDir(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "diff", "--name-only", startCommitID+"..."+endCommitID).RunInDir(repo.Path)
 	if err != nil && strings.Contains(err.Error(), "no merge base") {
 		// git >= 2.28 now returns an error if startCommitID and endCommitID have become unrelated.
 		// previously it would return the results of git diff --name-only startCommitID endCommitID so let's try that...
-		stdout, err = NewCommand("diff", "--name-only", startCommitID, endCommitID).RunInDir(repo.Path)
+		stdout, err = NewCommandContext(repo.Ctx, "diff", "--name-only", startCommitID, endCommitID).RunInDir(repo.Path)
 	}
 	if err != nil {
 		return 0, err
@@ -268,13 +333,13 @@ func (repo *Repository) CommitsBetween(last *Commit, before *Commit) ([]*Commit,
 	var stdout []byte
 	var err error
 	if before == nil {
-		stdout, err = NewCommand("rev-list", last.ID.String()).RunInDirBytes(repo.Path)
+		stdout, err = NewCommandContext(repo.Ctx, "rev-list", last.ID.String()).RunInDirBytes(repo.Path)
 	} else {
-		stdout, err = NewCommand("rev-list", before.ID.String()+".."+last.ID.String()).RunInDirBytes(repo.Path)
+		stdout, err = NewCommandContext(repo.Ctx, "rev-list", before.ID.String()+".."+last.ID.String()).RunInDirBytes(repo.Path)
 		if err != nil && strings.Contains(err.Error(), "no merge base") {
 			// future versions of git >= 2.28 are likely to return an error if before and last have become unrelated.
 			// previously it would return the results of git rev-list before last so let's try that...
-			stdout, err = NewCommand("rev-list", before.ID.String(), last.ID.String()).RunInDirBytes(repo.Path)
+			stdout, err = NewCommandContext(repo.Ctx, "rev-list", before.ID.String(), last.ID.String()).RunInDirBytes(repo.Path)
 		}
 	}
 	if err != nil {
@@ -288,13 +353,13 @@ func (repo *Repository) CommitsBetweenLimit(last *Commit, before *Commit, limit,
 	var stdout []byte
 	var err error
 	if before == nil {
-		stdout, err = NewCommand("rev-list", "--max-count", strconv.Itoa(limit), "--skip", strconv.Itoa(skip), last.ID.String()).RunInDirBytes(repo.Path)
+		stdout, err = NewCommandContext(repo.Ctx, "rev-list", "--max-count", strconv.Itoa(limit), "--skip", strconv.Itoa(skip), last.ID.String()).RunInDirBytes(repo.Path)
 	} else {
-		stdout, err = NewCommand("rev-list", "--max-count", strconv.Itoa(limit), "--skip", strconv.Itoa(skip), before.ID.String()+".."+last.ID.String()).RunInDirBytes(repo.Path)
+		stdout, err = NewCommandContext(repo.Ctx, "rev-list", "--max-count", strconv.Itoa(limit), "--skip", strconv.Itoa(skip), before.ID.String()+".."+last.ID.String()).RunInDirBytes(repo.Path)
 		if err != nil && strings.Contains(err.Error(), "no merge base") {
 			// future versions of git >= 2.28 are likely to return an error if before and last have become unrelated.
 			// previously it would return the results of git rev-list --max-count n before last so let's try that...
-			stdout, err = NewCommand("rev-list", "--max-count", strconv.Itoa(limit), "--skip", strconv.Itoa(skip), before.ID.String(), last.ID.String()).RunInDirBytes(repo.Path)
+			stdout, err = NewCommandContext(repo.Ctx, "rev-list", "--max-count", strconv.Itoa(limit), "--skip", strconv.Itoa(skip), before.ID.String(), last.ID.String()).RunInDirBytes(repo.Path)
 		}
 	}
 	if err != nil {
@@ -333,7 +398,7 @@ func (repo *Repository) CommitsCountBetween(start, end string) (int64, error) {
 
 // commitsBefore the limit is depth, not total number of returned commits.
 func (repo *Repository) commitsBefore(id SHA1, limit int) ([]*Commit, error) {
-	cmd := NewCommand("log")
+	cmd := NewCommandContext(repo.Ctx, "log")
 	if limit > 0 {
 		cmd.AddArguments("-"+strconv.Itoa(limit), prettyLogFormat, id.String())
 	} else {
@@ -377,7 +442,7 @@ func (repo *Repository) getCommitsBeforeLimit(id SHA1, num int) ([]*Commit, erro
 
 func (repo *Repository) getBranches(commit *Commit, limit int) ([]string, error) {
 	if CheckGitVersionAtLeast("2.7.0") == nil {
-		stdout, err := NewCommand("for-each-ref", "--count="+strconv.Itoa(limit), "--format=%(refname:strip=2)", "--contains", commit.ID.String(), BranchPrefix).RunInDir(repo.Path)
+		stdout, err := NewCommandContext(repo.Ctx, "for-each-ref", "--count="+strconv.Itoa(limit), "--format=%(refname:strip=2)", "--contains", commit.ID.String(), BranchPrefix).RunInDir(repo.Path)
 		if err != nil {
 			return nil, err
 		}
@@ -386,7 +451,7 @@ func (repo *Repository) getBranches(commit *Commit, limit int) ([]string, error)
 		return branches, nil
 	}
 
-	stdout, err := NewCommand("branch", "--contains", commit.ID.String()).RunInDir(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "branch", "--contains", commit.ID.String()).RunInDir(repo.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -425,9 +490,32 @@ func (repo *Repository) GetCommitsFromIDs(commitIDs []string) []*Commit {
 
 // IsCommitInBranch check if the commit is on the branch
 func (repo *Repository) IsCommitInBranch(commitID, branch string) (r bool, err error) {
-	stdout, err := NewCommand("branch", "--contains", commitID, branch).RunInDir(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "branch", "--contains", commitID, branch).RunInDir(repo.Path)
 	if err != nil {
 		return false, err
 	}
 	return len(stdout) > 0, err
 }
+
+// IsCommitChangeInBranch checks if the commit or a commit with the same patch id, e.g. a cherry-pick of it, is on the branch.
+// Only the ancestry is checked for root and merge commits.
+func (repo *Repository) IsCommitChangeInBranch(commitID, branch string) (bool, error) {
+	if contained, err := repo.IsCommitInBranch(commitID, branch); err != nil || contained {
+		return contained, err
+	}
+
+	commit, err := repo.GetCommit(commitID)
+	if err != nil {
+		return false, err
+	}
+	if commit.ParentCount() != 1 {
+		return false, nil
+	}
+
+	// git cherry marks the commit with "-" if a commit with the same patch id is on the branch
+	stdout, err := NewCommandContext(repo.Ctx, "cherry", branch, commit.ID.String(), commit.ID.String()+"^").RunInDir(repo.Path)
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(stdout, "-"), nil
+}
@@ -5,9 +5,12 @@
 package git
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -100,3 +103,62 @@ func TestRepository_CommitsBetweenIDs(t *testing.T) {
 		assert.Equal(t, c.ExpectedCommits, len(commits), "case %d", i)
 	}
 }
+
+func TestIsCommitChangeInBranch(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	clonedPath, err := cloneRepo(bareRepo1Path, testReposDir, "repo1_TestIsCommitChangeInBranch")
+	assert.NoError(t, err)
+	defer util.RemoveAll(clonedPath)
+
+	// cherry-pick "Edit file1.txt" of branch1 to master
+	_, err = NewCommand("cherry-pick", "2839944139e0de9737a044f78b0e4b40d989a9e3").RunInDirWithEnv(clonedPath, append(os.Environ(),
+		"GIT_AUTHOR_NAME=Gitea", "GIT_AUTHOR_EMAIL=gitea@example.com",
+		"GIT_COMMITTER_NAME=Gitea", "GIT_COMMITTER_EMAIL=gitea@example.com",
+	))
+	assert.NoError(t, err)
+
+	clonedRepo1, err := OpenRepository(clonedPath)
+	assert.NoError(t, err)
+	defer clonedRepo1.Close()
+
+	result, err := clonedRepo1.IsCommitChangeInBranch("2839944139e0de9737a044f78b0e4b40d989a9e3", "master")
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	result, err = clonedRepo1.IsCommitChangeInBranch("9c9aef8dd84e02bc7ec12641deb4c930a7c30185", "master")
+	assert.NoError(t, err)
+	assert.False(t, result)
+
+	result, err = clonedRepo1.IsCommitChangeInBranch("8d92fc957a4d7cfd98bc375f0b7bb189a0d6c9f2", "master")
+	assert.NoError(t, err)
+	assert.True(t, result)
+}
+
+func TestGetFileCommitAuthorEmails(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	clonedPath, err := cloneRepo(bareRepo1Path, testReposDir, "repo1_TestGetFileCommitAuthorEmails")
+	assert.NoError(t, err)
+	defer util.RemoveAll(clonedPath)
+
+	// apply "Edit file1.txt" of branch1 to master with another author, cherry-pick would keep the author
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME=Gitea", "GIT_AUTHOR_EMAIL=gitea@example.com",
+		"GIT_COMMITTER_NAME=Gitea", "GIT_COMMITTER_EMAIL=gitea@example.com",
+	)
+	_, err = NewCommand("cherry-pick", "--no-commit", "2839944139e0de9737a044f78b0e4b40d989a9e3").RunInDirWithEnv(clonedPath, env)
+	assert.NoError(t, err)
+	_, err = NewCommand("commit", "-m", "Edit file1.txt").RunInDirWithEnv(clonedPath, env)
+	assert.NoError(t, err)
+
+	clonedRepo1, err := OpenRepository(clonedPath)
+	assert.NoError(t, err)
+	defer clonedRepo1.Close()
+
+	emails, err := clonedRepo1.GetFileCommitAuthorEmails("master", "file1.txt", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gitea@example.com"}, emails)
+
+	emails, err = clonedRepo1.GetFileCommitAuthorEmails("master", "file2.txt", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, emails)
+}
@@ -35,13 +35,13 @@ func (repo *Repository) GetMergeBase(tmpRemote string, base, head string) (strin
 	if tmpRemote != "origin" {
 		tmpBaseName := "refs/remotes/" + tmpRemote + "/tmp_" + base
 		// Fetch commit into a temporary branch in order to be able to handle commits and tags
-		_, err := NewCommand("fetch", tmpRemote, base+":"+tmpBaseName).RunInDir(repo.Path)
+		_, err := NewCommandContext(repo.Ctx, "fetch", tmpRemote, base+":"+tmpBaseName).RunInDir(repo.Path)
 		if err == nil {
 			base = tmpBaseName
 		}
 	}
 
-	stdout, err := NewCommand("merge-base", "--", base, head).RunInDir(repo.Path)
+	stdout, err := NewCommandContext(repo.Ctx, "merge-base", "--", base, head).RunInDir(repo.Path)
 	return strings.TrimSpace(stdout), base, err
 }
 
@@ -80,7 +80,7 @@ func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string)
 			compareInfo.BaseCommitID = remoteBranch
 		}
 		// We have a common base - therefore we know that ... should work
-		logs, err := NewCommand("log", compareInfo.MergeBase+"..."+headBranch, prettyLogFormat).RunInDirBytes(repo.Path)
+		logs, err := NewCommandContext(repo.Ctx, "log", compareInfo.MergeBase+"..."+headBranch, prettyLogFormat).RunInDirBytes(repo.Path)
 		if err != nil {
 			return nil, err
 		}
@@ -125,14 +125,14 @@ func (repo *Repository) GetDiffNumChangedFiles(base, head string) (int, error) {
 	w := &lineCountWriter{}
 	stderr := new(bytes.Buffer)
 
-	if err := NewCommand("diff", "-z", "--name-only", base+"..."+head).
+	if err := NewCommandContext(repo.Ctx, "diff", "-z", "--name-only", base+"..."+head).
 		RunInDirPipeline(repo.Path, w, stderr); err != nil {
 		if strings.Contains(stderr.String(), "no merge base") {
 			// git >= 2.28 now returns an error if base and head have become unrelated.
 			// previously it would return the results of git diff -z --name-only base head so let's try that...
 			w = &lineCountWriter{}
 			stderr.Reset()
-			if err = NewCommand("diff", "-z", "--name-only", base, head).RunInDirPipeline(repo.Path, w, stderr); err == nil {
+			if err = NewCommandContext(repo.Ctx, "diff", "-z", "--name-only", base, head).RunInDirPipeline(repo.Path, w, stderr); err == nil {
 				return w.numLines, nil
 			}
 		}
@@ -141,6 +141,21 @@ func (repo *Repository) GetDiffNumChangedFiles(base, head string) (int, error) {
 	return w.numLines, nil
 }
 
+// GetFilesChangedBetween returns the names of the files changed between the merge base of base and head, and head
+func (repo *Repository) GetFilesChangedBetween(base, head string) ([]string, error) {
+	stdout, err := NewCommandContext(repo.Ctx, "diff", "-z", "--name-only", base+"..."+head).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, bytes.Count(stdout, []byte{'\000'}))
+	for _, file := range bytes.Split(stdout, []byte{'\000'}) {
+		if len(file) > 0 {
+			files = append(files, string(file))
+		}
+	}
+	return files, nil
+}
+
 // GetDiffShortStat counts number of changed files, number of additions and deletions
 func (repo *Repository) GetDiffShortStat(base, head string) (numFiles, totalAdditions, totalDeletions int, err error) {
 	numFiles, totalAdditions, totalDeletions, err = GetDiffShortStat(repo.Path, base+"..."+head)
@@ -212,17 +227,17 @@ func (repo *Repository) GetDiffOrPatch(base, head string, w io.Writer, formatted
 
 // GetDiff generates and returns patch data between given revisions.
 func (repo *Repository) GetDiff(base, head string, w io.Writer) error {
-	return NewCommand("diff", "-p", "--binary", base, head).
+	return NewCommandContext(repo.Ctx, "diff", "-p", "--binary", base, head).
 		RunInDirPipeline(repo.Path, w, nil)
 }
 
 // GetPatch generates and returns format-patch data between given revisions.
 func (repo *Repository) GetPatch(base, head string, w io.Writer) error {
 	stderr := new(bytes.Buffer)
-	err := NewCommand("format-patch", "--binary", "--stdout", base+"..."+head).
+	err := NewCommandContext(repo.Ctx, "format-patch", "--binary", "--stdout", base+"..."+head).
 		RunInDirPipeline(repo.Path, w, stderr)
 	if err != nil && bytes.Contains(stderr.Bytes(), []byte("no merge base")) {
-		return NewCommand("format-patch", "--binary", "--stdout", base, head).
+		return NewCommandContext(repo.Ctx, "format-patch", "--binary", "--stdout", base, head).
 			RunInDirPipeline(repo.Path, w, nil)
 	}
 	return err
@@ -231,10 +246,10 @@ func (repo *Repository) GetPatch(base, head string, w io.Writer) error {
 // GetDiffFromMergeBase generates and return patch data from merge base to head
 func (repo *Repository) GetDiffFromMergeBase(base, head string, w io.Writer) error {
 	stderr := new(bytes.Buffer)
-	err := NewCommand("diff", "-p", "--binary", base+"..."+head).
+	err := NewCommandContext(repo.Ctx, "diff", "-p", "--binary", base+"..."+head).
 		RunInDirPipeline(repo.Path, w, stderr)
 	if err != nil && bytes.Contains(stderr.Bytes(), []byte("no merge base")) {
-		return NewCommand("diff", "-p", "--binary", base, head).
+		return NewCommandContext(repo.Ctx, "diff", "-p", "--binary", base, head).
 			RunInDirPipeline(repo.Path, w, nil)
 	}
 	return err
@@ -0,0 +1,73 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GrepResult represents a line matched by git grep
+type GrepResult struct {
+	Filename   string
+	LineNumber int
+	Content    string
+}
+
+// GrepWord returns up to limit lines of the given revision which contain word as a whole word,
+// after skipping the first skip matching lines. Binary files are skipped.
+func (repo *Repository) GrepWord(revision, word string, skip, limit int) ([]*GrepResult, error) {
+	stdoutReader, stdoutWriter := io.Pipe()
+	defer func() {
+		_ = stdoutReader.Close()
+		_ = stdoutWriter.Close()
+	}()
+	go func() {
+		stderr := strings.Builder{}
+		err := NewCommandContext(repo.Ctx, "grep", "--null", "--line-number", "--word-regexp", "-I", "--fixed-strings", "-e", word, revision).
+			RunInDirPipeline(repo.Path, stdoutWriter, &stderr)
+		var exitErr *exec.ExitError
+		if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0) {
+			// exit code 1 without any message means that nothing matched
+			_ = stdoutWriter.CloseWithError(ConcatenateError(err, (&stderr).String()))
+		} else {
+			_ = stdoutWriter.Close()
+		}
+	}()
+
+	results := make([]*GrepResult, 0, 10)
+	prefix := revision + ":"
+	scanner := bufio.NewScanner(stdoutReader)
+	for len(results) < limit && scanner.Scan() {
+		// each line has the format <revision>:<filename>\0<line number>\0<content>
+		fields := strings.SplitN(strings.TrimPrefix(scanner.Text(), prefix), "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		lineNumber, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		results = append(results, &GrepResult{
+			Filename:   fields[0],
+			LineNumber: lineNumber,
+			Content:    fields[2],
+		})
+	}
+	if len(results) < limit {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_GrepWord(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	results, err := bareRepo1.GrepWord("master", "Hi", 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "foo/nar/hello", results[0].Filename)
+		assert.Equal(t, 1, results[0].LineNumber)
+		assert.Equal(t, "Hi", results[0].Content)
+	}
+
+	results, err = bareRepo1.GrepWord("master", "Hi", 1, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = bareRepo1.GrepWord("master", "file", 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = bareRepo1.GrepWord("master", "file1", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	_, err = bareRepo1.GrepWord("not-a-revision", "Hi", 0, 10)
+	assert.Error(t, err)
+}
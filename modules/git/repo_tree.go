@@ -11,6 +11,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"code.gitea.io/gitea/modules/maintenance"
 )
 
 // CommitTreeOpts represents the possible options to CommitTree
@@ -20,34 +22,49 @@ type CommitTreeOpts struct {
 	KeyID      string
 	NoGPGSign  bool
 	AlwaysSign bool
+	// Trailers are appended to the message, see ValidateTrailers
+	Trailers map[string]string
+	// AuthorDate and CommitterDate default to now
+	AuthorDate    time.Time
+	CommitterDate time.Time
 }
 
 // CommitTree creates a commit from a given tree id for the user with provided message
 func (repo *Repository) CommitTree(author *Signature, committer *Signature, tree *Tree, opts CommitTreeOpts) (SHA1, error) {
+	if err := maintenance.CheckWritable(); err != nil {
+		return SHA1{}, err
+	}
+
 	err := LoadGitVersion()
 	if err != nil {
 		return SHA1{}, err
 	}
 
-	commitTimeStr := time.Now().Format(time.RFC3339)
+	authorDate, committerDate := opts.AuthorDate, opts.CommitterDate
+	if authorDate.IsZero() {
+		authorDate = time.Now()
+	}
+	if committerDate.IsZero() {
+		committerDate = time.Now()
+	}
 
 	// Because this may call hooks we should pass in the environment
 	env := append(os.Environ(),
 		"GIT_AUTHOR_NAME="+author.Name,
 		"GIT_AUTHOR_EMAIL="+author.Email,
-		"GIT_AUTHOR_DATE="+commitTimeStr,
+		"GIT_AUTHOR_DATE="+authorDate.Format(time.RFC3339),
 		"GIT_COMMITTER_NAME="+committer.Name,
 		"GIT_COMMITTER_EMAIL="+committer.Email,
-		"GIT_COMMITTER_DATE="+commitTimeStr,
+		"GIT_COMMITTER_DATE="+committerDate.Format(time.RFC3339),
 	)
-	cmd := NewCommand("commit-tree", tree.ID.String())
+	cmd := NewCommandContext(repo.Ctx, "commit-tree", tree.ID.String())
 
 	for _, parent := range opts.Parents {
 		cmd.AddArguments("-p", parent)
 	}
 
 	messageBytes := new(bytes.Buffer)
-	_, _ = messageBytes.WriteString(opts.Message)
+	_, _ = messageBytes.WriteString(AppendTrailers(opts.Message, opts.Trailers))
 	_, _ = messageBytes.WriteString("\n")
 
 	if CheckGitVersionAtLeast("1.7.9") == nil && (opts.KeyID != "" || opts.AlwaysSign) {
@@ -67,3 +84,34 @@ func (repo *Repository) CommitTree(author *Signature, committer *Signature, tree
 	}
 	return NewIDFromString(strings.TrimSpace(stdout.String()))
 }
+
+// MakeTreeEntry is an entry of a tree written by MakeTree
+type MakeTreeEntry struct {
+	Mode EntryMode
+	Type ObjectType
+	ID   SHA1
+	Name string
+}
+
+// MakeTree writes a tree of the given entries to the object db and returns its id,
+// the objects of the entries must exist
+func (repo *Repository) MakeTree(entries []*MakeTreeEntry) (SHA1, error) {
+	if err := maintenance.CheckWritable(); err != nil {
+		return SHA1{}, err
+	}
+
+	stdin := new(bytes.Buffer)
+	for _, entry := range entries {
+		if entry.Name == "" || strings.ContainsAny(entry.Name, "/\x00") {
+			return SHA1{}, fmt.Errorf("invalid tree entry name %q", entry.Name)
+		}
+		_, _ = fmt.Fprintf(stdin, "%06o %s %s\t%s\x00", int(entry.Mode), entry.Type, entry.ID, entry.Name)
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if err := NewCommandContext(repo.Ctx, "mktree", "-z").RunInDirFullPipeline(repo.Path, stdout, stderr, stdin); err != nil {
+		return SHA1{}, ConcatenateError(err, stderr.String())
+	}
+	return NewIDFromString(strings.TrimSpace(stdout.String()))
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ReservedTrailers are the trailers Gitea adds to the commits it creates itself,
+// they can not be given by users
+var ReservedTrailers = []string{
+	"Signed-off-by",
+	"Co-authored-by",
+	"Co-committed-by",
+}
+
+var (
+	trailerKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+	// trailerBlockPattern matches a message whose last paragraph other than the subject consists of trailers only
+	trailerBlockPattern = regexp.MustCompile(`\n\n(?:[\w-]+[ \t]*:[^\n]+\n*(?:[ \t]+[^\n]+\n*)*)+$`)
+)
+
+// ErrInvalidTrailer represents an invalid trailer of a commit message
+type ErrInvalidTrailer struct {
+	Key    string
+	Reason string
+}
+
+// IsErrInvalidTrailer checks if an error is a ErrInvalidTrailer
+func IsErrInvalidTrailer(err error) bool {
+	_, ok := err.(ErrInvalidTrailer)
+	return ok
+}
+
+func (err ErrInvalidTrailer) Error() string {
+	return fmt.Sprintf("invalid trailer %q: %s", err.Key, err.Reason)
+}
+
+// IsReservedTrailer returns whether a trailer key is reserved for Gitea
+func IsReservedTrailer(key string) bool {
+	for _, reserved := range ReservedTrailers {
+		if strings.EqualFold(key, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTrailers checks that the keys of the trailers are valid and not reserved,
+// and that their values are single non-empty lines
+func ValidateTrailers(trailers map[string]string) error {
+	for key, value := range trailers {
+		if !trailerKeyPattern.MatchString(key) {
+			return ErrInvalidTrailer{Key: key, Reason: "key may only contain alphanumeric characters and dashes"}
+		}
+		if IsReservedTrailer(key) {
+			return ErrInvalidTrailer{Key: key, Reason: "key is reserved"}
+		}
+		if strings.TrimSpace(value) == "" {
+			return ErrInvalidTrailer{Key: key, Reason: "value must not be empty"}
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return ErrInvalidTrailer{Key: key, Reason: "value must not contain line breaks"}
+		}
+	}
+	return nil
+}
+
+// AppendTrailers appends the trailers sorted by key to a commit message, joining the trailers
+// the message already ends with
+func AppendTrailers(message string, trailers map[string]string) string {
+	if len(trailers) == 0 {
+		return message
+	}
+
+	keys := make([]string, 0, len(trailers))
+	for key := range trailers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	message = strings.TrimRight(message, "\n")
+	sb.WriteString(message)
+	if message != "" {
+		sb.WriteString("\n")
+		if !trailerBlockPattern.MatchString(message) {
+			sb.WriteString("\n")
+		}
+	}
+	for i, key := range keys {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(key)
+		sb.WriteString(": ")
+		sb.WriteString(strings.TrimSpace(trailers[key]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTrailers(t *testing.T) {
+	assert.NoError(t, ValidateTrailers(nil))
+	assert.NoError(t, ValidateTrailers(map[string]string{
+		"Reviewed-by": "User Two <user2@example.com>",
+		"Change-Id":   "I0123456789abcdef",
+	}))
+
+	for _, trailers := range []map[string]string{
+		{"Signed-off-by": "User Two <user2@example.com>"},
+		{"co-authored-by": "User Two <user2@example.com>"},
+		{"Reviewed by": "User Two <user2@example.com>"},
+		{"-Reviewed-by": "User Two <user2@example.com>"},
+		{"Reviewed-by": " "},
+		{"Reviewed-by": "User Two\nSigned-off-by: User Three"},
+	} {
+		err := ValidateTrailers(trailers)
+		assert.True(t, IsErrInvalidTrailer(err), "%v", trailers)
+	}
+}
+
+func TestAppendTrailers(t *testing.T) {
+	trailers := map[string]string{
+		"Reviewed-by": "User Two <user2@example.com>",
+		"Change-Id":   "I0123456789abcdef",
+	}
+
+	assert.Equal(t, "Update README.md", AppendTrailers("Update README.md", nil))
+	assert.Equal(t, "Update README.md\n\nChange-Id: I0123456789abcdef\nReviewed-by: User Two <user2@example.com>",
+		AppendTrailers("Update README.md\n", trailers))
+	assert.Equal(t, "Update README.md\n\nFixes: #1\nChange-Id: I0123456789abcdef\nReviewed-by: User Two <user2@example.com>",
+		AppendTrailers("Update README.md\n\nFixes: #1", trailers))
+	assert.Equal(t, "fix: typo\n\nChange-Id: I0123456789abcdef\nReviewed-by: User Two <user2@example.com>",
+		AppendTrailers("fix: typo", trailers))
+	assert.Equal(t, "Change-Id: I0123456789abcdef\nReviewed-by: User Two <user2@example.com>",
+		AppendTrailers("", trailers))
+}
@@ -49,7 +49,7 @@ func (t *Tree) SubTree(rpath string) (*Tree, error) {
 
 // LsTree checks if the given filenames are in the tree
 func (repo *Repository) LsTree(ref string, filenames ...string) ([]string, error) {
-	cmd := NewCommand("ls-tree", "-z", "--name-only", "--", ref)
+	cmd := NewCommandContext(repo.Ctx, "ls-tree", "-z", "--name-only", "--", ref)
 	for _, arg := range filenames {
 		if arg != "" {
 			cmd.AddArguments(arg)
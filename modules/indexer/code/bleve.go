@@ -91,6 +91,7 @@ func openBleveIndexer(path string, latestVersion int) (bleve.Index, error) {
 // RepoIndexerData data stored in the repo indexer
 type RepoIndexerData struct {
 	RepoID    int64
+	Filename  string
 	CommitID  string
 	Content   string
 	Language  string
@@ -105,7 +106,7 @@ func (d *RepoIndexerData) Type() string {
 const (
 	repoIndexerAnalyzer      = "repoIndexerAnalyzer"
 	repoIndexerDocType       = "repoIndexerDocType"
-	repoIndexerLatestVersion = 5
+	repoIndexerLatestVersion = 6
 )
 
 // createBleveIndexer create a bleve repo indexer if one does not already exist
@@ -124,6 +125,7 @@ func createBleveIndexer(path string, latestVersion int) (bleve.Index, error) {
 	termFieldMapping.Analyzer = analyzer_keyword.Name
 	docMapping.AddFieldMappingsAt("Language", termFieldMapping)
 	docMapping.AddFieldMappingsAt("CommitID", termFieldMapping)
+	docMapping.AddFieldMappingsAt("Filename", termFieldMapping)
 
 	timeFieldMapping := bleve.NewDateTimeFieldMapping()
 	timeFieldMapping.IncludeInAll = false
@@ -223,6 +225,7 @@ func (b *BleveIndexer) addUpdate(batchWriter git.WriteCloserError, batchReader *
 	id := filenameIndexerID(repo.ID, update.Filename)
 	return batch.Index(id, &RepoIndexerData{
 		RepoID:    repo.ID,
+		Filename:  update.Filename,
 		CommitID:  commitSha,
 		Content:   string(charset.ToUTF8DropErrors(fileContents)),
 		Language:  analyze.GetCodeLanguage(update.Filename, fileContents),
@@ -271,7 +274,7 @@ func (b *BleveIndexer) Index(repo *models.Repository, sha string, changes *repoC
 	batch := gitea_bleve.NewFlushingBatch(b.indexer, maxBatchSize)
 	if len(changes.Updates) > 0 {
 
-		batchWriter, batchReader, cancel := git.CatFileBatch(repo.RepoPath())
+		batchWriter, batchReader, cancel := git.CatFileBatch(git.DefaultContext, repo.RepoPath())
 		defer cancel()
 
 		for _, update := range changes.Updates {
@@ -308,7 +311,7 @@ func (b *BleveIndexer) Delete(repoID int64) error {
 
 // Search searches for files in the specified repo.
 // Returns the matching file-paths
-func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, pageSize int, isMatch bool) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+func (b *BleveIndexer) Search(repoIDs []int64, language, filename, keyword string, page, pageSize int, isMatch bool) (int64, []*SearchResult, []*SearchResultLanguages, error) {
 	var (
 		indexerQuery query.Query
 		keywordQuery query.Query
@@ -339,6 +342,12 @@ func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, p
 		indexerQuery = keywordQuery
 	}
 
+	if len(filename) > 0 {
+		filenameQuery := bleve.NewWildcardQuery(filename)
+		filenameQuery.FieldVal = "Filename"
+		indexerQuery = bleve.NewConjunctionQuery(indexerQuery, filenameQuery)
+	}
+
 	// Save for reuse without language filter
 	facetQuery := indexerQuery
 	if len(language) > 0 {
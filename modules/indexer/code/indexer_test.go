@@ -23,10 +23,11 @@ func testIndexer(name string, t *testing.T, indexer Indexer) {
 		assert.NoError(t, err)
 		var (
 			keywords = []struct {
-				RepoIDs []int64
-				Keyword string
-				IDs     []int64
-				Langs   int
+				RepoIDs  []int64
+				Filename string
+				Keyword  string
+				IDs      []int64
+				Langs    int
 			}{
 				{
 					RepoIDs: nil,
@@ -58,12 +59,26 @@ func testIndexer(name string, t *testing.T, indexer Indexer) {
 					IDs:     []int64{},
 					Langs:   0,
 				},
+				{
+					RepoIDs:  nil,
+					Filename: "*.md",
+					Keyword:  "Description",
+					IDs:      []int64{repoID},
+					Langs:    1,
+				},
+				{
+					RepoIDs:  nil,
+					Filename: "*.go",
+					Keyword:  "Description",
+					IDs:      []int64{},
+					Langs:    0,
+				},
 			}
 		)
 
 		for _, kw := range keywords {
-			t.Run(kw.Keyword, func(t *testing.T) {
-				total, res, langs, err := indexer.Search(kw.RepoIDs, "", kw.Keyword, 1, 10, false)
+			t.Run(kw.Keyword+kw.Filename, func(t *testing.T) {
+				total, res, langs, err := indexer.Search(kw.RepoIDs, "", kw.Filename, kw.Keyword, 1, 10, false)
 				assert.NoError(t, err)
 				assert.EqualValues(t, len(kw.IDs), total)
 				assert.Len(t, langs, kw.Langs)
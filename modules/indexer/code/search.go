@@ -105,13 +105,14 @@ func searchResult(result *SearchResult, startIndex, endIndex int) (*Result, erro
 	}, nil
 }
 
-// PerformSearch perform a search on a repository
-func PerformSearch(repoIDs []int64, language, keyword string, page, pageSize int, isMatch bool) (int, []*Result, []*SearchResultLanguages, error) {
+// PerformSearch perform a search on a repository.
+// filename is an optional wildcard pattern matched against the paths of the files.
+func PerformSearch(repoIDs []int64, language, filename, keyword string, page, pageSize int, isMatch bool) (int, []*Result, []*SearchResultLanguages, error) {
 	if len(keyword) == 0 {
 		return 0, nil, nil, nil
 	}
 
-	total, results, resultLanguages, err := indexer.Search(repoIDs, language, keyword, page, pageSize, isMatch)
+	total, results, resultLanguages, err := indexer.Search(repoIDs, language, filename, keyword, page, pageSize, isMatch)
 	if err != nil {
 		return 0, nil, nil, err
 	}
@@ -0,0 +1,192 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package dependency
+
+import (
+	"fmt"
+	"io"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/analyze"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+)
+
+// maxManifestSize is the size of the largest manifest which is parsed
+const maxManifestSize = 1024 * 1024
+
+// dependencyQueue represents a queue to handle repository dependency updates
+var dependencyQueue queue.UniqueQueue
+
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		repoID := datum.(int64)
+		if err := index(repoID); err != nil {
+			log.Error("dependency analyzer index(%d) failed: %v", repoID, err)
+		}
+	}
+}
+
+// Init initialize the dependency analyzer
+func Init() error {
+	dependencyQueue = queue.CreateUniqueQueue("repo_dependency_update", handle, int64(0)).(queue.UniqueQueue)
+	if dependencyQueue == nil {
+		return fmt.Errorf("Unable to create repo_dependency_update Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(dependencyQueue.Run)
+
+	go populateRepoIndexer()
+
+	return nil
+}
+
+// UpdateRepoIndexer queues the update of the dependencies of a repository
+func UpdateRepoIndexer(repo *models.Repository) error {
+	if err := dependencyQueue.Push(repo.ID); err != nil {
+		if err != queue.ErrAlreadyInQueue {
+			return err
+		}
+		log.Debug("Repo ID: %d already queued", repo.ID)
+	}
+	return nil
+}
+
+// populateRepoIndexer queues the repositories whose dependencies were never analyzed
+func populateRepoIndexer() {
+	isShutdown := graceful.GetManager().IsShutdown()
+
+	exist, err := db.IsTableNotEmpty("repository")
+	if err != nil {
+		log.Error("populateRepoIndexer: %v", err)
+		return
+	} else if !exist {
+		return
+	}
+
+	var maxRepoID int64
+	if maxRepoID, err = db.GetMaxID("repository"); err != nil {
+		log.Error("populateRepoIndexer: %v", err)
+		return
+	}
+
+	for maxRepoID > 0 {
+		select {
+		case <-isShutdown:
+			log.Info("Repository Dependency Analyzer population shutdown before completion")
+			return
+		default:
+		}
+		ids, err := models.GetUnindexedRepos(models.RepoIndexerTypeDependencies, maxRepoID, 0, 50)
+		if err != nil {
+			log.Error("populateRepoIndexer: %v", err)
+			return
+		} else if len(ids) == 0 {
+			break
+		}
+		for _, id := range ids {
+			if err := dependencyQueue.Push(id); err != nil && err != queue.ErrAlreadyInQueue {
+				log.Error("dependencyQueue.Push: %v", err)
+			}
+			maxRepoID = id - 1
+		}
+	}
+	log.Debug("Done populating the repository dependency analyzer")
+}
+
+func index(repoID int64) error {
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if repo.IsEmpty {
+		return nil
+	}
+
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeDependencies)
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		if git.IsErrBranchNotExist(err) || git.IsErrNotExist(err) {
+			log.Debug("Unable to get commit ID for default branch %s in %s ... skipping this repository", repo.DefaultBranch, repo.RepoPath())
+			return nil
+		}
+		return err
+	}
+
+	// Do not analyze the dependencies again if the default branch did not move
+	commitID := commit.ID.String()
+	if status.CommitSha == commitID {
+		return nil
+	}
+
+	deps, err := extractDependencies(commit)
+	if err != nil {
+		return err
+	}
+	if err := repo.ReplaceRepoDependencies(commitID, deps); err != nil {
+		return err
+	}
+
+	log.Debug("Dependency analyzer found %d dependencies of %s at %s", len(deps), repo.FullName(), commitID)
+	return nil
+}
+
+// extractDependencies parses the supported manifests of the commit, vendored files are skipped
+func extractDependencies(commit *git.Commit) ([]*models.RepoDependency, error) {
+	entries, err := commit.Tree.ListEntriesRecursive()
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []*models.RepoDependency
+	for _, entry := range entries {
+		if !entry.IsRegular() || entry.Size() > maxManifestSize {
+			continue
+		}
+		filePath := entry.Name()
+		parse := getManifestParser(filePath)
+		if parse == nil || analyze.IsVendor(filePath) {
+			continue
+		}
+
+		content, err := readBlob(entry.Blob())
+		if err != nil {
+			return nil, err
+		}
+		manifestDeps, err := parse(filePath, content)
+		if err != nil {
+			// a broken manifest must not stop the analysis of the others
+			log.Debug("Unable to parse manifest %s at %s: %v", filePath, commit.ID, err)
+			continue
+		}
+		deps = append(deps, manifestDeps...)
+	}
+	return deps, nil
+}
+
+func readBlob(blob *git.Blob) ([]byte, error) {
+	reader, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,202 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package dependency
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// manifestParser extracts the dependencies declared by a manifest
+type manifestParser func(manifest string, content []byte) ([]*models.RepoDependency, error)
+
+// getManifestParser returns the parser of the manifest at the path, nil if the file is not a supported manifest
+func getManifestParser(filePath string) manifestParser {
+	name := path.Base(filePath)
+	switch {
+	case name == "go.mod":
+		return parseGoMod
+	case name == "package.json":
+		return parsePackageJSON
+	case name == "composer.json":
+		return parseComposerJSON
+	case strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt"),
+		strings.HasSuffix(name, "-requirements.txt"):
+		return parseRequirements
+	}
+	return nil
+}
+
+// parseGoMod parses the require directives of a go.mod file
+func parseGoMod(manifest string, content []byte) ([]*models.RepoDependency, error) {
+	var deps []*models.RepoDependency
+	inRequireBlock := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		var comment string
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			comment = strings.TrimSpace(line[idx+2:])
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		if inRequireBlock {
+			if line == ")" {
+				inRequireBlock = false
+				continue
+			}
+		} else {
+			fields := strings.Fields(line)
+			if len(fields) == 0 || fields[0] != "require" {
+				continue
+			}
+			if len(fields) == 2 && fields[1] == "(" {
+				inRequireBlock = true
+				continue
+			}
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require"))
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		scope := models.DependencyScopeRuntime
+		if comment == "indirect" || strings.HasPrefix(comment, "indirect;") {
+			scope = models.DependencyScopeIndirect
+		}
+		deps = append(deps, &models.RepoDependency{
+			Manifest:  manifest,
+			Ecosystem: models.DependencyEcosystemGo,
+			Name:      strings.Trim(fields[0], `"`),
+			Version:   fields[1],
+			Scope:     scope,
+		})
+	}
+	return deps, scanner.Err()
+}
+
+type packageJSON struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// parsePackageJSON parses the dependencies of a package.json file of npm
+func parsePackageJSON(manifest string, content []byte) ([]*models.RepoDependency, error) {
+	var pkg packageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+
+	var deps []*models.RepoDependency
+	deps = appendDependencies(deps, manifest, models.DependencyEcosystemNpm, models.DependencyScopeRuntime, pkg.Dependencies)
+	deps = appendDependencies(deps, manifest, models.DependencyEcosystemNpm, models.DependencyScopeRuntime, pkg.OptionalDependencies)
+	deps = appendDependencies(deps, manifest, models.DependencyEcosystemNpm, models.DependencyScopeDevelopment, pkg.DevDependencies)
+	return deps, nil
+}
+
+type composerJSON struct {
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// parseComposerJSON parses the requirements of a composer.json file, the platform requirements are skipped
+func parseComposerJSON(manifest string, content []byte) ([]*models.RepoDependency, error) {
+	var pkg composerJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+
+	isPlatform := func(name string) bool {
+		return !strings.Contains(name, "/")
+	}
+	for name := range pkg.Require {
+		if isPlatform(name) {
+			delete(pkg.Require, name)
+		}
+	}
+	for name := range pkg.RequireDev {
+		if isPlatform(name) {
+			delete(pkg.RequireDev, name)
+		}
+	}
+
+	var deps []*models.RepoDependency
+	deps = appendDependencies(deps, manifest, models.DependencyEcosystemComposer, models.DependencyScopeRuntime, pkg.Require)
+	deps = appendDependencies(deps, manifest, models.DependencyEcosystemComposer, models.DependencyScopeDevelopment, pkg.RequireDev)
+	return deps, nil
+}
+
+// appendDependencies appends the dependencies of a name to version map sorted by name
+func appendDependencies(deps []*models.RepoDependency, manifest, ecosystem, scope string, versions map[string]string) []*models.RepoDependency {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		deps = append(deps, &models.RepoDependency{
+			Manifest:  manifest,
+			Ecosystem: ecosystem,
+			Name:      name,
+			Version:   versions[name],
+			Scope:     scope,
+		})
+	}
+	return deps
+}
+
+// parseRequirements parses a requirements file of pip, options, urls and includes of other files are skipped
+func parseRequirements(manifest string, content []byte) ([]*models.RepoDependency, error) {
+	scope := models.DependencyScopeRuntime
+	if name := path.Base(manifest); strings.Contains(name, "dev") || strings.Contains(name, "test") {
+		scope = models.DependencyScopeDevelopment
+	}
+
+	var deps []*models.RepoDependency
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		// environment markers
+		if idx := strings.Index(line, ";"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+
+		name, version := line, ""
+		if idx := strings.IndexAny(line, "=<>!~ "); idx >= 0 {
+			name, version = line[:idx], strings.TrimSpace(line[idx:])
+		}
+		// extras
+		if idx := strings.Index(name, "["); idx >= 0 {
+			name = name[:idx]
+		}
+		if strings.HasPrefix(version, "==") && !strings.HasPrefix(version, "===") && !strings.Contains(version, ",") {
+			version = strings.TrimSpace(version[2:])
+		}
+		deps = append(deps, &models.RepoDependency{
+			Manifest:  manifest,
+			Ecosystem: models.DependencyEcosystemPyPI,
+			Name:      name,
+			Version:   version,
+			Scope:     scope,
+		})
+	}
+	return deps, scanner.Err()
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package dependency
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifests(t *testing.T) {
+	kases := []struct {
+		path     string
+		content  string
+		expected []*models.RepoDependency
+	}{
+		{
+			path: "go.mod",
+			content: `module code.gitea.io/gitea
+
+go 1.16
+
+require github.com/stretchr/testify v1.7.0
+
+require (
+	gitea.com/go-chi/session v0.0.0-20210108030337-0cb48c5ba8ee
+	golang.org/x/sys v0.0.0-20210630005230-0f9fa26af87c // indirect
+)
+
+replace github.com/hashicorp/go-version => github.com/6543/go-version v1.3.1
+`,
+			expected: []*models.RepoDependency{
+				{Manifest: "go.mod", Ecosystem: "go", Name: "github.com/stretchr/testify", Version: "v1.7.0", Scope: "runtime"},
+				{Manifest: "go.mod", Ecosystem: "go", Name: "gitea.com/go-chi/session", Version: "v0.0.0-20210108030337-0cb48c5ba8ee", Scope: "runtime"},
+				{Manifest: "go.mod", Ecosystem: "go", Name: "golang.org/x/sys", Version: "v0.0.0-20210630005230-0f9fa26af87c", Scope: "indirect"},
+			},
+		},
+		{
+			path:    "web_src/package.json",
+			content: `{"name": "gitea", "dependencies": {"vue": "2.6.14", "@claviska/jquery-minicolors": "^2.3.5"}, "devDependencies": {"eslint": "7.32.0"}}`,
+			expected: []*models.RepoDependency{
+				{Manifest: "web_src/package.json", Ecosystem: "npm", Name: "@claviska/jquery-minicolors", Version: "^2.3.5", Scope: "runtime"},
+				{Manifest: "web_src/package.json", Ecosystem: "npm", Name: "vue", Version: "2.6.14", Scope: "runtime"},
+				{Manifest: "web_src/package.json", Ecosystem: "npm", Name: "eslint", Version: "7.32.0", Scope: "development"},
+			},
+		},
+		{
+			path:    "composer.json",
+			content: `{"require": {"php": ">=7.4", "ext-json": "*", "monolog/monolog": "2.3.5"}, "require-dev": {"phpunit/phpunit": "^9.5"}}`,
+			expected: []*models.RepoDependency{
+				{Manifest: "composer.json", Ecosystem: "composer", Name: "monolog/monolog", Version: "2.3.5", Scope: "runtime"},
+				{Manifest: "composer.json", Ecosystem: "composer", Name: "phpunit/phpunit", Version: "^9.5", Scope: "development"},
+			},
+		},
+		{
+			path: "docs/requirements-dev.txt",
+			content: `# docs
+-r requirements.txt
+mkdocs==1.2.3
+requests[security] >= 2.8.1, < 3 ; python_version < "3.8"
+git+https://github.com/psf/black#egg=black
+pytest
+`,
+			expected: []*models.RepoDependency{
+				{Manifest: "docs/requirements-dev.txt", Ecosystem: "pypi", Name: "mkdocs", Version: "1.2.3", Scope: "development"},
+				{Manifest: "docs/requirements-dev.txt", Ecosystem: "pypi", Name: "requests", Version: ">= 2.8.1, < 3", Scope: "development"},
+				{Manifest: "docs/requirements-dev.txt", Ecosystem: "pypi", Name: "pytest", Version: "", Scope: "development"},
+			},
+		},
+	}
+
+	for _, kase := range kases {
+		parse := getManifestParser(kase.path)
+		if !assert.NotNil(t, parse, kase.path) {
+			continue
+		}
+		deps, err := parse(kase.path, []byte(kase.content))
+		assert.NoError(t, err, kase.path)
+		assert.Equal(t, kase.expected, deps, kase.path)
+	}
+
+	assert.Nil(t, getManifestParser("README.md"))
+	assert.Nil(t, getManifestParser("go.sum"))
+}
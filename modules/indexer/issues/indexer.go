@@ -100,7 +100,7 @@ func InitIssueIndexer(syncReindex bool) {
 
 	// Create the Queue
 	switch setting.Indexer.IssueType {
-	case "bleve", "elasticsearch":
+	case "bleve", "elasticsearch", "meilisearch":
 		handler := func(data ...queue.Data) {
 			indexer := holder.get()
 			if indexer == nil {
@@ -116,6 +116,7 @@ func InitIssueIndexer(syncReindex bool) {
 					continue
 				}
 				log.Trace("IndexerData Process: %d %v %t", indexerData.ID, indexerData.IDs, indexerData.IsDelete)
+				stats.processed()
 				if indexerData.IsDelete {
 					_ = indexer.Delete(indexerData.IDs...)
 					continue
@@ -182,6 +183,19 @@ func InitIssueIndexer(syncReindex bool) {
 				populate = !exist
 				holder.set(issueIndexer)
 			})
+		case "meilisearch":
+			issueIndexer, err := NewMeilisearchIndexer(setting.Indexer.IssueConnStr, setting.Indexer.IssueIndexerName)
+			if err != nil {
+				holder.cancel()
+				log.Fatal("Unable to initialize Meilisearch Issue Indexer at connection: %s Error: %v", setting.Indexer.IssueConnStr, err)
+			}
+			exist, err := issueIndexer.Init()
+			if err != nil {
+				holder.cancel()
+				log.Fatal("Unable to issueIndexer.Init with connection %s Error: %v", setting.Indexer.IssueConnStr, err)
+			}
+			populate = !exist
+			holder.set(issueIndexer)
 		case "db":
 			issueIndexer := &DBIndexer{}
 			holder.set(issueIndexer)
@@ -303,9 +317,7 @@ func UpdateIssueIndexer(issue *models.Issue) {
 		Comments: comments,
 	}
 	log.Debug("Adding to channel: %v", indexerData)
-	if err := issueIndexerQueue.Push(indexerData); err != nil {
-		log.Error("Unable to push to issue indexer: %v: Error: %v", indexerData, err)
-	}
+	pushIndexerData(indexerData)
 }
 
 // DeleteRepoIssueIndexer deletes repo's all issues indexes
@@ -320,12 +332,19 @@ func DeleteRepoIssueIndexer(repo *models.Repository) {
 	if len(ids) == 0 {
 		return
 	}
-	indexerData := &IndexerData{
+	pushIndexerData(&IndexerData{
 		IDs:      ids,
 		IsDelete: true,
-	}
+	})
+}
+
+func pushIndexerData(indexerData *IndexerData) {
 	if err := issueIndexerQueue.Push(indexerData); err != nil {
 		log.Error("Unable to push to issue indexer: %v: Error: %v", indexerData, err)
+		return
+	}
+	if _, ok := issueIndexerQueue.(*queue.DummyQueue); !ok {
+		stats.queued()
 	}
 }
 
@@ -0,0 +1,242 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issues
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/json"
+)
+
+var (
+	_ Indexer = &MeilisearchIndexer{}
+)
+
+// MeilisearchIndexer implements Indexer interface using the HTTP API of Meilisearch
+type MeilisearchIndexer struct {
+	client      *http.Client
+	url         string
+	apiKey      string
+	indexerName string
+}
+
+type meilisearchDocument struct {
+	ID       int64    `json:"id"`
+	RepoID   int64    `json:"repo_id"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Comments []string `json:"comments"`
+}
+
+type meilisearchCreateIndex struct {
+	UID        string `json:"uid"`
+	PrimaryKey string `json:"primaryKey"`
+}
+
+type meilisearchSettings struct {
+	SearchableAttributes []string `json:"searchableAttributes"`
+	FilterableAttributes []string `json:"filterableAttributes"`
+}
+
+type meilisearchSearchRequest struct {
+	Query                string     `json:"q"`
+	Filter               [][]string `json:"filter,omitempty"`
+	Limit                int        `json:"limit"`
+	Offset               int        `json:"offset"`
+	AttributesToRetrieve []string   `json:"attributesToRetrieve"`
+}
+
+type meilisearchSearchResponse struct {
+	Hits []struct {
+		ID int64 `json:"id"`
+	} `json:"hits"`
+	EstimatedTotalHits int64 `json:"estimatedTotalHits"`
+	// NbHits is the total of versions before 0.28
+	NbHits int64 `json:"nbHits"`
+}
+
+type meilisearchError struct {
+	Message string `json:"message"`
+}
+
+// NewMeilisearchIndexer creates a new meilisearch indexer, the API key may be given as password of the url
+func NewMeilisearchIndexer(connStr, indexerName string) (*MeilisearchIndexer, error) {
+	u, err := url.Parse(connStr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid meilisearch url %q", connStr)
+	}
+
+	var apiKey string
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			apiKey = password
+		} else {
+			apiKey = u.User.Username()
+		}
+		u.User = nil
+	}
+
+	return &MeilisearchIndexer{
+		client:      &http.Client{Timeout: 30 * time.Second},
+		url:         strings.TrimSuffix(u.String(), "/"),
+		apiKey:      apiKey,
+		indexerName: indexerName,
+	}, nil
+}
+
+// do sends a request to meilisearch and decodes the response into result if it is not nil,
+// it returns whether the requested resource exists
+func (b *MeilisearchIndexer) do(method, path string, body, result interface{}) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return false, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, b.url+path, reader)
+	if err != nil {
+		return false, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode >= 300 {
+		var e meilisearchError
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return false, fmt.Errorf("meilisearch %s %s: %s %s", method, path, resp.Status, e.Message)
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (b *MeilisearchIndexer) indexPath() string {
+	return "/indexes/" + url.PathEscape(b.indexerName)
+}
+
+// Init will initialize the indexer
+func (b *MeilisearchIndexer) Init() (bool, error) {
+	exists, err := b.do(http.MethodGet, b.indexPath(), nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if !exists {
+		if _, err := b.do(http.MethodPost, "/indexes", &meilisearchCreateIndex{
+			UID:        b.indexerName,
+			PrimaryKey: "id",
+		}, nil); err != nil {
+			return false, err
+		}
+	}
+
+	// the settings are applied asynchronously by meilisearch, they are updated on every start
+	// so that an index created by an older version gets them too
+	_, err = b.do(http.MethodPatch, b.indexPath()+"/settings", &meilisearchSettings{
+		SearchableAttributes: []string{"title", "content", "comments"},
+		FilterableAttributes: []string{"repo_id"},
+	}, nil)
+	return exists, err
+}
+
+// Index will save the index data
+func (b *MeilisearchIndexer) Index(issues []*IndexerData) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	docs := make([]*meilisearchDocument, 0, len(issues))
+	for _, issue := range issues {
+		docs = append(docs, &meilisearchDocument{
+			ID:       issue.ID,
+			RepoID:   issue.RepoID,
+			Title:    issue.Title,
+			Content:  issue.Content,
+			Comments: issue.Comments,
+		})
+	}
+	_, err := b.do(http.MethodPost, b.indexPath()+"/documents", docs, nil)
+	return err
+}
+
+// Delete deletes indexes by ids
+func (b *MeilisearchIndexer) Delete(ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := b.do(http.MethodPost, b.indexPath()+"/documents/delete-batch", ids, nil)
+	return err
+}
+
+// Search searches for issues by given conditions.
+// Returns the matching issue IDs
+func (b *MeilisearchIndexer) Search(keyword string, repoIDs []int64, limit, start int) (*SearchResult, error) {
+	req := &meilisearchSearchRequest{
+		Query:                keyword,
+		Limit:                limit,
+		Offset:               start,
+		AttributesToRetrieve: []string{"id"},
+	}
+	if len(repoIDs) > 0 {
+		// the inner array of a filter is a disjunction
+		repoFilter := make([]string, 0, len(repoIDs))
+		for _, repoID := range repoIDs {
+			repoFilter = append(repoFilter, fmt.Sprintf("repo_id = %d", repoID))
+		}
+		req.Filter = [][]string{repoFilter}
+	}
+
+	var resp meilisearchSearchResponse
+	if _, err := b.do(http.MethodPost, b.indexPath()+"/search", req, &resp); err != nil {
+		return nil, err
+	}
+
+	hits := make([]Match, 0, len(resp.Hits))
+	for _, hit := range resp.Hits {
+		hits = append(hits, Match{
+			ID: hit.ID,
+		})
+	}
+
+	total := resp.EstimatedTotalHits
+	if total == 0 {
+		total = resp.NbHits
+	}
+	return &SearchResult{
+		Total: total,
+		Hits:  hits,
+	}, nil
+}
+
+// Close implements indexer
+func (b *MeilisearchIndexer) Close() {}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issues
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeilisearchIndexer(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer masterKey", r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /indexes/gitea_issues":
+			w.WriteHeader(http.StatusNotFound)
+		case "POST /indexes/gitea_issues/search":
+			_, _ = w.Write([]byte(`{"hits":[{"id":2},{"id":5}],"estimatedTotalHits":7}`))
+		default:
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"taskUid":1}`))
+		}
+	}))
+	defer server.Close()
+
+	indexer, err := NewMeilisearchIndexer(strings.Replace(server.URL, "http://", "http://:masterKey@", 1), "gitea_issues")
+	assert.NoError(t, err)
+
+	exist, err := indexer.Init()
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	assert.Equal(t, []string{
+		"GET /indexes/gitea_issues ",
+		`POST /indexes {"uid":"gitea_issues","primaryKey":"id"}`,
+		`PATCH /indexes/gitea_issues/settings {"searchableAttributes":["title","content","comments"],"filterableAttributes":["repo_id"]}`,
+	}, requests)
+
+	requests = nil
+	assert.NoError(t, indexer.Index([]*IndexerData{{ID: 2, RepoID: 1, Title: "issue2", Comments: []string{"good"}}}))
+	assert.NoError(t, indexer.Delete(3, 4))
+	assert.Equal(t, []string{
+		`POST /indexes/gitea_issues/documents [{"id":2,"repo_id":1,"title":"issue2","content":"","comments":["good"]}]`,
+		`POST /indexes/gitea_issues/documents/delete-batch [3,4]`,
+	}, requests)
+
+	requests = nil
+	result, err := indexer.Search("issue", []int64{1, 2}, 50, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, result.Total)
+	assert.Equal(t, []Match{{ID: 2}, {ID: 5}}, result.Hits)
+	assert.Equal(t, []string{
+		`POST /indexes/gitea_issues/search {"q":"issue","filter":[["repo_id = 1","repo_id = 2"]],"limit":50,"offset":0,"attributesToRetrieve":["id"]}`,
+	}, requests)
+}
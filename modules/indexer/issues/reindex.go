@@ -0,0 +1,261 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+var (
+	// ErrReindexRunning is returned if a reindex or a switch of the indexer is already running
+	ErrReindexRunning = errors.New("issue indexer is already being reindexed")
+	// ErrIndexerInUse is returned if the indexer to switch to is the current one
+	ErrIndexerInUse = errors.New("the issue indexer is already in use")
+	// ErrIndexerNotQueued is returned if the indexer can not be switched because the current one does not use a queue
+	ErrIndexerNotQueued = errors.New("the db issue indexer can not be switched without a restart")
+)
+
+// indexerStats counts the data pushed to and processed from the queue of the indexer,
+// their difference is the number of updates not yet indexed
+type indexerStats struct {
+	queuedCount     int64
+	processedCount  int64
+	lastIndexedUnix int64
+}
+
+var stats indexerStats
+
+func (s *indexerStats) queued() {
+	atomic.AddInt64(&s.queuedCount, 1)
+}
+
+func (s *indexerStats) processed() {
+	atomic.AddInt64(&s.processedCount, 1)
+	atomic.StoreInt64(&s.lastIndexedUnix, time.Now().Unix())
+}
+
+// pending returns the number of updates not yet indexed,
+// updates left in a persistent queue by a previous run are not counted
+func (s *indexerStats) pending() int64 {
+	pending := atomic.LoadInt64(&s.queuedCount) - atomic.LoadInt64(&s.processedCount)
+	if pending < 0 {
+		return 0
+	}
+	return pending
+}
+
+// reindexState is the reindex or switch of the indexer which is running
+type reindexState struct {
+	mutex       sync.Mutex
+	running     bool
+	switchingTo string
+}
+
+var reindexing reindexState
+
+func (r *reindexState) start(switchingTo string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.running {
+		return ErrReindexRunning
+	}
+	r.running = true
+	r.switchingTo = switchingTo
+	return nil
+}
+
+func (r *reindexState) finish() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.running = false
+	r.switchingTo = ""
+}
+
+// Status is the state of the issue indexer
+type Status struct {
+	Type string
+	// Pending is the number of issue updates waiting to be indexed
+	Pending     int64
+	LastIndexed timeutil.TimeStamp
+	Reindexing  bool
+	// SwitchingTo is the type of the indexer being populated to replace the current one
+	SwitchingTo string
+}
+
+// GetStatus returns the state of the issue indexer
+func GetStatus() *Status {
+	reindexing.mutex.Lock()
+	defer reindexing.mutex.Unlock()
+	return &Status{
+		Type:        setting.Indexer.IssueType,
+		Pending:     stats.pending(),
+		LastIndexed: timeutil.TimeStamp(atomic.LoadInt64(&stats.lastIndexedUnix)),
+		Reindexing:  reindexing.running,
+		SwitchingTo: reindexing.switchingTo,
+	}
+}
+
+// ReindexAll adds all issues to the indexer in the background
+func ReindexAll() error {
+	if err := reindexing.start(""); err != nil {
+		return err
+	}
+	go graceful.GetManager().RunWithShutdownContext(func(ctx context.Context) {
+		defer reindexing.finish()
+		populateIssueIndexer(ctx)
+	})
+	return nil
+}
+
+// ReindexRepo adds all issues of the repository to the indexer in the background
+func ReindexRepo(repo *models.Repository) {
+	go UpdateRepoIndexer(repo)
+}
+
+// SwitchIndexer replaces the issue indexer by a new one without downtime. Until the new indexer is populated,
+// updates are written to both indexers and searches are answered by the current one.
+// The settings are only changed in memory, they have to be changed in the config file to be kept after a restart.
+func SwitchIndexer(tp, path, connStr, indexerName string) error {
+	if _, ok := issueIndexerQueue.(*queue.DummyQueue); ok {
+		return ErrIndexerNotQueued
+	}
+	if tp == setting.Indexer.IssueType && path == setting.Indexer.IssuePath &&
+		connStr == setting.Indexer.IssueConnStr && indexerName == setting.Indexer.IssueIndexerName {
+		return ErrIndexerInUse
+	}
+	if err := reindexing.start(tp); err != nil {
+		return err
+	}
+
+	next, err := newIndexer(tp, path, connStr, indexerName)
+	if err != nil {
+		reindexing.finish()
+		return err
+	}
+	current := holder.get()
+	if current == nil {
+		reindexing.finish()
+		next.Close()
+		return fmt.Errorf("unable to get issue indexer")
+	}
+	holder.set(&dualIndexer{current: current, next: next})
+
+	go graceful.GetManager().RunWithShutdownContext(func(ctx context.Context) {
+		defer reindexing.finish()
+		populateIssueIndexer(ctx)
+		if !waitForQueue(ctx) {
+			log.Warn("Issue Indexer switch to %s shutdown before completion", tp)
+			return
+		}
+
+		holder.set(next)
+		current.Close()
+		setting.Indexer.IssueType = tp
+		setting.Indexer.IssuePath = path
+		setting.Indexer.IssueConnStr = connStr
+		setting.Indexer.IssueIndexerName = indexerName
+		log.Info("Issue Indexer switched to %s", tp)
+	})
+	return nil
+}
+
+// waitForQueue waits until all the queued updates are indexed, it returns false if ctx is done before
+func waitForQueue(ctx context.Context) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for stats.pending() > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+// newIndexer creates and initializes an issue indexer of the type
+func newIndexer(tp, path, connStr, indexerName string) (Indexer, error) {
+	var indexer Indexer
+	switch tp {
+	case "bleve":
+		indexer = NewBleveIndexer(path)
+	case "elasticsearch":
+		esIndexer, err := NewElasticSearchIndexer(connStr, indexerName)
+		if err != nil {
+			return nil, err
+		}
+		indexer = esIndexer
+	case "meilisearch":
+		msIndexer, err := NewMeilisearchIndexer(connStr, indexerName)
+		if err != nil {
+			return nil, err
+		}
+		indexer = msIndexer
+	case "db":
+		indexer = &DBIndexer{}
+	default:
+		return nil, fmt.Errorf("unknown issue indexer type: %s", tp)
+	}
+	if _, err := indexer.Init(); err != nil {
+		return nil, err
+	}
+	return indexer, nil
+}
+
+// dualIndexer writes to the current and the next indexer while the next indexer is populated
+type dualIndexer struct {
+	current Indexer
+	next    Indexer
+}
+
+var (
+	_ Indexer = &dualIndexer{}
+)
+
+// Init is not needed as both indexers are initialized
+func (d *dualIndexer) Init() (bool, error) {
+	return true, nil
+}
+
+// Index indexes the issues in both indexers
+func (d *dualIndexer) Index(issues []*IndexerData) error {
+	err := d.current.Index(issues)
+	if nextErr := d.next.Index(issues); nextErr != nil {
+		log.Error("Error whilst indexing in the next issue indexer: %v", nextErr)
+	}
+	return err
+}
+
+// Delete deletes the issues from both indexers
+func (d *dualIndexer) Delete(ids ...int64) error {
+	err := d.current.Delete(ids...)
+	if nextErr := d.next.Delete(ids...); nextErr != nil {
+		log.Error("Error whilst deleting from the next issue indexer: %v", nextErr)
+	}
+	return err
+}
+
+// Search searches the current indexer
+func (d *dualIndexer) Search(kw string, repoIDs []int64, limit, start int) (*SearchResult, error) {
+	return d.current.Search(kw, repoIDs, limit, start)
+}
+
+// Close closes both indexers
+func (d *dualIndexer) Close() {
+	d.current.Close()
+	d.next.Close()
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbol
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/analyze"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ctagsTimeout limits the time ctags may take to process a single commit
+const ctagsTimeout = 10 * time.Minute
+
+// ctagsTag is a tag as printed by universal-ctags with --output-format=json
+type ctagsTag struct {
+	Type      string `json:"_type"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Kind      string `json:"kind"`
+	Language  string `json:"language"`
+	Scope     string `json:"scope"`
+	Signature string `json:"signature"`
+}
+
+// extractSymbols runs ctags over the files of the given commit
+func extractSymbols(ctx context.Context, repo *git.Repository, commitID string) ([]*models.RepoSymbol, error) {
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "gitea-symbols")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractFiles(ctx, repo, commitID, tmpDir); err != nil {
+		return nil, err
+	}
+
+	// --options=NONE has to come first, it keeps ctags from loading option files like .ctags.d of the repository
+	stdout, stderr, err := process.GetManager().ExecDir(ctagsTimeout, tmpDir,
+		fmt.Sprintf("extractSymbols: %s %s", repo.Path, commitID),
+		setting.Indexer.CtagsPath, "--options=NONE", "--output-format=json", "--fields=+nKlS", "--recurse", "-f", "-")
+	if err != nil {
+		return nil, fmt.Errorf("ctags: %v - %s", err, stderr)
+	}
+	return parseCtags(strings.NewReader(stdout))
+}
+
+// extractFiles writes the files of a commit which should be indexed into dir
+func extractFiles(ctx context.Context, repo *git.Repository, commitID, dir string) error {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		_ = pw.CloseWithError(repo.CreateArchive(ctx, git.TARGZ, pw, false, commitID))
+	}()
+
+	gz, err := gzip.NewReader(pr)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg || !shouldIndexFile(hdr.Name, hdr.Size) {
+			continue
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+hdr.Name)))
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		f, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func shouldIndexFile(name string, size int64) bool {
+	if size > setting.Indexer.MaxIndexerFileSize {
+		return false
+	}
+	if setting.Indexer.ExcludeVendored && analyze.IsVendor(name) {
+		return false
+	}
+	return !analyze.IsGenerated(name)
+}
+
+// parseCtags parses the JSON lines output of universal-ctags
+func parseCtags(r io.Reader) ([]*models.RepoSymbol, error) {
+	symbols := make([]*models.RepoSymbol, 0, 100)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var tag ctagsTag
+		if err := json.Unmarshal(line, &tag); err != nil {
+			return nil, fmt.Errorf("unable to parse ctags output %q: %v", line, err)
+		}
+		if tag.Type != "tag" || tag.Name == "" || tag.Line <= 0 {
+			continue
+		}
+		symbols = append(symbols, &models.RepoSymbol{
+			Name:      tag.Name,
+			Kind:      tag.Kind,
+			Language:  tag.Language,
+			Path:      filepath.ToSlash(strings.TrimPrefix(tag.Path, "./")),
+			Line:      tag.Line,
+			Scope:     tag.Scope,
+			Signature: tag.Signature,
+		})
+	}
+	return symbols, scanner.Err()
+}
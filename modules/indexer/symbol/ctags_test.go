@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbol
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCtags(t *testing.T) {
+	output := `{"_type": "ptag", "name": "JSON_OUTPUT_VERSION", "path": "0.0", "pattern": "in development"}
+{"_type": "tag", "name": "Server", "path": "./server/server.go", "pattern": "/^type Server struct {$/", "language": "Go", "line": 10, "kind": "struct"}
+{"_type": "tag", "name": "Start", "path": "server/server.go", "pattern": "/^func (s *Server) Start(addr string) error {$/", "language": "Go", "line": 15, "kind": "method", "signature": "(addr string)", "scope": "Server", "scopeKind": "struct"}
+
+{"_type": "tag", "name": "broken", "path": "main.c", "language": "C", "kind": "macro"}
+`
+	symbols, err := parseCtags(strings.NewReader(output))
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.RepoSymbol{
+		{Name: "Server", Kind: "struct", Language: "Go", Path: "server/server.go", Line: 10},
+		{Name: "Start", Kind: "method", Language: "Go", Path: "server/server.go", Line: 15, Scope: "Server", Signature: "(addr string)"},
+	}, symbols)
+
+	_, err = parseCtags(strings.NewReader("ctags: Warning: something\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbol
+
+import (
+	"fmt"
+	"os/exec"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// IndexerData represents a branch whose symbols should be indexed
+type IndexerData struct {
+	RepoID int64
+	Ref    string
+}
+
+// symbolQueue represents a queue to handle symbol index updates
+var symbolQueue queue.UniqueQueue
+
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		indexerData, ok := datum.(*IndexerData)
+		if !ok {
+			log.Error("Unable to process provided datum: %v - not possible to cast to IndexerData", datum)
+			continue
+		}
+		if err := index(indexerData.RepoID, indexerData.Ref); err != nil {
+			log.Error("symbol indexer index(%d, %s) failed: %v", indexerData.RepoID, indexerData.Ref, err)
+		}
+	}
+}
+
+// Init initialize the symbol indexer
+func Init() error {
+	if !setting.Indexer.SymbolIndexerEnabled {
+		return nil
+	}
+
+	if _, err := exec.LookPath(setting.Indexer.CtagsPath); err != nil {
+		return fmt.Errorf("the symbol indexer requires universal-ctags but %q could not be found: %v", setting.Indexer.CtagsPath, err)
+	}
+
+	symbolQueue = queue.CreateUniqueQueue("symbol_indexer", handle, new(IndexerData)).(queue.UniqueQueue)
+	if symbolQueue == nil {
+		return fmt.Errorf("Unable to create symbol_indexer Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(symbolQueue.Run)
+
+	return nil
+}
+
+// ShouldIndex returns whether the symbols of the branch are indexed
+func ShouldIndex(repo *models.Repository, branch string) bool {
+	if !setting.Indexer.SymbolIndexerEnabled {
+		return false
+	}
+	return setting.Indexer.SymbolIndexerAllBranches || branch == repo.DefaultBranch
+}
+
+// UpdateRepoIndexer queues the update of the symbols of a branch
+func UpdateRepoIndexer(repo *models.Repository, branch string) error {
+	if !ShouldIndex(repo, branch) {
+		return nil
+	}
+	if err := symbolQueue.Push(&IndexerData{RepoID: repo.ID, Ref: branch}); err != nil {
+		if err != queue.ErrAlreadyInQueue {
+			return err
+		}
+		log.Debug("Repo ID: %d Branch: %s already queued", repo.ID, branch)
+	}
+	return nil
+}
+
+// DeleteBranchFromIndexer removes the symbols of a deleted branch
+func DeleteBranchFromIndexer(repo *models.Repository, branch string) error {
+	return models.DeleteRepoSymbols(repo.ID, branch)
+}
+
+func index(repoID int64, branch string) error {
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if repo.IsEmpty {
+		return nil
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	commitID, err := gitRepo.GetBranchCommitID(branch)
+	if err != nil {
+		if git.IsErrBranchNotExist(err) || git.IsErrNotExist(err) {
+			return models.DeleteRepoSymbols(repo.ID, branch)
+		}
+		return err
+	}
+
+	status, err := models.GetRepoSymbolStatus(repo.ID, branch)
+	if err != nil {
+		return err
+	}
+	// Do not extract the symbols again if the branch did not move
+	if status != nil && status.CommitSha == commitID {
+		return nil
+	}
+
+	symbols, err := extractSymbols(graceful.GetManager().ShutdownContext(), gitRepo, commitID)
+	if err != nil {
+		return err
+	}
+	if err := models.ReplaceRepoSymbols(repo.ID, branch, commitID, symbols); err != nil {
+		return err
+	}
+
+	log.Debug("Symbol indexer extracted %d symbols of branch %s in %s at %s", len(symbols), branch, repo.FullName(), commitID)
+	return nil
+}
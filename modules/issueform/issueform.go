@@ -0,0 +1,248 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issueform
+
+import (
+	"fmt"
+	"strings"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"gopkg.in/yaml.v2"
+)
+
+// IsFormFile returns whether a file of an issue template directory is an issue form
+func IsFormFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+// ErrInvalidForm represents an invalid issue form definition
+type ErrInvalidForm struct {
+	Reason string
+}
+
+// IsErrInvalidForm checks if an error is a ErrInvalidForm.
+func IsErrInvalidForm(err error) bool {
+	_, ok := err.(ErrInvalidForm)
+	return ok
+}
+
+func (err ErrInvalidForm) Error() string {
+	return fmt.Sprintf("invalid issue form: %s", err.Reason)
+}
+
+// ErrInvalidValue represents a value which does not satisfy the field of an issue form it is submitted for
+type ErrInvalidValue struct {
+	FieldID string
+	Reason  string
+}
+
+// IsErrInvalidValue checks if an error is a ErrInvalidValue.
+func IsErrInvalidValue(err error) bool {
+	_, ok := err.(ErrInvalidValue)
+	return ok
+}
+
+func (err ErrInvalidValue) Error() string {
+	return fmt.Sprintf("invalid value of field %s: %s", err.FieldID, err.Reason)
+}
+
+// Parse parses and validates an issue form defined in YAML
+func Parse(content []byte) (*api.IssueTemplate, error) {
+	var form struct {
+		api.IssueTemplate `yaml:",inline"`
+		// forms compatible with other forges describe themselves with description instead of about
+		Description string `yaml:"description"`
+	}
+	if err := yaml.Unmarshal(content, &form); err != nil {
+		return nil, ErrInvalidForm{Reason: err.Error()}
+	}
+	it := form.IssueTemplate
+	if it.About == "" {
+		it.About = form.Description
+	}
+	if err := Validate(&it); err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
+
+// Validate checks whether an issue form is well-defined
+func Validate(it *api.IssueTemplate) error {
+	if !it.Valid() {
+		return ErrInvalidForm{Reason: "name and description are required"}
+	}
+	if !it.IsForm() {
+		return ErrInvalidForm{Reason: "body has no fields"}
+	}
+
+	ids := make(map[string]bool, len(it.Fields))
+	hasInput := false
+	for i, field := range it.Fields {
+		if field == nil {
+			return ErrInvalidForm{Reason: fmt.Sprintf("field %d is empty", i)}
+		}
+		if field.ID != "" {
+			if ids[field.ID] {
+				return ErrInvalidForm{Reason: fmt.Sprintf("field id %s is not unique", field.ID)}
+			}
+			ids[field.ID] = true
+		}
+
+		switch field.Type {
+		case api.IssueFormFieldTypeMarkdown:
+			if field.Attributes.Value == "" {
+				return ErrInvalidForm{Reason: fmt.Sprintf("markdown field %d has no value", i)}
+			}
+			continue
+		case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+		case api.IssueFormFieldTypeDropdown, api.IssueFormFieldTypeCheckboxes:
+			if len(field.Attributes.Options) == 0 {
+				return ErrInvalidForm{Reason: fmt.Sprintf("%s field %d has no options", field.Type, i)}
+			}
+			for _, option := range field.Attributes.Options {
+				if option.Label == "" {
+					return ErrInvalidForm{Reason: fmt.Sprintf("%s field %d has an option without label", field.Type, i)}
+				}
+			}
+		default:
+			return ErrInvalidForm{Reason: fmt.Sprintf("field %d has unknown type %q", i, field.Type)}
+		}
+
+		if field.ID == "" {
+			return ErrInvalidForm{Reason: fmt.Sprintf("%s field %d has no id", field.Type, i)}
+		}
+		if field.Attributes.Label == "" {
+			return ErrInvalidForm{Reason: fmt.Sprintf("field %s has no label", field.ID)}
+		}
+		hasInput = true
+	}
+	if !hasInput {
+		return ErrInvalidForm{Reason: "body has no fields which can be submitted"}
+	}
+	return nil
+}
+
+// ValidateValues checks the values submitted for the fields of an issue form and
+// returns the values of its fields without surrounding whitespace or empty values
+func ValidateValues(it *api.IssueTemplate, values map[string][]string) (map[string][]string, error) {
+	result := make(map[string][]string, len(it.Fields))
+	for _, field := range it.Fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+
+		submitted := make([]string, 0, len(values[field.ID]))
+		for _, value := range values[field.ID] {
+			if value = strings.TrimSpace(value); value != "" {
+				submitted = append(submitted, value)
+			}
+		}
+
+		switch field.Type {
+		case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+			if len(submitted) > 1 {
+				return nil, ErrInvalidValue{FieldID: field.ID, Reason: "only one value is allowed"}
+			}
+		case api.IssueFormFieldTypeDropdown, api.IssueFormFieldTypeCheckboxes:
+			if field.Type == api.IssueFormFieldTypeDropdown && !field.Attributes.Multiple && len(submitted) > 1 {
+				return nil, ErrInvalidValue{FieldID: field.ID, Reason: "only one option can be selected"}
+			}
+			selected := make(map[string]bool, len(submitted))
+			for _, value := range submitted {
+				if !hasOption(field, value) {
+					return nil, ErrInvalidValue{FieldID: field.ID, Reason: fmt.Sprintf("%q is not an option", value)}
+				}
+				selected[value] = true
+			}
+			for _, option := range field.Attributes.Options {
+				if option.Required && !selected[option.Label] {
+					return nil, ErrInvalidValue{FieldID: field.ID, Reason: fmt.Sprintf("%q has to be checked", option.Label)}
+				}
+			}
+		}
+
+		if field.Validations.Required && len(submitted) == 0 {
+			return nil, ErrInvalidValue{FieldID: field.ID, Reason: "a value is required"}
+		}
+		if len(submitted) > 0 {
+			result[field.ID] = submitted
+		}
+	}
+
+	for id := range values {
+		if _, ok := result[id]; !ok && !isInputField(it, id) {
+			return nil, ErrInvalidValue{FieldID: id, Reason: "the form has no such field"}
+		}
+	}
+	return result, nil
+}
+
+func hasOption(field *api.IssueFormField, value string) bool {
+	for _, option := range field.Attributes.Options {
+		if option.Label == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isInputField(it *api.IssueTemplate, id string) bool {
+	for _, field := range it.Fields {
+		if field.ID == id && field.Type != api.IssueFormFieldTypeMarkdown {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderBody renders the values of the fields of an issue form, as returned by ValidateValues,
+// to the markdown body of the issue: one section per field headed by its label
+func RenderBody(it *api.IssueTemplate, values map[string][]string) string {
+	var sb strings.Builder
+	for _, field := range it.Fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "### %s\n\n", field.Attributes.Label)
+
+		submitted := values[field.ID]
+		switch field.Type {
+		case api.IssueFormFieldTypeCheckboxes:
+			selected := make(map[string]bool, len(submitted))
+			for _, value := range submitted {
+				selected[value] = true
+			}
+			for i, option := range field.Attributes.Options {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				if selected[option.Label] {
+					fmt.Fprintf(&sb, "- [x] %s", option.Label)
+				} else {
+					fmt.Fprintf(&sb, "- [ ] %s", option.Label)
+				}
+			}
+		case api.IssueFormFieldTypeTextarea:
+			if len(submitted) == 0 {
+				sb.WriteString("_No response_")
+			} else if field.Attributes.Render != "" {
+				fmt.Fprintf(&sb, "```%s\n%s\n```", field.Attributes.Render, submitted[0])
+			} else {
+				sb.WriteString(submitted[0])
+			}
+		default:
+			if len(submitted) == 0 {
+				sb.WriteString("_No response_")
+			} else {
+				sb.WriteString(strings.Join(submitted, ", "))
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issueform
+
+import (
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const bugReport = `name: Bug report
+description: Report a bug
+title: "[Bug]: "
+labels: ["bug"]
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for taking the time to fill out this bug report!
+  - type: input
+    id: version
+    attributes:
+      label: Version
+    validations:
+      required: true
+  - type: textarea
+    id: logs
+    attributes:
+      label: Log output
+      render: shell
+  - type: dropdown
+    id: browsers
+    attributes:
+      label: Browsers
+      multiple: true
+      options:
+        - Firefox
+        - Chrome
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Code of Conduct
+      options:
+        - label: I agree to follow the Code of Conduct
+          required: true
+        - label: I searched for existing issues
+`
+
+func TestParse(t *testing.T) {
+	it, err := Parse([]byte(bugReport))
+	assert.NoError(t, err)
+	assert.Equal(t, "Bug report", it.Name)
+	assert.Equal(t, "Report a bug", it.About)
+	assert.Equal(t, []string{"bug"}, it.Labels)
+	assert.True(t, it.IsForm())
+	assert.Len(t, it.Fields, 5)
+	assert.Equal(t, api.IssueFormFieldTypeDropdown, it.Fields[3].Type)
+	assert.Equal(t, []api.IssueFormFieldOption{{Label: "Firefox"}, {Label: "Chrome"}}, it.Fields[3].Attributes.Options)
+	assert.Equal(t, api.IssueFormFieldOption{Label: "I agree to follow the Code of Conduct", Required: true}, it.Fields[4].Attributes.Options[0])
+
+	for _, content := range []string{
+		"name: No body\ndescription: test\n",
+		"name: Unknown type\ndescription: test\nbody:\n  - type: radio\n    id: a\n    attributes:\n      label: A\n",
+		"name: No id\ndescription: test\nbody:\n  - type: input\n    attributes:\n      label: A\n",
+		"name: Duplicate id\ndescription: test\nbody:\n  - type: input\n    id: a\n    attributes:\n      label: A\n  - type: input\n    id: a\n    attributes:\n      label: B\n",
+		"name: No options\ndescription: test\nbody:\n  - type: dropdown\n    id: a\n    attributes:\n      label: A\n",
+		"name: Only markdown\ndescription: test\nbody:\n  - type: markdown\n    attributes:\n      value: text\n",
+	} {
+		_, err := Parse([]byte(content))
+		assert.True(t, IsErrInvalidForm(err), content)
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	it, err := Parse([]byte(bugReport))
+	assert.NoError(t, err)
+
+	values, err := ValidateValues(it, map[string][]string{
+		"version":  {" 1.16.0 "},
+		"logs":     {""},
+		"browsers": {"Firefox", "Chrome"},
+		"terms":    {"I agree to follow the Code of Conduct"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"version":  {"1.16.0"},
+		"browsers": {"Firefox", "Chrome"},
+		"terms":    {"I agree to follow the Code of Conduct"},
+	}, values)
+
+	assert.Equal(t, "### Version\n\n1.16.0\n\n"+
+		"### Log output\n\n_No response_\n\n"+
+		"### Browsers\n\nFirefox, Chrome\n\n"+
+		"### Code of Conduct\n\n- [x] I agree to follow the Code of Conduct\n- [ ] I searched for existing issues", RenderBody(it, values))
+
+	for _, values := range []map[string][]string{
+		{"terms": {"I agree to follow the Code of Conduct"}},
+		{"version": {"1.16.0"}},
+		{"version": {"1.16.0", "1.15.0"}, "terms": {"I agree to follow the Code of Conduct"}},
+		{"version": {"1.16.0"}, "browsers": {"Safari"}, "terms": {"I agree to follow the Code of Conduct"}},
+		{"version": {"1.16.0"}, "terms": {"I agree to follow the Code of Conduct"}, "unknown": {"value"}},
+	} {
+		_, err := ValidateValues(it, values)
+		assert.True(t, IsErrInvalidValue(err), values)
+	}
+}
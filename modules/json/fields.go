@@ -0,0 +1,182 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// FieldSet is a set of the fields of a JSON object to keep, with the set of the fields to keep of each of them.
+// A field without a set of its own is kept whole.
+type FieldSet map[string]FieldSet
+
+// ParseFieldSet parses a comma separated list of fields, the fields of nested objects are given by their path like
+// "user.login". A field given both whole and by its nested fields is kept whole. It returns nil if the list is empty.
+func ParseFieldSet(fields string) FieldSet {
+	var fs FieldSet
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if fs == nil {
+			fs = make(FieldSet)
+		}
+		set := fs
+		names := strings.Split(field, ".")
+		for i, name := range names {
+			sub, ok := set[name]
+			if ok && len(sub) == 0 {
+				// the field is already kept whole
+				break
+			}
+			if i == len(names)-1 {
+				set[name] = FieldSet{}
+				break
+			}
+			if !ok {
+				sub = make(FieldSet)
+				set[name] = sub
+			}
+			set = sub
+		}
+	}
+	return fs
+}
+
+// Has returns true if the field is kept
+func (fs FieldSet) Has(name string) bool {
+	if fs == nil {
+		return true
+	}
+	_, ok := fs[name]
+	return ok
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Select returns a value which is encoded like v but only with the fields in the set.
+// The fields of structs are selected by their JSON names, the fields of maps by their keys, and the elements of
+// slices are selected one by one. Unknown fields are ignored. A nil set keeps all the fields.
+func (fs FieldSet) Select(v interface{}) interface{} {
+	if fs == nil {
+		return v
+	}
+	return fs.selectValue(reflect.ValueOf(v))
+}
+
+func (fs FieldSet) selectValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if len(fs) == 0 || v.Type().Implements(marshalerType) || v.Type().Implements(textMarshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return fs.selectValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		selected := make([]interface{}, v.Len())
+		for i := range selected {
+			selected[i] = fs.selectValue(v.Index(i))
+		}
+		return selected
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		selected := make(map[string]interface{}, len(fs))
+		for name, sub := range fs {
+			if value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())); value.IsValid() {
+				selected[name] = sub.selectValue(value)
+			}
+		}
+		return selected
+	case reflect.Struct:
+		selected := make(map[string]interface{}, len(fs))
+		fs.selectFields(v, selected)
+		return selected
+	}
+	return v.Interface()
+}
+
+// selectFields adds the selected fields of a struct, the fields of embedded structs are promoted like encoding/json does
+func (fs FieldSet) selectFields(v reflect.Value, selected map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx >= 0 {
+			name, options = tag[:idx], tag[idx+1:]
+		}
+
+		value := v.Field(i)
+		if field.Anonymous && name == "" {
+			embedded := value
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fs.selectFields(embedded, selected)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		sub, ok := fs[name]
+		if !ok {
+			continue
+		}
+		if strings.Contains(","+options+",", ",omitempty,") && isEmptyValue(value) {
+			continue
+		}
+		selected[name] = sub.selectValue(value)
+	}
+}
+
+// isEmptyValue returns true if encoding/json omits the value of a field with the omitempty option
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMeta struct {
+	URL string `json:"url"`
+	SHA string `json:"sha"`
+}
+
+type testUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+type testCommit struct {
+	*testMeta
+	Message string    `json:"message"`
+	Author  *testUser `json:"author"`
+	Labels  []string  `json:"labels,omitempty"`
+	Created time.Time `json:"created"`
+	secret  string
+}
+
+func TestParseFieldSet(t *testing.T) {
+	assert.Nil(t, ParseFieldSet(""))
+	assert.Nil(t, ParseFieldSet(" , "))
+	assert.Equal(t, FieldSet{"sha": {}, "author": {"login": {}}}, ParseFieldSet("sha, author.login"))
+	// a field requested whole is kept whole
+	assert.Equal(t, FieldSet{"author": {}}, ParseFieldSet("author.login,author"))
+	assert.Equal(t, FieldSet{"author": {}}, ParseFieldSet("author,author.login"))
+
+	assert.True(t, FieldSet(nil).Has("files"))
+	assert.False(t, ParseFieldSet("sha").Has("files"))
+	assert.True(t, ParseFieldSet("files").Has("files"))
+}
+
+func TestFieldSetSelect(t *testing.T) {
+	created := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	commits := []*testCommit{
+		{
+			testMeta: &testMeta{URL: "https://example.com/1", SHA: "1"},
+			Message:  "first",
+			Author:   &testUser{ID: 2, Login: "user2"},
+			Created:  created,
+			secret:   "secret",
+		},
+		{
+			testMeta: &testMeta{URL: "https://example.com/2", SHA: "2"},
+			Message:  "second",
+			Labels:   []string{"bug"},
+			Created:  created,
+		},
+	}
+
+	marshal := func(v interface{}) string {
+		data, err := Marshal(v)
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	assert.Equal(t, marshal(commits), marshal(FieldSet(nil).Select(commits)))
+	assert.Equal(t, `[{"author":{"login":"user2"},"sha":"1"},{"author":null,"sha":"2"}]`,
+		marshal(ParseFieldSet("sha,author.login,unknown").Select(commits)))
+	assert.Equal(t, `[{"created":"2021-10-01T12:00:00Z"},{"created":"2021-10-01T12:00:00Z","labels":["bug"]}]`,
+		marshal(ParseFieldSet("labels,created.unknown,secret").Select(commits)))
+	assert.Equal(t, `{"data":[{"message":"first"},{"message":"second"}],"ok":true}`,
+		marshal(FieldSet{"ok": nil, "data": ParseFieldSet("message")}.Select(map[string]interface{}{
+			"ok":   true,
+			"data": commits,
+		})))
+}
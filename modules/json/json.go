@@ -12,6 +12,9 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
+// RawMessage is a raw encoded JSON value, e.g. to delay decoding it
+type RawMessage = json.RawMessage
+
 // Encoder represents an encoder for json
 type Encoder interface {
 	Encode(v interface{}) error
@@ -0,0 +1,146 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package maintenance holds the in-memory maintenance mode state of the instance.
+// While maintenance mode is enabled or the database is read-only the instance is read-only:
+// reads are served but writes are rejected.
+package maintenance
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// DefaultRetryAfter is the delay suggested to clients when none is configured
+const DefaultRetryAfter = 5 * time.Minute
+
+// State is the maintenance mode state of the instance
+type State struct {
+	Enabled bool
+	// Message is shown to users in the banner and in rejected responses
+	Message string
+	// RetryAfter is the delay clients are asked to wait before retrying a rejected write
+	RetryAfter time.Duration
+	Since      time.Time
+}
+
+var (
+	lock  sync.RWMutex
+	state State
+	// databaseReadOnlySince is when the database was found to be read-only, it is zero while the database is writable
+	databaseReadOnlySince time.Time
+)
+
+// Get returns the current maintenance mode state
+func Get() State {
+	lock.RLock()
+	defer lock.RUnlock()
+	return state
+}
+
+// Set replaces the current maintenance mode state
+func Set(s State) {
+	if s.RetryAfter <= 0 {
+		s.RetryAfter = DefaultRetryAfter
+	}
+	lock.Lock()
+	state = s
+	lock.Unlock()
+}
+
+// IsEnabled returns true if the instance is in maintenance mode
+func IsEnabled() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return state.Enabled
+}
+
+// SetDatabaseReadOnly records whether the database refuses writes. It returns true if the state changed.
+func SetDatabaseReadOnly(readOnly bool) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	if readOnly == !databaseReadOnlySince.IsZero() {
+		return false
+	}
+	if readOnly {
+		databaseReadOnlySince = time.Now()
+	} else {
+		databaseReadOnlySince = time.Time{}
+	}
+	return true
+}
+
+// DatabaseReadOnlySince returns when the database was found to be read-only, or the zero time if it is writable
+func DatabaseReadOnlySince() time.Time {
+	lock.RLock()
+	defer lock.RUnlock()
+	return databaseReadOnlySince
+}
+
+// IsDatabaseReadOnly returns true if the database was found to be read-only
+func IsDatabaseReadOnly() bool {
+	return !DatabaseReadOnlySince().IsZero()
+}
+
+// Reasons of an ErrReadOnly
+const (
+	ReasonMaintenance      = "maintenance"
+	ReasonDatabaseReadOnly = "database_read_only"
+)
+
+// ErrReadOnly is returned when a write is attempted while the instance is in maintenance mode or its database is read-only
+type ErrReadOnly struct {
+	Message    string
+	RetryAfter time.Duration
+	Database   bool
+}
+
+// IsErrReadOnly checks if an error is a ErrReadOnly
+func IsErrReadOnly(err error) bool {
+	_, ok := err.(ErrReadOnly)
+	return ok
+}
+
+func (err ErrReadOnly) Error() string {
+	if err.Database {
+		return "database is read-only, changes can not be saved at the moment"
+	}
+	if err.Message != "" {
+		return fmt.Sprintf("instance is in maintenance mode: %s", err.Message)
+	}
+	return "instance is in maintenance mode"
+}
+
+// RetryAfterSeconds returns the value of the Retry-After header for the error
+func (err ErrReadOnly) RetryAfterSeconds() string {
+	return fmt.Sprintf("%d", int64(err.RetryAfter/time.Second))
+}
+
+// Reason returns why the instance is read-only, ReasonMaintenance or ReasonDatabaseReadOnly
+func (err ErrReadOnly) Reason() string {
+	if err.Database {
+		return ReasonDatabaseReadOnly
+	}
+	return ReasonMaintenance
+}
+
+// NewErrDatabaseReadOnly returns the ErrReadOnly of a read-only database
+func NewErrDatabaseReadOnly() ErrReadOnly {
+	return ErrReadOnly{Database: true, RetryAfter: setting.Database.ReadOnlyRetryAfter}
+}
+
+// CheckWritable returns an ErrReadOnly if the instance is in maintenance mode or its database is read-only
+func CheckWritable() error {
+	s := Get()
+	if s.Enabled {
+		return ErrReadOnly{Message: s.Message, RetryAfter: s.RetryAfter}
+	}
+	if IsDatabaseReadOnly() {
+		return NewErrDatabaseReadOnly()
+	}
+	return nil
+}
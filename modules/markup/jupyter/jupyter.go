@@ -0,0 +1,175 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package jupyter
+
+import (
+	"bufio"
+	"bytes"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/highlight"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/alecthomas/chroma/lexers"
+)
+
+func init() {
+	markup.RegisterRenderer(Renderer{})
+}
+
+// Renderer implements markup.Renderer for Jupyter notebooks
+type Renderer struct {
+}
+
+// Name implements markup.Renderer
+func (Renderer) Name() string {
+	return "jupyter"
+}
+
+// NeedPostProcess implements markup.Renderer
+func (Renderer) NeedPostProcess() bool { return false }
+
+// Extensions implements markup.Renderer
+func (Renderer) Extensions() []string {
+	return []string{".ipynb"}
+}
+
+// SanitizerRules implements markup.Renderer
+func (Renderer) SanitizerRules() []setting.MarkupSanitizerRule {
+	return []setting.MarkupSanitizerRule{
+		{Element: "div", AllowAttr: "class", Regexp: regexp.MustCompile(`^notebook(-[a-z]+)?( notebook-[a-z]+)?$`)},
+		// images displayed by code cells are embedded in the notebook
+		{AllowDataURIImages: true},
+	}
+}
+
+var languageRe = regexp.MustCompile(`^[\w-]+$`)
+
+// imageMimeTypes are the types of the images which are displayed in place of the other representations of an output
+var imageMimeTypes = []string{"image/png", "image/jpeg", "image/gif"}
+
+// Render implements markup.Renderer
+func (Renderer) Render(ctx *markup.RenderContext, input io.Reader, output io.Writer) error {
+	// FIXME: don't read all to memory
+	rawBytes, err := io.ReadAll(input)
+	if err != nil {
+		return err
+	}
+
+	var nb *Notebook
+	if setting.UI.Notebook.MaxFileSize == 0 || int64(len(rawBytes)) <= setting.UI.Notebook.MaxFileSize {
+		nb, err = Parse(bytes.NewReader(rawBytes))
+	}
+	if nb == nil || err != nil {
+		// the notebook is too large or invalid, so it is shown as it is
+		_, err = io.WriteString(output, "<pre>"+html.EscapeString(string(rawBytes))+"</pre>")
+		return err
+	}
+
+	w := bufio.NewWriter(output)
+	language := strings.ToLower(nb.Language())
+	_, _ = w.WriteString(`<div class="notebook">`)
+	for _, cell := range nb.Cells {
+		switch cell.CellType {
+		case "markdown":
+			rendered, err := markdown.RenderString(&markup.RenderContext{
+				Ctx:       ctx.Ctx,
+				URLPrefix: ctx.URLPrefix,
+				Metas:     ctx.Metas,
+				GitRepo:   ctx.GitRepo,
+			}, string(cell.Source))
+			if err != nil {
+				return err
+			}
+			_, _ = w.WriteString(`<div class="notebook-cell notebook-markdown">` + rendered + `</div>`)
+		case "code":
+			_, _ = w.WriteString(`<div class="notebook-cell notebook-code">`)
+			_, _ = w.WriteString(`<div class="notebook-prompt">In [` + executionCount(cell.ExecutionCount) + `]:</div>`)
+			_, _ = w.WriteString(highlightCode(language, string(cell.Source)))
+			for _, o := range cell.Outputs {
+				if err := writeOutput(ctx, w, o); err != nil {
+					return err
+				}
+			}
+			_, _ = w.WriteString(`</div>`)
+		default:
+			_, _ = w.WriteString(`<div class="notebook-cell notebook-raw"><pre>` + html.EscapeString(string(cell.Source)) + `</pre></div>`)
+		}
+	}
+	_, _ = w.WriteString(`</div>`)
+	return w.Flush()
+}
+
+func executionCount(count *int) string {
+	if count == nil {
+		return " "
+	}
+	return strconv.Itoa(*count)
+}
+
+func highlightCode(language, source string) string {
+	if !languageRe.MatchString(language) {
+		return `<pre><code>` + html.EscapeString(source) + `</code></pre>`
+	}
+	code := html.EscapeString(source)
+	if lexer := lexers.Get(language); lexer != nil && len(lexer.Config().Filenames) > 0 {
+		code = highlight.Code(lexer.Config().Filenames[0], source)
+	}
+	// include language-x class as part of commonmark spec
+	return `<pre><code class="chroma language-` + language + `">` + code + `</code></pre>`
+}
+
+func writeOutput(ctx *markup.RenderContext, w *bufio.Writer, o *Output) error {
+	switch o.OutputType {
+	case "stream":
+		_, _ = w.WriteString(`<div class="notebook-output"><pre>` + html.EscapeString(string(o.Text)) + `</pre></div>`)
+		return nil
+	case "error":
+		_, _ = w.WriteString(`<div class="notebook-output notebook-error"><pre>` + html.EscapeString(o.ErrorText()) + `</pre></div>`)
+		return nil
+	}
+
+	data, err := renderOutputData(ctx, o)
+	if err != nil || data == "" {
+		return err
+	}
+	_, _ = w.WriteString(`<div class="notebook-output">`)
+	if o.OutputType == "execute_result" {
+		_, _ = w.WriteString(`<div class="notebook-prompt">Out [` + executionCount(o.ExecutionCount) + `]:</div>`)
+	}
+	_, _ = w.WriteString(data + `</div>`)
+	return nil
+}
+
+// renderOutputData renders the representation of a result or displayed data which is shown best
+func renderOutputData(ctx *markup.RenderContext, o *Output) (string, error) {
+	for _, mimeType := range imageMimeTypes {
+		if data, ok := o.DataString(mimeType); ok {
+			return `<img src="data:` + mimeType + `;base64,` + html.EscapeString(strings.Join(strings.Fields(data), "")) + `">`, nil
+		}
+	}
+	if data, ok := o.DataString("text/markdown"); ok {
+		return markdown.RenderString(&markup.RenderContext{
+			Ctx:       ctx.Ctx,
+			URLPrefix: ctx.URLPrefix,
+			Metas:     ctx.Metas,
+			GitRepo:   ctx.GitRepo,
+		}, data)
+	}
+	if data, ok := o.DataString("text/html"); ok {
+		// the HTML is sanitized with the rest of the notebook
+		return data, nil
+	}
+	if data, ok := o.DataString("text/plain"); ok {
+		return `<pre>` + html.EscapeString(data) + `</pre>`, nil
+	}
+	return "", nil
+}
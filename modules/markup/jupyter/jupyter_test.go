@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package jupyter
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+const testNotebook = `{
+ "nbformat": 4,
+ "nbformat_minor": 5,
+ "metadata": {"kernelspec": {"language": "python"}},
+ "cells": [
+  {
+   "cell_type": "code",
+   "execution_count": 1,
+   "source": ["x = 1\n", "print(x <= 2)"],
+   "outputs": [
+    {"output_type": "stream", "name": "stdout", "text": ["True\n"]},
+    {"output_type": "execute_result", "execution_count": 1, "data": {"text/plain": "1"}},
+    {"output_type": "error", "ename": "ValueError", "evalue": "x", "traceback": ["\u001b[0;31mValueError\u001b[0m: x"]}
+   ]
+  },
+  {"cell_type": "raw", "source": "<b>raw</b>"}
+ ]
+}`
+
+func TestParse(t *testing.T) {
+	nb, err := Parse(strings.NewReader(testNotebook))
+	assert.NoError(t, err)
+	assert.Equal(t, "python", nb.Language())
+	assert.Len(t, nb.Cells, 2)
+	assert.Equal(t, MultilineString("x = 1\nprint(x <= 2)"), nb.Cells[0].Source)
+	assert.Len(t, nb.Cells[0].Outputs, 3)
+	assert.Equal(t, MultilineString("True\n"), nb.Cells[0].Outputs[0].Text)
+	text, ok := nb.Cells[0].Outputs[1].DataString("text/plain")
+	assert.True(t, ok)
+	assert.Equal(t, "1", text)
+	assert.Equal(t, "ValueError: x", nb.Cells[0].Outputs[2].ErrorText())
+
+	_, err = Parse(strings.NewReader(`{"nbformat": 3, "worksheets": []}`))
+	assert.Error(t, err)
+}
+
+func TestRender(t *testing.T) {
+	setting.Cfg = ini.Empty()
+	var render Renderer
+	var buf strings.Builder
+	assert.NoError(t, render.Render(&markup.RenderContext{}, strings.NewReader(testNotebook), &buf))
+	rendered := buf.String()
+	assert.Contains(t, rendered, `<div class="notebook-prompt">In [1]:</div>`)
+	assert.Contains(t, rendered, `<div class="notebook-output"><pre>True`+"\n"+`</pre></div>`)
+	assert.Contains(t, rendered, `<div class="notebook-prompt">Out [1]:</div><pre>1</pre>`)
+	assert.Contains(t, rendered, `<div class="notebook-output notebook-error"><pre>ValueError: x</pre></div>`)
+	assert.Contains(t, rendered, `<div class="notebook-cell notebook-raw"><pre>&lt;b&gt;raw&lt;/b&gt;</pre></div>`)
+
+	// notebooks too large to be rendered are shown as they are
+	defer func(size int64) {
+		setting.UI.Notebook.MaxFileSize = size
+	}(setting.UI.Notebook.MaxFileSize)
+	setting.UI.Notebook.MaxFileSize = 10
+	buf.Reset()
+	assert.NoError(t, render.Render(&markup.RenderContext{}, strings.NewReader(`{"nbformat": 4, "cells": []}`), &buf))
+	assert.Equal(t, `<pre>{&#34;nbformat&#34;: 4, &#34;cells&#34;: []}</pre>`, buf.String())
+}
@@ -0,0 +1,111 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package jupyter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Notebook represents a Jupyter notebook in the nbformat 4
+type Notebook struct {
+	NBFormat int `json:"nbformat"`
+	Metadata struct {
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+		Kernelspec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+	} `json:"metadata"`
+	Cells []*Cell `json:"cells"`
+}
+
+// Language returns the programming language of the code cells of the notebook
+func (nb *Notebook) Language() string {
+	if nb.Metadata.LanguageInfo.Name != "" {
+		return nb.Metadata.LanguageInfo.Name
+	}
+	return nb.Metadata.Kernelspec.Language
+}
+
+// Cell represents a markdown, code or raw cell of a notebook
+type Cell struct {
+	CellType       string          `json:"cell_type"`
+	Source         MultilineString `json:"source"`
+	ExecutionCount *int            `json:"execution_count"`
+	Outputs        []*Output       `json:"outputs"`
+}
+
+// Output represents a stream, execute_result, display_data or error output of a code cell
+type Output struct {
+	OutputType string `json:"output_type"`
+	// Text is the text of a stream
+	Text MultilineString `json:"text"`
+	// Data maps MIME types to the representations of a result or displayed data
+	Data           map[string]json.RawMessage `json:"data"`
+	ExecutionCount *int                       `json:"execution_count"`
+	EName          string                     `json:"ename"`
+	EValue         string                     `json:"evalue"`
+	Traceback      []string                   `json:"traceback"`
+}
+
+// ansiEscapeRe matches the color codes tracebacks are formatted with
+var ansiEscapeRe = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// DataString returns the representation of the output with the MIME type, if it is a string as those of
+// texts and base64 encoded images are
+func (o *Output) DataString(mimeType string) (string, bool) {
+	raw, ok := o.Data[mimeType]
+	if !ok {
+		return "", false
+	}
+	var s MultilineString
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", false
+	}
+	return string(s), true
+}
+
+// ErrorText returns the traceback of an error output without its color codes
+func (o *Output) ErrorText() string {
+	if len(o.Traceback) == 0 {
+		return o.EName + ": " + o.EValue
+	}
+	return ansiEscapeRe.ReplaceAllString(strings.Join(o.Traceback, "\n"), "")
+}
+
+// MultilineString is a string which a notebook may split into an array of lines
+type MultilineString string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *MultilineString) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		*s = MultilineString(str)
+		return nil
+	}
+	var lines []string
+	if err := json.Unmarshal(b, &lines); err != nil {
+		return err
+	}
+	*s = MultilineString(strings.Join(lines, ""))
+	return nil
+}
+
+// Parse parses a notebook in the nbformat 4
+func Parse(r io.Reader) (*Notebook, error) {
+	nb := new(Notebook)
+	if err := json.NewDecoder(r).Decode(nb); err != nil {
+		return nil, err
+	}
+	if nb.NBFormat < 4 {
+		return nil, fmt.Errorf("unsupported nbformat %d", nb.NBFormat)
+	}
+	return nb, nil
+}
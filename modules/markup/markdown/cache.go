@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markdown
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// RendererVersion must be increased by changes of the renderer which change the rendered HTML,
+// so that the results cached by older versions are not used anymore
+const RendererVersion = 1
+
+// renderHash returns a hash of everything the rendered HTML of the content depends on
+func renderHash(ctx *markup.RenderContext, content string) string {
+	h := sha256.New()
+	write := func(s string) {
+		_, _ = io.WriteString(h, strconv.Itoa(len(s)))
+		_, _ = io.WriteString(h, ":")
+		_, _ = io.WriteString(h, s)
+	}
+	write(strconv.Itoa(RendererVersion))
+	write(setting.AppVer)
+	write(setting.AppURL)
+	write(ctx.URLPrefix)
+	// commit SHAs are only linked if the content is rendered with the git repository
+	if ctx.GitRepo != nil {
+		write(ctx.GitRepo.Path)
+	} else {
+		write("")
+	}
+	keys := make([]string, 0, len(ctx.Metas))
+	for k := range ctx.Metas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		write(k)
+		write(ctx.Metas[k])
+	}
+	write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// RenderStringCached renders like RenderString, but caches the rendered HTML under the key.
+// The cached HTML is only used while the content, the links and metas it is rendered with
+// and the renderer are the same, callers remove the key when the content is deleted.
+func RenderStringCached(key string, ctx *markup.RenderContext, content string) (string, error) {
+	c := cache.GetCache()
+	if c == nil || setting.CacheService.TTL == 0 {
+		return RenderString(ctx, content)
+	}
+
+	hash := renderHash(ctx, content)
+	if cached, ok := c.Get(key).(string); ok && strings.HasPrefix(cached, hash+":") {
+		return cached[len(hash)+1:], nil
+	}
+
+	rendered, err := RenderString(ctx, content)
+	if err != nil {
+		return "", err
+	}
+	if err := c.Put(key, hash+":"+rendered, setting.CacheService.TTLSeconds()); err != nil {
+		log.Warn("Unable to cache the rendered content under %s: %v", key, err)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/markup"
+	. "code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderStringCached(t *testing.T) {
+	setting.AppURL = AppURL
+	setting.AppSubURL = AppSubURL
+	setting.CacheService.Cache = setting.Cache{Enabled: true, Adapter: "memory", Interval: 60, TTL: time.Minute}
+	assert.NoError(t, cache.NewContext())
+
+	ctx := &markup.RenderContext{URLPrefix: AppSubURL, Metas: localMetas}
+	rendered, err := RenderStringCached("comment_render_test", ctx, "**bold**")
+	assert.NoError(t, err)
+	assert.Equal(t, "<p><strong>bold</strong></p>\n", rendered)
+
+	// replace the cached HTML to tell whether it is used
+	cached, ok := cache.GetCache().Get("comment_render_test").(string)
+	if !assert.True(t, ok) || !assert.True(t, strings.HasSuffix(cached, ":"+rendered)) {
+		return
+	}
+	hash := cached[:strings.IndexByte(cached, ':')]
+	assert.NoError(t, cache.GetCache().Put("comment_render_test", hash+":cached", 60))
+
+	rendered, err = RenderStringCached("comment_render_test", ctx, "**bold**")
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", rendered)
+
+	// commit SHAs are only linked with a git repository, so the context with one is rendered again
+	rendered, err = RenderStringCached("comment_render_test", &markup.RenderContext{URLPrefix: AppSubURL, Metas: localMetas, GitRepo: &git.Repository{Path: "repo.git"}}, "**bold**")
+	assert.NoError(t, err)
+	assert.Equal(t, "<p><strong>bold</strong></p>\n", rendered)
+
+	// another content or context is rendered again
+	rendered, err = RenderStringCached("comment_render_test", &markup.RenderContext{URLPrefix: AppURL, Metas: localMetas}, "**bold**")
+	assert.NoError(t, err)
+	assert.Equal(t, "<p><strong>bold</strong></p>\n", rendered)
+	rendered, err = RenderStringCached("comment_render_test", ctx, "*em*")
+	assert.NoError(t, err)
+	assert.Equal(t, "<p><em>em</em></p>\n", rendered)
+}
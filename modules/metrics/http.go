@@ -0,0 +1,106 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// routeOther replaces the routes seen after the route label limit has been reached
+	routeOther = "other"
+	// routeUnmatched is the route of requests which did not match any route
+	routeUnmatched = "unmatched"
+)
+
+var requestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    namespace + "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by route",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"method", "route", "status"},
+)
+
+// routeLabels guards the cardinality of the route label
+type routeLabels struct {
+	lock   sync.RWMutex
+	routes map[string]struct{}
+}
+
+var knownRoutes = &routeLabels{routes: make(map[string]struct{})}
+
+// label returns the route itself if it is known or there is still room for it, routeOther otherwise
+func (r *routeLabels) label(route string, limit int) string {
+	r.lock.RLock()
+	_, ok := r.routes[route]
+	r.lock.RUnlock()
+	if ok {
+		return route
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.routes[route]; ok {
+		return route
+	}
+	if limit > 0 && len(r.routes) >= limit {
+		return routeOther
+	}
+	r.routes[route] = struct{}{}
+	return route
+}
+
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// statusClass returns the class of a status code like "2xx" to limit the number of label values
+func statusClass(status int) string {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// RequestDuration is a middleware observing the duration of requests by their route pattern.
+// It must be used within a chi router and after the response has been wrapped by a writer with a Status method.
+func RequestDuration(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(resp, req)
+
+		route := routeUnmatched
+		if rctx := chi.RouteContext(req.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" && pattern != "/*" {
+				route = knownRoutes.label(pattern, setting.Metrics.MaxRouteLabels)
+			}
+		}
+		method := req.Method
+		if !knownMethods[method] {
+			method = routeOther
+		}
+		status := 0
+		if sw, ok := resp.(interface{ Status() int }); ok {
+			status = sw.Status()
+		}
+
+		requestDuration.WithLabelValues(method, route, statusClass(status)).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteLabels(t *testing.T) {
+	labels := &routeLabels{routes: make(map[string]struct{})}
+
+	assert.Equal(t, "/{username}", labels.label("/{username}", 2))
+	assert.Equal(t, "/{username}/{reponame}", labels.label("/{username}/{reponame}", 2))
+	// known routes keep their label once the limit is reached
+	assert.Equal(t, "/{username}", labels.label("/{username}", 2))
+	assert.Equal(t, routeOther, labels.label("/explore/repos", 2))
+
+	assert.Equal(t, "/explore/repos", labels.label("/explore/repos", 0))
+}
+
+func TestStatusClass(t *testing.T) {
+	assert.Equal(t, "2xx", statusClass(0))
+	assert.Equal(t, "2xx", statusClass(http.StatusNoContent))
+	assert.Equal(t, "4xx", statusClass(http.StatusNotFound))
+	assert.Equal(t, "5xx", statusClass(http.StatusServiceUnavailable))
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/session"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// sessionCollector exposes the number of sessions
+type sessionCollector struct {
+	Sessions *prometheus.Desc
+}
+
+func newSessionCollector() sessionCollector {
+	return sessionCollector{
+		Sessions: prometheus.NewDesc(
+			namespace+"sessions",
+			"Number of active sessions",
+			nil, nil,
+		),
+	}
+}
+
+// Describe returns all possible prometheus.Desc
+func (c sessionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Sessions
+}
+
+// Collect returns the metrics with values
+func (c sessionCollector) Collect(ch chan<- prometheus.Metric) {
+	// some session providers can not count their sessions
+	count := session.CountSessions()
+	if count < 0 {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.Sessions,
+		prometheus.GaugeValue,
+		float64(count),
+	)
+}
+
+// queueCollector exposes the depth and the workers of the queues
+type queueCollector struct {
+	QueueItems   *prometheus.Desc
+	QueueWorkers *prometheus.Desc
+}
+
+func newQueueCollector() queueCollector {
+	return queueCollector{
+		QueueItems: prometheus.NewDesc(
+			namespace+"queue_items",
+			"Number of items waiting in a queue",
+			[]string{"queue"}, nil,
+		),
+		QueueWorkers: prometheus.NewDesc(
+			namespace+"queue_workers",
+			"Number of workers of a queue",
+			[]string{"queue"}, nil,
+		),
+	}
+}
+
+// Describe returns all possible prometheus.Desc
+func (c queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.QueueItems
+	ch <- c.QueueWorkers
+}
+
+// Collect returns the metrics with values
+func (c queueCollector) Collect(ch chan<- prometheus.Metric) {
+	// queue names are not guaranteed to be unique and a duplicated metric would fail the whole scrape
+	seen := make(map[string]bool)
+	for _, mq := range queue.GetManager().ManagedQueues() {
+		if seen[mq.Name] {
+			continue
+		}
+		seen[mq.Name] = true
+
+		if items := mq.NumberInQueue(); items >= 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.QueueItems,
+				prometheus.GaugeValue,
+				float64(items),
+				mq.Name,
+			)
+		}
+		if workers := mq.NumberOfWorkers(); workers >= 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.QueueWorkers,
+				prometheus.GaugeValue,
+				float64(workers),
+				mq.Name,
+			)
+		}
+	}
+}
+
+// databaseCollector exposes the statistics of the database connection pool
+type databaseCollector struct {
+	MaxOpenConnections *prometheus.Desc
+	Connections        *prometheus.Desc
+	WaitCount          *prometheus.Desc
+	WaitDuration       *prometheus.Desc
+}
+
+func newDatabaseCollector() databaseCollector {
+	return databaseCollector{
+		MaxOpenConnections: prometheus.NewDesc(
+			namespace+"database_max_open_connections",
+			"Maximum number of open connections to the database",
+			nil, nil,
+		),
+		Connections: prometheus.NewDesc(
+			namespace+"database_connections",
+			"Number of connections to the database by state",
+			[]string{"state"}, nil,
+		),
+		WaitCount: prometheus.NewDesc(
+			namespace+"database_wait_count_total",
+			"Number of times a connection to the database was waited for",
+			nil, nil,
+		),
+		WaitDuration: prometheus.NewDesc(
+			namespace+"database_wait_duration_seconds_total",
+			"Total time spent waiting for connections to the database",
+			nil, nil,
+		),
+	}
+}
+
+// Describe returns all possible prometheus.Desc
+func (c databaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.MaxOpenConnections
+	ch <- c.Connections
+	ch <- c.WaitCount
+	ch <- c.WaitDuration
+}
+
+// Collect returns the metrics with values
+func (c databaseCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := db.GetDBStats()
+	ch <- prometheus.MustNewConstMetric(
+		c.MaxOpenConnections,
+		prometheus.GaugeValue,
+		float64(stats.MaxOpenConnections),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.Connections,
+		prometheus.GaugeValue,
+		float64(stats.InUse),
+		"in_use",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.Connections,
+		prometheus.GaugeValue,
+		float64(stats.Idle),
+		"idle",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.WaitCount,
+		prometheus.CounterValue,
+		float64(stats.WaitCount),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.WaitDuration,
+		prometheus.CounterValue,
+		stats.WaitDuration.Seconds(),
+	)
+}
@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var mailsSent = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: namespace + "mails_sent_total",
+		Help: "Number of mails sent by result",
+	},
+	[]string{"result"},
+)
+
+// ObserveMailSent counts a sent mail as success or failure depending on the send error
+func ObserveMailSent(err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	mailsSent.WithLabelValues(result).Inc()
+}
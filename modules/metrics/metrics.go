@@ -0,0 +1,76 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metric groups which can be enabled by [metrics] ENABLED_GROUPS
+const (
+	GroupModels   = "models"
+	GroupHTTP     = "http"
+	GroupSessions = "sessions"
+	GroupQueues   = "queues"
+	GroupMail     = "mail"
+	GroupDatabase = "database"
+)
+
+var groups = []string{GroupModels, GroupHTTP, GroupSessions, GroupQueues, GroupMail, GroupDatabase}
+
+// IsGroupEnabled returns if the metrics of a group are exposed
+func IsGroupEnabled(group string) bool {
+	if !setting.Metrics.Enabled {
+		return false
+	}
+	for _, g := range setting.Metrics.EnabledGroups {
+		if strings.EqualFold(strings.TrimSpace(g), group) {
+			return true
+		}
+	}
+	return false
+}
+
+// Register registers the collectors of all enabled metric groups
+func Register() {
+	for _, g := range setting.Metrics.EnabledGroups {
+		if !isKnownGroup(strings.TrimSpace(g)) {
+			log.Warn("Unknown metrics group %q in [metrics] ENABLED_GROUPS, known groups are %s", g, strings.Join(groups, ", "))
+		}
+	}
+
+	if IsGroupEnabled(GroupModels) {
+		prometheus.MustRegister(NewCollector())
+	}
+	if IsGroupEnabled(GroupHTTP) {
+		prometheus.MustRegister(requestDuration)
+	}
+	if IsGroupEnabled(GroupSessions) {
+		prometheus.MustRegister(newSessionCollector())
+	}
+	if IsGroupEnabled(GroupQueues) {
+		prometheus.MustRegister(newQueueCollector())
+	}
+	if IsGroupEnabled(GroupMail) {
+		prometheus.MustRegister(mailsSent)
+	}
+	if IsGroupEnabled(GroupDatabase) {
+		prometheus.MustRegister(newDatabaseCollector())
+	}
+}
+
+func isKnownGroup(group string) bool {
+	for _, g := range groups {
+		if strings.EqualFold(g, group) {
+			return true
+		}
+	}
+	return false
+}
@@ -28,21 +28,22 @@ func (c BasicIssueContext) ForeignID() int64 {
 
 // Issue is a standard issue information
 type Issue struct {
-	Number      int64
-	PosterID    int64  `yaml:"poster_id"`
-	PosterName  string `yaml:"poster_name"`
-	PosterEmail string `yaml:"poster_email"`
-	Title       string
-	Content     string
-	Ref         string
-	Milestone   string
-	State       string // closed, open
-	IsLocked    bool   `yaml:"is_locked"`
-	Created     time.Time
-	Updated     time.Time
-	Closed      *time.Time
-	Labels      []*Label
-	Reactions   []*Reaction
-	Assignees   []string
-	Context     IssueContext `yaml:"-"`
+	Number       int64
+	PosterID     int64  `yaml:"poster_id"`
+	PosterName   string `yaml:"poster_name"`
+	PosterEmail  string `yaml:"poster_email"`
+	Title        string
+	Content      string
+	Ref          string
+	Milestone    string
+	State        string // closed, open
+	IsLocked     bool   `yaml:"is_locked"`
+	Created      time.Time
+	Updated      time.Time
+	Closed       *time.Time
+	Labels       []*Label
+	Reactions    []*Reaction
+	Assignees    []string
+	CustomFields map[string]string `yaml:"custom_fields,omitempty"`
+	Context      IssueContext      `yaml:"-"`
 }
@@ -39,4 +39,6 @@ type MigrateOptions struct {
 	ReleaseAssets   bool
 	MigrateToRepoID int64
 	MirrorInterval  string `json:"mirror_interval"`
+	// MapUsersByEmail maps the users of the migrated data to the local users with the same verified email
+	MapUsersByEmail bool `json:"map_users_by_email"`
 }
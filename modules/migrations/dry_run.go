@@ -0,0 +1,174 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/migrations/base"
+)
+
+var (
+	_ base.Uploader = &identityCollector{}
+)
+
+// Identity is a user of the migrated data on the source service
+type Identity struct {
+	ID    int64
+	Name  string
+	Email string
+	// LocalUser is the local user the identity is mapped to, nil if it is not mapped
+	LocalUser *models.User
+}
+
+// identityCollector implements an Uploader which only collects the users of the migrated data
+type identityCollector struct {
+	identities []*Identity
+	index      map[string]*Identity
+}
+
+func (c *identityCollector) add(id int64, name, email string) {
+	key := fmt.Sprintf("%d/%s", id, name)
+	if id > 0 {
+		key = fmt.Sprintf("%d", id)
+	}
+	identity, ok := c.index[key]
+	if !ok {
+		identity = &Identity{ID: id, Name: name}
+		c.index[key] = identity
+		c.identities = append(c.identities, identity)
+	}
+	if identity.Email == "" {
+		identity.Email = email
+	}
+}
+
+func (c *identityCollector) addReactions(reactions []*base.Reaction) {
+	for _, reaction := range reactions {
+		c.add(reaction.UserID, reaction.UserName, "")
+	}
+}
+
+// MaxBatchInsertSize returns the table's max batch insert size
+func (c *identityCollector) MaxBatchInsertSize(tp string) int {
+	return 1000
+}
+
+// CreateRepo does nothing as no data is imported
+func (c *identityCollector) CreateRepo(repo *base.Repository, opts base.MigrateOptions) error {
+	return nil
+}
+
+// CreateTopics does nothing as topics have no users
+func (c *identityCollector) CreateTopics(topics ...string) error {
+	return nil
+}
+
+// CreateMilestones does nothing as milestones have no users
+func (c *identityCollector) CreateMilestones(milestones ...*base.Milestone) error {
+	return nil
+}
+
+// CreateReleases collects the publishers of releases
+func (c *identityCollector) CreateReleases(releases ...*base.Release) error {
+	for _, release := range releases {
+		c.add(release.PublisherID, release.PublisherName, release.PublisherEmail)
+	}
+	return nil
+}
+
+// SyncTags does nothing as no data is imported
+func (c *identityCollector) SyncTags() error {
+	return nil
+}
+
+// CreateLabels does nothing as labels have no users
+func (c *identityCollector) CreateLabels(labels ...*base.Label) error {
+	return nil
+}
+
+// CreateIssues collects the posters of issues and their reactions
+func (c *identityCollector) CreateIssues(issues ...*base.Issue) error {
+	for _, issue := range issues {
+		c.add(issue.PosterID, issue.PosterName, issue.PosterEmail)
+		c.addReactions(issue.Reactions)
+	}
+	return nil
+}
+
+// CreateComments collects the posters of comments and their reactions
+func (c *identityCollector) CreateComments(comments ...*base.Comment) error {
+	for _, comment := range comments {
+		c.add(comment.PosterID, comment.PosterName, comment.PosterEmail)
+		c.addReactions(comment.Reactions)
+	}
+	return nil
+}
+
+// CreatePullRequests collects the posters of pull requests and their reactions
+func (c *identityCollector) CreatePullRequests(prs ...*base.PullRequest) error {
+	for _, pr := range prs {
+		c.add(pr.PosterID, pr.PosterName, pr.PosterEmail)
+		c.addReactions(pr.Reactions)
+	}
+	return nil
+}
+
+// CreateReviews collects the reviewers of pull requests
+func (c *identityCollector) CreateReviews(reviews ...*base.Review) error {
+	for _, review := range reviews {
+		c.add(review.ReviewerID, review.ReviewerName, "")
+	}
+	return nil
+}
+
+// Rollback does nothing as no data is imported
+func (c *identityCollector) Rollback() error {
+	return nil
+}
+
+// Finish does nothing as no data is imported
+func (c *identityCollector) Finish() error {
+	return nil
+}
+
+// Close does nothing as no data is imported
+func (c *identityCollector) Close() {}
+
+// DryRunMigration downloads the data a migration according MigrateOptions would import without importing it,
+// and returns the users of the data together with the local users they would be mapped to
+func DryRunMigration(ctx context.Context, doer *models.User, ownerName string, opts base.MigrateOptions) ([]*Identity, error) {
+	err := IsMigrateURLAllowed(opts.CloneAddr, doer)
+	if err != nil {
+		return nil, err
+	}
+	downloader, err := newDownloader(ctx, ownerName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	collector := &identityCollector{index: make(map[string]*Identity)}
+	if err := migrateRepository(downloader, collector, opts, nil); err != nil {
+		return nil, err
+	}
+
+	// map the identities the same way the migration would
+	uploader := NewGiteaLocalUploader(ctx, doer, ownerName, opts.RepoName)
+	uploader.gitServiceType = opts.GitServiceType
+	uploader.mapUsersByEmail = opts.MapUsersByEmail
+	for _, identity := range collector.identities {
+		userID := uploader.getUserID(identity.ID, identity.Email)
+		if userID <= 0 {
+			continue
+		}
+		identity.LocalUser, err = models.GetUserByID(userID)
+		if err != nil && !models.IsErrUserNotExist(err) {
+			return nil, err
+		}
+	}
+	return collector.identities, nil
+}
@@ -90,14 +90,19 @@ func (g *RepositoryDumper) reviewDir() string {
 }
 
 func (g *RepositoryDumper) setURLToken(remoteAddr string) (string, error) {
-	if len(g.opts.AuthToken) > 0 || len(g.opts.AuthUsername) > 0 {
+	return setURLToken(remoteAddr, g.opts)
+}
+
+// setURLToken adds the credentials of the migrate options to the remote address
+func setURLToken(remoteAddr string, opts base.MigrateOptions) (string, error) {
+	if len(opts.AuthToken) > 0 || len(opts.AuthUsername) > 0 {
 		u, err := url.Parse(remoteAddr)
 		if err != nil {
 			return "", err
 		}
-		u.User = url.UserPassword(g.opts.AuthUsername, g.opts.AuthPassword)
-		if len(g.opts.AuthToken) > 0 {
-			u.User = url.UserPassword("oauth2", g.opts.AuthToken)
+		u.User = url.UserPassword(opts.AuthUsername, opts.AuthPassword)
+		if len(opts.AuthToken) > 0 {
+			u.User = url.UserPassword("oauth2", opts.AuthToken)
 		}
 		remoteAddr = u.String()
 	}
@@ -138,51 +143,65 @@ func (g *RepositoryDumper) CreateRepo(repo *base.Repository, opts base.MigrateOp
 		return err
 	}
 
-	repoPath := g.gitPath()
-	if err := os.MkdirAll(repoPath, os.ModePerm); err != nil {
+	remoteAddr, err := g.setURLToken(repo.CloneURL)
+	if err != nil {
 		return err
 	}
 
-	migrateTimeout := 2 * time.Hour
-
-	remoteAddr, err := g.setURLToken(repo.CloneURL)
-	if err != nil {
+	wikiPath := ""
+	if opts.Wiki {
+		wikiPath = g.wikiPath()
+	}
+	if _, err := cloneRepositoryData(remoteAddr, g.gitPath(), wikiPath); err != nil {
 		return err
 	}
 
-	err = git.Clone(remoteAddr, repoPath, git.CloneRepoOptions{
+	g.gitRepo, err = git.OpenRepository(g.gitPath())
+	return err
+}
+
+// cloneRepositoryData mirrors the git repository at remoteAddr to repoPath and, if wikiPath is not empty,
+// its wiki to wikiPath. It returns whether the wiki has been cloned.
+func cloneRepositoryData(remoteAddr, repoPath, wikiPath string) (bool, error) {
+	if err := os.MkdirAll(repoPath, os.ModePerm); err != nil {
+		return false, err
+	}
+
+	migrateTimeout := 2 * time.Hour
+
+	err := git.Clone(remoteAddr, repoPath, git.CloneRepoOptions{
 		Mirror:  true,
 		Quiet:   true,
 		Timeout: migrateTimeout,
 	})
 	if err != nil {
-		return fmt.Errorf("Clone: %v", err)
+		return false, fmt.Errorf("Clone: %v", err)
 	}
 
-	if opts.Wiki {
-		wikiPath := g.wikiPath()
-		wikiRemotePath := repository.WikiRemoteURL(remoteAddr)
-		if len(wikiRemotePath) > 0 {
-			if err := os.MkdirAll(wikiPath, os.ModePerm); err != nil {
-				return fmt.Errorf("Failed to remove %s: %v", wikiPath, err)
-			}
+	if wikiPath == "" {
+		return false, nil
+	}
+	wikiRemotePath := repository.WikiRemoteURL(remoteAddr)
+	if len(wikiRemotePath) == 0 {
+		return false, nil
+	}
+	if err := os.MkdirAll(wikiPath, os.ModePerm); err != nil {
+		return false, fmt.Errorf("Failed to remove %s: %v", wikiPath, err)
+	}
 
-			if err := git.Clone(wikiRemotePath, wikiPath, git.CloneRepoOptions{
-				Mirror:  true,
-				Quiet:   true,
-				Timeout: migrateTimeout,
-				Branch:  "master",
-			}); err != nil {
-				log.Warn("Clone wiki: %v", err)
-				if err := os.RemoveAll(wikiPath); err != nil {
-					return fmt.Errorf("Failed to remove %s: %v", wikiPath, err)
-				}
-			}
+	if err := git.Clone(wikiRemotePath, wikiPath, git.CloneRepoOptions{
+		Mirror:  true,
+		Quiet:   true,
+		Timeout: migrateTimeout,
+		Branch:  "master",
+	}); err != nil {
+		log.Warn("Clone wiki: %v", err)
+		if err := os.RemoveAll(wikiPath); err != nil {
+			return false, fmt.Errorf("Failed to remove %s: %v", wikiPath, err)
 		}
+		return false, nil
 	}
-
-	g.gitRepo, err = git.OpenRepository(g.gitPath())
-	return err
+	return true, nil
 }
 
 // Close closes this uploader
@@ -291,33 +310,7 @@ func (g *RepositoryDumper) CreateReleases(releases ...*base.Release) error {
 				attachLocalPath := filepath.Join(attachDir, asset.Name)
 				// download attachment
 
-				err := func(attachPath string) error {
-					var rc io.ReadCloser
-					var err error
-					if asset.DownloadURL == nil {
-						rc, err = asset.DownloadFunc()
-						if err != nil {
-							return err
-						}
-					} else {
-						resp, err := http.Get(*asset.DownloadURL)
-						if err != nil {
-							return err
-						}
-						rc = resp.Body
-					}
-					defer rc.Close()
-
-					fw, err := os.Create(attachPath)
-					if err != nil {
-						return fmt.Errorf("Create: %v", err)
-					}
-					defer fw.Close()
-
-					_, err = io.Copy(fw, rc)
-					return err
-				}(filepath.Join(g.baseDir, attachLocalPath))
-				if err != nil {
+				if err := downloadReleaseAsset(asset, filepath.Join(g.baseDir, attachLocalPath)); err != nil {
 					return err
 				}
 				asset.DownloadURL = &attachLocalPath // to save the filepath on the yml file, change the source
@@ -345,6 +338,34 @@ func (g *RepositoryDumper) CreateReleases(releases ...*base.Release) error {
 	return nil
 }
 
+// downloadReleaseAsset stores the content of a release asset to a local file
+func downloadReleaseAsset(asset *base.ReleaseAsset, attachPath string) error {
+	var rc io.ReadCloser
+	var err error
+	if asset.DownloadURL == nil {
+		rc, err = asset.DownloadFunc()
+		if err != nil {
+			return err
+		}
+	} else {
+		resp, err := http.Get(*asset.DownloadURL)
+		if err != nil {
+			return err
+		}
+		rc = resp.Body
+	}
+	defer rc.Close()
+
+	fw, err := os.Create(attachPath)
+	if err != nil {
+		return fmt.Errorf("Create: %v", err)
+	}
+	defer fw.Close()
+
+	_, err = io.Copy(fw, rc)
+	return err
+}
+
 // SyncTags syncs releases with tags in the database
 func (g *RepositoryDumper) SyncTags() error {
 	return nil
@@ -416,6 +437,9 @@ func (g *RepositoryDumper) CreatePullRequests(prs ...*base.PullRequest) error {
 	for _, pr := range prs {
 		// download patch file
 		err := func() error {
+			if pr.PatchURL == "" {
+				return nil
+			}
 			u, err := g.setURLToken(pr.PatchURL)
 			if err != nil {
 				return err
@@ -605,6 +629,30 @@ func updateOptionsUnits(opts *base.MigrateOptions, units []string) {
 	}
 }
 
+// BackupRepository dumps a repository of this instance with all its units to the directory <baseDir>/<owner>/<name>,
+// from which it can be restored by RestoreRepository
+func BackupRepository(ctx context.Context, baseDir string, repo *models.Repository) error {
+	opts := base.MigrateOptions{
+		RepoName: repo.Name,
+		Private:  repo.IsPrivate,
+	}
+	updateOptionsUnits(&opts, nil)
+
+	downloader := NewLocalDownloader(ctx, repo)
+	uploader, err := NewRepositoryDumper(ctx, baseDir, repo.OwnerName, repo.Name, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := migrateRepository(downloader, uploader, opts, nil); err != nil {
+		if err1 := uploader.Rollback(); err1 != nil {
+			log.Error("rollback failed: %v", err1)
+		}
+		return err
+	}
+	return nil
+}
+
 // RestoreRepository restore a repository from the disk directory
 func RestoreRepository(ctx context.Context, baseDir string, ownerName, repoName string, units []string) error {
 	doer, err := models.GetAdminUser()
@@ -635,3 +683,49 @@ func RestoreRepository(ctx context.Context, baseDir string, ownerName, repoName
 	}
 	return updateMigrationPosterIDByGitService(ctx, structs.GitServiceType(tp))
 }
+
+// ExportRepository dumps a repository according MigrateOptions to a local directory in the GitHub migration archive layout
+func ExportRepository(ctx context.Context, baseDir, ownerName string, opts base.MigrateOptions) error {
+	downloader, err := newDownloader(ctx, ownerName, opts)
+	if err != nil {
+		return err
+	}
+	uploader, err := NewGithubArchiveDumper(ctx, baseDir, ownerName, opts.RepoName, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := migrateRepository(downloader, uploader, opts, nil); err != nil {
+		if err1 := uploader.Rollback(); err1 != nil {
+			log.Error("rollback failed: %v", err1)
+		}
+		return err
+	}
+	return nil
+}
+
+// ImportRepository restores a repository from a GitHub migration archive extracted to the disk directory
+func ImportRepository(ctx context.Context, baseDir string, ownerName, repoName string, units []string) error {
+	doer, err := models.GetAdminUser()
+	if err != nil {
+		return err
+	}
+	var uploader = NewGiteaLocalUploader(ctx, doer, ownerName, repoName)
+	downloader, err := NewGithubArchiveRestorer(ctx, baseDir, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+
+	var migrateOpts = base.MigrateOptions{
+		Private: downloader.IsPrivate(),
+	}
+	updateOptionsUnits(&migrateOpts, units)
+
+	if err = migrateRepository(downloader, uploader, migrateOpts, nil); err != nil {
+		if err1 := uploader.Rollback(); err1 != nil {
+			log.Error("rollback failed: %v", err1)
+		}
+		return err
+	}
+	return nil
+}
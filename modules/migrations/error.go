@@ -14,6 +14,8 @@ import (
 var (
 	// ErrRepoNotCreated returns the error that repository not created
 	ErrRepoNotCreated = errors.New("repository is not created yet")
+	// ErrRepoNotSyncable returns the error that repository was not migrated with sync tracking
+	ErrRepoNotSyncable = errors.New("repository was not migrated with sync tracking")
 )
 
 // IsRateLimitError returns true if the err is github.RateLimitError
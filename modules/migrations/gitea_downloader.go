@@ -486,6 +486,7 @@ func (g *GiteaDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Comm
 			}
 
 			allComments = append(allComments, &base.Comment{
+				ID:          comment.ID,
 				IssueIndex:  opts.Context.LocalID(),
 				PosterID:    comment.Poster.ID,
 				PosterName:  comment.Poster.UserName,
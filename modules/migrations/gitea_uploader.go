@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/uri"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/pull"
 
 	gouuid "github.com/google/uuid"
@@ -48,9 +50,15 @@ type GiteaLocalUploader struct {
 	issues         sync.Map
 	gitRepo        *git.Repository
 	prHeadCache    map[string]struct{}
-	userMap        map[int64]int64 // external user id mapping to user id
+	userMap        map[int64]int64  // external user id mapping to user id
+	emailMap       map[string]int64 // external user email mapping to user id
 	prCache        map[int64]*models.PullRequest
 	gitServiceType structs.GitServiceType
+	// mapUsersByEmail maps external users not linked to local users to the local users with the same verified email
+	mapUsersByEmail bool
+	// sync imports into the existing repository only the data which was not imported by the migration or a previous sync
+	sync     bool
+	mappings map[models.MigrationMappingType]map[string]int64 // foreign id mapping to local id of imported data
 }
 
 // NewGiteaLocalUploader creates an gitea Uploader via gitea API v1
@@ -62,7 +70,9 @@ func NewGiteaLocalUploader(ctx context.Context, doer *models.User, repoOwner, re
 		repoName:    repoName,
 		prHeadCache: make(map[string]struct{}),
 		userMap:     make(map[int64]int64),
+		emailMap:    make(map[string]int64),
 		prCache:     make(map[int64]*models.PullRequest),
+		mappings:    make(map[models.MigrationMappingType]map[string]int64),
 	}
 }
 
@@ -92,6 +102,10 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 		return err
 	}
 
+	if g.sync {
+		return g.openSyncRepo(repo, opts)
+	}
+
 	var r *models.Repository
 	if opts.MigrateToRepoID <= 0 {
 		r, err = repo_module.CreateRepository(g.doer, owner, models.CreateRepoOptions{
@@ -134,6 +148,123 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 	return err
 }
 
+// openSyncRepo opens the repository to sync and loads the data which was already imported into it
+func (g *GiteaLocalUploader) openSyncRepo(repo *base.Repository, opts base.MigrateOptions) error {
+	r, err := models.GetRepositoryByID(opts.MigrateToRepoID)
+	if err != nil {
+		return err
+	}
+	g.repo = r
+	g.gitRepo, err = git.OpenRepository(r.RepoPath())
+	if err != nil {
+		return err
+	}
+
+	// mirrors fetch the git data by themselves, other repositories need at least the tags of the new releases
+	if !r.IsMirror {
+		if _, err := git.NewCommandContext(g.ctx, "fetch", "--tags", repo.CloneURL).
+			RunInDirTimeout(time.Duration(setting.Git.Timeout.Pull)*time.Second, r.RepoPath()); err != nil {
+			return util.NewStringURLSanitizedError(fmt.Errorf("fetch tags: %v", err), repo.CloneURL, true)
+		}
+	}
+
+	labels, err := models.GetLabelsByRepoID(r.ID, "", models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, lb := range labels {
+		g.labels.Store(lb.Name, lb)
+	}
+	milestones, _, err := models.GetMilestones(models.GetMilestonesOption{RepoID: r.ID, State: structs.StateAll})
+	if err != nil {
+		return err
+	}
+	for _, ms := range milestones {
+		g.milestones.Store(ms.Name, ms.ID)
+	}
+
+	for _, tp := range []models.MigrationMappingType{models.MigrationMappingIssue, models.MigrationMappingComment, models.MigrationMappingRelease} {
+		if g.mappings[tp], err = models.GetMigrationMappings(r.ID, tp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isImported returns whether the object of the source with the given foreign id was already imported
+func (g *GiteaLocalUploader) isImported(tp models.MigrationMappingType, foreignID string) bool {
+	_, ok := g.mappings[tp][foreignID]
+	return ok
+}
+
+// commentForeignID returns the id identifying the comment on the source
+func commentForeignID(comment *base.Comment) string {
+	if comment.ID > 0 {
+		return strconv.FormatInt(comment.ID, 10)
+	}
+	// some sources don't expose comment ids
+	return fmt.Sprintf("%d-%d-%d", comment.IssueIndex, comment.PosterID, comment.Created.Unix())
+}
+
+// getIssue returns the local issue the issue or pull request with the given number on the source was imported as,
+// it returns nil when syncing and the issue was not imported
+func (g *GiteaLocalUploader) getIssue(number int64) (*models.Issue, error) {
+	if issue, ok := g.issues.Load(number); ok {
+		return issue.(*models.Issue), nil
+	}
+
+	var issue *models.Issue
+	var err error
+	if g.sync {
+		issueID, ok := g.mappings[models.MigrationMappingIssue][strconv.FormatInt(number, 10)]
+		if !ok {
+			return nil, nil
+		}
+		issue, err = models.GetIssueByID(issueID)
+	} else {
+		issue, err = models.GetIssueByIndex(g.repo.ID, number)
+	}
+	if err != nil {
+		return nil, err
+	}
+	g.issues.Store(number, issue)
+	return issue, nil
+}
+
+// getUserID returns the id of the local user the external user is mapped to or 0 if it is not mapped
+func (g *GiteaLocalUploader) getUserID(externalID int64, email string) int64 {
+	if userid, ok := g.userMap[externalID]; ok {
+		return userid
+	}
+
+	if tp := g.gitServiceType.Name(); tp != "" {
+		userid, err := models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", externalID))
+		if err != nil {
+			log.Error("GetUserIDByExternalUserID: %v", err)
+		}
+		if userid > 0 {
+			g.userMap[externalID] = userid
+			return userid
+		}
+	}
+
+	if !g.mapUsersByEmail || email == "" {
+		return 0
+	}
+	email = strings.ToLower(email)
+	userid, ok := g.emailMap[email]
+	if !ok {
+		var err error
+		userid, err = models.GetUserIDByVerifiedEmail(email)
+		if err != nil {
+			log.Error("GetUserIDByVerifiedEmail: %v", err)
+			return 0
+		}
+		g.emailMap[email] = userid
+	}
+	return userid
+}
+
 // Close closes this uploader
 func (g *GiteaLocalUploader) Close() {
 	if g.gitRepo != nil {
@@ -143,6 +274,11 @@ func (g *GiteaLocalUploader) Close() {
 
 // CreateTopics creates topics
 func (g *GiteaLocalUploader) CreateTopics(topics ...string) error {
+	// keep the topics which may have been changed since the migration
+	if g.sync {
+		return nil
+	}
+
 	// ignore topics to long for the db
 	c := 0
 	for i := range topics {
@@ -159,6 +295,10 @@ func (g *GiteaLocalUploader) CreateTopics(topics ...string) error {
 func (g *GiteaLocalUploader) CreateMilestones(milestones ...*base.Milestone) error {
 	var mss = make([]*models.Milestone, 0, len(milestones))
 	for _, milestone := range milestones {
+		if _, ok := g.milestones.Load(milestone.Title); ok && g.sync {
+			continue
+		}
+
 		var deadline timeutil.TimeStamp
 		if milestone.Deadline != nil {
 			deadline = timeutil.TimeStamp(milestone.Deadline.Unix())
@@ -210,6 +350,9 @@ func (g *GiteaLocalUploader) CreateMilestones(milestones ...*base.Milestone) err
 func (g *GiteaLocalUploader) CreateLabels(labels ...*base.Label) error {
 	var lbs = make([]*models.Label, 0, len(labels))
 	for _, label := range labels {
+		if _, ok := g.labels.Load(label.Name); ok && g.sync {
+			continue
+		}
 		lbs = append(lbs, &models.Label{
 			RepoID:      g.repo.ID,
 			Name:        label.Name,
@@ -232,6 +375,20 @@ func (g *GiteaLocalUploader) CreateLabels(labels ...*base.Label) error {
 func (g *GiteaLocalUploader) CreateReleases(releases ...*base.Release) error {
 	var rels = make([]*models.Release, 0, len(releases))
 	for _, release := range releases {
+		if g.sync {
+			if g.isImported(models.MigrationMappingRelease, release.TagName) {
+				continue
+			}
+			// the release may have been created locally since the migration
+			exist, err := models.IsReleaseExist(g.repo.ID, release.TagName)
+			if err != nil {
+				return err
+			}
+			if exist {
+				continue
+			}
+		}
+
 		if release.Created.IsZero() {
 			if !release.Published.IsZero() {
 				release.Created = release.Published
@@ -254,19 +411,7 @@ func (g *GiteaLocalUploader) CreateReleases(releases ...*base.Release) error {
 			CreatedUnix:  timeutil.TimeStamp(release.Created.Unix()),
 		}
 
-		userid, ok := g.userMap[release.PublisherID]
-		tp := g.gitServiceType.Name()
-		if !ok && tp != "" {
-			var err error
-			userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", release.PublisherID))
-			if err != nil {
-				log.Error("GetUserIDByExternalUserID: %v", err)
-			}
-			if userid > 0 {
-				g.userMap[release.PublisherID] = userid
-			}
-		}
-
+		userid := g.getUserID(release.PublisherID, release.PublisherEmail)
 		if userid > 0 {
 			rel.PublisherID = userid
 		} else {
@@ -336,7 +481,20 @@ func (g *GiteaLocalUploader) CreateReleases(releases ...*base.Release) error {
 		rels = append(rels, &rel)
 	}
 
-	return models.InsertReleases(rels...)
+	if err := models.InsertReleases(rels...); err != nil {
+		return err
+	}
+
+	var mappings = make([]*models.MigrationMapping, 0, len(rels))
+	for _, rel := range rels {
+		mappings = append(mappings, &models.MigrationMapping{
+			RepoID:    g.repo.ID,
+			Type:      models.MigrationMappingRelease,
+			ForeignID: rel.TagName,
+			LocalID:   rel.ID,
+		})
+	}
+	return models.InsertMigrationMappings(mappings...)
 }
 
 // SyncTags syncs releases with tags in the database
@@ -347,7 +505,12 @@ func (g *GiteaLocalUploader) SyncTags() error {
 // CreateIssues creates issues
 func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 	var iss = make([]*models.Issue, 0, len(issues))
+	var numbers = make([]int64, 0, len(issues))
 	for _, issue := range issues {
+		if g.sync && g.isImported(models.MigrationMappingIssue, strconv.FormatInt(issue.Number, 10)) {
+			continue
+		}
+
 		var labels []*models.Label
 		for _, label := range issue.Labels {
 			lb, ok := g.labels.Load(label.Name)
@@ -394,19 +557,7 @@ func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 			UpdatedUnix: timeutil.TimeStamp(issue.Updated.Unix()),
 		}
 
-		userid, ok := g.userMap[issue.PosterID]
-		tp := g.gitServiceType.Name()
-		if !ok && tp != "" {
-			var err error
-			userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", issue.PosterID))
-			if err != nil {
-				log.Error("GetUserIDByExternalUserID: %v", err)
-			}
-			if userid > 0 {
-				g.userMap[issue.PosterID] = userid
-			}
-		}
-
+		userid := g.getUserID(issue.PosterID, issue.PosterEmail)
 		if userid > 0 {
 			is.PosterID = userid
 		} else {
@@ -415,22 +566,24 @@ func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 			is.OriginalAuthorID = issue.PosterID
 		}
 
+		if g.sync {
+			// the number may have been taken by an issue created locally since the migration
+			_, err := models.GetIssueByIndex(g.repo.ID, is.Index)
+			if err == nil {
+				if is.Index, err = db.GetNextResourceIndex("issue_index", g.repo.ID); err != nil {
+					return err
+				}
+			} else if !models.IsErrIssueNotExist(err) {
+				return err
+			}
+		}
+
 		if issue.Closed != nil {
 			is.ClosedUnix = timeutil.TimeStamp(issue.Closed.Unix())
 		}
 		// add reactions
 		for _, reaction := range issue.Reactions {
-			userid, ok := g.userMap[reaction.UserID]
-			if !ok && tp != "" {
-				var err error
-				userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", reaction.UserID))
-				if err != nil {
-					log.Error("GetUserIDByExternalUserID: %v", err)
-				}
-				if userid > 0 {
-					g.userMap[reaction.UserID] = userid
-				}
-			}
+			userid := g.getUserID(reaction.UserID, "")
 			var res = models.Reaction{
 				Type:        reaction.Content,
 				CreatedUnix: timeutil.TimeStampNow(),
@@ -445,6 +598,7 @@ func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 			is.Reactions = append(is.Reactions, &res)
 		}
 		iss = append(iss, &is)
+		numbers = append(numbers, issue.Number)
 	}
 
 	if len(iss) > 0 {
@@ -452,9 +606,17 @@ func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 			return err
 		}
 
-		for _, is := range iss {
-			g.issues.Store(is.Index, is)
+		var mappings = make([]*models.MigrationMapping, 0, len(iss))
+		for i, is := range iss {
+			g.issues.Store(numbers[i], is)
+			mappings = append(mappings, &models.MigrationMapping{
+				RepoID:    g.repo.ID,
+				Type:      models.MigrationMappingIssue,
+				ForeignID: strconv.FormatInt(numbers[i], 10),
+				LocalID:   is.ID,
+			})
 		}
+		return models.InsertMigrationMappings(mappings...)
 	}
 
 	return nil
@@ -463,33 +625,24 @@ func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 // CreateComments creates comments of issues
 func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 	var cms = make([]*models.Comment, 0, len(comments))
+	var foreignIDs = make([]string, 0, len(comments))
 	for _, comment := range comments {
-		var issue *models.Issue
-		issueInter, ok := g.issues.Load(comment.IssueIndex)
-		if !ok {
-			var err error
-			issue, err = models.GetIssueByIndex(g.repo.ID, comment.IssueIndex)
-			if err != nil {
-				return err
-			}
-			g.issues.Store(comment.IssueIndex, issue)
-		} else {
-			issue = issueInter.(*models.Issue)
+		foreignID := commentForeignID(comment)
+		if g.sync && g.isImported(models.MigrationMappingComment, foreignID) {
+			continue
 		}
 
-		userid, ok := g.userMap[comment.PosterID]
-		tp := g.gitServiceType.Name()
-		if !ok && tp != "" {
-			var err error
-			userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", comment.PosterID))
-			if err != nil {
-				log.Error("GetUserIDByExternalUserID: %v", err)
-			}
-			if userid > 0 {
-				g.userMap[comment.PosterID] = userid
-			}
+		issue, err := g.getIssue(comment.IssueIndex)
+		if err != nil {
+			return err
+		}
+		if issue == nil {
+			// the comment belongs to a pull request which is not synced
+			continue
 		}
 
+		userid := g.getUserID(comment.PosterID, comment.PosterEmail)
+
 		if comment.Created.IsZero() {
 			comment.Created = time.Unix(int64(issue.CreatedUnix), 0)
 		}
@@ -515,17 +668,7 @@ func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 
 		// add reactions
 		for _, reaction := range comment.Reactions {
-			userid, ok := g.userMap[reaction.UserID]
-			if !ok && tp != "" {
-				var err error
-				userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", reaction.UserID))
-				if err != nil {
-					log.Error("GetUserIDByExternalUserID: %v", err)
-				}
-				if userid > 0 {
-					g.userMap[reaction.UserID] = userid
-				}
-			}
+			userid := g.getUserID(reaction.UserID, "")
 			var res = models.Reaction{
 				Type:        reaction.Content,
 				CreatedUnix: timeutil.TimeStampNow(),
@@ -541,12 +684,26 @@ func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 		}
 
 		cms = append(cms, &cm)
+		foreignIDs = append(foreignIDs, foreignID)
 	}
 
 	if len(cms) == 0 {
 		return nil
 	}
-	return models.InsertIssueComments(cms)
+	if err := models.InsertIssueComments(cms); err != nil {
+		return err
+	}
+
+	var mappings = make([]*models.MigrationMapping, 0, len(cms))
+	for i, cm := range cms {
+		mappings = append(mappings, &models.MigrationMapping{
+			RepoID:    g.repo.ID,
+			Type:      models.MigrationMappingComment,
+			ForeignID: foreignIDs[i],
+			LocalID:   cm.ID,
+		})
+	}
+	return models.InsertMigrationMappings(mappings...)
 }
 
 // CreatePullRequests creates pull requests
@@ -558,19 +715,7 @@ func (g *GiteaLocalUploader) CreatePullRequests(prs ...*base.PullRequest) error
 			return err
 		}
 
-		userid, ok := g.userMap[pr.PosterID]
-		tp := g.gitServiceType.Name()
-		if !ok && tp != "" {
-			var err error
-			userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", pr.PosterID))
-			if err != nil {
-				log.Error("GetUserIDByExternalUserID: %v", err)
-			}
-			if userid > 0 {
-				g.userMap[pr.PosterID] = userid
-			}
-		}
-
+		userid := g.getUserID(pr.PosterID, pr.PosterEmail)
 		if userid > 0 {
 			gpr.Issue.PosterID = userid
 		} else {
@@ -584,11 +729,18 @@ func (g *GiteaLocalUploader) CreatePullRequests(prs ...*base.PullRequest) error
 	if err := models.InsertPullRequests(gprs...); err != nil {
 		return err
 	}
+	var mappings = make([]*models.MigrationMapping, 0, len(gprs))
 	for _, pr := range gprs {
 		g.issues.Store(pr.Issue.Index, pr.Issue)
 		pull.AddToTaskQueue(pr)
+		mappings = append(mappings, &models.MigrationMapping{
+			RepoID:    g.repo.ID,
+			Type:      models.MigrationMappingIssue,
+			ForeignID: strconv.FormatInt(pr.Issue.Index, 10),
+			LocalID:   pr.Issue.ID,
+		})
 	}
-	return nil
+	return models.InsertMigrationMappings(mappings...)
 }
 
 func (g *GiteaLocalUploader) newPullRequest(pr *base.PullRequest) (*models.PullRequest, error) {
@@ -720,20 +872,7 @@ func (g *GiteaLocalUploader) newPullRequest(pr *base.PullRequest) (*models.PullR
 		UpdatedUnix: timeutil.TimeStamp(pr.Updated.Unix()),
 	}
 
-	tp := g.gitServiceType.Name()
-
-	userid, ok := g.userMap[pr.PosterID]
-	if !ok && tp != "" {
-		var err error
-		userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", pr.PosterID))
-		if err != nil {
-			log.Error("GetUserIDByExternalUserID: %v", err)
-		}
-		if userid > 0 {
-			g.userMap[pr.PosterID] = userid
-		}
-	}
-
+	userid := g.getUserID(pr.PosterID, pr.PosterEmail)
 	if userid > 0 {
 		issue.PosterID = userid
 	} else {
@@ -744,17 +883,7 @@ func (g *GiteaLocalUploader) newPullRequest(pr *base.PullRequest) (*models.PullR
 
 	// add reactions
 	for _, reaction := range pr.Reactions {
-		userid, ok := g.userMap[reaction.UserID]
-		if !ok && tp != "" {
-			var err error
-			userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", reaction.UserID))
-			if err != nil {
-				log.Error("GetUserIDByExternalUserID: %v", err)
-			}
-			if userid > 0 {
-				g.userMap[reaction.UserID] = userid
-			}
-		}
+		userid := g.getUserID(reaction.UserID, "")
 		var res = models.Reaction{
 			Type:        reaction.Content,
 			CreatedUnix: timeutil.TimeStampNow(),
@@ -814,32 +943,16 @@ func convertReviewState(state string) models.ReviewType {
 func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 	var cms = make([]*models.Review, 0, len(reviews))
 	for _, review := range reviews {
-		var issue *models.Issue
-		issueInter, ok := g.issues.Load(review.IssueIndex)
-		if !ok {
-			var err error
-			issue, err = models.GetIssueByIndex(g.repo.ID, review.IssueIndex)
-			if err != nil {
-				return err
-			}
-			g.issues.Store(review.IssueIndex, issue)
-		} else {
-			issue = issueInter.(*models.Issue)
+		issue, err := g.getIssue(review.IssueIndex)
+		if err != nil {
+			return err
 		}
-
-		userid, ok := g.userMap[review.ReviewerID]
-		tp := g.gitServiceType.Name()
-		if !ok && tp != "" {
-			var err error
-			userid, err = models.GetUserIDByExternalUserID(tp, fmt.Sprintf("%v", review.ReviewerID))
-			if err != nil {
-				log.Error("GetUserIDByExternalUserID: %v", err)
-			}
-			if userid > 0 {
-				g.userMap[review.ReviewerID] = userid
-			}
+		if issue == nil {
+			continue
 		}
 
+		userid := g.getUserID(review.ReviewerID, "")
+
 		if review.CreatedAt.IsZero() {
 			review.CreatedAt = time.Unix(int64(issue.CreatedUnix), 0)
 		}
@@ -940,6 +1053,10 @@ func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 
 // Rollback when migrating failed, this will rollback all the changes.
 func (g *GiteaLocalUploader) Rollback() error {
+	// keep what was synced, the next sync will continue from there
+	if g.sync {
+		return nil
+	}
 	if g.repo != nil && g.repo.ID > 0 {
 		g.gitRepo.Close()
 		if err := models.DeleteRepository(g.doer, g.repo.OwnerID, g.repo.ID); err != nil {
@@ -960,6 +1077,10 @@ func (g *GiteaLocalUploader) Finish() error {
 		return err
 	}
 
+	if err := models.UpdateMigrationLastSync(g.repo.ID); err != nil {
+		return err
+	}
+
 	g.repo.Status = models.RepositoryReady
 	return models.UpdateRepositoryCols(g.repo, "status")
 }
@@ -112,3 +112,78 @@ func TestGiteaUploadRepo(t *testing.T) {
 	assert.NoError(t, pulls[0].Issue.LoadDiscussComments())
 	assert.Len(t, pulls[0].Issue.Comments, 2)
 }
+
+func TestGiteaUploadGetUserIDByEmail(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	uploader := NewGiteaLocalUploader(graceful.GetManager().HammerContext(), doer, doer.Name, "migrated")
+
+	assert.EqualValues(t, 0, uploader.getUserID(0, "user2@example.com"))
+
+	uploader.mapUsersByEmail = true
+	assert.EqualValues(t, 2, uploader.getUserID(0, "User2@example.com"))
+	assert.EqualValues(t, 0, uploader.getUserID(0, "user11@example.com"))
+	assert.EqualValues(t, 0, uploader.getUserID(0, ""))
+}
+
+func TestGiteaUploadSync(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	source := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, source.GetOwner())
+
+	opts := base.MigrateOptions{
+		RepoName:   "synced",
+		Labels:     true,
+		Milestones: true,
+		Issues:     true,
+		Comments:   true,
+	}
+	uploader := NewGiteaLocalUploader(context.Background(), doer, doer.Name, opts.RepoName)
+	assert.NoError(t, migrateRepository(NewLocalDownloader(context.Background(), source), uploader, opts, nil))
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: doer.ID, Name: opts.RepoName}).(*models.Repository)
+	lastSync, err := models.GetMigrationLastSync(repo.ID)
+	assert.NoError(t, err)
+	assert.NotZero(t, lastSync)
+
+	countIssues := func() int {
+		issues, err := models.Issues(&models.IssuesOptions{RepoIDs: []int64{repo.ID}, SortType: "oldest"})
+		assert.NoError(t, err)
+		return len(issues)
+	}
+	countComments := func() int64 {
+		count, err := models.CountComments(&models.FindCommentsOptions{RepoID: repo.ID, Type: models.CommentTypeComment})
+		assert.NoError(t, err)
+		return count
+	}
+	issues, comments := countIssues(), countComments()
+	assert.EqualValues(t, 2, issues)
+
+	sync := func() {
+		opts.MigrateToRepoID = repo.ID
+		uploader := NewGiteaLocalUploader(context.Background(), doer, doer.Name, opts.RepoName)
+		uploader.sync = true
+		assert.NoError(t, migrateRepository(NewLocalDownloader(context.Background(), source), uploader, opts, nil))
+	}
+
+	// nothing changed on the source
+	sync()
+	assert.Equal(t, issues, countIssues())
+	assert.Equal(t, comments, countComments())
+
+	// a new issue on the source whose number was taken by an issue created locally since the migration
+	upstream := &models.Issue{RepoID: source.ID, Repo: source, PosterID: doer.ID, Poster: doer, Title: "upstream"}
+	assert.NoError(t, models.NewIssue(source, upstream, nil, nil))
+	assert.NoError(t, models.InsertIssues(&models.Issue{RepoID: repo.ID, Repo: repo, Index: upstream.Index, PosterID: doer.ID, Title: "local"}))
+
+	sync()
+	assert.Equal(t, issues+2, countIssues())
+	assert.Equal(t, comments, countComments())
+	local := db.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: upstream.Index}).(*models.Issue)
+	assert.Equal(t, "local", local.Title)
+	synced := db.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Title: "upstream"}).(*models.Issue)
+	assert.NotEqual(t, upstream.Index, synced.Index)
+}
@@ -0,0 +1,306 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/migrations/base"
+)
+
+// The GitHub migration archive layout, documented in docs/content/doc/usage/backup-and-restore.en-us.md.
+// Every record is identified by its URL on the source instance and references other records by their URL,
+// files inside the archive are referenced by tarball://root/<path relative to the archive root>.
+const (
+	githubArchiveSchemaVersion = "1.0.1"
+	githubArchiveTarballPrefix = "tarball://root/"
+
+	githubArchiveRepositories   = "repositories"
+	githubArchiveUsers          = "users"
+	githubArchiveMilestones     = "milestones"
+	githubArchiveIssues         = "issues"
+	githubArchivePullRequests   = "pull_requests"
+	githubArchiveIssueComments  = "issue_comments"
+	githubArchiveReviews        = "pull_request_reviews"
+	githubArchiveReviewComments = "pull_request_review_comments"
+	githubArchiveReleases       = "releases"
+	githubArchiveReleaseAssets  = "release_assets"
+)
+
+type githubArchiveSchema struct {
+	Version string `json:"version"`
+}
+
+type githubArchiveUser struct {
+	Type   string                    `json:"type"`
+	URL    string                    `json:"url"`
+	ID     int64                     `json:"id"`
+	Login  string                    `json:"login"`
+	Emails []*githubArchiveUserEmail `json:"emails"`
+}
+
+type githubArchiveUserEmail struct {
+	Address string `json:"address"`
+	Primary bool   `json:"primary"`
+}
+
+type githubArchiveLabel struct {
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type githubArchiveRepository struct {
+	Type          string                `json:"type"`
+	URL           string                `json:"url"`
+	Owner         string                `json:"owner"`
+	Name          string                `json:"name"`
+	Description   string                `json:"description"`
+	Private       bool                  `json:"private"`
+	DefaultBranch string                `json:"default_branch"`
+	Topics        []string              `json:"topics"`
+	Labels        []*githubArchiveLabel `json:"labels"`
+	GitURL        string                `json:"git_url"`
+	WikiURL       string                `json:"wiki_url,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+type githubArchiveMilestone struct {
+	Type        string     `json:"type"`
+	URL         string     `json:"url"`
+	Repository  string     `json:"repository"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+type githubArchiveReaction struct {
+	User    string `json:"user"`
+	Content string `json:"content"`
+}
+
+type githubArchiveIssue struct {
+	Type       string                   `json:"type"`
+	URL        string                   `json:"url"`
+	Repository string                   `json:"repository"`
+	User       string                   `json:"user"`
+	Number     int64                    `json:"number"`
+	Title      string                   `json:"title"`
+	Body       string                   `json:"body"`
+	Ref        string                   `json:"ref,omitempty"`
+	State      string                   `json:"state"`
+	Locked     bool                     `json:"locked"`
+	Milestone  string                   `json:"milestone,omitempty"`
+	Labels     []string                 `json:"labels"`
+	Assignees  []string                 `json:"assignees"`
+	Reactions  []*githubArchiveReaction `json:"reactions"`
+	CreatedAt  time.Time                `json:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+	ClosedAt   *time.Time               `json:"closed_at"`
+}
+
+type githubArchivePullRequestBranch struct {
+	Ref      string `json:"ref"`
+	SHA      string `json:"sha"`
+	User     string `json:"user"`
+	Repo     string `json:"repo"`
+	CloneURL string `json:"clone_url,omitempty"`
+}
+
+type githubArchivePullRequest struct {
+	githubArchiveIssue
+	Base           githubArchivePullRequestBranch `json:"base"`
+	Head           githubArchivePullRequestBranch `json:"head"`
+	Merged         bool                           `json:"merged"`
+	MergedAt       *time.Time                     `json:"merged_at"`
+	MergeCommitSHA string                         `json:"merge_commit_sha,omitempty"`
+}
+
+type githubArchiveIssueComment struct {
+	Type        string                   `json:"type"`
+	URL         string                   `json:"url"`
+	Issue       string                   `json:"issue,omitempty"`
+	PullRequest string                   `json:"pull_request,omitempty"`
+	User        string                   `json:"user"`
+	Body        string                   `json:"body"`
+	Reactions   []*githubArchiveReaction `json:"reactions"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+type githubArchiveReview struct {
+	Type        string    `json:"type"`
+	URL         string    `json:"url"`
+	PullRequest string    `json:"pull_request"`
+	User        string    `json:"user"`
+	Body        string    `json:"body"`
+	HeadSHA     string    `json:"head_sha"`
+	State       string    `json:"state"`
+	Official    bool      `json:"official"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type githubArchiveReviewComment struct {
+	Type              string                   `json:"type"`
+	URL               string                   `json:"url"`
+	PullRequest       string                   `json:"pull_request"`
+	PullRequestReview string                   `json:"pull_request_review"`
+	InReplyTo         string                   `json:"in_reply_to,omitempty"`
+	User              string                   `json:"user"`
+	Body              string                   `json:"body"`
+	Path              string                   `json:"path"`
+	DiffHunk          string                   `json:"diff_hunk"`
+	Position          int                      `json:"position"`
+	Line              int                      `json:"line"`
+	CommitID          string                   `json:"commit_id"`
+	Reactions         []*githubArchiveReaction `json:"reactions"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+}
+
+type githubArchiveReleaseAsset struct {
+	Type          string    `json:"type"`
+	URL           string    `json:"url"`
+	Name          string    `json:"name"`
+	ContentType   *string   `json:"content_type"`
+	Size          *int      `json:"size"`
+	DownloadCount *int      `json:"download_count"`
+	AssetURL      string    `json:"asset_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type githubArchiveRelease struct {
+	Type            string                       `json:"type"`
+	URL             string                       `json:"url"`
+	Repository      string                       `json:"repository"`
+	User            string                       `json:"user"`
+	Name            string                       `json:"name"`
+	TagName         string                       `json:"tag_name"`
+	Body            string                       `json:"body"`
+	TargetCommitish string                       `json:"target_commitish"`
+	Draft           bool                         `json:"draft"`
+	Prerelease      bool                         `json:"prerelease"`
+	ReleaseAssets   []*githubArchiveReleaseAsset `json:"release_assets"`
+	CreatedAt       time.Time                    `json:"created_at"`
+	PublishedAt     time.Time                    `json:"published_at"`
+}
+
+// githubArchiveURLs builds the URLs identifying the records of a repository
+type githubArchiveURLs struct {
+	root    string
+	repoURL string
+}
+
+func newGithubArchiveURLs(originalURL, owner, name string) githubArchiveURLs {
+	root := "https://localhost"
+	if u, err := url.Parse(originalURL); err == nil && u.Scheme != "" && u.Host != "" {
+		root = u.Scheme + "://" + u.Host
+	}
+	return githubArchiveURLs{
+		root:    root,
+		repoURL: root + "/" + url.PathEscape(owner) + "/" + url.PathEscape(name),
+	}
+}
+
+func (u githubArchiveURLs) user(login string) string {
+	return u.root + "/" + url.PathEscape(login)
+}
+
+func (u githubArchiveURLs) label(name string) string {
+	return u.repoURL + "/labels/" + url.PathEscape(name)
+}
+
+func (u githubArchiveURLs) milestone(index int) string {
+	return fmt.Sprintf("%s/milestones/%d", u.repoURL, index)
+}
+
+func (u githubArchiveURLs) issue(number int64) string {
+	return fmt.Sprintf("%s/issues/%d", u.repoURL, number)
+}
+
+func (u githubArchiveURLs) pullRequest(number int64) string {
+	return fmt.Sprintf("%s/pull/%d", u.repoURL, number)
+}
+
+func (u githubArchiveURLs) release(tagName string) string {
+	return u.repoURL + "/releases/tag/" + url.PathEscape(tagName)
+}
+
+// githubArchiveFileName returns the name of the n-th file of the records of the kind
+func githubArchiveFileName(kind string, n int) string {
+	return fmt.Sprintf("%s_%06d.json", kind, n)
+}
+
+func writeGithubArchiveFile(baseDir, name string, v interface{}) error {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(baseDir, name), bs, 0o644)
+}
+
+// readGithubArchiveFiles decodes all the files of the records of the kind and appends the records to the slice
+// pointed to by items, GitHub splits large archives into multiple files of the same kind
+func readGithubArchiveFiles(baseDir, kind string, items interface{}) error {
+	matches, err := filepath.Glob(filepath.Join(baseDir, kind+"_*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+
+	slice := reflect.ValueOf(items).Elem()
+	for _, match := range matches {
+		bs, err := os.ReadFile(match)
+		if err != nil {
+			return err
+		}
+		part := reflect.New(slice.Type())
+		if err := json.Unmarshal(bs, part.Interface()); err != nil {
+			return fmt.Errorf("unable to decode %s: %v", filepath.Base(match), err)
+		}
+		slice.Set(reflect.AppendSlice(slice, part.Elem()))
+	}
+	return nil
+}
+
+// githubArchiveTarballPath returns the tarball URL of a path relative to the archive root
+func githubArchiveTarballPath(relPath string) string {
+	return githubArchiveTarballPrefix + path.Clean(filepath.ToSlash(relPath))
+}
+
+// githubArchiveLocalPath returns the local path of a tarball URL of an archive extracted to baseDir
+func githubArchiveLocalPath(baseDir, tarballURL string) (string, error) {
+	if !strings.HasPrefix(tarballURL, githubArchiveTarballPrefix) {
+		return "", fmt.Errorf("unsupported archive path: %s", tarballURL)
+	}
+	relPath := path.Clean("/" + strings.TrimPrefix(tarballURL, githubArchiveTarballPrefix))
+	return filepath.Join(baseDir, filepath.FromSlash(relPath)), nil
+}
+
+func toGithubArchiveReactions(urls githubArchiveURLs, reactions []*base.Reaction) []*githubArchiveReaction {
+	res := make([]*githubArchiveReaction, 0, len(reactions))
+	for _, reaction := range reactions {
+		res = append(res, &githubArchiveReaction{
+			User:    urls.user(reaction.UserName),
+			Content: reaction.Content,
+		})
+	}
+	return res
+}
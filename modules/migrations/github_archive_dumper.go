@@ -0,0 +1,462 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+)
+
+var (
+	_ base.Uploader = &GithubArchiveDumper{}
+)
+
+// GithubArchiveDumper implements an Uploader writing a repository in the GitHub migration archive layout
+// to the local directory
+type GithubArchiveDumper struct {
+	ctx       context.Context
+	baseDir   string
+	repoOwner string
+	repoName  string
+	opts      base.MigrateOptions
+	urls      githubArchiveURLs
+
+	repo           *githubArchiveRepository
+	users          map[string]*githubArchiveUser
+	userLogins     map[int64]string
+	milestones     []*githubArchiveMilestone
+	milestoneURLs  map[string]string
+	issues         []*githubArchiveIssue
+	pullRequests   []*githubArchivePullRequest
+	comments       []*githubArchiveIssueComment
+	reviews        []*githubArchiveReview
+	reviewComments []*githubArchiveReviewComment
+	releases       []*githubArchiveRelease
+	isPull         map[int64]bool
+
+	gitRepo *git.Repository
+}
+
+// NewGithubArchiveDumper creates an Uploader writing the GitHub migration archive layout to <baseDir>/<repoOwner>/<repoName>
+func NewGithubArchiveDumper(ctx context.Context, baseDir, repoOwner, repoName string, opts base.MigrateOptions) (*GithubArchiveDumper, error) {
+	baseDir = filepath.Join(baseDir, repoOwner, repoName)
+	if err := os.MkdirAll(baseDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &GithubArchiveDumper{
+		ctx:           ctx,
+		baseDir:       baseDir,
+		repoOwner:     repoOwner,
+		repoName:      repoName,
+		opts:          opts,
+		users:         make(map[string]*githubArchiveUser),
+		userLogins:    make(map[int64]string),
+		milestoneURLs: make(map[string]string),
+		isPull:        make(map[int64]bool),
+
+		milestones:     []*githubArchiveMilestone{},
+		issues:         []*githubArchiveIssue{},
+		pullRequests:   []*githubArchivePullRequest{},
+		comments:       []*githubArchiveIssueComment{},
+		reviews:        []*githubArchiveReview{},
+		reviewComments: []*githubArchiveReviewComment{},
+		releases:       []*githubArchiveRelease{},
+	}, nil
+}
+
+// MaxBatchInsertSize returns the table's max batch insert size
+func (g *GithubArchiveDumper) MaxBatchInsertSize(tp string) int {
+	return 1000
+}
+
+func (g *GithubArchiveDumper) gitRelPath() string {
+	return path.Join("repositories", g.repoOwner, g.repoName+".git")
+}
+
+func (g *GithubArchiveDumper) wikiRelPath() string {
+	return path.Join("repositories", g.repoOwner, g.repoName+".wiki.git")
+}
+
+// user registers a poster of the archived records and returns its URL
+func (g *GithubArchiveDumper) user(id int64, login, email string) string {
+	if login == "" {
+		login = g.userLogins[id]
+	}
+	if login == "" {
+		login = "ghost"
+	}
+	u, ok := g.users[login]
+	if !ok {
+		u = &githubArchiveUser{
+			Type:   "user",
+			URL:    g.urls.user(login),
+			ID:     id,
+			Login:  login,
+			Emails: []*githubArchiveUserEmail{},
+		}
+		g.users[login] = u
+	}
+	if u.ID == 0 {
+		u.ID = id
+	}
+	if id > 0 {
+		g.userLogins[id] = login
+	}
+	if email != "" && len(u.Emails) == 0 {
+		u.Emails = append(u.Emails, &githubArchiveUserEmail{Address: email, Primary: true})
+	}
+	return u.URL
+}
+
+func (g *GithubArchiveDumper) reactions(reactions []*base.Reaction) []*githubArchiveReaction {
+	for _, reaction := range reactions {
+		g.user(reaction.UserID, reaction.UserName, "")
+	}
+	return toGithubArchiveReactions(g.urls, reactions)
+}
+
+func (g *GithubArchiveDumper) issueRecord(tp, url string, number int64, posterID int64, posterName, posterEmail, title, content, milestone, state string, locked bool,
+	labels []*base.Label, assignees []string, reactions []*base.Reaction, created, updated time.Time, closed *time.Time) githubArchiveIssue {
+	issue := githubArchiveIssue{
+		Type:       tp,
+		URL:        url,
+		Repository: g.urls.repoURL,
+		User:       g.user(posterID, posterName, posterEmail),
+		Number:     number,
+		Title:      title,
+		Body:       content,
+		State:      state,
+		Locked:     locked,
+		Milestone:  g.milestoneURLs[milestone],
+		Labels:     make([]string, 0, len(labels)),
+		Assignees:  make([]string, 0, len(assignees)),
+		Reactions:  g.reactions(reactions),
+		CreatedAt:  created,
+		UpdatedAt:  updated,
+		ClosedAt:   closed,
+	}
+	for _, label := range labels {
+		issue.Labels = append(issue.Labels, g.urls.label(label.Name))
+	}
+	for _, assignee := range assignees {
+		issue.Assignees = append(issue.Assignees, g.user(0, assignee, ""))
+	}
+	return issue
+}
+
+// CreateRepo creates a repository
+func (g *GithubArchiveDumper) CreateRepo(repo *base.Repository, opts base.MigrateOptions) error {
+	owner, name := repo.Owner, repo.Name
+	if owner == "" {
+		owner = g.repoOwner
+	}
+	if name == "" {
+		name = g.repoName
+	}
+	g.repoOwner, g.repoName = owner, name
+	originalURL := repo.OriginalURL
+	if originalURL == "" {
+		originalURL = opts.CloneAddr
+	}
+	g.urls = newGithubArchiveURLs(originalURL, owner, name)
+	g.repo = &githubArchiveRepository{
+		Type:          "repository",
+		URL:           g.urls.repoURL,
+		Owner:         g.user(0, owner, ""),
+		Name:          name,
+		Description:   repo.Description,
+		Private:       opts.Private,
+		DefaultBranch: repo.DefaultBranch,
+		Topics:        []string{},
+		Labels:        []*githubArchiveLabel{},
+		CreatedAt:     time.Now(),
+	}
+	g.repo.GitURL = githubArchiveTarballPath(g.gitRelPath())
+
+	remoteAddr, err := setURLToken(repo.CloneURL, g.opts)
+	if err != nil {
+		return err
+	}
+
+	wikiPath := ""
+	if opts.Wiki {
+		wikiPath = filepath.Join(g.baseDir, filepath.FromSlash(g.wikiRelPath()))
+	}
+	hasWiki, err := cloneRepositoryData(remoteAddr, filepath.Join(g.baseDir, filepath.FromSlash(g.gitRelPath())), wikiPath)
+	if err != nil {
+		return err
+	}
+	if hasWiki {
+		g.repo.WikiURL = githubArchiveTarballPath(g.wikiRelPath())
+	}
+
+	g.gitRepo, err = git.OpenRepository(filepath.Join(g.baseDir, filepath.FromSlash(g.gitRelPath())))
+	return err
+}
+
+// Close closes this uploader
+func (g *GithubArchiveDumper) Close() {
+	if g.gitRepo != nil {
+		g.gitRepo.Close()
+	}
+}
+
+// CreateTopics creates topics
+func (g *GithubArchiveDumper) CreateTopics(topics ...string) error {
+	g.repo.Topics = append(g.repo.Topics, topics...)
+	return nil
+}
+
+// CreateMilestones creates milestones
+func (g *GithubArchiveDumper) CreateMilestones(milestones ...*base.Milestone) error {
+	for _, milestone := range milestones {
+		url := g.urls.milestone(len(g.milestones) + 1)
+		g.milestoneURLs[milestone.Title] = url
+		g.milestones = append(g.milestones, &githubArchiveMilestone{
+			Type:        "milestone",
+			URL:         url,
+			Repository:  g.urls.repoURL,
+			Title:       milestone.Title,
+			Description: milestone.Description,
+			State:       milestone.State,
+			DueOn:       milestone.Deadline,
+			CreatedAt:   milestone.Created,
+			UpdatedAt:   milestone.Updated,
+			ClosedAt:    milestone.Closed,
+		})
+	}
+	return nil
+}
+
+// CreateLabels creates labels
+func (g *GithubArchiveDumper) CreateLabels(labels ...*base.Label) error {
+	for _, label := range labels {
+		g.repo.Labels = append(g.repo.Labels, &githubArchiveLabel{
+			URL:         g.urls.label(label.Name),
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+		})
+	}
+	return nil
+}
+
+// CreateReleases creates releases
+func (g *GithubArchiveDumper) CreateReleases(releases ...*base.Release) error {
+	for _, release := range releases {
+		rel := &githubArchiveRelease{
+			Type:            "release",
+			URL:             g.urls.release(release.TagName),
+			Repository:      g.urls.repoURL,
+			User:            g.user(release.PublisherID, release.PublisherName, release.PublisherEmail),
+			Name:            release.Name,
+			TagName:         release.TagName,
+			Body:            release.Body,
+			TargetCommitish: release.TargetCommitish,
+			Draft:           release.Draft,
+			Prerelease:      release.Prerelease,
+			ReleaseAssets:   make([]*githubArchiveReleaseAsset, 0, len(release.Assets)),
+			CreatedAt:       release.Created,
+			PublishedAt:     release.Published,
+		}
+
+		if g.opts.ReleaseAssets && len(release.Assets) > 0 {
+			attachDir := path.Join(githubArchiveReleaseAssets, g.repoOwner, g.repoName, release.TagName)
+			if err := os.MkdirAll(filepath.Join(g.baseDir, filepath.FromSlash(attachDir)), os.ModePerm); err != nil {
+				return err
+			}
+			for _, asset := range release.Assets {
+				attachRelPath := path.Join(attachDir, asset.Name)
+				if err := downloadReleaseAsset(asset, filepath.Join(g.baseDir, filepath.FromSlash(attachRelPath))); err != nil {
+					return err
+				}
+				rel.ReleaseAssets = append(rel.ReleaseAssets, &githubArchiveReleaseAsset{
+					Type:          "release_asset",
+					URL:           fmt.Sprintf("%s/releases/assets/%d", g.urls.repoURL, asset.ID),
+					Name:          asset.Name,
+					ContentType:   asset.ContentType,
+					Size:          asset.Size,
+					DownloadCount: asset.DownloadCount,
+					AssetURL:      githubArchiveTarballPath(attachRelPath),
+					CreatedAt:     asset.Created,
+					UpdatedAt:     asset.Updated,
+				})
+			}
+		}
+		g.releases = append(g.releases, rel)
+	}
+	return nil
+}
+
+// SyncTags syncs releases with tags in the database
+func (g *GithubArchiveDumper) SyncTags() error {
+	return nil
+}
+
+// CreateIssues creates issues
+func (g *GithubArchiveDumper) CreateIssues(issues ...*base.Issue) error {
+	for _, issue := range issues {
+		record := g.issueRecord("issue", g.urls.issue(issue.Number), issue.Number, issue.PosterID, issue.PosterName, issue.PosterEmail,
+			issue.Title, issue.Content, issue.Milestone, issue.State, issue.IsLocked,
+			issue.Labels, issue.Assignees, issue.Reactions, issue.Created, issue.Updated, issue.Closed)
+		record.Ref = issue.Ref
+		g.issues = append(g.issues, &record)
+	}
+	return nil
+}
+
+// CreateComments creates comments of issues
+func (g *GithubArchiveDumper) CreateComments(comments ...*base.Comment) error {
+	for _, comment := range comments {
+		record := &githubArchiveIssueComment{
+			Type:      "issue_comment",
+			User:      g.user(comment.PosterID, comment.PosterName, comment.PosterEmail),
+			Body:      comment.Content,
+			Reactions: g.reactions(comment.Reactions),
+			CreatedAt: comment.Created,
+			UpdatedAt: comment.Updated,
+		}
+		if g.isPull[comment.IssueIndex] {
+			record.PullRequest = g.urls.pullRequest(comment.IssueIndex)
+			record.URL = fmt.Sprintf("%s#issuecomment-%d", record.PullRequest, len(g.comments)+1)
+		} else {
+			record.Issue = g.urls.issue(comment.IssueIndex)
+			record.URL = fmt.Sprintf("%s#issuecomment-%d", record.Issue, len(g.comments)+1)
+		}
+		g.comments = append(g.comments, record)
+	}
+	return nil
+}
+
+// CreatePullRequests creates pull requests
+func (g *GithubArchiveDumper) CreatePullRequests(prs ...*base.PullRequest) error {
+	for _, pr := range prs {
+		// keep the head of the pull request available in the archived git repository
+		if pr.Head.SHA != "" {
+			if _, err := git.NewCommand("update-ref", fmt.Sprintf("refs/pull/%d/head", pr.Number), pr.Head.SHA).RunInDir(g.gitRepo.Path); err != nil {
+				log.Warn("Unable to set the head of pull request %d: %v", pr.Number, err)
+			}
+		}
+
+		g.isPull[pr.Number] = true
+		record := &githubArchivePullRequest{
+			githubArchiveIssue: g.issueRecord("pull_request", g.urls.pullRequest(pr.Number), pr.Number, pr.PosterID, pr.PosterName, pr.PosterEmail,
+				pr.Title, pr.Content, pr.Milestone, pr.State, pr.IsLocked,
+				pr.Labels, pr.Assignees, pr.Reactions, pr.Created, pr.Updated, pr.Closed),
+			Base:           g.pullRequestBranch(pr.Base),
+			Head:           g.pullRequestBranch(pr.Head),
+			Merged:         pr.Merged,
+			MergedAt:       pr.MergedTime,
+			MergeCommitSHA: pr.MergeCommitSHA,
+		}
+		g.pullRequests = append(g.pullRequests, record)
+	}
+	return nil
+}
+
+func (g *GithubArchiveDumper) pullRequestBranch(branch base.PullRequestBranch) githubArchivePullRequestBranch {
+	return githubArchivePullRequestBranch{
+		Ref:      branch.Ref,
+		SHA:      branch.SHA,
+		User:     g.urls.user(branch.OwnerName),
+		Repo:     g.urls.user(branch.OwnerName) + "/" + branch.RepoName,
+		CloneURL: branch.CloneURL,
+	}
+}
+
+// CreateReviews create pull request reviews
+func (g *GithubArchiveDumper) CreateReviews(reviews ...*base.Review) error {
+	for _, review := range reviews {
+		pullURL := g.urls.pullRequest(review.IssueIndex)
+		reviewer := g.user(review.ReviewerID, review.ReviewerName, "")
+		record := &githubArchiveReview{
+			Type:        "pull_request_review",
+			URL:         fmt.Sprintf("%s#pullrequestreview-%d", pullURL, review.ID),
+			PullRequest: pullURL,
+			User:        reviewer,
+			Body:        review.Content,
+			HeadSHA:     review.CommitID,
+			State:       review.State,
+			Official:    review.Official,
+			CreatedAt:   review.CreatedAt,
+		}
+		g.reviews = append(g.reviews, record)
+
+		for _, comment := range review.Comments {
+			user := reviewer
+			if login, ok := g.userLogins[comment.PosterID]; ok {
+				user = g.urls.user(login)
+			}
+			reviewComment := &githubArchiveReviewComment{
+				Type:              "pull_request_review_comment",
+				URL:               fmt.Sprintf("%s#discussion_r%d", pullURL, comment.ID),
+				PullRequest:       pullURL,
+				PullRequestReview: record.URL,
+				User:              user,
+				Body:              comment.Content,
+				Path:              comment.TreePath,
+				DiffHunk:          comment.DiffHunk,
+				Position:          comment.Position,
+				Line:              comment.Line,
+				CommitID:          comment.CommitID,
+				Reactions:         g.reactions(comment.Reactions),
+				CreatedAt:         comment.CreatedAt,
+				UpdatedAt:         comment.UpdatedAt,
+			}
+			if comment.InReplyTo > 0 {
+				reviewComment.InReplyTo = fmt.Sprintf("%s#discussion_r%d", pullURL, comment.InReplyTo)
+			}
+			g.reviewComments = append(g.reviewComments, reviewComment)
+		}
+	}
+	return nil
+}
+
+// Rollback when migrating failed, this will rollback all the changes.
+func (g *GithubArchiveDumper) Rollback() error {
+	g.Close()
+	return os.RemoveAll(g.baseDir)
+}
+
+// Finish when migrating succeed, this will write the records of the archive.
+func (g *GithubArchiveDumper) Finish() error {
+	users := make([]*githubArchiveUser, 0, len(g.users))
+	for _, u := range g.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Login < users[j].Login
+	})
+
+	files := []struct {
+		kind  string
+		items interface{}
+	}{
+		{githubArchiveRepositories, []*githubArchiveRepository{g.repo}},
+		{githubArchiveUsers, users},
+		{githubArchiveMilestones, g.milestones},
+		{githubArchiveIssues, g.issues},
+		{githubArchivePullRequests, g.pullRequests},
+		{githubArchiveIssueComments, g.comments},
+		{githubArchiveReviews, g.reviews},
+		{githubArchiveReviewComments, g.reviewComments},
+		{githubArchiveReleases, g.releases},
+	}
+	for _, file := range files {
+		if err := writeGithubArchiveFile(g.baseDir, githubArchiveFileName(file.kind, 1), file.items); err != nil {
+			return err
+		}
+	}
+	return writeGithubArchiveFile(g.baseDir, "schema.json", githubArchiveSchema{Version: githubArchiveSchemaVersion})
+}
@@ -0,0 +1,424 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/migrations/base"
+)
+
+var (
+	_ base.Downloader = &GithubArchiveRestorer{}
+)
+
+// GithubArchiveRestorer implements a Downloader from a repository in the GitHub migration archive layout
+// extracted to the local directory
+type GithubArchiveRestorer struct {
+	base.NullDownloader
+	ctx       context.Context
+	baseDir   string
+	repoOwner string
+	repoName  string
+
+	repo           *githubArchiveRepository
+	users          map[string]*githubArchiveUser
+	milestones     []*githubArchiveMilestone
+	issues         []*githubArchiveIssue
+	pullRequests   []*githubArchivePullRequest
+	comments       []*githubArchiveIssueComment
+	reviews        []*githubArchiveReview
+	reviewComments []*githubArchiveReviewComment
+	releases       []*githubArchiveRelease
+
+	milestoneTitles map[string]string
+	labels          map[string]*githubArchiveLabel
+}
+
+// NewGithubArchiveRestorer creates a Downloader reading the GitHub migration archive extracted to baseDir
+func NewGithubArchiveRestorer(ctx context.Context, baseDir, owner, repoName string) (*GithubArchiveRestorer, error) {
+	baseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	r := &GithubArchiveRestorer{
+		ctx:             ctx,
+		baseDir:         baseDir,
+		repoOwner:       owner,
+		repoName:        repoName,
+		users:           make(map[string]*githubArchiveUser),
+		milestoneTitles: make(map[string]string),
+		labels:          make(map[string]*githubArchiveLabel),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *GithubArchiveRestorer) load() error {
+	var schema githubArchiveSchema
+	bs, err := os.ReadFile(filepath.Join(r.baseDir, "schema.json"))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(bs, &schema); err != nil {
+		return fmt.Errorf("unable to decode schema.json: %v", err)
+	} else if schema.Version == "" {
+		return errors.New("schema.json does not contain the version of the archive")
+	}
+
+	var repos []*githubArchiveRepository
+	var users []*githubArchiveUser
+	files := []struct {
+		kind  string
+		items interface{}
+	}{
+		{githubArchiveRepositories, &repos},
+		{githubArchiveUsers, &users},
+		{githubArchiveMilestones, &r.milestones},
+		{githubArchiveIssues, &r.issues},
+		{githubArchivePullRequests, &r.pullRequests},
+		{githubArchiveIssueComments, &r.comments},
+		{githubArchiveReviews, &r.reviews},
+		{githubArchiveReviewComments, &r.reviewComments},
+		{githubArchiveReleases, &r.releases},
+	}
+	for _, file := range files {
+		if err := readGithubArchiveFiles(r.baseDir, file.kind, file.items); err != nil {
+			return err
+		}
+	}
+
+	if len(repos) != 1 {
+		return fmt.Errorf("the archive must contain exactly one repository but contains %d", len(repos))
+	}
+	r.repo = repos[0]
+	if r.repo.GitURL == "" {
+		return errors.New("the archive does not contain the git repository")
+	}
+
+	for _, u := range users {
+		r.users[u.URL] = u
+	}
+	for _, milestone := range r.milestones {
+		r.milestoneTitles[milestone.URL] = milestone.Title
+	}
+	for _, label := range r.repo.Labels {
+		r.labels[label.URL] = label
+	}
+	return nil
+}
+
+// SetContext set context
+func (r *GithubArchiveRestorer) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// IsPrivate returns whether the archived repository is private
+func (r *GithubArchiveRestorer) IsPrivate() bool {
+	return r.repo.Private
+}
+
+// user returns the id, login and email of the user with the URL
+func (r *GithubArchiveRestorer) user(url string) (int64, string, string) {
+	u, ok := r.users[url]
+	if !ok {
+		return 0, path.Base(url), ""
+	}
+	for _, email := range u.Emails {
+		if email.Primary {
+			return u.ID, u.Login, email.Address
+		}
+	}
+	return u.ID, u.Login, ""
+}
+
+func (r *GithubArchiveRestorer) localPath(tarballURL string) (string, error) {
+	return githubArchiveLocalPath(r.baseDir, tarballURL)
+}
+
+func (r *GithubArchiveRestorer) reactions(reactions []*githubArchiveReaction) []*base.Reaction {
+	res := make([]*base.Reaction, 0, len(reactions))
+	for _, reaction := range reactions {
+		id, login, _ := r.user(reaction.User)
+		res = append(res, &base.Reaction{
+			UserID:   id,
+			UserName: login,
+			Content:  reaction.Content,
+		})
+	}
+	return res
+}
+
+func (r *GithubArchiveRestorer) issueLabels(urls []string) []*base.Label {
+	labels := make([]*base.Label, 0, len(urls))
+	for _, url := range urls {
+		label, ok := r.labels[url]
+		if !ok {
+			continue
+		}
+		labels = append(labels, &base.Label{
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+		})
+	}
+	return labels
+}
+
+func (r *GithubArchiveRestorer) assignees(urls []string) []string {
+	assignees := make([]string, 0, len(urls))
+	for _, url := range urls {
+		_, login, _ := r.user(url)
+		assignees = append(assignees, login)
+	}
+	return assignees
+}
+
+// GetRepoInfo returns a repository information
+func (r *GithubArchiveRestorer) GetRepoInfo() (*base.Repository, error) {
+	cloneURL, err := r.localPath(r.repo.GitURL)
+	if err != nil {
+		return nil, err
+	}
+	return &base.Repository{
+		Owner:         r.repoOwner,
+		Name:          r.repoName,
+		IsPrivate:     r.repo.Private,
+		Description:   r.repo.Description,
+		OriginalURL:   r.repo.URL,
+		CloneURL:      cloneURL,
+		DefaultBranch: r.repo.DefaultBranch,
+	}, nil
+}
+
+// GetTopics return repository topics
+func (r *GithubArchiveRestorer) GetTopics() ([]string, error) {
+	return r.repo.Topics, nil
+}
+
+// GetMilestones returns milestones
+func (r *GithubArchiveRestorer) GetMilestones() ([]*base.Milestone, error) {
+	milestones := make([]*base.Milestone, 0, len(r.milestones))
+	for _, milestone := range r.milestones {
+		milestones = append(milestones, &base.Milestone{
+			Title:       milestone.Title,
+			Description: milestone.Description,
+			Deadline:    milestone.DueOn,
+			Created:     milestone.CreatedAt,
+			Updated:     milestone.UpdatedAt,
+			Closed:      milestone.ClosedAt,
+			State:       milestone.State,
+		})
+	}
+	return milestones, nil
+}
+
+// GetReleases returns releases
+func (r *GithubArchiveRestorer) GetReleases() ([]*base.Release, error) {
+	releases := make([]*base.Release, 0, len(r.releases))
+	for _, release := range r.releases {
+		id, login, email := r.user(release.User)
+		rel := &base.Release{
+			TagName:         release.TagName,
+			TargetCommitish: release.TargetCommitish,
+			Name:            release.Name,
+			Body:            release.Body,
+			Draft:           release.Draft,
+			Prerelease:      release.Prerelease,
+			PublisherID:     id,
+			PublisherName:   login,
+			PublisherEmail:  email,
+			Assets:          make([]*base.ReleaseAsset, 0, len(release.ReleaseAssets)),
+			Created:         release.CreatedAt,
+			Published:       release.PublishedAt,
+		}
+		for i, asset := range release.ReleaseAssets {
+			localPath, err := r.localPath(asset.AssetURL)
+			if err != nil {
+				return nil, err
+			}
+			downloadURL := "file://" + localPath
+			rel.Assets = append(rel.Assets, &base.ReleaseAsset{
+				ID:            int64(i + 1),
+				Name:          asset.Name,
+				ContentType:   asset.ContentType,
+				Size:          asset.Size,
+				DownloadCount: asset.DownloadCount,
+				Created:       asset.CreatedAt,
+				Updated:       asset.UpdatedAt,
+				DownloadURL:   &downloadURL,
+			})
+		}
+		releases = append(releases, rel)
+	}
+	return releases, nil
+}
+
+// GetLabels returns labels
+func (r *GithubArchiveRestorer) GetLabels() ([]*base.Label, error) {
+	labels := make([]*base.Label, 0, len(r.repo.Labels))
+	for _, label := range r.repo.Labels {
+		labels = append(labels, &base.Label{
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+		})
+	}
+	return labels, nil
+}
+
+// GetIssues returns issues according start and limit
+func (r *GithubArchiveRestorer) GetIssues(page, perPage int) ([]*base.Issue, bool, error) {
+	issues := make([]*base.Issue, 0, len(r.issues))
+	for _, issue := range r.issues {
+		id, login, email := r.user(issue.User)
+		issues = append(issues, &base.Issue{
+			Number:      issue.Number,
+			PosterID:    id,
+			PosterName:  login,
+			PosterEmail: email,
+			Title:       issue.Title,
+			Content:     issue.Body,
+			Ref:         issue.Ref,
+			Milestone:   r.milestoneTitles[issue.Milestone],
+			State:       issue.State,
+			IsLocked:    issue.Locked,
+			Created:     issue.CreatedAt,
+			Updated:     issue.UpdatedAt,
+			Closed:      issue.ClosedAt,
+			Labels:      r.issueLabels(issue.Labels),
+			Reactions:   r.reactions(issue.Reactions),
+			Assignees:   r.assignees(issue.Assignees),
+			Context:     base.BasicIssueContext(issue.Number),
+		})
+	}
+	return issues, true, nil
+}
+
+// GetComments returns comments according issueNumber
+func (r *GithubArchiveRestorer) GetComments(opts base.GetCommentOptions) ([]*base.Comment, bool, error) {
+	number := opts.Context.ForeignID()
+	issueURL := fmt.Sprintf("%s/issues/%d", r.repo.URL, number)
+	pullURL := fmt.Sprintf("%s/pull/%d", r.repo.URL, number)
+
+	comments := make([]*base.Comment, 0, 10)
+	for _, comment := range r.comments {
+		if comment.Issue != issueURL && comment.PullRequest != pullURL {
+			continue
+		}
+		id, login, email := r.user(comment.User)
+		comments = append(comments, &base.Comment{
+			IssueIndex:  number,
+			PosterID:    id,
+			PosterName:  login,
+			PosterEmail: email,
+			Created:     comment.CreatedAt,
+			Updated:     comment.UpdatedAt,
+			Content:     comment.Body,
+			Reactions:   r.reactions(comment.Reactions),
+		})
+	}
+	return comments, false, nil
+}
+
+func (r *GithubArchiveRestorer) pullRequestBranch(branch githubArchivePullRequestBranch) base.PullRequestBranch {
+	_, login, _ := r.user(branch.User)
+	return base.PullRequestBranch{
+		CloneURL:  branch.CloneURL,
+		Ref:       branch.Ref,
+		SHA:       branch.SHA,
+		RepoName:  path.Base(branch.Repo),
+		OwnerName: login,
+	}
+}
+
+// GetPullRequests returns pull requests according page and perPage
+func (r *GithubArchiveRestorer) GetPullRequests(page, perPage int) ([]*base.PullRequest, bool, error) {
+	pulls := make([]*base.PullRequest, 0, len(r.pullRequests))
+	for _, pr := range r.pullRequests {
+		id, login, email := r.user(pr.User)
+		pulls = append(pulls, &base.PullRequest{
+			Number:         pr.Number,
+			Title:          pr.Title,
+			PosterName:     login,
+			PosterID:       id,
+			PosterEmail:    email,
+			Content:        pr.Body,
+			Milestone:      r.milestoneTitles[pr.Milestone],
+			State:          pr.State,
+			Created:        pr.CreatedAt,
+			Updated:        pr.UpdatedAt,
+			Closed:         pr.ClosedAt,
+			Labels:         r.issueLabels(pr.Labels),
+			Merged:         pr.Merged,
+			MergedTime:     pr.MergedAt,
+			MergeCommitSHA: pr.MergeCommitSHA,
+			Head:           r.pullRequestBranch(pr.Head),
+			Base:           r.pullRequestBranch(pr.Base),
+			Assignees:      r.assignees(pr.Assignees),
+			IsLocked:       pr.Locked,
+			Reactions:      r.reactions(pr.Reactions),
+			Context:        base.BasicIssueContext(pr.Number),
+		})
+	}
+	return pulls, true, nil
+}
+
+// GetReviews returns pull requests review
+func (r *GithubArchiveRestorer) GetReviews(context base.IssueContext) ([]*base.Review, error) {
+	pullURL := fmt.Sprintf("%s/pull/%d", r.repo.URL, context.ForeignID())
+
+	reviews := make([]*base.Review, 0, 10)
+	for i, review := range r.reviews {
+		if review.PullRequest != pullURL {
+			continue
+		}
+		id, login, _ := r.user(review.User)
+		rev := &base.Review{
+			ID:           int64(i + 1),
+			IssueIndex:   context.ForeignID(),
+			ReviewerID:   id,
+			ReviewerName: login,
+			Official:     review.Official,
+			CommitID:     review.HeadSHA,
+			Content:      review.Body,
+			CreatedAt:    review.CreatedAt,
+			State:        review.State,
+		}
+
+		commentIDs := make(map[string]int64)
+		for j, comment := range r.reviewComments {
+			if comment.PullRequestReview != review.URL {
+				continue
+			}
+			commentIDs[comment.URL] = int64(j + 1)
+			posterID, _, _ := r.user(comment.User)
+			rev.Comments = append(rev.Comments, &base.ReviewComment{
+				ID:        int64(j + 1),
+				InReplyTo: commentIDs[comment.InReplyTo],
+				Content:   comment.Body,
+				TreePath:  comment.Path,
+				DiffHunk:  comment.DiffHunk,
+				Position:  comment.Position,
+				Line:      comment.Line,
+				CommitID:  comment.CommitID,
+				PosterID:  posterID,
+				Reactions: r.reactions(comment.Reactions),
+				CreatedAt: comment.CreatedAt,
+				UpdatedAt: comment.UpdatedAt,
+			})
+		}
+		reviews = append(reviews, rev)
+	}
+	return reviews, nil
+}
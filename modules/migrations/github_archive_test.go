@@ -0,0 +1,93 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubArchiveLocalPath(t *testing.T) {
+	p, err := githubArchiveLocalPath("/archive", "tarball://root/repositories/user2/repo1.git")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/archive/repositories/user2/repo1.git"), p)
+
+	p, err = githubArchiveLocalPath("/archive", "tarball://root/../../etc/passwd")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/archive/etc/passwd"), p)
+
+	_, err = githubArchiveLocalPath("/archive", "https://example.com/repo.git")
+	assert.Error(t, err)
+}
+
+func TestGithubArchiveExportImport(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.GetOwner())
+
+	opts := base.MigrateOptions{RepoName: repo.Name}
+	updateOptionsUnits(&opts, nil)
+
+	baseDir := t.TempDir()
+	dumper, err := NewGithubArchiveDumper(context.Background(), baseDir, repo.OwnerName, repo.Name, opts)
+	assert.NoError(t, err)
+	assert.NoError(t, migrateRepository(NewLocalDownloader(context.Background(), repo), dumper, opts, nil))
+
+	archiveDir := filepath.Join(baseDir, repo.OwnerName, repo.Name)
+	assert.FileExists(t, filepath.Join(archiveDir, "schema.json"))
+	assert.FileExists(t, filepath.Join(archiveDir, "issues_000001.json"))
+	assert.DirExists(t, filepath.Join(archiveDir, "repositories", "user2", "repo1.git"))
+
+	restorer, err := NewGithubArchiveRestorer(context.Background(), archiveDir, "user2", "restored")
+	assert.NoError(t, err)
+
+	info, err := restorer.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "user2", info.Owner)
+	assert.Equal(t, "restored", info.Name)
+	assert.Equal(t, filepath.Join(archiveDir, "repositories", "user2", "repo1.git"), info.CloneURL)
+
+	labels, err := restorer.GetLabels()
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+
+	milestones, err := restorer.GetMilestones()
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 3)
+
+	issues, isEnd, err := restorer.GetIssues(1, 50)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 1, issues[0].Number)
+		assert.Equal(t, "issue1", issues[0].Title)
+		assert.Equal(t, "user1", issues[0].PosterName)
+		assert.EqualValues(t, 1, issues[0].PosterID)
+		assert.Equal(t, "open", issues[0].State)
+		assert.Equal(t, "closed", issues[1].State)
+		assert.Len(t, issues[0].Labels, 1)
+	}
+
+	downloader := NewLocalDownloader(context.Background(), repo)
+	expected, _, err := downloader.GetComments(base.GetCommentOptions{Context: base.BasicIssueContext(1)})
+	assert.NoError(t, err)
+	comments, _, err := restorer.GetComments(base.GetCommentOptions{Context: issues[0].Context})
+	assert.NoError(t, err)
+	if assert.Len(t, comments, len(expected)) {
+		for i, c := range comments {
+			assert.EqualValues(t, 1, c.IssueIndex)
+			assert.Equal(t, expected[i].Content, c.Content)
+			assert.Equal(t, expected[i].PosterName, c.PosterName)
+		}
+	}
+}
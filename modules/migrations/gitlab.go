@@ -497,6 +497,7 @@ func (g *GitlabDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Com
 			if !comment.IndividualNote {
 				for _, note := range comment.Notes {
 					allComments = append(allComments, &base.Comment{
+						ID:          int64(note.ID),
 						IssueIndex:  context.LocalID(),
 						PosterID:    int64(note.Author.ID),
 						PosterName:  note.Author.Username,
@@ -508,6 +509,7 @@ func (g *GitlabDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Com
 			} else {
 				c := comment.Notes[0]
 				allComments = append(allComments, &base.Comment{
+					ID:          int64(c.ID),
 					IssueIndex:  context.LocalID(),
 					PosterID:    int64(c.Author.ID),
 					PosterName:  c.Author.Username,
@@ -0,0 +1,488 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var (
+	_ base.Downloader = &LocalDownloader{}
+)
+
+// LocalDownloader implements a Downloader reading a repository of this instance from the database,
+// so that it can be dumped to a directory and restored later
+type LocalDownloader struct {
+	base.NullDownloader
+	ctx   context.Context
+	repo  *models.Repository
+	users map[int64]*models.User
+}
+
+// NewLocalDownloader creates a downloader for a repository of this instance
+func NewLocalDownloader(ctx context.Context, repo *models.Repository) *LocalDownloader {
+	return &LocalDownloader{
+		ctx:   ctx,
+		repo:  repo,
+		users: make(map[int64]*models.User),
+	}
+}
+
+// SetContext set context
+func (d *LocalDownloader) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+func (d *LocalDownloader) getUser(id int64) (*models.User, error) {
+	if u, ok := d.users[id]; ok {
+		return u, nil
+	}
+	u, err := models.GetUserByID(id)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		u = models.NewGhostUser()
+	}
+	d.users[id] = u
+	return u, nil
+}
+
+func timeStampPtr(ts timeutil.TimeStamp) *time.Time {
+	if ts == 0 {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// GetRepoInfo returns a repository information
+func (d *LocalDownloader) GetRepoInfo() (*base.Repository, error) {
+	return &base.Repository{
+		Name:          d.repo.Name,
+		Owner:         d.repo.OwnerName,
+		IsPrivate:     d.repo.IsPrivate,
+		Description:   d.repo.Description,
+		CloneURL:      d.repo.RepoPath(),
+		OriginalURL:   d.repo.OriginalURL,
+		DefaultBranch: d.repo.DefaultBranch,
+	}, nil
+}
+
+// GetTopics return the topics of the repository
+func (d *LocalDownloader) GetTopics() ([]string, error) {
+	return d.repo.Topics, nil
+}
+
+// GetMilestones returns milestones
+func (d *LocalDownloader) GetMilestones() ([]*base.Milestone, error) {
+	milestones, _, err := models.GetMilestones(models.GetMilestonesOption{
+		RepoID: d.repo.ID,
+		State:  structs.StateAll,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*base.Milestone, 0, len(milestones))
+	for _, m := range milestones {
+		state := "open"
+		if m.IsClosed {
+			state = "closed"
+		}
+		var deadline *time.Time
+		if m.DeadlineUnix.Year() < 9999 {
+			deadline = timeStampPtr(m.DeadlineUnix)
+		}
+		result = append(result, &base.Milestone{
+			Title:       m.Name,
+			Description: m.Content,
+			Deadline:    deadline,
+			Created:     m.CreatedUnix.AsTime(),
+			Updated:     timeStampPtr(m.UpdatedUnix),
+			Closed:      timeStampPtr(m.ClosedDateUnix),
+			State:       state,
+		})
+	}
+	return result, nil
+}
+
+// GetReleases returns releases
+func (d *LocalDownloader) GetReleases() ([]*base.Release, error) {
+	releases, err := models.GetReleasesByRepoID(d.repo.ID, models.FindReleasesOptions{
+		IncludeDrafts: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := models.GetReleaseAttachments(releases...); err != nil {
+		return nil, err
+	}
+
+	result := make([]*base.Release, 0, len(releases))
+	for _, rel := range releases {
+		publisher, err := d.getUser(rel.PublisherID)
+		if err != nil {
+			return nil, err
+		}
+
+		assets := make([]*base.ReleaseAsset, 0, len(rel.Attachments))
+		for _, attach := range rel.Attachments {
+			attach := attach
+			if _, err := storage.Attachments.Stat(attach.RelativePath()); err != nil {
+				log.Warn("Skipping asset %s of release %s in %-v which cannot be read: %v", attach.Name, rel.TagName, d.repo, err)
+				continue
+			}
+			size := int(attach.Size)
+			downloadCount := int(attach.DownloadCount)
+			assets = append(assets, &base.ReleaseAsset{
+				ID:            attach.ID,
+				Name:          attach.Name,
+				Size:          &size,
+				DownloadCount: &downloadCount,
+				Created:       attach.CreatedUnix.AsTime(),
+				Updated:       attach.CreatedUnix.AsTime(),
+				DownloadFunc: func() (io.ReadCloser, error) {
+					return storage.Attachments.Open(attach.RelativePath())
+				},
+			})
+		}
+
+		result = append(result, &base.Release{
+			TagName:         rel.TagName,
+			TargetCommitish: rel.Target,
+			Name:            rel.Title,
+			Body:            rel.Note,
+			Draft:           rel.IsDraft,
+			Prerelease:      rel.IsPrerelease,
+			PublisherID:     publisher.ID,
+			PublisherName:   publisher.Name,
+			PublisherEmail:  publisher.Email,
+			Assets:          assets,
+			Created:         rel.CreatedUnix.AsTime(),
+			Published:       rel.CreatedUnix.AsTime(),
+		})
+	}
+	return result, nil
+}
+
+// GetLabels returns labels
+func (d *LocalDownloader) GetLabels() ([]*base.Label, error) {
+	labels, err := models.GetLabelsByRepoID(d.repo.ID, "", models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return convertLocalLabels(labels), nil
+}
+
+func convertLocalLabels(labels []*models.Label) []*base.Label {
+	result := make([]*base.Label, 0, len(labels))
+	for _, l := range labels {
+		result = append(result, &base.Label{
+			Name:        l.Name,
+			Color:       strings.TrimPrefix(l.Color, "#"),
+			Description: l.Description,
+		})
+	}
+	return result
+}
+
+func (d *LocalDownloader) convertReactions(reactions models.ReactionList) ([]*base.Reaction, error) {
+	result := make([]*base.Reaction, 0, len(reactions))
+	for _, r := range reactions {
+		u, err := d.getUser(r.UserID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &base.Reaction{
+			UserID:   u.ID,
+			UserName: u.Name,
+			Content:  r.Type,
+		})
+	}
+	return result, nil
+}
+
+func (d *LocalDownloader) findIssues(page, perPage int, isPull bool) ([]*models.Issue, bool, error) {
+	issues, err := models.Issues(&models.IssuesOptions{
+		ListOptions: models.ListOptions{Page: page, PageSize: perPage},
+		RepoIDs:     []int64{d.repo.ID},
+		IsPull:      util.OptionalBoolOf(isPull),
+		SortType:    "oldest",
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if err := models.IssueList(issues).LoadAttributes(); err != nil {
+		return nil, false, err
+	}
+	return issues, len(issues) < perPage, nil
+}
+
+// convertIssue fills the fields shared by issues and pull requests
+func (d *LocalDownloader) convertIssue(issue *models.Issue) (*base.Issue, error) {
+	poster, err := d.getUser(issue.PosterID)
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := d.convertReactions(issue.Reactions)
+	if err != nil {
+		return nil, err
+	}
+
+	var milestone string
+	if issue.Milestone != nil {
+		milestone = issue.Milestone.Name
+	}
+	assignees := make([]string, 0, len(issue.Assignees))
+	for _, a := range issue.Assignees {
+		assignees = append(assignees, a.Name)
+	}
+	state := "open"
+	if issue.IsClosed {
+		state = "closed"
+	}
+	customFields, err := models.GetIssueCustomFieldValues(issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &base.Issue{
+		Number:       issue.Index,
+		PosterID:     poster.ID,
+		PosterName:   poster.Name,
+		PosterEmail:  poster.Email,
+		Title:        issue.Title,
+		Content:      issue.Content,
+		Ref:          issue.Ref,
+		Milestone:    milestone,
+		State:        state,
+		IsLocked:     issue.IsLocked,
+		Created:      issue.CreatedUnix.AsTime(),
+		Updated:      issue.UpdatedUnix.AsTime(),
+		Closed:       timeStampPtr(issue.ClosedUnix),
+		Labels:       convertLocalLabels(issue.Labels),
+		Reactions:    reactions,
+		Assignees:    assignees,
+		CustomFields: customFields,
+		Context:      base.BasicIssueContext(issue.Index),
+	}, nil
+}
+
+// GetIssues returns issues according start and limit
+func (d *LocalDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, error) {
+	issues, isEnd, err := d.findIssues(page, perPage, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := make([]*base.Issue, 0, len(issues))
+	for _, issue := range issues {
+		i, err := d.convertIssue(issue)
+		if err != nil {
+			return nil, false, err
+		}
+		result = append(result, i)
+	}
+	return result, isEnd, nil
+}
+
+// GetComments returns comments according issueNumber
+func (d *LocalDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Comment, bool, error) {
+	issue, err := models.GetIssueByIndex(d.repo.ID, opts.Context.LocalID())
+	if err != nil {
+		return nil, false, err
+	}
+	comments, err := models.FindComments(&models.FindCommentsOptions{
+		IssueID: issue.ID,
+		Type:    models.CommentTypeComment,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := make([]*base.Comment, 0, len(comments))
+	for _, c := range comments {
+		poster, err := d.getUser(c.PosterID)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := c.LoadReactions(d.repo); err != nil {
+			return nil, false, err
+		}
+		reactions, err := d.convertReactions(c.Reactions)
+		if err != nil {
+			return nil, false, err
+		}
+		result = append(result, &base.Comment{
+			ID:          c.ID,
+			IssueIndex:  issue.Index,
+			PosterID:    poster.ID,
+			PosterName:  poster.Name,
+			PosterEmail: poster.Email,
+			Created:     c.CreatedUnix.AsTime(),
+			Updated:     c.UpdatedUnix.AsTime(),
+			Content:     c.Content,
+			Reactions:   reactions,
+		})
+	}
+	return result, true, nil
+}
+
+// GetPullRequests returns pull requests according page and perPage
+func (d *LocalDownloader) GetPullRequests(page, perPage int) ([]*base.PullRequest, bool, error) {
+	issues, isEnd, err := d.findIssues(page, perPage, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	gitRepo, err := git.OpenRepositoryCtx(d.ctx, d.repo.RepoPath())
+	if err != nil {
+		return nil, false, err
+	}
+	defer gitRepo.Close()
+
+	result := make([]*base.PullRequest, 0, len(issues))
+	for _, issue := range issues {
+		i, err := d.convertIssue(issue)
+		if err != nil {
+			return nil, false, err
+		}
+		pr := issue.PullRequest
+		if err := pr.LoadHeadRepo(); err != nil {
+			return nil, false, err
+		}
+
+		head := base.PullRequestBranch{
+			Ref:       pr.HeadBranch,
+			RepoName:  d.repo.Name,
+			OwnerName: d.repo.OwnerName,
+		}
+		if pr.HeadRepo != nil {
+			head.RepoName = pr.HeadRepo.Name
+			head.OwnerName = pr.HeadRepo.OwnerName
+			head.CloneURL = pr.HeadRepo.RepoPath()
+		}
+		if head.SHA, err = gitRepo.GetRefCommitID(pr.GetGitRefName()); err != nil {
+			return nil, false, err
+		}
+
+		closed := i.Closed
+		merged := timeStampPtr(pr.MergedUnix)
+		if merged != nil && closed == nil {
+			closed = merged
+		}
+
+		result = append(result, &base.PullRequest{
+			Number:         i.Number,
+			Title:          i.Title,
+			PosterName:     i.PosterName,
+			PosterID:       i.PosterID,
+			PosterEmail:    i.PosterEmail,
+			Content:        i.Content,
+			Milestone:      i.Milestone,
+			State:          i.State,
+			Created:        i.Created,
+			Updated:        i.Updated,
+			Closed:         closed,
+			Labels:         i.Labels,
+			Merged:         pr.HasMerged,
+			MergedTime:     merged,
+			MergeCommitSHA: pr.MergedCommitID,
+			Head:           head,
+			Base: base.PullRequestBranch{
+				Ref:       pr.BaseBranch,
+				SHA:       pr.MergeBase,
+				RepoName:  d.repo.Name,
+				OwnerName: d.repo.OwnerName,
+			},
+			Assignees: i.Assignees,
+			IsLocked:  i.IsLocked,
+			Reactions: i.Reactions,
+			Context:   i.Context,
+		})
+	}
+	return result, isEnd, nil
+}
+
+var localReviewStates = map[models.ReviewType]string{
+	models.ReviewTypePending: base.ReviewStatePending,
+	models.ReviewTypeApprove: base.ReviewStateApproved,
+	models.ReviewTypeReject:  base.ReviewStateChangesRequested,
+	models.ReviewTypeComment: base.ReviewStateCommented,
+}
+
+// GetReviews returns pull requests review
+func (d *LocalDownloader) GetReviews(context base.IssueContext) ([]*base.Review, error) {
+	issue, err := models.GetIssueByIndex(d.repo.ID, context.LocalID())
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := models.FindReviews(models.FindReviewOptions{IssueID: issue.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*base.Review, 0, len(reviews))
+	for _, r := range reviews {
+		state, ok := localReviewStates[r.Type]
+		if !ok {
+			// review requests have no content to keep
+			continue
+		}
+		reviewer, err := d.getUser(r.ReviewerID)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.LoadCodeComments(); err != nil {
+			return nil, err
+		}
+
+		comments := make([]*base.ReviewComment, 0, 10)
+		for _, lines := range r.CodeComments {
+			for _, lineComments := range lines {
+				for _, c := range lineComments {
+					comments = append(comments, &base.ReviewComment{
+						ID:        c.ID,
+						Content:   c.Content,
+						TreePath:  c.TreePath,
+						DiffHunk:  c.Patch,
+						Line:      int(c.Line),
+						CommitID:  c.CommitSHA,
+						PosterID:  c.PosterID,
+						CreatedAt: c.CreatedUnix.AsTime(),
+						UpdatedAt: c.UpdatedUnix.AsTime(),
+					})
+				}
+			}
+		}
+
+		result = append(result, &base.Review{
+			ID:           r.ID,
+			IssueIndex:   issue.Index,
+			ReviewerID:   reviewer.ID,
+			ReviewerName: reviewer.Name,
+			Official:     r.Official,
+			CommitID:     r.CommitID,
+			Content:      r.Content,
+			CreatedAt:    r.CreatedUnix.AsTime(),
+			State:        state,
+			Comments:     comments,
+		})
+	}
+	return result, nil
+}
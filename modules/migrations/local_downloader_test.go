@@ -0,0 +1,55 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalDownloader(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.GetOwner())
+	downloader := NewLocalDownloader(context.Background(), repo)
+
+	info, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "user2", info.Owner)
+	assert.Equal(t, "repo1", info.Name)
+	assert.Equal(t, repo.RepoPath(), info.CloneURL)
+
+	labels, err := downloader.GetLabels()
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+
+	milestones, err := downloader.GetMilestones()
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 3)
+
+	issues, isEnd, err := downloader.GetIssues(1, 50)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 1, issues[0].Number)
+		assert.Equal(t, "issue1", issues[0].Title)
+		assert.Equal(t, "user1", issues[0].PosterName)
+		assert.Equal(t, "open", issues[0].State)
+		assert.Equal(t, "closed", issues[1].State)
+	}
+
+	comments, _, err := downloader.GetComments(base.GetCommentOptions{Context: issues[0].Context})
+	assert.NoError(t, err)
+	for _, c := range comments {
+		assert.EqualValues(t, 1, c.IssueIndex)
+	}
+}
@@ -89,7 +89,7 @@ func IsMigrateURLAllowed(remoteURL string, doer *models.User) error {
 			return &models.ErrInvalidCloneAddr{Host: u.Host, NotResolvedIP: true}
 		}
 		for _, addr := range addrList {
-			if isIPPrivate(addr) || !addr.IsGlobalUnicast() {
+			if util.IsIPLocalNetwork(addr) {
 				return &models.ErrInvalidCloneAddr{Host: u.Host, PrivateNet: addr.String(), IsPermissionDenied: true}
 			}
 		}
@@ -117,6 +117,7 @@ func MigrateRepository(ctx context.Context, doer *models.User, ownerName string,
 
 	var uploader = NewGiteaLocalUploader(ctx, doer, ownerName, opts.RepoName)
 	uploader.gitServiceType = opts.GitServiceType
+	uploader.mapUsersByEmail = opts.MapUsersByEmail
 
 	if err := migrateRepository(downloader, uploader, opts, messenger); err != nil {
 		if err1 := uploader.Rollback(); err1 != nil {
@@ -130,6 +131,37 @@ func MigrateRepository(ctx context.Context, doer *models.User, ownerName string,
 	return uploader.repo, nil
 }
 
+// SyncRepository re-runs the migration of a repository according MigrateOptions and imports only the
+// issues, comments, releases, milestones and labels which are new since the migration or the last sync
+func SyncRepository(ctx context.Context, doer *models.User, repo *models.Repository, opts base.MigrateOptions, messenger base.Messenger) error {
+	lastSync, err := models.GetMigrationLastSync(repo.ID)
+	if err != nil {
+		return err
+	}
+	if lastSync == 0 {
+		return ErrRepoNotSyncable
+	}
+	if err := IsMigrateURLAllowed(opts.CloneAddr, doer); err != nil {
+		return err
+	}
+
+	opts.RepoName = repo.Name
+	opts.MigrateToRepoID = repo.ID
+	// the head commits of pull requests are only imported by the migration
+	opts.PullRequests = false
+	downloader, err := newDownloader(ctx, repo.OwnerName, opts)
+	if err != nil {
+		return err
+	}
+
+	var uploader = NewGiteaLocalUploader(ctx, doer, repo.OwnerName, repo.Name)
+	uploader.gitServiceType = opts.GitServiceType
+	uploader.mapUsersByEmail = opts.MapUsersByEmail
+	uploader.sync = true
+
+	return migrateRepository(downloader, uploader, opts, messenger)
+}
+
 func newDownloader(ctx context.Context, ownerName string, opts base.MigrateOptions) (base.Downloader, error) {
 	var (
 		downloader base.Downloader
@@ -474,13 +506,3 @@ func Init() error {
 
 	return nil
 }
-
-// TODO: replace with `ip.IsPrivate()` if min go version is bumped to 1.17
-func isIPPrivate(ip net.IP) bool {
-	if ip4 := ip.To4(); ip4 != nil {
-		return ip4[0] == 10 ||
-			(ip4[0] == 172 && ip4[1]&0xf0 == 16) ||
-			(ip4[0] == 192 && ip4[1] == 168)
-	}
-	return len(ip) == net.IPv6len && ip[0]&0xfe == 0xfc
-}
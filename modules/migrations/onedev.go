@@ -364,6 +364,7 @@ func (d *OneDevDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Com
 	}
 
 	rawComments := make([]struct {
+		ID      int64     `json:"id"`
 		Date    time.Time `json:"date"`
 		UserID  int64     `json:"userId"`
 		Content string    `json:"content"`
@@ -413,6 +414,7 @@ func (d *OneDevDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Com
 		}
 		poster := d.tryGetUser(comment.UserID)
 		comments = append(comments, &base.Comment{
+			ID:          comment.ID,
 			IssueIndex:  context.LocalID(),
 			PosterID:    poster.ID,
 			PosterName:  poster.Name,
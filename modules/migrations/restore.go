@@ -95,6 +95,14 @@ func (r *RepositoryRestorer) GetTopics() ([]string, error) {
 		Topics []string `yaml:"topics"`
 	}{}
 
+	_, err := os.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
 	bs, err := os.ReadFile(p)
 	if err != nil {
 		return nil, err
@@ -259,7 +267,9 @@ func (r *RepositoryRestorer) GetPullRequests(page, perPage int) ([]*base.PullReq
 		return nil, false, err
 	}
 	for _, pr := range pulls {
-		pr.PatchURL = "file://" + filepath.Join(r.baseDir, pr.PatchURL)
+		if pr.PatchURL != "" {
+			pr.PatchURL = "file://" + filepath.Join(r.baseDir, pr.PatchURL)
+		}
 		pr.Context = base.BasicIssueContext(pr.Number)
 	}
 	return pulls, true, nil
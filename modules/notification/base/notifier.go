@@ -5,6 +5,8 @@
 package base
 
 import (
+	"time"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/repository"
 )
@@ -19,6 +21,7 @@ type Notifier interface {
 	NotifyForkRepository(doer *models.User, oldRepo, repo *models.Repository)
 	NotifyRenameRepository(doer *models.User, repo *models.Repository, oldRepoName string)
 	NotifyTransferRepository(doer *models.User, repo *models.Repository, oldOwnerName string)
+	NotifyRenameUser(doer *models.User, u *models.User, oldUserName string)
 
 	NotifyNewIssue(issue *models.Issue, mentions []*models.User)
 	NotifyIssueChangeStatus(*models.User, *models.Issue, *models.Comment, bool)
@@ -59,4 +62,6 @@ type Notifier interface {
 	NotifySyncDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string)
 
 	NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Repository)
+
+	NotifyScheduleFired(doer *models.User, repo *models.Repository, schedule *models.RepoSchedule, scheduledAt time.Time)
 }
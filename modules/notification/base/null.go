@@ -5,6 +5,8 @@
 package base
 
 import (
+	"time"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/repository"
 )
@@ -155,6 +157,10 @@ func (*NullNotifier) NotifyRenameRepository(doer *models.User, repo *models.Repo
 func (*NullNotifier) NotifyTransferRepository(doer *models.User, repo *models.Repository, oldOwnerName string) {
 }
 
+// NotifyRenameUser places a place holder function
+func (*NullNotifier) NotifyRenameUser(doer *models.User, u *models.User, oldUserName string) {
+}
+
 // NotifySyncPushCommits places a place holder function
 func (*NullNotifier) NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
 }
@@ -170,3 +176,7 @@ func (*NullNotifier) NotifySyncDeleteRef(doer *models.User, repo *models.Reposit
 // NotifyRepoPendingTransfer places a place holder function
 func (*NullNotifier) NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Repository) {
 }
+
+// NotifyScheduleFired places a place holder function
+func (*NullNotifier) NotifyScheduleFired(doer *models.User, repo *models.Repository, schedule *models.RepoSchedule, scheduledAt time.Time) {
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package chat
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/services/chat"
+)
+
+type chatNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &chatNotifier{}
+)
+
+// NewNotifier create a new chatNotifier notifier
+func NewNotifier() base.Notifier {
+	return &chatNotifier{}
+}
+
+func (c *chatNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment, mentions []*models.User) {
+	chat.NotifyMentions(issue, doer, comment, mentions)
+}
+
+func (c *chatNotifier) NotifyNewIssue(issue *models.Issue, mentions []*models.User) {
+	if err := issue.LoadPoster(); err != nil {
+		log.Error("issue.LoadPoster: %v", err)
+		return
+	}
+	chat.NotifyMentions(issue, issue.Poster, nil, mentions)
+}
+
+func (c *chatNotifier) NotifyNewPullRequest(pr *models.PullRequest, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		log.Error("issue.LoadPoster: %v", err)
+		return
+	}
+	chat.NotifyMentions(pr.Issue, pr.Issue.Poster, nil, mentions)
+}
+
+func (c *chatNotifier) NotifyPullRequestReview(pr *models.PullRequest, r *models.Review, comment *models.Comment, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := r.LoadReviewer(); err != nil {
+		log.Error("review.LoadReviewer: %v", err)
+		return
+	}
+	chat.NotifyMentions(pr.Issue, r.Reviewer, comment, mentions)
+}
+
+func (c *chatNotifier) NotifyPullRequestCodeComment(pr *models.PullRequest, comment *models.Comment, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := comment.LoadPoster(); err != nil {
+		log.Error("comment.LoadPoster: %v", err)
+		return
+	}
+	chat.NotifyMentions(pr.Issue, comment.Poster, comment, mentions)
+}
+
+func (c *chatNotifier) NotifyPullReviewRequest(doer *models.User, issue *models.Issue, reviewer *models.User, isRequest bool, comment *models.Comment) {
+	if isRequest {
+		chat.NotifyReviewRequest(issue, doer, reviewer)
+	}
+}
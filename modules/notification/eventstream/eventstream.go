@@ -0,0 +1,281 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package eventstream
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/eventstream"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+type eventStreamNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &eventStreamNotifier{}
+)
+
+// NewNotifier create a new eventStreamNotifier notifier
+func NewNotifier() base.Notifier {
+	return &eventStreamNotifier{}
+}
+
+func userIDs(users []*models.User) []int64 {
+	ids := make([]int64, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+func publishIssueEvent(doer *models.User, issue *models.Issue, action string, userIDs ...int64) {
+	event := &eventstream.Event{
+		Action:  action,
+		RepoID:  issue.RepoID,
+		Actor:   doer,
+		UserIDs: userIDs,
+	}
+	if issue.IsPull {
+		if err := issue.LoadPullRequest(); err != nil {
+			log.Error("issue.LoadPullRequest: %v", err)
+			return
+		}
+		issue.PullRequest.Issue = issue
+		event.Type = "pull_request"
+		event.Unit = models.UnitTypePullRequests
+		event.Payload = convert.ToAPIPullRequest(issue.PullRequest)
+	} else {
+		event.Type = "issue"
+		event.Unit = models.UnitTypeIssues
+		event.Payload = convert.ToAPIIssue(issue)
+	}
+	eventstream.Publish(event)
+}
+
+func publishCommentEvent(doer *models.User, issue *models.Issue, comment *models.Comment, action string, userIDs ...int64) {
+	unit := models.UnitTypeIssues
+	if issue.IsPull {
+		unit = models.UnitTypePullRequests
+	}
+	eventstream.Publish(&eventstream.Event{
+		Type:    "issue_comment",
+		Action:  action,
+		RepoID:  issue.RepoID,
+		Unit:    unit,
+		Actor:   doer,
+		UserIDs: userIDs,
+		Payload: convert.ToComment(comment),
+	})
+}
+
+func publishReleaseEvent(doer *models.User, rel *models.Release, action string) {
+	if err := rel.LoadAttributes(); err != nil {
+		log.Error("rel.LoadAttributes: %v", err)
+		return
+	}
+	// drafts can only be seen by the writers of the repository
+	if rel.IsDraft {
+		return
+	}
+	eventstream.Publish(&eventstream.Event{
+		Type:    "release",
+		Action:  action,
+		RepoID:  rel.RepoID,
+		Unit:    models.UnitTypeReleases,
+		Actor:   doer,
+		Payload: convert.ToRelease(rel),
+	})
+}
+
+func publishPushEvent(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	eventstream.Publish(&eventstream.Event{
+		Type:   "push",
+		RepoID: repo.ID,
+		Unit:   models.UnitTypeCode,
+		Actor:  pusher,
+		Payload: &api.StreamPushPayload{
+			Ref:          opts.RefFullName,
+			Before:       opts.OldCommitID,
+			After:        opts.NewCommitID,
+			TotalCommits: commits.Len,
+		},
+	})
+}
+
+func publishRefEvent(doer *models.User, repo *models.Repository, eventType, refType, refFullName string) {
+	eventstream.Publish(&eventstream.Event{
+		Type:   eventType,
+		RepoID: repo.ID,
+		Unit:   models.UnitTypeCode,
+		Actor:  doer,
+		Payload: &api.StreamRefPayload{
+			RefType: refType,
+			Ref:     refFullName,
+		},
+	})
+}
+
+func (n *eventStreamNotifier) NotifyNewIssue(issue *models.Issue, mentions []*models.User) {
+	if err := issue.LoadPoster(); err != nil {
+		log.Error("issue.LoadPoster: %v", err)
+		return
+	}
+	publishIssueEvent(issue.Poster, issue, "opened", userIDs(mentions)...)
+}
+
+func (n *eventStreamNotifier) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, actionComment *models.Comment, isClosed bool) {
+	if isClosed {
+		publishIssueEvent(doer, issue, "closed")
+	} else {
+		publishIssueEvent(doer, issue, "reopened")
+	}
+}
+
+func (n *eventStreamNotifier) NotifyIssueChangeMilestone(doer *models.User, issue *models.Issue, oldMilestoneID int64) {
+	if issue.MilestoneID > 0 {
+		publishIssueEvent(doer, issue, "milestoned")
+	} else {
+		publishIssueEvent(doer, issue, "demilestoned")
+	}
+}
+
+func (n *eventStreamNotifier) NotifyIssueChangeAssignee(doer *models.User, issue *models.Issue, assignee *models.User, removed bool, comment *models.Comment) {
+	if removed {
+		publishIssueEvent(doer, issue, "unassigned", assignee.ID)
+	} else {
+		publishIssueEvent(doer, issue, "assigned", assignee.ID)
+	}
+}
+
+func (n *eventStreamNotifier) NotifyPullReviewRequest(doer *models.User, issue *models.Issue, reviewer *models.User, isRequest bool, comment *models.Comment) {
+	if isRequest {
+		publishIssueEvent(doer, issue, "review_requested", reviewer.ID)
+	} else {
+		publishIssueEvent(doer, issue, "review_request_removed", reviewer.ID)
+	}
+}
+
+func (n *eventStreamNotifier) NotifyIssueChangeContent(doer *models.User, issue *models.Issue, oldContent string) {
+	publishIssueEvent(doer, issue, "edited")
+}
+
+func (n *eventStreamNotifier) NotifyIssueChangeTitle(doer *models.User, issue *models.Issue, oldTitle string) {
+	publishIssueEvent(doer, issue, "edited")
+}
+
+func (n *eventStreamNotifier) NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
+	addedLabels []*models.Label, removedLabels []*models.Label) {
+	publishIssueEvent(doer, issue, "label_updated")
+}
+
+func (n *eventStreamNotifier) NotifyIssueClearLabels(doer *models.User, issue *models.Issue) {
+	publishIssueEvent(doer, issue, "label_cleared")
+}
+
+func (n *eventStreamNotifier) NotifyNewPullRequest(pr *models.PullRequest, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		log.Error("issue.LoadPoster: %v", err)
+		return
+	}
+	publishIssueEvent(pr.Issue.Poster, pr.Issue, "opened", userIDs(mentions)...)
+}
+
+func (n *eventStreamNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	publishIssueEvent(doer, pr.Issue, "merged")
+}
+
+func (n *eventStreamNotifier) NotifyPullRequestSynchronized(doer *models.User, pr *models.PullRequest) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	publishIssueEvent(doer, pr.Issue, "synchronized")
+}
+
+func (n *eventStreamNotifier) NotifyPullRequestReview(pr *models.PullRequest, review *models.Review, comment *models.Comment, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := review.LoadReviewer(); err != nil {
+		log.Error("review.LoadReviewer: %v", err)
+		return
+	}
+	publishIssueEvent(review.Reviewer, pr.Issue, "reviewed", userIDs(mentions)...)
+}
+
+func (n *eventStreamNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment, mentions []*models.User) {
+	publishCommentEvent(doer, issue, comment, "created", userIDs(mentions)...)
+}
+
+func (n *eventStreamNotifier) NotifyUpdateComment(doer *models.User, c *models.Comment, oldContent string) {
+	if err := c.LoadIssue(); err != nil {
+		log.Error("comment.LoadIssue: %v", err)
+		return
+	}
+	publishCommentEvent(doer, c.Issue, c, "edited")
+}
+
+func (n *eventStreamNotifier) NotifyDeleteComment(doer *models.User, c *models.Comment) {
+	if err := c.LoadIssue(); err != nil {
+		log.Error("comment.LoadIssue: %v", err)
+		return
+	}
+	publishCommentEvent(doer, c.Issue, c, "deleted")
+}
+
+func (n *eventStreamNotifier) NotifyNewRelease(rel *models.Release) {
+	if err := rel.LoadAttributes(); err != nil {
+		log.Error("rel.LoadAttributes: %v", err)
+		return
+	}
+	publishReleaseEvent(rel.Publisher, rel, "published")
+}
+
+func (n *eventStreamNotifier) NotifyUpdateRelease(doer *models.User, rel *models.Release) {
+	publishReleaseEvent(doer, rel, "updated")
+}
+
+func (n *eventStreamNotifier) NotifyDeleteRelease(doer *models.User, rel *models.Release) {
+	publishReleaseEvent(doer, rel, "deleted")
+}
+
+func (n *eventStreamNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	publishPushEvent(pusher, repo, opts, commits)
+}
+
+func (n *eventStreamNotifier) NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	publishPushEvent(pusher, repo, opts, commits)
+}
+
+func (n *eventStreamNotifier) NotifyCreateRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	publishRefEvent(doer, repo, "create", refType, refFullName)
+}
+
+func (n *eventStreamNotifier) NotifySyncCreateRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	publishRefEvent(doer, repo, "create", refType, refFullName)
+}
+
+func (n *eventStreamNotifier) NotifyDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	publishRefEvent(doer, repo, "delete", refType, refFullName)
+}
+
+func (n *eventStreamNotifier) NotifySyncDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	publishRefEvent(doer, repo, "delete", refType, refFullName)
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"fmt"
+	"html"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/federation"
+)
+
+type federationNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &federationNotifier{}
+)
+
+// NewNotifier create a new federationNotifier notifier
+func NewNotifier() base.Notifier {
+	return &federationNotifier{}
+}
+
+func link(url, text string) string {
+	return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), html.EscapeString(text))
+}
+
+// posterLink links the poster of an issue, issues of users of other instances are shown with their handle
+func posterLink(issue *models.Issue) string {
+	if issue.OriginalAuthor != "" {
+		return html.EscapeString(issue.OriginalAuthor)
+	}
+	return link(issue.Poster.HTMLURL(), issue.Poster.Name)
+}
+
+// publish publishes an activity of a repository as a note of the repository and of the user who did it
+func publish(doer *models.User, repo *models.Repository, content, url string) {
+	if repo != nil && federation.IsFederatedRepo(repo) {
+		if err := federation.PublishNote(models.FederationOwnerRepo, repo.ID, content, url); err != nil {
+			log.Error("PublishNote [repo: %d]: %v", repo.ID, err)
+		}
+	} else if repo != nil {
+		// activities of private repositories are not published by their users either
+		return
+	}
+	if doer != nil && federation.IsFederatedUser(doer) {
+		if err := federation.PublishNote(models.FederationOwnerUser, doer.ID, content, url); err != nil {
+			log.Error("PublishNote [user: %d]: %v", doer.ID, err)
+		}
+	}
+}
+
+func (f *federationNotifier) NotifyNewIssue(issue *models.Issue, mentions []*models.User) {
+	if err := issue.LoadPoster(); err != nil {
+		log.Error("issue.LoadPoster: %v", err)
+		return
+	}
+	if err := issue.LoadRepo(); err != nil {
+		log.Error("issue.LoadRepo: %v", err)
+		return
+	}
+	content := fmt.Sprintf("%s opened issue %s: %s",
+		posterLink(issue),
+		link(issue.HTMLURL(), fmt.Sprintf("%s#%d", issue.Repo.FullName(), issue.Index)),
+		html.EscapeString(issue.Title))
+	publish(issue.Poster, issue.Repo, content, issue.HTMLURL())
+}
+
+func (f *federationNotifier) NotifyNewPullRequest(pr *models.PullRequest, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	issue := pr.Issue
+	if err := issue.LoadPoster(); err != nil {
+		log.Error("issue.LoadPoster: %v", err)
+		return
+	}
+	if err := issue.LoadRepo(); err != nil {
+		log.Error("issue.LoadRepo: %v", err)
+		return
+	}
+	content := fmt.Sprintf("%s opened pull request %s: %s",
+		posterLink(issue),
+		link(issue.HTMLURL(), fmt.Sprintf("%s#%d", issue.Repo.FullName(), issue.Index)),
+		html.EscapeString(issue.Title))
+	publish(issue.Poster, issue.Repo, content, issue.HTMLURL())
+}
+
+func (f *federationNotifier) NotifyNewRelease(rel *models.Release) {
+	if rel.IsDraft || rel.IsTag {
+		return
+	}
+	if err := rel.LoadAttributes(); err != nil {
+		log.Error("rel.LoadAttributes: %v", err)
+		return
+	}
+	content := fmt.Sprintf("%s released %s of %s",
+		link(rel.Publisher.HTMLURL(), rel.Publisher.Name),
+		link(rel.HTMLURL(), rel.TagName),
+		link(rel.Repo.HTMLURL(), rel.Repo.FullName()))
+	publish(rel.Publisher, rel.Repo, content, rel.HTMLURL())
+}
+
+func (f *federationNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if !opts.IsBranch() || opts.IsDelRef() || commits.Len == 0 {
+		return
+	}
+	url := repo.HTMLURL() + "/src/branch/" + opts.BranchName()
+	if commits.CompareURL != "" {
+		url = setting.AppURL + commits.CompareURL
+	}
+	content := fmt.Sprintf("%s pushed %d commit(s) to %s of %s",
+		link(pusher.HTMLURL(), pusher.Name),
+		commits.Len,
+		link(repo.HTMLURL()+"/src/branch/"+opts.BranchName(), opts.BranchName()),
+		link(repo.HTMLURL(), repo.FullName()))
+	publish(pusher, repo, content, url)
+}
+
+func (f *federationNotifier) NotifyCreateRepository(doer *models.User, u *models.User, repo *models.Repository) {
+	if repo.IsPrivate || !u.Visibility.IsPublic() {
+		return
+	}
+	content := fmt.Sprintf("%s created the repository %s",
+		link(doer.HTMLURL(), doer.Name),
+		link(repo.HTMLURL(), repo.FullName()))
+	publish(doer, nil, content, repo.HTMLURL())
+}
@@ -8,8 +8,10 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	dependency_indexer "code.gitea.io/gitea/modules/indexer/dependency"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
 	stats_indexer "code.gitea.io/gitea/modules/indexer/stats"
+	symbol_indexer "code.gitea.io/gitea/modules/indexer/symbol"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification/base"
 	"code.gitea.io/gitea/modules/repository"
@@ -121,6 +123,14 @@ func (r *indexerNotifier) NotifyMigrateRepository(doer *models.User, u *models.U
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
+	if !repo.IsEmpty {
+		if err := symbol_indexer.UpdateRepoIndexer(repo, repo.DefaultBranch); err != nil {
+			log.Error("symbol_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+		}
+		if err := dependency_indexer.UpdateRepoIndexer(repo); err != nil {
+			log.Error("dependency_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+		}
+	}
 }
 
 func (r *indexerNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
@@ -130,6 +140,16 @@ func (r *indexerNotifier) NotifyPushCommits(pusher *models.User, repo *models.Re
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
+	if opts.RefFullName == git.BranchPrefix+repo.DefaultBranch {
+		if err := dependency_indexer.UpdateRepoIndexer(repo); err != nil {
+			log.Error("dependency_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+		}
+	}
+	if opts.IsBranch() {
+		if err := symbol_indexer.UpdateRepoIndexer(repo, opts.BranchName()); err != nil {
+			log.Error("symbol_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+		}
+	}
 }
 
 func (r *indexerNotifier) NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
@@ -139,6 +159,28 @@ func (r *indexerNotifier) NotifySyncPushCommits(pusher *models.User, repo *model
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
+	if opts.RefFullName == git.BranchPrefix+repo.DefaultBranch {
+		if err := dependency_indexer.UpdateRepoIndexer(repo); err != nil {
+			log.Error("dependency_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+		}
+	}
+	if opts.IsBranch() {
+		if err := symbol_indexer.UpdateRepoIndexer(repo, opts.BranchName()); err != nil {
+			log.Error("symbol_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+		}
+	}
+}
+
+func (r *indexerNotifier) NotifyDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	if refType == "branch" && setting.Indexer.SymbolIndexerEnabled {
+		if err := symbol_indexer.DeleteBranchFromIndexer(repo, git.RefEndName(refFullName)); err != nil {
+			log.Error("symbol_indexer.DeleteBranchFromIndexer(%d) failed: %v", repo.ID, err)
+		}
+	}
+}
+
+func (r *indexerNotifier) NotifySyncDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	r.NotifyDeleteRef(doer, repo, refType, refFullName)
 }
 
 func (r *indexerNotifier) NotifyIssueChangeContent(doer *models.User, issue *models.Issue, oldContent string) {
@@ -41,6 +41,11 @@ func (m *mailNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.
 		act = 0
 	}
 
+	// the conversation of locked issues is not mailed
+	if issue.IsLocked && act == models.ActionCommentIssue {
+		return
+	}
+
 	if err := mailer.MailParticipantsComment(comment, act, issue, mentions); err != nil {
 		log.Error("MailParticipantsComment: %v", err)
 	}
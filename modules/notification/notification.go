@@ -5,11 +5,17 @@
 package notification
 
 import (
+	"time"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/notification/action"
 	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/notification/chat"
+	"code.gitea.io/gitea/modules/notification/eventstream"
+	"code.gitea.io/gitea/modules/notification/federation"
 	"code.gitea.io/gitea/modules/notification/indexer"
 	"code.gitea.io/gitea/modules/notification/mail"
+	"code.gitea.io/gitea/modules/notification/push"
 	"code.gitea.io/gitea/modules/notification/ui"
 	"code.gitea.io/gitea/modules/notification/webhook"
 	"code.gitea.io/gitea/modules/repository"
@@ -32,6 +38,18 @@ func NewContext() {
 	if setting.Service.EnableNotifyMail {
 		RegisterNotifier(mail.NewNotifier())
 	}
+	if setting.Push.Enabled {
+		RegisterNotifier(push.NewNotifier())
+	}
+	if setting.Chat.Enabled {
+		RegisterNotifier(chat.NewNotifier())
+	}
+	if setting.EventStream.Enabled {
+		RegisterNotifier(eventstream.NewNotifier())
+	}
+	if setting.Federation.Enabled {
+		RegisterNotifier(federation.NewNotifier())
+	}
 	RegisterNotifier(indexer.NewNotifier())
 	RegisterNotifier(webhook.NewNotifier())
 	RegisterNotifier(action.NewNotifier())
@@ -249,6 +267,13 @@ func NotifyRenameRepository(doer *models.User, repo *models.Repository, oldName
 	}
 }
 
+// NotifyRenameUser notifies user or organization renamed
+func NotifyRenameUser(doer *models.User, u *models.User, oldUserName string) {
+	for _, notifier := range notifiers {
+		notifier.NotifyRenameUser(doer, u, oldUserName)
+	}
+}
+
 // NotifyPushCommits notifies commits pushed to notifiers
 func NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
 	for _, notifier := range notifiers {
@@ -297,3 +322,10 @@ func NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Reposit
 		notifier.NotifyRepoPendingTransfer(doer, newOwner, repo)
 	}
 }
+
+// NotifyScheduleFired notifies a fired repository schedule to notifiers
+func NotifyScheduleFired(doer *models.User, repo *models.Repository, schedule *models.RepoSchedule, scheduledAt time.Time) {
+	for _, notifier := range notifiers {
+		notifier.NotifyScheduleFired(doer, repo, schedule, scheduledAt)
+	}
+}
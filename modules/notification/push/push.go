@@ -0,0 +1,127 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package push
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/services/push"
+)
+
+type pushNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &pushNotifier{}
+)
+
+// NewNotifier create a new pushNotifier notifier
+func NewNotifier() base.Notifier {
+	return &pushNotifier{}
+}
+
+func (p *pushNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment, mentions []*models.User) {
+	var act models.ActionType
+	switch comment.Type {
+	case models.CommentTypeClose:
+		act = models.ActionCloseIssue
+	case models.CommentTypeReopen:
+		act = models.ActionReopenIssue
+	case models.CommentTypeComment, models.CommentTypeCode:
+		act = models.ActionCommentIssue
+	case models.CommentTypePullPush:
+		act = 0
+	default:
+		return
+	}
+
+	// the conversation of locked issues is not pushed
+	if issue.IsLocked && act == models.ActionCommentIssue {
+		return
+	}
+
+	push.NotifyParticipants(issue, doer, act, comment, mentions)
+}
+
+func (p *pushNotifier) NotifyNewIssue(issue *models.Issue, mentions []*models.User) {
+	if err := issue.LoadPoster(); err != nil {
+		log.Error("issue.LoadPoster: %v", err)
+		return
+	}
+	push.NotifyParticipants(issue, issue.Poster, models.ActionCreateIssue, nil, mentions)
+}
+
+func (p *pushNotifier) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, actionComment *models.Comment, isClosed bool) {
+	var actionType models.ActionType
+	if issue.IsPull {
+		if isClosed {
+			actionType = models.ActionClosePullRequest
+		} else {
+			actionType = models.ActionReopenPullRequest
+		}
+	} else {
+		if isClosed {
+			actionType = models.ActionCloseIssue
+		} else {
+			actionType = models.ActionReopenIssue
+		}
+	}
+	push.NotifyParticipants(issue, doer, actionType, nil, nil)
+}
+
+func (p *pushNotifier) NotifyNewPullRequest(pr *models.PullRequest, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		log.Error("issue.LoadPoster: %v", err)
+		return
+	}
+	push.NotifyParticipants(pr.Issue, pr.Issue.Poster, models.ActionCreatePullRequest, nil, mentions)
+}
+
+func (p *pushNotifier) NotifyPullRequestReview(pr *models.PullRequest, r *models.Review, comment *models.Comment, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := r.LoadReviewer(); err != nil {
+		log.Error("review.LoadReviewer: %v", err)
+		return
+	}
+	push.NotifyParticipants(pr.Issue, r.Reviewer, models.ActionCommentPull, comment, mentions)
+}
+
+func (p *pushNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	push.NotifyParticipants(pr.Issue, doer, models.ActionMergePullRequest, nil, nil)
+}
+
+func (p *pushNotifier) NotifyPullRequestPushCommits(doer *models.User, pr *models.PullRequest, comment *models.Comment) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	push.NotifyParticipants(pr.Issue, doer, 0, comment, nil)
+}
+
+func (p *pushNotifier) NotifyIssueChangeAssignee(doer *models.User, issue *models.Issue, assignee *models.User, removed bool, comment *models.Comment) {
+	if !removed {
+		push.NotifyUsers(issue, doer, "notification.push.assigned", []*models.User{assignee})
+	}
+}
+
+func (p *pushNotifier) NotifyPullReviewRequest(doer *models.User, issue *models.Issue, reviewer *models.User, isRequest bool, comment *models.Comment) {
+	if isRequest {
+		push.NotifyUsers(issue, doer, "notification.push.review_requested", []*models.User{reviewer})
+	}
+}
@@ -23,6 +23,7 @@ type (
 		CommentID            int64
 		NotificationAuthorID int64
 		ReceiverID           int64 // 0 -- ALL Watcher
+		Event                models.SubscriptionEvents
 	}
 )
 
@@ -40,7 +41,7 @@ func NewNotifier() base.Notifier {
 func (ns *notificationService) handle(data ...queue.Data) {
 	for _, datum := range data {
 		opts := datum.(issueNotificationOpts)
-		if err := models.CreateOrUpdateIssueNotifications(opts.IssueID, opts.CommentID, opts.NotificationAuthorID, opts.ReceiverID); err != nil {
+		if err := models.CreateOrUpdateIssueNotifications(opts.IssueID, opts.CommentID, opts.NotificationAuthorID, opts.ReceiverID, opts.Event); err != nil {
 			log.Error("Was unable to create issue notification: %v", err)
 		}
 	}
@@ -55,6 +56,7 @@ func (ns *notificationService) NotifyCreateIssueComment(doer *models.User, repo
 	var opts = issueNotificationOpts{
 		IssueID:              issue.ID,
 		NotificationAuthorID: doer.ID,
+		Event:                models.SubscriptionEventComment,
 	}
 	if comment != nil {
 		opts.CommentID = comment.ID
@@ -65,6 +67,7 @@ func (ns *notificationService) NotifyCreateIssueComment(doer *models.User, repo
 			IssueID:              issue.ID,
 			NotificationAuthorID: doer.ID,
 			ReceiverID:           mention.ID,
+			Event:                models.SubscriptionEventMention,
 		}
 		if comment != nil {
 			opts.CommentID = comment.ID
@@ -77,12 +80,14 @@ func (ns *notificationService) NotifyNewIssue(issue *models.Issue, mentions []*m
 	_ = ns.issueQueue.Push(issueNotificationOpts{
 		IssueID:              issue.ID,
 		NotificationAuthorID: issue.Poster.ID,
+		Event:                models.SubscriptionEventIssue,
 	})
 	for _, mention := range mentions {
 		_ = ns.issueQueue.Push(issueNotificationOpts{
 			IssueID:              issue.ID,
 			NotificationAuthorID: issue.Poster.ID,
 			ReceiverID:           mention.ID,
+			Event:                models.SubscriptionEventMention,
 		})
 	}
 }
@@ -91,6 +96,7 @@ func (ns *notificationService) NotifyIssueChangeStatus(doer *models.User, issue
 	_ = ns.issueQueue.Push(issueNotificationOpts{
 		IssueID:              issue.ID,
 		NotificationAuthorID: doer.ID,
+		Event:                models.SubscriptionEventStateChange,
 	})
 }
 
@@ -103,6 +109,7 @@ func (ns *notificationService) NotifyIssueChangeTitle(doer *models.User, issue *
 		_ = ns.issueQueue.Push(issueNotificationOpts{
 			IssueID:              issue.ID,
 			NotificationAuthorID: doer.ID,
+			Event:                models.SubscriptionEventIssue,
 		})
 	}
 }
@@ -111,6 +118,7 @@ func (ns *notificationService) NotifyMergePullRequest(pr *models.PullRequest, do
 	_ = ns.issueQueue.Push(issueNotificationOpts{
 		IssueID:              pr.Issue.ID,
 		NotificationAuthorID: doer.ID,
+		Event:                models.SubscriptionEventStateChange,
 	})
 }
 
@@ -138,13 +146,20 @@ func (ns *notificationService) NotifyNewPullRequest(pr *models.PullRequest, ment
 	}
 	delete(toNotify, pr.Issue.PosterID)
 	for _, mention := range mentions {
-		toNotify[mention.ID] = struct{}{}
+		delete(toNotify, mention.ID)
+		_ = ns.issueQueue.Push(issueNotificationOpts{
+			IssueID:              pr.Issue.ID,
+			NotificationAuthorID: pr.Issue.PosterID,
+			ReceiverID:           mention.ID,
+			Event:                models.SubscriptionEventMention,
+		})
 	}
 	for receiverID := range toNotify {
 		_ = ns.issueQueue.Push(issueNotificationOpts{
 			IssueID:              pr.Issue.ID,
 			NotificationAuthorID: pr.Issue.PosterID,
 			ReceiverID:           receiverID,
+			Event:                models.SubscriptionEventIssue,
 		})
 	}
 }
@@ -153,6 +168,7 @@ func (ns *notificationService) NotifyPullRequestReview(pr *models.PullRequest, r
 	var opts = issueNotificationOpts{
 		IssueID:              pr.Issue.ID,
 		NotificationAuthorID: r.Reviewer.ID,
+		Event:                models.SubscriptionEventComment,
 	}
 	if c != nil {
 		opts.CommentID = c.ID
@@ -163,6 +179,7 @@ func (ns *notificationService) NotifyPullRequestReview(pr *models.PullRequest, r
 			IssueID:              pr.Issue.ID,
 			NotificationAuthorID: r.Reviewer.ID,
 			ReceiverID:           mention.ID,
+			Event:                models.SubscriptionEventMention,
 		}
 		if c != nil {
 			opts.CommentID = c.ID
@@ -178,6 +195,7 @@ func (ns *notificationService) NotifyPullRequestCodeComment(pr *models.PullReque
 			NotificationAuthorID: c.Poster.ID,
 			CommentID:            c.ID,
 			ReceiverID:           mention.ID,
+			Event:                models.SubscriptionEventMention,
 		})
 	}
 }
@@ -187,6 +205,7 @@ func (ns *notificationService) NotifyPullRequestPushCommits(doer *models.User, p
 		IssueID:              pr.IssueID,
 		NotificationAuthorID: doer.ID,
 		CommentID:            comment.ID,
+		Event:                models.SubscriptionEventPush,
 	}
 	_ = ns.issueQueue.Push(opts)
 }
@@ -196,6 +215,7 @@ func (ns *notificationService) NotifyPullRevieweDismiss(doer *models.User, revie
 		IssueID:              review.IssueID,
 		NotificationAuthorID: doer.ID,
 		CommentID:            comment.ID,
+		Event:                models.SubscriptionEventComment,
 	}
 	_ = ns.issueQueue.Push(opts)
 }
@@ -206,6 +226,7 @@ func (ns *notificationService) NotifyIssueChangeAssignee(doer *models.User, issu
 			IssueID:              issue.ID,
 			NotificationAuthorID: doer.ID,
 			ReceiverID:           assignee.ID,
+			Event:                models.SubscriptionEventMention,
 		}
 
 		if comment != nil {
@@ -222,6 +243,7 @@ func (ns *notificationService) NotifyPullReviewRequest(doer *models.User, issue
 			IssueID:              issue.ID,
 			NotificationAuthorID: doer.ID,
 			ReceiverID:           reviewer.ID,
+			Event:                models.SubscriptionEventMention,
 		}
 
 		if comment != nil {
@@ -5,6 +5,9 @@
 package webhook
 
 import (
+	"path"
+	"time"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
@@ -134,6 +137,76 @@ func (m *webhookNotifier) NotifyMigrateRepository(doer *models.User, u *models.U
 	}
 }
 
+func (m *webhookNotifier) NotifyRenameRepository(doer *models.User, repo *models.Repository, oldRepoName string) {
+	if err := repo.GetOwner(); err != nil {
+		log.Error("GetOwner [repo_id: %d]: %v", repo.ID, err)
+		return
+	}
+
+	if err := webhook_services.PrepareWebhooks(repo, models.HookEventRepository, &api.RepositoryPayload{
+		Action:     api.HookRepoRenamed,
+		Repository: convert.ToRepo(repo, models.AccessModeOwner),
+		Changes: &api.RepositoryChangesPayload{
+			Name:     &api.ChangesFromPayload{From: oldRepoName},
+			FullName: &api.ChangesFromPayload{From: path.Join(repo.OwnerName, oldRepoName)},
+		},
+		Organization: convert.ToUser(repo.Owner, nil),
+		Sender:       convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyTransferRepository(doer *models.User, repo *models.Repository, oldOwnerName string) {
+	if err := repo.GetOwner(); err != nil {
+		log.Error("GetOwner [repo_id: %d]: %v", repo.ID, err)
+		return
+	}
+
+	if err := webhook_services.PrepareWebhooks(repo, models.HookEventRepository, &api.RepositoryPayload{
+		Action:     api.HookRepoTransferred,
+		Repository: convert.ToRepo(repo, models.AccessModeOwner),
+		Changes: &api.RepositoryChangesPayload{
+			Owner:    &api.ChangesFromPayload{From: oldOwnerName},
+			FullName: &api.ChangesFromPayload{From: path.Join(oldOwnerName, repo.Name)},
+		},
+		Organization: convert.ToUser(repo.Owner, nil),
+		Sender:       convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyRenameUser(doer *models.User, u *models.User, oldUserName string) {
+	// the full names of all repositories of the user change
+	repoIDs, err := u.GetRepositoryIDs()
+	if err != nil {
+		log.Error("GetRepositoryIDs [user_id: %d]: %v", u.ID, err)
+		return
+	}
+
+	for _, repoID := range repoIDs {
+		repo, err := models.GetRepositoryByID(repoID)
+		if err != nil {
+			log.Error("GetRepositoryByID [repo_id: %d]: %v", repoID, err)
+			continue
+		}
+		repo.Owner = u
+		if err := webhook_services.PrepareWebhooks(repo, models.HookEventRepository, &api.RepositoryPayload{
+			Action:     api.HookRepoRenamed,
+			Repository: convert.ToRepo(repo, models.AccessModeOwner),
+			Changes: &api.RepositoryChangesPayload{
+				Owner:    &api.ChangesFromPayload{From: oldUserName},
+				FullName: &api.ChangesFromPayload{From: path.Join(oldUserName, repo.Name)},
+			},
+			Organization: convert.ToUser(u, nil),
+			Sender:       convert.ToUser(doer, nil),
+		}); err != nil {
+			log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+		}
+	}
+}
+
 func (m *webhookNotifier) NotifyIssueChangeAssignee(doer *models.User, issue *models.Issue, assignee *models.User, removed bool, comment *models.Comment) {
 	if issue.IsPull {
 		mode, _ := models.AccessLevelUnit(doer, issue.Repo, models.UnitTypePullRequests)
@@ -356,6 +429,9 @@ func (m *webhookNotifier) NotifyUpdateComment(doer *models.User, c *models.Comme
 		log.Error("LoadIssue: %v", err)
 		return
 	}
+	if c.Issue.IsLocked {
+		return
+	}
 
 	if err = c.Issue.LoadAttributes(); err != nil {
 		log.Error("LoadAttributes: %v", err)
@@ -400,6 +476,11 @@ func (m *webhookNotifier) NotifyUpdateComment(doer *models.User, c *models.Comme
 
 func (m *webhookNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
 	issue *models.Issue, comment *models.Comment, mentions []*models.User) {
+	// the conversation of locked issues is not announced
+	if issue.IsLocked {
+		return
+	}
+
 	mode, _ := models.AccessLevel(doer, repo)
 
 	var err error
@@ -439,6 +520,9 @@ func (m *webhookNotifier) NotifyDeleteComment(doer *models.User, comment *models
 		log.Error("LoadIssue: %v", err)
 		return
 	}
+	if comment.Issue.IsLocked {
+		return
+	}
 
 	if err = comment.Issue.LoadAttributes(); err != nil {
 		log.Error("LoadAttributes: %v", err)
@@ -819,3 +903,14 @@ func (m *webhookNotifier) NotifySyncCreateRef(pusher *models.User, repo *models.
 func (m *webhookNotifier) NotifySyncDeleteRef(pusher *models.User, repo *models.Repository, refType, refFullName string) {
 	m.NotifyDeleteRef(pusher, repo, refType, refFullName)
 }
+
+func (m *webhookNotifier) NotifyScheduleFired(doer *models.User, repo *models.Repository, schedule *models.RepoSchedule, scheduledAt time.Time) {
+	if err := webhook_services.PrepareWebhooks(repo, models.HookEventSchedule, &api.SchedulePayload{
+		Schedule:    convert.ToRepoSchedule(schedule),
+		ScheduledAt: scheduledAt,
+		Repository:  convert.ToRepo(repo, models.AccessModeNone),
+		Sender:      convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks [schedule: %d]: %v", schedule.ID, err)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+)
+
+// Media types of the supported manifests
+const (
+	ContentTypeDockerDistributionManifestV2   = "application/vnd.docker.distribution.manifest.v2+json"
+	ContentTypeDockerDistributionManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	ContentTypeOCIImageManifest               = "application/vnd.oci.image.manifest.v1+json"
+	ContentTypeOCIImageIndex                  = "application/vnd.oci.image.index.v1+json"
+)
+
+var (
+	// ErrInvalidManifest indicates an invalid or unsupported manifest
+	ErrInvalidManifest = errors.New("The manifest is invalid")
+
+	// https://github.com/opencontainers/distribution-spec/blob/main/spec.md#pulling-manifests
+	imageNamePattern = regexp.MustCompile(`\A[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*\z`)
+	tagPattern       = regexp.MustCompile(`\A[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}\z`)
+	digestPattern    = regexp.MustCompile(`\Asha256:[a-f0-9]{64}\z`)
+)
+
+// Descriptor references content of a manifest
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// Manifest is the union of the image manifest and image index formats
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Config        *Descriptor  `json:"config,omitempty"`
+	Layers        []Descriptor `json:"layers,omitempty"`
+	Manifests     []Descriptor `json:"manifests,omitempty"`
+}
+
+// Metadata represents the metadata of a container image which is stored with the package version
+type Metadata struct {
+	MediaType      string   `json:"media_type"`
+	ManifestDigest string   `json:"manifest_digest"`
+	IsTagged       bool     `json:"is_tagged"`
+	BlobDigests    []string `json:"blob_digests,omitempty"`
+}
+
+// ParseManifest parses an image manifest or image index. The content type header is used if the manifest does not contain a media type.
+func ParseManifest(r io.Reader, contentType string) (*Manifest, error) {
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, ErrInvalidManifest
+	}
+	if m.SchemaVersion != 2 {
+		return nil, ErrInvalidManifest
+	}
+	if m.MediaType == "" {
+		m.MediaType = contentType
+	}
+	if m.MediaType == "" {
+		if len(m.Manifests) > 0 {
+			m.MediaType = ContentTypeOCIImageIndex
+		} else {
+			m.MediaType = ContentTypeOCIImageManifest
+		}
+	}
+
+	switch m.MediaType {
+	case ContentTypeDockerDistributionManifestV2, ContentTypeOCIImageManifest:
+		if m.Config == nil || !IsValidDigest(m.Config.Digest) {
+			return nil, ErrInvalidManifest
+		}
+		for _, l := range m.Layers {
+			if !IsValidDigest(l.Digest) {
+				return nil, ErrInvalidManifest
+			}
+		}
+	case ContentTypeDockerDistributionManifestList, ContentTypeOCIImageIndex:
+		for _, sub := range m.Manifests {
+			if !IsValidDigest(sub.Digest) {
+				return nil, ErrInvalidManifest
+			}
+		}
+	default:
+		return nil, ErrInvalidManifest
+	}
+	return &m, nil
+}
+
+// IsIndex returns true if the manifest references other manifests
+func (m *Manifest) IsIndex() bool {
+	return m.MediaType == ContentTypeDockerDistributionManifestList || m.MediaType == ContentTypeOCIImageIndex
+}
+
+// ReferencedDigests returns the digests of all blobs and manifests referenced by this manifest
+func (m *Manifest) ReferencedDigests() []string {
+	digests := make([]string, 0, len(m.Layers)+len(m.Manifests)+1)
+	if m.Config != nil {
+		digests = append(digests, m.Config.Digest)
+	}
+	for _, l := range m.Layers {
+		digests = append(digests, l.Digest)
+	}
+	for _, sub := range m.Manifests {
+		digests = append(digests, sub.Digest)
+	}
+	return digests
+}
+
+// IsValidImageName checks if name is a valid repository name
+func IsValidImageName(name string) bool {
+	return len(name) <= 255 && imageNamePattern.MatchString(name)
+}
+
+// IsValidTag checks if tag is a valid tag
+func IsValidTag(tag string) bool {
+	return tagPattern.MatchString(tag)
+}
+
+// IsValidDigest checks if digest is a valid sha256 digest
+func IsValidDigest(digest string) bool {
+	return digestPattern.MatchString(digest)
+}
+
+// DigestToHash extracts the hex encoded hash of a digest
+func DigestToHash(digest string) string {
+	return strings.TrimPrefix(digest, "sha256:")
+}
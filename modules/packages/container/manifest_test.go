@@ -0,0 +1,64 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func TestParseManifest(t *testing.T) {
+	t.Run("ImageManifest", func(t *testing.T) {
+		content := `{"schemaVersion":2,"mediaType":"` + ContentTypeOCIImageManifest + `","config":{"digest":"` + digestA + `"},"layers":[{"digest":"` + digestB + `"}]}`
+		m, err := ParseManifest(strings.NewReader(content), "")
+		assert.NoError(t, err)
+		assert.False(t, m.IsIndex())
+		assert.Equal(t, []string{digestA, digestB}, m.ReferencedDigests())
+	})
+
+	t.Run("ContentTypeFallback", func(t *testing.T) {
+		content := `{"schemaVersion":2,"manifests":[{"digest":"` + digestA + `"}]}`
+		m, err := ParseManifest(strings.NewReader(content), ContentTypeDockerDistributionManifestList)
+		assert.NoError(t, err)
+		assert.True(t, m.IsIndex())
+		assert.Equal(t, ContentTypeDockerDistributionManifestList, m.MediaType)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, content := range []string{
+			`{`,
+			`{"schemaVersion":1}`,
+			`{"schemaVersion":2,"mediaType":"` + ContentTypeOCIImageManifest + `"}`,
+			`{"schemaVersion":2,"mediaType":"` + ContentTypeOCIImageManifest + `","config":{"digest":"sha256:abc"}}`,
+			`{"schemaVersion":2,"mediaType":"text/plain"}`,
+		} {
+			_, err := ParseManifest(strings.NewReader(content), "")
+			assert.ErrorIs(t, err, ErrInvalidManifest, content)
+		}
+	})
+}
+
+func TestValidation(t *testing.T) {
+	assert.True(t, IsValidImageName("test"))
+	assert.True(t, IsValidImageName("sub/test-image_1.0"))
+	assert.False(t, IsValidImageName("Test"))
+	assert.False(t, IsValidImageName("test/"))
+
+	assert.True(t, IsValidTag("latest"))
+	assert.True(t, IsValidTag("v1.0.0-rc1"))
+	assert.False(t, IsValidTag(".hidden"))
+	assert.False(t, IsValidTag(digestA))
+
+	assert.True(t, IsValidDigest(digestA))
+	assert.False(t, IsValidDigest("sha256:abc"))
+	assert.Equal(t, strings.Repeat("a", 64), DigestToHash(digestA))
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"io"
+	"path"
+
+	"code.gitea.io/gitea/modules/storage"
+)
+
+// ContentStore is a wrapper around ObjectStorage which stores package blobs by their SHA256 hash
+type ContentStore struct {
+	storage.ObjectStorage
+}
+
+// NewContentStore creates the default package store
+func NewContentStore() *ContentStore {
+	return &ContentStore{ObjectStorage: storage.Packages}
+}
+
+// Get opens the blob with the given hash
+func (s *ContentStore) Get(hashSHA256 string) (storage.Object, error) {
+	return s.Open(KeyToRelativePath(hashSHA256))
+}
+
+// Save stores the content of r as blob with the given hash
+func (s *ContentStore) Save(hashSHA256 string, r io.Reader, size int64) error {
+	_, err := s.ObjectStorage.Save(KeyToRelativePath(hashSHA256), r, size)
+	return err
+}
+
+// Delete removes the blob with the given hash
+func (s *ContentStore) Delete(hashSHA256 string) error {
+	return s.ObjectStorage.Delete(KeyToRelativePath(hashSHA256))
+}
+
+// KeyToRelativePath converts the SHA256 key aabb000000... to aa/bb/aabb000000...
+func KeyToRelativePath(key string) string {
+	if len(key) < 4 {
+		return key
+	}
+	return path.Join(key[0:2], key[2:4], key)
+}
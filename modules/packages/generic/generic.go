@@ -0,0 +1,54 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	nameMatcher    = regexp.MustCompile(`\A[A-Za-z0-9][A-Za-z0-9._-]*\z`)
+	versionMatcher = regexp.MustCompile(`\A[A-Za-z0-9][A-Za-z0-9._+-]*\z`)
+)
+
+// Metadata represents the user defined key/value metadata of a generic package version
+type Metadata map[string]string
+
+// VersionInfo describes a generic package version
+type VersionInfo struct {
+	Name          string     `json:"name"`
+	Version       string     `json:"version"`
+	Creator       string     `json:"creator"`
+	DownloadCount int64      `json:"download_count"`
+	Metadata      Metadata   `json:"metadata"`
+	Files         []FileInfo `json:"files"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// FileInfo describes a file of a generic package version
+type FileInfo struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// IsValidName checks if name is a valid generic package name
+func IsValidName(name string) bool {
+	return nameMatcher.MatchString(name)
+}
+
+// IsValidVersion checks if version is a valid generic package version
+func IsValidVersion(version string) bool {
+	return versionMatcher.MatchString(version)
+}
+
+// IsValidFilename checks if filename can be used for a file of a generic package
+func IsValidFilename(filename string) bool {
+	return filename != "" && filename != "." && filename != ".." &&
+		!strings.ContainsAny(filename, "/\\") && strings.TrimSpace(filename) == filename
+}
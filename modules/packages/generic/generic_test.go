@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation(t *testing.T) {
+	assert.True(t, IsValidName("my-artifact_1.0"))
+	assert.False(t, IsValidName(".hidden"))
+	assert.False(t, IsValidName("a/b"))
+
+	assert.True(t, IsValidVersion("1.0.0+build.5"))
+	assert.True(t, IsValidVersion("nightly"))
+	assert.False(t, IsValidVersion("-1"))
+
+	assert.True(t, IsValidFilename("artifact.tar.gz"))
+	assert.False(t, IsValidFilename(".."))
+	assert.False(t, IsValidFilename("dir/file"))
+	assert.False(t, IsValidFilename(" file"))
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"os"
+)
+
+// ErrFileTooLarge indicates that the uploaded content exceeds the configured limit
+var ErrFileTooLarge = errors.New("File is too large")
+
+// HashSums contains the hex encoded hashes of a content
+type HashSums struct {
+	MD5    string
+	SHA1   string
+	SHA256 string
+	SHA512 string
+}
+
+// MultiHasher calculates multiple hashes of the written content at once
+type MultiHasher struct {
+	md5    hash.Hash
+	sha1   hash.Hash
+	sha256 hash.Hash
+	sha512 hash.Hash
+	io.Writer
+}
+
+// NewMultiHasher creates a new MultiHasher
+func NewMultiHasher() *MultiHasher {
+	h := &MultiHasher{
+		md5:    md5.New(),
+		sha1:   sha1.New(),
+		sha256: sha256.New(),
+		sha512: sha512.New(),
+	}
+	h.Writer = io.MultiWriter(h.md5, h.sha1, h.sha256, h.sha512)
+	return h
+}
+
+// Sums returns the hex encoded hashes of the written content
+func (h *MultiHasher) Sums() HashSums {
+	return HashSums{
+		MD5:    hex.EncodeToString(h.md5.Sum(nil)),
+		SHA1:   hex.EncodeToString(h.sha1.Sum(nil)),
+		SHA256: hex.EncodeToString(h.sha256.Sum(nil)),
+		SHA512: hex.EncodeToString(h.sha512.Sum(nil)),
+	}
+}
+
+// HashedBuffer is a temporary file containing a content whose size and hashes are known
+type HashedBuffer struct {
+	*os.File
+	Size int64
+	HashSums
+}
+
+// CreateHashedBufferFromReader copies r into a temporary file and calculates its hashes.
+// maxSize limits the content size, a negative value disables the limit.
+func CreateHashedBufferFromReader(r io.Reader, maxSize int64) (*HashedBuffer, error) {
+	f, err := os.CreateTemp("", "gitea-package-")
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &HashedBuffer{File: f}
+
+	if maxSize >= 0 {
+		r = io.LimitReader(r, maxSize+1)
+	}
+
+	hasher := NewMultiHasher()
+	buf.Size, err = io.Copy(io.MultiWriter(f, hasher), r)
+	if err == nil && maxSize >= 0 && buf.Size > maxSize {
+		err = ErrFileTooLarge
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = buf.Close()
+		return nil, err
+	}
+
+	buf.HashSums = hasher.Sums()
+	return buf, nil
+}
+
+// Close closes and removes the temporary file
+func (b *HashedBuffer) Close() error {
+	name := b.File.Name()
+	err := b.File.Close()
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+	return err
+}
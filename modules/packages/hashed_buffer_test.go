@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateHashedBufferFromReader(t *testing.T) {
+	buf, err := CreateHashedBufferFromReader(strings.NewReader("gitea"), -1)
+	assert.NoError(t, err)
+	defer buf.Close()
+
+	assert.EqualValues(t, 5, buf.Size)
+	assert.Equal(t, "e3bef03c5f3b7f6b3ab3e3053ed71e9c", buf.MD5)
+	assert.Equal(t, "6ccce4863b70f258d691f59609d31b4502e1ba5199942d3bc5d35d17a4ce771d", buf.SHA256)
+
+	content, err := io.ReadAll(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "gitea", string(content))
+
+	_, err = CreateHashedBufferFromReader(strings.NewReader("gitea"), 4)
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+}
+
+func TestKeyToRelativePath(t *testing.T) {
+	assert.Equal(t, "ab/cd/abcdef", KeyToRelativePath("abcdef"))
+	assert.Equal(t, "abc", KeyToRelativePath("abc"))
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package npm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePackage(t *testing.T) {
+	packageScope := "@scope"
+	packageName := "test-package"
+	packageFullName := packageScope + "/" + packageName
+	packageVersion := "1.0.1-pre"
+	data := "H4sIAAAAAAAA/ytITM5OTE/VL4DQelnF+XkMVAYGBgZmJiYK2MRBwNDcSIHB2NTMwNDQzMwAqA7IMDUxA9LUdgg2UFpcklgEdAql5kD8ogCnhwio5lJQUMpLzE1VslJQcihOzi9I1S9JLS7RhSYIJR2QYlKOsKJAYkWI0pA46Om4/CEfwwAAAP//"
+	integrity := "sha512-8sMiuzJnnCcOOQv9VP9XyR7W7fE1v7NBpoLooEQQMlQEA7K1A6/4rk+Z8XutnKmUFigutl1obHJ6f5JZBBAeIA=="
+
+	createPackage := func(name, version string) string {
+		return `{
+			"_id": "` + name + `",
+			"name": "` + name + `",
+			"description": "Test Description",
+			"dist-tags": {
+				"latest": "` + version + `"
+			},
+			"versions": {
+				"` + version + `": {
+					"name": "` + name + `",
+					"version": "` + version + `",
+					"description": "Test Description",
+					"author": {
+						"name": "Test Author"
+					},
+					"license": "MIT",
+					"dist": {
+						"integrity": "` + integrity + `",
+						"shasum": "f5edf0448e779c8eec412cbf2c8f59f21143d920"
+					}
+				}
+			},
+			"_attachments": {
+				"` + packageFullName + `-` + version + `.tgz": {
+					"data": "` + data + `"
+				}
+			}
+		}`
+	}
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		p, err := ParsePackage(strings.NewReader("{"))
+		assert.Nil(t, p)
+		assert.Error(t, err)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		for _, name := range []string{" test", "_test", ".test", "te st"} {
+			p, err := ParsePackage(strings.NewReader(createPackage(name, packageVersion)))
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, ErrInvalidPackageName, name)
+		}
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		p, err := ParsePackage(strings.NewReader(createPackage(packageFullName, "1.")))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidPackageVersion)
+	})
+
+	t.Run("InvalidIntegrity", func(t *testing.T) {
+		content := strings.Replace(createPackage(packageFullName, packageVersion), data, base64.StdEncoding.EncodeToString([]byte("broken")), 1)
+		p, err := ParsePackage(strings.NewReader(content))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidIntegrity)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		p, err := ParsePackage(strings.NewReader(createPackage(packageFullName, packageVersion)))
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+
+		assert.Equal(t, packageFullName, p.Name)
+		assert.Equal(t, packageVersion, p.Version)
+		assert.Equal(t, []string{"latest"}, p.DistTags)
+		assert.Equal(t, fmt.Sprintf("%s-%s.tgz", packageName, packageVersion), p.Filename)
+		assert.Equal(t, packageScope, p.Metadata.Scope)
+		assert.Equal(t, packageName, p.Metadata.Name)
+		assert.Equal(t, "Test Author", p.Metadata.Author)
+		assert.Equal(t, "MIT", p.Metadata.License)
+
+		decoded, _ := base64.StdEncoding.DecodeString(data)
+		assert.True(t, bytes.Equal(decoded, p.Data))
+	})
+}
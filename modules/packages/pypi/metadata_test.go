@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "friendly-bard", NormalizeName("Friendly.Bard"))
+	assert.Equal(t, "friendly-bard", NormalizeName("FRIENDLY_BARD"))
+}
+
+func TestIsValidName(t *testing.T) {
+	assert.True(t, IsValidName("test"))
+	assert.True(t, IsValidName("test.package_1"))
+	assert.False(t, IsValidName("-test"))
+	assert.False(t, IsValidName("test package"))
+}
+
+func TestIsValidVersion(t *testing.T) {
+	for _, v := range []string{"1.0", "1.0.0", "1.0a1", "1.0.post1", "1.0.dev3", "1!2.0", "1.0+local.1"} {
+		assert.True(t, IsValidVersion(v), v)
+	}
+	for _, v := range []string{"", "abc", "1.0-", "1..0"} {
+		assert.False(t, IsValidVersion(v), v)
+	}
+}
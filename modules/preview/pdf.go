@@ -0,0 +1,65 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package preview
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/png" // for the image of the first page
+	"io"
+	"os/exec"
+	"strings"
+
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/typesniffer"
+)
+
+func init() {
+	RegisterProvider(pdfProvider{})
+}
+
+// pdfProvider renders the first page of PDFs with PDF_COMMAND
+type pdfProvider struct{}
+
+func (pdfProvider) Name() string {
+	return "pdf"
+}
+
+func (pdfProvider) CanPreview(filename string, head []byte) bool {
+	return setting.Preview.PDFCommand != "" && typesniffer.DetectContentType(head).IsPDF()
+}
+
+func (pdfProvider) Generate(ctx context.Context, filename string, r io.Reader) (*Preview, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	commands := strings.Fields(setting.Preview.PDFCommand)
+	pid := process.GetManager().Add(fmt.Sprintf("Render [%s] PDF %s", commands[0], filename), cancel)
+	defer process.GetManager().Remove(pid)
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, commands[0], commands[1:]...)
+	cmd.Stdin = r
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("rendering PDF %s with %s failed: %v\nstderr: %s", filename, commands[0], err, stderr.String())
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(stdout.Bytes()))
+	if err != nil || format != "png" {
+		return nil, fmt.Errorf("rendering PDF %s with %s did not output a PNG image", filename, commands[0])
+	}
+	return &Preview{
+		Image: stdout.Bytes(),
+		Metadata: map[string]interface{}{
+			"width":  cfg.Width,
+			"height": cfg.Height,
+		},
+	}, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package preview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Preview represents the preview of a file
+type Preview struct {
+	// Image is a PNG image of the file, if the provider renders one
+	Image []byte `json:"image,omitempty"`
+	// Metadata holds the properties of the file the provider extracts
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Provider generates the previews of a kind of files
+type Provider interface {
+	// Name identifies the provider, the previews it generates are cached under it
+	Name() string
+	// CanPreview returns whether the provider previews the file with the name and the first bytes
+	CanPreview(filename string, head []byte) bool
+	// Generate generates the preview of the file read from r
+	Generate(ctx context.Context, filename string, r io.Reader) (*Preview, error)
+}
+
+var providers []Provider
+
+// RegisterProvider registers a provider, the first registered provider previewing a file is used for it
+func RegisterProvider(p Provider) {
+	providers = append(providers, p)
+}
+
+// FindProvider returns the provider previewing the file with the name and the first bytes, or nil
+func FindProvider(filename string, head []byte) Provider {
+	if !setting.Preview.Enabled {
+		return nil
+	}
+	for _, p := range providers {
+		if p.CanPreview(filename, head) {
+			return p
+		}
+	}
+	return nil
+}
+
+// ErrNotPreviewable indicates that a file cannot be previewed
+type ErrNotPreviewable struct {
+	Name   string
+	Reason string
+}
+
+// IsErrNotPreviewable checks if an error is a ErrNotPreviewable.
+func IsErrNotPreviewable(err error) bool {
+	_, ok := err.(ErrNotPreviewable)
+	return ok
+}
+
+func (err ErrNotPreviewable) Error() string {
+	return fmt.Sprintf("%s cannot be previewed: %s", err.Name, err.Reason)
+}
+
+// FindBlobProvider returns the provider previewing the blob, or nil
+func FindBlobProvider(blob *git.Blob) (Provider, error) {
+	if !setting.Preview.Enabled || blob.Size() > setting.Preview.MaxFileSize {
+		return nil, nil
+	}
+	head, err := blob.GetBlobContent()
+	if err != nil {
+		return nil, err
+	}
+	return FindProvider(blob.Name(), []byte(head)), nil
+}
+
+// GenerateBlob returns the preview of the blob and the provider which generated it. As blobs are
+// content addressed, the preview is cached under the ID of the blob and the name of the provider.
+func GenerateBlob(ctx context.Context, blob *git.Blob) (*Preview, Provider, error) {
+	if !setting.Preview.Enabled {
+		return nil, nil, ErrNotPreviewable{Name: blob.Name(), Reason: "previews are disabled"}
+	}
+	if blob.Size() > setting.Preview.MaxFileSize {
+		return nil, nil, ErrNotPreviewable{Name: blob.Name(), Reason: "the file is too large"}
+	}
+	provider, err := FindBlobProvider(blob)
+	if err != nil {
+		return nil, nil, err
+	}
+	if provider == nil {
+		return nil, nil, ErrNotPreviewable{Name: blob.Name(), Reason: "no provider previews the file"}
+	}
+
+	key := "preview_" + provider.Name() + "_" + blob.ID.String()
+	c := cache.GetCache()
+	if c != nil && setting.CacheService.TTL > 0 {
+		if cached, ok := c.Get(key).(string); ok {
+			p := new(Preview)
+			if err := json.Unmarshal([]byte(cached), p); err == nil {
+				return p, provider, nil
+			}
+		}
+	}
+
+	rd, err := blob.DataAsync()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rd.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, setting.Preview.Timeout)
+	defer cancel()
+	p, err := provider.Generate(ctx, blob.Name(), rd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c != nil && setting.CacheService.TTL > 0 {
+		data, err := json.Marshal(p)
+		if err == nil {
+			err = c.Put(key, string(data), setting.CacheService.TTLSeconds())
+		}
+		if err != nil {
+			log.Warn("Unable to cache the preview under %s: %v", key, err)
+		}
+	}
+	return p, provider, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package preview
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+func init() {
+	RegisterProvider(stlProvider{})
+}
+
+// stlProvider extracts the number of triangles and the bounding box of STL models
+type stlProvider struct{}
+
+func (stlProvider) Name() string {
+	return "stl"
+}
+
+func (stlProvider) CanPreview(filename string, head []byte) bool {
+	return strings.EqualFold(path.Ext(filename), ".stl")
+}
+
+func (stlProvider) Generate(ctx context.Context, filename string, r io.Reader) (*Preview, error) {
+	data, err := io.ReadAll(io.LimitReader(r, setting.Preview.MaxFileSize))
+	if err != nil {
+		return nil, err
+	}
+	model, err := parseSTL(data)
+	if err != nil {
+		return nil, ErrNotPreviewable{Name: filename, Reason: err.Error()}
+	}
+	return &Preview{
+		Metadata: map[string]interface{}{
+			"format":    model.Format,
+			"triangles": model.Triangles,
+			"min":       model.Min,
+			"max":       model.Max,
+			"size":      [3]float64{model.Max[0] - model.Min[0], model.Max[1] - model.Min[1], model.Max[2] - model.Min[2]},
+		},
+	}, nil
+}
+
+type stlModel struct {
+	// Format is either "binary" or "ascii"
+	Format    string
+	Triangles int
+	Min       [3]float64
+	Max       [3]float64
+	vertices  int
+}
+
+func (m *stlModel) addVertex(v [3]float64) {
+	for i := range v {
+		if m.vertices == 0 || v[i] < m.Min[i] {
+			m.Min[i] = v[i]
+		}
+		if m.vertices == 0 || v[i] > m.Max[i] {
+			m.Max[i] = v[i]
+		}
+	}
+	m.vertices++
+}
+
+// parseSTL parses a binary or ASCII STL model. A binary model has an 80 bytes header, which may start
+// with "solid" as well, followed by the number of triangles and 50 bytes for each triangle.
+func parseSTL(data []byte) (*stlModel, error) {
+	if len(data) >= 84 {
+		count := binary.LittleEndian.Uint32(data[80:84])
+		if int64(len(data)) == 84+50*int64(count) {
+			return parseBinarySTL(data[84:], int(count)), nil
+		}
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return parseASCIISTL(data)
+	}
+	return nil, errors.New("invalid STL model")
+}
+
+func parseBinarySTL(data []byte, count int) *stlModel {
+	m := &stlModel{Format: "binary", Triangles: count}
+	for i := 0; i < count; i++ {
+		// each triangle is a normal and 3 vertices of 3 float32 followed by 2 bytes of attributes
+		triangle := data[i*50 : i*50+48]
+		for j := 1; j <= 3; j++ {
+			var v [3]float64
+			for k := range v {
+				bits := binary.LittleEndian.Uint32(triangle[j*12+k*4:])
+				v[k] = float64(math.Float32frombits(bits))
+			}
+			m.addVertex(v)
+		}
+	}
+	return m
+}
+
+func parseASCIISTL(data []byte) (*stlModel, error) {
+	m := &stlModel{Format: "ascii"}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "facet":
+			m.Triangles++
+		case "vertex":
+			if len(fields) != 4 {
+				return nil, fmt.Errorf("invalid vertex: %s", scanner.Text())
+			}
+			var v [3]float64
+			for k := range v {
+				f, err := strconv.ParseFloat(fields[k+1], 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid vertex: %s", scanner.Text())
+				}
+				v[k] = f
+			}
+			m.addVertex(v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
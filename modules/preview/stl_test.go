@@ -0,0 +1,63 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package preview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseASCIISTL(t *testing.T) {
+	model, err := parseSTL([]byte(`solid cube
+  facet normal 0 0 -1
+    outer loop
+      vertex 0 0 0
+      vertex 10 0 0
+      vertex 10 20 0
+    endloop
+  endfacet
+  facet normal 0 0 1
+    outer loop
+      vertex 0 -5 3.5
+      vertex 10 0 3.5
+      vertex 0 20 3.5
+    endloop
+  endfacet
+endsolid cube
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ascii", model.Format)
+	assert.Equal(t, 2, model.Triangles)
+	assert.Equal(t, [3]float64{0, -5, 0}, model.Min)
+	assert.Equal(t, [3]float64{10, 20, 3.5}, model.Max)
+
+	_, err = parseSTL([]byte("solid broken\nvertex 0 0\nendsolid broken\n"))
+	assert.Error(t, err)
+}
+
+func TestParseBinarySTL(t *testing.T) {
+	var buf bytes.Buffer
+	// the header of binary models may start with "solid" as well
+	buf.Write(append([]byte("solid binary"), make([]byte, 68)...))
+	assert.NoError(t, binary.Write(&buf, binary.LittleEndian, uint32(1)))
+	for _, f := range []float32{0, 0, 1, -1, 2, 0, 4, 2, 0, 1, 8, 0} {
+		assert.NoError(t, binary.Write(&buf, binary.LittleEndian, math.Float32bits(f)))
+	}
+	buf.Write([]byte{0, 0})
+
+	model, err := parseSTL(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, "binary", model.Format)
+	assert.Equal(t, 1, model.Triangles)
+	assert.Equal(t, [3]float64{-1, 2, 0}, model.Min)
+	assert.Equal(t, [3]float64{4, 8, 0}, model.Max)
+
+	_, err = parseSTL([]byte("not a model"))
+	assert.Error(t, err)
+}
@@ -66,11 +66,19 @@ type SSHLogOption struct {
 	Message string
 }
 
+// HookPreReceiveResult represents the result of PreReceive if the push is accepted
+type HookPreReceiveResult struct {
+	// Output is the output of the hook scripts of the repository
+	Output string
+}
+
 // HookPostReceiveResult represents an individual result from PostReceive
 type HookPostReceiveResult struct {
 	Results      []HookPostReceiveBranchResult
 	RepoWasEmpty bool
-	Err          string
+	// Output is the output of the hook scripts of the repository
+	Output string
+	Err    string
 }
 
 // HookPostReceiveBranchResult represents an individual branch result from PostReceive
@@ -98,7 +106,8 @@ type HookProcReceiveRefResult struct {
 	Err          string
 }
 
-// HookPreReceive check whether the provided commits are allowed
+// HookPreReceive check whether the provided commits are allowed,
+// if they are the output of the hook scripts of the repository is returned instead of an error message
 func HookPreReceive(ctx context.Context, ownerName, repoName string, opts HookOptions) (int, string) {
 	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/hook/pre-receive/%s/%s",
 		url.PathEscape(ownerName),
@@ -118,8 +127,10 @@ func HookPreReceive(ctx context.Context, ownerName, repoName string, opts HookOp
 	if resp.StatusCode != http.StatusOK {
 		return resp.StatusCode, decodeJSONError(resp).Err
 	}
+	res := &HookPreReceiveResult{}
+	_ = json.NewDecoder(resp.Body).Decode(res)
 
-	return http.StatusOK, ""
+	return http.StatusOK, res.Output
 }
 
 // HookPostReceive updates services and users
@@ -21,10 +21,11 @@ type RestoreParams struct {
 	OwnerName string
 	RepoName  string
 	Units     []string
+	Format    string
 }
 
 // RestoreRepo calls the internal RestoreRepo function
-func RestoreRepo(ctx context.Context, repoDir, ownerName, repoName string, units []string) (int, string) {
+func RestoreRepo(ctx context.Context, repoDir, ownerName, repoName string, units []string, format string) (int, string) {
 	reqURL := setting.LocalURL + "api/internal/restore_repo"
 
 	req := newInternalRequest(ctx, reqURL, "POST")
@@ -35,6 +36,7 @@ func RestoreRepo(ctx context.Context, repoDir, ownerName, repoName string, units
 		OwnerName: ownerName,
 		RepoName:  repoName,
 		Units:     units,
+		Format:    format,
 	})
 	req.Body(jsonBytes)
 	resp, err := req.Response()
@@ -72,6 +72,8 @@ type ManagedPool interface {
 	BoostWorkers() int
 	// SetPoolSettings sets the user updatable settings for the pool
 	SetPoolSettings(maxNumberOfWorkers, boostWorkers int, timeout time.Duration)
+	// NumberInQueue returns the number of items waiting to be handled by the pool
+	NumberInQueue() int64
 }
 
 // ManagedQueueList implements the sort.Interface
@@ -317,6 +319,26 @@ func (q *ManagedQueue) IsEmpty() bool {
 	return true
 }
 
+// IsTerminated returns if the queue has been terminated and no longer accepts work
+func (q *ManagedQueue) IsTerminated() bool {
+	if terminatable, ok := q.Managed.(Terminatable); ok {
+		select {
+		case <-terminatable.IsTerminated():
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// NumberInQueue returns the number of items waiting in the queue, -1 if the queue can not tell
+func (q *ManagedQueue) NumberInQueue() int64 {
+	if pool, ok := q.Managed.(ManagedPool); ok {
+		return pool.NumberInQueue()
+	}
+	return -1
+}
+
 // NumberOfWorkers returns the number of workers in the queue
 func (q *ManagedQueue) NumberOfWorkers() int {
 	if pool, ok := q.Managed.(ManagedPool); ok {
@@ -47,6 +47,11 @@ type Shutdownable interface {
 	Terminate()
 }
 
+// Terminatable represents a queue that reports when it has been terminated
+type Terminatable interface {
+	IsTerminated() <-chan struct{}
+}
+
 // Named represents a queue with a name
 type Named interface {
 	Name() string
@@ -108,6 +108,11 @@ func (q *ByteFIFOQueue) IsEmpty() bool {
 	return q.byteFIFO.Len(q.terminateCtx) == 0
 }
 
+// NumberInQueue returns the number of items in the pool and in the byte FIFO
+func (q *ByteFIFOQueue) NumberInQueue() int64 {
+	return q.WorkerPool.NumberInQueue() + q.byteFIFO.Len(q.terminateCtx)
+}
+
 // Run runs the bytefifo queue
 func (q *ByteFIFOQueue) Run(atShutdown, atTerminate func(func())) {
 	atShutdown(q.Shutdown)
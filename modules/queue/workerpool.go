@@ -315,6 +315,11 @@ func (p *WorkerPool) Flush(timeout time.Duration) error {
 	return p.FlushWithContext(ctx)
 }
 
+// NumberInQueue returns the number of items waiting to be handled by the pool
+func (p *WorkerPool) NumberInQueue() int64 {
+	return atomic.LoadInt64(&p.numInQueue)
+}
+
 // IsEmpty returns if true if the worker queue is empty
 func (p *WorkerPool) IsEmpty() bool {
 	return atomic.LoadInt64(&p.numInQueue) == 0
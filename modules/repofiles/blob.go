@@ -5,10 +5,14 @@
 package repofiles
 
 import (
+	"io"
+	"os"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // GetBlobBySHA get the GitBlobResponse of a repository using a sha hash.
@@ -37,3 +41,42 @@ func GetBlobBySHA(repo *models.Repository, sha string) (*api.GitBlobResponse, er
 		Content:  content,
 	}, nil
 }
+
+// CreateBlob writes the content read from r as a blob object of a repository, the content may be up to maxSize
+// bytes. It is spooled to a temporary file first so no object is written for a failed or too large upload.
+func CreateBlob(repo *models.Repository, r io.Reader, maxSize int64) (*api.CreatedGitBlob, error) {
+	tmpFile, err := os.CreateTemp("", "gitea-blob-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		_ = util.Remove(tmpFile.Name())
+	}()
+
+	size, err := io.Copy(tmpFile, io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > maxSize {
+		return nil, models.ErrBlobTooLarge{MaxSize: maxSize}
+	}
+	if _, err = tmpFile.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+	id, err := gitRepo.HashObject(tmpFile)
+	if err != nil {
+		return nil, err
+	}
+	return &api.CreatedGitBlob{
+		SHA:  id.String(),
+		URL:  repo.APIURL() + "/git/blobs/" + id.String(),
+		Size: size,
+	}, nil
+}
@@ -5,8 +5,10 @@
 package repofiles
 
 import (
+	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/test"
@@ -38,3 +40,23 @@ func TestGetBlobBySHA(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedGBR, gbr)
 }
+
+func TestCreateBlob(t *testing.T) {
+	db.PrepareTestEnv(t)
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	blob, err := CreateBlob(repo, strings.NewReader("Hello, world!\n"), 14)
+	assert.NoError(t, err)
+	assert.Equal(t, &api.CreatedGitBlob{
+		SHA:  "af5626b4a114abcb82d63db7c8082c3c4756e51b",
+		URL:  "https://try.gitea.io/api/v1/repos/user2/repo1/git/blobs/af5626b4a114abcb82d63db7c8082c3c4756e51b",
+		Size: 14,
+	}, blob)
+
+	gbr, err := GetBlobBySHA(repo, blob.SHA)
+	assert.NoError(t, err)
+	assert.Equal(t, "SGVsbG8sIHdvcmxkIQo=", gbr.Content)
+
+	_, err = CreateBlob(repo, strings.NewReader("Hello, world!\n"), 13)
+	assert.True(t, models.IsErrBlobTooLarge(err))
+}
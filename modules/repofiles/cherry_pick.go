@@ -0,0 +1,186 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+)
+
+// ErrCherryPickEmpty indicates a commit without changes is cherry-picked or reverted
+var ErrCherryPickEmpty = errors.New("Commit has no changes to apply")
+
+// ErrCherryPickConflict represents a cherry-pick or revert whose changes do not apply to the branch
+type ErrCherryPickConflict struct {
+	Branch string
+	Files  []string
+}
+
+// IsErrCherryPickConflict checks if an error is a ErrCherryPickConflict.
+func IsErrCherryPickConflict(err error) bool {
+	_, ok := err.(ErrCherryPickConflict)
+	return ok
+}
+
+func (err ErrCherryPickConflict) Error() string {
+	return fmt.Sprintf("changes do not apply to %s in %s", err.Branch, strings.Join(err.Files, ", "))
+}
+
+// CherryPickOptions holds the options to cherry-pick or revert a commit onto a branch
+type CherryPickOptions struct {
+	OldBranch string
+	NewBranch string
+	// Message defaults to the message of the commit for a cherry-pick and to a revert message for a revert
+	Message   string
+	Revert    bool
+	Author    *IdentityOptions
+	Committer *IdentityOptions
+	Dates     *CommitDateOptions
+	Signoff   bool
+	Trailers  map[string]string
+}
+
+// CherryPick applies the changes of a commit, or their reverse if reverting, onto the head of a branch
+// and pushes the new commit to the branch or a new branch. The changes of a merge commit are those
+// against its first parent.
+func CherryPick(repo *models.Repository, doer *models.User, commitID string, opts *CherryPickOptions) (*git.Commit, error) {
+	if err := git.ValidateTrailers(opts.Trailers); err != nil {
+		return nil, err
+	}
+
+	if opts.OldBranch == "" {
+		opts.OldBranch = repo.DefaultBranch
+	}
+	if opts.NewBranch == "" {
+		opts.NewBranch = opts.OldBranch
+	}
+
+	// oldBranch must exist for this operation
+	if _, err := repo_module.GetBranch(repo, opts.OldBranch); err != nil {
+		return nil, err
+	}
+	if opts.NewBranch != opts.OldBranch {
+		existingBranch, err := repo_module.GetBranch(repo, opts.NewBranch)
+		if existingBranch != nil {
+			return nil, models.ErrBranchAlreadyExists{
+				BranchName: opts.NewBranch,
+			}
+		}
+		if err != nil && !git.IsErrBranchNotExist(err) {
+			return nil, err
+		}
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, models.ErrSHANotFound{SHA: commitID}
+		}
+		return nil, err
+	}
+	parentID := git.EmptyTreeSHA
+	if commit.ParentCount() > 0 {
+		parent, err := commit.ParentID(0)
+		if err != nil {
+			return nil, err
+		}
+		parentID = parent.String()
+	}
+
+	if opts.NewBranch == opts.OldBranch {
+		files, err := commit.GetFilesChangedSinceCommit(parentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file == "" {
+				continue
+			}
+			if err := VerifyBranchProtection(repo, doer, opts.OldBranch, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	patch := new(bytes.Buffer)
+	if opts.Revert {
+		err = gitRepo.GetDiff(commit.ID.String(), parentID, patch)
+	} else {
+		err = gitRepo.GetDiff(parentID, commit.ID.String(), patch)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if patch.Len() == 0 {
+		return nil, ErrCherryPickEmpty
+	}
+
+	message := strings.TrimSpace(opts.Message)
+	if message == "" {
+		if opts.Revert {
+			message = fmt.Sprintf("Revert \"%s\"\n\nThis reverts commit %s.", commit.Summary(), commit.ID.String())
+		} else {
+			message = fmt.Sprintf("%s\n\n(cherry picked from commit %s)", strings.TrimSpace(commit.Message()), commit.ID.String())
+		}
+	}
+	message = git.AppendTrailers(message, opts.Trailers)
+
+	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
+
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	if err := t.Clone(opts.OldBranch); err != nil {
+		return nil, err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return nil, err
+	}
+
+	conflicts, err := t.ApplyPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, ErrCherryPickConflict{
+			Branch: opts.OldBranch,
+			Files:  conflicts,
+		}
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return nil, err
+	}
+	var commitHash string
+	if opts.Dates != nil {
+		commitHash, err = t.CommitTreeWithDate(author, committer, treeHash, message, opts.Signoff, opts.Dates.Author, opts.Dates.Committer)
+	} else {
+		commitHash, err = t.CommitTree(author, committer, treeHash, message, opts.Signoff)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.Push(doer, commitHash, opts.NewBranch); err != nil {
+		return nil, err
+	}
+	// the temporary repository is removed on return, the commit is read from the repository it was pushed to
+	return gitRepo.GetCommit(commitHash)
+}
@@ -5,6 +5,9 @@
 package repofiles
 
 import (
+	"strconv"
+	"strings"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 )
@@ -17,3 +20,112 @@ func CountDivergingCommits(repo *models.Repository, branch string) (*git.Diverge
 	}
 	return &divergence, nil
 }
+
+// CreateCommitOptions holds the options to create a commit object from an existing tree
+type CreateCommitOptions struct {
+	Message string
+	TreeSHA string
+	// Parents are commit SHAs, or "$<index>" to reference an earlier commit of the same batch
+	Parents   []string
+	Author    *IdentityOptions
+	Committer *IdentityOptions
+	Dates     *CommitDateOptions
+	Signoff   bool
+	Trailers  map[string]string
+}
+
+// CreateCommit creates a commit object from an existing tree, no branch is updated
+func CreateCommit(repo *models.Repository, doer *models.User, opts *CreateCommitOptions) (*git.Commit, error) {
+	commits, err := CreateCommits(repo, doer, []*CreateCommitOptions{opts})
+	if err != nil {
+		return nil, err
+	}
+	return commits[0], nil
+}
+
+// CreateCommits creates commit objects from existing trees in order within a single git session,
+// later commits may reference earlier ones as parents. No branch is updated.
+func CreateCommits(repo *models.Repository, doer *models.User, opts []*CreateCommitOptions) ([]*git.Commit, error) {
+	for _, opt := range opts {
+		if err := git.ValidateTrailers(opt.Trailers); err != nil {
+			return nil, err
+		}
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commits := make([]*git.Commit, 0, len(opts))
+	for _, opt := range opts {
+		tree, err := gitRepo.GetTree(opt.TreeSHA)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				return nil, models.ErrSHANotFound{SHA: opt.TreeSHA}
+			}
+			return nil, err
+		}
+
+		parents := make([]string, 0, len(opt.Parents))
+		for _, parent := range opt.Parents {
+			if strings.HasPrefix(parent, "$") {
+				idx, err := strconv.Atoi(parent[1:])
+				if err != nil || idx < 0 || idx >= len(commits) {
+					return nil, models.ErrSHANotFound{SHA: parent}
+				}
+				parents = append(parents, commits[idx].ID.String())
+				continue
+			}
+			commit, err := gitRepo.GetCommit(parent)
+			if err != nil {
+				if git.IsErrNotExist(err) {
+					return nil, models.ErrSHANotFound{SHA: parent}
+				}
+				return nil, err
+			}
+			parents = append(parents, commit.ID.String())
+		}
+
+		author, committer := GetAuthorAndCommitterUsers(opt.Author, opt.Committer, doer)
+		authorSig, committerSig := author.NewGitSig(), committer.NewGitSig()
+
+		commitTreeOpts := git.CommitTreeOpts{
+			Parents:  parents,
+			Message:  strings.TrimSpace(opt.Message),
+			Trailers: opt.Trailers,
+		}
+		if opt.Dates != nil {
+			commitTreeOpts.AuthorDate = opt.Dates.Author
+			commitTreeOpts.CommitterDate = opt.Dates.Committer
+		}
+
+		var parentCommit string
+		if len(parents) > 0 {
+			parentCommit = parents[0]
+		}
+		if sign, keyID, signer, _ := repo.SignCRUDAction(author, repo.RepoPath(), parentCommit); sign {
+			commitTreeOpts.KeyID = keyID
+			if repo.GetTrustModel() == models.CommitterTrustModel || repo.GetTrustModel() == models.CollaboratorCommitterTrustModel {
+				committerSig = signer
+			}
+		} else {
+			commitTreeOpts.NoGPGSign = true
+		}
+		if opt.Signoff {
+			commitTreeOpts.Message += "\n\nSigned-off-by: " + committerSig.String()
+		}
+
+		id, err := gitRepo.CommitTree(authorSig, committerSig, tree, commitTreeOpts)
+		if err != nil {
+			return nil, err
+		}
+		commit, err := gitRepo.GetCommit(id.String())
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
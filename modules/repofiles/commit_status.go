@@ -8,7 +8,11 @@ import (
 	"fmt"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/eventstream"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // CreateCommitStatus creates a new CommitStatus given a bunch of parameters
@@ -37,5 +41,19 @@ func CreateCommitStatus(repo *models.Repository, creator *models.User, sha strin
 		return fmt.Errorf("NewCommitStatus[repo_id: %d, user_id: %d, sha: %s]: %v", repo.ID, creator.ID, sha, err)
 	}
 
+	if setting.EventStream.Enabled {
+		eventstream.Publish(&eventstream.Event{
+			Type:   "status",
+			Action: string(status.State),
+			RepoID: repo.ID,
+			Unit:   models.UnitTypeCode,
+			Actor:  creator,
+			Payload: &api.StreamStatusPayload{
+				SHA:    sha,
+				Status: convert.ToCommitStatus(status),
+			},
+		})
+	}
+
 	return nil
 }
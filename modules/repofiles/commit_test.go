@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCommits(t *testing.T) {
+	db.PrepareTestEnv(t)
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+	head, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	assert.NoError(t, err)
+	treeSHA := head.Tree.ID.String()
+
+	authorDate := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	commits, err := CreateCommits(repo, doer, []*CreateCommitOptions{
+		{Message: "first", TreeSHA: treeSHA, Parents: []string{head.ID.String()}, Dates: &CommitDateOptions{Author: authorDate}},
+		{Message: "second", TreeSHA: treeSHA, Parents: []string{"$0"}, Author: &IdentityOptions{Name: "Jane", Email: "jane@example.com"}},
+		{Message: "merge", TreeSHA: treeSHA, Parents: []string{"$1", head.ID.String()}, Signoff: true},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 3) {
+		assert.Equal(t, head.ID, commits[0].Parents[0])
+		assert.True(t, authorDate.Equal(commits[0].Author.When))
+		assert.Equal(t, commits[0].ID, commits[1].Parents[0])
+		assert.Equal(t, "jane@example.com", commits[1].Author.Email)
+		assert.Equal(t, []git.SHA1{commits[1].ID, head.ID}, commits[2].Parents)
+		assert.Contains(t, commits[2].CommitMessage, "Signed-off-by: ")
+	}
+
+	// the branch is not updated
+	branchCommit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	assert.NoError(t, err)
+	assert.Equal(t, head.ID, branchCommit.ID)
+
+	_, err = CreateCommits(repo, doer, []*CreateCommitOptions{
+		{Message: "forward reference", TreeSHA: treeSHA, Parents: []string{"$0"}},
+	})
+	assert.True(t, models.IsErrSHANotFound(err))
+
+	_, err = CreateCommit(repo, doer, &CreateCommitOptions{Message: "missing tree", TreeSHA: "0000000000000000000000000000000000000000"})
+	assert.True(t, models.IsErrSHANotFound(err))
+}
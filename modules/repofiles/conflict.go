@@ -0,0 +1,201 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// The labels of the conflict markers of a merge preview
+const (
+	mergeLabelHead     = "head"
+	mergeLabelBase     = "base"
+	mergeLabelProposed = "proposed"
+)
+
+// PreviewFileEdit merges an edit of a file based on a base commit into the file of the branch tip without
+// committing anything and returns the merged content or the conflicting hunks
+func PreviewFileEdit(repo *models.Repository, branch, treePath, baseCommitID string, content []byte) (*structs.FileEditPreview, error) {
+	if branch == "" {
+		branch = repo.DefaultBranch
+	}
+	treePath = CleanUploadFileName(treePath)
+	if treePath == "" {
+		return nil, models.ErrFilenameInvalid{
+			Path: treePath,
+		}
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	headCommit, err := gitRepo.GetBranchCommit(branch)
+	if err != nil {
+		return nil, err
+	}
+	baseCommit, err := gitRepo.GetCommit(baseCommitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, models.ErrSHANotFound{SHA: baseCommitID}
+		}
+		return nil, err
+	}
+
+	preview := &structs.FileEditPreview{
+		BaseCommit: baseCommit.ID.String(),
+		HeadCommit: headCommit.ID.String(),
+		Conflicts:  []*structs.FileEditConflict{},
+	}
+
+	baseEntry, baseContent, err := readFileOfCommit(baseCommit, treePath)
+	if err != nil {
+		return nil, err
+	}
+	headEntry, headContent, err := readFileOfCommit(headCommit, treePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing to merge if the file is unchanged on the branch or already has the proposed content
+	if (baseEntry == nil && headEntry == nil) ||
+		(baseEntry != nil && headEntry != nil && baseEntry.ID == headEntry.ID) ||
+		bytes.Equal(headContent, content) {
+		preview.Content = base64.StdEncoding.EncodeToString(content)
+		return preview, nil
+	}
+
+	// git cannot merge binary files line by line
+	for _, data := range [][]byte{baseContent, headContent, content} {
+		if !typesniffer.DetectContentType(data).IsRepresentableAsText() {
+			preview.Conflicted = true
+			return preview, nil
+		}
+	}
+
+	merged, conflicts, err := mergeFileContents(headContent, baseContent, content)
+	if err != nil {
+		return nil, err
+	}
+	preview.Content = base64.StdEncoding.EncodeToString(merged)
+	preview.Conflicted = len(conflicts) > 0
+	preview.Conflicts = conflicts
+	return preview, nil
+}
+
+// readFileOfCommit returns the tree entry and the content of a file of a commit, nil is returned if it does not exist
+func readFileOfCommit(commit *git.Commit, treePath string) (*git.TreeEntry, []byte, error) {
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if !entry.IsRegular() && !entry.IsExecutable() {
+		return nil, nil, models.ErrFilePathInvalid{
+			Message: fmt.Sprintf("%s is not a file", treePath),
+			Path:    treePath,
+			Name:    entry.Name(),
+			Type:    entry.Mode(),
+		}
+	}
+
+	dataRc, err := entry.Blob().DataAsync()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer dataRc.Close()
+	content, err := io.ReadAll(dataRc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry, content, nil
+}
+
+// mergeFileContents runs a 3-way merge of the contents of a file and returns the merged content with diff3 style
+// conflict markers and the conflicting hunks
+func mergeFileContents(head, base, proposed []byte) ([]byte, []*structs.FileEditConflict, error) {
+	tmpDir, err := os.MkdirTemp("", "gitea-merge-file-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err := util.RemoveAll(tmpDir); err != nil {
+			log.Error("Unable to remove %s: %v", tmpDir, err)
+		}
+	}()
+
+	// the files are named like their labels
+	labels := []string{mergeLabelHead, mergeLabelBase, mergeLabelProposed}
+	for i, content := range [][]byte{head, base, proposed} {
+		if err := os.WriteFile(filepath.Join(tmpDir, labels[i]), content, 0o600); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	err = git.NewCommand("merge-file", "-p", "--diff3",
+		"-L", mergeLabelHead, "-L", mergeLabelBase, "-L", mergeLabelProposed).
+		AddArguments(labels...).
+		RunInDirTimeoutFullPipeline(-1, tmpDir, stdout, stderr, nil)
+	if err != nil {
+		// merge-file exits with the number of conflicts
+		var exitError *exec.ExitError
+		if !errors.As(err, &exitError) || exitError.ExitCode() <= 0 || exitError.ExitCode() > 127 {
+			return nil, nil, fmt.Errorf("%v - %s", err, stderr)
+		}
+	}
+	return stdout.Bytes(), parseMergeConflicts(stdout.String()), nil
+}
+
+// parseMergeConflicts returns the conflicting hunks marked in the output of a diff3 style merge
+func parseMergeConflicts(merged string) []*structs.FileEditConflict {
+	const markerSize = 7
+	conflicts := []*structs.FileEditConflict{}
+
+	var conflict *structs.FileEditConflict
+	var section *strings.Builder
+	for i, line := range strings.SplitAfter(merged, "\n") {
+		trimmed := strings.TrimRight(line, "\r\n")
+		switch {
+		case trimmed == strings.Repeat("<", markerSize)+" "+mergeLabelHead:
+			conflict = &structs.FileEditConflict{Line: i + 1}
+			section = new(strings.Builder)
+		case conflict == nil:
+		case trimmed == strings.Repeat("|", markerSize)+" "+mergeLabelBase:
+			conflict.Head = section.String()
+			section = new(strings.Builder)
+		case trimmed == strings.Repeat("=", markerSize):
+			conflict.Base = section.String()
+			section = new(strings.Builder)
+		case trimmed == strings.Repeat(">", markerSize)+" "+mergeLabelProposed:
+			conflict.Proposed = section.String()
+			conflicts = append(conflicts, conflict)
+			conflict = nil
+		default:
+			section.WriteString(line)
+		}
+	}
+	return conflicts
+}
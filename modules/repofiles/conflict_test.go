@@ -0,0 +1,61 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeFileContents(t *testing.T) {
+	base := []byte("a\nb\nc\nd\ne\n")
+
+	t.Run("Clean merge", func(t *testing.T) {
+		merged, conflicts, err := mergeFileContents([]byte("A\nb\nc\nd\ne\n"), base, []byte("a\nb\nc\nd\nE\n"))
+		assert.NoError(t, err)
+		assert.Empty(t, conflicts)
+		assert.Equal(t, "A\nb\nc\nd\nE\n", string(merged))
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		merged, conflicts, err := mergeFileContents([]byte("a\nb\nX\nd\ne\n"), base, []byte("a\nb\nY\nd\ne\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "a\nb\n<<<<<<< head\nX\n||||||| base\nc\n=======\nY\n>>>>>>> proposed\nd\ne\n", string(merged))
+		assert.Equal(t, []*structs.FileEditConflict{
+			{Line: 3, Head: "X\n", Base: "c\n", Proposed: "Y\n"},
+		}, conflicts)
+	})
+}
+
+func TestParseMergeConflicts(t *testing.T) {
+	conflicts := parseMergeConflicts("<<<<<<< head\n||||||| base\nold\n=======\nnew\n>>>>>>> proposed\nkept\n<<<<<<< head\nx\n||||||| base\n=======\ny\n>>>>>>> proposed\n")
+	assert.Equal(t, []*structs.FileEditConflict{
+		{Line: 1, Head: "", Base: "old\n", Proposed: "new\n"},
+		{Line: 8, Head: "x\n", Base: "", Proposed: "y\n"},
+	}, conflicts)
+
+	assert.Empty(t, parseMergeConflicts("no conflicts\n"))
+}
+
+func TestPreviewFileEdit(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	const headCommitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	preview, err := PreviewFileEdit(repo, "", "README.md", headCommitID, []byte("# repo1\n\nNew description\n"))
+	assert.NoError(t, err)
+	assert.False(t, preview.Conflicted)
+	assert.Equal(t, headCommitID, preview.HeadCommit)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("# repo1\n\nNew description\n")), preview.Content)
+
+	_, err = PreviewFileEdit(repo, "", "README.md", "0000000000000000000000000000000000000001", []byte("content"))
+	assert.True(t, models.IsErrSHANotFound(err))
+}
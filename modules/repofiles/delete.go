@@ -26,10 +26,15 @@ type DeleteRepoFileOptions struct {
 	Committer    *IdentityOptions
 	Dates        *CommitDateOptions
 	Signoff      bool
+	Trailers     map[string]string
 }
 
 // DeleteRepoFile deletes a file in the given repository
 func DeleteRepoFile(repo *models.Repository, doer *models.User, opts *DeleteRepoFileOptions) (*api.FileResponse, error) {
+	if err := git.ValidateTrailers(opts.Trailers); err != nil {
+		return nil, err
+	}
+
 	// If no branch name is set, assume the repo's default branch
 	if opts.OldBranch == "" {
 		opts.OldBranch = repo.DefaultBranch
@@ -69,9 +74,14 @@ func DeleteRepoFile(repo *models.Repository, doer *models.User, opts *DeleteRepo
 	}
 
 	message := strings.TrimSpace(opts.Message)
+	message = git.AppendTrailers(message, opts.Trailers)
 
 	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
 
+	if err := VerifyPushRule(repo, author, nil); err != nil {
+		return nil, err
+	}
+
 	t, err := NewTemporaryUploadRepository(repo)
 	if err != nil {
 		return nil, err
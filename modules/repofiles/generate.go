@@ -0,0 +1,186 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+// GenerateRepoFilesOptions holds the options to generate files from the bundled templates
+type GenerateRepoFilesOptions struct {
+	License         string
+	Gitignores      []string
+	Year            int
+	CopyrightHolder string
+	OldBranch       string
+	NewBranch       string
+	Message         string
+	Author          *IdentityOptions
+	Committer       *IdentityOptions
+	Dates           *CommitDateOptions
+	Signoff         bool
+	Trailers        map[string]string
+}
+
+// generatedFile is a file generated from a template
+type generatedFile struct {
+	treePath string
+	content  []byte
+}
+
+// GenerateRepoFiles generates a LICENSE and a .gitignore file from the bundled templates and commits them
+func GenerateRepoFiles(repo *models.Repository, doer *models.User, opts *GenerateRepoFilesOptions) (*structs.FileResponse, error) {
+	if err := git.ValidateTrailers(opts.Trailers); err != nil {
+		return nil, err
+	}
+
+	files := make([]*generatedFile, 0, 2)
+	if opts.License != "" {
+		if opts.Year == 0 {
+			opts.Year = time.Now().Year()
+		}
+		if opts.CopyrightHolder == "" {
+			if err := repo.GetOwner(); err != nil {
+				return nil, err
+			}
+			opts.CopyrightHolder = repo.Owner.DisplayName()
+		}
+		content, err := repo_module.GenerateLicense(opts.License, opts.Year, opts.CopyrightHolder)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &generatedFile{treePath: "LICENSE", content: content})
+	}
+	if len(opts.Gitignores) > 0 {
+		content, err := repo_module.GenerateGitignore(opts.Gitignores)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &generatedFile{treePath: ".gitignore", content: content})
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no template to generate files from")
+	}
+
+	if opts.OldBranch == "" {
+		opts.OldBranch = repo.DefaultBranch
+	}
+	if opts.NewBranch == "" {
+		opts.NewBranch = opts.OldBranch
+	}
+
+	// oldBranch must exist for this operation
+	if _, err := repo_module.GetBranch(repo, opts.OldBranch); err != nil {
+		return nil, err
+	}
+
+	if opts.NewBranch != opts.OldBranch {
+		existingBranch, err := repo_module.GetBranch(repo, opts.NewBranch)
+		if existingBranch != nil {
+			return nil, models.ErrBranchAlreadyExists{
+				BranchName: opts.NewBranch,
+			}
+		}
+		if err != nil && !git.IsErrBranchNotExist(err) {
+			return nil, err
+		}
+	} else {
+		for _, file := range files {
+			if err := VerifyBranchProtection(repo, doer, opts.OldBranch, file.treePath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	treePaths := make([]string, 0, len(files))
+	for _, file := range files {
+		treePaths = append(treePaths, file.treePath)
+	}
+	message := strings.TrimSpace(opts.Message)
+	if message == "" {
+		message = "Add " + strings.Join(treePaths, " and ")
+	}
+	message = git.AppendTrailers(message, opts.Trailers)
+
+	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
+
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	if err := t.Clone(opts.OldBranch); err != nil {
+		return nil, err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return nil, err
+	}
+
+	commit, err := t.GetBranchCommit(opts.OldBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]int64, len(files))
+	for _, file := range files {
+		if _, err := commit.GetTreeEntryByPath(file.treePath); err == nil {
+			return nil, models.ErrRepoFileAlreadyExists{
+				Path: file.treePath,
+			}
+		} else if !git.IsErrNotExist(err) {
+			return nil, err
+		}
+
+		objectHash, err := t.HashObject(bytes.NewReader(file.content))
+		if err != nil {
+			return nil, err
+		}
+		if err := t.AddObjectToIndex("100644", objectHash, file.treePath); err != nil {
+			return nil, err
+		}
+		sizes[file.treePath] = int64(len(file.content))
+	}
+
+	if err := VerifyPushRule(repo, author, sizes); err != nil {
+		return nil, err
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return nil, err
+	}
+
+	var commitHash string
+	if opts.Dates != nil {
+		commitHash, err = t.CommitTreeWithDate(author, committer, treeHash, message, opts.Signoff, opts.Dates.Author, opts.Dates.Committer)
+	} else {
+		commitHash, err = t.CommitTree(author, committer, treeHash, message, opts.Signoff)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.Push(doer, commitHash, opts.NewBranch); err != nil {
+		return nil, err
+	}
+
+	commit, err = t.GetCommit(commitHash)
+	if err != nil {
+		return nil, err
+	}
+	fileCommitResponse, _ := GetFileCommitResponse(repo, commit) // ok if fails, then will be nil
+	return &structs.FileResponse{
+		Commit:       fileCommitResponse,
+		Verification: GetPayloadCommitVerification(commit),
+	}, nil
+}
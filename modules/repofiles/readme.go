@@ -0,0 +1,122 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"io"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// readmeDocsDirs are searched for a README if the root directory has none, sorted by priority
+var readmeDocsDirs = []string{"docs", ".gitea", ".github"}
+
+// FindReadme returns the path of the README shown on the repository home page,
+// or an empty string if there is none
+func FindReadme(commit *git.Commit) (string, error) {
+	name, err := findReadmeInDir(commit, "")
+	if err != nil || name != "" {
+		return name, err
+	}
+	for _, dir := range readmeDocsDirs {
+		if name, err = findReadmeInDir(commit, dir); err != nil || name != "" {
+			return name, err
+		}
+	}
+	return "", nil
+}
+
+func findReadmeInDir(commit *git.Commit, dir string) (string, error) {
+	tree, err := commit.SubTree(dir)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	entries, err := tree.ListEntries()
+	if err != nil {
+		return "", err
+	}
+
+	// sorted by priority, the last one matches any extension
+	var found [4]string
+	exts := []string{".md", ".txt", ""}
+	for _, entry := range entries {
+		if !entry.IsRegular() && !entry.IsExecutable() {
+			continue
+		}
+		for i, ext := range exts {
+			if markup.IsReadmeFile(entry.Name(), ext) && found[i] == "" {
+				found[i] = entry.Name()
+			}
+		}
+		if markup.IsReadmeFile(entry.Name()) && found[3] == "" {
+			found[3] = entry.Name()
+		}
+	}
+	for _, name := range found {
+		if name != "" {
+			return path.Join(dir, name), nil
+		}
+	}
+	return "", nil
+}
+
+// RenderReadme renders a README of a repository at the given ref: markup files as HTML and other text files
+// as escaped text. Binary files and files too large to be displayed are rendered as an empty string.
+func RenderReadme(ctx context.Context, repo *models.Repository, gitRepo *git.Repository, blob *git.Blob, treePath, ref string) (string, error) {
+	if blob.Size() >= setting.UI.MaxDisplayFileSize {
+		return "", nil
+	}
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return "", err
+	}
+	defer dataRc.Close()
+
+	buf := make([]byte, 1024)
+	n, _ := io.ReadFull(dataRc, buf)
+	buf = buf[:n]
+	if !typesniffer.DetectContentType(buf).IsText() {
+		return "", nil
+	}
+	rd := charset.ToUTF8WithFallbackReader(io.MultiReader(bytes.NewReader(buf), dataRc))
+
+	if markup.Type(treePath) == "" {
+		content, err := io.ReadAll(rd)
+		if err != nil {
+			return "", err
+		}
+		return strings.ReplaceAll(template.HTMLEscapeString(string(content)), "\n", "<br>"), nil
+	}
+
+	urlPrefix := repo.HTMLURL() + "/src/" + util.PathEscapeSegments(ref)
+	if dir := path.Dir(treePath); dir != "." {
+		urlPrefix += "/" + util.PathEscapeSegments(dir)
+	}
+
+	var result strings.Builder
+	err = markup.Render(&markup.RenderContext{
+		Ctx:       ctx,
+		Filename:  treePath,
+		URLPrefix: urlPrefix,
+		Metas:     repo.ComposeDocumentMetas(),
+		GitRepo:   gitRepo,
+	}, rd, &result)
+	return result.String(), err
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/csv"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	_ "code.gitea.io/gitea/modules/markup/csv" // register the renderer of CSV files
+	"code.gitea.io/gitea/modules/markup/jupyter"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// renderedFileCache is a rendered file cached under the ID of its blob
+type renderedFileCache struct {
+	// URLPrefix is the prefix the links of the file are rendered with, which differs between refs and paths
+	URLPrefix string
+	File      *api.RenderedFile
+}
+
+// maxRenderedFileSize returns the maximum size of the files rendered by the renderer to HTML and JSON structures,
+// or -1 if the renderer does not render such structures
+func maxRenderedFileSize(renderer markup.Renderer) int64 {
+	if renderer == nil {
+		return -1
+	}
+	switch renderer.Name() {
+	case "csv":
+		return setting.UI.CSV.MaxFileSize
+	case "jupyter":
+		return setting.UI.Notebook.MaxFileSize
+	}
+	return -1
+}
+
+// IsRenderableFile returns whether RenderFile renders the file
+func IsRenderableFile(treePath string) bool {
+	return maxRenderedFileSize(markup.GetRendererByFileName(treePath)) >= 0
+}
+
+// RenderFile renders a CSV file or a Jupyter notebook of a repository at the given ref to sanitized HTML
+// and its rows or cells. Files larger than the maximum size of their renderer are not rendered. The result
+// is cached under the ID of the blob, as long as the links in it stay the same.
+func RenderFile(ctx context.Context, repo *models.Repository, gitRepo *git.Repository, blob *git.Blob, treePath, ref string) (*api.RenderedFile, error) {
+	renderer := markup.GetRendererByFileName(treePath)
+	maxSize := maxRenderedFileSize(renderer)
+	if maxSize < 0 {
+		return nil, markup.ErrUnsupportedRenderExtension{Extension: path.Ext(treePath)}
+	}
+
+	urlPrefix := repo.HTMLURL() + "/src/" + util.PathEscapeSegments(ref)
+	if dir := path.Dir(treePath); dir != "." {
+		urlPrefix += "/" + util.PathEscapeSegments(dir)
+	}
+
+	key := "rendered_file_" + blob.ID.String()
+	c := cache.GetCache()
+	if c != nil && setting.CacheService.TTL > 0 {
+		if cached, ok := c.Get(key).(string); ok {
+			var rendered renderedFileCache
+			if err := json.Unmarshal([]byte(cached), &rendered); err == nil && rendered.URLPrefix == urlPrefix {
+				rendered.File.Name = blob.Name()
+				rendered.File.Path = treePath
+				return rendered.File, nil
+			}
+		}
+	}
+
+	file := &api.RenderedFile{
+		Name: blob.Name(),
+		Path: treePath,
+		SHA:  blob.ID.String(),
+		Type: renderer.Name(),
+	}
+	if maxSize != 0 && blob.Size() > maxSize {
+		file.TooLarge = true
+		return file, nil
+	}
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	content, err := io.ReadAll(charset.ToUTF8WithFallbackReader(dataRc))
+	if err != nil {
+		return nil, err
+	}
+
+	var html strings.Builder
+	if err := markup.Render(&markup.RenderContext{
+		Ctx:       ctx,
+		Filename:  treePath,
+		URLPrefix: urlPrefix,
+		Metas:     repo.ComposeDocumentMetas(),
+		GitRepo:   gitRepo,
+	}, bytes.NewReader(content), &html); err != nil {
+		return nil, err
+	}
+	file.HTML = html.String()
+
+	switch file.Type {
+	case "csv":
+		rd, err := csv.CreateReaderAndGuessDelimiter(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		for {
+			fields, err := rd.Read()
+			if err == io.EOF {
+				break
+			}
+			// rows which cannot be parsed are left out, like in the HTML
+			if err == nil {
+				file.Rows = append(file.Rows, fields)
+			}
+		}
+	case "jupyter":
+		// an invalid notebook is rendered as it is and has no cells
+		if nb, err := jupyter.Parse(bytes.NewReader(content)); err == nil {
+			file.Language = nb.Language()
+			file.Cells = convert.ToNotebookCells(nb)
+		}
+	}
+
+	if c != nil && setting.CacheService.TTL > 0 {
+		data, err := json.Marshal(&renderedFileCache{URLPrefix: urlPrefix, File: file})
+		if err == nil {
+			err = c.Put(key, string(data), setting.CacheService.TTLSeconds())
+		}
+		if err != nil {
+			log.Warn("Unable to cache the rendered file under %s: %v", key, err)
+		}
+	}
+	return file, nil
+}
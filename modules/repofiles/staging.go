@@ -0,0 +1,273 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+// ErrStagingSessionEmpty indicates a staging session without changes is committed
+var ErrStagingSessionEmpty = errors.New("Staging session has no changes")
+
+// CommitStagingSessionOptions holds the options to commit the changes of a staging session
+type CommitStagingSessionOptions struct {
+	NewBranch string
+	Message   string
+	Author    *IdentityOptions
+	Committer *IdentityOptions
+	Dates     *CommitDateOptions
+	Signoff   bool
+	Trailers  map[string]string
+}
+
+// CommitStagingSession commits all changes of a staging session at once and deletes the session
+func CommitStagingSession(repo *models.Repository, doer *models.User, session *models.StagingSession, opts *CommitStagingSessionOptions) (*structs.FileResponse, error) {
+	if err := git.ValidateTrailers(opts.Trailers); err != nil {
+		return nil, err
+	}
+
+	if err := session.LoadChanges(); err != nil {
+		return nil, err
+	}
+	if len(session.Changes) == 0 {
+		return nil, ErrStagingSessionEmpty
+	}
+
+	oldBranch := session.Branch
+	if opts.NewBranch == "" {
+		opts.NewBranch = oldBranch
+	}
+
+	// oldBranch must exist for this operation
+	if _, err := repo_module.GetBranch(repo, oldBranch); err != nil {
+		return nil, err
+	}
+
+	if opts.NewBranch != oldBranch {
+		existingBranch, err := repo_module.GetBranch(repo, opts.NewBranch)
+		if existingBranch != nil {
+			return nil, models.ErrBranchAlreadyExists{
+				BranchName: opts.NewBranch,
+			}
+		}
+		if err != nil && !git.IsErrBranchNotExist(err) {
+			return nil, err
+		}
+	} else {
+		for _, change := range session.Changes {
+			if err := VerifyBranchProtection(repo, doer, oldBranch, change.TreePath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
+
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	if err := t.Clone(oldBranch); err != nil {
+		return nil, err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return nil, err
+	}
+
+	commit, err := t.GetBranchCommit(oldBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	// The staged files must not have been changed since the session was created
+	if commit.ID.String() != session.BaseCommitID {
+		for _, change := range session.Changes {
+			for _, treePath := range []string{change.TreePath, change.FromTreePath} {
+				if treePath == "" {
+					continue
+				}
+				if changed, err := commit.FileChangedSinceCommit(treePath, session.BaseCommitID); err != nil {
+					return nil, err
+				} else if changed {
+					return nil, models.ErrCommitIDDoesNotMatch{
+						GivenCommitID:   session.BaseCommitID,
+						CurrentCommitID: commit.ID.String(),
+					}
+				}
+			}
+		}
+	}
+
+	var filename2attribute2info map[string]map[string]string
+	if setting.LFS.StartServer {
+		filenames := make([]string, 0, len(session.Changes))
+		for _, change := range session.Changes {
+			filenames = append(filenames, change.TreePath)
+		}
+		filename2attribute2info, err = t.gitRepo.CheckAttribute(git.CheckAttributeOpts{
+			Attributes: []string{"filter"},
+			Filenames:  filenames,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sizes := make(map[string]int64, len(session.Changes))
+	contents := make(map[string][]byte, len(session.Changes))
+	var lfsMetaObjects []*models.LFSMetaObject
+	for _, change := range session.Changes {
+		if err := stageChangeInIndex(t, commit, change); err != nil {
+			return nil, err
+		}
+		if change.Operation == models.StagedChangeOperationDelete {
+			continue
+		}
+
+		content, err := base64.StdEncoding.DecodeString(change.Content)
+		if err != nil {
+			return nil, err
+		}
+		sizes[change.TreePath] = int64(len(content))
+		if filename2attribute2info[change.TreePath] != nil && filename2attribute2info[change.TreePath]["filter"] == "lfs" {
+			pointer, err := lfs.GeneratePointer(bytes.NewReader(content))
+			if err != nil {
+				return nil, err
+			}
+			lfsMetaObjects = append(lfsMetaObjects, &models.LFSMetaObject{Pointer: pointer, RepositoryID: repo.ID})
+			contents[pointer.Oid] = content
+			content = []byte(pointer.StringContent())
+		}
+
+		objectHash, err := t.HashObject(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		mode := "100644"
+		if change.Operation == models.StagedChangeOperationUpdate {
+			fromTreePath := change.FromTreePath
+			if fromTreePath == "" {
+				fromTreePath = change.TreePath
+			}
+			if entry, err := commit.GetTreeEntryByPath(fromTreePath); err == nil && entry.IsExecutable() {
+				mode = "100755"
+			}
+		}
+		if err := t.AddObjectToIndex(mode, objectHash, change.TreePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := VerifyPushRule(repo, author, sizes); err != nil {
+		return nil, err
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return nil, err
+	}
+
+	message := strings.TrimSpace(opts.Message)
+	message = git.AppendTrailers(message, opts.Trailers)
+	var commitHash string
+	if opts.Dates != nil {
+		commitHash, err = t.CommitTreeWithDate(author, committer, treeHash, message, opts.Signoff, opts.Dates.Author, opts.Dates.Committer)
+	} else {
+		commitHash, err = t.CommitTree(author, committer, treeHash, message, opts.Signoff)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contentStore := lfs.NewContentStore()
+	for _, lfsMetaObject := range lfsMetaObjects {
+		if lfsMetaObject, err = models.NewLFSMetaObject(lfsMetaObject); err != nil {
+			return nil, err
+		}
+		exist, err := contentStore.Exists(lfsMetaObject.Pointer)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			if err := contentStore.Put(lfsMetaObject.Pointer, bytes.NewReader(contents[lfsMetaObject.Oid])); err != nil {
+				if _, err2 := repo.RemoveLFSMetaObjectByOid(lfsMetaObject.Oid); err2 != nil {
+					return nil, fmt.Errorf("Error whilst removing failed inserted LFS object %s: %v (Prev Error: %v)", lfsMetaObject.Oid, err2, err)
+				}
+				return nil, err
+			}
+		}
+	}
+
+	if err := t.Push(doer, commitHash, opts.NewBranch); err != nil {
+		return nil, err
+	}
+
+	if err := models.DeleteStagingSession(session); err != nil {
+		return nil, err
+	}
+
+	commit, err = t.GetCommit(commitHash)
+	if err != nil {
+		return nil, err
+	}
+	fileCommitResponse, _ := GetFileCommitResponse(repo, commit) // ok if fails, then will be nil
+	return &structs.FileResponse{
+		Commit:       fileCommitResponse,
+		Verification: GetPayloadCommitVerification(commit),
+	}, nil
+}
+
+// stageChangeInIndex checks a staged change against the commit and removes the replaced files from the index
+func stageChangeInIndex(t *TemporaryUploadRepository, commit *git.Commit, change *models.StagedChange) error {
+	switch change.Operation {
+	case models.StagedChangeOperationCreate:
+		if _, err := commit.GetTreeEntryByPath(change.TreePath); err == nil {
+			return models.ErrRepoFileAlreadyExists{
+				Path: change.TreePath,
+			}
+		} else if !git.IsErrNotExist(err) {
+			return err
+		}
+		return nil
+	case models.StagedChangeOperationUpdate:
+		fromTreePath := change.FromTreePath
+		if fromTreePath == "" {
+			fromTreePath = change.TreePath
+		}
+		if _, err := commit.GetTreeEntryByPath(fromTreePath); err != nil {
+			return err
+		}
+		if fromTreePath != change.TreePath {
+			if _, err := commit.GetTreeEntryByPath(change.TreePath); err == nil {
+				return models.ErrRepoFileAlreadyExists{
+					Path: change.TreePath,
+				}
+			} else if !git.IsErrNotExist(err) {
+				return err
+			}
+			return t.RemoveFilesFromIndex(fromTreePath)
+		}
+		return nil
+	case models.StagedChangeOperationDelete:
+		if _, err := commit.GetTreeEntryByPath(change.TreePath); err != nil {
+			return err
+		}
+		return t.RemoveFilesFromIndex(change.TreePath)
+	default:
+		return fmt.Errorf("unknown operation %q of staged change %s", change.Operation, change.TreePath)
+	}
+}
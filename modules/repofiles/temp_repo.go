@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/services/gitdiff"
 )
@@ -156,8 +157,59 @@ func (t *TemporaryUploadRepository) AddObjectToIndex(mode, objectHash, objectPat
 	return nil
 }
 
+// ApplyPatch applies a patch to the index and returns the files whose changes do not apply
+func (t *TemporaryUploadRepository) ApplyPatch(patch io.Reader) ([]string, error) {
+	stdOut := new(bytes.Buffer)
+	stdErr := new(bytes.Buffer)
+
+	if err := git.NewCommand("apply", "--cached", "-").RunInDirFullPipeline(t.basePath, stdOut, stdErr, patch); err != nil {
+		if conflicts := parseApplyConflicts(stdErr.String()); len(conflicts) > 0 {
+			return conflicts, nil
+		}
+		log.Error("Unable to apply patch in temporary repo: %s (%s) Error: %v\nstdout: %s\nstderr: %s", t.repo.FullName(), t.basePath, err, stdOut.String(), stdErr.String())
+		return nil, fmt.Errorf("Unable to apply patch in temporary repo: %s Error: %v\nstdout: %s\nstderr: %s", t.repo.FullName(), err, stdOut.String(), stdErr.String())
+	}
+	return nil, nil
+}
+
+// parseApplyConflicts returns the files git apply reported errors for
+func parseApplyConflicts(stderr string) []string {
+	const failedPrefix = "error: patch failed:"
+	const errorPrefix = "error: "
+	errorSuffixes := []string{
+		": already exists in index",
+		": does not exist in index",
+		": patch does not apply",
+		": does not match index",
+	}
+
+	conflicts := make([]string, 0, 5)
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(stderr, "\n") {
+		var file string
+		if strings.HasPrefix(line, failedPrefix) {
+			file = strings.TrimSpace(strings.Split(line[len(failedPrefix):], ":")[0])
+		} else if strings.HasPrefix(line, errorPrefix) {
+			for _, suffix := range errorSuffixes {
+				if strings.HasSuffix(line, suffix) {
+					file = strings.TrimSpace(strings.TrimSuffix(line[len(errorPrefix):], suffix))
+					break
+				}
+			}
+		}
+		if file != "" && !seen[file] {
+			seen[file] = true
+			conflicts = append(conflicts, file)
+		}
+	}
+	return conflicts
+}
+
 // WriteTree writes the current index as a tree to the object db and returns its hash
 func (t *TemporaryUploadRepository) WriteTree() (string, error) {
+	if err := maintenance.CheckWritable(); err != nil {
+		return "", err
+	}
 	stdout, err := git.NewCommand("write-tree").RunInDir(t.basePath)
 	if err != nil {
 		log.Error("Unable to write tree in temporary repo: %s(%s): Error: %v", t.repo.FullName(), t.basePath, err)
@@ -191,6 +243,10 @@ func (t *TemporaryUploadRepository) CommitTree(author, committer *models.User, t
 
 // CommitTreeWithDate creates a commit from a given tree for the user with provided message
 func (t *TemporaryUploadRepository) CommitTreeWithDate(author, committer *models.User, treeHash string, message string, signoff bool, authorDate, committerDate time.Time) (string, error) {
+	if err := maintenance.CheckWritable(); err != nil {
+		return "", err
+	}
+
 	authorSig := author.NewGitSig()
 	committerSig := committer.NewGitSig()
 
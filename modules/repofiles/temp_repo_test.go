@@ -0,0 +1,22 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseApplyConflicts(t *testing.T) {
+	conflicts := parseApplyConflicts(`error: patch failed: README.md:1
+error: README.md: patch does not apply
+error: docs/new.md: already exists in index
+error: removed.txt: does not exist in index
+`)
+	assert.Equal(t, []string{"README.md", "docs/new.md", "removed.txt"}, conflicts)
+
+	assert.Empty(t, parseApplyConflicts("error: unrecognized input\n"))
+}
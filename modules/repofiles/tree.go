@@ -6,6 +6,9 @@ package repofiles
 
 import (
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
@@ -95,3 +98,221 @@ func GetTreeBySHA(repo *models.Repository, sha string, page, perPage int, recurs
 	}
 	return tree, nil
 }
+
+// WriteTreeEntry is an entry of a tree to write, its path may contain slashes to write it in a subtree
+type WriteTreeEntry struct {
+	Path string
+	Mode git.EntryMode
+	// SHA is the object of the entry, unless the entry is a blob with the given Content.
+	// Without both the path is removed from the base tree.
+	SHA     *string
+	Content *string
+}
+
+// WriteTreeOptions holds the options to write a tree
+type WriteTreeOptions struct {
+	// BaseTree is the tree the entries are merged into, the tree only has the entries if empty
+	BaseTree string
+	Entries  []*WriteTreeEntry
+}
+
+// writeTreeNode is a directory of the tree to write, with the entries written directly in it
+// or in its subdirectories
+type writeTreeNode struct {
+	entries  map[string]*WriteTreeEntry
+	children map[string]*writeTreeNode
+}
+
+func newWriteTreeNode() *writeTreeNode {
+	return &writeTreeNode{
+		entries:  make(map[string]*WriteTreeEntry),
+		children: make(map[string]*writeTreeNode),
+	}
+}
+
+func writeTreeEntryType(mode git.EntryMode) (git.ObjectType, bool) {
+	switch mode {
+	case git.EntryModeBlob, git.EntryModeExec, git.EntryModeSymlink:
+		return git.ObjectBlob, true
+	case git.EntryModeTree:
+		return git.ObjectTree, true
+	case git.EntryModeCommit:
+		return git.ObjectCommit, true
+	}
+	return "", false
+}
+
+// buildWriteTreeNodes groups the entries by directory, the intermediate directories of paths are created
+func buildWriteTreeNodes(entries []*WriteTreeEntry) (*writeTreeNode, error) {
+	root := newWriteTreeNode()
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Path == "" || path.Clean(entry.Path) != entry.Path || strings.HasPrefix(entry.Path, "/") ||
+			entry.Path == "." || strings.HasPrefix(entry.Path, "../") || entry.Path == ".." {
+			return nil, models.ErrFilePathInvalid{Message: fmt.Sprintf("path %q is invalid", entry.Path), Path: entry.Path}
+		}
+		if seen[entry.Path] {
+			return nil, models.ErrFilePathInvalid{Message: fmt.Sprintf("path %q is given more than once", entry.Path), Path: entry.Path}
+		}
+		seen[entry.Path] = true
+		if _, ok := writeTreeEntryType(entry.Mode); !ok {
+			return nil, models.ErrFilePathInvalid{Message: fmt.Sprintf("mode %06o of path %q is invalid", int(entry.Mode), entry.Path), Path: entry.Path, Type: entry.Mode}
+		}
+		if entry.Content != nil && (entry.SHA != nil || entry.Mode == git.EntryModeTree || entry.Mode == git.EntryModeCommit) {
+			return nil, models.ErrFilePathInvalid{Message: fmt.Sprintf("content of path %q can only be given for a blob without sha", entry.Path), Path: entry.Path}
+		}
+
+		node := root
+		parts := strings.Split(entry.Path, "/")
+		for i, name := range parts[:len(parts)-1] {
+			if name == ".git" || node.entries[name] != nil {
+				return nil, models.ErrFilePathInvalid{Message: fmt.Sprintf("path %q conflicts with %q", entry.Path, strings.Join(parts[:i+1], "/")), Path: entry.Path, Name: name}
+			}
+			child, ok := node.children[name]
+			if !ok {
+				child = newWriteTreeNode()
+				node.children[name] = child
+			}
+			node = child
+		}
+		name := parts[len(parts)-1]
+		if name == ".git" || node.children[name] != nil {
+			return nil, models.ErrFilePathInvalid{Message: fmt.Sprintf("path %q conflicts with the paths in it", entry.Path), Path: entry.Path, Name: name}
+		}
+		node.entries[name] = entry
+	}
+	return root, nil
+}
+
+// WriteTree writes a tree object of the entries merged into a base tree and returns its SHA. The paths of the
+// entries may contain slashes: the intermediate trees are merged with those of the base tree, and the trees
+// left empty by removals are removed. No commit or branch is updated.
+func WriteTree(repo *models.Repository, opts *WriteTreeOptions) (string, error) {
+	root, err := buildWriteTreeNodes(opts.Entries)
+	if err != nil {
+		return "", err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+
+	var base *git.Tree
+	if opts.BaseTree != "" {
+		if base, err = gitRepo.GetTree(opts.BaseTree); err != nil {
+			if git.IsErrNotExist(err) {
+				return "", models.ErrSHANotFound{SHA: opts.BaseTree}
+			}
+			return "", err
+		}
+	}
+
+	id, _, err := writeTreeNodes(gitRepo, base, root, true)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// writeTreeNodes writes the tree of a node merged into its base tree, which is nil if there is none.
+// It returns false without writing anything if the tree is empty, unless keepEmpty is set.
+func writeTreeNodes(gitRepo *git.Repository, base *git.Tree, node *writeTreeNode, keepEmpty bool) (git.SHA1, bool, error) {
+	entries := make(map[string]*git.MakeTreeEntry)
+	if base != nil {
+		baseEntries, err := base.ListEntries()
+		if err != nil {
+			return git.SHA1{}, false, err
+		}
+		for _, e := range baseEntries {
+			typ, _ := writeTreeEntryType(e.Mode())
+			entries[e.Name()] = &git.MakeTreeEntry{Mode: e.Mode(), Type: typ, ID: e.ID, Name: e.Name()}
+		}
+	}
+
+	for name, entry := range node.entries {
+		if entry.SHA == nil && entry.Content == nil {
+			if _, ok := entries[name]; !ok {
+				return git.SHA1{}, false, models.ErrFilePathInvalid{Message: fmt.Sprintf("path %q to remove does not exist in the base tree", entry.Path), Path: entry.Path, Name: name}
+			}
+			delete(entries, name)
+			continue
+		}
+
+		typ, _ := writeTreeEntryType(entry.Mode)
+		var id git.SHA1
+		var err error
+		if entry.Content != nil {
+			id, err = gitRepo.HashObject(strings.NewReader(*entry.Content))
+		} else {
+			id, err = resolveWriteTreeObject(gitRepo, typ, *entry.SHA)
+		}
+		if err != nil {
+			return git.SHA1{}, false, err
+		}
+		entries[name] = &git.MakeTreeEntry{Mode: entry.Mode, Type: typ, ID: id, Name: name}
+	}
+
+	for name, child := range node.children {
+		// a blob of the base tree is replaced by the tree of its path
+		var childBase *git.Tree
+		if e, ok := entries[name]; ok && e.Type == git.ObjectTree {
+			var err error
+			if childBase, err = gitRepo.GetTree(e.ID.String()); err != nil {
+				return git.SHA1{}, false, err
+			}
+		}
+		id, ok, err := writeTreeNodes(gitRepo, childBase, child, false)
+		if err != nil {
+			return git.SHA1{}, false, err
+		}
+		if !ok {
+			delete(entries, name)
+			continue
+		}
+		entries[name] = &git.MakeTreeEntry{Mode: git.EntryModeTree, Type: git.ObjectTree, ID: id, Name: name}
+	}
+
+	if len(entries) == 0 && !keepEmpty {
+		return git.SHA1{}, false, nil
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]*git.MakeTreeEntry, 0, len(names))
+	for _, name := range names {
+		list = append(list, entries[name])
+	}
+	id, err := gitRepo.MakeTree(list)
+	return id, true, err
+}
+
+// resolveWriteTreeObject checks the object of an entry exists, the commits of submodules are not checked
+// since they are not in the repository
+func resolveWriteTreeObject(gitRepo *git.Repository, typ git.ObjectType, sha string) (git.SHA1, error) {
+	switch typ {
+	case git.ObjectBlob:
+		id, err := git.NewIDFromString(sha)
+		if err != nil || !gitRepo.IsObjectExist(id.String()) {
+			return git.SHA1{}, models.ErrSHANotFound{SHA: sha}
+		}
+		return id, nil
+	case git.ObjectTree:
+		tree, err := gitRepo.GetTree(sha)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				return git.SHA1{}, models.ErrSHANotFound{SHA: sha}
+			}
+			return git.SHA1{}, err
+		}
+		return tree.ResolvedID, nil
+	}
+	id, err := git.NewIDFromString(sha)
+	if err != nil {
+		return git.SHA1{}, models.ErrSHANotFound{SHA: sha}
+	}
+	return id, nil
+}
@@ -7,7 +7,9 @@ package repofiles
 import (
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/test"
 
@@ -51,3 +53,67 @@ func TestGetTreeBySHA(t *testing.T) {
 
 	assert.EqualValues(t, expectedTree, tree)
 }
+
+func TestWriteTree(t *testing.T) {
+	db.PrepareTestEnv(t)
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	baseTree := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	content := func(s string) *string { return &s }
+
+	treePaths := func(sha string) []string {
+		tree, err := GetTreeBySHA(repo, sha, 1, 100, true)
+		assert.NoError(t, err)
+		paths := make([]string, 0, len(tree.Entries))
+		for _, e := range tree.Entries {
+			paths = append(paths, e.Path)
+		}
+		return paths
+	}
+
+	// the intermediate trees of nested paths are created and merged with the base tree
+	sha, err := WriteTree(repo, &WriteTreeOptions{
+		BaseTree: baseTree,
+		Entries: []*WriteTreeEntry{
+			{Path: "docs/guide/intro.md", Mode: git.EntryModeBlob, Content: content("intro")},
+			{Path: "docs/readme.md", Mode: git.EntryModeBlob, Content: content("docs")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "docs", "docs/guide", "docs/guide/intro.md", "docs/readme.md"}, treePaths(sha))
+
+	// the trees left empty are removed
+	sha, err = WriteTree(repo, &WriteTreeOptions{
+		BaseTree: sha,
+		Entries: []*WriteTreeEntry{
+			{Path: "docs/guide/intro.md", Mode: git.EntryModeBlob},
+			{Path: "docs/api/index.md", Mode: git.EntryModeBlob, SHA: content("4b4851ad51df6a7d9f25c979345979eaeb5b349f")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "docs", "docs/api", "docs/api/index.md", "docs/readme.md"}, treePaths(sha))
+
+	// without base tree, the tree only has the entries
+	sha, err = WriteTree(repo, &WriteTreeOptions{
+		Entries: []*WriteTreeEntry{{Path: "a/b.txt", Mode: git.EntryModeExec, Content: content("#!/bin/sh")}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "a/b.txt"}, treePaths(sha))
+
+	for _, entries := range [][]*WriteTreeEntry{
+		{{Path: "../outside", Mode: git.EntryModeBlob, Content: content("")}},
+		{{Path: "docs/", Mode: git.EntryModeBlob, Content: content("")}},
+		{{Path: "docs", Mode: git.EntryModeBlob, Content: content("")}, {Path: "docs/a.md", Mode: git.EntryModeBlob, Content: content("")}},
+		{{Path: ".git/config", Mode: git.EntryModeBlob, Content: content("")}},
+		{{Path: "missing.md", Mode: git.EntryModeBlob}},
+		{{Path: "file", Mode: git.EntryMode(0100600), Content: content("")}},
+	} {
+		_, err = WriteTree(repo, &WriteTreeOptions{BaseTree: baseTree, Entries: entries})
+		assert.True(t, models.IsErrFilePathInvalid(err), "%s", entries[0].Path)
+	}
+
+	_, err = WriteTree(repo, &WriteTreeOptions{
+		BaseTree: baseTree,
+		Entries:  []*WriteTreeEntry{{Path: "unknown.md", Mode: git.EntryModeBlob, SHA: content("0000000000000000000000000000000000000001")}},
+	})
+	assert.True(t, models.IsErrSHANotFound(err))
+}
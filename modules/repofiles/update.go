@@ -51,6 +51,7 @@ type UpdateRepoFileOptions struct {
 	Committer    *IdentityOptions
 	Dates        *CommitDateOptions
 	Signoff      bool
+	Trailers     map[string]string
 }
 
 func detectEncodingAndBOM(entry *git.TreeEntry, repo *models.Repository) (string, bool) {
@@ -122,6 +123,10 @@ func detectEncodingAndBOM(entry *git.TreeEntry, repo *models.Repository) (string
 
 // CreateOrUpdateRepoFile adds or updates a file in the given repository
 func CreateOrUpdateRepoFile(repo *models.Repository, doer *models.User, opts *UpdateRepoFileOptions) (*structs.FileResponse, error) {
+	if err := git.ValidateTrailers(opts.Trailers); err != nil {
+		return nil, err
+	}
+
 	// If no branch name is set, assume default branch
 	if opts.OldBranch == "" {
 		opts.OldBranch = repo.DefaultBranch
@@ -173,6 +178,7 @@ func CreateOrUpdateRepoFile(repo *models.Repository, doer *models.User, opts *Up
 	}
 
 	message := strings.TrimSpace(opts.Message)
+	message = git.AppendTrailers(message, opts.Trailers)
 
 	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
 
@@ -364,6 +370,10 @@ func CreateOrUpdateRepoFile(repo *models.Repository, doer *models.User, opts *Up
 			content = pointer.StringContent()
 		}
 	}
+	if err := VerifyPushRule(repo, author, map[string]int64{treePath: int64(len(content))}); err != nil {
+		return nil, err
+	}
+
 	// Add the object to the database
 	objectHash, err := t.HashObject(strings.NewReader(content))
 	if err != nil {
@@ -476,3 +486,15 @@ func VerifyBranchProtection(repo *models.Repository, doer *models.User, branchNa
 	}
 	return nil
 }
+
+// VerifyPushRule verifies the push rules of the repository for committing the given files, mapped to their size, as the author
+func VerifyPushRule(repo *models.Repository, author *models.User, files map[string]int64) error {
+	rule, err := models.GetRepoPushRule(repo.ID)
+	if err != nil {
+		return err
+	}
+	if err := rule.CheckAuthorEmail(author.Email); err != nil {
+		return err
+	}
+	return rule.CheckFiles(files)
+}
@@ -59,6 +59,7 @@ func UploadRepoFiles(repo *models.Repository, doer *models.User, opts *UploadRep
 
 	names := make([]string, len(uploads))
 	infos := make([]uploadInfo, len(uploads))
+	sizes := make(map[string]int64, len(uploads))
 	for i, upload := range uploads {
 		// Check file is not lfs locked, will return nil if lock setting not enabled
 		filepath := path.Join(opts.TreePath, upload.Name)
@@ -70,10 +71,20 @@ func UploadRepoFiles(repo *models.Repository, doer *models.User, opts *UploadRep
 			return models.ErrLFSFileLocked{RepoID: repo.ID, Path: filepath, UserName: lfsLock.Owner.Name}
 		}
 
+		info, err := os.Stat(upload.LocalPath())
+		if err != nil {
+			return err
+		}
+		sizes[filepath] = info.Size()
+
 		names[i] = upload.Name
 		infos[i] = uploadInfo{upload: upload}
 	}
 
+	if err := VerifyPushRule(repo, doer, sizes); err != nil {
+		return err
+	}
+
 	t, err := NewTemporaryUploadRepository(repo)
 	if err != nil {
 		return err
@@ -31,8 +31,8 @@ func GetBranches(repo *models.Repository, skip, limit int) ([]*git.Branch, int,
 	return git.GetBranchesByPath(repo.RepoPath(), skip, limit)
 }
 
-// checkBranchName validates branch name with existing repository branches
-func checkBranchName(repo *models.Repository, name string) error {
+// CheckBranchName validates branch name with existing repository branches
+func CheckBranchName(repo *models.Repository, name string) error {
 	gitRepo, err := git.OpenRepository(repo.RepoPath())
 	if err != nil {
 		return err
@@ -69,7 +69,7 @@ func checkBranchName(repo *models.Repository, name string) error {
 // CreateNewBranch creates a new repository branch
 func CreateNewBranch(doer *models.User, repo *models.Repository, oldBranchName, branchName string) (err error) {
 	// Check if branch name can be used
-	if err := checkBranchName(repo, branchName); err != nil {
+	if err := CheckBranchName(repo, branchName); err != nil {
 		return err
 	}
 
@@ -96,7 +96,7 @@ func CreateNewBranch(doer *models.User, repo *models.Repository, oldBranchName,
 // CreateNewBranchFromCommit creates a new repository branch
 func CreateNewBranchFromCommit(doer *models.User, repo *models.Repository, commit, branchName string) (err error) {
 	// Check if branch name can be used
-	if err := checkBranchName(repo, branchName); err != nil {
+	if err := CheckBranchName(repo, branchName); err != nil {
 		return err
 	}
 
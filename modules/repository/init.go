@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -65,20 +66,13 @@ func prepareRepoCommit(ctx *db.Context, repo *models.Repository, tmpDir, repoPat
 
 	// .gitignore
 	if len(opts.Gitignores) > 0 {
-		var buf bytes.Buffer
-		names := strings.Split(opts.Gitignores, ",")
-		for _, name := range names {
-			data, err = models.GetRepoInitFile("gitignore", name)
-			if err != nil {
-				return fmt.Errorf("GetRepoInitFile[%s]: %v", name, err)
-			}
-			buf.WriteString("# ---> " + name + "\n")
-			buf.Write(data)
-			buf.WriteString("\n")
+		data, err = GenerateGitignore(strings.Split(opts.Gitignores, ","))
+		if err != nil {
+			return err
 		}
 
-		if buf.Len() > 0 {
-			if err = os.WriteFile(filepath.Join(tmpDir, ".gitignore"), buf.Bytes(), 0644); err != nil {
+		if len(data) > 0 {
+			if err = os.WriteFile(filepath.Join(tmpDir, ".gitignore"), data, 0644); err != nil {
 				return fmt.Errorf("write .gitignore: %v", err)
 			}
 		}
@@ -99,6 +93,64 @@ func prepareRepoCommit(ctx *db.Context, repo *models.Repository, tmpDir, repoPat
 	return nil
 }
 
+// licenseYearPlaceholders are the placeholders of the year in the license templates
+var licenseYearPlaceholders = []string{"<year>", "[yyyy]", "[year]"}
+
+// licenseHolderPlaceholders are the placeholders of the copyright holder in the license templates
+var licenseHolderPlaceholders = []string{"<copyright holders>", "<owner>", "<name of author>", "[name of copyright owner]", "[fullname]"}
+
+// GenerateLicense returns the content of a license template with the year and the copyright holder substituted
+func GenerateLicense(name string, year int, holder string) ([]byte, error) {
+	data, err := models.GetRepoInitFile("license", name)
+	if err != nil {
+		return nil, fmt.Errorf("GetRepoInitFile[%s]: %v", name, err)
+	}
+
+	oldnew := make([]string, 0, 2*(len(licenseYearPlaceholders)+len(licenseHolderPlaceholders)))
+	for _, placeholder := range licenseYearPlaceholders {
+		oldnew = append(oldnew, placeholder, strconv.Itoa(year))
+	}
+	for _, placeholder := range licenseHolderPlaceholders {
+		oldnew = append(oldnew, placeholder, holder)
+	}
+	return []byte(strings.NewReplacer(oldnew...).Replace(string(data))), nil
+}
+
+// GetLicenseTitle returns the first line of a license template if it names the license, otherwise the name of the template
+func GetLicenseTitle(name string) (string, error) {
+	data, err := models.GetRepoInitFile("license", name)
+	if err != nil {
+		return "", fmt.Errorf("GetRepoInitFile[%s]: %v", name, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "Copyright") || len(line) > 100 {
+			break
+		}
+		return strings.TrimSuffix(line, ":"), nil
+	}
+	return name, nil
+}
+
+// GenerateGitignore concatenates gitignore templates to the content of a .gitignore file
+func GenerateGitignore(names []string) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, name := range names {
+		data, err := models.GetRepoInitFile("gitignore", name)
+		if err != nil {
+			return nil, fmt.Errorf("GetRepoInitFile[%s]: %v", name, err)
+		}
+		buf.WriteString("# ---> " + name + "\n")
+		buf.Write(data)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
 // initRepoCommit temporarily changes with work directory.
 func initRepoCommit(tmpPath string, repo *models.Repository, u *models.User, defaultBranch string) (err error) {
 	commitTimeStr := time.Now().Format(time.RFC3339)
@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateLicense(t *testing.T) {
+	data, err := GenerateLicense("MIT", 2021, "Gitea Authors")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "MIT License\n\nCopyright (c) 2021 Gitea Authors\n"))
+
+	data, err = GenerateLicense("BSD-3-Clause", 2020, "Gitea Authors")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "Copyright (c) 2020 Gitea Authors. All rights reserved.\n"))
+
+	_, err = GenerateLicense("does-not-exist", 2021, "Gitea Authors")
+	assert.Error(t, err)
+}
+
+func TestGetLicenseTitle(t *testing.T) {
+	title, err := GetLicenseTitle("MIT")
+	assert.NoError(t, err)
+	assert.Equal(t, "MIT License", title)
+
+	title, err = GetLicenseTitle("ISC")
+	assert.NoError(t, err)
+	assert.Equal(t, "ISC License", title)
+
+	// the template starts with the copyright notice
+	title, err = GetLicenseTitle("BSD-3-Clause")
+	assert.NoError(t, err)
+	assert.Equal(t, "BSD-3-Clause", title)
+}
+
+func TestGenerateGitignore(t *testing.T) {
+	data, err := GenerateGitignore([]string{"Go", "Python"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# ---> Go\n# Binaries for programs and plugins\n"))
+	assert.Contains(t, string(data), "\n# ---> Python\n")
+}
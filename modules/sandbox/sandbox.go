@@ -0,0 +1,135 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var (
+	// ErrNoSandbox is returned if a command should run without a sandbox command
+	ErrNoSandbox = errors.New("no sandbox command is configured")
+	// ErrTimedOut is returned if a command was killed because it exceeded its timeout
+	ErrTimedOut = errors.New("command timed out")
+)
+
+// Dir is an empty temporary directory commands are run in a sandbox in
+type Dir struct {
+	path    string
+	sandbox []string
+}
+
+// NewDir creates a temporary directory to run commands in the sandbox command in
+func NewDir(sandboxCommand, prefix string) (*Dir, error) {
+	sandbox := strings.Fields(sandboxCommand)
+	if len(sandbox) == 0 {
+		return nil, ErrNoSandbox
+	}
+	path, err := os.MkdirTemp("", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary directory: %v", err)
+	}
+	return &Dir{path: path, sandbox: sandbox}, nil
+}
+
+// Path returns the path of the directory
+func (d *Dir) Path() string {
+	return d.path
+}
+
+// Close removes the directory
+func (d *Dir) Close() {
+	if err := util.RemoveAll(d.path); err != nil {
+		log.Warn("Unable to remove temporary directory: %s: Error: %v", d.path, err)
+	}
+}
+
+// RunOptions represents the options of a command run in a sandbox
+type RunOptions struct {
+	// Args is the command line run in the sandbox command
+	Args []string
+	// Description describes the command in the process manager
+	Description string
+	// Timeout is the time after which the command is killed
+	Timeout time.Duration
+	// Env is the environment of the command besides PATH, and HOME and TMPDIR which point to the directory
+	Env    []string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Run runs a command in the sandbox command in the directory, without the environment of Gitea.
+// It returns ErrTimedOut if the command exceeded its timeout, or the error of exec.Cmd.Run.
+func (d *Dir) Run(ctx context.Context, opts *RunOptions) error {
+	args := append(append([]string{}, d.sandbox...), opts.Args...)
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	pid := process.GetManager().Add(opts.Description, cancel)
+	defer process.GetManager().Remove(pid)
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = d.path
+	cmd.Env = append([]string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + d.path,
+		"TMPDIR=" + d.path,
+	}, opts.Env...)
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return ErrTimedOut
+	}
+	return err
+}
+
+// LimitedBuffer keeps the first Limit bytes written to it and discards the rest
+type LimitedBuffer struct {
+	Limit int64
+	// OnExceed is called once when the limit is exceeded, e.g. to stop the command writing to the buffer
+	OnExceed func()
+
+	buf      strings.Builder
+	exceeded bool
+}
+
+func (b *LimitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.Limit - int64(b.buf.Len()); remaining < int64(len(p)) {
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		if !b.exceeded && b.OnExceed != nil {
+			b.OnExceed()
+		}
+		b.exceeded = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// Exceeded returns whether more than Limit bytes were written to the buffer
+func (b *LimitedBuffer) Exceeded() bool {
+	return b.exceeded
+}
+
+// String returns the bytes kept by the buffer
+func (b *LimitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sandbox
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDir(t *testing.T) {
+	_, err := NewDir(" ", "gitea-sandbox-test")
+	assert.Equal(t, ErrNoSandbox, err)
+
+	// env stands in for a real sandbox, it runs the command it is given
+	dir, err := NewDir("env", "gitea-sandbox-test")
+	assert.NoError(t, err)
+
+	var stdout strings.Builder
+	err = dir.Run(context.Background(), &RunOptions{
+		Args:    []string{"sh", "-c", "echo $HOME $FOO $GITEA_WORK_DIR; cat"},
+		Timeout: 10 * time.Second,
+		Env:     []string{"FOO=bar"},
+		Stdin:   strings.NewReader("input"),
+		Stdout:  &stdout,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, dir.Path()+" bar\ninput", stdout.String())
+
+	err = dir.Run(context.Background(), &RunOptions{
+		Args:    []string{"sleep", "5"},
+		Timeout: 100 * time.Millisecond,
+	})
+	assert.Equal(t, ErrTimedOut, err)
+
+	dir.Close()
+	_, err = os.Stat(dir.Path())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	exceeded := 0
+	buf := &LimitedBuffer{Limit: 5, OnExceed: func() { exceeded++ }}
+	_, _ = buf.Write([]byte("abc"))
+	assert.False(t, buf.Exceeded())
+	_, _ = buf.Write([]byte("def"))
+	_, _ = buf.Write([]byte("ghi"))
+	assert.True(t, buf.Exceeded())
+	assert.Equal(t, 1, exceeded)
+	assert.Equal(t, "abcde", buf.String())
+}
@@ -99,8 +99,22 @@ func (o *VirtualSessionProvider) GC() {
 	o.provider.GC()
 }
 
+var virtualSessionProvider = &VirtualSessionProvider{}
+
 func init() {
-	session.Register("VirtualSession", &VirtualSessionProvider{})
+	session.Register("VirtualSession", virtualSessionProvider)
+}
+
+// CountSessions returns the number of sessions stored by the configured provider,
+// -1 if the provider is not initialized or can not count its sessions
+func CountSessions() int {
+	virtualSessionProvider.lock.RLock()
+	provider := virtualSessionProvider.provider
+	virtualSessionProvider.lock.RUnlock()
+	if provider == nil {
+		return -1
+	}
+	return provider.Count()
 }
 
 // VirtualStore represents a virtual session store implementation.
@@ -0,0 +1,40 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Advisories settings
+var (
+	Advisories = struct {
+		Enabled bool
+		// OSVURL is the base URL of the OSV data dump, it serves an all.zip archive for each ecosystem
+		OSVURL string `ini:"OSV_URL"`
+		// Ecosystems are the package ecosystems of dependencies whose advisories are synchronized
+		Ecosystems []string `delim:","`
+		Timeout    time.Duration
+	}{
+		Enabled:    false,
+		OSVURL:     "https://osv-vulnerabilities.storage.googleapis.com",
+		Ecosystems: []string{"go", "npm", "pypi", "composer"},
+		Timeout:    10 * time.Minute,
+	}
+)
+
+func newAdvisories() {
+	sec := Cfg.Section("advisories")
+	if err := sec.MapTo(&Advisories); err != nil {
+		log.Fatal("Failed to map Advisories settings: %v", err)
+	}
+	Advisories.OSVURL = strings.TrimSuffix(Advisories.OSVURL, "/")
+	for i, ecosystem := range Advisories.Ecosystems {
+		Advisories.Ecosystems[i] = strings.ToLower(strings.TrimSpace(ecosystem))
+	}
+}
@@ -4,6 +4,13 @@
 
 package setting
 
+import (
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
 var (
 	// Attachment settings
 	Attachment = struct {
@@ -12,6 +19,16 @@ var (
 		MaxSize      int64
 		MaxFiles     int
 		Enabled      bool
+
+		// ThumbnailSizes are the sizes of the thumbnails of image attachments which may be requested
+		ThumbnailSizes []int
+		// ThumbnailMaxSourcePixels is the maximum number of pixels of the images thumbnails are generated for
+		ThumbnailMaxSourcePixels int
+
+		// VideoMaxSize is the maximum size of video attachments in MB, which replaces MaxSize for them
+		VideoMaxSize int64
+		// VideoTranscodeCommand is run for each uploaded video with the video on stdin, its output is stored instead
+		VideoTranscodeCommand string
 	}{
 		Storage: Storage{
 			ServeDirect: false,
@@ -20,6 +37,11 @@ var (
 		MaxSize:      4,
 		MaxFiles:     5,
 		Enabled:      true,
+
+		ThumbnailSizes:           []int{128, 256, 512},
+		ThumbnailMaxSourcePixels: 50000000,
+
+		VideoMaxSize: 32,
 	}
 )
 
@@ -29,8 +51,21 @@ func newAttachmentService() {
 
 	Attachment.Storage = getStorage("attachments", storageType, sec)
 
-	Attachment.AllowedTypes = sec.Key("ALLOWED_TYPES").MustString(".docx,.gif,.gz,.jpeg,.jpg,.log,.pdf,.png,.pptx,.txt,.xlsx,.zip")
+	Attachment.AllowedTypes = sec.Key("ALLOWED_TYPES").MustString(".docx,.gif,.gz,.jpeg,.jpg,.log,.mp4,.pdf,.png,.pptx,.txt,.webm,.xlsx,.zip")
 	Attachment.MaxSize = sec.Key("MAX_SIZE").MustInt64(4)
 	Attachment.MaxFiles = sec.Key("MAX_FILES").MustInt(5)
 	Attachment.Enabled = sec.Key("ENABLED").MustBool(true)
+
+	Attachment.ThumbnailSizes = nil
+	for _, size := range strings.Split(sec.Key("THUMBNAIL_SIZES").MustString("128,256,512"), ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil || n <= 0 {
+			log.Fatal("Invalid thumbnail size %q in [attachment] THUMBNAIL_SIZES", size)
+		}
+		Attachment.ThumbnailSizes = append(Attachment.ThumbnailSizes, n)
+	}
+	Attachment.ThumbnailMaxSourcePixels = sec.Key("THUMBNAIL_MAX_SOURCE_PIXELS").MustInt(50000000)
+
+	Attachment.VideoMaxSize = sec.Key("VIDEO_MAX_SIZE").MustInt64(32)
+	Attachment.VideoTranscodeCommand = strings.TrimSpace(sec.Key("VIDEO_TRANSCODE_COMMAND").MustString(""))
 }
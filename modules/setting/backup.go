@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path"
+	"path/filepath"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Backup settings
+var (
+	Backup = struct {
+		Storage
+		// TempPath is where backups are assembled before they are archived into the storage
+		TempPath string
+	}{}
+)
+
+func newBackupService() {
+	sec := Cfg.Section("backup")
+	if err := sec.MapTo(&Backup); err != nil {
+		log.Fatal("Failed to map Backup settings: %v", err)
+	}
+
+	storageType := sec.Key("STORAGE_TYPE").MustString("")
+	Backup.Storage = getStorage("backups", storageType, sec)
+
+	Backup.TempPath = filepath.ToSlash(sec.Key("TEMP_PATH").MustString("tmp/backup"))
+	if !filepath.IsAbs(Backup.TempPath) {
+		Backup.TempPath = filepath.ToSlash(path.Join(AppDataPath, Backup.TempPath))
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"fmt"
+	"net/url"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+var (
+	// Chat notification settings
+	Chat = struct {
+		Enabled        bool
+		QueueLength    int
+		DeliverTimeout int
+
+		// XMPP messages are sent by an external component (XEP-0114) of an XMPP server
+		XMPP struct {
+			Server          string
+			ComponentDomain string
+			Secret          string
+		} `ini:"-"`
+
+		IRC struct {
+			Server   string
+			UseTLS   bool `ini:"USE_TLS"`
+			Nick     string
+			Password string
+		} `ini:"-"`
+
+		Matrix struct {
+			HomeserverURL string `ini:"HOMESERVER_URL"`
+			AccessToken   string
+		} `ini:"-"`
+	}{
+		Enabled:        false,
+		QueueLength:    1000,
+		DeliverTimeout: 10,
+	}
+)
+
+func newChatService() {
+	if err := Cfg.Section("chat").MapTo(&Chat); err != nil {
+		log.Fatal("Failed to map Chat settings: %v", err)
+	}
+	if !Chat.Enabled {
+		return
+	}
+	if err := Cfg.Section("chat.xmpp").MapTo(&Chat.XMPP); err != nil {
+		log.Fatal("Failed to map Chat XMPP settings: %v", err)
+	}
+	if err := Cfg.Section("chat.irc").MapTo(&Chat.IRC); err != nil {
+		log.Fatal("Failed to map Chat IRC settings: %v", err)
+	}
+	if err := Cfg.Section("chat.matrix").MapTo(&Chat.Matrix); err != nil {
+		log.Fatal("Failed to map Chat Matrix settings: %v", err)
+	}
+
+	if Chat.IRC.Server != "" && Chat.IRC.Nick == "" {
+		Chat.IRC.Nick = "gitea"
+	}
+	if Chat.Matrix.HomeserverURL != "" {
+		if _, err := url.Parse(Chat.Matrix.HomeserverURL); err != nil {
+			log.Error("Chat Matrix HOMESERVER_URL is not valid: %v", err)
+			Chat.Matrix.HomeserverURL = ""
+		}
+	}
+
+	// QUEUE_LENGTH is the default length of the chat queue
+	section := Cfg.Section("queue.chat")
+	if !section.HasKey("LENGTH") {
+		_, _ = section.NewKey("LENGTH", fmt.Sprintf("%d", Chat.QueueLength))
+	}
+
+	log.Info("Chat Notification Service Enabled")
+}
@@ -46,9 +46,12 @@ var (
 		MaxOpenConns      int
 		ConnMaxLifetime   time.Duration
 		IterateBufferSize int
+		// ReadOnlyRetryAfter is how long writes are rejected after the database was found to be read-only, e.g. during a failover
+		ReadOnlyRetryAfter time.Duration
 	}{
-		Timeout:           500,
-		IterateBufferSize: 50,
+		Timeout:            500,
+		IterateBufferSize:  50,
+		ReadOnlyRetryAfter: 30 * time.Second,
 	}
 )
 
@@ -101,6 +104,7 @@ func InitDBConfig() {
 	Database.LogSQL = sec.Key("LOG_SQL").MustBool(true)
 	Database.DBConnectRetries = sec.Key("DB_RETRIES").MustInt(10)
 	Database.DBConnectBackoff = sec.Key("DB_RETRY_BACKOFF").MustDuration(3 * time.Second)
+	Database.ReadOnlyRetryAfter = sec.Key("READ_ONLY_RETRY_AFTER").MustDuration(30 * time.Second)
 }
 
 // DBConnStr returns database connection string
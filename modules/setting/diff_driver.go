@@ -0,0 +1,66 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// DiffDriver converts the files with the git attribute diff=<Name> to text, which is shown in diffs instead of their content
+type DiffDriver struct {
+	Name string
+	// Textconv converts the file on its stdin to text on its stdout
+	Textconv string
+	// Timeout is the time after which a running conversion is killed
+	Timeout time.Duration
+	// MaxFileSize limits the size of the converted files and of their text
+	MaxFileSize int64
+}
+
+// Diff driver settings
+var (
+	// DiffDriverSandboxCommand wraps the textconv commands, diff drivers are only used with one
+	DiffDriverSandboxCommand string
+	// DiffDrivers are the diff drivers configured in ini by their names
+	DiffDrivers = map[string]*DiffDriver{}
+)
+
+func newDiffDrivers() {
+	sec := Cfg.Section("diff_driver")
+	DiffDriverSandboxCommand = strings.TrimSpace(sec.Key("SANDBOX_COMMAND").String())
+	DiffDrivers = make(map[string]*DiffDriver)
+
+	for _, sec := range sec.ChildSections() {
+		name := strings.TrimPrefix(sec.Name(), "diff_driver.")
+		if name == "" {
+			log.Warn("name is empty, diff driver " + sec.Name() + " ignored")
+			continue
+		}
+		if !sec.Key("ENABLED").MustBool(true) {
+			continue
+		}
+
+		textconv := strings.TrimSpace(sec.Key("TEXTCONV").String())
+		if textconv == "" {
+			log.Warn("TEXTCONV is empty, diff driver " + name + " ignored")
+			continue
+		}
+
+		DiffDrivers[name] = &DiffDriver{
+			Name:        name,
+			Textconv:    textconv,
+			Timeout:     sec.Key("TIMEOUT").MustDuration(10 * time.Second),
+			MaxFileSize: sec.Key("MAX_FILE_SIZE").MustInt64(10 * 1024 * 1024),
+		}
+	}
+
+	if len(DiffDrivers) > 0 && DiffDriverSandboxCommand == "" {
+		log.Error("[diff_driver] SANDBOX_COMMAND is required to convert files with diff drivers, %d diff drivers ignored", len(DiffDrivers))
+		DiffDrivers = map[string]*DiffDriver{}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// EventStream settings
+var (
+	EventStream = struct {
+		Enabled bool
+		// MaxConnectionsPerUser limits the concurrent streams of a single user
+		MaxConnectionsPerUser int
+		// EventsPerSecond and Burst limit the rate at which events are delivered to a single stream
+		EventsPerSecond float64
+		Burst           int
+		// HistorySize is the number of recent events kept in memory to resume streams from
+		HistorySize  int
+		PingInterval time.Duration
+	}{
+		Enabled:               false,
+		MaxConnectionsPerUser: 5,
+		EventsPerSecond:       10,
+		Burst:                 50,
+		HistorySize:           1000,
+		PingInterval:          30 * time.Second,
+	}
+)
+
+func newEventStream() {
+	if err := Cfg.Section("event_stream").MapTo(&EventStream); err != nil {
+		log.Fatal("Failed to map EventStream settings: %v", err)
+	}
+	if EventStream.EventsPerSecond <= 0 {
+		EventStream.EventsPerSecond = 10
+	}
+	if EventStream.Burst < 1 {
+		EventStream.Burst = 1
+	}
+	if EventStream.HistorySize < 1 {
+		EventStream.HistorySize = 1
+	}
+	if EventStream.PingInterval <= 0 {
+		EventStream.PingInterval = 30 * time.Second
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Federation settings
+var (
+	Federation = struct {
+		Enabled bool
+		// MaxSize limits the size of the activities received by an inbox
+		MaxSize int64
+		// DeliverTimeout is the timeout of fetching remote actors and delivering activities
+		DeliverTimeout time.Duration
+		// BlockedDomains are the domains no activities are accepted from or delivered to
+		BlockedDomains []string `delim:","`
+		// AllowLocalNetworks allows fetching from and delivering to hosts in local networks
+		AllowLocalNetworks bool `ini:"ALLOW_LOCALNETWORKS"`
+	}{
+		Enabled:        false,
+		MaxSize:        4 * 1024 * 1024,
+		DeliverTimeout: 10 * time.Second,
+	}
+)
+
+func newFederation() {
+	if err := Cfg.Section("federation").MapTo(&Federation); err != nil {
+		log.Fatal("Failed to map Federation settings: %v", err)
+	}
+	for i, domain := range Federation.BlockedDomains {
+		Federation.BlockedDomains[i] = strings.ToLower(strings.TrimSpace(domain))
+	}
+}
+
+// IsFederationDomainBlocked returns whether a domain or one of its parent domains is blocked from federation
+func IsFederationDomainBlocked(domain string) bool {
+	domain = strings.ToLower(domain)
+	for _, blocked := range Federation.BlockedDomains {
+		if blocked != "" && (domain == blocked || strings.HasSuffix(domain, "."+blocked)) {
+			return true
+		}
+	}
+	return false
+}
@@ -26,6 +26,7 @@ var (
 		EnableAutoGitWireProtocol bool
 		PullRequestPushMessage    bool
 		LargeObjectThreshold      int64
+		SlowCommandThreshold      time.Duration
 		Timeout                   struct {
 			Default int
 			Migrate int
@@ -47,6 +48,7 @@ var (
 		EnableAutoGitWireProtocol: true,
 		PullRequestPushMessage:    true,
 		LargeObjectThreshold:      1024 * 1024,
+		SlowCommandThreshold:      10 * time.Second,
 		Timeout: struct {
 			Default int
 			Migrate int
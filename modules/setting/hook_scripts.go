@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// HookScripts settings
+var (
+	HookScripts = struct {
+		Enabled bool
+		// SandboxCommand is prepended to the command line of every script, e.g. to run it in bubblewrap or firejail.
+		// Hook scripts cannot be enabled without it.
+		SandboxCommand string
+		// Timeout is the time after which a running script is killed
+		Timeout time.Duration
+		// MaxOutputSize limits the output of a script returned to the pusher
+		MaxOutputSize int64
+	}{
+		Enabled:       false,
+		Timeout:       30 * time.Second,
+		MaxOutputSize: 64 * 1024,
+	}
+)
+
+func newHookScripts() {
+	if err := Cfg.Section("hook_scripts").MapTo(&HookScripts); err != nil {
+		log.Fatal("Failed to map HookScripts settings: %v", err)
+	}
+	HookScripts.SandboxCommand = strings.TrimSpace(HookScripts.SandboxCommand)
+	if HookScripts.Enabled && HookScripts.SandboxCommand == "" {
+		log.Error("[hook_scripts] SANDBOX_COMMAND is required to run hook scripts, hook scripts are disabled")
+		HookScripts.Enabled = false
+	}
+}
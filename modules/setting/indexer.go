@@ -44,6 +44,10 @@ var (
 		IncludePatterns    []glob.Glob
 		ExcludePatterns    []glob.Glob
 		ExcludeVendored    bool
+
+		SymbolIndexerEnabled     bool
+		SymbolIndexerAllBranches bool
+		CtagsPath                string
 	}{
 		IssueType:        "bleve",
 		IssuePath:        "indexers/issues.bleve",
@@ -58,6 +62,9 @@ var (
 		RepoIndexerName:    "gitea_codes",
 		MaxIndexerFileSize: 1024 * 1024,
 		ExcludeVendored:    true,
+
+		SymbolIndexerEnabled: false,
+		CtagsPath:            "ctags",
 	}
 )
 
@@ -92,6 +99,10 @@ func newIndexerService() {
 	Indexer.ExcludeVendored = sec.Key("REPO_INDEXER_EXCLUDE_VENDORED").MustBool(true)
 	Indexer.MaxIndexerFileSize = sec.Key("MAX_FILE_SIZE").MustInt64(1024 * 1024)
 	Indexer.StartupTimeout = sec.Key("STARTUP_TIMEOUT").MustDuration(30 * time.Second)
+
+	Indexer.SymbolIndexerEnabled = sec.Key("SYMBOL_INDEXER_ENABLED").MustBool(false)
+	Indexer.SymbolIndexerAllBranches = sec.Key("SYMBOL_INDEXER_ALL_BRANCHES").MustBool(false)
+	Indexer.CtagsPath = sec.Key("CTAGS_PATH").MustString("ctags")
 }
 
 // IndexerGlobFromString parses a comma separated list of patterns and returns a glob.Glob slice suited for repo indexing
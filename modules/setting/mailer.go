@@ -6,11 +6,13 @@ package setting
 
 import (
 	"net/mail"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/modules/log"
 
 	shellquote "github.com/kballard/go-shellquote"
+	ini "gopkg.in/ini.v1"
 )
 
 // Mailer represents mail service.
@@ -39,6 +41,25 @@ type Mailer struct {
 	SendmailPath    string
 	SendmailArgs    []string
 	SendmailTimeout time.Duration
+
+	// DKIM signing keys by sending domain
+	DKIM map[string]*MailerDKIM
+}
+
+// MailerDKIM represents the DKIM signing configuration of a sending domain
+type MailerDKIM struct {
+	Domain                 string
+	Selector               string
+	PrivateKeyFile         string
+	HeaderCanonicalization string
+	BodyCanonicalization   string
+	SignedHeaders          []string
+}
+
+// DefaultDKIMSignedHeaders are the headers signed when a DKIM domain does not configure SIGNED_HEADERS
+var DefaultDKIMSignedHeaders = []string{
+	"From", "To", "Cc", "Subject", "Date", "Message-ID", "In-Reply-To", "References",
+	"MIME-Version", "Content-Type", "List-ID", "List-Unsubscribe", "List-Unsubscribe-Post",
 }
 
 var (
@@ -106,9 +127,51 @@ func newMailService() {
 		}
 	}
 
+	newMailerDKIM(sec)
+
 	log.Info("Mail Service Enabled")
 }
 
+func newMailerDKIM(sec *ini.Section) {
+	MailService.DKIM = make(map[string]*MailerDKIM)
+	for _, child := range sec.ChildSections() {
+		if !strings.HasPrefix(child.Name(), "mailer.dkim.") {
+			continue
+		}
+		domain := strings.ToLower(strings.TrimPrefix(child.Name(), "mailer.dkim."))
+		if domain == "" {
+			log.Warn("domain is empty, DKIM configuration " + child.Name() + " ignored")
+			continue
+		}
+
+		dkim := &MailerDKIM{
+			Domain:         domain,
+			Selector:       child.Key("SELECTOR").String(),
+			PrivateKeyFile: child.Key("PRIVATE_KEY_FILE").String(),
+			SignedHeaders:  child.Key("SIGNED_HEADERS").Strings(","),
+		}
+		if dkim.Selector == "" || dkim.PrivateKeyFile == "" {
+			log.Error("DKIM configuration %s requires SELECTOR and PRIVATE_KEY_FILE, ignored", child.Name())
+			continue
+		}
+		if len(dkim.SignedHeaders) == 0 {
+			dkim.SignedHeaders = DefaultDKIMSignedHeaders
+		}
+
+		canonicalization := strings.SplitN(child.Key("CANONICALIZATION").In("relaxed/relaxed",
+			[]string{"relaxed/relaxed", "relaxed/simple", "simple/relaxed", "simple/simple", "relaxed", "simple"}), "/", 2)
+		dkim.HeaderCanonicalization = canonicalization[0]
+		// as per RFC 6376 a missing body canonicalization algorithm defaults to "simple"
+		dkim.BodyCanonicalization = "simple"
+		if len(canonicalization) == 2 {
+			dkim.BodyCanonicalization = canonicalization[1]
+		}
+
+		MailService.DKIM[domain] = dkim
+		log.Info("DKIM signing enabled for %s", domain)
+	}
+}
+
 func newRegisterMailService() {
 	if !Cfg.Section("service").Key("REGISTER_EMAIL_CONFIRM").MustBool() {
 		return
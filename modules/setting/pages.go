@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Pages settings
+var (
+	Pages = struct {
+		Enabled bool
+		// Domain is the domain under which the site of a repository is served at {owner}.{Domain}/{repo}/
+		Domain string
+		// AllowCustomDomains allows repositories to serve their site at a domain of their own
+		AllowCustomDomains bool
+	}{
+		Enabled:            false,
+		AllowCustomDomains: true,
+	}
+)
+
+func newPages() {
+	sec := Cfg.Section("pages")
+	if err := sec.MapTo(&Pages); err != nil {
+		log.Fatal("Failed to map Pages settings: %v", err)
+	}
+	Pages.Domain = strings.ToLower(strings.Trim(Pages.Domain, "."))
+	if Pages.Enabled && Pages.Domain == "" {
+		log.Fatal("[pages] DOMAIN must be set if pages are enabled")
+	}
+	if domain := strings.ToLower(Domain); Pages.Enabled && (Pages.Domain == domain || strings.HasSuffix(Pages.Domain, "."+domain)) {
+		log.Fatal("[pages] DOMAIN must differ from [server] DOMAIN and its subdomains, so pages cannot read the cookies of Gitea")
+	}
+}
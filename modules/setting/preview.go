@@ -0,0 +1,38 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Preview settings
+var (
+	Preview = struct {
+		Enabled bool
+		// MaxFileSize is the size in bytes of the largest file previews are generated for
+		MaxFileSize int64
+		// PDFCommand renders the first page of the PDF on its stdin to a PNG image on its stdout,
+		// PDFs are not previewed if it is empty
+		PDFCommand string `ini:"PDF_COMMAND"`
+		// Timeout is the time the generation of a preview may take
+		Timeout time.Duration
+	}{
+		Enabled:     true,
+		MaxFileSize: 32 * 1024 * 1024,
+		Timeout:     time.Minute,
+	}
+)
+
+func newPreview() {
+	sec := Cfg.Section("preview")
+	if err := sec.MapTo(&Preview); err != nil {
+		log.Fatal("Failed to map Preview settings: %v", err)
+	}
+	Preview.PDFCommand = strings.TrimSpace(Preview.PDFCommand)
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+
+	"code.gitea.io/gitea/modules/generate"
+	"code.gitea.io/gitea/modules/log"
+
+	ini "gopkg.in/ini.v1"
+)
+
+var (
+	// Push notification settings
+	Push = struct {
+		Enabled         bool
+		QueueLength     int
+		DeliverTimeout  int
+		VapidPublicKey  string `ini:"VAPID_PUBLIC_KEY"`
+		VapidPrivateKey string `ini:"VAPID_PRIVATE_KEY"`
+		VapidSubject    string `ini:"VAPID_SUBJECT"`
+		GatewayURL      string `ini:"GATEWAY_URL"`
+		GatewayToken    string `ini:"GATEWAY_TOKEN"`
+	}{
+		Enabled:        false,
+		QueueLength:    1000,
+		DeliverTimeout: 5,
+	}
+)
+
+func newPushService() {
+	sec := Cfg.Section("push")
+	if err := sec.MapTo(&Push); err != nil {
+		log.Fatal("Failed to map Push settings: %v", err)
+	}
+	if !Push.Enabled {
+		return
+	}
+
+	// QUEUE_LENGTH is the default length of the push queue
+	section := Cfg.Section("queue.push")
+	if !section.HasKey("LENGTH") {
+		_, _ = section.NewKey("LENGTH", fmt.Sprintf("%d", Push.QueueLength))
+	}
+
+	if Push.VapidSubject == "" {
+		Push.VapidSubject = AppURL
+	}
+
+	if Push.GatewayURL != "" {
+		if _, err := url.Parse(Push.GatewayURL); err != nil {
+			log.Error("Push GATEWAY_URL is not valid: %v", err)
+			Push.GatewayURL = ""
+		}
+	}
+
+	if !isValidVapidKeyPair(Push.VapidPublicKey, Push.VapidPrivateKey) {
+		var err error
+		Push.VapidPublicKey, Push.VapidPrivateKey, err = generate.NewVapidKeys()
+		if err != nil {
+			log.Fatal("Error generating VAPID keys for custom config: %v", err)
+			return
+		}
+
+		// Save keys
+		CreateOrAppendToCustomConf(func(cfg *ini.File) {
+			cfg.Section("push").Key("VAPID_PUBLIC_KEY").SetValue(Push.VapidPublicKey)
+			cfg.Section("push").Key("VAPID_PRIVATE_KEY").SetValue(Push.VapidPrivateKey)
+		})
+	}
+	log.Info("Push Notification Service Enabled")
+}
+
+// isValidVapidKeyPair checks the encoding and the length of the VAPID keys
+func isValidVapidKeyPair(publicKey, privateKey string) bool {
+	publicBytes, err := base64.RawURLEncoding.DecodeString(publicKey)
+	if err != nil || len(publicBytes) != 65 || publicBytes[0] != 4 {
+		return false
+	}
+	privateBytes, err := base64.RawURLEncoding.DecodeString(privateKey)
+	return err == nil && len(privateBytes) == 32
+}
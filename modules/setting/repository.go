@@ -46,6 +46,8 @@ var (
 		DefaultBranch                           string
 		AllowAdoptionOfUnadoptedRepositories    bool
 		AllowDeleteOfUnadoptedRepositories      bool
+		MaxPushCheckPackSize                    int64
+		MaxStagingSessionSize                   int64
 
 		// Repository editor settings
 		Editor struct {
@@ -157,6 +159,8 @@ var (
 		DisableMigrations:                       false,
 		DisableStars:                            false,
 		DefaultBranch:                           "master",
+		MaxPushCheckPackSize:                    50 * 1024 * 1024,
+		MaxStagingSessionSize:                   50 * 1024 * 1024,
 
 		// Repository editor settings
 		Editor: struct {
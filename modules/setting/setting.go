@@ -229,6 +229,10 @@ var (
 			MaxFileSize int64
 		} `ini:"ui.csv"`
 
+		Notebook struct {
+			MaxFileSize int64
+		} `ini:"ui.notebook"`
+
 		Admin struct {
 			UserPagingNum   int
 			RepoPagingNum   int
@@ -281,6 +285,11 @@ var (
 		}{
 			MaxFileSize: 524288,
 		},
+		Notebook: struct {
+			MaxFileSize int64
+		}{
+			MaxFileSize: 5242880,
+		},
 		Admin: struct {
 			UserPagingNum   int
 			RepoPagingNum   int
@@ -336,6 +345,7 @@ var (
 	EnableSSHLog       bool
 	AccessLogTemplate  string
 	EnableXORMLog      bool
+	RequestIDHeader    string
 
 	// Time settings
 	TimeFormat string
@@ -355,6 +365,7 @@ var (
 		DefaultPagingNum       int
 		DefaultGitTreesPerPage int
 		DefaultMaxBlobSize     int64
+		MaxBlobUploadSize      int64
 	}{
 		EnableSwagger:          true,
 		SwaggerURL:             "",
@@ -362,6 +373,7 @@ var (
 		DefaultPagingNum:       30,
 		DefaultGitTreesPerPage: 1000,
 		DefaultMaxBlobSize:     10485760,
+		MaxBlobUploadSize:      104857600,
 	}
 
 	OAuth2 = struct {
@@ -390,11 +402,15 @@ var (
 
 	// Metrics settings
 	Metrics = struct {
-		Enabled bool
-		Token   string
+		Enabled        bool
+		Token          string
+		EnabledGroups  []string
+		MaxRouteLabels int
 	}{
-		Enabled: false,
-		Token:   "",
+		Enabled:        false,
+		Token:          "",
+		EnabledGroups:  []string{"models", "http", "sessions", "queues", "mail", "database"},
+		MaxRouteLabels: 500,
 	}
 
 	// I18n settings
@@ -575,6 +591,7 @@ func NewContext() {
 	LogRootPath = Cfg.Section("log").Key("ROOT_PATH").MustString(path.Join(AppWorkPath, "log"))
 	forcePathSeparator(LogRootPath)
 	RouterLogLevel = log.FromString(Cfg.Section("log").Key("ROUTER_LOG_LEVEL").MustString("Info"))
+	RequestIDHeader = Cfg.Section("log").Key("REQUEST_ID_HEADER").MustString("X-Request-ID")
 
 	sec := Cfg.Section("server")
 	AppName = Cfg.Section("").Key("APP_NAME").MustString("Gitea: Git with a cup of tea")
@@ -852,6 +869,16 @@ func NewContext() {
 
 	newAttachmentService()
 	newLFSService()
+	newPackages()
+	newBackupService()
+	newWorkflow()
+	newHookScripts()
+	newEventStream()
+	newFederation()
+	newAdvisories()
+	newPages()
+	newPreview()
+	newDiffDrivers()
 
 	timeFormatKey := Cfg.Section("time").Key("FORMAT").MustString("")
 	if timeFormatKey != "" {
@@ -1180,6 +1207,8 @@ func NewServices() {
 	newMailService()
 	newRegisterMailService()
 	newNotifyMailService()
+	newPushService()
+	newChatService()
 	newProxyService()
 	newWebhookService()
 	newMigrationsService()
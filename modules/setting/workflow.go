@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Workflow settings
+var (
+	Workflow = struct {
+		Enabled bool
+		// MaxJobsPerRun limits the number of jobs a single workflow file can create
+		MaxJobsPerRun int
+		// JobTimeout is the time after which a running job is failed
+		JobTimeout time.Duration
+	}{
+		Enabled:       false,
+		MaxJobsPerRun: 50,
+		JobTimeout:    6 * time.Hour,
+	}
+)
+
+func newWorkflow() {
+	if err := Cfg.Section("workflow").MapTo(&Workflow); err != nil {
+		log.Fatal("Failed to map Workflow settings: %v", err)
+	}
+}
@@ -125,6 +125,12 @@ var (
 
 	// RepoArchives represents repository archives storage
 	RepoArchives ObjectStorage
+
+	// Packages represents packages storage
+	Packages ObjectStorage
+
+	// Backups represents instance and repository backups storage
+	Backups ObjectStorage
 )
 
 // Init init the stoarge
@@ -145,7 +151,15 @@ func Init() error {
 		return err
 	}
 
-	return initRepoArchives()
+	if err := initRepoArchives(); err != nil {
+		return err
+	}
+
+	if err := initPackages(); err != nil {
+		return err
+	}
+
+	return initBackups()
 }
 
 // NewStorage takes a storage type and some config and returns an ObjectStorage or an error
@@ -190,3 +204,15 @@ func initRepoArchives() (err error) {
 	RepoArchives, err = NewStorage(setting.RepoArchive.Storage.Type, &setting.RepoArchive.Storage)
 	return
 }
+
+func initPackages() (err error) {
+	log.Info("Initialising Packages storage with type: %s", setting.Packages.Storage.Type)
+	Packages, err = NewStorage(setting.Packages.Storage.Type, &setting.Packages.Storage)
+	return
+}
+
+func initBackups() (err error) {
+	log.Info("Initialising Backups storage with type: %s", setting.Backup.Storage.Type)
+	Backups, err = NewStorage(setting.Backup.Storage.Type, &setting.Backup.Storage)
+	return
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// UpdateAvatarOption options when setting the avatar of a user, an organization or a repository
+type UpdateAvatarOption struct {
+	// image of the avatar, encoded in base64
+	// required: true
+	Image string `json:"image" binding:"Required"`
+	// area of the image to use, the whole image is used if not set.
+	// The area is further cropped to its largest centered square.
+	Crop *AvatarCropOption `json:"crop"`
+}
+
+// AvatarCropOption an area of an avatar image, in pixels from its top left corner
+type AvatarCropOption struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
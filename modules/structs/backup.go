@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// Backup represents an archive of the instance or of a repository in the backup storage
+type Backup struct {
+	ID int64 `json:"id"`
+	// what the backup contains, `instance` or `repository`
+	Type string `json:"type"`
+	// full name of the backed up repository when it was backed up, empty for instance backups
+	Repository string `json:"repository,omitempty"`
+	// progress of the backup, one of `queued`, `running`, `finished` or `failed`
+	Status string `json:"status"`
+	// size of the archive in bytes
+	Size  int64  `json:"size"`
+	Error string `json:"error,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+}
+
+// CreateBackupOption options for creating a backup
+type CreateBackupOption struct {
+	// what to back up, `instance` or `repository`
+	// required: true
+	Type string `json:"type" binding:"Required"`
+	// owner of the repository to back up, required for repository backups
+	Owner string `json:"owner"`
+	// name of the repository to back up, required for repository backups
+	Repo string `json:"repo"`
+}
+
+// RestoreBackupOption options for restoring a repository from a backup
+type RestoreBackupOption struct {
+	// owner the repository is restored to
+	// required: true
+	Owner string `json:"owner" binding:"Required"`
+	// name of the restored repository, defaults to the name of the backed up repository
+	RepoName string `json:"repo_name" binding:"AlphaDashDot;MaxSize(100)"`
+}
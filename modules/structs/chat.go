@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// ChatAddress represents the address of a user on a chat network, which receives direct messages
+// about mentions and review requests once verified
+type ChatAddress struct {
+	// one of `xmpp`, `irc` or `matrix`
+	Protocol string `json:"protocol"`
+	// a bare JID for `xmpp`, a nickname for `irc` or a user ID for `matrix`
+	Address  string `json:"address"`
+	Verified bool   `json:"verified"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// BindChatAddressOption options for binding a chat address
+type BindChatAddressOption struct {
+	// required: true
+	Address string `json:"address" binding:"Required;MaxSize(255)"`
+}
+
+// VerifyChatAddressOption options for verifying a chat address
+type VerifyChatAddressOption struct {
+	// code sent to the chat address when binding it
+	//
+	// required: true
+	Code string `json:"code" binding:"Required"`
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// CodeSearchResults results of a code search
+type CodeSearchResults struct {
+	TotalCount int64                 `json:"total_count"`
+	Languages  []*CodeSearchLanguage `json:"languages"`
+	Results    []*CodeSearchResult   `json:"results"`
+}
+
+// CodeSearchLanguage number of matching files of a language
+type CodeSearchLanguage struct {
+	Language string `json:"language"`
+	Color    string `json:"color"`
+	Count    int    `json:"count"`
+}
+
+// CodeSearchResult a file matching a code search
+type CodeSearchResult struct {
+	RepoID       int64  `json:"repo_id"`
+	RepoFullName string `json:"repo_full_name"`
+	Filename     string `json:"filename"`
+	CommitID     string `json:"commit_id"`
+	Language     string `json:"language"`
+	HTMLURL      string `json:"html_url"`
+	// line numbers of the lines of the fragment
+	LineNumbers []int `json:"line_numbers"`
+	// the matching lines and their surrounding lines as HTML with syntax highlighting classes
+	Fragment string `json:"fragment"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
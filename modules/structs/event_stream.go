@@ -0,0 +1,44 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// StreamEvent represents an event delivered by the event stream
+type StreamEvent struct {
+	// ID is the cursor of the event, streams can be resumed after it
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Action     string          `json:"action"`
+	Repository *RepositoryMeta `json:"repository"`
+	Actor      *User           `json:"actor"`
+	// Payload is an Issue, PullRequest, Comment, Release, StreamPushPayload,
+	// StreamRefPayload or StreamStatusPayload depending on the type
+	Payload interface{} `json:"payload"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created"`
+}
+
+// StreamPushPayload represents the payload of a push event of the event stream
+type StreamPushPayload struct {
+	Ref          string `json:"ref"`
+	Before       string `json:"before"`
+	After        string `json:"after"`
+	TotalCommits int    `json:"total_commits"`
+}
+
+// StreamRefPayload represents the payload of a create or delete event of the event stream
+type StreamRefPayload struct {
+	RefType string `json:"ref_type"`
+	Ref     string `json:"ref"`
+}
+
+// StreamStatusPayload represents the payload of a status event of the event stream
+type StreamStatusPayload struct {
+	SHA    string        `json:"sha"`
+	Status *CommitStatus `json:"status"`
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// ActivityPub represents an ActivityPub object, e.g. an actor, an activity or a collection
+type ActivityPub struct {
+	Context string `json:"@context"`
+}
+
+// RemoteActor represents an actor of another instance
+type RemoteActor struct {
+	// ActivityPub ID of the actor
+	URI string `json:"uri"`
+	// handle of the actor, e.g. user@example.com
+	Handle string `json:"handle"`
+	Type   string `json:"type"`
+}
+
+// FederationFollowing represents a remote actor followed by the authenticated user
+type FederationFollowing struct {
+	ID    int64        `json:"id"`
+	Actor *RemoteActor `json:"actor"`
+	// whether the instance of the actor accepted the follow, activities are only received once it did
+	IsAccepted bool `json:"is_accepted"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// FollowRemoteActorOption options for following a remote actor
+type FollowRemoteActorOption struct {
+	// handle, e.g. user@example.com, or ActivityPub ID of the actor
+	// required: true
+	Actor string `json:"actor" binding:"Required;MaxSize(255)"`
+}
+
+// FederationActivity represents an activity received from a followed remote actor
+type FederationActivity struct {
+	ID    int64        `json:"id"`
+	URI   string       `json:"uri"`
+	Type  string       `json:"type"`
+	Actor *RemoteActor `json:"actor"`
+	// sanitized HTML content of the note the activity created, empty for other objects
+	Content string `json:"content"`
+	// URL of the object of the activity
+	URL string `json:"url"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// RepoFederation represents the opt-in of a repository to interactions of users of other instances
+type RepoFederation struct {
+	// whether users of other instances may star the repository
+	Enabled bool `json:"enabled"`
+	// whether users of other instances may open issues
+	AllowIssues bool `json:"allow_issues"`
+	// whether users of other instances may comment on issues and pull requests
+	AllowComments bool  `json:"allow_comments"`
+	NumStars      int64 `json:"stars_count"`
+}
+
+// EditRepoFederationOption options for opting a repository in to federation
+type EditRepoFederationOption struct {
+	AllowIssues   bool `json:"allow_issues"`
+	AllowComments bool `json:"allow_comments"`
+}
+
+// RemoteStar represents a repository starred by a remote actor
+type RemoteStar struct {
+	Actor *RemoteActor `json:"actor"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// FederationBlock represents a remote actor or a domain blocked from interacting with a repository
+type FederationBlock struct {
+	ID int64 `json:"id"`
+	// the blocked actor, null if a domain is blocked
+	Actor  *RemoteActor `json:"actor"`
+	Domain string       `json:"domain"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateFederationBlockOption options for blocking a remote actor or a domain, exactly one of them has to be given
+type CreateFederationBlockOption struct {
+	// handle, e.g. user@example.com, or ActivityPub ID of the actor
+	Actor  string `json:"actor" binding:"MaxSize(255)"`
+	Domain string `json:"domain" binding:"MaxSize(255)"`
+}
@@ -12,3 +12,19 @@ type GitBlobResponse struct {
 	SHA      string `json:"sha"`
 	Size     int64  `json:"size"`
 }
+
+// CreateGitBlobOption options for creating a blob object
+type CreateGitBlobOption struct {
+	// required: true
+	Content string `json:"content"`
+	// encoding of the content, utf-8 (default) or base64
+	// enum: utf-8,base64
+	Encoding string `json:"encoding"`
+}
+
+// CreatedGitBlob represents a blob object written to a repository
+type CreatedGitBlob struct {
+	SHA  string `json:"sha"`
+	URL  string `json:"url"`
+	Size int64  `json:"size"`
+}
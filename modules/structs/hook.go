@@ -112,6 +112,7 @@ var (
 	_ Payloader = &PullRequestPayload{}
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
+	_ Payloader = &SchedulePayload{}
 )
 
 // _________                        __
@@ -413,17 +414,50 @@ const (
 	HookRepoCreated HookRepoAction = "created"
 	// HookRepoDeleted deleted
 	HookRepoDeleted HookRepoAction = "deleted"
+	// HookRepoRenamed renamed, either the repository or its owner
+	HookRepoRenamed HookRepoAction = "renamed"
+	// HookRepoTransferred transferred
+	HookRepoTransferred HookRepoAction = "transferred"
 )
 
+// RepositoryChangesPayload represents the payload information of repository rename or transfer,
+// the old full name keeps redirecting to the repository
+type RepositoryChangesPayload struct {
+	Name     *ChangesFromPayload `json:"name,omitempty"`
+	Owner    *ChangesFromPayload `json:"owner,omitempty"`
+	FullName *ChangesFromPayload `json:"full_name,omitempty"`
+}
+
 // RepositoryPayload payload for repository webhooks
 type RepositoryPayload struct {
-	Action       HookRepoAction `json:"action"`
-	Repository   *Repository    `json:"repository"`
-	Organization *User          `json:"organization"`
-	Sender       *User          `json:"sender"`
+	Action       HookRepoAction            `json:"action"`
+	Repository   *Repository               `json:"repository"`
+	Changes      *RepositoryChangesPayload `json:"changes,omitempty"`
+	Organization *User                     `json:"organization"`
+	Sender       *User                     `json:"sender"`
 }
 
 // JSONPayload JSON representation of the payload
 func (p *RepositoryPayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", " ")
 }
+
+//   _________      .__               .___    .__
+//  /   _____/ ____ |  |__   ____   __| _/_ __|  |   ____
+//  \_____  \_/ ___\|  |  \_/ __ \ / __ |  |  \  | _/ __ \
+//  /        \  \___|   Y  \  ___// /_/ |  |  /  |_\  ___/
+// /_______  /\___  >___|  /\___  >____ |____/|____/\___  >
+//         \/     \/     \/     \/     \/               \/
+
+// SchedulePayload payload for webhooks fired by a repository schedule
+type SchedulePayload struct {
+	Schedule    *RepoSchedule `json:"schedule"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	Repository  *Repository   `json:"repository"`
+	Sender      *User         `json:"sender"`
+}
+
+// JSONPayload JSON representation of the payload
+func (p *SchedulePayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", " ")
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// IssueIndexerStatus represents the state of the issue indexer
+type IssueIndexerStatus struct {
+	// type of the indexer, one of `bleve`, `db`, `elasticsearch` or `meilisearch`
+	Type string `json:"type"`
+	// number of issue updates queued since the start which are not yet indexed
+	Pending int64 `json:"pending"`
+	// swagger:strfmt date-time
+	LastIndexed *time.Time `json:"last_indexed_at,omitempty"`
+	// whether a reindex of all issues or a switch of the indexer is running
+	Reindexing bool `json:"reindexing"`
+	// type of the indexer being populated to replace the current one
+	SwitchingTo string `json:"switching_to,omitempty"`
+}
+
+// ReindexIssuesOption options for reindexing issues
+type ReindexIssuesOption struct {
+	// owner of the repository to reindex, the issues of all repositories are reindexed if empty
+	Owner string `json:"owner"`
+	// name of the repository to reindex
+	Repo string `json:"repo"`
+}
+
+// SwitchIssueIndexerOption options for switching the issue indexer to another backend
+type SwitchIssueIndexerOption struct {
+	// required: true
+	// enum: bleve,db,elasticsearch,meilisearch
+	Type string `json:"type" binding:"Required;In(bleve,db,elasticsearch,meilisearch)"`
+	// index directory, required for `bleve`, relative paths are relative to the work path
+	Path string `json:"path"`
+	// connection url, required for `elasticsearch` and `meilisearch`
+	ConnStr string `json:"conn_str"`
+	// name of the index for `elasticsearch` and `meilisearch`, defaults to `gitea_issues`
+	IndexerName string `json:"indexer_name"`
+}
@@ -57,9 +57,26 @@ type Issue struct {
 	//
 	// type: string
 	// enum: open,closed
-	State    StateType `json:"state"`
-	IsLocked bool      `json:"is_locked"`
-	Comments int       `json:"comments"`
+	State StateType `json:"state"`
+	// Workflow state of the repository the issue is in, beyond open and closed
+	WorkflowState *IssueWorkflowState `json:"workflow_state"`
+	// Iteration the issue is planned in, null if none
+	Iteration *Iteration `json:"iteration"`
+	// Values of the custom fields of the repository and of its organization the issue has
+	CustomFields []*IssueCustomFieldValue `json:"custom_fields"`
+	// Priority level of the repository the issue has, null if none
+	Priority *IssuePriority `json:"priority"`
+	IsLocked bool           `json:"is_locked"`
+	// Reason the conversation was locked for, empty if none was given
+	//
+	// enum: off_topic,too_heated,resolved,spam
+	LockReason string `json:"lock_reason,omitempty"`
+	// Time the conversation is unlocked automatically
+	// swagger:strfmt date-time
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	Comments    int        `json:"comments"`
+	// Number of comments the authenticated user has not read yet, only given to signed in users
+	UnreadCount *int64 `json:"unread_count,omitempty"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -89,6 +106,12 @@ type CreateIssueOption struct {
 	// list of label ids
 	Labels []int64 `json:"labels"`
 	Closed bool    `json:"closed"`
+	// level of the priority of the repository to give the issue
+	Priority int `json:"priority"`
+	// file name of an issue form to submit, the body is rendered from its fields
+	Template string `json:"template"`
+	// values of the fields of the issue form by field id
+	Fields map[string][]string `json:"fields"`
 }
 
 // EditIssueOption options for editing an issue
@@ -101,6 +124,12 @@ type EditIssueOption struct {
 	Assignees []string `json:"assignees"`
 	Milestone *int64   `json:"milestone"`
 	State     *string  `json:"state"`
+	// id of the workflow state to move the issue into, 0 for none
+	WorkflowState *int64 `json:"workflow_state"`
+	// level of the priority of the repository to give the issue, 0 for none
+	Priority *int `json:"priority"`
+	// id of the iteration of the repository or of its organization to plan the issue in, 0 for none
+	Iteration *int64 `json:"iteration"`
 	// swagger:strfmt date-time
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
@@ -120,6 +149,15 @@ type IssueDeadline struct {
 	Deadline *time.Time `json:"due_date"`
 }
 
+// LockIssueOption options for locking the conversation of an issue
+type LockIssueOption struct {
+	// enum: off_topic,too_heated,resolved,spam
+	LockReason string `json:"lock_reason"`
+	// Time the conversation is unlocked automatically, it stays locked if not set
+	// swagger:strfmt date-time
+	LockedUntil *time.Time `json:"locked_until"`
+}
+
 // IssueTemplate represents an issue template for a repository
 // swagger:model
 type IssueTemplate struct {
@@ -129,9 +167,84 @@ type IssueTemplate struct {
 	Labels   []string `json:"labels" yaml:"labels"`
 	Content  string   `json:"content" yaml:"-"`
 	FileName string   `json:"file_name" yaml:"-"`
+	// fields of an issue form, empty for markdown templates
+	Fields []*IssueFormField `json:"body,omitempty" yaml:"body"`
 }
 
 // Valid checks whether an IssueTemplate is considered valid, e.g. at least name and about
 func (it IssueTemplate) Valid() bool {
 	return strings.TrimSpace(it.Name) != "" && strings.TrimSpace(it.About) != ""
 }
+
+// IsForm returns whether the template is an issue form defined in YAML
+func (it IssueTemplate) IsForm() bool {
+	return len(it.Fields) > 0
+}
+
+// IssueFormFieldType is the type of a field of an issue form
+type IssueFormFieldType string
+
+const (
+	// IssueFormFieldTypeMarkdown is text shown in the form, which is not submitted
+	IssueFormFieldTypeMarkdown IssueFormFieldType = "markdown"
+	// IssueFormFieldTypeInput is a single line text input
+	IssueFormFieldTypeInput IssueFormFieldType = "input"
+	// IssueFormFieldTypeTextarea is a multi line text input
+	IssueFormFieldTypeTextarea IssueFormFieldType = "textarea"
+	// IssueFormFieldTypeDropdown is a selection of one or multiple options
+	IssueFormFieldTypeDropdown IssueFormFieldType = "dropdown"
+	// IssueFormFieldTypeCheckboxes is a list of checkboxes
+	IssueFormFieldTypeCheckboxes IssueFormFieldType = "checkboxes"
+)
+
+// IssueFormField represents a field of an issue form
+type IssueFormField struct {
+	Type        IssueFormFieldType        `json:"type" yaml:"type"`
+	ID          string                    `json:"id" yaml:"id"`
+	Attributes  IssueFormFieldAttributes  `json:"attributes" yaml:"attributes"`
+	Validations IssueFormFieldValidations `json:"validations" yaml:"validations"`
+}
+
+// IssueFormFieldAttributes represents the attributes of a field of an issue form
+type IssueFormFieldAttributes struct {
+	Label       string `json:"label" yaml:"label"`
+	Description string `json:"description" yaml:"description"`
+	Placeholder string `json:"placeholder" yaml:"placeholder"`
+	// default value of an input or textarea, or the text of a markdown field
+	Value string `json:"value" yaml:"value"`
+	// language a textarea is rendered as code block in
+	Render string `json:"render" yaml:"render"`
+	// whether multiple options of a dropdown can be selected
+	Multiple bool                   `json:"multiple" yaml:"multiple"`
+	Options  []IssueFormFieldOption `json:"options" yaml:"options"`
+}
+
+// IssueFormFieldOption represents an option of a dropdown or checkboxes field of an issue form
+type IssueFormFieldOption struct {
+	Label string `json:"label" yaml:"label"`
+	// whether a checkbox has to be checked
+	Required bool `json:"required" yaml:"required"`
+}
+
+// UnmarshalYAML allows the options of dropdowns to be given as plain strings
+func (o *IssueFormFieldOption) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&o.Label); err == nil {
+		return nil
+	}
+	type option IssueFormFieldOption
+	return unmarshal((*option)(o))
+}
+
+// IssueFormFieldValidations represents the validations of a field of an issue form
+type IssueFormFieldValidations struct {
+	Required bool `json:"required" yaml:"required"`
+}
+
+// IssueFormValues represents the values an issue form was submitted with
+// swagger:model
+type IssueFormValues struct {
+	// file name of the issue form
+	Template string `json:"template"`
+	// values by field id
+	Fields map[string][]string `json:"fields"`
+}
@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// IssueBranch represents a branch created to work on an issue
+type IssueBranch struct {
+	Name string `json:"name"`
+	// index of the pull request opened from the branch, 0 if none is opened yet
+	PullRequestIndex int64 `json:"pull_request_index"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateIssueBranchOption options for creating a branch to work on an issue
+type CreateIssueBranchOption struct {
+	// name of the branch, defaults to a name from the issue branch name template of the repository
+	BranchName string `json:"branch_name" binding:"GitRefName;MaxSize(100)"`
+	// name of the branch to create the branch from, defaults to the default branch
+	OldBranchName string `json:"old_branch_name" binding:"GitRefName;MaxSize(100)"`
+}
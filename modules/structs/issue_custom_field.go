@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// IssueCustomField represents a custom field of the issues of an organization or a repository
+type IssueCustomField struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// enum: text,number,enum,date
+	Type string `json:"type"`
+	// allowed values of an enum field
+	Options []string `json:"options"`
+}
+
+// CreateIssueCustomFieldOption options for creating a custom field of the issues
+type CreateIssueCustomFieldOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(50)"`
+	Description string `json:"description"`
+	// required: true
+	// enum: text,number,enum,date
+	Type string `json:"type" binding:"Required;In(text,number,enum,date)"`
+	// allowed values of an enum field
+	Options []string `json:"options"`
+}
+
+// EditIssueCustomFieldOption options for editing a custom field of the issues, the type cannot be changed
+type EditIssueCustomFieldOption struct {
+	Name        *string `json:"name" binding:"MaxSize(50)"`
+	Description *string `json:"description"`
+	// allowed values of an enum field, the values of the issues which are no longer allowed are removed
+	Options []string `json:"options"`
+}
+
+// IssueCustomFieldValue represents the value of a custom field of an issue
+type IssueCustomFieldValue struct {
+	FieldID int64  `json:"field_id"`
+	Name    string `json:"name"`
+	// enum: text,number,enum,date
+	Type string `json:"type"`
+	// numbers are formatted without trailing zeros and dates as YYYY-MM-DD
+	Value string `json:"value"`
+}
+
+// SetIssueCustomFieldValuesOption options for setting the values of custom fields of an issue
+type SetIssueCustomFieldValuesOption struct {
+	// values by field name, an empty value removes the value of the field. The fields not given are left unchanged
+	// required: true
+	Values map[string]string `json:"values" binding:"Required"`
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// IssueFilter represents a saved issue search
+type IssueFilter struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// query string with the parameters of the issue search, e.g. `state=open&labels=bug`
+	Query string `json:"query"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateIssueFilterOption options when saving an issue filter
+type CreateIssueFilterOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// query string with the parameters of the issue search, e.g. `state=open&labels=bug`
+	Query string `json:"query"`
+}
+
+// EditIssueFilterOption options when editing a saved issue filter
+type EditIssueFilterOption struct {
+	Name  *string `json:"name" binding:"MaxSize(255)"`
+	Query *string `json:"query"`
+}
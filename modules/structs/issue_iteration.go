@@ -0,0 +1,66 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// IterationCadence represents a schedule of fixed-length iterations of an organization or a repository
+type IterationCadence struct {
+	ID           int64  `json:"id"`
+	Title        string `json:"title"`
+	DurationDays int    `json:"duration_days"`
+	// number of iterations created after the current one
+	IterationsAhead int `json:"iterations_ahead"`
+	// swagger:strfmt date-time
+	Start time.Time `json:"start_at"`
+}
+
+// CreateIterationCadenceOption options for creating an iteration cadence
+type CreateIterationCadenceOption struct {
+	// title of the cadence, the iterations are named after it and their number
+	// required: true
+	Title string `json:"title" binding:"Required;MaxSize(50)"`
+	// length of each iteration, up to 365 days
+	// required: true
+	DurationDays int `json:"duration_days" binding:"Required"`
+	// number of iterations created after the current one, up to 10, defaults to 1
+	IterationsAhead *int `json:"iterations_ahead"`
+	// start of the first iteration, defaults to now
+	// swagger:strfmt date-time
+	Start *time.Time `json:"start_at"`
+}
+
+// EditIterationCadenceOption options for editing an iteration cadence
+type EditIterationCadenceOption struct {
+	Title           *string `json:"title" binding:"MaxSize(50)"`
+	IterationsAhead *int    `json:"iterations_ahead"`
+}
+
+// Iteration represents an iteration (sprint) of an organization or a repository
+type Iteration struct {
+	ID        int64  `json:"id"`
+	CadenceID int64  `json:"cadence_id"`
+	Sequence  int    `json:"sequence"`
+	Title     string `json:"title"`
+	// enum: past,current,upcoming
+	State string `json:"state"`
+	// swagger:strfmt date-time
+	Start time.Time `json:"start_at"`
+	// end of the iteration, exclusive
+	// swagger:strfmt date-time
+	End time.Time `json:"end_at"`
+	// statistics of the issues assigned to the iteration, only set when a single iteration is requested
+	Stats *IterationStats `json:"stats,omitempty"`
+}
+
+// IterationStats represents the statistics of the issues assigned to an iteration
+type IterationStats struct {
+	Issues       int64 `json:"issues"`
+	ClosedIssues int64 `json:"closed_issues"`
+	// number of issues closed before the end of the iteration
+	Velocity int64 `json:"velocity"`
+}
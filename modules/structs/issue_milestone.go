@@ -43,3 +43,23 @@ type EditMilestoneOption struct {
 	State       *string    `json:"state"`
 	Deadline    *time.Time `json:"due_on"`
 }
+
+// CloneMilestoneOption options for cloning a milestone
+type CloneMilestoneOption struct {
+	// owner of the repository the milestone is cloned to, defaults to the owner of the repository of the milestone
+	Owner string `json:"owner"`
+	// name of the repository the milestone is cloned to, defaults to the repository of the milestone
+	Repo string `json:"repo"`
+	// title of the clone, defaults to the title of the milestone
+	Title string `json:"title"`
+}
+
+// MilestoneBurndownDay is the state of the issues of a milestone at the end of a day
+type MilestoneBurndownDay struct {
+	// the day in the default time zone of the server, e.g. `2021-03-01`
+	Date         string `json:"date"`
+	OpenIssues   int    `json:"open_issues"`
+	ClosedIssues int    `json:"closed_issues"`
+	// the time in seconds tracked on the issues of the milestone until the end of the day
+	TrackedTime int64 `json:"tracked_time"`
+}
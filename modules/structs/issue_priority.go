@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// IssuePriority represents a priority level the issues of a repository can be given
+// swagger:model
+type IssuePriority struct {
+	ID int64 `json:"id"`
+	// the higher the level the more urgent are the issues of the priority
+	Level       int    `json:"level"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// example: 00aabb
+	Color string `json:"color"`
+}
+
+// CreateIssuePriorityOption options for creating a priority level
+type CreateIssuePriorityOption struct {
+	// required:true
+	Level int `json:"level" binding:"Required"`
+	// required:true
+	Name string `json:"name" binding:"Required;MaxSize(50)"`
+	// example: #00aabb
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// EditIssuePriorityOption options for editing a priority level
+type EditIssuePriorityOption struct {
+	Level       *int    `json:"level"`
+	Name        *string `json:"name" binding:"MaxSize(50)"`
+	Color       *string `json:"color"`
+	Description *string `json:"description"`
+}
+
+// IssueSLAReport represents how fast the issues of a priority level got their first response,
+// which is the first comment or review by someone other than the poster of an issue
+// swagger:model
+type IssueSLAReport struct {
+	// null for the issues without priority
+	Priority *IssuePriority `json:"priority"`
+	// number of issues created in the reported period
+	IssueCount int64 `json:"issue_count"`
+	// number of these issues which got a response
+	RespondedCount int64 `json:"responded_count"`
+	// average time to the first response in seconds
+	AverageFirstResponse int64 `json:"average_first_response"`
+	// median time to the first response in seconds
+	MedianFirstResponse int64 `json:"median_first_response"`
+}
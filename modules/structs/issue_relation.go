@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// IssueRelation represents a relation of an issue to another issue
+type IssueRelation struct {
+	ID int64 `json:"id"`
+	// the relation seen from the issue
+	// enum: relates,duplicates,duplicated_by,caused_by,causes
+	Type       string    `json:"type"`
+	Repository string    `json:"repository"`
+	Index      int64     `json:"index"`
+	Title      string    `json:"title"`
+	State      StateType `json:"state"`
+	IsPull     bool      `json:"is_pull"`
+	HTMLURL    string    `json:"html_url"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateIssueRelationOption options for relating an issue to another issue
+type CreateIssueRelationOption struct {
+	// enum: relates,duplicates,caused_by
+	// required: true
+	Type string `json:"type" binding:"Required;In(relates,duplicates,caused_by)"`
+	// owner of the repository of the related issue, defaults to the owner of the repository of the issue
+	Owner string `json:"owner"`
+	// name of the repository of the related issue, defaults to the repository of the issue
+	Repo string `json:"repo"`
+	// index of the related issue
+	// required: true
+	Index int64 `json:"index" binding:"Required"`
+}
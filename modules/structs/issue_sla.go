@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// IssueSLAPolicy represents a service level agreement on the first response to the issues of a repository
+// swagger:model
+type IssueSLAPolicy struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// the label the issues of the policy have, 0 if the policy applies to all issues
+	LabelID int64 `json:"label_id"`
+	// number of hours the issues have to get their first response
+	FirstResponseHours int `json:"first_response_hours"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateIssueSLAPolicyOption options for creating a SLA policy
+type CreateIssueSLAPolicyOption struct {
+	// required:true
+	Name string `json:"name" binding:"Required;MaxSize(50)"`
+	// restrict the policy to the issues having this label of the repository or its organization
+	LabelID int64 `json:"label_id"`
+	// required:true
+	FirstResponseHours int `json:"first_response_hours" binding:"Required"`
+}
+
+// EditIssueSLAPolicyOption options for editing a SLA policy
+type EditIssueSLAPolicyOption struct {
+	Name *string `json:"name" binding:"MaxSize(50)"`
+	// 0 applies the policy to all issues
+	LabelID            *int64 `json:"label_id"`
+	FirstResponseHours *int   `json:"first_response_hours"`
+}
+
+// IssueSLACompliance represents how well the issues a SLA policy applies to met it
+// swagger:model
+type IssueSLACompliance struct {
+	Policy *IssueSLAPolicy `json:"policy"`
+	// number of issues created in the reported period
+	IssueCount int64 `json:"issue_count"`
+	// number of issues which got their first response in time
+	MetCount int64 `json:"met_count"`
+	// number of issues which got their first response late, or did not get one in time
+	BreachedCount int64 `json:"breached_count"`
+	// number of issues still waiting for their first response within the time of the policy
+	PendingCount int64 `json:"pending_count"`
+	// percentage of the met issues among the met and breached ones, 100 if there are none
+	CompliancePercent float64 `json:"compliance_percent"`
+}
@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// IssueWorkflowState represents a state of the issues of a repository beyond open and closed, e.g. "in progress"
+// swagger:model
+type IssueWorkflowState struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// example: 00aabb
+	Color string `json:"color"`
+	// position of the state in the workflow of the repository
+	Sort int `json:"sort"`
+}
+
+// CreateIssueWorkflowStateOption options for creating a workflow state
+type CreateIssueWorkflowStateOption struct {
+	// required:true
+	Name string `json:"name" binding:"Required;MaxSize(50)"`
+	// example: #00aabb
+	Color       string `json:"color"`
+	Description string `json:"description"`
+	Sort        int    `json:"sort"`
+}
+
+// EditIssueWorkflowStateOption options for editing a workflow state
+type EditIssueWorkflowStateOption struct {
+	Name        *string `json:"name" binding:"MaxSize(50)"`
+	Color       *string `json:"color"`
+	Description *string `json:"description"`
+	Sort        *int    `json:"sort"`
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// AssigneeWorkload represents the open issues and pull requests assigned to a user
+// swagger:model
+type AssigneeWorkload struct {
+	Assignee         *User `json:"assignee"`
+	OpenIssues       int64 `json:"open_issues"`
+	OpenPullRequests int64 `json:"open_pull_requests"`
+	// breakdown by milestone, the issues and pull requests without milestone come last
+	Milestones []*MilestoneWorkload `json:"milestones"`
+}
+
+// MilestoneWorkload represents the open issues and pull requests of a milestone assigned to a user
+type MilestoneWorkload struct {
+	// null for the issues and pull requests without milestone
+	Milestone *Milestone `json:"milestone"`
+	// repository of the milestone, null if there is none
+	Repo             *RepositoryMeta `json:"repository"`
+	OpenIssues       int64           `json:"open_issues"`
+	OpenPullRequests int64           `json:"open_pull_requests"`
+}
@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// MaintenanceMode represents the maintenance mode of the instance, during which writes are rejected
+type MaintenanceMode struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+	// seconds clients are asked to wait before retrying a rejected write
+	RetryAfter int64 `json:"retry_after"`
+	// swagger:strfmt date-time
+	Since time.Time `json:"since"`
+}
+
+// EditMaintenanceModeOption options for enabling or disabling the maintenance mode
+type EditMaintenanceModeOption struct {
+	// required: true
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+	// seconds clients are asked to wait before retrying a rejected write, defaults to 300
+	RetryAfter int64 `json:"retry_after"`
+}
@@ -17,6 +17,8 @@ type NotificationThread struct {
 	Pinned     bool                 `json:"pinned"`
 	UpdatedAt  time.Time            `json:"updated_at"`
 	URL        string               `json:"url"`
+	// Time a snoozed thread becomes unread again
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
 }
 
 // NotificationSubject contains the notification subject (Issue/Pull/Commit)
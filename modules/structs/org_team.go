@@ -17,6 +17,8 @@ type Team struct {
 	// example: ["repo.code","repo.issues","repo.ext_issues","repo.wiki","repo.pulls","repo.releases","repo.projects","repo.ext_wiki"]
 	Units            []string `json:"units"`
 	CanCreateOrgRepo bool     `json:"can_create_org_repo"`
+	// id of the parent team, 0 for top level teams
+	ParentTeamID int64 `json:"parent_team_id"`
 }
 
 // CreateTeamOption options for creating a team
@@ -30,6 +32,8 @@ type CreateTeamOption struct {
 	// example: ["repo.code","repo.issues","repo.ext_issues","repo.wiki","repo.pulls","repo.releases","repo.projects","repo.ext_wiki"]
 	Units            []string `json:"units"`
 	CanCreateOrgRepo bool     `json:"can_create_org_repo"`
+	// id of the parent team, members of the team inherit its repositories
+	ParentTeamID int64 `json:"parent_team_id"`
 }
 
 // EditTeamOption options for editing a team
@@ -43,4 +47,6 @@ type EditTeamOption struct {
 	// example: ["repo.code","repo.issues","repo.ext_issues","repo.wiki","repo.pulls","repo.releases","repo.projects","repo.ext_wiki"]
 	Units            []string `json:"units"`
 	CanCreateOrgRepo *bool    `json:"can_create_org_repo"`
+	// id of the parent team, 0 to make it a top level team
+	ParentTeamID *int64 `json:"parent_team_id"`
 }
@@ -0,0 +1,19 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// SetPinnedReposOption options for setting the repositories pinned on the profile of a user or an organization
+type SetPinnedReposOption struct {
+	// full names (owner/name) of the repositories in the order to show them, at most six.
+	// An empty list unpins all repositories.
+	Repos []string `json:"repos"`
+}
+
+// SetPinnedIssuesOption options for setting the issues pinned in a repository
+type SetPinnedIssuesOption struct {
+	// indexes of the issues in the order to show them, at most three.
+	// An empty list unpins all issues.
+	Issues []int64 `json:"issues"`
+}
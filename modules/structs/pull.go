@@ -22,7 +22,11 @@ type PullRequest struct {
 	Assignees []*User    `json:"assignees"`
 	State     StateType  `json:"state"`
 	IsLocked  bool       `json:"is_locked"`
-	Comments  int        `json:"comments"`
+	// enum: off_topic,too_heated,resolved,spam
+	LockReason string `json:"lock_reason,omitempty"`
+	// swagger:strfmt date-time
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	Comments    int        `json:"comments"`
 
 	HTMLURL  string `json:"html_url"`
 	DiffURL  string `json:"diff_url"`
@@ -93,3 +97,31 @@ type EditPullRequestOption struct {
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
 }
+
+// PullRequestMergeMessage represents the default commit message of merging a pull request
+type PullRequestMergeMessage struct {
+	// enum: merge,rebase,rebase-merge,squash
+	Style string `json:"style"`
+	// first line of the commit message
+	Title string `json:"title"`
+	// remaining lines of the commit message
+	Body string `json:"body"`
+}
+
+// PullRequestMergeEligibility represents whether a pull request can be merged now
+type PullRequestMergeEligibility struct {
+	// true if the pull request can be merged without conflicts
+	Mergeable bool `json:"mergeable"`
+	// true if the authenticated user may merge into the base branch
+	UserAllowed bool `json:"user_allowed"`
+	// true if nothing blocks the merge
+	Eligible bool `json:"eligible"`
+	// the reasons which block the merge
+	Blockers []string `json:"blockers"`
+	// combined state of the status checks required by the base branch, empty if none are required
+	StatusState CommitStatusState `json:"status_state"`
+	// maximum age in minutes of the status checks accepted by the base branch, 0 if unlimited
+	StatusCheckMaxAge int64 `json:"status_check_max_age"`
+	// contexts of the status checks which are too old to be accepted
+	StaleStatusContexts []string `json:"stale_status_contexts"`
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// PushConfig represents the push notification settings a client needs for registering a device
+type PushConfig struct {
+	Enabled bool `json:"enabled"`
+	// public key identifying the server to Web Push services, to be passed as `applicationServerKey` when subscribing
+	VapidPublicKey string `json:"vapid_public_key"`
+	// whether `gateway` devices can be registered
+	GatewayEnabled bool `json:"gateway_enabled"`
+}
+
+// PushDevice represents a device receiving push notifications
+type PushDevice struct {
+	ID int64 `json:"id"`
+	// one of `web` or `gateway`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Platform string `json:"platform,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// PushSubscriptionKeys are the keys of a Web Push subscription
+type PushSubscriptionKeys struct {
+	P256DH string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// CreatePushDeviceOption options for registering a push device
+type CreatePushDeviceOption struct {
+	// `web` for a browser subscribed through the Web Push API, `gateway` for a mobile device reached through the push gateway
+	//
+	// required: true
+	Type string `json:"type" binding:"Required;In(web,gateway)"`
+	Name string `json:"name" binding:"MaxSize(255)"`
+	// endpoint of the Web Push subscription
+	Endpoint string                `json:"endpoint"`
+	Keys     *PushSubscriptionKeys `json:"keys"`
+	// device token of a gateway device
+	Token string `json:"token"`
+	// platform of a gateway device, passed on to the push gateway
+	Platform string `json:"platform" binding:"MaxSize(255)"`
+}
+
+// NotificationChannel represents the issue events a user receives through a notification channel
+type NotificationChannel struct {
+	// one of `mail` or `push`
+	Channel string `json:"channel"`
+	// any of `issue`, `comment`, `state_change`, `push` and `mention`
+	Events []string `json:"events"`
+}
+
+// EditNotificationChannelOption options for changing the events received through a notification channel
+type EditNotificationChannelOption struct {
+	// any of `issue`, `comment`, `state_change`, `push` and `mention`
+	//
+	// required: true
+	Events []string `json:"events"`
+}
@@ -0,0 +1,19 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// Redirect represents a path a renamed or transferred user, organization or repository was available at
+type Redirect struct {
+	// path the user, organization or repository was available at
+	OldPath string `json:"old_path"`
+	// path the old path redirects to
+	NewPath string `json:"new_path"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -25,6 +25,8 @@ type InternalTracker struct {
 	AllowOnlyContributorsToTrackTime bool `json:"allow_only_contributors_to_track_time"`
 	// Enable dependencies for issues and pull requests (Built-in issue tracker)
 	EnableIssueDependencies bool `json:"enable_issue_dependencies"`
+	// Name template of the branches created for issues, may contain ${Index} and ${Slug}, empty for `issue-${Index}-${Slug}` (Built-in issue tracker)
+	IssueBranchNameTemplate string `json:"issue_branch_name_template"`
 }
 
 // ExternalTracker represents settings for external tracker
@@ -90,9 +92,13 @@ type Repository struct {
 	AllowRebaseMerge          bool             `json:"allow_rebase_explicit"`
 	AllowSquash               bool             `json:"allow_squash_merge"`
 	DefaultMergeStyle         string           `json:"default_merge_style"`
-	AvatarURL                 string           `json:"avatar_url"`
-	Internal                  bool             `json:"internal"`
-	MirrorInterval            string           `json:"mirror_interval"`
+	// template of the default message of merge commits, empty for the built-in message
+	DefaultMergeMessageTemplate string `json:"default_merge_message_template"`
+	// template of the default message of squash commits, empty for the built-in message
+	DefaultSquashMessageTemplate string `json:"default_squash_message_template"`
+	AvatarURL                    string `json:"avatar_url"`
+	Internal                     bool   `json:"internal"`
+	MirrorInterval               string `json:"mirror_interval"`
 }
 
 // CreateRepoOption options when creating repository
@@ -176,6 +182,10 @@ type EditRepoOption struct {
 	DefaultDeleteBranchAfterMerge *bool `json:"default_delete_branch_after_merge,omitempty"`
 	// set to a merge style to be used by this repository: "merge", "rebase", "rebase-merge", or "squash". `has_pull_requests` must be `true`.
 	DefaultMergeStyle *string `json:"default_merge_style,omitempty"`
+	// set to a template of the default message of merge commits, an empty string restores the built-in message. `has_pull_requests` must be `true`.
+	DefaultMergeMessageTemplate *string `json:"default_merge_message_template,omitempty"`
+	// set to a template of the default message of squash commits, an empty string restores the built-in message. `has_pull_requests` must be `true`.
+	DefaultSquashMessageTemplate *string `json:"default_squash_message_template,omitempty"`
 	// set to `true` to archive this repository.
 	Archived *bool `json:"archived,omitempty"`
 	// set to a string like `8h30m0s` to set the mirror interval time
@@ -228,6 +238,16 @@ type CreateBranchRepoOption struct {
 	OldBranchName string `json:"old_branch_name" binding:"GitRefName;MaxSize(100)"`
 }
 
+// RenameDefaultBranchOption options when renaming the default branch of a repository
+// swagger:model
+type RenameDefaultBranchOption struct {
+
+	// New name of the default branch
+	//
+	// required: true
+	Name string `json:"name" binding:"Required;GitRefName;MaxSize(100)"`
+}
+
 // TransferRepoOption options when transfer a repository's ownership
 // swagger:model
 type TransferRepoOption struct {
@@ -306,6 +326,39 @@ type MigrateRepoOptions struct {
 	PullRequests   bool   `json:"pull_requests"`
 	Releases       bool   `json:"releases"`
 	MirrorInterval string `json:"mirror_interval"`
+	// Map the users of the migrated data to the local users with the same verified email, only allowed for site administrators
+	MapUsersByEmail bool `json:"map_users_by_email"`
+}
+
+// MigrationIdentity represents a user of the migrated data on the source service
+type MigrationIdentity struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Name of the local user the identity is mapped to, empty if the identity is not mapped
+	LocalUser string `json:"local_user"`
+}
+
+// MigrationDryRun represents the users of the data a migration would import
+type MigrationDryRun struct {
+	Identities []*MigrationIdentity `json:"identities"`
+	// Number of identities not mapped to a local user
+	Unmapped int `json:"unmapped"`
+}
+
+// SyncMigrationOptions options for syncing a migrated repository with its source
+// this is used to interact with api v1
+type SyncMigrationOptions struct {
+	AuthUsername string `json:"auth_username"`
+	AuthPassword string `json:"auth_password"`
+	AuthToken    string `json:"auth_token"`
+
+	Milestones bool `json:"milestones"`
+	Labels     bool `json:"labels"`
+	Issues     bool `json:"issues"`
+	Releases   bool `json:"releases"`
+	// Map the users of the synced data to the local users with the same verified email, only allowed for site administrators
+	MapUsersByEmail bool `json:"map_users_by_email"`
 }
 
 // TokenAuth represents whether a service type supports token-based auth
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// RepoAutoRelease represents the rule of a repository creating a tag and a release when the version read from a file
+// of its default branch changes
+type RepoAutoRelease struct {
+	// path of the file of the default branch the version is read from, e.g. `VERSION` or `package.json`
+	FilePath string `json:"file_path"`
+	// regular expression whose first group matches the version in the file, empty to read the `version` key of a
+	// JSON file or the first line of any other file
+	VersionPattern string `json:"version_pattern"`
+	// template of the tag name, `${Version}` is replaced by the version
+	TagTemplate string `json:"tag_template"`
+	// template of the release title
+	TitleTemplate string `json:"title_template"`
+	// template of the release notes, `${Commits}` is replaced by the list of the commits since the previous release
+	NotesTemplate string `json:"notes_template"`
+	IsDraft       bool   `json:"draft"`
+	IsPrerelease  bool   `json:"prerelease"`
+	// version of the latest release created by the rule
+	LastVersion  string `json:"last_version"`
+	LastTagName  string `json:"last_tag_name"`
+	LastCommitID string `json:"last_commit_id"`
+	// error of the latest change of the version which did not create a release
+	LastError string `json:"last_error,omitempty"`
+	// swagger:strfmt date-time
+	LastReleased *time.Time `json:"last_released_at,omitempty"`
+}
+
+// CreateOrUpdateRepoAutoReleaseOption options when setting the auto release rule of a repository
+//
+// The templates can use the variables `${Version}`, `${PreviousVersion}`, `${Tag}`, `${PreviousTag}`, `${CommitID}`,
+// `${ShortCommitID}`, `${Commits}` and `${Repository}`.
+type CreateOrUpdateRepoAutoReleaseOption struct {
+	// required: true
+	FilePath       string `json:"file_path" binding:"Required;MaxSize(255)"`
+	VersionPattern string `json:"version_pattern" binding:"MaxSize(255)"`
+	// defaults to `v${Version}`
+	TagTemplate string `json:"tag_template" binding:"MaxSize(255)"`
+	// defaults to `${Tag}`
+	TitleTemplate string `json:"title_template" binding:"MaxSize(255)"`
+	// defaults to `${Commits}`
+	NotesTemplate string `json:"notes_template"`
+	IsDraft       bool   `json:"draft"`
+	IsPrerelease  bool   `json:"prerelease"`
+}
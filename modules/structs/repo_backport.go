@@ -0,0 +1,27 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// BackportMapping maps a backport label to the branch merged pull requests with the label are backported to
+type BackportMapping struct {
+	ID int64 `json:"id"`
+	// label requesting the backport, e.g. backport/v1.15
+	Label string `json:"label"`
+	// branch the pull requests are backported to
+	TargetBranch string `json:"target_branch"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateBackportMappingOption options when creating a backport branch mapping
+type CreateBackportMappingOption struct {
+	// label requesting the backport, it must start with `backport/`
+	// required: true
+	Label string `json:"label" binding:"Required;MaxSize(50)"`
+	// required: true
+	TargetBranch string `json:"target_branch" binding:"Required;GitRefName;MaxSize(100)"`
+}
@@ -34,6 +34,7 @@ type BranchProtection struct {
 	MergeWhitelistTeams           []string `json:"merge_whitelist_teams"`
 	EnableStatusCheck             bool     `json:"enable_status_check"`
 	StatusCheckContexts           []string `json:"status_check_contexts"`
+	StatusCheckMaxAge             int64    `json:"status_check_max_age"`
 	RequiredApprovals             int64    `json:"required_approvals"`
 	EnableApprovalsWhitelist      bool     `json:"enable_approvals_whitelist"`
 	ApprovalsWhitelistUsernames   []string `json:"approvals_whitelist_username"`
@@ -64,6 +65,7 @@ type CreateBranchProtectionOption struct {
 	MergeWhitelistTeams           []string `json:"merge_whitelist_teams"`
 	EnableStatusCheck             bool     `json:"enable_status_check"`
 	StatusCheckContexts           []string `json:"status_check_contexts"`
+	StatusCheckMaxAge             int64    `json:"status_check_max_age"`
 	RequiredApprovals             int64    `json:"required_approvals"`
 	EnableApprovalsWhitelist      bool     `json:"enable_approvals_whitelist"`
 	ApprovalsWhitelistUsernames   []string `json:"approvals_whitelist_username"`
@@ -89,6 +91,7 @@ type EditBranchProtectionOption struct {
 	MergeWhitelistTeams           []string `json:"merge_whitelist_teams"`
 	EnableStatusCheck             *bool    `json:"enable_status_check"`
 	StatusCheckContexts           []string `json:"status_check_contexts"`
+	StatusCheckMaxAge             *int64   `json:"status_check_max_age"`
 	RequiredApprovals             *int64   `json:"required_approvals"`
 	EnableApprovalsWhitelist      *bool    `json:"enable_approvals_whitelist"`
 	ApprovalsWhitelistUsernames   []string `json:"approvals_whitelist_username"`
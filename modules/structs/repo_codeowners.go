@@ -0,0 +1,63 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// ValidateCodeOwnersOption options for validating the content of a code owners file
+type ValidateCodeOwnersOption struct {
+	// required: true
+	Content string `json:"content" binding:"Required"`
+	// commit, branch or tag whose files are checked against the rules, defaults to the default branch
+	Ref string `json:"ref"`
+}
+
+// CodeOwnersError represents a problem of a line of a code owners file
+type CodeOwnersError struct {
+	Line int `json:"line"`
+	// enum: syntax,unknown_owner,unreachable_rule
+	Kind    string `json:"kind"`
+	Pattern string `json:"pattern,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+	Message string `json:"message"`
+}
+
+// CodeOwnersValidation represents the result of validating a code owners file
+type CodeOwnersValidation struct {
+	Valid  bool               `json:"valid"`
+	Errors []*CodeOwnersError `json:"errors"`
+}
+
+// CodeOwner represents an owner of a file changed by a pull request
+type CodeOwner struct {
+	// the owner as written in the code owners file
+	Name string `json:"name"`
+	// enum: user,team,email
+	Type string `json:"type"`
+	// the user of a user or email owner, empty if it does not exist
+	User *User `json:"user,omitempty"`
+	// the team of a team owner, empty if it does not exist
+	Team *Team `json:"team,omitempty"`
+	// true if the owner, or a member of the team, approved the pull request
+	Approved bool `json:"approved"`
+}
+
+// PullRequestFileOwners represents the owners of a file changed by a pull request
+type PullRequestFileOwners struct {
+	Filename string `json:"filename"`
+	// line of the code owners rule which applies to the file, 0 if none applies
+	RuleLine int          `json:"rule_line"`
+	Pattern  string       `json:"pattern"`
+	Owners   []*CodeOwner `json:"owners"`
+	// true if one of the owners approved the pull request or if the file has no owners
+	Approved bool `json:"approved"`
+}
+
+// PullRequestCodeOwners represents the ownership of the files changed by a pull request
+type PullRequestCodeOwners struct {
+	// path of the code owners file of the base branch, empty if there is none
+	CodeOwnersFile string                   `json:"codeowners_file"`
+	Files          []*PullRequestFileOwners `json:"files"`
+	// true if all the changed files are approved by one of their owners
+	Approved bool `json:"approved"`
+}
@@ -6,5 +6,14 @@ package structs
 
 // AddCollaboratorOption options when adding a user as a collaborator of a repository
 type AddCollaboratorOption struct {
+	// guests may only read the issues and pull requests of private repositories
+	// enum: read,write,admin,guest
 	Permission *string `json:"permission"`
 }
+
+// RepoCollaboratorPermission the permission of a user in a repository
+type RepoCollaboratorPermission struct {
+	// enum: none,read,write,admin,owner,guest
+	Permission string `json:"permission"`
+	User       *User  `json:"user"`
+}
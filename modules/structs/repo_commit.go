@@ -37,6 +37,8 @@ type RepoCommit struct {
 	Committer *CommitUser `json:"committer"`
 	Message   string      `json:"message"`
 	Tree      *CommitMeta `json:"tree"`
+	// Verification of the signature of the commit, only given if requested
+	Verification *PayloadCommitVerification `json:"verification,omitempty"`
 }
 
 // Commit contains information generated from a Git commit.
@@ -48,6 +50,15 @@ type Commit struct {
 	Committer  *User                  `json:"committer"`
 	Parents    []*CommitMeta          `json:"parents"`
 	Files      []*CommitAffectedFiles `json:"files"`
+	// Numbers of lines changed compared to the first parent, only given if requested
+	Stats *CommitStats `json:"stats,omitempty"`
+}
+
+// CommitStats is statistics for a RepoCommit
+type CommitStats struct {
+	Total     int `json:"total"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
 }
 
 // CommitDateOptions store dates for GIT_AUTHOR_DATE and GIT_COMMITTER_DATE
@@ -62,3 +73,83 @@ type CommitDateOptions struct {
 type CommitAffectedFiles struct {
 	Filename string `json:"filename"`
 }
+
+// CommitBackportStatus reports which branches contain the change of a commit
+type CommitBackportStatus struct {
+	SHA string `json:"sha"`
+	// branches which contain the commit or a commit with the same patch id
+	Contained []string `json:"contained"`
+	// branches the change still needs to be backported to
+	Missing []string `json:"missing"`
+}
+
+// CreateCommitOption options for creating a commit object from an existing tree
+// Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
+type CreateCommitOption struct {
+	// required: true
+	Message string `json:"message" binding:"Required"`
+	// SHA of the tree of the commit
+	// required: true
+	Tree string `json:"tree" binding:"Required"`
+	// SHAs of the parent commits, in a batch `$<index>` references an earlier commit of the batch
+	Parents   []string          `json:"parents"`
+	Author    Identity          `json:"author"`
+	Committer Identity          `json:"committer"`
+	Dates     CommitDateOptions `json:"dates"`
+	// Add a Signed-off-by trailer by the committer at the end of the commit log message.
+	Signoff bool `json:"signoff"`
+	// Additional trailers like Reviewed-by or Change-Id to add at the end of the commit log message.
+	// Signed-off-by, Co-authored-by and Co-committed-by are reserved.
+	Trailers map[string]string `json:"trailers"`
+}
+
+// CreateCommitsBatchOption options for creating several commit objects at once
+type CreateCommitsBatchOption struct {
+	// commits to create in order, up to 100
+	// required: true
+	Commits []CreateCommitOption `json:"commits" binding:"Required"`
+}
+
+// CommitVerificationDetail represents the verification of the signature of a commit in detail
+type CommitVerificationDetail struct {
+	SHA      string `json:"sha"`
+	Verified bool   `json:"verified"`
+	// whether the signature is suspicious, e.g. a key known to the instance claims it but does not verify it
+	Warning   bool   `json:"warning"`
+	Reason    string `json:"reason"`
+	Signature string `json:"signature"`
+	Payload   string `json:"payload"`
+	// ID of the key which made the signature or is claimed by it
+	KeyID string `json:"key_id"`
+	// fingerprint of the key which verified the signature
+	Fingerprint string `json:"fingerprint"`
+	// the key which verified the signature, its id is 0 for a signing key of the instance
+	Key    *GPGKey      `json:"key"`
+	Signer *PayloadUser `json:"signer"`
+	// swagger:strfmt email
+	SigningEmail string       `json:"signing_email"`
+	Committer    *PayloadUser `json:"committer"`
+	// the trust model of the repository: collaborator, committer or collaboratorcommitter
+	TrustModel string `json:"trust_model"`
+	// whether the repository trusts a verified signature per its trust model: trusted, untrusted or unmatched
+	TrustStatus string `json:"trust_status"`
+}
+
+// ApplyCommitOption options for cherry-picking or reverting a commit onto a branch
+// Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
+type ApplyCommitOption struct {
+	// message (optional) of the new commit. if not supplied, the message of the commit is used for a cherry-pick and a revert message for a revert
+	Message string `json:"message"`
+	// branch (optional) to apply the commit onto. if not given, the default branch is used
+	BranchName string `json:"branch" binding:"GitRefName;MaxSize(100)"`
+	// new_branch (optional) will make a new branch from `branch` for the new commit
+	NewBranchName string            `json:"new_branch" binding:"GitRefName;MaxSize(100)"`
+	Author        Identity          `json:"author"`
+	Committer     Identity          `json:"committer"`
+	Dates         CommitDateOptions `json:"dates"`
+	// Add a Signed-off-by trailer by the committer at the end of the commit log message.
+	Signoff bool `json:"signoff"`
+	// Additional trailers like Reviewed-by or Change-Id to add at the end of the commit log message.
+	// Signed-off-by, Co-authored-by and Co-committed-by are reserved.
+	Trailers map[string]string `json:"trailers"`
+}
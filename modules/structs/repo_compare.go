@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// RenderedDiff represents a diff between two commits with highlighted lines
+type RenderedDiff struct {
+	BaseCommitSHA  string `json:"base_commit_sha"`
+	HeadCommitSHA  string `json:"head_commit_sha"`
+	TotalAdditions int    `json:"total_additions"`
+	TotalDeletions int    `json:"total_deletions"`
+	// true if the diff was truncated because it exceeds the configured limits
+	IsIncomplete bool                `json:"is_incomplete"`
+	Files        []*RenderedDiffFile `json:"files"`
+}
+
+// RenderedDiffFile represents the changes of a file in a RenderedDiff
+type RenderedDiffFile struct {
+	Name    string `json:"name"`
+	OldName string `json:"old_name"`
+	// enum: added,modified,deleted,renamed,copied
+	Status       string `json:"status"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	IsBinary     bool   `json:"is_binary"`
+	IsLFS        bool   `json:"is_lfs"`
+	IsSubmodule  bool   `json:"is_submodule"`
+	IsGenerated  bool   `json:"is_generated"`
+	IsVendored   bool   `json:"is_vendored"`
+	IsIncomplete bool   `json:"is_incomplete"`
+	// name of the diff driver configured for the file in .gitattributes, the hunks are the diff of the text
+	// the file is converted to by the driver if set
+	DiffDriver string `json:"diff_driver"`
+	// the versions of the file if it is an image
+	Image *RenderedDiffImage  `json:"image,omitempty"`
+	Hunks []*RenderedDiffHunk `json:"hunks"`
+}
+
+// RenderedDiffImage represents the versions of an image changed in a RenderedDiffFile
+type RenderedDiffImage struct {
+	// the image in the base version, null if it was added or was no image
+	Before *RenderedDiffImageBlob `json:"before"`
+	// the image in the head version, null if it was deleted or is no image
+	After *RenderedDiffImageBlob `json:"after"`
+	// the ways the versions can be compared, swipe and onion-skin need both versions
+	Modes []string `json:"modes"`
+}
+
+// RenderedDiffImageBlob represents a version of a RenderedDiffImage
+type RenderedDiffImageBlob struct {
+	SHA      string `json:"sha"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type"`
+	// width in pixels, 0 if the format of the image is not supported
+	Width int `json:"width"`
+	// height in pixels, 0 if the format of the image is not supported
+	Height int    `json:"height"`
+	RawURL string `json:"raw_url"`
+}
+
+// RenderedDiffHunk represents a hunk of a RenderedDiffFile
+type RenderedDiffHunk struct {
+	// the hunk header, e.g. `@@ -1,3 +1,4 @@ func main() {`
+	Header string              `json:"header"`
+	Lines  []*RenderedDiffLine `json:"lines"`
+}
+
+// RenderedDiffLine represents a line of a RenderedDiffHunk
+type RenderedDiffLine struct {
+	// enum: context,add,delete
+	Type string `json:"type"`
+	// line number in the base version, 0 for added lines
+	OldLine int `json:"old_line"`
+	// line number in the head version, 0 for deleted lines
+	NewLine int `json:"new_line"`
+	// the raw content of the line without the leading diff marker
+	Content string `json:"content"`
+	// the content as HTML with syntax highlighting classes and, if requested, changed words wrapped in
+	// `<span class="added-code">` or `<span class="removed-code">`
+	HTML string `json:"html"`
+}
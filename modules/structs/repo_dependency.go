@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// Dependency represents a dependency declared by a manifest on the default branch of a repository
+type Dependency struct {
+	// path of the manifest declaring the dependency
+	Manifest string `json:"manifest"`
+	// package ecosystem, one of `go`, `npm`, `pypi` or `composer`
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	// version or version requirement as written in the manifest
+	Version string `json:"version"`
+	// one of `runtime`, `development` or `indirect`
+	Scope string `json:"scope"`
+	// package url of the dependency, without version if the manifest only gives a version requirement
+	PackageURL string `json:"purl"`
+}
+
+// CycloneDXBOM is a software bill of materials in the CycloneDX 1.4 JSON format
+type CycloneDXBOM struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     *CycloneDXMetadata    `json:"metadata"`
+	Components   []*CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXMetadata describes a CycloneDX BOM and the component it is about
+type CycloneDXMetadata struct {
+	// swagger:strfmt date-time
+	Timestamp time.Time           `json:"timestamp"`
+	Tools     []*CycloneDXTool    `json:"tools"`
+	Component *CycloneDXComponent `json:"component"`
+}
+
+// CycloneDXTool is a tool which produced a CycloneDX BOM
+type CycloneDXTool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// CycloneDXComponent is a component of a CycloneDX BOM
+type CycloneDXComponent struct {
+	BOMRef     string `json:"bom-ref,omitempty"`
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	PackageURL string `json:"purl,omitempty"`
+	// one of `required` or `excluded`
+	Scope string `json:"scope,omitempty"`
+}
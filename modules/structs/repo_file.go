@@ -19,6 +19,9 @@ type FileOptions struct {
 	Dates     CommitDateOptions `json:"dates"`
 	// Add a Signed-off-by trailer by the committer at the end of the commit log message.
 	Signoff bool `json:"signoff"`
+	// Additional trailers like Reviewed-by or Change-Id to add at the end of the commit log message.
+	// Signed-off-by, Co-authored-by and Co-committed-by are reserved.
+	Trailers map[string]string `json:"trailers"`
 }
 
 // CreateFileOptions options for creating files
@@ -104,3 +107,119 @@ type FileDeleteResponse struct {
 	Commit       *FileCommitResponse        `json:"commit"`
 	Verification *PayloadCommitVerification `json:"verification"`
 }
+
+// ReadmeResponse contains the README of a repository
+type ReadmeResponse struct {
+	ContentsResponse
+	// HTML rendering of the README, empty if the file is not text or too large to be displayed
+	HTML string `json:"html"`
+}
+
+// PreviewFileEditOptions options for previewing the merge of an edit of a file into a branch
+type PreviewFileEditOptions struct {
+	// branch (optional) the edit is merged into. if not given, the default branch is used
+	BranchName string `json:"branch" binding:"GitRefName;MaxSize(100)"`
+	// base_commit is the commit the edit is based on
+	// required: true
+	BaseCommit string `json:"base_commit" binding:"Required;MaxSize(40)"`
+	// content must be base64 encoded
+	// required: true
+	Content string `json:"content"`
+}
+
+// FileEditConflict is a hunk of a file which was changed both on the branch and by the edit
+type FileEditConflict struct {
+	// line of the conflict in the merged content
+	Line int `json:"line"`
+	// content of the hunk at the base commit
+	Base string `json:"base"`
+	// content of the hunk at the branch tip
+	Head string `json:"head"`
+	// content of the hunk proposed by the edit
+	Proposed string `json:"proposed"`
+}
+
+// FileEditPreview is the result of merging an edit of a file into a branch
+type FileEditPreview struct {
+	BaseCommit string `json:"base_commit"`
+	HeadCommit string `json:"head_commit"`
+	Conflicted bool   `json:"conflicted"`
+	// base64 encoded merged content, conflicts are marked in it. empty for conflicting binary files
+	Content   string              `json:"content"`
+	Conflicts []*FileEditConflict `json:"conflicts"`
+}
+
+// GenerateFilesOptions options for generating files from the bundled templates
+// Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
+type GenerateFilesOptions struct {
+	FileOptions
+	// name of the license template the LICENSE file is generated from
+	License string `json:"license"`
+	// names of the gitignore templates the .gitignore file is generated from
+	Gitignores []string `json:"gitignores"`
+	// year substituted in the license, defaults to the current year
+	Year int `json:"year"`
+	// copyright holder substituted in the license, defaults to the name of the repository owner
+	CopyrightHolder string `json:"copyright_holder" binding:"MaxSize(255)"`
+}
+
+// FileTemplate is a bundled template files can be generated from
+type FileTemplate struct {
+	// enum: license,gitignore
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// FilePreview is the preview of a file in a repository
+type FilePreview struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+	// name of the provider which generated the preview
+	// enum: pdf,stl
+	Provider string `json:"provider"`
+	// base64 encoded PNG image of the file, empty if the provider does not render one
+	Image string `json:"image"`
+	// properties of the file extracted by the provider
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// RenderedFile is a file in a repository rendered by the renderer of its type
+type RenderedFile struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+	// name of the renderer
+	// enum: csv,jupyter
+	Type string `json:"type"`
+	// whether the file exceeds the maximum size of files the renderer renders, it is not rendered then
+	TooLarge bool `json:"too_large"`
+	// sanitized HTML the file is rendered to
+	HTML string `json:"html"`
+	// rows of a CSV file, the first one is its header
+	Rows [][]string `json:"rows,omitempty"`
+	// programming language of the code cells of a Jupyter notebook
+	Language string `json:"language,omitempty"`
+	// cells of a Jupyter notebook
+	Cells []*NotebookCell `json:"cells,omitempty"`
+}
+
+// NotebookCell is a cell of a Jupyter notebook
+type NotebookCell struct {
+	// enum: markdown,code,raw
+	Type           string            `json:"type"`
+	Source         string            `json:"source"`
+	ExecutionCount *int              `json:"execution_count"`
+	Outputs        []*NotebookOutput `json:"outputs"`
+}
+
+// NotebookOutput is an output of a code cell of a Jupyter notebook
+type NotebookOutput struct {
+	// enum: stream,execute_result,display_data,error
+	Type string `json:"type"`
+	// text of a stream or traceback of an error
+	Text string `json:"text"`
+	// representations of a result or displayed data by MIME type, images are base64 encoded
+	Data map[string]string `json:"data"`
+}
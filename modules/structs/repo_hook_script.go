@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// HookScript represents a script run in the pre-receive or post-receive hook of a repository
+type HookScript struct {
+	ID int64 `json:"id"`
+	// full name of the repository of the script
+	Repository string `json:"repository"`
+	Name       string `json:"name"`
+	// git hook the script is run in, `pre-receive` or `post-receive`
+	HookType string `json:"hook_type"`
+	Content  string `json:"content"`
+	Active   bool   `json:"active"`
+	// position of the script among the scripts of its hook
+	Sort    int   `json:"sort"`
+	Creator *User `json:"creator"`
+	// scripts are only run once a site administrator approved them
+	Approved bool  `json:"approved"`
+	Approver *User `json:"approver,omitempty"`
+	// swagger:strfmt date-time
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateHookScriptOption options for creating a hook script
+type CreateHookScriptOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(100)"`
+	// required: true
+	// enum: pre-receive,post-receive
+	HookType string `json:"hook_type" binding:"Required;In(pre-receive,post-receive)"`
+	// the script has to start with a shebang line, e.g. `#!/bin/sh`
+	// required: true
+	Content string `json:"content" binding:"Required"`
+	// defaults to true
+	Active *bool `json:"active"`
+	Sort   int   `json:"sort"`
+}
+
+// EditHookScriptOption options for editing a hook script, changing its content or hook type revokes its approval
+type EditHookScriptOption struct {
+	Name *string `json:"name" binding:"MaxSize(100)"`
+	// enum: pre-receive,post-receive
+	HookType *string `json:"hook_type"`
+	Content  *string `json:"content"`
+	Active   *bool   `json:"active"`
+	Sort     *int    `json:"sort"`
+}
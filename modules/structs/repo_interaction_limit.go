@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// RepoInteractionLimit the temporary limit of the users who may open issues and comment in a repository
+type RepoInteractionLimit struct {
+	// enum: none,collaborators_only,contributors_only
+	Limit string `json:"limit"`
+	// swagger:strfmt date-time
+	Created *time.Time `json:"created_at,omitempty"`
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at,omitempty"`
+}
+
+// SetRepoInteractionLimitOption options for limiting the interactions with a repository
+type SetRepoInteractionLimitOption struct {
+	// collaborators_only allows collaborators and organization members, contributors_only
+	// also allows users who opened issues or commented before
+	// required: true
+	// enum: collaborators_only,contributors_only
+	Limit string `json:"limit" binding:"Required;In(collaborators_only,contributors_only)"`
+	// number of hours the limit applies, at most 4320 (180 days)
+	// required: true
+	DurationHours int `json:"duration_hours" binding:"Required;Range(1,4320)"`
+}
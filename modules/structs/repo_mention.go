@@ -0,0 +1,13 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Mentionables the users and teams which may be mentioned in a repository
+type Mentionables struct {
+	// users ordered by their last interaction with the repository
+	Users []*User `json:"users"`
+	// teams the authenticated user may mention, only for repositories of organizations
+	Teams []*Team `json:"teams"`
+}
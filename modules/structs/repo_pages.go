@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// RepoPages represents the static site published from a branch of a repository
+type RepoPages struct {
+	// branch the site is published from, it is republished on each push to it
+	Branch string `json:"branch"`
+	// directory of the branch which is the root of the site, empty for the root of the branch
+	Directory string `json:"directory"`
+	// domain of its own the site is served at once it is verified
+	CustomDomain string `json:"custom_domain"`
+	// whether the repository proved it controls the custom domain
+	CustomDomainVerified bool `json:"custom_domain_verified"`
+	// name of the TXT record of the custom domain which has to contain the verification code
+	CustomDomainChallengeRecord string `json:"custom_domain_challenge_record,omitempty"`
+	// value of the TXT record which verifies the custom domain
+	CustomDomainVerificationCode string `json:"custom_domain_verification_code,omitempty"`
+	URL                          string `json:"url"`
+	// progress of the latest publish, one of `queued`, `published` or `failed`
+	Status string `json:"status"`
+	// commit the site is served from, empty until it is published the first time
+	CommitID string `json:"commit_id"`
+	Error    string `json:"error,omitempty"`
+	// swagger:strfmt date-time
+	Published *time.Time `json:"published_at,omitempty"`
+}
+
+// CreateOrUpdateRepoPagesOption options when publishing the site of a repository
+type CreateOrUpdateRepoPagesOption struct {
+	// required: true
+	Branch       string `json:"branch" binding:"Required;GitRefName;MaxSize(100)"`
+	Directory    string `json:"directory" binding:"MaxSize(255)"`
+	CustomDomain string `json:"custom_domain" binding:"MaxSize(255)"`
+}
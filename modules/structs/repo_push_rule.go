@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// PushRule represents the rules every push to a repository has to follow
+type PushRule struct {
+	// maximum size in bytes of a pushed file, 0 means unlimited
+	MaxFileSize int64 `json:"max_file_size"`
+	// semicolon separated list of glob patterns of files which can not be pushed
+	ForbiddenFilePatterns string `json:"forbidden_file_patterns"`
+	// domains the author emails of pushed commits must belong to, empty allows all domains
+	AuthorEmailDomains []string `json:"author_email_domains"`
+	// reject force pushes to every branch
+	RejectForcePush bool `json:"reject_force_push"`
+}
+
+// EditPushRuleOption options when changing the push rules of a repository
+type EditPushRuleOption struct {
+	MaxFileSize           *int64   `json:"max_file_size"`
+	ForbiddenFilePatterns *string  `json:"forbidden_file_patterns"`
+	AuthorEmailDomains    []string `json:"author_email_domains"`
+	RejectForcePush       *bool    `json:"reject_force_push"`
+}
+
+// PushCheckRef is an update of a ref checked against the push rules
+type PushCheckRef struct {
+	// full name of the ref, e.g. refs/heads/main
+	// required: true
+	Ref string `json:"ref" binding:"Required"`
+	// commit the ref points to before the update, empty for a new ref
+	OldSHA string `json:"old_sha"`
+	// required: true
+	NewSHA string `json:"new_sha" binding:"Required"`
+}
+
+// PushCheckOption options when checking a push against the push rules without applying it
+type PushCheckOption struct {
+	// required: true
+	Refs []*PushCheckRef `json:"refs" binding:"Required"`
+	// base64 encoded pack with the pushed objects which are not in the repository yet
+	Pack string `json:"pack"`
+}
+
+// PushCheckFile represents a pushed file larger than the maximum file size
+type PushCheckFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// PushCheckRefResult represents whether an update of a ref is allowed by the push rules
+type PushCheckRefResult struct {
+	Ref     string `json:"ref"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+	// files larger than the maximum file size
+	OversizedFiles []*PushCheckFile `json:"oversized_files,omitempty"`
+	// patterns to track the oversized files with Git LFS
+	LFSPatterns []string `json:"lfs_patterns,omitempty"`
+	// git commands to move the oversized files to Git LFS
+	LFSHint string `json:"lfs_hint,omitempty"`
+}
+
+// PushCheckResult represents whether a push is allowed by the push rules
+type PushCheckResult struct {
+	Allowed bool                  `json:"allowed"`
+	Refs    []*PushCheckRefResult `json:"refs"`
+}
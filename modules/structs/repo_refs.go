@@ -17,3 +17,19 @@ type GitObject struct {
 	SHA  string `json:"sha"`
 	URL  string `json:"url"`
 }
+
+// CreateGitRefOption options for creating a branch or tag ref
+type CreateGitRefOption struct {
+	// SHA of the commit the ref points at
+	// required: true
+	SHA string `json:"sha" binding:"Required"`
+}
+
+// UpdateGitRefOption options for updating a branch or tag ref
+type UpdateGitRefOption struct {
+	// SHA of the commit the ref points at
+	// required: true
+	SHA string `json:"sha" binding:"Required"`
+	// allows updates which are not fast-forwards, and moving tags
+	Force bool `json:"force"`
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// RepoSchedule represents a cron expression which fires schedule events of a repository
+type RepoSchedule struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// standard cron expression with five fields or a descriptor like @daily or @every 1h
+	Cron string `json:"cron"`
+	// how runs are handled which were missed while the server was down
+	// enum: none,once,all
+	CatchUp string `json:"catch_up"`
+	Active  bool   `json:"active"`
+	// swagger:strfmt date-time
+	NextRun *time.Time `json:"next_run"`
+	// swagger:strfmt date-time
+	LastRun *time.Time `json:"last_run"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateRepoScheduleOption options when creating a repository schedule
+type CreateRepoScheduleOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;AlphaDashDot;MaxSize(100)"`
+	// required: true
+	Cron string `json:"cron" binding:"Required"`
+	// enum: none,once,all
+	CatchUp string `json:"catch_up"`
+	// default: true
+	Active *bool `json:"active"`
+}
+
+// EditRepoScheduleOption options when editing a repository schedule
+type EditRepoScheduleOption struct {
+	Cron *string `json:"cron"`
+	// enum: none,once,all
+	CatchUp *string `json:"catch_up"`
+	Active  *bool   `json:"active"`
+}
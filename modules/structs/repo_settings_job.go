@@ -0,0 +1,68 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// RepoSettingsPatch are the settings applied to each repository by a bulk update, only the fields that are set are changed
+type RepoSettingsPatch struct {
+	// either `true` to enable the built-in issue tracker or `false` to disable issues
+	HasIssues *bool `json:"has_issues,omitempty"`
+	// either `true` to enable the built-in wiki or `false` to disable the wiki
+	HasWiki *bool `json:"has_wiki,omitempty"`
+	// either `true` to allow pull requests, or `false` to prevent pull requests
+	HasPullRequests *bool `json:"has_pull_requests,omitempty"`
+	// either `true` to enable the project unit, or `false` to disable it
+	HasProjects *bool `json:"has_projects,omitempty"`
+	// the merge settings are only applied to repositories with pull requests enabled
+	AllowMerge       *bool `json:"allow_merge_commits,omitempty"`
+	AllowRebase      *bool `json:"allow_rebase,omitempty"`
+	AllowRebaseMerge *bool `json:"allow_rebase_explicit,omitempty"`
+	AllowSquash      *bool `json:"allow_squash_merge,omitempty"`
+	// set to `true` to delete pr branch after merge by default
+	DefaultDeleteBranchAfterMerge *bool `json:"default_delete_branch_after_merge,omitempty"`
+	// set to a merge style to be used by the repositories: "merge", "rebase", "rebase-merge", or "squash"
+	DefaultMergeStyle *string `json:"default_merge_style,omitempty"`
+	// set this structure to create or replace a branch protection in each repository which has the branch,
+	// the repositories without the branch are skipped
+	BranchProtection *CreateBranchProtectionOption `json:"branch_protection,omitempty"`
+	// name of a branch protection template of the organization to apply to the repositories
+	BranchProtectionTemplate *string `json:"branch_protection_template,omitempty"`
+}
+
+// CreateRepoSettingsJobOption options for updating the settings of many repositories of an organization
+type CreateRepoSettingsJobOption struct {
+	// names of the repositories to update, all the repositories of the organization if empty
+	Repos []string `json:"repos"`
+	// required: true
+	Settings *RepoSettingsPatch `json:"settings" binding:"Required"`
+}
+
+// RepoSettingsJob represents a bulk update of the settings of repositories of an organization
+type RepoSettingsJob struct {
+	ID int64 `json:"id"`
+	// progress of the job, one of `queued`, `running` or `finished`
+	Status   string             `json:"status"`
+	Settings *RepoSettingsPatch `json:"settings"`
+	Doer     *User              `json:"doer"`
+	// the outcome for each repository
+	Results []*RepoSettingsResult `json:"results"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+}
+
+// RepoSettingsResult represents the outcome of a bulk settings update for one repository
+type RepoSettingsResult struct {
+	// name of the repository when the job was created
+	Repository string `json:"repository"`
+	// one of `pending`, `success`, `failed` or `skipped`
+	Status string `json:"status"`
+	// why the update failed or was skipped, or which settings did not apply to the repository
+	Message string `json:"message,omitempty"`
+}
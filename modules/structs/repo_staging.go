@@ -0,0 +1,76 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// StagingSession represents changes of files of a branch which are committed at once
+type StagingSession struct {
+	ID int64 `json:"id"`
+	// branch the changes are based on
+	Branch string `json:"branch"`
+	// commit of the branch when the session was created
+	BaseCommitSHA string          `json:"base_commit_sha"`
+	Changes       []*StagedChange `json:"changes,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// StagedChange represents a change of a file staged in a session
+type StagedChange struct {
+	Path string `json:"path"`
+	// path of the file moved to `path` by an update
+	FromPath string `json:"from_path,omitempty"`
+	// enum: create,update,delete
+	Operation string `json:"operation"`
+	// base64 encoded content of the file, empty for a deletion
+	Content string `json:"content,omitempty"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateStagingSessionOption options when creating a staging session
+type CreateStagingSessionOption struct {
+	// branch (optional) the changes are based on, if not given the default branch is used
+	Branch string `json:"branch" binding:"GitRefName;MaxSize(100)"`
+}
+
+// StageChangeOption options when staging or unstaging the change of a file
+type StageChangeOption struct {
+	// required: true
+	// enum: create,update,delete,unstage
+	Operation string `json:"operation" binding:"Required"`
+	// required: true
+	Path string `json:"path" binding:"Required;MaxSize(500)"`
+	// from_path (optional) is the path of the original file which is moved to `path` by an update
+	FromPath string `json:"from_path" binding:"MaxSize(500)"`
+	// content must be base64 encoded, required for create and update
+	Content string `json:"content"`
+}
+
+// EditStagingSessionOption options when staging changes in a session
+type EditStagingSessionOption struct {
+	// required: true
+	Changes []*StageChangeOption `json:"changes" binding:"Required"`
+}
+
+// CommitStagingSessionOption options when committing the changes of a staging session
+// Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
+type CommitStagingSessionOption struct {
+	// message (optional) for the commit, if not supplied a default message will be used
+	Message string `json:"message"`
+	// new_branch (optional) will make a new branch from the branch of the session for the commit
+	NewBranchName string            `json:"new_branch" binding:"GitRefName;MaxSize(100)"`
+	Author        Identity          `json:"author"`
+	Committer     Identity          `json:"committer"`
+	Dates         CommitDateOptions `json:"dates"`
+	// Add a Signed-off-by trailer by the committer at the end of the commit log message.
+	Signoff bool `json:"signoff"`
+	// Additional trailers like Reviewed-by or Change-Id to add at the end of the commit log message.
+	// Signed-off-by, Co-authored-by and Co-committed-by are reserved.
+	Trailers map[string]string `json:"trailers"`
+}
@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Symbol a definition found by the symbol indexer
+type Symbol struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Language string `json:"language"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	// name of the enclosing definition, e.g. the type of a method
+	Scope     string `json:"scope"`
+	Signature string `json:"signature"`
+	HTMLURL   string `json:"html_url"`
+}
+
+// SymbolReference a line referencing a symbol
+type SymbolReference struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Content string `json:"content"`
+	// whether the line is a definition of the symbol
+	IsDefinition bool   `json:"is_definition"`
+	HTMLURL      string `json:"html_url"`
+}
@@ -23,3 +23,25 @@ type GitTreeResponse struct {
 	Page       int        `json:"page"`
 	TotalCount int        `json:"total_count"`
 }
+
+// CreateGitTreeEntry is an entry of a tree object to create
+type CreateGitTreeEntry struct {
+	// path of the entry, which may contain slashes to write it in a subtree
+	// required: true
+	Path string `json:"path" binding:"Required"`
+	// one of 100644 (file), 100755 (executable), 040000 (subtree), 160000 (submodule) or 120000 (symlink)
+	// required: true
+	Mode string `json:"mode" binding:"Required"`
+	// SHA of the object of the entry, the path is removed from the base tree if both sha and content are null
+	SHA *string `json:"sha"`
+	// content of a new blob of the entry, instead of sha
+	Content *string `json:"content"`
+}
+
+// CreateGitTreeOption options for creating a tree object
+type CreateGitTreeOption struct {
+	// SHA of the tree the entries are merged into, the tree only has the given entries if empty
+	BaseTree string `json:"base_tree"`
+	// required: true
+	Tree []*CreateGitTreeEntry `json:"tree" binding:"Required"`
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// TrendingRepository represents a repository with its activity over a trending period
+type TrendingRepository struct {
+	Repository *Repository `json:"repository"`
+	// the trending period, one of `daily`, `weekly` or `monthly`
+	Period string `json:"period"`
+	// number of stars gained in the period
+	StarsGained int64 `json:"stars_gained"`
+	// number of pull requests opened and merged in the period
+	PullRequests int64 `json:"pull_requests"`
+	Score        int64 `json:"score"`
+	// swagger:strfmt date-time
+	ComputedAt time.Time `json:"computed_at"`
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// SecurityAdvisory represents an advisory of the OSV database affecting a package
+type SecurityAdvisory struct {
+	// id of the advisory in the OSV database
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+	Summary string   `json:"summary"`
+	// one of `low`, `moderate`, `high` or `critical`, empty if unknown
+	Severity string `json:"severity"`
+	URL      string `json:"url"`
+	// swagger:strfmt date-time
+	Published time.Time `json:"published_at"`
+	// swagger:strfmt date-time
+	Modified time.Time `json:"modified_at"`
+}
+
+// VulnerabilityAlert represents a dependency of a repository on a version affected by a security advisory
+type VulnerabilityAlert struct {
+	ID int64 `json:"id"`
+	// enum: open,dismissed,fixed
+	State    string            `json:"state"`
+	Advisory *SecurityAdvisory `json:"advisory"`
+	// path of the manifest declaring the dependency
+	Manifest  string `json:"manifest"`
+	Ecosystem string `json:"ecosystem"`
+	Package   string `json:"package"`
+	// version or version requirement as written in the manifest
+	Version string `json:"version"`
+	// lowest version which is not affected, empty if no fix is known
+	FixedVersion     string `json:"fixed_version"`
+	DismissedReason  string `json:"dismissed_reason,omitempty"`
+	DismissedComment string `json:"dismissed_comment,omitempty"`
+	DismissedBy      *User  `json:"dismissed_by,omitempty"`
+	// swagger:strfmt date-time
+	Dismissed *time.Time `json:"dismissed_at,omitempty"`
+	// swagger:strfmt date-time
+	Fixed *time.Time `json:"fixed_at,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditVulnerabilityAlertOption options when dismissing or reopening a vulnerability alert
+type EditVulnerabilityAlertOption struct {
+	// required: true
+	// enum: open,dismissed
+	State string `json:"state" binding:"Required"`
+	// required if the state is `dismissed`
+	// enum: fix_started,inaccurate,no_bandwidth,not_used,tolerable_risk
+	DismissedReason  string `json:"dismissed_reason"`
+	DismissedComment string `json:"dismissed_comment" binding:"MaxSize(280)"`
+}
+
+// VulnerabilityAlertSettings represents whether a repository is alerted about vulnerable dependencies
+type VulnerabilityAlertSettings struct {
+	Enabled bool `json:"enabled"`
+	// open an issue for each new alert instead of mailing the repository owners
+	OpenIssues bool `json:"open_issues"`
+	// swagger:strfmt date-time
+	LastChecked *time.Time `json:"last_checked,omitempty"`
+}
+
+// EditVulnerabilityAlertSettingsOption options when changing whether a repository is alerted about vulnerable dependencies
+type EditVulnerabilityAlertSettingsOption struct {
+	Enabled    *bool `json:"enabled"`
+	OpenIssues *bool `json:"open_issues"`
+}
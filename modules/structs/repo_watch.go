@@ -16,4 +16,18 @@ type WatchInfo struct {
 	CreatedAt     time.Time   `json:"created_at"`
 	URL           string      `json:"url"`
 	RepositoryURL string      `json:"repository_url"`
+	// which events are delivered, one of `all`, `participating` or `custom`
+	Level string `json:"level"`
+	// events delivered by a custom subscription
+	Events []string `json:"events"`
+}
+
+// EditSubscriptionOption options when changing the level of a subscription
+type EditSubscriptionOption struct {
+	// `all` activity, only mentions and activity on issues you are `participating` in or only the selected `custom` events
+	//
+	// required: true
+	Level string `json:"level" binding:"Required;In(all,participating,custom)"`
+	// events of a `custom` subscription, any of `issue`, `comment`, `state_change`, `push` and `mention`
+	Events []string `json:"events"`
 }
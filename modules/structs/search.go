@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// SearchType is a kind of object an instance-wide search can find
+type SearchType string
+
+const (
+	// SearchTypeRepo finds repositories
+	SearchTypeRepo SearchType = "repo"
+	// SearchTypeIssue finds issues and pull requests
+	SearchTypeIssue SearchType = "issue"
+	// SearchTypeUser finds users
+	SearchTypeUser SearchType = "user"
+	// SearchTypeCode finds files of repositories, requires the code indexer
+	SearchTypeCode SearchType = "code"
+)
+
+// SearchTypes are all the kinds of objects an instance-wide search can find
+var SearchTypes = []SearchType{SearchTypeRepo, SearchTypeIssue, SearchTypeUser, SearchTypeCode}
+
+// UnifiedSearchResults results of an instance-wide search grouped by type,
+// the groups of types which were not searched are empty
+type UnifiedSearchResults struct {
+	Repositories []*Repository       `json:"repositories"`
+	Issues       []*Issue            `json:"issues"`
+	Users        []*User             `json:"users"`
+	Code         []*CodeSearchResult `json:"code"`
+	Facets       *SearchFacets       `json:"facets"`
+}
+
+// SearchFacets total number of matches of each type of an instance-wide search
+type SearchFacets struct {
+	Repositories int64 `json:"repositories"`
+	Issues       int64 `json:"issues"`
+	Users        int64 `json:"users"`
+	Code         int64 `json:"code"`
+	// number of matching files of each language
+	CodeLanguages []*CodeSearchLanguage `json:"code_languages"`
+}
@@ -27,6 +27,7 @@ type GeneralAPISettings struct {
 	DefaultPagingNum       int   `json:"default_paging_num"`
 	DefaultGitTreesPerPage int   `json:"default_git_trees_per_page"`
 	DefaultMaxBlobSize     int64 `json:"default_max_blob_size"`
+	MaxBlobUploadSize      int64 `json:"max_blob_upload_size"`
 }
 
 // GeneralAttachmentSettings contains global Attachment settings exposed by API
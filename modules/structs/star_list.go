@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// StarList represents a named collection of starred repositories
+type StarList struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Private     bool   `json:"private"`
+	// the default list holds all repositories starred by the user
+	IsDefault bool  `json:"is_default"`
+	NumRepos  int64 `json:"repos_count"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateStarListOption options for creating a star list
+type CreateStarListOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(255)"`
+	Description string `json:"description"`
+	// whether the list is only visible to its owner
+	Private bool `json:"private"`
+}
+
+// EditStarListOption options for editing a star list
+type EditStarListOption struct {
+	Name        *string `json:"name" binding:"MaxSize(255)"`
+	Description *string `json:"description"`
+	// whether the list is only visible to its owner
+	Private *bool `json:"private"`
+}
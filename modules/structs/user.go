@@ -75,6 +75,13 @@ type UserSettings struct {
 	// Privacy
 	HideEmail    bool `json:"hide_email"`
 	HideActivity bool `json:"hide_activity"`
+	// Watching
+	// whether the user starts watching the repositories they push to: always, never
+	// or empty for the default of the instance
+	AutoWatchOnPush string `json:"auto_watch_on_push"`
+	// whether the user starts watching the issues they are mentioned in: always, never, on_reaction
+	// to start watching once the user reacts to the issue or one of its comments, or empty for never
+	AutoWatchOnMention string `json:"auto_watch_on_mention"`
 }
 
 // UserSettingsOptions represents options to change user settings
@@ -90,4 +97,7 @@ type UserSettingsOptions struct {
 	// Privacy
 	HideEmail    *bool `json:"hide_email"`
 	HideActivity *bool `json:"hide_activity"`
+	// Watching
+	AutoWatchOnPush    *string `json:"auto_watch_on_push"`
+	AutoWatchOnMention *string `json:"auto_watch_on_mention"`
 }
@@ -0,0 +1,14 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// ProfileReadme represents the README of the `.profile` repository shown on the profile page of a user or an organization
+type ProfileReadme struct {
+	// full name of the repository the README is read from
+	Repository string `json:"repository"`
+	HTMLURL    string `json:"html_url"`
+	// HTML rendering of the README, empty if the file is not text or too large to be displayed
+	HTML string `json:"html"`
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// WorkflowRun represents a run of a workflow
+type WorkflowRun struct {
+	ID                int64  `json:"id"`
+	WorkflowID        string `json:"workflow_id"`
+	Name              string `json:"name"`
+	Event             string `json:"event"`
+	Ref               string `json:"ref"`
+	CommitSHA         string `json:"commit_sha"`
+	IsForkPullRequest bool   `json:"is_fork_pull_request"`
+	TriggerUser       *User  `json:"trigger_user"`
+	// enum: waiting,pending,running,success,failure,cancelled,skipped
+	Status string `json:"status"`
+	URL    string `json:"url"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	Stopped *time.Time `json:"stopped_at"`
+	// swagger:strfmt date-time
+	Created time.Time      `json:"created_at"`
+	Jobs    []*WorkflowJob `json:"jobs,omitempty"`
+}
+
+// WorkflowJob represents a job of a workflow run
+type WorkflowJob struct {
+	ID     int64    `json:"id"`
+	JobID  string   `json:"job_id"`
+	Name   string   `json:"name"`
+	RunsOn []string `json:"runs_on"`
+	Needs  []string `json:"needs"`
+	// enum: waiting,pending,running,success,failure,cancelled,skipped
+	Status   string `json:"status"`
+	RunnerID int64  `json:"runner_id"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	Stopped *time.Time `json:"stopped_at"`
+}
+
+// WorkflowRunner represents a runner which executes workflow jobs
+type WorkflowRunner struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Labels []string `json:"labels"`
+	// the token is only returned when the runner is created
+	Token          string `json:"token,omitempty"`
+	TokenLastEight string `json:"token_last_eight"`
+	// swagger:strfmt date-time
+	LastOnline *time.Time `json:"last_online_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateWorkflowRunnerOption options when registering a runner
+type CreateWorkflowRunnerOption struct {
+	// required: true
+	Name   string   `json:"name" binding:"Required;MaxSize(255)"`
+	Labels []string `json:"labels"`
+}
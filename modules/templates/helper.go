@@ -183,6 +183,10 @@ func NewFuncMap() []template.FuncMap {
 			mimeType := mime.TypeByExtension(filepath.Ext(filename))
 			return strings.HasPrefix(mimeType, "image/")
 		},
+		"FilenameIsVideo": func(filename string) bool {
+			mimeType := mime.TypeByExtension(filepath.Ext(filename))
+			return strings.HasPrefix(mimeType, "video/")
+		},
 		"TabSizeClass": func(ec interface{}, filename string) string {
 			var (
 				value *editorconfig.Editorconfig
@@ -56,6 +56,11 @@ func (ct SniffedType) IsAudio() bool {
 	return strings.Contains(ct.contentType, "audio/")
 }
 
+// GetMimeType returns the mime type without parameters like the charset
+func (ct SniffedType) GetMimeType() string {
+	return strings.TrimSpace(strings.SplitN(ct.contentType, ";", 2)[0])
+}
+
 // IsRepresentableAsText returns true if file content can be represented as
 // plain text or is empty.
 func (ct SniffedType) IsRepresentableAsText() bool {
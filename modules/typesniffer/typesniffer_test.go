@@ -83,6 +83,12 @@ func TestIsVideo(t *testing.T) {
 	assert.False(t, DetectContentType([]byte("plain text")).IsVideo())
 }
 
+func TestGetMimeType(t *testing.T) {
+	mp4, _ := base64.StdEncoding.DecodeString("AAAAGGZ0eXBtcDQyAAAAAGlzb21tcDQyAAEI721vb3YAAABsbXZoZAAAAADaBlwX2gZcFwAAA+gA")
+	assert.Equal(t, "video/mp4", DetectContentType(mp4).GetMimeType())
+	assert.Equal(t, "text/plain", DetectContentType([]byte("plain text")).GetMimeType())
+}
+
 func TestIsAudio(t *testing.T) {
 	mp3, _ := base64.StdEncoding.DecodeString("SUQzBAAAAAABAFRYWFgAAAASAAADbWFqb3JfYnJhbmQAbXA0MgBUWFhYAAAAEQAAA21pbm9yX3Zl")
 	assert.True(t, DetectContentType(mp3).IsAudio())
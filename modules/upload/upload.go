@@ -5,6 +5,8 @@
 package upload
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"path"
 	"regexp"
@@ -30,6 +32,42 @@ func (err ErrFileTypeForbidden) Error() string {
 	return "This file extension or type is not allowed to be uploaded."
 }
 
+// ErrFileTooLarge file too large error
+type ErrFileTooLarge struct {
+	MaxSize int64
+}
+
+// IsErrFileTooLarge checks if an error is a ErrFileTooLarge.
+func IsErrFileTooLarge(err error) bool {
+	_, ok := err.(ErrFileTooLarge)
+	return ok
+}
+
+func (err ErrFileTooLarge) Error() string {
+	return fmt.Sprintf("This file exceeds the maximum size of %d MB.", err.MaxSize)
+}
+
+// LimitedReader reads from R until more than MaxSize MB are read, then it fails with ErrFileTooLarge
+type LimitedReader struct {
+	R       io.Reader
+	MaxSize int64
+	read    int64
+}
+
+func (r *LimitedReader) Read(p []byte) (int, error) {
+	n, err := r.R.Read(p)
+	r.read += int64(n)
+	if r.read > r.MaxSize<<20 {
+		return n, ErrFileTooLarge{MaxSize: r.MaxSize}
+	}
+	return n, err
+}
+
+// Exceeded returns whether more than MaxSize MB were read
+func (r *LimitedReader) Exceeded() bool {
+	return r.read > r.MaxSize<<20
+}
+
 var mimeTypeSuffixRe = regexp.MustCompile(`;.*$`)
 var wildcardTypeRe = regexp.MustCompile(`^[a-z]+/\*$`)
 
@@ -79,6 +117,7 @@ func AddUploadContext(ctx *context.Context, uploadType string) {
 		ctx.Data["UploadAccepts"] = strings.ReplaceAll(setting.Repository.Release.AllowedTypes, "|", ",")
 		ctx.Data["UploadMaxFiles"] = setting.Attachment.MaxFiles
 		ctx.Data["UploadMaxSize"] = setting.Attachment.MaxSize
+		ctx.Data["UploadVideoMaxSize"] = setting.Attachment.VideoMaxSize
 	} else if uploadType == "comment" {
 		ctx.Data["UploadUrl"] = ctx.Repo.RepoLink + "/issues/attachments"
 		ctx.Data["UploadRemoveUrl"] = ctx.Repo.RepoLink + "/issues/attachments/remove"
@@ -90,6 +129,7 @@ func AddUploadContext(ctx *context.Context, uploadType string) {
 		ctx.Data["UploadAccepts"] = strings.ReplaceAll(setting.Attachment.AllowedTypes, "|", ",")
 		ctx.Data["UploadMaxFiles"] = setting.Attachment.MaxFiles
 		ctx.Data["UploadMaxSize"] = setting.Attachment.MaxSize
+		ctx.Data["UploadVideoMaxSize"] = setting.Attachment.VideoMaxSize
 	} else if uploadType == "repo" {
 		ctx.Data["UploadUrl"] = ctx.Repo.RepoLink + "/upload-file"
 		ctx.Data["UploadRemoveUrl"] = ctx.Repo.RepoLink + "/upload-remove"
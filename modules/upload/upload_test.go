@@ -7,6 +7,7 @@ package upload
 import (
 	"bytes"
 	"compress/gzip"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -193,3 +194,15 @@ func TestUpload(t *testing.T) {
 		assert.Equal(t, kase.err, Verify(kase.data, kase.fileName, kase.allowedTypes))
 	}
 }
+
+func TestLimitedReader(t *testing.T) {
+	r := &LimitedReader{R: bytes.NewReader(make([]byte, 1<<20)), MaxSize: 1}
+	_, err := io.Copy(io.Discard, r)
+	assert.NoError(t, err)
+	assert.False(t, r.Exceeded())
+
+	r = &LimitedReader{R: bytes.NewReader(make([]byte, 1<<20+1)), MaxSize: 1}
+	_, err = io.Copy(io.Discard, r)
+	assert.True(t, IsErrFileTooLarge(err))
+	assert.True(t, r.Exceeded())
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package util
+
+import "net"
+
+// IsIPPrivate returns whether an IP is in one of the private address ranges
+// TODO: replace with `ip.IsPrivate()` if min go version is bumped to 1.17
+func IsIPPrivate(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4[0] == 10 ||
+			(ip4[0] == 172 && ip4[1]&0xf0 == 16) ||
+			(ip4[0] == 192 && ip4[1] == 168)
+	}
+	return len(ip) == net.IPv6len && ip[0]&0xfe == 0xfc
+}
+
+// IsIPLocalNetwork returns whether an IP is a loopback, private, link-local or unspecified address,
+// or any other address which is not a global unicast address
+func IsIPLocalNetwork(ip net.IP) bool {
+	return IsIPPrivate(ip) || !ip.IsGlobalUnicast()
+}
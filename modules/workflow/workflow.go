@@ -0,0 +1,243 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"gopkg.in/yaml.v2"
+)
+
+// Dir is the directory of a repository which contains the workflow files
+const Dir = ".gitea/workflows"
+
+// Events which can trigger a workflow
+const (
+	EventPush        = "push"
+	EventPullRequest = "pull_request"
+)
+
+// ErrInvalidWorkflow indicates an invalid workflow definition
+var ErrInvalidWorkflow = errors.New("Invalid workflow")
+
+// Workflow represents a parsed workflow file
+type Workflow struct {
+	Name string          `yaml:"name"`
+	On   Triggers        `yaml:"on"`
+	Env  Env             `yaml:"env"`
+	Jobs map[string]*Job `yaml:"jobs"`
+}
+
+// Triggers maps event names to their filters
+type Triggers map[string]*Trigger
+
+// Trigger restricts the refs an event triggers the workflow for
+type Trigger struct {
+	Branches       []string `yaml:"branches" json:"branches,omitempty"`
+	BranchesIgnore []string `yaml:"branches-ignore" json:"branches_ignore,omitempty"`
+	Tags           []string `yaml:"tags" json:"tags,omitempty"`
+	TagsIgnore     []string `yaml:"tags-ignore" json:"tags_ignore,omitempty"`
+}
+
+// Env holds environment variables
+type Env map[string]string
+
+// Job represents a job of a workflow
+type Job struct {
+	Name   string      `yaml:"name" json:"name"`
+	RunsOn StringSlice `yaml:"runs-on" json:"runs_on"`
+	Needs  StringSlice `yaml:"needs" json:"needs,omitempty"`
+	Env    Env         `yaml:"env" json:"env,omitempty"`
+	Steps  []*Step     `yaml:"steps" json:"steps"`
+}
+
+// Step represents a single step of a job
+type Step struct {
+	Name             string            `yaml:"name" json:"name,omitempty"`
+	Uses             string            `yaml:"uses" json:"uses,omitempty"`
+	With             map[string]string `yaml:"with" json:"with,omitempty"`
+	Run              string            `yaml:"run" json:"run,omitempty"`
+	Shell            string            `yaml:"shell" json:"shell,omitempty"`
+	WorkingDirectory string            `yaml:"working-directory" json:"working_directory,omitempty"`
+	Env              Env               `yaml:"env" json:"env,omitempty"`
+}
+
+// StringSlice accepts a single string or a list of strings
+type StringSlice []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (s *StringSlice) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*s = StringSlice{single}
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler and accepts an event name, a list of event names
+// or a mapping of event names to filters
+func (t *Triggers) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var events StringSlice
+	if err := unmarshal(&events); err == nil {
+		*t = make(Triggers, len(events))
+		for _, event := range events {
+			(*t)[event] = &Trigger{}
+		}
+		return nil
+	}
+	var mapping map[string]*Trigger
+	if err := unmarshal(&mapping); err != nil {
+		return err
+	}
+	for event, trigger := range mapping {
+		if trigger == nil {
+			mapping[event] = &Trigger{}
+		}
+	}
+	*t = mapping
+	return nil
+}
+
+// Parse parses and validates the content of a workflow file
+func Parse(content []byte) (*Workflow, error) {
+	w := &Workflow{}
+	if err := yaml.Unmarshal(content, w); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkflow, err)
+	}
+
+	if len(w.On) == 0 {
+		return nil, fmt.Errorf("%w: no trigger defined", ErrInvalidWorkflow)
+	}
+	if len(w.Jobs) == 0 {
+		return nil, fmt.Errorf("%w: no jobs defined", ErrInvalidWorkflow)
+	}
+	for id, job := range w.Jobs {
+		if job == nil || len(job.Steps) == 0 {
+			return nil, fmt.Errorf("%w: job %s has no steps", ErrInvalidWorkflow, id)
+		}
+		for i, step := range job.Steps {
+			if step == nil || (step.Run == "") == (step.Uses == "") {
+				return nil, fmt.Errorf("%w: step %d of job %s must define either run or uses", ErrInvalidWorkflow, i+1, id)
+			}
+		}
+		for _, need := range job.Needs {
+			if _, ok := w.Jobs[need]; !ok {
+				return nil, fmt.Errorf("%w: job %s needs unknown job %s", ErrInvalidWorkflow, id, need)
+			}
+		}
+		if job.Name == "" {
+			job.Name = id
+		}
+	}
+	if err := checkCycles(w.Jobs); err != nil {
+		return nil, err
+	}
+
+	for _, trigger := range w.On {
+		for _, patterns := range [][]string{trigger.Branches, trigger.BranchesIgnore, trigger.Tags, trigger.TagsIgnore} {
+			for _, pattern := range patterns {
+				if _, err := glob.Compile(pattern, '/'); err != nil {
+					return nil, fmt.Errorf("%w: invalid pattern %s: %v", ErrInvalidWorkflow, pattern, err)
+				}
+			}
+		}
+	}
+
+	return w, nil
+}
+
+func checkCycles(jobs map[string]*Job) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(jobs))
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("%w: circular dependency of job %s", ErrInvalidWorkflow, id)
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		for _, need := range jobs[id].Needs {
+			if err := visit(need); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		return nil
+	}
+	for _, id := range SortedJobIDs(jobs) {
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortedJobIDs returns the job ids in a stable order
+func SortedJobIDs(jobs map[string]*Job) []string {
+	ids := make([]string, 0, len(jobs))
+	for id := range jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// IsWorkflowFile checks if the path is a workflow file
+func IsWorkflowFile(p string) bool {
+	ext := path.Ext(p)
+	return path.Dir(p) == Dir && (ext == ".yml" || ext == ".yaml")
+}
+
+// Matches checks if the workflow is triggered by the event for the ref
+func (w *Workflow) Matches(event, refName string) bool {
+	trigger, ok := w.On[event]
+	if !ok {
+		return false
+	}
+
+	if strings.HasPrefix(refName, "refs/tags/") {
+		if len(trigger.Branches) > 0 && len(trigger.Tags) == 0 {
+			return false
+		}
+		return matchesPatterns(strings.TrimPrefix(refName, "refs/tags/"), trigger.Tags, trigger.TagsIgnore)
+	}
+	if len(trigger.Tags) > 0 && len(trigger.Branches) == 0 {
+		return false
+	}
+	return matchesPatterns(strings.TrimPrefix(refName, "refs/heads/"), trigger.Branches, trigger.BranchesIgnore)
+}
+
+func matchesPatterns(name string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if g, err := glob.Compile(pattern, '/'); err == nil && g.Match(name) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if g, err := glob.Compile(pattern, '/'); err == nil && g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		w, err := Parse([]byte(`name: CI
+on:
+  push:
+    branches: [main, 'release/*']
+  pull_request:
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+      - run: make build
+  test:
+    name: Unit tests
+    runs-on: [linux, x64]
+    needs: build
+    steps:
+      - run: make test
+`))
+		assert.NoError(t, err)
+		assert.Equal(t, "CI", w.Name)
+		assert.Len(t, w.On, 2)
+		assert.Len(t, w.Jobs, 2)
+		assert.Equal(t, "build", w.Jobs["build"].Name)
+		assert.Equal(t, StringSlice{"ubuntu-latest"}, w.Jobs["build"].RunsOn)
+		assert.Equal(t, "Unit tests", w.Jobs["test"].Name)
+		assert.Equal(t, StringSlice{"linux", "x64"}, w.Jobs["test"].RunsOn)
+		assert.Equal(t, StringSlice{"build"}, w.Jobs["test"].Needs)
+
+		assert.True(t, w.Matches(EventPush, "refs/heads/main"))
+		assert.True(t, w.Matches(EventPush, "refs/heads/release/1.0"))
+		assert.False(t, w.Matches(EventPush, "refs/heads/feature"))
+		assert.False(t, w.Matches(EventPush, "refs/tags/v1.0"))
+		assert.True(t, w.Matches(EventPullRequest, "refs/heads/feature"))
+	})
+
+	t.Run("EventList", func(t *testing.T) {
+		w, err := Parse([]byte(`on: [push, pull_request]
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make
+`))
+		assert.NoError(t, err)
+		assert.True(t, w.Matches(EventPush, "refs/tags/v1.0"))
+		assert.True(t, w.Matches(EventPullRequest, "refs/heads/main"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cases := []string{
+			`jobs: {build: {steps: [{run: make}]}}`,
+			`on: push`,
+			`on: push
+jobs: {build: {steps: []}}`,
+			`on: push
+jobs: {build: {steps: [{run: make, uses: actions/checkout@v2}]}}`,
+			`on: push
+jobs: {build: {needs: missing, steps: [{run: make}]}}`,
+			`on: push
+jobs: {a: {needs: b, steps: [{run: make}]}, b: {needs: a, steps: [{run: make}]}}`,
+		}
+		for _, c := range cases {
+			_, err := Parse([]byte(c))
+			assert.True(t, errors.Is(err, ErrInvalidWorkflow), c)
+		}
+	})
+}
+
+func TestIsWorkflowFile(t *testing.T) {
+	assert.True(t, IsWorkflowFile(".gitea/workflows/ci.yml"))
+	assert.True(t, IsWorkflowFile(".gitea/workflows/ci.yaml"))
+	assert.False(t, IsWorkflowFile(".gitea/workflows/sub/ci.yml"))
+	assert.False(t, IsWorkflowFile(".gitea/ci.yml"))
+	assert.False(t, IsWorkflowFile(".gitea/workflows/README.md"))
+}
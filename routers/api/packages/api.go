@@ -0,0 +1,200 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/packages/container"
+	"code.gitea.io/gitea/routers/api/packages/generic"
+	"code.gitea.io/gitea/routers/api/packages/npm"
+	"code.gitea.io/gitea/routers/api/packages/pypi"
+	"code.gitea.io/gitea/services/auth"
+)
+
+func reqPackageAccess(accessMode models.AccessMode) func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if ctx.Package.AccessMode < accessMode {
+			ctx.Resp.Header().Set("WWW-Authenticate", `Basic realm="Gitea Package API"`)
+			ctx.Error(http.StatusUnauthorized, "reqPackageAccess", "user should have specific permission or be a site admin")
+			return
+		}
+	}
+}
+
+// Routes registers the package registry routes, mounted under /api/packages
+func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
+	r := web.NewRoute()
+
+	r.Use(sessioner)
+	r.Use(context.APIContexter())
+	r.Use(context.APIAuth(auth.NewGroup(auth.Methods()...)))
+
+	r.Group("/{username}", func() {
+		r.Group("/generic", func() {
+			r.Group("/{packagename}/{packageversion}", func() {
+				r.Get("", generic.GetPackageVersion)
+				r.Put("", reqPackageAccess(models.AccessModeWrite), generic.UpdatePackageVersionMetadata)
+				r.Delete("", reqPackageAccess(models.AccessModeWrite), generic.DeletePackageVersion)
+				r.Get("/{filename}", generic.DownloadPackageFile)
+				r.Put("/{filename}", reqPackageAccess(models.AccessModeWrite), generic.UploadPackageFile)
+			})
+		})
+		r.Group("/npm", func() {
+			r.Group("/@{scope}/{id}", func() {
+				r.Get("", npm.PackageMetadata)
+				r.Put("", reqPackageAccess(models.AccessModeWrite), npm.UploadPackage)
+				r.Get("/-/{version}/{filename}", npm.DownloadPackageFile)
+			})
+			r.Group("/{id}", func() {
+				r.Get("", npm.PackageMetadata)
+				r.Put("", reqPackageAccess(models.AccessModeWrite), npm.UploadPackage)
+				r.Get("/-/{version}/{filename}", npm.DownloadPackageFile)
+			})
+		})
+		r.Group("/pypi", func() {
+			r.Post("", reqPackageAccess(models.AccessModeWrite), pypi.UploadPackageFile)
+			r.Get("/files/{id}/{version}/{filename}", pypi.DownloadPackageFile)
+			r.Get("/simple/{id}", pypi.PackageMetadata)
+		})
+	}, context.PackageAssignmentAPI(), reqPackageAccess(models.AccessModeRead))
+
+	return r
+}
+
+// containerPathRe splits a registry path into owner, image name and endpoint
+var containerPathRe = regexp.MustCompile(`^/([^/]+)/((?:[^/]+/)*?[^/]+)/(blobs/uploads(?:/([^/]*))?|blobs/([^/]+)|manifests/([^/]+)|tags/list)$`)
+
+// ContainerRoutes registers the OCI distribution API routes, mounted under /v2
+// https://github.com/opencontainers/distribution-spec/blob/main/spec.md
+func ContainerRoutes(sessioner func(http.Handler) http.Handler) *web.Route {
+	r := web.NewRoute()
+
+	r.Use(sessioner)
+	r.Use(context.APIContexter())
+	r.Use(context.APIAuth(auth.NewGroup(auth.Methods()...)))
+	r.Use(container.SetDistributionHeader)
+
+	r.Get("", container.CheckAuthenticated)
+	r.Get("/", container.CheckAuthenticated)
+	r.Any("/*", dispatchContainerRequest)
+
+	return r
+}
+
+// dispatchContainerRequest routes the request manually because image names may contain slashes
+func dispatchContainerRequest(ctx *context.APIContext) {
+	m := containerPathRe.FindStringSubmatch(strings.TrimPrefix(ctx.Req.URL.Path, "/v2"))
+	if m == nil {
+		ctx.NotFound()
+		return
+	}
+
+	image := strings.ToLower(m[2])
+	if !container_module.IsValidImageName(image) {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{
+			"errors": []map[string]string{{"code": "NAME_INVALID", "message": "invalid image name"}},
+		})
+		return
+	}
+
+	ctx.SetParams("username", m[1])
+	ctx.SetParams("image", image)
+	context.PackageAssignmentAPI()(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := applyContainerRepositoryAccess(ctx, image); err != nil {
+		ctx.Error(http.StatusInternalServerError, "applyContainerRepositoryAccess", err)
+		return
+	}
+
+	method := ctx.Req.Method
+	accessMode := models.AccessModeRead
+	if method != http.MethodGet && method != http.MethodHead {
+		accessMode = models.AccessModeWrite
+	}
+	if !container.ReqContainerAccess(ctx, accessMode) {
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(m[3], "blobs/uploads"):
+		ctx.SetParams("uuid", m[4])
+		switch {
+		case m[4] == "" && method == http.MethodPost:
+			container.InitiateUploadBlob(ctx)
+		case m[4] != "" && method == http.MethodGet:
+			container.GetUploadBlob(ctx)
+		case m[4] != "" && method == http.MethodPatch:
+			container.UploadBlob(ctx)
+		case m[4] != "" && method == http.MethodPut:
+			container.EndUploadBlob(ctx)
+		case m[4] != "" && method == http.MethodDelete:
+			container.CancelUploadBlob(ctx)
+		default:
+			ctx.Status(http.StatusMethodNotAllowed)
+		}
+	case strings.HasPrefix(m[3], "blobs/"):
+		ctx.SetParams("digest", m[5])
+		switch method {
+		case http.MethodHead:
+			container.HeadBlob(ctx)
+		case http.MethodGet:
+			container.GetBlob(ctx)
+		default:
+			ctx.Status(http.StatusMethodNotAllowed)
+		}
+	case strings.HasPrefix(m[3], "manifests/"):
+		ctx.SetParams("reference", m[6])
+		switch method {
+		case http.MethodHead:
+			container.HeadManifest(ctx)
+		case http.MethodGet:
+			container.GetManifest(ctx)
+		case http.MethodPut:
+			container.UploadManifest(ctx)
+		case http.MethodDelete:
+			container.DeleteManifest(ctx)
+		default:
+			ctx.Status(http.StatusMethodNotAllowed)
+		}
+	default:
+		if method != http.MethodGet {
+			ctx.Status(http.StatusMethodNotAllowed)
+			return
+		}
+		container.GetTagList(ctx)
+	}
+}
+
+// applyContainerRepositoryAccess links an image to the repository named like the first
+// segment of the image. If such a repository exists, its permissions apply to the image.
+func applyContainerRepositoryAccess(ctx *context.APIContext, image string) error {
+	if ctx.Package.AccessMode >= models.AccessModeWrite {
+		return nil
+	}
+
+	repo, err := models.GetRepositoryByName(ctx.Package.Owner.ID, strings.SplitN(image, "/", 2)[0])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	perm, err := models.GetUserRepoPermission(repo, ctx.User)
+	if err != nil {
+		return err
+	}
+	ctx.Package.AccessMode = perm.UnitAccessMode(models.UnitTypeCode)
+	return nil
+}
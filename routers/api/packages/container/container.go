@@ -0,0 +1,501 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+	"code.gitea.io/gitea/modules/setting"
+	container_service "code.gitea.io/gitea/services/packages/container"
+)
+
+// maxManifestSize limits the size of an uploaded manifest
+const maxManifestSize = 4 * 1024 * 1024
+
+// Error codes defined by the distribution specification
+const (
+	errCodeBlobUnknown         = "BLOB_UNKNOWN"
+	errCodeBlobUploadInvalid   = "BLOB_UPLOAD_INVALID"
+	errCodeBlobUploadUnknown   = "BLOB_UPLOAD_UNKNOWN"
+	errCodeDigestInvalid       = "DIGEST_INVALID"
+	errCodeManifestBlobUnknown = "MANIFEST_BLOB_UNKNOWN"
+	errCodeManifestInvalid     = "MANIFEST_INVALID"
+	errCodeManifestUnknown     = "MANIFEST_UNKNOWN"
+	errCodeNameUnknown         = "NAME_UNKNOWN"
+	errCodeSizeInvalid         = "SIZE_INVALID"
+	errCodeUnauthorized        = "UNAUTHORIZED"
+	errCodeDenied              = "DENIED"
+	errCodeUnknown             = "UNKNOWN"
+)
+
+type apiErrorEntry struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func apiError(ctx *context.APIContext, status int, code string, obj interface{}) {
+	message := ""
+	switch m := obj.(type) {
+	case error:
+		message = m.Error()
+	case string:
+		message = m
+	}
+	if status == http.StatusInternalServerError {
+		log.Error("container: %s", message)
+	}
+	ctx.JSON(status, map[string][]apiErrorEntry{
+		"errors": {{Code: code, Message: message}},
+	})
+}
+
+// apiUnauthorized asks the client for credentials or denies the request if the user is signed in already
+func apiUnauthorized(ctx *context.APIContext) {
+	if ctx.User == nil {
+		ctx.Resp.Header().Set("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, setting.AppName))
+		apiError(ctx, http.StatusUnauthorized, errCodeUnauthorized, "authentication required")
+		return
+	}
+	apiError(ctx, http.StatusForbidden, errCodeDenied, "requested access to the resource is denied")
+}
+
+// ReqContainerAccess checks the access mode of the current package context
+func ReqContainerAccess(ctx *context.APIContext, accessMode models.AccessMode) bool {
+	if ctx.Package.AccessMode < accessMode {
+		apiUnauthorized(ctx)
+		return false
+	}
+	return true
+}
+
+// SetDistributionHeader adds the API version header to every registry response
+func SetDistributionHeader(ctx *context.APIContext) {
+	ctx.Resp.Header().Set("Docker-Distribution-Api-Version", "registry/2.0")
+}
+
+// CheckAuthenticated handles GET /v2/ which is used by clients to verify the credentials
+func CheckAuthenticated(ctx *context.APIContext) {
+	if !setting.Packages.Enabled {
+		apiError(ctx, http.StatusNotFound, errCodeUnknown, "packages are disabled")
+		return
+	}
+	if ctx.User == nil {
+		apiUnauthorized(ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, struct{}{})
+}
+
+func imageName(ctx *context.APIContext) string {
+	return ctx.Params("image")
+}
+
+func blobUploadURL(ctx *context.APIContext, uuid string) string {
+	return fmt.Sprintf("/v2/%s/%s/blobs/uploads/%s", url.PathEscape(ctx.Package.Owner.LowerName), imageName(ctx), uuid)
+}
+
+func getPackage(ctx *context.APIContext) *models.Package {
+	p, err := models.GetPackageByName(ctx.Package.Owner.ID, models.PackageTypeContainer, imageName(ctx))
+	if err != nil {
+		if err == models.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, errCodeNameUnknown, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		}
+		return nil
+	}
+	return p
+}
+
+func getOrCreatePackage(ctx *context.APIContext) *models.Package {
+	p, err := models.GetOrInsertPackage(db.DefaultContext(), &models.Package{
+		OwnerID: ctx.Package.Owner.ID,
+		Type:    models.PackageTypeContainer,
+		Name:    imageName(ctx),
+	})
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		return nil
+	}
+	return p
+}
+
+func handleBlobError(ctx *context.APIContext, err error) {
+	switch err {
+	case container_service.ErrDigestMismatch:
+		apiError(ctx, http.StatusBadRequest, errCodeDigestInvalid, err)
+	case packages_module.ErrFileTooLarge:
+		apiError(ctx, http.StatusRequestEntityTooLarge, errCodeSizeInvalid, err)
+	default:
+		apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+	}
+}
+
+func blobCreated(ctx *context.APIContext, digest string) {
+	ctx.Resp.Header().Set("Location", fmt.Sprintf("/v2/%s/%s/blobs/%s", url.PathEscape(ctx.Package.Owner.LowerName), imageName(ctx), digest))
+	ctx.Resp.Header().Set("Docker-Content-Digest", digest)
+	ctx.Status(http.StatusCreated)
+}
+
+// InitiateUploadBlob starts a blob upload. The blob is uploaded monolithic if a digest is provided
+// or mounted from another image of the same owner if the mount and from parameters are set.
+// https://github.com/opencontainers/distribution-spec/blob/main/spec.md#pushing-blobs
+func InitiateUploadBlob(ctx *context.APIContext) {
+	p := getOrCreatePackage(ctx)
+	if p == nil {
+		return
+	}
+
+	if mount := ctx.FormTrim("mount"); mount != "" && container_module.IsValidDigest(mount) {
+		if from, err := models.GetPackageByName(ctx.Package.Owner.ID, models.PackageTypeContainer, ctx.FormTrim("from")); err == nil {
+			pb, err := models.GetPackageBlobByHash(container_module.DigestToHash(mount))
+			if err == nil {
+				var referenced bool
+				if referenced, err = models.IsPackageBlobReferencedByPackage(from.ID, pb.ID); err == nil && referenced {
+					blobCreated(ctx, mount)
+					return
+				}
+			}
+			if err != nil && err != models.ErrPackageBlobNotExist {
+				apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+				return
+			}
+		}
+	}
+
+	if digest := ctx.FormTrim("digest"); digest != "" {
+		if !container_module.IsValidDigest(digest) {
+			apiError(ctx, http.StatusBadRequest, errCodeDigestInvalid, "invalid digest")
+			return
+		}
+		if _, err := container_service.SaveBlob(ctx.Req.Body, digest); err != nil {
+			handleBlobError(ctx, err)
+			return
+		}
+		blobCreated(ctx, digest)
+		return
+	}
+
+	pbu, err := container_service.CreateBlobUpload(p.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		return
+	}
+
+	ctx.Resp.Header().Set("Location", blobUploadURL(ctx, pbu.ID))
+	ctx.Resp.Header().Set("Range", "0-0")
+	ctx.Resp.Header().Set("Docker-Upload-UUID", pbu.ID)
+	ctx.Status(http.StatusAccepted)
+}
+
+func getBlobUpload(ctx *context.APIContext) *models.PackageBlobUpload {
+	pbu, err := models.GetPackageBlobUploadByID(ctx.Params("uuid"))
+	if err != nil {
+		if err == models.ErrPackageBlobUploadNotExist {
+			apiError(ctx, http.StatusNotFound, errCodeBlobUploadUnknown, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		}
+		return nil
+	}
+	p, err := models.GetPackageByName(ctx.Package.Owner.ID, models.PackageTypeContainer, imageName(ctx))
+	if err != nil || p.ID != pbu.PackageID {
+		apiError(ctx, http.StatusNotFound, errCodeBlobUploadUnknown, models.ErrPackageBlobUploadNotExist)
+		return nil
+	}
+	return pbu
+}
+
+func setUploadProgressHeaders(ctx *context.APIContext, pbu *models.PackageBlobUpload) {
+	ctx.Resp.Header().Set("Location", blobUploadURL(ctx, pbu.ID))
+	ctx.Resp.Header().Set("Docker-Upload-UUID", pbu.ID)
+	end := pbu.BytesReceived - 1
+	if end < 0 {
+		end = 0
+	}
+	ctx.Resp.Header().Set("Range", fmt.Sprintf("0-%d", end))
+}
+
+// GetUploadBlob returns the progress of a blob upload
+func GetUploadBlob(ctx *context.APIContext) {
+	pbu := getBlobUpload(ctx)
+	if pbu == nil {
+		return
+	}
+	setUploadProgressHeaders(ctx, pbu)
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadBlob appends a chunk to a blob upload
+func UploadBlob(ctx *context.APIContext) {
+	pbu := getBlobUpload(ctx)
+	if pbu == nil {
+		return
+	}
+
+	if contentRange := ctx.Req.Header.Get("Content-Range"); contentRange != "" {
+		start, _ := strconv.ParseInt(strings.SplitN(contentRange, "-", 2)[0], 10, 64)
+		if start != pbu.BytesReceived {
+			setUploadProgressHeaders(ctx, pbu)
+			apiError(ctx, http.StatusRequestedRangeNotSatisfiable, errCodeBlobUploadInvalid, "invalid content range")
+			return
+		}
+	}
+
+	if err := container_service.AppendToBlobUpload(pbu, ctx.Req.Body); err != nil {
+		handleBlobError(ctx, err)
+		return
+	}
+
+	setUploadProgressHeaders(ctx, pbu)
+	ctx.Status(http.StatusAccepted)
+}
+
+// EndUploadBlob appends the last chunk and finishes the blob upload
+func EndUploadBlob(ctx *context.APIContext) {
+	pbu := getBlobUpload(ctx)
+	if pbu == nil {
+		return
+	}
+
+	digest := ctx.FormTrim("digest")
+	if !container_module.IsValidDigest(digest) {
+		apiError(ctx, http.StatusBadRequest, errCodeDigestInvalid, "invalid digest")
+		return
+	}
+
+	if ctx.Req.Body != nil {
+		if err := container_service.AppendToBlobUpload(pbu, ctx.Req.Body); err != nil {
+			handleBlobError(ctx, err)
+			return
+		}
+	}
+
+	if _, err := container_service.FinishBlobUpload(pbu, digest); err != nil {
+		handleBlobError(ctx, err)
+		return
+	}
+
+	blobCreated(ctx, digest)
+}
+
+// CancelUploadBlob aborts a blob upload
+func CancelUploadBlob(ctx *context.APIContext) {
+	pbu := getBlobUpload(ctx)
+	if pbu == nil {
+		return
+	}
+	if err := container_service.CancelBlobUpload(pbu.ID); err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getBlob(ctx *context.APIContext) *models.PackageBlob {
+	digest := ctx.Params("digest")
+	if !container_module.IsValidDigest(digest) {
+		apiError(ctx, http.StatusBadRequest, errCodeDigestInvalid, "invalid digest")
+		return nil
+	}
+
+	p := getPackage(ctx)
+	if p == nil {
+		return nil
+	}
+
+	// blobs are only visible through the images which reference them, as nothing records who uploaded
+	// a blob which is not referenced by a manifest yet
+	pb, err := models.GetPackageBlobByHash(container_module.DigestToHash(digest))
+	if err == nil {
+		var referenced bool
+		if referenced, err = models.IsPackageBlobReferencedByPackage(p.ID, pb.ID); err == nil && !referenced {
+			err = models.ErrPackageBlobNotExist
+		}
+	}
+	if err != nil {
+		if err == models.ErrPackageBlobNotExist {
+			apiError(ctx, http.StatusNotFound, errCodeBlobUnknown, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		}
+		return nil
+	}
+	return pb
+}
+
+// HeadBlob checks if a blob exists
+func HeadBlob(ctx *context.APIContext) {
+	pb := getBlob(ctx)
+	if pb == nil {
+		return
+	}
+	ctx.Resp.Header().Set("Docker-Content-Digest", "sha256:"+pb.HashSHA256)
+	ctx.Resp.Header().Set("Content-Length", strconv.FormatInt(pb.Size, 10))
+	ctx.Status(http.StatusOK)
+}
+
+// GetBlob streams the content of a blob
+func GetBlob(ctx *context.APIContext) {
+	pb := getBlob(ctx)
+	if pb == nil {
+		return
+	}
+	serveBlob(ctx, pb, "application/octet-stream")
+}
+
+func serveBlob(ctx *context.APIContext, pb *models.PackageBlob, contentType string) {
+	s, err := packages_module.NewContentStore().Get(pb.HashSHA256)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		return
+	}
+	defer s.Close()
+
+	ctx.Resp.Header().Set("Docker-Content-Digest", "sha256:"+pb.HashSHA256)
+	ctx.Resp.Header().Set("Content-Type", contentType)
+	ctx.Resp.Header().Set("Content-Length", strconv.FormatInt(pb.Size, 10))
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(ctx.Resp, s); err != nil {
+		log.Error("Error streaming package blob %d: %v", pb.ID, err)
+	}
+}
+
+// UploadManifest stores a manifest under a tag or digest
+func UploadManifest(ctx *context.APIContext) {
+	reference := ctx.Params("reference")
+	if !container_module.IsValidTag(reference) && !container_module.IsValidDigest(reference) {
+		apiError(ctx, http.StatusBadRequest, errCodeManifestInvalid, "invalid reference")
+		return
+	}
+
+	content, err := io.ReadAll(io.LimitReader(ctx.Req.Body, maxManifestSize+1))
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		return
+	}
+	if len(content) > maxManifestSize {
+		apiError(ctx, http.StatusRequestEntityTooLarge, errCodeSizeInvalid, "manifest is too large")
+		return
+	}
+
+	digest, err := container_service.PutManifest(ctx.Package.Owner, ctx.User, imageName(ctx), reference, content, ctx.Req.Header.Get("Content-Type"))
+	if err != nil {
+		switch err {
+		case container_module.ErrInvalidManifest:
+			apiError(ctx, http.StatusBadRequest, errCodeManifestInvalid, err)
+		case container_service.ErrBlobUnknown:
+			apiError(ctx, http.StatusBadRequest, errCodeManifestBlobUnknown, err)
+		case container_service.ErrDigestMismatch:
+			apiError(ctx, http.StatusBadRequest, errCodeDigestInvalid, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		}
+		return
+	}
+
+	ctx.Resp.Header().Set("Location", fmt.Sprintf("/v2/%s/%s/manifests/%s", url.PathEscape(ctx.Package.Owner.LowerName), imageName(ctx), reference))
+	ctx.Resp.Header().Set("Docker-Content-Digest", digest)
+	ctx.Status(http.StatusCreated)
+}
+
+func getManifest(ctx *context.APIContext) *container_service.ManifestInfo {
+	p := getPackage(ctx)
+	if p == nil {
+		return nil
+	}
+	mi, err := container_service.GetManifest(p, ctx.Params("reference"))
+	if err != nil {
+		if err == container_service.ErrManifestUnknown || err == models.ErrPackageBlobNotExist {
+			apiError(ctx, http.StatusNotFound, errCodeManifestUnknown, container_service.ErrManifestUnknown)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		}
+		return nil
+	}
+	return mi
+}
+
+// HeadManifest checks if a manifest exists
+func HeadManifest(ctx *context.APIContext) {
+	mi := getManifest(ctx)
+	if mi == nil {
+		return
+	}
+	ctx.Resp.Header().Set("Docker-Content-Digest", mi.Digest)
+	ctx.Resp.Header().Set("Content-Type", mi.MediaType)
+	ctx.Resp.Header().Set("Content-Length", strconv.FormatInt(mi.Size, 10))
+	ctx.Status(http.StatusOK)
+}
+
+// GetManifest returns the content of a manifest
+func GetManifest(ctx *context.APIContext) {
+	mi := getManifest(ctx)
+	if mi == nil {
+		return
+	}
+	if err := models.IncrementPackageVersionDownloadCount(mi.Version.ID); err != nil {
+		log.Error("Error incrementing download count of package version %d: %v", mi.Version.ID, err)
+	}
+	serveBlob(ctx, mi.Blob, mi.MediaType)
+}
+
+// DeleteManifest removes the package version referenced by the tag or digest
+func DeleteManifest(ctx *context.APIContext) {
+	mi := getManifest(ctx)
+	if mi == nil {
+		return
+	}
+	if err := models.DeletePackageVersionByID(mi.Version.ID); err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		return
+	}
+	ctx.Status(http.StatusAccepted)
+}
+
+// GetTagList returns the tags of an image
+// https://github.com/opencontainers/distribution-spec/blob/main/spec.md#content-discovery
+func GetTagList(ctx *context.APIContext) {
+	p := getPackage(ctx)
+	if p == nil {
+		return
+	}
+
+	tags, err := container_service.GetTags(p)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeUnknown, err)
+		return
+	}
+	sort.Strings(tags)
+
+	if last := ctx.FormTrim("last"); last != "" {
+		idx := sort.SearchStrings(tags, last)
+		if idx < len(tags) && tags[idx] == last {
+			idx++
+		}
+		tags = tags[idx:]
+	}
+	if n := ctx.FormInt("n"); n > 0 && n < len(tags) {
+		tags = tags[:n]
+		ctx.Resp.Header().Set("Link", fmt.Sprintf(`</v2/%s/%s/tags/list?n=%d&last=%s>; rel="next"`, url.PathEscape(ctx.Package.Owner.LowerName), imageName(ctx), n, url.QueryEscape(tags[n-1])))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"name": fmt.Sprintf("%s/%s", ctx.Package.Owner.LowerName, p.LowerName),
+		"tags": tags,
+	})
+}
@@ -0,0 +1,232 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	generic_module "code.gitea.io/gitea/modules/packages/generic"
+	"code.gitea.io/gitea/modules/setting"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// maxMetadataSize limits the size of the metadata document of a version
+const maxMetadataSize = 64 * 1024
+
+func apiError(ctx *context.APIContext, status int, obj interface{}) {
+	message := ""
+	switch m := obj.(type) {
+	case error:
+		message = m.Error()
+	case string:
+		message = m
+	}
+	if status == http.StatusInternalServerError {
+		log.Error("generic: %s", message)
+	}
+	ctx.PlainText(status, []byte(message))
+}
+
+func packageInfoFromParams(ctx *context.APIContext) (*packages_service.PackageInfo, bool) {
+	name := ctx.Params("packagename")
+	version := ctx.Params("packageversion")
+	if !generic_module.IsValidName(name) || !generic_module.IsValidVersion(version) {
+		apiError(ctx, http.StatusBadRequest, "invalid package name or version")
+		return nil, false
+	}
+	return &packages_service.PackageInfo{
+		Owner:       ctx.Package.Owner,
+		PackageType: models.PackageTypeGeneric,
+		Name:        name,
+		Version:     version,
+	}, true
+}
+
+func getPackageVersion(ctx *context.APIContext) *models.PackageVersion {
+	pi, ok := packageInfoFromParams(ctx)
+	if !ok {
+		return nil
+	}
+	p, err := models.GetPackageByName(pi.Owner.ID, pi.PackageType, pi.Name)
+	if err == nil {
+		var pv *models.PackageVersion
+		if pv, err = models.GetPackageVersionByVersion(p.ID, pi.Version); err == nil {
+			return pv
+		}
+	}
+	if err == models.ErrPackageNotExist || err == models.ErrPackageVersionNotExist {
+		apiError(ctx, http.StatusNotFound, err)
+	} else {
+		apiError(ctx, http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+// DownloadPackageFile serves the content of a package file
+func DownloadPackageFile(ctx *context.APIContext) {
+	pi, ok := packageInfoFromParams(ctx)
+	if !ok {
+		return
+	}
+
+	s, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(pi, ctx.Params("filename"))
+	if err != nil {
+		if err == models.ErrPackageNotExist || err == models.ErrPackageVersionNotExist || err == models.ErrPackageFileNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer s.Close()
+
+	ctx.ServeStream(s, pf.Name)
+}
+
+// UploadPackageFile adds a file to the package version. If the package or version does not exist, it gets created.
+func UploadPackageFile(ctx *context.APIContext) {
+	pi, ok := packageInfoFromParams(ctx)
+	if !ok {
+		return
+	}
+
+	filename := ctx.Params("filename")
+	if !generic_module.IsValidFilename(filename) {
+		apiError(ctx, http.StatusBadRequest, "invalid filename")
+		return
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(ctx.Req.Body, setting.Packages.MaxFileSize)
+	if err != nil {
+		if err == packages_module.ErrFileTooLarge {
+			apiError(ctx, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	_, _, err = packages_service.CreatePackageOrAddFileToExisting(
+		&packages_service.PackageCreationInfo{
+			PackageInfo: *pi,
+			Creator:     ctx.User,
+			Metadata:    generic_module.Metadata{},
+		},
+		&packages_service.PackageFileInfo{
+			Filename: filename,
+			Data:     buf,
+		},
+	)
+	if err != nil {
+		if err == models.ErrDuplicatePackageFile {
+			apiError(ctx, http.StatusConflict, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// GetPackageVersion returns the metadata and files of a package version
+func GetPackageVersion(ctx *context.APIContext) {
+	pv := getPackageVersion(ctx)
+	if pv == nil {
+		return
+	}
+
+	metadata := generic_module.Metadata{}
+	if err := json.Unmarshal([]byte(pv.MetadataJSON), &metadata); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pfs, err := models.GetPackageFilesByVersionID(pv.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	files := make([]generic_module.FileInfo, 0, len(pfs))
+	for _, pf := range pfs {
+		pb, err := models.GetPackageBlobByID(pf.BlobID)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		files = append(files, generic_module.FileInfo{
+			Name:   pf.Name,
+			Size:   pb.Size,
+			SHA256: pb.HashSHA256,
+		})
+	}
+
+	creator := ""
+	if u, err := models.GetUserByID(pv.CreatorID); err == nil {
+		creator = u.Name
+	} else if !models.IsErrUserNotExist(err) {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &generic_module.VersionInfo{
+		Name:          ctx.Params("packagename"),
+		Version:       pv.Version,
+		Creator:       creator,
+		DownloadCount: pv.DownloadCount,
+		Metadata:      metadata,
+		Files:         files,
+		Created:       pv.CreatedUnix.AsTime(),
+	})
+}
+
+// UpdatePackageVersionMetadata replaces the metadata of a package version with the posted JSON object
+func UpdatePackageVersionMetadata(ctx *context.APIContext) {
+	pv := getPackageVersion(ctx)
+	if pv == nil {
+		return
+	}
+
+	metadata := generic_module.Metadata{}
+	if err := json.NewDecoder(http.MaxBytesReader(ctx.Resp, ctx.Req.Body, maxMetadataSize)).Decode(&metadata); err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pv.MetadataJSON = string(metadataJSON)
+	if err := models.UpdatePackageVersionMetadata(db.DefaultContext(), pv); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeletePackageVersion deletes a package version with all its files
+func DeletePackageVersion(ctx *context.APIContext) {
+	pv := getPackageVersion(ctx)
+	if pv == nil {
+		return
+	}
+
+	if err := models.DeletePackageVersionByID(pv.ID); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,213 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package npm
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+func apiError(ctx *context.APIContext, status int, obj interface{}) {
+	message := ""
+	switch m := obj.(type) {
+	case error:
+		message = m.Error()
+	case string:
+		message = m
+	}
+	if status == http.StatusInternalServerError {
+		log.Error("npm: %s", message)
+	}
+	ctx.JSON(status, map[string]string{
+		"error": message,
+	})
+}
+
+// packageNameFromParams gets the package name from the url parameters
+// Variations: /name/, /@scope/name/, /@scope%2Fname/
+func packageNameFromParams(ctx *context.APIContext) string {
+	scope := ctx.Params("scope")
+	id := ctx.Params("id")
+	if scope != "" {
+		return fmt.Sprintf("@%s/%s", scope, id)
+	}
+	return id
+}
+
+func packageTarballURL(owner *models.User, name, version, filename string) string {
+	return fmt.Sprintf("%sapi/packages/%s/npm/%s/-/%s/%s", setting.AppURL, url.PathEscape(owner.Name), name, url.PathEscape(version), url.PathEscape(filename))
+}
+
+// PackageMetadata returns the metadata for a single package
+func PackageMetadata(ctx *context.APIContext) {
+	packageName := packageNameFromParams(ctx)
+
+	p, err := models.GetPackageByName(ctx.Package.Owner.ID, models.PackageTypeNpm, packageName)
+	if err != nil {
+		if err == models.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	versions, err := models.GetPackageVersionsByPackageID(p.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if len(versions) == 0 {
+		apiError(ctx, http.StatusNotFound, models.ErrPackageNotExist)
+		return
+	}
+
+	resp := &npm_module.PackageMetadata{
+		ID:       p.Name,
+		Name:     p.Name,
+		DistTags: make(map[string]string),
+		Versions: make(map[string]*npm_module.PackageMetadataVersion, len(versions)),
+	}
+	for _, pv := range versions {
+		var metadata npm_module.Metadata
+		if err := json.Unmarshal([]byte(pv.MetadataJSON), &metadata); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		files, err := models.GetPackageFilesByVersionID(pv.ID)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		tarball := ""
+		if len(files) > 0 {
+			tarball = packageTarballURL(ctx.Package.Owner, p.Name, pv.Version, files[0].Name)
+		}
+
+		resp.Versions[pv.Version] = &npm_module.PackageMetadataVersion{
+			ID:                   fmt.Sprintf("%s@%s", p.Name, pv.Version),
+			Name:                 p.Name,
+			Version:              pv.Version,
+			Description:          metadata.Description,
+			Author:               npm_module.User{Name: metadata.Author},
+			Homepage:             metadata.ProjectURL,
+			License:              metadata.License,
+			Keywords:             metadata.Keywords,
+			Dependencies:         metadata.Dependencies,
+			DevDependencies:      metadata.DevelopmentDependencies,
+			PeerDependencies:     metadata.PeerDependencies,
+			OptionalDependencies: metadata.OptionalDependencies,
+			Readme:               metadata.Readme,
+			Dist: npm_module.PackageDistribution{
+				Shasum:    metadata.Shasum,
+				Integrity: metadata.Integrity,
+				Tarball:   tarball,
+			},
+		}
+
+		// versions are sorted by creation, so newer tags override older ones
+		for _, tag := range metadata.DistTags {
+			resp.DistTags[tag] = pv.Version
+		}
+		resp.Description = metadata.Description
+		resp.Readme = metadata.Readme
+		resp.Homepage = metadata.ProjectURL
+		resp.License = metadata.License
+	}
+	if _, ok := resp.DistTags["latest"]; !ok {
+		resp.DistTags["latest"] = versions[len(versions)-1].Version
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// DownloadPackageFile serves the content of a package
+func DownloadPackageFile(ctx *context.APIContext) {
+	s, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: models.PackageTypeNpm,
+			Name:        packageNameFromParams(ctx),
+			Version:     ctx.Params("version"),
+		},
+		ctx.Params("filename"),
+	)
+	if err != nil {
+		if err == models.ErrPackageNotExist || err == models.ErrPackageVersionNotExist || err == models.ErrPackageFileNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer s.Close()
+
+	ctx.ServeStream(s, pf.Name)
+}
+
+// UploadPackage creates a new package
+func UploadPackage(ctx *context.APIContext) {
+	npmPackage, err := npm_module.ParsePackage(ctx.Req.Body)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !strings.EqualFold(npmPackage.Name, packageNameFromParams(ctx)) {
+		apiError(ctx, http.StatusBadRequest, "package name does not match the url")
+		return
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(bytes.NewReader(npmPackage.Data), setting.Packages.MaxFileSize)
+	if err != nil {
+		if err == packages_module.ErrFileTooLarge {
+			apiError(ctx, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	_, _, err = packages_service.CreatePackageAndAddFile(
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: models.PackageTypeNpm,
+				Name:        npmPackage.Name,
+				Version:     npmPackage.Version,
+			},
+			Creator:  ctx.User,
+			Metadata: npmPackage.Metadata,
+		},
+		&packages_service.PackageFileInfo{
+			Filename: npmPackage.Filename,
+			Data:     buf,
+		},
+	)
+	if err != nil {
+		if err == models.ErrDuplicatePackageVersion {
+			apiError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	pypi_module "code.gitea.io/gitea/modules/packages/pypi"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/validation"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+func apiError(ctx *context.APIContext, status int, obj interface{}) {
+	message := ""
+	switch m := obj.(type) {
+	case error:
+		message = m.Error()
+	case string:
+		message = m
+	}
+	if status == http.StatusInternalServerError {
+		log.Error("pypi: %s", message)
+	}
+	ctx.PlainText(status, []byte(message))
+}
+
+// PackageMetadata returns the simple repository index of a package (PEP 503)
+func PackageMetadata(ctx *context.APIContext) {
+	packageName := pypi_module.NormalizeName(ctx.Params("id"))
+
+	p, err := models.GetPackageByName(ctx.Package.Owner.ID, models.PackageTypePyPI, packageName)
+	if err != nil {
+		if err == models.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	versions, err := models.GetPackageVersionsByPackageID(p.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head><title>Links for ")
+	sb.WriteString(html.EscapeString(p.Name))
+	sb.WriteString("</title></head>\n<body>\n<h1>Links for ")
+	sb.WriteString(html.EscapeString(p.Name))
+	sb.WriteString("</h1>\n")
+	for _, pv := range versions {
+		files, err := models.GetPackageFilesByVersionID(pv.ID)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		for _, pf := range files {
+			pb, err := models.GetPackageBlobByID(pf.BlobID)
+			if err != nil {
+				apiError(ctx, http.StatusInternalServerError, err)
+				return
+			}
+			link := fmt.Sprintf("%sapi/packages/%s/pypi/files/%s/%s/%s#sha256=%s",
+				setting.AppURL,
+				url.PathEscape(ctx.Package.Owner.Name),
+				url.PathEscape(p.Name),
+				url.PathEscape(pv.Version),
+				url.PathEscape(pf.Name),
+				pb.HashSHA256,
+			)
+			fmt.Fprintf(&sb, "<a href=\"%s\">%s</a><br/>\n", html.EscapeString(link), html.EscapeString(pf.Name))
+		}
+	}
+	sb.WriteString("</body>\n</html>\n")
+
+	ctx.Resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	_, _ = ctx.Resp.Write([]byte(sb.String()))
+}
+
+// DownloadPackageFile serves the content of a package file
+func DownloadPackageFile(ctx *context.APIContext) {
+	s, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: models.PackageTypePyPI,
+			Name:        pypi_module.NormalizeName(ctx.Params("id")),
+			Version:     ctx.Params("version"),
+		},
+		ctx.Params("filename"),
+	)
+	if err != nil {
+		if err == models.ErrPackageNotExist || err == models.ErrPackageVersionNotExist || err == models.ErrPackageFileNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer s.Close()
+
+	ctx.ServeStream(s, pf.Name)
+}
+
+// UploadPackageFile adds a file to the package. If the package does not exist, it gets created.
+func UploadPackageFile(ctx *context.APIContext) {
+	file, fileHeader, err := ctx.Req.FormFile("content")
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	buf, err := packages_module.CreateHashedBufferFromReader(file, setting.Packages.MaxFileSize)
+	if err != nil {
+		if err == packages_module.ErrFileTooLarge {
+			apiError(ctx, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	expectedSHA256 := ctx.Req.FormValue("sha256_digest")
+	if expectedSHA256 != "" && !strings.EqualFold(expectedSHA256, buf.SHA256) {
+		apiError(ctx, http.StatusBadRequest, "hash mismatch")
+		return
+	}
+
+	packageName := ctx.Req.FormValue("name")
+	packageVersion := ctx.Req.FormValue("version")
+	if !pypi_module.IsValidName(packageName) || !pypi_module.IsValidVersion(packageVersion) {
+		apiError(ctx, http.StatusBadRequest, "invalid package name or version")
+		return
+	}
+
+	projectURL := ctx.Req.FormValue("home_page")
+	if !validation.IsValidURL(projectURL) {
+		projectURL = ""
+	}
+
+	_, _, err = packages_service.CreatePackageOrAddFileToExisting(
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: models.PackageTypePyPI,
+				Name:        pypi_module.NormalizeName(packageName),
+				Version:     packageVersion,
+			},
+			Creator: ctx.User,
+			Metadata: &pypi_module.Metadata{
+				Author:          ctx.Req.FormValue("author"),
+				Description:     ctx.Req.FormValue("description"),
+				LongDescription: ctx.Req.FormValue("long_description"),
+				Summary:         ctx.Req.FormValue("summary"),
+				ProjectURL:      projectURL,
+				License:         ctx.Req.FormValue("license"),
+				RequiresPython:  ctx.Req.FormValue("requires_python"),
+			},
+		},
+		&packages_service.PackageFileInfo{
+			Filename: fileHeader.Filename,
+			Data:     buf,
+		},
+	)
+	if err != nil {
+		if err == models.ErrDuplicatePackageFile {
+			apiError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package runner implements the API which is used by workflow runners to fetch jobs and to report their status.
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	workflow_module "code.gitea.io/gitea/modules/workflow"
+	secrets_service "code.gitea.io/gitea/services/secrets"
+	workflow_service "code.gitea.io/gitea/services/workflow"
+)
+
+// Job represents a job assigned to a runner
+type Job struct {
+	ID                int64                `json:"id"`
+	RunID             int64                `json:"run_id"`
+	JobID             string               `json:"job_id"`
+	Name              string               `json:"name"`
+	Repository        string               `json:"repository"`
+	CloneURL          string               `json:"clone_url"`
+	Event             string               `json:"event"`
+	Ref               string               `json:"ref"`
+	CommitSHA         string               `json:"commit_sha"`
+	IsForkPullRequest bool                 `json:"is_fork_pull_request"`
+	Job               *workflow_module.Job `json:"job"`
+	// Secrets are not passed to jobs of pull requests from forks
+	Secrets map[string]string `json:"secrets"`
+}
+
+// UpdateJobStatusOption options when a runner reports the status of a job
+type UpdateJobStatusOption struct {
+	Status string `json:"status"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func apiError(ctx *context.PrivateContext, status int, message string) {
+	ctx.JSON(status, errorResponse{Message: message})
+}
+
+// Routes registers the runner API routes, mounted under /api/runner
+func Routes() *web.Route {
+	r := web.NewRoute()
+	r.Use(context.PrivateContexter())
+	r.Use(authenticate)
+
+	r.Post("/jobs/fetch", FetchJob)
+	r.Post("/jobs/{id}/status", UpdateJobStatus)
+
+	return r
+}
+
+// authenticate looks up the runner by the token passed in the Authorization header
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.GetPrivateContext(req)
+
+		if !setting.Workflow.Enabled {
+			apiError(ctx, http.StatusNotFound, "workflows are disabled")
+			return
+		}
+
+		fields := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
+		if len(fields) != 2 || (!strings.EqualFold(fields[0], "bearer") && !strings.EqualFold(fields[0], "token")) {
+			apiError(ctx, http.StatusUnauthorized, "runner token required")
+			return
+		}
+
+		runner, err := models.GetWorkflowRunnerByToken(strings.TrimSpace(fields[1]))
+		if err != nil {
+			if err == models.ErrWorkflowRunnerNotExist {
+				apiError(ctx, http.StatusUnauthorized, "invalid runner token")
+			} else {
+				log.Error("GetWorkflowRunnerByToken: %v", err)
+				apiError(ctx, http.StatusInternalServerError, "")
+			}
+			return
+		}
+		ctx.Data["Runner"] = runner
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+func getRunner(ctx *context.PrivateContext) *models.WorkflowRunner {
+	return ctx.Data["Runner"].(*models.WorkflowRunner)
+}
+
+// FetchJob assigns a pending job to the runner. It responds with 204 if there is no job.
+func FetchJob(ctx *context.PrivateContext) {
+	job, run, err := workflow_service.FetchJob(getRunner(ctx))
+	if err != nil {
+		log.Error("FetchJob: %v", err)
+		apiError(ctx, http.StatusInternalServerError, "")
+		return
+	}
+	if job == nil {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	definition := &workflow_module.Job{}
+	if err := json.Unmarshal([]byte(job.Payload), definition); err != nil {
+		log.Error("Unmarshal job %d: %v", job.ID, err)
+		apiError(ctx, http.StatusInternalServerError, "")
+		return
+	}
+	if err := run.LoadAttributes(); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		apiError(ctx, http.StatusInternalServerError, "")
+		return
+	}
+
+	secrets := map[string]string{}
+	if !run.IsForkPullRequest {
+		secrets, err = secrets_service.GetRepositorySecrets(run.Repo, fmt.Sprintf("runner %d (job %d)", getRunner(ctx).ID, job.ID))
+		if err != nil {
+			log.Error("GetRepositorySecrets: %v", err)
+			apiError(ctx, http.StatusInternalServerError, "")
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &Job{
+		ID:                job.ID,
+		RunID:             run.ID,
+		JobID:             job.JobID,
+		Name:              job.Name,
+		Repository:        run.Repo.FullName(),
+		CloneURL:          run.Repo.CloneLink().HTTPS,
+		Event:             run.Event,
+		Ref:               run.Ref,
+		CommitSHA:         run.CommitSHA,
+		IsForkPullRequest: run.IsForkPullRequest,
+		Job:               definition,
+		Secrets:           secrets,
+	})
+}
+
+// UpdateJobStatus sets the final status of a job which is executed by the runner
+func UpdateJobStatus(ctx *context.PrivateContext) {
+	job, err := models.GetWorkflowJobByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrWorkflowJobNotExist {
+			apiError(ctx, http.StatusNotFound, err.Error())
+		} else {
+			log.Error("GetWorkflowJobByID: %v", err)
+			apiError(ctx, http.StatusInternalServerError, "")
+		}
+		return
+	}
+	if job.RunnerID != getRunner(ctx).ID {
+		apiError(ctx, http.StatusNotFound, models.ErrWorkflowJobNotExist.Error())
+		return
+	}
+
+	var opts UpdateJobStatusOption
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&opts); err != nil {
+		apiError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	status, ok := models.WorkflowStatusFromString(opts.Status)
+	if !ok {
+		apiError(ctx, http.StatusBadRequest, "unknown status")
+		return
+	}
+	if status == models.WorkflowStatusRunning && job.Status == models.WorkflowStatusRunning {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	if err := workflow_service.UpdateJobStatus(job, status); err != nil {
+		if err == workflow_service.ErrInvalidStatusTransition {
+			apiError(ctx, http.StatusConflict, err.Error())
+		} else {
+			log.Error("UpdateJobStatus: %v", err)
+			apiError(ctx, http.StatusInternalServerError, "")
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
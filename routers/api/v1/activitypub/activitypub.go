@@ -0,0 +1,167 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/federation"
+	"code.gitea.io/gitea/services/forgefed"
+)
+
+// response writes an ActivityPub object
+func response(ctx *context.APIContext, v interface{}) {
+	ctx.Resp.Header().Set("Content-Type", activitypub.ContentType+"; charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(ctx.Resp).Encode(v); err != nil {
+		log.Error("Render ActivityPub object failed: %v", err)
+	}
+}
+
+// inboxHandler handles an activity whose signature was verified
+type inboxHandler func(sender *models.RemoteActor, activity *activitypub.Activity, body []byte) error
+
+// inbox handles an activity delivered to the inbox of a local actor
+func inbox(ctx *context.APIContext, ownerType models.FederationOwnerType, ownerID int64, handle inboxHandler) {
+	body, err := io.ReadAll(io.LimitReader(ctx.Req.Body, setting.Federation.MaxSize+1))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReadAll", err)
+		return
+	}
+	if int64(len(body)) > setting.Federation.MaxSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", fmt.Sprintf("activities are limited to %d bytes", setting.Federation.MaxSize))
+		return
+	}
+
+	activity, err := federation.ParseActivity(body)
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "", err)
+		return
+	}
+	if _, err := activitypub.CheckURL(string(activity.Actor)); err != nil {
+		ctx.Error(http.StatusForbidden, "", err)
+		return
+	}
+
+	sender, err := federation.VerifyRequest(ctx, ownerType, ownerID, ctx.Req, body)
+	if err != nil {
+		if activitypub.IsErrInvalidSignature(err) || activitypub.IsErrBlockedURL(err) {
+			ctx.Error(http.StatusUnauthorized, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "VerifyRequest", err)
+		}
+		return
+	}
+
+	if err := handle(sender, activity, body); err != nil {
+		if activitypub.IsErrInvalidSignature(err) {
+			ctx.Error(http.StatusUnauthorized, "", err)
+		} else if forgefed.IsErrInteractionForbidden(err) {
+			ctx.Error(http.StatusForbidden, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "HandleInbox", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusAccepted)
+}
+
+// outbox lists the activities published by a local actor
+func outbox(ctx *context.APIContext, ownerType models.FederationOwnerType, ownerID int64) {
+	activities, count, err := models.FindFederationActivities(&models.FindFederationActivitiesOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindFederationActivities", err)
+		return
+	}
+
+	items := make([]interface{}, len(activities))
+	for i := range activities {
+		items[i] = json.RawMessage(activities[i].Content)
+	}
+	response(ctx, &activitypub.OrderedCollection{
+		Context:      activitypub.ActivityStreamsContext,
+		ID:           federation.ActorURI(ownerType, ownerID) + "/outbox",
+		Type:         activitypub.TypeOrderedCollection,
+		TotalItems:   count,
+		OrderedItems: items,
+	})
+}
+
+// followers lists the remote actors following a local actor
+func followers(ctx *context.APIContext, ownerType models.FederationOwnerType, ownerID int64) {
+	actors, err := models.GetFederationFollowers(ownerType, ownerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFederationFollowers", err)
+		return
+	}
+
+	items := make([]interface{}, len(actors))
+	for i := range actors {
+		items[i] = actors[i].URI
+	}
+	response(ctx, &activitypub.OrderedCollection{
+		Context:      activitypub.ActivityStreamsContext,
+		ID:           federation.ActorURI(ownerType, ownerID) + "/followers",
+		Type:         activitypub.TypeOrderedCollection,
+		TotalItems:   int64(len(items)),
+		OrderedItems: items,
+	})
+}
+
+// Activity returns an activity published by a local actor
+func Activity(ctx *context.APIContext) {
+	// swagger:operation GET /activitypub/activity/{uuid} activitypub activitypubActivity
+	// ---
+	// summary: Returns an activity published by a user or a repository
+	// produces:
+	// - application/activity+json
+	// parameters:
+	// - name: uuid
+	//   in: path
+	//   description: id of the activity
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActivityPub"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	activity, err := models.GetPublishedFederationActivity(federation.ActivityURI(ctx.Params(":uuid")))
+	if err != nil {
+		if models.IsErrFederationActivityNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPublishedFederationActivity", err)
+		}
+		return
+	}
+
+	// the activities of actors which are not federated anymore are not exposed
+	switch activity.OwnerType {
+	case models.FederationOwnerUser:
+		if getFederatedUser(ctx, activity.OwnerID); ctx.Written() {
+			return
+		}
+	case models.FederationOwnerRepo:
+		if getFederatedRepo(ctx, activity.OwnerID); ctx.Written() {
+			return
+		}
+	}
+	response(ctx, json.RawMessage(activity.Content))
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/services/federation"
+)
+
+// getFederatedUser returns the user of the request if it is exposed as an actor
+func getFederatedUser(ctx *context.APIContext, id int64) *models.User {
+	user, err := models.GetUserByID(id)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByID", err)
+		}
+		return nil
+	}
+	if !federation.IsFederatedUser(user) {
+		ctx.NotFound()
+		return nil
+	}
+	return user
+}
+
+// Person returns the actor of a user
+func Person(ctx *context.APIContext) {
+	// swagger:operation GET /activitypub/user-id/{user-id} activitypub activitypubPerson
+	// ---
+	// summary: Returns the Person actor of a user
+	// produces:
+	// - application/activity+json
+	// parameters:
+	// - name: user-id
+	//   in: path
+	//   description: user ID of the user
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActivityPub"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	user := getFederatedUser(ctx, ctx.ParamsInt64(":user-id"))
+	if ctx.Written() {
+		return
+	}
+	actor, err := federation.UserActor(user)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "UserActor", err)
+		return
+	}
+	response(ctx, actor)
+}
+
+// PersonInbox handles an activity delivered to the inbox of a user
+func PersonInbox(ctx *context.APIContext) {
+	// swagger:operation POST /activitypub/user-id/{user-id}/inbox activitypub activitypubPersonInbox
+	// ---
+	// summary: Send an activity to the inbox of a user. The request has to be signed by the actor of the activity.
+	// consumes:
+	// - application/activity+json
+	// parameters:
+	// - name: user-id
+	//   in: path
+	//   description: user ID of the user
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "401":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	user := getFederatedUser(ctx, ctx.ParamsInt64(":user-id"))
+	if ctx.Written() {
+		return
+	}
+	inbox(ctx, models.FederationOwnerUser, user.ID, func(sender *models.RemoteActor, activity *activitypub.Activity, body []byte) error {
+		return federation.HandleInbox(models.FederationOwnerUser, user.ID, sender, activity, body)
+	})
+}
+
+// PersonOutbox lists the activities published by a user
+func PersonOutbox(ctx *context.APIContext) {
+	// swagger:operation GET /activitypub/user-id/{user-id}/outbox activitypub activitypubPersonOutbox
+	// ---
+	// summary: List the activities published by a user, newest first
+	// produces:
+	// - application/activity+json
+	// parameters:
+	// - name: user-id
+	//   in: path
+	//   description: user ID of the user
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActivityPub"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	user := getFederatedUser(ctx, ctx.ParamsInt64(":user-id"))
+	if ctx.Written() {
+		return
+	}
+	outbox(ctx, models.FederationOwnerUser, user.ID)
+}
+
+// PersonFollowers lists the remote actors following a user
+func PersonFollowers(ctx *context.APIContext) {
+	// swagger:operation GET /activitypub/user-id/{user-id}/followers activitypub activitypubPersonFollowers
+	// ---
+	// summary: List the remote actors following a user
+	// produces:
+	// - application/activity+json
+	// parameters:
+	// - name: user-id
+	//   in: path
+	//   description: user ID of the user
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActivityPub"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	user := getFederatedUser(ctx, ctx.ParamsInt64(":user-id"))
+	if ctx.Written() {
+		return
+	}
+	followers(ctx, models.FederationOwnerUser, user.ID)
+}
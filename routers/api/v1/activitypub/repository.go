@@ -0,0 +1,164 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/services/federation"
+	"code.gitea.io/gitea/services/forgefed"
+)
+
+// getFederatedRepo returns the repository of the request if it is exposed as an actor
+func getFederatedRepo(ctx *context.APIContext, id int64) *models.Repository {
+	repo, err := models.GetRepositoryByID(id)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByID", err)
+		}
+		return nil
+	}
+	if !federation.IsFederatedRepo(repo) {
+		ctx.NotFound()
+		return nil
+	}
+	return repo
+}
+
+// Repository returns the actor of a repository
+func Repository(ctx *context.APIContext) {
+	// swagger:operation GET /activitypub/repository-id/{repository-id} activitypub activitypubRepository
+	// ---
+	// summary: Returns the actor of a repository
+	// produces:
+	// - application/activity+json
+	// parameters:
+	// - name: repository-id
+	//   in: path
+	//   description: repository ID of the repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActivityPub"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repo := getFederatedRepo(ctx, ctx.ParamsInt64(":repository-id"))
+	if ctx.Written() {
+		return
+	}
+	actor, err := federation.RepoActor(repo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RepoActor", err)
+		return
+	}
+	response(ctx, actor)
+}
+
+// RepositoryInbox handles an activity delivered to the inbox of a repository
+func RepositoryInbox(ctx *context.APIContext) {
+	// swagger:operation POST /activitypub/repository-id/{repository-id}/inbox activitypub activitypubRepositoryInbox
+	// ---
+	// summary: Send an activity to the inbox of a repository. The request has to be signed by the actor of the activity.
+	// description: Stars (Like or Star), tickets (Offer of a Ticket) and comments (Create of a Note replying to an issue) of the ForgeFed
+	//   vocabulary are only accepted if the repository opted in to federation and the actor is not blocked.
+	// consumes:
+	// - application/activity+json
+	// parameters:
+	// - name: repository-id
+	//   in: path
+	//   description: repository ID of the repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "401":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repo := getFederatedRepo(ctx, ctx.ParamsInt64(":repository-id"))
+	if ctx.Written() {
+		return
+	}
+	inbox(ctx, models.FederationOwnerRepo, repo.ID, func(sender *models.RemoteActor, activity *activitypub.Activity, body []byte) error {
+		return forgefed.HandleRepoInbox(repo, sender, activity, body)
+	})
+}
+
+// RepositoryOutbox lists the activities published by a repository
+func RepositoryOutbox(ctx *context.APIContext) {
+	// swagger:operation GET /activitypub/repository-id/{repository-id}/outbox activitypub activitypubRepositoryOutbox
+	// ---
+	// summary: List the activities published by a repository, newest first
+	// produces:
+	// - application/activity+json
+	// parameters:
+	// - name: repository-id
+	//   in: path
+	//   description: repository ID of the repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActivityPub"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repo := getFederatedRepo(ctx, ctx.ParamsInt64(":repository-id"))
+	if ctx.Written() {
+		return
+	}
+	outbox(ctx, models.FederationOwnerRepo, repo.ID)
+}
+
+// RepositoryFollowers lists the remote actors following a repository
+func RepositoryFollowers(ctx *context.APIContext) {
+	// swagger:operation GET /activitypub/repository-id/{repository-id}/followers activitypub activitypubRepositoryFollowers
+	// ---
+	// summary: List the remote actors following a repository
+	// produces:
+	// - application/activity+json
+	// parameters:
+	// - name: repository-id
+	//   in: path
+	//   description: repository ID of the repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActivityPub"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repo := getFederatedRepo(ctx, ctx.ParamsInt64(":repository-id"))
+	if ctx.Written() {
+		return
+	}
+	followers(ctx, models.FederationOwnerRepo, repo.ID)
+}
@@ -0,0 +1,249 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	backup_service "code.gitea.io/gitea/services/backup"
+)
+
+// ListBackups api for listing the available backups
+func ListBackups(ctx *context.APIContext) {
+	// swagger:operation GET /admin/backups admin adminListBackups
+	// ---
+	// summary: List backups, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: query
+	//   description: only list backups of this type
+	//   type: string
+	//   enum: [instance, repository]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BackupList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := &models.FindBackupsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+	}
+	if typeName := ctx.FormString("type"); typeName != "" {
+		tp, ok := models.BackupTypeFromString(typeName)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown backup type: %s", typeName))
+			return
+		}
+		opts.Type = &tp
+	}
+
+	backups, count, err := models.FindBackups(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindBackups", err)
+		return
+	}
+
+	apiBackups := make([]*api.Backup, 0, len(backups))
+	for _, backup := range backups {
+		apiBackups = append(apiBackups, convert.ToBackup(backup))
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiBackups)
+}
+
+// CreateBackup api for triggering a backup
+func CreateBackup(ctx *context.APIContext) {
+	// swagger:operation POST /admin/backups admin adminCreateBackup
+	// ---
+	// summary: Trigger a backup of the instance or of a repository, which is produced asynchronously into the backup storage
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateBackupOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/Backup"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateBackupOption)
+	tp, ok := models.BackupTypeFromString(form.Type)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown backup type: %s", form.Type))
+		return
+	}
+
+	var (
+		backup *models.Backup
+		err    error
+	)
+	if tp == models.BackupTypeRepository {
+		if form.Owner == "" || form.Repo == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("owner and repo are required for repository backups"))
+			return
+		}
+		var repo *models.Repository
+		repo, err = models.GetRepositoryByOwnerAndName(form.Owner, form.Repo)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return
+		}
+		backup, err = backup_service.CreateRepositoryBackup(ctx.User, repo)
+	} else {
+		backup, err = backup_service.CreateInstanceBackup(ctx.User)
+	}
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateBackup", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, convert.ToBackup(backup))
+}
+
+func getBackupByParams(ctx *context.APIContext) *models.Backup {
+	backup, err := models.GetBackupByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrBackupNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBackupByID", err)
+		}
+		return nil
+	}
+	return backup
+}
+
+// GetBackup api for getting a backup
+func GetBackup(ctx *context.APIContext) {
+	// swagger:operation GET /admin/backups/{id} admin adminGetBackup
+	// ---
+	// summary: Get a backup
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the backup
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Backup"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	backup := getBackupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToBackup(backup))
+}
+
+// RestoreBackup api for restoring a repository from a backup
+func RestoreBackup(ctx *context.APIContext) {
+	// swagger:operation POST /admin/backups/{id}/restore admin adminRestoreBackup
+	// ---
+	// summary: Restore a repository from a finished repository backup as a new repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the backup
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RestoreBackupOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: The repository with the same name already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.RestoreBackupOption)
+	backup := getBackupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	owner, err := models.GetUserByName(form.Owner)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("owner does not exist: %s", form.Owner))
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+
+	repoName := form.RepoName
+	if repoName == "" {
+		repoName = backup.RepoName
+	}
+	has, err := models.IsRepositoryExist(owner, repoName)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsRepositoryExist", err)
+		return
+	} else if has {
+		ctx.Error(http.StatusConflict, "", "The repository with the same name already exists.")
+		return
+	}
+
+	repo, err := backup_service.RestoreRepository(ctx, backup, owner, repoName)
+	if err != nil {
+		if err == backup_service.ErrBackupNotRestorable {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RestoreRepository", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToRepo(repo, models.AccessModeOwner))
+}
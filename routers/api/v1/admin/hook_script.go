@@ -0,0 +1,139 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListHookScripts api for listing the hook scripts of all repositories
+func ListHookScripts(ctx *context.APIContext) {
+	// swagger:operation GET /admin/hook_scripts admin adminListHookScripts
+	// ---
+	// summary: List the hook scripts of all repositories, last updated first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: approved
+	//   in: query
+	//   description: only list approved scripts if true, or scripts awaiting approval if false
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookScriptList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	opts := &models.FindRepoHookScriptsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+	}
+	if approved := ctx.FormOptionalBool("approved"); !approved.IsNone() {
+		isApproved := approved.IsTrue()
+		opts.IsApproved = &isApproved
+	}
+
+	scripts, count, err := models.FindRepoHookScripts(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoHookScripts", err)
+		return
+	}
+
+	apiScripts := make([]*api.HookScript, 0, len(scripts))
+	for _, script := range scripts {
+		if err := script.LoadAttributes(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+			return
+		}
+		apiScripts = append(apiScripts, convert.ToHookScript(script, ctx.User))
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiScripts)
+}
+
+func setHookScriptApproval(ctx *context.APIContext, approve bool) {
+	script, err := models.GetRepoHookScriptByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoHookScriptNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoHookScriptByID", err)
+		}
+		return
+	}
+
+	if err := models.ApproveRepoHookScript(script, ctx.User, approve); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ApproveRepoHookScript", err)
+		return
+	}
+	if err := script.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToHookScript(script, ctx.User))
+}
+
+// ApproveHookScript api for approving a hook script, so it is run
+func ApproveHookScript(ctx *context.APIContext) {
+	// swagger:operation POST /admin/hook_scripts/{id}/approval admin adminApproveHookScript
+	// ---
+	// summary: Approve a hook script, so it is run until its content is changed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook script to approve
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookScript"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setHookScriptApproval(ctx, true)
+}
+
+// RevokeHookScriptApproval api for revoking the approval of a hook script, so it is no longer run
+func RevokeHookScriptApproval(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/hook_scripts/{id}/approval admin adminRevokeHookScriptApproval
+	// ---
+	// summary: Revoke the approval of a hook script, so it is no longer run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook script to revoke the approval of
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookScript"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setHookScriptApproval(ctx, false)
+}
@@ -0,0 +1,162 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+func toIssueIndexerStatus(s *issue_indexer.Status) *api.IssueIndexerStatus {
+	status := &api.IssueIndexerStatus{
+		Type:        s.Type,
+		Pending:     s.Pending,
+		Reindexing:  s.Reindexing,
+		SwitchingTo: s.SwitchingTo,
+	}
+	if s.LastIndexed > 0 {
+		lastIndexed := s.LastIndexed.AsTime()
+		status.LastIndexed = &lastIndexed
+	}
+	return status
+}
+
+// GetIssueIndexerStatus api for getting the state of the issue indexer
+func GetIssueIndexerStatus(ctx *context.APIContext) {
+	// swagger:operation GET /admin/indexer/issues admin adminGetIssueIndexerStatus
+	// ---
+	// summary: Get the state of the issue indexer, including the number of updates not yet indexed
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueIndexerStatus"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	ctx.JSON(http.StatusOK, toIssueIndexerStatus(issue_indexer.GetStatus()))
+}
+
+// ReindexIssues api for reindexing the issues of all repositories or of one repository
+func ReindexIssues(ctx *context.APIContext) {
+	// swagger:operation POST /admin/indexer/issues/reindex admin adminReindexIssues
+	// ---
+	// summary: Reindex the issues of all repositories or of one repository asynchronously
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ReindexIssuesOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/IssueIndexerStatus"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.ReindexIssuesOption)
+	if (form.Owner == "") != (form.Repo == "") {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("owner and repo must be given together"))
+		return
+	}
+
+	if form.Repo != "" {
+		repo, err := models.GetRepositoryByOwnerAndName(form.Owner, form.Repo)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return
+		}
+		issue_indexer.ReindexRepo(repo)
+	} else if err := issue_indexer.ReindexAll(); err != nil {
+		if err == issue_indexer.ErrReindexRunning {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ReindexAll", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusAccepted, toIssueIndexerStatus(issue_indexer.GetStatus()))
+}
+
+// SwitchIssueIndexer api for switching the issue indexer to another backend
+func SwitchIssueIndexer(ctx *context.APIContext) {
+	// swagger:operation POST /admin/indexer/issues/switch admin adminSwitchIssueIndexer
+	// ---
+	// summary: Switch the issue indexer to another backend without downtime
+	// description: Until the new indexer is populated, updates are written to both indexers and searches are
+	//   answered by the current one. The change is not saved, the config file has to be changed to keep the
+	//   new indexer after a restart.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SwitchIssueIndexerOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/IssueIndexerStatus"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.SwitchIssueIndexerOption)
+
+	path := form.Path
+	switch form.Type {
+	case "bleve":
+		if path == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("path is required for the bleve indexer"))
+			return
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(setting.AppWorkPath, path)
+		}
+		path = filepath.ToSlash(path)
+	case "elasticsearch", "meilisearch":
+		if form.ConnStr == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("conn_str is required for the %s indexer", form.Type))
+			return
+		}
+	}
+	indexerName := form.IndexerName
+	if indexerName == "" {
+		indexerName = "gitea_issues"
+	}
+
+	if err := issue_indexer.SwitchIndexer(form.Type, path, form.ConnStr, indexerName); err != nil {
+		switch err {
+		case issue_indexer.ErrReindexRunning, issue_indexer.ErrIndexerInUse, issue_indexer.ErrIndexerNotQueued:
+			ctx.Error(http.StatusConflict, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "SwitchIndexer", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusAccepted, toIssueIndexerStatus(issue_indexer.GetStatus()))
+}
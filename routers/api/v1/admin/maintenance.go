@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/maintenance"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	maintenance_service "code.gitea.io/gitea/services/maintenance"
+)
+
+func toMaintenanceMode(s maintenance.State) *api.MaintenanceMode {
+	m := &api.MaintenanceMode{
+		Enabled:    s.Enabled,
+		Message:    s.Message,
+		RetryAfter: int64(s.RetryAfter / time.Second),
+	}
+	if s.Enabled {
+		m.Since = s.Since
+	}
+	return m
+}
+
+// GetMaintenanceMode api for getting the maintenance mode
+func GetMaintenanceMode(ctx *context.APIContext) {
+	// swagger:operation GET /admin/maintenance admin adminGetMaintenanceMode
+	// ---
+	// summary: Get the maintenance mode of the instance
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MaintenanceMode"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	ctx.JSON(http.StatusOK, toMaintenanceMode(maintenance.Get()))
+}
+
+// EditMaintenanceMode api for enabling or disabling the maintenance mode
+func EditMaintenanceMode(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/maintenance admin adminEditMaintenanceMode
+	// ---
+	// summary: Enable or disable the maintenance mode of the instance, which rejects writes while enabled
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditMaintenanceModeOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MaintenanceMode"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.EditMaintenanceModeOption)
+	if form.RetryAfter < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("retry_after must not be negative"))
+		return
+	}
+
+	var err error
+	if form.Enabled {
+		err = maintenance_service.Enable(ctx.User, form.Message, time.Duration(form.RetryAfter)*time.Second)
+	} else {
+		err = maintenance_service.Disable(ctx.User)
+	}
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetMaintenanceMode", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toMaintenanceMode(maintenance.Get()))
+}
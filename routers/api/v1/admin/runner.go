@@ -0,0 +1,118 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListRunners api for listing the global workflow runners
+func ListRunners(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runners admin adminListRunners
+	// ---
+	// summary: List the global workflow runners
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRunnerList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	runners, err := models.FindWorkflowRunners(0, 0)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindWorkflowRunners", err)
+		return
+	}
+
+	apiRunners := make([]*api.WorkflowRunner, 0, len(runners))
+	for _, r := range runners {
+		apiRunners = append(apiRunners, convert.ToWorkflowRunner(r))
+	}
+	ctx.JSON(http.StatusOK, apiRunners)
+}
+
+// CreateRunner api for registering a global workflow runner
+func CreateRunner(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runners admin adminCreateRunner
+	// ---
+	// summary: Register a global workflow runner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateWorkflowRunnerOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/WorkflowRunner"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateWorkflowRunnerOption)
+	runner := &models.WorkflowRunner{
+		Name:   form.Name,
+		Labels: form.Labels,
+	}
+	if err := models.CreateWorkflowRunner(runner); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateWorkflowRunner", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToWorkflowRunner(runner))
+}
+
+// DeleteRunner api for deleting a global workflow runner
+func DeleteRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/runners/{id} admin adminDeleteRunner
+	// ---
+	// summary: Delete a global workflow runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the runner to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	runner, err := models.GetWorkflowRunnerByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrWorkflowRunnerNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetWorkflowRunnerByID", err)
+		}
+		return
+	}
+	if runner.OwnerID != 0 || runner.RepoID != 0 {
+		ctx.NotFound()
+		return
+	}
+
+	if err := models.DeleteWorkflowRunnerByID(runner.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteWorkflowRunnerByID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
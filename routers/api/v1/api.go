@@ -75,6 +75,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/activitypub"
 	"code.gitea.io/gitea/routers/api/v1/admin"
 	"code.gitea.io/gitea/routers/api/v1/misc"
 	"code.gitea.io/gitea/routers/api/v1/notify"
@@ -401,6 +402,56 @@ func reqWebhooksEnabled() func(ctx *context.APIContext) {
 	}
 }
 
+// reqAdvisoriesEnabled requires security advisories to be enabled by admin.
+func reqAdvisoriesEnabled() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if !setting.Advisories.Enabled {
+			ctx.Error(http.StatusForbidden, "", "security advisories disabled by administrator")
+			return
+		}
+	}
+}
+
+// reqHookScriptsEnabled requires hook scripts to be enabled by admin.
+func reqHookScriptsEnabled() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if !setting.HookScripts.Enabled {
+			ctx.Error(http.StatusForbidden, "", "hook scripts disabled by administrator")
+			return
+		}
+	}
+}
+
+// reqEventStreamEnabled requires the event stream to be enabled by admin.
+func reqEventStreamEnabled() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if !setting.EventStream.Enabled {
+			ctx.Error(http.StatusForbidden, "", "event stream disabled by administrator")
+			return
+		}
+	}
+}
+
+// reqFederationEnabled requires federation to be enabled by admin.
+func reqFederationEnabled() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if !setting.Federation.Enabled {
+			ctx.Error(http.StatusForbidden, "", "federation disabled by administrator")
+			return
+		}
+	}
+}
+
+// reqPagesEnabled requires pages to be enabled by admin.
+func reqPagesEnabled() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if !setting.Pages.Enabled {
+			ctx.Error(http.StatusForbidden, "", "pages disabled by administrator")
+			return
+		}
+	}
+}
+
 func orgAssignment(args ...bool) func(ctx *context.APIContext) {
 	var (
 		assignOrg  bool
@@ -528,6 +579,32 @@ func mustNotBeArchived(ctx *context.APIContext) {
 	}
 }
 
+// reqBodySize limits the size of the request body, reading more fails
+func reqBodySize(maxSize int64) func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		ctx.Req.Body = http.MaxBytesReader(ctx.Resp, ctx.Req.Body, maxSize)
+	}
+}
+
+// fileContentBodySize returns the maximum size of a request body with the base64 encoded content of a file
+func fileContentBodySize() int64 {
+	return setting.Repository.Upload.FileMaxSize*1024*1024*4/3 + 64*1024
+}
+
+// previewFileEdit handles a POST to /contents/{filepath}/preview by the handlers of the file edit preview,
+// other paths are left to the next handlers of the route
+func previewFileEdit(handlers ...interface{}) func(ctx *context.APIContext) {
+	preview := web.Wrap(handlers...)
+	return func(ctx *context.APIContext) {
+		treePath := ctx.Params("*")
+		if !strings.HasSuffix(treePath, "/preview") {
+			return
+		}
+		ctx.SetParams("*", strings.TrimSuffix(treePath, "/preview"))
+		preview(ctx.Resp, ctx.Req)
+	}
+}
+
 // bind binding an obj to a func(ctx *context.APIContext)
 func bind(obj interface{}) http.HandlerFunc {
 	var tp = reflect.TypeOf(obj)
@@ -583,6 +660,8 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 		m.Get("/signing-key.gpg", misc.SigningKey)
 		m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
 		m.Post("/markdown/raw", misc.MarkdownRaw)
+		m.Get("/file-templates", misc.ListFileTemplates)
+		m.Get("/search", reqExploreSignIn(), misc.Search)
 		m.Group("/settings", func() {
 			m.Get("/ui", settings.GetGeneralUISettings)
 			m.Get("/api", settings.GetGeneralAPISettings)
@@ -601,6 +680,26 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				Patch(notify.ReadThread)
 		}, reqToken())
 
+		// Event stream
+		m.Get("/events/stream", reqToken(), reqEventStreamEnabled(), misc.EventStream)
+
+		// ActivityPub
+		m.Group("/activitypub", func() {
+			m.Group("/user-id/{user-id}", func() {
+				m.Get("", activitypub.Person)
+				m.Post("/inbox", activitypub.PersonInbox)
+				m.Get("/outbox", activitypub.PersonOutbox)
+				m.Get("/followers", activitypub.PersonFollowers)
+			})
+			m.Group("/repository-id/{repository-id}", func() {
+				m.Get("", activitypub.Repository)
+				m.Post("/inbox", activitypub.RepositoryInbox)
+				m.Get("/outbox", activitypub.RepositoryOutbox)
+				m.Get("/followers", activitypub.RepositoryFollowers)
+			})
+			m.Get("/activity/{uuid}", activitypub.Activity)
+		}, reqFederationEnabled())
+
 		// Users
 		m.Group("/users", func() {
 			m.Get("/search", reqExploreSignIn(), user.Search)
@@ -613,6 +712,9 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				}
 
 				m.Get("/repos", reqExploreSignIn(), user.ListUserRepos)
+				m.Get("/redirects", reqExploreSignIn(), user.ListRedirects)
+				m.Get("/profile_readme", reqExploreSignIn(), user.GetProfileReadme)
+				m.Get("/pinned_repos", reqExploreSignIn(), user.ListPinnedRepos)
 				m.Group("/tokens", func() {
 					m.Combo("").Get(user.ListAccessTokens).
 						Post(bind(api.CreateAccessTokenOption{}), user.CreateAccessToken)
@@ -633,6 +735,10 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				})
 
 				m.Get("/starred", user.GetStarredRepos)
+				m.Group("/star-lists", func() {
+					m.Get("", user.ListUserStarLists)
+					m.Get("/{id}/repos", user.ListUserStarListRepos)
+				})
 
 				m.Get("/subscriptions", user.GetWatchedRepos)
 			})
@@ -644,6 +750,10 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				m.Get("", user.GetUserSettings)
 				m.Patch("", bind(api.UserSettingsOptions{}), user.UpdateUserSettings)
 			}, reqToken())
+			m.Combo("/avatar", reqToken()).
+				Post(bind(api.UpdateAvatarOption{}), user.UpdateAvatar).
+				Delete(user.DeleteAvatar)
+			m.Put("/pinned_repos", reqToken(), bind(api.SetPinnedReposOption{}), user.SetMyPinnedRepos)
 			m.Combo("/emails").Get(user.ListEmails).
 				Post(bind(api.CreateEmailOption{}), user.AddEmail).
 				Delete(bind(api.DeleteEmailOption{}), user.DeleteEmail)
@@ -691,6 +801,19 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					m.Delete("", user.Unstar)
 				}, repoAssignment())
 			})
+			m.Group("/star-lists", func() {
+				m.Combo("").Get(user.ListMyStarLists).
+					Post(bind(api.CreateStarListOption{}), user.CreateStarList)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(user.GetMyStarList).
+						Patch(bind(api.EditStarListOption{}), user.EditStarList).
+						Delete(user.DeleteStarList)
+					m.Get("/repos", user.ListMyStarListRepos)
+					m.Combo("/repos/{username}/{reponame}", repoAssignment()).
+						Put(user.AddRepoToStarList).
+						Delete(user.RemoveRepoFromStarList)
+				})
+			})
 			m.Get("/times", repo.ListMyTrackedTimes)
 
 			m.Get("/stopwatches", repo.GetStopwatches)
@@ -698,6 +821,47 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
 
 			m.Get("/teams", org.ListUserTeams)
+
+			m.Group("/issues", func() {
+				m.Get("/assigned", user.ListMyAssignedIssues)
+				m.Get("/mentioned", user.ListMyMentionedIssues)
+				m.Get("/participated", user.ListMyParticipatedIssues)
+				m.Get("/review_requested", user.ListMyReviewRequests)
+			})
+
+			m.Group("/issue_filters", func() {
+				m.Combo("").Get(user.ListIssueFilters).
+					Post(bind(api.CreateIssueFilterOption{}), user.CreateIssueFilter)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(user.GetIssueFilter).
+						Patch(bind(api.EditIssueFilterOption{}), user.EditIssueFilter).
+						Delete(user.DeleteIssueFilter)
+					m.Get("/issues", user.SearchIssueFilter)
+				})
+			})
+
+			m.Group("/push", func() {
+				m.Get("/config", user.GetPushConfig)
+				m.Combo("/devices").Get(user.ListPushDevices).
+					Post(bind(api.CreatePushDeviceOption{}), user.RegisterPushDevice)
+				m.Delete("/devices/{id}", user.DeletePushDevice)
+			})
+			m.Get("/notification-channels", user.ListNotificationChannels)
+			m.Put("/notification-channels/{channel}", bind(api.EditNotificationChannelOption{}), user.EditNotificationChannel)
+
+			m.Group("/chat-addresses", func() {
+				m.Get("", user.ListChatAddresses)
+				m.Combo("/{protocol}").Put(bind(api.BindChatAddressOption{}), user.BindChatAddress).
+					Delete(user.DeleteChatAddress)
+				m.Post("/{protocol}/verify", bind(api.VerifyChatAddressOption{}), user.VerifyChatAddress)
+			})
+
+			m.Group("/federation", func() {
+				m.Combo("/following").Get(user.ListFederationFollowing).
+					Post(bind(api.FollowRemoteActorOption{}), user.FollowRemoteActor)
+				m.Delete("/following/{id}", user.UnfollowRemoteActor)
+				m.Get("/feed", user.ListFederationFeed)
+			}, reqFederationEnabled())
 		}, reqToken())
 
 		// Repositories
@@ -707,10 +871,13 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 
 		m.Group("/repos", func() {
 			m.Get("/search", repo.Search)
+			m.Get("/trending", repo.ListTrending)
+			m.Get("/code-search", repo.SearchCode)
 
 			m.Get("/issues/search", repo.SearchIssues)
 
 			m.Post("/migrate", reqToken(), bind(api.MigrateRepoOptions{}), repo.Migrate)
+			m.Post("/migrate/dry-run", reqToken(), bind(api.MigrateRepoOptions{}), repo.MigrateDryRun)
 
 			m.Group("/{username}/{reponame}", func() {
 				m.Combo("").Get(reqAnyRepoReader(), repo.Get).
@@ -718,6 +885,8 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					Patch(reqToken(), reqAdmin(), bind(api.EditRepoOption{}), repo.Edit)
 				m.Post("/generate", reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.GenerateRepoOption{}), repo.Generate)
 				m.Post("/transfer", reqOwner(), bind(api.TransferRepoOption{}), repo.Transfer)
+				m.Get("/redirects", reqAnyRepoReader(), repo.ListRedirects)
+				m.Post("/migrate/sync", reqToken(), reqAdmin(), bind(api.SyncMigrationOptions{}), repo.SyncMigration)
 				m.Combo("/notifications").
 					Get(reqToken(), notify.ListRepoNotifications).
 					Put(reqToken(), notify.ReadRepoNotifications)
@@ -729,6 +898,13 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							Delete(repo.DeleteGitHook)
 					})
 				}, reqToken(), reqAdmin(), reqGitHook(), context.ReferencesGitRepo(true))
+				m.Group("/hooks/scripts", func() {
+					m.Combo("").Get(repo.ListHookScripts).
+						Post(bind(api.CreateHookScriptOption{}), repo.CreateHookScript)
+					m.Combo("/{id}").Get(repo.GetHookScript).
+						Patch(bind(api.EditHookScriptOption{}), repo.EditHookScript).
+						Delete(repo.DeleteHookScript)
+				}, reqToken(), reqAdmin(), reqHookScriptsEnabled())
 				m.Group("/hooks", func() {
 					m.Combo("").Get(repo.ListHooks).
 						Post(bind(api.CreateHookOption{}), repo.CreateHook)
@@ -744,6 +920,7 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					m.Combo("/{collaborator}").Get(reqAnyRepoReader(), repo.IsCollaborator).
 						Put(reqAdmin(), bind(api.AddCollaboratorOption{}), repo.AddCollaborator).
 						Delete(reqAdmin(), repo.DeleteCollaborator)
+					m.Get("/{collaborator}/permission", reqAnyRepoReader(), repo.GetRepoPermissions)
 				}, reqToken())
 				m.Get("/assignees", reqToken(), reqAnyRepoReader(), repo.GetAssignees)
 				m.Get("/reviewers", reqToken(), reqAnyRepoReader(), repo.GetReviewers)
@@ -754,6 +931,8 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 						Delete(reqAdmin(), repo.DeleteTeam)
 				}, reqToken())
 				m.Get("/raw/*", context.RepoRefForAPI, reqRepoReader(models.UnitTypeCode), repo.GetRawFile)
+				m.Get("/preview/*", context.RepoRefForAPI, reqRepoReader(models.UnitTypeCode), repo.GetFilePreview)
+				m.Get("/render/*", context.RepoRefForAPI, reqRepoReader(models.UnitTypeCode), repo.GetRenderedFile)
 				m.Get("/archive/*", reqRepoReader(models.UnitTypeCode), repo.GetArchive)
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
@@ -763,6 +942,8 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					m.Delete("/*", context.ReferencesGitRepo(false), reqRepoWriter(models.UnitTypeCode), repo.DeleteBranch)
 					m.Post("", reqRepoWriter(models.UnitTypeCode), bind(api.CreateBranchRepoOption{}), repo.CreateBranch)
 				}, reqRepoReader(models.UnitTypeCode))
+				m.Post("/default-branch/rename", reqToken(), reqAdmin(), mustNotBeArchived, context.ReferencesGitRepo(false),
+					bind(api.RenameDefaultBranchOption{}), repo.RenameDefaultBranch)
 				m.Group("/branch_protections", func() {
 					m.Get("", repo.ListBranchProtections)
 					m.Post("", bind(api.CreateBranchProtectionOption{}), repo.CreateBranchProtection)
@@ -790,7 +971,11 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				}, mustEnableIssues, reqToken())
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
-						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
+						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), context.ReferencesGitRepo(true), repo.CreateIssue)
+					m.Combo("/pinned").Get(repo.ListPinnedIssues).
+						Put(reqToken(), reqRepoWriter(models.UnitTypeIssues), bind(api.SetPinnedIssuesOption{}), repo.SetPinnedIssues)
+					m.Get("/sla", repo.GetIssueSLAReport)
+					m.Get("/sla/compliance", repo.GetIssueSLACompliance)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Group("/{id}", func() {
@@ -807,6 +992,18 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					m.Group("/{index}", func() {
 						m.Combo("").Get(repo.GetIssue).
 							Patch(reqToken(), bind(api.EditIssueOption{}), repo.EditIssue)
+						m.Get("/form", repo.GetIssueForm)
+						m.Get("/branches", repo.ListIssueBranches)
+						m.Post("/create-branch", reqToken(), mustNotBeArchived, reqRepoWriter(models.UnitTypeCode),
+							bind(api.CreateIssueBranchOption{}), repo.CreateIssueBranch)
+						m.Group("/relations", func() {
+							m.Combo("").Get(repo.ListIssueRelations).
+								Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueRelationOption{}), repo.CreateIssueRelation)
+							m.Delete("/{id}", reqToken(), mustNotBeArchived, repo.DeleteIssueRelation)
+						})
+						m.Combo("/custom_fields").Get(repo.GetIssueCustomFieldValues).
+							Put(reqToken(), mustNotBeArchived, bind(api.SetIssueCustomFieldValuesOption{}), repo.SetIssueCustomFieldValues)
+						m.Put("/read", reqToken(), repo.MarkIssueRead)
 						m.Group("/comments", func() {
 							m.Combo("").Get(repo.ListIssueComments).
 								Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
@@ -828,6 +1025,9 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							m.Delete("/{id}", repo.DeleteTime)
 						}, reqToken())
 						m.Combo("/deadline").Post(reqToken(), bind(api.EditDeadlineOption{}), repo.UpdateIssueDeadline)
+						m.Combo("/lock", reqToken()).
+							Put(bind(api.LockIssueOption{}), repo.LockIssue).
+							Delete(repo.UnlockIssue)
 						m.Group("/stopwatch", func() {
 							m.Post("/start", reqToken(), repo.StartIssueStopwatch)
 							m.Post("/stop", reqToken(), repo.StopIssueStopwatch)
@@ -837,6 +1037,7 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							m.Get("", repo.GetIssueSubscribers)
 							m.Get("/check", reqToken(), repo.CheckIssueSubscription)
 							m.Put("/{user}", reqToken(), repo.AddIssueSubscription)
+							m.Patch("/{user}", reqToken(), bind(api.EditSubscriptionOption{}), repo.EditIssueSubscription)
 							m.Delete("/{user}", reqToken(), repo.DelIssueSubscription)
 						})
 						m.Combo("/reactions").
@@ -852,6 +1053,45 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditLabelOption{}), repo.EditLabel).
 						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteLabel)
 				})
+				m.Group("/issue_custom_fields", func() {
+					m.Combo("").Get(repo.ListIssueCustomFields).
+						Post(reqToken(), reqAdmin(), bind(api.CreateIssueCustomFieldOption{}), repo.CreateIssueCustomField)
+					m.Combo("/{id}", reqToken(), reqAdmin()).
+						Patch(bind(api.EditIssueCustomFieldOption{}), repo.EditIssueCustomField).
+						Delete(repo.DeleteIssueCustomField)
+				}, mustEnableIssuesOrPulls)
+				m.Group("/iteration_cadences", func() {
+					m.Combo("").Get(repo.ListIterationCadences).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues), bind(api.CreateIterationCadenceOption{}), repo.CreateIterationCadence)
+					m.Combo("/{id}", reqToken(), reqRepoWriter(models.UnitTypeIssues)).
+						Patch(bind(api.EditIterationCadenceOption{}), repo.EditIterationCadence).
+						Delete(repo.DeleteIterationCadence)
+				}, mustEnableIssues)
+				m.Group("/iterations", func() {
+					m.Get("", repo.ListIterations)
+					m.Get("/{id}", repo.GetIteration)
+				}, mustEnableIssues)
+				m.Group("/workflow_states", func() {
+					m.Combo("").Get(repo.ListIssueWorkflowStates).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateIssueWorkflowStateOption{}), repo.CreateIssueWorkflowState)
+					m.Combo("/{id}").Get(repo.GetIssueWorkflowState).
+						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditIssueWorkflowStateOption{}), repo.EditIssueWorkflowState).
+						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteIssueWorkflowState)
+				}, mustEnableIssuesOrPulls)
+				m.Group("/priorities", func() {
+					m.Combo("").Get(repo.ListIssuePriorities).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateIssuePriorityOption{}), repo.CreateIssuePriority)
+					m.Combo("/{id}").Get(repo.GetIssuePriority).
+						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditIssuePriorityOption{}), repo.EditIssuePriority).
+						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteIssuePriority)
+				}, mustEnableIssuesOrPulls)
+				m.Group("/sla_policies", func() {
+					m.Combo("").Get(repo.ListIssueSLAPolicies).
+						Post(reqToken(), reqAdmin(), bind(api.CreateIssueSLAPolicyOption{}), repo.CreateIssueSLAPolicy)
+					m.Combo("/{id}").Get(repo.GetIssueSLAPolicy).
+						Patch(reqToken(), reqAdmin(), bind(api.EditIssueSLAPolicyOption{}), repo.EditIssueSLAPolicy).
+						Delete(reqToken(), reqAdmin(), repo.DeleteIssueSLAPolicy)
+				}, mustEnableIssues)
 				m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
 				m.Post("/markdown/raw", misc.MarkdownRaw)
 				m.Group("/milestones", func() {
@@ -860,12 +1100,16 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					m.Combo("/{id}").Get(repo.GetMilestone).
 						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditMilestoneOption{}), repo.EditMilestone).
 						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteMilestone)
+					m.Get("/{id}/burndown", repo.GetMilestoneBurndown)
+					m.Post("/{id}/clone", reqToken(), bind(api.CloneMilestoneOption{}), repo.CloneMilestone)
+					m.Post("/{id}/migrate-issues", reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.MigrateMilestoneIssues)
 				})
 				m.Get("/stargazers", repo.ListStargazers)
 				m.Get("/subscribers", repo.ListSubscribers)
 				m.Group("/subscription", func() {
 					m.Get("", user.IsWatching)
 					m.Put("", reqToken(), user.Watch)
+					m.Patch("", reqToken(), bind(api.EditSubscriptionOption{}), user.EditWatch)
 					m.Delete("", reqToken(), user.Unwatch)
 				})
 				m.Group("/releases", func() {
@@ -902,6 +1146,10 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 						m.Get("/commits", repo.GetPullRequestCommits)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
 							Post(reqToken(), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.MergePullRequest)
+						m.Get("/merge-message", repo.GetPullRequestMergeMessage)
+						m.Get("/merge_eligibility", repo.GetPullRequestMergeEligibility)
+						m.Get("/suggested-reviewers", repo.GetPullRequestSuggestedReviewers)
+						m.Get("/codeowners", repo.GetPullRequestCodeOwners)
 						m.Group("/reviews", func() {
 							m.Combo("").
 								Get(repo.ListPullReviews).
@@ -922,6 +1170,52 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							Post(reqToken(), bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests)
 					})
 				}, mustAllowPulls, reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false))
+				m.Group("/secrets", func() {
+					m.Get("", repo.ListSecrets)
+					m.Combo("/{secretname}").
+						Put(bind(api.CreateOrUpdateSecretOption{}), repo.CreateOrUpdateSecret).
+						Delete(repo.DeleteSecret)
+				}, reqToken(), reqAdmin())
+				m.Group("/runners", func() {
+					m.Combo("").Get(repo.ListRunners).
+						Post(bind(api.CreateWorkflowRunnerOption{}), repo.CreateRunner)
+					m.Delete("/{id}", repo.DeleteRunner)
+				}, reqToken(), reqAdmin())
+				m.Group("/badges", func() {
+					m.Get("/commit-status/*", context.ReferencesGitRepo(false), repo.GetCommitStatusBadge)
+					m.Get("/workflows/*", repo.GetWorkflowBadge)
+				}, reqRepoReader(models.UnitTypeCode))
+				m.Group("/backport-mappings", func() {
+					m.Combo("").Get(repo.ListBackportMappings).
+						Post(bind(api.CreateBackportMappingOption{}), repo.CreateBackportMapping)
+					m.Delete("/{id}", repo.DeleteBackportMapping)
+				}, reqToken(), reqAdmin())
+				m.Group("/auto_release", func() {
+					m.Combo("").Get(repo.GetRepoAutoRelease).
+						Put(reqToken(), reqAdmin(), bind(api.CreateOrUpdateRepoAutoReleaseOption{}), repo.CreateOrUpdateRepoAutoRelease).
+						Delete(reqToken(), reqAdmin(), repo.DeleteRepoAutoRelease)
+				}, reqRepoReader(models.UnitTypeReleases))
+				m.Group("/pages", func() {
+					m.Combo("").Get(repo.GetRepoPages).
+						Put(reqToken(), reqAdmin(), bind(api.CreateOrUpdateRepoPagesOption{}), repo.CreateOrUpdateRepoPages).
+						Delete(reqToken(), reqAdmin(), repo.DeleteRepoPages)
+					m.Post("/publish", reqToken(), reqRepoWriter(models.UnitTypeCode), repo.PublishRepoPages)
+					m.Post("/verify", reqToken(), reqAdmin(), repo.VerifyRepoPagesCustomDomain)
+				}, reqRepoReader(models.UnitTypeCode), reqPagesEnabled())
+				m.Group("/schedules", func() {
+					m.Combo("").Get(repo.ListSchedules).
+						Post(bind(api.CreateRepoScheduleOption{}), repo.CreateSchedule)
+					m.Combo("/{id}").Get(repo.GetSchedule).
+						Patch(bind(api.EditRepoScheduleOption{}), repo.EditSchedule).
+						Delete(repo.DeleteSchedule)
+				}, reqToken(), reqAdmin())
+				m.Group("/workflows/runs", func() {
+					m.Get("", repo.ListWorkflowRuns)
+					m.Group("/{id}", func() {
+						m.Get("", repo.GetWorkflowRun)
+						m.Post("/cancel", reqToken(), reqRepoWriter(models.UnitTypeCode), repo.CancelWorkflowRun)
+					})
+				}, reqRepoReader(models.UnitTypeCode))
 				m.Group("/statuses", func() {
 					m.Combo("/{sha}").Get(repo.GetCommitStatuses).
 						Post(reqToken(), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
@@ -935,34 +1229,101 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				}, reqRepoReader(models.UnitTypeCode))
 				m.Group("/git", func() {
 					m.Group("/commits", func() {
+						m.Group("", func() {
+							m.Post("", bind(api.CreateCommitOption{}), repo.CreateCommit)
+							m.Post("/batch", bind(api.CreateCommitsBatchOption{}), repo.CreateCommitsBatch)
+							m.Post("/{sha}/cherry-pick", bind(api.ApplyCommitOption{}), repo.CherryPickCommit)
+							m.Post("/{sha}/revert", bind(api.ApplyCommitOption{}), repo.RevertCommit)
+						}, reqToken(), reqRepoWriter(models.UnitTypeCode))
 						m.Get("/{sha}", repo.GetSingleCommit)
 						m.Get("/{sha}.{diffType:diff|patch}", repo.DownloadCommitDiffOrPatch)
+						m.Get("/{sha}/backports", context.ReferencesGitRepo(false), repo.GetCommitBackports)
+						m.Get("/{sha}/verification", repo.GetCommitVerification)
 					})
 					m.Get("/refs", repo.GetGitAllRefs)
 					m.Get("/refs/*", repo.GetGitRefs)
+					m.Combo("/refs/*", reqToken(), reqRepoWriter(models.UnitTypeCode)).
+						Post(bind(api.CreateGitRefOption{}), repo.CreateGitRef).
+						Patch(bind(api.UpdateGitRefOption{}), repo.UpdateGitRef).
+						Delete(repo.DeleteGitRef)
+					m.Post("/trees", reqToken(), reqRepoWriter(models.UnitTypeCode), bind(api.CreateGitTreeOption{}), repo.CreateTree)
 					m.Get("/trees/{sha}", context.RepoRefForAPI, repo.GetTree)
+					m.Post("/blobs", reqToken(), reqRepoWriter(models.UnitTypeCode), repo.CreateBlob)
 					m.Get("/blobs/{sha}", context.RepoRefForAPI, repo.GetBlob)
 					m.Get("/tags/{sha}", context.RepoRefForAPI, repo.GetAnnotatedTag)
 					m.Get("/notes/{sha}", repo.GetNote)
 				}, reqRepoReader(models.UnitTypeCode))
 				m.Group("/contents", func() {
 					m.Get("", repo.GetContentsList)
+					m.Post("/generate", reqToken(), reqRepoWriter(models.UnitTypeCode), bind(api.GenerateFilesOptions{}), repo.GenerateFiles)
 					m.Get("/*", repo.GetContents)
+					m.Post("/*", previewFileEdit(reqRepoReader(models.UnitTypeCode), reqBodySize(fileContentBodySize()), bind(api.PreviewFileEditOptions{}), repo.PreviewFileEdit),
+						reqRepoWriter(models.UnitTypeCode), reqToken(), bind(api.CreateFileOptions{}), repo.CreateFile)
 					m.Group("/*", func() {
-						m.Post("", bind(api.CreateFileOptions{}), repo.CreateFile)
 						m.Put("", bind(api.UpdateFileOptions{}), repo.UpdateFile)
 						m.Delete("", bind(api.DeleteFileOptions{}), repo.DeleteFile)
 					}, reqRepoWriter(models.UnitTypeCode), reqToken())
 				}, reqRepoReader(models.UnitTypeCode))
 				m.Get("/signing-key.gpg", misc.SigningKey)
+				m.Combo("/avatar", reqToken(), reqAdmin()).
+					Post(bind(api.UpdateAvatarOption{}), repo.UpdateAvatar).
+					Delete(repo.DeleteAvatar)
 				m.Group("/topics", func() {
 					m.Combo("").Get(repo.ListTopics).
 						Put(reqToken(), reqAdmin(), bind(api.RepoTopicOptions{}), repo.UpdateTopics)
+					m.Get("/suggest", repo.SuggestTopics)
 					m.Group("/{topic}", func() {
 						m.Combo("").Put(reqToken(), repo.AddTopic).
 							Delete(reqToken(), repo.DeleteTopic)
 					}, reqAdmin())
 				}, reqAnyRepoReader())
+				m.Get("/code-search", reqRepoReader(models.UnitTypeCode), repo.SearchRepoCode)
+				m.Get("/compare/*", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), repo.CompareRendered)
+				m.Get("/readme", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), repo.GetReadme)
+				m.Post("/codeowners/validate", reqToken(), reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(true),
+					bind(api.ValidateCodeOwnersOption{}), repo.ValidateCodeOwners)
+				m.Group("/symbols", func() {
+					m.Get("/definitions", repo.ListSymbolDefinitions)
+					m.Get("/references", repo.ListSymbolReferences)
+				}, reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false))
+				m.Group("/dependencies", func() {
+					m.Get("", repo.ListDependencies)
+					m.Get("/sbom", repo.GetDependencySBOM)
+				}, reqRepoReader(models.UnitTypeCode))
+				m.Group("/vulnerability-alerts", func() {
+					m.Get("", repo.ListVulnerabilityAlerts)
+					m.Combo("/settings").Get(repo.GetVulnerabilityAlertSettings).
+						Patch(bind(api.EditVulnerabilityAlertSettingsOption{}), repo.EditVulnerabilityAlertSettings)
+					m.Combo("/{id}").Get(repo.GetVulnerabilityAlert).
+						Patch(bind(api.EditVulnerabilityAlertOption{}), repo.EditVulnerabilityAlert)
+				}, reqToken(), reqAdmin(), reqAdvisoriesEnabled())
+				m.Combo("/push-rules", reqToken(), reqAdmin()).Get(repo.GetPushRule).
+					Patch(bind(api.EditPushRuleOption{}), repo.EditPushRule)
+				m.Post("/push-check", reqToken(), reqRepoWriter(models.UnitTypeCode),
+					reqBodySize(setting.Repository.MaxPushCheckPackSize*4/3+64*1024), bind(api.PushCheckOption{}), repo.CheckPush)
+				m.Group("/staging-sessions", func() {
+					m.Combo("").Get(repo.ListStagingSessions).
+						Post(bind(api.CreateStagingSessionOption{}), repo.CreateStagingSession)
+					m.Group("/{id}", func() {
+						m.Combo("").Get(repo.GetStagingSession).
+							Patch(reqBodySize(setting.Repository.MaxStagingSessionSize*4/3+64*1024), bind(api.EditStagingSessionOption{}), repo.EditStagingSession).
+							Delete(repo.DeleteStagingSession)
+						m.Post("/commit", bind(api.CommitStagingSessionOption{}), repo.CommitStagingSession)
+					})
+				}, reqToken(), reqRepoWriter(models.UnitTypeCode), context.ReferencesGitRepo(false))
+				m.Get("/mentionable-users", reqToken(), reqAnyRepoReader(), repo.ListMentionables)
+				m.Combo("/interaction-limits").Get(reqAnyRepoReader(), repo.GetInteractionLimit).
+					Put(reqToken(), reqAdmin(), bind(api.SetRepoInteractionLimitOption{}), repo.SetInteractionLimit).
+					Delete(reqToken(), reqAdmin(), repo.DeleteInteractionLimit)
+				m.Group("/federation", func() {
+					m.Combo("").Get(repo.GetFederation).
+						Put(bind(api.EditRepoFederationOption{}), repo.EditFederation).
+						Delete(repo.DeleteFederation)
+					m.Get("/stargazers", repo.ListRemoteStargazers)
+					m.Combo("/blocks").Get(repo.ListFederationBlocks).
+						Post(bind(api.CreateFederationBlockOption{}), repo.CreateFederationBlock)
+					m.Delete("/blocks/{id}", repo.DeleteFederationBlock)
+				}, reqToken(), reqAdmin(), reqFederationEnabled())
 				m.Get("/issue_templates", context.ReferencesGitRepo(false), repo.GetIssueTemplates)
 				m.Get("/languages", reqRepoReader(models.UnitTypeCode), repo.GetLanguages)
 			}, repoAssignment())
@@ -977,6 +1338,10 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 			m.Combo("").Get(org.Get).
 				Patch(reqToken(), reqOrgOwnership(), bind(api.EditOrgOption{}), org.Edit).
 				Delete(reqToken(), reqOrgOwnership(), org.Delete)
+			m.Combo("/avatar", reqToken(), reqOrgOwnership()).
+				Post(bind(api.UpdateAvatarOption{}), org.UpdateAvatar).
+				Delete(org.DeleteAvatar)
+			m.Put("/pinned_repos", reqToken(), reqOrgOwnership(), bind(api.SetPinnedReposOption{}), org.SetPinnedRepos)
 			m.Combo("/repos").Get(user.ListOrgRepos).
 				Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
 			m.Group("/members", func() {
@@ -1002,6 +1367,31 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					Patch(reqToken(), reqOrgOwnership(), bind(api.EditLabelOption{}), org.EditLabel).
 					Delete(reqToken(), reqOrgOwnership(), org.DeleteLabel)
 			})
+			m.Group("/milestones", func() {
+				m.Combo("").Get(org.ListMilestones).
+					Post(reqToken(), reqOrgOwnership(), bind(api.CreateMilestoneOption{}), org.CreateMilestone)
+				m.Combo("/{id}").Get(org.GetMilestone).
+					Patch(reqToken(), reqOrgOwnership(), bind(api.EditMilestoneOption{}), org.EditMilestone).
+					Delete(reqToken(), reqOrgOwnership(), org.DeleteMilestone)
+			})
+			m.Group("/issue_custom_fields", func() {
+				m.Combo("").Get(org.ListIssueCustomFields).
+					Post(reqToken(), reqOrgOwnership(), bind(api.CreateIssueCustomFieldOption{}), org.CreateIssueCustomField)
+				m.Combo("/{id}", reqToken(), reqOrgOwnership()).
+					Patch(bind(api.EditIssueCustomFieldOption{}), org.EditIssueCustomField).
+					Delete(org.DeleteIssueCustomField)
+			})
+			m.Group("/iteration_cadences", func() {
+				m.Combo("").Get(org.ListIterationCadences).
+					Post(reqToken(), reqOrgOwnership(), bind(api.CreateIterationCadenceOption{}), org.CreateIterationCadence)
+				m.Combo("/{id}", reqToken(), reqOrgOwnership()).
+					Patch(bind(api.EditIterationCadenceOption{}), org.EditIterationCadence).
+					Delete(org.DeleteIterationCadence)
+			})
+			m.Group("/iterations", func() {
+				m.Get("", org.ListIterations)
+				m.Get("/{id}", org.GetIteration)
+			})
 			m.Group("/hooks", func() {
 				m.Combo("").Get(org.ListHooks).
 					Post(bind(api.CreateHookOption{}), org.CreateHook)
@@ -1009,11 +1399,48 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					Patch(bind(api.EditHookOption{}), org.EditHook).
 					Delete(org.DeleteHook)
 			}, reqToken(), reqOrgOwnership(), reqWebhooksEnabled())
+			m.Group("/secrets", func() {
+				m.Get("", org.ListSecrets)
+				m.Combo("/{secretname}").
+					Put(bind(api.CreateOrUpdateSecretOption{}), org.CreateOrUpdateSecret).
+					Delete(org.DeleteSecret)
+			}, reqToken(), reqOrgOwnership())
+			m.Get("/code-search", org.SearchCode)
+			m.Get("/assignee-workload", reqToken(), reqOrgMembership(), org.GetAssigneeWorkload)
+			m.Group("/branch_protection_templates", func() {
+				m.Combo("").Get(org.ListBranchProtectionTemplates).
+					Post(bind(api.CreateBranchProtectionTemplateOption{}), org.CreateBranchProtectionTemplate)
+				m.Group("/{name}", func() {
+					m.Combo("").Get(org.GetBranchProtectionTemplate).
+						Patch(bind(api.EditBranchProtectionTemplateOption{}), org.EditBranchProtectionTemplate).
+						Delete(org.DeleteBranchProtectionTemplate)
+					m.Get("/drift", org.GetBranchProtectionTemplateDrift)
+					m.Combo("/repos/{repo}").
+						Put(org.ApplyBranchProtectionTemplate).
+						Delete(org.RemoveBranchProtectionTemplate)
+				})
+			}, reqToken(), reqOrgOwnership())
+			m.Group("/repo_settings_jobs", func() {
+				m.Combo("").Get(org.ListRepoSettingsJobs).
+					Post(bind(api.CreateRepoSettingsJobOption{}), org.CreateRepoSettingsJob)
+				m.Get("/{id}", org.GetRepoSettingsJob)
+			}, reqToken(), reqOrgOwnership())
+			m.Group("/issue_filters", func() {
+				m.Combo("").Get(org.ListIssueFilters).
+					Post(reqOrgOwnership(), bind(api.CreateIssueFilterOption{}), org.CreateIssueFilter)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(org.GetIssueFilter).
+						Patch(reqOrgOwnership(), bind(api.EditIssueFilterOption{}), org.EditIssueFilter).
+						Delete(reqOrgOwnership(), org.DeleteIssueFilter)
+					m.Get("/issues", org.SearchIssueFilter)
+				})
+			}, reqToken(), reqOrgMembership())
 		}, orgAssignment(true))
 		m.Group("/teams/{teamid}", func() {
 			m.Combo("").Get(org.GetTeam).
 				Patch(reqOrgOwnership(), bind(api.EditTeamOption{}), org.EditTeam).
 				Delete(reqOrgOwnership(), org.DeleteTeam)
+			m.Get("/teams", org.ListChildTeams)
 			m.Group("/members", func() {
 				m.Get("", org.GetTeamMembers)
 				m.Combo("/{username}").
@@ -1030,11 +1457,34 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 		}, orgAssignment(false, true), reqToken(), reqTeamMembership())
 
 		m.Group("/admin", func() {
+			m.Group("/backups", func() {
+				m.Combo("").Get(admin.ListBackups).
+					Post(bind(api.CreateBackupOption{}), admin.CreateBackup)
+				m.Get("/{id}", admin.GetBackup)
+				m.Post("/{id}/restore", bind(api.RestoreBackupOption{}), admin.RestoreBackup)
+			})
 			m.Group("/cron", func() {
 				m.Get("", admin.ListCronTasks)
 				m.Post("/{task}", admin.PostCronTask)
 			})
+			m.Combo("/maintenance").Get(admin.GetMaintenanceMode).
+				Put(bind(api.EditMaintenanceModeOption{}), admin.EditMaintenanceMode)
+			m.Group("/hook_scripts", func() {
+				m.Get("", admin.ListHookScripts)
+				m.Combo("/{id}/approval").Post(admin.ApproveHookScript).
+					Delete(admin.RevokeHookScriptApproval)
+			}, reqHookScriptsEnabled())
+			m.Group("/indexer/issues", func() {
+				m.Get("", admin.GetIssueIndexerStatus)
+				m.Post("/reindex", bind(api.ReindexIssuesOption{}), admin.ReindexIssues)
+				m.Post("/switch", bind(api.SwitchIssueIndexerOption{}), admin.SwitchIssueIndexer)
+			})
 			m.Get("/orgs", admin.GetAllOrgs)
+			m.Group("/runners", func() {
+				m.Combo("").Get(admin.ListRunners).
+					Post(bind(api.CreateWorkflowRunnerOption{}), admin.CreateRunner)
+				m.Delete("/{id}", admin.DeleteRunner)
+			})
 			m.Group("/users", func() {
 				m.Get("", admin.GetAllUsers)
 				m.Post("", bind(api.CreateUserOption{}), admin.CreateUser)
@@ -0,0 +1,389 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/eventstream"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"golang.org/x/time/rate"
+)
+
+// streamEventTypes are the types of the events published to the event stream
+var streamEventTypes = map[string]bool{
+	"issue":         true,
+	"pull_request":  true,
+	"issue_comment": true,
+	"release":       true,
+	"push":          true,
+	"create":        true,
+	"delete":        true,
+	"status":        true,
+}
+
+// streamPermissionTTL is the time after which the permissions and watched repositories of a stream are reloaded
+const streamPermissionTTL = time.Minute
+
+type streamRepo struct {
+	repo *models.Repository
+	perm models.Permission
+}
+
+// eventStreamFilter decides which events are delivered to a stream
+type eventStreamFilter struct {
+	user    *models.User
+	repoIDs map[int64]bool
+	types   map[string]bool
+	actorID int64
+
+	watched   map[int64]bool
+	repos     map[int64]*streamRepo
+	refreshed time.Time
+}
+
+func newEventStreamFilter(ctx *context.APIContext) *eventStreamFilter {
+	filter := &eventStreamFilter{
+		user:  ctx.User,
+		types: make(map[string]bool),
+	}
+
+	for _, typ := range ctx.FormStrings("type") {
+		if !streamEventTypes[typ] {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unknown event type %q", typ))
+			return nil
+		}
+		filter.types[typ] = true
+	}
+
+	if actor := ctx.FormTrim("actor"); actor != "" {
+		u, err := models.GetUserByName(actor)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return nil
+		}
+		filter.actorID = u.ID
+	}
+
+	if names := ctx.FormStrings("repo"); len(names) > 0 {
+		filter.repoIDs = make(map[int64]bool, len(names))
+		for _, name := range names {
+			parts := strings.SplitN(name, "/", 2)
+			if len(parts) != 2 {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid repository %q, expected owner/name", name))
+				return nil
+			}
+			repo, err := models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+			if err != nil {
+				if models.IsErrRepoNotExist(err) {
+					ctx.NotFound()
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+				}
+				return nil
+			}
+			perm, err := models.GetUserRepoPermission(repo, ctx.User)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+				return nil
+			} else if !perm.HasAccess() {
+				ctx.NotFound()
+				return nil
+			}
+			filter.repoIDs[repo.ID] = true
+		}
+	}
+
+	if err := filter.refresh(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "refresh", err)
+		return nil
+	}
+	return filter
+}
+
+// refresh drops the cached permissions and reloads the watched repositories
+func (f *eventStreamFilter) refresh() error {
+	f.repos = make(map[int64]*streamRepo)
+	f.refreshed = time.Now()
+	if f.repoIDs != nil {
+		return nil
+	}
+
+	repos, _, err := models.GetWatchedRepos(f.user.ID, true, models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	f.watched = make(map[int64]bool, len(repos))
+	for _, repo := range repos {
+		f.watched[repo.ID] = true
+	}
+	return nil
+}
+
+func (f *eventStreamFilter) getRepo(repoID int64) (*streamRepo, error) {
+	if r, ok := f.repos[repoID]; ok {
+		return r, nil
+	}
+
+	r := &streamRepo{}
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		if !models.IsErrRepoNotExist(err) {
+			return nil, err
+		}
+	} else {
+		if r.perm, err = models.GetUserRepoPermission(repo, f.user); err != nil {
+			return nil, err
+		}
+		r.repo = repo
+	}
+	f.repos[repoID] = r
+	return r, nil
+}
+
+// match returns the repository of an event if it is delivered to the stream
+func (f *eventStreamFilter) match(event *eventstream.Event) (*models.Repository, error) {
+	if len(f.types) > 0 && !f.types[event.Type] {
+		return nil, nil
+	}
+	if f.actorID > 0 && (event.Actor == nil || event.Actor.ID != f.actorID) {
+		return nil, nil
+	}
+
+	if time.Since(f.refreshed) > streamPermissionTTL {
+		if err := f.refresh(); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.repoIDs != nil {
+		if !f.repoIDs[event.RepoID] {
+			return nil, nil
+		}
+	} else if !f.watched[event.RepoID] && !event.Involves(f.user.ID) {
+		return nil, nil
+	}
+
+	r, err := f.getRepo(event.RepoID)
+	if err != nil || r.repo == nil || !r.perm.CanRead(event.Unit) {
+		return nil, err
+	}
+	return r.repo, nil
+}
+
+type eventStream struct {
+	ctx     *context.APIContext
+	broker  *eventstream.Broker
+	filter  *eventStreamFilter
+	limiter *rate.Limiter
+	cursor  string
+}
+
+func (s *eventStream) write(event *eventsource.Event) error {
+	if _, err := event.WriteTo(s.ctx.Resp); err != nil {
+		return err
+	}
+	s.ctx.Resp.Flush()
+	return nil
+}
+
+// catchUp writes the events published after the cursor of the stream,
+// it returns true if the rate limit of the stream was reached
+func (s *eventStream) catchUp() (bool, error) {
+	events, ok := s.broker.Since(s.cursor)
+	if !ok {
+		s.cursor = s.broker.Latest()
+		return false, s.write(&eventsource.Event{
+			Name: "reset",
+			Data: "events after the cursor are no longer available, the stream continues with the latest event",
+			ID:   s.cursor,
+		})
+	}
+
+	for _, event := range events {
+		repo, err := s.filter.match(event)
+		if err != nil {
+			return false, err
+		}
+		if repo != nil {
+			if !s.limiter.Allow() {
+				return true, nil
+			}
+			cursor := s.broker.Cursor(event.Seq)
+			if err := s.write(&eventsource.Event{
+				Name: event.Type,
+				ID:   cursor,
+				Data: &api.StreamEvent{
+					ID:     cursor,
+					Type:   event.Type,
+					Action: event.Action,
+					Repository: &api.RepositoryMeta{
+						ID:       repo.ID,
+						Name:     repo.Name,
+						Owner:    repo.OwnerName,
+						FullName: repo.FullName(),
+					},
+					Actor:   convert.ToUser(event.Actor, s.ctx.User),
+					Payload: event.Payload,
+					Created: event.Created,
+				},
+			}); err != nil {
+				return false, err
+			}
+		}
+		s.cursor = s.broker.Cursor(event.Seq)
+	}
+	return false, nil
+}
+
+// EventStream streams the events of repositories as server-sent events
+func EventStream(ctx *context.APIContext) {
+	// swagger:operation GET /events/stream miscellaneous streamEvents
+	// ---
+	// summary: Stream the live events of repositories as server-sent events
+	// description: Without a repo filter, the events of the watched repositories and the events
+	//   directly involving the user, e.g. assignments, review requests and mentions, are streamed.
+	//   Only events of repositories the user can read are delivered. Every event carries a cursor
+	//   as its id, a dropped stream can be resumed after it with the Last-Event-ID header or the
+	//   cursor parameter. If the events after the cursor are no longer available, a reset event is sent.
+	// produces:
+	// - text/event-stream
+	// parameters:
+	// - name: repo
+	//   in: query
+	//   description: only stream the events of these repositories, given as owner/name
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	// - name: type
+	//   in: query
+	//   description: only stream events of these types
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	//     enum: [issue, pull_request, issue_comment, release, push, create, delete, status]
+	// - name: actor
+	//   in: query
+	//   description: only stream the events caused by this user
+	//   type: string
+	// - name: cursor
+	//   in: query
+	//   description: resume the stream after the event with this cursor, takes precedence over the Last-Event-ID header
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StreamEvent"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "429":
+	//     "$ref": "#/responses/error"
+
+	filter := newEventStreamFilter(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	broker := eventstream.GetBroker()
+	if !broker.AcquireConnection(ctx.User.ID) {
+		ctx.Error(http.StatusTooManyRequests, "", "too many open event streams")
+		return
+	}
+	defer broker.ReleaseConnection(ctx.User.ID)
+
+	// subscribe before reading the history, so no event is missed in between
+	wake := broker.Subscribe()
+	defer broker.Unsubscribe(wake)
+
+	stream := &eventStream{
+		ctx:     ctx,
+		broker:  broker,
+		filter:  filter,
+		limiter: rate.NewLimiter(rate.Limit(setting.EventStream.EventsPerSecond), setting.EventStream.Burst),
+		cursor:  ctx.FormString("cursor"),
+	}
+	if stream.cursor == "" {
+		stream.cursor = ctx.Req.Header.Get("Last-Event-ID")
+	}
+	if stream.cursor == "" {
+		stream.cursor = broker.Latest()
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "text/event-stream")
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	ctx.Resp.Header().Set("Connection", "keep-alive")
+	ctx.Resp.Header().Set("X-Accel-Buffering", "no")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	ctx.Resp.Flush()
+
+	ping := time.NewTicker(setting.EventStream.PingInterval)
+	defer ping.Stop()
+
+	// retry is set while the rate limit of the stream is reached
+	var retry <-chan time.Time
+	catchUp := func() bool {
+		throttled, err := stream.catchUp()
+		if err != nil {
+			log.Debug("Event stream of user %s stopped: %v", ctx.User.Name, err)
+			return false
+		}
+		if throttled {
+			retry = time.After(time.Duration(float64(time.Second) / setting.EventStream.EventsPerSecond))
+		} else {
+			retry = nil
+		}
+		return true
+	}
+
+	if !catchUp() {
+		return
+	}
+
+	shutdownCtx := graceful.GetManager().ShutdownContext()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-shutdownCtx.Done():
+			return
+		case <-ping.C:
+			// the cursor advances past the events filtered out, so resumed streams skip them
+			if err := stream.write(&eventsource.Event{Name: "ping", ID: stream.cursor}); err != nil {
+				return
+			}
+		case <-wake:
+			if retry != nil {
+				continue
+			}
+			if !catchUp() {
+				return
+			}
+		case <-retry:
+			if !catchUp() {
+				return
+			}
+		}
+	}
+}
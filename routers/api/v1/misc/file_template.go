@@ -0,0 +1,67 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListFileTemplates lists the bundled templates files can be generated from
+func ListFileTemplates(ctx *context.APIContext) {
+	// swagger:operation GET /file-templates miscellaneous listFileTemplates
+	// ---
+	// summary: List the license and gitignore templates files can be generated from
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: query
+	//   description: type of the templates, all types are listed if not given
+	//   type: string
+	//   enum: [license, gitignore]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileTemplateList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	tp := ctx.FormString("type")
+	if tp != "" && tp != "license" && tp != "gitignore" {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown template type %q", tp))
+		return
+	}
+
+	templates := make([]*api.FileTemplate, 0, len(models.Licenses)+len(models.Gitignores))
+	if tp == "" || tp == "license" {
+		for _, name := range models.Licenses {
+			title, err := repo_module.GetLicenseTitle(name)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetLicenseTitle", err)
+				return
+			}
+			templates = append(templates, &api.FileTemplate{
+				Type:        "license",
+				Name:        name,
+				Description: title,
+			})
+		}
+	}
+	if tp == "" || tp == "gitignore" {
+		for _, name := range models.Gitignores {
+			templates = append(templates, &api.FileTemplate{
+				Type:        "gitignore",
+				Name:        name,
+				Description: fmt.Sprintf("Files to ignore in %s projects", name),
+			})
+		}
+	}
+	ctx.JSON(http.StatusOK, templates)
+}
@@ -0,0 +1,234 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// Search searches repositories, issues, users and code the doer may see
+func Search(ctx *context.APIContext) {
+	// swagger:operation GET /search miscellaneous search
+	// ---
+	// summary: Search repositories, issues, users and code the user has access to
+	// description: Results are grouped by type, every group is paginated on its own.
+	//   Code is only searched if the code indexer is enabled.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword to search for
+	//   type: string
+	//   required: true
+	// - name: types
+	//   in: query
+	//   description: comma separated list of the types to search, all types are searched if empty
+	//   type: array
+	//   collectionFormat: csv
+	//   items:
+	//     type: string
+	//     enum: [repo, issue, user, code]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of the results of each type
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UnifiedSearchResults"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	keyword := ctx.FormTrim("q")
+	if strings.IndexByte(keyword, 0) >= 0 {
+		keyword = ""
+	}
+	if keyword == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "q must not be empty")
+		return
+	}
+
+	types, err := parseSearchTypes(ctx.FormStrings("types"))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	results := &api.UnifiedSearchResults{
+		Repositories: []*api.Repository{},
+		Issues:       []*api.Issue{},
+		Users:        []*api.User{},
+		Code:         []*api.CodeSearchResult{},
+		Facets: &api.SearchFacets{
+			CodeLanguages: []*api.CodeSearchLanguage{},
+		},
+	}
+
+	if types[api.SearchTypeRepo] {
+		if err := searchRepos(ctx, keyword, listOptions, results); err != nil {
+			ctx.Error(http.StatusInternalServerError, "searchRepos", err)
+			return
+		}
+	}
+	if types[api.SearchTypeIssue] {
+		if err := searchIssues(ctx, keyword, listOptions, results); err != nil {
+			ctx.Error(http.StatusInternalServerError, "searchIssues", err)
+			return
+		}
+	}
+	if types[api.SearchTypeUser] {
+		users, count, err := models.SearchUsers(&models.SearchUserOptions{
+			Actor:       ctx.User,
+			Keyword:     keyword,
+			Type:        models.UserTypeIndividual,
+			ListOptions: listOptions,
+		})
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "SearchUsers", err)
+			return
+		}
+		results.Users = convert.ToUsers(ctx.User, users)
+		results.Facets.Users = count
+	}
+	if types[api.SearchTypeCode] && setting.Indexer.RepoIndexerEnabled {
+		repoIDs, err := utils.CodeSearchableRepoIDs(ctx, 0)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "CodeSearchableRepoIDs", err)
+			return
+		}
+		code, err := utils.FindCode(repoIDs, keyword, "", "", listOptions, false)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "FindCode", err)
+			return
+		}
+		results.Code = code.Results
+		results.Facets.Code = code.TotalCount
+		results.Facets.CodeLanguages = code.Languages
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
+
+// parseSearchTypes returns the set of the types to search, all types if none are given
+func parseSearchTypes(values []string) (map[api.SearchType]bool, error) {
+	types := make(map[api.SearchType]bool, len(api.SearchTypes))
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			tp := api.SearchType(name)
+			if !isSearchType(tp) {
+				return nil, fmt.Errorf("Invalid search type: \"%s\"", name)
+			}
+			types[tp] = true
+		}
+	}
+	if len(types) == 0 {
+		for _, tp := range api.SearchTypes {
+			types[tp] = true
+		}
+	}
+	return types, nil
+}
+
+func isSearchType(tp api.SearchType) bool {
+	for _, t := range api.SearchTypes {
+		if t == tp {
+			return true
+		}
+	}
+	return false
+}
+
+func searchRepos(ctx *context.APIContext, keyword string, listOptions models.ListOptions, results *api.UnifiedSearchResults) error {
+	repos, count, err := models.SearchRepository(&models.SearchRepoOptions{
+		ListOptions:        listOptions,
+		Actor:              ctx.User,
+		Keyword:            keyword,
+		Collaborate:        util.OptionalBoolNone,
+		Private:            ctx.IsSigned,
+		Template:           util.OptionalBoolNone,
+		IncludeDescription: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	results.Repositories = make([]*api.Repository, len(repos))
+	for i, repo := range repos {
+		if err := repo.GetOwner(); err != nil {
+			return err
+		}
+		accessMode, err := models.AccessLevel(ctx.User, repo)
+		if err != nil {
+			return err
+		}
+		results.Repositories[i] = convert.ToRepo(repo, accessMode)
+	}
+	results.Facets.Repositories = count
+	return nil
+}
+
+func searchIssues(ctx *context.APIContext, keyword string, listOptions models.ListOptions, results *api.UnifiedSearchResults) error {
+	// find repos user can access (for issue search)
+	repoIDs, _, err := models.SearchRepositoryIDs(&models.SearchRepoOptions{
+		Private:     ctx.IsSigned,
+		AllPublic:   true,
+		AllLimited:  ctx.IsSigned,
+		Collaborate: util.OptionalBoolNone,
+		OrderBy:     models.SearchOrderByAlphabetically,
+		Actor:       ctx.User,
+	})
+	if err != nil || len(repoIDs) == 0 {
+		return err
+	}
+
+	issueIDs, err := issue_indexer.SearchIssuesByKeyword(repoIDs, keyword)
+	if err != nil || len(issueIDs) == 0 {
+		return err
+	}
+
+	opts := &models.IssuesOptions{
+		ListOptions: listOptions,
+		RepoIDs:     repoIDs,
+		IssueIDs:    issueIDs,
+		IsClosed:    util.OptionalBoolNone,
+		SortType:    "recentupdate",
+	}
+	issues, err := models.Issues(opts)
+	if err != nil {
+		return err
+	}
+
+	opts.ListOptions = models.ListOptions{
+		Page: -1,
+	}
+	count, err := models.CountIssues(opts)
+	if err != nil {
+		return err
+	}
+
+	results.Issues = convert.ToAPIIssueList(issues)
+	results.Facets.Issues = count
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchTypes(t *testing.T) {
+	types, err := parseSearchTypes(nil)
+	assert.NoError(t, err)
+	assert.Len(t, types, len(api.SearchTypes))
+
+	types, err = parseSearchTypes([]string{"repo, code", "user"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[api.SearchType]bool{
+		api.SearchTypeRepo: true,
+		api.SearchTypeCode: true,
+		api.SearchTypeUser: true,
+	}, types)
+
+	_, err = parseSearchTypes([]string{"repo,wiki"})
+	assert.Error(t, err)
+}
@@ -5,12 +5,16 @@
 package notify
 
 import (
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
@@ -37,6 +41,13 @@ func getFindNotificationOptions(ctx *context.APIContext) *models.FindNotificatio
 		UpdatedBeforeUnix: before,
 		UpdatedAfterUnix:  since,
 	}
+	if utils.IsCursorPagination(ctx) {
+		if opts.After, err = utils.GetCursor(ctx); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "GetCursor", err)
+			return nil
+		}
+		opts.Page = 1
+	}
 	if !ctx.FormBool("all") {
 		statuses := ctx.FormStrings("status-types")
 		opts.Status = statusStringsToNotificationStatuses(statuses, []string{"unread", "pinned"})
@@ -50,6 +61,119 @@ func getFindNotificationOptions(ctx *context.APIContext) *models.FindNotificatio
 	return opts
 }
 
+// listNotificationsByCursor responds with the page of notifications after the cursor, the notifications are not counted
+func listNotificationsByCursor(ctx *context.APIContext, opts *models.FindNotificationOptions) {
+	nl, err := models.GetNotifications(opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := nl.LoadAttributes(); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if _, take := opts.GetSkipTake(); len(nl) > 0 && len(nl) == take {
+		ctx.SetCursorLinkHeader(utils.EncodeCursor(opts.CursorOf(nl[len(nl)-1])))
+	}
+	ctx.JSON(http.StatusOK, convert.ToNotifications(nl))
+}
+
+// getReadNotificationOptions returns the filter of the notifications whose status is changed in bulk
+func getReadNotificationOptions(ctx *context.APIContext) *models.FindNotificationOptions {
+	lastRead, err := parseQueryTime(ctx, "last_read_at")
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "last_read_at", err)
+		return nil
+	}
+	since, err := parseQueryTime(ctx, "since")
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "since", err)
+		return nil
+	}
+
+	opts := &models.FindNotificationOptions{
+		UserID:            ctx.User.ID,
+		UpdatedBeforeUnix: lastRead,
+		UpdatedAfterUnix:  since,
+	}
+	if !ctx.FormBool("all") {
+		statuses := ctx.FormStrings("status-types")
+		opts.Status = statusStringsToNotificationStatuses(statuses, []string{"unread"})
+	}
+
+	subjectTypes := ctx.FormStrings("subject-type")
+	if len(subjectTypes) != 0 {
+		opts.Source = subjectToSource(subjectTypes)
+	}
+
+	return opts
+}
+
+// getTargetStatus returns the status to change notifications to and,
+// if they are snoozed, the time they become unread again
+func getTargetStatus(ctx *context.APIContext) (models.NotificationStatus, timeutil.TimeStamp) {
+	snoozeUntil, err := parseQueryTime(ctx, "snooze-until")
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "snooze-until", err)
+		return 0, 0
+	}
+
+	targetStatus := statusStringToNotificationStatus(ctx.FormString("to-status"))
+	if targetStatus == 0 {
+		if snoozeUntil != 0 {
+			targetStatus = models.NotificationStatusSnoozed
+		} else {
+			targetStatus = models.NotificationStatusRead
+		}
+	}
+
+	if targetStatus != models.NotificationStatusSnoozed {
+		if snoozeUntil != 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "snooze-until", errors.New("snooze-until is only allowed when snoozing"))
+		}
+		return targetStatus, 0
+	}
+	if snoozeUntil <= time.Now().Unix() {
+		ctx.Error(http.StatusUnprocessableEntity, "snooze-until", errors.New("snooze-until must be in the future"))
+		return 0, 0
+	}
+	return targetStatus, timeutil.TimeStamp(snoozeUntil)
+}
+
+// changeNotificationStatuses changes the status of the notifications and returns the changed threads
+func changeNotificationStatuses(ctx *context.APIContext, nl models.NotificationList, status models.NotificationStatus, snoozeUntil timeutil.TimeStamp) []*api.NotificationThread {
+	changed := make([]*api.NotificationThread, 0, len(nl))
+	for _, n := range nl {
+		var notif *models.Notification
+		var err error
+		if status == models.NotificationStatusSnoozed {
+			notif, err = models.SnoozeNotification(n.ID, ctx.User, snoozeUntil)
+		} else {
+			notif, err = models.SetNotificationStatus(n.ID, ctx.User, status)
+		}
+		if err != nil {
+			ctx.InternalServerError(err)
+			return nil
+		}
+		_ = notif.LoadAttributes()
+		changed = append(changed, convert.ToNotificationThread(notif))
+	}
+	return changed
+}
+
+// parseQueryTime parses a query parameter holding a time in RFC 3339 format, 0 if it is not set
+func parseQueryTime(ctx *context.APIContext, name string) (int64, error) {
+	value := ctx.FormTrim(name)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil || t.IsZero() {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
+
 func subjectToSource(value []string) (result []models.NotificationSource) {
 	for _, v := range value {
 		switch strings.ToLower(v) {
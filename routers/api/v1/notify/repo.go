@@ -7,13 +7,11 @@ package notify
 import (
 	"net/http"
 	"strings"
-	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
-	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
 func statusStringToNotificationStatus(status string) models.NotificationStatus {
@@ -24,6 +22,8 @@ func statusStringToNotificationStatus(status string) models.NotificationStatus {
 		return models.NotificationStatusRead
 	case "pinned":
 		return models.NotificationStatusPinned
+	case "snoozed":
+		return models.NotificationStatusSnoozed
 	default:
 		return 0
 	}
@@ -69,7 +69,7 @@ func ListRepoNotifications(ctx *context.APIContext) {
 	//   type: boolean
 	// - name: status-types
 	//   in: query
-	//   description: "Show notifications with the provided status types. Options are: unread, read and/or pinned. Defaults to unread & pinned"
+	//   description: "Show notifications with the provided status types. Options are: unread, read, pinned and/or snoozed. Defaults to unread & pinned"
 	//   type: array
 	//   collectionFormat: multi
 	//   items:
@@ -96,6 +96,10 @@ func ListRepoNotifications(ctx *context.APIContext) {
 	//   in: query
 	//   description: page number of results to return (1-based)
 	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: cursor of the page to return, taken from the next link of the previous page. An empty cursor returns the first page paginated by cursor, without counting the notifications
+	//   type: string
 	// - name: limit
 	//   in: query
 	//   description: page size of results
@@ -103,12 +107,19 @@ func ListRepoNotifications(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/NotificationThreadList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	opts := getFindNotificationOptions(ctx)
 	if ctx.Written() {
 		return
 	}
 	opts.RepoID = ctx.Repo.Repository.ID
 
+	if utils.IsCursorPagination(ctx) {
+		listNotificationsByCursor(ctx, opts)
+		return
+	}
+
 	totalCount, err := models.CountNotifications(opts)
 	if err != nil {
 		ctx.InternalServerError(err)
@@ -135,7 +146,7 @@ func ListRepoNotifications(ctx *context.APIContext) {
 func ReadRepoNotifications(ctx *context.APIContext) {
 	// swagger:operation PUT /repos/{owner}/{repo}/notifications notification notifyReadRepoList
 	// ---
-	// summary: Mark notification threads as read, pinned or unread on a specific repo
+	// summary: Mark notification threads as read, pinned, unread or snoozed on a specific repo
 	// consumes:
 	// - application/json
 	// produces:
@@ -158,12 +169,27 @@ func ReadRepoNotifications(ctx *context.APIContext) {
 	//   required: false
 	// - name: status-types
 	//   in: query
-	//   description: "Mark notifications with the provided status types. Options are: unread, read and/or pinned. Defaults to unread."
+	//   description: "Mark notifications with the provided status types. Options are: unread, read, pinned and/or snoozed. Defaults to unread."
 	//   type: array
 	//   collectionFormat: multi
 	//   items:
 	//     type: string
 	//   required: false
+	// - name: subject-type
+	//   in: query
+	//   description: "Only mark notifications with the provided subject types"
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	//     enum: [issue,pull,commit,repository]
+	//   required: false
+	// - name: since
+	//   in: query
+	//   description: Only mark notifications updated after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	//   required: false
 	// - name: to-status
 	//   in: query
 	//   description: Status to mark notifications as. Defaults to read.
@@ -175,55 +201,37 @@ func ReadRepoNotifications(ctx *context.APIContext) {
 	//   type: string
 	//   format: date-time
 	//   required: false
+	// - name: snooze-until
+	//   in: query
+	//   description: Time snoozed notifications become unread again. Required if to-status is snoozed, implies it if to-status is not given.
+	//   type: string
+	//   format: date-time
+	//   required: false
 	// responses:
 	//   "205":
 	//     "$ref": "#/responses/NotificationThreadList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
-	lastRead := int64(0)
-	qLastRead := ctx.FormTrim("last_read_at")
-	if len(qLastRead) > 0 {
-		tmpLastRead, err := time.Parse(time.RFC3339, qLastRead)
-		if err != nil {
-			ctx.InternalServerError(err)
-			return
-		}
-		if !tmpLastRead.IsZero() {
-			lastRead = tmpLastRead.Unix()
-		}
+	opts := getReadNotificationOptions(ctx)
+	if ctx.Written() {
+		return
 	}
-
-	opts := &models.FindNotificationOptions{
-		UserID:            ctx.User.ID,
-		RepoID:            ctx.Repo.Repository.ID,
-		UpdatedBeforeUnix: lastRead,
+	opts.RepoID = ctx.Repo.Repository.ID
+	targetStatus, snoozeUntil := getTargetStatus(ctx)
+	if ctx.Written() {
+		return
 	}
 
-	if !ctx.FormBool("all") {
-		statuses := ctx.FormStrings("status-types")
-		opts.Status = statusStringsToNotificationStatuses(statuses, []string{"unread"})
-		log.Error("%v", opts.Status)
-	}
 	nl, err := models.GetNotifications(opts)
 	if err != nil {
 		ctx.InternalServerError(err)
 		return
 	}
 
-	targetStatus := statusStringToNotificationStatus(ctx.FormString("to-status"))
-	if targetStatus == 0 {
-		targetStatus = models.NotificationStatusRead
-	}
-
-	changed := make([]*structs.NotificationThread, len(nl))
-
-	for _, n := range nl {
-		notif, err := models.SetNotificationStatus(n.ID, ctx.User, targetStatus)
-		if err != nil {
-			ctx.InternalServerError(err)
-			return
-		}
-		_ = notif.LoadAttributes()
-		changed = append(changed, convert.ToNotificationThread(notif))
+	changed := changeNotificationStatuses(ctx, nl, targetStatus, snoozeUntil)
+	if ctx.Written() {
+		return
 	}
 	ctx.JSON(http.StatusResetContent, changed)
 }
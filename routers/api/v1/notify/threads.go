@@ -69,6 +69,12 @@ func ReadThread(ctx *context.APIContext) {
 	//   type: string
 	//   default: read
 	//   required: false
+	// - name: snooze-until
+	//   in: query
+	//   description: Time the snoozed notification becomes unread again. Required if to-status is snoozed, implies it if to-status is not given.
+	//   type: string
+	//   format: date-time
+	//   required: false
 	// responses:
 	//   "205":
 	//     "$ref": "#/responses/NotificationThread"
@@ -76,27 +82,24 @@ func ReadThread(ctx *context.APIContext) {
 	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	n := getThread(ctx)
 	if n == nil {
 		return
 	}
 
-	targetStatus := statusStringToNotificationStatus(ctx.FormString("to-status"))
-	if targetStatus == 0 {
-		targetStatus = models.NotificationStatusRead
-	}
-
-	notif, err := models.SetNotificationStatus(n.ID, ctx.User, targetStatus)
-	if err != nil {
-		ctx.InternalServerError(err)
+	targetStatus, snoozeUntil := getTargetStatus(ctx)
+	if ctx.Written() {
 		return
 	}
-	if err = notif.LoadAttributes(); err != nil {
-		ctx.InternalServerError(err)
+
+	changed := changeNotificationStatuses(ctx, models.NotificationList{n}, targetStatus, snoozeUntil)
+	if ctx.Written() {
 		return
 	}
-	ctx.JSON(http.StatusResetContent, convert.ToNotificationThread(notif))
+	ctx.JSON(http.StatusResetContent, changed[0])
 }
 
 func getThread(ctx *context.APIContext) *models.Notification {
@@ -6,12 +6,11 @@ package notify
 
 import (
 	"net/http"
-	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
-	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
 // ListNotifications list users's notification threads
@@ -30,7 +29,7 @@ func ListNotifications(ctx *context.APIContext) {
 	//   type: boolean
 	// - name: status-types
 	//   in: query
-	//   description: "Show notifications with the provided status types. Options are: unread, read and/or pinned. Defaults to unread & pinned."
+	//   description: "Show notifications with the provided status types. Options are: unread, read, pinned and/or snoozed. Defaults to unread & pinned."
 	//   type: array
 	//   collectionFormat: multi
 	//   items:
@@ -57,6 +56,10 @@ func ListNotifications(ctx *context.APIContext) {
 	//   in: query
 	//   description: page number of results to return (1-based)
 	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: cursor of the page to return, taken from the next link of the previous page. An empty cursor returns the first page paginated by cursor, without counting the notifications
+	//   type: string
 	// - name: limit
 	//   in: query
 	//   description: page size of results
@@ -64,11 +67,18 @@ func ListNotifications(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/NotificationThreadList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	opts := getFindNotificationOptions(ctx)
 	if ctx.Written() {
 		return
 	}
 
+	if utils.IsCursorPagination(ctx) {
+		listNotificationsByCursor(ctx, opts)
+		return
+	}
+
 	totalCount, err := models.CountNotifications(opts)
 	if err != nil {
 		ctx.InternalServerError(err)
@@ -94,7 +104,7 @@ func ListNotifications(ctx *context.APIContext) {
 func ReadNotifications(ctx *context.APIContext) {
 	// swagger:operation PUT /notifications notification notifyReadList
 	// ---
-	// summary: Mark notification threads as read, pinned or unread
+	// summary: Mark notification threads as read, pinned, unread or snoozed
 	// consumes:
 	// - application/json
 	// produces:
@@ -113,62 +123,62 @@ func ReadNotifications(ctx *context.APIContext) {
 	//   required: false
 	// - name: status-types
 	//   in: query
-	//   description: "Mark notifications with the provided status types. Options are: unread, read and/or pinned. Defaults to unread."
+	//   description: "Mark notifications with the provided status types. Options are: unread, read, pinned and/or snoozed. Defaults to unread."
 	//   type: array
 	//   collectionFormat: multi
 	//   items:
 	//     type: string
 	//   required: false
+	// - name: subject-type
+	//   in: query
+	//   description: "Only mark notifications with the provided subject types"
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	//     enum: [issue,pull,commit,repository]
+	//   required: false
+	// - name: since
+	//   in: query
+	//   description: Only mark notifications updated after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	//   required: false
 	// - name: to-status
 	//   in: query
 	//   description: Status to mark notifications as, Defaults to read.
 	//   type: string
 	//   required: false
+	// - name: snooze-until
+	//   in: query
+	//   description: Time snoozed notifications become unread again. Required if to-status is snoozed, implies it if to-status is not given.
+	//   type: string
+	//   format: date-time
+	//   required: false
 	// responses:
 	//   "205":
 	//     "$ref": "#/responses/NotificationThreadList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
-	lastRead := int64(0)
-	qLastRead := ctx.FormTrim("last_read_at")
-	if len(qLastRead) > 0 {
-		tmpLastRead, err := time.Parse(time.RFC3339, qLastRead)
-		if err != nil {
-			ctx.InternalServerError(err)
-			return
-		}
-		if !tmpLastRead.IsZero() {
-			lastRead = tmpLastRead.Unix()
-		}
-	}
-	opts := &models.FindNotificationOptions{
-		UserID:            ctx.User.ID,
-		UpdatedBeforeUnix: lastRead,
+	opts := getReadNotificationOptions(ctx)
+	if ctx.Written() {
+		return
 	}
-	if !ctx.FormBool("all") {
-		statuses := ctx.FormStrings("status-types")
-		opts.Status = statusStringsToNotificationStatuses(statuses, []string{"unread"})
+	targetStatus, snoozeUntil := getTargetStatus(ctx)
+	if ctx.Written() {
+		return
 	}
+
 	nl, err := models.GetNotifications(opts)
 	if err != nil {
 		ctx.InternalServerError(err)
 		return
 	}
 
-	targetStatus := statusStringToNotificationStatus(ctx.FormString("to-status"))
-	if targetStatus == 0 {
-		targetStatus = models.NotificationStatusRead
-	}
-
-	changed := make([]*structs.NotificationThread, 0, len(nl))
-
-	for _, n := range nl {
-		notif, err := models.SetNotificationStatus(n.ID, ctx.User, targetStatus)
-		if err != nil {
-			ctx.InternalServerError(err)
-			return
-		}
-		_ = notif.LoadAttributes()
-		changed = append(changed, convert.ToNotificationThread(notif))
+	changed := changeNotificationStatuses(ctx, nl, targetStatus, snoozeUntil)
+	if ctx.Written() {
+		return
 	}
 
 	ctx.JSON(http.StatusResetContent, changed)
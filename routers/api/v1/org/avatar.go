@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// UpdateAvatar sets the avatar of an organization
+func UpdateAvatar(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/avatar organization orgUpdateAvatar
+	// ---
+	// summary: Set the avatar of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdateAvatarOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.UpdateAvatarOption)
+	data, crop, ok := utils.DecodeAvatarOption(ctx, form)
+	if !ok {
+		return
+	}
+
+	if err := ctx.Org.Organization.UploadCroppedAvatar(data, crop); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UploadCroppedAvatar", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteAvatar resets the avatar of an organization
+func DeleteAvatar(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/avatar organization orgDeleteAvatar
+	// ---
+	// summary: Reset the avatar of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := ctx.Org.Organization.DeleteAvatar(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAvatar", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
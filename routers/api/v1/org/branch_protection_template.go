@@ -0,0 +1,374 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ListBranchProtectionTemplates list the branch protection templates of an organization
+func ListBranchProtectionTemplates(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/branch_protection_templates organization orgListBranchProtectionTemplates
+	// ---
+	// summary: List the branch protection templates of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtectionTemplateList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	templates, err := models.GetBranchProtectionTemplates(ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranchProtectionTemplates", err)
+		return
+	}
+
+	apiTemplates := make([]*api.BranchProtectionTemplate, 0, len(templates))
+	for _, t := range templates {
+		apiTemplates = append(apiTemplates, convert.ToBranchProtectionTemplate(t))
+	}
+	ctx.JSON(http.StatusOK, apiTemplates)
+}
+
+// GetBranchProtectionTemplate get a branch protection template of an organization
+func GetBranchProtectionTemplate(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/branch_protection_templates/{name} organization orgGetBranchProtectionTemplate
+	// ---
+	// summary: Get a branch protection template of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtectionTemplate"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getBranchProtectionTemplate(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToBranchProtectionTemplate(t))
+}
+
+// CreateBranchProtectionTemplate create a branch protection template for an organization
+func CreateBranchProtectionTemplate(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/branch_protection_templates organization orgCreateBranchProtectionTemplate
+	// ---
+	// summary: Create a branch protection template for an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateBranchProtectionTemplateOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/BranchProtectionTemplate"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateBranchProtectionTemplateOption)
+	t, err := repo_service.CreateProtectionTemplate(ctx.Org.Organization, form)
+	if err != nil {
+		switch {
+		case models.IsErrBranchProtectionTemplateAlreadyExist(err):
+			ctx.Error(http.StatusConflict, "", err)
+		case models.IsErrInvalidBranchPattern(err), models.IsErrUserNotExist(err), models.IsErrTeamNotExist(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CreateProtectionTemplate", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToBranchProtectionTemplate(t))
+}
+
+// EditBranchProtectionTemplate edit a branch protection template of an organization
+func EditBranchProtectionTemplate(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/branch_protection_templates/{name} organization orgEditBranchProtectionTemplate
+	// ---
+	// summary: Edit a branch protection template of an organization. Only fields that are set will be changed
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditBranchProtectionTemplateOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtectionTemplate"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	t := getBranchProtectionTemplate(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditBranchProtectionTemplateOption)
+	if err := repo_service.EditProtectionTemplate(t, form); err != nil {
+		if models.IsErrInvalidBranchPattern(err) || models.IsErrUserNotExist(err) || models.IsErrTeamNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "EditProtectionTemplate", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToBranchProtectionTemplate(t))
+}
+
+// DeleteBranchProtectionTemplate delete a branch protection template of an organization
+func DeleteBranchProtectionTemplate(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/branch_protection_templates/{name} organization orgDeleteBranchProtectionTemplate
+	// ---
+	// summary: Delete a branch protection template of an organization, the branch protections created from it are kept
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getBranchProtectionTemplate(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeleteBranchProtectionTemplate(t); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteBranchProtectionTemplate", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ApplyBranchProtectionTemplate apply a branch protection template to a repository of an organization
+func ApplyBranchProtectionTemplate(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/branch_protection_templates/{name}/repos/{repo} organization orgApplyBranchProtectionTemplate
+	// ---
+	// summary: Apply a branch protection template to a repository of the organization, replacing the protections of the matching branches
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getBranchProtectionTemplate(ctx)
+	if ctx.Written() {
+		return
+	}
+	repo := getTemplateRepository(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := repo_service.ApplyProtectionTemplate(t, repo); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ApplyProtectionTemplate", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveBranchProtectionTemplate stop applying a branch protection template to a repository of an organization
+func RemoveBranchProtectionTemplate(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/branch_protection_templates/{name}/repos/{repo} organization orgRemoveBranchProtectionTemplate
+	// ---
+	// summary: Stop applying a branch protection template to a repository of the organization, the existing branch protections are kept
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getBranchProtectionTemplate(ctx)
+	if ctx.Written() {
+		return
+	}
+	repo := getTemplateRepository(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.UnlinkBranchProtectionTemplate(t.ID, repo.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnlinkBranchProtectionTemplate", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetBranchProtectionTemplateDrift list the branches whose protection deviates from a branch protection template
+func GetBranchProtectionTemplateDrift(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/branch_protection_templates/{name}/drift organization orgGetBranchProtectionTemplateDrift
+	// ---
+	// summary: List the branches matching a branch protection template whose protection deviates from it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtectionDriftList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getBranchProtectionTemplate(ctx)
+	if ctx.Written() {
+		return
+	}
+	drifts, err := repo_service.GetProtectionTemplateDrift(t)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectionTemplateDrift", err)
+		return
+	}
+
+	apiDrifts := make([]*api.BranchProtectionDrift, 0, len(drifts))
+	for _, drift := range drifts {
+		apiDrifts = append(apiDrifts, &api.BranchProtectionDrift{
+			Repository:  drift.Repo.Name,
+			Branch:      drift.Branch,
+			Protected:   drift.Protection != nil,
+			Differences: drift.Differences,
+		})
+	}
+	ctx.JSON(http.StatusOK, apiDrifts)
+}
+
+func getBranchProtectionTemplate(ctx *context.APIContext) *models.BranchProtectionTemplate {
+	t, err := models.GetBranchProtectionTemplateByName(ctx.Org.Organization.ID, ctx.Params(":name"))
+	if err != nil {
+		if models.IsErrBranchProtectionTemplateNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBranchProtectionTemplateByName", err)
+		}
+		return nil
+	}
+	return t
+}
+
+func getTemplateRepository(ctx *context.APIContext) *models.Repository {
+	repo, err := models.GetRepositoryByName(ctx.Org.Organization.ID, ctx.Params(":repo"))
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByName", err)
+		}
+		return nil
+	}
+	return repo
+}
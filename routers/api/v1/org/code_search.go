@@ -0,0 +1,68 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// SearchCode searches the code of the repositories of an organization the doer may read
+func SearchCode(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/code-search organization orgSearchCode
+	// ---
+	// summary: Search the code of the repositories of an organization the user may read
+	// description: Requires the code indexer to be enabled.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: keyword to search for
+	//   type: string
+	//   required: true
+	// - name: mode
+	//   in: query
+	//   description: "`match` finds words starting with the keyword, `fuzzy` (default) finds the keyword as phrase"
+	//   type: string
+	//   enum: [fuzzy, match]
+	// - name: language
+	//   in: query
+	//   description: only search files of this language
+	//   type: string
+	// - name: filename
+	//   in: query
+	//   description: only search files whose path matches this wildcard pattern, e.g. `*.go`
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResults"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	repoIDs, err := utils.CodeSearchableRepoIDs(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CodeSearchableRepoIDs", err)
+		return
+	}
+	utils.SearchCode(ctx, repoIDs)
+}
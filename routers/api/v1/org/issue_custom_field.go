@@ -0,0 +1,138 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListIssueCustomFields list the custom fields of the issues of an organization
+func ListIssueCustomFields(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issue_custom_fields organization orgListIssueCustomFields
+	// ---
+	// summary: List the custom fields of the issues of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueCustomFieldList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.ListIssueCustomFields(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// CreateIssueCustomField create a custom field of the issues of an organization
+func CreateIssueCustomField(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/issue_custom_fields organization orgCreateIssueCustomField
+	// ---
+	// summary: Create a custom field of the issues of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueCustomFieldOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueCustomField"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: A custom field with the same name already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueCustomFieldOption)
+	utils.CreateIssueCustomField(ctx, ctx.Org.Organization.ID, 0, form)
+}
+
+// EditIssueCustomField edit a custom field of the issues of an organization
+func EditIssueCustomField(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/issue_custom_fields/{id} organization orgEditIssueCustomField
+	// ---
+	// summary: Edit a custom field of the issues of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the custom field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueCustomFieldOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueCustomField"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: A custom field with the same name already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIssueCustomFieldOption)
+	utils.EditIssueCustomField(ctx, ctx.Org.Organization.ID, 0, form)
+}
+
+// DeleteIssueCustomField delete a custom field of the issues of an organization
+func DeleteIssueCustomField(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/issue_custom_fields/{id} organization orgDeleteIssueCustomField
+	// ---
+	// summary: Delete a custom field of the issues of an organization with the values of the issues
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the custom field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteIssueCustomField(ctx, ctx.Org.Organization.ID, 0)
+}
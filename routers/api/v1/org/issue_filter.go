@@ -0,0 +1,208 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/repo"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListIssueFilters list the saved issue filters of an organization
+func ListIssueFilters(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issue_filters organization orgListIssueFilters
+	// ---
+	// summary: List the saved issue filters of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilterList"
+
+	utils.ListIssueFilters(ctx, ctx.Org.Organization.ID)
+}
+
+// GetIssueFilter get a saved issue filter of an organization
+func GetIssueFilter(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issue_filters/{id} organization orgGetIssueFilter
+	// ---
+	// summary: Get a saved issue filter of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the issue filter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetIssueFilter(ctx, ctx.Org.Organization.ID)
+}
+
+// CreateIssueFilter save an issue filter for an organization
+func CreateIssueFilter(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/issue_filters organization orgCreateIssueFilter
+	// ---
+	// summary: Save an issue filter for an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueFilterOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.CreateIssueFilter(ctx, ctx.Org.Organization.ID, web.GetForm(ctx).(*api.CreateIssueFilterOption))
+}
+
+// EditIssueFilter modify a saved issue filter of an organization
+func EditIssueFilter(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/issue_filters/{id} organization orgEditIssueFilter
+	// ---
+	// summary: Edit a saved issue filter of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the issue filter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueFilterOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.EditIssueFilter(ctx, ctx.Org.Organization.ID, web.GetForm(ctx).(*api.EditIssueFilterOption))
+}
+
+// DeleteIssueFilter delete a saved issue filter of an organization
+func DeleteIssueFilter(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/issue_filters/{id} organization orgDeleteIssueFilter
+	// ---
+	// summary: Delete a saved issue filter of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the issue filter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteIssueFilter(ctx, ctx.Org.Organization.ID)
+}
+
+// SearchIssueFilter run a saved issue filter of an organization
+func SearchIssueFilter(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issue_filters/{id}/issues organization orgSearchIssueFilter
+	// ---
+	// summary: Search for issues and pull requests matching a saved issue filter of an organization
+	// description: The saved query is run like the issue search, restricted to the repositories of the organization.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the issue filter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.PrepareIssueFilterSearch(ctx, ctx.Org.Organization.ID, ctx.Org.Organization.Name)
+	if ctx.Written() {
+		return
+	}
+	repo.SearchIssues(ctx)
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// GetAssigneeWorkload summarizes the open issues and pull requests per assignee across the repositories of an organization
+func GetAssigneeWorkload(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/assignee-workload organization orgGetAssigneeWorkload
+	// ---
+	// summary: Get the open issues and pull requests per assignee across the repositories of an organization the doer may read, most loaded assignee first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AssigneeWorkloadList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	repoIDs, _, err := models.SearchRepositoryIDs(&models.SearchRepoOptions{
+		Actor:       ctx.User,
+		OwnerID:     ctx.Org.Organization.ID,
+		Private:     true,
+		Collaborate: util.OptionalBoolFalse,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchRepositoryIDs", err)
+		return
+	}
+	repos, err := models.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	issueRepoIDs := make([]int64, 0, len(repos))
+	pullRepoIDs := make([]int64, 0, len(repos))
+	for id, repo := range repos {
+		if repo.CheckUnitUser(ctx.User, models.UnitTypeIssues) {
+			issueRepoIDs = append(issueRepoIDs, id)
+		}
+		if repo.CheckUnitUser(ctx.User, models.UnitTypePullRequests) {
+			pullRepoIDs = append(pullRepoIDs, id)
+		}
+	}
+
+	workloads, err := models.GetAssigneeWorkloads(issueRepoIDs, pullRepoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetAssigneeWorkloads", err)
+		return
+	}
+
+	apiWorkloads := make([]*api.AssigneeWorkload, len(workloads))
+	for i := range workloads {
+		apiWorkloads[i] = convert.ToAPIAssigneeWorkload(workloads[i], ctx.User)
+	}
+	ctx.JSON(http.StatusOK, &apiWorkloads)
+}
@@ -0,0 +1,192 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListIterationCadences list the iteration cadences of an organization
+func ListIterationCadences(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/iteration_cadences organization orgListIterationCadences
+	// ---
+	// summary: List the iteration cadences of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IterationCadenceList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.ListIterationCadences(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// CreateIterationCadence create an iteration cadence of an organization
+func CreateIterationCadence(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/iteration_cadences organization orgCreateIterationCadence
+	// ---
+	// summary: Create an iteration cadence of an organization, its first iterations are created
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIterationCadenceOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IterationCadence"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIterationCadenceOption)
+	utils.CreateIterationCadence(ctx, ctx.Org.Organization.ID, 0, form)
+}
+
+// EditIterationCadence edit an iteration cadence of an organization
+func EditIterationCadence(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/iteration_cadences/{id} organization orgEditIterationCadence
+	// ---
+	// summary: Edit an iteration cadence of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the iteration cadence
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIterationCadenceOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IterationCadence"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIterationCadenceOption)
+	utils.EditIterationCadence(ctx, ctx.Org.Organization.ID, 0, form)
+}
+
+// DeleteIterationCadence delete an iteration cadence of an organization
+func DeleteIterationCadence(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/iteration_cadences/{id} organization orgDeleteIterationCadence
+	// ---
+	// summary: Delete an iteration cadence of an organization with its iterations, their issues are unassigned
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the iteration cadence
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteIterationCadence(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// ListIterations list the iterations of an organization
+func ListIterations(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/iterations organization orgListIterations
+	// ---
+	// summary: List the iterations of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: only list the past, current or upcoming iterations
+	//   type: string
+	//   enum: [past, current, upcoming]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IterationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ListIterations(ctx, &models.FindIterationOptions{OrgID: ctx.Org.Organization.ID})
+}
+
+// GetIteration get an iteration of an organization with the statistics of its issues
+func GetIteration(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/iterations/{id} organization orgGetIteration
+	// ---
+	// summary: Get an iteration of an organization with the statistics of its issues
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the iteration
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Iteration"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetIteration(ctx, &models.FindIterationOptions{OrgID: ctx.Org.Organization.ID})
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// SetPinnedRepos sets the repositories pinned on the profile of an organization
+func SetPinnedRepos(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/pinned_repos organization orgSetPinnedRepos
+	// ---
+	// summary: Set the repositories pinned on the profile of an organization, in their order
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetPinnedReposOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.SetPinnedRepos(ctx, ctx.Org.Organization.ID, web.GetForm(ctx).(*api.SetPinnedReposOption))
+}
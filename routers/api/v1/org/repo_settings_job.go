@@ -0,0 +1,168 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ListRepoSettingsJobs list the bulk repository settings updates of an organization
+func ListRepoSettingsJobs(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/repo_settings_jobs organization orgListRepoSettingsJobs
+	// ---
+	// summary: List the bulk repository settings updates of an organization, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSettingsJobList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	jobs, count, err := models.FindRepoSettingsJobs(ctx.Org.Organization.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoSettingsJobs", err)
+		return
+	}
+
+	apiJobs := make([]*api.RepoSettingsJob, 0, len(jobs))
+	for _, job := range jobs {
+		apiJob, err := toRepoSettingsJob(job)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "toRepoSettingsJob", err)
+			return
+		}
+		apiJobs = append(apiJobs, apiJob)
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiJobs)
+}
+
+// GetRepoSettingsJob get a bulk repository settings update of an organization
+func GetRepoSettingsJob(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/repo_settings_jobs/{id} organization orgGetRepoSettingsJob
+	// ---
+	// summary: Get a bulk repository settings update of an organization with its outcome for each repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSettingsJob"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	job, err := models.GetRepoSettingsJobByID(ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoSettingsJobNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoSettingsJobByID", err)
+		}
+		return
+	}
+
+	apiJob, err := toRepoSettingsJob(job)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toRepoSettingsJob", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiJob)
+}
+
+// CreateRepoSettingsJob update the settings of many repositories of an organization in the background
+func CreateRepoSettingsJob(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/repo_settings_jobs organization orgCreateRepoSettingsJob
+	// ---
+	// summary: Apply a settings patch to many repositories of an organization asynchronously, the job reports the outcome for each repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRepoSettingsJobOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/RepoSettingsJob"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateRepoSettingsJobOption)
+	job, err := repo_service.CreateSettingsJob(ctx.User, ctx.Org.Organization, form.Repos, form.Settings)
+	if err != nil {
+		switch {
+		case models.IsErrRepoNotExist(err):
+			ctx.NotFound(err)
+		case repo_service.IsErrInvalidSettingsPatch(err), models.IsErrUserNotExist(err), models.IsErrTeamNotExist(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CreateSettingsJob", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, convert.ToRepoSettingsJob(job, ctx.User))
+}
+
+func toRepoSettingsJob(job *models.RepoSettingsJob) (*api.RepoSettingsJob, error) {
+	if err := job.LoadResults(); err != nil {
+		return nil, err
+	}
+	doer, err := models.GetUserByID(job.DoerID)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		doer = models.NewGhostUser()
+	}
+	return convert.ToRepoSettingsJob(job, doer), nil
+}
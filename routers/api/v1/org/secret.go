@@ -0,0 +1,102 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListSecrets list the secrets of an organization
+func ListSecrets(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/secrets organization orgListSecrets
+	// ---
+	// summary: List the secrets of an organization. Values are never returned.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SecretList"
+
+	utils.ListSecrets(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// CreateOrUpdateSecret create or update a secret of an organization
+func CreateOrUpdateSecret(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/secrets/{secretname} organization orgCreateOrUpdateSecret
+	// ---
+	// summary: Create or update a secret of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateSecretOption"
+	// responses:
+	//   "201":
+	//     description: secret created
+	//   "204":
+	//     description: secret updated
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.CreateOrUpdateSecret(ctx, ctx.Org.Organization.ID, 0, web.GetForm(ctx).(*api.CreateOrUpdateSecretOption))
+}
+
+// DeleteSecret delete a secret of an organization
+func DeleteSecret(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/secrets/{secretname} organization orgDeleteSecret
+	// ---
+	// summary: Delete a secret of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteSecret(ctx, ctx.Org.Organization.ID, 0)
+}
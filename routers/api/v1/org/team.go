@@ -138,6 +138,42 @@ func GetTeam(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, convert.ToTeam(ctx.Org.Team))
 }
 
+// ListChildTeams api for listing the child teams of a team
+func ListChildTeams(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/teams organization orgListChildTeams
+	// ---
+	// summary: List the teams directly below a team
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamList"
+
+	teams, err := models.GetChildTeams(ctx.Org.Team)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetChildTeams", err)
+		return
+	}
+
+	apiTeams := make([]*api.Team, len(teams))
+	for i := range teams {
+		if err := teams[i].GetUnits(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUnits", err)
+			return
+		}
+		apiTeams[i] = convert.ToTeam(teams[i])
+	}
+
+	ctx.JSON(http.StatusOK, apiTeams)
+}
+
 // CreateTeam api for create a team
 func CreateTeam(ctx *context.APIContext) {
 	// swagger:operation POST /orgs/{org}/teams organization orgCreateTeam
@@ -170,6 +206,7 @@ func CreateTeam(ctx *context.APIContext) {
 		IncludesAllRepositories: form.IncludesAllRepositories,
 		CanCreateOrgRepo:        form.CanCreateOrgRepo,
 		Authorize:               models.ParseAccessMode(form.Permission),
+		ParentTeamID:            form.ParentTeamID,
 	}
 
 	unitTypes := models.FindUnitTypes(form.Units...)
@@ -186,7 +223,7 @@ func CreateTeam(ctx *context.APIContext) {
 	}
 
 	if err := models.NewTeam(team); err != nil {
-		if models.IsErrTeamAlreadyExist(err) {
+		if models.IsErrTeamAlreadyExist(err) || models.IsErrTeamParentInvalid(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "NewTeam", err)
@@ -219,6 +256,8 @@ func EditTeam(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Team"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.EditTeamOption)
 
@@ -240,6 +279,10 @@ func EditTeam(ctx *context.APIContext) {
 		team.Description = *form.Description
 	}
 
+	if form.ParentTeamID != nil {
+		team.ParentTeamID = *form.ParentTeamID
+	}
+
 	isAuthChanged := false
 	isIncludeAllChanged := false
 	if !team.IsOwnerTeam() && len(form.Permission) != 0 {
@@ -272,7 +315,11 @@ func EditTeam(ctx *context.APIContext) {
 	}
 
 	if err := models.UpdateTeam(team, isAuthChanged, isIncludeAllChanged); err != nil {
-		ctx.Error(http.StatusInternalServerError, "EditTeam", err)
+		if models.IsErrTeamParentInvalid(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "EditTeam", err)
+		}
 		return
 	}
 	ctx.JSON(http.StatusOK, convert.ToTeam(team))
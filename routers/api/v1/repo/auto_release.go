@@ -0,0 +1,153 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	release_service "code.gitea.io/gitea/services/release"
+)
+
+// GetRepoAutoRelease get the auto release rule of a repository
+func GetRepoAutoRelease(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/auto_release repository repoGetAutoRelease
+	// ---
+	// summary: Get the rule creating a tag and a release when the version file of the default branch changes
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoAutoRelease"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	rule, err := models.GetRepoAutoRelease(ctx.Repo.Repository.ID)
+	if err != nil {
+		if err == models.ErrRepoAutoReleaseNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoAutoRelease", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoAutoRelease(rule))
+}
+
+// CreateOrUpdateRepoAutoRelease set the auto release rule of a repository
+func CreateOrUpdateRepoAutoRelease(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/auto_release repository repoCreateOrUpdateAutoRelease
+	// ---
+	// summary: Create a tag and a release each time a push changes the version in a file of the default branch
+	// description: A release is created once per version, pushes which do not change the version or bring back
+	//   the version of the latest release are ignored, as are versions whose tag already exists.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateRepoAutoReleaseOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoAutoRelease"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateOrUpdateRepoAutoReleaseOption)
+	filePath := strings.TrimPrefix(path.Clean("/"+form.FilePath), "/")
+	if filePath == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid file path")
+		return
+	}
+
+	rule := &models.RepoAutoRelease{
+		RepoID:         ctx.Repo.Repository.ID,
+		FilePath:       filePath,
+		VersionPattern: form.VersionPattern,
+		TagTemplate:    form.TagTemplate,
+		TitleTemplate:  form.TitleTemplate,
+		NotesTemplate:  form.NotesTemplate,
+		IsDraft:        form.IsDraft,
+		IsPrerelease:   form.IsPrerelease,
+	}
+	if err := release_service.ValidateAutoRelease(rule); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+	if err := models.CreateOrUpdateRepoAutoRelease(rule); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateOrUpdateRepoAutoRelease", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoAutoRelease(rule))
+}
+
+// DeleteRepoAutoRelease delete the auto release rule of a repository
+func DeleteRepoAutoRelease(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/auto_release repository repoDeleteAutoRelease
+	// ---
+	// summary: Stop creating releases when the version file of the default branch changes
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteRepoAutoRelease(ctx.Repo.Repository.ID); err != nil {
+		if err == models.ErrRepoAutoReleaseNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteRepoAutoRelease", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
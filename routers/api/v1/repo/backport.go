@@ -0,0 +1,148 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListBackportMappings list the backport branch mappings of a repository
+func ListBackportMappings(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/backport-mappings repository repoListBackportMappings
+	// ---
+	// summary: List the backport branch mappings of a repository
+	// description: Labeling a merged pull request with a backport label opens a pull request with its changes
+	//   against the branch the label maps to. Without a mapping a label `backport/<branch>` maps to `<branch>`.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BackportMappingList"
+
+	mappings, err := models.GetBackportMappings(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBackportMappings", err)
+		return
+	}
+
+	apiMappings := make([]*api.BackportMapping, 0, len(mappings))
+	for _, m := range mappings {
+		apiMappings = append(apiMappings, convert.ToBackportMapping(m))
+	}
+	ctx.JSON(http.StatusOK, apiMappings)
+}
+
+// CreateBackportMapping create a backport branch mapping for a repository
+func CreateBackportMapping(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/backport-mappings repository repoCreateBackportMapping
+	// ---
+	// summary: Map a backport label to the branch merged pull requests with the label are backported to
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateBackportMappingOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/BackportMapping"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateBackportMappingOption)
+	if !models.IsBackportLabel(form.Label) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("label must start with %s", models.BackportLabelPrefix))
+		return
+	}
+
+	m := &models.BackportMapping{
+		RepoID:       ctx.Repo.Repository.ID,
+		Label:        form.Label,
+		TargetBranch: form.TargetBranch,
+	}
+	if err := models.CreateBackportMapping(m); err != nil {
+		if err == models.ErrBackportMappingAlreadyExist {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateBackportMapping", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToBackportMapping(m))
+}
+
+// DeleteBackportMapping delete a backport branch mapping of a repository
+func DeleteBackportMapping(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/backport-mappings/{id} repository repoDeleteBackportMapping
+	// ---
+	// summary: Delete a backport branch mapping of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the mapping to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteBackportMapping(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if err == models.ErrBackportMappingNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteBackportMapping", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/badge"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/httpcache"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+const svgSuffix = ".svg"
+
+// maxBadgeLabelLength limits the length of a custom badge label
+const maxBadgeLabelLength = 64
+
+var commitStatusBadges = map[api.CommitStatusState][2]string{
+	api.CommitStatusPending: {"pending", badge.ColorYellow},
+	api.CommitStatusSuccess: {"passing", badge.ColorGreen},
+	api.CommitStatusError:   {"error", badge.ColorRed},
+	api.CommitStatusFailure: {"failing", badge.ColorRed},
+	api.CommitStatusWarning: {"warning", badge.ColorOrange},
+}
+
+var workflowStatusBadges = map[models.WorkflowStatus][2]string{
+	models.WorkflowStatusWaiting:   {"waiting", badge.ColorYellow},
+	models.WorkflowStatusPending:   {"pending", badge.ColorYellow},
+	models.WorkflowStatusRunning:   {"running", badge.ColorBlue},
+	models.WorkflowStatusSuccess:   {"passing", badge.ColorGreen},
+	models.WorkflowStatusFailure:   {"failing", badge.ColorRed},
+	models.WorkflowStatusCancelled: {"cancelled", badge.ColorGrey},
+	models.WorkflowStatusSkipped:   {"skipped", badge.ColorGrey},
+}
+
+// GetCommitStatusBadge renders the combined commit status of a branch as SVG badge
+func GetCommitStatusBadge(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/commit-status/{branch}.svg repository repoGetCommitStatusBadge
+	// ---
+	// summary: Get a SVG badge of the combined commit status of a branch
+	// produces:
+	// - image/svg+xml
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: name of the branch
+	//   type: string
+	//   required: true
+	// - name: label
+	//   in: query
+	//   description: text of the left side of the badge, defaults to "status"
+	//   type: string
+	// responses:
+	//   "200":
+	//     description: SVG badge
+	//   "304":
+	//     description: badge not modified
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	branch := ctx.Params("*")
+	if !strings.HasSuffix(branch, svgSuffix) {
+		ctx.NotFound()
+		return
+	}
+	branch = strings.TrimSuffix(branch, svgSuffix)
+
+	sha, err := ctx.Repo.GitRepo.GetBranchCommitID(branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBranchCommitID", err)
+		}
+		return
+	}
+
+	statuses, err := models.GetLatestCommitStatus(ctx.Repo.Repository.ID, sha, models.ListOptions{})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLatestCommitStatus", err)
+		return
+	}
+
+	message, color := "unknown", badge.ColorGrey
+	if combined := models.CalcCommitStatus(statuses); combined != nil {
+		if b, ok := commitStatusBadges[combined.State]; ok {
+			message, color = b[0], b[1]
+		}
+	}
+	serveBadge(ctx, ctx.FormString("label"), "status", message, color)
+}
+
+// GetWorkflowBadge renders the status of the latest run of a workflow as SVG badge
+func GetWorkflowBadge(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/workflows/{workflow}.svg repository repoGetWorkflowBadge
+	// ---
+	// summary: Get a SVG badge of the status of the latest run of a workflow
+	// produces:
+	// - image/svg+xml
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow
+	//   in: path
+	//   description: name of the workflow file, e.g. ci.yml
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branch the workflow ran for, defaults to the default branch of the repository
+	//   type: string
+	// - name: label
+	//   in: query
+	//   description: text of the left side of the badge, defaults to the name of the workflow
+	//   type: string
+	// responses:
+	//   "200":
+	//     description: SVG badge
+	//   "304":
+	//     description: badge not modified
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	workflowID := ctx.Params("*")
+	if !strings.HasSuffix(workflowID, svgSuffix) {
+		ctx.NotFound()
+		return
+	}
+	workflowID = strings.TrimSuffix(workflowID, svgSuffix)
+
+	branch := ctx.FormTrim("branch")
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+
+	runs, _, err := models.FindWorkflowRuns(&models.FindWorkflowRunOptions{
+		ListOptions: models.ListOptions{Page: 1, PageSize: 1},
+		RepoID:      ctx.Repo.Repository.ID,
+		WorkflowID:  workflowID,
+		Ref:         git.BranchPrefix + branch,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindWorkflowRuns", err)
+		return
+	}
+
+	defaultLabel, message, color := workflowID, "unknown", badge.ColorGrey
+	if len(runs) > 0 {
+		defaultLabel = runs[0].Name
+		if b, ok := workflowStatusBadges[runs[0].Status]; ok {
+			message, color = b[0], b[1]
+		}
+	}
+	serveBadge(ctx, ctx.FormString("label"), defaultLabel, message, color)
+}
+
+// serveBadge writes the badge. Clients have to revalidate the badge on every request,
+// so the ETag lets them skip the download while the status did not change.
+// Badges with custom labels are not cached, as anyone could fill the cache with them.
+func serveBadge(ctx *context.APIContext, label, defaultLabel, message, color string) {
+	var svg string
+	if label != "" {
+		if labelRunes := []rune(label); len(labelRunes) > maxBadgeLabelLength {
+			label = string(labelRunes[:maxBadgeLabelLength])
+		}
+		svg = badge.Generate(label, message, color)
+	} else {
+		var err error
+		if svg, err = badge.GetOrGenerate(defaultLabel, message, color); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetOrGenerate", err)
+			return
+		}
+	}
+
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	if httpcache.HandleGenericETagCache(ctx.Req, ctx.Resp, `"`+base.EncodeSha1(svg)+`"`) {
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write([]byte(svg)); err != nil {
+		log.Error("Write badge: %v", err)
+	}
+}
@@ -5,10 +5,18 @@
 package repo
 
 import (
+	"bytes"
+	"encoding/base64"
+	"io"
 	"net/http"
+	"strings"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // GetBlob get the blob of a repository file.
@@ -51,3 +59,77 @@ func GetBlob(ctx *context.APIContext) {
 		ctx.JSON(http.StatusOK, blob)
 	}
 }
+
+// CreateBlob writes a blob object to a repository
+func CreateBlob(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/blobs repository repoCreateBlob
+	// ---
+	// summary: Create a blob object, which trees can then reference
+	// description: The content is given in a JSON body, or streamed as the raw request body with the `application/octet-stream` content type, which suits large files. Its size is limited by the `max_blob_upload_size` API setting.
+	// consumes:
+	// - application/json
+	// - application/octet-stream
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateGitBlobOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/CreatedGitBlob"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	maxSize := setting.API.MaxBlobUploadSize
+	content := io.Reader(ctx.Req.Body)
+	if !strings.HasPrefix(ctx.Req.Header.Get("Content-Type"), "application/octet-stream") {
+		// escaped or base64 encoded content is larger than the blob, the decoded size is checked below
+		form := new(api.CreateGitBlobOption)
+		if err := json.NewDecoder(io.LimitReader(ctx.Req.Body, 2*maxSize+4096)).Decode(form); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		switch form.Encoding {
+		case "", "utf-8":
+			content = strings.NewReader(form.Content)
+		case "base64":
+			data, err := base64.StdEncoding.DecodeString(form.Content)
+			if err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+				return
+			}
+			content = bytes.NewReader(data)
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "", "encoding must be utf-8 or base64")
+			return
+		}
+	}
+
+	blob, err := repofiles.CreateBlob(ctx.Repo.Repository, content, maxSize)
+	if err != nil {
+		if models.IsErrBlobTooLarge(err) {
+			ctx.Error(http.StatusRequestEntityTooLarge, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateBlob", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, blob)
+}
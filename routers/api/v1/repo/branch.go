@@ -9,13 +9,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"path"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
 	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	pull_service "code.gitea.io/gitea/services/pull"
@@ -48,6 +52,8 @@ func GetBranch(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Branch"
+	//   "301":
+	//     description: The branch was renamed, the Location header is the URL of the new name.
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
@@ -56,7 +62,7 @@ func GetBranch(ctx *context.APIContext) {
 	branch, err := repo_module.GetBranch(ctx.Repo.Repository, branchName)
 	if err != nil {
 		if git.IsErrBranchNotExist(err) {
-			ctx.NotFound(err)
+			redirectRenamedBranch(ctx, branchName, err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "GetBranch", err)
 		}
@@ -84,6 +90,22 @@ func GetBranch(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, br)
 }
 
+// redirectRenamedBranch redirects permanently to the new name of a renamed branch, or responds not found
+func redirectRenamedBranch(ctx *context.APIContext, branchName string, notFoundErr error) {
+	renamed, err := models.FindRenamedBranch(ctx.Repo.Repository.ID, branchName)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRenamedBranch", err)
+		return
+	} else if renamed == nil {
+		ctx.NotFound(notFoundErr)
+		return
+	}
+	ctx.Redirect(path.Join(
+		setting.AppSubURL,
+		strings.TrimSuffix(ctx.Req.URL.EscapedPath(), util.PathEscapeSegments(branchName)),
+		util.PathEscapeSegments(renamed.To)), http.StatusMovedPermanently)
+}
+
 // DeleteBranch get a branch of a repository
 func DeleteBranch(ctx *context.APIContext) {
 	// swagger:operation DELETE /repos/{owner}/{repo}/branches/{branch} repository repoDeleteBranch
@@ -134,6 +156,92 @@ func DeleteBranch(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// RenameDefaultBranch renames the default branch of a repository
+func RenameDefaultBranch(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/default-branch/rename repository repoRenameDefaultBranch
+	// ---
+	// summary: Rename the default branch, its protection and the base branch of its pull requests
+	// description: Requests for the old name of the branch are redirected to the new name.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RenameDefaultBranchOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Branch"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: A branch or tag with the new name already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opt := web.GetForm(ctx).(*api.RenameDefaultBranchOption)
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return
+	}
+	if ctx.Repo.Repository.IsMirror {
+		ctx.Error(http.StatusUnprocessableEntity, "", "The branches of a mirror can not be renamed.")
+		return
+	}
+
+	if err := repo_service.RenameBranch(ctx.User, ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.Repo.Repository.DefaultBranch, opt.Name); err != nil {
+		switch {
+		case models.IsErrBranchDoesNotExist(err):
+			ctx.NotFound(err)
+		case models.IsErrBranchAlreadyExists(err), models.IsErrBranchNameConflict(err), models.IsErrTagAlreadyExists(err):
+			ctx.Error(http.StatusConflict, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "RenameBranch", err)
+		}
+		return
+	}
+
+	branch, err := ctx.Repo.GitRepo.GetBranch(opt.Name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranch", err)
+		return
+	}
+
+	commit, err := branch.GetCommit()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return
+	}
+
+	branchProtection, err := ctx.Repo.Repository.GetBranchProtection(branch.Name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranchProtection", err)
+		return
+	}
+
+	br, err := convert.ToBranch(ctx.Repo.Repository, branch, commit, branchProtection, ctx.User, ctx.Repo.IsAdmin())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "convert.ToBranch", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, br)
+}
+
 // CreateBranch creates a branch for a user's repository
 func CreateBranch(ctx *context.APIContext) {
 	// swagger:operation POST /repos/{owner}/{repo}/branches repository repoCreateBranch
@@ -424,6 +532,11 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		requiredApprovals = form.RequiredApprovals
 	}
 
+	var statusCheckMaxAge int64
+	if form.StatusCheckMaxAge > 0 {
+		statusCheckMaxAge = form.StatusCheckMaxAge
+	}
+
 	whitelistUsers, err := models.GetUserIDsByNames(form.PushWhitelistUsernames, false)
 	if err != nil {
 		if models.IsErrUserNotExist(err) {
@@ -491,6 +604,7 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		WhitelistDeployKeys:           form.EnablePush && form.EnablePushWhitelist && form.PushWhitelistDeployKeys,
 		EnableStatusCheck:             form.EnableStatusCheck,
 		StatusCheckContexts:           form.StatusCheckContexts,
+		StatusCheckMaxAge:             statusCheckMaxAge,
 		EnableApprovalsWhitelist:      form.EnableApprovalsWhitelist,
 		RequiredApprovals:             requiredApprovals,
 		BlockOnRejectedReviews:        form.BlockOnRejectedReviews,
@@ -615,6 +729,9 @@ func EditBranchProtection(ctx *context.APIContext) {
 	if protectBranch.EnableStatusCheck {
 		protectBranch.StatusCheckContexts = form.StatusCheckContexts
 	}
+	if form.StatusCheckMaxAge != nil && *form.StatusCheckMaxAge >= 0 {
+		protectBranch.StatusCheckMaxAge = *form.StatusCheckMaxAge
+	}
 
 	if form.RequiredApprovals != nil && *form.RequiredApprovals >= 0 {
 		protectBranch.RequiredApprovals = *form.RequiredApprovals
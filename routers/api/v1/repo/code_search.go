@@ -0,0 +1,119 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// SearchCode searches the code of all repositories the doer may read
+func SearchCode(ctx *context.APIContext) {
+	// swagger:operation GET /repos/code-search repository repoSearchCode
+	// ---
+	// summary: Search the code of all repositories the user may read
+	// description: Requires the code indexer to be enabled.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword to search for
+	//   type: string
+	//   required: true
+	// - name: mode
+	//   in: query
+	//   description: "`match` finds words starting with the keyword, `fuzzy` (default) finds the keyword as phrase"
+	//   type: string
+	//   enum: [fuzzy, match]
+	// - name: language
+	//   in: query
+	//   description: only search files of this language
+	//   type: string
+	// - name: filename
+	//   in: query
+	//   description: only search files whose path matches this wildcard pattern, e.g. `*.go`
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResults"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	repoIDs, err := utils.CodeSearchableRepoIDs(ctx, 0)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CodeSearchableRepoIDs", err)
+		return
+	}
+	utils.SearchCode(ctx, repoIDs)
+}
+
+// SearchRepoCode searches the code of a repository
+func SearchRepoCode(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/code-search repository repoSearchRepoCode
+	// ---
+	// summary: Search the code of a repository
+	// description: Requires the code indexer to be enabled.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: keyword to search for
+	//   type: string
+	//   required: true
+	// - name: mode
+	//   in: query
+	//   description: "`match` finds words starting with the keyword, `fuzzy` (default) finds the keyword as phrase"
+	//   type: string
+	//   enum: [fuzzy, match]
+	// - name: language
+	//   in: query
+	//   description: only search files of this language
+	//   type: string
+	// - name: filename
+	//   in: query
+	//   description: only search files whose path matches this wildcard pattern, e.g. `*.go`
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResults"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.SearchCode(ctx, []int64{ctx.Repo.Repository.ID})
+}
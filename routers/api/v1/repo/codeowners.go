@@ -0,0 +1,163 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ValidateCodeOwners checks the proposed content of the code owners file of a repository
+func ValidateCodeOwners(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/codeowners/validate repository repoValidateCodeOwners
+	// ---
+	// summary: Check the content of a code owners file for syntax errors, unknown users and teams and unreachable rules
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ValidateCodeOwnersOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeOwnersValidation"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ValidateCodeOwnersOption)
+
+	var commit *git.Commit
+	if !ctx.Repo.Repository.IsEmpty {
+		ref := form.Ref
+		if ref == "" {
+			ref = ctx.Repo.Repository.DefaultBranch
+		}
+		var err error
+		commit, err = ctx.Repo.GitRepo.GetCommit(ref)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+			}
+			return
+		}
+	}
+
+	problems, err := repo_service.ValidateCodeOwners(ctx.Repo.Repository, commit, []byte(form.Content))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ValidateCodeOwners", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.CodeOwnersValidation{
+		Valid:  len(problems) == 0,
+		Errors: problems,
+	})
+}
+
+// GetPullRequestCodeOwners lists the owners of the files changed by a pull request and whether they approved it
+func GetPullRequestCodeOwners(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/codeowners repository repoGetPullRequestCodeOwners
+	// ---
+	// summary: List the owners of the files changed by a pull request according to the code owners file of the base branch, and whether they approved it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestCodeOwners"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	codeOwnersFile, files, err := repo_service.GetPullRequestCodeOwners(pr)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetPullRequestCodeOwners", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestCodeOwners", err)
+		}
+		return
+	}
+
+	result := &api.PullRequestCodeOwners{
+		CodeOwnersFile: codeOwnersFile,
+		Files:          make([]*api.PullRequestFileOwners, 0, len(files)),
+		Approved:       true,
+	}
+	for _, file := range files {
+		apiFile := &api.PullRequestFileOwners{
+			Filename: file.Path,
+			Owners:   make([]*api.CodeOwner, 0, len(file.Owners)),
+			Approved: file.Approved,
+		}
+		if file.Rule != nil {
+			apiFile.RuleLine = file.Rule.Line
+			apiFile.Pattern = file.Rule.Pattern
+		}
+		for _, owner := range file.Owners {
+			apiOwner := &api.CodeOwner{
+				Name:     owner.Owner.String(),
+				Type:     string(owner.Owner.Type),
+				Team:     convert.ToTeam(owner.Team),
+				Approved: owner.Approved,
+			}
+			if owner.User != nil {
+				apiOwner.User = convert.ToUser(owner.User, ctx.User)
+			}
+			apiFile.Owners = append(apiFile.Owners, apiOwner)
+		}
+		result.Files = append(result.Files, apiFile)
+		result.Approved = result.Approved && file.Approved
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -8,6 +8,7 @@ package repo
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -120,6 +121,74 @@ func IsCollaborator(ctx *context.APIContext) {
 	}
 }
 
+// GetRepoPermissions gets the permission of a user in a repository
+func GetRepoPermissions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/collaborators/{collaborator}/permission repository repoGetRepoPermissions
+	// ---
+	// summary: Get the permission of a user in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: collaborator
+	//   in: path
+	//   description: username of the collaborator
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoCollaboratorPermission"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !ctx.IsUserRepoAdmin() && ctx.User.LowerName != strings.ToLower(ctx.Params(":collaborator")) {
+		ctx.Error(http.StatusForbidden, "User", "Only admins can query the permissions of other users")
+		return
+	}
+
+	collaborator, err := models.GetUserByName(ctx.Params(":collaborator"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+
+	permission := &api.RepoCollaboratorPermission{
+		User: convert.ToUser(collaborator, ctx.User),
+	}
+	collaboration, err := ctx.Repo.Repository.GetCollaboration(collaborator.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCollaboration", err)
+		return
+	}
+	if collaboration != nil && collaboration.IsGuest && ctx.Repo.Repository.IsPrivate {
+		permission.Permission = collaboration.Permission()
+	} else {
+		perm, err := models.GetUserRepoPermission(ctx.Repo.Repository, collaborator)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		permission.Permission = perm.AccessMode.String()
+	}
+
+	ctx.JSON(http.StatusOK, permission)
+}
+
 // AddCollaborator add a collaborator to a repository
 func AddCollaborator(ctx *context.APIContext) {
 	// swagger:operation PUT /repos/{owner}/{repo}/collaborators/{collaborator} repository repoAddCollaborator
@@ -175,7 +244,12 @@ func AddCollaborator(ctx *context.APIContext) {
 		return
 	}
 
-	if form.Permission != nil {
+	if form.Permission != nil && *form.Permission == "guest" {
+		if err := ctx.Repo.Repository.ChangeCollaborationToGuest(collaborator.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ChangeCollaborationToGuest", err)
+			return
+		}
+	} else if form.Permission != nil {
 		if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(collaborator.ID, models.ParseAccessMode(*form.Permission)); err != nil {
 			ctx.Error(http.StatusInternalServerError, "ChangeCollaborationAccessMode", err)
 			return
@@ -10,15 +10,22 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+
+	"github.com/gobwas/glob"
 )
 
 // GetSingleCommit get a commit via sha
@@ -44,6 +51,22 @@ func GetSingleCommit(ctx *context.APIContext) {
 	//   description: a git ref or commit sha
 	//   type: string
 	//   required: true
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the commit to return, e.g. sha,commit.message,author.login. The files are only listed if requested. All fields are returned if not given
+	//   type: string
+	// - name: stat
+	//   in: query
+	//   description: include the numbers of lines added and deleted by the commit
+	//   type: boolean
+	// - name: verification
+	//   in: query
+	//   description: include the verification of the signature of the commit
+	//   type: boolean
+	// - name: files
+	//   in: query
+	//   description: list the files affected by the commit, true by default unless the files are left out by fields
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Commit"
@@ -77,12 +100,74 @@ func getCommit(ctx *context.APIContext, identifier string) {
 		return
 	}
 
-	json, err := convert.ToCommit(ctx.Repo.Repository, commit, nil)
+	json, err := convert.ToCommit(ctx.Repo.Repository, commit, nil, toCommitOptions(ctx))
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "toCommit", err)
 		return
 	}
-	ctx.JSON(http.StatusOK, json)
+	ctx.JSONWithFields(http.StatusOK, json)
+}
+
+// GetCommitVerification get the verification of the signature of a commit in detail
+func GetCommitVerification(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/commits/{sha}/verification repository repoGetCommitVerification
+	// ---
+	// summary: Get the verification of the signature of a commit in detail
+	// description: The verification includes the key which verified the signature and whether the repository trusts it per its trust model.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitVerificationDetail"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	sha := ctx.Params(":sha")
+	if (validation.GitRefNamePatternInvalid.MatchString(sha) || !validation.CheckGitRefAdditionalRulesValid(sha)) && !git.SHAPattern.MatchString(sha) {
+		ctx.Error(http.StatusUnprocessableEntity, "no valid ref or sha", fmt.Sprintf("no valid ref or sha: %s", sha))
+		return
+	}
+
+	gitRepo, err := git.OpenRepository(ctx.Repo.Repository.RepoPath())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+		return
+	}
+	defer gitRepo.Close()
+	commit, err := gitRepo.GetCommit(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(sha)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "gitRepo.GetCommit", err)
+		return
+	}
+
+	detail, err := convert.ToCommitVerificationDetail(ctx.Repo.Repository, commit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToCommitVerificationDetail", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, detail)
 }
 
 // GetAllCommits get all commits via
@@ -107,14 +192,52 @@ func GetAllCommits(ctx *context.APIContext) {
 	//   in: query
 	//   description: SHA or branch to start listing commits from (usually 'master')
 	//   type: string
+	// - name: path
+	//   in: query
+	//   description: only list the commits which changed the file or directory at the path
+	//   type: string
+	// - name: author
+	//   in: query
+	//   description: only list the commits whose author name or email matches the pattern
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only list the commits committed after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: until
+	//   in: query
+	//   description: only list the commits committed before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
 	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: cursor of the page to return, taken from the next link of the previous page. An empty cursor returns the first page paginated by cursor, without counting the commits
+	//   type: string
 	// - name: limit
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the commits to return, e.g. sha,commit.message,author.login. The files are only listed if requested. All fields are returned if not given
+	//   type: string
+	// - name: stat
+	//   in: query
+	//   description: include the numbers of lines added and deleted by the commits
+	//   type: boolean
+	// - name: verification
+	//   in: query
+	//   description: include the verification of the signature of the commits
+	//   type: boolean
+	// - name: files
+	//   in: query
+	//   description: list the files affected by the commits, true by default unless the files are left out by fields
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/CommitList"
@@ -122,6 +245,8 @@ func GetAllCommits(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	//   "409":
 	//     "$ref": "#/responses/EmptyRepository"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	if ctx.Repo.Repository.IsEmpty {
 		ctx.JSON(http.StatusConflict, api.APIError{
@@ -147,7 +272,23 @@ func GetAllCommits(ctx *context.APIContext) {
 		listOptions.PageSize = setting.Git.CommitsRangeSize
 	}
 
+	cursorSHA, skip, err := utils.GetCommitCursor(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetCommitCursor", err)
+		return
+	}
+
+	filter, err := getCommitsFilter(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "getCommitsFilter", err)
+		return
+	}
+
 	sha := ctx.FormString("sha")
+	if cursorSHA != "" {
+		// the cursor pins the commit the first page was listed from, so that new commits do not shift the pages
+		sha = cursorSHA
+	}
 
 	var baseCommit *git.Commit
 	if len(sha) == 0 {
@@ -172,32 +313,45 @@ func GetAllCommits(ctx *context.APIContext) {
 		}
 	}
 
+	if utils.IsCursorPagination(ctx) {
+		// counting the commits walks the whole history, so it is skipped
+		commits, err := baseCommit.FilteredCommitsBySkip(filter, skip, listOptions.PageSize)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "FilteredCommitsBySkip", err)
+			return
+		}
+		apiCommits, err := toAPICommits(ctx, commits)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "toCommit", err)
+			return
+		}
+		if len(commits) == listOptions.PageSize {
+			ctx.SetCursorLinkHeader(utils.EncodeCommitCursor(baseCommit.ID.String(), skip+len(commits)))
+		}
+		ctx.JSONWithFields(http.StatusOK, apiCommits)
+		return
+	}
+
 	// Total commit count
-	commitsCountTotal, err := baseCommit.CommitsCount()
+	commitsCountTotal, err := baseCommit.FilteredCommitsCount(filter)
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "GetCommitsCount", err)
+		ctx.Error(http.StatusInternalServerError, "FilteredCommitsCount", err)
 		return
 	}
 
 	pageCount := int(math.Ceil(float64(commitsCountTotal) / float64(listOptions.PageSize)))
 
 	// Query commits
-	commits, err := baseCommit.CommitsByRange(listOptions.Page, listOptions.PageSize)
+	commits, err := baseCommit.FilteredCommitsBySkip(filter, (listOptions.Page-1)*listOptions.PageSize, listOptions.PageSize)
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "CommitsByRange", err)
+		ctx.Error(http.StatusInternalServerError, "FilteredCommitsBySkip", err)
 		return
 	}
 
-	userCache := make(map[string]*models.User)
-
-	apiCommits := make([]*api.Commit, len(commits))
-	for i, commit := range commits {
-		// Create json struct
-		apiCommits[i], err = convert.ToCommit(ctx.Repo.Repository, commit, userCache)
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, "toCommit", err)
-			return
-		}
+	apiCommits, err := toAPICommits(ctx, commits)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toCommit", err)
+		return
 	}
 
 	ctx.SetLinkHeader(int(commitsCountTotal), listOptions.PageSize)
@@ -211,7 +365,56 @@ func GetAllCommits(ctx *context.APIContext) {
 	ctx.Header().Set("X-HasMore", strconv.FormatBool(listOptions.Page < pageCount))
 	ctx.AppendAccessControlExposeHeaders("X-Page", "X-PerPage", "X-Total", "X-PageCount", "X-HasMore")
 
-	ctx.JSON(http.StatusOK, &apiCommits)
+	ctx.JSONWithFields(http.StatusOK, apiCommits)
+}
+
+// getCommitsFilter returns the filter of the listed commits given by the path, author, since and until query parameters
+func getCommitsFilter(ctx *context.APIContext) (*git.CommitsFilter, error) {
+	filter := &git.CommitsFilter{
+		Path:   ctx.FormTrim("path"),
+		Author: ctx.FormTrim("author"),
+	}
+	if since := ctx.FormTrim("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since: %v", err)
+		}
+		filter.Since = t.Format(time.RFC3339)
+	}
+	if until := ctx.FormTrim("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid until: %v", err)
+		}
+		filter.Until = t.Format(time.RFC3339)
+	}
+	return filter, nil
+}
+
+func toAPICommits(ctx *context.APIContext, commits []*git.Commit) ([]*api.Commit, error) {
+	userCache := make(map[string]*models.User)
+	opts := toCommitOptions(ctx)
+
+	apiCommits := make([]*api.Commit, len(commits))
+	for i, commit := range commits {
+		var err error
+		// Create json struct
+		if apiCommits[i], err = convert.ToCommit(ctx.Repo.Repository, commit, userCache, opts); err != nil {
+			return nil, err
+		}
+	}
+	return apiCommits, nil
+}
+
+// toCommitOptions returns the parts of the commits requested by the query, the stats, the verification
+// and the files each run git for every commit. The stats and the verification are skipped unless requested,
+// the files are still listed by default.
+func toCommitOptions(ctx *context.APIContext) convert.ToCommitOptions {
+	return convert.ToCommitOptions{
+		Stat:         ctx.FormBool("stat"),
+		Verification: ctx.FormBool("verification"),
+		Files:        ctx.FormOptionalBool("files") != util.OptionalBoolFalse && ctx.FieldSet().Has("files"),
+	}
 }
 
 // DownloadCommitDiffOrPatch render a commit's raw diff or patch
@@ -263,3 +466,414 @@ func DownloadCommitDiffOrPatch(ctx *context.APIContext) {
 		return
 	}
 }
+
+// GetCommitBackports reports which branches contain the change of a commit
+func GetCommitBackports(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/commits/{sha}/backports repository repoGetCommitBackports
+	// ---
+	// summary: Get the branches which contain the change of a commit and the branches it needs to be backported to
+	// description: A branch contains the change if it contains the commit or a commit with the same patch id,
+	//   e.g. a cherry-pick of it. Only the ancestry is checked for root and merge commits.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// - name: branches
+	//   in: query
+	//   description: glob patterns of the branches to check, e.g. `release/*`
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitBackportStatus"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	patterns := ctx.FormStrings("branches")
+	if len(patterns) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "branches is required")
+		return
+	}
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid branch pattern %q: %v", pattern, err))
+			return
+		}
+		globs = append(globs, g)
+	}
+
+	commit, err := ctx.Repo.GitRepo.GetCommit(ctx.Params(":sha"))
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(ctx.Params(":sha"))
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return
+	}
+
+	branches, _, err := ctx.Repo.GitRepo.GetBranches(0, 0)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranches", err)
+		return
+	}
+
+	status := &api.CommitBackportStatus{
+		SHA:       commit.ID.String(),
+		Contained: []string{},
+		Missing:   []string{},
+	}
+	for _, branch := range branches {
+		matched := false
+		for _, g := range globs {
+			if g.Match(branch) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		contained, err := ctx.Repo.GitRepo.IsCommitChangeInBranch(status.SHA, branch)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsCommitChangeInBranch", err)
+			return
+		}
+		if contained {
+			status.Contained = append(status.Contained, branch)
+		} else {
+			status.Missing = append(status.Missing, branch)
+		}
+	}
+	ctx.JSON(http.StatusOK, status)
+}
+
+// maxCommitsPerBatch is the maximum number of commits created by a single batch request
+const maxCommitsPerBatch = 100
+
+// CreateCommit creates a commit object from an existing tree
+func CreateCommit(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/commits repository repoCreateCommit
+	// ---
+	// summary: Create a commit object from an existing tree, no branch is updated
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateCommitOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Commit"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateCommitOption)
+	createCommits(ctx, []api.CreateCommitOption{*form}, false)
+}
+
+// CreateCommitsBatch creates several commit objects from existing trees at once
+func CreateCommitsBatch(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/commits/batch repository repoCreateCommitsBatch
+	// ---
+	// summary: Create several commit objects from existing trees in order, no branch is updated
+	// description: A parent of the form `$<index>` references an earlier commit of the batch, e.g. `$0` is the first one.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateCommitsBatchOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/CreatedCommitList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateCommitsBatchOption)
+	if len(form.Commits) > maxCommitsPerBatch {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("at most %d commits can be created at once", maxCommitsPerBatch))
+		return
+	}
+	createCommits(ctx, form.Commits, true)
+}
+
+func createCommits(ctx *context.APIContext, forms []api.CreateCommitOption, batch bool) {
+	opts := make([]*repofiles.CreateCommitOptions, 0, len(forms))
+	for i, form := range forms {
+		if strings.TrimSpace(form.Message) == "" || form.Tree == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("commit %d: the message and the tree are required", i))
+			return
+		}
+		if !batch {
+			for _, parent := range form.Parents {
+				if strings.HasPrefix(parent, "$") {
+					ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("parent %s can only be used in a batch", parent))
+					return
+				}
+			}
+		}
+		opts = append(opts, &repofiles.CreateCommitOptions{
+			Message: form.Message,
+			TreeSHA: form.Tree,
+			Parents: form.Parents,
+			Author: &repofiles.IdentityOptions{
+				Name:  form.Author.Name,
+				Email: form.Author.Email,
+			},
+			Committer: &repofiles.IdentityOptions{
+				Name:  form.Committer.Name,
+				Email: form.Committer.Email,
+			},
+			Dates: &repofiles.CommitDateOptions{
+				Author:    form.Dates.Author,
+				Committer: form.Dates.Committer,
+			},
+			Signoff:  form.Signoff,
+			Trailers: form.Trailers,
+		})
+	}
+
+	commits, err := repofiles.CreateCommits(ctx.Repo.Repository, ctx.User, opts)
+	if err != nil {
+		if models.IsErrSHANotFound(err) || git.IsErrInvalidTrailer(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateCommits", err)
+		}
+		return
+	}
+
+	apiCommits, err := toAPICommits(ctx, commits)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toAPICommits", err)
+		return
+	}
+	if batch {
+		ctx.JSON(http.StatusCreated, apiCommits)
+	} else {
+		ctx.JSON(http.StatusCreated, apiCommits[0])
+	}
+}
+
+// CherryPickCommit applies the changes of a commit onto a branch
+func CherryPickCommit(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/commits/{sha}/cherry-pick repository repoCherryPickCommit
+	// ---
+	// summary: Apply the changes of a commit onto a branch as a new commit
+	// description: The changes of a merge commit are those against its first parent.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ApplyCommitOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Commit"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	applyCommit(ctx, false)
+}
+
+// RevertCommit reverts the changes of a commit on a branch
+func RevertCommit(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/commits/{sha}/revert repository repoRevertCommit
+	// ---
+	// summary: Revert the changes of a commit on a branch with a new commit
+	// description: The changes of a merge commit are those against its first parent.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ApplyCommitOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Commit"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	applyCommit(ctx, true)
+}
+
+func applyCommit(ctx *context.APIContext, revert bool) {
+	form := web.GetForm(ctx).(*api.ApplyCommitOption)
+	if !canWriteFiles(ctx.Repo) {
+		ctx.Error(http.StatusForbidden, "", models.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.User.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+
+	opts := &repofiles.CherryPickOptions{
+		OldBranch: form.BranchName,
+		NewBranch: form.NewBranchName,
+		Message:   form.Message,
+		Revert:    revert,
+		Author: &repofiles.IdentityOptions{
+			Name:  form.Author.Name,
+			Email: form.Author.Email,
+		},
+		Committer: &repofiles.IdentityOptions{
+			Name:  form.Committer.Name,
+			Email: form.Committer.Email,
+		},
+		Signoff:  form.Signoff,
+		Trailers: form.Trailers,
+	}
+	if !form.Dates.Author.IsZero() || !form.Dates.Committer.IsZero() {
+		opts.Dates = &repofiles.CommitDateOptions{
+			Author:    form.Dates.Author,
+			Committer: form.Dates.Committer,
+		}
+		if opts.Dates.Author.IsZero() {
+			opts.Dates.Author = time.Now()
+		}
+		if opts.Dates.Committer.IsZero() {
+			opts.Dates.Committer = time.Now()
+		}
+	}
+
+	commit, err := repofiles.CherryPick(ctx.Repo.Repository, ctx.User, ctx.Params(":sha"), opts)
+	if err != nil {
+		switch {
+		case models.IsErrUserCannotCommit(err), models.IsErrFilePathProtected(err):
+			ctx.Error(http.StatusForbidden, "", err)
+		case git.IsErrPushRejected(err):
+			if msg := err.(*git.ErrPushRejected).Message; msg != "" {
+				ctx.Error(http.StatusForbidden, "", msg)
+			} else {
+				ctx.Error(http.StatusForbidden, "", "the push was rejected")
+			}
+		case models.IsErrSHANotFound(err), models.IsErrBranchDoesNotExist(err), git.IsErrBranchNotExist(err):
+			ctx.NotFound(err)
+		case repofiles.IsErrCherryPickConflict(err), models.IsErrBranchAlreadyExists(err):
+			ctx.Error(http.StatusConflict, "", err)
+		case git.IsErrPushOutOfDate(err):
+			ctx.Error(http.StatusConflict, "", "the branch was updated in the meantime")
+		case err == repofiles.ErrCherryPickEmpty, git.IsErrInvalidTrailer(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CherryPick", err)
+		}
+		return
+	}
+
+	apiCommit, err := convert.ToCommit(ctx.Repo.Repository, commit, nil, convert.ToCommitOptions{Verification: true, Files: true})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiCommit)
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/gitdiff"
+)
+
+// CompareRendered renders the diff between two refs of a repository
+func CompareRendered(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/compare/{basehead}/rendered repository repoCompareRendered
+	// ---
+	// summary: Get the diff between two refs with syntax highlighted lines
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: basehead
+	//   in: path
+	//   description: "refs to compare, in the format `base...head` to compare head with the merge base of both refs or `base..head` to compare them directly"
+	//   type: string
+	//   required: true
+	// - name: word_diff
+	//   in: query
+	//   description: mark the changed words of modified lines
+	//   type: boolean
+	// - name: whitespace
+	//   in: query
+	//   description: how to treat whitespace changes
+	//   type: string
+	//   enum: [ignore-all, ignore-change, ignore-eol]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RenderedDiff"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	basehead := ctx.Params("*")
+	if !strings.HasSuffix(basehead, "/rendered") {
+		ctx.NotFound()
+		return
+	}
+	basehead = strings.TrimSuffix(basehead, "/rendered")
+
+	useMergeBase := true
+	refs := strings.SplitN(basehead, "...", 2)
+	if len(refs) != 2 {
+		useMergeBase = false
+		refs = strings.SplitN(basehead, "..", 2)
+	}
+	if len(refs) != 2 || refs[0] == "" || refs[1] == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "refs must be given as base...head or base..head")
+		return
+	}
+
+	baseCommit, err := ctx.Repo.GitRepo.GetCommit(refs[0])
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+	headCommit, err := ctx.Repo.GitRepo.GetCommit(refs[1])
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+
+	baseSHA, headSHA := baseCommit.ID.String(), headCommit.ID.String()
+	if useMergeBase {
+		if baseSHA, _, err = ctx.Repo.GitRepo.GetMergeBase("", baseSHA, headSHA); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", "refs have no common ancestor")
+			return
+		}
+	}
+
+	diff, err := gitdiff.GetDiffRangeWithWhitespaceBehavior(ctx.Repo.GitRepo, baseSHA, headSHA,
+		setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles,
+		gitdiff.GetWhitespaceFlag(ctx.FormString("whitespace")))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiffRangeWithWhitespaceBehavior", err)
+		return
+	}
+
+	rendered := convert.ToRenderedDiff(diff, baseSHA, headSHA, ctx.FormBool("word_diff"))
+	for i, file := range diff.Files {
+		image, err := gitdiff.GetImageDiff(ctx.Repo.GitRepo, file, baseSHA, headSHA)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetImageDiff", err)
+			return
+		}
+		if image != nil {
+			rendered.Files[i].Image = convert.ToRenderedDiffImage(ctx.Repo.Repository, file, image, baseSHA, headSHA)
+		}
+	}
+	ctx.JSON(http.StatusOK, rendered)
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	dependency_indexer "code.gitea.io/gitea/modules/indexer/dependency"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// getDependencyStatus returns the commit the dependencies of the repository were extracted from,
+// if they were not extracted yet the repository is queued and the 404 response is written
+func getDependencyStatus(ctx *context.APIContext) *models.RepoIndexerStatus {
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return nil
+	}
+
+	status, err := ctx.Repo.Repository.GetIndexerStatus(models.RepoIndexerTypeDependencies)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIndexerStatus", err)
+		return nil
+	}
+	if status.CommitSha == "" {
+		if err := dependency_indexer.UpdateRepoIndexer(ctx.Repo.Repository); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateRepoIndexer", err)
+			return nil
+		}
+		ctx.NotFound("the dependencies of the repository are being analyzed")
+		return nil
+	}
+	return status
+}
+
+// ListDependencies lists the dependencies declared by the manifests of the default branch
+func ListDependencies(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/dependencies repository repoListDependencies
+	// ---
+	// summary: List the dependencies declared by the manifests on the default branch of a repository
+	// description: The supported manifests are go.mod, package.json, composer.json and requirements files of pip.
+	//   Repositories which were not analyzed yet are queued for analysis.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ecosystem
+	//   in: query
+	//   description: only list dependencies of this package ecosystem
+	//   type: string
+	//   enum: [go, npm, pypi, composer]
+	// - name: manifest
+	//   in: query
+	//   description: only list dependencies declared by the manifest at this path
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DependencyList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	getDependencyStatus(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	opts := &models.FindRepoDependencyOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		Ecosystem:   ctx.FormTrim("ecosystem"),
+		Manifest:    ctx.FormTrim("manifest"),
+	}
+	deps, count, err := models.FindRepoDependencies(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoDependencies", err)
+		return
+	}
+
+	apiDeps := make([]*api.Dependency, 0, len(deps))
+	for _, d := range deps {
+		apiDeps = append(apiDeps, convert.ToDependency(d))
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiDeps)
+}
+
+// GetDependencySBOM exports the dependencies of the default branch as software bill of materials
+func GetDependencySBOM(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/dependencies/sbom repository repoGetDependencySBOM
+	// ---
+	// summary: Export the dependencies on the default branch of a repository as CycloneDX software bill of materials
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CycloneDXBOM"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	status := getDependencyStatus(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	deps, _, err := models.FindRepoDependencies(&models.FindRepoDependencyOptions{
+		RepoID: ctx.Repo.Repository.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoDependencies", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToCycloneDXBOM(ctx.Repo.Repository, status.CommitSha, deps))
+}
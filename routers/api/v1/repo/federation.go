@@ -0,0 +1,351 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/federation"
+)
+
+func writeRepoFederation(ctx *context.APIContext, settings *models.RepoFederation) {
+	numStars, err := models.CountRemoteStars(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountRemoteStars", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoFederation(settings, numStars))
+}
+
+// GetFederation returns the federation settings of a repository
+func GetFederation(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/federation repository repoGetFederation
+	// ---
+	// summary: Get whether users of other instances may interact with a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoFederation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	settings, err := models.GetRepoFederation(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoFederation", err)
+		return
+	}
+	writeRepoFederation(ctx, settings)
+}
+
+// EditFederation opts a repository in to federation
+func EditFederation(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/federation repository repoEditFederation
+	// ---
+	// summary: Let users of other instances star a public repository and optionally open issues and comment
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoFederationOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoFederation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !federation.IsFederatedRepo(ctx.Repo.Repository) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "only public repositories of public owners can be federated")
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditRepoFederationOption)
+	settings := &models.RepoFederation{
+		RepoID:        ctx.Repo.Repository.ID,
+		AllowIssues:   form.AllowIssues,
+		AllowComments: form.AllowComments,
+	}
+	if err := models.SetRepoFederation(settings); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetRepoFederation", err)
+		return
+	}
+	writeRepoFederation(ctx, settings)
+}
+
+// DeleteFederation opts a repository out of federation
+func DeleteFederation(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/federation repository repoDeleteFederation
+	// ---
+	// summary: Stop accepting interactions of users of other instances. Their stars are removed, their issues and comments are kept.
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := models.DeleteRepoFederation(ctx.Repo.Repository.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRepoFederation", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListRemoteStargazers lists the remote actors who starred a repository
+func ListRemoteStargazers(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/federation/stargazers repository repoListRemoteStargazers
+	// ---
+	// summary: List the users of other instances who starred a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RemoteStarList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	stars, count, err := models.GetRemoteStars(ctx.Repo.Repository.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRemoteStars", err)
+		return
+	}
+
+	apiStars := make([]*api.RemoteStar, len(stars))
+	for i := range stars {
+		if err := stars[i].LoadRemoteActor(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadRemoteActor", err)
+			return
+		}
+		apiStars[i] = convert.ToRemoteStar(stars[i])
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiStars)
+}
+
+// ListFederationBlocks lists the remote actors and domains blocked from interacting with a repository
+func ListFederationBlocks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/federation/blocks repository repoListFederationBlocks
+	// ---
+	// summary: List the users and domains of other instances blocked from interacting with a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FederationBlockList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	blocks, err := models.GetFederationBlocks(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFederationBlocks", err)
+		return
+	}
+
+	apiBlocks := make([]*api.FederationBlock, len(blocks))
+	for i := range blocks {
+		if err := blocks[i].LoadRemoteActor(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadRemoteActor", err)
+			return
+		}
+		apiBlocks[i] = convert.ToFederationBlock(blocks[i])
+	}
+	ctx.JSON(http.StatusOK, &apiBlocks)
+}
+
+// CreateFederationBlock blocks a remote actor or a domain from interacting with a repository
+func CreateFederationBlock(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/federation/blocks repository repoCreateFederationBlock
+	// ---
+	// summary: Block a user or a domain of other instances from interacting with a repository. The stars of the blocked users are removed.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateFederationBlockOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FederationBlock"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateFederationBlockOption)
+	form.Actor = strings.TrimSpace(form.Actor)
+	form.Domain = strings.TrimSpace(form.Domain)
+	if (form.Actor == "") == (form.Domain == "") {
+		ctx.Error(http.StatusUnprocessableEntity, "", "either an actor or a domain has to be given")
+		return
+	}
+
+	block := &models.FederationBlock{
+		RepoID: ctx.Repo.Repository.ID,
+		Domain: form.Domain,
+		DoerID: ctx.User.ID,
+	}
+	if form.Actor != "" {
+		actor, err := models.GetRemoteActorByURI(form.Actor)
+		if models.IsErrRemoteActorNotExist(err) {
+			actor, err = federation.ResolveActor(ctx, ctx.User, form.Actor)
+		}
+		if err != nil {
+			if activitypub.IsErrBlockedURL(err) || federation.IsErrResolveActor(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "ResolveActor", err)
+			}
+			return
+		}
+		block.RemoteActorID = actor.ID
+		block.RemoteActor = actor
+	}
+
+	if err := models.CreateFederationBlock(block); err != nil {
+		if models.IsErrFederationBlockAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateFederationBlock", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToFederationBlock(block))
+}
+
+// DeleteFederationBlock lifts a block of a repository
+func DeleteFederationBlock(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/federation/blocks/{id} repository repoDeleteFederationBlock
+	// ---
+	// summary: Lift the block of a user or a domain of other instances
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the block
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	block, err := models.GetFederationBlockByID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrFederationBlockNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetFederationBlockByID", err)
+		}
+		return
+	}
+	if err := models.DeleteFederationBlock(block); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteFederationBlock", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/repofiles"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/routers/web/repo"
@@ -258,7 +259,8 @@ func CreateFile(ctx *context.APIContext) {
 			Author:    apiOpts.Dates.Author,
 			Committer: apiOpts.Dates.Committer,
 		},
-		Signoff: apiOpts.Signoff,
+		Signoff:  apiOpts.Signoff,
+		Trailers: apiOpts.Trailers,
 	}
 	if opts.Dates.Author.IsZero() {
 		opts.Dates.Author = time.Now()
@@ -278,6 +280,107 @@ func CreateFile(ctx *context.APIContext) {
 	}
 }
 
+// GenerateFiles handles API call for generating files from the bundled templates
+func GenerateFiles(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/contents/generate repository repoGenerateFiles
+	// ---
+	// summary: Generate a LICENSE and a .gitignore file from the bundled templates and commit them
+	// description: The year and the copyright holder are substituted in the license. See `/file-templates` for the available templates.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/GenerateFilesOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	apiOpts := web.GetForm(ctx).(*api.GenerateFilesOptions)
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Error(http.StatusUnprocessableEntity, "RepoIsEmpty", fmt.Errorf("repo is empty"))
+		return
+	}
+	if !canWriteFiles(ctx.Repo) {
+		ctx.Error(http.StatusForbidden, "Access", models.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.User.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+
+	if apiOpts.License == "" && len(apiOpts.Gitignores) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("license or gitignores is required"))
+		return
+	}
+	if apiOpts.License != "" && !util.IsStringInSlice(apiOpts.License, models.Licenses) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown license template %q", apiOpts.License))
+		return
+	}
+	for _, name := range apiOpts.Gitignores {
+		if !util.IsStringInSlice(name, models.Gitignores) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown gitignore template %q", name))
+			return
+		}
+	}
+
+	opts := &repofiles.GenerateRepoFilesOptions{
+		License:         apiOpts.License,
+		Gitignores:      apiOpts.Gitignores,
+		Year:            apiOpts.Year,
+		CopyrightHolder: apiOpts.CopyrightHolder,
+		OldBranch:       apiOpts.BranchName,
+		NewBranch:       apiOpts.NewBranchName,
+		Message:         apiOpts.Message,
+		Committer: &repofiles.IdentityOptions{
+			Name:  apiOpts.Committer.Name,
+			Email: apiOpts.Committer.Email,
+		},
+		Author: &repofiles.IdentityOptions{
+			Name:  apiOpts.Author.Name,
+			Email: apiOpts.Author.Email,
+		},
+		Dates: &repofiles.CommitDateOptions{
+			Author:    apiOpts.Dates.Author,
+			Committer: apiOpts.Dates.Committer,
+		},
+		Signoff:  apiOpts.Signoff,
+		Trailers: apiOpts.Trailers,
+	}
+	if opts.Dates.Author.IsZero() {
+		opts.Dates.Author = time.Now()
+	}
+	if opts.Dates.Committer.IsZero() {
+		opts.Dates.Committer = time.Now()
+	}
+
+	if fileResponse, err := repofiles.GenerateRepoFiles(ctx.Repo.Repository, ctx.User, opts); err != nil {
+		handleCreateOrUpdateFileError(ctx, err)
+	} else {
+		ctx.JSON(http.StatusCreated, fileResponse)
+	}
+}
+
 // UpdateFile handles API call for updating a file
 func UpdateFile(ctx *context.APIContext) {
 	// swagger:operation PUT /repos/{owner}/{repo}/contents/{filepath} repository repoUpdateFile
@@ -347,7 +450,8 @@ func UpdateFile(ctx *context.APIContext) {
 			Author:    apiOpts.Dates.Author,
 			Committer: apiOpts.Dates.Committer,
 		},
-		Signoff: apiOpts.Signoff,
+		Signoff:  apiOpts.Signoff,
+		Trailers: apiOpts.Trailers,
 	}
 	if opts.Dates.Author.IsZero() {
 		opts.Dates.Author = time.Now()
@@ -368,12 +472,12 @@ func UpdateFile(ctx *context.APIContext) {
 }
 
 func handleCreateOrUpdateFileError(ctx *context.APIContext, err error) {
-	if models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) {
+	if models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) || models.IsErrPushRuleViolated(err) {
 		ctx.Error(http.StatusForbidden, "Access", err)
 		return
 	}
 	if models.IsErrBranchAlreadyExists(err) || models.IsErrFilenameInvalid(err) || models.IsErrSHADoesNotMatch(err) ||
-		models.IsErrFilePathInvalid(err) || models.IsErrRepoFileAlreadyExists(err) {
+		models.IsErrFilePathInvalid(err) || models.IsErrRepoFileAlreadyExists(err) || git.IsErrInvalidTrailer(err) {
 		ctx.Error(http.StatusUnprocessableEntity, "Invalid", err)
 		return
 	}
@@ -474,7 +578,8 @@ func DeleteFile(ctx *context.APIContext) {
 			Author:    apiOpts.Dates.Author,
 			Committer: apiOpts.Dates.Committer,
 		},
-		Signoff: apiOpts.Signoff,
+		Signoff:  apiOpts.Signoff,
+		Trailers: apiOpts.Trailers,
 	}
 	if opts.Dates.Author.IsZero() {
 		opts.Dates.Author = time.Now()
@@ -495,10 +600,11 @@ func DeleteFile(ctx *context.APIContext) {
 			models.IsErrFilenameInvalid(err) ||
 			models.IsErrSHADoesNotMatch(err) ||
 			models.IsErrCommitIDDoesNotMatch(err) ||
-			models.IsErrSHAOrCommitIDNotProvided(err) {
+			models.IsErrSHAOrCommitIDNotProvided(err) ||
+			git.IsErrInvalidTrailer(err) {
 			ctx.Error(http.StatusBadRequest, "DeleteFile", err)
 			return
-		} else if models.IsErrUserCannotCommit(err) {
+		} else if models.IsErrUserCannotCommit(err) || models.IsErrPushRuleViolated(err) {
 			ctx.Error(http.StatusForbidden, "DeleteFile", err)
 			return
 		}
@@ -508,6 +614,71 @@ func DeleteFile(ctx *context.APIContext) {
 	}
 }
 
+// PreviewFileEdit handles API call for previewing the merge of an edit of a file into a branch
+func PreviewFileEdit(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/contents/{filepath}/preview repository repoPreviewFileEdit
+	// ---
+	// summary: Check whether an edit of a file conflicts with the changes made on the branch since its base commit
+	// description: The edit is merged with the file of the branch tip by a 3-way merge, nothing is committed.
+	//   Since this path ends in `/preview`, files named `preview` cannot be created by a POST to their contents path.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the edited file
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/PreviewFileEditOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileEditPreview"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	form := web.GetForm(ctx).(*api.PreviewFileEditOptions)
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Error(http.StatusUnprocessableEntity, "RepoIsEmpty", fmt.Errorf("repo is empty"))
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(form.Content)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("content is not base64 encoded: %v", err))
+		return
+	}
+
+	preview, err := repofiles.PreviewFileEdit(ctx.Repo.Repository, form.BranchName, ctx.Params("*"), form.BaseCommit, content)
+	if err != nil {
+		if git.IsErrNotExist(err) || git.IsErrBranchNotExist(err) {
+			ctx.NotFound(err)
+		} else if models.IsErrSHANotFound(err) || models.IsErrFilenameInvalid(err) || models.IsErrFilePathInvalid(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "Invalid", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "PreviewFileEdit", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, preview)
+}
+
 // GetContents Get the metadata and contents (if a file) of an entry in a repository, or a list of entries if a dir
 func GetContents(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/contents/{filepath} repository repoGetContents
@@ -5,11 +5,16 @@
 package repo
 
 import (
+	"errors"
 	"net/http"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 // GetGitAllRefs get ref or an list all the refs of a repository
@@ -104,3 +109,203 @@ func getGitRefsInternal(ctx *context.APIContext, filter string) {
 	}
 	ctx.JSON(http.StatusOK, &apiRefs)
 }
+
+// CreateGitRef create a branch or tag ref
+func CreateGitRef(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/refs/{ref} repository repoCreateGitRef
+	// ---
+	// summary: Create a branch or tag ref pointing at a commit
+	// description: The ref is created by a push, so the protected branches and tags and the push rules apply.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: path
+	//   description: full name of the ref, e.g. heads/main or tags/v1.0
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateGitRefOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Reference"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateGitRefOption)
+	refName := getGitRefName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	sha, err := repo_service.CreateRef(ctx.User, ctx.Repo.Repository, ctx.Repo.GitRepo, refName, form.SHA)
+	if err != nil {
+		handleGitRefUpdateError(ctx, "CreateRef", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, toAPIGitRef(ctx, refName, sha))
+}
+
+// UpdateGitRef update a branch or tag ref
+func UpdateGitRef(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/git/refs/{ref} repository repoUpdateGitRef
+	// ---
+	// summary: Point a branch or tag ref at another commit
+	// description: Branches are only fast-forwarded and tags not moved unless forced. The ref is updated by a push, so the protected branches and tags and the push rules apply.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: path
+	//   description: full name of the ref, e.g. heads/main or tags/v1.0
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UpdateGitRefOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Reference"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.UpdateGitRefOption)
+	refName := getGitRefName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	sha, err := repo_service.UpdateRef(ctx.User, ctx.Repo.Repository, ctx.Repo.GitRepo, refName, form.SHA, form.Force)
+	if err != nil {
+		handleGitRefUpdateError(ctx, "UpdateRef", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toAPIGitRef(ctx, refName, sha))
+}
+
+// DeleteGitRef delete a branch or tag ref
+func DeleteGitRef(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/git/refs/{ref} repository repoDeleteGitRef
+	// ---
+	// summary: Delete a branch or tag ref
+	// description: The ref is deleted by a push, so the protected branches and tags and the push rules apply. The default branch cannot be deleted.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: path
+	//   description: full name of the ref, e.g. heads/main or tags/v1.0
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	refName := getGitRefName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := repo_service.DeleteRef(ctx.User, ctx.Repo.Repository, refName); err != nil {
+		handleGitRefUpdateError(ctx, "DeleteRef", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getGitRefName(ctx *context.APIContext) string {
+	refName, err := repo_service.NormalizeRefName(ctx.Params("*"))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return ""
+	}
+	return refName
+}
+
+func handleGitRefUpdateError(ctx *context.APIContext, name string, err error) {
+	switch {
+	case git.IsErrNotExist(err):
+		ctx.NotFound(err)
+	case models.IsErrSHANotFound(err), errors.Is(err, repo_service.ErrBranchIsDefault):
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	case models.IsErrBranchAlreadyExists(err), models.IsErrBranchNameConflict(err), models.IsErrTagAlreadyExists(err):
+		ctx.Error(http.StatusConflict, "", err)
+	case git.IsErrPushOutOfDate(err):
+		ctx.Error(http.StatusConflict, "", "the update is not a fast-forward")
+	case git.IsErrPushRejected(err):
+		if msg := err.(*git.ErrPushRejected).Message; msg != "" {
+			ctx.Error(http.StatusForbidden, "", msg)
+		} else {
+			ctx.Error(http.StatusForbidden, "", "the push was rejected")
+		}
+	default:
+		ctx.Error(http.StatusInternalServerError, name, err)
+	}
+}
+
+func toAPIGitRef(ctx *context.APIContext, refName, sha string) *api.Reference {
+	return &api.Reference{
+		Ref: refName,
+		URL: ctx.Repo.Repository.APIURL() + "/git/" + refName,
+		Object: &api.GitObject{
+			SHA:  sha,
+			Type: "commit",
+			URL:  ctx.Repo.Repository.APIURL() + "/git/commits/" + sha,
+		},
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListHookScripts list the hook scripts of a repository
+func ListHookScripts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/scripts repository repoListHookScripts
+	// ---
+	// summary: List the pre-receive and post-receive hook scripts of a repository in the order they are run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookScriptList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	scripts, err := models.GetRepoHookScripts(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoHookScripts", err)
+		return
+	}
+
+	apiScripts := make([]*api.HookScript, len(scripts))
+	for i, script := range scripts {
+		script.Repo = ctx.Repo.Repository
+		if err := script.LoadAttributes(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+			return
+		}
+		apiScripts[i] = convert.ToHookScript(script, ctx.User)
+	}
+	ctx.JSON(http.StatusOK, &apiScripts)
+}
+
+// getHookScriptByParams returns the hook script of the repository with the id of the path
+func getHookScriptByParams(ctx *context.APIContext) *models.RepoHookScript {
+	script, err := models.GetRepoHookScriptByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoHookScriptNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoHookScriptByRepoID", err)
+		}
+		return nil
+	}
+	script.Repo = ctx.Repo.Repository
+	return script
+}
+
+func respondHookScript(ctx *context.APIContext, status int, script *models.RepoHookScript) {
+	if err := script.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(status, convert.ToHookScript(script, ctx.User))
+}
+
+// GetHookScript get a hook script of a repository
+func GetHookScript(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/scripts/{id} repository repoGetHookScript
+	// ---
+	// summary: Get a hook script
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook script to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookScript"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	script := getHookScriptByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	respondHookScript(ctx, http.StatusOK, script)
+}
+
+// CreateHookScript create a hook script of a repository
+func CreateHookScript(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/hooks/scripts repository repoCreateHookScript
+	// ---
+	// summary: Create a hook script, which is only run once a site administrator approved it
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateHookScriptOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/HookScript"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateHookScriptOption)
+	script := &models.RepoHookScript{
+		RepoID:    ctx.Repo.Repository.ID,
+		Repo:      ctx.Repo.Repository,
+		Name:      form.Name,
+		HookType:  models.HookScriptType(form.HookType),
+		Content:   form.Content,
+		IsActive:  form.Active == nil || *form.Active,
+		Sort:      form.Sort,
+		CreatorID: ctx.User.ID,
+		Creator:   ctx.User,
+	}
+	if err := models.CreateRepoHookScript(script); err != nil {
+		handleHookScriptError(ctx, "CreateRepoHookScript", err)
+		return
+	}
+	respondHookScript(ctx, http.StatusCreated, script)
+}
+
+// EditHookScript modify a hook script of a repository
+func EditHookScript(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/hooks/scripts/{id} repository repoEditHookScript
+	// ---
+	// summary: Update a hook script, changing its content or hook type revokes its approval
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook script to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditHookScriptOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookScript"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditHookScriptOption)
+	script := getHookScriptByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		script.Name = *form.Name
+	}
+	if form.HookType != nil {
+		script.HookType = models.HookScriptType(*form.HookType)
+	}
+	if form.Content != nil {
+		script.Content = *form.Content
+	}
+	if form.Active != nil {
+		script.IsActive = *form.Active
+	}
+	if form.Sort != nil {
+		script.Sort = *form.Sort
+	}
+	if err := models.UpdateRepoHookScript(script); err != nil {
+		handleHookScriptError(ctx, "UpdateRepoHookScript", err)
+		return
+	}
+	respondHookScript(ctx, http.StatusOK, script)
+}
+
+func handleHookScriptError(ctx *context.APIContext, name string, err error) {
+	switch {
+	case models.IsErrRepoHookScriptAlreadyExist(err):
+		ctx.Error(http.StatusConflict, "", err)
+	case models.IsErrRepoHookScriptInvalid(err):
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	case models.IsErrRepoHookScriptNotExist(err):
+		ctx.NotFound()
+	default:
+		ctx.Error(http.StatusInternalServerError, name, err)
+	}
+}
+
+// DeleteHookScript delete a hook script of a repository
+func DeleteHookScript(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/hooks/scripts/{id} repository repoDeleteHookScript
+	// ---
+	// summary: Delete a hook script
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook script to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteRepoHookScript(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		handleHookScriptError(ctx, "DeleteRepoHookScript", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// GetInteractionLimit returns the active interaction limit of a repository
+func GetInteractionLimit(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/interaction-limits repository repoGetInteractionLimit
+	// ---
+	// summary: Get the interaction limit of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoInteractionLimit"
+
+	limit, err := models.GetRepoInteractionLimit(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoInteractionLimit", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoInteractionLimit(limit))
+}
+
+// SetInteractionLimit limits the interactions with a repository temporarily
+func SetInteractionLimit(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/interaction-limits repository repoSetInteractionLimit
+	// ---
+	// summary: Temporarily limit who may open issues and pull requests and comment in a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetRepoInteractionLimitOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoInteractionLimit"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetRepoInteractionLimitOption)
+	limitType, ok := models.InteractionLimitTypeFromString(form.Limit)
+	if !ok || limitType == models.InteractionLimitNone {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid limit")
+		return
+	}
+
+	limit, err := models.SetRepoInteractionLimit(ctx.Repo.Repository, ctx.User, limitType, time.Duration(form.DurationHours)*time.Hour)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetRepoInteractionLimit", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoInteractionLimit(limit))
+}
+
+// DeleteInteractionLimit lifts the interaction limit of a repository
+func DeleteInteractionLimit(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/interaction-limits repository repoDeleteInteractionLimit
+	// ---
+	// summary: Lift the interaction limit of a repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+
+	if err := models.DeleteRepoInteractionLimit(ctx.Repo.Repository.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRepoInteractionLimit", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
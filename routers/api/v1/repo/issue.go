@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/issueform"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -55,6 +56,11 @@ func SearchIssues(ctx *context.APIContext) {
 	//   description: repository to prioritize in the results
 	//   type: integer
 	//   format: int64
+	// - name: sort
+	//   in: query
+	//   description: sort order of the results, overrides prioritizing the repository given by priority_repo_id
+	//   type: string
+	//   enum: [newest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, priority, nearduedate, farduedate]
 	// - name: type
 	//   in: query
 	//   description: filter by type (issues / pulls) if set
@@ -95,23 +101,50 @@ func SearchIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: filter by team (requires organization owner parameter to be provided)
 	//   type: string
+	// - name: custom_field
+	//   in: query
+	//   description: Only show items having the value of a custom field, given as name=value. Values are compared as stored, i.e. numbers without trailing zeros and dates as YYYY-MM-DD
+	//   type: array
+	//   items:
+	//     type: string
+	//   collectionFormat: multi
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
 	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: cursor of the page to return, taken from the next link of the previous page. An empty cursor returns the first page paginated by cursor, without counting the issues. Only the newest, oldest, recentupdate and leastupdate sort orders support cursors
+	//   type: string
 	// - name: limit
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the issues to return, e.g. number,title,user.login. All fields are returned if not given
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/IssueList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	before, since, err := utils.GetQueryBeforeSince(ctx)
 	if err != nil {
 		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
 		return
 	}
+	after, err := utils.GetCursor(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetCursor", err)
+		return
+	}
+	customFields, err := utils.GetCustomFieldFilters(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetCustomFieldFilters", err)
+		return
+	}
 
 	var isClosed util.OptionalBool
 	switch ctx.FormString("state") {
@@ -240,6 +273,10 @@ func SearchIssues(ctx *context.APIContext) {
 			IsPull:             isPull,
 			UpdatedBeforeUnix:  before,
 			UpdatedAfterUnix:   since,
+			CustomFields:       customFields,
+		}
+		if sortType := ctx.FormString("sort"); sortType != "" {
+			issuesOpt.SortType = sortType
 		}
 
 		// Filter for: Created by User, Assigned to User, Mentioning User, Review of User Requested
@@ -256,6 +293,11 @@ func SearchIssues(ctx *context.APIContext) {
 			issuesOpt.ReviewRequestedID = ctx.User.ID
 		}
 
+		if utils.IsCursorPagination(ctx) {
+			listIssuesByCursor(ctx, issuesOpt, after)
+			return
+		}
+
 		if issues, err = models.Issues(issuesOpt); err != nil {
 			ctx.Error(http.StatusInternalServerError, "Issues", err)
 			return
@@ -270,9 +312,20 @@ func SearchIssues(ctx *context.APIContext) {
 		}
 	}
 
+	if utils.IsCursorPagination(ctx) {
+		// the search did not return any issue
+		ctx.JSONWithFields(http.StatusOK, convert.ToAPIIssueList(issues))
+		return
+	}
+
+	apiIssues := utils.ToAPIIssueList(ctx, issues)
+	if ctx.Written() {
+		return
+	}
+
 	ctx.SetLinkHeader(int(filteredCount), setting.UI.IssuePagingNum)
 	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+	ctx.JSONWithFields(http.StatusOK, apiIssues)
 }
 
 // ListIssues list the issues of a repository
@@ -339,22 +392,59 @@ func ListIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: Only show items in which the given user was mentioned
 	//   type: string
+	// - name: workflow_state
+	//   in: query
+	//   description: Only show items in the workflow state with the given id, -1 for items in no workflow state
+	//   type: integer
+	//   format: int64
+	// - name: custom_field
+	//   in: query
+	//   description: Only show items having the value of a custom field, given as name=value. Values are compared as stored, i.e. numbers without trailing zeros and dates as YYYY-MM-DD
+	//   type: array
+	//   items:
+	//     type: string
+	//   collectionFormat: multi
+	// - name: sort
+	//   in: query
+	//   description: sort order of the results, newest first if not given
+	//   type: string
+	//   enum: [newest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, priority, nearduedate, farduedate]
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
 	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: cursor of the page to return, taken from the next link of the previous page. An empty cursor returns the first page paginated by cursor, without counting the issues. Only the newest, oldest, recentupdate and leastupdate sort orders support cursors
+	//   type: string
 	// - name: limit
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the issues to return, e.g. number,title,user.login. All fields are returned if not given
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/IssueList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	before, since, err := utils.GetQueryBeforeSince(ctx)
 	if err != nil {
 		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
 		return
 	}
+	after, err := utils.GetCursor(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetCursor", err)
+		return
+	}
+	customFields, err := utils.GetCustomFieldFilters(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetCustomFieldFilters", err)
+		return
+	}
 
 	var isClosed util.OptionalBool
 	switch ctx.FormString("state") {
@@ -397,6 +487,9 @@ func ListIssues(ctx *context.APIContext) {
 			// uses names and fall back to ids
 			// non existent milestones are discarded
 			mile, err := models.GetMilestoneByRepoIDANDName(ctx.Repo.Repository.ID, part[i])
+			if models.IsErrMilestoneNotExist(err) {
+				mile, err = models.GetMilestoneByOwnerIDAndName(ctx.Repo.Repository.OwnerID, part[i])
+			}
 			if err == nil {
 				mileIDs = append(mileIDs, mile.ID)
 				continue
@@ -409,7 +502,7 @@ func ListIssues(ctx *context.APIContext) {
 			if err != nil {
 				continue
 			}
-			mile, err = models.GetMilestoneByRepoID(ctx.Repo.Repository.ID, id)
+			mile, err = ctx.Repo.Repository.GetMilestoneByID(id)
 			if err == nil {
 				mileIDs = append(mileIDs, mile.ID)
 				continue
@@ -417,7 +510,7 @@ func ListIssues(ctx *context.APIContext) {
 			if models.IsErrMilestoneNotExist(err) {
 				continue
 			}
-			ctx.Error(http.StatusInternalServerError, "GetMilestoneByRepoID", err)
+			ctx.Error(http.StatusInternalServerError, "GetMilestoneByID", err)
 		}
 	}
 
@@ -463,6 +556,14 @@ func ListIssues(ctx *context.APIContext) {
 			PosterID:          createdByID,
 			AssigneeID:        assignedByID,
 			MentionedID:       mentionedByID,
+			WorkflowStateID:   ctx.FormInt64("workflow_state"),
+			CustomFields:      customFields,
+			SortType:          ctx.FormString("sort"),
+		}
+
+		if utils.IsCursorPagination(ctx) {
+			listIssuesByCursor(ctx, issuesOpt, after)
+			return
 		}
 
 		if issues, err = models.Issues(issuesOpt); err != nil {
@@ -479,9 +580,44 @@ func ListIssues(ctx *context.APIContext) {
 		}
 	}
 
+	if utils.IsCursorPagination(ctx) {
+		// the search did not return any issue
+		ctx.JSONWithFields(http.StatusOK, convert.ToAPIIssueList(issues))
+		return
+	}
+
+	apiIssues := utils.ToAPIIssueList(ctx, issues)
+	if ctx.Written() {
+		return
+	}
+
 	ctx.SetLinkHeader(int(filteredCount), listOptions.PageSize)
 	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+	ctx.JSONWithFields(http.StatusOK, apiIssues)
+}
+
+// listIssuesByCursor responds with the page of issues after the cursor, the issues are not counted
+func listIssuesByCursor(ctx *context.APIContext, opts *models.IssuesOptions, after *models.Cursor) {
+	if !opts.SupportsCursor() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the sort order does not support cursor pagination")
+		return
+	}
+	opts.Page = 0
+	opts.After = after
+
+	issues, err := models.Issues(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Issues", err)
+		return
+	}
+	apiIssues := utils.ToAPIIssueList(ctx, issues)
+	if ctx.Written() {
+		return
+	}
+	if len(issues) > 0 && len(issues) == opts.PageSize {
+		ctx.SetCursorLinkHeader(utils.EncodeCursor(opts.CursorOf(issues[len(issues)-1])))
+	}
+	ctx.JSONWithFields(http.StatusOK, apiIssues)
 }
 
 func getUserIDForFilter(ctx *context.APIContext, queryName string) int64 {
@@ -528,6 +664,10 @@ func GetIssue(ctx *context.APIContext) {
 	//   type: integer
 	//   format: int64
 	//   required: true
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the issue to return, e.g. number,title,user.login. All fields are returned if not given
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Issue"
@@ -543,7 +683,11 @@ func GetIssue(ctx *context.APIContext) {
 		}
 		return
 	}
-	ctx.JSON(http.StatusOK, convert.ToAPIIssue(issue))
+	apiIssues := utils.ToAPIIssueList(ctx, models.IssueList{issue})
+	if ctx.Written() {
+		return
+	}
+	ctx.JSONWithFields(http.StatusOK, apiIssues[0])
 }
 
 // CreateIssue create an issue of a repository
@@ -551,6 +695,7 @@ func CreateIssue(ctx *context.APIContext) {
 	// swagger:operation POST /repos/{owner}/{repo}/issues issue issueCreateIssue
 	// ---
 	// summary: Create an issue. If using deadline only the date will be taken into account, and time of day ignored.
+	// description: If an issue form is given as template, the body is rendered from the values of its fields.
 	// consumes:
 	// - application/json
 	// produces:
@@ -600,6 +745,17 @@ func CreateIssue(ctx *context.APIContext) {
 	var err error
 	if ctx.Repo.CanWrite(models.UnitTypeIssues) {
 		issue.MilestoneID = form.Milestone
+		if form.Priority != 0 {
+			if _, err = models.GetIssuePriorityByLevel(ctx.Repo.Repository.ID, form.Priority); err != nil {
+				if models.IsErrIssuePriorityNotExist(err) {
+					ctx.Error(http.StatusUnprocessableEntity, "", err)
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetIssuePriorityByLevel", err)
+				}
+				return
+			}
+			issue.Priority = form.Priority
+		}
 		assigneeIDs, err = models.MakeIDsFromAPIAssigneesToAdd(form.Assignee, form.Assignees)
 		if err != nil {
 			if models.IsErrUserNotExist(err) {
@@ -633,10 +789,26 @@ func CreateIssue(ctx *context.APIContext) {
 		form.Labels = make([]int64, 0)
 	}
 
-	if err := issue_service.NewIssue(ctx.Repo.Repository, issue, form.Labels, nil, assigneeIDs); err != nil {
+	if form.Template != "" {
+		issueForm := ctx.IssueFormFromDefaultBranch(form.Template)
+		if issueForm == nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("Issue form does not exist: [template: %s]", form.Template))
+			return
+		}
+		err = issue_service.NewIssueFromForm(ctx.Repo.Repository, issue, form.Labels, nil, assigneeIDs, issueForm, form.Fields)
+	} else {
+		err = issue_service.NewIssue(ctx.Repo.Repository, issue, form.Labels, nil, assigneeIDs)
+	}
+	if err != nil {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
 			return
+		} else if models.IsErrRepoInteractionLimited(err) {
+			ctx.Error(http.StatusForbidden, "NewIssue", err)
+			return
+		} else if issueform.IsErrInvalidValue(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
 		}
 		ctx.Error(http.StatusInternalServerError, "NewIssue", err)
 		return
@@ -662,6 +834,62 @@ func CreateIssue(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(issue))
 }
 
+// GetIssueForm get the values of the issue form an issue was created with
+func GetIssueForm(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/form issue issueGetIssueForm
+	// ---
+	// summary: Get the values of the issue form an issue was created with
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFormValues"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	issueForm, err := models.GetIssueForm(issue.ID)
+	if err != nil {
+		if models.IsErrIssueFormNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueForm", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.IssueFormValues{
+		Template: issueForm.Template,
+		Fields:   issueForm.Values,
+	})
+}
+
 // EditIssue modify an issue of a repository
 func EditIssue(ctx *context.APIContext) {
 	// swagger:operation PATCH /repos/{owner}/{repo}/issues/{index} issue issueEditIssue
@@ -701,6 +929,8 @@ func EditIssue(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	//   "412":
 	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.EditIssueOption)
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
@@ -788,6 +1018,39 @@ func EditIssue(ctx *context.APIContext) {
 			return
 		}
 	}
+	if canWrite && form.WorkflowState != nil &&
+		issue.WorkflowStateID != *form.WorkflowState {
+		if err = models.ChangeIssueWorkflowState(issue, ctx.User, *form.WorkflowState); err != nil {
+			if models.IsErrIssueWorkflowStateNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "ChangeIssueWorkflowState", err)
+			return
+		}
+	}
+	if canWrite && form.Priority != nil &&
+		issue.Priority != *form.Priority {
+		if err = models.ChangeIssuePriority(issue, *form.Priority); err != nil {
+			if models.IsErrIssuePriorityNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "ChangeIssuePriority", err)
+			return
+		}
+	}
+	if canWrite && form.Iteration != nil &&
+		issue.IterationID != *form.Iteration {
+		if err = models.ChangeIssueIteration(issue, *form.Iteration); err != nil {
+			if models.IsErrIterationNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "ChangeIssueIteration", err)
+			return
+		}
+	}
 	if form.State != nil {
 		issue.IsClosed = api.StateClosed == api.StateType(*form.State)
 	}
@@ -0,0 +1,191 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ListIssueBranches list the branches created to work on an issue
+func ListIssueBranches(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/branches issue issueListIssueBranches
+	// ---
+	// summary: List the branches created to work on an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueBranchList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	branches, err := models.GetIssueBranches(issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueBranches", err)
+		return
+	}
+
+	apiBranches := make([]*api.IssueBranch, 0, len(branches))
+	for _, branch := range branches {
+		apiBranch := &api.IssueBranch{
+			Name:    branch.BranchName,
+			Created: branch.CreatedUnix.AsTime(),
+		}
+		if branch.PullID != 0 {
+			pr, err := models.GetPullRequestByID(branch.PullID)
+			if err != nil && !models.IsErrPullRequestNotExist(err) {
+				ctx.Error(http.StatusInternalServerError, "GetPullRequestByID", err)
+				return
+			}
+			if pr != nil {
+				apiBranch.PullRequestIndex = pr.Index
+			}
+		}
+		apiBranches = append(apiBranches, apiBranch)
+	}
+	ctx.JSON(http.StatusOK, apiBranches)
+}
+
+// CreateIssueBranch create a branch to work on an issue
+func CreateIssueBranch(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/create-branch issue issueCreateIssueBranch
+	// ---
+	// summary: Create a branch to work on an issue, the pull request opened from it is linked to the issue
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueBranchOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Branch"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: The branch with the same name already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueBranchOption)
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	if issue.IsPull {
+		ctx.Error(http.StatusUnprocessableEntity, "", "a pull request has a branch already")
+		return
+	}
+
+	branchName, err := issue_service.CreateIssueBranch(ctx.User, issue, form.OldBranchName, form.BranchName)
+	if err != nil {
+		switch {
+		case models.IsErrBranchDoesNotExist(err):
+			ctx.Error(http.StatusNotFound, "", "The old branch does not exist")
+		case issue_service.IsErrInvalidIssueBranchName(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		case models.IsErrTagAlreadyExists(err):
+			ctx.Error(http.StatusConflict, "", "The branch with the same tag already exists.")
+		case models.IsErrBranchAlreadyExists(err), git.IsErrPushOutOfDate(err):
+			ctx.Error(http.StatusConflict, "", "The branch already exists.")
+		case models.IsErrBranchNameConflict(err):
+			ctx.Error(http.StatusConflict, "", "The branch with the same name already exists.")
+		case git.IsErrPushRejected(err):
+			ctx.Error(http.StatusForbidden, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CreateIssueBranch", err)
+		}
+		return
+	}
+
+	branch, err := repo_module.GetBranch(ctx.Repo.Repository, branchName)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranch", err)
+		return
+	}
+	commit, err := branch.GetCommit()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return
+	}
+	branchProtection, err := ctx.Repo.Repository.GetBranchProtection(branch.Name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranchProtection", err)
+		return
+	}
+	br, err := convert.ToBranch(ctx.Repo.Repository, branch, commit, branchProtection, ctx.User, ctx.Repo.IsAdmin())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "convert.ToBranch", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, br)
+}
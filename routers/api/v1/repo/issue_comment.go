@@ -243,6 +243,10 @@ func CreateIssueComment(ctx *context.APIContext) {
 
 	comment, err := comment_service.CreateIssueComment(ctx.User, ctx.Repo.Repository, issue, form.Body, nil)
 	if err != nil {
+		if models.IsErrRepoInteractionLimited(err) {
+			ctx.Error(http.StatusForbidden, "CreateIssueComment", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "CreateIssueComment", err)
 		return
 	}
@@ -0,0 +1,284 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListIssueCustomFields list the custom fields of the issues of a repository
+func ListIssueCustomFields(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_custom_fields issue repoListIssueCustomFields
+	// ---
+	// summary: List the custom fields of the issues of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueCustomFieldList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.ListIssueCustomFields(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// CreateIssueCustomField create a custom field of the issues of a repository
+func CreateIssueCustomField(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issue_custom_fields issue repoCreateIssueCustomField
+	// ---
+	// summary: Create a custom field of the issues of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueCustomFieldOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueCustomField"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: A custom field with the same name already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueCustomFieldOption)
+	utils.CreateIssueCustomField(ctx, 0, ctx.Repo.Repository.ID, form)
+}
+
+// EditIssueCustomField edit a custom field of the issues of a repository
+func EditIssueCustomField(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/issue_custom_fields/{id} issue repoEditIssueCustomField
+	// ---
+	// summary: Edit a custom field of the issues of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the custom field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueCustomFieldOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueCustomField"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: A custom field with the same name already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIssueCustomFieldOption)
+	utils.EditIssueCustomField(ctx, 0, ctx.Repo.Repository.ID, form)
+}
+
+// DeleteIssueCustomField delete a custom field of the issues of a repository
+func DeleteIssueCustomField(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issue_custom_fields/{id} issue repoDeleteIssueCustomField
+	// ---
+	// summary: Delete a custom field of the issues of a repository with the values of the issues
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the custom field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteIssueCustomField(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// GetIssueCustomFieldValues list the values of the custom fields of an issue
+func GetIssueCustomFieldValues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/custom_fields issue issueGetCustomFieldValues
+	// ---
+	// summary: List the values of the custom fields of an issue, the fields without value are omitted
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueCustomFieldValueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	issue.Repo = ctx.Repo.Repository
+
+	writeIssueCustomFieldValues(ctx, issue)
+}
+
+// SetIssueCustomFieldValues set the values of custom fields of an issue
+func SetIssueCustomFieldValues(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/{index}/custom_fields issue issueSetCustomFieldValues
+	// ---
+	// summary: Set the values of custom fields of an issue, the fields not given are left unchanged
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetIssueCustomFieldValuesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueCustomFieldValueList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetIssueCustomFieldValuesOption)
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	issue.Repo = ctx.Repo.Repository
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "Not repo writer")
+		return
+	}
+
+	if err := models.SetIssueCustomFieldValues(issue, form.Values); err != nil {
+		if models.IsErrIssueCustomFieldNotExist(err) || models.IsErrInvalidIssueCustomFieldValue(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetIssueCustomFieldValues", err)
+		}
+		return
+	}
+
+	writeIssueCustomFieldValues(ctx, issue)
+}
+
+func writeIssueCustomFieldValues(ctx *context.APIContext, issue *models.Issue) {
+	entries, err := models.GetIssueCustomFieldEntries(issue)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueCustomFieldEntries", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueCustomFieldValues(entries))
+}
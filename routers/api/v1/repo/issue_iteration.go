@@ -0,0 +1,221 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListIterationCadences list the iteration cadences of a repository
+func ListIterationCadences(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/iteration_cadences issue repoListIterationCadences
+	// ---
+	// summary: List the iteration cadences of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IterationCadenceList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.ListIterationCadences(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// CreateIterationCadence create an iteration cadence of a repository
+func CreateIterationCadence(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/iteration_cadences issue repoCreateIterationCadence
+	// ---
+	// summary: Create an iteration cadence of a repository, its first iterations are created
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIterationCadenceOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IterationCadence"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIterationCadenceOption)
+	utils.CreateIterationCadence(ctx, 0, ctx.Repo.Repository.ID, form)
+}
+
+// EditIterationCadence edit an iteration cadence of a repository
+func EditIterationCadence(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/iteration_cadences/{id} issue repoEditIterationCadence
+	// ---
+	// summary: Edit an iteration cadence of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the iteration cadence
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIterationCadenceOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IterationCadence"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIterationCadenceOption)
+	utils.EditIterationCadence(ctx, 0, ctx.Repo.Repository.ID, form)
+}
+
+// DeleteIterationCadence delete an iteration cadence of a repository
+func DeleteIterationCadence(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/iteration_cadences/{id} issue repoDeleteIterationCadence
+	// ---
+	// summary: Delete an iteration cadence of a repository with its iterations, their issues are unassigned
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the iteration cadence
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteIterationCadence(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// ListIterations list the iterations of a repository, including those of its organization
+func ListIterations(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/iterations issue repoListIterations
+	// ---
+	// summary: List the iterations of a repository, including those of its organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: only list the past, current or upcoming iterations
+	//   type: string
+	//   enum: [past, current, upcoming]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IterationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ListIterations(ctx, utils.RepoIterationOptions(ctx.Repo.Repository))
+}
+
+// GetIteration get an iteration of a repository or of its organization with the statistics of its issues
+func GetIteration(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/iterations/{id} issue repoGetIteration
+	// ---
+	// summary: Get an iteration of a repository or of its organization with the statistics of its issues
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the iteration
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Iteration"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetIteration(ctx, utils.RepoIterationOptions(ctx.Repo.Repository))
+}
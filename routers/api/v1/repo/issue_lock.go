@@ -0,0 +1,185 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// LockIssue locks the conversation of an issue
+func LockIssue(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/{index}/lock issue issueLockIssue
+	// ---
+	// summary: Lock the conversation of an issue or pull request, limiting comments to collaborators
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/LockIssueOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.LockIssueOption)
+	issue := getIssueToLock(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if issue.IsLocked {
+		ctx.Error(http.StatusConflict, "LockIssue", errors.New("issue is already locked"))
+		return
+	}
+
+	reason, ok := models.IssueLockReasonFromString(form.LockReason)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "LockIssue", errors.New("unknown lock reason"))
+		return
+	}
+
+	var until timeutil.TimeStamp
+	if form.LockedUntil != nil && !form.LockedUntil.IsZero() {
+		if !form.LockedUntil.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "LockIssue", errors.New("locked_until must be in the future"))
+			return
+		}
+		until = timeutil.TimeStamp(form.LockedUntil.Unix())
+	}
+
+	if err := models.LockIssue(&models.IssueLockOptions{
+		Doer:   ctx.User,
+		Issue:  issue,
+		Reason: lockReasonText(reason),
+		Until:  until,
+	}); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LockIssue", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnlockIssue unlocks the conversation of an issue
+func UnlockIssue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/lock issue issueUnlockIssue
+	// ---
+	// summary: Unlock the conversation of an issue or pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	issue := getIssueToLock(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !issue.IsLocked {
+		ctx.Error(http.StatusConflict, "UnlockIssue", errors.New("issue is not locked"))
+		return
+	}
+
+	if err := models.UnlockIssue(&models.IssueLockOptions{
+		Doer:  ctx.User,
+		Issue: issue,
+	}); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnlockIssue", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// getIssueToLock returns the issue of the request if the user may lock it
+func getIssueToLock(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "Not repo writer")
+		return nil
+	}
+	issue.Repo = ctx.Repo.Repository
+	return issue
+}
+
+// lockReasonText returns the configured lock reason matching reason, which is shown in the lock comment
+func lockReasonText(reason models.IssueLockReason) string {
+	if reason == models.IssueLockReasonNone {
+		return ""
+	}
+	for _, text := range setting.Repository.Issue.LockReasons {
+		if r, ok := models.IssueLockReasonFromString(text); ok && r == reason {
+			return text
+		}
+	}
+	return reason.String()
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListPinnedIssues lists the issues pinned in a repository
+func ListPinnedIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/pinned issue issueListPinnedIssues
+	// ---
+	// summary: List the issues pinned in a repository, in their order
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+
+	issues, err := models.GetPinnedIssues(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPinnedIssues", err)
+		return
+	}
+	apiIssues := utils.ToAPIIssueList(ctx, issues)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, apiIssues)
+}
+
+// SetPinnedIssues sets the issues pinned in a repository
+func SetPinnedIssues(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/pinned issue issueSetPinnedIssues
+	// ---
+	// summary: Set the issues pinned in a repository, in their order
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetPinnedIssuesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetPinnedIssuesOption)
+	issues := make([]*models.Issue, 0, len(form.Issues))
+	for _, index := range form.Issues {
+		issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, index)
+		if err != nil {
+			if models.IsErrIssueNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("issue does not exist: %d", index))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+			}
+			return
+		}
+		issues = append(issues, issue)
+	}
+
+	if err := models.SetPinnedIssues(ctx.Repo.Repository.ID, issues); err != nil {
+		if err == models.ErrTooManyPinnedIssues || err == models.ErrPinnedIssueIsPull {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetPinnedIssues", err)
+		}
+		return
+	}
+	ListPinnedIssues(ctx)
+}
@@ -0,0 +1,333 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListIssuePriorities list the priority levels of the issues of a repository
+func ListIssuePriorities(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/priorities issue issueListPriorities
+	// ---
+	// summary: Get the priority levels of a repository's issues, most urgent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssuePriorityList"
+
+	priorities, err := models.GetIssuePrioritiesByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssuePrioritiesByRepoID", err)
+		return
+	}
+
+	apiPriorities := make([]*api.IssuePriority, len(priorities))
+	for i := range priorities {
+		apiPriorities[i] = convert.ToAPIIssuePriority(priorities[i])
+	}
+	ctx.JSON(http.StatusOK, &apiPriorities)
+}
+
+// GetIssuePriority get a priority level of the issues of a repository
+func GetIssuePriority(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/priorities/{id} issue issueGetPriority
+	// ---
+	// summary: Get a priority level
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the priority level to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssuePriority"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	priority, err := models.GetIssuePriorityByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssuePriorityNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssuePriorityByRepoID", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssuePriority(priority))
+}
+
+// CreateIssuePriority create a priority level of the issues of a repository
+func CreateIssuePriority(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/priorities issue issueCreatePriority
+	// ---
+	// summary: Create a priority level
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssuePriorityOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssuePriority"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssuePriorityOption)
+	color, ok := normalizeColor(ctx, form.Color)
+	if !ok {
+		return
+	}
+
+	priority := &models.IssuePriority{
+		RepoID:      ctx.Repo.Repository.ID,
+		Level:       form.Level,
+		Name:        form.Name,
+		Description: form.Description,
+		Color:       color,
+	}
+	if err := models.NewIssuePriority(priority); err != nil {
+		if models.IsErrIssuePriorityAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else if models.IsErrIssuePriorityInvalidLevel(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewIssuePriority", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssuePriority(priority))
+}
+
+// EditIssuePriority modify a priority level of the issues of a repository
+func EditIssuePriority(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/priorities/{id} issue issueEditPriority
+	// ---
+	// summary: Update a priority level, the issues of the priority keep it when its level is changed
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the priority level to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssuePriorityOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssuePriority"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIssuePriorityOption)
+	priority, err := models.GetIssuePriorityByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssuePriorityNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssuePriorityByRepoID", err)
+		}
+		return
+	}
+
+	if form.Level != nil {
+		priority.Level = *form.Level
+	}
+	if form.Name != nil {
+		if strings.TrimSpace(*form.Name) == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "name must not be empty")
+			return
+		}
+		priority.Name = *form.Name
+	}
+	if form.Color != nil {
+		color, ok := normalizeColor(ctx, *form.Color)
+		if !ok {
+			return
+		}
+		priority.Color = color
+	}
+	if form.Description != nil {
+		priority.Description = *form.Description
+	}
+	if err := models.UpdateIssuePriority(priority); err != nil {
+		if models.IsErrIssuePriorityAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else if models.IsErrIssuePriorityInvalidLevel(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssuePriority", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssuePriority(priority))
+}
+
+// DeleteIssuePriority delete a priority level of the issues of a repository
+func DeleteIssuePriority(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/priorities/{id} issue issueDeletePriority
+	// ---
+	// summary: Delete a priority level, the issues of the priority are left without one
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the priority level to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteIssuePriority(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrIssuePriorityNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteIssuePriority", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetIssueSLAReport report the time-to-first-response of the issues of a repository per priority level
+func GetIssueSLAReport(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/sla issue issueGetSLAReport
+	// ---
+	// summary: Get the time-to-first-response of a repository's issues per priority level, most urgent first
+	// description: The first response to an issue is the first comment or review by someone other than its poster. The last entry reports the issues without priority.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Only report issues created after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only report issues created before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAReportList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	before, since, err := utils.GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	reports, err := models.GetIssueSLAReports(ctx.Repo.Repository.ID, since, before)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueSLAReports", err)
+		return
+	}
+
+	apiReports := make([]*api.IssueSLAReport, len(reports))
+	for i := range reports {
+		apiReports[i] = convert.ToAPIIssueSLAReport(reports[i])
+	}
+	ctx.JSON(http.StatusOK, &apiReports)
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// MarkIssueRead marks the comments of an issue as read by the authenticated user
+func MarkIssueRead(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/{index}/read issue issueMarkRead
+	// ---
+	// summary: Mark the comments of an issue or pull request as read by the authenticated user
+	// description: The issues and pull requests listed afterwards count the comments after the read ones as unread_count.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: comment_id
+	//   in: query
+	//   description: id of the last comment read, so that comments posted since they were fetched stay unread. All comments are read if not given
+	//   type: integer
+	//   format: int64
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	commentID := ctx.FormInt64("comment_id")
+	if commentID == 0 {
+		if err := issue.ReadBy(ctx.User.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ReadBy", err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	comment, err := models.GetCommentByID(commentID)
+	if err != nil && !models.IsErrCommentNotExist(err) {
+		ctx.Error(http.StatusInternalServerError, "GetCommentByID", err)
+		return
+	} else if err != nil || comment.IssueID != issue.ID {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the comment is not one of the issue")
+		return
+	}
+	if err := models.SetIssueReadMarker(ctx.User.ID, issue, comment.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetIssueReadMarker", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
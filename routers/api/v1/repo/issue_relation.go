@@ -0,0 +1,265 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ListIssueRelations list the relations of an issue to other issues
+func ListIssueRelations(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/relations issue issueListIssueRelations
+	// ---
+	// summary: List the relations of an issue to other issues
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueRelationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	relations, err := models.GetIssueRelations(issue)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueRelations", err)
+		return
+	}
+
+	relations, err = issue_service.FilterReadableIssueRelations(ctx.User, issue.RepoID, relations)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FilterReadableIssueRelations", err)
+		return
+	}
+
+	apiRelations := make([]*api.IssueRelation, 0, len(relations))
+	for _, relation := range relations {
+		apiRelations = append(apiRelations, convert.ToAPIIssueRelation(relation))
+	}
+	ctx.JSON(http.StatusOK, apiRelations)
+}
+
+// CreateIssueRelation relate an issue to another issue
+func CreateIssueRelation(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/relations issue issueCreateIssueRelation
+	// ---
+	// summary: Relate an issue to another issue, an open issue marked as a duplicate is closed
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueRelationOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueRelation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: The relation already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueRelationOption)
+	issue := getIssueToRelate(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	relatedRepo := ctx.Repo.Repository
+	if form.Owner != "" || form.Repo != "" {
+		owner, name := form.Owner, form.Repo
+		if owner == "" {
+			owner = ctx.Repo.Repository.OwnerName
+		}
+		if name == "" {
+			name = ctx.Repo.Repository.Name
+		}
+		repo, err := models.GetRepositoryByOwnerAndName(owner, name)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", errors.New("the repository of the related issue does not exist"))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return
+		}
+		relatedRepo = repo
+	}
+
+	related, err := models.GetIssueByIndex(relatedRepo.ID, form.Index)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("the related issue does not exist"))
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	if relatedRepo.ID != ctx.Repo.Repository.ID {
+		perm, err := models.GetUserRepoPermission(relatedRepo, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		if !perm.CanReadIssuesOrPulls(related.IsPull) {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("the related issue does not exist"))
+			return
+		}
+	}
+	related.Repo = relatedRepo
+
+	typ, _ := models.IssueRelationTypeFromString(form.Type)
+	relation, err := issue_service.CreateIssueRelation(ctx.User, issue, related, typ)
+	if err != nil {
+		switch {
+		case models.IsErrIssueRelationExists(err):
+			ctx.Error(http.StatusConflict, "", err)
+		case models.IsErrInvalidIssueRelation(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CreateIssueRelation", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueRelation(&models.IssueRelationInfo{
+		IssueRelation: relation,
+		Issue:         related,
+	}))
+}
+
+// DeleteIssueRelation delete a relation of an issue
+func DeleteIssueRelation(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/relations/{id} issue issueDeleteIssueRelation
+	// ---
+	// summary: Delete a relation of an issue
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the relation
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueToRelate(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	relation, err := models.GetIssueRelationByID(issue.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueRelationNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueRelationByID", err)
+		}
+		return
+	}
+
+	if err := models.DeleteIssueRelation(relation); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueRelation", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// getIssueToRelate returns the issue of the request if the user may edit its relations
+func getIssueToRelate(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "Not repo writer")
+		return nil
+	}
+	issue.Repo = ctx.Repo.Repository
+	return issue
+}
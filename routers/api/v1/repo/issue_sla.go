@@ -0,0 +1,309 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListIssueSLAPolicies list the SLA policies of the issues of a repository
+func ListIssueSLAPolicies(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/sla_policies issue issueListSLAPolicies
+	// ---
+	// summary: Get the SLA policies of a repository's issues
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAPolicyList"
+
+	policies, err := models.GetIssueSLAPoliciesByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueSLAPoliciesByRepoID", err)
+		return
+	}
+
+	apiPolicies := make([]*api.IssueSLAPolicy, len(policies))
+	for i := range policies {
+		apiPolicies[i] = convert.ToAPIIssueSLAPolicy(policies[i])
+	}
+	ctx.JSON(http.StatusOK, &apiPolicies)
+}
+
+// GetIssueSLAPolicy get a SLA policy of the issues of a repository
+func GetIssueSLAPolicy(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/sla_policies/{id} issue issueGetSLAPolicy
+	// ---
+	// summary: Get a SLA policy
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the SLA policy to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAPolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy := getIssueSLAPolicy(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueSLAPolicy(policy))
+}
+
+// CreateIssueSLAPolicy create a SLA policy of the issues of a repository
+func CreateIssueSLAPolicy(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/sla_policies issue issueCreateSLAPolicy
+	// ---
+	// summary: Create a SLA policy, the open issues breaching it are flagged by a background job
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueSLAPolicyOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueSLAPolicy"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueSLAPolicyOption)
+	policy := &models.IssueSLAPolicy{
+		RepoID:             ctx.Repo.Repository.ID,
+		Name:               form.Name,
+		LabelID:            form.LabelID,
+		FirstResponseHours: form.FirstResponseHours,
+	}
+	if err := models.NewIssueSLAPolicy(policy); err != nil {
+		if models.IsErrInvalidIssueSLAPolicy(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewIssueSLAPolicy", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueSLAPolicy(policy))
+}
+
+// EditIssueSLAPolicy modify a SLA policy of the issues of a repository
+func EditIssueSLAPolicy(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/sla_policies/{id} issue issueEditSLAPolicy
+	// ---
+	// summary: Update a SLA policy, the issues already flagged stay flagged
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the SLA policy to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueSLAPolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAPolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIssueSLAPolicyOption)
+	policy := getIssueSLAPolicy(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		policy.Name = *form.Name
+	}
+	if form.LabelID != nil {
+		policy.LabelID = *form.LabelID
+	}
+	if form.FirstResponseHours != nil {
+		policy.FirstResponseHours = *form.FirstResponseHours
+	}
+	if err := models.UpdateIssueSLAPolicy(policy); err != nil {
+		if models.IsErrInvalidIssueSLAPolicy(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssueSLAPolicy", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueSLAPolicy(policy))
+}
+
+// DeleteIssueSLAPolicy delete a SLA policy of the issues of a repository
+func DeleteIssueSLAPolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/sla_policies/{id} issue issueDeleteSLAPolicy
+	// ---
+	// summary: Delete a SLA policy
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the SLA policy to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy := getIssueSLAPolicy(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeleteIssueSLAPolicy(policy); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueSLAPolicy", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func getIssueSLAPolicy(ctx *context.APIContext) *models.IssueSLAPolicy {
+	policy, err := models.GetIssueSLAPolicyByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueSLAPolicyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueSLAPolicyByRepoID", err)
+		}
+		return nil
+	}
+	return policy
+}
+
+// GetIssueSLACompliance report the compliance of the issues of a repository with its SLA policies
+func GetIssueSLACompliance(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/sla/compliance issue issueGetSLACompliance
+	// ---
+	// summary: Get the compliance of a repository's issues with each of its SLA policies
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Only report issues created after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only report issues created before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAComplianceList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	before, since, err := utils.GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	compliances, err := models.GetIssueSLACompliances(ctx.Repo.Repository.ID, since, before, time.Now().Unix())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueSLACompliances", err)
+		return
+	}
+
+	apiCompliances := make([]*api.IssueSLACompliance, len(compliances))
+	for i := range compliances {
+		apiCompliances[i] = convert.ToAPIIssueSLACompliance(compliances[i])
+	}
+	ctx.JSON(http.StatusOK, &apiCompliances)
+}
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
@@ -195,19 +196,117 @@ func CheckIssueSubscription(ctx *context.APIContext) {
 		return
 	}
 
-	watching, err := models.CheckIssueWatch(ctx.User, issue)
+	writeIssueWatchInfo(ctx, ctx.User, issue)
+}
+
+// EditIssueSubscription subscribe user to issue with the given subscription level
+func EditIssueSubscription(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/issues/{index}/subscriptions/{user} issue issueEditSubscription
+	// ---
+	// summary: Subscribe user to issue with the given subscription level
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: user
+	//   in: path
+	//   description: user to subscribe
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditSubscriptionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WatchInfo"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	subscription, ok := utils.ToSubscription(ctx, web.GetForm(ctx).(*api.EditSubscriptionOption))
+	if !ok {
+		return
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	user, err := models.GetUserByName(ctx.Params(":user"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+
+	//only admin and user for itself can change subscription
+	if user.ID != ctx.User.ID && !ctx.User.IsAdmin {
+		ctx.Error(http.StatusForbidden, "User", nil)
+		return
+	}
+
+	if err := models.UpdateIssueWatchSubscription(user.ID, issue.ID, subscription); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateIssueWatchSubscription", err)
+		return
+	}
+	writeIssueWatchInfo(ctx, user, issue)
+}
+
+// writeIssueWatchInfo writes whether the user is subscribed to the issue and the level of the subscription
+func writeIssueWatchInfo(ctx *context.APIContext, user *models.User, issue *models.Issue) {
+	watching, err := models.CheckIssueWatch(user, issue)
 	if err != nil {
 		ctx.InternalServerError(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, api.WatchInfo{
+	info := api.WatchInfo{
 		Subscribed:    watching,
 		Ignored:       !watching,
 		Reason:        nil,
 		CreatedAt:     issue.CreatedUnix.AsTime(),
 		URL:           issue.APIURL() + "/subscriptions",
 		RepositoryURL: ctx.Repo.Repository.APIURL(),
-	})
+	}
+	if watching {
+		subscription, err := models.GetIssueSubscription(user.ID, issue)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetIssueSubscription", err)
+			return
+		}
+		info.Level = subscription.Level.String()
+		info.Events = subscription.Events.Names()
+	}
+	ctx.JSON(http.StatusOK, info)
 }
 
 // GetIssueSubscribers return subscribers of an issue
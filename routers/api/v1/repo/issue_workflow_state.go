@@ -0,0 +1,291 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListIssueWorkflowStates list the workflow states of the issues of a repository
+func ListIssueWorkflowStates(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/workflow_states issue issueListWorkflowStates
+	// ---
+	// summary: Get the workflow states of a repository's issues in their order
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueWorkflowStateList"
+
+	states, err := models.GetIssueWorkflowStatesByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueWorkflowStatesByRepoID", err)
+		return
+	}
+
+	apiStates := make([]*api.IssueWorkflowState, len(states))
+	for i := range states {
+		apiStates[i] = convert.ToAPIIssueWorkflowState(states[i])
+	}
+	ctx.JSON(http.StatusOK, &apiStates)
+}
+
+// GetIssueWorkflowState get a workflow state of the issues of a repository
+func GetIssueWorkflowState(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/workflow_states/{id} issue issueGetWorkflowState
+	// ---
+	// summary: Get a workflow state
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the workflow state to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueWorkflowState"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	state, err := models.GetIssueWorkflowStateByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueWorkflowStateNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueWorkflowStateByRepoID", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueWorkflowState(state))
+}
+
+// normalizeColor returns a color given to the API as it is stored, or writes an error to ctx
+func normalizeColor(ctx *context.APIContext, color string) (string, bool) {
+	color = strings.Trim(color, " ")
+	if color == "" {
+		return "", true
+	}
+	if len(color) == 6 {
+		color = "#" + color
+	}
+	if !models.LabelColorPattern.MatchString(color) {
+		ctx.Error(http.StatusUnprocessableEntity, "ColorPattern", fmt.Errorf("bad color code: %s", color))
+		return "", false
+	}
+	return color, true
+}
+
+// CreateIssueWorkflowState create a workflow state of the issues of a repository
+func CreateIssueWorkflowState(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/workflow_states issue issueCreateWorkflowState
+	// ---
+	// summary: Create a workflow state
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueWorkflowStateOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueWorkflowState"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueWorkflowStateOption)
+	color, ok := normalizeColor(ctx, form.Color)
+	if !ok {
+		return
+	}
+
+	state := &models.IssueWorkflowState{
+		RepoID:      ctx.Repo.Repository.ID,
+		Name:        form.Name,
+		Description: form.Description,
+		Color:       color,
+		Sort:        form.Sort,
+	}
+	if err := models.NewIssueWorkflowState(state); err != nil {
+		if models.IsErrIssueWorkflowStateAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewIssueWorkflowState", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueWorkflowState(state))
+}
+
+// EditIssueWorkflowState modify a workflow state of the issues of a repository
+func EditIssueWorkflowState(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/workflow_states/{id} issue issueEditWorkflowState
+	// ---
+	// summary: Update a workflow state
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the workflow state to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueWorkflowStateOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueWorkflowState"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIssueWorkflowStateOption)
+	state, err := models.GetIssueWorkflowStateByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueWorkflowStateNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueWorkflowStateByRepoID", err)
+		}
+		return
+	}
+
+	if form.Name != nil {
+		if strings.TrimSpace(*form.Name) == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "name must not be empty")
+			return
+		}
+		state.Name = *form.Name
+	}
+	if form.Color != nil {
+		color, ok := normalizeColor(ctx, *form.Color)
+		if !ok {
+			return
+		}
+		state.Color = color
+	}
+	if form.Description != nil {
+		state.Description = *form.Description
+	}
+	if form.Sort != nil {
+		state.Sort = *form.Sort
+	}
+	if err := models.UpdateIssueWorkflowState(state); err != nil {
+		if models.IsErrIssueWorkflowStateAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssueWorkflowState", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueWorkflowState(state))
+}
+
+// DeleteIssueWorkflowState delete a workflow state of the issues of a repository
+func DeleteIssueWorkflowState(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/workflow_states/{id} issue issueDeleteWorkflowState
+	// ---
+	// summary: Delete a workflow state, the issues in it are left in no workflow state
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the workflow state to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteIssueWorkflowState(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrIssueWorkflowStateNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteIssueWorkflowState", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
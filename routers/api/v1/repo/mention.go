@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListMentionables lists the users and teams the authenticated user may mention in a repository
+func ListMentionables(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/mentionable-users repository repoListMentionables
+	// ---
+	// summary: List the users and teams which may be mentioned in the issues and pull requests of a repository
+	// description: Users are ordered by their last interaction with the repository. Teams are only listed for repositories of organizations.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: beginning of the name of the user or team
+	//   type: string
+	// - name: limit
+	//   in: query
+	//   description: maximum number of users, defaults to 10
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Mentionables"
+
+	keyword := ctx.FormTrim("q")
+	limit := ctx.FormInt("limit")
+	if limit <= 0 || limit > setting.API.MaxResponseItems {
+		limit = 10
+	}
+
+	users, err := models.GetMentionableUsers(ctx.Repo.Repository, keyword, limit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMentionableUsers", err)
+		return
+	}
+	teams, err := models.GetMentionableTeams(ctx.Repo.Repository, ctx.User, keyword)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMentionableTeams", err)
+		return
+	}
+
+	apiTeams := make([]*api.Team, len(teams))
+	for i, team := range teams {
+		apiTeams[i] = convert.ToTeam(team)
+		apiTeams[i].Organization = convert.ToOrganization(ctx.Repo.Owner)
+	}
+	ctx.JSON(http.StatusOK, &api.Mentionables{
+		Users: convert.ToUsers(ctx.User, users),
+		Teams: apiTeams,
+	})
+}
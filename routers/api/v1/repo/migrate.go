@@ -51,7 +51,199 @@ func Migrate(ctx *context.APIContext) {
 	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.MigrateRepoOptions)
+	repoOwner, opts := parseMigrateForm(ctx, form)
+	if ctx.Written() {
+		return
+	}
+
+	repo, err := repo_module.CreateRepository(ctx.User, repoOwner, models.CreateRepoOptions{
+		Name:           opts.RepoName,
+		Description:    opts.Description,
+		OriginalURL:    form.CloneAddr,
+		GitServiceType: opts.GitServiceType,
+		IsPrivate:      opts.Private,
+		IsMirror:       opts.Mirror,
+		Status:         models.RepositoryBeingMigrated,
+	})
+	if err != nil {
+		handleMigrateError(ctx, repoOwner, opts.CloneAddr, err)
+		return
+	}
+
+	opts.MigrateToRepoID = repo.ID
+
+	defer func() {
+		if e := recover(); e != nil {
+			var buf bytes.Buffer
+			fmt.Fprintf(&buf, "Handler crashed with error: %v", log.Stack(2))
+
+			err = errors.New(buf.String())
+		}
+
+		if err == nil {
+			notification.NotifyMigrateRepository(ctx.User, repoOwner, repo)
+			return
+		}
+
+		if repo != nil {
+			if errDelete := models.DeleteRepository(ctx.User, repoOwner.ID, repo.ID); errDelete != nil {
+				log.Error("DeleteRepository: %v", errDelete)
+			}
+		}
+	}()
+
+	if _, err = migrations.MigrateRepository(graceful.GetManager().HammerContext(), ctx.User, repoOwner.Name, *opts, nil); err != nil {
+		handleMigrateError(ctx, repoOwner, opts.CloneAddr, err)
+		return
+	}
+
+	log.Trace("Repository migrated: %s/%s", repoOwner.Name, form.RepoName)
+	ctx.JSON(http.StatusCreated, convert.ToRepo(repo, models.AccessModeAdmin))
+}
+
+// MigrateDryRun reports the users of the data a migration would import
+func MigrateDryRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/migrate/dry-run repository repoMigrateDryRun
+	// ---
+	// summary: Report the users of the data a migration would import and the local users they would be mapped to, without migrating
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MigrateRepoOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MigrationDryRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.MigrateRepoOptions)
+	repoOwner, opts := parseMigrateForm(ctx, form)
+	if ctx.Written() {
+		return
+	}
+
+	identities, err := migrations.DryRunMigration(ctx, ctx.User, repoOwner.Name, *opts)
+	if err != nil {
+		handleMigrateError(ctx, repoOwner, opts.CloneAddr, err)
+		return
+	}
+
+	report := &api.MigrationDryRun{
+		Identities: make([]*api.MigrationIdentity, 0, len(identities)),
+	}
+	for _, identity := range identities {
+		apiIdentity := &api.MigrationIdentity{
+			ID:    identity.ID,
+			Name:  identity.Name,
+			Email: identity.Email,
+		}
+		if identity.LocalUser != nil {
+			apiIdentity.LocalUser = identity.LocalUser.Name
+		} else {
+			report.Unmapped++
+		}
+		report.Identities = append(report.Identities, apiIdentity)
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// SyncMigration imports the data which is new on the source of a migrated repository
+func SyncMigration(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/migrate/sync repository repoSyncMigration
+	// ---
+	// summary: Import the issues, comments, releases, milestones and labels which were added to the source of a migrated repository since the migration or the last sync
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo to sync
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to sync
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SyncMigrationOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
+	form := web.GetForm(ctx).(*api.SyncMigrationOptions)
+	repo := ctx.Repo.Repository
+
+	if setting.Repository.DisableMigrations {
+		ctx.Error(http.StatusForbidden, "MigrationsGlobalDisabled", fmt.Errorf("the site administrator has disabled migrations"))
+		return
+	}
+	if repo.IsMirror || repo.OriginalURL == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "The repository is not a migrated repository.")
+		return
+	}
+	// mapping by email attributes the synced data to any local user
+	if form.MapUsersByEmail && !ctx.User.IsAdmin {
+		ctx.Error(http.StatusForbidden, "", "Only site administrators can map users by email.")
+		return
+	}
+
+	remoteAddr, err := forms.ParseRemoteAddr(repo.OriginalURL, form.AuthUsername, form.AuthPassword)
+	if err != nil {
+		handleRemoteAddrError(ctx, err)
+		return
+	}
+
+	var opts = migrations.MigrateOptions{
+		CloneAddr:      remoteAddr,
+		OriginalURL:    repo.OriginalURL,
+		AuthUsername:   form.AuthUsername,
+		AuthPassword:   form.AuthPassword,
+		AuthToken:      form.AuthToken,
+		Issues:         form.Issues,
+		Milestones:     form.Milestones,
+		Labels:         form.Labels,
+		Comments:       true,
+		Releases:       form.Releases,
+		GitServiceType: repo.OriginalServiceType,
+
+		MapUsersByEmail: form.MapUsersByEmail,
+	}
+
+	if err := migrations.SyncRepository(graceful.GetManager().HammerContext(), ctx.User, repo, opts, nil); err != nil {
+		switch {
+		case err == migrations.ErrRepoNotSyncable:
+			ctx.Error(http.StatusUnprocessableEntity, "", "The repository was migrated before syncing was supported.")
+		case models.IsErrInvalidCloneAddr(err):
+			handleRemoteAddrError(ctx, err)
+		default:
+			handleMigrateError(ctx, repo.Owner, remoteAddr, err)
+		}
+		return
+	}
+
+	log.Trace("Repository synced: %s", repo.FullName())
+	ctx.JSON(http.StatusOK, convert.ToRepo(repo, ctx.Repo.AccessMode))
+}
+
+// parseMigrateForm checks the migrate options and returns the owner of the migrated repository with
+// the options of the migration
+func parseMigrateForm(ctx *context.APIContext, form *api.MigrateRepoOptions) (*models.User, *migrations.MigrateOptions) {
 	//get repoOwner
 	var (
 		repoOwner *models.User
@@ -70,18 +262,18 @@ func Migrate(ctx *context.APIContext) {
 		} else {
 			ctx.Error(http.StatusInternalServerError, "GetUser", err)
 		}
-		return
+		return nil, nil
 	}
 
 	if ctx.HasError() {
 		ctx.Error(http.StatusUnprocessableEntity, "", ctx.GetErrMsg())
-		return
+		return nil, nil
 	}
 
 	if !ctx.User.IsAdmin {
 		if !repoOwner.IsOrganization() && ctx.User.ID != repoOwner.ID {
 			ctx.Error(http.StatusForbidden, "", "Given user is not an organization.")
-			return
+			return nil, nil
 		}
 
 		if repoOwner.IsOrganization() {
@@ -89,33 +281,39 @@ func Migrate(ctx *context.APIContext) {
 			isOwner, err := repoOwner.IsOwnedBy(ctx.User.ID)
 			if err != nil {
 				ctx.Error(http.StatusInternalServerError, "IsOwnedBy", err)
-				return
+				return nil, nil
 			} else if !isOwner {
 				ctx.Error(http.StatusForbidden, "", "Given user is not owner of organization.")
-				return
+				return nil, nil
 			}
 		}
 	}
 
+	// mapping by email attributes the migrated data to any local user
+	if form.MapUsersByEmail && !ctx.User.IsAdmin {
+		ctx.Error(http.StatusForbidden, "", "Only site administrators can map users by email.")
+		return nil, nil
+	}
+
 	remoteAddr, err := forms.ParseRemoteAddr(form.CloneAddr, form.AuthUsername, form.AuthPassword)
 	if err == nil {
 		err = migrations.IsMigrateURLAllowed(remoteAddr, ctx.User)
 	}
 	if err != nil {
 		handleRemoteAddrError(ctx, err)
-		return
+		return nil, nil
 	}
 
 	gitServiceType := convert.ToGitServiceType(form.Service)
 
 	if form.Mirror && setting.Mirror.DisableNewPull {
 		ctx.Error(http.StatusForbidden, "MirrorsGlobalDisabled", fmt.Errorf("the site administrator has disabled the creation of new pull mirrors"))
-		return
+		return nil, nil
 	}
 
 	if setting.Repository.DisableMigrations {
 		ctx.Error(http.StatusForbidden, "MigrationsGlobalDisabled", fmt.Errorf("the site administrator has disabled migrations"))
-		return
+		return nil, nil
 	}
 
 	form.LFS = form.LFS && setting.LFS.StartServer
@@ -124,12 +322,12 @@ func Migrate(ctx *context.APIContext) {
 		ep := lfs.DetermineEndpoint("", form.LFSEndpoint)
 		if ep == nil {
 			ctx.Error(http.StatusInternalServerError, "", ctx.Tr("repo.migrate.invalid_lfs_endpoint"))
-			return
+			return nil, nil
 		}
 		err = migrations.IsMigrateURLAllowed(ep.String(), ctx.User)
 		if err != nil {
 			handleRemoteAddrError(ctx, err)
-			return
+			return nil, nil
 		}
 	}
 
@@ -153,6 +351,8 @@ func Migrate(ctx *context.APIContext) {
 		Releases:       form.Releases,
 		GitServiceType: gitServiceType,
 		MirrorInterval: form.MirrorInterval,
+
+		MapUsersByEmail: form.MapUsersByEmail,
 	}
 	if opts.Mirror {
 		opts.Issues = false
@@ -162,50 +362,7 @@ func Migrate(ctx *context.APIContext) {
 		opts.PullRequests = false
 		opts.Releases = false
 	}
-
-	repo, err := repo_module.CreateRepository(ctx.User, repoOwner, models.CreateRepoOptions{
-		Name:           opts.RepoName,
-		Description:    opts.Description,
-		OriginalURL:    form.CloneAddr,
-		GitServiceType: gitServiceType,
-		IsPrivate:      opts.Private,
-		IsMirror:       opts.Mirror,
-		Status:         models.RepositoryBeingMigrated,
-	})
-	if err != nil {
-		handleMigrateError(ctx, repoOwner, remoteAddr, err)
-		return
-	}
-
-	opts.MigrateToRepoID = repo.ID
-
-	defer func() {
-		if e := recover(); e != nil {
-			var buf bytes.Buffer
-			fmt.Fprintf(&buf, "Handler crashed with error: %v", log.Stack(2))
-
-			err = errors.New(buf.String())
-		}
-
-		if err == nil {
-			notification.NotifyMigrateRepository(ctx.User, repoOwner, repo)
-			return
-		}
-
-		if repo != nil {
-			if errDelete := models.DeleteRepository(ctx.User, repoOwner.ID, repo.ID); errDelete != nil {
-				log.Error("DeleteRepository: %v", errDelete)
-			}
-		}
-	}()
-
-	if _, err = migrations.MigrateRepository(graceful.GetManager().HammerContext(), ctx.User, repoOwner.Name, opts, nil); err != nil {
-		handleMigrateError(ctx, repoOwner, remoteAddr, err)
-		return
-	}
-
-	log.Trace("Repository migrated: %s/%s", repoOwner.Name, form.RepoName)
-	ctx.JSON(http.StatusCreated, convert.ToRepo(repo, models.AccessModeAdmin))
+	return repoOwner, &opts
 }
 
 func handleMigrateError(ctx *context.APIContext, repoOwner *models.User, remoteAddr string, err error) {
@@ -6,6 +6,7 @@
 package repo
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,10 +14,12 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 // ListMilestones list milestones for a repository
@@ -262,6 +265,120 @@ func DeleteMilestone(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// GetMilestoneBurndown get the burndown of a milestone of a repository
+func GetMilestoneBurndown(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/milestones/{id}/burndown issue issueGetMilestoneBurndown
+	// ---
+	// summary: Get the open and closed issues and the tracked time of a milestone at the end of every day
+	// description: The days range from the creation of the milestone to its deadline, or to today if it has
+	//   no deadline or the deadline has not passed yet. The past states of the issues are computed from their
+	//   history of milestone changes, closing and reopening.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: the milestone, identified by ID and if not available by name
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MilestoneBurndown"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	milestone := getMilestoneByIDOrName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	days, err := models.GetMilestoneBurndown(milestone, setting.DefaultUILocation)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMilestoneBurndown", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToMilestoneBurndown(days))
+}
+
+// MigrateMilestoneIssues moves the open issues of a milestone to another milestone
+func MigrateMilestoneIssues(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/milestones/{id}/migrate-issues issue issueMigrateMilestoneIssues
+	// ---
+	// summary: Move the open issues of a milestone to another milestone
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: the milestone to move the issues from, identified by ID and if not available by name
+	//   type: string
+	//   required: true
+	// - name: target
+	//   in: query
+	//   description: id of the milestone of the repository or shared by its owner to move the issues to
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Milestone"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	milestone := getMilestoneByIDOrName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	target, err := ctx.Repo.Repository.GetMilestoneByID(ctx.FormInt64("target"))
+	if err != nil {
+		if models.IsErrMilestoneNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("the target milestone does not exist"))
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetMilestoneByID", err)
+		}
+		return
+	}
+	if target.ID == milestone.ID {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("the target milestone is the milestone of the issues"))
+		return
+	}
+
+	if _, err := issue_service.MoveMilestoneIssues(ctx.User, milestone, target); err != nil {
+		ctx.Error(http.StatusInternalServerError, "MoveMilestoneIssues", err)
+		return
+	}
+
+	target, err = models.GetMilestoneByID(target.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMilestoneByID", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIMilestone(target))
+}
+
 // getMilestoneByIDOrName get milestone by ID and if not available by name
 func getMilestoneByIDOrName(ctx *context.APIContext) *models.Milestone {
 	mile := ctx.Params(":id")
@@ -289,3 +406,100 @@ func getMilestoneByIDOrName(ctx *context.APIContext) *models.Milestone {
 
 	return milestone
 }
+
+// CloneMilestone clone a milestone of a repository to the same or another repository
+func CloneMilestone(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/milestones/{id}/clone issue issueCloneMilestone
+	// ---
+	// summary: Clone a milestone to the same or another repository
+	// description: The clone has the title and description of the milestone. Its deadline is as far from the
+	//   creation of the clone as the deadline of the milestone was from the creation of the milestone.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: the milestone to clone, identified by ID and if not available by name
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CloneMilestoneOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Milestone"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CloneMilestoneOption)
+	milestone := getMilestoneByIDOrName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	targetRepo := ctx.Repo.Repository
+	if form.Owner != "" || form.Repo != "" {
+		owner, name := form.Owner, form.Repo
+		if owner == "" {
+			owner = ctx.Repo.Repository.OwnerName
+		}
+		if name == "" {
+			name = ctx.Repo.Repository.Name
+		}
+		repo, err := models.GetRepositoryByOwnerAndName(owner, name)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", errors.New("the target repository does not exist"))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return
+		}
+		targetRepo = repo
+	}
+	// reading the milestone is enough to clone it to a repository the doer can write milestones of
+	perm := ctx.Repo.Permission
+	if targetRepo.ID != ctx.Repo.Repository.ID {
+		var err error
+		perm, err = models.GetUserRepoPermission(targetRepo, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		if !perm.HasAccess() {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("the target repository does not exist"))
+			return
+		}
+	}
+	if !perm.CanWrite(models.UnitTypeIssues) && !perm.CanWrite(models.UnitTypePullRequests) {
+		ctx.Error(http.StatusForbidden, "", "Must have write access to the issues or pull requests of the target repository")
+		return
+	}
+
+	if form.Title != "" {
+		milestone.Name = form.Title
+	}
+	clone, err := models.CloneMilestone(milestone, targetRepo.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CloneMilestone", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAPIMilestone(clone))
+}
@@ -72,7 +72,7 @@ func getNote(ctx *context.APIContext, identifier string) {
 		return
 	}
 
-	cmt, err := convert.ToCommit(ctx.Repo.Repository, note.Commit, nil)
+	cmt, err := convert.ToCommit(ctx.Repo.Repository, note.Commit, nil, convert.ToCommitOptions{Files: true})
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "ToCommit", err)
 		return
@@ -0,0 +1,261 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	pages_service "code.gitea.io/gitea/services/pages"
+)
+
+// GetRepoPages get the pages site of a repository
+func GetRepoPages(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pages repository repoGetPages
+	// ---
+	// summary: Get the static site published from a branch of a repository and the status of its latest publish
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPages"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pages, err := models.GetRepoPages(ctx.Repo.Repository.ID)
+	if err != nil {
+		if err == models.ErrRepoPagesNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoPages", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoPages(ctx.Repo.Repository, pages))
+}
+
+// CreateOrUpdateRepoPages publish a branch of a repository as a static site
+func CreateOrUpdateRepoPages(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/pages repository repoCreateOrUpdatePages
+	// ---
+	// summary: Publish a branch of a repository as a static site, or change the settings of its site
+	// description: The site is served at `{owner}.{pages domain}/{repo}/` and at its custom domain, whose DNS record must
+	//   point to this instance. The custom domain is only used once it is verified, by a TXT record named `custom_domain_challenge_record`
+	//   containing `custom_domain_verification_code`. Only sites of public repositories are served. The site is republished on each push to the branch.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateRepoPagesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPages"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateOrUpdateRepoPagesOption)
+	customDomain := strings.ToLower(strings.TrimSpace(form.CustomDomain))
+	if customDomain != "" {
+		if !setting.Pages.AllowCustomDomains {
+			ctx.Error(http.StatusUnprocessableEntity, "", "custom domains are disabled by administrator")
+			return
+		}
+		if !pages_service.IsValidCustomDomain(customDomain) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid custom domain")
+			return
+		}
+	}
+
+	pages := &models.RepoPages{
+		RepoID:       ctx.Repo.Repository.ID,
+		Branch:       form.Branch,
+		Directory:    strings.Trim(form.Directory, "/"),
+		CustomDomain: customDomain,
+	}
+	if err := models.CreateOrUpdateRepoPages(pages); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateOrUpdateRepoPages", err)
+		return
+	}
+	// the TXT record might be in place already
+	if _, err := pages_service.VerifyCustomDomain(pages); err != nil {
+		ctx.Error(http.StatusInternalServerError, "VerifyCustomDomain", err)
+		return
+	}
+	if err := pages_service.QueuePublish(pages); err != nil {
+		ctx.Error(http.StatusInternalServerError, "QueuePublish", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoPages(ctx.Repo.Repository, pages))
+}
+
+// PublishRepoPages republish the pages site of a repository
+func PublishRepoPages(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pages/publish repository repoPublishPages
+	// ---
+	// summary: Republish the static site of a repository from the current head of its branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/RepoPages"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pages, err := models.GetRepoPages(ctx.Repo.Repository.ID)
+	if err != nil {
+		if err == models.ErrRepoPagesNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoPages", err)
+		}
+		return
+	}
+	if err := pages_service.QueuePublish(pages); err != nil {
+		ctx.Error(http.StatusInternalServerError, "QueuePublish", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, convert.ToRepoPages(ctx.Repo.Repository, pages))
+}
+
+// VerifyRepoPagesCustomDomain verify the repository controls the custom domain of its pages site
+func VerifyRepoPagesCustomDomain(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pages/verify repository repoVerifyPagesCustomDomain
+	// ---
+	// summary: Verify the custom domain of the static site of a repository by its TXT record
+	// description: The TXT record named `custom_domain_challenge_record` has to contain `custom_domain_verification_code`.
+	//   The site is served at the custom domain once it is verified. Other repositories which claim the domain have to verify it again.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPages"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pages, err := models.GetRepoPages(ctx.Repo.Repository.ID)
+	if err != nil {
+		if err == models.ErrRepoPagesNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoPages", err)
+		}
+		return
+	}
+	if pages.CustomDomain == "" || !setting.Pages.AllowCustomDomains {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the site has no custom domain")
+		return
+	}
+	verified, err := pages_service.VerifyCustomDomain(pages)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "VerifyCustomDomain", err)
+		return
+	}
+	if !verified {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("the TXT record %s does not contain the verification code", pages.CustomDomainChallengeRecord()))
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoPages(ctx.Repo.Repository, pages))
+}
+
+// DeleteRepoPages stop publishing the pages site of a repository
+func DeleteRepoPages(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pages repository repoDeletePages
+	// ---
+	// summary: Stop serving the static site of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteRepoPages(ctx.Repo.Repository.ID); err != nil {
+		if err == models.ErrRepoPagesNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteRepoPages", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/preview"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetFilePreview gets the preview of a file in a repository
+func GetFilePreview(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/preview/{filepath} repository repoGetFilePreview
+	// ---
+	// summary: Get the preview of a file in a repository, like the first page of a PDF or the bounding box of a STL model
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: filepath of the file to preview
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FilePreview"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return
+	}
+
+	commit := ctx.Repo.Commit
+
+	if ref := ctx.FormTrim("ref"); len(ref) > 0 {
+		var err error
+		commit, err = ctx.Repo.GitRepo.GetCommit(ref)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+			}
+			return
+		}
+	}
+
+	blob, err := commit.GetBlobByPath(ctx.Repo.TreePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBlobByPath", err)
+		}
+		return
+	}
+
+	p, provider, err := preview.GenerateBlob(ctx, blob)
+	if err != nil {
+		if preview.IsErrNotPreviewable(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GenerateBlob", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.FilePreview{
+		Name:     blob.Name(),
+		Path:     ctx.Repo.TreePath,
+		SHA:      blob.ID.String(),
+		Provider: provider.Name(),
+		Image:    base64.StdEncoding.EncodeToString(p.Image),
+		Metadata: p.Metadata,
+	})
+}
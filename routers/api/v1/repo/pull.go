@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
@@ -258,6 +259,8 @@ func CreatePullRequest(ctx *context.APIContext) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/PullRequest"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "409":
 	//     "$ref": "#/responses/error"
 	//   "422":
@@ -333,12 +336,12 @@ func CreatePullRequest(ctx *context.APIContext) {
 	}
 
 	if form.Milestone > 0 {
-		milestone, err := models.GetMilestoneByRepoID(ctx.Repo.Repository.ID, form.Milestone)
+		milestone, err := ctx.Repo.Repository.GetMilestoneByID(form.Milestone)
 		if err != nil {
 			if models.IsErrMilestoneNotExist(err) {
 				ctx.NotFound()
 			} else {
-				ctx.Error(http.StatusInternalServerError, "GetMilestoneByRepoID", err)
+				ctx.Error(http.StatusInternalServerError, "GetMilestoneByID", err)
 			}
 			return
 		}
@@ -405,6 +408,9 @@ func CreatePullRequest(ctx *context.APIContext) {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
 			return
+		} else if models.IsErrRepoInteractionLimited(err) {
+			ctx.Error(http.StatusForbidden, "NewPullRequest", err)
+			return
 		}
 		ctx.Error(http.StatusInternalServerError, "NewPullRequest", err)
 		return
@@ -663,6 +669,238 @@ func IsPullRequestMerged(ctx *context.APIContext) {
 	ctx.NotFound()
 }
 
+// GetPullRequestMergeMessage returns the default commit message of merging a PR
+func GetPullRequestMergeMessage(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/merge-message repository repoGetPullRequestMergeMessage
+	// ---
+	// summary: Preview the default commit message of merging a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: style
+	//   in: query
+	//   description: merge style, defaults to the default merge style of the repository
+	//   type: string
+	//   enum: [merge, rebase, rebase-merge, squash]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestMergeMessage"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	style := models.MergeStyle(ctx.FormString("style"))
+	switch style {
+	case models.MergeStyleMerge, models.MergeStyleRebase, models.MergeStyleRebaseMerge, models.MergeStyleSquash:
+	case "":
+		prUnit, err := ctx.Repo.Repository.GetUnit(models.UnitTypePullRequests)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUnit", err)
+			return
+		}
+		style = prUnit.PullRequestsConfig().GetDefaultMergeStyle()
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid merge style %q", style))
+		return
+	}
+
+	message, err := pull_service.GetDefaultMergeMessage(pr, style)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDefaultMergeMessage", err)
+		return
+	}
+	title, body := pull_service.SplitCommitMessage(message)
+	ctx.JSON(http.StatusOK, &api.PullRequestMergeMessage{
+		Style: string(style),
+		Title: title,
+		Body:  body,
+	})
+}
+
+// GetPullRequestMergeEligibility returns whether a pull request can be merged now and what blocks it
+func GetPullRequestMergeEligibility(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/merge_eligibility repository repoGetPullRequestMergeEligibility
+	// ---
+	// summary: Check whether a pull request can be merged now and list what blocks it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestMergeEligibility"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+	if err = pr.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return
+	}
+	pr.Issue.Repo = ctx.Repo.Repository
+
+	eligibility := &api.PullRequestMergeEligibility{
+		Mergeable:           pr.CanAutoMerge(),
+		Blockers:            []string{},
+		StaleStatusContexts: []string{},
+	}
+	eligibility.UserAllowed, err = pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsUserAllowedToMerge", err)
+		return
+	}
+
+	switch {
+	case pr.HasMerged:
+		eligibility.Blockers = append(eligibility.Blockers, "The pull request is already merged")
+	case pr.Issue.IsClosed:
+		eligibility.Blockers = append(eligibility.Blockers, "The pull request is closed")
+	case !eligibility.Mergeable:
+		eligibility.Blockers = append(eligibility.Blockers, "The pull request is not in a mergeable state")
+	}
+	if pr.IsWorkInProgress() {
+		eligibility.Blockers = append(eligibility.Blockers, "The pull request is a work in progress")
+	}
+	if !eligibility.UserAllowed {
+		eligibility.Blockers = append(eligibility.Blockers, "User not allowed to merge the pull request")
+	}
+
+	// the head branch of a closed pull request may be deleted already
+	if !pr.HasMerged && !pr.Issue.IsClosed {
+		reasons, err := pull_service.GetPRMergeBlockers(pr, false)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetPRMergeBlockers", err)
+			return
+		}
+		eligibility.Blockers = append(eligibility.Blockers, reasons...)
+
+		if pr.ProtectedBranch != nil && pr.ProtectedBranch.EnableStatusCheck {
+			eligibility.StatusCheckMaxAge = pr.ProtectedBranch.StatusCheckMaxAge
+			eligibility.StatusState, err = pull_service.GetPullRequestCommitStatusState(pr)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetPullRequestCommitStatusState", err)
+				return
+			}
+			staleContexts, err := pull_service.GetPullRequestStaleStatusContexts(pr)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetPullRequestStaleStatusContexts", err)
+				return
+			}
+			eligibility.StaleStatusContexts = append(eligibility.StaleStatusContexts, staleContexts...)
+		}
+	}
+
+	eligibility.Eligible = len(eligibility.Blockers) == 0
+	ctx.JSON(http.StatusOK, eligibility)
+}
+
+// GetPullRequestSuggestedReviewers suggests reviewers of a pull request from the history of its changed files
+func GetPullRequestSuggestedReviewers(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/suggested-reviewers repository repoGetPullRequestSuggestedReviewers
+	// ---
+	// summary: Suggest reviewers of a pull request from the recent authors of its changed files
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: limit
+	//   in: query
+	//   description: maximum number of suggested reviewers, defaults to 5
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	limit := ctx.FormInt("limit")
+	if limit <= 0 || limit > setting.API.MaxResponseItems {
+		limit = 5
+	}
+
+	reviewers, err := pull_service.GetSuggestedReviewers(pr, limit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSuggestedReviewers", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToUsers(ctx.User, reviewers))
+}
+
 // MergePullRequest merges a PR given an index
 func MergePullRequest(ctx *context.APIContext) {
 	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/merge repository repoMergePullRequest
@@ -811,11 +1049,10 @@ func MergePullRequest(ctx *context.APIContext) {
 
 	message := strings.TrimSpace(form.MergeTitleField)
 	if len(message) == 0 {
-		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
-			message = pr.GetDefaultSquashMessage()
+		message, err = pull_service.GetDefaultMergeMessage(pr, models.MergeStyle(form.Do))
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetDefaultMergeMessage", err)
+			return
 		}
 	}
 
@@ -1208,7 +1445,7 @@ func GetPullRequestCommits(ctx *context.APIContext) {
 
 	apiCommits := make([]*api.Commit, 0, end-start)
 	for i := start; i < end; i++ {
-		apiCommit, err := convert.ToCommit(ctx.Repo.Repository, commits[i], userCache)
+		apiCommit, err := convert.ToCommit(ctx.Repo.Repository, commits[i], userCache, convert.ToCommitOptions{Files: true})
 		if err != nil {
 			ctx.ServerError("toCommit", err)
 			return
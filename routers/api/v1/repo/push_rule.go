@@ -0,0 +1,233 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetPushRule returns the push rules of a repository
+func GetPushRule(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/push-rules repository repoGetPushRule
+	// ---
+	// summary: Get the rules every push to a repository has to follow
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushRule"
+
+	rule, err := models.GetRepoPushRule(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoPushRule", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPushRule(rule))
+}
+
+// EditPushRule changes the push rules of a repository
+func EditPushRule(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/push-rules repository repoEditPushRule
+	// ---
+	// summary: Change the rules every push to a repository has to follow
+	// description: The rules are checked for pushes over git and for changes made with the contents API and the web editor.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPushRuleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushRule"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditPushRuleOption)
+	rule, err := models.GetRepoPushRule(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoPushRule", err)
+		return
+	}
+
+	if form.MaxFileSize != nil {
+		if *form.MaxFileSize < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("max_file_size must not be negative"))
+			return
+		}
+		rule.MaxFileSize = *form.MaxFileSize
+	}
+	if form.ForbiddenFilePatterns != nil {
+		rule.ForbiddenFilePatterns = strings.TrimSpace(*form.ForbiddenFilePatterns)
+	}
+	if form.AuthorEmailDomains != nil {
+		for _, domain := range form.AuthorEmailDomains {
+			if strings.ContainsAny(strings.TrimPrefix(domain, "@"), "@, ") {
+				ctx.Error(http.StatusUnprocessableEntity, "", errors.New("author_email_domains must only contain domains"))
+				return
+			}
+		}
+		rule.AuthorEmailDomains = strings.Join(form.AuthorEmailDomains, ",")
+	}
+	if form.RejectForcePush != nil {
+		rule.RejectForcePush = *form.RejectForcePush
+	}
+
+	if err := models.SaveRepoPushRule(rule); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SaveRepoPushRule", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPushRule(rule))
+}
+
+// CheckPush checks a push against the push rules of a repository without applying it
+func CheckPush(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/push-check repository repoCheckPush
+	// ---
+	// summary: Check a push against the push rules of a repository without applying it
+	// description: The pushed objects which are not in the repository yet have to be sent as pack.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/PushCheckOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushCheckResult"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.PushCheckOption)
+	repo := ctx.Repo.Repository
+
+	for _, ref := range form.Refs {
+		if !strings.HasPrefix(ref.Ref, "refs/") {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("ref %s is not a full ref name", ref.Ref))
+			return
+		}
+		for _, sha := range []string{ref.OldSHA, ref.NewSHA} {
+			if sha != "" && (len(sha) != 40 || !git.SHAPattern.MatchString(sha)) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is not a full commit SHA", sha))
+				return
+			}
+		}
+	}
+
+	rule, err := models.GetRepoPushRule(repo.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoPushRule", err)
+		return
+	}
+
+	env := os.Environ()
+	if form.Pack != "" {
+		if int64(base64.StdEncoding.DecodedLen(len(form.Pack))) > setting.Repository.MaxPushCheckPackSize {
+			ctx.Error(http.StatusRequestEntityTooLarge, "", fmt.Errorf("pack is larger than %d bytes", setting.Repository.MaxPushCheckPackSize))
+			return
+		}
+		pack, err := base64.StdEncoding.DecodeString(form.Pack)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("pack is not base64 encoded: %v", err))
+			return
+		}
+		var cleanup func()
+		env, cleanup, err = repo_service.UnpackPushObjects(repo, pack)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unable to unpack the pack: %v", err))
+			return
+		}
+		defer cleanup()
+	}
+
+	result := &api.PushCheckResult{
+		Allowed: true,
+		Refs:    make([]*api.PushCheckRefResult, 0, len(form.Refs)),
+	}
+	for _, ref := range form.Refs {
+		oldSHA := ref.OldSHA
+		if oldSHA == "" {
+			oldSHA = git.EmptySHA
+		}
+		for _, sha := range []string{oldSHA, ref.NewSHA} {
+			if sha != git.EmptySHA && !repo_service.IsPushCommitExist(repo, sha, env) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("commit %s is neither in the repository nor in the pack", sha))
+				return
+			}
+		}
+
+		refResult := &api.PushCheckRefResult{Ref: ref.Ref, Allowed: true}
+		if err := repo_service.CheckPushRule(repo, rule, oldSHA, ref.NewSHA, ref.Ref, env); err != nil {
+			if !models.IsErrPushRuleViolated(err) {
+				ctx.Error(http.StatusInternalServerError, "CheckPushRule", err)
+				return
+			}
+			violation := err.(models.ErrPushRuleViolated)
+			refResult.Allowed = false
+			refResult.Reason = violation.Reason
+			for _, f := range violation.OversizedFiles {
+				refResult.OversizedFiles = append(refResult.OversizedFiles, &api.PushCheckFile{Path: f.Path, Size: f.Size})
+			}
+			refResult.LFSPatterns = repo_service.PushRuleLFSPatterns(violation.OversizedFiles)
+			refResult.LFSHint = repo_service.PushRuleLFSHint(violation.OversizedFiles)
+			result.Allowed = false
+		}
+		result.Refs = append(result.Refs, refResult)
+	}
+	ctx.JSON(http.StatusOK, result)
+}
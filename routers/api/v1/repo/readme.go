@@ -0,0 +1,95 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetReadme gets the README of a repository
+func GetReadme(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/readme repository repoGetReadme
+	// ---
+	// summary: Gets the README of a repository with its raw and rendered content
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReadmeResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return
+	}
+
+	ref := ctx.FormTrim("ref")
+	if ref == "" {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+
+	treePath, err := repofiles.FindReadme(commit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindReadme", err)
+		return
+	}
+	if treePath == "" {
+		ctx.NotFound()
+		return
+	}
+
+	contents, err := repofiles.GetContents(ctx.Repo.Repository, treePath, ref, false)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetContents", err)
+		return
+	}
+
+	blob, err := commit.GetBlobByPath(treePath)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBlobByPath", err)
+		return
+	}
+	html, err := repofiles.RenderReadme(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, blob, treePath, ref)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RenderReadme", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.ReadmeResponse{
+		ContentsResponse: *contents,
+		HTML:             html,
+	})
+}
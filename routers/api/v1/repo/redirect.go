@@ -0,0 +1,67 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListRedirects list the old paths which redirect to a repository
+func ListRedirects(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/redirects repository repoListRedirects
+	// ---
+	// summary: List the old paths of a renamed or transferred repository, which redirect to it
+	// description: Paths of previous owners which are not visible to the user are not listed.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RedirectList"
+
+	redirects, err := models.FindRepoRedirects(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoRedirects", err)
+		return
+	}
+
+	// previous owners the doer can not see are left out, their names must not leak through a transfer
+	ownerNames := make(map[int64]string)
+	apiRedirects := make([]*api.Redirect, 0, len(redirects))
+	for _, redirect := range redirects {
+		ownerName, ok := ownerNames[redirect.OwnerID]
+		if !ok {
+			owner, err := models.GetUserByID(redirect.OwnerID)
+			if err != nil && !models.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusInternalServerError, "GetUserByID", err)
+				return
+			}
+			if err == nil && models.HasOrgOrUserVisible(owner, ctx.User) {
+				ownerName = owner.Name
+			}
+			ownerNames[redirect.OwnerID] = ownerName
+		}
+		if ownerName == "" {
+			continue
+		}
+		apiRedirects = append(apiRedirects, convert.ToRepoRedirect(redirect, ownerName, ctx.Repo.Repository))
+	}
+	ctx.JSON(http.StatusOK, &apiRedirects)
+}
@@ -154,6 +154,8 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	//     "$ref": "#/responses/Attachment"
 	//   "400":
 	//     "$ref": "#/responses/error"
+	//   "413":
+	//     "$ref": "#/responses/error"
 
 	// Check if attachments are enabled
 	if !setting.Attachment.Enabled {
@@ -189,6 +191,10 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 			ctx.Error(http.StatusBadRequest, "DetectContentType", err)
 			return
 		}
+		if upload.IsErrFileTooLarge(err) {
+			ctx.Error(http.StatusRequestEntityTooLarge, "", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "NewAttachment", err)
 		return
 	}
@@ -0,0 +1,96 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"path"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/repofiles"
+)
+
+// GetRenderedFile renders a CSV file or a Jupyter notebook of a repository
+func GetRenderedFile(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/render/{filepath} repository repoGetRenderedFile
+	// ---
+	// summary: Render a CSV file or a Jupyter notebook of a repository to sanitized HTML and its rows or cells
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: filepath of the file to render
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RenderedFile"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return
+	}
+
+	commit := ctx.Repo.Commit
+	ref := ctx.FormTrim("ref")
+	if len(ref) > 0 {
+		var err error
+		commit, err = ctx.Repo.GitRepo.GetCommit(ref)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+			}
+			return
+		}
+	} else {
+		ref = ctx.Repo.CommitID
+	}
+
+	blob, err := commit.GetBlobByPath(ctx.Repo.TreePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBlobByPath", err)
+		}
+		return
+	}
+
+	if !repofiles.IsRenderableFile(ctx.Repo.TreePath) {
+		ctx.Error(http.StatusUnprocessableEntity, "", markup.ErrUnsupportedRenderExtension{Extension: path.Ext(ctx.Repo.TreePath)})
+		return
+	}
+
+	file, err := repofiles.RenderFile(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, blob, ctx.Repo.TreePath, ref)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RenderFile", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, file)
+}
@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -22,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	issue_service "code.gitea.io/gitea/services/issue"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
 
@@ -126,6 +128,10 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the repositories to return, e.g. full_name,owner.login,stars_count. All fields are returned if not given
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/SearchResults"
@@ -231,10 +237,15 @@ func Search(ctx *context.APIContext) {
 
 	ctx.SetLinkHeader(int(count), opts.PageSize)
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, api.SearchResults{
+	fields := ctx.FieldSet()
+	if fields != nil {
+		// the fields are the fields of the repositories
+		fields = json.FieldSet{"ok": nil, "data": fields}
+	}
+	ctx.JSON(http.StatusOK, fields.Select(api.SearchResults{
 		OK:   true,
 		Data: results,
-	})
+	}))
 }
 
 // CreateUserRepo create a repository for a user
@@ -524,11 +535,15 @@ func Get(ctx *context.APIContext) {
 	//   description: name of the repo
 	//   type: string
 	//   required: true
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the repository to return, e.g. full_name,owner.login,stars_count. All fields are returned if not given
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Repository"
 
-	ctx.JSON(http.StatusOK, convert.ToRepo(ctx.Repo.Repository, ctx.Repo.AccessMode))
+	ctx.JSONWithFields(http.StatusOK, convert.ToRepo(ctx.Repo.Repository, ctx.Repo.AccessMode))
 }
 
 // GetByID returns a single Repository
@@ -766,10 +781,16 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			var config *models.IssuesConfig
 
 			if opts.InternalTracker != nil {
+				branchNameTemplate := strings.TrimSpace(opts.InternalTracker.IssueBranchNameTemplate)
+				if err := issue_service.ValidateIssueBranchNameTemplate(branchNameTemplate); err != nil {
+					ctx.Error(http.StatusUnprocessableEntity, "Invalid issue branch name template", err)
+					return err
+				}
 				config = &models.IssuesConfig{
 					EnableTimetracker:                opts.InternalTracker.EnableTimeTracker,
 					AllowOnlyContributorsToTrackTime: opts.InternalTracker.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               opts.InternalTracker.EnableIssueDependencies,
+					BranchNameTemplate:               branchNameTemplate,
 				}
 			} else if unit, err := repo.GetUnit(models.UnitTypeIssues); err != nil {
 				// Unit type doesn't exist so we make a new config file with default values
@@ -884,6 +905,12 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			if opts.DefaultMergeStyle != nil {
 				config.DefaultMergeStyle = models.MergeStyle(*opts.DefaultMergeStyle)
 			}
+			if opts.DefaultMergeMessageTemplate != nil {
+				config.DefaultMergeMessageTemplate = strings.TrimSpace(*opts.DefaultMergeMessageTemplate)
+			}
+			if opts.DefaultSquashMessageTemplate != nil {
+				config.DefaultSquashMessageTemplate = strings.TrimSpace(*opts.DefaultSquashMessageTemplate)
+			}
 
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
@@ -0,0 +1,144 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListRunners list the workflow runners registered for a repository
+func ListRunners(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/runners repository repoListRunners
+	// ---
+	// summary: List the workflow runners registered for a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRunnerList"
+
+	runners, err := models.FindWorkflowRunners(0, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindWorkflowRunners", err)
+		return
+	}
+
+	apiRunners := make([]*api.WorkflowRunner, 0, len(runners))
+	for _, r := range runners {
+		apiRunners = append(apiRunners, convert.ToWorkflowRunner(r))
+	}
+	ctx.JSON(http.StatusOK, apiRunners)
+}
+
+// CreateRunner registers a workflow runner for a repository
+func CreateRunner(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/runners repository repoCreateRunner
+	// ---
+	// summary: Register a workflow runner for a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateWorkflowRunnerOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/WorkflowRunner"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateWorkflowRunnerOption)
+	runner := &models.WorkflowRunner{
+		Name:   form.Name,
+		RepoID: ctx.Repo.Repository.ID,
+		Labels: form.Labels,
+	}
+	if err := models.CreateWorkflowRunner(runner); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateWorkflowRunner", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToWorkflowRunner(runner))
+}
+
+// DeleteRunner deletes a workflow runner of a repository
+func DeleteRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/runners/{id} repository repoDeleteRunner
+	// ---
+	// summary: Delete a workflow runner of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the runner to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	runner, err := models.GetWorkflowRunnerByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrWorkflowRunnerNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetWorkflowRunnerByID", err)
+		}
+		return
+	}
+	if runner.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return
+	}
+
+	if err := models.DeleteWorkflowRunnerByID(runner.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteWorkflowRunnerByID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,276 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListSchedules list the schedules of a repository
+func ListSchedules(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/schedules repository repoListSchedules
+	// ---
+	// summary: List the schedules of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoScheduleList"
+
+	schedules, err := models.GetRepoSchedules(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoSchedules", err)
+		return
+	}
+
+	apiSchedules := make([]*api.RepoSchedule, 0, len(schedules))
+	for _, s := range schedules {
+		apiSchedules = append(apiSchedules, convert.ToRepoSchedule(s))
+	}
+	ctx.JSON(http.StatusOK, apiSchedules)
+}
+
+// GetSchedule get a schedule of a repository
+func GetSchedule(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/schedules/{id} repository repoGetSchedule
+	// ---
+	// summary: Get a schedule of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the schedule to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSchedule"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getRepoScheduleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoSchedule(s))
+}
+
+// CreateSchedule create a schedule for a repository
+func CreateSchedule(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/schedules repository repoCreateSchedule
+	// ---
+	// summary: Create a schedule for a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRepoScheduleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RepoSchedule"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateRepoScheduleOption)
+	s := &models.RepoSchedule{
+		RepoID:    ctx.Repo.Repository.ID,
+		Name:      form.Name,
+		Spec:      form.Cron,
+		IsActive:  form.Active == nil || *form.Active,
+		CreatorID: ctx.User.ID,
+	}
+	if form.CatchUp != "" {
+		policy, ok := models.ScheduleCatchUpPolicyFromString(form.CatchUp)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid catch_up policy: %s", form.CatchUp))
+			return
+		}
+		s.CatchUp = policy
+	}
+
+	if err := models.CreateRepoSchedule(s); err != nil {
+		if models.IsErrInvalidScheduleSpec(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else if err == models.ErrRepoScheduleAlreadyExist {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateRepoSchedule", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToRepoSchedule(s))
+}
+
+// EditSchedule modify a schedule of a repository
+func EditSchedule(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/schedules/{id} repository repoEditSchedule
+	// ---
+	// summary: Edit a schedule of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the schedule to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoScheduleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSchedule"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditRepoScheduleOption)
+	s := getRepoScheduleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Cron != nil {
+		s.Spec = *form.Cron
+	}
+	if form.CatchUp != nil {
+		policy, ok := models.ScheduleCatchUpPolicyFromString(*form.CatchUp)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid catch_up policy: %s", *form.CatchUp))
+			return
+		}
+		s.CatchUp = policy
+	}
+	if form.Active != nil {
+		s.IsActive = *form.Active
+	}
+
+	if err := models.UpdateRepoSchedule(s); err != nil {
+		if models.IsErrInvalidScheduleSpec(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateRepoSchedule", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoSchedule(s))
+}
+
+// DeleteSchedule delete a schedule of a repository
+func DeleteSchedule(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/schedules/{id} repository repoDeleteSchedule
+	// ---
+	// summary: Delete a schedule of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the schedule to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteRepoSchedule(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if err == models.ErrRepoScheduleNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteRepoSchedule", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// getRepoScheduleByParams gets the schedule of the repository identified by the id url parameter.
+// On error it writes the response.
+func getRepoScheduleByParams(ctx *context.APIContext) *models.RepoSchedule {
+	s, err := models.GetRepoScheduleByID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrRepoScheduleNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoScheduleByID", err)
+		}
+		return nil
+	}
+	return s
+}
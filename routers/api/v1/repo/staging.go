@@ -0,0 +1,473 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListStagingSessions lists the staging sessions of the authenticated user in a repository
+func ListStagingSessions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/staging-sessions repository repoListStagingSessions
+	// ---
+	// summary: List the staging sessions of the authenticated user in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StagingSessionList"
+
+	sessions, err := models.FindStagingSessions(ctx.Repo.Repository.ID, ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindStagingSessions", err)
+		return
+	}
+	apiSessions := make([]*api.StagingSession, 0, len(sessions))
+	for _, s := range sessions {
+		apiSessions = append(apiSessions, convert.ToStagingSession(s))
+	}
+	ctx.JSON(http.StatusOK, apiSessions)
+}
+
+// CreateStagingSession creates a staging session for a branch
+func CreateStagingSession(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/staging-sessions repository repoCreateStagingSession
+	// ---
+	// summary: Create a session to stage changes of files which are committed at once
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateStagingSessionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/StagingSession"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	form := web.GetForm(ctx).(*api.CreateStagingSessionOption)
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Error(http.StatusUnprocessableEntity, "RepoIsEmpty", fmt.Errorf("repo is empty"))
+		return
+	}
+
+	branch := form.Branch
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+	commit, err := ctx.Repo.GitRepo.GetBranchCommit(branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBranchCommit", err)
+		}
+		return
+	}
+
+	s := &models.StagingSession{
+		RepoID:       ctx.Repo.Repository.ID,
+		UserID:       ctx.User.ID,
+		Branch:       branch,
+		BaseCommitID: commit.ID.String(),
+	}
+	if err := models.CreateStagingSession(s); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateStagingSession", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToStagingSession(s))
+}
+
+// GetStagingSession returns a staging session with its changes
+func GetStagingSession(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/staging-sessions/{id} repository repoGetStagingSession
+	// ---
+	// summary: Get a staging session with its changes
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the staging session
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StagingSession"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getStagingSessionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := s.LoadChanges(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadChanges", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStagingSession(s))
+}
+
+// EditStagingSession stages or unstages changes of files in a staging session
+func EditStagingSession(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/staging-sessions/{id} repository repoEditStagingSession
+	// ---
+	// summary: Stage or unstage changes of files in a staging session
+	// description: A staged change replaces the change previously staged for the same path. The content of each
+	//   staged file is limited by the maximum upload file size and the content of all staged files by the maximum
+	//   staging session size.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the staging session
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditStagingSessionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StagingSession"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	form := web.GetForm(ctx).(*api.EditStagingSessionOption)
+	s := getStagingSessionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	maxFileSize := setting.Repository.Upload.FileMaxSize * 1024 * 1024
+	changes := make([]*models.StagedChange, 0, len(form.Changes))
+	unstaged := make([]string, 0, len(form.Changes))
+	for _, opt := range form.Changes {
+		treePath := repofiles.CleanUploadFileName(opt.Path)
+		if treePath == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "Invalid", models.ErrFilenameInvalid{Path: opt.Path})
+			return
+		}
+		if opt.Operation == "unstage" {
+			unstaged = append(unstaged, treePath)
+			continue
+		}
+
+		op := models.StagedChangeOperation(opt.Operation)
+		if !op.IsValid() {
+			ctx.Error(http.StatusUnprocessableEntity, "Invalid", fmt.Errorf("unknown operation %q", opt.Operation))
+			return
+		}
+		change := &models.StagedChange{
+			TreePath:  treePath,
+			Operation: op,
+		}
+		if op != models.StagedChangeOperationDelete {
+			if int64(base64.StdEncoding.DecodedLen(len(opt.Content))) > maxFileSize {
+				ctx.Error(http.StatusRequestEntityTooLarge, "", fmt.Errorf("content of %s is larger than %d bytes", opt.Path, maxFileSize))
+				return
+			}
+			if _, err := base64.StdEncoding.DecodeString(opt.Content); err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "Invalid", fmt.Errorf("content of %s is not base64 encoded: %v", opt.Path, err))
+				return
+			}
+			change.Content = opt.Content
+		}
+		if op == models.StagedChangeOperationUpdate && opt.FromPath != "" {
+			change.FromTreePath = repofiles.CleanUploadFileName(opt.FromPath)
+			if change.FromTreePath == "" {
+				ctx.Error(http.StatusUnprocessableEntity, "Invalid", models.ErrFilenameInvalid{Path: opt.FromPath})
+				return
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	// the size of the staged files must stay within the limit of a session once the changes are applied
+	if err := s.LoadChanges(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadChanges", err)
+		return
+	}
+	sizes := make(map[string]int64, len(s.Changes)+len(changes))
+	for _, change := range s.Changes {
+		sizes[change.TreePath] = int64(base64.StdEncoding.DecodedLen(len(change.Content)))
+	}
+	for _, change := range changes {
+		sizes[change.TreePath] = int64(base64.StdEncoding.DecodedLen(len(change.Content)))
+	}
+	for _, treePath := range unstaged {
+		delete(sizes, treePath)
+	}
+	var sessionSize int64
+	for _, size := range sizes {
+		sessionSize += size
+	}
+	if sessionSize > setting.Repository.MaxStagingSessionSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", fmt.Errorf("staged files are larger than %d bytes", setting.Repository.MaxStagingSessionSize))
+		return
+	}
+
+	for _, change := range changes {
+		if err := models.StageChange(s, change); err != nil {
+			ctx.Error(http.StatusInternalServerError, "StageChange", err)
+			return
+		}
+	}
+	for _, treePath := range unstaged {
+		if err := models.UnstageChange(s, treePath); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UnstageChange", err)
+			return
+		}
+	}
+
+	s, err := models.GetStagingSession(s.RepoID, s.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStagingSession", err)
+		return
+	}
+	if err := s.LoadChanges(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadChanges", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStagingSession(s))
+}
+
+// DeleteStagingSession discards a staging session and its changes
+func DeleteStagingSession(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/staging-sessions/{id} repository repoDeleteStagingSession
+	// ---
+	// summary: Discard a staging session and its changes
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the staging session
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getStagingSessionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeleteStagingSession(s); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteStagingSession", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// CommitStagingSession commits all changes of a staging session at once
+func CommitStagingSession(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/staging-sessions/{id}/commit repository repoCommitStagingSession
+	// ---
+	// summary: Commit all changes of a staging session at once
+	// description: The session is deleted once its changes were committed.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the staging session
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CommitStagingSessionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	form := web.GetForm(ctx).(*api.CommitStagingSessionOption)
+	s := getStagingSessionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := s.LoadChanges(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadChanges", err)
+		return
+	}
+
+	opts := &repofiles.CommitStagingSessionOptions{
+		NewBranch: form.NewBranchName,
+		Message:   form.Message,
+		Committer: &repofiles.IdentityOptions{
+			Name:  form.Committer.Name,
+			Email: form.Committer.Email,
+		},
+		Author: &repofiles.IdentityOptions{
+			Name:  form.Author.Name,
+			Email: form.Author.Email,
+		},
+		Dates: &repofiles.CommitDateOptions{
+			Author:    form.Dates.Author,
+			Committer: form.Dates.Committer,
+		},
+		Signoff:  form.Signoff,
+		Trailers: form.Trailers,
+	}
+	if opts.Dates.Author.IsZero() {
+		opts.Dates.Author = time.Now()
+	}
+	if opts.Dates.Committer.IsZero() {
+		opts.Dates.Committer = time.Now()
+	}
+	if opts.Message == "" {
+		if len(s.Changes) == 1 {
+			switch s.Changes[0].Operation {
+			case models.StagedChangeOperationCreate:
+				opts.Message = ctx.Tr("repo.editor.add", s.Changes[0].TreePath)
+			case models.StagedChangeOperationDelete:
+				opts.Message = ctx.Tr("repo.editor.delete", s.Changes[0].TreePath)
+			default:
+				opts.Message = ctx.Tr("repo.editor.update", s.Changes[0].TreePath)
+			}
+		} else {
+			opts.Message = ctx.Tr("repo.editor.update_files", len(s.Changes))
+		}
+	}
+
+	fileResponse, err := repofiles.CommitStagingSession(ctx.Repo.Repository, ctx.User, s, opts)
+	if err != nil {
+		switch {
+		case models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) || models.IsErrPushRuleViolated(err):
+			ctx.Error(http.StatusForbidden, "Access", err)
+		case models.IsErrCommitIDDoesNotMatch(err):
+			ctx.Error(http.StatusConflict, "Conflict", err)
+		case err == repofiles.ErrStagingSessionEmpty || models.IsErrBranchAlreadyExists(err) ||
+			models.IsErrRepoFileAlreadyExists(err) || git.IsErrNotExist(err) || git.IsErrBranchNotExist(err) ||
+			git.IsErrInvalidTrailer(err):
+			ctx.Error(http.StatusUnprocessableEntity, "Invalid", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CommitStagingSession", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, fileResponse)
+}
+
+// getStagingSessionByParams returns the staging session of the authenticated user with the id of the path
+func getStagingSessionByParams(ctx *context.APIContext) *models.StagingSession {
+	s, err := models.GetStagingSession(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrStagingSessionNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetStagingSession", err)
+		}
+		return nil
+	}
+	if s.UserID != ctx.User.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,237 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	symbol_indexer "code.gitea.io/gitea/modules/indexer/symbol"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListSymbolDefinitions lists the definitions found by the symbol indexer
+func ListSymbolDefinitions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/symbols/definitions repository repoListSymbolDefinitions
+	// ---
+	// summary: Find the definitions of a symbol or list the symbols defined in a file
+	// description: Requires the symbol indexer to be enabled. Branches which were not indexed yet are queued for indexing.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the symbol, required if no path is given
+	//   type: string
+	// - name: path
+	//   in: query
+	//   description: only list symbols defined in this file, required if no name is given
+	//   type: string
+	// - name: kind
+	//   in: query
+	//   description: only list symbols of this kind, e.g. `function`
+	//   type: string
+	// - name: ref
+	//   in: query
+	//   description: "name of the branch (default: the repository's default branch)"
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SymbolList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !setting.Indexer.SymbolIndexerEnabled {
+		ctx.NotFound()
+		return
+	}
+
+	opts := &models.FindRepoSymbolOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		Ref:         ctx.FormTrim("ref"),
+		Name:        ctx.FormTrim("name"),
+		Kind:        ctx.FormTrim("kind"),
+		Path:        ctx.FormTrim("path"),
+	}
+	if opts.Name == "" && opts.Path == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "name or path is required")
+		return
+	}
+	if opts.Ref == "" {
+		opts.Ref = ctx.Repo.Repository.DefaultBranch
+	}
+	if !ctx.Repo.GitRepo.IsBranchExist(opts.Ref) {
+		ctx.NotFound()
+		return
+	}
+	if !symbol_indexer.ShouldIndex(ctx.Repo.Repository, opts.Ref) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("symbols of branch %s are not indexed", opts.Ref))
+		return
+	}
+
+	status, err := models.GetRepoSymbolStatus(ctx.Repo.Repository.ID, opts.Ref)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoSymbolStatus", err)
+		return
+	} else if status == nil {
+		if err := symbol_indexer.UpdateRepoIndexer(ctx.Repo.Repository, opts.Ref); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateRepoIndexer", err)
+			return
+		}
+		ctx.NotFound(fmt.Sprintf("symbols of branch %s are being indexed", opts.Ref))
+		return
+	}
+
+	symbols, count, err := models.FindRepoSymbols(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoSymbols", err)
+		return
+	}
+
+	apiSymbols := make([]*api.Symbol, 0, len(symbols))
+	for _, s := range symbols {
+		apiSymbols = append(apiSymbols, convert.ToSymbol(ctx.Repo.Repository, s))
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiSymbols)
+}
+
+// ListSymbolReferences lists the lines which reference a symbol
+func ListSymbolReferences(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/symbols/references repository repoListSymbolReferences
+	// ---
+	// summary: Find the lines referencing a symbol
+	// description: Lines containing the name of the symbol as a whole word are returned. If the symbols of the ref are indexed, definitions are marked.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the symbol
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "name of the commit/branch/tag (default: the repository's default branch)"
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SymbolReferenceList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	name := ctx.FormTrim("name")
+	if name == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "name is required")
+		return
+	}
+	ref := ctx.FormTrim("ref")
+	if ref == "" {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+
+	commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+	commitID := commit.ID.String()
+
+	listOptions := utils.GetListOptions(ctx)
+	if listOptions.Page <= 0 {
+		listOptions.Page = 1
+	}
+	skip := (listOptions.Page - 1) * listOptions.PageSize
+	results, err := ctx.Repo.GitRepo.GrepWord(commitID, name, skip, listOptions.PageSize)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GrepWord", err)
+		return
+	}
+
+	// definitions can only be marked if the symbols of this very commit are indexed
+	definitions := make(map[string]bool)
+	if setting.Indexer.SymbolIndexerEnabled {
+		status, err := models.GetRepoSymbolStatus(ctx.Repo.Repository.ID, ref)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetRepoSymbolStatus", err)
+			return
+		}
+		if status != nil && status.CommitSha == commitID {
+			symbols, _, err := models.FindRepoSymbols(&models.FindRepoSymbolOptions{
+				RepoID: ctx.Repo.Repository.ID,
+				Ref:    ref,
+				Name:   name,
+			})
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "FindRepoSymbols", err)
+				return
+			}
+			for _, s := range symbols {
+				definitions[s.Path+":"+strconv.Itoa(s.Line)] = true
+			}
+		}
+	}
+
+	references := make([]*api.SymbolReference, 0, len(results))
+	for _, r := range results {
+		isDefinition := definitions[r.Filename+":"+strconv.Itoa(r.LineNumber)]
+		references = append(references, convert.ToSymbolReference(ctx.Repo.Repository, commitID, r, isDefinition))
+	}
+	ctx.JSON(http.StatusOK, references)
+}
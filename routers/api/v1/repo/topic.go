@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -245,6 +246,52 @@ func DeleteTopic(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// SuggestTopics suggests topics for a repository
+func SuggestTopics(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/topics/suggest repository repoSuggestTopics
+	// ---
+	// summary: Suggest existing topics for a repository based on its name, description, languages and the topics of its owner
+	// produces:
+	//   - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: limit
+	//   in: query
+	//   description: maximum number of suggestions, defaults to 10
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TopicListResponse"
+
+	limit := ctx.FormInt("limit")
+	if limit <= 0 || limit > setting.API.MaxResponseItems {
+		limit = 10
+	}
+
+	topics, err := models.SuggestTopics(ctx.Repo.Repository, ctx.User, limit)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	topicResponses := make([]*api.TopicResponse, len(topics))
+	for i, topic := range topics {
+		topicResponses[i] = convert.ToTopicResponse(topic)
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"topics": topicResponses,
+	})
+}
+
 // TopicSearch search for creating topic
 func TopicSearch(ctx *context.APIContext) {
 	// swagger:operation GET /topics/search repository topicSearch
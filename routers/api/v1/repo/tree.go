@@ -7,8 +7,12 @@ package repo
 import (
 	"net/http"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/repofiles"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
 )
 
 // GetTree get the tree of a repository.
@@ -66,3 +70,71 @@ func GetTree(ctx *context.APIContext) {
 		ctx.JSON(http.StatusOK, tree)
 	}
 }
+
+// CreateTree creates a tree object from entries merged into a base tree
+func CreateTree(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/trees repository repoCreateTree
+	// ---
+	// summary: Create a tree object, no commit or branch is updated
+	// description: The paths of the entries may contain slashes, the intermediate trees are then merged with those of the base tree. The trees left empty by removed paths are removed.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateGitTreeOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/GitTreeResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateGitTreeOption)
+	opts := &repofiles.WriteTreeOptions{
+		BaseTree: form.BaseTree,
+		Entries:  make([]*repofiles.WriteTreeEntry, 0, len(form.Tree)),
+	}
+	for _, entry := range form.Tree {
+		opts.Entries = append(opts.Entries, &repofiles.WriteTreeEntry{
+			Path:    entry.Path,
+			Mode:    git.ToEntryMode(entry.Mode),
+			SHA:     entry.SHA,
+			Content: entry.Content,
+		})
+	}
+
+	sha, err := repofiles.WriteTree(ctx.Repo.Repository, opts)
+	if err != nil {
+		if models.IsErrSHANotFound(err) || models.IsErrFilePathInvalid(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "WriteTree", err)
+		}
+		return
+	}
+
+	tree, err := repofiles.GetTreeBySHA(ctx.Repo.Repository, sha, 1, 0, false)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTreeBySHA", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, tree)
+}
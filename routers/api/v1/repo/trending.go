@@ -0,0 +1,81 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListTrending lists the trending repositories visible to the doer
+func ListTrending(ctx *context.APIContext) {
+	// swagger:operation GET /repos/trending repository repoListTrending
+	// ---
+	// summary: List the trending repositories
+	// description: Repositories are ranked by the stars gained and the pull requests opened and merged in the period.
+	//              The ranking is computed periodically by the `update_repo_trending` cron task.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: period
+	//   in: query
+	//   description: period to rank the activity over, defaults to `weekly`
+	//   type: string
+	//   enum: [daily, weekly, monthly]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TrendingRepositoryList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	period := models.TrendingPeriodWeekly
+	if name := ctx.FormTrim("period"); name != "" {
+		var ok bool
+		if period, ok = models.TrendingPeriodFromString(name); !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid period: %s", name))
+			return
+		}
+	}
+
+	trending, count, err := models.FindTrendingRepos(&models.FindTrendingReposOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Period:      period,
+		Actor:       ctx.User,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindTrendingRepos", err)
+		return
+	}
+
+	results := make([]*api.TrendingRepository, 0, len(trending))
+	for _, t := range trending {
+		if t.Repo == nil {
+			continue
+		}
+		accessMode, err := models.AccessLevel(ctx.User, t.Repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		results = append(results, convert.ToTrendingRepository(t, accessMode))
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,303 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// GetVulnerabilityAlertSettings returns whether a repository is alerted about vulnerable dependencies
+func GetVulnerabilityAlertSettings(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/vulnerability-alerts/settings repository repoGetVulnerabilityAlertSettings
+	// ---
+	// summary: Get whether a repository is alerted about dependencies on vulnerable versions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VulnerabilityAlertSettings"
+
+	s, err := models.GetRepoVulnerabilitySetting(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoVulnerabilitySetting", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToVulnerabilityAlertSettings(s))
+}
+
+// EditVulnerabilityAlertSettings changes whether a repository is alerted about vulnerable dependencies
+func EditVulnerabilityAlertSettings(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/vulnerability-alerts/settings repository repoEditVulnerabilityAlertSettings
+	// ---
+	// summary: Change whether a repository is alerted about dependencies on vulnerable versions
+	// description: Issues for new alerts are opened in the name of the user who last changed the settings.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditVulnerabilityAlertSettingsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VulnerabilityAlertSettings"
+
+	form := web.GetForm(ctx).(*api.EditVulnerabilityAlertSettingsOption)
+	s, err := models.GetRepoVulnerabilitySetting(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoVulnerabilitySetting", err)
+		return
+	}
+
+	if form.Enabled != nil {
+		s.Enabled = *form.Enabled
+	}
+	if form.OpenIssues != nil {
+		s.OpenIssues = *form.OpenIssues
+	}
+	s.DoerID = ctx.User.ID
+	if err := models.SaveRepoVulnerabilitySetting(s); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SaveRepoVulnerabilitySetting", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToVulnerabilityAlertSettings(s))
+}
+
+// ListVulnerabilityAlerts lists the vulnerability alerts of a repository
+func ListVulnerabilityAlerts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/vulnerability-alerts repository repoListVulnerabilityAlerts
+	// ---
+	// summary: List the alerts about dependencies of a repository on vulnerable versions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: only list alerts in this state
+	//   type: string
+	//   enum: [open, dismissed, fixed]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VulnerabilityAlertList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := &models.FindVulnerabilityAlertOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+	}
+	if name := ctx.FormTrim("state"); name != "" {
+		state, ok := models.VulnerabilityAlertStateFromString(name)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid state: %s", name))
+			return
+		}
+		opts.States = []models.VulnerabilityAlertState{state}
+	}
+
+	alerts, count, err := models.FindVulnerabilityAlerts(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindVulnerabilityAlerts", err)
+		return
+	}
+
+	apiAlerts := make([]*api.VulnerabilityAlert, 0, len(alerts))
+	for _, a := range alerts {
+		if err := a.LoadAttributes(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+			return
+		}
+		apiAlerts = append(apiAlerts, convert.ToVulnerabilityAlert(a, ctx.User))
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiAlerts)
+}
+
+// GetVulnerabilityAlert gets a vulnerability alert of a repository
+func GetVulnerabilityAlert(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/vulnerability-alerts/{id} repository repoGetVulnerabilityAlert
+	// ---
+	// summary: Get a vulnerability alert of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the alert to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VulnerabilityAlert"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	alert := getVulnerabilityAlertByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToVulnerabilityAlert(alert, ctx.User))
+}
+
+// EditVulnerabilityAlert dismisses or reopens a vulnerability alert of a repository
+func EditVulnerabilityAlert(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/vulnerability-alerts/{id} repository repoEditVulnerabilityAlert
+	// ---
+	// summary: Dismiss or reopen a vulnerability alert of a repository
+	// description: Fixed alerts can not be changed, they are reopened when the repository depends on a vulnerable version again.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the alert to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditVulnerabilityAlertOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VulnerabilityAlert"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditVulnerabilityAlertOption)
+	alert := getVulnerabilityAlertByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if alert.State == models.VulnerabilityAlertStateFixed {
+		ctx.Error(http.StatusUnprocessableEntity, "", "fixed alerts can not be changed")
+		return
+	}
+
+	switch form.State {
+	case models.VulnerabilityAlertStateDismissed.String():
+		if !util.IsStringInSlice(form.DismissedReason, models.VulnerabilityAlertDismissReasons) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid dismissed_reason: %s", form.DismissedReason))
+			return
+		}
+		alert.State = models.VulnerabilityAlertStateDismissed
+		alert.DismissReason = form.DismissedReason
+		alert.DismissComment = form.DismissedComment
+		alert.DismisserID = ctx.User.ID
+		alert.Dismisser = ctx.User
+		alert.DismissedUnix = timeutil.TimeStampNow()
+	case models.VulnerabilityAlertStateOpen.String():
+		alert.State = models.VulnerabilityAlertStateOpen
+		alert.DismissReason = ""
+		alert.DismissComment = ""
+		alert.DismisserID = 0
+		alert.Dismisser = nil
+		alert.DismissedUnix = 0
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid state: %s", form.State))
+		return
+	}
+
+	if err := models.UpdateVulnerabilityAlert(alert, "state", "dismiss_reason", "dismiss_comment", "dismisser_id", "dismissed_unix"); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateVulnerabilityAlert", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToVulnerabilityAlert(alert, ctx.User))
+}
+
+func getVulnerabilityAlertByParams(ctx *context.APIContext) *models.VulnerabilityAlert {
+	alert, err := models.GetVulnerabilityAlertByID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrVulnerabilityAlertNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetVulnerabilityAlertByID", err)
+		}
+		return nil
+	}
+	if err := alert.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return nil
+	}
+	return alert
+}
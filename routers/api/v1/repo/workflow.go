@@ -0,0 +1,203 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	workflow_service "code.gitea.io/gitea/services/workflow"
+)
+
+// ListWorkflowRuns list the workflow runs of a repository
+func ListWorkflowRuns(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/workflows/runs repository repoListWorkflowRuns
+	// ---
+	// summary: List the workflow runs of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow
+	//   in: query
+	//   description: filter by the name of the workflow file
+	//   type: string
+	// - name: ref
+	//   in: query
+	//   description: filter by the full name of the ref
+	//   type: string
+	// - name: event
+	//   in: query
+	//   description: filter by the triggering event
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: filter by status
+	//   type: string
+	//   enum: [waiting, pending, running, success, failure, cancelled, skipped]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRunList"
+
+	opts := &models.FindWorkflowRunOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		WorkflowID:  ctx.FormTrim("workflow"),
+		Ref:         ctx.FormTrim("ref"),
+		Event:       ctx.FormTrim("event"),
+	}
+	if status := ctx.FormTrim("status"); status != "" {
+		var ok bool
+		if opts.Status, ok = models.WorkflowStatusFromString(status); !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid status")
+			return
+		}
+	}
+
+	runs, count, err := models.FindWorkflowRuns(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindWorkflowRuns", err)
+		return
+	}
+
+	apiRuns := make([]*api.WorkflowRun, 0, len(runs))
+	for _, run := range runs {
+		run.Repo = ctx.Repo.Repository
+		if err := run.LoadAttributes(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+			return
+		}
+		apiRuns = append(apiRuns, convert.ToWorkflowRun(run, nil))
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiRuns)
+}
+
+// GetWorkflowRun get a workflow run of a repository including its jobs
+func GetWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/workflows/runs/{id} repository repoGetWorkflowRun
+	// ---
+	// summary: Get a workflow run including its jobs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getWorkflowRun(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	jobs, err := models.GetWorkflowJobsByRunID(db.DefaultContext(), run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetWorkflowJobsByRunID", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToWorkflowRun(run, jobs))
+}
+
+// CancelWorkflowRun cancel the unfinished jobs of a workflow run
+func CancelWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/workflows/runs/{id}/cancel repository repoCancelWorkflowRun
+	// ---
+	// summary: Cancel the unfinished jobs of a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getWorkflowRun(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := workflow_service.CancelRun(run); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CancelRun", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getWorkflowRun(ctx *context.APIContext) *models.WorkflowRun {
+	run, err := models.GetWorkflowRunByRepoAndID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrWorkflowRunNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetWorkflowRunByRepoAndID", err)
+		}
+		return nil
+	}
+	run.Repo = ctx.Repo.Repository
+	if err := run.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return nil
+	}
+	return run
+}
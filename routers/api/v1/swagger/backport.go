@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// BackportMapping
+// swagger:response BackportMapping
+type swaggerResponseBackportMapping struct {
+	// in:body
+	Body api.BackportMapping `json:"body"`
+}
+
+// BackportMappingList
+// swagger:response BackportMappingList
+type swaggerResponseBackportMappingList struct {
+	// in:body
+	Body []api.BackportMapping `json:"body"`
+}
@@ -64,6 +64,13 @@ type swaggerResponseMilestoneList struct {
 	Body []api.Milestone `json:"body"`
 }
 
+// MilestoneBurndown
+// swagger:response MilestoneBurndown
+type swaggerResponseMilestoneBurndown struct {
+	// in:body
+	Body []api.MilestoneBurndownDay `json:"body"`
+}
+
 // TrackedTime
 // swagger:response TrackedTime
 type swaggerResponseTrackedTime struct {
@@ -92,6 +99,139 @@ type swaggerIssueTemplates struct {
 	Body []api.IssueTemplate `json:"body"`
 }
 
+// IssueWorkflowState
+// swagger:response IssueWorkflowState
+type swaggerIssueWorkflowState struct {
+	// in:body
+	Body api.IssueWorkflowState `json:"body"`
+}
+
+// IssueWorkflowStateList
+// swagger:response IssueWorkflowStateList
+type swaggerIssueWorkflowStateList struct {
+	// in:body
+	Body []api.IssueWorkflowState `json:"body"`
+}
+
+// IssuePriority
+// swagger:response IssuePriority
+type swaggerIssuePriority struct {
+	// in:body
+	Body api.IssuePriority `json:"body"`
+}
+
+// IssuePriorityList
+// swagger:response IssuePriorityList
+type swaggerIssuePriorityList struct {
+	// in:body
+	Body []api.IssuePriority `json:"body"`
+}
+
+// IssueSLAPolicy
+// swagger:response IssueSLAPolicy
+type swaggerIssueSLAPolicy struct {
+	// in:body
+	Body api.IssueSLAPolicy `json:"body"`
+}
+
+// IssueSLAPolicyList
+// swagger:response IssueSLAPolicyList
+type swaggerIssueSLAPolicyList struct {
+	// in:body
+	Body []api.IssueSLAPolicy `json:"body"`
+}
+
+// IssueSLAComplianceList
+// swagger:response IssueSLAComplianceList
+type swaggerIssueSLAComplianceList struct {
+	// in:body
+	Body []api.IssueSLACompliance `json:"body"`
+}
+
+// IssueSLAReportList
+// swagger:response IssueSLAReportList
+type swaggerIssueSLAReportList struct {
+	// in:body
+	Body []api.IssueSLAReport `json:"body"`
+}
+
+// IssueFormValues
+// swagger:response IssueFormValues
+type swaggerIssueFormValues struct {
+	// in:body
+	Body api.IssueFormValues `json:"body"`
+}
+
+// IssueBranchList
+// swagger:response IssueBranchList
+type swaggerIssueBranchList struct {
+	// in:body
+	Body []api.IssueBranch `json:"body"`
+}
+
+// IssueRelation
+// swagger:response IssueRelation
+type swaggerIssueRelation struct {
+	// in:body
+	Body api.IssueRelation `json:"body"`
+}
+
+// IssueRelationList
+// swagger:response IssueRelationList
+type swaggerIssueRelationList struct {
+	// in:body
+	Body []api.IssueRelation `json:"body"`
+}
+
+// IssueCustomField
+// swagger:response IssueCustomField
+type swaggerIssueCustomField struct {
+	// in:body
+	Body api.IssueCustomField `json:"body"`
+}
+
+// IssueCustomFieldList
+// swagger:response IssueCustomFieldList
+type swaggerIssueCustomFieldList struct {
+	// in:body
+	Body []api.IssueCustomField `json:"body"`
+}
+
+// IssueCustomFieldValueList
+// swagger:response IssueCustomFieldValueList
+type swaggerIssueCustomFieldValueList struct {
+	// in:body
+	Body []api.IssueCustomFieldValue `json:"body"`
+}
+
+// IterationCadence
+// swagger:response IterationCadence
+type swaggerIterationCadence struct {
+	// in:body
+	Body api.IterationCadence `json:"body"`
+}
+
+// IterationCadenceList
+// swagger:response IterationCadenceList
+type swaggerIterationCadenceList struct {
+	// in:body
+	Body []api.IterationCadence `json:"body"`
+}
+
+// Iteration
+// swagger:response Iteration
+type swaggerIteration struct {
+	// in:body
+	Body api.Iteration `json:"body"`
+}
+
+// IterationList
+// swagger:response IterationList
+type swaggerIterationList struct {
+	// in:body
+	Body []api.Iteration `json:"body"`
+}
+
 // StopWatch
 // swagger:response StopWatch
 type swaggerResponseStopWatch struct {
@@ -119,3 +259,17 @@ type swaggerReactionList struct {
 	// in:body
 	Body []api.Reaction `json:"body"`
 }
+
+// IssueFilter
+// swagger:response IssueFilter
+type swaggerIssueFilter struct {
+	// in:body
+	Body api.IssueFilter `json:"body"`
+}
+
+// IssueFilterList
+// swagger:response IssueFilterList
+type swaggerIssueFilterList struct {
+	// in:body
+	Body []api.IssueFilter `json:"body"`
+}
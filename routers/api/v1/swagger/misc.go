@@ -21,3 +21,59 @@ type swaggerResponseStringSlice struct {
 	// in:body
 	Body []string `json:"body"`
 }
+
+// MaintenanceMode
+// swagger:response MaintenanceMode
+type swaggerResponseMaintenanceMode struct {
+	// in:body
+	Body api.MaintenanceMode `json:"body"`
+}
+
+// Backup
+// swagger:response Backup
+type swaggerResponseBackup struct {
+	// in:body
+	Body api.Backup `json:"body"`
+}
+
+// BackupList
+// swagger:response BackupList
+type swaggerResponseBackupList struct {
+	// in:body
+	Body []api.Backup `json:"body"`
+}
+
+// UnifiedSearchResults
+// swagger:response UnifiedSearchResults
+type swaggerResponseUnifiedSearchResults struct {
+	// in:body
+	Body api.UnifiedSearchResults `json:"body"`
+}
+
+// IssueIndexerStatus
+// swagger:response IssueIndexerStatus
+type swaggerResponseIssueIndexerStatus struct {
+	// in:body
+	Body api.IssueIndexerStatus `json:"body"`
+}
+
+// FileTemplateList
+// swagger:response FileTemplateList
+type swaggerResponseFileTemplateList struct {
+	// in:body
+	Body []api.FileTemplate `json:"body"`
+}
+
+// StreamEvent
+// swagger:response StreamEvent
+type swaggerResponseStreamEvent struct {
+	// in:body
+	Body api.StreamEvent `json:"body"`
+}
+
+// ActivityPub
+// swagger:response ActivityPub
+type swaggerResponseActivityPub struct {
+	// in:body
+	Body api.ActivityPub `json:"body"`
+}
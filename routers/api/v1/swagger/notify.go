@@ -28,3 +28,17 @@ type swaggerNotificationCount struct {
 	// in:body
 	Body api.NotificationCount `json:"body"`
 }
+
+// NotificationChannel
+// swagger:response NotificationChannel
+type swaggerNotificationChannel struct {
+	// in:body
+	Body api.NotificationChannel `json:"body"`
+}
+
+// NotificationChannelList
+// swagger:response NotificationChannelList
+type swaggerNotificationChannelList struct {
+	// in:body
+	Body []api.NotificationChannel `json:"body"`
+}
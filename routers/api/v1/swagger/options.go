@@ -169,4 +169,205 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	UserSettingsOptions api.UserSettingsOptions
+
+	// in:body
+	CreateWorkflowRunnerOption api.CreateWorkflowRunnerOption
+
+	// in:body
+	CreateOrUpdateSecretOption api.CreateOrUpdateSecretOption
+
+	// in:body
+	CreateRepoScheduleOption api.CreateRepoScheduleOption
+
+	// in:body
+	EditRepoScheduleOption api.EditRepoScheduleOption
+
+	// in:body
+	CreateIssueFilterOption api.CreateIssueFilterOption
+
+	// in:body
+	EditIssueFilterOption api.EditIssueFilterOption
+
+	// in:body
+	EditSubscriptionOption api.EditSubscriptionOption
+
+	// in:body
+	SetRepoInteractionLimitOption api.SetRepoInteractionLimitOption
+
+	// in:body
+	LockIssueOption api.LockIssueOption
+
+	// in:body
+	CreatePushDeviceOption api.CreatePushDeviceOption
+
+	// in:body
+	EditNotificationChannelOption api.EditNotificationChannelOption
+
+	// in:body
+	BindChatAddressOption api.BindChatAddressOption
+
+	// in:body
+	VerifyChatAddressOption api.VerifyChatAddressOption
+
+	// in:body
+	CreateStarListOption api.CreateStarListOption
+
+	// in:body
+	EditStarListOption api.EditStarListOption
+
+	// in:body
+	EditMaintenanceModeOption api.EditMaintenanceModeOption
+
+	// in:body
+	CreateBackupOption api.CreateBackupOption
+
+	// in:body
+	RestoreBackupOption api.RestoreBackupOption
+
+	// in:body
+	SyncMigrationOptions api.SyncMigrationOptions
+
+	// in:body
+	ReindexIssuesOption api.ReindexIssuesOption
+
+	// in:body
+	SwitchIssueIndexerOption api.SwitchIssueIndexerOption
+
+	// in:body
+	EditVulnerabilityAlertOption api.EditVulnerabilityAlertOption
+
+	// in:body
+	EditVulnerabilityAlertSettingsOption api.EditVulnerabilityAlertSettingsOption
+
+	// in:body
+	EditPushRuleOption api.EditPushRuleOption
+
+	// in:body
+	PushCheckOption api.PushCheckOption
+
+	// in:body
+	CreateStagingSessionOption api.CreateStagingSessionOption
+
+	// in:body
+	EditStagingSessionOption api.EditStagingSessionOption
+
+	// in:body
+	CommitStagingSessionOption api.CommitStagingSessionOption
+
+	// in:body
+	PreviewFileEditOptions api.PreviewFileEditOptions
+
+	// in:body
+	GenerateFilesOptions api.GenerateFilesOptions
+
+	// in:body
+	CreateBackportMappingOption api.CreateBackportMappingOption
+
+	// in:body
+	CreateOrUpdateRepoPagesOption api.CreateOrUpdateRepoPagesOption
+
+	// in:body
+	UpdateAvatarOption api.UpdateAvatarOption
+
+	// in:body
+	SetPinnedReposOption api.SetPinnedReposOption
+
+	// in:body
+	SetPinnedIssuesOption api.SetPinnedIssuesOption
+
+	// in:body
+	CreateIssueWorkflowStateOption api.CreateIssueWorkflowStateOption
+
+	// in:body
+	EditIssueWorkflowStateOption api.EditIssueWorkflowStateOption
+
+	// in:body
+	CreateIssuePriorityOption api.CreateIssuePriorityOption
+
+	// in:body
+	EditIssuePriorityOption api.EditIssuePriorityOption
+
+	// in:body
+	CreateHookScriptOption api.CreateHookScriptOption
+
+	// in:body
+	EditHookScriptOption api.EditHookScriptOption
+
+	// in:body
+	FollowRemoteActorOption api.FollowRemoteActorOption
+
+	// in:body
+	EditRepoFederationOption api.EditRepoFederationOption
+
+	// in:body
+	CreateFederationBlockOption api.CreateFederationBlockOption
+
+	// in:body
+	CreateRepoSettingsJobOption api.CreateRepoSettingsJobOption
+
+	// in:body
+	CreateBranchProtectionTemplateOption api.CreateBranchProtectionTemplateOption
+
+	// in:body
+	EditBranchProtectionTemplateOption api.EditBranchProtectionTemplateOption
+
+	// in:body
+	RenameDefaultBranchOption api.RenameDefaultBranchOption
+
+	// in:body
+	CreateOrUpdateRepoAutoReleaseOption api.CreateOrUpdateRepoAutoReleaseOption
+
+	// in:body
+	ValidateCodeOwnersOption api.ValidateCodeOwnersOption
+
+	// in:body
+	CreateIssueBranchOption api.CreateIssueBranchOption
+
+	// in:body
+	CreateIssueRelationOption api.CreateIssueRelationOption
+
+	// in:body
+	CreateIterationCadenceOption api.CreateIterationCadenceOption
+
+	// in:body
+	EditIterationCadenceOption api.EditIterationCadenceOption
+
+	// in:body
+	CreateCommitOption api.CreateCommitOption
+
+	// in:body
+	CreateCommitsBatchOption api.CreateCommitsBatchOption
+
+	// in:body
+	CreateIssueCustomFieldOption api.CreateIssueCustomFieldOption
+
+	// in:body
+	EditIssueCustomFieldOption api.EditIssueCustomFieldOption
+
+	// in:body
+	SetIssueCustomFieldValuesOption api.SetIssueCustomFieldValuesOption
+
+	// in:body
+	CreateIssueSLAPolicyOption api.CreateIssueSLAPolicyOption
+
+	// in:body
+	EditIssueSLAPolicyOption api.EditIssueSLAPolicyOption
+
+	// in:body
+	CreateGitTreeOption api.CreateGitTreeOption
+
+	// in:body
+	CreateGitBlobOption api.CreateGitBlobOption
+
+	// in:body
+	CreateGitRefOption api.CreateGitRefOption
+
+	// in:body
+	UpdateGitRefOption api.UpdateGitRefOption
+
+	// in:body
+	ApplyCommitOption api.ApplyCommitOption
+
+	// in:body
+	CloneMilestoneOption api.CloneMilestoneOption
 }
@@ -35,3 +35,45 @@ type swaggerResponseTeamList struct {
 	// in:body
 	Body []api.Team `json:"body"`
 }
+
+// AssigneeWorkloadList
+// swagger:response AssigneeWorkloadList
+type swaggerResponseAssigneeWorkloadList struct {
+	// in:body
+	Body []api.AssigneeWorkload `json:"body"`
+}
+
+// RepoSettingsJob
+// swagger:response RepoSettingsJob
+type swaggerResponseRepoSettingsJob struct {
+	// in:body
+	Body api.RepoSettingsJob `json:"body"`
+}
+
+// RepoSettingsJobList
+// swagger:response RepoSettingsJobList
+type swaggerResponseRepoSettingsJobList struct {
+	// in:body
+	Body []api.RepoSettingsJob `json:"body"`
+}
+
+// BranchProtectionTemplate
+// swagger:response BranchProtectionTemplate
+type swaggerResponseBranchProtectionTemplate struct {
+	// in:body
+	Body api.BranchProtectionTemplate `json:"body"`
+}
+
+// BranchProtectionTemplateList
+// swagger:response BranchProtectionTemplateList
+type swaggerResponseBranchProtectionTemplateList struct {
+	// in:body
+	Body []api.BranchProtectionTemplate `json:"body"`
+}
+
+// BranchProtectionDriftList
+// swagger:response BranchProtectionDriftList
+type swaggerResponseBranchProtectionDriftList struct {
+	// in:body
+	Body []api.BranchProtectionDrift `json:"body"`
+}
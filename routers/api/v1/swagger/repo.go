@@ -113,6 +113,20 @@ type swaggerResponseGitHookList struct {
 	Body []api.GitHook `json:"body"`
 }
 
+// HookScript
+// swagger:response HookScript
+type swaggerResponseHookScript struct {
+	// in:body
+	Body api.HookScript `json:"body"`
+}
+
+// HookScriptList
+// swagger:response HookScriptList
+type swaggerResponseHookScriptList struct {
+	// in:body
+	Body []api.HookScript `json:"body"`
+}
+
 // Release
 // swagger:response Release
 type swaggerResponseRelease struct {
@@ -141,6 +155,20 @@ type swaggerResponsePullRequestList struct {
 	Body []api.PullRequest `json:"body"`
 }
 
+// PullRequestMergeMessage
+// swagger:response PullRequestMergeMessage
+type swaggerResponsePullRequestMergeMessage struct {
+	// in:body
+	Body api.PullRequestMergeMessage `json:"body"`
+}
+
+// PullRequestMergeEligibility
+// swagger:response PullRequestMergeEligibility
+type swaggerResponsePullRequestMergeEligibility struct {
+	// in:body
+	Body api.PullRequestMergeEligibility `json:"body"`
+}
+
 // PullReview
 // swagger:response PullReview
 type swaggerResponsePullReview struct {
@@ -225,6 +253,13 @@ type swaggerGitBlobResponse struct {
 	Body api.GitBlobResponse `json:"body"`
 }
 
+// CreatedGitBlob
+// swagger:response CreatedGitBlob
+type swaggerCreatedGitBlob struct {
+	// in: body
+	Body api.CreatedGitBlob `json:"body"`
+}
+
 // Commit
 // swagger:response Commit
 type swaggerCommit struct {
@@ -232,6 +267,27 @@ type swaggerCommit struct {
 	Body api.Commit `json:"body"`
 }
 
+// CommitBackportStatus
+// swagger:response CommitBackportStatus
+type swaggerCommitBackportStatus struct {
+	// in: body
+	Body api.CommitBackportStatus `json:"body"`
+}
+
+// CommitVerificationDetail
+// swagger:response CommitVerificationDetail
+type swaggerCommitVerificationDetail struct {
+	// in: body
+	Body api.CommitVerificationDetail `json:"body"`
+}
+
+// CreatedCommitList
+// swagger:response CreatedCommitList
+type swaggerCreatedCommitList struct {
+	// in: body
+	Body []api.Commit `json:"body"`
+}
+
 // CommitList
 // swagger:response CommitList
 type swaggerCommitList struct {
@@ -282,6 +338,41 @@ type swaggerContentsResponse struct {
 	Body api.ContentsResponse `json:"body"`
 }
 
+// CodeSearchResults
+// swagger:response CodeSearchResults
+type swaggerCodeSearchResults struct {
+	// in: body
+	Body api.CodeSearchResults `json:"body"`
+}
+
+// RenderedDiff
+// swagger:response RenderedDiff
+type swaggerRenderedDiff struct {
+	// in: body
+	Body api.RenderedDiff `json:"body"`
+}
+
+// ReadmeResponse
+// swagger:response ReadmeResponse
+type swaggerReadmeResponse struct {
+	// in: body
+	Body api.ReadmeResponse `json:"body"`
+}
+
+// CodeOwnersValidation
+// swagger:response CodeOwnersValidation
+type swaggerCodeOwnersValidation struct {
+	// in: body
+	Body api.CodeOwnersValidation `json:"body"`
+}
+
+// PullRequestCodeOwners
+// swagger:response PullRequestCodeOwners
+type swaggerPullRequestCodeOwners struct {
+	// in: body
+	Body api.PullRequestCodeOwners `json:"body"`
+}
+
 // ContentsListResponse
 // swagger:response ContentsListResponse
 type swaggerContentsListResponse struct {
@@ -323,3 +414,178 @@ type swaggerCombinedStatus struct {
 	// in: body
 	Body api.CombinedStatus `json:"body"`
 }
+
+// SymbolList
+// swagger:response SymbolList
+type swaggerSymbolList struct {
+	// in: body
+	Body []api.Symbol `json:"body"`
+}
+
+// DependencyList
+// swagger:response DependencyList
+type swaggerDependencyList struct {
+	// in: body
+	Body []api.Dependency `json:"body"`
+}
+
+// CycloneDXBOM
+// swagger:response CycloneDXBOM
+type swaggerCycloneDXBOM struct {
+	// in: body
+	Body api.CycloneDXBOM `json:"body"`
+}
+
+// VulnerabilityAlert
+// swagger:response VulnerabilityAlert
+type swaggerVulnerabilityAlert struct {
+	// in: body
+	Body api.VulnerabilityAlert `json:"body"`
+}
+
+// VulnerabilityAlertList
+// swagger:response VulnerabilityAlertList
+type swaggerVulnerabilityAlertList struct {
+	// in: body
+	Body []api.VulnerabilityAlert `json:"body"`
+}
+
+// VulnerabilityAlertSettings
+// swagger:response VulnerabilityAlertSettings
+type swaggerVulnerabilityAlertSettings struct {
+	// in: body
+	Body api.VulnerabilityAlertSettings `json:"body"`
+}
+
+// PushRule
+// swagger:response PushRule
+type swaggerPushRule struct {
+	// in: body
+	Body api.PushRule `json:"body"`
+}
+
+// PushCheckResult
+// swagger:response PushCheckResult
+type swaggerPushCheckResult struct {
+	// in: body
+	Body api.PushCheckResult `json:"body"`
+}
+
+// StagingSession
+// swagger:response StagingSession
+type swaggerStagingSession struct {
+	// in: body
+	Body api.StagingSession `json:"body"`
+}
+
+// StagingSessionList
+// swagger:response StagingSessionList
+type swaggerStagingSessionList struct {
+	// in: body
+	Body []api.StagingSession `json:"body"`
+}
+
+// FileEditPreview
+// swagger:response FileEditPreview
+type swaggerFileEditPreview struct {
+	// in: body
+	Body api.FileEditPreview `json:"body"`
+}
+
+// FilePreview
+// swagger:response FilePreview
+type swaggerFilePreview struct {
+	// in: body
+	Body api.FilePreview `json:"body"`
+}
+
+// RenderedFile
+// swagger:response RenderedFile
+type swaggerRenderedFile struct {
+	// in: body
+	Body api.RenderedFile `json:"body"`
+}
+
+// SymbolReferenceList
+// swagger:response SymbolReferenceList
+type swaggerSymbolReferenceList struct {
+	// in: body
+	Body []api.SymbolReference `json:"body"`
+}
+
+// Mentionables
+// swagger:response Mentionables
+type swaggerMentionables struct {
+	// in: body
+	Body api.Mentionables `json:"body"`
+}
+
+// RepoCollaboratorPermission
+// swagger:response RepoCollaboratorPermission
+type swaggerRepoCollaboratorPermission struct {
+	// in: body
+	Body api.RepoCollaboratorPermission `json:"body"`
+}
+
+// RepoInteractionLimit
+// swagger:response RepoInteractionLimit
+type swaggerRepoInteractionLimit struct {
+	// in: body
+	Body api.RepoInteractionLimit `json:"body"`
+}
+
+// TrendingRepositoryList
+// swagger:response TrendingRepositoryList
+type swaggerTrendingRepositoryList struct {
+	// in: body
+	Body []api.TrendingRepository `json:"body"`
+}
+
+// MigrationDryRun
+// swagger:response MigrationDryRun
+type swaggerResponseMigrationDryRun struct {
+	// in:body
+	Body api.MigrationDryRun `json:"body"`
+}
+
+// RedirectList
+// swagger:response RedirectList
+type swaggerResponseRedirectList struct {
+	// in:body
+	Body []api.Redirect `json:"body"`
+}
+
+// RepoFederation
+// swagger:response RepoFederation
+type swaggerRepoFederation struct {
+	// in:body
+	Body api.RepoFederation `json:"body"`
+}
+
+// RemoteStarList
+// swagger:response RemoteStarList
+type swaggerRemoteStarList struct {
+	// in:body
+	Body []api.RemoteStar `json:"body"`
+}
+
+// FederationBlock
+// swagger:response FederationBlock
+type swaggerFederationBlock struct {
+	// in:body
+	Body api.FederationBlock `json:"body"`
+}
+
+// FederationBlockList
+// swagger:response FederationBlockList
+type swaggerFederationBlockList struct {
+	// in:body
+	Body []api.FederationBlock `json:"body"`
+}
+
+// RepoAutoRelease
+// swagger:response RepoAutoRelease
+type swaggerRepoAutoRelease struct {
+	// in:body
+	Body api.RepoAutoRelease `json:"body"`
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// RepoSchedule
+// swagger:response RepoSchedule
+type swaggerResponseRepoSchedule struct {
+	// in:body
+	Body api.RepoSchedule `json:"body"`
+}
+
+// RepoScheduleList
+// swagger:response RepoScheduleList
+type swaggerResponseRepoScheduleList struct {
+	// in:body
+	Body []api.RepoSchedule `json:"body"`
+}
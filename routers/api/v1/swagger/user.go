@@ -49,3 +49,80 @@ type swaggerResponseUserSettings struct {
 	// in:body
 	Body []api.UserSettings `json:"body"`
 }
+
+// PushConfig
+// swagger:response PushConfig
+type swaggerResponsePushConfig struct {
+	// in:body
+	Body api.PushConfig `json:"body"`
+}
+
+// PushDevice
+// swagger:response PushDevice
+type swaggerResponsePushDevice struct {
+	// in:body
+	Body api.PushDevice `json:"body"`
+}
+
+// PushDeviceList
+// swagger:response PushDeviceList
+type swaggerResponsePushDeviceList struct {
+	// in:body
+	Body []api.PushDevice `json:"body"`
+}
+
+// ChatAddress
+// swagger:response ChatAddress
+type swaggerResponseChatAddress struct {
+	// in:body
+	Body api.ChatAddress `json:"body"`
+}
+
+// ChatAddressList
+// swagger:response ChatAddressList
+type swaggerResponseChatAddressList struct {
+	// in:body
+	Body []api.ChatAddress `json:"body"`
+}
+
+// StarList
+// swagger:response StarList
+type swaggerResponseStarList struct {
+	// in:body
+	Body api.StarList `json:"body"`
+}
+
+// StarListList
+// swagger:response StarListList
+type swaggerResponseStarListList struct {
+	// in:body
+	Body []api.StarList `json:"body"`
+}
+
+// ProfileReadme
+// swagger:response ProfileReadme
+type swaggerResponseProfileReadme struct {
+	// in:body
+	Body api.ProfileReadme `json:"body"`
+}
+
+// FederationFollowing
+// swagger:response FederationFollowing
+type swaggerResponseFederationFollowing struct {
+	// in:body
+	Body api.FederationFollowing `json:"body"`
+}
+
+// FederationFollowingList
+// swagger:response FederationFollowingList
+type swaggerResponseFederationFollowingList struct {
+	// in:body
+	Body []api.FederationFollowing `json:"body"`
+}
+
+// FederationActivityList
+// swagger:response FederationActivityList
+type swaggerResponseFederationActivityList struct {
+	// in:body
+	Body []api.FederationActivity `json:"body"`
+}
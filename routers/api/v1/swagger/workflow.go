@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// WorkflowRun
+// swagger:response WorkflowRun
+type swaggerResponseWorkflowRun struct {
+	// in:body
+	Body api.WorkflowRun `json:"body"`
+}
+
+// WorkflowRunList
+// swagger:response WorkflowRunList
+type swaggerResponseWorkflowRunList struct {
+	// in:body
+	Body []api.WorkflowRun `json:"body"`
+}
+
+// WorkflowRunner
+// swagger:response WorkflowRunner
+type swaggerResponseWorkflowRunner struct {
+	// in:body
+	Body api.WorkflowRunner `json:"body"`
+}
+
+// WorkflowRunnerList
+// swagger:response WorkflowRunnerList
+type swaggerResponseWorkflowRunnerList struct {
+	// in:body
+	Body []api.WorkflowRunner `json:"body"`
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// UpdateAvatar sets the avatar of the authenticated user
+func UpdateAvatar(ctx *context.APIContext) {
+	// swagger:operation POST /user/avatar user userUpdateAvatar
+	// ---
+	// summary: Set the avatar of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdateAvatarOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.UpdateAvatarOption)
+	data, crop, ok := utils.DecodeAvatarOption(ctx, form)
+	if !ok {
+		return
+	}
+
+	if err := ctx.User.UploadCroppedAvatar(data, crop); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UploadCroppedAvatar", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteAvatar resets the avatar of the authenticated user
+func DeleteAvatar(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/avatar user userDeleteAvatar
+	// ---
+	// summary: Reset the avatar of the authenticated user
+	// produces:
+	// - application/json
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+
+	if err := ctx.User.DeleteAvatar(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAvatar", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/chat"
+)
+
+// ListChatAddresses lists the chat addresses of the authenticated user
+func ListChatAddresses(ctx *context.APIContext) {
+	// swagger:operation GET /user/chat-addresses user userListChatAddresses
+	// ---
+	// summary: List the authenticated user's chat addresses
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChatAddressList"
+	addresses, err := models.GetChatAddresses(ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetChatAddresses", err)
+		return
+	}
+
+	apiAddresses := make([]*api.ChatAddress, len(addresses))
+	for i := range addresses {
+		apiAddresses[i] = convert.ToChatAddress(addresses[i])
+	}
+	ctx.JSON(http.StatusOK, &apiAddresses)
+}
+
+// BindChatAddress sets the address of the authenticated user on a chat network
+func BindChatAddress(ctx *context.APIContext) {
+	// swagger:operation PUT /user/chat-addresses/{protocol} user userBindChatAddress
+	// ---
+	// summary: Set the authenticated user's address on a chat network. A verification code is sent to new addresses.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: protocol
+	//   in: path
+	//   description: chat network of the address
+	//   type: string
+	//   enum: [xmpp, irc, matrix]
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/BindChatAddressOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChatAddress"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	protocol, sender := getChatSender(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.BindChatAddressOption)
+	if err := sender.ValidateAddress(form.Address); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	chatAddress, err := models.BindChatAddress(ctx.User.ID, protocol, form.Address)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "BindChatAddress", err)
+		return
+	}
+	if !chatAddress.IsVerified {
+		chat.SendVerificationCode(ctx.User, chatAddress)
+	}
+	ctx.JSON(http.StatusOK, convert.ToChatAddress(chatAddress))
+}
+
+// VerifyChatAddress verifies the address of the authenticated user on a chat network
+func VerifyChatAddress(ctx *context.APIContext) {
+	// swagger:operation POST /user/chat-addresses/{protocol}/verify user userVerifyChatAddress
+	// ---
+	// summary: Verify the authenticated user's address on a chat network with the code sent to it
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: protocol
+	//   in: path
+	//   description: chat network of the address
+	//   type: string
+	//   enum: [xmpp, irc, matrix]
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/VerifyChatAddressOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChatAddress"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	protocol, ok := models.ChatProtocolFromString(ctx.Params(":protocol"))
+	if !ok {
+		ctx.NotFound()
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.VerifyChatAddressOption)
+	chatAddress, err := models.VerifyChatAddress(ctx.User.ID, protocol, form.Code)
+	if err != nil {
+		switch err {
+		case models.ErrChatAddressNotExist:
+			ctx.NotFound()
+		case models.ErrChatAddressVerificationFailed:
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "VerifyChatAddress", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToChatAddress(chatAddress))
+}
+
+// DeleteChatAddress removes the address of the authenticated user on a chat network
+func DeleteChatAddress(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/chat-addresses/{protocol} user userDeleteChatAddress
+	// ---
+	// summary: Remove the authenticated user's address on a chat network
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: protocol
+	//   in: path
+	//   description: chat network of the address
+	//   type: string
+	//   enum: [xmpp, irc, matrix]
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	protocol, ok := models.ChatProtocolFromString(ctx.Params(":protocol"))
+	if !ok {
+		ctx.NotFound()
+		return
+	}
+
+	if err := models.DeleteChatAddress(ctx.User.ID, protocol); err != nil {
+		if err == models.ErrChatAddressNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteChatAddress", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// getChatSender returns the chat network of the request if messages can be delivered to it
+func getChatSender(ctx *context.APIContext) (models.ChatProtocol, chat.Sender) {
+	protocol, ok := models.ChatProtocolFromString(ctx.Params(":protocol"))
+	if !ok {
+		ctx.NotFound()
+		return 0, nil
+	}
+	sender, ok := chat.GetSender(protocol)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "", "chat network is not configured")
+		return 0, nil
+	}
+	return protocol, sender
+}
@@ -0,0 +1,247 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListMyReviewRequests list the pull requests requesting a review of the authenticated user
+func ListMyReviewRequests(ctx *context.APIContext) {
+	// swagger:operation GET /user/issues/review_requested user userListReviewRequests
+	// ---
+	// summary: List the pull requests requesting a review of the authenticated user or one of their teams
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: state
+	//   in: query
+	//   description: whether the pull requests are open (default), closed or all
+	//   type: string
+	//   enum: [open, closed, all]
+	// - name: sort
+	//   in: query
+	//   description: sort order of the results, recentupdate by default
+	//   type: string
+	//   enum: [newest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, nearduedate, farduedate]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+
+	listMyIssues(ctx, &models.IssuesOptions{ReviewRequestedID: ctx.User.ID, IsPull: util.OptionalBoolTrue})
+}
+
+// ListMyAssignedIssues list the issues and pull requests assigned to the authenticated user
+func ListMyAssignedIssues(ctx *context.APIContext) {
+	// swagger:operation GET /user/issues/assigned user userListAssignedIssues
+	// ---
+	// summary: List the issues and pull requests assigned to the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: state
+	//   in: query
+	//   description: whether the issues are open (default), closed or all
+	//   type: string
+	//   enum: [open, closed, all]
+	// - name: type
+	//   in: query
+	//   description: filter by type (issues / pulls) if set
+	//   type: string
+	//   enum: [issues, pulls]
+	// - name: sort
+	//   in: query
+	//   description: sort order of the results, recentupdate by default
+	//   type: string
+	//   enum: [newest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, nearduedate, farduedate]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+
+	listMyIssues(ctx, &models.IssuesOptions{AssigneeID: ctx.User.ID, IsPull: issueTypeFilter(ctx)})
+}
+
+// ListMyMentionedIssues list the issues and pull requests mentioning the authenticated user
+func ListMyMentionedIssues(ctx *context.APIContext) {
+	// swagger:operation GET /user/issues/mentioned user userListMentionedIssues
+	// ---
+	// summary: List the issues and pull requests mentioning the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: state
+	//   in: query
+	//   description: whether the issues are open (default), closed or all
+	//   type: string
+	//   enum: [open, closed, all]
+	// - name: type
+	//   in: query
+	//   description: filter by type (issues / pulls) if set
+	//   type: string
+	//   enum: [issues, pulls]
+	// - name: sort
+	//   in: query
+	//   description: sort order of the results, recentupdate by default
+	//   type: string
+	//   enum: [newest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, nearduedate, farduedate]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+
+	listMyIssues(ctx, &models.IssuesOptions{MentionedID: ctx.User.ID, IsPull: issueTypeFilter(ctx)})
+}
+
+// ListMyParticipatedIssues list the issues and pull requests the authenticated user opened or commented on
+func ListMyParticipatedIssues(ctx *context.APIContext) {
+	// swagger:operation GET /user/issues/participated user userListParticipatedIssues
+	// ---
+	// summary: List the issues and pull requests the authenticated user opened or commented on, the recently updated first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: state
+	//   in: query
+	//   description: whether the issues are open (default), closed or all
+	//   type: string
+	//   enum: [open, closed, all]
+	// - name: type
+	//   in: query
+	//   description: filter by type (issues / pulls) if set
+	//   type: string
+	//   enum: [issues, pulls]
+	// - name: sort
+	//   in: query
+	//   description: sort order of the results, recentupdate by default
+	//   type: string
+	//   enum: [newest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, nearduedate, farduedate]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+
+	listMyIssues(ctx, &models.IssuesOptions{ParticipantID: ctx.User.ID, IsPull: issueTypeFilter(ctx)})
+}
+
+func issueTypeFilter(ctx *context.APIContext) util.OptionalBool {
+	switch ctx.FormString("type") {
+	case "pulls":
+		return util.OptionalBoolTrue
+	case "issues":
+		return util.OptionalBoolFalse
+	default:
+		return util.OptionalBoolNone
+	}
+}
+
+// listMyIssues responds with a page of the issues matching opts in all repositories the user may read,
+// the issues and their repositories are loaded in batches rather than one by one
+func listMyIssues(ctx *context.APIContext, opts *models.IssuesOptions) {
+	switch ctx.FormString("state") {
+	case "closed":
+		opts.IsClosed = util.OptionalBoolTrue
+	case "all":
+		opts.IsClosed = util.OptionalBoolNone
+	default:
+		opts.IsClosed = util.OptionalBoolFalse
+	}
+	opts.SortType = ctx.FormString("sort")
+	if opts.SortType == "" {
+		opts.SortType = "recentupdate"
+	}
+
+	repoIDs, _, err := models.SearchRepositoryIDs(&models.SearchRepoOptions{
+		Actor:       ctx.User,
+		Private:     true,
+		AllPublic:   true,
+		AllLimited:  true,
+		Collaborate: util.OptionalBoolNone,
+		OrderBy:     models.SearchOrderByAlphabetically,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchRepositoryIDs", err)
+		return
+	}
+	if len(repoIDs) == 0 {
+		// no repositories would mean all of them to the issue search
+		ctx.SetTotalCountHeader(0)
+		ctx.JSON(http.StatusOK, convert.ToAPIIssueList(nil))
+		return
+	}
+	opts.RepoIDs = repoIDs
+
+	listOptions := utils.GetListOptions(ctx)
+	opts.ListOptions = listOptions
+	issues, err := models.Issues(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Issues", err)
+		return
+	}
+	opts.ListOptions = models.ListOptions{Page: -1}
+	count, err := models.CountIssues(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountIssues", err)
+		return
+	}
+
+	// the issues share the loaded repositories, load all of their owners at once
+	repos := make(models.RepositoryList, 0, len(issues))
+	seen := make(map[int64]bool, len(issues))
+	for _, issue := range issues {
+		if issue.Repo != nil && !seen[issue.RepoID] {
+			seen[issue.RepoID] = true
+			repos = append(repos, issue.Repo)
+		}
+	}
+	if err := repos.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+
+	apiIssues := utils.ToAPIIssueList(ctx, issues)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiIssues)
+}
@@ -0,0 +1,174 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/federation"
+)
+
+// ListFederationFollowing lists the remote actors followed by the authenticated user
+func ListFederationFollowing(ctx *context.APIContext) {
+	// swagger:operation GET /user/federation/following user userListFederationFollowing
+	// ---
+	// summary: List the remote actors followed by the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FederationFollowingList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	followings, count, err := models.GetFederationFollowings(ctx.User.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFederationFollowings", err)
+		return
+	}
+
+	apiFollowings := make([]*api.FederationFollowing, len(followings))
+	for i := range followings {
+		if err := followings[i].LoadRemoteActor(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadRemoteActor", err)
+			return
+		}
+		apiFollowings[i] = convert.ToFederationFollowing(followings[i])
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiFollowings)
+}
+
+// FollowRemoteActor follows a remote actor
+func FollowRemoteActor(ctx *context.APIContext) {
+	// swagger:operation POST /user/federation/following user userFollowRemoteActor
+	// ---
+	// summary: Follow a user or repository of another instance. Its activities are received once the instance accepted the follow.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/FollowRemoteActorOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FederationFollowing"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.FollowRemoteActorOption)
+	following, err := federation.FollowRemote(ctx, ctx.User, form.Actor)
+	if err != nil {
+		if activitypub.IsErrBlockedURL(err) || federation.IsErrResolveActor(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "FollowRemote", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToFederationFollowing(following))
+}
+
+// UnfollowRemoteActor stops following a remote actor
+func UnfollowRemoteActor(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/federation/following/{id} user userUnfollowRemoteActor
+	// ---
+	// summary: Stop following a remote actor
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the following
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	following, err := models.GetFederationFollowingByID(ctx.User.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrFederationFollowingNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetFederationFollowingByID", err)
+		}
+		return
+	}
+	if err := federation.UnfollowRemote(ctx.User, following); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnfollowRemote", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListFederationFeed lists the activities received from the remote actors followed by the authenticated user
+func ListFederationFeed(ctx *context.APIContext) {
+	// swagger:operation GET /user/federation/feed user userListFederationFeed
+	// ---
+	// summary: List the activities received from the remote actors followed by the authenticated user, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FederationActivityList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	activities, count, err := models.FindFederationActivities(&models.FindFederationActivitiesOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OwnerType:   models.FederationOwnerUser,
+		OwnerID:     ctx.User.ID,
+		Received:    true,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindFederationActivities", err)
+		return
+	}
+
+	apiActivities := make([]*api.FederationActivity, len(activities))
+	for i := range activities {
+		if err := activities[i].LoadRemoteActor(); err != nil && !models.IsErrRemoteActorNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "LoadRemoteActor", err)
+			return
+		}
+		apiActivities[i] = convert.ToFederationActivity(activities[i])
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiActivities)
+}
@@ -0,0 +1,178 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/repo"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListIssueFilters list the saved issue filters of the authenticated user
+func ListIssueFilters(ctx *context.APIContext) {
+	// swagger:operation GET /user/issue_filters user userListIssueFilters
+	// ---
+	// summary: List the saved issue filters of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilterList"
+
+	utils.ListIssueFilters(ctx, ctx.User.ID)
+}
+
+// GetIssueFilter get a saved issue filter of the authenticated user
+func GetIssueFilter(ctx *context.APIContext) {
+	// swagger:operation GET /user/issue_filters/{id} user userGetIssueFilter
+	// ---
+	// summary: Get a saved issue filter of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the issue filter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetIssueFilter(ctx, ctx.User.ID)
+}
+
+// CreateIssueFilter save an issue filter for the authenticated user
+func CreateIssueFilter(ctx *context.APIContext) {
+	// swagger:operation POST /user/issue_filters user userCreateIssueFilter
+	// ---
+	// summary: Save an issue filter for the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueFilterOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.CreateIssueFilter(ctx, ctx.User.ID, web.GetForm(ctx).(*api.CreateIssueFilterOption))
+}
+
+// EditIssueFilter modify a saved issue filter of the authenticated user
+func EditIssueFilter(ctx *context.APIContext) {
+	// swagger:operation PATCH /user/issue_filters/{id} user userEditIssueFilter
+	// ---
+	// summary: Edit a saved issue filter of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the issue filter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueFilterOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.EditIssueFilter(ctx, ctx.User.ID, web.GetForm(ctx).(*api.EditIssueFilterOption))
+}
+
+// DeleteIssueFilter delete a saved issue filter of the authenticated user
+func DeleteIssueFilter(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/issue_filters/{id} user userDeleteIssueFilter
+	// ---
+	// summary: Delete a saved issue filter of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the issue filter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteIssueFilter(ctx, ctx.User.ID)
+}
+
+// SearchIssueFilter run a saved issue filter of the authenticated user
+func SearchIssueFilter(ctx *context.APIContext) {
+	// swagger:operation GET /user/issue_filters/{id}/issues user userSearchIssueFilter
+	// ---
+	// summary: Search for issues and pull requests matching a saved issue filter of the authenticated user
+	// description: The saved query is run like the issue search of all repositories the user may read.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the issue filter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.PrepareIssueFilterSearch(ctx, ctx.User.ID, "")
+	if ctx.Written() {
+		return
+	}
+	repo.SearchIssues(ctx)
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListPinnedRepos lists the repositories pinned on the profile of a user or an organization
+func ListPinnedRepos(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/pinned_repos user userListPinnedRepos
+	// ---
+	// summary: List the repositories pinned on the profile of a user or an organization, in their order
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	u := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !u.IsVisibleToUser(ctx.User) {
+		// fake ErrUserNotExist error message to not leak information about existence
+		ctx.NotFound("GetUserByName", models.ErrUserNotExist{Name: ctx.Params(":username")})
+		return
+	}
+	utils.ListPinnedRepos(ctx, u.ID)
+}
+
+// SetMyPinnedRepos sets the repositories pinned on the profile of the authenticated user
+func SetMyPinnedRepos(ctx *context.APIContext) {
+	// swagger:operation PUT /user/pinned_repos user userCurrentSetPinnedRepos
+	// ---
+	// summary: Set the repositories pinned on the profile of the authenticated user, in their order
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetPinnedReposOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.SetPinnedRepos(ctx, ctx.User.ID, web.GetForm(ctx).(*api.SetPinnedReposOption))
+}
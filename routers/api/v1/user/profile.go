@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	profile_service "code.gitea.io/gitea/services/profile"
+)
+
+// GetProfileReadme gets the rendered profile README of a user or an organization
+func GetProfileReadme(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/profile_readme user userGetProfileReadme
+	// ---
+	// summary: Get the rendered README of the public `.profile` repository of a user or an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProfileReadme"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	u := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !u.IsVisibleToUser(ctx.User) {
+		// fake ErrUserNotExist error message to not leak information about existence
+		ctx.NotFound("GetUserByName", models.ErrUserNotExist{Name: ctx.Params(":username")})
+		return
+	}
+
+	repo, err := profile_service.GetProfileRepo(u)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProfileRepo", err)
+		return
+	}
+	if repo == nil {
+		ctx.NotFound()
+		return
+	}
+	html, err := profile_service.GetProfileReadme(ctx, u)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProfileReadme", err)
+		return
+	}
+	if html == "" {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.ProfileReadme{
+		Repository: repo.FullName(),
+		HTMLURL:    repo.HTMLURL(),
+		HTML:       html,
+	})
+}
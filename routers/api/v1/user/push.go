@@ -0,0 +1,235 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/push"
+)
+
+// GetPushConfig returns the settings needed for registering push devices
+func GetPushConfig(ctx *context.APIContext) {
+	// swagger:operation GET /user/push/config user userGetPushConfig
+	// ---
+	// summary: Get the settings needed for registering push devices
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushConfig"
+	config := &api.PushConfig{Enabled: setting.Push.Enabled}
+	if config.Enabled {
+		config.VapidPublicKey = setting.Push.VapidPublicKey
+		config.GatewayEnabled = push.IsSupportedDeviceType(models.PushDeviceTypeGateway)
+	}
+	ctx.JSON(http.StatusOK, config)
+}
+
+// ListPushDevices lists the push devices of the authenticated user
+func ListPushDevices(ctx *context.APIContext) {
+	// swagger:operation GET /user/push/devices user userListPushDevices
+	// ---
+	// summary: List the authenticated user's push devices
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushDeviceList"
+	devices, err := models.GetPushDevices(ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPushDevices", err)
+		return
+	}
+
+	apiDevices := make([]*api.PushDevice, len(devices))
+	for i := range devices {
+		apiDevices[i] = convert.ToPushDevice(devices[i])
+	}
+	ctx.JSON(http.StatusOK, &apiDevices)
+}
+
+// RegisterPushDevice registers a device receiving push notifications for the authenticated user
+func RegisterPushDevice(ctx *context.APIContext) {
+	// swagger:operation POST /user/push/devices user userRegisterPushDevice
+	// ---
+	// summary: Register a device receiving push notifications. Registering a known device again updates it.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePushDeviceOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PushDevice"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if !setting.Push.Enabled {
+		ctx.NotFound()
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.CreatePushDeviceOption)
+	deviceType, ok := models.PushDeviceTypeFromString(form.Type)
+	if !ok || !push.IsSupportedDeviceType(deviceType) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "unsupported device type")
+		return
+	}
+
+	device := &models.PushDevice{
+		UserID: ctx.User.ID,
+		Type:   deviceType,
+		Name:   form.Name,
+	}
+	switch deviceType {
+	case models.PushDeviceTypeWeb:
+		if u, err := url.Parse(form.Endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "endpoint must be an https URL")
+			return
+		}
+		if form.Keys == nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", "keys of the subscription are required")
+			return
+		}
+		if err := push.ValidateWebPushKeys(form.Keys.P256DH, form.Keys.Auth); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		device.Endpoint = form.Endpoint
+		device.P256DH = form.Keys.P256DH
+		device.Auth = form.Keys.Auth
+	case models.PushDeviceTypeGateway:
+		if form.Token == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "token is required")
+			return
+		}
+		device.Token = form.Token
+		device.Platform = form.Platform
+	}
+
+	if err := models.RegisterPushDevice(device); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RegisterPushDevice", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToPushDevice(device))
+}
+
+// DeletePushDevice removes a push device of the authenticated user
+func DeletePushDevice(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/push/devices/{id} user userDeletePushDevice
+	// ---
+	// summary: Remove a push device
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the device to remove
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	if err := models.DeletePushDevice(ctx.User.ID, ctx.ParamsInt64(":id")); err != nil {
+		if err == models.ErrPushDeviceNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeletePushDevice", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListNotificationChannels lists the issue events the authenticated user receives through each notification channel
+func ListNotificationChannels(ctx *context.APIContext) {
+	// swagger:operation GET /user/notification-channels user userListNotificationChannels
+	// ---
+	// summary: List the issue events the authenticated user receives by mail and push notifications
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationChannelList"
+	events, err := models.GetNotificationChannelEvents(ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetNotificationChannelEvents", err)
+		return
+	}
+
+	channels := make([]*api.NotificationChannel, 0, len(models.NotificationChannels))
+	for _, c := range models.NotificationChannels {
+		channels = append(channels, convert.ToNotificationChannel(c, events[c]))
+	}
+	ctx.JSON(http.StatusOK, &channels)
+}
+
+// EditNotificationChannel changes the issue events the authenticated user receives through a notification channel
+func EditNotificationChannel(ctx *context.APIContext) {
+	// swagger:operation PUT /user/notification-channels/{channel} user userEditNotificationChannel
+	// ---
+	// summary: Change the issue events the authenticated user receives through a notification channel
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: channel
+	//   in: path
+	//   description: name of the channel
+	//   type: string
+	//   enum: [mail, push]
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditNotificationChannelOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationChannel"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	channel, ok := models.NotificationChannelFromString(ctx.Params(":channel"))
+	if !ok {
+		ctx.NotFound()
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditNotificationChannelOption)
+	if form.Events == nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("events are required"))
+		return
+	}
+	events, err := models.ParseSubscriptionEvents(form.Events)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if err := models.SetNotificationChannelEvents(ctx.User.ID, channel, events); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetNotificationChannelEvents", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToNotificationChannel(channel, events))
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListRedirects list the old names which redirect to a user or an organization
+func ListRedirects(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/redirects user userListRedirects
+	// ---
+	// summary: List the old names of a renamed user or organization, which redirect to it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RedirectList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	u := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !u.IsVisibleToUser(ctx.User) {
+		// fake ErrUserNotExist error message to not leak information about existence
+		ctx.NotFound("GetUserByName", models.ErrUserNotExist{Name: ctx.Params(":username")})
+		return
+	}
+
+	redirects, err := models.FindUserRedirects(u.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindUserRedirects", err)
+		return
+	}
+
+	apiRedirects := make([]*api.Redirect, 0, len(redirects))
+	for _, redirect := range redirects {
+		apiRedirects = append(apiRedirects, convert.ToUserRedirect(redirect, u))
+	}
+	ctx.JSON(http.StatusOK, &apiRedirects)
+}
@@ -43,7 +43,7 @@ func listUserRepos(ctx *context.APIContext, u *models.User, private bool) {
 
 	ctx.SetLinkHeader(int(count), opts.PageSize)
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, &apiRepos)
+	ctx.JSONWithFields(http.StatusOK, apiRepos)
 }
 
 // ListUserRepos - list the repos owned by the given user.
@@ -67,6 +67,10 @@ func ListUserRepos(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the repositories to return, e.g. full_name,owner.login,stars_count. All fields are returned if not given
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
@@ -95,6 +99,10 @@ func ListMyRepos(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the repositories to return, e.g. full_name,owner.login,stars_count. All fields are returned if not given
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
@@ -129,7 +137,7 @@ func ListMyRepos(ctx *context.APIContext) {
 
 	ctx.SetLinkHeader(int(count), opts.ListOptions.PageSize)
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, &results)
+	ctx.JSONWithFields(http.StatusOK, results)
 }
 
 // ListOrgRepos - list the repositories of an organization.
@@ -153,6 +161,10 @@ func ListOrgRepos(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: fields
+	//   in: query
+	//   description: comma separated list of the fields of the repositories to return, e.g. full_name,owner.login,stars_count. All fields are returned if not given
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
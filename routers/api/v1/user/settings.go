@@ -5,6 +5,7 @@
 package user
 
 import (
+	"fmt"
 	"net/http"
 
 	"code.gitea.io/gitea/models"
@@ -42,6 +43,8 @@ func UpdateUserSettings(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/UserSettings"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.UserSettingsOptions)
 
@@ -74,6 +77,21 @@ func UpdateUserSettings(ctx *context.APIContext) {
 		ctx.User.KeepActivityPrivate = *form.HideActivity
 	}
 
+	if form.AutoWatchOnPush != nil {
+		if !models.IsValidAutoWatchOnPush(*form.AutoWatchOnPush) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid auto_watch_on_push: %s", *form.AutoWatchOnPush))
+			return
+		}
+		ctx.User.AutoWatchOnPush = *form.AutoWatchOnPush
+	}
+	if form.AutoWatchOnMention != nil {
+		if !models.IsValidAutoWatchOnMention(*form.AutoWatchOnMention) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid auto_watch_on_mention: %s", *form.AutoWatchOnMention))
+			return
+		}
+		ctx.User.AutoWatchOnMention = *form.AutoWatchOnMention
+	}
+
 	if err := models.UpdateUser(ctx.User); err != nil {
 		ctx.InternalServerError(err)
 		return
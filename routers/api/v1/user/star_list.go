@@ -0,0 +1,442 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListMyStarLists lists the star lists of the authenticated user
+func ListMyStarLists(ctx *context.APIContext) {
+	// swagger:operation GET /user/star-lists user userCurrentListStarLists
+	// ---
+	// summary: List the star lists of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StarListList"
+
+	listStarLists(ctx, ctx.User)
+}
+
+// ListUserStarLists lists the star lists of a user visible to the authenticated user
+func ListUserStarLists(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/star-lists user userListStarLists
+	// ---
+	// summary: List the star lists of a user. Private lists are only listed for their owner.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StarListList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	user := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	listStarLists(ctx, user)
+}
+
+func listStarLists(ctx *context.APIContext, user *models.User) {
+	lists, count, err := models.FindStarLists(user.ID, canSeePrivateStarLists(ctx, user), utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindStarLists", err)
+		return
+	}
+
+	apiLists := make([]*api.StarList, len(lists))
+	for i := range lists {
+		apiLists[i] = convert.ToStarList(lists[i])
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiLists)
+}
+
+// GetMyStarList gets a star list of the authenticated user
+func GetMyStarList(ctx *context.APIContext) {
+	// swagger:operation GET /user/star-lists/{id} user userCurrentGetStarList
+	// ---
+	// summary: Get a star list of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StarList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	list := getStarList(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStarList(list))
+}
+
+// CreateStarList creates a star list for the authenticated user
+func CreateStarList(ctx *context.APIContext) {
+	// swagger:operation POST /user/star-lists user userCurrentCreateStarList
+	// ---
+	// summary: Create a star list for the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateStarListOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/StarList"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateStarListOption)
+	list := &models.StarList{
+		UserID:      ctx.User.ID,
+		Name:        form.Name,
+		Description: form.Description,
+		IsPrivate:   form.Private,
+	}
+	if err := models.CreateStarList(list); err != nil {
+		handleStarListError(ctx, "CreateStarList", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToStarList(list))
+}
+
+// EditStarList modifies a star list of the authenticated user
+func EditStarList(ctx *context.APIContext) {
+	// swagger:operation PATCH /user/star-lists/{id} user userCurrentEditStarList
+	// ---
+	// summary: Edit a star list of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditStarListOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StarList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	list := getStarList(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditStarListOption)
+	if form.Name != nil {
+		list.Name = *form.Name
+	}
+	if form.Description != nil {
+		list.Description = *form.Description
+	}
+	if form.Private != nil {
+		list.IsPrivate = *form.Private
+	}
+	if err := models.UpdateStarList(list); err != nil {
+		handleStarListError(ctx, "UpdateStarList", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStarList(list))
+}
+
+// DeleteStarList deletes a star list of the authenticated user
+func DeleteStarList(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/star-lists/{id} user userCurrentDeleteStarList
+	// ---
+	// summary: Delete a star list of the authenticated user. The repositories in it stay starred.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if err := models.DeleteStarList(ctx.User.ID, ctx.ParamsInt64(":id")); err != nil {
+		handleStarListError(ctx, "DeleteStarList", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListMyStarListRepos lists the repositories in a star list of the authenticated user
+func ListMyStarListRepos(ctx *context.APIContext) {
+	// swagger:operation GET /user/star-lists/{id}/repos user userCurrentListStarListRepos
+	// ---
+	// summary: List the repositories in a star list of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listStarListRepos(ctx, ctx.User)
+}
+
+// ListUserStarListRepos lists the repositories in a star list of a user
+func ListUserStarListRepos(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/star-lists/{id}/repos user userListStarListRepos
+	// ---
+	// summary: List the repositories in a star list of a user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	user := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	listStarListRepos(ctx, user)
+}
+
+func listStarListRepos(ctx *context.APIContext, user *models.User) {
+	list := getStarList(ctx, user)
+	if ctx.Written() {
+		return
+	}
+
+	repos, count, err := models.GetStarListRepos(list, ctx.User.ID == user.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStarListRepos", err)
+		return
+	}
+
+	apiRepos := make([]*api.Repository, len(repos))
+	for i, repo := range repos {
+		access, err := models.AccessLevel(ctx.User, repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiRepos[i] = convert.ToRepo(repo, access)
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiRepos)
+}
+
+// AddRepoToStarList adds a repository to a star list of the authenticated user
+func AddRepoToStarList(ctx *context.APIContext) {
+	// swagger:operation PUT /user/star-lists/{id}/repos/{owner}/{repo} user userCurrentAddRepoToStarList
+	// ---
+	// summary: Add a repository to a star list of the authenticated user, starring it if needed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	list := getStarList(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+	if err := models.AddRepoToStarList(list, ctx.Repo.Repository.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "AddRepoToStarList", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveRepoFromStarList removes a repository from a star list of the authenticated user
+func RemoveRepoFromStarList(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/star-lists/{id}/repos/{owner}/{repo} user userCurrentRemoveRepoFromStarList
+	// ---
+	// summary: Remove a repository from a star list of the authenticated user. Removing it from the default list unstars it.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	list := getStarList(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+	if err := models.RemoveRepoFromStarList(list, ctx.Repo.Repository.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RemoveRepoFromStarList", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// canSeePrivateStarLists returns whether the authenticated user may see the private star lists of user
+func canSeePrivateStarLists(ctx *context.APIContext, user *models.User) bool {
+	return ctx.User.ID == user.ID || ctx.User.IsAdmin
+}
+
+// getStarList returns the star list of the request, private lists are only found for their owner
+func getStarList(ctx *context.APIContext, user *models.User) *models.StarList {
+	list, err := models.GetStarListByID(user.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		handleStarListError(ctx, "GetStarListByID", err)
+		return nil
+	}
+	if list.IsPrivate && !canSeePrivateStarLists(ctx, user) {
+		ctx.NotFound()
+		return nil
+	}
+	return list
+}
+
+func handleStarListError(ctx *context.APIContext, name string, err error) {
+	switch err {
+	case models.ErrStarListNotExist:
+		ctx.NotFound()
+	case models.ErrStarListAlreadyExist:
+		ctx.Error(http.StatusConflict, name, err)
+	case models.ErrStarListNameInvalid, models.ErrStarListIsDefault:
+		ctx.Error(http.StatusUnprocessableEntity, name, err)
+	default:
+		ctx.Error(http.StatusInternalServerError, name, err)
+	}
+}
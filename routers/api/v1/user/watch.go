@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
@@ -120,18 +121,7 @@ func IsWatching(ctx *context.APIContext) {
 	//   "404":
 	//     description: User is not watching this repo or repo do not exist
 
-	if models.IsWatching(ctx.User.ID, ctx.Repo.Repository.ID) {
-		ctx.JSON(http.StatusOK, api.WatchInfo{
-			Subscribed:    true,
-			Ignored:       false,
-			Reason:        nil,
-			CreatedAt:     ctx.Repo.Repository.CreatedUnix.AsTime(),
-			URL:           subscriptionURL(ctx.Repo.Repository),
-			RepositoryURL: ctx.Repo.Repository.APIURL(),
-		})
-	} else {
-		ctx.NotFound()
-	}
+	writeWatchInfo(ctx)
 }
 
 // Watch the repo specified in ctx, as the authenticated user
@@ -159,15 +149,55 @@ func Watch(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "WatchRepo", err)
 		return
 	}
-	ctx.JSON(http.StatusOK, api.WatchInfo{
-		Subscribed:    true,
-		Ignored:       false,
-		Reason:        nil,
-		CreatedAt:     ctx.Repo.Repository.CreatedUnix.AsTime(),
-		URL:           subscriptionURL(ctx.Repo.Repository),
-		RepositoryURL: ctx.Repo.Repository.APIURL(),
-	})
+	writeWatchInfo(ctx)
+}
+
+// EditWatch changes which issue events of the repo specified in ctx are delivered to the authenticated user
+func EditWatch(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/subscription repository userCurrentEditSubscription
+	// ---
+	// summary: Change the subscription level of a watched repo
+	// description: The level applies to the issues of the repo which the user does not watch individually.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditSubscriptionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WatchInfo"
+	//   "404":
+	//     description: User is not watching this repo or repo do not exist
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
+	subscription, ok := utils.ToSubscription(ctx, web.GetForm(ctx).(*api.EditSubscriptionOption))
+	if !ok {
+		return
+	}
+	if err := models.UpdateRepoWatchSubscription(ctx.User.ID, ctx.Repo.Repository.ID, subscription); err != nil {
+		if err == models.ErrNotWatching {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateRepoWatchSubscription", err)
+		}
+		return
+	}
+	writeWatchInfo(ctx)
 }
 
 // Unwatch the repo specified in ctx, as the authenticated user
@@ -198,6 +228,29 @@ func Unwatch(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// writeWatchInfo writes the watch status of the authenticated user for the repo specified in ctx
+func writeWatchInfo(ctx *context.APIContext) {
+	subscription, err := models.GetRepoWatchSubscription(ctx.User.ID, ctx.Repo.Repository.ID)
+	if err != nil {
+		if err == models.ErrNotWatching {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoWatchSubscription", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, api.WatchInfo{
+		Subscribed:    true,
+		Ignored:       false,
+		Reason:        nil,
+		CreatedAt:     ctx.Repo.Repository.CreatedUnix.AsTime(),
+		URL:           subscriptionURL(ctx.Repo.Repository),
+		RepositoryURL: ctx.Repo.Repository.APIURL(),
+		Level:         subscription.Level.String(),
+		Events:        subscription.Events.Names(),
+	})
+}
+
 // subscriptionURL returns the URL of the subscription API endpoint of a repo
 func subscriptionURL(repo *models.Repository) string {
 	return repo.APIURL() + "/subscription"
@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/typesniffer"
+)
+
+// DecodeAvatarOption returns the image data and the crop area of an avatar update.
+// If they are invalid, an error is written to `ctx` and false is returned.
+func DecodeAvatarOption(ctx *context.APIContext, form *api.UpdateAvatarOption) ([]byte, image.Rectangle, bool) {
+	data, err := base64.StdEncoding.DecodeString(form.Image)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("image is not valid base64: %v", err))
+		return nil, image.Rectangle{}, false
+	}
+	if int64(len(data)) > setting.Avatar.MaxFileSize {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("image is too large: %d > %d bytes", len(data), setting.Avatar.MaxFileSize))
+		return nil, image.Rectangle{}, false
+	}
+
+	st := typesniffer.DetectContentType(data)
+	if !(st.IsImage() && !st.IsSvgImage()) {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("image is not a supported image"))
+		return nil, image.Rectangle{}, false
+	}
+	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("image is not a supported image: %v", err))
+		return nil, image.Rectangle{}, false
+	}
+	if imgCfg.Width > setting.Avatar.MaxWidth || imgCfg.Height > setting.Avatar.MaxHeight {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("image is too large: %dx%d > %dx%d", imgCfg.Width, imgCfg.Height, setting.Avatar.MaxWidth, setting.Avatar.MaxHeight))
+		return nil, image.Rectangle{}, false
+	}
+
+	var crop image.Rectangle
+	if form.Crop != nil {
+		crop = image.Rect(form.Crop.X, form.Crop.Y, form.Crop.X+form.Crop.Width, form.Crop.Y+form.Crop.Height)
+		if form.Crop.Width <= 0 || form.Crop.Height <= 0 || !crop.In(image.Rect(0, 0, imgCfg.Width, imgCfg.Height)) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("crop area is out of the image bounds: %dx%d", imgCfg.Width, imgCfg.Height))
+			return nil, image.Rectangle{}, false
+		}
+	}
+	return data, crop, true
+}
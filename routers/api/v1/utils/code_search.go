@@ -0,0 +1,142 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CodeSearchableRepoIDs returns the ids of the repositories whose code the doer may read.
+// If ownerID is not zero, only repositories of the owner are returned.
+// A nil slice means all repositories.
+func CodeSearchableRepoIDs(ctx *context.APIContext, ownerID int64) ([]int64, error) {
+	if ctx.User != nil && ctx.User.IsAdmin {
+		if ownerID == 0 {
+			return nil, nil
+		}
+		repoIDs, _, err := models.SearchRepositoryIDs(&models.SearchRepoOptions{
+			Actor:   ctx.User,
+			OwnerID: ownerID,
+			Private: true,
+		})
+		return repoIDs, err
+	}
+
+	accessibleIDs, err := models.FindUserAccessibleRepoIDs(ctx.User)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := models.GetRepositoriesMapByIDs(accessibleIDs)
+	if err != nil {
+		return nil, err
+	}
+	repoIDs := make([]int64, 0, len(repos))
+	for id, repo := range repos {
+		if ownerID != 0 && repo.OwnerID != ownerID {
+			continue
+		}
+		if ctx.User != nil && !repo.CheckUnitUser(ctx.User, models.UnitTypeCode) {
+			continue
+		}
+		if ctx.User == nil && !repo.UnitEnabled(models.UnitTypeCode) {
+			continue
+		}
+		repoIDs = append(repoIDs, id)
+	}
+	return repoIDs, nil
+}
+
+// SearchCode searches the code of the repositories and writes the results to `ctx`.
+// A nil slice of repository ids searches all repositories.
+func SearchCode(ctx *context.APIContext, repoIDs []int64) {
+	if !setting.Indexer.RepoIndexerEnabled {
+		ctx.NotFound()
+		return
+	}
+
+	keyword := ctx.FormTrim("q")
+	if keyword == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "q must not be empty")
+		return
+	}
+
+	listOptions := GetListOptions(ctx)
+	results, err := FindCode(repoIDs, keyword, ctx.FormTrim("language"), ctx.FormTrim("filename"),
+		listOptions, ctx.FormTrim("mode") == "match")
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindCode", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(results.TotalCount), listOptions.PageSize)
+	ctx.SetTotalCountHeader(results.TotalCount)
+	ctx.JSON(http.StatusOK, results)
+}
+
+// FindCode searches the code of the repositories for the keyword.
+// A nil slice of repository ids searches all repositories.
+func FindCode(repoIDs []int64, keyword, language, filename string, listOptions models.ListOptions, isMatch bool) (*api.CodeSearchResults, error) {
+	results := &api.CodeSearchResults{
+		Languages: []*api.CodeSearchLanguage{},
+		Results:   []*api.CodeSearchResult{},
+	}
+	if repoIDs != nil && len(repoIDs) == 0 {
+		return results, nil
+	}
+
+	if listOptions.Page <= 0 {
+		listOptions.Page = 1
+	}
+	total, searchResults, searchResultLanguages, err := code_indexer.PerformSearch(repoIDs,
+		language, filename, keyword, listOptions.Page, listOptions.PageSize, isMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	loadRepoIDs := make([]int64, 0, len(searchResults))
+	for _, result := range searchResults {
+		if !util.IsInt64InSlice(result.RepoID, loadRepoIDs) {
+			loadRepoIDs = append(loadRepoIDs, result.RepoID)
+		}
+	}
+	repos, err := models.GetRepositoriesMapByIDs(loadRepoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	results.TotalCount = int64(total)
+	for _, lang := range searchResultLanguages {
+		results.Languages = append(results.Languages, &api.CodeSearchLanguage{
+			Language: lang.Language,
+			Color:    lang.Color,
+			Count:    lang.Count,
+		})
+	}
+	for _, result := range searchResults {
+		repo, ok := repos[result.RepoID]
+		if !ok {
+			continue
+		}
+		results.Results = append(results.Results, &api.CodeSearchResult{
+			RepoID:       repo.ID,
+			RepoFullName: repo.FullName(),
+			Filename:     result.Filename,
+			CommitID:     result.CommitID,
+			Language:     result.Language,
+			HTMLURL:      repo.HTMLURL() + "/src/commit/" + result.CommitID + "/" + util.PathEscapeSegments(result.Filename),
+			LineNumbers:  result.LineNumbers,
+			Fragment:     result.FormattedLines,
+			Updated:      result.UpdatedUnix.AsTime(),
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// ErrInvalidCursor is returned when the cursor of a request can not be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorToken is the content of an opaque cursor, encoded as base64 JSON
+type cursorToken struct {
+	Value int64  `json:"v,omitempty"`
+	ID    int64  `json:"i,omitempty"`
+	SHA   string `json:"s,omitempty"`
+	Skip  int    `json:"k,omitempty"`
+}
+
+func (t *cursorToken) encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(ctx *context.APIContext) (*cursorToken, error) {
+	cursor := ctx.FormString("cursor")
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	t := &cursorToken{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// IsCursorPagination returns true if a list is requested by cursor instead of by page.
+// An empty cursor requests the first page.
+func IsCursorPagination(ctx *context.APIContext) bool {
+	_, ok := ctx.Req.URL.Query()["cursor"]
+	return ok
+}
+
+// GetCursor returns the position after which the requested page of a list starts, nil for the first page
+func GetCursor(ctx *context.APIContext) (*models.Cursor, error) {
+	t, err := decodeCursor(ctx)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	if t.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &models.Cursor{Value: t.Value, ID: t.ID}, nil
+}
+
+// EncodeCursor returns the opaque cursor of a position in a list
+func EncodeCursor(c *models.Cursor) string {
+	return (&cursorToken{Value: c.Value, ID: c.ID}).encode()
+}
+
+// GetCommitCursor returns the commit whose history is listed and the number of commits of the previous pages,
+// sha is empty for the first page
+func GetCommitCursor(ctx *context.APIContext) (sha string, skip int, err error) {
+	t, err := decodeCursor(ctx)
+	if err != nil || t == nil {
+		return "", 0, err
+	}
+	if t.SHA == "" || t.Skip < 0 {
+		return "", 0, ErrInvalidCursor
+	}
+	return t.SHA, t.Skip, nil
+}
+
+// EncodeCommitCursor returns the opaque cursor of a position in the history of a commit
+func EncodeCommitCursor(sha string, skip int) string {
+	return (&cursorToken{SHA: sha, Skip: skip}).encode()
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListIssueCustomFields writes the custom fields of the issues of an organization or a repository to `ctx`
+func ListIssueCustomFields(ctx *context.APIContext, orgID, repoID int64) {
+	fields, err := models.GetIssueCustomFields(orgID, repoID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueCustomFields", err)
+		return
+	}
+
+	apiFields := make([]*api.IssueCustomField, 0, len(fields))
+	for _, f := range fields {
+		apiFields = append(apiFields, convert.ToAPIIssueCustomField(f))
+	}
+	ctx.JSON(http.StatusOK, apiFields)
+}
+
+// CreateIssueCustomField creates a custom field of the issues of an organization or a repository.
+// Writes to `ctx` accordingly
+func CreateIssueCustomField(ctx *context.APIContext, orgID, repoID int64, form *api.CreateIssueCustomFieldOption) {
+	typ, _ := models.IssueCustomFieldTypeFromString(form.Type)
+	field := &models.IssueCustomField{
+		OrgID:       orgID,
+		RepoID:      repoID,
+		Name:        form.Name,
+		Description: form.Description,
+		Type:        typ,
+		Options:     form.Options,
+	}
+	if err := models.NewIssueCustomField(field); err != nil {
+		handleIssueCustomFieldError(ctx, "NewIssueCustomField", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueCustomField(field))
+}
+
+// EditIssueCustomField edits a custom field of the issues of an organization or a repository.
+// Writes to `ctx` accordingly
+func EditIssueCustomField(ctx *context.APIContext, orgID, repoID int64, form *api.EditIssueCustomFieldOption) {
+	field := getIssueCustomField(ctx, orgID, repoID)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		field.Name = *form.Name
+	}
+	if form.Description != nil {
+		field.Description = *form.Description
+	}
+	if form.Options != nil {
+		field.Options = form.Options
+	}
+	if err := models.UpdateIssueCustomField(field); err != nil {
+		handleIssueCustomFieldError(ctx, "UpdateIssueCustomField", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueCustomField(field))
+}
+
+// DeleteIssueCustomField deletes a custom field of the issues of an organization or a repository with its values.
+// Writes to `ctx` accordingly
+func DeleteIssueCustomField(ctx *context.APIContext, orgID, repoID int64) {
+	field := getIssueCustomField(ctx, orgID, repoID)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteIssueCustomField(field); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueCustomField", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getIssueCustomField(ctx *context.APIContext, orgID, repoID int64) *models.IssueCustomField {
+	field, err := models.GetIssueCustomFieldByID(orgID, repoID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueCustomFieldNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueCustomFieldByID", err)
+		}
+		return nil
+	}
+	return field
+}
+
+func handleIssueCustomFieldError(ctx *context.APIContext, name string, err error) {
+	switch {
+	case models.IsErrIssueCustomFieldAlreadyExist(err):
+		ctx.Error(http.StatusConflict, "", err)
+	case models.IsErrInvalidIssueCustomField(err):
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	default:
+		ctx.Error(http.StatusInternalServerError, name, err)
+	}
+}
+
+// GetCustomFieldFilters returns the values of the custom fields to filter issues by, by field name,
+// parsed from the `custom_field` query parameters of the form `name=value`
+func GetCustomFieldFilters(ctx *context.APIContext) (map[string]string, error) {
+	params := ctx.FormStrings("custom_field")
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	filters := make(map[string]string, len(params))
+	for _, param := range params {
+		fields := strings.SplitN(param, "=", 2)
+		if len(fields) != 2 || strings.TrimSpace(fields[0]) == "" {
+			return nil, fmt.Errorf("invalid custom field filter %q, expected name=value", param)
+		}
+		filters[strings.TrimSpace(fields[0])] = strings.TrimSpace(fields[1])
+	}
+	return filters, nil
+}
@@ -129,6 +129,7 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 				PullRequestSync:      pullHook(form.Events, string(models.HookEventPullRequestSync)),
 				Repository:           util.IsStringInSlice(string(models.HookEventRepository), form.Events, true),
 				Release:              util.IsStringInSlice(string(models.HookEventRelease), form.Events, true),
+				Schedule:             util.IsStringInSlice(string(models.HookEventSchedule), form.Events, true),
 			},
 			BranchFilter: form.BranchFilter,
 		},
@@ -255,6 +256,7 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	w.PullRequest = util.IsStringInSlice(string(models.HookEventPullRequest), form.Events, true)
 	w.Repository = util.IsStringInSlice(string(models.HookEventRepository), form.Events, true)
 	w.Release = util.IsStringInSlice(string(models.HookEventRelease), form.Events, true)
+	w.Schedule = util.IsStringInSlice(string(models.HookEventSchedule), form.Events, true)
 	w.BranchFilter = form.BranchFilter
 
 	if err := w.UpdateEvent(); err != nil {
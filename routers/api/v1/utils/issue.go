@@ -0,0 +1,38 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAPIIssueList converts issues to API format along with the numbers of their comments the signed in user
+// has not read yet, which are loaded at once. Writes to `ctx` if this fails.
+func ToAPIIssueList(ctx *context.APIContext, issues models.IssueList) []*api.Issue {
+	apiIssues := convert.ToAPIIssueList(issues)
+	if !ctx.IsSigned || len(issues) == 0 {
+		return apiIssues
+	}
+
+	issueIDs := make([]int64, len(issues))
+	for i := range issues {
+		issueIDs[i] = issues[i].ID
+	}
+	counts, err := models.GetIssueUnreadCounts(ctx.User.ID, issueIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueUnreadCounts", err)
+		return nil
+	}
+	for i := range issues {
+		count := counts[issues[i].ID]
+		apiIssues[i].UnreadCount = &count
+	}
+	return apiIssues
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListIssueFilters writes the saved issue filters of an owner to `ctx`
+func ListIssueFilters(ctx *context.APIContext, ownerID int64) {
+	listOptions := GetListOptions(ctx)
+	filters, count, err := models.FindIssueFilters(ownerID, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindIssueFilters", err)
+		return
+	}
+
+	apiFilters := make([]*api.IssueFilter, 0, len(filters))
+	for _, f := range filters {
+		apiFilters = append(apiFilters, convert.ToIssueFilter(f))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiFilters)
+}
+
+// GetIssueFilter writes the saved issue filter of an owner identified by the id url parameter to `ctx`
+func GetIssueFilter(ctx *context.APIContext, ownerID int64) {
+	f := getIssueFilterByParams(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueFilter(f))
+}
+
+// CreateIssueFilter saves an issue filter of an owner. Writes to `ctx` accordingly
+func CreateIssueFilter(ctx *context.APIContext, ownerID int64, form *api.CreateIssueFilterOption) {
+	f := &models.IssueFilter{
+		OwnerID:   ownerID,
+		Name:      form.Name,
+		Query:     form.Query,
+		CreatorID: ctx.User.ID,
+	}
+	if err := models.CreateIssueFilter(f); err != nil {
+		writeIssueFilterError(ctx, "CreateIssueFilter", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToIssueFilter(f))
+}
+
+// EditIssueFilter modifies a saved issue filter of an owner. Writes to `ctx` accordingly
+func EditIssueFilter(ctx *context.APIContext, ownerID int64, form *api.EditIssueFilterOption) {
+	f := getIssueFilterByParams(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	if form.Name != nil {
+		f.Name = *form.Name
+	}
+	if form.Query != nil {
+		f.Query = *form.Query
+	}
+	if err := models.UpdateIssueFilter(f); err != nil {
+		writeIssueFilterError(ctx, "UpdateIssueFilter", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueFilter(f))
+}
+
+// DeleteIssueFilter deletes a saved issue filter of an owner. Writes to `ctx` accordingly
+func DeleteIssueFilter(ctx *context.APIContext, ownerID int64) {
+	if err := models.DeleteIssueFilter(ownerID, ctx.ParamsInt64(":id")); err != nil {
+		if err == models.ErrIssueFilterNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteIssueFilter", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// PrepareIssueFilterSearch replaces the query of the request by the query of the saved issue filter,
+// keeping only the pagination parameters, so that the issue search can run it.
+// If owner is not empty, the search is restricted to the repositories of this owner.
+// On error it writes the response.
+func PrepareIssueFilterSearch(ctx *context.APIContext, ownerID int64, owner string) {
+	f := getIssueFilterByParams(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	values, err := models.ParseIssueFilterQuery(f.Query)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+	for _, key := range []string{"page", "limit"} {
+		if v := ctx.FormString(key); v != "" {
+			values.Set(key, v)
+		}
+	}
+	if owner != "" {
+		values.Set("owner", owner)
+	}
+	ctx.Req.Form = values
+	ctx.Req.URL.RawQuery = values.Encode()
+}
+
+func getIssueFilterByParams(ctx *context.APIContext, ownerID int64) *models.IssueFilter {
+	f, err := models.GetIssueFilterByID(ownerID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == models.ErrIssueFilterNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueFilterByID", err)
+		}
+		return nil
+	}
+	return f
+}
+
+func writeIssueFilterError(ctx *context.APIContext, funcName string, err error) {
+	if err == models.ErrIssueFilterAlreadyExist {
+		ctx.Error(http.StatusConflict, "", err)
+	} else if err == models.ErrIssueFilterNameInvalid || models.IsErrIssueFilterQueryInvalid(err) {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	} else {
+		ctx.Error(http.StatusInternalServerError, funcName, err)
+	}
+}
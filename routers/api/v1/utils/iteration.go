@@ -0,0 +1,182 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ListIterationCadences writes the iteration cadences of an organization or a repository to `ctx`
+func ListIterationCadences(ctx *context.APIContext, orgID, repoID int64) {
+	cadences, err := models.GetIterationCadences(orgID, repoID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIterationCadences", err)
+		return
+	}
+
+	apiCadences := make([]*api.IterationCadence, 0, len(cadences))
+	for _, c := range cadences {
+		apiCadences = append(apiCadences, convert.ToAPIIterationCadence(c))
+	}
+	ctx.JSON(http.StatusOK, apiCadences)
+}
+
+// CreateIterationCadence creates an iteration cadence of an organization or a repository. Writes to `ctx` accordingly
+func CreateIterationCadence(ctx *context.APIContext, orgID, repoID int64, form *api.CreateIterationCadenceOption) {
+	cadence := &models.IterationCadence{
+		OrgID:           orgID,
+		RepoID:          repoID,
+		Title:           form.Title,
+		DurationDays:    form.DurationDays,
+		IterationsAhead: 1,
+		StartUnix:       timeutil.TimeStampNow(),
+	}
+	if form.IterationsAhead != nil {
+		cadence.IterationsAhead = *form.IterationsAhead
+	}
+	if form.Start != nil && !form.Start.IsZero() {
+		cadence.StartUnix = timeutil.TimeStamp(form.Start.Unix())
+	}
+
+	if err := models.NewIterationCadence(cadence); err != nil {
+		if models.IsErrInvalidIterationCadence(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewIterationCadence", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAPIIterationCadence(cadence))
+}
+
+// EditIterationCadence edits an iteration cadence of an organization or a repository. Writes to `ctx` accordingly
+func EditIterationCadence(ctx *context.APIContext, orgID, repoID int64, form *api.EditIterationCadenceOption) {
+	cadence := getIterationCadence(ctx, orgID, repoID)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Title != nil {
+		if *form.Title == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("the title cannot be empty"))
+			return
+		}
+		cadence.Title = *form.Title
+	}
+	if form.IterationsAhead != nil {
+		cadence.IterationsAhead = *form.IterationsAhead
+	}
+
+	if err := models.UpdateIterationCadence(cadence); err != nil {
+		if models.IsErrInvalidIterationCadence(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateIterationCadence", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIterationCadence(cadence))
+}
+
+// DeleteIterationCadence deletes an iteration cadence of an organization or a repository with its iterations.
+// Writes to `ctx` accordingly
+func DeleteIterationCadence(ctx *context.APIContext, orgID, repoID int64) {
+	cadence := getIterationCadence(ctx, orgID, repoID)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteIterationCadence(cadence); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIterationCadence", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getIterationCadence(ctx *context.APIContext, orgID, repoID int64) *models.IterationCadence {
+	cadence, err := models.GetIterationCadenceByID(orgID, repoID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIterationCadenceNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIterationCadenceByID", err)
+		}
+		return nil
+	}
+	return cadence
+}
+
+// ListIterations writes the iterations matching the options and the state of the request to `ctx`
+func ListIterations(ctx *context.APIContext, opts *models.FindIterationOptions) {
+	opts.State = ctx.FormString("state")
+	switch opts.State {
+	case "", models.IterationStatePast, models.IterationStateCurrent, models.IterationStateUpcoming:
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("state must be past, current or upcoming"))
+		return
+	}
+
+	iterations, err := models.FindIterations(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindIterations", err)
+		return
+	}
+
+	apiIterations := make([]*api.Iteration, 0, len(iterations))
+	for _, it := range iterations {
+		apiIterations = append(apiIterations, convert.ToAPIIteration(it))
+	}
+	ctx.JSON(http.StatusOK, apiIterations)
+}
+
+// GetIteration writes an iteration within the scope of the options with the statistics of its issues to `ctx`
+func GetIteration(ctx *context.APIContext, opts *models.FindIterationOptions) {
+	id := ctx.ParamsInt64(":id")
+	it, err := models.GetIterationByID(id)
+	if err == nil &&
+		!(it.OrgID == opts.OrgID && it.RepoID == opts.RepoID) &&
+		!(opts.IncludeOrgID != 0 && it.OrgID == opts.IncludeOrgID && it.RepoID == 0) {
+		err = models.ErrIterationNotExist{ID: id}
+	}
+	if err != nil {
+		if models.IsErrIterationNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIterationByID", err)
+		}
+		return
+	}
+
+	stats, err := models.GetIterationStats(it)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIterationStats", err)
+		return
+	}
+
+	apiIteration := convert.ToAPIIteration(it)
+	apiIteration.Stats = &api.IterationStats{
+		Issues:       stats.Issues,
+		ClosedIssues: stats.ClosedIssues,
+		Velocity:     stats.Velocity,
+	}
+	ctx.JSON(http.StatusOK, apiIteration)
+}
+
+// RepoIterationOptions returns the options to find the iterations the issues of a repository can be assigned to,
+// those of the repository and of the organization owning it
+func RepoIterationOptions(repo *models.Repository) *models.FindIterationOptions {
+	opts := &models.FindIterationOptions{RepoID: repo.ID}
+	if err := repo.GetOwner(); err == nil && repo.Owner.IsOrganization() {
+		opts.IncludeOrgID = repo.OwnerID
+	}
+	return opts
+}
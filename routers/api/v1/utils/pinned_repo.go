@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListPinnedRepos writes the repositories pinned on the profile of a user or an organization to `ctx`,
+// leaving out those the doer has no access to
+func ListPinnedRepos(ctx *context.APIContext, ownerID int64) {
+	repos, err := models.GetPinnedRepos(ownerID, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPinnedRepos", err)
+		return
+	}
+
+	apiRepos := make([]*api.Repository, len(repos))
+	for i, repo := range repos {
+		access, err := models.AccessLevel(ctx.User, repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiRepos[i] = convert.ToRepo(repo, access)
+	}
+	ctx.JSON(http.StatusOK, &apiRepos)
+}
+
+// SetPinnedRepos replaces the repositories pinned on the profile of a user or an organization
+// and writes them to `ctx`. Only repositories the doer has access to can be pinned.
+func SetPinnedRepos(ctx *context.APIContext, ownerID int64, form *api.SetPinnedReposOption) {
+	repoIDs := make([]int64, 0, len(form.Repos))
+	for _, fullName := range form.Repos {
+		parts := strings.SplitN(fullName, "/", 2)
+		if len(parts) != 2 {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid repository name: %s", fullName))
+			return
+		}
+		repo, err := models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("repository does not exist: %s", fullName))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return
+		}
+		perm, err := models.GetUserRepoPermission(repo, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		if !perm.HasAccess() {
+			// do not leak the existence of the repository
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("repository does not exist: %s", fullName))
+			return
+		}
+		repoIDs = append(repoIDs, repo.ID)
+	}
+
+	if err := models.SetPinnedRepos(ownerID, repoIDs); err != nil {
+		if err == models.ErrTooManyPinnedRepos {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetPinnedRepos", err)
+		}
+		return
+	}
+	ListPinnedRepos(ctx, ownerID)
+}
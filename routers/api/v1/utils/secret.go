@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListSecrets writes the secrets of an owner or a repository without their values to `ctx`
+func ListSecrets(ctx *context.APIContext, ownerID, repoID int64) {
+	opts := &models.FindSecretsOptions{
+		ListOptions: GetListOptions(ctx),
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+	}
+	secrets, count, err := models.FindSecrets(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindSecrets", err)
+		return
+	}
+
+	apiSecrets := make([]*api.Secret, 0, len(secrets))
+	for _, s := range secrets {
+		apiSecrets = append(apiSecrets, convert.ToSecret(s))
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiSecrets)
+}
+
+// CreateOrUpdateSecret stores a secret of an owner or a repository. Writes to `ctx` accordingly
+func CreateOrUpdateSecret(ctx *context.APIContext, ownerID, repoID int64, form *api.CreateOrUpdateSecretOption) {
+	name := ctx.Params(":secretname")
+	created, err := models.CreateOrUpdateSecret(ownerID, repoID, name, form.Data)
+	if err != nil {
+		if err == models.ErrSecretNameInvalid || err == models.ErrSecretDataTooLarge {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateOrUpdateSecret", err)
+		}
+		return
+	}
+
+	log.Info("Secret %s [owner: %d, repo: %d] stored by %s", name, ownerID, repoID, ctx.User.Name)
+	if created {
+		ctx.Status(http.StatusCreated)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// DeleteSecret deletes a secret of an owner or a repository. Writes to `ctx` accordingly
+func DeleteSecret(ctx *context.APIContext, ownerID, repoID int64) {
+	name := ctx.Params(":secretname")
+	if err := models.DeleteSecret(ownerID, repoID, name); err != nil {
+		if err == models.ErrSecretNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteSecret", err)
+		}
+		return
+	}
+
+	log.Info("Secret %s [owner: %d, repo: %d] deleted by %s", name, ownerID, repoID, ctx.User.Name)
+	ctx.Status(http.StatusNoContent)
+}
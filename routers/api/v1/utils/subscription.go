@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToSubscription converts the form to a subscription. On error it writes the response.
+func ToSubscription(ctx *context.APIContext, form *api.EditSubscriptionOption) (models.Subscription, bool) {
+	level, ok := models.SubscriptionLevelFromString(form.Level)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid subscription level: "+form.Level)
+		return models.Subscription{}, false
+	}
+	s := models.Subscription{Level: level}
+	if level == models.SubscriptionLevelCustom {
+		events, err := models.ParseSubscriptionEvents(form.Events)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return models.Subscription{}, false
+		}
+		s.Events = events
+	}
+	return s, true
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package common
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
+)
+
+// maintenanceAllowedPaths are the paths which, together with the paths below them, may be posted to while the
+// instance is in maintenance mode. The internal API checks pushes itself, signing in does not change any content
+// and the admin endpoint must stay writable to end the maintenance.
+var maintenanceAllowedPaths = []string{
+	"/api/internal",
+	"/api/v1/admin/maintenance",
+	"/api/v1/markdown",
+	"/user/login",
+	"/user/logout",
+	"/user/two_factor",
+	"/user/u2f",
+}
+
+// maintenanceAllowedSuffixes are the git and LFS endpoints which are posted to but only read
+var maintenanceAllowedSuffixes = []string{
+	"/git-upload-pack",
+	"/info/lfs/objects/batch",
+}
+
+// isWriteRequest returns if a request may change the state of the instance
+func isWriteRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	for _, allowed := range maintenanceAllowedPaths {
+		if req.URL.Path == allowed || strings.HasPrefix(req.URL.Path, allowed+"/") {
+			return false
+		}
+	}
+	for _, suffix := range maintenanceAllowedSuffixes {
+		if strings.HasSuffix(req.URL.Path, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// MaintenanceMode rejects the requests which may write with 503 Service Unavailable while the instance is in
+// maintenance mode or its database is read-only. Reads are served as usual.
+func MaintenanceMode(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !isWriteRequest(req) {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		err := maintenance.CheckWritable()
+		if err == nil {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		readOnlyErr := err.(maintenance.ErrReadOnly)
+		resp.Header().Set("Retry-After", readOnlyErr.RetryAfterSeconds())
+		if !strings.HasPrefix(req.URL.Path, "/api/") {
+			http.Error(resp, readOnlyErr.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		resp.Header().Set("Content-Type", "application/json;charset=utf-8")
+		resp.WriteHeader(http.StatusServiceUnavailable)
+		if err := json.NewEncoder(resp).Encode(context.NewAPIReadOnlyError(readOnlyErr)); err != nil {
+			log.Error("Unable to write maintenance mode response: %v", err)
+		}
+	})
+}
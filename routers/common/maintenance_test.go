@@ -0,0 +1,91 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/maintenance"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	defer maintenance.Set(maintenance.State{})
+
+	handler := MaintenanceMode(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	// writes pass while the instance is not in maintenance mode
+	assert.EqualValues(t, http.StatusNoContent, serve("POST", "/api/v1/user/repos").Code)
+
+	maintenance.Set(maintenance.State{Enabled: true, Message: "upgrading", RetryAfter: 2 * time.Minute})
+
+	for _, c := range []struct {
+		method, path string
+	}{
+		{"GET", "/user2/repo1"},
+		{"HEAD", "/api/v1/repos/user2/repo1"},
+		{"POST", "/user2/repo1.git/git-upload-pack"},
+		{"POST", "/user2/repo1.git/info/lfs/objects/batch"},
+		{"POST", "/user/login"},
+		{"PUT", "/api/v1/admin/maintenance"},
+		{"POST", "/api/v1/markdown/raw"},
+		{"POST", "/api/internal/hook/pre-receive/user2/repo1"},
+	} {
+		assert.EqualValues(t, http.StatusNoContent, serve(c.method, c.path).Code, "%s %s", c.method, c.path)
+	}
+
+	recorder := serve("POST", "/api/v1/user/repos")
+	assert.EqualValues(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "120", recorder.Header().Get("Retry-After"))
+	assert.Contains(t, recorder.Body.String(), `"message":"instance is in maintenance mode: upgrading"`)
+	assert.Contains(t, recorder.Body.String(), `"reason":"maintenance"`)
+
+	recorder = serve("POST", "/user2/repo1.git/git-receive-pack")
+	assert.EqualValues(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "120", recorder.Header().Get("Retry-After"))
+
+	assert.EqualValues(t, http.StatusServiceUnavailable, serve("DELETE", "/user2/repo1/settings").Code)
+	assert.EqualValues(t, http.StatusServiceUnavailable, serve("POST", "/api/v1/markdownfiles").Code)
+	assert.EqualValues(t, http.StatusServiceUnavailable, serve("POST", "/user/login_source").Code)
+}
+
+func TestMaintenanceModeDatabaseReadOnly(t *testing.T) {
+	defer maintenance.SetDatabaseReadOnly(false)
+
+	handler := MaintenanceMode(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	assert.True(t, maintenance.SetDatabaseReadOnly(true))
+	assert.False(t, maintenance.SetDatabaseReadOnly(true))
+	assert.True(t, maintenance.IsDatabaseReadOnly())
+
+	assert.EqualValues(t, http.StatusNoContent, serve("GET", "/api/v1/repos/user2/repo1").Code)
+
+	recorder := serve("POST", "/api/v1/repos/user2/repo1/issues")
+	assert.EqualValues(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+	assert.Contains(t, recorder.Body.String(), `"reason":"database_read_only"`)
+	assert.Contains(t, recorder.Body.String(), `"retry_after":30`)
+
+	assert.True(t, maintenance.SetDatabaseReadOnly(false))
+	assert.EqualValues(t, http.StatusNoContent, serve("POST", "/api/v1/repos/user2/repo1/issues").Code)
+}
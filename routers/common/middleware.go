@@ -11,6 +11,7 @@ import (
 
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/chi-middleware/proxy"
@@ -27,6 +28,12 @@ func Middlewares() []func(http.Handler) http.Handler {
 		},
 	}
 
+	handlers = append(handlers, RequestID)
+
+	if metrics.IsGroupEnabled(metrics.GroupHTTP) {
+		handlers = append(handlers, metrics.RequestDuration)
+	}
+
 	if setting.ReverseProxyLimit > 0 {
 		opt := proxy.NewForwardedHeadersOptions().
 			WithForwardLimit(setting.ReverseProxyLimit).
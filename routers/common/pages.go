@@ -0,0 +1,21 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package common
+
+import (
+	"net/http"
+
+	pages_service "code.gitea.io/gitea/services/pages"
+)
+
+// Pages serves the requests to the hosts of pages sites, all other requests are passed on
+func Pages(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if pages_service.ServeHTTP(resp, req) {
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
@@ -7,9 +7,11 @@ package common
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/charset"
 	"code.gitea.io/gitea/modules/context"
@@ -50,13 +52,43 @@ func ServeData(ctx *context.Context, name string, size int64, reader io.Reader)
 		buf = buf[:n]
 	}
 
-	ctx.Resp.Header().Set("Cache-Control", "public,max-age=86400")
-
 	if size >= 0 {
 		ctx.Resp.Header().Set("Content-Length", fmt.Sprintf("%d", size))
 	} else {
 		log.Error("ServeData called to serve data: %s with size < 0: %d", name, size)
 	}
+	setServeHeaders(ctx, name, buf)
+
+	_, err = ctx.Resp.Write(buf)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(ctx.Resp, reader)
+	return err
+}
+
+// ServeContent serves a file which can be read from any position like ServeData, but answers requests
+// for ranges of it, e.g. to play a video from any position
+func ServeContent(ctx *context.Context, name string, modTime time.Time, reader io.ReadSeeker) error {
+	buf := make([]byte, 1024)
+	n, err := io.ReadFull(reader, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	buf = buf[:n]
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	setServeHeaders(ctx, name, buf)
+	http.ServeContent(ctx.Resp, ctx.Req, path.Base(name), modTime, reader)
+	return nil
+}
+
+// setServeHeaders sets the headers of a served file from its name and its first bytes
+func setServeHeaders(ctx *context.Context, name string, buf []byte) {
+	ctx.Resp.Header().Set("Cache-Control", "public,max-age=86400")
+
 	name = path.Base(name)
 
 	// Google Chrome dislike commas in filenames, so let's change it to a space
@@ -83,8 +115,11 @@ func ServeData(ctx *context.Context, name string, size int64, reader io.Reader)
 		ctx.Resp.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
 		if mappedMimeType != "" {
 			ctx.Resp.Header().Set("Content-Type", mappedMimeType)
+		} else if st.IsVideo() || st.IsAudio() {
+			// media is only played inline if its type is known
+			ctx.Resp.Header().Set("Content-Type", st.GetMimeType())
 		}
-		if (st.IsImage() || st.IsPDF()) && (setting.UI.SVG.Enabled || !st.IsSvgImage()) {
+		if (st.IsImage() || st.IsPDF() || st.IsVideo() || st.IsAudio()) && (setting.UI.SVG.Enabled || !st.IsSvgImage()) {
 			ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, name))
 			if st.IsSvgImage() {
 				ctx.Resp.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
@@ -95,11 +130,4 @@ func ServeData(ctx *context.Context, name string, size int64, reader io.Reader)
 			ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
 		}
 	}
-
-	_, err = ctx.Resp.Write(buf)
-	if err != nil {
-		return err
-	}
-	_, err = io.Copy(ctx.Resp, reader)
-	return err
 }
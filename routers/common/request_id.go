@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package common
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// maxRequestIDLength is the maximum length of a request id accepted from a client or proxy
+const maxRequestIDLength = 64
+
+// isValidRequestID returns if a request id received in a header may be used in logs and headers
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestID assigns an id to every request, which is returned in a response header and attached to the log lines
+// and the git commands of the request. The id of a proxy in front of Gitea is reused if it is valid.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		requestID := req.Header.Get(setting.RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			var err error
+			if requestID, err = util.RandomString(20); err != nil {
+				log.Error("Unable to generate request id: %v", err)
+				next.ServeHTTP(resp, req)
+				return
+			}
+		}
+
+		resp.Header().Set(setting.RequestIDHeader, requestID)
+		next.ServeHTTP(resp, req.WithContext(log.WithRequestID(req.Context(), requestID)))
+	})
+}
+
+// requestIDLogSuffix returns the request id to append to log lines, if the request has one
+func requestIDLogSuffix(req *http.Request) string {
+	if requestID := log.RequestIDFromContext(req.Context()); requestID != "" {
+		return " [request_id: " + requestID + "]"
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	setting.RequestIDHeader = "X-Request-ID"
+
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		seen = log.RequestIDFromContext(req.Context())
+	}))
+
+	// a valid id of a proxy is reused
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "abc-123.def")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, "abc-123.def", seen)
+	assert.Equal(t, "abc-123.def", recorder.Header().Get("X-Request-ID"))
+
+	// an invalid id is replaced by a generated one
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "bad id\n")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Len(t, seen, 20)
+	assert.NotEqual(t, "bad id\n", seen)
+	assert.Equal(t, seen, recorder.Header().Get("X-Request-ID"))
+}
@@ -15,8 +15,10 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/highlight"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	dependency_indexer "code.gitea.io/gitea/modules/indexer/dependency"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
 	stats_indexer "code.gitea.io/gitea/modules/indexer/stats"
+	symbol_indexer "code.gitea.io/gitea/modules/indexer/symbol"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/external"
@@ -29,6 +31,8 @@ import (
 	"code.gitea.io/gitea/modules/task"
 	"code.gitea.io/gitea/modules/translation"
 	"code.gitea.io/gitea/modules/web"
+	packages_router "code.gitea.io/gitea/routers/api/packages"
+	runner_router "code.gitea.io/gitea/routers/api/runner"
 	apiv1 "code.gitea.io/gitea/routers/api/v1"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/routers/private"
@@ -36,11 +40,22 @@ import (
 	"code.gitea.io/gitea/services/archiver"
 	"code.gitea.io/gitea/services/auth"
 	"code.gitea.io/gitea/services/auth/source/oauth2"
+	backport_service "code.gitea.io/gitea/services/backport"
+	backup_service "code.gitea.io/gitea/services/backup"
+	"code.gitea.io/gitea/services/chat"
+	"code.gitea.io/gitea/services/federation"
+	issue_service "code.gitea.io/gitea/services/issue"
 	"code.gitea.io/gitea/services/mailer"
+	maintenance_service "code.gitea.io/gitea/services/maintenance"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	pages_service "code.gitea.io/gitea/services/pages"
+	profile_service "code.gitea.io/gitea/services/profile"
 	pull_service "code.gitea.io/gitea/services/pull"
+	"code.gitea.io/gitea/services/push"
+	release_service "code.gitea.io/gitea/services/release"
 	"code.gitea.io/gitea/services/repository"
 	"code.gitea.io/gitea/services/webhook"
+	workflow_service "code.gitea.io/gitea/services/workflow"
 
 	"gitea.com/go-chi/session"
 )
@@ -55,10 +70,14 @@ func NewServices() {
 		log.Fatal("repository init failed: %v", err)
 	}
 	mailer.NewContext()
+	push.NewContext()
+	chat.NewContext()
+	federation.NewContext()
 	if err := cache.NewContext(); err != nil {
 		log.Fatal("Unable to start cache service: %v", err)
 	}
 	notification.NewContext()
+	workflow_service.Init()
 	if err := archiver.Init(); err != nil {
 		log.Fatal("archiver init failed: %v", err)
 	}
@@ -110,6 +129,10 @@ func GlobalInit(ctx context.Context) {
 
 	models.NewRepoContext()
 
+	if err := maintenance_service.Init(); err != nil {
+		log.Fatal("Failed to load maintenance mode: %v", err)
+	}
+
 	// Booting long running goroutines.
 	cron.NewContext()
 	issue_indexer.InitIssueIndexer(false)
@@ -117,6 +140,12 @@ func GlobalInit(ctx context.Context) {
 	if err := stats_indexer.Init(); err != nil {
 		log.Fatal("Failed to initialize repository stats indexer queue: %v", err)
 	}
+	if err := symbol_indexer.Init(); err != nil {
+		log.Fatal("Failed to initialize symbol indexer: %v", err)
+	}
+	if err := dependency_indexer.Init(); err != nil {
+		log.Fatal("Failed to initialize repository dependency analyzer queue: %v", err)
+	}
 	mirror_service.InitSyncMirrors()
 	webhook.InitDeliverHooks()
 	if err := pull_service.Init(); err != nil {
@@ -128,6 +157,20 @@ func GlobalInit(ctx context.Context) {
 	if err := repo_migrations.Init(); err != nil {
 		log.Fatal("Failed to initialize repository migrations: %v", err)
 	}
+	if err := backup_service.Init(); err != nil {
+		log.Fatal("Failed to initialize backup queue: %v", err)
+	}
+	if err := backport_service.Init(); err != nil {
+		log.Fatal("Failed to initialize backport queue: %v", err)
+	}
+	if err := pages_service.Init(); err != nil {
+		log.Fatal("Failed to initialize pages publish queue: %v", err)
+	}
+	if err := release_service.InitAutoRelease(); err != nil {
+		log.Fatal("Failed to initialize auto release queue: %v", err)
+	}
+	profile_service.Init()
+	issue_service.InitIssueBranch()
 	eventsource.GetManager().Init()
 
 	if setting.SSH.StartBuiltinServer {
@@ -147,6 +190,8 @@ func NormalRoutes() *web.Route {
 	for _, middle := range common.Middlewares() {
 		r.Use(middle)
 	}
+	r.Use(common.MaintenanceMode)
+	r.Use(common.Pages)
 
 	sessioner := session.Sessioner(session.Options{
 		Provider:       setting.SessionConfig.Provider,
@@ -162,6 +207,9 @@ func NormalRoutes() *web.Route {
 
 	r.Mount("/", web_routers.Routes(sessioner))
 	r.Mount("/api/v1", apiv1.Routes(sessioner))
+	r.Mount("/api/packages", packages_router.Routes(sessioner))
+	r.Mount("/v2", packages_router.ContainerRoutes(sessioner))
+	r.Mount("/api/runner", runner_router.Routes())
 	r.Mount("/api/internal", private.Routes())
 	return r
 }
@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	hookscript_service "code.gitea.io/gitea/services/hookscript"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
 
@@ -112,6 +113,24 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 		}
 	}
 
+	// Run the post-receive hook scripts, their failure does not affect the push
+	var output string
+	if setting.HookScripts.Enabled && !opts.IsWiki && len(opts.OldCommitIDs) > 0 {
+		if repo == nil {
+			repo = loadRepository(ctx, ownerName, repoName)
+			if ctx.Written() {
+				// Error handled in loadRepository
+				return
+			}
+			wasEmpty = repo.IsEmpty
+		}
+		scriptResults, err := hookscript_service.Run(ctx.Req.Context(), repo, models.HookScriptPostReceive, opts)
+		if err != nil {
+			log.Error("Unable to run the post-receive hook scripts of %-v: %v", repo, err)
+		}
+		output = hookscript_service.FormatOutput(scriptResults)
+	}
+
 	results := make([]private.HookPostReceiveBranchResult, 0, len(opts.OldCommitIDs))
 
 	// We have to reload the repo in case its state is changed above
@@ -139,6 +158,7 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 					// We can stop there's no need to go any further
 					ctx.JSON(http.StatusOK, private.HookPostReceiveResult{
 						RepoWasEmpty: wasEmpty,
+						Output:       output,
 					})
 					return
 				}
@@ -197,5 +217,6 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 	ctx.JSON(http.StatusOK, private.HookPostReceiveResult{
 		Results:      results,
 		RepoWasEmpty: wasEmpty,
+		Output:       output,
 	})
 }
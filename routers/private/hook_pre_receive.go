@@ -15,8 +15,10 @@ import (
 	gitea_context "code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/web"
+	hookscript_service "code.gitea.io/gitea/services/hookscript"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
 
@@ -34,6 +36,8 @@ type preReceiveContext struct {
 	protectedTags    []*models.ProtectedTag
 	gotProtectedTags bool
 
+	pushRule *models.RepoPushRule
+
 	env []string
 
 	opts *private.HookOptions
@@ -105,6 +109,14 @@ func (ctx *preReceiveContext) AssertCreatePullRequest() bool {
 func HookPreReceive(ctx *gitea_context.PrivateContext) {
 	opts := web.GetForm(ctx).(*private.HookOptions)
 
+	if err := maintenance.CheckWritable(); err != nil {
+		log.Warn("Rejected push to %-v: %v", ctx.Repo.Repository, err)
+		ctx.JSON(http.StatusServiceUnavailable, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
 	ourCtx := &preReceiveContext{
 		PrivateContext: ctx,
 		env:            generateGitEnv(opts), // Generate git environment for checking commits
@@ -132,7 +144,27 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		}
 	}
 
-	ctx.PlainText(http.StatusOK, []byte("ok"))
+	results, err := hookscript_service.Run(ctx.Req.Context(), ctx.Repo.Repository, models.HookScriptPreReceive, opts)
+	if err != nil {
+		log.Error("Unable to run the pre-receive hook scripts of %-v: %v", ctx.Repo.Repository, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to run the pre-receive hook scripts of %s: %v", ctx.Repo.Repository.FullName(), err),
+		})
+		return
+	}
+	output := hookscript_service.FormatOutput(results)
+	for _, result := range results {
+		if !result.Succeeded() {
+			ctx.JSON(http.StatusForbidden, private.Response{
+				Err: fmt.Sprintf("Declined by the pre-receive hook script %q\n%s", result.Script.Name, output),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, private.HookPreReceiveResult{
+		Output: output,
+	})
 }
 
 func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullName string) {
@@ -152,6 +184,11 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 		return
 	}
 
+	preReceivePushRule(ctx, oldCommitID, newCommitID, refFullName)
+	if ctx.Written() {
+		return
+	}
+
 	protectBranch, err := models.GetProtectedBranchBy(repo.ID, branchName)
 	if err != nil {
 		log.Error("Unable to get protected branch: %s in %-v Error: %v", branchName, repo, err)
@@ -357,6 +394,11 @@ func preReceiveTag(ctx *preReceiveContext, oldCommitID, newCommitID, refFullName
 		return
 	}
 
+	preReceivePushRule(ctx, oldCommitID, newCommitID, refFullName)
+	if ctx.Written() {
+		return
+	}
+
 	tagName := strings.TrimPrefix(refFullName, git.TagPrefix)
 
 	if !ctx.gotProtectedTags {
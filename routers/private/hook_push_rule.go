@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// PushRule gets or loads the push rules of the repository
+func (ctx *preReceiveContext) PushRule() *models.RepoPushRule {
+	if ctx.pushRule == nil {
+		rule, err := models.GetRepoPushRule(ctx.Repo.Repository.ID)
+		if err != nil {
+			log.Error("Unable to get push rules for %-v Error: %v", ctx.Repo.Repository, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: err.Error(),
+			})
+			return nil
+		}
+		ctx.pushRule = rule
+	}
+	return ctx.pushRule
+}
+
+func preReceivePushRule(ctx *preReceiveContext, oldCommitID, newCommitID, refFullName string) {
+	if ctx.opts.IsWiki {
+		return
+	}
+
+	rule := ctx.PushRule()
+	if rule == nil {
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	if err := repo_service.CheckPushRule(repo, rule, oldCommitID, newCommitID, refFullName, ctx.env); err != nil {
+		if !models.IsErrPushRuleViolated(err) {
+			log.Error("Unable to check push rules for commits from %s to %s in %-v: %v", oldCommitID, newCommitID, repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: fmt.Sprintf("Unable to check push rules for commits from %s to %s: %v", oldCommitID, newCommitID, err),
+			})
+			return
+		}
+		log.Warn("Forbidden: Push to %s in %-v violates the push rules: %v", refFullName, repo, err)
+		ctx.JSON(http.StatusForbidden, private.Response{
+			Err: formatPushRuleViolation(err.(models.ErrPushRuleViolated)),
+		})
+	}
+}
+
+// formatPushRuleViolation returns the message shown to the pusher, oversized files are listed together with
+// the commands to move them to LFS
+func formatPushRuleViolation(err models.ErrPushRuleViolated) string {
+	if len(err.OversizedFiles) == 0 {
+		return err.Error()
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "push rules reject files larger than %s:\n", base.FileSize(err.MaxFileSize))
+	for _, f := range err.OversizedFiles {
+		fmt.Fprintf(&msg, "  %s (%s)\n", f.Path, base.FileSize(f.Size))
+	}
+	if hint := repo_service.PushRuleLFSHint(err.OversizedFiles); hint != "" {
+		msg.WriteString("\nhint: Store large files with Git LFS instead, for example by running:\n")
+		for _, line := range strings.Split(hint, "\n") {
+			fmt.Fprintf(&msg, "hint:   %s\n", line)
+		}
+	}
+	return strings.TrimSuffix(msg.String(), "\n")
+}
@@ -28,6 +28,7 @@ func RestoreRepo(ctx *myCtx.PrivateContext) {
 		OwnerName string
 		RepoName  string
 		Units     []string
+		Format    string
 	}{}
 	if err = json.Unmarshal(bs, &params); err != nil {
 		ctx.JSON(http.StatusInternalServerError, private.Response{
@@ -36,7 +37,12 @@ func RestoreRepo(ctx *myCtx.PrivateContext) {
 		return
 	}
 
-	if err := migrations.RestoreRepository(
+	restore := migrations.RestoreRepository
+	if params.Format == "github" {
+		restore = migrations.ImportRepository
+	}
+
+	if err := restore(
 		ctx,
 		params.RepoDir,
 		params.OwnerName,
@@ -14,6 +14,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/setting"
 	repo_service "code.gitea.io/gitea/services/repository"
@@ -90,6 +91,14 @@ func ServCommand(ctx *context.PrivateContext) {
 	modeString := "read"
 	if mode > models.AccessModeRead {
 		modeString = "write to"
+
+		if err := maintenance.CheckWritable(); err != nil {
+			ctx.JSON(http.StatusServiceUnavailable, private.ErrServCommand{
+				Results: results,
+				Err:     fmt.Sprintf("Unable to write to %s/%s: %v", ownerName, repoName, err),
+			})
+			return
+		}
 	}
 
 	// The default unit we're trying to look at is code
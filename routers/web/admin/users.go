@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/password"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web"
@@ -288,8 +289,10 @@ func EditUserPost(ctx *context.Context) {
 			ctx.Redirect(setting.AppSubURL + "/admin/users")
 			return
 		}
+		oldName := u.Name
 		u.Name = form.UserName
 		u.LowerName = strings.ToLower(form.UserName)
+		notification.NotifyRenameUser(ctx.User, u, oldName)
 	}
 
 	if form.Reset2FA {
@@ -0,0 +1,212 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package healthcheck
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// The response format follows https://datatracker.ietf.org/doc/html/draft-inadarei-api-health-check
+
+type status string
+
+const (
+	// pass healthy
+	pass status = "pass"
+	// fail unhealthy
+	fail status = "fail"
+	// warn healthy, with some concerns
+	warn status = "warn"
+)
+
+// checkTimeout is the longest time a check of an external service may take
+const checkTimeout = 5 * time.Second
+
+func (s status) ToHTTPStatus() int {
+	if s == fail {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// worse returns the more severe of two statuses
+func (s status) worse(other status) status {
+	if s == fail || other == fail {
+		return fail
+	}
+	if s == warn || other == warn {
+		return warn
+	}
+	return pass
+}
+
+type checks map[string][]componentStatus
+
+// response is the data returned by the health and readiness endpoints
+type response struct {
+	Status      status `json:"status"`
+	Description string `json:"description"`      // a human-friendly description of the service
+	Checks      checks `json:"checks,omitempty"` // the component statuses, keyed by "component:measurement"
+}
+
+// componentStatus presents one status of a single component
+type componentStatus struct {
+	ComponentID string `json:"componentId,omitempty"`
+	Status      status `json:"status"`
+	Time        string `json:"time"`             // the time when the check was done
+	Output      string `json:"output,omitempty"` // the reason of a failure or warning
+}
+
+// newComponentStatus returns the status of a check, which is failStatus if it returned an error
+func newComponentStatus(name string, err error, failStatus status) componentStatus {
+	s := componentStatus{
+		Status: pass,
+		Time:   getCheckTime(),
+	}
+	if err != nil {
+		log.Error("health check %s failed with error: %v", name, err)
+		s.Status = failStatus
+		s.Output = err.Error()
+	}
+	return s
+}
+
+// Check is the liveness endpoint, it only checks the services Gitea can not work without
+func Check(w http.ResponseWriter, r *http.Request) {
+	rsp := response{
+		Status:      pass,
+		Description: setting.AppName,
+		Checks:      make(checks),
+	}
+
+	rsp.Status = rsp.Status.worse(checkDatabase(rsp.Checks))
+	rsp.Status = rsp.Status.worse(checkCache(rsp.Checks))
+
+	writeResponse(w, rsp)
+}
+
+// Ready is the readiness endpoint, it checks all services Gitea depends on to handle requests.
+// An unreachable mail server only results in a warning as Gitea can still serve requests without it.
+func Ready(w http.ResponseWriter, r *http.Request) {
+	rsp := response{
+		Status:      pass,
+		Description: setting.AppName,
+		Checks:      make(checks),
+	}
+
+	rsp.Status = rsp.Status.worse(checkDatabase(rsp.Checks))
+	rsp.Status = rsp.Status.worse(checkCache(rsp.Checks))
+	rsp.Status = rsp.Status.worse(checkQueues(rsp.Checks))
+	rsp.Status = rsp.Status.worse(checkGit(rsp.Checks))
+	rsp.Status = rsp.Status.worse(checkMailer(rsp.Checks))
+
+	writeResponse(w, rsp)
+}
+
+func writeResponse(w http.ResponseWriter, rsp response) {
+	if rsp.Description == "" {
+		rsp.Description, _ = os.Hostname()
+	}
+	data, err := json.MarshalIndent(rsp, "", "  ")
+	if err != nil {
+		log.Error("Failed to marshal health check response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/health+json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(rsp.Status.ToHTTPStatus())
+	if _, err := w.Write(data); err != nil {
+		log.Error("Failed to write health check response: %v", err)
+	}
+}
+
+// checkDatabase checks that the database can be reached. A read-only database only results in a warning as reads are
+// still served.
+func checkDatabase(checks checks) status {
+	st := newComponentStatus("database", db.Ping(), fail)
+	checks["database:ping"] = []componentStatus{st}
+	if st.Status == fail {
+		return fail
+	}
+
+	readOnly := componentStatus{
+		Status: pass,
+		Time:   getCheckTime(),
+	}
+	if since := maintenance.DatabaseReadOnlySince(); !since.IsZero() {
+		readOnly.Status = warn
+		readOnly.Output = fmt.Sprintf("database is read-only since %s, writes are rejected", since.UTC().Format(time.RFC3339))
+	}
+	checks["database:read_only"] = []componentStatus{readOnly}
+	return readOnly.Status
+}
+
+// checkCache checks that the cache stores values if it is enabled
+func checkCache(checks checks) status {
+	if !setting.CacheService.Enabled {
+		return pass
+	}
+	st := newComponentStatus("cache", cache.Ping(), fail)
+	checks["cache:ping"] = []componentStatus{st}
+	return st.Status
+}
+
+// checkQueues checks that no queue has been terminated
+func checkQueues(checks checks) status {
+	result := pass
+	mqs := queue.GetManager().ManagedQueues()
+	statuses := make([]componentStatus, 0, len(mqs))
+	for _, mq := range mqs {
+		st := componentStatus{
+			ComponentID: mq.Name,
+			Status:      pass,
+			Time:        getCheckTime(),
+		}
+		if mq.IsTerminated() {
+			st.Status = fail
+			st.Output = "queue is terminated"
+		}
+		result = result.worse(st.Status)
+		statuses = append(statuses, st)
+	}
+	checks["queue:status"] = statuses
+	return result
+}
+
+// checkGit checks that the git binary can be run
+func checkGit(checks checks) status {
+	_, err := git.NewCommand("version").RunTimeout(checkTimeout)
+	st := newComponentStatus("git", err, fail)
+	checks["git:version"] = []componentStatus{st}
+	return st.Status
+}
+
+// checkMailer checks that the SMTP server can be reached if mails are sent by SMTP
+func checkMailer(checks checks) status {
+	if setting.MailService == nil || setting.MailService.MailerType != "smtp" {
+		return pass
+	}
+	st := newComponentStatus("mailer", mailer.CheckSMTPConnection(checkTimeout), warn)
+	checks["mailer:connection"] = []componentStatus{st}
+	return st.Status
+}
+
+func getCheckTime() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
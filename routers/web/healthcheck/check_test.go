@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusWorse(t *testing.T) {
+	assert.Equal(t, pass, pass.worse(pass))
+	assert.Equal(t, warn, pass.worse(warn))
+	assert.Equal(t, fail, warn.worse(fail))
+	assert.Equal(t, fail, fail.worse(pass))
+
+	assert.Equal(t, http.StatusOK, warn.ToHTTPStatus())
+	assert.Equal(t, http.StatusServiceUnavailable, fail.ToHTTPStatus())
+}
+
+func TestNewComponentStatus(t *testing.T) {
+	st := newComponentStatus("mailer", errors.New("connection refused"), warn)
+	assert.Equal(t, warn, st.Status)
+	assert.Equal(t, "connection refused", st.Output)
+
+	st = newComponentStatus("database", nil, fail)
+	assert.Equal(t, pass, st.Status)
+	assert.Empty(t, st.Output)
+}
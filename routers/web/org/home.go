@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
+	profile_service "code.gitea.io/gitea/services/profile"
 )
 
 const (
@@ -134,6 +135,20 @@ func Home(ctx *context.Context) {
 		return
 	}
 
+	profileReadme, err := profile_service.GetProfileReadme(ctx, org)
+	if err != nil {
+		ctx.ServerError("GetProfileReadme", err)
+		return
+	}
+	ctx.Data["ProfileReadme"] = profileReadme
+
+	pinnedRepos, err := models.GetPinnedRepos(org.ID, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetPinnedRepos", err)
+		return
+	}
+	ctx.Data["PinnedRepos"] = pinnedRepos
+
 	ctx.Data["Owner"] = org
 	ctx.Data["Repos"] = repos
 	ctx.Data["Total"] = count
@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web"
 	userSetting "code.gitea.io/gitea/routers/web/user/setting"
@@ -53,6 +54,8 @@ func SettingsPost(ctx *context.Context) {
 
 	org := ctx.Org.Organization
 	nameChanged := org.Name != form.Name
+	oldName := org.Name
+	renamed := false
 
 	// Check if organization name has been changed.
 	if org.LowerName != strings.ToLower(form.Name) {
@@ -77,6 +80,7 @@ func SettingsPost(ctx *context.Context) {
 		ctx.Org.OrgLink = setting.AppSubURL + "/org/" + form.Name
 		log.Trace("Organization name changed: %s -> %s", org.Name, form.Name)
 		nameChanged = false
+		renamed = true
 	}
 
 	// In case it's just a case change.
@@ -121,6 +125,10 @@ func SettingsPost(ctx *context.Context) {
 		}
 	}
 
+	if renamed {
+		notification.NotifyRenameUser(ctx.User, org, oldName)
+	}
+
 	log.Trace("Organization setting updated: %s", org.Name)
 	ctx.Flash.Success(ctx.Tr("org.settings.update_setting_success"))
 	ctx.Redirect(ctx.Org.OrgLink + "/settings")
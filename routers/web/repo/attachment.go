@@ -7,6 +7,7 @@ package repo
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -49,6 +50,10 @@ func uploadAttachment(ctx *context.Context, repoID int64, allowedTypes string) {
 			ctx.Error(http.StatusBadRequest, err.Error())
 			return
 		}
+		if upload.IsErrFileTooLarge(err) {
+			ctx.Error(http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("NewAttachment: %v", err))
 		return
 	}
@@ -81,8 +86,15 @@ func DeleteAttachment(ctx *context.Context) {
 	})
 }
 
-// GetAttachment serve attachements
+// GetAttachment serve attachements, or the thumbnails of images with the size given by the size parameter.
+// Ranges of attachments may be requested, e.g. to play videos inline.
 func GetAttachment(ctx *context.Context) {
+	size := ctx.FormInt("size")
+	if size != 0 && !attachment.IsThumbnailSize(size) {
+		ctx.Error(http.StatusBadRequest, fmt.Sprintf("Thumbnails of size %d are not available", size))
+		return
+	}
+
 	attach, err := models.GetAttachmentByUUID(ctx.Params(":uuid"))
 	if err != nil {
 		if models.IsErrAttachmentNotExist(err) {
@@ -116,9 +128,26 @@ func GetAttachment(ctx *context.Context) {
 		}
 	}
 
-	if err := attach.IncreaseDownloadCount(); err != nil {
-		ctx.ServerError("IncreaseDownloadCount", err)
-		return
+	if size != 0 {
+		thumbnail, err := attachment.OpenThumbnail(attach, size)
+		if err != nil {
+			ctx.ServerError("OpenThumbnail", err)
+			return
+		}
+		// images without a thumbnail of the size are served as they are
+		if thumbnail != nil {
+			defer thumbnail.Close()
+			serveAttachmentThumbnail(ctx, attach, size, thumbnail)
+			return
+		}
+	}
+
+	// a video which is played requests many ranges of it, only the request of its start counts as a download
+	if rng := ctx.Req.Header.Get("Range"); rng == "" || strings.HasPrefix(rng, "bytes=0-") {
+		if err := attach.IncreaseDownloadCount(); err != nil {
+			ctx.ServerError("IncreaseDownloadCount", err)
+			return
+		}
 	}
 
 	if setting.Attachment.ServeDirect {
@@ -143,7 +172,32 @@ func GetAttachment(ctx *context.Context) {
 	}
 	defer fr.Close()
 
-	if err = common.ServeData(ctx, attach.Name, attach.Size, fr); err != nil {
+	if err = common.ServeContent(ctx, attach.Name, attach.CreatedUnix.AsTime(), fr); err != nil {
+		ctx.ServerError("ServeContent", err)
+		return
+	}
+}
+
+// serveAttachmentThumbnail serves a thumbnail, viewing it does not count as a download of the attachment
+func serveAttachmentThumbnail(ctx *context.Context, attach *models.Attachment, size int, thumbnail storage.Object) {
+	if setting.Attachment.ServeDirect {
+		u, err := storage.Attachments.URL(attach.ThumbnailRelativePath(size), attach.Name)
+		if u != nil && err == nil {
+			ctx.Redirect(u.String())
+			return
+		}
+	}
+
+	if httpcache.HandleGenericETagCache(ctx.Req, ctx.Resp, fmt.Sprintf(`"%s-%d"`, attach.UUID, size)) {
+		return
+	}
+
+	stat, err := thumbnail.Stat()
+	if err != nil {
+		ctx.ServerError("Stat", err)
+		return
+	}
+	if err = common.ServeData(ctx, attach.Name, stat.Size(), thumbnail); err != nil {
 		ctx.ServerError("ServeData", err)
 		return
 	}
@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/issueform"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/markdown"
@@ -49,6 +50,7 @@ const (
 
 	issueTemplateKey      = "IssueTemplate"
 	issueTemplateTitleKey = "IssueTemplateTitle"
+	issueFormKey          = "IssueForm"
 )
 
 var (
@@ -119,6 +121,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 
 	var (
 		assigneeID        = ctx.FormInt64("assignee")
+		workflowStateID   = ctx.FormInt64("workflow_state")
 		posterID          int64
 		mentionedID       int64
 		reviewRequestedID int64
@@ -180,6 +183,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			ReviewRequestedID: reviewRequestedID,
 			IsPull:            isPullOption,
 			IssueIDs:          issueIDs,
+			WorkflowStateID:   workflowStateID,
 		})
 		if err != nil {
 			ctx.ServerError("GetIssueStats", err)
@@ -232,6 +236,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			LabelIDs:          labelIDs,
 			SortType:          sortType,
 			IssueIDs:          issueIDs,
+			WorkflowStateID:   workflowStateID,
 		})
 		if err != nil {
 			ctx.ServerError("Issues", err)
@@ -246,6 +251,19 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 		return
 	}
 
+	relations, err := issueList.GetRelations()
+	if err != nil {
+		ctx.ServerError("GetRelations", err)
+		return
+	}
+	for issueID := range relations {
+		if relations[issueID], err = issue_service.FilterReadableIssueRelations(ctx.User, repo.ID, relations[issueID]); err != nil {
+			ctx.ServerError("FilterReadableIssueRelations", err)
+			return
+		}
+	}
+	ctx.Data["IssueRelations"] = relations
+
 	// Get posters.
 	for i := range issues {
 		// Check read status
@@ -333,6 +351,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	ctx.Data["SortType"] = sortType
 	ctx.Data["MilestoneID"] = milestoneID
 	ctx.Data["AssigneeID"] = assigneeID
+	ctx.Data["WorkflowStateID"] = workflowStateID
 	ctx.Data["IsShowClosed"] = isShowClosed
 	ctx.Data["Keyword"] = keyword
 	if isShowClosed {
@@ -348,6 +367,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	pager.AddParam(ctx, "labels", "SelectLabels")
 	pager.AddParam(ctx, "milestone", "MilestoneID")
 	pager.AddParam(ctx, "assignee", "AssigneeID")
+	pager.AddParam(ctx, "workflow_state", "WorkflowStateID")
 	ctx.Data["Page"] = pager
 }
 
@@ -379,14 +399,21 @@ func Issues(ctx *context.Context) {
 	var err error
 	// Get milestones
 	ctx.Data["Milestones"], _, err = models.GetMilestones(models.GetMilestonesOption{
-		RepoID: ctx.Repo.Repository.ID,
-		State:  api.StateType(ctx.FormString("state")),
+		RepoID:  ctx.Repo.Repository.ID,
+		OwnerID: ctx.Repo.Repository.OwnerID,
+		State:   api.StateType(ctx.FormString("state")),
 	})
 	if err != nil {
 		ctx.ServerError("GetAllRepoMilestones", err)
 		return
 	}
 
+	ctx.Data["WorkflowStates"], err = models.GetIssueWorkflowStatesByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetIssueWorkflowStatesByRepoID", err)
+		return
+	}
+
 	ctx.Data["CanWriteIssuesOrPulls"] = ctx.Repo.CanWriteIssuesOrPulls(isPullList)
 
 	ctx.HTML(http.StatusOK, tplIssues)
@@ -396,16 +423,18 @@ func Issues(ctx *context.Context) {
 func RetrieveRepoMilestonesAndAssignees(ctx *context.Context, repo *models.Repository) {
 	var err error
 	ctx.Data["OpenMilestones"], _, err = models.GetMilestones(models.GetMilestonesOption{
-		RepoID: repo.ID,
-		State:  api.StateOpen,
+		RepoID:  repo.ID,
+		OwnerID: repo.OwnerID,
+		State:   api.StateOpen,
 	})
 	if err != nil {
 		ctx.ServerError("GetMilestones", err)
 		return
 	}
 	ctx.Data["ClosedMilestones"], _, err = models.GetMilestones(models.GetMilestonesOption{
-		RepoID: repo.ID,
-		State:  api.StateClosed,
+		RepoID:  repo.ID,
+		OwnerID: repo.OwnerID,
+		State:   api.StateClosed,
 	})
 	if err != nil {
 		ctx.ServerError("GetMilestones", err)
@@ -418,6 +447,12 @@ func RetrieveRepoMilestonesAndAssignees(ctx *context.Context, repo *models.Repos
 		return
 	}
 
+	ctx.Data["WorkflowStates"], err = models.GetIssueWorkflowStatesByRepoID(repo.ID)
+	if err != nil {
+		ctx.ServerError("GetIssueWorkflowStatesByRepoID", err)
+		return
+	}
+
 	handleTeamMentions(ctx)
 }
 
@@ -734,31 +769,83 @@ func setTemplateIfExists(ctx *context.Context, ctxDataKey string, possibleDirs [
 			}
 			ctx.Data[issueTemplateTitleKey] = meta.Title
 			ctx.Data[ctxDataKey] = templateBody
-			labelIDs := make([]string, 0, len(meta.Labels))
-			if repoLabels, err := models.GetLabelsByRepoID(ctx.Repo.Repository.ID, "", models.ListOptions{}); err == nil {
-				ctx.Data["Labels"] = repoLabels
-				if ctx.Repo.Owner.IsOrganization() {
-					if orgLabels, err := models.GetLabelsByOrgID(ctx.Repo.Owner.ID, ctx.FormString("sort"), models.ListOptions{}); err == nil {
-						ctx.Data["OrgLabels"] = orgLabels
-						repoLabels = append(repoLabels, orgLabels...)
-					}
-				}
+			setTemplateLabels(ctx, meta.Labels)
+			return
+		}
+	}
+}
 
-				for _, metaLabel := range meta.Labels {
-					for _, repoLabel := range repoLabels {
-						if strings.EqualFold(repoLabel.Name, metaLabel) {
-							repoLabel.IsChecked = true
-							labelIDs = append(labelIDs, fmt.Sprintf("%d", repoLabel.ID))
-							break
-						}
-					}
+// setTemplateLabels preselects the labels of the repository named by an issue template
+func setTemplateLabels(ctx *context.Context, labels []string) {
+	labelIDs := make([]string, 0, len(labels))
+	if repoLabels, err := models.GetLabelsByRepoID(ctx.Repo.Repository.ID, "", models.ListOptions{}); err == nil {
+		ctx.Data["Labels"] = repoLabels
+		if ctx.Repo.Owner.IsOrganization() {
+			if orgLabels, err := models.GetLabelsByOrgID(ctx.Repo.Owner.ID, ctx.FormString("sort"), models.ListOptions{}); err == nil {
+				ctx.Data["OrgLabels"] = orgLabels
+				repoLabels = append(repoLabels, orgLabels...)
+			}
+		}
+
+		for _, metaLabel := range labels {
+			for _, repoLabel := range repoLabels {
+				if strings.EqualFold(repoLabel.Name, metaLabel) {
+					repoLabel.IsChecked = true
+					labelIDs = append(labelIDs, fmt.Sprintf("%d", repoLabel.ID))
+					break
 				}
 			}
-			ctx.Data["HasSelectedLabel"] = len(labelIDs) > 0
-			ctx.Data["label_ids"] = strings.Join(labelIDs, ",")
-			return
 		}
 	}
+	ctx.Data["HasSelectedLabel"] = len(labelIDs) > 0
+	ctx.Data["label_ids"] = strings.Join(labelIDs, ",")
+}
+
+// setIssueFormIfExists sets the issue form named by the template parameter to be filled in with values,
+// and returns it, or nil if there is no such issue form
+func setIssueFormIfExists(ctx *context.Context, values map[string][]string) *api.IssueTemplate {
+	name := ctx.FormString("template")
+	if !issueform.IsFormFile(name) {
+		return nil
+	}
+	form := ctx.IssueFormFromDefaultBranch(name)
+	if form == nil {
+		return nil
+	}
+
+	rendered := make([]string, len(form.Fields))
+	for i, field := range form.Fields {
+		if field.Type != api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		var err error
+		rendered[i], err = markdown.RenderString(&markup.RenderContext{
+			URLPrefix: ctx.Repo.RepoLink,
+			Metas:     ctx.Repo.Repository.ComposeMetas(),
+			GitRepo:   ctx.Repo.GitRepo,
+			Ctx:       ctx,
+		}, field.Attributes.Value)
+		if err != nil {
+			log.Debug("could not render markdown field of %s [%s]: %v", name, ctx.Repo.Repository.FullName(), err)
+		}
+	}
+
+	ctx.Data[issueFormKey] = form
+	ctx.Data["IssueFormMarkdown"] = rendered
+	ctx.Data["IssueFormValues"] = values
+	ctx.Data[issueTemplateTitleKey] = form.Title
+	return form
+}
+
+// issueFormValues returns the values submitted for the fields of an issue form by field id
+func issueFormValues(ctx *context.Context) map[string][]string {
+	values := make(map[string][]string)
+	for key, value := range ctx.Req.PostForm {
+		if id := strings.TrimPrefix(key, "form-field-"); id != key {
+			values[id] = value
+		}
+	}
+	return values
 }
 
 // NewIssue render creating issue page
@@ -805,7 +892,11 @@ func NewIssue(ctx *context.Context) {
 	}
 
 	RetrieveRepoMetas(ctx, ctx.Repo.Repository, false)
-	setTemplateIfExists(ctx, issueTemplateKey, context.IssueTemplateDirCandidates, IssueTemplateCandidates)
+	if form := setIssueFormIfExists(ctx, map[string][]string{}); form != nil {
+		setTemplateLabels(ctx, form.Labels)
+	} else {
+		setTemplateIfExists(ctx, issueTemplateKey, context.IssueTemplateDirCandidates, IssueTemplateCandidates)
+	}
 	if ctx.Written() {
 		return
 	}
@@ -948,6 +1039,13 @@ func NewIssuePost(ctx *context.Context) {
 		return
 	}
 
+	formValues := issueFormValues(ctx)
+	issueForm := setIssueFormIfExists(ctx, formValues)
+	if issueform.IsFormFile(ctx.FormString("template")) && issueForm == nil {
+		ctx.RenderWithErr(ctx.Tr("repo.issues.new.form_not_exist"), tplIssueNew, form)
+		return
+	}
+
 	if setting.Attachment.Enabled {
 		attachments = form.Files
 	}
@@ -972,10 +1070,29 @@ func NewIssuePost(ctx *context.Context) {
 		Ref:         form.Ref,
 	}
 
-	if err := issue_service.NewIssue(repo, issue, labelIDs, attachments, assigneeIDs); err != nil {
+	var err error
+	if issueForm != nil {
+		err = issue_service.NewIssueFromForm(repo, issue, labelIDs, nil, assigneeIDs, issueForm, formValues)
+	} else {
+		err = issue_service.NewIssue(repo, issue, labelIDs, attachments, assigneeIDs)
+	}
+	if err != nil {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err.Error())
 			return
+		} else if models.IsErrRepoInteractionLimited(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.interaction_limit.limited"), tplIssueNew, form)
+			return
+		} else if issueform.IsErrInvalidValue(err) {
+			invalid := err.(issueform.ErrInvalidValue)
+			label := invalid.FieldID
+			for _, field := range issueForm.Fields {
+				if field.ID == invalid.FieldID {
+					label = field.Attributes.Label
+				}
+			}
+			ctx.RenderWithErr(ctx.Tr("repo.issues.new.form_invalid_value", label, invalid.Reason), tplIssueNew, form)
+			return
 		}
 		ctx.ServerError("NewIssue", err)
 		return
@@ -1297,7 +1414,7 @@ func ViewIssue(ctx *context.Context) {
 				return
 			}
 
-			comment.RenderedContent, err = markdown.RenderString(&markup.RenderContext{
+			comment.RenderedContent, err = markdown.RenderStringCached(comment.RenderCacheKey(), &markup.RenderContext{
 				URLPrefix: ctx.Repo.RepoLink,
 				Metas:     ctx.Repo.Repository.ComposeMetas(),
 				GitRepo:   ctx.Repo.GitRepo,
@@ -1326,6 +1443,21 @@ func ViewIssue(ctx *context.Context) {
 				ctx.ServerError("LoadLabel", err)
 				return
 			}
+		} else if comment.Type == models.CommentTypeWorkflowState {
+			if err = comment.LoadWorkflowState(); err != nil {
+				ctx.ServerError("LoadWorkflowState", err)
+				return
+			}
+			ghostState := &models.IssueWorkflowState{
+				ID:   -1,
+				Name: ctx.Tr("repo.issues.deleted_workflow_state"),
+			}
+			if comment.OldWorkflowStateID > 0 && comment.OldWorkflowState == nil {
+				comment.OldWorkflowState = ghostState
+			}
+			if comment.WorkflowStateID > 0 && comment.WorkflowState == nil {
+				comment.WorkflowState = ghostState
+			}
 		} else if comment.Type == models.CommentTypeMilestone {
 			if err = comment.LoadMilestone(); err != nil {
 				ctx.ServerError("LoadMilestone", err)
@@ -1374,7 +1506,7 @@ func ViewIssue(ctx *context.Context) {
 				}
 			}
 		} else if comment.Type == models.CommentTypeCode || comment.Type == models.CommentTypeReview || comment.Type == models.CommentTypeDismissReview {
-			comment.RenderedContent, err = markdown.RenderString(&markup.RenderContext{
+			comment.RenderedContent, err = markdown.RenderStringCached(comment.RenderCacheKey(), &markup.RenderContext{
 				URLPrefix: ctx.Repo.RepoLink,
 				Metas:     ctx.Repo.Repository.ComposeMetas(),
 				GitRepo:   ctx.Repo.GitRepo,
@@ -1583,6 +1715,12 @@ func ViewIssue(ctx *context.Context) {
 		return
 	}
 
+	ctx.Data["IssueCustomFields"], err = models.GetIssueCustomFieldEntries(issue)
+	if err != nil {
+		ctx.ServerError("GetIssueCustomFieldEntries", err)
+		return
+	}
+
 	ctx.Data["Participants"] = participants
 	ctx.Data["NumParticipants"] = len(participants)
 	ctx.Data["Issue"] = issue
@@ -1777,6 +1915,63 @@ func UpdateIssueMilestone(ctx *context.Context) {
 	})
 }
 
+// UpdateIssueWorkflowState change issue's or pull's workflow state
+func UpdateIssueWorkflowState(ctx *context.Context) {
+	issues := getActionIssues(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	stateID := ctx.FormInt64("id")
+	for _, issue := range issues {
+		if err := models.ChangeIssueWorkflowState(issue, ctx.User, stateID); err != nil {
+			if models.IsErrIssueWorkflowStateNotExist(err) {
+				ctx.NotFound("ChangeIssueWorkflowState", err)
+			} else {
+				ctx.ServerError("ChangeIssueWorkflowState", err)
+			}
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+// UpdateIssueCustomFields sets the values of the custom fields of an issue or pull from the sidebar form
+func UpdateIssueCustomFields(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	fields, err := models.GetIssueCustomFieldsForRepo(ctx.Repo.Repository)
+	if err != nil {
+		ctx.ServerError("GetIssueCustomFieldsForRepo", err)
+		return
+	}
+	// the form has an input for every field, an empty one removes the value
+	values := make(map[string]string, len(fields))
+	for _, f := range fields {
+		values[f.Name] = ctx.FormString(fmt.Sprintf("field_%d", f.ID))
+	}
+
+	if err := models.SetIssueCustomFieldValues(issue, values); err != nil {
+		if models.IsErrInvalidIssueCustomFieldValue(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.custom_fields.invalid_value", err.(models.ErrInvalidIssueCustomFieldValue).Name))
+		} else {
+			ctx.ServerError("SetIssueCustomFieldValues", err)
+			return
+		}
+	}
+	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+}
+
 // UpdateIssueAssignee change issue's or pull's assignee
 func UpdateIssueAssignee(ctx *context.Context) {
 	issues := getActionIssues(ctx)
@@ -2111,6 +2306,10 @@ func NewComment(ctx *context.Context) {
 
 	comment, err := comment_service.CreateIssueComment(ctx.User, ctx.Repo.Repository, issue, form.Content, attachments)
 	if err != nil {
+		if models.IsErrRepoInteractionLimited(err) {
+			ctx.Flash.Error(ctx.Tr("repo.interaction_limit.limited"))
+			return
+		}
 		ctx.ServerError("CreateIssueComment", err)
 		return
 	}
@@ -55,6 +55,7 @@ func Migrate(ctx *context.Context) {
 	ctx.Data["issues"] = ctx.FormString("issues") == "1"
 	ctx.Data["pull_requests"] = ctx.FormString("pull_requests") == "1"
 	ctx.Data["releases"] = ctx.FormString("releases") == "1"
+	ctx.Data["map_users_by_email"] = ctx.FormString("map_users_by_email") == "1"
 
 	ctxUser := checkContextUser(ctx, ctx.FormInt64("org"))
 	if ctx.Written() {
@@ -219,6 +220,8 @@ func MigratePost(ctx *context.Context) {
 		Comments:       form.Issues || form.PullRequests,
 		PullRequests:   form.PullRequests,
 		Releases:       form.Releases,
+
+		MapUsersByEmail: form.MapUsersByEmail && ctx.User.IsAdmin,
 	}
 	if opts.Mirror {
 		opts.Issues = false
@@ -458,6 +458,18 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 		ctx.Data["GetCommitMessages"] = pull_service.GetSquashMergeCommitMessages(pull)
 	}
 
+	// The merge form is still usable if the default messages cannot be generated
+	defaultMergeMessage, err := pull_service.GetDefaultMergeMessage(pull, models.MergeStyleMerge)
+	if err != nil {
+		log.Error("GetDefaultMergeMessage: %v", err)
+	}
+	ctx.Data["DefaultMergeTitle"], ctx.Data["DefaultMergeBody"] = pull_service.SplitCommitMessage(defaultMergeMessage)
+	defaultSquashMessage, err := pull_service.GetDefaultMergeMessage(pull, models.MergeStyleSquash)
+	if err != nil {
+		log.Error("GetDefaultMergeMessage: %v", err)
+	}
+	ctx.Data["DefaultSquashTitle"], ctx.Data["DefaultSquashBody"] = pull_service.SplitCommitMessage(defaultSquashMessage)
+
 	sha, err := baseGitRepo.GetRefCommitID(pull.GetGitRefName())
 	if err != nil {
 		if git.IsErrNotExist(err) {
@@ -886,14 +898,10 @@ func MergePullRequest(ctx *context.Context) {
 
 	message := strings.TrimSpace(form.MergeTitleField)
 	if len(message) == 0 {
-		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleRebaseMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
-			message = pr.GetDefaultSquashMessage()
+		message, err = pull_service.GetDefaultMergeMessage(pr, models.MergeStyle(form.Do))
+		if err != nil {
+			ctx.ServerError("GetDefaultMergeMessage", err)
+			return
 		}
 	}
 
@@ -1138,6 +1146,10 @@ func CompareAndPullRequestPost(ctx *context.Context) {
 			}
 			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pullIssue.Index))
 			return
+		} else if models.IsErrRepoInteractionLimited(err) {
+			ctx.Flash.Error(ctx.Tr("repo.interaction_limit.limited"))
+			ctx.Redirect(ctx.Link)
+			return
 		}
 		ctx.ServerError("NewPullRequest", err)
 		return
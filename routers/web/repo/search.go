@@ -31,7 +31,7 @@ func Search(ctx *context.Context) {
 	isMatch := queryType == "match"
 
 	total, searchResults, searchResultLanguages, err := code_indexer.PerformSearch([]int64{ctx.Repo.Repository.ID},
-		language, keyword, page, setting.UI.RepoSearchPagingNum, isMatch)
+		language, "", keyword, page, setting.UI.RepoSearchPagingNum, isMatch)
 	if err != nil {
 		ctx.ServerError("SearchResults", err)
 		return
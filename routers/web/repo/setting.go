@@ -31,6 +31,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/utils"
 	"code.gitea.io/gitea/services/forms"
+	issue_service "code.gitea.io/gitea/services/issue"
 	"code.gitea.io/gitea/services/mailer"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	repo_service "code.gitea.io/gitea/services/repository"
@@ -61,6 +62,10 @@ func Settings(ctx *context.Context) {
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
 
+	if unit, err := ctx.Repo.Repository.GetUnit(models.UnitTypeIssues); err == nil {
+		ctx.Data["IssueBranchNameTemplate"] = unit.IssuesConfig().BranchNameTemplate
+	}
+
 	ctx.HTML(http.StatusOK, tplSettingsOptions)
 }
 
@@ -400,6 +405,12 @@ func SettingsPost(ctx *context.Context) {
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeIssues)
 		} else if form.EnableIssues && !form.EnableExternalTracker && !models.UnitTypeIssues.UnitGlobalDisabled() {
+			branchNameTemplate := strings.TrimSpace(form.IssueBranchNameTemplate)
+			if err := issue_service.ValidateIssueBranchNameTemplate(branchNameTemplate); err != nil {
+				ctx.Flash.Error(ctx.Tr("repo.settings.issue_branch_name_template_error", err.Error()))
+				ctx.Redirect(repo.Link() + "/settings")
+				return
+			}
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeIssues,
@@ -407,6 +418,7 @@ func SettingsPost(ctx *context.Context) {
 					EnableTimetracker:                form.EnableTimetracker,
 					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               form.EnableIssueDependencies,
+					BranchNameTemplate:               branchNameTemplate,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalTracker)
@@ -442,6 +454,8 @@ func SettingsPost(ctx *context.Context) {
 					AutodetectManualMerge:         form.EnableAutodetectManualMerge,
 					DefaultDeleteBranchAfterMerge: form.DefaultDeleteBranchAfterMerge,
 					DefaultMergeStyle:             models.MergeStyle(form.PullsDefaultMergeStyle),
+					DefaultMergeMessageTemplate:   strings.TrimSpace(form.PullsDefaultMergeMessageTemplate),
+					DefaultSquashMessageTemplate:  strings.TrimSpace(form.PullsDefaultSquashMessageTemplate),
 				},
 			})
 		} else if !models.UnitTypePullRequests.UnitGlobalDisabled() {
@@ -843,6 +857,13 @@ func CollaborationPost(ctx *context.Context) {
 
 // ChangeCollaborationAccessMode response for changing access of a collaboration
 func ChangeCollaborationAccessMode(ctx *context.Context) {
+	if ctx.FormString("mode") == "guest" {
+		if err := ctx.Repo.Repository.ChangeCollaborationToGuest(ctx.FormInt64("uid")); err != nil {
+			log.Error("ChangeCollaborationToGuest: %v", err)
+		}
+		return
+	}
+
 	if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(
 		ctx.FormInt64("uid"),
 		models.AccessMode(ctx.FormInt("mode"))); err != nil {
@@ -237,6 +237,11 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 		} else {
 			protectBranch.StatusCheckContexts = nil
 		}
+		if f.EnableStatusCheck && f.StatusCheckMaxAge > 0 {
+			protectBranch.StatusCheckMaxAge = f.StatusCheckMaxAge
+		} else {
+			protectBranch.StatusCheckMaxAge = 0
+		}
 
 		protectBranch.RequiredApprovals = f.RequiredApprovals
 		protectBranch.EnableApprovalsWhitelist = f.EnableApprovalsWhitelist
@@ -27,9 +27,11 @@ import (
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/preview"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/util"
 )
 
 const (
@@ -476,6 +478,12 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 	ctx.Data["IsDisplayingRendered"] = isDisplayingRendered
 	ctx.Data["IsTextSource"] = isTextFile || isDisplayingSource
 
+	// previews are generated of blobs, the one of a LFS file would be that of its pointer
+	if !isLFSFile && fileSize <= setting.Preview.MaxFileSize && preview.FindProvider(blob.Name(), buf) != nil {
+		ctx.Data["PreviewLink"] = fmt.Sprintf("%s/api/v1/repos/%s/preview/%s?ref=%s", setting.AppSubURL,
+			ctx.Repo.Repository.FullName(), util.PathEscapeSegments(ctx.Repo.TreePath), ctx.Repo.CommitID)
+	}
+
 	// Check LFS Lock
 	lfsLock, err := ctx.Repo.Repository.GetTreePathLock(ctx.Repo.TreePath)
 	ctx.Data["LFSLock"] = lfsLock
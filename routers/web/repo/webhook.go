@@ -168,6 +168,7 @@ func ParseHookEvent(form forms.WebhookForm) *models.HookEvent {
 			IssueMilestone:       form.IssueMilestone,
 			IssueComment:         form.IssueComment,
 			Release:              form.Release,
+			Schedule:             form.Schedule,
 			Push:                 form.Push,
 			PullRequest:          form.PullRequest,
 			PullRequestAssign:    form.PullRequestAssign,
@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/org"
+	profile_service "code.gitea.io/gitea/services/profile"
 )
 
 // GetUserByName get user by name
@@ -280,6 +281,11 @@ func Profile(ctx *context.Context) {
 
 		total = int(count)
 	default:
+		if err = loadProfileShowcase(ctx, ctxUser); err != nil {
+			ctx.ServerError("loadProfileShowcase", err)
+			return
+		}
+
 		repos, count, err = models.SearchRepository(&models.SearchRepoOptions{
 			ListOptions: models.ListOptions{
 				PageSize: setting.UI.User.RepoPagingNum,
@@ -313,6 +319,22 @@ func Profile(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplProfile)
 }
 
+// loadProfileShowcase loads the profile README and the pinned repositories shown above the repositories of a user
+func loadProfileShowcase(ctx *context.Context, ctxUser *models.User) error {
+	profileReadme, err := profile_service.GetProfileReadme(ctx, ctxUser)
+	if err != nil {
+		return fmt.Errorf("GetProfileReadme: %v", err)
+	}
+	ctx.Data["ProfileReadme"] = profileReadme
+
+	pinnedRepos, err := models.GetPinnedRepos(ctxUser.ID, ctx.User)
+	if err != nil {
+		return fmt.Errorf("GetPinnedRepos: %v", err)
+	}
+	ctx.Data["PinnedRepos"] = pinnedRepos
+	return nil
+}
+
 // Action response for follow/unfollow user request
 func Action(ctx *context.Context) {
 	u := GetUserByParams(ctx)
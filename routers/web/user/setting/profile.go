@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/typesniffer"
 	"code.gitea.io/gitea/modules/util"
@@ -106,8 +107,10 @@ func ProfilePost(ctx *context.Context) {
 			ctx.Redirect(setting.AppSubURL + "/user/settings")
 			return
 		}
+		oldName := ctx.User.Name
 		ctx.User.Name = form.Name
 		ctx.User.LowerName = strings.ToLower(form.Name)
+		notification.NotifyRenameUser(ctx.User, ctx.User, oldName)
 	}
 
 	ctx.User.FullName = form.FullName
@@ -0,0 +1,72 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+const tplUnsubscribe base.TplName = "user/unsubscribe"
+
+// getUnsubscribeIssue returns the user and the issue of the unsubscribe token of the request
+func getUnsubscribeIssue(ctx *context.Context) (*models.User, *models.Issue) {
+	userID, issueID, ok := mailer.ParseUnsubscribeToken(ctx.Params(":token"))
+	if !ok {
+		ctx.NotFound("ParseUnsubscribeToken", nil)
+		return nil, nil
+	}
+	u, err := models.GetUserByID(userID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetUserByID", models.IsErrUserNotExist, err)
+		return nil, nil
+	}
+	issue, err := models.GetIssueByID(issueID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByID", models.IsErrIssueNotExist, err)
+		return nil, nil
+	}
+	if err := issue.LoadRepo(); err != nil {
+		ctx.ServerError("LoadRepo", err)
+		return nil, nil
+	}
+	return u, issue
+}
+
+// Unsubscribe shows the page confirming to mute an issue from the link of a notification mail.
+// The issue is only muted on POST, as links may be followed automatically by mail clients.
+func Unsubscribe(ctx *context.Context) {
+	_, issue := getUnsubscribeIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("mail.unsubscribe")
+	ctx.Data["Issue"] = issue
+	ctx.HTML(http.StatusOK, tplUnsubscribe)
+}
+
+// UnsubscribePost mutes an issue for the user of an unsubscribe token. It also handles
+// the one-click unsubscribe requests sent by mail clients for the List-Unsubscribe-Post header.
+func UnsubscribePost(ctx *context.Context) {
+	u, issue := getUnsubscribeIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.CreateOrUpdateIssueWatch(u.ID, issue.ID, false); err != nil {
+		ctx.ServerError("CreateOrUpdateIssueWatch", err)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("mail.unsubscribe")
+	ctx.Data["Issue"] = issue
+	ctx.Data["IsUnsubscribed"] = true
+	ctx.HTML(http.StatusOK, tplUnsubscribe)
+}
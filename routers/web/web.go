@@ -27,6 +27,7 @@ import (
 	"code.gitea.io/gitea/routers/web/dev"
 	"code.gitea.io/gitea/routers/web/events"
 	"code.gitea.io/gitea/routers/web/explore"
+	"code.gitea.io/gitea/routers/web/healthcheck"
 	"code.gitea.io/gitea/routers/web/org"
 	"code.gitea.io/gitea/routers/web/repo"
 	"code.gitea.io/gitea/routers/web/user"
@@ -43,7 +44,6 @@ import (
 	"github.com/NYTimes/gziphandler"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/cors"
-	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tstranex/u2f"
 )
 
@@ -130,12 +130,15 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 
 	// prometheus metrics endpoint - do not need to go through contexter
 	if setting.Metrics.Enabled {
-		c := metrics.NewCollector()
-		prometheus.MustRegister(c)
+		metrics.Register()
 
 		routes.Get("/metrics", append(common, Metrics)...)
 	}
 
+	// health and readiness checks for load balancers - do not need to go through contexter
+	routes.Get("/api/healthz", healthcheck.Check)
+	routes.Get("/api/readyz", healthcheck.Ready)
+
 	routes.Get("/ssh_info", func(rw http.ResponseWriter, req *http.Request) {
 		if !git.SupportProcReceive {
 			rw.WriteHeader(404)
@@ -234,6 +237,7 @@ func RegisterRoutes(m *web.Route) {
 	// for health check
 	m.Get("/", Home)
 	m.Get("/.well-known/openid-configuration", user.OIDCWellKnown)
+	m.Get("/.well-known/webfinger", WebfingerQuery)
 	m.Group("/explore", func() {
 		m.Get("", func(ctx *context.Context) {
 			ctx.Redirect(setting.AppSubURL + "/explore/repos")
@@ -375,6 +379,7 @@ func RegisterRoutes(m *web.Route) {
 	// ***** END: User *****
 
 	m.Get("/avatar/{hash}", user.AvatarByEmailHash)
+	m.Combo("/unsubscribe/{token}", ignSignInAndCsrf).Get(user.Unsubscribe).Post(user.UnsubscribePost)
 
 	adminReq := context.Toggle(&context.ToggleOptions{SignInRequired: true, AdminRequired: true})
 
@@ -723,6 +728,7 @@ func RegisterRoutes(m *web.Route) {
 				m.Post("/reactions/{action}", bindIgnErr(forms.ReactionForm{}), repo.ChangeIssueReaction)
 				m.Post("/lock", reqRepoIssueWriter, bindIgnErr(forms.IssueLockForm{}), repo.LockIssue)
 				m.Post("/unlock", reqRepoIssueWriter, repo.UnlockIssue)
+				m.Post("/custom_fields", reqRepoIssuesOrPullsWriter, repo.UpdateIssueCustomFields)
 			}, context.RepoMustNotBeArchived())
 			m.Group("/{index}", func() {
 				m.Get("/attachments", repo.GetIssueAttachments)
@@ -731,6 +737,7 @@ func RegisterRoutes(m *web.Route) {
 
 			m.Post("/labels", reqRepoIssuesOrPullsWriter, repo.UpdateIssueLabel)
 			m.Post("/milestone", reqRepoIssuesOrPullsWriter, repo.UpdateIssueMilestone)
+			m.Post("/workflow_state", reqRepoIssuesOrPullsWriter, repo.UpdateIssueWorkflowState)
 			m.Post("/projects", reqRepoIssuesOrPullsWriter, repo.UpdateIssueProject)
 			m.Post("/assignee", reqRepoIssuesOrPullsWriter, repo.UpdateIssueAssignee)
 			m.Post("/request_review", reqRepoIssuesOrPullsReader, repo.UpdatePullReviewRequest)
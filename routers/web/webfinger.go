@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/federation"
+)
+
+// WebfingerQuery returns the actor of a user for its handle, e.g. acct:user@example.com, or profile URL,
+// so users of other instances can follow it
+func WebfingerQuery(ctx *context.Context) {
+	if !setting.Federation.Enabled {
+		ctx.Error(http.StatusNotFound)
+		return
+	}
+
+	resource := ctx.FormString("resource")
+	appURL, _ := url.Parse(setting.AppURL)
+
+	var name string
+	if strings.HasPrefix(resource, "acct:") {
+		handle := strings.TrimPrefix(strings.TrimPrefix(resource, "acct:"), "@")
+		idx := strings.LastIndexByte(handle, '@')
+		if idx <= 0 || !strings.EqualFold(handle[idx+1:], appURL.Host) {
+			ctx.Error(http.StatusNotFound)
+			return
+		}
+		name = handle[:idx]
+	} else if strings.HasPrefix(resource, setting.AppURL) {
+		name = strings.TrimPrefix(resource, setting.AppURL)
+	}
+	if name == "" || strings.Contains(name, "/") {
+		ctx.Error(http.StatusNotFound)
+		return
+	}
+
+	user, err := models.GetUserByName(name)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusNotFound)
+		} else {
+			log.Error("GetUserByName: %v", err)
+			ctx.Error(http.StatusInternalServerError)
+		}
+		return
+	}
+	if !federation.IsFederatedUser(user) {
+		ctx.Error(http.StatusNotFound)
+		return
+	}
+
+	actorURI := federation.ActorURI(models.FederationOwnerUser, user.ID)
+	ctx.Resp.Header().Set("Access-Control-Allow-Origin", "*")
+	ctx.Resp.Header().Set("Content-Type", "application/jrd+json")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(ctx.Resp).Encode(&activitypub.WebFingerResource{
+		Subject: "acct:" + user.Name + "@" + appURL.Host,
+		Aliases: []string{user.HTMLURL(), actorURI},
+		Links: []activitypub.WebFingerLink{
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: user.HTMLURL()},
+			{Rel: "self", Type: activitypub.ContentType, Href: actorURI},
+		},
+	}); err != nil {
+		log.Error("Render WebFinger resource failed: %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package advisory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	dependency_indexer "code.gitea.io/gitea/modules/indexer/dependency"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// CheckRepositories updates the alerts of the repositories whose dependencies or advisories changed since their last check
+func CheckRepositories(ctx context.Context) error {
+	settings, err := models.FindEnabledRepoVulnerabilitySettings()
+	if err != nil {
+		return err
+	}
+	latestAdvisory, err := models.GetLatestSecurityAdvisoryUpdate()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range settings {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+		}
+
+		repo, err := models.GetRepositoryByID(s.RepoID)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if err := checkRepository(repo, s, latestAdvisory); err != nil {
+			log.Error("Unable to check the vulnerability alerts of %s: %v", repo.FullName(), err)
+		}
+	}
+	return nil
+}
+
+func checkRepository(repo *models.Repository, s *models.RepoVulnerabilitySetting, latestAdvisory timeutil.TimeStamp) error {
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeDependencies)
+	if err != nil {
+		return err
+	}
+	if status.CommitSha == "" {
+		// the alerts are checked once the dependencies were analyzed
+		return dependency_indexer.UpdateRepoIndexer(repo)
+	}
+	if status.CommitSha == s.CheckedCommitSha && s.CheckedUnix > latestAdvisory {
+		return nil
+	}
+
+	if err := updateAlerts(repo, s); err != nil {
+		return err
+	}
+	s.CheckedCommitSha = status.CommitSha
+	s.CheckedUnix = timeutil.TimeStampNow()
+	return models.UpdateRepoVulnerabilityChecked(s)
+}
+
+// updateAlerts opens an alert for each dependency of the repository on a vulnerable version
+// and marks the alerts of the dependencies which are not vulnerable anymore as fixed
+func updateAlerts(repo *models.Repository, s *models.RepoVulnerabilitySetting) error {
+	deps, _, err := models.FindRepoDependencies(&models.FindRepoDependencyOptions{RepoID: repo.ID})
+	if err != nil {
+		return err
+	}
+	existing, _, err := models.FindVulnerabilityAlerts(&models.FindVulnerabilityAlertOptions{RepoID: repo.ID})
+	if err != nil {
+		return err
+	}
+
+	alertKey := func(advisoryID int64, manifest string) string {
+		return fmt.Sprintf("%d:%s", advisoryID, manifest)
+	}
+	alerts := make(map[string]*models.VulnerabilityAlert, len(existing))
+	for _, a := range existing {
+		alerts[alertKey(a.AdvisoryID, a.Manifest)] = a
+	}
+
+	packageAdvisories := make(map[string][]*models.SecurityAdvisory)
+	seen := make(map[string]bool)
+	var opened []*models.VulnerabilityAlert
+	for _, dep := range deps {
+		version, ok := resolveVersion(dep.Version)
+		if !ok {
+			continue
+		}
+
+		pkg := normalizePackageName(dep.Ecosystem, dep.Name)
+		advisories, ok := packageAdvisories[dep.Ecosystem+":"+pkg]
+		if !ok {
+			if advisories, err = models.FindSecurityAdvisoriesByPackage(dep.Ecosystem, pkg); err != nil {
+				return err
+			}
+			packageAdvisories[dep.Ecosystem+":"+pkg] = advisories
+		}
+
+		for _, adv := range advisories {
+			affected, fixed := affects(adv, version)
+			key := alertKey(adv.ID, dep.Manifest)
+			if !affected || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			alert, has := alerts[key]
+			if !has {
+				alert = &models.VulnerabilityAlert{
+					RepoID:       repo.ID,
+					AdvisoryID:   adv.ID,
+					Manifest:     dep.Manifest,
+					Ecosystem:    dep.Ecosystem,
+					Package:      dep.Name,
+					Version:      dep.Version,
+					FixedVersion: fixed,
+					State:        models.VulnerabilityAlertStateOpen,
+				}
+				if err := models.InsertVulnerabilityAlert(alert); err != nil {
+					return err
+				}
+				alert.Advisory = adv
+				opened = append(opened, alert)
+				continue
+			}
+
+			cols := make([]string, 0, 4)
+			if alert.Version != dep.Version || alert.FixedVersion != fixed {
+				alert.Version, alert.FixedVersion = dep.Version, fixed
+				cols = append(cols, "version", "fixed_version")
+			}
+			if alert.State == models.VulnerabilityAlertStateFixed {
+				// the vulnerable version came back
+				alert.State, alert.FixedUnix = models.VulnerabilityAlertStateOpen, 0
+				cols = append(cols, "state", "fixed_unix")
+				alert.Advisory = adv
+				opened = append(opened, alert)
+			}
+			if len(cols) > 0 {
+				if err := models.UpdateVulnerabilityAlert(alert, cols...); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	var fixed []*models.VulnerabilityAlert
+	for key, alert := range alerts {
+		if seen[key] || alert.State == models.VulnerabilityAlertStateFixed {
+			continue
+		}
+		alert.State, alert.FixedUnix = models.VulnerabilityAlertStateFixed, timeutil.TimeStampNow()
+		if err := models.UpdateVulnerabilityAlert(alert, "state", "fixed_unix"); err != nil {
+			return err
+		}
+		fixed = append(fixed, alert)
+	}
+
+	notifyAlerts(repo, s, opened, fixed)
+	return nil
+}
+
+// notifyAlerts opens an issue for each new alert and closes the issues of the fixed alerts if the repository
+// is configured to open issues, otherwise the repository owners are mailed about the new alerts
+func notifyAlerts(repo *models.Repository, s *models.RepoVulnerabilitySetting, opened, fixed []*models.VulnerabilityAlert) {
+	if len(opened) == 0 && len(fixed) == 0 {
+		return
+	}
+
+	if s.OpenIssues && repo.UnitEnabled(models.UnitTypeIssues) {
+		doer, err := models.GetUserByID(s.DoerID)
+		if err == nil {
+			for _, alert := range opened {
+				if err := openAlertIssue(repo, doer, alert); err != nil {
+					log.Error("Unable to open an issue for vulnerability alert %d of %s: %v", alert.ID, repo.FullName(), err)
+				}
+			}
+			for _, alert := range fixed {
+				if err := closeAlertIssue(doer, alert); err != nil {
+					log.Error("Unable to close the issue of vulnerability alert %d of %s: %v", alert.ID, repo.FullName(), err)
+				}
+			}
+			return
+		} else if !models.IsErrUserNotExist(err) {
+			log.Error("GetUserByID(%d): %v", s.DoerID, err)
+		}
+	}
+
+	if len(opened) > 0 {
+		if err := mailer.MailVulnerabilityAlerts(repo, opened); err != nil {
+			log.Error("MailVulnerabilityAlerts(%s): %v", repo.FullName(), err)
+		}
+	}
+}
+
+func openAlertIssue(repo *models.Repository, doer *models.User, alert *models.VulnerabilityAlert) error {
+	adv := alert.Advisory
+	var content strings.Builder
+	fmt.Fprintf(&content, "`%s` depends on version `%s` of `%s`, which is affected by %s.\n\n", alert.Manifest, alert.Version, alert.Package, adv.OSVID)
+	if adv.Summary != "" {
+		fmt.Fprintf(&content, "> %s\n\n", adv.Summary)
+	}
+	if adv.Severity != "" {
+		fmt.Fprintf(&content, "- Severity: %s\n", adv.Severity)
+	}
+	if len(adv.Aliases) > 0 {
+		fmt.Fprintf(&content, "- Aliases: %s\n", strings.Join(adv.Aliases, ", "))
+	}
+	if alert.FixedVersion != "" {
+		fmt.Fprintf(&content, "- Fixed in: `%s`\n", alert.FixedVersion)
+	} else {
+		content.WriteString("- Fixed in: no fixed version is known\n")
+	}
+	if adv.URL != "" {
+		fmt.Fprintf(&content, "- Advisory: %s\n", adv.URL)
+	}
+
+	issue := &models.Issue{
+		RepoID:   repo.ID,
+		Repo:     repo,
+		Title:    fmt.Sprintf("%s %s is affected by %s", alert.Package, alert.Version, adv.OSVID),
+		PosterID: doer.ID,
+		Poster:   doer,
+		Content:  content.String(),
+	}
+	if err := issue_service.NewIssue(repo, issue, nil, nil, nil); err != nil {
+		return err
+	}
+	alert.IssueID = issue.ID
+	return models.UpdateVulnerabilityAlert(alert, "issue_id")
+}
+
+func closeAlertIssue(doer *models.User, alert *models.VulnerabilityAlert) error {
+	if alert.IssueID == 0 {
+		return nil
+	}
+	issue, err := models.GetIssueByID(alert.IssueID)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if issue.IsClosed {
+		return nil
+	}
+	return issue_service.ChangeStatus(issue, doer, true)
+}
@@ -0,0 +1,314 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package advisory
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// upsertBatchSize is the number of advisories stored by a single transaction
+const upsertBatchSize = 100
+
+// osvEcosystems maps the ecosystems of dependencies to the ecosystems of the OSV database
+var osvEcosystems = map[string]string{
+	models.DependencyEcosystemGo:       "Go",
+	models.DependencyEcosystemNpm:      "npm",
+	models.DependencyEcosystemPyPI:     "PyPI",
+	models.DependencyEcosystemComposer: "Packagist",
+}
+
+// osvVulnerability is an entry of the OSV database, see https://ossf.github.io/osv-schema/
+type osvVulnerability struct {
+	ID        string     `json:"id"`
+	Modified  time.Time  `json:"modified"`
+	Published time.Time  `json:"published"`
+	Withdrawn *time.Time `json:"withdrawn"`
+	Aliases   []string   `json:"aliases"`
+	Summary   string     `json:"summary"`
+	Details   string     `json:"details"`
+	Affected  []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string              `json:"type"`
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+		Versions         []string          `json:"versions"`
+		DatabaseSpecific *osvSpecificField `json:"database_specific"`
+	} `json:"affected"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"`
+	DatabaseSpecific *osvSpecificField `json:"database_specific"`
+}
+
+type osvSpecificField struct {
+	Severity string `json:"severity"`
+}
+
+// Sync synchronizes the advisories of the configured ecosystems and updates the alerts of all repositories
+func Sync(ctx context.Context) error {
+	var firstErr error
+	for _, ecosystem := range setting.Advisories.Ecosystems {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+		}
+
+		changed, err := syncEcosystem(ctx, ecosystem)
+		if err != nil {
+			log.Error("Unable to synchronize the advisories of %s: %v", ecosystem, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		log.Debug("Synchronized %d changed advisories of %s", changed, ecosystem)
+	}
+
+	if err := CheckRepositories(ctx); err != nil {
+		return err
+	}
+	return firstErr
+}
+
+// syncEcosystem downloads the advisories of an ecosystem and stores the new and modified ones
+func syncEcosystem(ctx context.Context, ecosystem string) (int, error) {
+	osvEcosystem, ok := osvEcosystems[ecosystem]
+	if !ok {
+		return 0, fmt.Errorf("unsupported ecosystem %q", ecosystem)
+	}
+
+	archive, err := downloadArchive(ctx, setting.Advisories.OSVURL+"/"+osvEcosystem+"/all.zip")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = archive.Close()
+		if err := os.Remove(archive.Name()); err != nil {
+			log.Error("Unable to remove %s: %v", archive.Name(), err)
+		}
+	}()
+
+	info, err := archive.Stat()
+	if err != nil {
+		return 0, err
+	}
+	zr, err := zip.NewReader(archive, info.Size())
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	batch := make([]*models.SecurityAdvisory, 0, upsertBatchSize)
+	flush := func() error {
+		n, err := models.UpsertSecurityAdvisories(batch)
+		changed += n
+		batch = batch[:0]
+		return err
+	}
+	for _, f := range zr.File {
+		if path.Ext(f.Name) != ".json" {
+			continue
+		}
+		vuln, err := readVulnerability(f)
+		if err != nil {
+			log.Debug("Unable to parse advisory %s of %s: %v", f.Name, ecosystem, err)
+			continue
+		}
+		for _, adv := range toSecurityAdvisories(vuln, ecosystem) {
+			batch = append(batch, adv)
+			if len(batch) == upsertBatchSize {
+				if err := flush(); err != nil {
+					return changed, err
+				}
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// downloadArchive downloads an archive to a temporary file, the caller must close and remove it
+func downloadArchive(ctx context.Context, url string) (*os.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, setting.Advisories.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: proxy.Proxy()},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s from %s", resp.Status, url)
+	}
+
+	f, err := os.CreateTemp("", "gitea-advisories-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	return f, nil
+}
+
+func readVulnerability(f *zip.File) (*osvVulnerability, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	vuln := new(osvVulnerability)
+	if err := json.NewDecoder(r).Decode(vuln); err != nil {
+		return nil, err
+	}
+	return vuln, nil
+}
+
+// toSecurityAdvisories converts an OSV entry to one advisory for each affected package of the ecosystem
+func toSecurityAdvisories(vuln *osvVulnerability, ecosystem string) []*models.SecurityAdvisory {
+	summary := vuln.Summary
+	if summary == "" {
+		summary = strings.SplitN(strings.TrimSpace(vuln.Details), "\n", 2)[0]
+	}
+	var url string
+	for _, ref := range vuln.References {
+		if url == "" || ref.Type == "ADVISORY" {
+			url = ref.URL
+		}
+		if ref.Type == "ADVISORY" {
+			break
+		}
+	}
+	var withdrawn timeutil.TimeStamp
+	if vuln.Withdrawn != nil {
+		withdrawn = timeutil.TimeStamp(vuln.Withdrawn.Unix())
+	}
+
+	advisories := make(map[string]*models.SecurityAdvisory, len(vuln.Affected))
+	result := make([]*models.SecurityAdvisory, 0, len(vuln.Affected))
+	for _, affected := range vuln.Affected {
+		if affected.Package.Ecosystem != osvEcosystems[ecosystem] || affected.Package.Name == "" {
+			continue
+		}
+
+		pkg := normalizePackageName(ecosystem, affected.Package.Name)
+		adv, ok := advisories[pkg]
+		if !ok {
+			adv = &models.SecurityAdvisory{
+				OSVID:         vuln.ID,
+				Ecosystem:     ecosystem,
+				Package:       pkg,
+				Aliases:       vuln.Aliases,
+				Summary:       summary,
+				Severity:      toSeverity(vuln.DatabaseSpecific),
+				URL:           url,
+				PublishedUnix: timeutil.TimeStamp(vuln.Published.Unix()),
+				ModifiedUnix:  timeutil.TimeStamp(vuln.Modified.Unix()),
+				WithdrawnUnix: withdrawn,
+			}
+			advisories[pkg] = adv
+			result = append(result, adv)
+		}
+		if adv.Severity == "" {
+			adv.Severity = toSeverity(affected.DatabaseSpecific)
+		}
+		adv.Versions = append(adv.Versions, affected.Versions...)
+
+		for _, r := range affected.Ranges {
+			if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+				continue
+			}
+			adv.Ranges = append(adv.Ranges, toVersionRanges(r.Events)...)
+		}
+	}
+	return result
+}
+
+// toVersionRanges converts the events of an OSV range to ranges of affected versions
+func toVersionRanges(events []map[string]string) []*models.AdvisoryVersionRange {
+	var ranges []*models.AdvisoryVersionRange
+	var current *models.AdvisoryVersionRange
+	for _, event := range events {
+		if introduced, ok := event["introduced"]; ok {
+			if current != nil {
+				ranges = append(ranges, current)
+			}
+			if introduced == "0" {
+				introduced = ""
+			}
+			current = &models.AdvisoryVersionRange{Introduced: introduced}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if fixed, ok := event["fixed"]; ok {
+			current.Fixed = fixed
+		} else if lastAffected, ok := event["last_affected"]; ok {
+			current.LastAffected = lastAffected
+		} else {
+			continue
+		}
+		ranges = append(ranges, current)
+		current = nil
+	}
+	if current != nil {
+		ranges = append(ranges, current)
+	}
+	return ranges
+}
+
+func toSeverity(field *osvSpecificField) string {
+	if field == nil {
+		return ""
+	}
+	switch strings.ToLower(field.Severity) {
+	case "low":
+		return models.AdvisorySeverityLow
+	case "moderate", "medium":
+		return models.AdvisorySeverityModerate
+	case "high":
+		return models.AdvisorySeverityHigh
+	case "critical":
+		return models.AdvisorySeverityCritical
+	default:
+		return ""
+	}
+}
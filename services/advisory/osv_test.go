@@ -0,0 +1,77 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package advisory
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToSecurityAdvisories(t *testing.T) {
+	content := `{
+  "id": "GHSA-35jh-r3h4-6jhm",
+  "modified": "2021-11-01T12:00:00Z",
+  "published": "2021-03-19T22:45:29Z",
+  "aliases": ["CVE-2021-23337"],
+  "summary": "Command Injection in lodash",
+  "affected": [
+    {
+      "package": {"ecosystem": "npm", "name": "lodash"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+    },
+    {
+      "package": {"ecosystem": "npm", "name": "lodash-es"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "4.0.0"}, {"last_affected": "4.17.20"}]}],
+      "versions": ["4.17.20"]
+    },
+    {
+      "package": {"ecosystem": "PyPI", "name": "lodash"},
+      "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}]}]
+    }
+  ],
+  "references": [
+    {"type": "WEB", "url": "https://github.com/lodash/lodash/commit/3469357"},
+    {"type": "ADVISORY", "url": "https://nvd.nist.gov/vuln/detail/CVE-2021-23337"}
+  ],
+  "database_specific": {"severity": "HIGH"}
+}`
+	vuln := new(osvVulnerability)
+	assert.NoError(t, json.Unmarshal([]byte(content), vuln))
+
+	advisories := toSecurityAdvisories(vuln, models.DependencyEcosystemNpm)
+	if assert.Len(t, advisories, 2) {
+		assert.Equal(t, "GHSA-35jh-r3h4-6jhm", advisories[0].OSVID)
+		assert.Equal(t, "lodash", advisories[0].Package)
+		assert.Equal(t, []string{"CVE-2021-23337"}, advisories[0].Aliases)
+		assert.Equal(t, "Command Injection in lodash", advisories[0].Summary)
+		assert.Equal(t, models.AdvisorySeverityHigh, advisories[0].Severity)
+		assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2021-23337", advisories[0].URL)
+		assert.EqualValues(t, 1635768000, advisories[0].ModifiedUnix)
+		assert.Equal(t, []*models.AdvisoryVersionRange{{Fixed: "4.17.21"}}, advisories[0].Ranges)
+
+		assert.Equal(t, "lodash-es", advisories[1].Package)
+		assert.Equal(t, []*models.AdvisoryVersionRange{{Introduced: "4.0.0", LastAffected: "4.17.20"}}, advisories[1].Ranges)
+		assert.Equal(t, []string{"4.17.20"}, advisories[1].Versions)
+	}
+}
+
+func TestToVersionRanges(t *testing.T) {
+	ranges := toVersionRanges([]map[string]string{
+		{"introduced": "0"},
+		{"fixed": "1.0.1"},
+		{"introduced": "2.0.0"},
+		{"fixed": "2.0.3"},
+		{"introduced": "3.0.0"},
+	})
+	assert.Equal(t, []*models.AdvisoryVersionRange{
+		{Fixed: "1.0.1"},
+		{Introduced: "2.0.0", Fixed: "2.0.3"},
+		{Introduced: "3.0.0"},
+	}, ranges)
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package advisory
+
+import (
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/hashicorp/go-version"
+)
+
+var pypiNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePackageName returns the name of a package as it is compared to the names of affected packages
+func normalizePackageName(ecosystem, name string) string {
+	switch ecosystem {
+	case models.DependencyEcosystemPyPI:
+		// see https://www.python.org/dev/peps/pep-0503/#normalized-names
+		return pypiNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	case models.DependencyEcosystemComposer:
+		return strings.ToLower(name)
+	default:
+		return name
+	}
+}
+
+// resolveVersion returns the lowest version allowed by the version requirement of a manifest.
+// Only single versions with an optional operator are resolved, other requirements can not be checked.
+func resolveVersion(requirement string) (string, bool) {
+	v := strings.TrimSpace(requirement)
+	for _, op := range []string{"===", "==", ">=", "~=", "^", "~", "="} {
+		if strings.HasPrefix(v, op) {
+			v = strings.TrimSpace(v[len(op):])
+			break
+		}
+	}
+	if v == "" || strings.ContainsAny(v, "<>=!*|, :/") {
+		return "", false
+	}
+	for _, part := range strings.Split(v, ".") {
+		if part == "x" || part == "X" {
+			return "", false
+		}
+	}
+	if _, err := version.NewVersion(v); err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+// affects returns whether the version is affected by the advisory and the lowest version fixing it
+func affects(adv *models.SecurityAdvisory, v string) (bool, string) {
+	for _, affected := range adv.Versions {
+		if affected == v {
+			return true, fixedVersion(adv, v)
+		}
+	}
+
+	ver, err := version.NewVersion(v)
+	if err != nil {
+		return false, ""
+	}
+	for _, r := range adv.Ranges {
+		if inRange(r, ver) {
+			return true, fixedVersion(adv, v)
+		}
+	}
+	return false, ""
+}
+
+// inRange returns whether the version is in the range, ranges with invalid bounds contain no version
+func inRange(r *models.AdvisoryVersionRange, v *version.Version) bool {
+	if r.Introduced != "" {
+		introduced, err := version.NewVersion(r.Introduced)
+		if err != nil || v.LessThan(introduced) {
+			return false
+		}
+	}
+	if r.Fixed != "" {
+		fixed, err := version.NewVersion(r.Fixed)
+		if err != nil || !v.LessThan(fixed) {
+			return false
+		}
+	}
+	if r.LastAffected != "" {
+		lastAffected, err := version.NewVersion(r.LastAffected)
+		if err != nil || v.GreaterThan(lastAffected) {
+			return false
+		}
+	}
+	return true
+}
+
+// fixedVersion returns the lowest version fixing the advisory which is greater than the version
+func fixedVersion(adv *models.SecurityAdvisory, v string) string {
+	ver, err := version.NewVersion(v)
+	if err != nil {
+		return ""
+	}
+
+	var lowest *version.Version
+	var fixed string
+	for _, r := range adv.Ranges {
+		if r.Fixed == "" {
+			continue
+		}
+		f, err := version.NewVersion(r.Fixed)
+		if err != nil || !ver.LessThan(f) {
+			continue
+		}
+		if lowest == nil || f.LessThan(lowest) {
+			lowest, fixed = f, r.Fixed
+		}
+	}
+	return fixed
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package advisory
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveVersion(t *testing.T) {
+	kases := map[string]string{
+		"v1.7.0":                             "v1.7.0",
+		"v0.0.0-20210630005230-0f9fa26af87c": "v0.0.0-20210630005230-0f9fa26af87c",
+		"^2.6.14":                            "2.6.14",
+		"~1.2":                               "1.2",
+		"==3.2.5":                            "3.2.5",
+		">= 1.0.0":                           "1.0.0",
+	}
+	for requirement, expected := range kases {
+		v, ok := resolveVersion(requirement)
+		assert.True(t, ok, requirement)
+		assert.Equal(t, expected, v, requirement)
+	}
+
+	for _, requirement := range []string{"", "*", "1.x", ">=1.0 <2.0", "^1.0 || ^2.0", "<3.0", "latest", "git+https://example.com/a.git", "file:../lib"} {
+		_, ok := resolveVersion(requirement)
+		assert.False(t, ok, requirement)
+	}
+}
+
+func TestAffects(t *testing.T) {
+	adv := &models.SecurityAdvisory{
+		Ranges: []*models.AdvisoryVersionRange{
+			{Fixed: "1.2.3"},
+			{Introduced: "2.0.0", Fixed: "2.1.0"},
+			{Introduced: "3.0.0", LastAffected: "3.0.5"},
+		},
+		Versions: []string{"4.0.0-beta"},
+	}
+
+	kases := []struct {
+		version  string
+		affected bool
+		fixed    string
+	}{
+		{"1.0.0", true, "1.2.3"},
+		{"1.2.3", false, ""},
+		{"1.9.0", false, ""},
+		{"2.0.0", true, "2.1.0"},
+		{"v2.0.7", true, "2.1.0"},
+		{"2.1.0", false, ""},
+		{"3.0.5", true, ""},
+		{"3.0.6", false, ""},
+		{"4.0.0-beta", true, ""},
+		{"not-a-version", false, ""},
+	}
+	for _, kase := range kases {
+		affected, fixed := affects(adv, kase.version)
+		assert.Equal(t, kase.affected, affected, kase.version)
+		assert.Equal(t, kase.fixed, fixed, kase.version)
+	}
+}
+
+func TestNormalizePackageName(t *testing.T) {
+	assert.Equal(t, "zope-interface", normalizePackageName(models.DependencyEcosystemPyPI, "Zope.Interface"))
+	assert.Equal(t, "my-package", normalizePackageName(models.DependencyEcosystemPyPI, "my__package"))
+	assert.Equal(t, "laravel/framework", normalizePackageName(models.DependencyEcosystemComposer, "Laravel/Framework"))
+	assert.Equal(t, "@Scope/Name", normalizePackageName(models.DependencyEcosystemNpm, "@Scope/Name"))
+}
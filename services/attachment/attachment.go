@@ -6,30 +6,68 @@ package attachment
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/typesniffer"
 	"code.gitea.io/gitea/modules/upload"
 
 	"github.com/google/uuid"
 )
 
+// blobWorkingPool serializes adding references to a blob and deleting it, so an unreferenced blob
+// is never deleted while an upload with the same content starts to reference it
+var blobWorkingPool = sync.NewExclusivePool()
+
 // NewAttachment creates a new attachment object, but do not verify.
+// Its content is shared with the other attachments with the same content.
 func NewAttachment(attach *models.Attachment, file io.Reader) (*models.Attachment, error) {
 	if attach.RepoID == 0 {
 		return nil, fmt.Errorf("attachment %s should belong to a repository", attach.Name)
 	}
 
-	err := db.WithTx(func(ctx *db.Context) error {
-		attach.UUID = uuid.New().String()
-		size, err := storage.Attachments.Save(attach.RelativePath(), file, -1)
+	// the hash is only known after reading the content, so it is stored on its own first
+	attach.UUID = uuid.New().String()
+	uploadPath := attach.RelativePath()
+	hash := sha256.New()
+	size, err := storage.Attachments.Save(uploadPath, io.TeeReader(file, hash), -1)
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+	defer func() {
+		if err := storage.Attachments.Delete(uploadPath); err != nil {
+			log.Error("Delete uploaded attachment %s: %v", uploadPath, err)
+		}
+	}()
+	attach.Size = size
+	attach.HashSHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	blobWorkingPool.CheckIn(attach.HashSHA256)
+	defer blobWorkingPool.CheckOut(attach.HashSHA256)
+
+	err = db.WithTx(func(ctx *db.Context) error {
+		ab, exists, err := models.GetOrInsertAttachmentBlob(ctx, &models.AttachmentBlob{
+			HashSHA256: attach.HashSHA256,
+			Size:       size,
+		})
 		if err != nil {
-			return fmt.Errorf("Create: %v", err)
+			return fmt.Errorf("GetOrInsertAttachmentBlob: %v", err)
+		}
+		if !exists {
+			if _, err := storage.Copy(storage.Attachments, ab.RelativePath(), storage.Attachments, uploadPath); err != nil {
+				return fmt.Errorf("Copy: %v", err)
+			}
 		}
-		attach.Size = size
 
 		return db.Insert(ctx, attach)
 	})
@@ -49,10 +87,54 @@ func UploadAttachment(file io.Reader, actorID, repoID, releaseID int64, fileName
 		return nil, err
 	}
 
-	return NewAttachment(&models.Attachment{
+	attach := &models.Attachment{
 		RepoID:     repoID,
 		UploaderID: actorID,
 		ReleaseID:  releaseID,
 		Name:       fileName,
-	}, io.MultiReader(bytes.NewReader(buf), file))
+	}
+	content := io.MultiReader(bytes.NewReader(buf), file)
+	if typesniffer.DetectContentType(buf).IsVideo() {
+		return newVideoAttachment(attach, content)
+	}
+	return NewAttachment(attach, content)
+}
+
+// DeleteUnreferencedBlobs deletes the blobs older than olderThan which are not the content of any attachment
+func DeleteUnreferencedBlobs(ctx context.Context, olderThan time.Duration) error {
+	blobs, err := models.FindUnreferencedAttachmentBlobs(timeutil.TimeStampNow().AddDuration(-olderThan))
+	if err != nil {
+		return err
+	}
+	for _, ab := range blobs {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("Before deleting attachment blob %s", ab.HashSHA256)
+		default:
+		}
+		if err := deleteBlobIfUnreferenced(ab); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteBlobIfUnreferenced(ab *models.AttachmentBlob) error {
+	blobWorkingPool.CheckIn(ab.HashSHA256)
+	defer blobWorkingPool.CheckOut(ab.HashSHA256)
+
+	// an attachment may have been uploaded with the same content since the blob was found
+	count, err := models.CountAttachmentBlobReferences(ab.HashSHA256)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	if err := storage.Attachments.Delete(ab.RelativePath()); err != nil {
+		log.Error("Error deleting attachment blob %s from storage: %v", ab.HashSHA256, err)
+		return nil
+	}
+	models.RemoveAttachmentThumbnails(ab.RelativePath())
+	return models.DeleteAttachmentBlobByID(ab.ID)
 }
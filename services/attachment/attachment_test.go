@@ -5,12 +5,16 @@
 package attachment
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/storage"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -41,3 +45,33 @@ func TestUploadAttachment(t *testing.T) {
 	assert.EqualValues(t, user.ID, attachment.UploaderID)
 	assert.Equal(t, int64(0), attachment.DownloadCount)
 }
+
+func TestDeduplicateAttachments(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	first, err := NewAttachment(&models.Attachment{RepoID: 1, UploaderID: 2, Name: "first.txt"}, strings.NewReader("same content"))
+	assert.NoError(t, err)
+	second, err := NewAttachment(&models.Attachment{RepoID: 2, UploaderID: 2, Name: "second.txt"}, strings.NewReader("same content"))
+	assert.NoError(t, err)
+
+	assert.True(t, first.IsDeduplicated())
+	assert.Equal(t, first.HashSHA256, second.HashSHA256)
+	assert.Equal(t, first.RelativePath(), second.RelativePath())
+	count, err := models.CountAttachmentBlobReferences(first.HashSHA256)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	assert.NoError(t, models.DeleteAttachment(first, true))
+	assert.NoError(t, DeleteUnreferencedBlobs(context.Background(), -time.Hour))
+	exist, err := models.ExistAttachmentBlobByHash(second.HashSHA256)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	_, err = storage.Attachments.Stat(second.RelativePath())
+	assert.NoError(t, err)
+
+	assert.NoError(t, models.DeleteAttachment(second, true))
+	assert.NoError(t, DeleteUnreferencedBlobs(context.Background(), -time.Hour))
+	exist, err = models.ExistAttachmentBlobByHash(second.HashSHA256)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
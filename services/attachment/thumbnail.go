@@ -0,0 +1,94 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package attachment
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/sync"
+
+	"github.com/nfnt/resize"
+)
+
+// thumbnailWorkingPool serializes the generation of each thumbnail, so that concurrent requests generate it once
+var thumbnailWorkingPool = sync.NewExclusivePool()
+
+// IsThumbnailSize returns whether thumbnails with the size may be requested
+func IsThumbnailSize(size int) bool {
+	for _, s := range setting.Attachment.ThumbnailSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenThumbnail opens the thumbnail of an image attachment fitting in a square of the size, which is generated and
+// stored on first use. It returns nil if the attachment has no such thumbnail because it is not a PNG or JPEG image,
+// it is too large to be decoded or it already fits, then the attachment itself is served instead.
+func OpenThumbnail(attach *models.Attachment, size int) (storage.Object, error) {
+	p := attach.ThumbnailRelativePath(size)
+
+	thumbnailWorkingPool.CheckIn(p)
+	defer thumbnailWorkingPool.CheckOut(p)
+
+	if _, err := storage.Attachments.Stat(p); err != nil {
+		generated, err := generateThumbnail(attach, size, p)
+		if err != nil || !generated {
+			return nil, err
+		}
+	}
+	return storage.Attachments.Open(p)
+}
+
+func generateThumbnail(attach *models.Attachment, size int, p string) (bool, error) {
+	fr, err := storage.Attachments.Open(attach.RelativePath())
+	if err != nil {
+		return false, fmt.Errorf("Open: %v", err)
+	}
+	defer fr.Close()
+
+	cfg, format, err := image.DecodeConfig(fr)
+	if err != nil || (format != "png" && format != "jpeg") {
+		return false, nil
+	}
+	if (cfg.Width <= size && cfg.Height <= size) ||
+		int64(cfg.Width)*int64(cfg.Height) > int64(setting.Attachment.ThumbnailMaxSourcePixels) {
+		return false, nil
+	}
+
+	if _, err := fr.Seek(0, io.SeekStart); err != nil {
+		return false, fmt.Errorf("Seek: %v", err)
+	}
+	img, _, err := image.Decode(fr)
+	if err != nil {
+		// a broken image is served as it is
+		return false, nil
+	}
+
+	thumbnail := resize.Thumbnail(uint(size), uint(size), img, resize.Bilinear)
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, thumbnail)
+	}
+	if err != nil {
+		return false, fmt.Errorf("Encode: %v", err)
+	}
+
+	if _, err := storage.Attachments.Save(p, &buf, int64(buf.Len())); err != nil {
+		return false, fmt.Errorf("Save: %v", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package attachment
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestImageAttachment(t *testing.T, width, height int) *models.Attachment {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	attach, err := NewAttachment(&models.Attachment{RepoID: 1, UploaderID: 2, Name: "screenshot.png"}, &buf)
+	assert.NoError(t, err)
+	return attach
+}
+
+func TestOpenThumbnail(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.True(t, IsThumbnailSize(256))
+	assert.False(t, IsThumbnailSize(300))
+
+	attach := newTestImageAttachment(t, 600, 300)
+	thumbnail, err := OpenThumbnail(attach, 256)
+	assert.NoError(t, err)
+	if assert.NotNil(t, thumbnail) {
+		cfg, format, err := image.DecodeConfig(thumbnail)
+		thumbnail.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, "png", format)
+		assert.Equal(t, 256, cfg.Width)
+		assert.Equal(t, 128, cfg.Height)
+	}
+	_, err = storage.Attachments.Stat(attach.ThumbnailRelativePath(256))
+	assert.NoError(t, err)
+
+	// images fitting in the size and other files have no thumbnails
+	attach = newTestImageAttachment(t, 100, 50)
+	thumbnail, err = OpenThumbnail(attach, 256)
+	assert.NoError(t, err)
+	assert.Nil(t, thumbnail)
+
+	attach, err = NewAttachment(&models.Attachment{RepoID: 1, UploaderID: 2, Name: "notes.txt"}, strings.NewReader("not an image"))
+	assert.NoError(t, err)
+	thumbnail, err = OpenThumbnail(attach, 256)
+	assert.NoError(t, err)
+	assert.Nil(t, thumbnail)
+}
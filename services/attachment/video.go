@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/upload"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// newVideoAttachment creates an attachment of a video which may be as large as VIDEO_MAX_SIZE,
+// the video is transcoded by VIDEO_TRANSCODE_COMMAND first if set
+func newVideoAttachment(attach *models.Attachment, file io.Reader) (*models.Attachment, error) {
+	limited := &upload.LimitedReader{R: file, MaxSize: setting.Attachment.VideoMaxSize}
+	if setting.Attachment.VideoTranscodeCommand == "" {
+		attach, err := NewAttachment(attach, limited)
+		if limited.Exceeded() {
+			return nil, upload.ErrFileTooLarge{MaxSize: limited.MaxSize}
+		}
+		return attach, err
+	}
+
+	transcoded, err := transcodeVideo(attach.Name, limited)
+	if limited.Exceeded() {
+		return nil, upload.ErrFileTooLarge{MaxSize: limited.MaxSize}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = transcoded.Close()
+		if err := util.Remove(transcoded.Name()); err != nil {
+			log.Warn("Unable to remove temporary file: %s: Error: %v", transcoded.Name(), err)
+		}
+	}()
+	return NewAttachment(attach, transcoded)
+}
+
+// transcodeVideo runs VIDEO_TRANSCODE_COMMAND with the video on stdin and returns a temporary file of its output
+func transcodeVideo(name string, video io.Reader) (*os.File, error) {
+	f, err := os.CreateTemp("", "gitea_video")
+	if err != nil {
+		return nil, fmt.Errorf("CreateTemp: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(graceful.GetManager().ShutdownContext())
+	defer cancel()
+	commands := strings.Fields(setting.Attachment.VideoTranscodeCommand)
+	pid := process.GetManager().Add(fmt.Sprintf("Transcode [%s] video %s", commands[0], name), cancel)
+	defer process.GetManager().Remove(pid)
+
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, commands[0], commands[1:]...)
+	cmd.Stdin = video
+	cmd.Stdout = f
+	cmd.Stderr = stderr
+	if err = cmd.Run(); err != nil {
+		err = fmt.Errorf("transcoding video %s with %s failed: %v\nstderr: %s", name, commands[0], err, stderr.String())
+	} else {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = f.Close()
+		if err := util.Remove(f.Name()); err != nil {
+			log.Warn("Unable to remove temporary file: %s: Error: %v", f.Name(), err)
+		}
+		return nil, err
+	}
+	return f, nil
+}
@@ -103,6 +103,11 @@ func isGitRawReleaseOrLFSPath(req *http.Request) bool {
 	return false
 }
 
+// isContainerRegistryPath checks if the request targets the container registry
+func isContainerRegistryPath(req *http.Request) bool {
+	return setting.Packages.Enabled && (req.URL.Path == "/v2" || strings.HasPrefix(req.URL.Path, "/v2/"))
+}
+
 // handleSignIn clears existing session variables and stores new ones for the specified user object
 func handleSignIn(resp http.ResponseWriter, req *http.Request, sess SessionStore, user *models.User) {
 	_ = sess.Delete("openid_verified_uri")
@@ -0,0 +1,146 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package backport
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// ErrBackportConflict represents a backport whose changes do not apply to the target branch
+type ErrBackportConflict struct {
+	TargetBranch string
+	Files        []string
+}
+
+// IsErrBackportConflict checks if an error is a ErrBackportConflict.
+func IsErrBackportConflict(err error) bool {
+	_, ok := err.(ErrBackportConflict)
+	return ok
+}
+
+func (err ErrBackportConflict) Error() string {
+	return fmt.Sprintf("backport to %s conflicts in %s", err.TargetBranch, strings.Join(err.Files, ", "))
+}
+
+// backportBranchName returns the name of the branch a backport of a pull request is pushed to
+func backportBranchName(pr *models.PullRequest, targetBranch string) string {
+	return fmt.Sprintf("backport-%d-to-%s", pr.Index, strings.ReplaceAll(targetBranch, "/", "-"))
+}
+
+// Backport cherry-picks the changes of a merged pull request onto a new branch based on the target branch
+// and opens a pull request against the target branch
+func Backport(pr *models.PullRequest, doer *models.User, targetBranch string) (*models.PullRequest, error) {
+	if err := pr.LoadIssue(); err != nil {
+		return nil, err
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	if !pr.HasMerged {
+		return nil, fmt.Errorf("pull request %d is not merged", pr.ID)
+	}
+	repo := pr.BaseRepo
+
+	if _, err := repo_module.GetBranch(repo, targetBranch); err != nil {
+		return nil, err
+	}
+	newBranch := backportBranchName(pr, targetBranch)
+	if existingBranch, err := repo_module.GetBranch(repo, newBranch); existingBranch != nil {
+		return nil, models.ErrBranchAlreadyExists{
+			BranchName: newBranch,
+		}
+	} else if err != nil && !git.IsErrBranchNotExist(err) {
+		return nil, err
+	}
+
+	// the changes of the pull request are the changes between its merge base and its head
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+	patch := new(bytes.Buffer)
+	if err := gitRepo.GetDiff(pr.MergeBase, headCommitID, patch); err != nil {
+		return nil, err
+	}
+
+	t, err := repofiles.NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	if err := t.Clone(targetBranch); err != nil {
+		return nil, err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return nil, err
+	}
+	targetCommit, err := t.GetBranchCommit(targetBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts, err := t.ApplyPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, ErrBackportConflict{
+			TargetBranch: targetBranch,
+			Files:        conflicts,
+		}
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return nil, err
+	}
+	message := fmt.Sprintf("%s (#%d)\n\nBackport of #%d", pr.Issue.Title, pr.Index, pr.Index)
+	commitHash, err := t.CommitTree(pr.Issue.Poster, doer, treeHash, message, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Push(doer, commitHash, newBranch); err != nil {
+		return nil, err
+	}
+
+	issue := &models.Issue{
+		RepoID:   repo.ID,
+		Title:    fmt.Sprintf("%s (backport of #%d)", pr.Issue.Title, pr.Index),
+		PosterID: doer.ID,
+		Poster:   doer,
+		IsPull:   true,
+		Content:  fmt.Sprintf("Backport of #%d to `%s`.", pr.Index, targetBranch),
+	}
+	backport := &models.PullRequest{
+		HeadRepoID: repo.ID,
+		BaseRepoID: repo.ID,
+		HeadBranch: newBranch,
+		BaseBranch: targetBranch,
+		HeadRepo:   repo,
+		BaseRepo:   repo,
+		MergeBase:  targetCommit.ID.String(),
+		Type:       models.PullRequestGitea,
+	}
+	if err := pull_service.NewPullRequest(repo, issue, nil, nil, backport, nil); err != nil {
+		return nil, err
+	}
+	return backport, nil
+}
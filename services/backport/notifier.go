@@ -0,0 +1,139 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package backport
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/queue"
+	comment_service "code.gitea.io/gitea/services/comments"
+)
+
+var backportQueue queue.Queue
+
+// Task is the backport of a merged pull request requested by a label
+type Task struct {
+	PullID int64
+	DoerID int64
+	Label  string
+}
+
+type backportNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &backportNotifier{}
+)
+
+// Init creates the queue creating the backports and registers the notifier which requests them
+func Init() error {
+	backportQueue = queue.CreateQueue("backport", func(data ...queue.Data) {
+		for _, datum := range data {
+			task := datum.(*Task)
+			if err := run(task); err != nil {
+				log.Error("Backport of pull request %d for label %s failed: %v", task.PullID, task.Label, err)
+			}
+		}
+	}, &Task{})
+	if backportQueue == nil {
+		return errors.New("unable to create backport queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(backportQueue.Run)
+	notification.RegisterNotifier(&backportNotifier{})
+	return nil
+}
+
+func (*backportNotifier) NotifyIssueChangeLabels(doer *models.User, issue *models.Issue, addedLabels, removedLabels []*models.Label) {
+	if !issue.IsPull {
+		return
+	}
+	if err := issue.LoadPullRequest(); err != nil {
+		log.Error("issue.LoadPullRequest: %v", err)
+		return
+	}
+	if !issue.PullRequest.HasMerged {
+		return
+	}
+	queueBackports(issue.PullRequest, doer, addedLabels)
+}
+
+func (*backportNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadLabels(); err != nil {
+		log.Error("issue.LoadLabels: %v", err)
+		return
+	}
+	queueBackports(pr, doer, pr.Issue.Labels)
+}
+
+func queueBackports(pr *models.PullRequest, doer *models.User, labels []*models.Label) {
+	for _, label := range labels {
+		if !models.IsBackportLabel(label.Name) {
+			continue
+		}
+		if err := backportQueue.Push(&Task{PullID: pr.ID, DoerID: doer.ID, Label: label.Name}); err != nil {
+			log.Error("Unable to queue backport of pull request %d for label %s: %v", pr.ID, label.Name, err)
+		}
+	}
+}
+
+// run creates a backport and reports its result as a comment on the backported pull request
+func run(task *Task) error {
+	pr, err := models.GetPullRequestByID(task.PullID)
+	if err != nil {
+		return err
+	}
+	doer, err := models.GetUserByID(task.DoerID)
+	if err != nil {
+		return err
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return err
+	}
+	targetBranch, err := models.GetBackportTargetBranch(pr.BaseRepoID, task.Label)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	backport, err := Backport(pr, doer, targetBranch)
+	switch {
+	case err == nil:
+		content = fmt.Sprintf("Backported to `%s` in #%d.", targetBranch, backport.Index)
+	case IsErrBackportConflict(err):
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Unable to backport to `%s` because of conflicts in:\n", targetBranch)
+		for _, file := range err.(ErrBackportConflict).Files {
+			fmt.Fprintf(&sb, "\n- `%s`", file)
+		}
+		content = sb.String()
+	case git.IsErrBranchNotExist(err):
+		content = fmt.Sprintf("Unable to backport to `%s` because the branch does not exist.", targetBranch)
+	case models.IsErrBranchAlreadyExists(err):
+		// the pull request was backported to the branch before
+		return nil
+	default:
+		return err
+	}
+
+	_, err = comment_service.CreateIssueComment(doer, pr.BaseRepo, pr.Issue, content, nil)
+	return err
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package backup
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"code.gitea.io/gitea/modules/storage"
+
+	archiver "github.com/mholt/archiver/v3"
+)
+
+func addFile(w archiver.Writer, insidePath, absPath string) error {
+	file, err := os.Open(absPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	return w.Write(archiver.File{
+		FileInfo: archiver.FileInfo{
+			FileInfo:   fileInfo,
+			CustomName: insidePath,
+		},
+		ReadCloser: file,
+	})
+}
+
+// addRecursive adds the directory absPath to the archive as insidePath
+func addRecursive(w archiver.Writer, insidePath, absPath string) error {
+	absPath, err := filepath.Abs(absPath)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(absPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		currentAbsPath := filepath.Join(absPath, entry.Name())
+		currentInsidePath := path.Join(insidePath, entry.Name())
+		if err := addFile(w, currentInsidePath, currentAbsPath); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if err := addRecursive(w, currentInsidePath, currentAbsPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addStorage adds all objects of an object storage to the archive under insidePath
+func addStorage(w archiver.Writer, insidePath string, objStorage storage.ObjectStorage) error {
+	return objStorage.IterateObjects(func(objPath string, object storage.Object) error {
+		info, err := object.Stat()
+		if err != nil {
+			return err
+		}
+
+		return w.Write(archiver.File{
+			FileInfo: archiver.FileInfo{
+				FileInfo:   info,
+				CustomName: path.Join(insidePath, objPath),
+			},
+			ReadCloser: object,
+		})
+	})
+}
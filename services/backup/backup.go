@@ -0,0 +1,257 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+
+	archiver "github.com/mholt/archiver/v3"
+)
+
+var (
+	// ErrBackupNotRestorable is returned when restoring a repository from a backup which is not
+	// a finished repository backup
+	ErrBackupNotRestorable = errors.New("only finished repository backups can be restored")
+
+	backupQueue queue.Queue
+)
+
+// Task is the backup queued to be produced
+type Task struct {
+	BackupID int64
+}
+
+// Init creates the queue producing the backups
+func Init() error {
+	backupQueue = queue.CreateQueue("backup", func(data ...queue.Data) {
+		for _, datum := range data {
+			task := datum.(*Task)
+			if err := run(graceful.GetManager().ShutdownContext(), task.BackupID); err != nil {
+				log.Error("Backup %d failed: %v", task.BackupID, err)
+			}
+		}
+	}, &Task{})
+	if backupQueue == nil {
+		return errors.New("unable to create backup queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(backupQueue.Run)
+	return nil
+}
+
+// CreateInstanceBackup queues a backup of the database, all repositories, attachments and LFS objects
+func CreateInstanceBackup(doer *models.User) (*models.Backup, error) {
+	return create(&models.Backup{
+		Type:   models.BackupTypeInstance,
+		DoerID: doer.ID,
+	})
+}
+
+// CreateRepositoryBackup queues a backup of a repository with its wiki, issues, pull requests and releases
+func CreateRepositoryBackup(doer *models.User, repo *models.Repository) (*models.Backup, error) {
+	return create(&models.Backup{
+		Type:      models.BackupTypeRepository,
+		RepoID:    repo.ID,
+		OwnerName: repo.OwnerName,
+		RepoName:  repo.Name,
+		DoerID:    doer.ID,
+	})
+}
+
+func create(backup *models.Backup) (*models.Backup, error) {
+	if err := models.CreateBackup(backup); err != nil {
+		return nil, err
+	}
+	if err := backupQueue.Push(&Task{BackupID: backup.ID}); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+func run(ctx context.Context, backupID int64) error {
+	backup, err := models.GetBackupByID(backupID)
+	if err != nil {
+		return err
+	}
+
+	backup.Status = models.BackupStatusRunning
+	if err := models.UpdateBackupStatus(backup); err != nil {
+		return err
+	}
+
+	if err := produce(ctx, backup); err != nil {
+		backup.Status = models.BackupStatusFailed
+		backup.Error = err.Error()
+		if err := models.UpdateBackupStatus(backup); err != nil {
+			log.Error("UpdateBackupStatus: %v", err)
+		}
+		return err
+	}
+
+	backup.Status = models.BackupStatusFinished
+	return models.UpdateBackupStatus(backup)
+}
+
+// produce assembles the backup in a temporary directory, archives it and stores the archive
+func produce(ctx context.Context, backup *models.Backup) error {
+	if err := os.MkdirAll(setting.Backup.TempPath, os.ModePerm); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(setting.Backup.TempPath, fmt.Sprintf("backup-%d-", backup.ID))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Error("Unable to remove temporary backup directory %s: %v", tmpDir, err)
+		}
+	}()
+
+	archivePath := filepath.Join(tmpDir, "backup.zip")
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer archiveFile.Close()
+
+	w := archiver.NewZip()
+	if err := w.Create(archiveFile); err != nil {
+		return err
+	}
+
+	switch backup.Type {
+	case models.BackupTypeInstance:
+		err = writeInstance(w, tmpDir)
+	case models.BackupTypeRepository:
+		err = writeRepository(ctx, w, tmpDir, backup.RepoID)
+	default:
+		err = fmt.Errorf("unknown backup type %d", backup.Type)
+	}
+	if err != nil {
+		_ = w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if _, err := archiveFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	info, err := archiveFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	backup.Path = fmt.Sprintf("%s-%d.zip", backup.Type, backup.ID)
+	if _, err := storage.Backups.Save(backup.Path, archiveFile, info.Size()); err != nil {
+		return err
+	}
+	backup.Size = info.Size()
+	return nil
+}
+
+func writeInstance(w archiver.Writer, tmpDir string) error {
+	dbDump := filepath.Join(tmpDir, "gitea-db.sql")
+	if err := db.DumpDatabase(dbDump, ""); err != nil {
+		return fmt.Errorf("DumpDatabase: %v", err)
+	}
+	if err := addFile(w, "gitea-db.sql", dbDump); err != nil {
+		return err
+	}
+	if err := addRecursive(w, "repos", setting.RepoRootPath); err != nil {
+		return fmt.Errorf("unable to add repositories: %v", err)
+	}
+	if err := addStorage(w, "data/attachments", storage.Attachments); err != nil {
+		return fmt.Errorf("unable to add attachments: %v", err)
+	}
+	if setting.LFS.StartServer {
+		if err := addStorage(w, "data/lfs", storage.LFS); err != nil {
+			return fmt.Errorf("unable to add LFS objects: %v", err)
+		}
+	}
+	return nil
+}
+
+func writeRepository(ctx context.Context, w archiver.Writer, tmpDir string, repoID int64) error {
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		return err
+	}
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+
+	dumpDir := filepath.Join(tmpDir, "repository")
+	if err := migrations.BackupRepository(ctx, dumpDir, repo); err != nil {
+		return fmt.Errorf("BackupRepository: %v", err)
+	}
+	return addRecursive(w, "", filepath.Join(dumpDir, repo.OwnerName, repo.Name))
+}
+
+// RestoreRepository restores a repository backup as a new repository of the owner
+func RestoreRepository(ctx context.Context, backup *models.Backup, owner *models.User, repoName string) (*models.Repository, error) {
+	if backup.Type != models.BackupTypeRepository || backup.Status != models.BackupStatusFinished {
+		return nil, ErrBackupNotRestorable
+	}
+
+	if err := os.MkdirAll(setting.Backup.TempPath, os.ModePerm); err != nil {
+		return nil, err
+	}
+	tmpDir, err := os.MkdirTemp(setting.Backup.TempPath, fmt.Sprintf("restore-%d-", backup.ID))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Error("Unable to remove temporary restore directory %s: %v", tmpDir, err)
+		}
+	}()
+
+	archivePath := filepath.Join(tmpDir, "backup.zip")
+	if err := download(backup.Path, archivePath); err != nil {
+		return nil, err
+	}
+	dumpDir := filepath.Join(tmpDir, "repository")
+	if err := archiver.NewZip().Unarchive(archivePath, dumpDir); err != nil {
+		return nil, fmt.Errorf("Unarchive: %v", err)
+	}
+
+	if err := migrations.RestoreRepository(ctx, dumpDir, owner.Name, repoName, nil); err != nil {
+		return nil, err
+	}
+	return models.GetRepositoryByName(owner.ID, repoName)
+}
+
+func download(objPath, localPath string) error {
+	obj, err := storage.Backups.Open(objPath)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	f, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, obj)
+	return err
+}
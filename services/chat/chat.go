@@ -0,0 +1,119 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package chat
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// Sender delivers direct messages to the addresses of one chat network
+type Sender interface {
+	// ValidateAddress checks whether address is a valid address on the chat network
+	ValidateAddress(address string) error
+	// Send delivers a plain text message to a chat address
+	Send(chatAddress *models.ChatAddress, text string) error
+}
+
+// Task is a message waiting for delivery to a chat address
+type Task struct {
+	UserID   int64
+	Protocol models.ChatProtocol
+	Text     string
+	// Verification messages are delivered to addresses which are not verified yet
+	Verification bool
+}
+
+var (
+	chatQueue queue.Queue
+	senders   = make(map[models.ChatProtocol]Sender)
+)
+
+// RegisterSender sets the sender of a chat network
+func RegisterSender(protocol models.ChatProtocol, sender Sender) {
+	senders[protocol] = sender
+}
+
+// GetSender returns the sender of a chat network, if messages can be delivered to it
+func GetSender(protocol models.ChatProtocol) (Sender, bool) {
+	sender, ok := senders[protocol]
+	return sender, ok
+}
+
+// SupportedProtocols returns the chat networks messages can be delivered to
+func SupportedProtocols() []models.ChatProtocol {
+	protocols := make([]models.ChatProtocol, 0, len(senders))
+	for p := range senders {
+		protocols = append(protocols, p)
+	}
+	return protocols
+}
+
+// NewContext registers the senders of the configured chat networks and starts the chat queue
+func NewContext() {
+	if !setting.Chat.Enabled || chatQueue != nil {
+		return
+	}
+
+	if setting.Chat.XMPP.Server != "" {
+		RegisterSender(models.ChatProtocolXMPP, &xmppSender{})
+	}
+	if setting.Chat.IRC.Server != "" {
+		RegisterSender(models.ChatProtocolIRC, &ircSender{})
+	}
+	if setting.Chat.Matrix.HomeserverURL != "" {
+		RegisterSender(models.ChatProtocolMatrix, newMatrixSender())
+	}
+
+	chatQueue = queue.CreateQueue("chat", func(data ...queue.Data) {
+		for _, datum := range data {
+			deliver(datum.(*Task))
+		}
+	}, &Task{})
+
+	go graceful.GetManager().RunWithShutdownFns(chatQueue.Run)
+}
+
+// SendVerificationCode sends the code verifying a chat address to the address
+func SendVerificationCode(user *models.User, chatAddress *models.ChatAddress) {
+	text := translation.NewLocale(user.Language).Tr("notification.chat.verification_code", setting.AppName, chatAddress.VerificationCode)
+	sendAsync(&Task{UserID: user.ID, Protocol: chatAddress.Protocol, Text: text, Verification: true})
+}
+
+func sendAsync(task *Task) {
+	if chatQueue == nil {
+		log.Error("Chat: sendAsync is being invoked but the chat service hasn't been initialized")
+		return
+	}
+
+	go func() {
+		_ = chatQueue.Push(task)
+	}()
+}
+
+func deliver(task *Task) {
+	sender, ok := senders[task.Protocol]
+	if !ok {
+		return
+	}
+	chatAddress, err := models.GetChatAddress(task.UserID, task.Protocol)
+	if err != nil {
+		if err != models.ErrChatAddressNotExist {
+			log.Error("GetChatAddress: %v", err)
+		}
+		return
+	}
+	if !chatAddress.IsVerified && !task.Verification {
+		return
+	}
+
+	if err := sender.Send(chatAddress, task.Text); err != nil {
+		log.Error("Failed to deliver %s message to user %d: %v", task.Protocol, task.UserID, err)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package chat
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddress(t *testing.T) {
+	cases := []struct {
+		sender  Sender
+		address string
+		valid   bool
+	}{
+		{&xmppSender{}, "user@example.com", true},
+		{&xmppSender{}, "user@example.com/resource", false},
+		{&xmppSender{}, "user", false},
+		{&ircSender{}, "user2", true},
+		{&ircSender{}, "[user]", true},
+		{&ircSender{}, "#channel", false},
+		{&ircSender{}, "user 2", false},
+		{&matrixSender{}, "@user2:example.com", true},
+		{&matrixSender{}, "@user2:example.com:8448", true},
+		{&matrixSender{}, "user2:example.com", false},
+		{&matrixSender{}, "!room:example.com", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.valid, c.sender.ValidateAddress(c.address) == nil, c.address)
+	}
+}
+
+func TestIRCSend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			if strings.HasPrefix(line, "USER ") {
+				_, _ = conn.Write([]byte("PING :irc.example.com\r\n:irc.example.com 001 gitea :Welcome\r\n"))
+			}
+			if line == "QUIT" {
+				break
+			}
+		}
+		received <- lines
+	}()
+
+	setting.Chat.DeliverTimeout = 5
+	setting.Chat.IRC.Server = listener.Addr().String()
+	setting.Chat.IRC.Nick = "gitea"
+	sender := &ircSender{}
+	assert.NoError(t, sender.Send(&models.ChatAddress{Address: "user2"}, "first line\nsecond line"))
+
+	assert.Equal(t, []string{
+		"NICK gitea",
+		"USER gitea 0 * :" + setting.AppName,
+		"PONG :irc.example.com",
+		"PRIVMSG user2 :first line",
+		"PRIVMSG user2 :second line",
+		"QUIT",
+	}, <-received)
+}
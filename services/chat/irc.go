@@ -0,0 +1,118 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package chat
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var ircNickPattern = regexp.MustCompile("^[A-Za-z\\[\\]\\\\`_^{|}][A-Za-z0-9\\[\\]\\\\`_^{|}-]{0,31}$")
+
+// ircSender delivers messages by connecting to an IRC server for every message
+type ircSender struct{}
+
+// ValidateAddress implements Sender
+func (s *ircSender) ValidateAddress(address string) error {
+	if !ircNickPattern.MatchString(address) {
+		return errors.New("address must be an IRC nickname")
+	}
+	return nil
+}
+
+// Send implements Sender
+func (s *ircSender) Send(chatAddress *models.ChatAddress, text string) error {
+	timeout := time.Duration(setting.Chat.DeliverTimeout) * time.Second
+	var conn net.Conn
+	var err error
+	if setting.Chat.IRC.UseTLS {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", setting.Chat.IRC.Server, nil)
+	} else {
+		conn, err = net.DialTimeout("tcp", setting.Chat.IRC.Server, timeout)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	nick := setting.Chat.IRC.Nick
+	if setting.Chat.IRC.Password != "" {
+		if err := writeIRCLine(conn, "PASS "+setting.Chat.IRC.Password); err != nil {
+			return err
+		}
+	}
+	if err := writeIRCLine(conn, "NICK "+nick); err != nil {
+		return err
+	}
+	if err := writeIRCLine(conn, "USER "+nick+" 0 * :"+setting.AppName); err != nil {
+		return err
+	}
+	if err := waitForIRCWelcome(conn, bufio.NewReader(conn)); err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if err := writeIRCLine(conn, "PRIVMSG "+chatAddress.Address+" :"+line); err != nil {
+			return err
+		}
+	}
+	return writeIRCLine(conn, "QUIT")
+}
+
+// waitForIRCWelcome reads the messages of the server until the registration of the connection is complete
+func waitForIRCWelcome(conn net.Conn, reader *bufio.Reader) error {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if strings.HasPrefix(line, "PING ") {
+			if err := writeIRCLine(conn, "PONG "+strings.TrimPrefix(line, "PING ")); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "ERROR ") {
+			return fmt.Errorf("IRC server closed the connection: %s", line)
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[1] {
+		case "001":
+			return nil
+		case "432", "433", "436", "464", "465":
+			return fmt.Errorf("IRC server refused the registration: %s", line)
+		}
+	}
+}
+
+func writeIRCLine(conn net.Conn, line string) error {
+	line = strings.NewReplacer("\r", " ", "\n", " ").Replace(line)
+	if len(line) > 510 {
+		line = line[:510]
+	}
+	_, err := conn.Write([]byte(line + "\r\n"))
+	return err
+}
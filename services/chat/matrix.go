@@ -0,0 +1,114 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package chat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var matrixUserIDPattern = regexp.MustCompile(`^@[a-z0-9._=\-/]+:[A-Za-z0-9.\-]+(:[0-9]+)?$`)
+
+// matrixSender delivers messages through the client-server API of a Matrix homeserver,
+// using the account of the access token and one direct message room per address
+type matrixSender struct {
+	client *http.Client
+}
+
+func newMatrixSender() *matrixSender {
+	return &matrixSender{
+		client: &http.Client{
+			Timeout: time.Duration(setting.Chat.DeliverTimeout) * time.Second,
+			Transport: &http.Transport{
+				Proxy: proxy.Proxy(),
+			},
+		},
+	}
+}
+
+// ValidateAddress implements Sender
+func (s *matrixSender) ValidateAddress(address string) error {
+	if !matrixUserIDPattern.MatchString(address) {
+		return errors.New("address must be a Matrix user ID like @user:example.com")
+	}
+	return nil
+}
+
+// Send implements Sender
+func (s *matrixSender) Send(chatAddress *models.ChatAddress, text string) error {
+	if chatAddress.RoomID == "" {
+		var room struct {
+			RoomID string `json:"room_id"`
+		}
+		if err := s.request("POST", "/_matrix/client/r0/createRoom", map[string]interface{}{
+			"invite":    []string{chatAddress.Address},
+			"is_direct": true,
+			"preset":    "trusted_private_chat",
+		}, &room); err != nil {
+			return fmt.Errorf("create room: %v", err)
+		}
+		chatAddress.RoomID = room.RoomID
+		if err := models.UpdateChatAddressRoom(chatAddress); err != nil {
+			return err
+		}
+	}
+
+	txnID := fmt.Sprintf("gitea-%d", time.Now().UnixNano())
+	err := s.request("PUT", "/_matrix/client/r0/rooms/"+url.PathEscape(chatAddress.RoomID)+"/send/m.room.message/"+txnID, map[string]string{
+		"msgtype": "m.text",
+		"body":    text,
+	}, nil)
+	if err == errMatrixForbidden {
+		// the user left the room, a new one is created for the next message
+		chatAddress.RoomID = ""
+		if err := models.UpdateChatAddressRoom(chatAddress); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+var errMatrixForbidden = errors.New("homeserver denied access to the room")
+
+func (s *matrixSender) request(method, path string, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(setting.Chat.Matrix.HomeserverURL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+setting.Chat.Matrix.AccessToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden:
+		return errMatrixForbidden
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("homeserver responded with status %d", resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
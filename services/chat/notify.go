@@ -0,0 +1,100 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package chat
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// NotifyMentions sends a direct message to the users mentioned in an issue, a pull request or a comment
+func NotifyMentions(issue *models.Issue, doer *models.User, comment *models.Comment, mentions []*models.User) {
+	if chatQueue == nil || len(mentions) == 0 {
+		return
+	}
+	if err := issue.LoadRepo(); err != nil {
+		log.Error("LoadRepo: %v", err)
+		return
+	}
+
+	mentionIDs := make([]int64, 0, len(mentions))
+	for _, mention := range mentions {
+		mentionIDs = append(mentionIDs, mention.ID)
+	}
+	mentionIDs, err := models.FilterIssueSubscribers(issue, mentionIDs, models.SubscriptionEventMention)
+	if err != nil {
+		log.Error("FilterIssueSubscribers(%d): %v", issue.ID, err)
+		return
+	}
+	subscribed := make(map[int64]bool, len(mentionIDs))
+	for _, id := range mentionIDs {
+		subscribed[id] = true
+	}
+	receivers := make([]*models.User, 0, len(mentionIDs))
+	for _, mention := range mentions {
+		if subscribed[mention.ID] {
+			receivers = append(receivers, mention)
+		}
+	}
+
+	link := issue.HTMLURL()
+	if comment != nil {
+		link = comment.HTMLURL()
+	}
+	if err := notifyUsers(issue, doer, receivers, "notification.chat.mentioned", link); err != nil {
+		log.Error("chat.NotifyMentions(%d): %v", issue.ID, err)
+	}
+}
+
+// NotifyReviewRequest sends a direct message to a user requested to review a pull request
+func NotifyReviewRequest(issue *models.Issue, doer, reviewer *models.User) {
+	if chatQueue == nil {
+		return
+	}
+	if err := issue.LoadRepo(); err != nil {
+		log.Error("LoadRepo: %v", err)
+		return
+	}
+	if err := notifyUsers(issue, doer, []*models.User{reviewer}, "notification.chat.review_requested", issue.HTMLURL()); err != nil {
+		log.Error("chat.NotifyReviewRequest(%d): %v", issue.ID, err)
+	}
+}
+
+// notifyUsers sends a message to the verified chat addresses of the users who may see the issue
+func notifyUsers(issue *models.Issue, doer *models.User, users []*models.User, key, link string) error {
+	checkUnit := models.UnitTypeIssues
+	if issue.IsPull {
+		checkUnit = models.UnitTypePullRequests
+	}
+
+	receivers := make(map[int64]*models.User, len(users))
+	ids := make([]int64, 0, len(users))
+	for _, user := range users {
+		if user.ID == doer.ID || !user.IsActive || user.ProhibitLogin || receivers[user.ID] != nil {
+			continue
+		}
+		if !issue.Repo.CheckUnitUser(user, checkUnit) {
+			continue
+		}
+		receivers[user.ID] = user
+		ids = append(ids, user.ID)
+	}
+
+	addresses, err := models.GetVerifiedChatAddressesByUserIDs(ids, SupportedProtocols())
+	if err != nil {
+		return fmt.Errorf("GetVerifiedChatAddressesByUserIDs(): %v", err)
+	}
+
+	title := fmt.Sprintf("[%s] %s (#%d)", issue.Repo.FullName(), issue.Title, issue.Index)
+	for _, address := range addresses {
+		user := receivers[address.UserID]
+		text := translation.NewLocale(user.Language).Tr(key, doer.DisplayName(), title, link)
+		sendAsync(&Task{UserID: user.ID, Protocol: address.Protocol, Text: text})
+	}
+	return nil
+}
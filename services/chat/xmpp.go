@@ -0,0 +1,116 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package chat
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var xmppAddressPattern = regexp.MustCompile(`^[^@/\s"&'<>:]+@[^@/\s]+$`)
+
+// xmppSender delivers messages as an external component of an XMPP server (XEP-0114),
+// which saves maintaining a client session
+type xmppSender struct{}
+
+// ValidateAddress implements Sender
+func (s *xmppSender) ValidateAddress(address string) error {
+	if !xmppAddressPattern.MatchString(address) {
+		return errors.New("address must be a bare JID like user@example.com")
+	}
+	return nil
+}
+
+// Send implements Sender
+func (s *xmppSender) Send(chatAddress *models.ChatAddress, text string) error {
+	conn, err := net.DialTimeout("tcp", setting.Chat.XMPP.Server, time.Duration(setting.Chat.DeliverTimeout)*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(time.Duration(setting.Chat.DeliverTimeout) * time.Second)); err != nil {
+		return err
+	}
+
+	domain := xmlEscape(setting.Chat.XMPP.ComponentDomain)
+	if _, err := fmt.Fprintf(conn, "<?xml version='1.0'?><stream:stream xmlns='jabber:component:accept' xmlns:stream='http://etherx.jabber.org/streams' to='%s'>", domain); err != nil {
+		return err
+	}
+
+	decoder := xml.NewDecoder(conn)
+	streamID, err := readXMPPStreamID(decoder)
+	if err != nil {
+		return err
+	}
+
+	digest := sha1.Sum([]byte(streamID + setting.Chat.XMPP.Secret))
+	if _, err := fmt.Fprintf(conn, "<handshake>%s</handshake>", hex.EncodeToString(digest[:])); err != nil {
+		return err
+	}
+	if err := readXMPPHandshake(decoder); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(conn, "<message from='%s' to='%s' type='chat'><body>%s</body></message></stream:stream>",
+		domain, xmlEscape(chatAddress.Address), xmlEscape(text))
+	return err
+}
+
+// readXMPPStreamID reads the stream header sent by the server and returns the id of the stream
+func readXMPPStreamID(decoder *xml.Decoder) (string, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Local != "stream" {
+				return "", fmt.Errorf("unexpected element %s", start.Name.Local)
+			}
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "id" {
+					return attr.Value, nil
+				}
+			}
+			return "", errors.New("stream has no id")
+		}
+	}
+}
+
+// readXMPPHandshake reads the answer of the server to the handshake of the component
+func readXMPPHandshake(decoder *xml.Decoder) error {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			switch start.Name.Local {
+			case "handshake":
+				return nil
+			case "error":
+				return errors.New("XMPP server rejected the component handshake")
+			default:
+				return fmt.Errorf("unexpected element %s", start.Name.Local)
+			}
+		}
+	}
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -6,12 +6,17 @@ package comments
 
 import (
 	"code.gitea.io/gitea/models"
-	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/services/outbox"
 )
 
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(doer *models.User, repo *models.Repository, issue *models.Issue, content string, attachments []string) (*models.Comment, error) {
+	if err := models.CheckRepoInteractionLimit(repo, doer); err != nil {
+		return nil, err
+	}
+
 	comment, err := models.CreateComment(&models.CreateCommentOptions{
 		Type:        models.CommentTypeComment,
 		Doer:        doer,
@@ -19,15 +24,45 @@ func CreateIssueComment(doer *models.User, repo *models.Repository, issue *model
 		Issue:       issue,
 		Content:     content,
 		Attachments: attachments,
+		OutboxEvent: models.OutboxEventCommentCreated,
 	})
 	if err != nil {
 		return nil, err
 	}
-	mentions, err := issue.FindAndUpdateIssueMentions(db.DefaultContext(), doer, comment.Content)
+
+	// the comment is committed, a failed dispatch is retried later
+	if err := outbox.Dispatch(models.OutboxEventCommentCreated, comment.ID); err != nil {
+		log.Error("Unable to dispatch the creation of comment %d: %v", comment.ID, err)
+	}
+
+	return comment, nil
+}
+
+// CreateRemoteIssueComment creates a plain issue comment of a user of another instance,
+// which is shown under the given name as it has no local account.
+func CreateRemoteIssueComment(repo *models.Repository, issue *models.Issue, author, content string) (*models.Comment, error) {
+	doer := models.NewGhostUser()
+	if err := models.CheckRepoInteractionLimit(repo, doer); err != nil {
+		return nil, err
+	}
+
+	comment, err := models.CreateComment(&models.CreateCommentOptions{
+		Type:           models.CommentTypeComment,
+		Doer:           doer,
+		Repo:           repo,
+		Issue:          issue,
+		Content:        content,
+		OutboxEvent:    models.OutboxEventCommentCreated,
+		OriginalAuthor: author,
+	})
 	if err != nil {
 		return nil, err
 	}
-	notification.NotifyCreateIssueComment(doer, repo, issue, comment, mentions)
+
+	// the comment is committed, a failed dispatch is retried later
+	if err := outbox.Dispatch(models.OutboxEventCommentCreated, comment.ID); err != nil {
+		log.Error("Unable to dispatch the creation of comment %d: %v", comment.ID, err)
+	}
 
 	return comment, nil
 }
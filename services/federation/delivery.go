@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Task is an activity of a local actor waiting for delivery to an inbox
+type Task struct {
+	OwnerType models.FederationOwnerType
+	OwnerID   int64
+	Inbox     string
+	Activity  json.RawMessage
+}
+
+var deliveryQueue queue.Queue
+
+// NewContext starts the delivery queue
+func NewContext() {
+	if !setting.Federation.Enabled || deliveryQueue != nil {
+		return
+	}
+
+	deliveryQueue = queue.CreateQueue("activitypub_delivery", func(data ...queue.Data) {
+		for _, datum := range data {
+			deliver(datum.(*Task))
+		}
+	}, &Task{})
+
+	go graceful.GetManager().RunWithShutdownFns(deliveryQueue.Run)
+}
+
+// DeliverAsync delivers an activity of a local actor to the inboxes of remote actors asynchronously
+func DeliverAsync(ownerType models.FederationOwnerType, ownerID int64, activity *activitypub.Activity, actors ...*models.RemoteActor) {
+	if deliveryQueue == nil {
+		log.Error("Federation: DeliverAsync is being invoked but the federation service hasn't been initialized")
+		return
+	}
+	content, err := json.Marshal(activity)
+	if err != nil {
+		log.Error("Unable to marshal activity %s: %v", activity.ID, err)
+		return
+	}
+
+	// remote actors of the same instance usually share their inbox
+	inboxes := make(map[string]bool, len(actors))
+	for _, actor := range actors {
+		inbox := actor.Inbox
+		if actor.SharedInbox != "" {
+			inbox = actor.SharedInbox
+		}
+		if inboxes[inbox] {
+			continue
+		}
+		inboxes[inbox] = true
+		if err := deliveryQueue.Push(&Task{OwnerType: ownerType, OwnerID: ownerID, Inbox: inbox, Activity: content}); err != nil {
+			log.Error("Unable to queue the delivery of activity %s to %s: %v", activity.ID, inbox, err)
+		}
+	}
+}
+
+func deliver(task *Task) {
+	client, err := newClient(task.OwnerType, task.OwnerID)
+	if err != nil {
+		log.Error("Unable to create the federation client of %s %d: %v", task.OwnerType, task.OwnerID, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(graceful.GetManager().ShutdownContext(), setting.Federation.DeliverTimeout)
+	defer cancel()
+	if err := client.Post(ctx, task.Inbox, task.Activity); err != nil {
+		log.Warn("Unable to deliver an activity of %s %d to %s: %v", task.OwnerType, task.OwnerID, task.Inbox, err)
+	}
+}
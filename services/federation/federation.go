@@ -0,0 +1,230 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/google/uuid"
+)
+
+// ErrResolveActor represents a remote actor which could not be fetched or is invalid
+type ErrResolveActor struct {
+	Actor string
+	Err   error
+}
+
+// IsErrResolveActor checks if an error is a ErrResolveActor.
+func IsErrResolveActor(err error) bool {
+	_, ok := err.(ErrResolveActor)
+	return ok
+}
+
+func (err ErrResolveActor) Error() string {
+	return fmt.Sprintf("unable to resolve the actor %s: %v", err.Actor, err.Err)
+}
+
+// ActorURI returns the ActivityPub ID of a local user or repository
+func ActorURI(ownerType models.FederationOwnerType, ownerID int64) string {
+	if ownerType == models.FederationOwnerRepo {
+		return fmt.Sprintf("%sapi/v1/activitypub/repository-id/%d", setting.AppURL, ownerID)
+	}
+	return fmt.Sprintf("%sapi/v1/activitypub/user-id/%d", setting.AppURL, ownerID)
+}
+
+// KeyID returns the ID of the key the requests of a local actor are signed with
+func KeyID(ownerType models.FederationOwnerType, ownerID int64) string {
+	return ActorURI(ownerType, ownerID) + "#main-key"
+}
+
+// ActivityURI returns the ID of an activity published by a local actor
+func ActivityURI(id string) string {
+	return setting.AppURL + "api/v1/activitypub/activity/" + id
+}
+
+// IsFederatedUser returns whether a user is exposed as an actor
+func IsFederatedUser(u *models.User) bool {
+	return u.IsActive && !u.ProhibitLogin && u.Visibility.IsPublic()
+}
+
+// IsFederatedRepo returns whether a repository is exposed as an actor
+func IsFederatedRepo(repo *models.Repository) bool {
+	if repo.IsPrivate {
+		return false
+	}
+	if err := repo.GetOwner(); err != nil {
+		return false
+	}
+	return repo.Owner.Visibility.IsPublic()
+}
+
+func getOrCreateKey(ownerType models.FederationOwnerType, ownerID int64) (*models.FederationKey, error) {
+	key, err := models.GetFederationKey(ownerType, ownerID)
+	if err == nil || !models.IsErrFederationKeyNotExist(err) {
+		return key, err
+	}
+
+	privateKey, publicKey, err := activitypub.GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	key = &models.FederationKey{
+		OwnerType:  ownerType,
+		OwnerID:    ownerID,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}
+	if err := models.InsertFederationKey(key); err != nil {
+		// another request created the key in the meantime
+		if existing, getErr := models.GetFederationKey(ownerType, ownerID); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+func newClient(ownerType models.FederationOwnerType, ownerID int64) (*activitypub.Client, error) {
+	key, err := getOrCreateKey(ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := activitypub.ParsePrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return activitypub.NewClient(KeyID(ownerType, ownerID), privateKey), nil
+}
+
+func newActor(ownerType models.FederationOwnerType, ownerID int64, actorType, username string) (*activitypub.Actor, error) {
+	key, err := getOrCreateKey(ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	uri := ActorURI(ownerType, ownerID)
+	return &activitypub.Actor{
+		Context:           []string{activitypub.ActivityStreamsContext, activitypub.SecurityContext},
+		ID:                uri,
+		Type:              actorType,
+		PreferredUsername: username,
+		Inbox:             uri + "/inbox",
+		Outbox:            uri + "/outbox",
+		Followers:         uri + "/followers",
+		PublicKey: activitypub.PublicKey{
+			ID:           KeyID(ownerType, ownerID),
+			Owner:        uri,
+			PublicKeyPem: key.PublicKey,
+		},
+	}, nil
+}
+
+// UserActor returns the actor of a user or an organization
+func UserActor(u *models.User) (*activitypub.Actor, error) {
+	actorType := activitypub.TypePerson
+	if u.IsOrganization() {
+		actorType = activitypub.TypeGroup
+	}
+	actor, err := newActor(models.FederationOwnerUser, u.ID, actorType, u.Name)
+	if err != nil {
+		return nil, err
+	}
+	actor.Name = u.DisplayName()
+	actor.Summary = u.Description
+	actor.URL = u.HTMLURL()
+	actor.Icon = &activitypub.Image{Type: "Image", URL: u.AvatarLink()}
+	return actor, nil
+}
+
+// RepoActor returns the ForgeFed actor of a repository
+func RepoActor(repo *models.Repository) (*activitypub.Actor, error) {
+	actor, err := newActor(models.FederationOwnerRepo, repo.ID, activitypub.TypeRepository, repo.Name)
+	if err != nil {
+		return nil, err
+	}
+	actor.Context = []string{activitypub.ActivityStreamsContext, activitypub.SecurityContext, activitypub.ForgeFedContext}
+	actor.Name = repo.FullName()
+	actor.Summary = repo.Description
+	actor.URL = repo.HTMLURL()
+	return actor, nil
+}
+
+// NewActivity creates an activity of a local actor with a new ID
+func NewActivity(actorURI, activityType string, object interface{}) (*activitypub.Activity, error) {
+	rawObject, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	return &activitypub.Activity{
+		Context:   activitypub.ActivityStreamsContext,
+		ID:        ActivityURI(uuid.New().String()),
+		Type:      activityType,
+		Actor:     activitypub.IRI(actorURI),
+		Object:    rawObject,
+		Published: time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// fetchActor fetches a remote actor and saves it
+func fetchActor(ctx context.Context, client *activitypub.Client, uri string) (*models.RemoteActor, error) {
+	if strings.HasPrefix(uri, setting.AppURL) {
+		return nil, ErrResolveActor{Actor: uri, Err: errors.New("it is a local actor")}
+	}
+	var actor activitypub.Actor
+	if err := client.Fetch(ctx, uri, &actor); err != nil {
+		if activitypub.IsErrBlockedURL(err) {
+			return nil, err
+		}
+		return nil, ErrResolveActor{Actor: uri, Err: err}
+	}
+	if actor.ID != uri {
+		return nil, ErrResolveActor{Actor: uri, Err: fmt.Errorf("the actor %s was returned", actor.ID)}
+	}
+	if actor.Inbox == "" {
+		return nil, ErrResolveActor{Actor: uri, Err: errors.New("not an actor")}
+	}
+
+	remoteActor := &models.RemoteActor{
+		URI:               actor.ID,
+		Type:              actor.Type,
+		PreferredUsername: actor.PreferredUsername,
+		Inbox:             actor.Inbox,
+		PublicKeyID:       actor.PublicKey.ID,
+		PublicKey:         actor.PublicKey.PublicKeyPem,
+	}
+	if actor.Endpoints != nil {
+		remoteActor.SharedInbox = actor.Endpoints.SharedInbox
+	}
+	if err := models.SaveRemoteActor(remoteActor); err != nil {
+		return nil, err
+	}
+	return remoteActor, nil
+}
+
+// ResolveActor fetches a remote actor by its handle, e.g. user@example.com, or its ID on behalf of a local user
+func ResolveActor(ctx context.Context, doer *models.User, handleOrURI string) (*models.RemoteActor, error) {
+	client, err := newClient(models.FederationOwnerUser, doer.ID)
+	if err != nil {
+		return nil, err
+	}
+	uri := handleOrURI
+	if !strings.HasPrefix(handleOrURI, "https://") && !strings.HasPrefix(handleOrURI, "http://") {
+		if uri, err = client.ResolveHandle(ctx, handleOrURI); err != nil {
+			if activitypub.IsErrBlockedURL(err) {
+				return nil, err
+			}
+			return nil, ErrResolveActor{Actor: handleOrURI, Err: err}
+		}
+	}
+	return fetchActor(ctx, client, uri)
+}
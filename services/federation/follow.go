@@ -0,0 +1,70 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// FollowRemote makes a local user follow a remote actor given by its handle or ID.
+// The following is only effective once the remote instance accepted it.
+func FollowRemote(ctx context.Context, doer *models.User, handleOrURI string) (*models.FederationFollowing, error) {
+	actor, err := ResolveActor(ctx, doer, handleOrURI)
+	if err != nil {
+		return nil, err
+	}
+
+	doerURI := ActorURI(models.FederationOwnerUser, doer.ID)
+	follow, err := NewActivity(doerURI, activitypub.TypeFollow, actor.URI)
+	if err != nil {
+		return nil, err
+	}
+	follow.To = []string{actor.URI}
+
+	following := &models.FederationFollowing{
+		UserID:        doer.ID,
+		RemoteActorID: actor.ID,
+		RemoteActor:   actor,
+		ActivityURI:   follow.ID,
+	}
+	if err := models.CreateFederationFollowing(following); err != nil {
+		return nil, err
+	}
+	DeliverAsync(models.FederationOwnerUser, doer.ID, follow, actor)
+	return following, nil
+}
+
+// UnfollowRemote makes a local user stop following a remote actor
+func UnfollowRemote(doer *models.User, following *models.FederationFollowing) error {
+	if err := following.LoadRemoteActor(); err != nil {
+		return err
+	}
+	if err := models.DeleteFederationFollowing(following); err != nil {
+		return err
+	}
+
+	doerURI := ActorURI(models.FederationOwnerUser, doer.ID)
+	object, err := json.Marshal(following.RemoteActor.URI)
+	if err != nil {
+		return err
+	}
+	follow := &activitypub.Activity{
+		ID:     following.ActivityURI,
+		Type:   activitypub.TypeFollow,
+		Actor:  activitypub.IRI(doerURI),
+		Object: object,
+	}
+	undo, err := NewActivity(doerURI, activitypub.TypeUndo, follow)
+	if err != nil {
+		return err
+	}
+	undo.To = []string{following.RemoteActor.URI}
+	DeliverAsync(models.FederationOwnerUser, doer.ID, undo, following.RemoteActor)
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// VerifyRequest verifies the signature of a request delivered to the inbox of a local actor and returns the remote actor who signed it.
+// Unknown actors are fetched on behalf of the local actor.
+func VerifyRequest(ctx context.Context, ownerType models.FederationOwnerType, ownerID int64, req *http.Request, body []byte) (*models.RemoteActor, error) {
+	keyID, err := activitypub.SignatureKeyID(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := activitypub.CheckURL(keyID); err != nil {
+		return nil, err
+	}
+
+	actor, err := models.GetRemoteActorByKeyID(keyID)
+	if err != nil {
+		if !models.IsErrRemoteActorNotExist(err) {
+			return nil, err
+		}
+		client, err := newClient(ownerType, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		// the key of an actor is usually embedded in the actor, its ID being the ID of the actor with a fragment
+		if actor, err = fetchActor(ctx, client, strings.SplitN(keyID, "#", 2)[0]); err != nil {
+			return nil, activitypub.ErrInvalidSignature{Reason: fmt.Sprintf("unable to fetch the key: %v", err)}
+		}
+		if actor.PublicKeyID != keyID {
+			return nil, activitypub.ErrInvalidSignature{Reason: "unknown key"}
+		}
+	}
+
+	publicKey, err := activitypub.ParsePublicKey(actor.PublicKey)
+	if err != nil {
+		return nil, activitypub.ErrInvalidSignature{Reason: fmt.Sprintf("invalid key: %v", err)}
+	}
+	if err := activitypub.VerifyRequest(req, body, publicKey); err != nil {
+		return nil, err
+	}
+	return actor, nil
+}
+
+// HandleInbox handles an activity a remote actor delivered to the inbox of a local user or repository.
+// Activities Gitea does not support are ignored.
+func HandleInbox(ownerType models.FederationOwnerType, ownerID int64, sender *models.RemoteActor, activity *activitypub.Activity, content []byte) error {
+	if string(activity.Actor) != sender.URI {
+		return activitypub.ErrInvalidSignature{Reason: "the activity was not signed by its actor"}
+	}
+	ownerURI := ActorURI(ownerType, ownerID)
+
+	switch activity.Type {
+	case activitypub.TypeFollow:
+		if string(activity.ObjectIRI()) != ownerURI {
+			return nil
+		}
+		if err := models.AddFederationFollower(ownerType, ownerID, sender.ID); err != nil {
+			return err
+		}
+		accept, err := NewActivity(ownerURI, activitypub.TypeAccept, activity)
+		if err != nil {
+			return err
+		}
+		accept.To = []string{sender.URI}
+		DeliverAsync(ownerType, ownerID, accept, sender)
+	case activitypub.TypeUndo:
+		object, err := activity.ObjectActivity()
+		if err != nil || object.Type != activitypub.TypeFollow || string(object.ObjectIRI()) != ownerURI {
+			return nil
+		}
+		return models.RemoveFederationFollower(ownerType, ownerID, sender.ID)
+	case activitypub.TypeAccept, activitypub.TypeReject:
+		if ownerType != models.FederationOwnerUser {
+			return nil
+		}
+		following, err := models.GetFederationFollowingByActivityURI(string(activity.ObjectIRI()))
+		if err != nil {
+			if models.IsErrFederationFollowingNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if following.UserID != ownerID || following.RemoteActorID != sender.ID {
+			return nil
+		}
+		if activity.Type == activitypub.TypeAccept {
+			return models.AcceptFederationFollowing(following)
+		}
+		return models.DeleteFederationFollowing(following)
+	case activitypub.TypeCreate, activitypub.TypeAnnounce:
+		if ownerType != models.FederationOwnerUser || activity.ID == "" {
+			return nil
+		}
+		isFollowing, err := models.IsFollowingRemoteActor(ownerID, sender.ID)
+		if err != nil || !isFollowing {
+			return err
+		}
+		return models.CreateFederationActivity(&models.FederationActivity{
+			OwnerType:     ownerType,
+			OwnerID:       ownerID,
+			RemoteActorID: sender.ID,
+			URI:           activity.ID,
+			Type:          activity.Type,
+			Content:       string(content),
+		})
+	default:
+		log.Trace("Ignoring %s activity %s delivered to %s", activity.Type, activity.ID, ownerURI)
+	}
+	return nil
+}
+
+// ParseActivity parses an activity delivered to an inbox
+func ParseActivity(content []byte) (*activitypub.Activity, error) {
+	activity := new(activitypub.Activity)
+	if err := json.Unmarshal(content, activity); err != nil {
+		return nil, err
+	}
+	if activity.Type == "" || activity.Actor == "" {
+		return nil, fmt.Errorf("not an activity")
+	}
+	return activity, nil
+}
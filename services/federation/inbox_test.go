@@ -0,0 +1,38 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/activitypub"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRequestLocalNetworkKey(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	body := []byte(`{"type":"Follow"}`)
+	req, err := http.NewRequest(http.MethodPost, "https://try.gitea.io/api/v1/activitypub/user-id/2/inbox", strings.NewReader(string(body)))
+	assert.NoError(t, err)
+	req.Header.Set("Signature", `keyId="`+server.URL+`/users/alice#main-key",algorithm="rsa-sha256",headers="(request-target) date digest",signature="c2lnbmF0dXJl"`)
+
+	_, err = VerifyRequest(context.Background(), models.FederationOwnerUser, 2, req, body)
+	assert.True(t, activitypub.IsErrBlockedURL(err))
+	assert.False(t, requested)
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// PublishNote publishes a note of a local user or repository, it is added to its outbox and delivered to its followers.
+// content is HTML and url links to the page of the note on Gitea.
+func PublishNote(ownerType models.FederationOwnerType, ownerID int64, content, url string) error {
+	ownerURI := ActorURI(ownerType, ownerID)
+	to := []string{activitypub.PublicCollection}
+	cc := []string{ownerURI + "/followers"}
+
+	create, err := NewActivity(ownerURI, activitypub.TypeCreate, nil)
+	if err != nil {
+		return err
+	}
+	note := &activitypub.Note{
+		ID:           create.ID + "/object",
+		Type:         activitypub.TypeNote,
+		AttributedTo: activitypub.IRI(ownerURI),
+		Content:      content,
+		URL:          url,
+		To:           to,
+		Published:    create.Published,
+	}
+	if create.Object, err = json.Marshal(note); err != nil {
+		return err
+	}
+	create.To = to
+	create.Cc = cc
+
+	activity, err := json.Marshal(create)
+	if err != nil {
+		return err
+	}
+	if err := models.CreateFederationActivity(&models.FederationActivity{
+		OwnerType: ownerType,
+		OwnerID:   ownerID,
+		URI:       create.ID,
+		Type:      create.Type,
+		Content:   string(activity),
+	}); err != nil {
+		return err
+	}
+
+	followers, err := models.GetFederationFollowers(ownerType, ownerID)
+	if err != nil {
+		return err
+	}
+	if len(followers) > 0 {
+		DeliverAsync(ownerType, ownerID, create, followers...)
+	}
+	return nil
+}
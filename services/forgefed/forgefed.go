@@ -0,0 +1,228 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package forgefed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/activitypub"
+	"code.gitea.io/gitea/modules/json"
+	comment_service "code.gitea.io/gitea/services/comments"
+	"code.gitea.io/gitea/services/federation"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ErrInteractionForbidden represents an interaction of a remote actor a repository does not accept
+type ErrInteractionForbidden struct {
+	RepoID int64
+	Reason string
+}
+
+// IsErrInteractionForbidden checks if an error is a ErrInteractionForbidden.
+func IsErrInteractionForbidden(err error) bool {
+	_, ok := err.(ErrInteractionForbidden)
+	return ok
+}
+
+func (err ErrInteractionForbidden) Error() string {
+	return fmt.Sprintf("interaction forbidden [repo_id: %d]: %s", err.RepoID, err.Reason)
+}
+
+// isRepoObject returns whether an object refers to a repository, by its actor or its web page
+func isRepoObject(repo *models.Repository, iri activitypub.IRI) bool {
+	return string(iri) == federation.ActorURI(models.FederationOwnerRepo, repo.ID) || string(iri) == repo.HTMLURL()
+}
+
+// HandleRepoInbox handles an activity a remote actor delivered to the inbox of a repository.
+// Stars, tickets and comments are only accepted if the repository opted in to federation
+// and the actor is not blocked, other activities are handled like those of any actor.
+func HandleRepoInbox(repo *models.Repository, sender *models.RemoteActor, activity *activitypub.Activity, content []byte) error {
+	if string(activity.Actor) != sender.URI {
+		return activitypub.ErrInvalidSignature{Reason: "the activity was not signed by its actor"}
+	}
+
+	switch activity.Type {
+	case activitypub.TypeLike, activitypub.TypeStar, activitypub.TypeOffer, activitypub.TypeCreate:
+	case activitypub.TypeUndo:
+		object, err := activity.ObjectActivity()
+		if err == nil && (object.Type == activitypub.TypeLike || object.Type == activitypub.TypeStar) {
+			// the star of a blocked actor is already removed
+			return models.RemoveRemoteStar(repo.ID, sender.ID)
+		}
+		return federation.HandleInbox(models.FederationOwnerRepo, repo.ID, sender, activity, content)
+	default:
+		return federation.HandleInbox(models.FederationOwnerRepo, repo.ID, sender, activity, content)
+	}
+
+	settings, err := models.GetRepoFederation(repo.ID)
+	if err != nil {
+		return err
+	} else if settings == nil {
+		return ErrInteractionForbidden{RepoID: repo.ID, Reason: "the repository does not accept interactions of other instances"}
+	}
+	if blocked, err := models.IsFederationBlocked(repo.ID, sender); err != nil {
+		return err
+	} else if blocked {
+		return ErrInteractionForbidden{RepoID: repo.ID, Reason: "the actor is blocked"}
+	}
+
+	switch activity.Type {
+	case activitypub.TypeLike, activitypub.TypeStar:
+		if !isRepoObject(repo, activity.ObjectIRI()) {
+			return nil
+		}
+		return models.AddRemoteStar(repo.ID, sender.ID, activity.ID)
+	case activitypub.TypeOffer:
+		if !settings.AllowIssues || repo.IsArchived || !repo.UnitEnabled(models.UnitTypeIssues) {
+			return ErrInteractionForbidden{RepoID: repo.ID, Reason: "the repository does not accept tickets of other instances"}
+		}
+		return offerTicket(repo, sender, activity)
+	case activitypub.TypeCreate:
+		if !settings.AllowComments || repo.IsArchived {
+			return ErrInteractionForbidden{RepoID: repo.ID, Reason: "the repository does not accept comments of other instances"}
+		}
+		return createComment(repo, sender, activity)
+	}
+	return nil
+}
+
+// offerTicket opens an issue for a ticket offered by a remote actor and accepts the offer with the issue as result
+func offerTicket(repo *models.Repository, sender *models.RemoteActor, offer *activitypub.Activity) error {
+	var ticket activitypub.Ticket
+	if err := json.Unmarshal(offer.Object, &ticket); err != nil || ticket.Type != activitypub.TypeTicket {
+		return nil
+	}
+	if !isRepoObject(repo, offer.Target) && !isRepoObject(repo, ticket.Context) {
+		return nil
+	}
+	if ticket.ID == "" || string(ticket.AttributedTo) != sender.URI {
+		return ErrInteractionForbidden{RepoID: repo.ID, Reason: "the ticket is not attributed to the actor"}
+	}
+
+	object, err := models.GetFederatedObjectByURI(ticket.ID)
+	if err != nil {
+		return err
+	}
+	var issue *models.Issue
+	if object != nil {
+		// the offer was delivered again, accept it again
+		if issue, err = models.GetIssueByID(object.IssueID); err != nil {
+			return err
+		}
+	} else {
+		title := ticket.Summary
+		if title == "" {
+			title = ticket.Name
+		}
+		if strings.TrimSpace(title) == "" {
+			return ErrInteractionForbidden{RepoID: repo.ID, Reason: "the ticket has no summary"}
+		}
+		ghost := models.NewGhostUser()
+		issue = &models.Issue{
+			RepoID:         repo.ID,
+			Repo:           repo,
+			Title:          title,
+			Content:        activitypub.SourceContent(ticket.Source, ticket.Content),
+			PosterID:       ghost.ID,
+			Poster:         ghost,
+			OriginalAuthor: sender.Handle(),
+		}
+		if err := issue_service.NewIssue(repo, issue, nil, nil, nil); err != nil {
+			if models.IsErrRepoInteractionLimited(err) {
+				return ErrInteractionForbidden{RepoID: repo.ID, Reason: err.Error()}
+			}
+			return err
+		}
+		if err := models.InsertFederatedObject(&models.FederatedObject{
+			RepoID:        repo.ID,
+			RemoteActorID: sender.ID,
+			URI:           ticket.ID,
+			IssueID:       issue.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	repoURI := federation.ActorURI(models.FederationOwnerRepo, repo.ID)
+	accept, err := federation.NewActivity(repoURI, activitypub.TypeAccept, offer)
+	if err != nil {
+		return err
+	}
+	accept.Result = issue.HTMLURL()
+	accept.To = []string{sender.URI}
+	federation.DeliverAsync(models.FederationOwnerRepo, repo.ID, accept, sender)
+	return nil
+}
+
+// replyIssue returns the issue of the repository a note replies to,
+// which is given by the web page of the issue or of one of its comments, or by a remote object it was created from
+func replyIssue(repo *models.Repository, inReplyTo activitypub.IRI) (*models.Issue, error) {
+	target := strings.SplitN(string(inReplyTo), "#", 2)[0]
+	for _, prefix := range []string{repo.HTMLURL() + "/issues/", repo.HTMLURL() + "/pulls/"} {
+		if !strings.HasPrefix(target, prefix) {
+			continue
+		}
+		index, err := strconv.ParseInt(strings.TrimPrefix(target, prefix), 10, 64)
+		if err != nil {
+			return nil, nil
+		}
+		issue, err := models.GetIssueByIndex(repo.ID, index)
+		if models.IsErrIssueNotExist(err) {
+			return nil, nil
+		}
+		return issue, err
+	}
+
+	object, err := models.GetFederatedObjectByURI(string(inReplyTo))
+	if err != nil || object == nil || object.RepoID != repo.ID {
+		return nil, err
+	}
+	issue, err := models.GetIssueByID(object.IssueID)
+	if models.IsErrIssueNotExist(err) {
+		return nil, nil
+	}
+	return issue, err
+}
+
+// createComment comments on an issue with a note created by a remote actor
+func createComment(repo *models.Repository, sender *models.RemoteActor, create *activitypub.Activity) error {
+	var note activitypub.Note
+	if err := json.Unmarshal(create.Object, &note); err != nil || note.Type != activitypub.TypeNote || note.InReplyTo == "" {
+		return nil
+	}
+	if note.ID == "" || string(note.AttributedTo) != sender.URI {
+		return ErrInteractionForbidden{RepoID: repo.ID, Reason: "the note is not attributed to the actor"}
+	}
+
+	issue, err := replyIssue(repo, note.InReplyTo)
+	if err != nil || issue == nil {
+		return err
+	}
+	if issue.IsLocked {
+		return ErrInteractionForbidden{RepoID: repo.ID, Reason: "the conversation of the issue is locked"}
+	}
+	if object, err := models.GetFederatedObjectByURI(note.ID); err != nil || object != nil {
+		return err
+	}
+
+	issue.Repo = repo
+	comment, err := comment_service.CreateRemoteIssueComment(repo, issue, sender.Handle(), activitypub.SourceContent(note.Source, note.Content))
+	if err != nil {
+		if models.IsErrRepoInteractionLimited(err) {
+			return ErrInteractionForbidden{RepoID: repo.ID, Reason: err.Error()}
+		}
+		return err
+	}
+	return models.InsertFederatedObject(&models.FederatedObject{
+		RepoID:        repo.ID,
+		RemoteActorID: sender.ID,
+		URI:           note.ID,
+		IssueID:       issue.ID,
+		CommentID:     comment.ID,
+	})
+}
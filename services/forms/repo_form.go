@@ -82,6 +82,8 @@ type MigrateRepoForm struct {
 	PullRequests   bool   `json:"pull_requests"`
 	Releases       bool   `json:"releases"`
 	MirrorInterval string `json:"mirror_interval"`
+
+	MapUsersByEmail bool `json:"map_users_by_email"`
 }
 
 // Validate validates the fields
@@ -150,11 +152,14 @@ type RepoSettingForm struct {
 	PullsAllowSquash                      bool
 	PullsAllowManualMerge                 bool
 	PullsDefaultMergeStyle                string
+	PullsDefaultMergeMessageTemplate      string
+	PullsDefaultSquashMessageTemplate     string
 	EnableAutodetectManualMerge           bool
 	DefaultDeleteBranchAfterMerge         bool
 	EnableTimetracker                     bool
 	AllowOnlyContributorsToTrackTime      bool
 	EnableIssueDependencies               bool
+	IssueBranchNameTemplate               string
 	IsArchived                            bool
 
 	// Signing Settings
@@ -189,6 +194,7 @@ type ProtectBranchForm struct {
 	MergeWhitelistTeams           string
 	EnableStatusCheck             bool
 	StatusCheckContexts           []string
+	StatusCheckMaxAge             int64
 	RequiredApprovals             int64
 	EnableApprovalsWhitelist      bool
 	ApprovalsWhitelistUsers       string
@@ -227,6 +233,7 @@ type WebhookForm struct {
 	IssueMilestone       bool
 	IssueComment         bool
 	Release              bool
+	Schedule             bool
 	Push                 bool
 	PullRequest          bool
 	PullRequestAssign    bool
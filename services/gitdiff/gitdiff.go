@@ -577,6 +577,17 @@ func (diffSection *DiffSection) GetComputedInlineDiffFor(diffLine *DiffLine) tem
 	return diffToHTML(diffSection.FileName, diffRecord, diffLine.Type)
 }
 
+// GetComputedHighlightFor highlights the given line without computing inline changes.
+func (diffSection *DiffSection) GetComputedHighlightFor(diffLine *DiffLine) template.HTML {
+	if diffLine.Type == DiffLineSection || setting.Git.DisableDiffHighlight {
+		return template.HTML(getLineContent(diffLine.Content[1:]))
+	}
+	if strings.IndexByte(" +-", diffLine.Content[0]) > -1 {
+		return template.HTML(highlight.Code(diffSection.FileName, diffLine.Content[1:]))
+	}
+	return template.HTML(highlight.Code(diffSection.FileName, diffLine.Content))
+}
+
 // DiffFile represents a file diff.
 type DiffFile struct {
 	Name                    string
@@ -597,6 +608,8 @@ type DiffFile struct {
 	IsProtected             bool
 	IsGenerated             bool
 	IsVendored              bool
+	// DiffDriver is the name of the diff driver the sections are the diff of the converted text of
+	DiffDriver string
 }
 
 // GetType returns type of diff file.
@@ -606,7 +619,7 @@ func (diffFile *DiffFile) GetType() int {
 
 // GetTailSection creates a fake DiffLineSection if the last section is not the end of the file
 func (diffFile *DiffFile) GetTailSection(gitRepo *git.Repository, leftCommitID, rightCommitID string) *DiffSection {
-	if len(diffFile.Sections) == 0 || diffFile.Type != DiffFileChange || diffFile.IsBin || diffFile.IsLFSFile {
+	if len(diffFile.Sections) == 0 || diffFile.Type != DiffFileChange || diffFile.IsBin || diffFile.IsLFSFile || diffFile.DiffDriver != "" {
 		return nil
 	}
 	leftCommit, err := gitRepo.GetCommit(leftCommitID)
@@ -1288,8 +1301,12 @@ func GetDiffRangeWithWhitespaceBehavior(gitRepo *git.Repository, beforeCommitID,
 				_ = util.RemoveAll(workdir)
 			}()
 
+			attributes := []string{"linguist-vendored", "linguist-generated"}
+			if len(setting.DiffDrivers) > 0 {
+				attributes = append(attributes, "diff")
+			}
 			checker = &git.CheckAttributeReader{
-				Attributes: []string{"linguist-vendored", "linguist-generated"},
+				Attributes: attributes,
 				Repo:       gitRepo,
 				IndexFile:  indexFilename,
 				WorkTree:   workdir,
@@ -1316,6 +1333,7 @@ func GetDiffRangeWithWhitespaceBehavior(gitRepo *git.Repository, beforeCommitID,
 
 		gotVendor := false
 		gotGenerated := false
+		var driver *setting.DiffDriver
 		if checker != nil {
 			attrs, err := checker.CheckPath(diffFile.Name)
 			if err == nil {
@@ -1335,6 +1353,7 @@ func GetDiffRangeWithWhitespaceBehavior(gitRepo *git.Repository, beforeCommitID,
 						gotGenerated = generated == "false"
 					}
 				}
+				driver = setting.DiffDrivers[attrs["diff"]]
 			} else {
 				log.Error("Unexpected error: %v", err)
 			}
@@ -1347,6 +1366,10 @@ func GetDiffRangeWithWhitespaceBehavior(gitRepo *git.Repository, beforeCommitID,
 			diffFile.IsGenerated = analyze.IsGenerated(diffFile.Name)
 		}
 
+		if driver != nil {
+			applyDiffDriver(ctx, gitRepo, diffFile, driver, beforeCommitID, afterCommitID, maxLines, maxLineCharacters)
+		}
+
 		tailSection := diffFile.GetTailSection(gitRepo, beforeCommitID, afterCommitID)
 		if tailSection != nil {
 			diffFile.Sections = append(diffFile.Sections, tailSection)
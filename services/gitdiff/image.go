@@ -0,0 +1,110 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"  // for the dimensions of gif images
+	_ "image/jpeg" // for the dimensions of jpeg images
+	_ "image/png"  // for the dimensions of png images
+	"io"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/typesniffer"
+)
+
+// ImageDiffBlob is a version of an image in a diff
+type ImageDiffBlob struct {
+	SHA      string
+	Size     int64
+	MimeType string
+	// Width and Height are 0 if the format of the image is not supported
+	Width  int
+	Height int
+}
+
+// ImageDiff are the versions of a changed image, Before is nil if the image was added and After if it was deleted
+type ImageDiff struct {
+	Before *ImageDiffBlob
+	After  *ImageDiffBlob
+}
+
+// GetImageDiff returns the versions of a file of a diff if it is an image, or nil if it is none
+func GetImageDiff(gitRepo *git.Repository, diffFile *DiffFile, beforeCommitID, afterCommitID string) (*ImageDiff, error) {
+	if diffFile.IsSubmodule || diffFile.IsLFSFile {
+		return nil, nil
+	}
+	// images are binary apart from svg images, or converted to text by a diff driver
+	if !diffFile.IsBin && diffFile.DiffDriver == "" && !strings.EqualFold(path.Ext(diffFile.Name), ".svg") {
+		return nil, nil
+	}
+
+	oldName := diffFile.OldName
+	if oldName == "" {
+		oldName = diffFile.Name
+	}
+	imageDiff := &ImageDiff{}
+	var err error
+	if !diffFile.IsCreated {
+		if imageDiff.Before, err = getImageDiffBlob(gitRepo, beforeCommitID, oldName); err != nil {
+			return nil, err
+		}
+	}
+	if !diffFile.IsDeleted {
+		if imageDiff.After, err = getImageDiffBlob(gitRepo, afterCommitID, diffFile.Name); err != nil {
+			return nil, err
+		}
+	}
+	if imageDiff.Before == nil && imageDiff.After == nil {
+		return nil, nil
+	}
+	return imageDiff, nil
+}
+
+// getImageDiffBlob returns the file at treePath in a commit if it is an image, or nil if it is none or does not exist
+func getImageDiffBlob(gitRepo *git.Repository, commitID, treePath string) (*ImageDiffBlob, error) {
+	if commitID == "" || commitID == git.EmptySHA {
+		return nil, nil
+	}
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := commit.GetBlobByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+
+	buf := make([]byte, 1024)
+	n, _ := io.ReadFull(dataRc, buf)
+	buf = buf[:n]
+	st := typesniffer.DetectContentType(buf)
+	if !st.IsImage() {
+		return nil, nil
+	}
+
+	result := &ImageDiffBlob{
+		SHA:      blob.ID.String(),
+		Size:     blob.Size(),
+		MimeType: st.GetMimeType(),
+	}
+	if cfg, _, err := image.DecodeConfig(io.MultiReader(bytes.NewReader(buf), dataRc)); err == nil {
+		result.Width = cfg.Width
+		result.Height = cfg.Height
+	}
+	return result, nil
+}
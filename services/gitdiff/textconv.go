@@ -0,0 +1,182 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/sandbox"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// errTextconvTooLarge is returned if the text of a file converted by a diff driver exceeds its MaxFileSize
+var errTextconvTooLarge = errors.New("text exceeds the maximum file size of the diff driver")
+
+// textconv converts a file to text with a diff driver. The driver only gets the content of the file
+// on its stdin, and runs in the sandbox command in an empty directory without the environment of Gitea.
+func textconv(ctx context.Context, driver *setting.DiffDriver, treePath string, content io.Reader) ([]byte, error) {
+	dir, err := sandbox.NewDir(setting.DiffDriverSandboxCommand, "gitea-textconv")
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// the driver is stopped as soon as its text is too large
+	stdout := &sandbox.LimitedBuffer{Limit: driver.MaxFileSize, OnExceed: cancel}
+	stderr := new(bytes.Buffer)
+	err = dir.Run(ctx, &sandbox.RunOptions{
+		Args:        strings.Fields(driver.Textconv),
+		Description: fmt.Sprintf("Textconv [%s] %s", driver.Name, treePath),
+		Timeout:     driver.Timeout,
+		Stdin:       content,
+		Stdout:      stdout,
+		Stderr:      stderr,
+	})
+	switch {
+	case stdout.Exceeded():
+		return nil, errTextconvTooLarge
+	case err == sandbox.ErrTimedOut:
+		return nil, fmt.Errorf("diff driver %s timed out after %v", driver.Name, driver.Timeout)
+	case err != nil:
+		return nil, fmt.Errorf("diff driver %s failed: %v\nstderr: %s", driver.Name, err, stderr.String())
+	}
+	return []byte(stdout.String()), nil
+}
+
+// textconvBlob converts the file at treePath in a commit to text with a diff driver,
+// a file which does not exist in the commit is empty
+func textconvBlob(ctx context.Context, gitRepo *git.Repository, driver *setting.DiffDriver, commitID, treePath string) ([]byte, error) {
+	if commitID == "" || commitID == git.EmptySHA {
+		return nil, nil
+	}
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := commit.GetBlobByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if blob.Size() > driver.MaxFileSize {
+		return nil, errTextconvTooLarge
+	}
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	return textconv(ctx, driver, treePath, dataRc)
+}
+
+// diffText diffs two texts with git and returns their sections as sections of the file
+func diffText(ctx context.Context, treePath string, oldText, newText []byte, maxLines, maxLineCharacters int) (*DiffFile, error) {
+	dir, err := os.MkdirTemp("", "gitea-textconv-diff")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary directory: %v", err)
+	}
+	defer func() {
+		if err := util.RemoveAll(dir); err != nil {
+			log.Warn("Unable to remove temporary directory: %s: Error: %v", dir, err)
+		}
+	}()
+	if err := os.WriteFile(filepath.Join(dir, "old"), oldText, 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new"), newText, 0o600); err != nil {
+		return nil, err
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, git.GitExecutable, "diff", "--no-index", "--no-color", "--no-ext-diff",
+		"--src-prefix=\\a/", "--dst-prefix=\\b/", "old", "new")
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// git diff --no-index exits with 1 if the texts differ
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return nil, fmt.Errorf("git diff --no-index: %v\nstderr: %s", err, stderr.String())
+	}
+
+	diff, err := ParsePatch(maxLines, maxLineCharacters, 1, stdout)
+	if err != nil {
+		return nil, fmt.Errorf("ParsePatch: %v", err)
+	}
+	file := &DiffFile{Name: treePath}
+	if len(diff.Files) == 0 {
+		// the texts are the same
+		return file, nil
+	}
+	file.Sections = diff.Files[0].Sections
+	file.Addition, file.Deletion = diff.Files[0].Addition, diff.Files[0].Deletion
+	file.IsBin = diff.Files[0].IsBin
+	file.IsIncomplete = diff.Files[0].IsIncomplete
+	file.IsIncompleteLineTooLong = diff.Files[0].IsIncompleteLineTooLong
+	for _, section := range file.Sections {
+		section.FileName = treePath
+		for _, line := range section.Lines {
+			// the lines of the text are not the lines of the file, which cannot be expanded
+			line.SectionInfo = nil
+		}
+	}
+	return file, nil
+}
+
+// applyDiffDriver replaces the sections of a file in a diff by the diff of its text converted by a diff driver.
+// The file is left as it is if it cannot be converted.
+func applyDiffDriver(ctx context.Context, gitRepo *git.Repository, diffFile *DiffFile, driver *setting.DiffDriver, beforeCommitID, afterCommitID string, maxLines, maxLineCharacters int) {
+	if diffFile.IsSubmodule || diffFile.IsLFSFile {
+		return
+	}
+
+	oldName := diffFile.OldName
+	if oldName == "" {
+		oldName = diffFile.Name
+	}
+	var oldText, newText []byte
+	var err error
+	if !diffFile.IsCreated {
+		if oldText, err = textconvBlob(ctx, gitRepo, driver, beforeCommitID, oldName); err != nil {
+			log.Warn("Unable to convert %s at %s with diff driver %s: %v", oldName, beforeCommitID, driver.Name, err)
+			return
+		}
+	}
+	if !diffFile.IsDeleted {
+		if newText, err = textconvBlob(ctx, gitRepo, driver, afterCommitID, diffFile.Name); err != nil {
+			log.Warn("Unable to convert %s at %s with diff driver %s: %v", diffFile.Name, afterCommitID, driver.Name, err)
+			return
+		}
+	}
+
+	text, err := diffText(ctx, diffFile.Name, oldText, newText, maxLines, maxLineCharacters)
+	if err != nil {
+		log.Warn("Unable to diff the text of %s converted by diff driver %s: %v", diffFile.Name, driver.Name, err)
+		return
+	}
+	diffFile.Sections = text.Sections
+	diffFile.Addition, diffFile.Deletion = text.Addition, text.Deletion
+	diffFile.IsBin = text.IsBin
+	diffFile.IsIncomplete = text.IsIncomplete
+	diffFile.IsIncompleteLineTooLong = text.IsIncompleteLineTooLong
+	diffFile.DiffDriver = driver.Name
+}
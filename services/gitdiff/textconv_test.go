@@ -0,0 +1,67 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/sandbox"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextconv(t *testing.T) {
+	defer func(sandboxCommand string) {
+		setting.DiffDriverSandboxCommand = sandboxCommand
+	}(setting.DiffDriverSandboxCommand)
+
+	// diff drivers never run without a sandbox
+	setting.DiffDriverSandboxCommand = ""
+	driver := &setting.DiffDriver{
+		Name:        "upper",
+		Textconv:    "tr a-z A-Z",
+		Timeout:     10 * time.Second,
+		MaxFileSize: 1024,
+	}
+	_, err := textconv(context.Background(), driver, "a.txt", strings.NewReader("hello\n"))
+	assert.Equal(t, sandbox.ErrNoSandbox, err)
+
+	// env stands in for a real sandbox, it runs the command it is given
+	setting.DiffDriverSandboxCommand = "env"
+	text, err := textconv(context.Background(), driver, "a.txt", strings.NewReader("hello\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "HELLO\n", string(text))
+
+	_, err = textconv(context.Background(), driver, "a.txt", strings.NewReader(strings.Repeat("a", 2048)))
+	assert.Equal(t, errTextconvTooLarge, err)
+
+	driver.Textconv = "sleep 5"
+	driver.Timeout = 100 * time.Millisecond
+	_, err = textconv(context.Background(), driver, "a.txt", strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestDiffText(t *testing.T) {
+	file, err := diffText(context.Background(), "doc.docx", []byte("one\ntwo\n"), []byte("one\nthree\n"), 100, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "doc.docx", file.Name)
+	assert.Equal(t, 1, file.Addition)
+	assert.Equal(t, 1, file.Deletion)
+	if assert.Len(t, file.Sections, 1) {
+		assert.Equal(t, "doc.docx", file.Sections[0].FileName)
+		assert.Equal(t, DiffLineDel, file.Sections[0].Lines[2].Type)
+		assert.Equal(t, "-two", file.Sections[0].Lines[2].Content)
+		assert.Equal(t, DiffLineAdd, file.Sections[0].Lines[3].Type)
+		assert.Equal(t, "+three", file.Sections[0].Lines[3].Content)
+	}
+
+	file, err = diffText(context.Background(), "doc.docx", []byte("same\n"), []byte("same\n"), 100, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, file.Sections)
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package hookscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/sandbox"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Result represents the outcome of a hook script
+type Result struct {
+	Script   *models.RepoHookScript
+	ExitCode int
+	TimedOut bool
+	Output   string
+}
+
+// Succeeded returns whether the script exited with 0 in time
+func (r *Result) Succeeded() bool {
+	return !r.TimedOut && r.ExitCode == 0
+}
+
+// FormatOutput returns the output of the scripts as it is shown to the pusher
+func FormatOutput(results []*Result) string {
+	var sb strings.Builder
+	for _, result := range results {
+		output := strings.TrimRight(result.Output, "\n")
+		if output == "" && result.Succeeded() {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s hook script %q:\n", result.Script.HookType, result.Script.Name)
+		if output != "" {
+			sb.WriteString(output)
+			sb.WriteString("\n")
+		}
+		if result.TimedOut {
+			fmt.Fprintf(&sb, "Timed out after %v\n", setting.HookScripts.Timeout)
+		} else if result.ExitCode != 0 {
+			fmt.Fprintf(&sb, "Exited with %d\n", result.ExitCode)
+		}
+	}
+	return sb.String()
+}
+
+// Run runs the active and approved scripts of a repository for a git hook with the updated refs of a push
+// in the order of the scripts. The scripts of pre-receive hooks stop at the first one which did not succeed.
+func Run(ctx context.Context, repo *models.Repository, hookType models.HookScriptType, opts *private.HookOptions) ([]*Result, error) {
+	if !setting.HookScripts.Enabled {
+		return nil, nil
+	}
+
+	scripts, err := models.GetRunnableRepoHookScripts(repo.ID, hookType)
+	if err != nil || len(scripts) == 0 {
+		return nil, err
+	}
+
+	var input strings.Builder
+	for i := range opts.OldCommitIDs {
+		fmt.Fprintf(&input, "%s %s %s\n", opts.OldCommitIDs[i], opts.NewCommitIDs[i], opts.RefFullNames[i])
+	}
+
+	results := make([]*Result, 0, len(scripts))
+	for _, script := range scripts {
+		result, err := runScript(ctx, repo, script, input.String(), opts)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+		if hookType == models.HookScriptPreReceive && !result.Succeeded() {
+			break
+		}
+	}
+	return results, nil
+}
+
+func scriptEnv(repo *models.Repository, opts *private.HookOptions) []string {
+	env := []string{
+		"GIT_DIR=" + repo.RepoPath(),
+		models.EnvRepoUsername + "=" + repo.OwnerName,
+		models.EnvRepoName + "=" + repo.Name,
+		models.EnvPusherName + "=" + opts.UserName,
+		models.EnvPusherID + "=" + strconv.FormatInt(opts.UserID, 10),
+	}
+	// the objects of a pre-receive hook are only in the quarantine directory until they are accepted
+	if opts.GitObjectDirectory != "" {
+		env = append(env, private.GitObjectDirectory+"="+opts.GitObjectDirectory)
+	}
+	if opts.GitAlternativeObjectDirectories != "" {
+		env = append(env, private.GitAlternativeObjectDirectories+"="+opts.GitAlternativeObjectDirectories)
+	}
+	if opts.GitQuarantinePath != "" {
+		env = append(env, private.GitQuarantinePath+"="+opts.GitQuarantinePath)
+	}
+	if len(opts.GitPushOptions) > 0 {
+		env = append(env, fmt.Sprintf("%s=%d", private.GitPushOptionCount, len(opts.GitPushOptions)))
+		i := 0
+		for key, value := range opts.GitPushOptions {
+			option := key
+			if value != "" {
+				option += "=" + value
+			}
+			env = append(env, fmt.Sprintf("GIT_PUSH_OPTION_%d=%s", i, option))
+			i++
+		}
+	}
+	return env
+}
+
+func runScript(ctx context.Context, repo *models.Repository, script *models.RepoHookScript, input string, opts *private.HookOptions) (*Result, error) {
+	dir, err := sandbox.NewDir(setting.HookScripts.SandboxCommand, "gitea-hook-script")
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	scriptPath := filepath.Join(dir.Path(), "hook")
+	if err := os.WriteFile(scriptPath, []byte(strings.ReplaceAll(script.Content, "\r\n", "\n")), 0o700); err != nil {
+		return nil, fmt.Errorf("unable to write hook script: %v", err)
+	}
+
+	output := &sandbox.LimitedBuffer{Limit: setting.HookScripts.MaxOutputSize}
+	err = dir.Run(ctx, &sandbox.RunOptions{
+		Args:        []string{scriptPath},
+		Description: fmt.Sprintf("HookScript [%s] %s for %s", script.HookType, script.Name, repo.FullName()),
+		Timeout:     setting.HookScripts.Timeout,
+		Env:         scriptEnv(repo, opts),
+		Stdin:       strings.NewReader(input),
+		Stdout:      output,
+		Stderr:      output,
+	})
+
+	result := &Result{Script: script, Output: output.String()}
+	if output.Exceeded() {
+		result.Output += "\n[output truncated]\n"
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case err == sandbox.ErrTimedOut:
+		result.TimedOut = true
+		result.ExitCode = -1
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		// the script could not be started, e.g. because it lacks a shebang
+		log.Error("Unable to run hook script %d of %-v: %v", script.ID, repo, err)
+		result.ExitCode = -1
+		result.Output += fmt.Sprintf("Unable to run script: %v\n", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/notification/base"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/validation"
+)
+
+// maxIssueBranchSlugLength is the maximum length of the slug of the title of an issue in the name of its branch
+const maxIssueBranchSlugLength = 50
+
+var (
+	issueBranchVariablePattern = regexp.MustCompile(`\$\{(\w+)\}`)
+	issueBranchSlugPattern     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ErrInvalidIssueBranchName represents a branch name generated for an issue which is not a valid git reference name
+type ErrInvalidIssueBranchName struct {
+	BranchName string
+}
+
+// IsErrInvalidIssueBranchName checks if an error is a ErrInvalidIssueBranchName.
+func IsErrInvalidIssueBranchName(err error) bool {
+	_, ok := err.(ErrInvalidIssueBranchName)
+	return ok
+}
+
+func (err ErrInvalidIssueBranchName) Error() string {
+	return fmt.Sprintf("invalid issue branch name: %s", err.BranchName)
+}
+
+type issueBranchNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &issueBranchNotifier{}
+)
+
+// InitIssueBranch registers the notifier which links the pull requests opened from issue branches to their issues
+func InitIssueBranch() {
+	notification.RegisterNotifier(&issueBranchNotifier{})
+}
+
+func (*issueBranchNotifier) NotifyNewPullRequest(pr *models.PullRequest, mentions []*models.User) {
+	if pr.Flow != models.PullRequestFlowGithub || pr.HeadRepoID != pr.BaseRepoID {
+		return
+	}
+	branch, err := models.GetIssueBranch(pr.HeadRepoID, pr.HeadBranch)
+	if err != nil {
+		log.Error("GetIssueBranch [repo: %d, branch: %s]: %v", pr.HeadRepoID, pr.HeadBranch, err)
+		return
+	}
+	// only the first pull request opened from the branch is linked
+	if branch == nil || branch.PullID != 0 {
+		return
+	}
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		log.Error("LoadPoster: %v", err)
+		return
+	}
+	if err := models.LinkIssueBranchPull(branch, pr, pr.Issue.Poster); err != nil {
+		log.Error("LinkIssueBranchPull [issue: %d, pull: %d]: %v", branch.IssueID, pr.ID, err)
+	}
+}
+
+// ValidateIssueBranchNameTemplate checks the variables of a name template of issue branches
+func ValidateIssueBranchNameTemplate(tmpl string) error {
+	for _, match := range issueBranchVariablePattern.FindAllStringSubmatch(tmpl, -1) {
+		if match[1] != "Index" && match[1] != "Slug" {
+			return fmt.Errorf("unknown template variable %s", match[0])
+		}
+	}
+	if !strings.Contains(tmpl, "${Index}") && tmpl != "" {
+		return fmt.Errorf("the branch name template must contain ${Index}")
+	}
+	return nil
+}
+
+// IssueBranchName returns the name of the branch for an issue from the name template of its repository
+func IssueBranchName(issue *models.Issue) (string, error) {
+	if err := issue.LoadRepo(); err != nil {
+		return "", err
+	}
+	vars := map[string]string{
+		"Index": strconv.FormatInt(issue.Index, 10),
+		"Slug":  issueBranchSlug(issue.Title),
+	}
+	name := issueBranchVariablePattern.ReplaceAllStringFunc(issue.Repo.IssueBranchNameTemplate(), func(variable string) string {
+		if value, ok := vars[variable[2:len(variable)-1]]; ok {
+			return value
+		}
+		return variable
+	})
+	// an empty slug leaves a separator behind
+	name = strings.Trim(name, "-/")
+	if validation.GitRefNamePatternInvalid.MatchString(name) || !validation.CheckGitRefAdditionalRulesValid(name) {
+		return "", ErrInvalidIssueBranchName{BranchName: name}
+	}
+	return name, nil
+}
+
+// CreateIssueBranch creates a branch from the base branch to work on an issue, named from the name template
+// of its repository unless a name is given, and returns its name
+func CreateIssueBranch(doer *models.User, issue *models.Issue, baseBranch, branchName string) (string, error) {
+	if err := issue.LoadRepo(); err != nil {
+		return "", err
+	}
+	if branchName == "" {
+		var err error
+		if branchName, err = IssueBranchName(issue); err != nil {
+			return "", err
+		}
+	}
+	if baseBranch == "" {
+		baseBranch = issue.Repo.DefaultBranch
+	}
+
+	if err := repo_module.CreateNewBranch(doer, issue.Repo, baseBranch, branchName); err != nil {
+		return "", err
+	}
+	if err := models.CreateIssueBranch(&models.IssueBranch{
+		RepoID:     issue.RepoID,
+		BranchName: branchName,
+		IssueID:    issue.ID,
+		CreatorID:  doer.ID,
+	}); err != nil {
+		return "", err
+	}
+	return branchName, nil
+}
+
+// issueBranchSlug returns the lowercase words of the title of an issue joined by dashes
+func issueBranchSlug(title string) string {
+	slug := strings.Trim(issueBranchSlugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxIssueBranchSlugLength {
+		slug = strings.TrimRight(slug[:maxIssueBranchSlugLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueBranchSlug(t *testing.T) {
+	assert.Equal(t, "fix-the-login-page", issueBranchSlug("Fix the login page!"))
+	assert.Equal(t, "crash-on-v1-2", issueBranchSlug("  [Crash] on v1.2 "))
+	assert.Equal(t, "", issueBranchSlug("???"))
+
+	slug := issueBranchSlug(strings.Repeat("word ", 20))
+	assert.LessOrEqual(t, len(slug), maxIssueBranchSlugLength)
+	assert.False(t, strings.HasSuffix(slug, "-"))
+}
+
+func TestValidateIssueBranchNameTemplate(t *testing.T) {
+	assert.NoError(t, ValidateIssueBranchNameTemplate(""))
+	assert.NoError(t, ValidateIssueBranchNameTemplate("feature/${Index}-${Slug}"))
+	assert.NoError(t, ValidateIssueBranchNameTemplate("issue-${Index}"))
+	assert.Error(t, ValidateIssueBranchNameTemplate("issue-${Slug}"))
+	assert.Error(t, ValidateIssueBranchNameTemplate("issue-${Index}-${Title}"))
+}
+
+func TestIssueBranchName(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	issue, err := models.GetIssueByID(1)
+	assert.NoError(t, err)
+
+	name, err := IssueBranchName(issue)
+	assert.NoError(t, err)
+	assert.Equal(t, "issue-1-issue1", name)
+
+	issue.Title = "..."
+	name, err = IssueBranchName(issue)
+	assert.NoError(t, err)
+	assert.Equal(t, "issue-1", name)
+}
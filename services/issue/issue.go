@@ -6,14 +6,21 @@ package issue
 
 import (
 	"code.gitea.io/gitea/models"
-	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/issueform"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/outbox"
 )
 
 // NewIssue creates new issue with labels for repository.
 func NewIssue(repo *models.Repository, issue *models.Issue, labelIDs []int64, uuids []string, assigneeIDs []int64) error {
+	if err := models.CheckRepoInteractionLimit(repo, issue.Poster); err != nil {
+		return err
+	}
+
 	if err := models.NewIssue(repo, issue, labelIDs, uuids); err != nil {
 		return err
 	}
@@ -24,20 +31,31 @@ func NewIssue(repo *models.Repository, issue *models.Issue, labelIDs []int64, uu
 		}
 	}
 
-	mentions, err := issue.FindAndUpdateIssueMentions(db.DefaultContext(), issue.Poster, issue.Content)
+	// the issue is committed, a failed dispatch is retried later
+	if err := outbox.Dispatch(models.OutboxEventIssueCreated, issue.ID); err != nil {
+		log.Error("Unable to dispatch the creation of issue %d: %v", issue.ID, err)
+	}
+
+	return nil
+}
+
+// NewIssueFromForm creates a new issue whose content is rendered from the values submitted for an issue form,
+// and stores these values alongside the issue.
+func NewIssueFromForm(repo *models.Repository, issue *models.Issue, labelIDs []int64, uuids []string, assigneeIDs []int64, form *api.IssueTemplate, values map[string][]string) error {
+	values, err := issueform.ValidateValues(form, values)
 	if err != nil {
 		return err
 	}
+	issue.Content = issueform.RenderBody(form, values)
 
-	notification.NotifyNewIssue(issue, mentions)
-	if len(issue.Labels) > 0 {
-		notification.NotifyIssueChangeLabels(issue.Poster, issue, issue.Labels, nil)
-	}
-	if issue.Milestone != nil {
-		notification.NotifyIssueChangeMilestone(issue.Poster, issue, 0)
+	if err := NewIssue(repo, issue, labelIDs, uuids, assigneeIDs); err != nil {
+		return err
 	}
-
-	return nil
+	return models.InsertIssueForm(&models.IssueForm{
+		IssueID:  issue.ID,
+		Template: form.FileName,
+		Values:   values,
+	})
 }
 
 // ChangeTitle changes the title of this issue, as the given user.
@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// RollIterations creates the current iterations of all cadences and the ones created ahead.
+func RollIterations(ctx context.Context) error {
+	cadences, err := models.GetAllIterationCadences()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, cadence := range cadences {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+		}
+
+		if err := models.RollIterationCadence(cadence, now); err != nil {
+			log.Error("RollIterationCadence [%d]: %v", cadence.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// UnlockExpiredIssues unlocks all issues whose lock expired.
+// The unlock is recorded on behalf of the user who locked the issue.
+func UnlockExpiredIssues(ctx context.Context) error {
+	issues, err := models.FindIssuesToUnlock(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+		}
+
+		if err := unlockExpiredIssue(issue); err != nil {
+			log.Error("Unable to unlock issue %d: %v", issue.ID, err)
+		}
+	}
+	return nil
+}
+
+func unlockExpiredIssue(issue *models.Issue) error {
+	if err := issue.LoadRepo(); err != nil {
+		return err
+	}
+	locker, err := models.GetIssueLocker(issue)
+	if err != nil {
+		return err
+	}
+	return models.UnlockIssue(&models.IssueLockOptions{
+		Doer:  locker,
+		Issue: issue,
+	})
+}
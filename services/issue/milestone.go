@@ -19,3 +19,17 @@ func ChangeMilestoneAssign(issue *models.Issue, doer *models.User, oldMilestoneI
 
 	return nil
 }
+
+// MoveMilestoneIssues moves the open issues of a milestone to another milestone.
+func MoveMilestoneIssues(doer *models.User, from, to *models.Milestone) (models.IssueList, error) {
+	issues, err := models.MoveMilestoneIssues(doer, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		notification.NotifyIssueChangeMilestone(doer, issue, from.ID)
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// CreateIssueRelation creates a relation between two issues.
+// An open issue marked as a duplicate is closed, unless open dependencies prevent it.
+func CreateIssueRelation(doer *models.User, issue, related *models.Issue, typ models.IssueRelationType) (*models.IssueRelation, error) {
+	relation, err := models.CreateIssueRelation(doer, issue, related, typ)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ == models.IssueRelationTypeDuplicates && !issue.IsClosed {
+		if err := ChangeStatus(issue, doer, true); err != nil {
+			if !models.IsErrDependenciesLeft(err) {
+				return nil, err
+			}
+			log.Debug("Duplicate issue %d is not closed: %v", issue.ID, err)
+		}
+	}
+	return relation, nil
+}
+
+// FilterReadableIssueRelations returns the relations of an issue of a repository to the issues the user can read
+func FilterReadableIssueRelations(doer *models.User, repoID int64, relations []*models.IssueRelationInfo) ([]*models.IssueRelationInfo, error) {
+	// the permissions are cached by repository ID, negated for pull requests
+	canRead := map[int64]bool{}
+	readable := make([]*models.IssueRelationInfo, 0, len(relations))
+	for _, relation := range relations {
+		if relation.Issue.RepoID != repoID {
+			key := relation.Issue.RepoID
+			if relation.Issue.IsPull {
+				key = -key
+			}
+			allowed, ok := canRead[key]
+			if !ok {
+				perm, err := models.GetUserRepoPermission(relation.Issue.Repo, doer)
+				if err != nil {
+					return nil, err
+				}
+				allowed = perm.CanReadIssuesOrPulls(relation.Issue.IsPull)
+				canRead[key] = allowed
+			}
+			if !allowed {
+				continue
+			}
+		}
+		readable = append(readable, relation)
+	}
+	return readable, nil
+}
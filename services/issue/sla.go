@@ -0,0 +1,44 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// CheckIssueSLAs flags the open issues which did not get their first response within the time of the SLA policies
+// of their repository and posts a warning on them.
+func CheckIssueSLAs(ctx context.Context) error {
+	policies, err := models.GetAllIssueSLAPolicies()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	for _, policy := range policies {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+		}
+
+		issues, err := models.FindIssuesBreachingSLAPolicy(policy, now)
+		if err != nil {
+			log.Error("FindIssuesBreachingSLAPolicy [%d]: %v", policy.ID, err)
+			continue
+		}
+		for _, issue := range issues {
+			if err := models.NewIssueSLABreach(policy, issue); err != nil {
+				log.Error("NewIssueSLABreach [policy: %d, issue: %d]: %v", policy.ID, issue.ID, err)
+			}
+		}
+	}
+	return nil
+}
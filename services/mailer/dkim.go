@@ -0,0 +1,259 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"gopkg.in/gomail.v2"
+)
+
+// dkimSigner signs messages sent from a domain as described by RFC 6376
+type dkimSigner struct {
+	domain                 string
+	selector               string
+	algorithm              string
+	key                    crypto.Signer
+	headerCanonicalization string
+	bodyCanonicalization   string
+	signedHeaders          []string
+}
+
+// newDKIMSigner reads the private key of a DKIM configuration, which is either
+// a PKCS #1 RSA key or a PKCS #8 RSA or Ed25519 key in PEM format
+func newDKIMSigner(cfg *setting.MailerDKIM) (*dkimSigner, error) {
+	data, err := os.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", cfg.PrivateKeyFile)
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q in %s", block.Type, cfg.PrivateKeyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &dkimSigner{
+		domain:                 cfg.Domain,
+		selector:               cfg.Selector,
+		headerCanonicalization: cfg.HeaderCanonicalization,
+		bodyCanonicalization:   cfg.BodyCanonicalization,
+		signedHeaders:          cfg.SignedHeaders,
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signer.algorithm = "rsa-sha256"
+		signer.key = k
+	case ed25519.PrivateKey:
+		signer.algorithm = "ed25519-sha256"
+		signer.key = k
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T in %s", key, cfg.PrivateKeyFile)
+	}
+	return signer, nil
+}
+
+// Sign returns the DKIM-Signature header field, including its trailing CRLF, of a message
+func (s *dkimSigner) Sign(msg []byte, now time.Time) (string, error) {
+	header, body := splitMessage(msg)
+	fields := splitHeaderFields(header)
+
+	bodyHash := sha256.Sum256(canonicalizeBody(body, s.bodyCanonicalization))
+
+	// select the last not yet signed instance of each header field, as per RFC 6376 section 5.4.2
+	used := make(map[int]bool)
+	names := make([]string, 0, len(s.signedHeaders))
+	signed := make([]string, 0, len(s.signedHeaders))
+	for _, name := range s.signedHeaders {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if used[i] || !strings.EqualFold(headerFieldName(fields[i]), name) {
+				continue
+			}
+			used[i] = true
+			names = append(names, strings.ToLower(name))
+			signed = append(signed, fields[i])
+			break
+		}
+	}
+
+	value := fmt.Sprintf(" v=1; a=%s; c=%s/%s; d=%s; s=%s;\r\n\tt=%d; h=%s;\r\n\tbh=%s;\r\n\tb=",
+		s.algorithm, s.headerCanonicalization, s.bodyCanonicalization, s.domain, s.selector,
+		now.Unix(), strings.Join(names, ":"), base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	h := sha256.New()
+	for _, field := range signed {
+		_, _ = io.WriteString(h, canonicalizeHeaderField(field, s.headerCanonicalization))
+	}
+	// the signature header field itself is signed without its trailing CRLF
+	_, _ = io.WriteString(h, strings.TrimSuffix(canonicalizeHeaderField("DKIM-Signature:"+value+"\r\n", s.headerCanonicalization), "\r\n"))
+
+	var sig []byte
+	var err error
+	if s.algorithm == "ed25519-sha256" {
+		// RFC 8463 signs the hash with PureEdDSA
+		sig, err = s.key.Sign(rand.Reader, h.Sum(nil), crypto.Hash(0))
+	} else {
+		sig, err = s.key.Sign(rand.Reader, h.Sum(nil), crypto.SHA256)
+	}
+	if err != nil {
+		return "", err
+	}
+	return "DKIM-Signature:" + value + base64.StdEncoding.EncodeToString(sig) + "\r\n", nil
+}
+
+// splitMessage splits a message into its header, including the CRLF of its last field, and its body
+func splitMessage(msg []byte) (header, body []byte) {
+	if idx := bytes.Index(msg, []byte("\r\n\r\n")); idx >= 0 {
+		return msg[:idx+2], msg[idx+4:]
+	}
+	return msg, nil
+}
+
+// splitHeaderFields splits a header into its fields, each including its continuation lines and trailing CRLF
+func splitHeaderFields(header []byte) []string {
+	var fields []string
+	for _, line := range strings.SplitAfter(string(header), "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+		} else {
+			fields = append(fields, line)
+		}
+	}
+	return fields
+}
+
+func headerFieldName(field string) string {
+	if idx := strings.IndexByte(field, ':'); idx >= 0 {
+		return strings.TrimRight(field[:idx], " \t")
+	}
+	return ""
+}
+
+// canonicalizeHeaderField canonicalizes a header field as per RFC 6376 section 3.4.1 and 3.4.2
+func canonicalizeHeaderField(field, canonicalization string) string {
+	if canonicalization != "relaxed" {
+		return field
+	}
+	idx := strings.IndexByte(field, ':')
+	if idx < 0 {
+		return field
+	}
+	name := strings.ToLower(strings.TrimRight(field[:idx], " \t"))
+	value := strings.NewReplacer("\r\n", "").Replace(field[idx+1:])
+	return name + ":" + strings.TrimSpace(collapseWhitespace(value)) + "\r\n"
+}
+
+// canonicalizeBody canonicalizes a message body as per RFC 6376 section 3.4.3 and 3.4.4
+func canonicalizeBody(body []byte, canonicalization string) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	if canonicalization == "relaxed" {
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(collapseWhitespace(line), " ")
+		}
+	}
+	// ignore all empty lines at the end of the body
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		if canonicalization == "relaxed" {
+			return []byte{}
+		}
+		return []byte("\r\n")
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// collapseWhitespace reduces all sequences of spaces and tabs to a single space
+func collapseWhitespace(s string) string {
+	var sb strings.Builder
+	inWhitespace := false
+	for i := 0; i < len(s); i++ {
+		if s[i] == ' ' || s[i] == '\t' {
+			inWhitespace = true
+			continue
+		}
+		if inWhitespace {
+			sb.WriteByte(' ')
+			inWhitespace = false
+		}
+		sb.WriteByte(s[i])
+	}
+	if inWhitespace {
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+// dkimSender signs messages with the DKIM key of their sending domain before passing them on
+type dkimSender struct {
+	sender  gomail.Sender
+	signers map[string]*dkimSigner
+}
+
+func (s *dkimSender) Send(from string, to []string, msg io.WriterTo) error {
+	var domain string
+	if idx := strings.LastIndexByte(from, '@'); idx >= 0 {
+		domain = strings.ToLower(from[idx+1:])
+	}
+	signer, ok := s.signers[domain]
+	if !ok {
+		return s.sender.Send(from, to, msg)
+	}
+
+	var buf bytes.Buffer
+	if _, err := msg.WriteTo(&buf); err != nil {
+		return fmt.Errorf("WriteTo: %v", err)
+	}
+	signature, err := signer.Sign(buf.Bytes(), time.Now())
+	if err != nil {
+		return fmt.Errorf("DKIM sign: %v", err)
+	}
+	signed := bytes.NewBufferString(signature)
+	signed.Write(buf.Bytes())
+	return s.sender.Send(from, to, signed)
+}
+
+// newDKIMSender wraps sender to sign messages with the configured DKIM keys
+func newDKIMSender(sender gomail.Sender, configs map[string]*setting.MailerDKIM) (gomail.Sender, error) {
+	signers := make(map[string]*dkimSigner, len(configs))
+	for domain, cfg := range configs {
+		signer, err := newDKIMSigner(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("DKIM key for %s: %v", domain, err)
+		}
+		signers[domain] = signer
+	}
+	return &dkimSender{sender: sender, signers: signers}, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDKIMCanonicalization(t *testing.T) {
+	// examples from RFC 6376 section 3.4.5
+	fields := splitHeaderFields([]byte("A: X\r\nB : Y\t\r\n\tZ  \r\n"))
+	assert.Equal(t, []string{"A: X\r\n", "B : Y\t\r\n\tZ  \r\n"}, fields)
+	assert.Equal(t, "a:X\r\n", canonicalizeHeaderField(fields[0], "relaxed"))
+	assert.Equal(t, "b:Y Z\r\n", canonicalizeHeaderField(fields[1], "relaxed"))
+	assert.Equal(t, "B : Y\t\r\n\tZ  \r\n", canonicalizeHeaderField(fields[1], "simple"))
+
+	body := []byte(" C \r\nD \t E\r\n\r\n\r\n")
+	assert.Equal(t, " C\r\nD E\r\n", string(canonicalizeBody(body, "relaxed")))
+	assert.Equal(t, " C \r\nD \t E\r\n", string(canonicalizeBody(body, "simple")))
+
+	assert.Equal(t, "", string(canonicalizeBody(nil, "relaxed")))
+	assert.Equal(t, "\r\n", string(canonicalizeBody(nil, "simple")))
+}
+
+func TestDKIMSign(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	signer := &dkimSigner{
+		domain:                 "example.com",
+		selector:               "gitea",
+		algorithm:              "ed25519-sha256",
+		key:                    priv,
+		headerCanonicalization: "relaxed",
+		bodyCanonicalization:   "relaxed",
+		signedHeaders:          []string{"From", "Subject", "To"},
+	}
+	msg := "From: Gitea <gitea@example.com>\r\nSubject:  Test\r\n\tsubject\r\nX-Other: ignored\r\n\r\nHello  world \r\n\r\n"
+
+	header, err := signer.Sign([]byte(msg), time.Unix(1600000000, 0))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(header, "DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed; d=example.com; s=gitea;\r\n"))
+	assert.Contains(t, header, "t=1600000000; h=from:subject;")
+
+	bodyHash := sha256.Sum256([]byte("Hello world\r\n"))
+	assert.Contains(t, header, "bh="+base64.StdEncoding.EncodeToString(bodyHash[:])+";")
+
+	idx := strings.LastIndex(header, "b=")
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(header[idx+2:], "\r\n"))
+	assert.NoError(t, err)
+
+	signedData := "from:Gitea <gitea@example.com>\r\nsubject:Test subject\r\n" +
+		strings.TrimSuffix(canonicalizeHeaderField(header[:idx+2]+"\r\n", "relaxed"), "\r\n")
+	hash := sha256.Sum256([]byte(signedData))
+	assert.True(t, ed25519.Verify(pub, hash[:], sig))
+}
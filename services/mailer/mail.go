@@ -39,6 +39,8 @@ const (
 
 	mailRepoTransferNotify base.TplName = "notify/repo_transfer"
 
+	mailVulnerabilityAlert base.TplName = "notify/vulnerability_alert"
+
 	// There's no actual limit for subject in RFC 5322
 	mailMaxSubjectRunes = 256
 )
@@ -299,6 +301,18 @@ func composeIssueCommentMessages(ctx *mailCommentContext, lang string, recipient
 		log.Error("ExecuteTemplate [%s]: %v", string(tplName)+"/body", err)
 	}
 
+	// Code comments are threaded per file and line below the issue, so that mail clients group them per thread
+	var threadReference string
+	isThreadRoot := false
+	if ctx.Comment != nil && ctx.Comment.Type == models.CommentTypeCode {
+		root, err := models.GetCodeCommentThreadRoot(ctx.Comment)
+		if err != nil {
+			return nil, err
+		}
+		threadReference = ctx.Issue.CodeThreadReplyReference(root)
+		isThreadRoot = root.ID == ctx.Comment.ID
+	}
+
 	// Make sure to compose independent messages to avoid leaking user emails
 	msgs := make([]*Message, 0, len(recipients))
 	for _, recipient := range recipients {
@@ -306,9 +320,17 @@ func composeIssueCommentMessages(ctx *mailCommentContext, lang string, recipient
 		msg.Info = fmt.Sprintf("Subject: %s, %s", subject, info)
 
 		// Set Message-ID on first message so replies know what to reference
-		if actName == "new" {
+		switch {
+		case actName == "new":
 			msg.SetHeader("Message-ID", "<"+ctx.Issue.ReplyReference()+">")
-		} else {
+		case isThreadRoot:
+			msg.SetHeader("Message-ID", "<"+threadReference+">")
+			msg.SetHeader("In-Reply-To", "<"+ctx.Issue.ReplyReference()+">")
+			msg.SetHeader("References", "<"+ctx.Issue.ReplyReference()+">")
+		case threadReference != "":
+			msg.SetHeader("In-Reply-To", "<"+threadReference+">")
+			msg.SetHeader("References", "<"+ctx.Issue.ReplyReference()+"> <"+threadReference+">")
+		default:
 			msg.SetHeader("In-Reply-To", "<"+ctx.Issue.ReplyReference()+">")
 			msg.SetHeader("References", "<"+ctx.Issue.ReplyReference()+">")
 		}
@@ -333,7 +355,10 @@ func generateAdditionalHeaders(ctx *mailCommentContext, reason string, recipient
 		// https://datatracker.ietf.org/doc/html/rfc2369
 		"List-Archive": fmt.Sprintf("<%s>", repo.HTMLURL()),
 		//"List-Post": https://github.com/go-gitea/gitea/pull/13585
-		//"List-Unsubscribe": https://github.com/go-gitea/gitea/issues/10808, https://github.com/go-gitea/gitea/issues/13283
+		"List-Unsubscribe": fmt.Sprintf("<%s>", unsubscribeLink(recipient.ID, ctx.Issue.ID)),
+
+		// https://datatracker.ietf.org/doc/html/rfc8058
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
 
 		"X-Gitea-Reason":            reason,
 		"X-Gitea-Sender":            ctx.Doer.DisplayName(),
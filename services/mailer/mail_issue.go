@@ -10,6 +10,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 )
 
 func fallbackMailSubject(issue *models.Issue) string {
@@ -83,13 +84,34 @@ func mailIssueCommentToParticipants(ctx *mailCommentContext, mentions []*models.
 		unfiltered = append(ids, unfiltered...)
 	}
 
+	// =========== Subscriptions ===========
+	// Drop watchers whose subscription level excludes this event
+	unfiltered, err = models.FilterIssueSubscribers(ctx.Issue, unfiltered, subscriptionEvent(ctx))
+	if err != nil {
+		return fmt.Errorf("FilterIssueSubscribers(%d): %v", ctx.Issue.ID, err)
+	}
+	mentionIDs := make([]int64, 0, len(mentions))
+	for _, mention := range mentions {
+		mentionIDs = append(mentionIDs, mention.ID)
+	}
+	mentionIDs, err = models.FilterIssueSubscribers(ctx.Issue, mentionIDs, models.SubscriptionEventMention)
+	if err != nil {
+		return fmt.Errorf("FilterIssueSubscribers(%d): %v", ctx.Issue.ID, err)
+	}
+	subscribedMentions := make([]*models.User, 0, len(mentionIDs))
+	for _, mention := range mentions {
+		if util.IsInt64InSlice(mention.ID, mentionIDs) {
+			subscribedMentions = append(subscribedMentions, mention)
+		}
+	}
+
 	visited := make(map[int64]bool, len(unfiltered)+len(mentions)+1)
 
 	// Avoid mailing the doer
 	visited[ctx.Doer.ID] = true
 
 	// =========== Mentions ===========
-	if err = mailIssueCommentBatch(ctx, mentions, visited, true); err != nil {
+	if err = mailIssueCommentBatch(ctx, subscribedMentions, visited, true); err != nil {
 		return fmt.Errorf("mailIssueCommentBatch() mentions: %v", err)
 	}
 
@@ -113,12 +135,34 @@ func mailIssueCommentToParticipants(ctx *mailCommentContext, mentions []*models.
 	return nil
 }
 
+// subscriptionEvent returns the event watchers must be subscribed to for receiving the mail
+func subscriptionEvent(ctx *mailCommentContext) models.SubscriptionEvents {
+	if ctx.Comment != nil && ctx.Comment.Type == models.CommentTypePullPush {
+		return models.SubscriptionEventPush
+	}
+	return models.SubscriptionEventFromActionType(ctx.ActionType)
+}
+
 func mailIssueCommentBatch(ctx *mailCommentContext, users []*models.User, visited map[int64]bool, fromMention bool) error {
 	checkUnit := models.UnitTypeIssues
 	if ctx.Issue.IsPull {
 		checkUnit = models.UnitTypePullRequests
 	}
 
+	// Drop users who don't want to receive this event by mail
+	event := subscriptionEvent(ctx)
+	if fromMention {
+		event = models.SubscriptionEventMention
+	}
+	receiverIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		receiverIDs = append(receiverIDs, user.ID)
+	}
+	receiverIDs, err := models.FilterNotificationChannelReceivers(receiverIDs, models.NotificationChannelMail, event)
+	if err != nil {
+		return fmt.Errorf("FilterNotificationChannelReceivers(): %v", err)
+	}
+
 	langMap := make(map[string][]*models.User)
 	for _, user := range users {
 		// At this point we exclude:
@@ -133,6 +177,11 @@ func mailIssueCommentBatch(ctx *mailCommentContext, users []*models.User, visite
 			continue
 		}
 
+		// exclude users who disabled mails for this event
+		if !util.IsInt64InSlice(user.ID, receiverIDs) {
+			continue
+		}
+
 		// now mark them as visited
 		visited[user.ID] = true
 
@@ -12,6 +12,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
@@ -82,6 +83,44 @@ func TestComposeIssueCommentMessage(t *testing.T) {
 	assert.Equal(t, references[0], "<user2/repo1/issues/1@localhost>", "References header doesn't match")
 }
 
+func TestComposeCodeCommentMessages(t *testing.T) {
+	doer, _, _, _ := prepareMailerTest(t)
+	pull := db.AssertExistsAndLoadBean(t, &models.Issue{ID: 2}).(*models.Issue)
+	assert.NoError(t, pull.LoadRepo())
+
+	stpl := texttmpl.Must(texttmpl.New("issue/comment").Parse(subjectTpl))
+	btpl := template.Must(template.New("issue/comment").Parse(bodyTpl))
+	InitMailRender(stpl, btpl)
+
+	issueReference := "<user2/repo1/pulls/2@localhost>"
+	threadReference := "<user2/repo1/pulls/2/reviews/0/" + base.EncodeSha1("README.md") + "/-4@localhost>"
+	recipients := []*models.User{{Name: "Test", Email: "test@gitea.com"}}
+
+	// the first comment of a thread starts it
+	root := db.AssertExistsAndLoadBean(t, &models.Comment{ID: 5}).(*models.Comment)
+	msgs, err := composeIssueCommentMessages(&mailCommentContext{Issue: pull, Doer: doer, ActionType: models.ActionCommentPull,
+		Content: "test body", Comment: root}, "en-US", recipients, false, "code comment")
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 1) {
+		gomailMsg := msgs[0].ToMessage()
+		assert.Equal(t, []string{threadReference}, gomailMsg.GetHeader("Message-ID"))
+		assert.Equal(t, []string{issueReference}, gomailMsg.GetHeader("In-Reply-To"))
+		assert.Equal(t, []string{issueReference}, gomailMsg.GetHeader("References"))
+	}
+
+	// the other comments on the same line of the file reply to it
+	reply := db.AssertExistsAndLoadBean(t, &models.Comment{ID: 6}).(*models.Comment)
+	msgs, err = composeIssueCommentMessages(&mailCommentContext{Issue: pull, Doer: doer, ActionType: models.ActionCommentPull,
+		Content: "test body", Comment: reply}, "en-US", recipients, false, "code comment")
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 1) {
+		gomailMsg := msgs[0].ToMessage()
+		assert.Nil(t, gomailMsg.GetHeader("Message-ID"))
+		assert.Equal(t, []string{threadReference}, gomailMsg.GetHeader("In-Reply-To"))
+		assert.Equal(t, []string{issueReference + " " + threadReference}, gomailMsg.GetHeader("References"))
+	}
+}
+
 func TestComposeIssueMessage(t *testing.T) {
 	doer, _, issue, _ := prepareMailerTest(t)
 
@@ -212,6 +251,8 @@ func TestGenerateAdditionalHeaders(t *testing.T) {
 	expected := map[string]string{
 		"List-ID":                   "user2/repo1 <repo1.user2.localhost>",
 		"List-Archive":              "<https://try.gitea.io/user2/repo1>",
+		"List-Unsubscribe":          "<https://try.gitea.io/unsubscribe/" + UnsubscribeToken(0, issue.ID) + ">",
+		"List-Unsubscribe-Post":     "List-Unsubscribe=One-Click",
 		"X-Gitea-Reason":            "dummy-reason",
 		"X-Gitea-Sender":            "< U<se>r Tw<o > ><",
 		"X-Gitea-Recipient":         "Test",
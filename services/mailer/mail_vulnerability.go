@@ -0,0 +1,90 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// MailVulnerabilityAlerts notifies the owners of a repository about new vulnerability alerts
+func MailVulnerabilityAlerts(repo *models.Repository, alerts []*models.VulnerabilityAlert) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+	ownerIDs := []int64{repo.OwnerID}
+	if repo.Owner.IsOrganization() {
+		team, err := repo.Owner.GetOwnerTeam()
+		if err != nil {
+			return err
+		}
+		if err := team.GetMembers(&models.SearchMembersOptions{}); err != nil {
+			return err
+		}
+		ownerIDs = make([]int64, 0, len(team.Members))
+		for _, member := range team.Members {
+			ownerIDs = append(ownerIDs, member.ID)
+		}
+	}
+
+	recipients, err := models.GetMaileableUsersByIDs(ownerIDs, false)
+	if err != nil {
+		return err
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+	for lang, tos := range langMap {
+		if err := sendVulnerabilityAlertsMailPerLang(lang, tos, repo, alerts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendVulnerabilityAlertsMailPerLang(lang string, tos []string, repo *models.Repository, alerts []*models.VulnerabilityAlert) error {
+	var (
+		locale  = translation.NewLocale(lang)
+		content bytes.Buffer
+	)
+
+	subject := locale.Tr("mail.repo.vulnerability.subject", len(alerts), repo.FullName())
+	data := map[string]interface{}{
+		"Repo":     repo.FullName(),
+		"Link":     repo.HTMLURL(),
+		"Alerts":   alerts,
+		"Subject":  subject,
+		"Language": locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailVulnerabilityAlert), data); err != nil {
+		return err
+	}
+
+	msgs := make([]*Message, 0, len(tos))
+	for _, to := range tos {
+		msg := NewMessage([]string{to}, subject, content.String())
+		msg.Info = fmt.Sprintf("Repo: %d, vulnerability alerts", repo.ID)
+		msgs = append(msgs, msg)
+	}
+	SendAsyncs(msgs)
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/queue"
 	"code.gitea.io/gitea/modules/setting"
@@ -129,13 +130,12 @@ func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
 type smtpSender struct {
 }
 
-// Send send email
-func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
-	opts := setting.MailService
-
+// newSMTPClient connects to the configured SMTP server, greets it and switches to TLS if possible.
+// A timeout of zero means no timeout.
+func newSMTPClient(opts *setting.Mailer, timeout time.Duration) (*smtp.Client, error) {
 	host, port, err := net.SplitHostPort(opts.Host)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	tlsconfig := &tls.Config{
@@ -146,16 +146,21 @@ func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
 	if opts.UseCertificate {
 		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		tlsconfig.Certificates = []tls.Certificate{cert}
 	}
 
-	conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), timeout)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			conn.Close()
+			return nil, err
+		}
 	}
-	defer conn.Close()
 
 	isSecureConn := opts.IsTLSEnabled || (strings.HasSuffix(port, "465"))
 	// Start TLS directly if the port ends with 465 (SMTPS protocol)
@@ -165,7 +170,8 @@ func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
 
 	client, err := smtp.NewClient(conn, host)
 	if err != nil {
-		return fmt.Errorf("NewClient: %v", err)
+		conn.Close()
+		return nil, fmt.Errorf("NewClient: %v", err)
 	}
 
 	if !opts.DisableHelo {
@@ -173,12 +179,14 @@ func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
 		if len(hostname) == 0 {
 			hostname, err = os.Hostname()
 			if err != nil {
-				return err
+				client.Close()
+				return nil, err
 			}
 		}
 
 		if err = client.Hello(hostname); err != nil {
-			return fmt.Errorf("Hello: %v", err)
+			client.Close()
+			return nil, fmt.Errorf("Hello: %v", err)
 		}
 	}
 
@@ -186,10 +194,42 @@ func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
 	hasStartTLS, _ := client.Extension("STARTTLS")
 	if !isSecureConn && hasStartTLS {
 		if err = client.StartTLS(tlsconfig); err != nil {
-			return fmt.Errorf("StartTLS: %v", err)
+			client.Close()
+			return nil, fmt.Errorf("StartTLS: %v", err)
 		}
 	}
 
+	return client, nil
+}
+
+// CheckSMTPConnection checks that the configured SMTP server can be reached and greeted
+func CheckSMTPConnection(timeout time.Duration) error {
+	if setting.MailService == nil || setting.MailService.MailerType != "smtp" {
+		return nil
+	}
+	client, err := newSMTPClient(setting.MailService, timeout)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Quit()
+}
+
+// Send send email
+func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
+	opts := setting.MailService
+
+	host, _, err := net.SplitHostPort(opts.Host)
+	if err != nil {
+		return err
+	}
+
+	client, err := newSMTPClient(opts, 0)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
 	canAuth, options := client.Extension("AUTH")
 	if canAuth && len(opts.User) > 0 {
 		var auth smtp.Auth
@@ -319,12 +359,22 @@ func NewContext() {
 		Sender = &dummySender{}
 	}
 
+	if len(setting.MailService.DKIM) > 0 {
+		sender, err := newDKIMSender(Sender, setting.MailService.DKIM)
+		if err != nil {
+			log.Fatal("Failed to load DKIM keys: %v", err)
+		}
+		Sender = sender
+	}
+
 	mailQueue = queue.CreateQueue("mail", func(data ...queue.Data) {
 		for _, datum := range data {
 			msg := datum.(*Message)
 			gomailMsg := msg.ToMessage()
 			log.Trace("New e-mail sending request %s: %s", gomailMsg.GetHeader("To"), msg.Info)
-			if err := gomail.Send(Sender, gomailMsg); err != nil {
+			err := gomail.Send(Sender, gomailMsg)
+			metrics.ObserveMailSent(err)
+			if err != nil {
 				log.Error("Failed to send emails %s: %s - %v", gomailMsg.GetHeader("To"), msg.Info, err)
 			} else {
 				log.Trace("E-mails sent %s: %s", gomailMsg.GetHeader("To"), msg.Info)
@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// UnsubscribeToken returns the signed token allowing a user to mute an issue from its notification mails
+func UnsubscribeToken(userID, issueID int64) string {
+	return fmt.Sprintf("%d.%d.%s", userID, issueID, unsubscribeSignature(userID, issueID))
+}
+
+// ParseUnsubscribeToken returns the user and the issue of a token returned by UnsubscribeToken,
+// ok is false if the token is malformed or its signature does not match
+func ParseUnsubscribeToken(token string) (userID, issueID int64, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+	userID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	issueID, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(unsubscribeSignature(userID, issueID))) {
+		return 0, 0, false
+	}
+	return userID, issueID, true
+}
+
+func unsubscribeSignature(userID, issueID int64) string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = fmt.Fprintf(mac, "unsubscribe:%d:%d", userID, issueID)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// unsubscribeLink returns the link muting an issue for a user
+func unsubscribeLink(userID, issueID int64) string {
+	return setting.AppURL + "unsubscribe/" + UnsubscribeToken(userID, issueID)
+}
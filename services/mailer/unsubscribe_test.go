@@ -0,0 +1,38 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	setting.SecretKey = "secret"
+
+	token := UnsubscribeToken(2, 10)
+	userID, issueID, ok := ParseUnsubscribeToken(token)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, userID)
+	assert.EqualValues(t, 10, issueID)
+
+	// the signature covers both the user and the issue
+	_, _, ok = ParseUnsubscribeToken("3.10." + token[len("2.10."):])
+	assert.False(t, ok)
+	_, _, ok = ParseUnsubscribeToken("2.11." + token[len("2.10."):])
+	assert.False(t, ok)
+
+	for _, invalid := range []string{"", "2.10", "a.10.abc", "2.b.abc", "2.10.abc.def"} {
+		_, _, ok = ParseUnsubscribeToken(invalid)
+		assert.False(t, ok, invalid)
+	}
+
+	setting.SecretKey = "other secret"
+	_, _, ok = ParseUnsubscribeToken(token)
+	assert.False(t, ok)
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/maintenance"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// refreshInterval is how often the persisted state is reloaded, so that instances sharing a database follow each other
+const refreshInterval = 10 * time.Second
+
+// Init loads the persisted maintenance mode and keeps it refreshed in the background
+func Init() error {
+	if err := load(); err != nil {
+		return err
+	}
+	go graceful.GetManager().RunWithShutdownContext(refresh)
+	return nil
+}
+
+func refresh(ctx context.Context) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := load(); err != nil {
+				log.Error("Unable to load maintenance mode: %v", err)
+			}
+			checkDatabase()
+		}
+	}
+}
+
+// checkDatabase updates whether the database is read-only, so that writes are rejected as soon as a failover
+// begins and accepted again as soon as it ends
+func checkDatabase() {
+	readOnly, err := db.ProbeReadOnly()
+	if err == db.ErrReadOnlyProbeUnsupported {
+		// the database can only be checked by writing to it, let the writes through again once the suggested delay has passed
+		since := maintenance.DatabaseReadOnlySince()
+		readOnly = !since.IsZero() && time.Since(since) < setting.Database.ReadOnlyRetryAfter
+	} else if err != nil {
+		log.Error("Unable to check if the database is read-only: %v", err)
+		return
+	}
+
+	if !maintenance.SetDatabaseReadOnly(readOnly) {
+		return
+	}
+	if readOnly {
+		log.Warn("Database is read-only, writes are rejected until it is writable again")
+	} else {
+		log.Info("Database is writable again")
+	}
+}
+
+func load() error {
+	m, err := models.GetMaintenanceMode()
+	if err != nil {
+		return err
+	}
+	maintenance.Set(toState(m))
+	return nil
+}
+
+func toState(m *models.MaintenanceMode) maintenance.State {
+	return maintenance.State{
+		Enabled:    m.IsEnabled,
+		Message:    m.Message,
+		RetryAfter: time.Duration(m.RetryAfter) * time.Second,
+		Since:      m.UpdatedUnix.AsTime(),
+	}
+}
+
+// Enable puts the instance in maintenance mode, rejecting writes until it is disabled
+func Enable(doer *models.User, message string, retryAfter time.Duration) error {
+	return set(&models.MaintenanceMode{
+		IsEnabled:  true,
+		Message:    message,
+		RetryAfter: int64(retryAfter / time.Second),
+		DoerID:     doer.ID,
+	})
+}
+
+// Disable takes the instance out of maintenance mode
+func Disable(doer *models.User) error {
+	return set(&models.MaintenanceMode{DoerID: doer.ID})
+}
+
+func set(m *models.MaintenanceMode) error {
+	if err := models.SetMaintenanceMode(m); err != nil {
+		return err
+	}
+	if m.IsEnabled {
+		log.Warn("Maintenance mode enabled by user %d: %s", m.DoerID, m.Message)
+	} else {
+		log.Info("Maintenance mode disabled by user %d", m.DoerID)
+	}
+	return load()
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// staleAfter is the time after which an event which was not dispatched right after its mutation,
+// or claimed by a dispatcher which did not finish, is dispatched by DispatchPending
+const staleAfter = 5 * time.Minute
+
+// batchSize is the number of pending events dispatched at once by DispatchPending
+const batchSize = 50
+
+type handler func(event *models.OutboxEvent) error
+
+var handlers = map[models.OutboxEventType]handler{
+	models.OutboxEventIssueCreated:   dispatchIssueCreated,
+	models.OutboxEventCommentCreated: dispatchCommentCreated,
+}
+
+func dispatchIssueCreated(event *models.OutboxEvent) error {
+	issue, err := models.GetIssueByID(event.RefID)
+	if err != nil {
+		return err
+	}
+	if err := issue.LoadAttributes(); err != nil {
+		return err
+	}
+
+	mentions, err := issue.FindAndUpdateIssueMentions(db.DefaultContext(), issue.Poster, issue.Content)
+	if err != nil {
+		return err
+	}
+
+	notification.NotifyNewIssue(issue, mentions)
+	if len(issue.Labels) > 0 {
+		notification.NotifyIssueChangeLabels(issue.Poster, issue, issue.Labels, nil)
+	}
+	if issue.Milestone != nil {
+		notification.NotifyIssueChangeMilestone(issue.Poster, issue, 0)
+	}
+	return nil
+}
+
+func dispatchCommentCreated(event *models.OutboxEvent) error {
+	comment, err := models.GetCommentByID(event.RefID)
+	if err != nil {
+		return err
+	}
+	if err := comment.LoadPoster(); err != nil {
+		return err
+	}
+	if err := comment.LoadIssue(); err != nil {
+		return err
+	}
+	if err := comment.Issue.LoadRepo(); err != nil {
+		return err
+	}
+
+	mentions, err := comment.Issue.FindAndUpdateIssueMentions(db.DefaultContext(), comment.Poster, comment.Content)
+	if err != nil {
+		return err
+	}
+
+	notification.NotifyCreateIssueComment(comment.Poster, comment.Issue.Repo, comment.Issue, comment, mentions)
+	return nil
+}
+
+// dispatch claims an event and fires its webhooks, notifications and mails. Events whose
+// mutation no longer exists, e.g. an issue deleted in the meantime, are marked as dispatched.
+func dispatch(event *models.OutboxEvent) error {
+	handle, ok := handlers[event.Type]
+	if !ok {
+		return fmt.Errorf("unknown outbox event type %q", event.Type)
+	}
+
+	claimed, err := models.ClaimOutboxEvent(event)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := handle(event); err != nil && !models.IsErrIssueNotExist(err) && !models.IsErrCommentNotExist(err) {
+		if markErr := models.MarkOutboxEventFailed(event, err); markErr != nil {
+			log.Error("MarkOutboxEventFailed[%d]: %v", event.ID, markErr)
+		}
+		return err
+	}
+	return models.MarkOutboxEventDispatched(event)
+}
+
+// Dispatch fires the webhooks, notifications and mails of a mutation right after its transaction
+// was committed. If the process dies before, they are fired by DispatchPending.
+func Dispatch(typ models.OutboxEventType, refID int64) error {
+	event, err := models.GetUndispatchedOutboxEvent(typ, refID)
+	if err != nil {
+		if models.IsErrOutboxEventNotExist(err) {
+			// already dispatched by DispatchPending
+			return nil
+		}
+		return err
+	}
+	return dispatch(event)
+}
+
+// DispatchPending dispatches the events which were not dispatched after their mutation,
+// because the process died or their dispatch failed
+func DispatchPending(ctx context.Context) error {
+	var lastID int64
+	for {
+		events, err := models.FindPendingOutboxEvents(lastID, staleAfter, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("aborted dispatching outbox events: %w", ctx.Err())
+			default:
+			}
+			// failed events are retried by the next run
+			if err := dispatch(event); err != nil {
+				log.Error("Unable to dispatch outbox event %d [%s %d]: %v", event.ID, event.Type, event.RefID, err)
+			}
+			lastID = event.ID
+		}
+		if len(events) < batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+var (
+	// ErrDigestMismatch indicates that the content does not match the provided digest
+	ErrDigestMismatch = errors.New("Digest does not match the content")
+	// ErrBlobUnknown indicates that a referenced blob is unknown
+	ErrBlobUnknown = errors.New("Referenced blob is unknown")
+	// ErrManifestUnknown indicates that the manifest is unknown
+	ErrManifestUnknown = errors.New("Manifest is unknown")
+)
+
+// ManifestInfo describes a stored manifest
+type ManifestInfo struct {
+	Digest    string
+	MediaType string
+	Size      int64
+	Version   *models.PackageVersion
+	Blob      *models.PackageBlob
+}
+
+func uploadPath(id string) string {
+	return filepath.Join(setting.Packages.ChunkedUploadPath, id)
+}
+
+// CreateBlobUpload starts a new chunked upload for the package
+func CreateBlobUpload(packageID int64) (*models.PackageBlobUpload, error) {
+	pbu, err := models.CreatePackageBlobUpload(packageID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Create(uploadPath(pbu.ID))
+	if err != nil {
+		return nil, err
+	}
+	return pbu, f.Close()
+}
+
+// AppendToBlobUpload appends the content of r to the upload
+func AppendToBlobUpload(pbu *models.PackageBlobUpload, r io.Reader) error {
+	f, err := os.OpenFile(uploadPath(pbu.ID), os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return err
+	}
+
+	pbu.BytesReceived += n
+	if setting.Packages.MaxFileSize >= 0 && pbu.BytesReceived > setting.Packages.MaxFileSize {
+		return packages_module.ErrFileTooLarge
+	}
+	return models.UpdatePackageBlobUpload(pbu)
+}
+
+// FinishBlobUpload verifies the content of the upload against the digest and stores it as blob
+func FinishBlobUpload(pbu *models.PackageBlobUpload, digest string) (*models.PackageBlob, error) {
+	f, err := os.Open(uploadPath(pbu.ID))
+	if err != nil {
+		return nil, err
+	}
+	pb, err := SaveBlob(f, digest)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return pb, CancelBlobUpload(pbu.ID)
+}
+
+// CancelBlobUpload removes the upload and its content
+func CancelBlobUpload(id string) error {
+	if err := os.Remove(uploadPath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return models.DeletePackageBlobUploadByID(id)
+}
+
+// SaveBlob stores the content of r as blob after validating the digest
+func SaveBlob(r io.Reader, digest string) (*models.PackageBlob, error) {
+	buf, err := packages_module.CreateHashedBufferFromReader(r, setting.Packages.MaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	if container_module.DigestToHash(digest) != buf.SHA256 {
+		return nil, ErrDigestMismatch
+	}
+
+	return saveHashedBuffer(buf)
+}
+
+func saveHashedBuffer(buf *packages_module.HashedBuffer) (*models.PackageBlob, error) {
+	pb, exists, err := models.GetOrInsertPackageBlob(db.DefaultContext(), &models.PackageBlob{
+		Size:       buf.Size,
+		HashMD5:    buf.MD5,
+		HashSHA1:   buf.SHA1,
+		HashSHA256: buf.SHA256,
+		HashSHA512: buf.SHA512,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := packages_module.NewContentStore().Save(pb.HashSHA256, buf, buf.Size); err != nil {
+			if err := models.DeletePackageBlobByID(pb.ID); err != nil {
+				log.Error("Error deleting package blob %d: %v", pb.ID, err)
+			}
+			return nil, err
+		}
+	}
+	return pb, nil
+}
+
+// PutManifest stores the manifest under the reference, which is either a tag or the digest of the manifest
+func PutManifest(owner, creator *models.User, image, reference string, content []byte, contentType string) (string, error) {
+	m, err := container_module.ParseManifest(bytes.NewReader(content), contentType)
+	if err != nil {
+		return "", err
+	}
+
+	digests := m.ReferencedDigests()
+	for _, digest := range digests {
+		if _, err := models.GetPackageBlobByHash(container_module.DigestToHash(digest)); err != nil {
+			if err == models.ErrPackageBlobNotExist {
+				return "", ErrBlobUnknown
+			}
+			return "", err
+		}
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(bytes.NewReader(content), -1)
+	if err != nil {
+		return "", err
+	}
+	defer buf.Close()
+
+	manifestDigest := "sha256:" + buf.SHA256
+	isTagged := !container_module.IsValidDigest(reference)
+	if !isTagged && reference != manifestDigest {
+		return "", ErrDigestMismatch
+	}
+
+	manifestBlob, err := saveHashedBuffer(buf)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := json.Marshal(&container_module.Metadata{
+		MediaType:      m.MediaType,
+		ManifestDigest: manifestDigest,
+		IsTagged:       isTagged,
+		BlobDigests:    digests,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = db.WithTx(func(ctx *db.Context) error {
+		p, err := models.GetOrInsertPackage(ctx, &models.Package{
+			OwnerID: owner.ID,
+			Type:    models.PackageTypeContainer,
+			Name:    image,
+		})
+		if err != nil {
+			return err
+		}
+
+		pv := &models.PackageVersion{
+			PackageID:    p.ID,
+			CreatorID:    creator.ID,
+			Version:      reference,
+			MetadataJSON: string(metadata),
+		}
+		if err := models.InsertPackageVersion(ctx, pv); err != nil {
+			if err != models.ErrDuplicatePackageVersion {
+				return err
+			}
+			// tags are mutable, point the existing version to the new manifest
+			if _, err := ctx.Engine().Where("package_id = ? AND lower_version = ?", p.ID, pv.LowerVersion).Get(pv); err != nil {
+				return err
+			}
+			pv.MetadataJSON = string(metadata)
+			if err := models.UpdatePackageVersionMetadata(ctx, pv); err != nil {
+				return err
+			}
+			if err := models.DeletePackageFilesByVersionID(ctx, pv.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := models.InsertPackageFile(ctx, &models.PackageFile{
+			VersionID: pv.ID,
+			BlobID:    manifestBlob.ID,
+			Name:      manifestDigest,
+		}); err != nil {
+			return err
+		}
+		for _, digest := range digests {
+			pb, err := models.GetPackageBlobByHash(container_module.DigestToHash(digest))
+			if err != nil {
+				return err
+			}
+			if err := models.InsertPackageFile(ctx, &models.PackageFile{
+				VersionID: pv.ID,
+				BlobID:    pb.ID,
+				Name:      digest,
+			}); err != nil && err != models.ErrDuplicatePackageFile {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return manifestDigest, nil
+}
+
+// GetManifest returns the manifest stored under the reference, which is either a tag or a digest
+func GetManifest(p *models.Package, reference string) (*ManifestInfo, error) {
+	versions, err := models.GetPackageVersionsByPackageID(p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	isDigest := container_module.IsValidDigest(reference)
+	for i := len(versions) - 1; i >= 0; i-- {
+		pv := versions[i]
+		var metadata container_module.Metadata
+		if err := json.Unmarshal([]byte(pv.MetadataJSON), &metadata); err != nil {
+			return nil, fmt.Errorf("invalid metadata of package version %d: %v", pv.ID, err)
+		}
+		if (isDigest && metadata.ManifestDigest != reference) || (!isDigest && pv.LowerVersion != reference && pv.Version != reference) {
+			continue
+		}
+
+		pb, err := models.GetPackageBlobByHash(container_module.DigestToHash(metadata.ManifestDigest))
+		if err != nil {
+			return nil, err
+		}
+		return &ManifestInfo{
+			Digest:    metadata.ManifestDigest,
+			MediaType: metadata.MediaType,
+			Size:      pb.Size,
+			Version:   pv,
+			Blob:      pb,
+		}, nil
+	}
+	return nil, ErrManifestUnknown
+}
+
+// GetTags returns the tags of an image
+func GetTags(p *models.Package) ([]string, error) {
+	versions, err := models.GetPackageVersionsByPackageID(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(versions))
+	for _, pv := range versions {
+		var metadata container_module.Metadata
+		if err := json.Unmarshal([]byte(pv.MetadataJSON), &metadata); err != nil {
+			return nil, err
+		}
+		if metadata.IsTagged {
+			tags = append(tags, pv.Version)
+		}
+	}
+	return tags, nil
+}
+
+// CleanupExpiredUploads removes blob uploads which were not updated for the given duration
+func CleanupExpiredUploads(olderThan time.Duration) error {
+	pbus, err := models.FindExpiredPackageBlobUploads(timeutil.TimeStampNow().AddDuration(-olderThan))
+	if err != nil {
+		return err
+	}
+	for _, pbu := range pbus {
+		if err := CancelBlobUpload(pbu.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
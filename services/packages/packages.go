@@ -0,0 +1,235 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/timeutil"
+	container_service "code.gitea.io/gitea/services/packages/container"
+)
+
+// PackageInfo describes a package version
+type PackageInfo struct {
+	Owner       *models.User
+	PackageType models.PackageType
+	Name        string
+	Version     string
+}
+
+// PackageCreationInfo describes a package version to create
+type PackageCreationInfo struct {
+	PackageInfo
+	Creator  *models.User
+	Metadata interface{}
+}
+
+// PackageFileInfo describes a package file to add
+type PackageFileInfo struct {
+	Filename string
+	Data     *packages_module.HashedBuffer
+}
+
+// CreatePackageAndAddFile creates a package with a file. If the same package version exists already, ErrDuplicatePackageVersion is returned
+func CreatePackageAndAddFile(pvci *PackageCreationInfo, pfi *PackageFileInfo) (*models.PackageVersion, *models.PackageFile, error) {
+	return createPackageAndAddFile(pvci, pfi, false)
+}
+
+// CreatePackageOrAddFileToExisting creates a package with a file or adds the file if the package version exists already
+func CreatePackageOrAddFileToExisting(pvci *PackageCreationInfo, pfi *PackageFileInfo) (*models.PackageVersion, *models.PackageFile, error) {
+	return createPackageAndAddFile(pvci, pfi, true)
+}
+
+func createPackageAndAddFile(pvci *PackageCreationInfo, pfi *PackageFileInfo, allowExisting bool) (*models.PackageVersion, *models.PackageFile, error) {
+	ctx, committer, err := db.TxContext()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer committer.Close()
+
+	pv, err := getOrCreatePackageVersion(ctx, pvci, allowExisting)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pf, blobCreated, err := addFileToPackageVersion(ctx, pv, pfi)
+	removeBlob := false
+	defer func() {
+		if blobCreated && removeBlob {
+			if err := packages_module.NewContentStore().Delete(pfi.Data.SHA256); err != nil {
+				log.Error("Error deleting package blob from content store: %v", err)
+			}
+		}
+	}()
+	if err != nil {
+		removeBlob = true
+		return nil, nil, err
+	}
+
+	if err := committer.Commit(); err != nil {
+		removeBlob = true
+		return nil, nil, err
+	}
+
+	return pv, pf, nil
+}
+
+func getOrCreatePackageVersion(ctx *db.Context, pvci *PackageCreationInfo, allowExisting bool) (*models.PackageVersion, error) {
+	p, err := models.GetOrInsertPackage(ctx, &models.Package{
+		OwnerID: pvci.Owner.ID,
+		Type:    pvci.PackageType,
+		Name:    pvci.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetOrInsertPackage: %v", err)
+	}
+
+	metadataJSON, err := json.Marshal(pvci.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	pv := &models.PackageVersion{
+		PackageID:    p.ID,
+		CreatorID:    pvci.Creator.ID,
+		Version:      pvci.Version,
+		MetadataJSON: string(metadataJSON),
+	}
+	if err := models.InsertPackageVersion(ctx, pv); err != nil {
+		if err == models.ErrDuplicatePackageVersion && allowExisting {
+			existing := &models.PackageVersion{}
+			if _, err := ctx.Engine().Where("package_id = ? AND lower_version = ?", p.ID, pv.LowerVersion).Get(existing); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+		return nil, err
+	}
+	return pv, nil
+}
+
+func addFileToPackageVersion(ctx *db.Context, pv *models.PackageVersion, pfi *PackageFileInfo) (*models.PackageFile, bool, error) {
+	pb, exists, err := models.GetOrInsertPackageBlob(ctx, &models.PackageBlob{
+		Size:       pfi.Data.Size,
+		HashMD5:    pfi.Data.MD5,
+		HashSHA1:   pfi.Data.SHA1,
+		HashSHA256: pfi.Data.SHA256,
+		HashSHA512: pfi.Data.SHA512,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("GetOrInsertPackageBlob: %v", err)
+	}
+	if !exists {
+		if err := packages_module.NewContentStore().Save(pb.HashSHA256, pfi.Data, pfi.Data.Size); err != nil {
+			return nil, false, fmt.Errorf("Save: %v", err)
+		}
+	}
+
+	pf := &models.PackageFile{
+		VersionID: pv.ID,
+		BlobID:    pb.ID,
+		Name:      pfi.Filename,
+	}
+	if err := models.InsertPackageFile(ctx, pf); err != nil {
+		return nil, !exists, err
+	}
+	return pf, !exists, nil
+}
+
+// GetFileStreamByPackageNameAndVersion returns the content of the named file of a package version
+func GetFileStreamByPackageNameAndVersion(pi *PackageInfo, filename string) (io.ReadCloser, *models.PackageFile, error) {
+	p, err := models.GetPackageByName(pi.Owner.ID, pi.PackageType, pi.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	pv, err := models.GetPackageVersionByVersion(p.ID, pi.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	return GetFileStreamByPackageVersion(pv, filename)
+}
+
+// GetFileStreamByPackageVersion returns the content of the named file of the package version
+func GetFileStreamByPackageVersion(pv *models.PackageVersion, filename string) (io.ReadCloser, *models.PackageFile, error) {
+	pf, err := models.GetPackageFileByName(pv.ID, filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	pb, err := models.GetPackageBlobByID(pf.BlobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := packages_module.NewContentStore().Get(pb.HashSHA256)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := models.IncrementPackageVersionDownloadCount(pv.ID); err != nil {
+		log.Error("Error incrementing download count of package version %d: %v", pv.ID, err)
+	}
+
+	return s, pf, nil
+}
+
+// Cleanup removes package versions older than olderThan exceeding the numberToKeep most
+// recent versions of every package, expired blob uploads and blobs which are not referenced anymore
+func Cleanup(ctx context.Context, olderThan time.Duration, numberToKeep int) error {
+	cutoff := timeutil.TimeStampNow().AddDuration(-olderThan)
+
+	if olderThan > 0 {
+		packageIDs, err := models.GetAllPackageIDs()
+		if err != nil {
+			return err
+		}
+		for _, packageID := range packageIDs {
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("Before cleanup of package %d", packageID)
+			default:
+			}
+
+			versions, err := models.GetPackageVersionsByPackageID(packageID)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < len(versions)-numberToKeep; i++ {
+				if versions[i].CreatedUnix >= cutoff {
+					continue
+				}
+				if err := models.DeletePackageVersionByID(versions[i].ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := container_service.CleanupExpiredUploads(24 * time.Hour); err != nil {
+		return err
+	}
+
+	blobs, err := models.FindUnreferencedPackageBlobs(timeutil.TimeStampNow().AddDuration(-time.Hour))
+	if err != nil {
+		return err
+	}
+	contentStore := packages_module.NewContentStore()
+	for _, pb := range blobs {
+		if err := contentStore.Delete(pb.HashSHA256); err != nil {
+			log.Error("Error deleting package blob %d from content store: %v", pb.ID, err)
+			continue
+		}
+		if err := models.DeletePackageBlobByID(pb.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"net"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// lookupTXT is replaced by tests
+var lookupTXT = net.LookupTXT
+
+// IsValidCustomDomain returns whether a site may be served at a custom domain.
+// The domain of Gitea, the pages domain and their subdomains are not allowed, so sites cannot share their cookies.
+func IsValidCustomDomain(domain string) bool {
+	if domain == "" || strings.ContainsAny(domain, "/:@ ") {
+		return false
+	}
+	for _, reserved := range []string{strings.ToLower(setting.Domain), setting.Pages.Domain} {
+		if reserved != "" && (domain == reserved || strings.HasSuffix(domain, "."+reserved)) {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyCustomDomain verifies that the repository of a site controls its custom domain by the verification code
+// in the TXT record of its challenge record and returns whether it does.
+// The site is served at the domain and certificates are obtained for it once it is verified.
+func VerifyCustomDomain(pages *models.RepoPages) (bool, error) {
+	if pages.CustomDomain == "" || pages.CustomDomainVerificationCode == "" {
+		return false, nil
+	}
+	if pages.CustomDomainVerified {
+		return true, nil
+	}
+	records, err := lookupTXT(pages.CustomDomainChallengeRecord())
+	if err != nil {
+		if _, ok := err.(*net.DNSError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == pages.CustomDomainVerificationCode {
+			return true, models.VerifyRepoPagesCustomDomain(pages)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"net"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidCustomDomain(t *testing.T) {
+	oldDomain, oldPagesDomain := setting.Domain, setting.Pages.Domain
+	setting.Domain, setting.Pages.Domain = "Gitea.example.com", "pages.example.com"
+	defer func() {
+		setting.Domain, setting.Pages.Domain = oldDomain, oldPagesDomain
+	}()
+
+	assert.True(t, IsValidCustomDomain("docs.example.org"))
+	assert.True(t, IsValidCustomDomain("example.com"))
+	for _, domain := range []string{"", "gitea.example.com", "docs.gitea.example.com", "pages.example.com", "user2.pages.example.com", "docs.example.org:443", "a b"} {
+		assert.False(t, IsValidCustomDomain(domain), domain)
+	}
+}
+
+func TestVerifyCustomDomain(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	oldEnabled := setting.Pages.Enabled
+	setting.Pages.Enabled = true
+	defer func() {
+		setting.Pages.Enabled = oldEnabled
+	}()
+
+	records := map[string][]string{}
+	lookupTXT = func(name string) ([]string, error) {
+		if r, ok := records[name]; ok {
+			return r, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	defer func() {
+		lookupTXT = net.LookupTXT
+	}()
+
+	assert.NoError(t, models.CreateOrUpdateRepoPages(&models.RepoPages{RepoID: 1, Branch: "master", CustomDomain: "docs.example.org"}))
+	pages, err := models.GetRepoPages(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "_gitea-pages-challenge.docs.example.org", pages.CustomDomainChallengeRecord())
+
+	verified, err := VerifyCustomDomain(pages)
+	assert.NoError(t, err)
+	assert.False(t, verified)
+
+	records[pages.CustomDomainChallengeRecord()] = []string{"another-code"}
+	verified, err = VerifyCustomDomain(pages)
+	assert.NoError(t, err)
+	assert.False(t, verified)
+	isPagesHost, err := IsPagesHost("docs.example.org")
+	assert.NoError(t, err)
+	assert.False(t, isPagesHost)
+
+	records[pages.CustomDomainChallengeRecord()] = []string{"another-code", pages.CustomDomainVerificationCode}
+	verified, err = VerifyCustomDomain(pages)
+	assert.NoError(t, err)
+	assert.True(t, verified)
+	isPagesHost, err = IsPagesHost("docs.example.org")
+	assert.NoError(t, err)
+	assert.True(t, isPagesHost)
+}
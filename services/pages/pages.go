@@ -0,0 +1,144 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var publishQueue queue.UniqueQueue
+
+type pagesNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &pagesNotifier{}
+)
+
+// Init creates the queue publishing pages sites and registers the notifier which republishes them on push
+func Init() error {
+	if !setting.Pages.Enabled {
+		return nil
+	}
+
+	publishQueue = queue.CreateUniqueQueue("pages_publish", func(data ...queue.Data) {
+		for _, datum := range data {
+			repoID := datum.(int64)
+			if err := publish(repoID); err != nil {
+				log.Error("Publishing the pages of repository %d failed: %v", repoID, err)
+			}
+		}
+	}, int64(0))
+	if publishQueue == nil {
+		return errors.New("unable to create pages publish queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(publishQueue.Run)
+	notification.RegisterNotifier(&pagesNotifier{})
+	return nil
+}
+
+func (*pagesNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if !opts.IsBranch() || opts.IsDelRef() {
+		return
+	}
+	pages, err := models.GetRepoPages(repo.ID)
+	if err != nil {
+		if err != models.ErrRepoPagesNotExist {
+			log.Error("GetRepoPages [repo: %d]: %v", repo.ID, err)
+		}
+		return
+	}
+	if pages.Branch != opts.BranchName() {
+		return
+	}
+	if err := QueuePublish(pages); err != nil {
+		log.Error("Unable to queue publishing the pages of repository %d: %v", repo.ID, err)
+	}
+}
+
+// QueuePublish marks a pages site as queued and queues publishing it
+func QueuePublish(pages *models.RepoPages) error {
+	if publishQueue == nil {
+		return errors.New("pages are disabled")
+	}
+	pages.Status = models.RepoPagesStatusQueued
+	pages.Error = ""
+	if err := models.UpdateRepoPagesStatus(pages); err != nil {
+		return err
+	}
+	return publishQueue.Push(pages.RepoID)
+}
+
+// publish serves the site of a repository from the current head of its branch
+func publish(repoID int64) error {
+	pages, err := models.GetRepoPages(repoID)
+	if err != nil {
+		if err == models.ErrRepoPagesNotExist {
+			// the site was deleted after it was queued
+			return nil
+		}
+		return err
+	}
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		return err
+	}
+
+	commitID, publishErr := resolveCommit(repo, pages)
+	if publishErr != nil {
+		pages.Status = models.RepoPagesStatusFailed
+		pages.Error = publishErr.Error()
+	} else {
+		pages.Status = models.RepoPagesStatusPublished
+		pages.CommitID = commitID
+	}
+	return models.UpdateRepoPagesStatus(pages)
+}
+
+// resolveCommit returns the head commit of the branch of a site, after checking it contains the directory of the site
+func resolveCommit(repo *models.Repository, pages *models.RepoPages) (string, error) {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(pages.Branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", fmt.Errorf("branch %s does not exist", pages.Branch)
+		}
+		return "", err
+	}
+
+	if dir := strings.Trim(path.Clean("/"+pages.Directory), "/"); dir != "" {
+		entry, err := commit.GetTreeEntryByPath(dir)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				return "", fmt.Errorf("directory %s does not exist in branch %s", dir, pages.Branch)
+			}
+			return "", err
+		}
+		if !entry.IsDir() {
+			return "", fmt.Errorf("%s is not a directory in branch %s", dir, pages.Branch)
+		}
+	}
+	return commit.ID.String(), nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/httpcache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// site is a published site and the path of a request below it
+type site struct {
+	repo     *models.Repository
+	pages    *models.RepoPages
+	filePath string
+}
+
+// splitOwnerHost returns the owner whose sites are served at a host below the pages domain
+func splitOwnerHost(host string) (string, bool) {
+	owner := strings.TrimSuffix(host, "."+setting.Pages.Domain)
+	if owner == host || owner == "" || strings.Contains(owner, ".") {
+		return "", false
+	}
+	return owner, true
+}
+
+// IsPagesHost returns whether sites are served at a host, either as the host of an owner or as a verified custom domain
+func IsPagesHost(host string) (bool, error) {
+	if !setting.Pages.Enabled {
+		return false, nil
+	}
+	host = strings.ToLower(host)
+	if owner, ok := splitOwnerHost(host); ok {
+		_, err := models.GetUserByName(owner)
+		if models.IsErrUserNotExist(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if !setting.Pages.AllowCustomDomains {
+		return false, nil
+	}
+	_, err := models.GetRepoPagesByCustomDomain(host)
+	if err == models.ErrRepoPagesNotExist {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ServeHTTP serves a request to the host of a site and returns whether it was one.
+// Requests to the hosts of owners redirect to the path of a repository with a trailing slash.
+func ServeHTTP(resp http.ResponseWriter, req *http.Request) bool {
+	if !setting.Pages.Enabled {
+		return false
+	}
+	host := strings.ToLower(req.Host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == strings.ToLower(setting.Domain) {
+		return false
+	}
+
+	var (
+		s   *site
+		err error
+	)
+	if owner, ok := splitOwnerHost(host); ok {
+		parts := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)
+		if len(parts) == 1 {
+			if parts[0] == "" {
+				http.NotFound(resp, req)
+			} else {
+				http.Redirect(resp, req, "/"+parts[0]+"/", http.StatusMovedPermanently)
+			}
+			return true
+		}
+		s, err = getOwnerSite(owner, parts[0], parts[1])
+	} else if setting.Pages.AllowCustomDomains {
+		s, err = getCustomDomainSite(host, req.URL.Path)
+		if err == models.ErrRepoPagesNotExist {
+			return false
+		}
+	} else {
+		return false
+	}
+
+	if err == nil {
+		err = serveSite(resp, req, s)
+	}
+	if err != nil {
+		if err == models.ErrRepoPagesNotExist || models.IsErrUserNotExist(err) || models.IsErrRepoNotExist(err) || git.IsErrNotExist(err) {
+			http.NotFound(resp, req)
+			return true
+		}
+		log.Error("Serving pages of %s%s failed: %v", host, req.URL.Path, err)
+		http.Error(resp, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+	return true
+}
+
+func getOwnerSite(ownerName, repoName, filePath string) (*site, error) {
+	repo, err := models.GetRepositoryByOwnerAndName(ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := models.GetRepoPages(repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &site{repo: repo, pages: pages, filePath: filePath}, nil
+}
+
+func getCustomDomainSite(host, filePath string) (*site, error) {
+	pages, err := models.GetRepoPagesByCustomDomain(host)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := models.GetRepositoryByID(pages.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	return &site{repo: repo, pages: pages, filePath: filePath}, nil
+}
+
+// serveSite serves a file of a published site, index.html for directories and 404.html of the site for missing files
+func serveSite(resp http.ResponseWriter, req *http.Request, s *site) error {
+	// sites are served without authentication, so only the sites of public repositories are served
+	if err := s.repo.GetOwner(); err != nil {
+		return err
+	}
+	if s.repo.IsPrivate || s.repo.Owner.Visibility != api.VisibleTypePublic || s.pages.CommitID == "" {
+		return models.ErrRepoPagesNotExist
+	}
+
+	gitRepo, err := git.OpenRepository(s.repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(s.pages.CommitID)
+	if err != nil {
+		return err
+	}
+
+	status := http.StatusOK
+	blob, name, err := getSiteBlob(commit, s.pages.Directory, s.filePath)
+	if git.IsErrNotExist(err) {
+		status = http.StatusNotFound
+		blob, name, err = getSiteBlob(commit, s.pages.Directory, "404.html")
+	}
+	if err != nil {
+		return err
+	}
+
+	if status == http.StatusOK && httpcache.HandleGenericETagCache(req, resp, `"`+blob.ID.String()+`"`) {
+		return nil
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	resp.Header().Set("Content-Type", contentType)
+	resp.Header().Set("Content-Length", fmt.Sprintf("%d", blob.Size()))
+	resp.Header().Set("X-Content-Type-Options", "nosniff")
+	resp.WriteHeader(status)
+	if req.Method == http.MethodHead {
+		return nil
+	}
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return err
+	}
+	defer dataRc.Close()
+	_, err = io.Copy(resp, dataRc)
+	return err
+}
+
+// getSiteBlob returns the blob of a file below the directory of a site and its name, the index.html of directories
+func getSiteBlob(commit *git.Commit, dir, filePath string) (*git.Blob, string, error) {
+	treePath := strings.Trim(path.Join(path.Clean("/"+dir), path.Clean("/"+filePath)), "/")
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		return nil, "", err
+	}
+	if entry.IsDir() {
+		treePath = path.Join(treePath, "index.html")
+		if entry, err = commit.GetTreeEntryByPath(treePath); err != nil {
+			return nil, "", err
+		}
+	}
+	if !entry.IsRegular() && !entry.IsExecutable() {
+		return nil, "", git.ErrNotExist{RelPath: treePath}
+	}
+	return entry.Blob(), path.Base(treePath), nil
+}
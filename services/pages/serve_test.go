@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitOwnerHost(t *testing.T) {
+	oldDomain := setting.Pages.Domain
+	setting.Pages.Domain = "pages.example.com"
+	defer func() {
+		setting.Pages.Domain = oldDomain
+	}()
+
+	owner, ok := splitOwnerHost("user2.pages.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "user2", owner)
+
+	for _, host := range []string{"pages.example.com", ".pages.example.com", "a.user2.pages.example.com", "user2.example.com", "docs.example.org"} {
+		_, ok = splitOwnerHost(host)
+		assert.False(t, ok, host)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package profile
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/repository"
+)
+
+// RepoName is the name of the repository whose README is shown on the profile page of its owner
+const RepoName = ".profile"
+
+type profileNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &profileNotifier{}
+)
+
+// Init registers the notifier which invalidates cached profile READMEs on push
+func Init() {
+	notification.RegisterNotifier(&profileNotifier{})
+}
+
+func (*profileNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if repo.LowerName != RepoName || !opts.IsBranch() || opts.BranchName() != repo.DefaultBranch {
+		return
+	}
+	cache.Remove(readmeCacheKey(repo.ID))
+}
+
+func readmeCacheKey(repoID int64) string {
+	return fmt.Sprintf("profile_readme_%d", repoID)
+}
+
+// GetProfileRepo returns the public repository whose README is shown on the profile page of owner,
+// or nil if it has none
+func GetProfileRepo(owner *models.User) (*models.Repository, error) {
+	repo, err := models.GetRepositoryByName(owner.ID, RepoName)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if repo.IsPrivate || repo.IsEmpty {
+		return nil, nil
+	}
+	return repo, nil
+}
+
+// GetProfileReadme returns the rendered README of the profile repository of owner,
+// or an empty string if there is none. The rendered README is cached until the next push to the default branch.
+func GetProfileReadme(ctx context.Context, owner *models.User) (string, error) {
+	repo, err := GetProfileRepo(owner)
+	if err != nil || repo == nil {
+		return "", err
+	}
+
+	return cache.GetString(readmeCacheKey(repo.ID), func() (string, error) {
+		return renderProfileReadme(ctx, repo)
+	})
+}
+
+func renderProfileReadme(ctx context.Context, repo *models.Repository) (string, error) {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	treePath, err := repofiles.FindReadme(commit)
+	if err != nil || treePath == "" {
+		return "", err
+	}
+	blob, err := commit.GetBlobByPath(treePath)
+	if err != nil {
+		return "", err
+	}
+
+	return repofiles.RenderReadme(ctx, repo, gitRepo, blob, treePath, repo.DefaultBranch)
+}
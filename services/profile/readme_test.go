@@ -0,0 +1,81 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package profile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func renameRepo(t *testing.T, id int64, name string) *models.Repository {
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: id}).(*models.Repository)
+	assert.NoError(t, models.ChangeRepositoryName(doer, repo, name))
+	repo.Name = name
+	assert.NoError(t, models.UpdateRepository(repo, false))
+	return repo
+}
+
+func TestGetProfileReadme(t *testing.T) {
+	db.PrepareTestEnv(t)
+	setting.CacheService.Cache = setting.Cache{Enabled: true, Adapter: "memory", Interval: 60, TTL: time.Minute}
+	assert.NoError(t, cache.NewContext())
+	user2 := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+
+	// users without profile repository have no README
+	html, err := GetProfileReadme(context.Background(), user2)
+	assert.NoError(t, err)
+	assert.Empty(t, html)
+
+	repo := renameRepo(t, 1, RepoName)
+	html, err = GetProfileReadme(context.Background(), user2)
+	assert.NoError(t, err)
+	assert.Contains(t, html, "Description for repo1")
+
+	// the rendered README is cached until the default branch is pushed to
+	assert.NoError(t, cache.GetCache().Put(readmeCacheKey(repo.ID), "cached", 60))
+	notifier := &profileNotifier{}
+	notifier.NotifyPushCommits(user2, repo, &repository.PushUpdateOptions{RefFullName: git.BranchPrefix + "develop"}, nil)
+	html, err = GetProfileReadme(context.Background(), user2)
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", html)
+
+	notifier.NotifyPushCommits(user2, repo, &repository.PushUpdateOptions{RefFullName: git.BranchPrefix + repo.DefaultBranch}, nil)
+	html, err = GetProfileReadme(context.Background(), user2)
+	assert.NoError(t, err)
+	assert.Contains(t, html, "Description for repo1")
+
+	// the README of a private profile repository is not shown, even if it is cached
+	repo.IsPrivate = true
+	assert.NoError(t, models.UpdateRepositoryCols(repo, "is_private"))
+	repo, err = GetProfileRepo(user2)
+	assert.NoError(t, err)
+	assert.Nil(t, repo)
+	html, err = GetProfileReadme(context.Background(), user2)
+	assert.NoError(t, err)
+	assert.Empty(t, html)
+
+	// repo2 has files, but no README
+	renameRepo(t, 1, "repo1")
+	repo = renameRepo(t, 2, RepoName)
+	repo.IsPrivate = false
+	assert.NoError(t, models.UpdateRepositoryCols(repo, "is_private"))
+	repo, err = GetProfileRepo(user2)
+	assert.NoError(t, err)
+	assert.NotNil(t, repo)
+	html, err = GetProfileReadme(context.Background(), user2)
+	assert.NoError(t, err)
+	assert.Empty(t, html)
+}
@@ -96,23 +96,77 @@ func IsPullCommitStatusPass(pr *models.PullRequest) (bool, error) {
 
 // GetPullRequestCommitStatusState returns pull request merged commit status state
 func GetPullRequestCommitStatusState(pr *models.PullRequest) (structs.CommitStatusState, error) {
+	commitStatuses, err := getPullRequestHeadCommitStatuses(pr)
+	if err != nil {
+		return "", err
+	}
+
+	freshStatuses, staleStatuses := SplitStaleCommitStatuses(pr.ProtectedBranch, commitStatuses)
+	// statuses which are all too old must be reported again before merging
+	if len(pr.ProtectedBranch.StatusCheckContexts) == 0 && len(freshStatuses) == 0 && len(staleStatuses) > 0 {
+		return structs.CommitStatusPending, nil
+	}
+	return MergeRequiredContextsCommitStatus(freshStatuses, pr.ProtectedBranch.StatusCheckContexts), nil
+}
+
+// GetPullRequestStaleStatusContexts returns the contexts of the head commit statuses of a pull request
+// which are older than the maximum status check age of its protected branch
+func GetPullRequestStaleStatusContexts(pr *models.PullRequest) ([]string, error) {
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return nil, errors.Wrap(err, "LoadProtectedBranch")
+	}
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.EnableStatusCheck || pr.ProtectedBranch.StatusCheckMaxAge == 0 {
+		return nil, nil
+	}
+
+	commitStatuses, err := getPullRequestHeadCommitStatuses(pr)
+	if err != nil {
+		return nil, err
+	}
+	_, staleStatuses := SplitStaleCommitStatuses(pr.ProtectedBranch, commitStatuses)
+
+	contexts := make([]string, 0, len(staleStatuses))
+	for _, status := range staleStatuses {
+		contexts = append(contexts, status.Context)
+	}
+	return contexts, nil
+}
+
+// SplitStaleCommitStatuses splits commit statuses into the ones which are recent enough for a protected branch and the stale ones
+func SplitStaleCommitStatuses(protectBranch *models.ProtectedBranch, commitStatuses []*models.CommitStatus) (fresh, stale []*models.CommitStatus) {
+	if protectBranch == nil || protectBranch.StatusCheckMaxAge == 0 {
+		return commitStatuses, nil
+	}
+	fresh = make([]*models.CommitStatus, 0, len(commitStatuses))
+	for _, status := range commitStatuses {
+		if protectBranch.IsStatusCheckStale(status) {
+			stale = append(stale, status)
+		} else {
+			fresh = append(fresh, status)
+		}
+	}
+	return fresh, stale
+}
+
+// getPullRequestHeadCommitStatuses returns the latest commit statuses of the head commit of a pull request
+func getPullRequestHeadCommitStatuses(pr *models.PullRequest) ([]*models.CommitStatus, error) {
 	// Ensure HeadRepo is loaded
 	if err := pr.LoadHeadRepo(); err != nil {
-		return "", errors.Wrap(err, "LoadHeadRepo")
+		return nil, errors.Wrap(err, "LoadHeadRepo")
 	}
 
 	// check if all required status checks are successful
 	headGitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
 	if err != nil {
-		return "", errors.Wrap(err, "OpenRepository")
+		return nil, errors.Wrap(err, "OpenRepository")
 	}
 	defer headGitRepo.Close()
 
 	if pr.Flow == models.PullRequestFlowGithub && !headGitRepo.IsBranchExist(pr.HeadBranch) {
-		return "", errors.New("Head branch does not exist, can not merge")
+		return nil, errors.New("Head branch does not exist, can not merge")
 	}
 	if pr.Flow == models.PullRequestFlowAGit && !git.IsReferenceExist(headGitRepo.Path, pr.GetGitRefName()) {
-		return "", errors.New("Head branch does not exist, can not merge")
+		return nil, errors.New("Head branch does not exist, can not merge")
 	}
 
 	var sha string
@@ -122,17 +176,16 @@ func GetPullRequestCommitStatusState(pr *models.PullRequest) (structs.CommitStat
 		sha, err = headGitRepo.GetRefCommitID(pr.GetGitRefName())
 	}
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if err := pr.LoadBaseRepo(); err != nil {
-		return "", errors.Wrap(err, "LoadBaseRepo")
+		return nil, errors.Wrap(err, "LoadBaseRepo")
 	}
 
 	commitStatuses, err := models.GetLatestCommitStatus(pr.BaseRepo.ID, sha, models.ListOptions{})
 	if err != nil {
-		return "", errors.Wrap(err, "GetLatestCommitStatus")
+		return nil, errors.Wrap(err, "GetLatestCommitStatus")
 	}
-
-	return MergeRequiredContextsCommitStatus(commitStatuses, pr.ProtectedBranch.StatusCheckContexts), nil
+	return commitStatuses, nil
 }
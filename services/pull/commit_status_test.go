@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStaleCommitStatuses(t *testing.T) {
+	statuses := []*models.CommitStatus{
+		{Context: "ci/build", State: structs.CommitStatusSuccess, UpdatedUnix: timeutil.TimeStamp(time.Now().Add(-10 * time.Minute).Unix())},
+		{Context: "ci/lint", State: structs.CommitStatusSuccess, UpdatedUnix: timeutil.TimeStamp(time.Now().Add(-2 * time.Hour).Unix())},
+	}
+
+	fresh, stale := SplitStaleCommitStatuses(&models.ProtectedBranch{}, statuses)
+	assert.Len(t, fresh, 2)
+	assert.Empty(t, stale)
+
+	fresh, stale = SplitStaleCommitStatuses(&models.ProtectedBranch{StatusCheckMaxAge: 60}, statuses)
+	assert.Len(t, fresh, 1)
+	assert.Equal(t, "ci/build", fresh[0].Context)
+	assert.Len(t, stale, 1)
+	assert.Equal(t, "ci/lint", stale[0].Context)
+
+	// a stale required context counts as not reported yet
+	assert.Equal(t, structs.CommitStatusPending, MergeRequiredContextsCommitStatus(fresh, []string{"ci/build", "ci/lint"}))
+	assert.Equal(t, structs.CommitStatusSuccess, MergeRequiredContextsCommitStatus(fresh, []string{"ci/build"}))
+}
@@ -50,6 +50,14 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 		return models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
 	}
 
+	// Fall back to the default message of the repository if none is given
+	if strings.TrimSpace(message) == "" {
+		if message, err = GetDefaultMergeMessage(pr, mergeStyle); err != nil {
+			log.Error("GetDefaultMergeMessage: %v", err)
+			return err
+		}
+	}
+
 	defer func() {
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false, "", "")
 	}()
@@ -574,60 +582,67 @@ func IsUserAllowedToMerge(pr *models.PullRequest, p models.Permission, user *mod
 
 // CheckPRReadyToMerge checks whether the PR is ready to be merged (reviews and status checks)
 func CheckPRReadyToMerge(pr *models.PullRequest, skipProtectedFilesCheck bool) (err error) {
-	if err = pr.LoadBaseRepo(); err != nil {
-		return fmt.Errorf("LoadBaseRepo: %v", err)
+	reasons, err := GetPRMergeBlockers(pr, skipProtectedFilesCheck)
+	if err != nil {
+		return err
+	}
+	if len(reasons) > 0 {
+		return models.ErrNotAllowedToMerge{
+			Reason: reasons[0],
+		}
+	}
+	return nil
+}
+
+// GetPRMergeBlockers returns all the reasons why the protected branch of the PR does not allow it to be merged yet
+func GetPRMergeBlockers(pr *models.PullRequest, skipProtectedFilesCheck bool) ([]string, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, fmt.Errorf("LoadBaseRepo: %v", err)
 	}
 
-	if err = pr.LoadProtectedBranch(); err != nil {
-		return fmt.Errorf("LoadProtectedBranch: %v", err)
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return nil, fmt.Errorf("LoadProtectedBranch: %v", err)
 	}
 	if pr.ProtectedBranch == nil {
-		return nil
+		return nil, nil
 	}
 
+	var reasons []string
 	isPass, err := IsPullCommitStatusPass(pr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !isPass {
-		return models.ErrNotAllowedToMerge{
-			Reason: "Not all required status checks successful",
+		staleContexts, err := GetPullRequestStaleStatusContexts(pr)
+		if err != nil {
+			return nil, err
+		}
+		if len(staleContexts) > 0 {
+			reasons = append(reasons, fmt.Sprintf("Status checks are older than %d minutes: %s", pr.ProtectedBranch.StatusCheckMaxAge, strings.Join(staleContexts, ", ")))
+		} else {
+			reasons = append(reasons, "Not all required status checks successful")
 		}
 	}
 
 	if !pr.ProtectedBranch.HasEnoughApprovals(pr) {
-		return models.ErrNotAllowedToMerge{
-			Reason: "Does not have enough approvals",
-		}
+		reasons = append(reasons, "Does not have enough approvals")
 	}
 	if pr.ProtectedBranch.MergeBlockedByRejectedReview(pr) {
-		return models.ErrNotAllowedToMerge{
-			Reason: "There are requested changes",
-		}
+		reasons = append(reasons, "There are requested changes")
 	}
 	if pr.ProtectedBranch.MergeBlockedByOfficialReviewRequests(pr) {
-		return models.ErrNotAllowedToMerge{
-			Reason: "There are official review requests",
-		}
+		reasons = append(reasons, "There are official review requests")
 	}
 
 	if pr.ProtectedBranch.MergeBlockedByOutdatedBranch(pr) {
-		return models.ErrNotAllowedToMerge{
-			Reason: "The head branch is behind the base branch",
-		}
-	}
-
-	if skipProtectedFilesCheck {
-		return nil
+		reasons = append(reasons, "The head branch is behind the base branch")
 	}
 
-	if pr.ProtectedBranch.MergeBlockedByProtectedFiles(pr) {
-		return models.ErrNotAllowedToMerge{
-			Reason: "Changed protected files",
-		}
+	if !skipProtectedFilesCheck && pr.ProtectedBranch.MergeBlockedByProtectedFiles(pr) {
+		reasons = append(reasons, "Changed protected files")
 	}
 
-	return nil
+	return reasons, nil
 }
 
 // MergedManually mark pr as merged manually
@@ -0,0 +1,205 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/references"
+)
+
+var mergeMessageVariablePattern = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// GetDefaultMergeMessage returns the default commit message of merging a pull request with a merge style,
+// expanded from the template of the repository if one is configured
+func GetDefaultMergeMessage(pr *models.PullRequest, mergeStyle models.MergeStyle) (string, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return "", err
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return "", err
+	}
+
+	var tmpl string
+	if prUnit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests); err == nil {
+		prConfig := prUnit.PullRequestsConfig()
+		switch mergeStyle {
+		case models.MergeStyleMerge, models.MergeStyleRebaseMerge:
+			tmpl = prConfig.DefaultMergeMessageTemplate
+		case models.MergeStyleSquash:
+			tmpl = prConfig.DefaultSquashMessageTemplate
+		}
+	} else if !models.IsErrUnitTypeNotExist(err) {
+		return "", err
+	}
+
+	if tmpl == "" {
+		switch mergeStyle {
+		case models.MergeStyleMerge, models.MergeStyleRebaseMerge:
+			return pr.GetDefaultMergeMessage(), nil
+		case models.MergeStyleSquash:
+			return pr.GetDefaultSquashMessage(), nil
+		}
+		return "", nil
+	}
+
+	return expandMergeMessageTemplate(tmpl, func(name string) (string, bool, error) {
+		return getMergeMessageVariable(pr, name)
+	})
+}
+
+// SplitCommitMessage splits a commit message into its title and its body, a non empty body ends with a newline
+func SplitCommitMessage(message string) (title, body string) {
+	parts := strings.SplitN(message, "\n", 2)
+	title = strings.TrimSpace(parts[0])
+	if len(parts) > 1 {
+		body = strings.TrimSpace(parts[1])
+		if body != "" {
+			body += "\n"
+		}
+	}
+	return title, body
+}
+
+// expandMergeMessageTemplate replaces the ${Name} variables of a template, unknown variables are kept as they are
+func expandMergeMessageTemplate(tmpl string, getVariable func(name string) (string, bool, error)) (string, error) {
+	var expandErr error
+	message := mergeMessageVariablePattern.ReplaceAllStringFunc(tmpl, func(variable string) string {
+		name := variable[2 : len(variable)-1]
+		value, ok, err := getVariable(name)
+		if err != nil {
+			if expandErr == nil {
+				expandErr = err
+			}
+			return ""
+		}
+		if !ok {
+			return variable
+		}
+		return value
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return strings.TrimSpace(message), nil
+}
+
+// getMergeMessageVariable returns the value of a variable of a merge message template for a pull request
+func getMergeMessageVariable(pr *models.PullRequest, name string) (string, bool, error) {
+	switch name {
+	case "PullRequestTitle":
+		return pr.Issue.Title, true, nil
+	case "PullRequestIndex":
+		return strconv.FormatInt(pr.Issue.Index, 10), true, nil
+	case "PullRequestReference":
+		if pr.BaseRepo.UnitEnabled(models.UnitTypeExternalTracker) {
+			return fmt.Sprintf("!%d", pr.Issue.Index), true, nil
+		}
+		return fmt.Sprintf("#%d", pr.Issue.Index), true, nil
+	case "PullRequestDescription":
+		return strings.TrimSpace(pr.Issue.Content), true, nil
+	case "PullRequestPosterName":
+		if err := pr.Issue.LoadPoster(); err != nil {
+			return "", false, err
+		}
+		return pr.Issue.Poster.Name, true, nil
+	case "HeadBranch":
+		return pr.HeadBranch, true, nil
+	case "BaseBranch":
+		return pr.BaseBranch, true, nil
+	case "CoAuthors":
+		coAuthors, err := getPullRequestCoAuthors(pr)
+		if err != nil {
+			return "", false, err
+		}
+		trailers := make([]string, 0, len(coAuthors))
+		for _, coAuthor := range coAuthors {
+			trailers = append(trailers, "Co-authored-by: "+coAuthor)
+		}
+		return strings.Join(trailers, "\n"), true, nil
+	case "ClosingIssues":
+		issues, err := getPullRequestClosingIssues(pr)
+		if err != nil {
+			return "", false, err
+		}
+		return strings.Join(issues, ", "), true, nil
+	}
+	return "", false, nil
+}
+
+// getPullRequestCoAuthors returns the authors of the commits of a pull request other than its poster
+func getPullRequestCoAuthors(pr *models.PullRequest) ([]string, error) {
+	if err := pr.Issue.LoadPoster(); err != nil {
+		return nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+	headCommit, err := gitRepo.GetCommit(headCommitID)
+	if err != nil {
+		return nil, err
+	}
+	mergeBase, err := gitRepo.GetCommit(pr.MergeBase)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := gitRepo.CommitsBetween(headCommit, mergeBase)
+	if err != nil {
+		return nil, err
+	}
+
+	posterSig := pr.Issue.Poster.NewGitSig().String()
+	authorsMap := map[string]bool{}
+	authors := make([]string, 0, len(commits))
+	// commits list is in reverse chronological order
+	for i := len(commits) - 1; i >= 0; i-- {
+		authorString := commits[i].Author.String()
+		if !authorsMap[authorString] && authorString != posterSig {
+			authors = append(authors, authorString)
+			authorsMap[authorString] = true
+		}
+	}
+	return authors, nil
+}
+
+// getPullRequestClosingIssues returns the references of the issues a pull request closes
+func getPullRequestClosingIssues(pr *models.PullRequest) ([]string, error) {
+	refs, err := pr.ResolveCrossReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref.RefAction != references.XRefActionCloses {
+			continue
+		}
+		if err := ref.LoadIssue(); err != nil {
+			return nil, err
+		}
+		if ref.Issue.RepoID == pr.BaseRepoID {
+			issues = append(issues, fmt.Sprintf("#%d", ref.Issue.Index))
+			continue
+		}
+		if err := ref.Issue.LoadRepo(); err != nil {
+			return nil, err
+		}
+		issues = append(issues, fmt.Sprintf("%s#%d", ref.Issue.Repo.FullName(), ref.Issue.Index))
+	}
+	return issues, nil
+}
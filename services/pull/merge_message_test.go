@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"errors"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandMergeMessageTemplate(t *testing.T) {
+	variables := map[string]string{
+		"PullRequestTitle": "Fix the bug",
+		"PullRequestIndex": "2",
+	}
+	getVariable := func(name string) (string, bool, error) {
+		value, ok := variables[name]
+		return value, ok, nil
+	}
+
+	message, err := expandMergeMessageTemplate("${PullRequestTitle} (#${PullRequestIndex})\n\n${Unknown} $PullRequestTitle\n", getVariable)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fix the bug (#2)\n\n${Unknown} $PullRequestTitle", message)
+
+	_, err = expandMergeMessageTemplate("${PullRequestTitle}", func(string) (string, bool, error) {
+		return "", false, errors.New("lookup failed")
+	})
+	assert.EqualError(t, err, "lookup failed")
+}
+
+func TestSplitCommitMessage(t *testing.T) {
+	title, body := SplitCommitMessage("Title\n\nBody\n")
+	assert.Equal(t, "Title", title)
+	assert.Equal(t, "Body\n", body)
+
+	title, body = SplitCommitMessage("Title only")
+	assert.Equal(t, "Title only", title)
+	assert.Empty(t, body)
+}
+
+func TestGetDefaultMergeMessage(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	pr := db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+
+	// without templates the built-in messages are used
+	message, err := GetDefaultMergeMessage(pr, models.MergeStyleMerge)
+	assert.NoError(t, err)
+	assert.Equal(t, pr.GetDefaultMergeMessage(), message)
+	message, err = GetDefaultMergeMessage(pr, models.MergeStyleSquash)
+	assert.NoError(t, err)
+	assert.Equal(t, pr.GetDefaultSquashMessage(), message)
+
+	assert.NoError(t, pr.LoadBaseRepo())
+	prUnit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests)
+	assert.NoError(t, err)
+	prConfig := prUnit.PullRequestsConfig()
+	prConfig.DefaultSquashMessageTemplate = "${PullRequestTitle} (${PullRequestReference})\n\nFrom ${HeadBranch} into ${BaseBranch}"
+	assert.NoError(t, models.UpdateRepositoryUnits(pr.BaseRepo, []models.RepoUnit{{
+		RepoID: pr.BaseRepo.ID,
+		Type:   models.UnitTypePullRequests,
+		Config: prConfig,
+	}}, nil))
+	pr.BaseRepo = nil
+
+	message, err = GetDefaultMergeMessage(pr, models.MergeStyleSquash)
+	assert.NoError(t, err)
+	assert.Equal(t, "issue3 (#3)\n\nFrom branch2 into master", message)
+	message, err = GetDefaultMergeMessage(pr, models.MergeStyleMerge)
+	assert.NoError(t, err)
+	assert.Equal(t, pr.GetDefaultMergeMessage(), message)
+	message, err = GetDefaultMergeMessage(pr, models.MergeStyleRebase)
+	assert.NoError(t, err)
+	assert.Empty(t, message)
+}
@@ -26,6 +26,10 @@ import (
 
 // NewPullRequest creates new pull request with labels for repository.
 func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int64, uuids []string, pr *models.PullRequest, assigneeIDs []int64) error {
+	if err := models.CheckRepoInteractionLimit(repo, pull.Poster); err != nil {
+		return err
+	}
+
 	if err := TestPatch(pr); err != nil {
 		return err
 	}
@@ -0,0 +1,151 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+)
+
+const (
+	// suggestedReviewersMaxFiles is the maximum number of changed files whose history is inspected
+	suggestedReviewersMaxFiles = 50
+	// suggestedReviewersCommitsPerFile is the number of latest commits inspected for each changed file
+	suggestedReviewersCommitsPerFile = 10
+)
+
+// GetSuggestedReviewers returns up to limit users who recently authored commits changing the files of a pull request,
+// ordered by the number of those commits. The poster and users who cannot be requested to review are excluded.
+// The candidates are cached per head commit of the pull request.
+func GetSuggestedReviewers(pr *models.PullRequest, limit int) ([]*models.User, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := cache.GetString(fmt.Sprintf("pull_suggested_reviewers_%d_%s", pr.ID, headCommitID), func() (string, error) {
+		candidates, err := getReviewerCandidates(gitRepo, pr, headCommitID)
+		if err != nil {
+			return "", err
+		}
+		ids := make([]string, 0, len(candidates))
+		for _, id := range candidates {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		return strings.Join(ids, ","), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached == "" {
+		return []*models.User{}, nil
+	}
+
+	// availability is checked on every call, as it may change while the head commit does not
+	reviewers, err := pr.BaseRepo.GetReviewers(0, pr.Issue.PosterID)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[int64]*models.User, len(reviewers))
+	for _, reviewer := range reviewers {
+		if reviewer.IsActive && !reviewer.ProhibitLogin {
+			available[reviewer.ID] = reviewer
+		}
+	}
+
+	users := make([]*models.User, 0, limit)
+	for _, idStr := range strings.Split(cached, ",") {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if user, ok := available[id]; ok {
+			users = append(users, user)
+			if len(users) == limit {
+				break
+			}
+		}
+	}
+	return users, nil
+}
+
+// getReviewerCandidates returns the IDs of the users who authored the latest commits changing the files of a pull request
+// before its merge base, ordered by the number of those commits
+func getReviewerCandidates(gitRepo *git.Repository, pr *models.PullRequest, headCommitID string) ([]int64, error) {
+	mergeBase := pr.MergeBase
+	if mergeBase == "" {
+		mergeBase = pr.BaseBranch
+	}
+
+	files, err := gitRepo.GetFilesChangedBetween(mergeBase, headCommitID)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > suggestedReviewersMaxFiles {
+		files = files[:suggestedReviewersMaxFiles]
+	}
+
+	emailCounts := make(map[string]int)
+	for _, file := range files {
+		emails, err := gitRepo.GetFileCommitAuthorEmails(mergeBase, file, suggestedReviewersCommitsPerFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, email := range emails {
+			emailCounts[strings.ToLower(email)]++
+		}
+	}
+
+	userCounts := make(map[int64]int)
+	for email, count := range emailCounts {
+		user, err := models.GetUserByEmail(email)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if user.ID == pr.Issue.PosterID {
+			continue
+		}
+		userCounts[user.ID] += count
+	}
+
+	return rankReviewerCandidates(userCounts), nil
+}
+
+// rankReviewerCandidates orders user IDs by their count descending, and by ID for equal counts
+func rankReviewerCandidates(userCounts map[int64]int) []int64 {
+	ids := make([]int64, 0, len(userCounts))
+	for id := range userCounts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if userCounts[ids[i]] != userCounts[ids[j]] {
+			return userCounts[ids[i]] > userCounts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
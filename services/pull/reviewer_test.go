@@ -0,0 +1,21 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankReviewerCandidates(t *testing.T) {
+	assert.Empty(t, rankReviewerCandidates(map[int64]int{}))
+	assert.Equal(t, []int64{4, 2, 5, 1}, rankReviewerCandidates(map[int64]int{
+		1: 1,
+		2: 3,
+		4: 7,
+		5: 3,
+	}))
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package push
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// gatewayMessage is the request body posted to the push gateway
+type gatewayMessage struct {
+	Token    string `json:"token"`
+	Platform string `json:"platform"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	URL      string `json:"url"`
+	Tag      string `json:"tag"`
+}
+
+// gatewaySender delivers messages to mobile devices through a push gateway,
+// which forwards them to the push service of the device platform
+type gatewaySender struct {
+	client *http.Client
+}
+
+func newGatewaySender(client *http.Client) *gatewaySender {
+	return &gatewaySender{client: client}
+}
+
+// Send implements Sender
+func (s *gatewaySender) Send(device *models.PushDevice, msg *Message) error {
+	body, err := json.Marshal(&gatewayMessage{
+		Token:    device.Token,
+		Platform: device.Platform,
+		Title:    msg.Title,
+		Body:     msg.Body,
+		URL:      msg.URL,
+		Tag:      msg.Tag,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", setting.Push.GatewayURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if setting.Push.GatewayToken != "" {
+		req.Header.Set("Authorization", "Bearer "+setting.Push.GatewayToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrDeviceGone
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("push gateway responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
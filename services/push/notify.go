@@ -0,0 +1,192 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package push
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// NotifyParticipants pushes an issue event to the users watching or participating in the issue and to the
+// mentioned users, in the same way the issue event is mailed to them
+func NotifyParticipants(issue *models.Issue, doer *models.User, opType models.ActionType, comment *models.Comment, mentions []*models.User) {
+	if pushQueue == nil {
+		return
+	}
+	if err := notifyParticipants(issue, doer, opType, comment, mentions); err != nil {
+		log.Error("push.NotifyParticipants(%d): %v", issue.ID, err)
+	}
+}
+
+func notifyParticipants(issue *models.Issue, doer *models.User, opType models.ActionType, comment *models.Comment, mentions []*models.User) error {
+	if err := issue.LoadRepo(); err != nil {
+		return fmt.Errorf("LoadRepo(): %v", err)
+	}
+	if err := issue.LoadPullRequest(); err != nil {
+		return fmt.Errorf("LoadPullRequest(): %v", err)
+	}
+
+	event := models.SubscriptionEventFromActionType(opType)
+	if comment != nil && comment.Type == models.CommentTypePullPush {
+		event = models.SubscriptionEventPush
+	}
+
+	unfiltered := []int64{issue.PosterID}
+	ids, err := models.GetAssigneeIDsByIssue(issue.ID)
+	if err != nil {
+		return fmt.Errorf("GetAssigneeIDsByIssue(): %v", err)
+	}
+	unfiltered = append(unfiltered, ids...)
+	ids, err = models.GetParticipantsIDsByIssueID(issue.ID)
+	if err != nil {
+		return fmt.Errorf("GetParticipantsIDsByIssueID(): %v", err)
+	}
+	unfiltered = append(unfiltered, ids...)
+	ids, err = models.GetIssueWatchersIDs(issue.ID, true)
+	if err != nil {
+		return fmt.Errorf("GetIssueWatchersIDs(): %v", err)
+	}
+	unfiltered = append(unfiltered, ids...)
+	if !(issue.IsPull && issue.PullRequest.IsWorkInProgress() && opType != models.ActionCreatePullRequest) {
+		ids, err = models.GetRepoWatchersIDs(issue.RepoID)
+		if err != nil {
+			return fmt.Errorf("GetRepoWatchersIDs(): %v", err)
+		}
+		unfiltered = append(unfiltered, ids...)
+	}
+	unfiltered, err = models.FilterIssueSubscribers(issue, unfiltered, event)
+	if err != nil {
+		return fmt.Errorf("FilterIssueSubscribers(): %v", err)
+	}
+
+	mentionIDs := make([]int64, 0, len(mentions))
+	for _, mention := range mentions {
+		mentionIDs = append(mentionIDs, mention.ID)
+	}
+	mentionIDs, err = models.FilterIssueSubscribers(issue, mentionIDs, models.SubscriptionEventMention)
+	if err != nil {
+		return fmt.Errorf("FilterIssueSubscribers(): %v", err)
+	}
+
+	visited := map[int64]bool{doer.ID: true}
+	if err := notifyUserIDs(issue, doer, mentionIDs, models.SubscriptionEventMention, "notification.push.mentioned", visited); err != nil {
+		return err
+	}
+
+	// explicit unwatchers don't receive anything but mentions
+	ids, err = models.GetIssueWatchersIDs(issue.ID, false)
+	if err != nil {
+		return fmt.Errorf("GetIssueWatchersIDs(): %v", err)
+	}
+	for _, id := range ids {
+		visited[id] = true
+	}
+
+	return notifyUserIDs(issue, doer, unfiltered, event, actionBodyKey(opType, event), visited)
+}
+
+// NotifyUsers pushes an issue event addressed to some users directly, like an assignment or a review request
+func NotifyUsers(issue *models.Issue, doer *models.User, bodyKey string, users []*models.User) {
+	if pushQueue == nil {
+		return
+	}
+	if err := issue.LoadRepo(); err != nil {
+		log.Error("LoadRepo: %v", err)
+		return
+	}
+
+	ids := make([]int64, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	if err := notifyUserIDs(issue, doer, ids, models.SubscriptionEventMention, bodyKey, map[int64]bool{doer.ID: true}); err != nil {
+		log.Error("push.NotifyUsers(%d): %v", issue.ID, err)
+	}
+}
+
+// notifyUserIDs pushes the event to the devices of the users who weren't visited yet,
+// may see the issue and receive the event through the push channel
+func notifyUserIDs(issue *models.Issue, doer *models.User, userIDs []int64, event models.SubscriptionEvents, bodyKey string, visited map[int64]bool) error {
+	ids := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !visited[id] {
+			visited[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	ids, err := models.FilterNotificationChannelReceivers(ids, models.NotificationChannelPush, event)
+	if err != nil {
+		return fmt.Errorf("FilterNotificationChannelReceivers(): %v", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	devices, err := models.GetPushDevicesByUserIDs(ids, SupportedDeviceTypes())
+	if err != nil {
+		return fmt.Errorf("GetPushDevicesByUserIDs(): %v", err)
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+	devicesByUser := make(map[int64][]*models.PushDevice, len(ids))
+	for _, device := range devices {
+		devicesByUser[device.UserID] = append(devicesByUser[device.UserID], device)
+	}
+	ids = ids[:0]
+	for id := range devicesByUser {
+		ids = append(ids, id)
+	}
+
+	users, err := models.GetUsersByIDs(ids)
+	if err != nil {
+		return fmt.Errorf("GetUsersByIDs(): %v", err)
+	}
+
+	checkUnit := models.UnitTypeIssues
+	if issue.IsPull {
+		checkUnit = models.UnitTypePullRequests
+	}
+	for _, user := range users {
+		if !user.IsActive || user.ProhibitLogin || !issue.Repo.CheckUnitUser(user, checkUnit) {
+			continue
+		}
+
+		locale := translation.NewLocale(user.Language)
+		SendAsync(devicesByUser[user.ID], &Message{
+			Title: fmt.Sprintf("[%s] %s (#%d)", issue.Repo.FullName(), issue.Title, issue.Index),
+			Body:  locale.Tr(bodyKey, doer.DisplayName()),
+			URL:   issue.HTMLURL(),
+			Tag:   fmt.Sprintf("%s#%d", issue.Repo.FullName(), issue.Index),
+		})
+	}
+	return nil
+}
+
+// actionBodyKey returns the locale key of the message body describing an issue event
+func actionBodyKey(opType models.ActionType, event models.SubscriptionEvents) string {
+	switch opType {
+	case models.ActionCreateIssue:
+		return "notification.push.issue_opened"
+	case models.ActionCreatePullRequest:
+		return "notification.push.pull_opened"
+	case models.ActionPullRequestReadyForReview:
+		return "notification.push.pull_ready_for_review"
+	case models.ActionCloseIssue, models.ActionClosePullRequest:
+		return "notification.push.closed"
+	case models.ActionReopenIssue, models.ActionReopenPullRequest:
+		return "notification.push.reopened"
+	case models.ActionMergePullRequest:
+		return "notification.push.merged"
+	}
+	if event == models.SubscriptionEventPush {
+		return "notification.push.pushed"
+	}
+	return "notification.push.commented"
+}
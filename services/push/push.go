@@ -0,0 +1,140 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package push
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ErrDeviceGone indicates that a device does not accept push messages anymore and should be removed
+var ErrDeviceGone = errors.New("Push device is gone")
+
+// Message is a push notification shown on a device
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	// Tag groups the messages of the same issue, so a device may replace an older message by a newer one
+	Tag string `json:"tag"`
+}
+
+// Sender delivers push messages to devices of one type
+type Sender interface {
+	Send(device *models.PushDevice, msg *Message) error
+}
+
+// Task is a push message waiting for delivery to a device
+type Task struct {
+	DeviceID int64
+	UserID   int64
+	Message  Message
+}
+
+var (
+	pushQueue queue.Queue
+	senders   = make(map[models.PushDeviceType]Sender)
+)
+
+// RegisterSender sets the sender of the devices of a type
+func RegisterSender(deviceType models.PushDeviceType, sender Sender) {
+	senders[deviceType] = sender
+}
+
+// SupportedDeviceTypes returns the types of the devices push messages can be delivered to
+func SupportedDeviceTypes() []models.PushDeviceType {
+	types := make([]models.PushDeviceType, 0, len(senders))
+	for t := range senders {
+		types = append(types, t)
+	}
+	return types
+}
+
+// IsSupportedDeviceType returns true if push messages can be delivered to devices of the type
+func IsSupportedDeviceType(deviceType models.PushDeviceType) bool {
+	_, ok := senders[deviceType]
+	return ok
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: time.Duration(setting.Push.DeliverTimeout) * time.Second,
+		Transport: &http.Transport{
+			Proxy: proxy.Proxy(),
+		},
+	}
+}
+
+// NewContext registers the senders and starts the push queue
+func NewContext() {
+	if !setting.Push.Enabled || pushQueue != nil {
+		return
+	}
+
+	sender, err := newWebPushSender(newHTTPClient())
+	if err != nil {
+		log.Error("Unable to create the Web Push sender: %v", err)
+	} else {
+		RegisterSender(models.PushDeviceTypeWeb, sender)
+	}
+	if setting.Push.GatewayURL != "" {
+		RegisterSender(models.PushDeviceTypeGateway, newGatewaySender(newHTTPClient()))
+	}
+
+	pushQueue = queue.CreateQueue("push", func(data ...queue.Data) {
+		for _, datum := range data {
+			deliver(datum.(*Task))
+		}
+	}, &Task{})
+
+	go graceful.GetManager().RunWithShutdownFns(pushQueue.Run)
+}
+
+// SendAsync delivers a message to the devices asynchronously
+func SendAsync(devices []*models.PushDevice, msg *Message) {
+	if pushQueue == nil {
+		log.Error("Push: SendAsync is being invoked but the push service hasn't been initialized")
+		return
+	}
+
+	go func() {
+		for _, device := range devices {
+			_ = pushQueue.Push(&Task{DeviceID: device.ID, UserID: device.UserID, Message: *msg})
+		}
+	}()
+}
+
+func deliver(task *Task) {
+	device, err := models.GetPushDeviceByID(task.UserID, task.DeviceID)
+	if err != nil {
+		if err != models.ErrPushDeviceNotExist {
+			log.Error("GetPushDeviceByID: %v", err)
+		}
+		return
+	}
+	sender, ok := senders[device.Type]
+	if !ok {
+		return
+	}
+
+	if err := sender.Send(device, &task.Message); err != nil {
+		if err == ErrDeviceGone {
+			log.Trace("Push device %d of user %d is gone, removing it", device.ID, device.UserID)
+			if err := models.DeletePushDevice(device.UserID, device.ID); err != nil && err != models.ErrPushDeviceNotExist {
+				log.Error("DeletePushDevice: %v", err)
+			}
+			return
+		}
+		log.Error("Failed to deliver push message to device %d of user %d: %v", device.ID, device.UserID, err)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package push
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// webPushRecordSize is the record size announced in the aes128gcm content coding header
+	webPushRecordSize = 4096
+	// webPushTTL is how long, in seconds, the push service keeps undelivered messages
+	webPushTTL = 24 * 60 * 60
+)
+
+// webPushSender delivers messages through the Web Push protocol (RFC 8030),
+// encrypting them (RFC 8291) and identifying the server with VAPID (RFC 8292)
+type webPushSender struct {
+	client    *http.Client
+	key       *ecdsa.PrivateKey
+	publicKey string
+}
+
+func newWebPushSender(client *http.Client) (*webPushSender, error) {
+	privateBytes, err := base64.RawURLEncoding.DecodeString(setting.Push.VapidPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicBytes, err := base64.RawURLEncoding.DecodeString(setting.Push.VapidPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	x, y := elliptic.Unmarshal(elliptic.P256(), publicBytes)
+	if x == nil {
+		return nil, errors.New("invalid VAPID public key")
+	}
+
+	return &webPushSender{
+		client: client,
+		key: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y},
+			D:         new(big.Int).SetBytes(privateBytes),
+		},
+		publicKey: setting.Push.VapidPublicKey,
+	}, nil
+}
+
+// Send implements Sender
+func (s *webPushSender) Send(device *models.PushDevice, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	body, err := encryptWebPushPayload(payload, device.P256DH, device.Auth)
+	if err != nil {
+		return fmt.Errorf("encrypt payload: %v", err)
+	}
+
+	endpoint, err := url.Parse(device.Endpoint)
+	if err != nil {
+		return ErrDeviceGone
+	}
+	token, err := s.vapidToken(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return fmt.Errorf("sign VAPID token: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", device.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(webPushTTL))
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", token, s.publicKey))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrDeviceGone
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("push service responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// vapidToken returns a JWT identifying the server to the push service at audience
+func (s *webPushSender) vapidToken(audience string) (string, error) {
+	subject := setting.Push.VapidSubject
+	// push services may reject subjects which are neither https nor mailto URLs
+	if u, err := url.Parse(subject); (err != nil || (u.Scheme != "https" && u.Scheme != "mailto")) && setting.MailService != nil {
+		subject = "mailto:" + setting.MailService.From
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.StandardClaims{
+		Audience:  audience,
+		ExpiresAt: time.Now().Add(12 * time.Hour).Unix(),
+		Subject:   subject,
+	})
+	return token.SignedString(s.key)
+}
+
+// encryptWebPushPayload encrypts payload for the subscription identified by the base64 encoded
+// public key p256dh and authentication secret auth using the aes128gcm content coding (RFC 8291)
+func encryptWebPushPayload(payload []byte, p256dh, auth string) ([]byte, error) {
+	uaPublic, err := decodeWebPushKey(p256dh)
+	if err != nil {
+		return nil, err
+	}
+	authSecret, err := decodeWebPushKey(auth)
+	if err != nil {
+		return nil, err
+	}
+	curve := elliptic.P256()
+	uaX, uaY := elliptic.Unmarshal(curve, uaPublic)
+	if uaX == nil {
+		return nil, errors.New("invalid subscription public key")
+	}
+
+	asKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := elliptic.Marshal(curve, asKey.X, asKey.Y)
+	sharedX, _ := curve.ScalarMult(uaX, uaY, asKey.D.Bytes())
+	sharedSecret := make([]byte, 32)
+	sharedX.FillBytes(sharedSecret)
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	keyInfo := append(append(append([]byte("WebPush: info\x00"), uaPublic...), asPublic...), 1)
+	ikm := hmacSHA256(hmacSHA256(authSecret, sharedSecret), keyInfo)
+	prk := hmacSHA256(salt, ikm)
+	cek := hmacSHA256(prk, []byte("Content-Encoding: aes128gcm\x00\x01"))[:16]
+	nonce := hmacSHA256(prk, []byte("Content-Encoding: nonce\x00\x01"))[:12]
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload)+1+gcm.Overhead() > webPushRecordSize {
+		return nil, errors.New("payload is too large")
+	}
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = append(header, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(header[16:20], webPushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// a single record, ended by the last record delimiter
+	plaintext := append(append([]byte{}, payload...), 2)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// ValidateWebPushKeys checks the keys of a push subscription
+func ValidateWebPushKeys(p256dh, auth string) error {
+	publicKey, err := decodeWebPushKey(p256dh)
+	if err != nil {
+		return fmt.Errorf("invalid p256dh key: %v", err)
+	}
+	if x, _ := elliptic.Unmarshal(elliptic.P256(), publicKey); x == nil {
+		return errors.New("p256dh is not an uncompressed P-256 public key")
+	}
+	authSecret, err := decodeWebPushKey(auth)
+	if err != nil {
+		return fmt.Errorf("invalid auth secret: %v", err)
+	}
+	if len(authSecret) != 16 {
+		return errors.New("auth secret must be 16 bytes long")
+	}
+	return nil
+}
+
+// decodeWebPushKey decodes a key of a push subscription, which browsers encode as base64url
+func decodeWebPushKey(key string) ([]byte, error) {
+	if b, err := base64.RawURLEncoding.DecodeString(key); err == nil {
+		return b, nil
+	}
+	return base64.URLEncoding.DecodeString(key)
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(data)
+	return mac.Sum(nil)
+}
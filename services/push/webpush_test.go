@@ -0,0 +1,86 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package push
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// decryptWebPushPayload decrypts a message body in the way a user agent does
+func decryptWebPushPayload(t *testing.T, body []byte, uaKey *ecdsa.PrivateKey, authSecret []byte) []byte {
+	salt := body[:16]
+	assert.EqualValues(t, webPushRecordSize, binary.BigEndian.Uint32(body[16:20]))
+	idLen := int(body[20])
+	asPublic := body[21 : 21+idLen]
+	ciphertext := body[21+idLen:]
+
+	curve := elliptic.P256()
+	asX, asY := elliptic.Unmarshal(curve, asPublic)
+	if !assert.NotNil(t, asX) {
+		return nil
+	}
+	sharedX, _ := curve.ScalarMult(asX, asY, uaKey.D.Bytes())
+	sharedSecret := make([]byte, 32)
+	sharedX.FillBytes(sharedSecret)
+
+	uaPublic := elliptic.Marshal(curve, uaKey.X, uaKey.Y)
+	keyInfo := append(append(append([]byte("WebPush: info\x00"), uaPublic...), asPublic...), 1)
+	ikm := hmacSHA256(hmacSHA256(authSecret, sharedSecret), keyInfo)
+	prk := hmacSHA256(salt, ikm)
+	cek := hmacSHA256(prk, []byte("Content-Encoding: aes128gcm\x00\x01"))[:16]
+	nonce := hmacSHA256(prk, []byte("Content-Encoding: nonce\x00\x01"))[:12]
+
+	block, err := aes.NewCipher(cek)
+	assert.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	assert.EqualValues(t, 2, plaintext[len(plaintext)-1], "last record delimiter")
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestEncryptWebPushPayload(t *testing.T) {
+	uaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	authSecret := make([]byte, 16)
+	_, err = rand.Read(authSecret)
+	assert.NoError(t, err)
+
+	p256dh := base64.RawURLEncoding.EncodeToString(elliptic.Marshal(elliptic.P256(), uaKey.X, uaKey.Y))
+	auth := base64.RawURLEncoding.EncodeToString(authSecret)
+	assert.NoError(t, ValidateWebPushKeys(p256dh, auth))
+
+	payload := []byte(`{"title":"[user2/repo1] issue1 (#1)","body":"user1 commented."}`)
+	body, err := encryptWebPushPayload(payload, p256dh, auth)
+	assert.NoError(t, err)
+	assert.Equal(t, payload, decryptWebPushPayload(t, body, uaKey, authSecret))
+
+	_, err = encryptWebPushPayload(make([]byte, webPushRecordSize), p256dh, auth)
+	assert.Error(t, err)
+}
+
+func TestValidateWebPushKeys(t *testing.T) {
+	uaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p256dh := base64.URLEncoding.EncodeToString(elliptic.Marshal(elliptic.P256(), uaKey.X, uaKey.Y))
+	auth := base64.URLEncoding.EncodeToString(make([]byte, 16))
+
+	assert.NoError(t, ValidateWebPushKeys(p256dh, auth))
+	assert.Error(t, ValidateWebPushKeys("not a key", auth))
+	assert.Error(t, ValidateWebPushKeys(auth, auth))
+	assert.Error(t, ValidateWebPushKeys(p256dh, p256dh))
+}
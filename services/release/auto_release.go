@@ -0,0 +1,328 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package release
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/repository"
+)
+
+const (
+	// DefaultAutoReleaseTagTemplate is the tag template of an auto release rule which does not set one
+	DefaultAutoReleaseTagTemplate = "v${Version}"
+	// DefaultAutoReleaseTitleTemplate is the title template of an auto release rule which does not set one
+	DefaultAutoReleaseTitleTemplate = "${Tag}"
+	// DefaultAutoReleaseNotesTemplate is the notes template of an auto release rule which does not set one
+	DefaultAutoReleaseNotesTemplate = "${Commits}"
+
+	// maxVersionFileSize is the size of the beginning of a version file which is searched for the version
+	maxVersionFileSize = 1024 * 1024
+	// maxAutoReleaseCommits is the number of commits listed by the ${Commits} variable
+	maxAutoReleaseCommits = 100
+)
+
+var (
+	autoReleaseVariablePattern = regexp.MustCompile(`\$\{(\w+)\}`)
+
+	autoReleaseQueue queue.Queue
+)
+
+// AutoReleaseTask is a push to the default branch of a repository with an auto release rule
+type AutoReleaseTask struct {
+	RepoID      int64
+	PusherID    int64
+	OldCommitID string
+	NewCommitID string
+}
+
+type autoReleaseNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &autoReleaseNotifier{}
+)
+
+// InitAutoRelease creates the queue of the auto release rules and registers the notifier which feeds it on push
+func InitAutoRelease() error {
+	autoReleaseQueue = queue.CreateQueue("auto_release", func(data ...queue.Data) {
+		for _, datum := range data {
+			task := datum.(*AutoReleaseTask)
+			if err := handleAutoRelease(task); err != nil {
+				log.Error("Auto release of repository %d for commit %s failed: %v", task.RepoID, task.NewCommitID, err)
+			}
+		}
+	}, &AutoReleaseTask{})
+	if autoReleaseQueue == nil {
+		return errors.New("unable to create auto release queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(autoReleaseQueue.Run)
+	notification.RegisterNotifier(&autoReleaseNotifier{})
+	return nil
+}
+
+func (*autoReleaseNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if !opts.IsBranch() || opts.IsDelRef() || opts.BranchName() != repo.DefaultBranch {
+		return
+	}
+	if _, err := models.GetRepoAutoRelease(repo.ID); err != nil {
+		if err != models.ErrRepoAutoReleaseNotExist {
+			log.Error("GetRepoAutoRelease [repo: %d]: %v", repo.ID, err)
+		}
+		return
+	}
+	if err := autoReleaseQueue.Push(&AutoReleaseTask{
+		RepoID:      repo.ID,
+		PusherID:    pusher.ID,
+		OldCommitID: opts.OldCommitID,
+		NewCommitID: opts.NewCommitID,
+	}); err != nil {
+		log.Error("Unable to queue the auto release of repository %d: %v", repo.ID, err)
+	}
+}
+
+// ValidateAutoRelease checks the version pattern and the templates of an auto release rule
+func ValidateAutoRelease(rule *models.RepoAutoRelease) error {
+	if rule.VersionPattern != "" {
+		if _, err := regexp.Compile(rule.VersionPattern); err != nil {
+			return fmt.Errorf("invalid version pattern: %v", err)
+		}
+	}
+	for _, tmpl := range []string{rule.TagTemplate, rule.TitleTemplate, rule.NotesTemplate} {
+		for _, match := range autoReleaseVariablePattern.FindAllStringSubmatch(tmpl, -1) {
+			if !isAutoReleaseVariable(match[1]) {
+				return fmt.Errorf("unknown template variable %s", match[0])
+			}
+		}
+	}
+	if !strings.Contains(rule.TagTemplate, "${Version}") && rule.TagTemplate != "" {
+		return errors.New("the tag template must contain ${Version}")
+	}
+	return nil
+}
+
+// handleAutoRelease creates the release of a push to the default branch if it changed the version
+func handleAutoRelease(task *AutoReleaseTask) error {
+	rule, err := models.GetRepoAutoRelease(task.RepoID)
+	if err != nil {
+		if err == models.ErrRepoAutoReleaseNotExist {
+			// the rule was deleted after the push was queued
+			return nil
+		}
+		return err
+	}
+	repo, err := models.GetRepositoryByID(task.RepoID)
+	if err != nil {
+		return err
+	}
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(task.NewCommitID)
+	if err != nil {
+		return err
+	}
+	version, err := readVersion(rule, commit)
+	if err != nil {
+		rule.LastError = err.Error()
+		return models.UpdateRepoAutoReleaseResult(rule)
+	}
+
+	previousVersion := rule.LastVersion
+	if task.OldCommitID != "" && task.OldCommitID != git.EmptySHA {
+		if oldCommit, err := gitRepo.GetCommit(task.OldCommitID); err == nil {
+			if oldVersion, err := readVersion(rule, oldCommit); err == nil {
+				if oldVersion == version {
+					return nil
+				}
+				if previousVersion == "" {
+					previousVersion = oldVersion
+				}
+			}
+		}
+	}
+	// the version may already be released by an earlier push, e.g. after a revert
+	if version == rule.LastVersion {
+		return nil
+	}
+
+	vars := map[string]string{
+		"Version":         version,
+		"PreviousVersion": previousVersion,
+		"PreviousTag":     rule.LastTagName,
+		"CommitID":        commit.ID.String(),
+		"ShortCommitID":   commit.ID.String()[:10],
+		"Repository":      repo.FullName(),
+	}
+	tagName := strings.TrimSpace(expandAutoReleaseTemplate(orDefault(rule.TagTemplate, DefaultAutoReleaseTagTemplate), vars))
+	vars["Tag"] = tagName
+
+	isExist, err := models.IsReleaseExist(repo.ID, tagName)
+	if err != nil {
+		return err
+	}
+	if isExist || gitRepo.IsTagExist(tagName) {
+		log.Trace("Auto release of repository %d skipped, tag %s already exists", repo.ID, tagName)
+		rule.LastVersion, rule.LastTagName, rule.LastCommitID, rule.LastError = version, tagName, commit.ID.String(), ""
+		return models.UpdateRepoAutoReleaseResult(rule)
+	}
+
+	vars["Commits"], err = listAutoReleaseCommits(gitRepo, commit, rule.LastTagName)
+	if err != nil {
+		return err
+	}
+
+	pusher, err := models.GetUserByID(task.PusherID)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			return err
+		}
+		pusher = models.NewGhostUser()
+	}
+
+	rel := &models.Release{
+		RepoID:       repo.ID,
+		Repo:         repo,
+		PublisherID:  pusher.ID,
+		Publisher:    pusher,
+		TagName:      tagName,
+		Target:       commit.ID.String(),
+		Title:        strings.TrimSpace(expandAutoReleaseTemplate(orDefault(rule.TitleTemplate, DefaultAutoReleaseTitleTemplate), vars)),
+		Note:         strings.TrimSpace(expandAutoReleaseTemplate(orDefault(rule.NotesTemplate, DefaultAutoReleaseNotesTemplate), vars)),
+		IsDraft:      rule.IsDraft,
+		IsPrerelease: rule.IsPrerelease,
+	}
+	if err := CreateRelease(gitRepo, rel, nil, ""); err != nil {
+		if !models.IsErrInvalidTagName(err) && !models.IsErrProtectedTagName(err) && !models.IsErrReleaseAlreadyExist(err) {
+			return err
+		}
+		rule.LastError = err.Error()
+		return models.UpdateRepoAutoReleaseResult(rule)
+	}
+
+	rule.LastVersion, rule.LastTagName, rule.LastCommitID, rule.LastError = version, tagName, commit.ID.String(), ""
+	return models.UpdateRepoAutoReleaseResult(rule)
+}
+
+// readVersion reads the version from the file of an auto release rule in a commit
+func readVersion(rule *models.RepoAutoRelease, commit *git.Commit) (string, error) {
+	blob, err := commit.GetBlobByPath(rule.FilePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", fmt.Errorf("file %s does not exist", rule.FilePath)
+		}
+		return "", err
+	}
+	rc, err := blob.DataAsync()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(io.LimitReader(rc, maxVersionFileSize))
+	if err != nil {
+		return "", err
+	}
+	return extractVersion(rule, content)
+}
+
+// extractVersion returns the version in the content of the file of an auto release rule
+func extractVersion(rule *models.RepoAutoRelease, content []byte) (string, error) {
+	var version string
+	switch {
+	case rule.VersionPattern != "":
+		re, err := regexp.Compile(rule.VersionPattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid version pattern: %v", err)
+		}
+		match := re.FindSubmatch(content)
+		if match == nil {
+			return "", fmt.Errorf("version pattern does not match %s", rule.FilePath)
+		}
+		if len(match) > 1 {
+			version = string(match[1])
+		} else {
+			version = string(match[0])
+		}
+	case strings.EqualFold(path.Ext(rule.FilePath), ".json"):
+		var manifest struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return "", fmt.Errorf("unable to parse %s: %v", rule.FilePath, err)
+		}
+		version = manifest.Version
+	default:
+		version = strings.SplitN(string(content), "\n", 2)[0]
+	}
+
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return "", fmt.Errorf("no version found in %s", rule.FilePath)
+	}
+	return version, nil
+}
+
+// listAutoReleaseCommits returns the markdown list of the commits since the previous tag of an auto release rule
+func listAutoReleaseCommits(gitRepo *git.Repository, commit *git.Commit, previousTag string) (string, error) {
+	var before *git.Commit
+	if previousTag != "" {
+		if tagCommit, err := gitRepo.GetTagCommit(previousTag); err == nil {
+			before = tagCommit
+		}
+	}
+	commits, err := gitRepo.CommitsBetweenLimit(commit, before, maxAutoReleaseCommits, 0)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, c := range commits {
+		fmt.Fprintf(&sb, "- %s (%s)\n", c.Summary(), c.ID.String()[:10])
+	}
+	return sb.String(), nil
+}
+
+// expandAutoReleaseTemplate replaces the ${Name} variables of a template of an auto release rule
+func expandAutoReleaseTemplate(tmpl string, vars map[string]string) string {
+	return autoReleaseVariablePattern.ReplaceAllStringFunc(tmpl, func(variable string) string {
+		if value, ok := vars[variable[2:len(variable)-1]]; ok {
+			return value
+		}
+		return variable
+	})
+}
+
+func isAutoReleaseVariable(name string) bool {
+	switch name {
+	case "Version", "PreviousVersion", "Tag", "PreviousTag", "CommitID", "ShortCommitID", "Commits", "Repository":
+		return true
+	}
+	return false
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package release
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractVersion(t *testing.T) {
+	version, err := extractVersion(&models.RepoAutoRelease{FilePath: "VERSION"}, []byte("1.2.3\nignored\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", version)
+
+	version, err = extractVersion(&models.RepoAutoRelease{FilePath: "package.json"}, []byte(`{"name": "app", "version": "2.0.0-rc.1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0-rc.1", version)
+
+	version, err = extractVersion(&models.RepoAutoRelease{FilePath: "setup.py", VersionPattern: `version="([^"]+)"`}, []byte(`setup(name="app", version="0.4")`))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.4", version)
+
+	_, err = extractVersion(&models.RepoAutoRelease{FilePath: "setup.py", VersionPattern: `version="([^"]+)"`}, []byte(`setup(name="app")`))
+	assert.Error(t, err)
+	_, err = extractVersion(&models.RepoAutoRelease{FilePath: "VERSION"}, []byte("\n"))
+	assert.Error(t, err)
+}
+
+func TestValidateAutoRelease(t *testing.T) {
+	assert.NoError(t, ValidateAutoRelease(&models.RepoAutoRelease{FilePath: "VERSION"}))
+	assert.NoError(t, ValidateAutoRelease(&models.RepoAutoRelease{FilePath: "VERSION", TagTemplate: "release-${Version}", NotesTemplate: "Changes since ${PreviousTag}:\n${Commits}"}))
+	assert.Error(t, ValidateAutoRelease(&models.RepoAutoRelease{FilePath: "VERSION", TagTemplate: "latest"}))
+	assert.Error(t, ValidateAutoRelease(&models.RepoAutoRelease{FilePath: "VERSION", TitleTemplate: "${Unknown}"}))
+	assert.Error(t, ValidateAutoRelease(&models.RepoAutoRelease{FilePath: "VERSION", VersionPattern: "("}))
+
+	assert.Equal(t, "v1.0 (${Unknown})", expandAutoReleaseTemplate("v${Version} (${Unknown})", map[string]string{"Version": "1.0"}))
+}
+
+func TestHandleAutoRelease(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	rule := &models.RepoAutoRelease{
+		RepoID:         1,
+		FilePath:       "README.md",
+		VersionPattern: `# (\S+)`,
+		TitleTemplate:  "Release ${Version}",
+		IsDraft:        true,
+	}
+	assert.NoError(t, models.CreateOrUpdateRepoAutoRelease(rule))
+
+	task := &AutoReleaseTask{RepoID: 1, PusherID: 2, NewCommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d"}
+	assert.NoError(t, handleAutoRelease(task))
+
+	rel := db.AssertExistsAndLoadBean(t, &models.Release{RepoID: 1, TagName: "vrepo1"}).(*models.Release)
+	assert.Equal(t, "Release repo1", rel.Title)
+	assert.True(t, rel.IsDraft)
+	assert.EqualValues(t, 2, rel.PublisherID)
+
+	rule, err := models.GetRepoAutoRelease(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "repo1", rule.LastVersion)
+	assert.Equal(t, "vrepo1", rule.LastTagName)
+	assert.Empty(t, rule.LastError)
+
+	// the same version is only released once
+	assert.NoError(t, handleAutoRelease(task))
+	db.AssertCount(t, &models.Release{RepoID: 1, TagName: "vrepo1"}, 1)
+}
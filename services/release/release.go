@@ -204,6 +204,7 @@ func UpdateRelease(doer *models.User, gitRepo *git.Repository, rel *models.Relea
 	}
 
 	var deletedUUIDsMap = make(map[string]bool)
+	var deletedPaths []string
 	if len(delAttachmentUUIDs) > 0 {
 		// Check attachments
 		attachments, err := models.GetAttachmentsByUUIDs(ctx, delAttachmentUUIDs)
@@ -215,6 +216,9 @@ func UpdateRelease(doer *models.User, gitRepo *git.Repository, rel *models.Relea
 				return errors.New("delete attachement of release permission denied")
 			}
 			deletedUUIDsMap[attach.UUID] = true
+			if !attach.IsDeduplicated() {
+				deletedPaths = append(deletedPaths, attach.RelativePath())
+			}
 		}
 
 		if _, err := models.DeleteAttachments(ctx, attachments, false); err != nil {
@@ -254,14 +258,15 @@ func UpdateRelease(doer *models.User, gitRepo *git.Repository, rel *models.Relea
 		return
 	}
 
-	for _, uuid := range delAttachmentUUIDs {
-		if err := storage.Attachments.Delete(models.AttachmentRelativePath(uuid)); err != nil {
+	for _, p := range deletedPaths {
+		if err := storage.Attachments.Delete(p); err != nil {
 			// Even delete files failed, but the attachments has been removed from database, so we
 			// should not return error but only record the error on logs.
 			// users have to delete this attachments manually or we should have a
 			// synchronize between database attachment table and attachment storage
-			log.Error("delete attachment[uuid: %s] failed: %v", uuid, err)
+			log.Error("delete attachment[path: %s] failed: %v", p, err)
 		}
+		models.RemoveAttachmentThumbnails(p)
 	}
 
 	if !isCreated {
@@ -327,9 +332,13 @@ func DeleteReleaseByID(id int64, doer *models.User, delTag bool) error {
 
 	for i := range rel.Attachments {
 		attachment := rel.Attachments[i]
+		if attachment.IsDeduplicated() {
+			continue
+		}
 		if err := storage.Attachments.Delete(attachment.RelativePath()); err != nil {
 			log.Error("Delete attachment %s of release %s failed: %v", attachment.UUID, rel.ID, err)
 		}
+		models.RemoveAttachmentThumbnails(attachment.RelativePath())
 	}
 
 	notification.NotifyDeleteRelease(doer, rel)
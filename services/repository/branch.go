@@ -10,6 +10,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
@@ -70,3 +71,31 @@ func DeleteBranch(doer *models.User, repo *models.Repository, gitRepo *git.Repos
 
 	return nil
 }
+
+// RenameBranch renames a branch and moves the default branch, the branch protection and the pull requests to the new name
+func RenameBranch(doer *models.User, repo *models.Repository, gitRepo *git.Repository, from, to string) error {
+	if !gitRepo.IsBranchExist(from) {
+		return models.ErrBranchDoesNotExist{
+			BranchName: from,
+		}
+	}
+	if err := repo_module.CheckBranchName(repo, to); err != nil {
+		return err
+	}
+
+	if err := repo.RenameBranch(from, to, func(isDefault bool) error {
+		if err := gitRepo.RenameBranch(from, to); err != nil {
+			return err
+		}
+		if isDefault {
+			return gitRepo.SetDefaultBranch(to)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	notification.NotifyDeleteRef(doer, repo, "branch", git.BranchPrefix+from)
+	notification.NotifyCreateRef(doer, repo, "branch", git.BranchPrefix+to)
+	return nil
+}
@@ -0,0 +1,290 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/codeowners"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// maxCodeOwnersFileSize is the size of the beginning of a code owners file which is parsed
+const maxCodeOwnersFileSize = 3 * 1024 * 1024
+
+// The kinds of problems of a code owners file
+const (
+	CodeOwnersErrorSyntax          = "syntax"
+	CodeOwnersErrorUnknownOwner    = "unknown_owner"
+	CodeOwnersErrorUnreachableRule = "unreachable_rule"
+)
+
+// ValidateCodeOwners checks the content of a code owners file for a repository,
+// the rules are checked against the files of the commit unless it is nil
+func ValidateCodeOwners(repo *models.Repository, commit *git.Commit, content []byte) ([]*api.CodeOwnersError, error) {
+	file, syntaxErrs := codeowners.Parse(content)
+
+	problems := make([]*api.CodeOwnersError, 0, len(syntaxErrs))
+	for _, err := range syntaxErrs {
+		problems = append(problems, &api.CodeOwnersError{
+			Line:    err.Line,
+			Kind:    CodeOwnersErrorSyntax,
+			Message: err.Reason,
+		})
+	}
+
+	// the same owner is usually listed on many lines
+	checked := make(map[string]error)
+	for _, rule := range file.Rules {
+		for _, owner := range rule.Owners {
+			err, ok := checked[strings.ToLower(owner.String())]
+			if !ok {
+				_, _, err = ResolveCodeOwner(repo, owner)
+				checked[strings.ToLower(owner.String())] = err
+			}
+			if err == nil {
+				continue
+			}
+			if !models.IsErrUserNotExist(err) && !models.IsErrTeamNotExist(err) {
+				return nil, err
+			}
+			problems = append(problems, &api.CodeOwnersError{
+				Line:    rule.Line,
+				Kind:    CodeOwnersErrorUnknownOwner,
+				Pattern: rule.Pattern,
+				Owner:   owner.String(),
+				Message: fmt.Sprintf("unknown %s %s", owner.Type, owner),
+			})
+		}
+	}
+
+	if commit != nil {
+		entries, err := commit.Tree.ListEntriesRecursive()
+		if err != nil {
+			return nil, err
+		}
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			if !entry.IsDir() && !entry.IsSubModule() {
+				paths = append(paths, entry.Name())
+			}
+		}
+
+		for _, rule := range file.UnmatchedRules(paths) {
+			message := "pattern matches no file"
+			for _, path := range paths {
+				if rule.Match(path) {
+					message = "all files matching the pattern are owned by later rules"
+					break
+				}
+			}
+			problems = append(problems, &api.CodeOwnersError{
+				Line:    rule.Line,
+				Kind:    CodeOwnersErrorUnreachableRule,
+				Pattern: rule.Pattern,
+				Message: message,
+			})
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+	return problems, nil
+}
+
+// ResolveCodeOwner returns the user or the team of an owner of a code owners rule of a repository,
+// teams must belong to the organization owning the repository
+func ResolveCodeOwner(repo *models.Repository, owner *codeowners.Owner) (*models.User, *models.Team, error) {
+	switch owner.Type {
+	case codeowners.OwnerTypeTeam:
+		if err := repo.GetOwner(); err != nil {
+			return nil, nil, err
+		}
+		if !repo.Owner.IsOrganization() || !strings.EqualFold(repo.Owner.Name, owner.Org) {
+			return nil, nil, models.ErrTeamNotExist{OrgID: repo.OwnerID, Name: owner.Name}
+		}
+		team, err := models.GetTeam(repo.OwnerID, owner.Name)
+		return nil, team, err
+	case codeowners.OwnerTypeEmail:
+		u, err := models.GetUserByEmail(owner.Name)
+		return u, nil, err
+	default:
+		u, err := models.GetUserByName(owner.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if u.IsOrganization() {
+			return nil, nil, models.ErrUserNotExist{Name: owner.Name}
+		}
+		return u, nil, nil
+	}
+}
+
+// FindCodeOwnersFile returns the path and the content of the code owners file of a commit, the path is empty if there is none
+func FindCodeOwnersFile(commit *git.Commit) (string, []byte, error) {
+	for _, filePath := range codeowners.FilePaths {
+		blob, err := commit.GetBlobByPath(filePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return "", nil, err
+		}
+		rc, err := blob.DataAsync()
+		if err != nil {
+			return "", nil, err
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxCodeOwnersFileSize))
+		rc.Close()
+		if err != nil {
+			return "", nil, err
+		}
+		return filePath, content, nil
+	}
+	return "", nil, nil
+}
+
+// CodeOwner is a resolved owner of a file changed by a pull request
+type CodeOwner struct {
+	Owner *codeowners.Owner
+	// User or Team is nil if the owner does not exist
+	User     *models.User
+	Team     *models.Team
+	Approved bool
+}
+
+// FileCodeOwners are the owners of a file changed by a pull request
+type FileCodeOwners struct {
+	Path string
+	// Rule is nil if no rule of the code owners file applies to the file
+	Rule   *codeowners.Rule
+	Owners []*CodeOwner
+	// Approved is true if one of the owners approved the pull request or if the file has no owners
+	Approved bool
+}
+
+// GetPullRequestCodeOwners returns the owners of the files changed by a pull request according to the code owners file
+// of its base branch, and whether they approved it. The path of the code owners file is empty if there is none.
+func GetPullRequestCodeOwners(pr *models.PullRequest) (string, []*FileCodeOwners, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return "", nil, err
+	}
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return "", nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return "", nil, err
+	}
+	defer gitRepo.Close()
+
+	baseCommit, err := gitRepo.GetBranchCommit(pr.BaseBranch)
+	if err != nil {
+		return "", nil, err
+	}
+	codeOwnersPath, content, err := FindCodeOwnersFile(baseCommit)
+	if err != nil {
+		return "", nil, err
+	}
+	file, _ := codeowners.Parse(content)
+
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return "", nil, err
+	}
+	mergeBase := pr.MergeBase
+	if mergeBase == "" {
+		mergeBase = pr.BaseBranch
+	}
+	paths, err := gitRepo.GetFilesChangedBetween(mergeBase, headCommitID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	approvers, err := getPullRequestApprovers(pr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the owners of a rule are resolved once for all the files it applies to
+	ruleOwners := make(map[*codeowners.Rule][]*CodeOwner)
+	files := make([]*FileCodeOwners, 0, len(paths))
+	for _, path := range paths {
+		fileOwners := &FileCodeOwners{Path: path, Rule: file.Match(path)}
+		if fileOwners.Rule != nil {
+			owners, ok := ruleOwners[fileOwners.Rule]
+			if !ok {
+				if owners, err = resolveCodeOwners(pr.BaseRepo, fileOwners.Rule, approvers); err != nil {
+					return "", nil, err
+				}
+				ruleOwners[fileOwners.Rule] = owners
+			}
+			fileOwners.Owners = owners
+		}
+
+		fileOwners.Approved = len(fileOwners.Owners) == 0
+		for _, owner := range fileOwners.Owners {
+			if owner.Approved {
+				fileOwners.Approved = true
+				break
+			}
+		}
+		files = append(files, fileOwners)
+	}
+	return codeOwnersPath, files, nil
+}
+
+// getPullRequestApprovers returns the IDs of the users whose latest review of a pull request is a valid approval
+func getPullRequestApprovers(pr *models.PullRequest) ([]int64, error) {
+	reviews, err := models.GetReviewersByIssueID(pr.IssueID)
+	if err != nil {
+		return nil, err
+	}
+	approvers := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		if review.Type != models.ReviewTypeApprove || review.ReviewerID == 0 {
+			continue
+		}
+		if review.Stale && pr.ProtectedBranch != nil && pr.ProtectedBranch.DismissStaleApprovals {
+			continue
+		}
+		approvers = append(approvers, review.ReviewerID)
+	}
+	return approvers, nil
+}
+
+func resolveCodeOwners(repo *models.Repository, rule *codeowners.Rule, approvers []int64) ([]*CodeOwner, error) {
+	owners := make([]*CodeOwner, 0, len(rule.Owners))
+	for _, o := range rule.Owners {
+		owner := &CodeOwner{Owner: o}
+		user, team, err := ResolveCodeOwner(repo, o)
+		if err != nil && !models.IsErrUserNotExist(err) && !models.IsErrTeamNotExist(err) {
+			return nil, err
+		}
+		owner.User, owner.Team = user, team
+
+		for _, approverID := range approvers {
+			if user != nil && user.ID == approverID {
+				owner.Approved = true
+			} else if team != nil {
+				if owner.Approved, err = models.IsTeamMember(team.OrgID, team.ID, approverID); err != nil {
+					return nil, err
+				}
+			}
+			if owner.Approved {
+				break
+			}
+		}
+		owners = append(owners, owner)
+	}
+	return owners, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/codeowners"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCodeOwners(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo3 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	problems, err := ValidateCodeOwners(repo3, nil, []byte("*  @user2 @user3/owners user2@example.com\n/docs/  @nobody @user3\nsrc/[a-z]  @user2\n*.go  @user3/missing\n"))
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 4) {
+		assert.Equal(t, CodeOwnersErrorUnknownOwner, problems[0].Kind)
+		assert.Equal(t, "@nobody", problems[0].Owner)
+		assert.Equal(t, CodeOwnersErrorUnknownOwner, problems[1].Kind)
+		assert.Equal(t, "@user3", problems[1].Owner)
+		assert.Equal(t, CodeOwnersErrorSyntax, problems[2].Kind)
+		assert.Equal(t, 3, problems[2].Line)
+		assert.Equal(t, CodeOwnersErrorUnknownOwner, problems[3].Kind)
+		assert.Equal(t, "@user3/missing", problems[3].Owner)
+	}
+}
+
+func TestResolveCodeOwners(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo3 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	file, errs := codeowners.Parse([]byte("* @user4 @user3/owners @user3/team1 @nobody\n"))
+	assert.Empty(t, errs)
+
+	owners, err := resolveCodeOwners(repo3, file.Rules[0], []int64{2})
+	assert.NoError(t, err)
+	if assert.Len(t, owners, 4) {
+		assert.EqualValues(t, 4, owners[0].User.ID)
+		assert.False(t, owners[0].Approved)
+		assert.EqualValues(t, 1, owners[1].Team.ID)
+		assert.True(t, owners[1].Approved)
+		assert.EqualValues(t, 2, owners[2].Team.ID)
+		assert.Nil(t, owners[3].User)
+		assert.False(t, owners[3].Approved)
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/notification/base"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// CreateProtectionTemplate creates a branch protection template of an organization
+func CreateProtectionTemplate(org *models.User, form *api.CreateBranchProtectionTemplateOption) (*models.BranchProtectionTemplate, error) {
+	whitelists, err := getWhitelistOptions(org.ID,
+		form.PushWhitelistUsernames, form.PushWhitelistTeams,
+		form.MergeWhitelistUsernames, form.MergeWhitelistTeams,
+		form.ApprovalsWhitelistUsernames, form.ApprovalsWhitelistTeams)
+	if err != nil {
+		return nil, err
+	}
+
+	var requiredApprovals int64
+	if form.RequiredApprovals > 0 {
+		requiredApprovals = form.RequiredApprovals
+	}
+
+	var statusCheckMaxAge int64
+	if form.StatusCheckMaxAge > 0 {
+		statusCheckMaxAge = form.StatusCheckMaxAge
+	}
+	t := &models.BranchProtectionTemplate{
+		OrgID:                         org.ID,
+		Name:                          form.Name,
+		BranchPattern:                 strings.TrimSpace(form.BranchPattern),
+		ApplyToNewRepos:               form.ApplyToNewRepos,
+		CanPush:                       form.EnablePush,
+		EnableWhitelist:               form.EnablePush && form.EnablePushWhitelist,
+		WhitelistUserIDs:              whitelists.UserIDs,
+		WhitelistTeamIDs:              whitelists.TeamIDs,
+		EnableMergeWhitelist:          form.EnableMergeWhitelist,
+		WhitelistDeployKeys:           form.EnablePush && form.EnablePushWhitelist && form.PushWhitelistDeployKeys,
+		MergeWhitelistUserIDs:         whitelists.MergeUserIDs,
+		MergeWhitelistTeamIDs:         whitelists.MergeTeamIDs,
+		EnableStatusCheck:             form.EnableStatusCheck,
+		StatusCheckContexts:           form.StatusCheckContexts,
+		StatusCheckMaxAge:             statusCheckMaxAge,
+		EnableApprovalsWhitelist:      form.EnableApprovalsWhitelist,
+		ApprovalsWhitelistUserIDs:     whitelists.ApprovalsUserIDs,
+		ApprovalsWhitelistTeamIDs:     whitelists.ApprovalsTeamIDs,
+		RequiredApprovals:             requiredApprovals,
+		BlockOnRejectedReviews:        form.BlockOnRejectedReviews,
+		BlockOnOfficialReviewRequests: form.BlockOnOfficialReviewRequests,
+		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
+		DismissStaleApprovals:         form.DismissStaleApprovals,
+		RequireSignedCommits:          form.RequireSignedCommits,
+		ProtectedFilePatterns:         form.ProtectedFilePatterns,
+		UnprotectedFilePatterns:       form.UnprotectedFilePatterns,
+	}
+	if err := models.CreateBranchProtectionTemplate(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// EditProtectionTemplate changes the settings of a branch protection template which are set in the form,
+// the branch protections created from it are not changed
+func EditProtectionTemplate(t *models.BranchProtectionTemplate, form *api.EditBranchProtectionTemplateOption) error {
+	if form.BranchPattern != nil {
+		t.BranchPattern = strings.TrimSpace(*form.BranchPattern)
+	}
+	if form.ApplyToNewRepos != nil {
+		t.ApplyToNewRepos = *form.ApplyToNewRepos
+	}
+	if form.EnablePush != nil {
+		t.CanPush = *form.EnablePush
+	}
+	if form.EnablePushWhitelist != nil {
+		t.EnableWhitelist = *form.EnablePushWhitelist
+	}
+	if form.PushWhitelistDeployKeys != nil {
+		t.WhitelistDeployKeys = *form.PushWhitelistDeployKeys
+	}
+	if !t.CanPush {
+		t.EnableWhitelist = false
+	}
+	if !t.EnableWhitelist {
+		t.WhitelistDeployKeys = false
+	}
+	if form.EnableMergeWhitelist != nil {
+		t.EnableMergeWhitelist = *form.EnableMergeWhitelist
+	}
+	if form.EnableStatusCheck != nil {
+		t.EnableStatusCheck = *form.EnableStatusCheck
+	}
+	if form.StatusCheckContexts != nil {
+		t.StatusCheckContexts = form.StatusCheckContexts
+	}
+	if form.StatusCheckMaxAge != nil && *form.StatusCheckMaxAge >= 0 {
+		t.StatusCheckMaxAge = *form.StatusCheckMaxAge
+	}
+	if form.RequiredApprovals != nil && *form.RequiredApprovals >= 0 {
+		t.RequiredApprovals = *form.RequiredApprovals
+	}
+	if form.EnableApprovalsWhitelist != nil {
+		t.EnableApprovalsWhitelist = *form.EnableApprovalsWhitelist
+	}
+	if form.BlockOnRejectedReviews != nil {
+		t.BlockOnRejectedReviews = *form.BlockOnRejectedReviews
+	}
+	if form.BlockOnOfficialReviewRequests != nil {
+		t.BlockOnOfficialReviewRequests = *form.BlockOnOfficialReviewRequests
+	}
+	if form.BlockOnOutdatedBranch != nil {
+		t.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
+	}
+	if form.DismissStaleApprovals != nil {
+		t.DismissStaleApprovals = *form.DismissStaleApprovals
+	}
+	if form.RequireSignedCommits != nil {
+		t.RequireSignedCommits = *form.RequireSignedCommits
+	}
+	if form.ProtectedFilePatterns != nil {
+		t.ProtectedFilePatterns = *form.ProtectedFilePatterns
+	}
+	if form.UnprotectedFilePatterns != nil {
+		t.UnprotectedFilePatterns = *form.UnprotectedFilePatterns
+	}
+
+	// the whitelists which are not in the form are kept
+	whitelists, err := getWhitelistOptions(t.OrgID,
+		form.PushWhitelistUsernames, form.PushWhitelistTeams,
+		form.MergeWhitelistUsernames, form.MergeWhitelistTeams,
+		form.ApprovalsWhitelistUsernames, form.ApprovalsWhitelistTeams)
+	if err != nil {
+		return err
+	}
+	if form.PushWhitelistUsernames != nil {
+		t.WhitelistUserIDs = whitelists.UserIDs
+	}
+	if form.PushWhitelistTeams != nil {
+		t.WhitelistTeamIDs = whitelists.TeamIDs
+	}
+	if form.MergeWhitelistUsernames != nil {
+		t.MergeWhitelistUserIDs = whitelists.MergeUserIDs
+	}
+	if form.MergeWhitelistTeams != nil {
+		t.MergeWhitelistTeamIDs = whitelists.MergeTeamIDs
+	}
+	if form.ApprovalsWhitelistUsernames != nil {
+		t.ApprovalsWhitelistUserIDs = whitelists.ApprovalsUserIDs
+	}
+	if form.ApprovalsWhitelistTeams != nil {
+		t.ApprovalsWhitelistTeamIDs = whitelists.ApprovalsTeamIDs
+	}
+
+	return models.UpdateBranchProtectionTemplate(t)
+}
+
+// ApplyProtectionTemplate links a template to a repository and protects the branches of the repository matching it,
+// the existing protections of these branches are replaced
+func ApplyProtectionTemplate(t *models.BranchProtectionTemplate, repo *models.Repository) error {
+	if err := models.LinkBranchProtectionTemplate(t.ID, repo.ID); err != nil {
+		return err
+	}
+	if repo.IsEmpty {
+		return nil
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	branches, _, err := gitRepo.GetBranches(0, 0)
+	if err != nil {
+		return err
+	}
+	for _, branch := range branches {
+		if !t.MatchBranch(branch) {
+			continue
+		}
+		if err := protectBranchFromTemplate(t, repo, branch, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protectBranchFromTemplate protects a branch with the settings of a template,
+// an existing protection is only replaced if replace is true
+func protectBranchFromTemplate(t *models.BranchProtectionTemplate, repo *models.Repository, branchName string, replace bool) error {
+	protectBranch, err := models.GetProtectedBranchBy(repo.ID, branchName)
+	if err != nil {
+		return err
+	}
+	if protectBranch == nil {
+		protectBranch = &models.ProtectedBranch{
+			RepoID:     repo.ID,
+			BranchName: branchName,
+		}
+	} else if !replace {
+		return nil
+	}
+
+	t.ApplyTo(protectBranch)
+	if err := models.UpdateProtectBranch(repo, protectBranch, t.WhitelistOptions()); err != nil {
+		return fmt.Errorf("UpdateProtectBranch: %v", err)
+	}
+	return pull_service.CheckPrsForBaseBranch(repo, branchName)
+}
+
+// ProtectionDrift is a branch of a repository whose protection deviates from its template
+type ProtectionDrift struct {
+	Repo   *models.Repository
+	Branch string
+	// Protection is nil if the branch is not protected
+	Protection  *models.ProtectedBranch
+	Differences []string
+}
+
+// GetProtectionTemplateDrift returns the branches of the repositories a template is applied to which match the template
+// but whose protection deviates from it
+func GetProtectionTemplateDrift(t *models.BranchProtectionTemplate) ([]*ProtectionDrift, error) {
+	repos, err := models.GetBranchProtectionTemplateRepos(t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]*ProtectionDrift, 0, len(repos))
+	for _, repo := range repos {
+		if repo.IsEmpty {
+			continue
+		}
+		repoDrifts, err := getRepoProtectionDrift(t, repo)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", repo.FullName(), err)
+		}
+		drifts = append(drifts, repoDrifts...)
+	}
+	return drifts, nil
+}
+
+func getRepoProtectionDrift(t *models.BranchProtectionTemplate, repo *models.Repository) ([]*ProtectionDrift, error) {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	branches, _, err := gitRepo.GetBranches(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []*ProtectionDrift
+	for _, branch := range branches {
+		if !t.MatchBranch(branch) {
+			continue
+		}
+		protectBranch, err := models.GetProtectedBranchBy(repo.ID, branch)
+		if err != nil {
+			return nil, err
+		}
+		drift := &ProtectionDrift{Repo: repo, Branch: branch, Protection: protectBranch}
+		if protectBranch != nil {
+			if drift.Differences = t.Differences(protectBranch); len(drift.Differences) == 0 {
+				continue
+			}
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
+
+type protectionTemplateNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &protectionTemplateNotifier{}
+)
+
+func initProtectionTemplateNotifier() {
+	notification.RegisterNotifier(&protectionTemplateNotifier{})
+}
+
+func (*protectionTemplateNotifier) NotifyCreateRepository(doer, u *models.User, repo *models.Repository) {
+	applyNewRepoProtectionTemplates(u, repo)
+}
+
+func (*protectionTemplateNotifier) NotifyMigrateRepository(doer, u *models.User, repo *models.Repository) {
+	applyNewRepoProtectionTemplates(u, repo)
+}
+
+// NotifyCreateRef protects the new branches matching the templates applied to the repository,
+// the branches which are already protected are kept as they are
+func (*protectionTemplateNotifier) NotifyCreateRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	if refType != "branch" {
+		return
+	}
+	templates, err := models.GetRepoBranchProtectionTemplates(repo.ID)
+	if err != nil {
+		log.Error("GetRepoBranchProtectionTemplates: %v", err)
+		return
+	}
+	branch := strings.TrimPrefix(refFullName, git.BranchPrefix)
+	for _, t := range templates {
+		if !t.MatchBranch(branch) {
+			continue
+		}
+		if err := protectBranchFromTemplate(t, repo, branch, false); err != nil {
+			log.Error("Unable to protect branch %s of %s with template %s: %v", branch, repo.FullName(), t.Name, err)
+		}
+	}
+}
+
+func applyNewRepoProtectionTemplates(owner *models.User, repo *models.Repository) {
+	if !owner.IsOrganization() {
+		return
+	}
+	templates, err := models.GetNewRepoBranchProtectionTemplates(owner.ID)
+	if err != nil {
+		log.Error("GetNewRepoBranchProtectionTemplates: %v", err)
+		return
+	}
+	for _, t := range templates {
+		if err := ApplyProtectionTemplate(t, repo); err != nil {
+			log.Error("Unable to apply branch protection template %s to %s: %v", t.Name, repo.FullName(), err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CheckPushRule checks an update of a ref against the push rules of the repository and returns an
+// models.ErrPushRuleViolated if the update is rejected. The environment is passed to git, so the
+// objects of a push which are not yet in the repository can be found through the object directory variables.
+func CheckPushRule(repo *models.Repository, rule *models.RepoPushRule, oldCommitID, newCommitID, refFullName string, env []string) error {
+	if rule.IsEmpty() || newCommitID == git.EmptySHA {
+		return nil
+	}
+
+	// 1. Disallow force pushes to all branches
+	if rule.RejectForcePush && oldCommitID != git.EmptySHA && strings.HasPrefix(refFullName, git.BranchPrefix) {
+		output, err := git.NewCommand("rev-list", "--max-count=1", oldCommitID, "^"+newCommitID).RunInDirWithEnv(repo.RepoPath(), env)
+		if err != nil {
+			return fmt.Errorf("Unable to detect force push between %s and %s: %v", oldCommitID, newCommitID, err)
+		} else if len(output) > 0 {
+			return models.ErrPushRuleViolated{
+				Reason: fmt.Sprintf("force pushes to %s are rejected", strings.TrimPrefix(refFullName, git.BranchPrefix)),
+			}
+		}
+	}
+
+	// 2. Check the authors and the files of the new commits
+	checkAuthors := len(rule.GetAuthorEmailDomains()) > 0
+	checkFiles := rule.HasFileRules()
+	if !checkAuthors && !checkFiles {
+		return nil
+	}
+
+	revs := []string{newCommitID, "--not", "--all"}
+	if oldCommitID != git.EmptySHA {
+		revs = []string{oldCommitID + ".." + newCommitID, "--not", "--all"}
+	}
+	stdout, err := git.NewCommand(append([]string{"log", "--format=%H %ae"}, revs...)...).RunInDirWithEnv(repo.RepoPath(), env)
+	if err != nil {
+		return err
+	}
+	commits := make([]string, 0, 10)
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields[0]) == 0 {
+			continue
+		}
+		if checkAuthors {
+			email := ""
+			if len(fields) == 2 {
+				email = fields[1]
+			}
+			if err := rule.CheckAuthorEmail(email); err != nil {
+				return models.ErrPushRuleViolated{
+					Reason: fmt.Sprintf("commit %s: %s", fields[0], err.(models.ErrPushRuleViolated).Reason),
+				}
+			}
+		}
+		commits = append(commits, fields[0])
+	}
+	if !checkFiles || len(commits) == 0 {
+		return nil
+	}
+
+	blobPaths, err := getAddedBlobPaths(commits, repo.RepoPath(), env)
+	if err != nil {
+		return err
+	}
+	sizes, err := getBlobSizes(blobPaths, repo.RepoPath(), env)
+	if err != nil {
+		return err
+	}
+	files := make(map[string]int64, len(blobPaths))
+	for blobID, paths := range blobPaths {
+		for _, treePath := range paths {
+			if sizes[blobID] >= files[treePath] {
+				files[treePath] = sizes[blobID]
+			}
+		}
+	}
+	return rule.CheckFiles(files)
+}
+
+// getAddedBlobPaths returns the paths of the files added or modified by the commits mapped by their blob ID
+func getAddedBlobPaths(commits []string, repoPath string, env []string) (map[string][]string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	// -m compares merge commits with each of their parents, files added by a merge resolution are missed otherwise
+	if err := git.NewCommand("diff-tree", "-r", "-m", "-z", "--root", "--no-commit-id", "--no-renames", "--diff-filter=d", "--stdin").
+		RunInDirTimeoutEnvFullPipeline(env, -1, repoPath, stdout, stderr, strings.NewReader(strings.Join(commits, "\n")+"\n")); err != nil {
+		return nil, fmt.Errorf("%v - %s", err, stderr)
+	}
+
+	// Every changed file is written as ":<old mode> <new mode> <old sha> <new sha> <status>\0<path>\0"
+	blobPaths := make(map[string][]string)
+	fields := strings.Split(stdout.String(), "\x00")
+	for i := 0; i+1 < len(fields); i++ {
+		if !strings.HasPrefix(fields[i], ":") {
+			continue
+		}
+		meta := strings.Fields(fields[i])
+		treePath := fields[i+1]
+		i++
+		if len(meta) < 4 || meta[1] == "160000" {
+			// submodules do not point to blobs
+			continue
+		}
+		blobPaths[meta[3]] = append(blobPaths[meta[3]], treePath)
+	}
+	return blobPaths, nil
+}
+
+// getBlobSizes returns the sizes of the blobs
+func getBlobSizes(blobPaths map[string][]string, repoPath string, env []string) (map[string]int64, error) {
+	input := new(strings.Builder)
+	for blobID := range blobPaths {
+		input.WriteString(blobID)
+		input.WriteByte('\n')
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if err := git.NewCommand("cat-file", "--batch-check").
+		RunInDirTimeoutEnvFullPipeline(env, -1, repoPath, stdout, stderr, strings.NewReader(input.String())); err != nil {
+		return nil, fmt.Errorf("%v - %s", err, stderr)
+	}
+
+	sizes := make(map[string]int64, len(blobPaths))
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		// <sha> <type> <size>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		sizes[fields[0]] = size
+	}
+	return sizes, scanner.Err()
+}
+
+// PushRuleLFSPatterns returns the patterns to track the oversized files of a rejected push with Git LFS,
+// nothing is returned if the LFS server is disabled
+func PushRuleLFSPatterns(files []*models.OversizedFile) []string {
+	if !setting.LFS.StartServer || len(files) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(files))
+	patterns := make([]string, 0, len(files))
+	for _, f := range files {
+		pattern := f.Path
+		if ext := path.Ext(f.Path); ext != "" && ext != path.Base(f.Path) {
+			pattern = "*" + ext
+		}
+		if !seen[pattern] {
+			seen[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// PushRuleLFSHint returns the git commands to move the oversized files of a rejected push to Git LFS
+func PushRuleLFSHint(files []*models.OversizedFile) string {
+	patterns := PushRuleLFSPatterns(files)
+	if len(patterns) == 0 {
+		return ""
+	}
+	quoted := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		quoted = append(quoted, strconv.Quote(pattern))
+	}
+	return fmt.Sprintf("git lfs track %s\ngit lfs migrate import --include=%s",
+		strings.Join(quoted, " "), strconv.Quote(strings.Join(patterns, ",")))
+}
+
+// UnpackPushObjects unpacks a pack into a temporary object directory and returns the environment git has to run
+// with to find the unpacked objects besides the objects of the repository, like during a push.
+// The returned function removes the unpacked objects.
+func UnpackPushObjects(repo *models.Repository, pack []byte) ([]string, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "gitea-push-check-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		if err := util.RemoveAll(tmpDir); err != nil {
+			log.Error("Unable to remove %s: %v", tmpDir, err)
+		}
+	}
+
+	env := append(os.Environ(),
+		private.GitObjectDirectory+"="+tmpDir,
+		private.GitAlternativeObjectDirectories+"="+filepath.Join(repo.RepoPath(), "objects"),
+	)
+	stderr := new(bytes.Buffer)
+	if err := git.NewCommand("unpack-objects", "-q").
+		RunInDirTimeoutEnvFullPipeline(env, time.Duration(setting.Git.Timeout.Default)*time.Second, repo.RepoPath(), nil, stderr, bytes.NewReader(pack)); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("%v - %s", err, stderr)
+	}
+	return env, cleanup, nil
+}
+
+// IsPushCommitExist returns whether the commit of a pushed ref can be found with the environment
+func IsPushCommitExist(repo *models.Repository, commitID string, env []string) bool {
+	_, err := git.NewCommand("cat-file", "-e", commitID+"^{commit}").RunInDirWithEnv(repo.RepoPath(), env)
+	return err == nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPushRule(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	const master = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	const branch2 = "985f0301dba5e7b34be866819cd15ad3d8f508ee"
+
+	// force push
+	rule := &models.RepoPushRule{RejectForcePush: true}
+	assert.NoError(t, CheckPushRule(repo, rule, master, branch2, "refs/heads/master", os.Environ()))
+	err := CheckPushRule(repo, rule, branch2, master, "refs/heads/branch2", os.Environ())
+	assert.True(t, models.IsErrPushRuleViolated(err))
+	assert.NoError(t, CheckPushRule(repo, rule, branch2, master, "refs/tags/v1", os.Environ()))
+
+	// pushed objects are checked in the temporary object directory
+	tmpDir, err := os.MkdirTemp("", "push-rule")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, git.Clone(repo.RepoPath(), tmpDir, git.CloneRepoOptions{}))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "image.iso"), bytes.Repeat([]byte{'a'}, 2048), 0644))
+	_, err = git.NewCommand("add", "image.iso").RunInDir(tmpDir)
+	assert.NoError(t, err)
+	_, err = git.NewCommand("-c", "user.name=Pusher", "-c", "user.email=pusher@example.com", "commit", "-m", "Add image").RunInDir(tmpDir)
+	assert.NoError(t, err)
+	newCommitID, err := git.NewCommand("rev-parse", "HEAD").RunInDir(tmpDir)
+	assert.NoError(t, err)
+	newCommitID = strings.TrimSpace(newCommitID)
+
+	pack := new(bytes.Buffer)
+	assert.NoError(t, git.NewCommand("pack-objects", "--revs", "--stdout").
+		RunInDirFullPipeline(tmpDir, pack, nil, strings.NewReader(newCommitID+"\n^"+master+"\n")))
+
+	assert.False(t, IsPushCommitExist(repo, newCommitID, os.Environ()))
+	env, cleanup, err := UnpackPushObjects(repo, pack.Bytes())
+	assert.NoError(t, err)
+	defer cleanup()
+	assert.True(t, IsPushCommitExist(repo, newCommitID, env))
+
+	rule = &models.RepoPushRule{MaxFileSize: 1024}
+	err = CheckPushRule(repo, rule, master, newCommitID, "refs/heads/master", env)
+	if assert.True(t, models.IsErrPushRuleViolated(err)) {
+		assert.Equal(t, []*models.OversizedFile{{Path: "image.iso", Size: 2048}}, err.(models.ErrPushRuleViolated).OversizedFiles)
+	}
+
+	rule = &models.RepoPushRule{AuthorEmailDomains: "example.org"}
+	assert.True(t, models.IsErrPushRuleViolated(CheckPushRule(repo, rule, master, newCommitID, "refs/heads/master", env)))
+	rule.AuthorEmailDomains = "example.org,example.com"
+	assert.NoError(t, CheckPushRule(repo, rule, master, newCommitID, "refs/heads/master", env))
+}
+
+func TestCheckPushRuleMergeCommit(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	const master = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	const branch2 = "985f0301dba5e7b34be866819cd15ad3d8f508ee"
+
+	// a merge of branch2 into master which adds a large file in the merge resolution
+	tmpDir, err := os.MkdirTemp("", "push-rule")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, git.Clone(repo.RepoPath(), tmpDir, git.CloneRepoOptions{}))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "image.iso"), bytes.Repeat([]byte{'a'}, 2048), 0644))
+	_, err = git.NewCommand("add", "image.iso").RunInDir(tmpDir)
+	assert.NoError(t, err)
+	treeID, err := git.NewCommand("write-tree").RunInDir(tmpDir)
+	assert.NoError(t, err)
+	mergeCommitID, err := git.NewCommand("-c", "user.name=Pusher", "-c", "user.email=pusher@example.com",
+		"commit-tree", strings.TrimSpace(treeID), "-p", master, "-p", branch2, "-m", "Merge branch2").RunInDir(tmpDir)
+	assert.NoError(t, err)
+	mergeCommitID = strings.TrimSpace(mergeCommitID)
+
+	pack := new(bytes.Buffer)
+	assert.NoError(t, git.NewCommand("pack-objects", "--revs", "--stdout").
+		RunInDirFullPipeline(tmpDir, pack, nil, strings.NewReader(mergeCommitID+"\n^"+master+"\n^"+branch2+"\n")))
+	env, cleanup, err := UnpackPushObjects(repo, pack.Bytes())
+	assert.NoError(t, err)
+	defer cleanup()
+
+	rule := &models.RepoPushRule{MaxFileSize: 1024}
+	err = CheckPushRule(repo, rule, master, mergeCommitID, "refs/heads/master", env)
+	if assert.True(t, models.IsErrPushRuleViolated(err)) {
+		assert.Contains(t, err.(models.ErrPushRuleViolated).OversizedFiles, &models.OversizedFile{Path: "image.iso", Size: 2048})
+	}
+}
+
+func TestPushRuleLFSHint(t *testing.T) {
+	oldStartServer := setting.LFS.StartServer
+	defer func() {
+		setting.LFS.StartServer = oldStartServer
+	}()
+	files := []*models.OversizedFile{{Path: "a/b.iso"}, {Path: "c.iso"}, {Path: "data/blob"}}
+
+	setting.LFS.StartServer = false
+	assert.Empty(t, PushRuleLFSHint(files))
+
+	setting.LFS.StartServer = true
+	assert.Equal(t, []string{"*.iso", "data/blob"}, PushRuleLFSPatterns(files))
+	assert.Equal(t, "git lfs track \"*.iso\" \"data/blob\"\ngit lfs migrate import --include=\"*.iso,data/blob\"", PushRuleLFSHint(files))
+}
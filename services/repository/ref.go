@@ -0,0 +1,116 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/validation"
+)
+
+// ErrRefNameInvalid is returned for refs which are neither valid branch nor tag names
+var ErrRefNameInvalid = errors.New("ref must be a valid branch (refs/heads/...) or tag (refs/tags/...) name")
+
+// NormalizeRefName returns the full name of a branch or tag ref given with or without its refs/ prefix
+func NormalizeRefName(ref string) (string, error) {
+	refName := ref
+	if !strings.HasPrefix(refName, "refs/") {
+		refName = "refs/" + refName
+	}
+	var name string
+	switch {
+	case strings.HasPrefix(refName, git.BranchPrefix):
+		name = refName[len(git.BranchPrefix):]
+	case strings.HasPrefix(refName, git.TagPrefix):
+		name = refName[len(git.TagPrefix):]
+	default:
+		return "", ErrRefNameInvalid
+	}
+	if name == "" || validation.GitRefNamePatternInvalid.MatchString(name) || !validation.CheckGitRefAdditionalRulesValid(name) {
+		return "", ErrRefNameInvalid
+	}
+	return refName, nil
+}
+
+// CreateRef creates a branch or tag ref pointing at a commit. The ref is pushed, so that the
+// pre-receive checks of protected branches and tags and of the push rules apply.
+func CreateRef(doer *models.User, repo *models.Repository, gitRepo *git.Repository, refName, sha string) (string, error) {
+	if strings.HasPrefix(refName, git.BranchPrefix) {
+		if err := repo_module.CheckBranchName(repo, refName[len(git.BranchPrefix):]); err != nil {
+			return "", err
+		}
+	} else if name := refName[len(git.TagPrefix):]; gitRepo.IsTagExist(name) {
+		return "", models.ErrTagAlreadyExists{TagName: name}
+	}
+
+	commit, err := getRefCommit(gitRepo, sha)
+	if err != nil {
+		return "", err
+	}
+	return commit.ID.String(), pushRef(doer, repo, commit.ID.String()+":"+refName, false)
+}
+
+// UpdateRef points an existing branch or tag ref at another commit. Unless forced, branches may only be
+// fast-forwarded and tags not be moved at all. The protected branches may still forbid forced updates.
+func UpdateRef(doer *models.User, repo *models.Repository, gitRepo *git.Repository, refName, sha string, force bool) (string, error) {
+	if !git.IsReferenceExist(repo.RepoPath(), refName) {
+		return "", git.ErrNotExist{ID: refName}
+	}
+	if strings.HasPrefix(refName, git.TagPrefix) && !force {
+		return "", models.ErrTagAlreadyExists{TagName: refName[len(git.TagPrefix):]}
+	}
+
+	commit, err := getRefCommit(gitRepo, sha)
+	if err != nil {
+		return "", err
+	}
+	return commit.ID.String(), pushRef(doer, repo, commit.ID.String()+":"+refName, force)
+}
+
+// DeleteRef deletes a branch or tag ref, except for the default branch
+func DeleteRef(doer *models.User, repo *models.Repository, refName string) error {
+	if !git.IsReferenceExist(repo.RepoPath(), refName) {
+		return git.ErrNotExist{ID: refName}
+	}
+	if refName == git.BranchPrefix+repo.DefaultBranch {
+		return ErrBranchIsDefault
+	}
+	return pushRef(doer, repo, ":"+refName, false)
+}
+
+func getRefCommit(gitRepo *git.Repository, sha string) (*git.Commit, error) {
+	if _, err := git.NewIDFromString(sha); err != nil {
+		return nil, models.ErrSHANotFound{SHA: sha}
+	}
+	commit, err := gitRepo.GetCommit(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, models.ErrSHANotFound{SHA: sha}
+		}
+		return nil, err
+	}
+	return commit, nil
+}
+
+func pushRef(doer *models.User, repo *models.Repository, refspec string, force bool) error {
+	// the repository pushes to itself, which runs the hooks like any other push
+	if err := git.Push(repo.RepoPath(), git.PushOptions{
+		Remote: repo.RepoPath(),
+		Branch: refspec,
+		Force:  force,
+		Env:    models.PushingEnvironment(doer, repo),
+	}); err != nil {
+		if git.IsErrPushOutOfDate(err) || git.IsErrPushRejected(err) {
+			return err
+		}
+		return fmt.Errorf("Push: %v", err)
+	}
+	return nil
+}
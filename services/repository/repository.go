@@ -98,5 +98,9 @@ func PushCreateRepo(authUser, owner *models.User, repoName string) (*models.Repo
 
 // NewContext start repository service
 func NewContext() error {
-	return initPushQueue()
+	if err := initPushQueue(); err != nil {
+		return err
+	}
+	initProtectionTemplateNotifier()
+	return initSettingsJobQueue()
 }
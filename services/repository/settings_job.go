@@ -0,0 +1,413 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// ErrInvalidSettingsPatch is returned when a settings patch can not be applied to any repository
+type ErrInvalidSettingsPatch struct {
+	Reason string
+}
+
+// IsErrInvalidSettingsPatch checks if an error is a ErrInvalidSettingsPatch.
+func IsErrInvalidSettingsPatch(err error) bool {
+	_, ok := err.(ErrInvalidSettingsPatch)
+	return ok
+}
+
+func (err ErrInvalidSettingsPatch) Error() string {
+	return fmt.Sprintf("invalid settings patch: %s", err.Reason)
+}
+
+// settingsJobQueue applies the settings patches of bulk updates
+var settingsJobQueue queue.Queue
+
+// SettingsJobTask is the bulk settings update queued to be applied
+type SettingsJobTask struct {
+	JobID int64
+}
+
+func initSettingsJobQueue() error {
+	settingsJobQueue = queue.CreateQueue("repo_settings_job", func(data ...queue.Data) {
+		for _, datum := range data {
+			task := datum.(*SettingsJobTask)
+			if err := runSettingsJob(task.JobID); err != nil {
+				log.Error("Repository settings job %d failed: %v", task.JobID, err)
+			}
+		}
+	}, &SettingsJobTask{})
+	if settingsJobQueue == nil {
+		return errors.New("unable to create repo_settings_job queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(settingsJobQueue.Run)
+	return nil
+}
+
+// CreateSettingsJob queues the update of the settings of repositories of an organization,
+// all of its repositories are updated if no name is given
+func CreateSettingsJob(doer, org *models.User, names []string, patch *api.RepoSettingsPatch) (*models.RepoSettingsJob, error) {
+	if err := validateSettingsPatch(patch); err != nil {
+		return nil, err
+	}
+	if patch.BranchProtection != nil {
+		// unknown users and teams are reported now rather than for each repository
+		if _, err := getProtectionWhitelists(org, patch.BranchProtection); err != nil {
+			return nil, err
+		}
+	}
+	if patch.BranchProtectionTemplate != nil {
+		if _, err := models.GetBranchProtectionTemplateByName(org.ID, *patch.BranchProtectionTemplate); err != nil {
+			if models.IsErrBranchProtectionTemplateNotExist(err) {
+				return nil, ErrInvalidSettingsPatch{Reason: fmt.Sprintf("unknown branch protection template: %s", *patch.BranchProtectionTemplate)}
+			}
+			return nil, err
+		}
+	}
+
+	lowerNames := make([]string, 0, len(names))
+	for _, name := range names {
+		lowerNames = append(lowerNames, strings.ToLower(name))
+	}
+	repos, err := models.GetOwnerRepositoriesByNames(org.ID, lowerNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(repos) < len(lowerNames) {
+		found := make(map[string]bool, len(repos))
+		for _, repo := range repos {
+			found[repo.LowerName] = true
+		}
+		for i, lowerName := range lowerNames {
+			if !found[lowerName] {
+				return nil, models.ErrRepoNotExist{UID: org.ID, OwnerName: org.Name, Name: names[i]}
+			}
+		}
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	job := &models.RepoSettingsJob{
+		OrgID:  org.ID,
+		DoerID: doer.ID,
+		Patch:  string(data),
+	}
+	if err := models.CreateRepoSettingsJob(job, repos); err != nil {
+		return nil, err
+	}
+	if err := settingsJobQueue.Push(&SettingsJobTask{JobID: job.ID}); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func validateSettingsPatch(patch *api.RepoSettingsPatch) error {
+	if patch.DefaultMergeStyle != nil {
+		switch models.MergeStyle(*patch.DefaultMergeStyle) {
+		case models.MergeStyleMerge, models.MergeStyleRebase, models.MergeStyleRebaseMerge, models.MergeStyleSquash:
+		default:
+			return ErrInvalidSettingsPatch{Reason: fmt.Sprintf("unknown merge style: %s", *patch.DefaultMergeStyle)}
+		}
+	}
+	if patch.BranchProtection != nil && patch.BranchProtection.BranchName == "" {
+		return ErrInvalidSettingsPatch{Reason: "branch_name of branch_protection is required"}
+	}
+	return nil
+}
+
+// getProtectionWhitelists returns the ids of the users and teams of an organization whitelisted by a branch protection
+func getProtectionWhitelists(org *models.User, opt *api.CreateBranchProtectionOption) (models.WhitelistOptions, error) {
+	return getWhitelistOptions(org.ID,
+		opt.PushWhitelistUsernames, opt.PushWhitelistTeams,
+		opt.MergeWhitelistUsernames, opt.MergeWhitelistTeams,
+		opt.ApprovalsWhitelistUsernames, opt.ApprovalsWhitelistTeams)
+}
+
+// getWhitelistOptions returns the ids of whitelisted users and teams of an organization given by their names
+func getWhitelistOptions(orgID int64, pushUsers, pushTeams, mergeUsers, mergeTeams, approvalsUsers, approvalsTeams []string) (opts models.WhitelistOptions, err error) {
+	if opts.UserIDs, err = models.GetUserIDsByNames(pushUsers, false); err != nil {
+		return opts, err
+	}
+	if opts.MergeUserIDs, err = models.GetUserIDsByNames(mergeUsers, false); err != nil {
+		return opts, err
+	}
+	if opts.ApprovalsUserIDs, err = models.GetUserIDsByNames(approvalsUsers, false); err != nil {
+		return opts, err
+	}
+	if opts.TeamIDs, err = models.GetTeamIDsByNames(orgID, pushTeams, false); err != nil {
+		return opts, err
+	}
+	if opts.MergeTeamIDs, err = models.GetTeamIDsByNames(orgID, mergeTeams, false); err != nil {
+		return opts, err
+	}
+	opts.ApprovalsTeamIDs, err = models.GetTeamIDsByNames(orgID, approvalsTeams, false)
+	return opts, err
+}
+
+func runSettingsJob(jobID int64) error {
+	job, err := models.GetRepoSettingsJobByID(0, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == models.RepoSettingsJobStatusFinished {
+		return nil
+	}
+
+	job.Status = models.RepoSettingsJobStatusRunning
+	if err := models.UpdateRepoSettingsJobStatus(job); err != nil {
+		return err
+	}
+	if err := job.LoadResults(); err != nil {
+		return err
+	}
+
+	patch, err := loadSettingsPatch(job)
+
+	for _, result := range job.Results {
+		if result.Status != models.RepoSettingsResultStatusPending {
+			continue
+		}
+		if err != nil {
+			// the patch can not be applied to any repository
+			result.Status = models.RepoSettingsResultStatusFailed
+			result.Message = err.Error()
+		} else {
+			applySettingsResult(result, patch)
+		}
+		if err := models.UpdateRepoSettingsResult(result); err != nil {
+			return err
+		}
+	}
+
+	job.Status = models.RepoSettingsJobStatusFinished
+	return models.UpdateRepoSettingsJobStatus(job)
+}
+
+// settingsPatch is a settings patch with the whitelists and the template it refers to
+type settingsPatch struct {
+	*api.RepoSettingsPatch
+	whitelists models.WhitelistOptions
+	template   *models.BranchProtectionTemplate
+}
+
+func loadSettingsPatch(job *models.RepoSettingsJob) (*settingsPatch, error) {
+	patch := &settingsPatch{RepoSettingsPatch: &api.RepoSettingsPatch{}}
+	if err := json.Unmarshal([]byte(job.Patch), patch.RepoSettingsPatch); err != nil {
+		return nil, err
+	}
+	if patch.BranchProtection == nil && patch.BranchProtectionTemplate == nil {
+		return patch, nil
+	}
+
+	org, err := models.GetUserByID(job.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if patch.BranchProtection != nil {
+		if patch.whitelists, err = getProtectionWhitelists(org, patch.BranchProtection); err != nil {
+			return nil, err
+		}
+	}
+	if patch.BranchProtectionTemplate != nil {
+		if patch.template, err = models.GetBranchProtectionTemplateByName(org.ID, *patch.BranchProtectionTemplate); err != nil {
+			return nil, err
+		}
+	}
+	return patch, nil
+}
+
+// applySettingsResult applies the patch to the repository of a result and records the outcome
+func applySettingsResult(result *models.RepoSettingsResult, patch *settingsPatch) {
+	repo, err := models.GetRepositoryByID(result.RepoID)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			result.Status = models.RepoSettingsResultStatusSkipped
+			result.Message = "repository has been deleted"
+			return
+		}
+		result.Status = models.RepoSettingsResultStatusFailed
+		result.Message = err.Error()
+		return
+	}
+	if repo.IsArchived {
+		result.Status = models.RepoSettingsResultStatusSkipped
+		result.Message = "repository is archived"
+		return
+	}
+
+	message, err := applySettingsPatch(repo, patch)
+	if err != nil {
+		log.Error("Unable to apply settings patch to %s: %v", repo.FullName(), err)
+		result.Status = models.RepoSettingsResultStatusFailed
+		result.Message = err.Error()
+		return
+	}
+	result.Status = models.RepoSettingsResultStatusSuccess
+	result.Message = message
+}
+
+// applySettingsPatch applies the patch to a repository, the message tells which parts of the patch do not apply to it
+func applySettingsPatch(repo *models.Repository, patch *settingsPatch) (string, error) {
+	var units []models.RepoUnit
+	var deleteUnitTypes []models.UnitType
+
+	if patch.HasIssues != nil && !models.UnitTypeIssues.UnitGlobalDisabled() {
+		if *patch.HasIssues {
+			config := &models.IssuesConfig{
+				EnableTimetracker:                true,
+				AllowOnlyContributorsToTrackTime: true,
+				EnableDependencies:               true,
+			}
+			if unit, err := repo.GetUnit(models.UnitTypeIssues); err == nil {
+				config = unit.IssuesConfig()
+			}
+			units = append(units, models.RepoUnit{RepoID: repo.ID, Type: models.UnitTypeIssues, Config: config})
+		} else {
+			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeIssues)
+		}
+		deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalTracker)
+	}
+
+	if patch.HasWiki != nil && !models.UnitTypeWiki.UnitGlobalDisabled() {
+		if *patch.HasWiki {
+			units = append(units, models.RepoUnit{RepoID: repo.ID, Type: models.UnitTypeWiki, Config: &models.UnitConfig{}})
+		} else {
+			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeWiki)
+		}
+		deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalWiki)
+	}
+
+	if !models.UnitTypePullRequests.UnitGlobalDisabled() {
+		unit, err := repo.GetUnit(models.UnitTypePullRequests)
+		if patch.HasPullRequests != nil && !*patch.HasPullRequests {
+			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypePullRequests)
+		} else if err == nil || patch.HasPullRequests != nil {
+			var config *models.PullRequestsConfig
+			if err != nil {
+				config = &models.PullRequestsConfig{
+					AllowMerge:        true,
+					AllowRebase:       true,
+					AllowRebaseMerge:  true,
+					AllowSquash:       true,
+					AllowManualMerge:  true,
+					DefaultMergeStyle: models.MergeStyleMerge,
+				}
+			} else {
+				config = unit.PullRequestsConfig()
+			}
+			if applyPullRequestsPatch(config, patch.RepoSettingsPatch) || err != nil {
+				units = append(units, models.RepoUnit{RepoID: repo.ID, Type: models.UnitTypePullRequests, Config: config})
+			}
+		}
+	}
+
+	if patch.HasProjects != nil && !models.UnitTypeProjects.UnitGlobalDisabled() {
+		if *patch.HasProjects {
+			units = append(units, models.RepoUnit{RepoID: repo.ID, Type: models.UnitTypeProjects})
+		} else {
+			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeProjects)
+		}
+	}
+
+	if len(units) > 0 || len(deleteUnitTypes) > 0 {
+		if err := models.UpdateRepositoryUnits(repo, units, deleteUnitTypes); err != nil {
+			return "", fmt.Errorf("UpdateRepositoryUnits: %v", err)
+		}
+	}
+
+	if patch.template != nil {
+		if err := ApplyProtectionTemplate(patch.template, repo); err != nil {
+			return "", fmt.Errorf("ApplyProtectionTemplate: %v", err)
+		}
+	}
+
+	if patch.BranchProtection == nil {
+		return "", nil
+	}
+	if !git.IsBranchExist(repo.RepoPath(), patch.BranchProtection.BranchName) {
+		return fmt.Sprintf("branch %s does not exist, the branch protection was not created", patch.BranchProtection.BranchName), nil
+	}
+	return "", protectBranchFromOption(repo, patch.BranchProtection, patch.whitelists)
+}
+
+// applyPullRequestsPatch sets the merge settings of the patch, it returns true if any is set
+func applyPullRequestsPatch(config *models.PullRequestsConfig, patch *api.RepoSettingsPatch) bool {
+	changed := false
+	set := func(dst *bool, src *bool) {
+		if src != nil {
+			*dst = *src
+			changed = true
+		}
+	}
+	set(&config.AllowMerge, patch.AllowMerge)
+	set(&config.AllowRebase, patch.AllowRebase)
+	set(&config.AllowRebaseMerge, patch.AllowRebaseMerge)
+	set(&config.AllowSquash, patch.AllowSquash)
+	set(&config.DefaultDeleteBranchAfterMerge, patch.DefaultDeleteBranchAfterMerge)
+	if patch.DefaultMergeStyle != nil {
+		config.DefaultMergeStyle = models.MergeStyle(*patch.DefaultMergeStyle)
+		changed = true
+	}
+	return changed
+}
+
+// protectBranchFromOption creates or replaces the protection of a branch of a repository
+func protectBranchFromOption(repo *models.Repository, opt *api.CreateBranchProtectionOption, whitelists models.WhitelistOptions) error {
+	protectBranch, err := models.GetProtectedBranchBy(repo.ID, opt.BranchName)
+	if err != nil {
+		return err
+	}
+	if protectBranch == nil {
+		protectBranch = &models.ProtectedBranch{
+			RepoID:     repo.ID,
+			BranchName: opt.BranchName,
+		}
+	}
+
+	var requiredApprovals int64
+	if opt.RequiredApprovals > 0 {
+		requiredApprovals = opt.RequiredApprovals
+	}
+	var statusCheckMaxAge int64
+	if opt.StatusCheckMaxAge > 0 {
+		statusCheckMaxAge = opt.StatusCheckMaxAge
+	}
+	protectBranch.CanPush = opt.EnablePush
+	protectBranch.EnableWhitelist = opt.EnablePush && opt.EnablePushWhitelist
+	protectBranch.EnableMergeWhitelist = opt.EnableMergeWhitelist
+	protectBranch.WhitelistDeployKeys = opt.EnablePush && opt.EnablePushWhitelist && opt.PushWhitelistDeployKeys
+	protectBranch.EnableStatusCheck = opt.EnableStatusCheck
+	protectBranch.StatusCheckContexts = opt.StatusCheckContexts
+	protectBranch.StatusCheckMaxAge = statusCheckMaxAge
+	protectBranch.EnableApprovalsWhitelist = opt.EnableApprovalsWhitelist
+	protectBranch.RequiredApprovals = requiredApprovals
+	protectBranch.BlockOnRejectedReviews = opt.BlockOnRejectedReviews
+	protectBranch.BlockOnOfficialReviewRequests = opt.BlockOnOfficialReviewRequests
+	protectBranch.DismissStaleApprovals = opt.DismissStaleApprovals
+	protectBranch.RequireSignedCommits = opt.RequireSignedCommits
+	protectBranch.ProtectedFilePatterns = opt.ProtectedFilePatterns
+	protectBranch.UnprotectedFilePatterns = opt.UnprotectedFilePatterns
+	protectBranch.BlockOnOutdatedBranch = opt.BlockOnOutdatedBranch
+
+	if err := models.UpdateProtectBranch(repo, protectBranch, whitelists); err != nil {
+		return fmt.Errorf("UpdateProtectBranch: %v", err)
+	}
+	return pull_service.CheckPrsForBaseBranch(repo, protectBranch.BranchName)
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSettingsJob(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo3 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	repo32 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 32}).(*models.Repository)
+	deleted := &models.Repository{ID: 9999, Name: "deleted"}
+
+	job := &models.RepoSettingsJob{
+		OrgID:  3,
+		DoerID: 2,
+		Patch:  `{"has_wiki":false,"default_merge_style":"squash"}`,
+	}
+	assert.NoError(t, models.CreateRepoSettingsJob(job, []*models.Repository{repo3, repo32, deleted}))
+	assert.NoError(t, runSettingsJob(job.ID))
+
+	job, err := models.GetRepoSettingsJobByID(3, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RepoSettingsJobStatusFinished, job.Status)
+	assert.NotZero(t, job.FinishedUnix)
+	assert.NoError(t, job.LoadResults())
+	if assert.Len(t, job.Results, 3) {
+		assert.Equal(t, models.RepoSettingsResultStatusSuccess, job.Results[0].Status)
+		assert.Equal(t, models.RepoSettingsResultStatusSuccess, job.Results[1].Status)
+		assert.Equal(t, models.RepoSettingsResultStatusSkipped, job.Results[2].Status)
+	}
+
+	db.AssertNotExistsBean(t, &models.RepoUnit{RepoID: 3, Type: models.UnitTypeWiki})
+	unit := db.AssertExistsAndLoadBean(t, &models.RepoUnit{RepoID: 3, Type: models.UnitTypePullRequests}).(*models.RepoUnit)
+	assert.Equal(t, models.MergeStyleSquash, unit.PullRequestsConfig().DefaultMergeStyle)
+	assert.True(t, unit.PullRequestsConfig().IgnoreWhitespaceConflicts)
+	// pull requests are not enabled by setting their merge style
+	db.AssertNotExistsBean(t, &models.RepoUnit{RepoID: 32, Type: models.UnitTypePullRequests})
+}
+
+func TestCreateSettingsJobValidation(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	org := db.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User)
+
+	style := "fast-forward"
+	_, err := CreateSettingsJob(doer, org, nil, &api.RepoSettingsPatch{DefaultMergeStyle: &style})
+	assert.True(t, IsErrInvalidSettingsPatch(err))
+
+	_, err = CreateSettingsJob(doer, org, nil, &api.RepoSettingsPatch{BranchProtection: &api.CreateBranchProtectionOption{}})
+	assert.True(t, IsErrInvalidSettingsPatch(err))
+
+	_, err = CreateSettingsJob(doer, org, nil, &api.RepoSettingsPatch{BranchProtection: &api.CreateBranchProtectionOption{
+		BranchName:         "master",
+		PushWhitelistTeams: []string{"no-such-team"},
+	}})
+	assert.True(t, models.IsErrTeamNotExist(err))
+
+	_, err = CreateSettingsJob(doer, org, []string{"repo3", "no-such-repo"}, &api.RepoSettingsPatch{})
+	assert.True(t, models.IsErrRepoNotExist(err))
+}
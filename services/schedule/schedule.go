@@ -0,0 +1,97 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// catchUpGracePeriod is the delay after which a run of a schedule with catch up policy none counts as missed
+const catchUpGracePeriod = 5 * time.Minute
+
+// maxCatchUpRuns limits the number of missed runs which are fired for a schedule with catch up policy all
+const maxCatchUpRuns = 50
+
+// Run fires the events of all due schedules
+func Run(ctx context.Context) error {
+	now := time.Now()
+	schedules, err := models.FindDueRepoSchedules(now)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+		}
+
+		if err := fire(schedule, now); err != nil {
+			log.Error("Unable to fire schedule %d of repository %d: %v", schedule.ID, schedule.RepoID, err)
+		}
+	}
+	return nil
+}
+
+// fire fires the events of a due schedule according to its catch up policy and computes its next run
+func fire(schedule *models.RepoSchedule, now time.Time) error {
+	missed, err := schedule.MissedRuns(now, maxCatchUpRuns)
+	if err != nil {
+		return err
+	}
+
+	runs := runsToFire(schedule.CatchUp, missed, now)
+	if len(runs) > 0 {
+		repo, err := models.GetRepositoryByID(schedule.RepoID)
+		if err != nil {
+			return err
+		}
+		if !repo.IsArchived {
+			doer, err := models.GetUserByID(schedule.CreatorID)
+			if err != nil {
+				if !models.IsErrUserNotExist(err) {
+					return err
+				}
+				doer = models.NewGhostUser()
+			}
+			for _, t := range runs {
+				notification.NotifyScheduleFired(doer, repo, schedule, t)
+			}
+		}
+		schedule.LastRunUnix = timeutil.TimeStamp(runs[len(runs)-1].Unix())
+	}
+
+	if err := schedule.UpdateNextRun(now); err != nil {
+		return err
+	}
+	return models.UpdateRepoScheduleRuns(schedule)
+}
+
+// runsToFire selects the runs to fire out of the missed runs
+func runsToFire(policy models.ScheduleCatchUpPolicy, missed []time.Time, now time.Time) []time.Time {
+	if len(missed) == 0 {
+		return nil
+	}
+	latest := missed[len(missed)-1]
+	switch policy {
+	case models.ScheduleCatchUpAll:
+		return missed
+	case models.ScheduleCatchUpOnce:
+		return []time.Time{latest}
+	default:
+		if now.Sub(latest) > catchUpGracePeriod {
+			return nil
+		}
+		return []time.Time{latest}
+	}
+}
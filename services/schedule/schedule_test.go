@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsToFire(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	recent := []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour), now.Add(-time.Minute)}
+	old := []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)}
+
+	assert.Nil(t, runsToFire(models.ScheduleCatchUpNone, nil, now))
+
+	assert.Equal(t, []time.Time{now.Add(-time.Minute)}, runsToFire(models.ScheduleCatchUpNone, recent, now))
+	assert.Nil(t, runsToFire(models.ScheduleCatchUpNone, old, now))
+
+	assert.Equal(t, []time.Time{now.Add(-time.Minute)}, runsToFire(models.ScheduleCatchUpOnce, recent, now))
+	assert.Equal(t, []time.Time{now.Add(-time.Hour)}, runsToFire(models.ScheduleCatchUpOnce, old, now))
+
+	assert.Equal(t, old, runsToFire(models.ScheduleCatchUpAll, old, now))
+}
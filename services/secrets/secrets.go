@@ -0,0 +1,92 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package secrets provides the decrypted values of stored secrets to the subsystems which are allowed to use them.
+// The API never returns secret values. Every access is recorded in the log.
+package secrets
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Mask replaces secret values in content
+const Mask = "***"
+
+var referencePattern = regexp.MustCompile(`^\$\{\{\s*secrets\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}$`)
+
+// ParseReference returns the name of the secret if value is a reference of the form ${{ secrets.NAME }}
+func ParseReference(value string) (string, bool) {
+	m := referencePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return "", false
+	}
+	return strings.ToUpper(m[1]), true
+}
+
+// GetRepositorySecrets returns the values of all secrets available to the repository.
+// Secrets of the repository override secrets of the owner with the same name.
+func GetRepositorySecrets(repo *models.Repository, accessor string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, scope := range []struct{ ownerID, repoID int64 }{{repo.OwnerID, 0}, {0, repo.ID}} {
+		secrets, _, err := models.FindSecrets(&models.FindSecretsOptions{OwnerID: scope.ownerID, RepoID: scope.repoID})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range secrets {
+			value, err := s.Decrypt()
+			if err != nil {
+				return nil, err
+			}
+			values[s.Name] = value
+		}
+	}
+
+	if len(values) > 0 {
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		log.Info("Secrets %s of repository %s accessed by %s", strings.Join(names, ", "), repo.FullName(), accessor)
+	}
+	return values, nil
+}
+
+// GetSecret returns the value of a secret of a repository or, if repoID is 0 or the repository has no such secret, of the owner
+func GetSecret(ownerID, repoID int64, name, accessor string) (string, error) {
+	var s *models.Secret
+	var err error
+	if repoID > 0 {
+		s, err = models.GetSecret(0, repoID, name)
+	}
+	if repoID == 0 || err == models.ErrSecretNotExist {
+		s, err = models.GetSecret(ownerID, 0, name)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value, err := s.Decrypt()
+	if err != nil {
+		return "", err
+	}
+	log.Info("Secret %s [owner: %d, repo: %d] accessed by %s", s.Name, s.OwnerID, s.RepoID, accessor)
+	return value, nil
+}
+
+// MaskValues replaces all occurrences of the secret values in content
+func MaskValues(content string, values map[string]string) string {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		content = strings.ReplaceAll(content, value, Mask)
+	}
+	return content
+}
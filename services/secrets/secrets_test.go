@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	name, ok := ParseReference("${{ secrets.hook_secret }}")
+	assert.True(t, ok)
+	assert.Equal(t, "HOOK_SECRET", name)
+
+	name, ok = ParseReference("${{secrets.TOKEN}}")
+	assert.True(t, ok)
+	assert.Equal(t, "TOKEN", name)
+
+	for _, value := range []string{"", "secret", "${{ secrets.A }} suffix", "${{ vars.A }}"} {
+		_, ok = ParseReference(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestMaskValues(t *testing.T) {
+	assert.Equal(t, "user *** token ***", MaskValues("user s3cr3t token abc", map[string]string{"A": "s3cr3t", "B": "abc", "C": ""}))
+}
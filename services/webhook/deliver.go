@@ -26,9 +26,33 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/proxy"
 	"code.gitea.io/gitea/modules/setting"
+	secrets_service "code.gitea.io/gitea/services/secrets"
 	"github.com/gobwas/glob"
 )
 
+// getHookSecret returns the secret which is used to sign the payload.
+// The secret of a webhook can reference a stored secret of its repository or organization with ${{ secrets.NAME }}.
+func getHookSecret(w *models.Webhook) (string, error) {
+	name, ok := secrets_service.ParseReference(w.Secret)
+	if !ok {
+		return w.Secret, nil
+	}
+
+	ownerID := w.OrgID
+	if w.RepoID > 0 {
+		repo, err := models.GetRepositoryByID(w.RepoID)
+		if err != nil {
+			return "", err
+		}
+		ownerID = repo.OwnerID
+	}
+	value, err := secrets_service.GetSecret(ownerID, w.RepoID, name, fmt.Sprintf("webhook %d", w.ID))
+	if err != nil {
+		return "", fmt.Errorf("Unable to get secret %s for webhook %d: %v", name, w.ID, err)
+	}
+	return value, nil
+}
+
 // Deliver deliver hook task
 func Deliver(t *models.HookTask) error {
 	w, err := models.GetWebhookByID(t.HookID)
@@ -100,11 +124,16 @@ func Deliver(t *models.HookTask) error {
 		return fmt.Errorf("Invalid http method for webhook: [%d] %v", t.ID, w.HTTPMethod)
 	}
 
+	hookSecret, err := getHookSecret(w)
+	if err != nil {
+		return err
+	}
+
 	var signatureSHA1 string
 	var signatureSHA256 string
-	if len(w.Secret) > 0 {
-		sig1 := hmac.New(sha1.New, []byte(w.Secret))
-		sig256 := hmac.New(sha256.New, []byte(w.Secret))
+	if len(hookSecret) > 0 {
+		sig1 := hmac.New(sha1.New, []byte(hookSecret))
+		sig256 := hmac.New(sha256.New, []byte(hookSecret))
 		_, err = io.MultiWriter(sig1, sig256).Write([]byte(t.PayloadContent))
 		if err != nil {
 			log.Error("prepareWebhooks.sigWrite: %v", err)
@@ -156,6 +156,9 @@ func (d *DingtalkPayload) Repository(p *api.RepositoryPayload) (api.Payloader, e
 				Content: title,
 			},
 		}, nil
+	case api.HookRepoRenamed, api.HookRepoTransferred:
+		title := fmt.Sprintf("[%s] Repository %s from %s", p.Repository.FullName, p.Action, p.Changes.FullName.From)
+		return createDingtalkPayload(title, title, "view repository", p.Repository.HTMLURL), nil
 	}
 
 	return nil, nil
@@ -168,6 +171,13 @@ func (d *DingtalkPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	return createDingtalkPayload(text, text, "view release", p.Release.URL), nil
 }
 
+// Schedule implements PayloadConvertor Schedule method
+func (d *DingtalkPayload) Schedule(p *api.SchedulePayload) (api.Payloader, error) {
+	title := fmt.Sprintf("[%s] Schedule %s fired", p.Repository.FullName, p.Schedule.Name)
+
+	return createDingtalkPayload(title, title, "view repository", p.Repository.HTMLURL), nil
+}
+
 func createDingtalkPayload(title, text, singleTitle, singleURL string) *DingtalkPayload {
 	return &DingtalkPayload{
 		MsgType: "actionCard",
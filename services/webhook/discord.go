@@ -231,6 +231,10 @@ func (d *DiscordPayload) Repository(p *api.RepositoryPayload) (api.Payloader, er
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		color = redColor
+	case api.HookRepoRenamed, api.HookRepoTransferred:
+		title = fmt.Sprintf("[%s] Repository %s from %s", p.Repository.FullName, p.Action, p.Changes.FullName.From)
+		url = p.Repository.HTMLURL
+		color = yellowColor
 	}
 
 	return d.createPayload(p.Sender, title, "", url, color), nil
@@ -243,6 +247,13 @@ func (d *DiscordPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	return d.createPayload(p.Sender, text, p.Release.Note, p.Release.URL, color), nil
 }
 
+// Schedule implements PayloadConvertor Schedule method
+func (d *DiscordPayload) Schedule(p *api.SchedulePayload) (api.Payloader, error) {
+	title := fmt.Sprintf("[%s] Schedule %s fired", p.Repository.FullName, p.Schedule.Name)
+
+	return d.createPayload(p.Sender, title, "", p.Repository.HTMLURL, greyColor), nil
+}
+
 // GetDiscordPayload converts a discord webhook into a DiscordPayload
 func GetDiscordPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	s := new(DiscordPayload)
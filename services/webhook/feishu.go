@@ -142,6 +142,9 @@ func (f *FeishuPayload) Repository(p *api.RepositoryPayload) (api.Payloader, err
 	case api.HookRepoDeleted:
 		text = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		return newFeishuTextPayload(text), nil
+	case api.HookRepoRenamed, api.HookRepoTransferred:
+		text = fmt.Sprintf("[%s] Repository %s from %s", p.Repository.FullName, p.Action, p.Changes.FullName.From)
+		return newFeishuTextPayload(text), nil
 	}
 
 	return nil, nil
@@ -154,6 +157,13 @@ func (f *FeishuPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	return newFeishuTextPayload(text), nil
 }
 
+// Schedule implements PayloadConvertor Schedule method
+func (f *FeishuPayload) Schedule(p *api.SchedulePayload) (api.Payloader, error) {
+	text := fmt.Sprintf("[%s] Schedule %s fired", p.Repository.FullName, p.Schedule.Name)
+
+	return newFeishuTextPayload(text), nil
+}
+
 // GetFeishuPayload converts a ding talk webhook into a FeishuPayload
 func GetFeishuPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(FeishuPayload), p, event)
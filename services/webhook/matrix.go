@@ -150,6 +150,14 @@ func (m *MatrixPayloadUnsafe) Release(p *api.ReleasePayload) (api.Payloader, err
 	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
 }
 
+// Schedule implements PayloadConvertor Schedule method
+func (m *MatrixPayloadUnsafe) Schedule(p *api.SchedulePayload) (api.Payloader, error) {
+	repoLink := MatrixLinkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	text := fmt.Sprintf("[%s] Schedule %s fired", repoLink, p.Schedule.Name)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
 // Push implements PayloadConvertor Push method
 func (m *MatrixPayloadUnsafe) Push(p *api.PushPayload) (api.Payloader, error) {
 	var commitDesc string
@@ -216,6 +224,8 @@ func (m *MatrixPayloadUnsafe) Repository(p *api.RepositoryPayload) (api.Payloade
 		text = fmt.Sprintf("[%s] Repository created by %s", repoLink, senderLink)
 	case api.HookRepoDeleted:
 		text = fmt.Sprintf("[%s] Repository deleted by %s", repoLink, senderLink)
+	case api.HookRepoRenamed, api.HookRepoTransferred:
+		text = fmt.Sprintf("[%s] Repository %s from %s by %s", repoLink, p.Action, p.Changes.FullName.From, senderLink)
 	}
 
 	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
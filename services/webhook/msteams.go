@@ -254,6 +254,10 @@ func (m *MSTeamsPayload) Repository(p *api.RepositoryPayload) (api.Payloader, er
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		color = yellowColor
+	case api.HookRepoRenamed, api.HookRepoTransferred:
+		title = fmt.Sprintf("[%s] Repository %s from %s", p.Repository.FullName, p.Action, p.Changes.FullName.From)
+		url = p.Repository.HTMLURL
+		color = yellowColor
 	}
 
 	return createMSTeamsPayload(
@@ -282,6 +286,21 @@ func (m *MSTeamsPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	), nil
 }
 
+// Schedule implements PayloadConvertor Schedule method
+func (m *MSTeamsPayload) Schedule(p *api.SchedulePayload) (api.Payloader, error) {
+	title := fmt.Sprintf("[%s] Schedule %s fired", p.Repository.FullName, p.Schedule.Name)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.Repository.HTMLURL,
+		greyColor,
+		&MSTeamsFact{"Schedule:", p.Schedule.Name},
+	), nil
+}
+
 // GetMSTeamsPayload converts a MSTeams webhook into a MSTeamsPayload
 func GetMSTeamsPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(MSTeamsPayload), p, event)
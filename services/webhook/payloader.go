@@ -22,6 +22,7 @@ type PayloadConvertor interface {
 	Review(*api.PullRequestPayload, models.HookEventType) (api.Payloader, error)
 	Repository(*api.RepositoryPayload) (api.Payloader, error)
 	Release(*api.ReleasePayload) (api.Payloader, error)
+	Schedule(*api.SchedulePayload) (api.Payloader, error)
 }
 
 func convertPayloader(s PayloadConvertor, p api.Payloader, event models.HookEventType) (api.Payloader, error) {
@@ -51,6 +52,8 @@ func convertPayloader(s PayloadConvertor, p api.Payloader, event models.HookEven
 		return s.Repository(p.(*api.RepositoryPayload))
 	case models.HookEventRelease:
 		return s.Release(p.(*api.ReleasePayload))
+	case models.HookEventSchedule:
+		return s.Schedule(p.(*api.SchedulePayload))
 	}
 	return s, nil
 }
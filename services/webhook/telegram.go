@@ -169,6 +169,9 @@ func (t *TelegramPayload) Repository(p *api.RepositoryPayload) (api.Payloader, e
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		return createTelegramPayload(title), nil
+	case api.HookRepoRenamed, api.HookRepoTransferred:
+		title = fmt.Sprintf(`[<a href="%s">%s</a>] Repository %s from %s`, p.Repository.HTMLURL, p.Repository.FullName, p.Action, p.Changes.FullName.From)
+		return createTelegramPayload(title), nil
 	}
 	return nil, nil
 }
@@ -180,6 +183,13 @@ func (t *TelegramPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	return createTelegramPayload(text), nil
 }
 
+// Schedule implements PayloadConvertor Schedule method
+func (t *TelegramPayload) Schedule(p *api.SchedulePayload) (api.Payloader, error) {
+	title := fmt.Sprintf(`[<a href="%s">%s</a>] Schedule %s fired`, p.Repository.HTMLURL, p.Repository.FullName, p.Schedule.Name)
+
+	return createTelegramPayload(title), nil
+}
+
 // GetTelegramPayload converts a telegram webhook into a TelegramPayload
 func GetTelegramPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(TelegramPayload), p, event)
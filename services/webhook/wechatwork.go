@@ -170,6 +170,9 @@ func (f *WechatworkPayload) Repository(p *api.RepositoryPayload) (api.Payloader,
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		return newWechatworkMarkdownPayload(title), nil
+	case api.HookRepoRenamed, api.HookRepoTransferred:
+		title = fmt.Sprintf("[%s] Repository %s from %s", p.Repository.FullName, p.Action, p.Changes.FullName.From)
+		return newWechatworkMarkdownPayload(title), nil
 	}
 
 	return nil, nil
@@ -182,6 +185,13 @@ func (f *WechatworkPayload) Release(p *api.ReleasePayload) (api.Payloader, error
 	return newWechatworkMarkdownPayload(text), nil
 }
 
+// Schedule implements PayloadConvertor Schedule method
+func (f *WechatworkPayload) Schedule(p *api.SchedulePayload) (api.Payloader, error) {
+	title := fmt.Sprintf("[%s] Schedule %s fired", p.Repository.FullName, p.Schedule.Name)
+
+	return newWechatworkMarkdownPayload(title), nil
+}
+
 // GetWechatworkPayload GetWechatworkPayload converts a ding talk webhook into a WechatworkPayload
 func GetWechatworkPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(WechatworkPayload), p, event)
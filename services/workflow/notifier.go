@@ -0,0 +1,103 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	workflow_module "code.gitea.io/gitea/modules/workflow"
+)
+
+type workflowNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &workflowNotifier{}
+)
+
+// Init registers the notifier which triggers workflows if workflows are enabled
+func Init() {
+	if !setting.Workflow.Enabled {
+		return
+	}
+	notification.RegisterNotifier(&workflowNotifier{})
+}
+
+func (*workflowNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if opts.IsDelRef() {
+		return
+	}
+	if err := TriggerWorkflows(&TriggerOptions{
+		Repo:      repo,
+		Doer:      pusher,
+		Event:     workflow_module.EventPush,
+		Ref:       opts.RefFullName,
+		FilterRef: opts.RefFullName,
+		CommitSHA: opts.NewCommitID,
+	}); err != nil {
+		log.Error("TriggerWorkflows [repo: %d, ref: %s]: %v", repo.ID, opts.RefFullName, err)
+	}
+}
+
+func (*workflowNotifier) NotifyNewPullRequest(pr *models.PullRequest, mentions []*models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		log.Error("pr.Issue.LoadPoster: %v", err)
+		return
+	}
+	triggerPullRequestWorkflows(pr.Issue.Poster, pr)
+}
+
+func (*workflowNotifier) NotifyPullRequestSynchronized(doer *models.User, pr *models.PullRequest) {
+	triggerPullRequestWorkflows(doer, pr)
+}
+
+func triggerPullRequestWorkflows(doer *models.User, pr *models.PullRequest) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		log.Error("pr.LoadBaseRepo: %v", err)
+		return
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		log.Error("pr.LoadHeadRepo: %v", err)
+		return
+	}
+	if pr.HeadRepo == nil {
+		return
+	}
+
+	headGitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
+	if err != nil {
+		log.Error("OpenRepository: %v", err)
+		return
+	}
+	commitID, err := headGitRepo.GetBranchCommitID(pr.HeadBranch)
+	headGitRepo.Close()
+	if err != nil {
+		log.Error("GetBranchCommitID: %v", err)
+		return
+	}
+
+	if err := TriggerWorkflows(&TriggerOptions{
+		Repo:              pr.BaseRepo,
+		CommitRepo:        pr.HeadRepo,
+		Doer:              doer,
+		Event:             workflow_module.EventPullRequest,
+		Ref:               pr.GetGitRefName(),
+		FilterRef:         git.BranchPrefix + pr.BaseBranch,
+		CommitSHA:         commitID,
+		IsForkPullRequest: !pr.IsSameRepo(),
+	}); err != nil {
+		log.Error("TriggerWorkflows [pr: %d]: %v", pr.ID, err)
+	}
+}
@@ -0,0 +1,461 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/timeutil"
+	workflow_module "code.gitea.io/gitea/modules/workflow"
+)
+
+// ErrInvalidStatusTransition indicates that a job can not change to the requested status
+var ErrInvalidStatusTransition = errors.New("Invalid status transition")
+
+// maxWorkflowFileSize limits the size of a workflow file which gets parsed
+const maxWorkflowFileSize = 1024 * 1024
+
+// runWorkingPool serializes status changes of the jobs of a run
+var runWorkingPool = sync.NewExclusivePool()
+
+// TriggerOptions describes the event which triggers workflows
+type TriggerOptions struct {
+	Repo              *models.Repository
+	CommitRepo        *models.Repository // repository which contains the commit, defaults to Repo
+	Doer              *models.User
+	Event             string
+	Ref               string // ref the run is executed for
+	FilterRef         string // ref the trigger filters are matched against
+	CommitSHA         string
+	IsForkPullRequest bool
+}
+
+// DetectWorkflows returns the workflows of the commit. Invalid workflow files are logged and skipped.
+func DetectWorkflows(commit *git.Commit) (map[string]*workflow_module.Workflow, error) {
+	tree, err := commit.SubTree(workflow_module.Dir)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entries, err := tree.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := make(map[string]*workflow_module.Workflow)
+	for _, entry := range entries {
+		if !entry.IsRegular() || !workflow_module.IsWorkflowFile(workflow_module.Dir+"/"+entry.Name()) {
+			continue
+		}
+		if entry.Blob().Size() > maxWorkflowFileSize {
+			log.Warn("Workflow file %s in commit %s is too large", entry.Name(), commit.ID)
+			continue
+		}
+		rd, err := entry.Blob().DataAsync()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rd)
+		rd.Close()
+		if err != nil {
+			return nil, err
+		}
+		w, err := workflow_module.Parse(content)
+		if err != nil {
+			log.Warn("Invalid workflow file %s in commit %s: %v", entry.Name(), commit.ID, err)
+			continue
+		}
+		workflows[entry.Name()] = w
+	}
+	return workflows, nil
+}
+
+// TriggerWorkflows creates runs for all workflows of the commit which are triggered by the event
+func TriggerWorkflows(opts *TriggerOptions) error {
+	if !setting.Workflow.Enabled || opts.Repo.IsMirror || opts.Repo.IsArchived {
+		return nil
+	}
+
+	commitRepo := opts.CommitRepo
+	if commitRepo == nil {
+		commitRepo = opts.Repo
+	}
+	gitRepo, err := git.OpenRepository(commitRepo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(opts.CommitSHA)
+	if err != nil {
+		return err
+	}
+	workflows, err := DetectWorkflows(commit)
+	if err != nil {
+		return err
+	}
+
+	for filename, w := range workflows {
+		if !w.Matches(opts.Event, opts.FilterRef) {
+			continue
+		}
+		if len(w.Jobs) > setting.Workflow.MaxJobsPerRun {
+			log.Warn("Workflow %s of repository %s has too many jobs", filename, opts.Repo.FullName())
+			continue
+		}
+		if err := createRun(opts, filename, w); err != nil {
+			return fmt.Errorf("createRun[%s]: %v", filename, err)
+		}
+	}
+	return nil
+}
+
+func createRun(opts *TriggerOptions, filename string, w *workflow_module.Workflow) error {
+	name := w.Name
+	if name == "" {
+		name = filename
+	}
+	run := &models.WorkflowRun{
+		RepoID:            opts.Repo.ID,
+		Repo:              opts.Repo,
+		WorkflowID:        filename,
+		Name:              name,
+		Event:             opts.Event,
+		Ref:               opts.Ref,
+		CommitSHA:         opts.CommitSHA,
+		IsForkPullRequest: opts.IsForkPullRequest,
+		TriggerUserID:     opts.Doer.ID,
+		TriggerUser:       opts.Doer,
+		Status:            models.WorkflowStatusPending,
+	}
+
+	jobs := make([]*models.WorkflowJob, 0, len(w.Jobs))
+	for _, id := range workflow_module.SortedJobIDs(w.Jobs) {
+		job := w.Jobs[id]
+		env := make(workflow_module.Env, len(w.Env)+len(job.Env))
+		for k, v := range w.Env {
+			env[k] = v
+		}
+		for k, v := range job.Env {
+			env[k] = v
+		}
+		job.Env = env
+		payload, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+
+		status := models.WorkflowStatusPending
+		if len(job.Needs) > 0 {
+			status = models.WorkflowStatusWaiting
+		}
+		jobs = append(jobs, &models.WorkflowJob{
+			JobID:   id,
+			Name:    job.Name,
+			RunsOn:  job.RunsOn,
+			Needs:   job.Needs,
+			Payload: string(payload),
+			Status:  status,
+		})
+	}
+
+	if err := models.InsertWorkflowRun(run, jobs); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		createCommitStatus(run, job)
+	}
+	return nil
+}
+
+// FetchJob assigns the oldest pending job the runner is able to execute to the runner.
+// It returns nil if there is no such job.
+func FetchJob(runner *models.WorkflowRunner) (*models.WorkflowJob, *models.WorkflowRun, error) {
+	if err := models.UpdateWorkflowRunnerLastOnline(runner); err != nil {
+		log.Error("UpdateWorkflowRunnerLastOnline: %v", err)
+	}
+
+	// the labels are only stored as JSON, so the pending jobs are paged through until the runner can run one
+	const pageSize = 50
+	var afterID int64
+	for {
+		jobs, err := models.FindPendingWorkflowJobs(runner, afterID, pageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, job := range jobs {
+			afterID = job.ID
+			if !runner.CanRunJob(job) {
+				continue
+			}
+			claimed, err := models.ClaimWorkflowJob(job, runner.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !claimed {
+				continue
+			}
+
+			run, err := updateRunStatus(job)
+			if err != nil {
+				return nil, nil, err
+			}
+			createCommitStatus(run, job)
+			return job, run, nil
+		}
+		if len(jobs) < pageSize {
+			return nil, nil, nil
+		}
+	}
+}
+
+// FailTimedOutJobs fails the jobs which are running for longer than the job timeout,
+// their runner is assumed to have crashed
+func FailTimedOutJobs(ctx context.Context) error {
+	jobs, err := models.FindTimedOutWorkflowJobs(timeutil.TimeStampNow().AddDuration(-setting.Workflow.JobTimeout))
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted failing timed out workflow jobs before job %d", job.ID)
+		default:
+		}
+		if err := UpdateJobStatus(job, models.WorkflowStatusFailure); err != nil && err != ErrInvalidStatusTransition {
+			return err
+		}
+		log.Trace("Workflow job %d timed out", job.ID)
+	}
+	return nil
+}
+
+// UpdateJobStatus sets the final status of a job reported by its runner
+func UpdateJobStatus(job *models.WorkflowJob, status models.WorkflowStatus) error {
+	if job.Status != models.WorkflowStatusRunning || !status.IsDone() || status == models.WorkflowStatusSkipped {
+		return ErrInvalidStatusTransition
+	}
+
+	job.Status = status
+	job.StoppedUnix = timeutil.TimeStampNow()
+	if err := models.UpdateWorkflowJob(db.DefaultContext(), job); err != nil {
+		return err
+	}
+
+	run, err := updateRunStatus(job)
+	if err != nil {
+		return err
+	}
+	createCommitStatus(run, job)
+	return nil
+}
+
+// CancelRun cancels all jobs of the run which are not finished yet
+func CancelRun(run *models.WorkflowRun) error {
+	key := strconv.FormatInt(run.ID, 10)
+	runWorkingPool.CheckIn(key)
+	defer runWorkingPool.CheckOut(key)
+
+	jobs, err := models.GetWorkflowJobsByRunID(db.DefaultContext(), run.ID)
+	if err != nil {
+		return err
+	}
+	cancelled := make([]*models.WorkflowJob, 0, len(jobs))
+	err = db.WithTx(func(ctx *db.Context) error {
+		for _, job := range jobs {
+			if job.Status.IsDone() {
+				continue
+			}
+			job.Status = models.WorkflowStatusCancelled
+			job.StoppedUnix = timeutil.TimeStampNow()
+			if err := models.UpdateWorkflowJob(ctx, job); err != nil {
+				return err
+			}
+			cancelled = append(cancelled, job)
+		}
+		return resolveRunStatus(ctx, run, jobs)
+	})
+	if err != nil {
+		return err
+	}
+	for _, job := range cancelled {
+		createCommitStatus(run, job)
+	}
+	return nil
+}
+
+// updateRunStatus starts the jobs which are unblocked by the status change of job and updates the status of the run
+func updateRunStatus(job *models.WorkflowJob) (*models.WorkflowRun, error) {
+	key := strconv.FormatInt(job.RunID, 10)
+	runWorkingPool.CheckIn(key)
+	defer runWorkingPool.CheckOut(key)
+
+	run, err := models.GetWorkflowRunByID(job.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []*models.WorkflowJob
+	err = db.WithTx(func(ctx *db.Context) error {
+		jobs, err := models.GetWorkflowJobsByRunID(ctx, run.ID)
+		if err != nil {
+			return err
+		}
+		if changed, err = unblockJobs(ctx, jobs); err != nil {
+			return err
+		}
+		return resolveRunStatus(ctx, run, jobs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range changed {
+		createCommitStatus(run, job)
+	}
+	return run, nil
+}
+
+// unblockJobs moves waiting jobs to pending if all their needed jobs succeeded
+// or skips them if one of their needed jobs did not succeed
+func unblockJobs(ctx *db.Context, jobs []*models.WorkflowJob) ([]*models.WorkflowJob, error) {
+	byJobID := make(map[string]*models.WorkflowJob, len(jobs))
+	for _, job := range jobs {
+		byJobID[job.JobID] = job
+	}
+
+	var changed []*models.WorkflowJob
+	for progress := true; progress; {
+		progress = false
+		for _, job := range jobs {
+			if job.Status != models.WorkflowStatusWaiting {
+				continue
+			}
+			status := models.WorkflowStatusPending
+			for _, need := range job.Needs {
+				needed, ok := byJobID[need]
+				if !ok || (needed.Status.IsDone() && needed.Status != models.WorkflowStatusSuccess) {
+					status = models.WorkflowStatusSkipped
+					break
+				}
+				if !needed.Status.IsDone() {
+					status = models.WorkflowStatusWaiting
+				}
+			}
+			if status == models.WorkflowStatusWaiting {
+				continue
+			}
+			job.Status = status
+			if status == models.WorkflowStatusSkipped {
+				job.StoppedUnix = timeutil.TimeStampNow()
+			}
+			if err := models.UpdateWorkflowJob(ctx, job); err != nil {
+				return nil, err
+			}
+			changed = append(changed, job)
+			progress = true
+		}
+	}
+	return changed, nil
+}
+
+// resolveRunStatus derives the status of the run from the status of its jobs
+func resolveRunStatus(ctx *db.Context, run *models.WorkflowRun, jobs []*models.WorkflowJob) error {
+	var running, pending, failed, cancelled bool
+	for _, job := range jobs {
+		switch job.Status {
+		case models.WorkflowStatusRunning:
+			running = true
+		case models.WorkflowStatusWaiting, models.WorkflowStatusPending:
+			pending = true
+		case models.WorkflowStatusFailure:
+			failed = true
+		case models.WorkflowStatusCancelled:
+			cancelled = true
+		}
+	}
+
+	status := models.WorkflowStatusSuccess
+	switch {
+	case running:
+		status = models.WorkflowStatusRunning
+	case pending && run.StartedUnix > 0:
+		status = models.WorkflowStatusRunning
+	case pending:
+		status = models.WorkflowStatusPending
+	case failed:
+		status = models.WorkflowStatusFailure
+	case cancelled:
+		status = models.WorkflowStatusCancelled
+	}
+	if status == run.Status {
+		return nil
+	}
+
+	run.Status = status
+	if status == models.WorkflowStatusRunning && run.StartedUnix == 0 {
+		run.StartedUnix = timeutil.TimeStampNow()
+	}
+	if status.IsDone() {
+		run.StoppedUnix = timeutil.TimeStampNow()
+	}
+	return models.UpdateWorkflowRun(ctx, run)
+}
+
+// toCommitStatusState maps the status of a job to a commit status
+func toCommitStatusState(status models.WorkflowStatus) (api.CommitStatusState, string) {
+	switch status {
+	case models.WorkflowStatusWaiting:
+		return api.CommitStatusPending, "Waiting for other jobs"
+	case models.WorkflowStatusPending:
+		return api.CommitStatusPending, "Waiting for a runner"
+	case models.WorkflowStatusRunning:
+		return api.CommitStatusPending, "Running"
+	case models.WorkflowStatusSuccess:
+		return api.CommitStatusSuccess, "Successful"
+	case models.WorkflowStatusFailure:
+		return api.CommitStatusFailure, "Failed"
+	case models.WorkflowStatusCancelled:
+		return api.CommitStatusError, "Cancelled"
+	default:
+		return api.CommitStatusWarning, "Skipped"
+	}
+}
+
+// createCommitStatus reports the status of the job as commit status of the commit the run belongs to
+func createCommitStatus(run *models.WorkflowRun, job *models.WorkflowJob) {
+	if err := run.LoadAttributes(); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+
+	state, description := toCommitStatusState(job.Status)
+	if err := models.NewCommitStatus(models.NewCommitStatusOptions{
+		Repo:    run.Repo,
+		Creator: run.TriggerUser,
+		SHA:     run.CommitSHA,
+		CommitStatus: &models.CommitStatus{
+			State:       state,
+			TargetURL:   fmt.Sprintf("%s/workflows/runs/%d", run.Repo.APIURL(), run.ID),
+			Description: description,
+			Context:     fmt.Sprintf("%s / %s (%s)", run.Name, job.Name, run.Event),
+		},
+	}); err != nil {
+		log.Error("NewCommitStatus[run: %d, job: %d]: %v", run.ID, job.ID, err)
+	}
+}
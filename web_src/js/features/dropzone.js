@@ -5,5 +5,22 @@ export default async function createDropzone(el, opts) {
   ]);
 
   Dropzone.autoDiscover = false;
+
+  // videos have a maximum size of their own, but dropzone only knows a single one
+  const videoMaxSize = Number(el.getAttribute('data-video-max-size'));
+  if (videoMaxSize && opts.maxFilesize) {
+    const maxSize = opts.maxFilesize;
+    opts.maxFilesize = Math.max(maxSize, videoMaxSize);
+    opts.accept = (file, done) => {
+      const fileMaxSize = file.type.startsWith('video/') ? videoMaxSize : maxSize;
+      if (file.size > fileMaxSize * 1024 * 1024) {
+        done(opts.dictFileTooBig
+          .replace('{{filesize}}', Math.round(file.size / 1024 / 10.24) / 100)
+          .replace('{{maxFilesize}}', fileMaxSize));
+        return;
+      }
+      done();
+    };
+  }
   return new Dropzone(el, opts);
 }
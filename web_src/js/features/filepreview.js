@@ -0,0 +1,59 @@
+export default async function initFilePreview() {
+  for (const el of document.querySelectorAll('.file-preview[data-preview-url]')) {
+    try {
+      const res = await fetch(el.dataset.previewUrl);
+      if (!res.ok) throw new Error(`${res.status} ${res.statusText}`);
+      renderPreview(el, await res.json());
+    } catch (err) {
+      console.error(err);
+      if (el.dataset.viewerUrl) {
+        showViewer(el);
+      } else {
+        el.remove();
+      }
+    }
+  }
+}
+
+function renderPreview(el, preview) {
+  if (preview.image) {
+    const img = document.createElement('img');
+    img.src = `data:image/png;base64,${preview.image}`;
+    img.alt = preview.name;
+    if (el.dataset.viewerUrl) {
+      // the preview of a document is replaced by the viewer once it is clicked
+      img.title = el.dataset.viewerTitle;
+      img.classList.add('clickable');
+      img.addEventListener('click', () => showViewer(el));
+    }
+    el.appendChild(img);
+  } else if (el.dataset.viewerUrl) {
+    showViewer(el);
+    return;
+  }
+
+  const metadata = Object.entries(preview.metadata || {});
+  if (!metadata.length || el.dataset.viewerUrl) return;
+  const table = document.createElement('table');
+  table.classList.add('ui', 'very', 'basic', 'compact', 'table');
+  for (const [key, value] of metadata) {
+    const row = table.insertRow();
+    row.insertCell().textContent = key;
+    row.insertCell().textContent = formatValue(value, key === 'size' ? ' × ' : ', ');
+  }
+  el.appendChild(table);
+}
+
+function formatValue(value, separator) {
+  if (Array.isArray(value)) return value.map((v) => formatValue(v, separator)).join(separator);
+  if (typeof value === 'number' && !Number.isInteger(value)) return String(Number(value.toFixed(3)));
+  return String(value);
+}
+
+function showViewer(el) {
+  const iframe = document.createElement('iframe');
+  iframe.width = '100%';
+  iframe.height = '600px';
+  iframe.src = el.dataset.viewerUrl;
+  el.replaceWith(iframe);
+}
@@ -9,6 +9,7 @@ import attachTribute from './features/tribute.js';
 import createColorPicker from './features/colorpicker.js';
 import createDropzone from './features/dropzone.js';
 import initClipboard from './features/clipboard.js';
+import initFilePreview from './features/filepreview.js';
 import initContextPopups from './features/contextpopup.js';
 import initGitGraph from './features/gitgraph.js';
 import initHeatmap from './features/heatmap.js';
@@ -580,6 +581,7 @@ function initCommentForm() {
   selectItem('.select-project', '#project_id');
   selectItem('.select-milestone', '#milestone_id');
   selectItem('.select-assignee', '#assignee_id');
+  selectItem('.select-workflow-state', '#workflow_state_id');
 }
 
 function initInstall() {
@@ -2887,6 +2889,7 @@ $(document).ready(async () => {
     initMarkupContent(),
     initGithook(),
     initImageDiff(),
+    initFilePreview(),
   ]);
 });
 